@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/maraichr/lattice/internal/api"
+	"github.com/maraichr/lattice/internal/api/graphql"
 	"github.com/maraichr/lattice/internal/auth"
 	"github.com/maraichr/lattice/internal/config"
 	"github.com/maraichr/lattice/internal/embedding"
@@ -21,6 +22,10 @@ import (
 	"github.com/maraichr/lattice/internal/llm"
 	"github.com/maraichr/lattice/internal/mcp/session"
 	"github.com/maraichr/lattice/internal/oracle"
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/builtin"
+	jsts "github.com/maraichr/lattice/internal/parser/javascript"
+	"github.com/maraichr/lattice/internal/parser/tsql"
 	"github.com/maraichr/lattice/internal/store"
 	minioclient "github.com/maraichr/lattice/internal/store/minio"
 	"github.com/maraichr/lattice/internal/store/postgres"
@@ -48,24 +53,62 @@ func main() {
 	defer pool.Close()
 	logger.Info("connected to database")
 
-	s := store.New(pool)
+	s := store.NewWithTimeouts(pool, store.Timeouts{Read: cfg.Database.ReadTimeout, Write: cfg.Database.WriteTimeout})
 
 	deps := &api.RouterDeps{}
+	deps.EdgeDirections, err = lineage.NewEdgeDirections(cfg.Lineage.EdgeDirections)
+	if err != nil {
+		logger.Error("invalid LINEAGE_EDGE_DIRECTIONS", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	severity, err := impact.NewSeverityConfig(cfg.Impact.SeverityThresholds)
+	if err != nil {
+		logger.Error("invalid IMPACT_SEVERITY_THRESHOLDS", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	jsConfidence, err := jsts.NewConfidence(cfg.Parser.JSPatternConfidence)
+	if err != nil {
+		logger.Error("invalid JS_PATTERN_CONFIDENCE", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Parser registries for the debug parse endpoint (same parsers and limits as the
+	// worker), one per concurrent request as tree-sitter parsers are not goroutine-safe
+	parserOpts := builtin.Options{
+		TSQLLimits: tsql.Limits{
+			MaxNestingDepth:    cfg.Parser.TSQLMaxNestingDepth,
+			MaxStatementTokens: cfg.Parser.TSQLMaxStatementTokens,
+		},
+		JSConfidence:        jsConfidence,
+		DetectMinConfidence: cfg.Parser.DetectMinConfidence,
+	}
+	deps.Parsers = parser.NewRegistryPool(cfg.Parser.Concurrency, func() *parser.Registry {
+		return builtin.NewRegistry(parserOpts)
+	})
+	deps.GraphQL = graphql.Limits{
+		MaxPageSize: cfg.GraphQL.MaxPageSize,
+		MaxDepth:    cfg.GraphQL.MaxDepth,
+		MaxFields:   cfg.GraphQL.MaxFields,
+	}
+	deps.Retention = cfg.Retention.SoftDelete
 
 	// Neo4j (optional)
 	graphClient, err := graph.NewClient(cfg.Neo4j)
 	if err != nil {
-		logger.Warn("neo4j connection failed, lineage queries disabled", slog.String("error", err.Error()))
+		logger.Warn("neo4j connection failed, lineage answered from the database", slog.String("error", err.Error()))
 	} else {
 		if err := graphClient.EnsureIndexes(ctx); err != nil {
 			logger.Warn("neo4j ensure indexes failed", slog.String("error", err.Error()))
 		}
 		deps.Graph = graphClient
 		deps.Lineage = lineage.NewEngine(s, graphClient, logger)
-		deps.Impact = impact.NewEngine(graphClient, s, logger)
 		defer graphClient.Close(ctx)
 		logger.Info("connected to neo4j")
 	}
+	// Without Neo4j the impact walk runs over the edges in the database (graph.LineageFor)
+	deps.Impact = impact.NewEngine(deps.Graph, s, logger)
+	deps.Impact.SetSeverityConfig(severity)
 
 	// MinIO (optional — enables uploads)
 	mc, err := minioclient.NewClient(cfg.MinIO)
@@ -82,12 +125,13 @@ func main() {
 		logger.Warn("valkey connection failed, job queue disabled", slog.String("error", err.Error()))
 	} else {
 		deps.Producer = ingestion.NewProducer(vkClient)
+		deps.Producer.SetCoalesceWindow(cfg.Parser.CoalesceWindow)
 		defer vkClient.Close()
 		logger.Info("connected to valkey")
 	}
 
 	// Embeddings (auto-selects: OpenRouter > Bedrock > disabled)
-	embedder, err := embedding.NewEmbedder(cfg)
+	embedder, err := embedding.NewEmbedder(ctx, cfg, s)
 	if err != nil {
 		logger.Warn("embedder init failed, semantic search disabled", slog.String("error", err.Error()))
 	} else if embedder != nil {
@@ -102,7 +146,11 @@ func main() {
 			logger.Error("AUTH_ENABLED=true but AUTH_ISSUER_URL is empty")
 			os.Exit(1)
 		}
-		verifier, err := auth.NewVerifier(ctx, cfg.Auth.IssuerURL, cfg.Auth.PublicIssuer, cfg.Auth.Audience)
+		verifier, err := auth.NewVerifier(ctx, cfg.Auth.IssuerURL, cfg.Auth.PublicIssuer, cfg.Auth.Audience, auth.ClaimMapping{
+			TenantClaim: cfg.Auth.TenantClaim,
+			RolesClaim:  cfg.Auth.RolesClaim,
+			AdminRole:   cfg.Auth.AdminRole,
+		})
 		if err != nil {
 			logger.Error("failed to init OIDC verifier", slog.String("error", err.Error()))
 			os.Exit(1)
@@ -116,6 +164,7 @@ func main() {
 		llmClient := llm.NewClient(cfg.OpenRouter.APIKey, cfg.Oracle.Model, cfg.OpenRouter.BaseURL)
 		sessionMgr := session.NewManager(vkClient)
 		deps.Oracle = oracle.NewEngine(s, sessionMgr, llmClient, graphClient, deps.Impact, logger)
+		deps.Oracle.SetEdgeDirections(deps.EdgeDirections)
 		logger.Info("oracle enabled", slog.String("model", cfg.Oracle.Model))
 	}
 
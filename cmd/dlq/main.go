@@ -0,0 +1,166 @@
+// dlq lists the ingestion jobs moved to the dead-letter stream after failing
+// ingestion.MaxRetries times, and replays selected ones onto the ingest stream once the
+// cause is fixed, optionally skipping the stages that keep failing.
+// Run from project root:
+//
+//	go run ./cmd/dlq list [-project my-project]
+//	go run ./cmd/dlq replay [-project my-project] [-skip-stage embed] (-all | <id>...)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/config"
+	"github.com/maraichr/lattice/internal/ingestion"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	vk "github.com/maraichr/lattice/internal/store/valkey"
+)
+
+const usage = `usage:
+  dlq list [-project <slug>]
+  dlq replay [-project <slug>] [-skip-stage <stage>[,<stage>...]] (-all | <id>...)`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	flags := flag.NewFlagSet("dlq "+cmd, flag.ExitOnError)
+	slug := flags.String("project", "", "only dead letters of the project with this slug")
+	skip := flags.String("skip-stage", "", "replay: comma-separated pipeline stages not to run (e.g. embed)")
+	all := flags.Bool("all", false, "replay: every dead letter (of -project, if given)")
+	_ = flags.Parse(os.Args[2:])
+
+	if cmd != "list" && cmd != "replay" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if cmd == "replay" && *all == (flags.NArg() > 0) {
+		fmt.Fprintln(os.Stderr, "replay takes either -all or dead letter IDs")
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("load config", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	vkClient, err := vk.NewClient(cfg.Valkey)
+	if err != nil {
+		fatal("connect to valkey", err)
+	}
+	defer vkClient.Close()
+	dlq := ingestion.NewDeadLetterQueue(vkClient)
+
+	// Projects are given by slug but dead letters carry their ID
+	projectID := uuid.Nil
+	if *slug != "" {
+		pool, err := postgres.NewPool(ctx, cfg.Database.DSN(), cfg.Database.MaxConns, cfg.Database.MinConns)
+		if err != nil {
+			fatal("connect to database", err)
+		}
+		project, err := store.New(pool).GetProject(ctx, *slug)
+		pool.Close()
+		if err != nil {
+			fatal("load project "+*slug, err)
+		}
+		projectID = project.ID
+	}
+
+	switch cmd {
+	case "list":
+		letters, err := dlq.List(ctx, projectID)
+		if err != nil {
+			fatal("list dead letters", err)
+		}
+		printLetters(letters, time.Now())
+	case "replay":
+		ids := flags.Args()
+		if *all {
+			letters, err := dlq.List(ctx, projectID)
+			if err != nil {
+				fatal("list dead letters", err)
+			}
+			for _, l := range letters {
+				ids = append(ids, l.ID)
+			}
+		}
+		var stages []string
+		if *skip != "" {
+			stages = strings.Split(*skip, ",")
+		}
+		failed := false
+		for _, id := range ids {
+			if !replay(ctx, dlq, id, projectID, stages) {
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	}
+}
+
+// replay replays one dead letter, refusing one of a project other than projectID (when
+// set), and reports the outcome.
+func replay(ctx context.Context, dlq *ingestion.DeadLetterQueue, id string, projectID uuid.UUID, skipStages []string) bool {
+	if projectID != uuid.Nil {
+		letter, err := dlq.Get(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			return false
+		}
+		if letter.Message.ProjectID != projectID {
+			fmt.Fprintf(os.Stderr, "%s: belongs to project %s, not the one given\n", id, letter.Message.ProjectID)
+			return false
+		}
+	}
+	newID, err := dlq.Replay(ctx, id, func(msg *ingestion.IngestMessage) {
+		msg.SkipStages = append(msg.SkipStages, skipStages...)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+		return false
+	}
+	fmt.Printf("%s: replayed as %s\n", id, newID)
+	return true
+}
+
+// printLetters writes one line per dead letter, with how long ago it failed.
+func printLetters(letters []ingestion.DeadLetter, now time.Time) {
+	if len(letters) == 0 {
+		fmt.Println("no dead-lettered messages")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPROJECT\tINDEX RUN\tTRIGGER\tAGE\tERROR")
+	for _, l := range letters {
+		age := "?"
+		if !l.FailedAt.IsZero() {
+			age = now.Sub(l.FailedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			l.ID, l.Message.ProjectID, l.Message.IndexRunID, l.Message.Trigger, age, l.Error)
+	}
+	w.Flush()
+}
+
+func fatal(what string, err error) {
+	fmt.Fprintf(os.Stderr, "dlq: %s: %v\n", what, err)
+	os.Exit(1)
+}
@@ -12,10 +12,14 @@ import (
 	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
 	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/modelcontextprotocol/go-sdk/oauthex"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/maraichr/lattice/internal/auth"
 	"github.com/maraichr/lattice/internal/config"
 	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/impact"
+	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/mcp"
 	"github.com/maraichr/lattice/internal/mcp/tools"
 	"github.com/maraichr/lattice/internal/store"
@@ -46,7 +50,7 @@ func main() {
 	defer pool.Close()
 	logger.Info("connected to database")
 
-	s := store.New(pool)
+	s := store.NewWithTimeouts(pool, store.Timeouts{Read: cfg.Database.ReadTimeout, Write: cfg.Database.WriteTimeout})
 
 	// Valkey (optional for sessions)
 	vkClient, err := vk.NewClient(cfg.Valkey)
@@ -58,7 +62,7 @@ func main() {
 	}
 
 	// Embedder (optional for semantic search)
-	embedder, err := embedding.NewEmbedder(cfg)
+	embedder, err := embedding.NewEmbedder(ctx, cfg, s)
 	if err != nil {
 		logger.Warn("embedder unavailable, semantic search disabled", slog.String("error", err.Error()))
 	} else if embedder != nil {
@@ -79,59 +83,167 @@ func main() {
 	listProjects := tools.NewListProjectsHandler(s, logger)
 	searchSymbols := tools.NewSearchSymbolsHandler(s, mcpServer.Session, logger)
 	getLineage := tools.NewGetLineageHandler(s, logger)
+	traceColumnLineage := tools.NewTraceColumnLineageHandler(s, logger)
+	edgeDirections, err := lineage.NewEdgeDirections(cfg.Lineage.EdgeDirections)
+	if err != nil {
+		logger.Error("invalid LINEAGE_EDGE_DIRECTIONS", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	getLineage.SetEdgeDirections(edgeDirections)
+	askCodebase.SetEdgeDirections(edgeDirections)
+	navRules, err := mcp.LoadNavigationRules(cfg.MCP.NavigationRulesFile)
+	if err != nil {
+		logger.Error("invalid MCP_NAVIGATION_RULES_FILE", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	mcpServer.Nav.SetRules(navRules)
+	extractSubgraph.SetNavigationRules(navRules)
+	askCodebase.SetNavigationRules(navRules)
+	searchSymbols.SetNavigationRules(navRules)
 	analyzeImpact := tools.NewAnalyzeImpactHandler(s, logger)
+	severity, err := impact.NewSeverityConfig(cfg.Impact.SeverityThresholds)
+	if err != nil {
+		logger.Error("invalid IMPACT_SEVERITY_THRESHOLDS", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	analyzeImpact.SetSeverityConfig(severity)
+	askCodebase.SetSeverityConfig(severity)
+	analyzeFileImpact := tools.NewAnalyzeFileImpactHandler(s, logger)
 	getProjectAnalytics := tools.NewGetProjectAnalyticsHandler(s, logger)
 	semanticSearch := tools.NewSemanticSearchHandler(s, embedder, logger)
 	traceCrossLang := tools.NewTraceCrossLanguageHandler(s, logger)
+	traceToUI := tools.NewTraceToUIHandler(s, logger)
+	listEndpoints := tools.NewListEndpointsHandler(s, logger)
+	schemaDiff := tools.NewSchemaDiffHandler(s, logger)
+	explainConnection := tools.NewExplainConnectionHandler(s, logger)
+	findConfigReaders := tools.NewFindConfigReadersHandler(s, logger)
+	findUnusedSymbols := tools.NewFindUnusedSymbolsHandler(s, logger)
+	callTree := tools.NewCallTreeHandler(s, logger)
+
+	// Tool telemetry: result usefulness per tool/intent, served on /metrics
+	metricsRegistry := prometheus.NewRegistry()
+	telemetry := mcp.NewTelemetry(metricsRegistry, logger)
+	// Symbols shown in responses are counted for get_project_analytics scope=hot_symbols
+	usage := mcp.NewUsage(s, logger)
+	telemetry.TrackUsage(usage)
+	usageDone := make(chan struct{})
+	go func() {
+		usage.Run(ctx, mcp.UsageFlushInterval)
+		close(usageDone)
+	}()
 
 	// SDK MCP server
 	sdkServer := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "lattice", Version: "1.0.0"}, nil)
 
-	// Register all tools using WrapHandler
+	// Register all tools using WrapHandler; every call is reported to telemetry, and
+	// project-scoped tools warn while the project is still being indexed (see tools.GateReadiness)
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "extract_subgraph",
-		Description: "Extract a subgraph of symbols and relationships around a topic or set of seed symbols. Returns symbol cards with metadata, edges, and navigation hints.",
-	}, tools.WrapHandler[tools.ExtractSubgraphParams](extractSubgraph))
+		Description: "Extract a subgraph of symbols and relationships around a topic or set of seed symbols. Returns symbol cards with metadata, edges, and navigation hints. Set group_by=community to group cards by detected community, output=edges for a JSON node and edge list, or output=dot for a Graphviz digraph (nodes styled by kind, edges labelled by type, max_fanout edges per node). When the cards do not all fit, pass the next_cursor from the hints as cursor, with the same other parameters, for the next page.",
+	}, tools.WrapHandler[tools.ExtractSubgraphParams](tools.Instrument[tools.ExtractSubgraphParams]("extract_subgraph", telemetry,
+		tools.GateReadiness[tools.ExtractSubgraphParams](s, extractSubgraph))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "ask_codebase",
-		Description: "Ask a natural language question about the codebase. Routes to overview, search, ranking, impact analysis, lineage tracing, or subgraph exploration.",
-	}, tools.WrapHandler[tools.AskCodebaseParams](askCodebase))
+		Description: "Ask a natural language question about the codebase. Routes to overview, search, ranking, impact analysis, lineage tracing, dependency cycles, or subgraph exploration. Ranking questions order by in-degree unless rank_by is pagerank or betweenness. Searches also match the project's search_synonyms.",
+	}, tools.WrapHandler[tools.AskCodebaseParams](tools.Instrument[tools.AskCodebaseParams]("ask_codebase", telemetry,
+		tools.GateReadiness[tools.AskCodebaseParams](s, askCodebase))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "list_projects",
 		Description: "List all projects accessible to the authenticated user. Returns project slug, name, and description.",
-	}, tools.WrapHandler[tools.ListProjectsParams](listProjects))
+	}, tools.WrapHandler[tools.ListProjectsParams](tools.Instrument[tools.ListProjectsParams]("list_projects", telemetry,
+		listProjects)))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "search_symbols",
-		Description: "Search for symbols (tables, procedures, classes, functions, etc.) by name or keyword within a project. Supports filtering by kind and language.",
-	}, tools.WrapHandler[tools.SearchSymbolsParams](searchSymbols))
+		Description: "Search for symbols (tables, procedures, classes, functions, etc.) by name or keyword within a project. Supports filtering by kind, language, monorepo module, and tag (curated metadata tags or automatic ones, e.g. tag \"pii\" for columns that look like personal data). Set rank_by (in_degree, pagerank, betweenness) to order matches by graph centrality instead of name relevance; PageRank and betweenness favour the symbols that tie the codebase together over utilities everything calls. Set include_ownership to show the last commit author and date of each symbol's file (git sources with track_ownership). Query terms listed in the project's search_synonyms setting also match their synonyms, and the response notes the expansion. Set name_pattern to keep only symbols whose qualified name matches a glob (* and ?, whole name, case-insensitive, e.g. dbo.usp_Get*), or with pattern_type \"regex\" an RE2 regular expression; query may then be omitted. When more matches remain, pass the next_cursor from the hints as cursor, with the same query and filters, for the next page.",
+	}, tools.WrapHandler[tools.SearchSymbolsParams](tools.Instrument[tools.SearchSymbolsParams]("search_symbols", telemetry,
+		tools.GateReadiness[tools.SearchSymbolsParams](s, searchSymbols))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "get_lineage",
-		Description: "Trace the upstream (data sources, callers) or downstream (consumers, dependents) lineage of a symbol. Useful for understanding data flow and call chains.",
-	}, tools.WrapHandler[tools.GetLineageParams](getLineage))
+		Description: "Trace the upstream (data sources, callers) or downstream (consumers, callees) lineage of a symbol. Each edge type keeps its own direction: tables a procedure reads_from are upstream of it, tables it writes_to downstream. Set include_inferred to also follow related_to edges between columns inferred to be foreign keys from their names and types (with confidence) where none is declared. Useful for understanding data flow and call chains.",
+	}, tools.WrapHandler[tools.GetLineageParams](tools.Instrument[tools.GetLineageParams]("get_lineage", telemetry,
+		tools.GateReadiness[tools.GetLineageParams](s, getLineage))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "trace_column_lineage",
+		Description: "Trace the column-level lineage of a fully qualified column (e.g. dbo.Orders.Amount) through the column references extracted from SQL: upstream to where its value comes from, downstream to the columns it feeds. Each chain is shown hop by hop with the derivation (direct_copy, transform, aggregate, filter, join, conditional) and expression of every hop.",
+	}, tools.WrapHandler[tools.TraceColumnLineageParams](tools.Instrument[tools.TraceColumnLineageParams]("trace_column_lineage", telemetry,
+		tools.GateReadiness[tools.TraceColumnLineageParams](s, traceColumnLineage))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "analyze_impact",
-		Description: "Analyze the blast radius of modifying, deleting, or renaming a symbol. Shows direct and transitive impacts with severity classification.",
-	}, tools.WrapHandler[tools.AnalyzeImpactParams](analyzeImpact))
+		Description: "Analyze the blast radius of modifying, deleting, or renaming a symbol. Shows direct and transitive impacts with severity classification, and rates the whole blast radius low, medium, high or critical from its direct (including callers) and transitive counts, one level higher when it crosses languages. Pass severity_thresholds, e.g. {\"critical\": {\"direct\": 20}, \"cross_language\": false}, to rate by other cutoffs than the configured ones. Blast radii above max_affected (default 200) are summarized by kind and layer. Set include_implementations to follow calls on interface methods to their implementations (reduced confidence). Calls only taken behind a feature flag are followed at reduced confidence and noted; set exclude_conditional to skip them. Set include_ownership to show who last committed to each affected symbol's file. With a progress token, progress notifications report each depth walked.",
+	}, tools.WrapHandler[tools.AnalyzeImpactParams](tools.Instrument[tools.AnalyzeImpactParams]("analyze_impact", telemetry,
+		tools.GateReadiness[tools.AnalyzeImpactParams](s, analyzeImpact))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "analyze_file_impact",
+		Description: "Analyze the blast radius of changing a file. Aggregates impact across every symbol defined in the file and lists the affected files, deduplicated, with per-file affected-symbol counts.",
+	}, tools.WrapHandler[tools.AnalyzeFileImpactParams](tools.Instrument[tools.AnalyzeFileImpactParams]("analyze_file_impact", telemetry,
+		tools.GateReadiness[tools.AnalyzeFileImpactParams](s, analyzeFileImpact))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "get_project_analytics",
-		Description: "Get project-level analytics: summary stats, language distribution, symbol kind counts, architectural layer distribution, or cross-language bridges.",
-	}, tools.WrapHandler[tools.GetProjectAnalyticsParams](getProjectAnalytics))
+		Description: "Get project-level analytics: summary stats, language distribution, symbol kind counts, architectural layer distribution, cross-language bridges, detected communities (scope=communities), regions held together mostly by low-confidence inferred links (scope=low_confidence_regions), missing indexes suggested from the columns queries filter and join on (scope=index_suggestions), the functions and procedures ranked by size, coupling and branch complexity for refactoring (scope=metrics), dependency cycles with their symbols and the edge types forming each loop (scope=cycles), unreferenced candidate dead code by kind (scope=unreferenced), the symbols agents looked at most in the last week (scope=hot_symbols), or per-module breakdowns for monorepos (scope=modules, optional module).",
+	}, tools.WrapHandler[tools.GetProjectAnalyticsParams](tools.Instrument[tools.GetProjectAnalyticsParams]("get_project_analytics", telemetry,
+		tools.GateReadiness[tools.GetProjectAnalyticsParams](s, getProjectAnalytics))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "semantic_search",
-		Description: "Search symbols using natural language via vector embeddings. Finds conceptually similar symbols even without exact name matches. Requires embedding provider to be configured.",
-	}, tools.WrapHandler[tools.SemanticSearchParams](semanticSearch))
+		Description: "Search symbols using natural language via vector embeddings. Finds conceptually similar symbols even without exact name matches, showing each match's similarity score; matches below min_similarity (default 0.3) are left out. By default (mode=hybrid) the similarity ranking is fused with a name-match ranking so exact names rank first; keyword_weight (0-1, default 0.5) sets the name ranking's share. mode=vector ranks by similarity alone, mode=keyword by name alone. Use limit to cap the results (default 10, max 50). Requires embedding provider to be configured, except for mode=keyword.",
+	}, tools.WrapHandler[tools.SemanticSearchParams](tools.Instrument[tools.SemanticSearchParams]("semantic_search", telemetry,
+		tools.GateReadiness[tools.SemanticSearchParams](s, semanticSearch))))
 
 	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
 		Name:        "trace_cross_language",
-		Description: "Trace cross-language paths from a symbol, showing how code flows across language boundaries (e.g., TypeScript → C# → SQL). Groups results by stack layer with confidence scores.",
-	}, tools.WrapHandler[tools.TraceCrossLanguageParams](traceCrossLang))
+		Description: "Trace cross-language paths from a symbol, showing how code flows across language boundaries (e.g., TypeScript → C# → SQL). Groups results by stack layer with confidence scores. With a progress token, a progress notification reports each direction once it is traced.",
+	}, tools.WrapHandler[tools.TraceCrossLanguageParams](tools.Instrument[tools.TraceCrossLanguageParams]("trace_cross_language", telemetry,
+		tools.GateReadiness[tools.TraceCrossLanguageParams](s, traceCrossLang))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "trace_to_ui",
+		Description: "Trace a table back to the screens and endpoints that surface its data: walks writes_to/reads_from back to procedures, then calls/calls_api back to controllers and frontend components. Returns the reachable UI-layer symbols and endpoints with the path from each to the table.",
+	}, tools.WrapHandler[tools.TraceToUIParams](tools.Instrument[tools.TraceToUIParams]("trace_to_ui", telemetry,
+		tools.GateReadiness[tools.TraceToUIParams](s, traceToUI))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "list_endpoints",
+		Description: "List API endpoints with their HTTP method/route and the tables each reads or writes through its call chain. Filter by table to find which endpoints touch it.",
+	}, tools.WrapHandler[tools.ListEndpointsParams](tools.Instrument[tools.ListEndpointsParams]("list_endpoints", telemetry,
+		tools.GateReadiness[tools.ListEndpointsParams](s, listEndpoints))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "call_tree",
+		Description: "Render the full downstream call and data tree of an endpoint or other entry point as an indented outline (controller → service → repository → procedure → tables), each node labelled with the edge reaching it. Shared subtrees are shown once and referenced with ↩ see #n. Depth and node count are capped; truncation is reported.",
+	}, tools.WrapHandler[tools.CallTreeParams](tools.Instrument[tools.CallTreeParams]("call_tree", telemetry,
+		tools.GateReadiness[tools.CallTreeParams](s, callTree))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "explain_connection",
+		Description: "Explain how two symbols are connected: a direct edge (with its metadata), a path and its length, or, when neither exists, the nearest unresolved reference that would have connected them.",
+	}, tools.WrapHandler[tools.ExplainConnectionParams](tools.Instrument[tools.ExplainConnectionParams]("explain_connection", telemetry,
+		tools.GateReadiness[tools.ExplainConnectionParams](s, explainConnection))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "schema_diff",
+		Description: "Compare the database schema between two index runs: added/dropped tables, added/dropped/retyped columns and added/dropped foreign keys, destructive changes first. Defaults to the latest run against the one before it.",
+	}, tools.WrapHandler[tools.SchemaDiffParams](tools.Instrument[tools.SchemaDiffParams]("schema_diff", telemetry,
+		tools.GateReadiness[tools.SchemaDiffParams](s, schemaDiff))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "find_config_readers",
+		Description: "Find the symbols that read a configuration key: process.env/import.meta.env in JavaScript and TypeScript, IConfiguration and environment variables in C#, @Value placeholders and Environment/System properties in Java. Use it to gauge the blast radius of a config change.",
+	}, tools.WrapHandler[tools.FindConfigReadersParams](tools.Instrument[tools.FindConfigReadersParams]("find_config_readers", telemetry,
+		tools.GateReadiness[tools.FindConfigReadersParams](s, findConfigReaders))))
+
+	sdkmcp.AddTool(sdkServer, &sdkmcp.Tool{
+		Name:        "find_unused_symbols",
+		Description: "Find candidate dead code: tables, views, procedures, functions, classes and interfaces no other symbol references, as symbol cards. Filter by kinds and languages. Entry points such as endpoints and triggers are never listed. Symbols reached only by cross-language links below the analytics confidence threshold are left out unless include_low_confidence is set.",
+	}, tools.WrapHandler[tools.FindUnusedSymbolsParams](tools.Instrument[tools.FindUnusedSymbolsParams]("find_unused_symbols", telemetry,
+		tools.GateReadiness[tools.FindUnusedSymbolsParams](s, findUnusedSymbols))))
 
 	// Use Stateless mode so that stale session IDs from server restarts (hot-reload)
 	// are ignored rather than returning 404. Each request gets a pre-initialized
@@ -151,7 +263,11 @@ func main() {
 			logger.Error("AUTH_ENABLED=true but AUTH_ISSUER_URL is empty")
 			os.Exit(1)
 		}
-		verifier, err := auth.NewVerifier(ctx, cfg.Auth.IssuerURL, cfg.Auth.PublicIssuer, cfg.Auth.Audience)
+		verifier, err := auth.NewVerifier(ctx, cfg.Auth.IssuerURL, cfg.Auth.PublicIssuer, cfg.Auth.Audience, auth.ClaimMapping{
+			TenantClaim: cfg.Auth.TenantClaim,
+			RolesClaim:  cfg.Auth.RolesClaim,
+			AdminRole:   cfg.Auth.AdminRole,
+		})
 		if err != nil {
 			logger.Error("failed to init OIDC verifier for MCP", slog.String("error", err.Error()))
 			os.Exit(1)
@@ -189,6 +305,7 @@ func main() {
 		mcpHandler = auth.DevModeMiddleware(logger)(sdkHandler)
 	}
 
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
 	mux.Handle("/mcp", mcpHandler)
 	// Also serve on root for backwards compat
 	mux.Handle("/", mcpHandler)
@@ -209,5 +326,6 @@ func main() {
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		logger.Warn("MCP HTTP shutdown", slog.String("error", err.Error()))
 	}
+	<-usageDone
 	logger.Info("MCP server stopped")
 }
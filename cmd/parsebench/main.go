@@ -0,0 +1,45 @@
+// parsebench reports parser throughput (symbols/sec and MB/sec) per language over the
+// generated parsebench corpus, to compare parser changes before they ship.
+// Run from project root: go run ./cmd/parsebench -scale 5 -runs 3
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/maraichr/lattice/internal/parser/builtin"
+	"github.com/maraichr/lattice/internal/parser/parsebench"
+)
+
+func main() {
+	scale := flag.Int("scale", 1, "corpus size multiplier (1 is around 100 KB per language)")
+	runs := flag.Int("runs", 3, "parses per language; the mean is reported")
+	lang := flag.String("lang", "", "only benchmark this language (e.g. tsql, csharp)")
+	flag.Parse()
+
+	registry := builtin.NewRegistry(builtin.Options{})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "language\tKB\tsymbols\tms/parse\tsymbols/sec\tMB/sec\t")
+	failed := false
+	for _, f := range parsebench.Corpus(*scale) {
+		if *lang != "" && f.Language != *lang {
+			continue
+		}
+		res, err := parsebench.Measure(registry, f, *runs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", f.Language, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.0f\t%.2f\t\n",
+			res.Language, res.Bytes/1024, res.Symbols,
+			float64(res.PerParse().Microseconds())/1000, res.SymbolsPerSec(), res.MBPerSec())
+	}
+	w.Flush()
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,75 @@
+// reembed regenerates symbol embeddings for a project with the currently configured model,
+// e.g. after switching OPENROUTER_MODEL. Symbols and edges are left untouched; only vectors
+// from a different model are replaced. Interrupt with Ctrl-C and re-run to resume.
+// Run from project root: go run ./cmd/reembed -project my-project
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/maraichr/lattice/internal/config"
+	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func main() {
+	slug := flag.String("project", "", "slug of the project to re-embed (required)")
+	pageSize := flag.Int("page-size", embedding.DefaultReembedPageSize, "symbols per embedding request")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+
+	if *slug == "" {
+		fmt.Fprintln(os.Stderr, "usage: reembed -project <slug> [-page-size N]")
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("failed to load config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := postgres.NewPool(ctx, cfg.Database.DSN(), cfg.Database.MaxConns, cfg.Database.MinConns)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	s := store.NewWithTimeouts(pool, store.Timeouts{Read: cfg.Database.WriteTimeout, Write: cfg.Database.WriteTimeout})
+
+	embedder, err := embedding.NewEmbedder(ctx, cfg, s)
+	if err != nil {
+		logger.Error("embedder init failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if embedder == nil {
+		logger.Error("no embedding provider configured (set OPENROUTER_API_KEY or BEDROCK_REGION)")
+		os.Exit(1)
+	}
+
+	project, err := s.GetProject(ctx, *slug)
+	if err != nil {
+		logger.Error("failed to load project", slog.String("project", *slug), slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	count, err := embedding.Reembed(ctx, embedder, s, project.ID, embedding.KindFilterFromSettings(project.Settings), *pageSize, logger)
+	if err != nil {
+		logger.Error("reembed stopped", slog.Int("embedded", count), slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
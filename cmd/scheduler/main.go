@@ -3,9 +3,18 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	"github.com/maraichr/lattice/internal/config"
+	"github.com/maraichr/lattice/internal/ingestion"
+	"github.com/maraichr/lattice/internal/scheduler"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	vk "github.com/maraichr/lattice/internal/store/valkey"
 )
 
 func main() {
@@ -13,16 +22,64 @@ func main() {
 		Level: slog.LevelInfo,
 	}))
 
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("failed to load config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	logger.Info("starting scheduler")
+	// Database
+	pool, err := postgres.NewPool(ctx, cfg.Database.DSN(), cfg.Database.MaxConns, cfg.Database.MinConns)
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer pool.Close()
+	logger.Info("connected to database")
+
+	s := store.NewWithTimeouts(pool, store.Timeouts{Read: cfg.Database.ReadTimeout, Write: cfg.Database.WriteTimeout})
+
+	// Valkey
+	vkClient, err := vk.NewClient(cfg.Valkey)
+	if err != nil {
+		logger.Error("failed to connect to valkey", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer vkClient.Close()
+	logger.Info("connected to valkey")
+
+	sched := scheduler.New(s, ingestion.NewProducer(vkClient), scheduler.NewValkeyLocker(vkClient), logger)
+
+	// Push webhooks from GitHub and GitLab
+	if cfg.Scheduler.WebhookSecret == "" {
+		logger.Warn("WEBHOOK_SECRET is not set, webhooks will be refused")
+	}
+	srv := &http.Server{
+		Addr:         cfg.Scheduler.Addr,
+		Handler:      sched.WebhookHandler(cfg.Scheduler.WebhookSecret),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+	go func() {
+		logger.Info("starting webhook server", slog.String("addr", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("server error", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}()
+
+	// Cron schedules; the first tick catches up on runs missed while stopped
+	logger.Info("starting scheduler", slog.Duration("tick_interval", cfg.Scheduler.TickInterval))
+	sched.Run(ctx, cfg.Scheduler.TickInterval)
 
-	// TODO: Initialize scheduler with:
-	// - Cron-based index run scheduling
-	// - Webhook-triggered re-indexing
-	// - Job queue management via Valkey
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown error", slog.String("error", err.Error()))
+	}
 
-	<-ctx.Done()
 	logger.Info("scheduler stopped")
 }
@@ -16,12 +16,8 @@ import (
 	"github.com/maraichr/lattice/internal/ingestion/connectors"
 	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/parser"
-	"github.com/maraichr/lattice/internal/parser/asp"
-	"github.com/maraichr/lattice/internal/parser/delphi"
-	csharpp "github.com/maraichr/lattice/internal/parser/csharp"
-	javap "github.com/maraichr/lattice/internal/parser/java"
+	"github.com/maraichr/lattice/internal/parser/builtin"
 	jsts "github.com/maraichr/lattice/internal/parser/javascript"
-	"github.com/maraichr/lattice/internal/parser/pgsql"
 	"github.com/maraichr/lattice/internal/parser/tsql"
 	"github.com/maraichr/lattice/internal/resolver"
 	"github.com/maraichr/lattice/internal/store"
@@ -53,7 +49,8 @@ func main() {
 	defer pool.Close()
 	logger.Info("connected to database")
 
-	s := store.New(pool)
+	// Ingestion reads whole projects, so the worker gives reads the write budget too
+	s := store.NewWithTimeouts(pool, store.Timeouts{Read: cfg.Database.WriteTimeout, Write: cfg.Database.WriteTimeout})
 
 	// Valkey
 	vkClient, err := vk.NewClient(cfg.Valkey)
@@ -99,34 +96,36 @@ func main() {
 		}
 	}
 
-	// Parser registry
-	registry := parser.NewRegistry()
-	sqlRouter := parser.NewSQLRouter(tsql.New(), pgsql.New())
-	registry.Register(".sql", sqlRouter)
-	registry.Register(".sqldataprovider", sqlRouter)
-	aspParser := asp.New()
-	registry.Register(".asp", aspParser)
-	registry.Register(".aspx", aspParser)
-	registry.Register(".ascx", aspParser)
-	registry.Register(".ashx", aspParser)
-	registry.Register(".master", aspParser)
-	delphiParser := delphi.New()
-	registry.Register(".pas", delphiParser)
-	registry.Register(".dfm", delphiParser)
-	registry.Register(".dpr", delphiParser)
-	registry.Register(".java", javap.New())
-	registry.Register(".cs", csharpp.New())
-	jsParser := jsts.NewJS()
-	registry.Register(".js", jsParser)
-	registry.Register(".jsx", jsParser)
-	registry.Register(".mjs", jsParser)
-	tsParser := jsts.NewTS()
-	registry.Register(".ts", tsParser)
-	registry.Register(".tsx", tsParser)
+	// GitHub connector (optional; public repositories need no token)
+	var githubConn *connectors.GitHubConnector
+	if cfg.GitHub.Enabled {
+		githubConn = connectors.NewGitHubConnector(cfg.GitHub)
+		logger.Info("github connector enabled", slog.Bool("token", cfg.GitHub.Token != ""))
+	}
+
+	jsConfidence, err := jsts.NewConfidence(cfg.Parser.JSPatternConfidence)
+	if err != nil {
+		logger.Error("invalid JS_PATTERN_CONFIDENCE", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Parser registries: tree-sitter parsers are not goroutine-safe, so each concurrent
+	// job gets a registry of its own
+	parserOpts := builtin.Options{
+		TSQLLimits: tsql.Limits{
+			MaxNestingDepth:    cfg.Parser.TSQLMaxNestingDepth,
+			MaxStatementTokens: cfg.Parser.TSQLMaxStatementTokens,
+		},
+		JSConfidence:        jsConfidence,
+		DetectMinConfidence: cfg.Parser.DetectMinConfidence,
+	}
+	registries := parser.NewRegistryPool(cfg.Parser.Concurrency, func() *parser.Registry {
+		return builtin.NewRegistry(parserOpts)
+	})
 
 	// Embeddings (auto-selects: OpenRouter > Bedrock > disabled)
 	var embedStage ingestion.Stage
-	embedder, err := embedding.NewEmbedder(cfg)
+	embedder, err := embedding.NewEmbedder(ctx, cfg, s)
 	if err != nil {
 		logger.Warn("embedder init failed, embedding stage disabled", slog.String("error", err.Error()))
 		embedStage = ingestion.NewNoOpStage("embed")
@@ -138,20 +137,43 @@ func main() {
 	}
 
 	// Resolver engine
-	resolverEngine := resolver.NewEngine(s, logger)
+	resolverEngine := resolver.NewEngine(s, resolver.NewIgnoreList(cfg.Resolver.IgnoreSymbols), cfg.Database.EdgeBatchSize, logger)
+	tablePrecedence, err := resolver.NewTablePrecedence(cfg.Resolver.TablePrecedence, cfg.Resolver.TableConflict != "demote")
+	if err != nil {
+		logger.Error("invalid RESOLVER_TABLE_PRECEDENCE", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	resolverEngine.SetTablePrecedence(tablePrecedence)
+	resolverEngine.SetCaseSensitiveRoutes(cfg.Resolver.RouteCase == "sensitive")
 
 	// Lineage engine
 	lineageEngine := lineage.NewEngine(s, graphClient, logger)
 
 	// Analytics engine (degree, PageRank, layers, summaries, bridges)
 	analyticsEngine := analytics.NewEngine(s, logger)
+	analyticsEngine.SetSampling(analytics.Sampling{
+		Threshold:            cfg.Analytics.SamplingThreshold,
+		Walks:                cfg.Analytics.SampleWalks,
+		Seed:                 cfg.Analytics.SampleSeed,
+		BetweennessThreshold: cfg.Analytics.BetweennessThreshold,
+		Pivots:               cfg.Analytics.BetweennessPivots,
+	})
+	analyticsEngine.SetLowConfidenceThreshold(cfg.Analytics.LowConfidenceThreshold)
+	analyticsEngine.SetMaxCommunityNodes(cfg.Analytics.MaxCommunityNodes)
+
+	parseStage := ingestion.NewParseStage(registries, s, cfg.Database.EdgeBatchSize, cfg.Parser.MaxSymbolsPerFile)
+	parseStage.SetSymbolLimits(ingestion.SymbolLimits{
+		Warn: cfg.Parser.ProjectSymbolWarn,
+		Max:  cfg.Parser.ProjectSymbolLimit,
+	}, logger)
 
 	// Pipeline stages
 	stages := []ingestion.Stage{
-		ingestion.NewCloneStage(s, zipConn, gitConn, s3Conn),
-		ingestion.NewParseStage(registry, s),
+		ingestion.NewCloneStage(s, zipConn, gitConn, githubConn, s3Conn),
+		parseStage,
 		ingestion.NewResolveStage(resolverEngine),
 		ingestion.NewLineageStage(lineageEngine, logger),
+		ingestion.NewSchemaSnapshotStage(s, logger),
 		ingestion.NewGraphStage(s, graphClient, logger),
 		embedStage,
 		ingestion.NewAnalyticsStage(analyticsEngine, logger),
@@ -161,12 +183,16 @@ func main() {
 
 	// Consumer
 	consumer := ingestion.NewConsumer(vkClient, "worker-1", logger)
+	consumer.SetConcurrency(cfg.Parser.Concurrency)
 	if err := consumer.EnsureGroup(ctx); err != nil {
 		logger.Error("failed to ensure consumer group", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	logger.Info("starting worker, consuming from stream", slog.String("stream", ingestion.StreamName))
+	// Hard-delete soft-deleted projects once their retention window has passed
+	go s.RunPurge(ctx, cfg.Retention.SoftDelete, cfg.Retention.PurgeInterval, logger)
+
+	logger.Info("starting worker, consuming from stream", slog.String("stream", ingestion.StreamName), slog.Int("concurrency", cfg.Parser.Concurrency))
 
 	if err := consumer.Consume(ctx, pipeline.Run); err != nil {
 		if ctx.Err() != nil {
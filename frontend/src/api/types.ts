@@ -6,6 +6,7 @@ export interface Project {
   settings: Record<string, unknown>;
   created_at: string;
   updated_at: string;
+  archived_at: string | null;
 }
 
 export interface Source {
@@ -9,9 +9,10 @@ import { IndexRunTimeline } from "./IndexRunTimeline";
 
 interface Props {
   projectSlug: string;
+  readOnly?: boolean;
 }
 
-export function IndexRunList({ projectSlug }: Props) {
+export function IndexRunList({ projectSlug, readOnly = false }: Props) {
   const { data, isLoading, error, refetch } = useIndexRuns(projectSlug);
   const trigger = useTriggerIndexRun(projectSlug);
   const [selectedRun, setSelectedRun] = useState<IndexRun | null>(null);
@@ -40,9 +41,11 @@ export function IndexRunList({ projectSlug }: Props) {
     <div>
       <div className="mb-3 flex items-center justify-between">
         <h4 className="text-sm font-medium">Index Runs</h4>
-        <Button size="sm" onClick={() => trigger.mutate(undefined)} disabled={trigger.isPending}>
-          {trigger.isPending ? "Triggering..." : "Trigger Run"}
-        </Button>
+        {!readOnly && (
+          <Button size="sm" onClick={() => trigger.mutate(undefined)} disabled={trigger.isPending}>
+            {trigger.isPending ? "Triggering..." : "Trigger Run"}
+          </Button>
+        )}
       </div>
 
       {runs.length === 0 ? (
@@ -1,5 +1,6 @@
 import { Link } from "react-router";
 import type { Project } from "../../api/types";
+import { Badge } from "../ui/badge";
 import { Card, CardContent, CardHeader, CardTitle } from "../ui/card";
 
 interface Props {
@@ -11,7 +12,10 @@ export function ProjectCard({ project }: Props) {
     <Link to={`/projects/${project.slug}`}>
       <Card className="transition-all hover:border-primary/30 hover:shadow-[0_0_15px_rgba(0,210,210,0.08)]">
         <CardHeader>
-          <CardTitle>{project.name}</CardTitle>
+          <div className="flex items-center justify-between gap-2">
+            <CardTitle>{project.name}</CardTitle>
+            {project.archived_at && <Badge variant="outline">Archived</Badge>}
+          </div>
           <p className="text-sm font-mono text-muted-foreground">{project.slug}</p>
         </CardHeader>
         <CardContent>
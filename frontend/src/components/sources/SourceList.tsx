@@ -4,9 +4,10 @@ import { ErrorState } from "../ui/ErrorState";
 
 interface Props {
   projectSlug: string;
+  readOnly?: boolean;
 }
 
-export function SourceList({ projectSlug }: Props) {
+export function SourceList({ projectSlug, readOnly = false }: Props) {
   const { data, isLoading, error, refetch } = useSources(projectSlug);
   const deleteSource = useDeleteSource(projectSlug);
 
@@ -38,13 +39,15 @@ export function SourceList({ projectSlug }: Props) {
                 ` \u00B7 Last synced ${new Date(source.last_synced_at).toLocaleString()}`}
             </p>
           </div>
-          <button
-            type="button"
-            onClick={() => deleteSource.mutate(source.id)}
-            className="text-xs text-red-600 hover:text-red-800"
-          >
-            Remove
-          </button>
+          {!readOnly && (
+            <button
+              type="button"
+              onClick={() => deleteSource.mutate(source.id)}
+              className="text-xs text-red-600 hover:text-red-800"
+            >
+              Remove
+            </button>
+          )}
         </li>
       ))}
     </ul>
@@ -24,6 +24,7 @@ import { IndexRunList } from "../components/indexruns/IndexRunList";
 import { AddSourceDialog } from "../components/sources/AddSourceDialog";
 import { SourceList } from "../components/sources/SourceList";
 import { ZipUpload } from "../components/sources/ZipUpload";
+import { Badge } from "../components/ui/badge";
 import { Button } from "../components/ui/button";
 import { Card, CardContent, CardHeader, CardTitle } from "../components/ui/card";
 import { ErrorState } from "../components/ui/ErrorState";
@@ -74,10 +75,16 @@ export function ProjectDashboard() {
     );
   }
 
+  // Archived projects are read-only until unarchived
+  const archived = project.archived_at !== null;
+
   return (
     <div className="space-y-6">
       <div>
-        <h2 className="text-2xl font-bold">{project.name}</h2>
+        <div className="flex items-center gap-3">
+          <h2 className="text-2xl font-bold">{project.name}</h2>
+          {archived && <Badge variant="outline">Archived · read-only</Badge>}
+        </div>
         {project.description && (
           <p className="mt-1 text-sm text-muted-foreground">{project.description}</p>
         )}
@@ -104,16 +111,20 @@ export function ProjectDashboard() {
         <Card>
           <CardHeader className="flex-row items-center justify-between space-y-0">
             <CardTitle className="text-sm">Sources</CardTitle>
-            <Button size="sm" onClick={() => setShowAddSource(true)}>
-              Add Source
-            </Button>
+            {!archived && (
+              <Button size="sm" onClick={() => setShowAddSource(true)}>
+                Add Source
+              </Button>
+            )}
           </CardHeader>
           <CardContent>
-            <SourceList projectSlug={slug} />
-            <div className="mt-4 border-t pt-4">
-              <p className="mb-2 text-xs font-medium text-muted-foreground">Quick Upload</p>
-              <ZipUpload projectSlug={slug} />
-            </div>
+            <SourceList projectSlug={slug} readOnly={archived} />
+            {!archived && (
+              <div className="mt-4 border-t pt-4">
+                <p className="mb-2 text-xs font-medium text-muted-foreground">Quick Upload</p>
+                <ZipUpload projectSlug={slug} />
+              </div>
+            )}
           </CardContent>
         </Card>
 
@@ -127,7 +138,7 @@ export function ProjectDashboard() {
 
       <Card>
         <CardContent className="p-6">
-          <IndexRunList projectSlug={slug} />
+          <IndexRunList projectSlug={slug} readOnly={archived} />
         </CardContent>
       </Card>
 
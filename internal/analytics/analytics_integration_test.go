@@ -282,6 +282,60 @@ func TestComputeProjectSummaries_Integration(t *testing.T) {
 	}
 }
 
+func TestComputeCommunities_DropsStaleAndSkippedRows(t *testing.T) {
+	s := setupStore(t)
+	projID, cleanup := seedTestGraph(t, s)
+	defer cleanup()
+
+	engine := NewEngine(s, slog.Default())
+	ctx := context.Background()
+
+	// A community from an earlier run that no longer exists
+	stale := "stale community"
+	if _, err := s.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projID,
+		Scope:     "communities",
+		ScopeID:   "99",
+		Analytics: []byte(`{"community_id": 99, "size": 12}`),
+		Summary:   &stale,
+	}); err != nil {
+		t.Fatalf("upsert stale community: %v", err)
+	}
+
+	if err := engine.ComputeCommunities(ctx, projID); err != nil {
+		t.Fatalf("ComputeCommunities: %v", err)
+	}
+	rows, err := s.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{ProjectID: projID, Scope: "communities"})
+	if err != nil {
+		t.Fatalf("list communities: %v", err)
+	}
+	for _, r := range rows {
+		if r.ScopeID == "99" {
+			t.Error("expected the stale community row to be deleted")
+		}
+	}
+
+	// Over the ceiling, every community row goes and the overview says why
+	engine.SetMaxCommunityNodes(2)
+	if err := engine.ComputeCommunities(ctx, projID); err != nil {
+		t.Fatalf("ComputeCommunities over the ceiling: %v", err)
+	}
+	rows, err = s.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{ProjectID: projID, Scope: "communities"})
+	if err != nil {
+		t.Fatalf("list communities: %v", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("expected no community rows once skipped, got %d", len(rows))
+	}
+	overview, err := s.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{ProjectID: projID, Scope: "project", ScopeID: "communities"})
+	if err != nil {
+		t.Fatalf("get community overview: %v", err)
+	}
+	if overview.Summary == nil || *overview.Summary != "Community detection skipped: 4 symbols exceed the ceiling of 2." {
+		t.Errorf("expected the skip to be recorded, got %v", overview.Summary)
+	}
+}
+
 func TestComputeCrossLanguageBridges_Integration(t *testing.T) {
 	s := setupStore(t)
 	projID, cleanup := seedTestGraph(t, s)
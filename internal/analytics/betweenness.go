@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// ComputeBetweenness computes the betweenness centrality of every symbol: the share of
+// shortest paths between other symbols that pass through it, normalized to 0-1. Unlike
+// in-degree it is high for the symbols that connect parts of the graph rather than for
+// the utilities everything calls. The value is stored as betweenness in the metadata.
+// Graphs over the betweenness threshold are estimated from sampled pivots (see Sampling);
+// every score is then flagged betweenness_approximate and the run is recorded under the
+// "centrality" analytics scope.
+func (e *Engine) ComputeBetweenness(ctx context.Context, projectID uuid.UUID) error {
+	edges, err := e.store.GetEdgeList(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get edge list: %w", err)
+	}
+	if len(edges) == 0 {
+		e.logger.Info("no edges for betweenness")
+		return nil
+	}
+
+	scores, sampled := betweennessCentrality(edges, e.sampling)
+	e.logger.Info("computing betweenness", slog.Int("nodes", len(scores)), slog.Bool("sampled", sampled))
+
+	updates := make(map[uuid.UUID]map[string]any, len(scores))
+	for node, score := range scores {
+		updates[node] = map[string]any{
+			"betweenness":             math.Round(score*1e6) / 1e6,
+			"betweenness_approximate": sampled,
+		}
+	}
+	e.updateSymbolsMetadata(ctx, updates, "betweenness")
+	e.recordBetweennessRun(ctx, projectID, len(scores), len(edges), sampled)
+	return nil
+}
+
+// recordBetweennessRun adds how betweenness was computed to the centrality run that
+// ComputePageRank stored for the project.
+func (e *Engine) recordBetweennessRun(ctx context.Context, projectID uuid.UUID, nodes, edges int, sampled bool) {
+	run := CentralityRun{Mode: CentralityExact, Nodes: nodes, Edges: edges}
+	if stored, err := e.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "centrality",
+	}); err == nil {
+		_ = json.Unmarshal(stored.Analytics, &run)
+	}
+
+	run.BetweennessMode, run.BetweennessThreshold, run.Pivots = CentralityExact, 0, 0
+	if sampled {
+		run.BetweennessMode = CentralitySampled
+		run.BetweennessThreshold, run.Pivots, run.Seed = e.sampling.BetweennessThreshold, max(e.sampling.Pivots, 1), e.sampling.Seed
+	}
+
+	runJSON, _ := json.Marshal(run)
+	summary := run.Summary()
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "centrality",
+		Analytics: runJSON,
+		Summary:   &summary,
+	}); err != nil {
+		e.logger.Warn("failed to upsert centrality analytics", slog.String("error", err.Error()))
+	}
+}
+
+// betweennessCentrality runs Brandes' algorithm over the directed edge list from every
+// node, or from Pivots seeded nodes when the graph is over the BetweennessThreshold, and
+// reports whether it sampled. Scores are divided by (n-1)(n-2), the number of ordered pairs a
+// node can lie between.
+func betweennessCentrality(edges []postgres.GetEdgeListRow, sampling Sampling) (map[uuid.UUID]float64, bool) {
+	index := make(map[uuid.UUID]int)
+	var nodes []uuid.UUID
+	indexOf := func(id uuid.UUID) int {
+		i, ok := index[id]
+		if !ok {
+			i = len(nodes)
+			index[id] = i
+			nodes = append(nodes, id)
+		}
+		return i
+	}
+	var outLinks [][]int
+	for _, edge := range edges {
+		src, tgt := indexOf(edge.SourceID), indexOf(edge.TargetID)
+		for len(outLinks) < len(nodes) {
+			outLinks = append(outLinks, nil)
+		}
+		if src != tgt {
+			outLinks[src] = append(outLinks[src], tgt)
+		}
+	}
+	n := len(nodes)
+
+	sources := make([]int, n)
+	for i := range sources {
+		sources[i] = i
+	}
+	sampled := sampling.engagesBetweenness(n)
+	if sampled {
+		rng := rand.New(rand.NewSource(sampling.Seed))
+		rng.Shuffle(n, func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+		sources = sources[:max(sampling.Pivots, 1)]
+	}
+
+	score := make([]float64, n)
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	for _, s := range sources {
+		for i := range n {
+			sigma[i], dist[i], delta[i], preds[i] = 0, -1, 0, preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+		order := []int{s}
+		for head := 0; head < len(order); head++ {
+			v := order[head]
+			for _, w := range outLinks[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					order = append(order, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		// Accumulate dependencies, farthest nodes first
+		for i := len(order) - 1; i > 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			score[w] += delta[w]
+		}
+	}
+
+	scale := 1.0
+	if n > 2 {
+		scale = 1 / float64((n-1)*(n-2))
+	}
+	if sampled {
+		scale *= float64(n) / float64(len(sources))
+	}
+	scores := make(map[uuid.UUID]float64, n)
+	for i, id := range nodes {
+		scores[id] = math.Min(score[i]*scale, 1)
+	}
+	return scores, sampled
+}
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestBetweennessCentrality(t *testing.T) {
+	a, b, hub, c, d, util := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	edge := func(src, tgt uuid.UUID) postgres.GetEdgeListRow {
+		return postgres.GetEdgeListRow{SourceID: src, TargetID: tgt}
+	}
+	// hub joins a and b to c and d; util is called by everyone but leads nowhere
+	edges := []postgres.GetEdgeListRow{
+		edge(a, hub), edge(b, hub), edge(hub, c), edge(hub, d),
+		edge(a, util), edge(b, util), edge(c, util), edge(d, util), edge(hub, util),
+	}
+
+	scores, sampled := betweennessCentrality(edges, DefaultSampling())
+	if sampled {
+		t.Fatal("expected a small graph to be computed exactly")
+	}
+	// four of the 20 ordered pairs of other nodes route through hub: a and b to c and d
+	if got, want := scores[hub], 4.0/20.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected hub betweenness %v, got %v", want, got)
+	}
+	if scores[util] != 0 || scores[a] != 0 {
+		t.Errorf("expected the sink and the sources to lie on no paths, got util=%v a=%v", scores[util], scores[a])
+	}
+
+	scores, sampled = betweennessCentrality(edges, Sampling{BetweennessThreshold: 2, Pivots: 3, Seed: DefaultSampleSeed})
+	if !sampled {
+		t.Fatal("expected a graph over the exact limit to be sampled")
+	}
+	for id, s := range scores {
+		if s < 0 || s > 1 {
+			t.Errorf("expected sampled scores within 0-1, got %v for %s", s, id)
+		}
+	}
+
+	// A zero threshold always computes betweenness exactly
+	if _, sampled = betweennessCentrality(edges, Sampling{Pivots: 3}); sampled {
+		t.Error("expected a zero betweenness threshold to disable sampling")
+	}
+}
+
+func TestCentralityRunSummary_SampledBetweenness(t *testing.T) {
+	run := CentralityRun{Mode: CentralityExact, Nodes: 8000, Edges: 20000,
+		BetweennessMode: CentralitySampled, BetweennessThreshold: 5000, Pivots: 1000, Seed: 1}
+
+	summary := run.Summary()
+	for _, want := range []string{"PageRank is exact", "Betweenness is approximate", "threshold of 5000", "1000 sampled symbols"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected summary to contain %q: %s", want, summary)
+		}
+	}
+}
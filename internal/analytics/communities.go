@@ -0,0 +1,404 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// DefaultMaxCommunityNodes gates Louvain: projects with more symbols skip community
+// detection unless the ceiling is raised with SetMaxCommunityNodes.
+const DefaultMaxCommunityNodes = 50_000
+
+const (
+	maxLouvainLevels    = 10
+	maxLouvainPasses    = 20
+	communitySampleSize = 10
+	communityMinGainEps = 1e-12
+)
+
+// ComputeCommunities runs Louvain modularity optimization over the symbol graph and
+// stores each symbol's community_id plus per-community summaries under the
+// "communities" analytics scope, dropping the rows of communities no longer found.
+// Communities reveal de facto modules that may not match the folder or namespace
+// structure. Projects over the node ceiling keep no communities.
+func (e *Engine) ComputeCommunities(ctx context.Context, projectID uuid.UUID) error {
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+	if len(symbols) == 0 {
+		return e.deleteStaleCommunities(ctx, projectID, nil)
+	}
+	if e.maxCommunityNodes > 0 && len(symbols) > e.maxCommunityNodes {
+		e.logger.Info("skipping community detection: graph exceeds node ceiling",
+			slog.Int("symbols", len(symbols)),
+			slog.Int("ceiling", e.maxCommunityNodes))
+		return e.skipCommunities(ctx, projectID, symbols)
+	}
+
+	edgeRows, err := e.store.GetEdgeList(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get edge list: %w", err)
+	}
+
+	nodeIndex := make(map[uuid.UUID]int, len(symbols))
+	for i, sym := range symbols {
+		nodeIndex[sym.ID] = i
+	}
+	edges := make([][2]int, 0, len(edgeRows))
+	for _, er := range edgeRows {
+		src, ok1 := nodeIndex[er.SourceID]
+		tgt, ok2 := nodeIndex[er.TargetID]
+		if ok1 && ok2 {
+			edges = append(edges, [2]int{src, tgt})
+		}
+	}
+
+	e.logger.Info("computing communities via louvain",
+		slog.Int("symbols", len(symbols)),
+		slog.Int("edges", len(edges)))
+
+	labels := louvain(len(symbols), edges)
+	modularity := computeModularity(len(symbols), edges, labels)
+
+	// Number communities by size (largest = 1); tiny groups stay unassigned (0).
+	members := make(map[int][]int)
+	for i, label := range labels {
+		members[label] = append(members[label], i)
+	}
+	var ordered []int
+	for label, idxs := range members {
+		if len(idxs) >= clusterMinSize {
+			ordered = append(ordered, label)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		li, lj := len(members[ordered[i]]), len(members[ordered[j]])
+		if li != lj {
+			return li > lj
+		}
+		return members[ordered[i]][0] < members[ordered[j]][0]
+	})
+
+	assigned := make(map[uuid.UUID]bool, len(symbols))
+	communityIDs := make([]string, 0, len(ordered))
+	for n, label := range ordered {
+		communityID := n + 1
+		idxs := members[label]
+		communityIDs = append(communityIDs, fmt.Sprintf("%d", communityID))
+
+		ids := make([]uuid.UUID, len(idxs))
+		languages := make(map[string]int)
+		kinds := make(map[string]int)
+		sample := make([]string, 0, communitySampleSize)
+		for i, idx := range idxs {
+			sym := symbols[idx]
+			ids[i] = sym.ID
+			assigned[sym.ID] = true
+			languages[sym.Language]++
+			kinds[sym.Kind]++
+			if len(sample) < communitySampleSize {
+				sample = append(sample, sym.QualifiedName)
+			}
+		}
+
+		metaJSON, _ := json.Marshal(map[string]any{"community_id": communityID})
+		if err := e.store.BatchUpdateSymbolMetadata(ctx, postgres.BatchUpdateSymbolMetadataParams{
+			AnalyticsJson: metaJSON,
+			SymbolIds:     ids,
+		}); err != nil {
+			e.logger.Warn("failed to update community", slog.Int("community_id", communityID), slog.String("error", err.Error()))
+		}
+
+		communityJSON, _ := json.Marshal(map[string]any{
+			"community_id": communityID,
+			"size":         len(idxs),
+			"languages":    languages,
+			"kinds":        kinds,
+			"sample":       sample,
+		})
+		summary := fmt.Sprintf("Community %d: %d symbols", communityID, len(idxs))
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "communities",
+			ScopeID:   fmt.Sprintf("%d", communityID),
+			Analytics: communityJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert community analytics", slog.Int("community_id", communityID))
+		}
+	}
+	if err := e.deleteStaleCommunities(ctx, projectID, communityIDs); err != nil {
+		return err
+	}
+
+	var unassigned []uuid.UUID
+	for _, sym := range symbols {
+		if !assigned[sym.ID] {
+			unassigned = append(unassigned, sym.ID)
+		}
+	}
+	if len(unassigned) > 0 {
+		metaJSON, _ := json.Marshal(map[string]any{"community_id": 0})
+		if err := e.store.BatchUpdateSymbolMetadata(ctx, postgres.BatchUpdateSymbolMetadataParams{
+			AnalyticsJson: metaJSON,
+			SymbolIds:     unassigned,
+		}); err != nil {
+			e.logger.Warn("failed to clear community for unassigned symbols", slog.String("error", err.Error()))
+		}
+	}
+
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"community_count":    len(ordered),
+		"modularity":         math.Round(modularity*1000) / 1000,
+		"unassigned_symbols": len(unassigned),
+	})
+	summary := fmt.Sprintf("%d communities detected (modularity %.3f); %d symbols unassigned.",
+		len(ordered), modularity, len(unassigned))
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "communities",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert community overview: %w", err)
+	}
+
+	e.logger.Info("communities computed",
+		slog.Int("communities", len(ordered)),
+		slog.Float64("modularity", modularity))
+	return nil
+}
+
+// skipCommunities clears the communities of a project over the node ceiling, so results
+// from before it grew past the ceiling are not mistaken for current ones, and records why
+// there are none.
+func (e *Engine) skipCommunities(ctx context.Context, projectID uuid.UUID, symbols []postgres.Symbol) error {
+	ids := make([]uuid.UUID, len(symbols))
+	for i, sym := range symbols {
+		ids[i] = sym.ID
+	}
+	metaJSON, _ := json.Marshal(map[string]any{"community_id": 0})
+	if err := e.store.BatchUpdateSymbolMetadata(ctx, postgres.BatchUpdateSymbolMetadataParams{
+		AnalyticsJson: metaJSON,
+		SymbolIds:     ids,
+	}); err != nil {
+		e.logger.Warn("failed to clear communities", slog.String("error", err.Error()))
+	}
+	if err := e.deleteStaleCommunities(ctx, projectID, nil); err != nil {
+		return err
+	}
+
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"community_count": 0,
+		"skipped":         true,
+		"symbols":         len(symbols),
+		"ceiling":         e.maxCommunityNodes,
+	})
+	summary := fmt.Sprintf("Community detection skipped: %d symbols exceed the ceiling of %d.", len(symbols), e.maxCommunityNodes)
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "communities",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert community overview: %w", err)
+	}
+	return nil
+}
+
+// deleteStaleCommunities drops the community rows whose ID is not in keep, all of them
+// when keep is empty.
+func (e *Engine) deleteStaleCommunities(ctx context.Context, projectID uuid.UUID, keep []string) error {
+	if keep == nil {
+		keep = []string{} // a NULL array would match no row and keep them all
+	}
+	if err := e.store.DeleteStaleProjectAnalytics(ctx, postgres.DeleteStaleProjectAnalyticsParams{
+		ProjectID:    projectID,
+		Scope:        "communities",
+		KeepScopeIds: keep,
+	}); err != nil {
+		return fmt.Errorf("delete stale communities: %w", err)
+	}
+	return nil
+}
+
+// louvain partitions an undirected graph of n nodes into communities by greedy
+// modularity optimization (Blondel et al. 2008). Edges are index pairs; repeated
+// edges add weight and direction is ignored. Nodes are visited in index order so the
+// result is deterministic. Returns a community label per node.
+func louvain(n int, edges [][2]int) []int {
+	// Level graph: symmetric adjacency (without self-loops) and self-loop weights.
+	adj := make([]map[int]float64, n)
+	self := make([]float64, n)
+	for i := range adj {
+		adj[i] = make(map[int]float64)
+	}
+	for _, e := range edges {
+		u, v := e[0], e[1]
+		if u == v {
+			self[u]++
+			continue
+		}
+		adj[u][v]++
+		adj[v][u]++
+	}
+
+	// partition maps each original node to its node in the current level graph.
+	partition := make([]int, n)
+	for i := range partition {
+		partition[i] = i
+	}
+
+	for range maxLouvainLevels {
+		comm, moved := louvainLocalMoving(adj, self)
+		if !moved {
+			break
+		}
+
+		// Renumber communities densely in order of first appearance.
+		renum := make(map[int]int)
+		for _, c := range comm {
+			if _, ok := renum[c]; !ok {
+				renum[c] = len(renum)
+			}
+		}
+		for i := range partition {
+			partition[i] = renum[comm[partition[i]]]
+		}
+
+		// Aggregate: each community becomes a node of the next level graph.
+		k := len(renum)
+		nextAdj := make([]map[int]float64, k)
+		nextSelf := make([]float64, k)
+		for i := range nextAdj {
+			nextAdj[i] = make(map[int]float64)
+		}
+		for i := range adj {
+			ci := renum[comm[i]]
+			nextSelf[ci] += self[i]
+			for j, w := range adj[i] {
+				cj := renum[comm[j]]
+				if ci == cj {
+					nextSelf[ci] += w / 2 // each undirected edge is visited from both ends
+				} else {
+					nextAdj[ci][cj] += w
+				}
+			}
+		}
+		adj, self = nextAdj, nextSelf
+		if k == 1 {
+			break
+		}
+	}
+
+	return partition
+}
+
+// louvainLocalMoving runs the first Louvain phase on a level graph: nodes repeatedly
+// move to the neighboring community with the largest modularity gain until no move
+// helps. Returns the community of each node and whether any node moved.
+func louvainLocalMoving(adj []map[int]float64, self []float64) ([]int, bool) {
+	n := len(adj)
+	degree := make([]float64, n)
+	var m2 float64 // twice the total edge weight
+	for i := range adj {
+		for _, w := range adj[i] {
+			degree[i] += w
+		}
+		degree[i] += 2 * self[i]
+		m2 += degree[i]
+	}
+
+	comm := make([]int, n)
+	tot := make([]float64, n) // total degree per community
+	for i := range comm {
+		comm[i] = i
+		tot[i] = degree[i]
+	}
+	if m2 == 0 {
+		return comm, false
+	}
+
+	movedAny := false
+	for range maxLouvainPasses {
+		moved := false
+		for i := range n {
+			// Weight from i to each neighboring community.
+			links := make(map[int]float64)
+			for j, w := range adj[i] {
+				links[comm[j]] += w
+			}
+
+			// Remove i from its community.
+			current := comm[i]
+			tot[current] -= degree[i]
+
+			// Visit candidate communities in a fixed order so ties resolve deterministically;
+			// staying put wins any tie.
+			candidates := make([]int, 0, len(links))
+			for c := range links {
+				candidates = append(candidates, c)
+			}
+			sort.Ints(candidates)
+
+			best := current
+			bestGain := links[current] - tot[current]*degree[i]/m2
+			for _, c := range candidates {
+				gain := links[c] - tot[c]*degree[i]/m2
+				if gain > bestGain+communityMinGainEps {
+					best = c
+					bestGain = gain
+				}
+			}
+
+			comm[i] = best
+			tot[best] += degree[i]
+			if best != current {
+				moved = true
+				movedAny = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return comm, movedAny
+}
+
+// computeModularity returns the Newman modularity of a partition of an undirected graph.
+func computeModularity(n int, edges [][2]int, labels []int) float64 {
+	if len(edges) == 0 {
+		return 0
+	}
+	degree := make([]float64, n)
+	internal := make(map[int]float64)
+	for _, e := range edges {
+		degree[e[0]]++
+		degree[e[1]]++
+		if labels[e[0]] == labels[e[1]] {
+			internal[labels[e[0]]]++
+		}
+	}
+	m := float64(len(edges))
+	tot := make(map[int]float64)
+	for i, d := range degree {
+		tot[labels[i]] += d
+	}
+	var q float64
+	for c, t := range tot {
+		q += internal[c]/m - (t/(2*m))*(t/(2*m))
+	}
+	return q
+}
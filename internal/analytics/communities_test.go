@@ -0,0 +1,49 @@
+package analytics
+
+import "testing"
+
+// cliqueEdges returns every pair within [start, start+size).
+func cliqueEdges(start, size int) [][2]int {
+	var edges [][2]int
+	for i := start; i < start+size; i++ {
+		for j := i + 1; j < start+size; j++ {
+			edges = append(edges, [2]int{i, j})
+		}
+	}
+	return edges
+}
+
+func TestLouvain_TwoClusters(t *testing.T) {
+	edges := append(cliqueEdges(0, 5), cliqueEdges(5, 5)...)
+	edges = append(edges, [2]int{4, 5}) // single bridge between the cliques
+
+	labels := louvain(10, edges)
+
+	for i := 1; i < 5; i++ {
+		if labels[i] != labels[0] {
+			t.Errorf("node %d: expected community of node 0 (%d), got %d", i, labels[0], labels[i])
+		}
+	}
+	for i := 6; i < 10; i++ {
+		if labels[i] != labels[5] {
+			t.Errorf("node %d: expected community of node 5 (%d), got %d", i, labels[5], labels[i])
+		}
+	}
+	if labels[0] == labels[5] {
+		t.Errorf("expected the two cliques in distinct communities, both got %d", labels[0])
+	}
+
+	if q := computeModularity(10, edges, labels); q <= 0.3 {
+		t.Errorf("expected modularity > 0.3 for two clear clusters, got %.3f", q)
+	}
+}
+
+func TestLouvain_NoEdges(t *testing.T) {
+	labels := louvain(3, nil)
+	if len(labels) != 3 {
+		t.Fatalf("expected 3 labels, got %d", len(labels))
+	}
+	if labels[0] == labels[1] || labels[1] == labels[2] {
+		t.Errorf("isolated nodes should stay in their own communities, got %v", labels)
+	}
+}
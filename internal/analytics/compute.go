@@ -1,11 +1,13 @@
 package analytics
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
+	"slices"
 
 	"github.com/google/uuid"
 
@@ -21,16 +23,45 @@ const (
 
 // Engine computes graph analytics (centrality, summaries, bridges, layers) for a project.
 type Engine struct {
-	store  *store.Store
-	logger *slog.Logger
+	store             *store.Store
+	logger            *slog.Logger
+	sampling          Sampling
+	lowConfidence     float64 // edges below this confidence are low-trust (see ComputeLowConfidenceRegions)
+	maxCommunityNodes int     // projects with more symbols skip community detection; 0 never skips
 }
 
-// NewEngine creates a new analytics engine.
+// NewEngine creates a new analytics engine with the default sampling parameters,
+// low-confidence threshold and community detection ceiling.
 func NewEngine(s *store.Store, logger *slog.Logger) *Engine {
-	return &Engine{store: s, logger: logger}
+	return &Engine{
+		store:             s,
+		logger:            logger,
+		sampling:          DefaultSampling(),
+		lowConfidence:     DefaultLowConfidenceThreshold,
+		maxCommunityNodes: DefaultMaxCommunityNodes,
+	}
+}
+
+// SetSampling sets when and how centrality is approximated on large graphs.
+func (e *Engine) SetSampling(s Sampling) {
+	e.sampling = s
+}
+
+// SetLowConfidenceThreshold sets the edge confidence below which a link counts as
+// low-trust inference when flagging low-confidence regions.
+func (e *Engine) SetLowConfidenceThreshold(threshold float64) {
+	e.lowConfidence = threshold
+}
+
+// SetMaxCommunityNodes sets the symbol count above which a project skips community
+// detection; 0 never skips it.
+func (e *Engine) SetMaxCommunityNodes(n int) {
+	e.maxCommunityNodes = n
 }
 
-// ComputeAll runs all analytics for a project: degrees, PageRank, summaries, bridges, layers.
+// ComputeAll runs all analytics for a project: degrees, PageRank, betweenness, layers, communities, summaries,
+// modules, bridges, low-confidence regions, index suggestions, symbol metrics, dependency cycles,
+// unreferenced symbols.
 func (e *Engine) ComputeAll(ctx context.Context, projectID uuid.UUID) error {
 	e.logger.Info("computing analytics", slog.String("project_id", projectID.String()))
 
@@ -42,14 +73,26 @@ func (e *Engine) ComputeAll(ctx context.Context, projectID uuid.UUID) error {
 		return fmt.Errorf("compute pagerank: %w", err)
 	}
 
+	if err := e.ComputeBetweenness(ctx, projectID); err != nil {
+		return fmt.Errorf("compute betweenness: %w", err)
+	}
+
 	if err := e.ComputeLayers(ctx, projectID); err != nil {
 		return fmt.Errorf("compute layers: %w", err)
 	}
 
+	if err := e.ComputeCommunities(ctx, projectID); err != nil {
+		return fmt.Errorf("compute communities: %w", err)
+	}
+
 	if err := e.ComputeProjectSummaries(ctx, projectID); err != nil {
 		return fmt.Errorf("compute summaries: %w", err)
 	}
 
+	if err := e.ComputeModuleSummaries(ctx, projectID); err != nil {
+		return fmt.Errorf("compute module summaries: %w", err)
+	}
+
 	if err := e.ComputeCrossLanguageBridges(ctx, projectID); err != nil {
 		return fmt.Errorf("compute bridges: %w", err)
 	}
@@ -58,10 +101,55 @@ func (e *Engine) ComputeAll(ctx context.Context, projectID uuid.UUID) error {
 		return fmt.Errorf("compute bridge coverage: %w", err)
 	}
 
+	if err := e.ComputeLowConfidenceRegions(ctx, projectID); err != nil {
+		return fmt.Errorf("compute low confidence regions: %w", err)
+	}
+
+	if err := e.ComputeIndexSuggestions(ctx, projectID); err != nil {
+		return fmt.Errorf("compute index suggestions: %w", err)
+	}
+
+	if err := e.ComputeSymbolMetrics(ctx, projectID); err != nil {
+		return fmt.Errorf("compute symbol metrics: %w", err)
+	}
+
+	if err := e.ComputeCycles(ctx, projectID); err != nil {
+		return fmt.Errorf("compute cycles: %w", err)
+	}
+
+	if err := e.ComputeUnreferenced(ctx, projectID); err != nil {
+		return fmt.Errorf("compute unreferenced: %w", err)
+	}
+
 	e.logger.Info("analytics complete", slog.String("project_id", projectID.String()))
 	return nil
 }
 
+// updateSymbolsMetadata merges each symbol's keys into its metadata, batchSize symbols
+// per statement. A batch that fails is logged under what and the others still written.
+func (e *Engine) updateSymbolsMetadata(ctx context.Context, updates map[uuid.UUID]map[string]any, what string) {
+	ids := make([]uuid.UUID, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	for i := 0; i < len(ids); i += batchSize {
+		batch := ids[i:min(i+batchSize, len(ids))]
+		metas := make([]string, len(batch))
+		for j, id := range batch {
+			metaJSON, _ := json.Marshal(updates[id])
+			metas[j] = string(metaJSON)
+		}
+		if err := e.store.BatchUpdateSymbolMetadataEach(ctx, postgres.BatchUpdateSymbolMetadataEachParams{
+			SymbolIds:     batch,
+			AnalyticsJson: metas,
+		}); err != nil {
+			e.logger.Warn("failed to update "+what, slog.Int("symbols", len(batch)), slog.String("error", err.Error()))
+		}
+	}
+}
+
 // ComputeDegrees calculates in-degree and out-degree for all symbols in a project.
 func (e *Engine) ComputeDegrees(ctx context.Context, projectID uuid.UUID) error {
 	degrees, err := e.store.GetSymbolDegrees(ctx, projectID)
@@ -100,7 +188,9 @@ func (e *Engine) ComputeDegrees(ctx context.Context, projectID uuid.UUID) error
 	return nil
 }
 
-// ComputePageRank runs iterative PageRank over the symbol graph.
+// ComputePageRank runs iterative PageRank over the symbol graph. Graphs over the sampling
+// threshold are ranked approximately (see Sampling); every rank is then flagged
+// pagerank_approximate and the run is recorded under the "centrality" analytics scope.
 func (e *Engine) ComputePageRank(ctx context.Context, projectID uuid.UUID) error {
 	edges, err := e.store.GetEdgeList(ctx, projectID)
 	if err != nil {
@@ -112,76 +202,26 @@ func (e *Engine) ComputePageRank(ctx context.Context, projectID uuid.UUID) error
 		return nil
 	}
 
-	// Build adjacency lists and collect node set
-	nodeSet := make(map[uuid.UUID]struct{})
-	outLinks := make(map[uuid.UUID][]uuid.UUID)
-	for _, edge := range edges {
-		nodeSet[edge.SourceID] = struct{}{}
-		nodeSet[edge.TargetID] = struct{}{}
-		outLinks[edge.SourceID] = append(outLinks[edge.SourceID], edge.TargetID)
-	}
-
-	n := len(nodeSet)
-	if n == 0 {
-		return nil
-	}
-
 	e.logger.Info("computing pagerank",
-		slog.Int("nodes", n),
 		slog.Int("edges", len(edges)),
-		slog.Int("iterations", pageRankIterations))
-
-	// Initialize ranks
-	initRank := 1.0 / float64(n)
-	rank := make(map[uuid.UUID]float64, n)
-	for node := range nodeSet {
-		rank[node] = initRank
-	}
-
-	// Iterative computation
-	for iter := range pageRankIterations {
-		newRank := make(map[uuid.UUID]float64, n)
-		sinkRank := 0.0
-
-		// Accumulate sink node (no outlinks) rank
-		for node := range nodeSet {
-			if _, hasOut := outLinks[node]; !hasOut {
-				sinkRank += rank[node]
-			}
-		}
-
-		base := (1.0-pageRankDamping)/float64(n) + pageRankDamping*sinkRank/float64(n)
-
-		for node := range nodeSet {
-			newRank[node] = base
-		}
+		slog.Int("iterations", pageRankIterations),
+		slog.Int("sampling_threshold", e.sampling.Threshold))
 
-		for src, targets := range outLinks {
-			share := pageRankDamping * rank[src] / float64(len(targets))
-			for _, tgt := range targets {
-				newRank[tgt] += share
-			}
-		}
-
-		rank = newRank
-
-		if iter == pageRankIterations-1 {
-			// Check convergence on last iteration
-			var maxDiff float64
-			for node := range nodeSet {
-				diff := math.Abs(rank[node] - newRank[node])
-				if diff > maxDiff {
-					maxDiff = diff
-				}
-			}
-			e.logger.Debug("pagerank iteration", slog.Int("iter", iter), slog.Float64("max_diff", maxDiff))
-		}
+	rank, run := rankGraph(edges, e.sampling)
+	if run.Approximate() {
+		e.logger.Warn("pagerank is approximate: graph exceeds sampling threshold",
+			slog.Int("nodes", run.Nodes),
+			slog.Int("threshold", run.Threshold),
+			slog.Int("walks", run.Walks))
 	}
 
 	// Persist PageRank values
 	count := 0
 	for node, pr := range rank {
-		meta := map[string]any{"pagerank": math.Round(pr*1e6) / 1e6}
+		meta := map[string]any{
+			"pagerank":             math.Round(pr*1e6) / 1e6,
+			"pagerank_approximate": run.Approximate(),
+		}
 		metaJSON, err := json.Marshal(meta)
 		if err != nil {
 			continue
@@ -195,7 +235,19 @@ func (e *Engine) ComputePageRank(ctx context.Context, projectID uuid.UUID) error
 		count++
 	}
 
-	e.logger.Info("pagerank computed", slog.Int("nodes", count))
+	runJSON, _ := json.Marshal(run)
+	summary := run.Summary()
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "centrality",
+		Analytics: runJSON,
+		Summary:   &summary,
+	}); err != nil {
+		e.logger.Warn("failed to upsert centrality analytics", slog.String("error", err.Error()))
+	}
+
+	e.logger.Info("pagerank computed", slog.Int("nodes", count), slog.String("mode", run.Mode))
 	return nil
 }
 
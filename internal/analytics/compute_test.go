@@ -1,8 +1,11 @@
 package analytics
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
@@ -69,6 +72,83 @@ func TestGenerateProjectSummary_TruncatesAt5Kinds(t *testing.T) {
 	assertContains(t, summary, "and 2 more")
 }
 
+// --- rankGraph ---
+
+// starGraph has a hub every leaf links to, and a leaf chain feeding a second hub.
+func starGraph(leaves int) (hub, second uuid.UUID, edges []postgres.GetEdgeListRow) {
+	hub, second = uuid.New(), uuid.New()
+	for i := 0; i < leaves; i++ {
+		leaf := uuid.New()
+		edges = append(edges, postgres.GetEdgeListRow{SourceID: leaf, TargetID: hub})
+		if i%10 == 0 {
+			edges = append(edges, postgres.GetEdgeListRow{SourceID: leaf, TargetID: second})
+		}
+	}
+	return hub, second, edges
+}
+
+func TestRankGraph_ExactBelowThreshold(t *testing.T) {
+	hub, second, edges := starGraph(50)
+
+	rank, run := rankGraph(edges, Sampling{Threshold: 100, Walks: 1000, Seed: 1})
+
+	if run.Approximate() || run.Mode != CentralityExact {
+		t.Fatalf("expected exact mode below the threshold, got %+v", run)
+	}
+	if run.Nodes != 52 || run.Edges != len(edges) {
+		t.Errorf("expected 52 nodes and %d edges, got %+v", len(edges), run)
+	}
+	if run.Walks != 0 || run.Threshold != 0 {
+		t.Errorf("exact runs should not report sampling parameters, got %+v", run)
+	}
+	if rank[hub] <= rank[second] {
+		t.Errorf("expected hub to outrank second, got %f <= %f", rank[hub], rank[second])
+	}
+	assertContains(t, run.Summary(), "exact")
+}
+
+func TestRankGraph_SampledAboveThreshold(t *testing.T) {
+	hub, second, edges := starGraph(500)
+	sampling := Sampling{Threshold: 100, Walks: 20000, Seed: 7}
+
+	rank, run := rankGraph(edges, sampling)
+
+	if !run.Approximate() || run.Mode != CentralitySampled {
+		t.Fatalf("expected sampled mode above the threshold, got %+v", run)
+	}
+	if run.Threshold != 100 || run.Walks != 20000 || run.Seed != 7 || run.Nodes != 502 {
+		t.Errorf("expected the sampling parameters to be recorded, got %+v", run)
+	}
+	summary := run.Summary()
+	for _, want := range []string{"approximate", "502 nodes", "threshold of 100", "20000 random walks", "seed 7"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected summary to contain %q: %s", want, summary)
+		}
+	}
+
+	// The estimate agrees with exact PageRank where it matters: the top of the ranking.
+	exact, _ := rankGraph(edges, Sampling{})
+	if rank[hub] <= rank[second] {
+		t.Errorf("expected hub to outrank second, got %f <= %f", rank[hub], rank[second])
+	}
+	if diff := rank[hub] - exact[hub]; diff > 0.05 || diff < -0.05 {
+		t.Errorf("sampled hub rank %f too far from exact %f", rank[hub], exact[hub])
+	}
+	total := 0.0
+	for _, r := range rank {
+		total += r
+	}
+	if total < 0.999 || total > 1.001 {
+		t.Errorf("expected sampled ranks to sum to 1, got %f", total)
+	}
+
+	// Reruns with the same seed agree.
+	again, _ := rankGraph(edges, sampling)
+	if again[hub] != rank[hub] {
+		t.Errorf("expected the same seed to reproduce ranks, got %f and %f", rank[hub], again[hub])
+	}
+}
+
 func assertContains(t *testing.T, s, substr string) {
 	t.Helper()
 	if len(s) == 0 || len(substr) == 0 {
@@ -0,0 +1,201 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// DefaultLowConfidenceThreshold is the edge confidence below which a link counts as
+// low-trust inference, matching the low-confidence cut of bridge coverage.
+const DefaultLowConfidenceThreshold = 0.8
+
+// regionSampleSize bounds the member names stored with each low-confidence region.
+const regionSampleSize = 10
+
+// confidenceEdge is an edge between symbol indexes with its resolution confidence.
+type confidenceEdge struct {
+	src, tgt   int
+	confidence float64
+}
+
+// lowConfidenceRegion is a group of symbols held together mostly by low-confidence edges.
+type lowConfidenceRegion struct {
+	members       []int
+	lowEdges      int     // below-threshold edges between members
+	edges         int     // every edge touching a member
+	minConfidence float64 // weakest link between members
+}
+
+// ComputeLowConfidenceRegions flags the parts of the graph built on shaky inference:
+// clusters of symbols connected primarily by edges below the engine's confidence
+// threshold, typically inferred cross-language links. Each region is stored under the
+// "low_confidence_regions" analytics scope with a project-level overview, so agents can
+// warn when an answer relies on them.
+func (e *Engine) ComputeLowConfidenceRegions(ctx context.Context, projectID uuid.UUID) error {
+	edgeRows, err := e.store.GetEdgeConfidences(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get edge confidences: %w", err)
+	}
+
+	nodeIndex := make(map[uuid.UUID]int)
+	var nodes []uuid.UUID
+	index := func(id uuid.UUID) int {
+		i, ok := nodeIndex[id]
+		if !ok {
+			i = len(nodes)
+			nodeIndex[id] = i
+			nodes = append(nodes, id)
+		}
+		return i
+	}
+	edges := make([]confidenceEdge, 0, len(edgeRows))
+	for _, er := range edgeRows {
+		edges = append(edges, confidenceEdge{src: index(er.SourceID), tgt: index(er.TargetID), confidence: er.Confidence})
+	}
+
+	regions := findLowConfidenceRegions(len(nodes), edges, e.lowConfidence)
+	e.logger.Info("computing low confidence regions",
+		slog.Int("edges", len(edges)),
+		slog.Float64("threshold", e.lowConfidence),
+		slog.Int("regions", len(regions)))
+
+	var names map[uuid.UUID]string
+	if len(regions) > 0 {
+		symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("list symbols: %w", err)
+		}
+		names = make(map[uuid.UUID]string, len(symbols))
+		for _, sym := range symbols {
+			names[sym.ID] = sym.QualifiedName
+		}
+	}
+
+	flagged := 0
+	for n, region := range regions {
+		regionID := n + 1
+		sample := make([]string, 0, regionSampleSize)
+		for _, idx := range region.members {
+			if len(sample) == regionSampleSize {
+				break
+			}
+			if name := names[nodes[idx]]; name != "" {
+				sample = append(sample, name)
+			}
+		}
+		flagged += len(region.members)
+
+		regionJSON, _ := json.Marshal(map[string]any{
+			"region_id":      regionID,
+			"size":           len(region.members),
+			"low_edges":      region.lowEdges,
+			"edges":          region.edges,
+			"min_confidence": math.Round(region.minConfidence*100) / 100,
+			"sample":         sample,
+		})
+		summary := fmt.Sprintf("Region %d: %d symbols linked by %d of %d edges below %.2f confidence",
+			regionID, len(region.members), region.lowEdges, region.edges, e.lowConfidence)
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "low_confidence_regions",
+			ScopeID:   fmt.Sprintf("%d", regionID),
+			Analytics: regionJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert low confidence region", slog.Int("region_id", regionID))
+		}
+	}
+
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"threshold":       e.lowConfidence,
+		"region_count":    len(regions),
+		"flagged_symbols": flagged,
+	})
+	summary := fmt.Sprintf("%d low-confidence regions (%d symbols) rely mainly on links below %.2f confidence.",
+		len(regions), flagged, e.lowConfidence)
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "low_confidence_regions",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert low confidence overview: %w", err)
+	}
+	return nil
+}
+
+// findLowConfidenceRegions groups the n nodes joined by edges below threshold into
+// connected clusters and keeps those whose edges are mostly below it: more than half of
+// the edges touching the cluster are low-confidence links inside it. A weak link between
+// two otherwise well-connected symbols is therefore not a region. Regions are ordered
+// largest first.
+func findLowConfidenceRegions(n int, edges []confidenceEdge, threshold float64) []lowConfidenceRegion {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	low := func(e confidenceEdge) bool { return e.confidence < threshold && e.src != e.tgt }
+	for _, e := range edges {
+		if low(e) {
+			parent[find(e.src)] = find(e.tgt)
+		}
+	}
+
+	byRoot := make(map[int]*lowConfidenceRegion)
+	for _, e := range edges {
+		if low(e) {
+			r := byRoot[find(e.src)]
+			if r == nil {
+				r = &lowConfidenceRegion{minConfidence: e.confidence}
+				byRoot[find(e.src)] = r
+			}
+			r.lowEdges++
+			r.minConfidence = min(r.minConfidence, e.confidence)
+		}
+	}
+	for _, e := range edges {
+		// every edge touching a cluster counts once against it
+		rs, rt := find(e.src), find(e.tgt)
+		if r := byRoot[rs]; r != nil {
+			r.edges++
+		}
+		if r := byRoot[rt]; r != nil && rt != rs {
+			r.edges++
+		}
+	}
+	for i := range n {
+		if r := byRoot[find(i)]; r != nil {
+			r.members = append(r.members, i)
+		}
+	}
+
+	var regions []lowConfidenceRegion
+	for _, r := range byRoot {
+		if r.lowEdges*2 > r.edges {
+			regions = append(regions, *r)
+		}
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if len(regions[i].members) != len(regions[j].members) {
+			return len(regions[i].members) > len(regions[j].members)
+		}
+		return regions[i].members[0] < regions[j].members[0]
+	})
+	return regions
+}
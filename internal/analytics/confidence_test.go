@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindLowConfidenceRegions(t *testing.T) {
+	var edges []confidenceEdge
+	// Nodes 0-4: a well-resolved clique, with one inferred link inside it.
+	for _, e := range cliqueEdges(0, 5) {
+		edges = append(edges, confidenceEdge{src: e[0], tgt: e[1], confidence: 1})
+	}
+	edges = append(edges, confidenceEdge{src: 1, tgt: 2, confidence: 0.7})
+	// Nodes 5-8: held together only by inferred cross-language links.
+	for _, e := range [][2]int{{5, 6}, {6, 7}, {7, 8}, {5, 8}} {
+		edges = append(edges, confidenceEdge{src: e[0], tgt: e[1], confidence: 0.7})
+	}
+	// One exact edge from the clique into the inferred cluster.
+	edges = append(edges, confidenceEdge{src: 4, tgt: 5, confidence: 1})
+
+	regions := findLowConfidenceRegions(9, edges, DefaultLowConfidenceThreshold)
+	if len(regions) != 1 {
+		t.Fatalf("expected one flagged region, got %+v", regions)
+	}
+	r := regions[0]
+	if !slices.Equal(r.members, []int{5, 6, 7, 8}) {
+		t.Errorf("expected the 0.7-linked cluster flagged, got members %v", r.members)
+	}
+	if r.lowEdges != 4 || r.edges != 5 || r.minConfidence != 0.7 {
+		t.Errorf("expected 4 of 5 edges below threshold at 0.7, got %+v", r)
+	}
+
+	// Lowering the threshold below the inferred links flags nothing.
+	if regions := findLowConfidenceRegions(9, edges, 0.5); len(regions) != 0 {
+		t.Errorf("expected no regions at threshold 0.5, got %+v", regions)
+	}
+}
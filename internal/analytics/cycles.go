@@ -0,0 +1,319 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Cycles beyond cyclesLimit are counted in the overview but not stored, and a stored
+// cycle lists at most cycleMemberLimit of its symbols.
+const (
+	cyclesLimit      = 100
+	cycleMemberLimit = 25
+)
+
+// cycleStep is one edge of a dependency loop.
+type cycleStep struct {
+	from, to uuid.UUID
+	edgeType string
+}
+
+// dependencyCycle is a strongly connected component of the symbol graph: symbols that
+// each reach all the others, so none can change or deploy independently.
+type dependencyCycle struct {
+	members   []uuid.UUID // by qualified name
+	edgeTypes map[string]int
+	loop      []cycleStep // a shortest loop through the first member
+}
+
+// ComputeCycles finds the dependency cycles of a project with Tarjan's strongly
+// connected components over symbol_edges. Each component of two or more symbols is
+// stored under the "cycles" analytics scope, largest first, with its members, the edge
+// types inside it and one loop through it; a project-level overview counts them all.
+// Self-references (recursion) are not reported.
+func (e *Engine) ComputeCycles(ctx context.Context, projectID uuid.UUID) error {
+	edges, err := e.store.ListEdgesByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list edges: %w", err)
+	}
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+	byID := make(map[uuid.UUID]postgres.Symbol, len(symbols))
+	names := make(map[uuid.UUID]string, len(symbols))
+	for _, s := range symbols {
+		byID[s.ID] = s
+		names[s.ID] = s.QualifiedName
+	}
+
+	cycles := findCycles(edges, names)
+	e.logger.Info("computing dependency cycles", slog.Int("edges", len(edges)), slog.Int("cycles", len(cycles)))
+
+	nameOf := func(id uuid.UUID) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return id.String()
+	}
+	stored := cycles
+	if len(stored) > cyclesLimit {
+		stored = stored[:cyclesLimit]
+	}
+	cyclic := 0
+	for _, c := range cycles {
+		cyclic += len(c.members)
+	}
+
+	for i, c := range stored {
+		rank := i + 1
+		members := make([]map[string]any, 0, min(len(c.members), cycleMemberLimit))
+		for _, id := range c.members[:min(len(c.members), cycleMemberLimit)] {
+			members = append(members, map[string]any{
+				"symbol_id":      id.String(),
+				"qualified_name": nameOf(id),
+				"kind":           byID[id].Kind,
+			})
+		}
+		loop := make([]map[string]any, len(c.loop))
+		path := make([]string, 0, len(c.loop)+1)
+		for j, step := range c.loop {
+			loop[j] = map[string]any{
+				"from":      nameOf(step.from),
+				"to":        nameOf(step.to),
+				"edge_type": step.edgeType,
+			}
+			path = append(path, nameOf(step.from))
+		}
+		path = append(path, nameOf(c.members[0]))
+
+		cycleJSON, _ := json.Marshal(map[string]any{
+			"rank":       rank,
+			"size":       len(c.members),
+			"members":    members,
+			"edge_types": c.edgeTypes,
+			"loop":       loop,
+		})
+		summary := fmt.Sprintf("%d symbols: %s (%s)", len(c.members), strings.Join(path, " → "), formatEdgeTypes(c.edgeTypes))
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "cycles",
+			ScopeID:   fmt.Sprintf("%d", rank),
+			Analytics: cycleJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert dependency cycle", slog.Int("rank", rank))
+		}
+	}
+
+	largest := 0
+	if len(cycles) > 0 {
+		largest = len(cycles[0].members)
+	}
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"cycle_count":   len(cycles),
+		"symbol_count":  cyclic,
+		"largest_cycle": largest,
+		"ranked_count":  len(stored),
+	})
+	summary := "No dependency cycles found."
+	if len(cycles) > 0 {
+		summary = fmt.Sprintf("%d dependency cycles involving %d symbols; the largest has %d.", len(cycles), cyclic, largest)
+	}
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "cycles",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert cycles overview: %w", err)
+	}
+	return nil
+}
+
+// formatEdgeTypes lists edge types by count, most frequent first: "calls ×3, uses_table".
+func formatEdgeTypes(types map[string]int) string {
+	keys := make([]string, 0, len(types))
+	for t := range types {
+		keys = append(keys, t)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if types[keys[i]] != types[keys[j]] {
+			return types[keys[i]] > types[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, len(keys))
+	for i, t := range keys {
+		parts[i] = t
+		if types[t] > 1 {
+			parts[i] = fmt.Sprintf("%s ×%d", t, types[t])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// findCycles returns the strongly connected components of two or more symbols in the
+// edge graph, largest first and then by the name of their first member. Members are
+// ordered by name, and the loop starts and ends at the first.
+func findCycles(edges []postgres.SymbolEdge, names map[uuid.UUID]string) []dependencyCycle {
+	type link struct {
+		to       int
+		edgeType string
+	}
+	index := make(map[uuid.UUID]int)
+	var nodes []uuid.UUID
+	indexOf := func(id uuid.UUID) int {
+		i, ok := index[id]
+		if !ok {
+			i = len(nodes)
+			index[id] = i
+			nodes = append(nodes, id)
+		}
+		return i
+	}
+	var outLinks [][]link
+	for _, edge := range edges {
+		src, tgt := indexOf(edge.SourceID), indexOf(edge.TargetID)
+		for len(outLinks) < len(nodes) {
+			outLinks = append(outLinks, nil)
+		}
+		if src != tgt {
+			outLinks[src] = append(outLinks[src], link{to: tgt, edgeType: edge.EdgeType})
+		}
+	}
+	n := len(nodes)
+
+	// Tarjan's algorithm, iterative so deep call chains cannot overflow the stack
+	order := make([]int, n)
+	low := make([]int, n)
+	for i := range order {
+		order[i] = -1
+	}
+	onStack := make([]bool, n)
+	var stack []int
+	var components [][]int
+	type frame struct{ v, next int }
+	visited := 0
+	for root := range n {
+		if order[root] >= 0 {
+			continue
+		}
+		order[root], low[root] = visited, visited
+		visited++
+		stack = append(stack, root)
+		onStack[root] = true
+		calls := []frame{{v: root}}
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			if f.next < len(outLinks[f.v]) {
+				w := outLinks[f.v][f.next].to
+				f.next++
+				if order[w] < 0 {
+					order[w], low[w] = visited, visited
+					visited++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{v: w})
+				} else if onStack[w] {
+					low[f.v] = min(low[f.v], order[w])
+				}
+				continue
+			}
+			v := f.v
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].v
+				low[parent] = min(low[parent], low[v])
+			}
+			if low[v] != order[v] {
+				continue
+			}
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			if len(component) > 1 {
+				components = append(components, component)
+			}
+		}
+	}
+
+	nameOf := func(i int) string {
+		if name, ok := names[nodes[i]]; ok {
+			return name
+		}
+		return nodes[i].String()
+	}
+	cycles := make([]dependencyCycle, 0, len(components))
+	for _, component := range components {
+		sort.Slice(component, func(i, j int) bool { return nameOf(component[i]) < nameOf(component[j]) })
+		in := make(map[int]bool, len(component))
+		for _, v := range component {
+			in[v] = true
+		}
+
+		c := dependencyCycle{edgeTypes: make(map[string]int)}
+		for _, v := range component {
+			c.members = append(c.members, nodes[v])
+			for _, l := range outLinks[v] {
+				if in[l.to] {
+					c.edgeTypes[l.edgeType]++
+				}
+			}
+		}
+
+		// Breadth-first from the first member back to itself gives a shortest loop
+		start := component[0]
+		via := map[int]link{}
+		from := map[int]int{}
+		queue := []int{start}
+		for len(queue) > 0 && c.loop == nil {
+			v := queue[0]
+			queue = queue[1:]
+			for _, l := range outLinks[v] {
+				if !in[l.to] {
+					continue
+				}
+				if l.to == start {
+					c.loop = []cycleStep{{from: nodes[v], to: nodes[start], edgeType: l.edgeType}}
+					for w := v; w != start; w = from[w] {
+						c.loop = append(c.loop, cycleStep{from: nodes[from[w]], to: nodes[w], edgeType: via[w].edgeType})
+					}
+					break
+				}
+				if _, seen := from[l.to]; !seen && l.to != start {
+					from[l.to], via[l.to] = v, l
+					queue = append(queue, l.to)
+				}
+			}
+		}
+		for i, j := 0, len(c.loop)-1; i < j; i, j = i+1, j-1 {
+			c.loop[i], c.loop[j] = c.loop[j], c.loop[i]
+		}
+		cycles = append(cycles, c)
+	}
+
+	sort.SliceStable(cycles, func(i, j int) bool {
+		if len(cycles[i].members) != len(cycles[j].members) {
+			return len(cycles[i].members) > len(cycles[j].members)
+		}
+		return names[cycles[i].members[0]] < names[cycles[j].members[0]]
+	})
+	return cycles
+}
@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestFindCycles(t *testing.T) {
+	ids := map[string]uuid.UUID{}
+	names := map[uuid.UUID]string{}
+	for _, name := range []string{"orders.Service", "billing.Service", "billing.Invoice", "util.Log", "dbo.Orders", "dbo.trg_Audit", "walk"} {
+		ids[name] = uuid.New()
+		names[ids[name]] = name
+	}
+	edge := func(from, to, edgeType string) postgres.SymbolEdge {
+		return postgres.SymbolEdge{SourceID: ids[from], TargetID: ids[to], EdgeType: edgeType}
+	}
+	edges := []postgres.SymbolEdge{
+		// orders and billing call each other through the invoice
+		edge("orders.Service", "billing.Service", "calls"),
+		edge("billing.Service", "billing.Invoice", "calls"),
+		edge("billing.Invoice", "orders.Service", "references"),
+		edge("billing.Service", "orders.Service", "calls"),
+		// everything logs: not a cycle
+		edge("orders.Service", "util.Log", "calls"),
+		edge("billing.Service", "util.Log", "calls"),
+		// a trigger writing the table it fires on
+		edge("dbo.Orders", "dbo.trg_Audit", "triggers"),
+		edge("dbo.trg_Audit", "dbo.Orders", "writes_to"),
+		// recursion is not a dependency cycle
+		edge("walk", "walk", "calls"),
+	}
+
+	cycles := findCycles(edges, names)
+	if len(cycles) != 2 {
+		t.Fatalf("expected 2 cycles, got %d: %+v", len(cycles), cycles)
+	}
+
+	big := cycles[0]
+	if len(big.members) != 3 || names[big.members[0]] != "billing.Invoice" {
+		t.Fatalf("expected the 3-symbol cycle first, led by billing.Invoice, got %+v", big)
+	}
+	if big.edgeTypes["calls"] != 3 || big.edgeTypes["references"] != 1 {
+		t.Errorf("expected 3 calls and 1 references inside the cycle, got %v", big.edgeTypes)
+	}
+	// billing.Invoice → orders.Service → billing.Service → billing.Invoice
+	want := []string{"billing.Invoice", "orders.Service", "billing.Service"}
+	if len(big.loop) != len(want) {
+		t.Fatalf("expected a 3-step loop, got %+v", big.loop)
+	}
+	for i, step := range big.loop {
+		if names[step.from] != want[i] || names[step.to] != want[(i+1)%len(want)] {
+			t.Errorf("loop step %d: got %s → %s", i, names[step.from], names[step.to])
+		}
+	}
+	if big.loop[0].edgeType != "references" {
+		t.Errorf("expected the loop to leave billing.Invoice by references, got %s", big.loop[0].edgeType)
+	}
+
+	small := cycles[1]
+	if len(small.members) != 2 || names[small.members[0]] != "dbo.Orders" {
+		t.Errorf("expected the trigger cycle second, got %+v", small)
+	}
+	if got := formatEdgeTypes(big.edgeTypes); got != "calls ×3, references" {
+		t.Errorf("formatEdgeTypes = %q", got)
+	}
+
+	if cycles := findCycles(nil, names); len(cycles) != 0 {
+		t.Errorf("expected no cycles without edges, got %+v", cycles)
+	}
+}
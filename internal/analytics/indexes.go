@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// indexSuggestionLimit bounds the candidate indexes stored per project.
+const indexSuggestionLimit = 50
+
+// predicateUsage is a column with the number of queries filtering and joining on it.
+type predicateUsage struct {
+	id      uuid.UUID
+	column  string // qualified: schema.table.column
+	filters int
+	joins   int
+}
+
+// indexSuggestion is an unindexed column proposed for an index.
+type indexSuggestion struct {
+	table   string
+	column  string
+	filters int
+	joins   int
+}
+
+func (s indexSuggestion) uses() int { return s.filters + s.joins }
+
+// ComputeIndexSuggestions proposes missing indexes from query access patterns: columns
+// that procedures, views and functions compare in WHERE clauses and join conditions but
+// that lead no index or key constraint. Candidates are ranked by how many queries use
+// them and stored under the "index_suggestions" analytics scope, by rank, with a
+// project-level overview.
+func (e *Engine) ComputeIndexSuggestions(ctx context.Context, projectID uuid.UUID) error {
+	rows, err := e.store.GetColumnPredicateUsage(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get column predicate usage: %w", err)
+	}
+	indexedIDs, err := e.store.GetIndexedColumnIDs(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get indexed columns: %w", err)
+	}
+
+	usage := make([]predicateUsage, 0, len(rows))
+	for _, r := range rows {
+		usage = append(usage, predicateUsage{id: r.ID, column: r.QualifiedName, filters: int(r.Filters), joins: int(r.Joins)})
+	}
+	indexed := make(map[uuid.UUID]bool, len(indexedIDs))
+	for _, id := range indexedIDs {
+		indexed[id] = true
+	}
+
+	suggestions := suggestIndexes(usage, indexed, indexSuggestionLimit)
+	e.logger.Info("computing index suggestions",
+		slog.Int("predicate_columns", len(usage)),
+		slog.Int("indexed_columns", len(indexed)),
+		slog.Int("suggestions", len(suggestions)))
+
+	for i, s := range suggestions {
+		rank := i + 1
+		suggestionJSON, _ := json.Marshal(map[string]any{
+			"rank":    rank,
+			"table":   s.table,
+			"column":  s.column,
+			"filters": s.filters,
+			"joins":   s.joins,
+			"ddl":     suggestedIndexDDL(s),
+		})
+		summary := fmt.Sprintf("%s.%s is filtered by %d and joined on by %d queries but not indexed",
+			s.table, s.column, s.filters, s.joins)
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "index_suggestions",
+			ScopeID:   fmt.Sprintf("%d", rank),
+			Analytics: suggestionJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert index suggestion", slog.Int("rank", rank))
+		}
+	}
+
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"suggestion_count":  len(suggestions),
+		"predicate_columns": len(usage),
+		"indexed_columns":   len(indexed),
+	})
+	summary := fmt.Sprintf("%d of %d filtered or joined columns lead no index.", len(suggestions), len(usage))
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "index_suggestions",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert index suggestion overview: %w", err)
+	}
+	return nil
+}
+
+// suggestIndexes returns the columns in usage not among the indexed ones, most used
+// first, with ties going to the more filtered column and then by name, keeping at most
+// limit. Columns that lead an index are served by it and are not suggested again.
+func suggestIndexes(usage []predicateUsage, indexed map[uuid.UUID]bool, limit int) []indexSuggestion {
+	var out []indexSuggestion
+	for _, u := range usage {
+		dot := strings.LastIndex(u.column, ".")
+		if indexed[u.id] || dot < 0 || u.filters+u.joins == 0 {
+			continue
+		}
+		out = append(out, indexSuggestion{
+			table:   u.column[:dot],
+			column:  u.column[dot+1:],
+			filters: u.filters,
+			joins:   u.joins,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].uses() != out[j].uses() {
+			return out[i].uses() > out[j].uses()
+		}
+		if out[i].filters != out[j].filters {
+			return out[i].filters > out[j].filters
+		}
+		return out[i].table+"."+out[i].column < out[j].table+"."+out[j].column
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// suggestedIndexDDL is a CREATE INDEX statement for a suggestion, named ix_<table>_<column>.
+func suggestedIndexDDL(s indexSuggestion) string {
+	table := s.table
+	if dot := strings.LastIndex(table, "."); dot >= 0 {
+		table = table[dot+1:]
+	}
+	return fmt.Sprintf("CREATE INDEX ix_%s_%s ON %s (%s);", table, s.column, s.table, s.column)
+}
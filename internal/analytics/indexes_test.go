@@ -0,0 +1,42 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSuggestIndexes(t *testing.T) {
+	status, customer, orderID, lineOrder, region := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	usage := []predicateUsage{
+		// filtered by seven procedures, no index
+		{id: status, column: "dbo.Orders.Status", filters: 7},
+		// joined on often but leads IX_Orders_CustomerID
+		{id: customer, column: "dbo.Orders.CustomerID", filters: 2, joins: 9},
+		// the primary key
+		{id: orderID, column: "dbo.Orders.OrderID", filters: 12, joins: 4},
+		// second column of PK_OrderLines (OrderID, LineNo), so not seekable alone
+		{id: lineOrder, column: "dbo.OrderLines.LineNo", joins: 3},
+		{id: region, column: "dbo.Customers.RegionID", filters: 1, joins: 2},
+	}
+	indexed := map[uuid.UUID]bool{customer: true, orderID: true}
+
+	got := suggestIndexes(usage, indexed, indexSuggestionLimit)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 suggestions, got %+v", got)
+	}
+	if got[0].table != "dbo.Orders" || got[0].column != "Status" || got[0].filters != 7 {
+		t.Errorf("expected the frequently filtered dbo.Orders.Status first, got %+v", got[0])
+	}
+	// equal use: the more filtered column ranks first
+	if got[1].column != "RegionID" || got[2].column != "LineNo" {
+		t.Errorf("expected RegionID then LineNo, got %+v", got[1:])
+	}
+	if ddl := suggestedIndexDDL(got[0]); ddl != "CREATE INDEX ix_Orders_Status ON dbo.Orders (Status);" {
+		t.Errorf("unexpected DDL %q", ddl)
+	}
+
+	if got := suggestIndexes(usage, indexed, 1); len(got) != 1 || got[0].column != "Status" {
+		t.Errorf("expected the limit to keep the top suggestion, got %+v", got)
+	}
+}
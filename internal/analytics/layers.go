@@ -26,7 +26,8 @@ const (
 
 // dataKinds are symbol kinds inherently in the data layer.
 var dataKinds = map[string]bool{
-	"table": true, "view": true, "column": true, "procedure": true, "trigger": true,
+	"table": true, "view": true, "column": true, "procedure": true, "trigger": true, "sequence": true,
+	"index": true,
 }
 
 // dataNamespacePatterns match data-layer namespaces.
@@ -0,0 +1,198 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// metricsLimit bounds the ranked symbols stored per project under the "metrics" scope.
+const metricsLimit = 50
+
+// metricKinds are the symbol kinds with a body worth measuring.
+var metricKinds = map[string]bool{
+	"function": true, "method": true, "constructor": true, "procedure": true, "trigger": true,
+}
+
+// symbolMetrics are the size and coupling measures of one callable symbol.
+type symbolMetrics struct {
+	id            uuid.UUID
+	qualifiedName string
+	kind          string
+	lines         int
+	fanIn         int
+	fanOut        int
+	complexity    int // 0 where the parser does not measure it
+	score         float64
+}
+
+// ComputeSymbolMetrics measures the functions, methods and procedures of a project for
+// refactoring prioritization: line count from the stored ranges, fan-in and fan-out from
+// the edges, and the branch-count complexity recorded by the parsers. Each symbol gets
+// the measures and a combined complexity_score in its metadata; the highest scoring are
+// stored under the "metrics" analytics scope, by rank, with a project-level overview.
+func (e *Engine) ComputeSymbolMetrics(ctx context.Context, projectID uuid.UUID) error {
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+	degrees, err := e.store.GetSymbolDegrees(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("get symbol degrees: %w", err)
+	}
+	byID := make(map[uuid.UUID]postgres.GetSymbolDegreesRow, len(degrees))
+	for _, d := range degrees {
+		byID[d.ID] = d
+	}
+
+	var metrics []symbolMetrics
+	for _, s := range symbols {
+		if !metricKinds[s.Kind] {
+			continue
+		}
+		m := symbolMetrics{
+			id:            s.ID,
+			qualifiedName: s.QualifiedName,
+			kind:          s.Kind,
+			lines:         max(int(s.EndLine-s.StartLine)+1, 1),
+			fanIn:         int(byID[s.ID].InDegree),
+			fanOut:        int(byID[s.ID].OutDegree),
+		}
+		var meta map[string]any
+		if len(s.Metadata) > 0 && json.Unmarshal(s.Metadata, &meta) == nil {
+			if c, ok := meta["complexity"].(float64); ok {
+				m.complexity = int(c)
+			}
+		}
+		metrics = append(metrics, m)
+	}
+
+	scoreMetrics(metrics)
+	e.logger.Info("computing symbol metrics", slog.Int("symbols", len(metrics)))
+
+	updates := make(map[uuid.UUID]map[string]any, len(metrics))
+	for _, m := range metrics {
+		updates[m.id] = map[string]any{
+			"line_count":       m.lines,
+			"fan_in":           m.fanIn,
+			"fan_out":          m.fanOut,
+			"complexity_score": m.score,
+		}
+	}
+	e.updateSymbolsMetadata(ctx, updates, "symbol metrics")
+
+	ranked := rankByComplexity(metrics, metricsLimit)
+	for i, m := range ranked {
+		rank := i + 1
+		rankJSON, _ := json.Marshal(map[string]any{
+			"rank":             rank,
+			"symbol_id":        m.id.String(),
+			"qualified_name":   m.qualifiedName,
+			"kind":             m.kind,
+			"line_count":       m.lines,
+			"fan_in":           m.fanIn,
+			"fan_out":          m.fanOut,
+			"complexity":       m.complexity,
+			"complexity_score": m.score,
+		})
+		summary := fmt.Sprintf("%s: %d lines, fan-in %d, fan-out %d, complexity %d",
+			m.qualifiedName, m.lines, m.fanIn, m.fanOut, m.complexity)
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "metrics",
+			ScopeID:   fmt.Sprintf("%d", rank),
+			Analytics: rankJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert symbol metrics", slog.Int("rank", rank))
+		}
+	}
+
+	lines, measured := 0, 0
+	for _, m := range metrics {
+		lines += m.lines
+		if m.complexity > 0 {
+			measured++
+		}
+	}
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"symbol_count":   len(metrics),
+		"measured_count": measured,
+		"total_lines":    lines,
+		"ranked_count":   len(ranked),
+	})
+	summary := fmt.Sprintf("%d functions and procedures measured, %d with branch complexity.", len(metrics), measured)
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "metrics",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert metrics overview: %w", err)
+	}
+	return nil
+}
+
+// scoreMetrics sets the combined complexity score of each symbol: the sum of its four
+// measures, each divided by the project's 90th percentile of that measure (at least 1),
+// so a symbol typical of the project scores about 4 or less whatever the language.
+func scoreMetrics(metrics []symbolMetrics) {
+	measures := []func(symbolMetrics) int{
+		func(m symbolMetrics) int { return m.lines },
+		func(m symbolMetrics) int { return m.fanIn },
+		func(m symbolMetrics) int { return m.fanOut },
+		func(m symbolMetrics) int { return m.complexity },
+	}
+	scales := make([]float64, len(measures))
+	for i, measure := range measures {
+		values := make([]int, len(metrics))
+		for j, m := range metrics {
+			values[j] = measure(m)
+		}
+		scales[i] = math.Max(float64(percentile90(values)), 1)
+	}
+	for j := range metrics {
+		score := 0.0
+		for i, measure := range measures {
+			score += float64(measure(metrics[j])) / scales[i]
+		}
+		metrics[j].score = math.Round(score*100) / 100
+	}
+}
+
+// percentile90 returns the nearest-rank 90th percentile of values, 0 when empty.
+func percentile90(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	return sorted[int(math.Ceil(0.9*float64(len(sorted))))-1]
+}
+
+// rankByComplexity returns the scored symbols highest score first, ties going to the
+// longer symbol and then by name, keeping at most limit.
+func rankByComplexity(metrics []symbolMetrics, limit int) []symbolMetrics {
+	out := append([]symbolMetrics(nil), metrics...)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		if out[i].lines != out[j].lines {
+			return out[i].lines > out[j].lines
+		}
+		return out[i].qualifiedName < out[j].qualifiedName
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
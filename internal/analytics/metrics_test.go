@@ -0,0 +1,53 @@
+package analytics
+
+import "testing"
+
+func TestRankByComplexity(t *testing.T) {
+	metrics := []symbolMetrics{
+		{qualifiedName: "billing.Invoice.Total", lines: 12, fanIn: 4, fanOut: 2, complexity: 2},
+		// long, branchy and called from everywhere: the refactoring candidate
+		{qualifiedName: "billing.Reconcile", lines: 240, fanIn: 18, fanOut: 25, complexity: 31},
+		{qualifiedName: "billing.format", lines: 6, fanIn: 1, fanOut: 0, complexity: 1},
+		// long but straight-line and barely used
+		{qualifiedName: "billing.seedTaxTable", lines: 300, fanIn: 1, fanOut: 1, complexity: 1},
+		// a stored procedure: no branch count from its parser
+		{qualifiedName: "dbo.usp_CloseMonth", lines: 80, fanIn: 3, fanOut: 14},
+		{qualifiedName: "billing.Invoice.Add", lines: 12, fanIn: 4, fanOut: 2, complexity: 2},
+	}
+	scoreMetrics(metrics)
+
+	got := rankByComplexity(metrics, metricsLimit)
+	if len(got) != len(metrics) {
+		t.Fatalf("expected %d ranked symbols, got %d", len(metrics), len(got))
+	}
+	if got[0].qualifiedName != "billing.Reconcile" {
+		t.Errorf("expected billing.Reconcile first, got %+v", got[0])
+	}
+	if got[len(got)-1].qualifiedName != "billing.format" {
+		t.Errorf("expected billing.format last, got %+v", got[len(got)-1])
+	}
+	// equal scores fall back to the name
+	for i, m := range got {
+		if m.qualifiedName == "billing.Invoice.Add" && (i+1 >= len(got) || got[i+1].qualifiedName != "billing.Invoice.Total") {
+			t.Errorf("expected billing.Invoice.Add right before billing.Invoice.Total, got %+v", got)
+		}
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].score > got[i-1].score {
+			t.Errorf("rank %d scores %v above rank %d's %v", i+1, got[i].score, i, got[i-1].score)
+		}
+	}
+
+	if got := rankByComplexity(metrics, 2); len(got) != 2 || got[0].qualifiedName != "billing.Reconcile" {
+		t.Errorf("expected the limit to keep the top symbols, got %+v", got)
+	}
+}
+
+func TestPercentile90(t *testing.T) {
+	if got := percentile90(nil); got != 0 {
+		t.Errorf("expected 0 for no values, got %d", got)
+	}
+	if got := percentile90([]int{5, 1, 9, 3, 7, 2, 8, 4, 6, 10}); got != 9 {
+		t.Errorf("expected 9, got %d", got)
+	}
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// moduleStats aggregates the symbols tagged with one monorepo module.
+type moduleStats struct {
+	SymbolCount int            `json:"symbol_count"`
+	Languages   map[string]int `json:"languages"`
+	Kinds       map[string]int `json:"kinds"`
+}
+
+// ComputeModuleSummaries stores per-module symbol breakdowns under the "module" analytics
+// scope for projects indexed with module_detection, dropping rows for modules no longer
+// found. Projects without module tags keep no module rows.
+func (e *Engine) ComputeModuleSummaries(ctx context.Context, projectID uuid.UUID) error {
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+
+	modules := groupByModule(symbols)
+	names := make([]string, 0, len(modules))
+	for name, stats := range modules {
+		names = append(names, name)
+		statsJSON, _ := json.Marshal(stats)
+		summary := fmt.Sprintf("Module %s contains %d symbols in %d language(s).",
+			name, stats.SymbolCount, len(stats.Languages))
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "module",
+			ScopeID:   name,
+			Analytics: statsJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert module analytics", slog.String("module", name))
+		}
+	}
+
+	// Drop modules removed or renamed since the last run, or all of them once
+	// module_detection is turned off
+	if err := e.store.DeleteStaleProjectAnalytics(ctx, postgres.DeleteStaleProjectAnalyticsParams{
+		ProjectID:    projectID,
+		Scope:        "module",
+		KeepScopeIds: names,
+	}); err != nil {
+		return fmt.Errorf("delete stale module analytics: %w", err)
+	}
+
+	if len(modules) > 0 {
+		e.logger.Info("module summaries computed", slog.Int("modules", len(modules)))
+	}
+	return nil
+}
+
+// groupByModule buckets symbols by their metadata "module" tag; untagged symbols are skipped.
+func groupByModule(symbols []postgres.Symbol) map[string]*moduleStats {
+	modules := make(map[string]*moduleStats)
+	for _, sym := range symbols {
+		name := symbolModule(sym)
+		if name == "" {
+			continue
+		}
+		ms, ok := modules[name]
+		if !ok {
+			ms = &moduleStats{Languages: make(map[string]int), Kinds: make(map[string]int)}
+			modules[name] = ms
+		}
+		ms.SymbolCount++
+		ms.Languages[sym.Language]++
+		ms.Kinds[sym.Kind]++
+	}
+	return modules
+}
+
+// symbolModule returns the monorepo module tag recorded at ingest, or "".
+func symbolModule(sym postgres.Symbol) string {
+	if len(sym.Metadata) == 0 {
+		return ""
+	}
+	var meta struct {
+		Module string `json:"module"`
+	}
+	if json.Unmarshal(sym.Metadata, &meta) != nil {
+		return ""
+	}
+	return meta.Module
+}
@@ -0,0 +1,122 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// PIITag is the tag applied to columns whose names look like personal data.
+const PIITag = "pii"
+
+// DefaultPIIPatterns are the column-name patterns used when a project does not set
+// pii_patterns. They match on underscore-separated words so e.g. social_club is left alone.
+var DefaultPIIPatterns = []string{
+	`(?i)(^|_)ssn($|_)`,
+	`(?i)social_?security`,
+	`(?i)e_?mail`,
+	`(?i)(^|_)(dob|date_?of_?birth|birth_?date)($|_)`,
+	`(?i)credit_?card|card_?number`,
+	`(?i)passport`,
+	`(?i)(^|_)(phone|mobile)(_?number)?($|_)`,
+	`(?i)tax_?id|national_?id`,
+}
+
+// CompilePIIPatterns compiles column-name patterns. Invalid patterns are skipped and
+// reported in the returned error; the valid ones are still returned.
+func CompilePIIPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	var errs []error
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pii pattern %q: %w", p, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, errors.Join(errs...)
+}
+
+// TagPIIColumns auto-tags column symbols whose names match one of the patterns. Auto-tags
+// are stored under metadata "auto_tags", separate from manually curated "tags", so they can
+// be told apart and recomputed on every run; columns that no longer match lose the tag.
+func (e *Engine) TagPIIColumns(ctx context.Context, projectID uuid.UUID, patterns []*regexp.Regexp) error {
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+
+	tag, untag := planPIITags(symbols, patterns)
+	if err := e.batchSetAutoTags(ctx, tag, []string{PIITag}); err != nil {
+		return err
+	}
+	if err := e.batchSetAutoTags(ctx, untag, []string{}); err != nil {
+		return err
+	}
+
+	e.logger.Info("pii columns tagged", slog.Int("tagged", len(tag)), slog.Int("untagged", len(untag)))
+	return nil
+}
+
+func (e *Engine) batchSetAutoTags(ctx context.Context, ids []uuid.UUID, tags []string) error {
+	meta, _ := json.Marshal(map[string]any{"auto_tags": tags})
+	for i := 0; i < len(ids); i += batchSize {
+		end := min(i+batchSize, len(ids))
+		if err := e.store.BatchUpdateSymbolMetadata(ctx, postgres.BatchUpdateSymbolMetadataParams{
+			AnalyticsJson: meta,
+			SymbolIds:     ids[i:end],
+		}); err != nil {
+			return fmt.Errorf("update auto tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// planPIITags returns the columns to auto-tag as PII and the previously auto-tagged
+// columns that no longer match.
+func planPIITags(symbols []postgres.Symbol, patterns []*regexp.Regexp) (tag, untag []uuid.UUID) {
+	for _, sym := range symbols {
+		if sym.Kind != "column" {
+			continue
+		}
+		switch {
+		case isPIIColumn(sym.Name, patterns):
+			tag = append(tag, sym.ID)
+		case slices.Contains(symbolAutoTags(sym), PIITag):
+			untag = append(untag, sym.ID)
+		}
+	}
+	return tag, untag
+}
+
+func isPIIColumn(name string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// symbolAutoTags returns the tags previously applied by auto-tagging.
+func symbolAutoTags(sym postgres.Symbol) []string {
+	if len(sym.Metadata) == 0 {
+		return nil
+	}
+	var meta struct {
+		AutoTags []string `json:"auto_tags"`
+	}
+	if json.Unmarshal(sym.Metadata, &meta) != nil {
+		return nil
+	}
+	return meta.AutoTags
+}
@@ -0,0 +1,42 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestPlanPIITags_DefaultPatterns(t *testing.T) {
+	patterns, err := CompilePIIPatterns(DefaultPIIPatterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ssn := postgres.Symbol{ID: uuid.New(), Name: "social_security_number", Kind: "column"}
+	club := postgres.Symbol{ID: uuid.New(), Name: "social_club", Kind: "column"}
+	stale := postgres.Symbol{ID: uuid.New(), Name: "nickname", Kind: "column", Metadata: []byte(`{"auto_tags":["pii"]}`)}
+	manual := postgres.Symbol{ID: uuid.New(), Name: "notes", Kind: "column", Metadata: []byte(`{"tags":["pii"]}`)}
+	table := postgres.Symbol{ID: uuid.New(), Name: "email", Kind: "table"}
+
+	tag, untag := planPIITags([]postgres.Symbol{ssn, club, stale, manual, table}, patterns)
+
+	if !slices.Equal(tag, []uuid.UUID{ssn.ID}) {
+		t.Errorf("expected only social_security_number to be auto-tagged, got %v", tag)
+	}
+	if !slices.Equal(untag, []uuid.UUID{stale.ID}) {
+		t.Errorf("expected only the stale auto-tag to be removed (manual tags untouched), got %v", untag)
+	}
+}
+
+func TestCompilePIIPatterns_SkipsInvalid(t *testing.T) {
+	patterns, err := CompilePIIPatterns([]string{`(?i)^iban$`, `(`})
+	if err == nil {
+		t.Error("expected an error for the invalid pattern")
+	}
+	if len(patterns) != 1 || !isPIIColumn("IBAN", patterns) {
+		t.Errorf("expected the valid pattern to be kept, got %v", patterns)
+	}
+}
@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Default sampling parameters. Exact PageRank over a few million nodes no longer fits the
+// pipeline window; a couple of million walks rank the top of such a graph well.
+// Betweenness costs a graph traversal per source node, so it is estimated from sampled
+// pivots far sooner.
+const (
+	DefaultSamplingThreshold    = 1_000_000
+	DefaultSampleWalks          = 2_000_000
+	DefaultSampleSeed           = 1
+	DefaultBetweennessThreshold = 5_000
+	DefaultBetweennessPivots    = 1_000
+)
+
+// Centrality modes recorded with the results.
+const (
+	CentralityExact   = "exact"
+	CentralitySampled = "sampled"
+)
+
+// Sampling configures approximate centrality for graphs too large to rank exactly. Above
+// Threshold nodes, PageRank is estimated by Monte Carlo: Walks random walks start from
+// uniformly sampled nodes and each node's rank is its share of the visits. Above
+// BetweennessThreshold nodes, betweenness is estimated from the shortest paths out of
+// Pivots sampled source nodes, scaled up to the whole graph.
+type Sampling struct {
+	Threshold            int   // nodes above which ranks are approximated; 0 always ranks exactly
+	Walks                int   // random walks started
+	Seed                 int64 // seeds the walks and pivots, so reruns over the same graph agree
+	BetweennessThreshold int   // nodes above which betweenness is approximated; 0 always computes it exactly
+	Pivots               int   // source nodes sampled for approximate betweenness
+}
+
+// DefaultSampling returns the default sampling parameters.
+func DefaultSampling() Sampling {
+	return Sampling{
+		Threshold:            DefaultSamplingThreshold,
+		Walks:                DefaultSampleWalks,
+		Seed:                 DefaultSampleSeed,
+		BetweennessThreshold: DefaultBetweennessThreshold,
+		Pivots:               DefaultBetweennessPivots,
+	}
+}
+
+// engages reports whether a graph of n nodes is ranked by sampling.
+func (s Sampling) engages(n int) bool {
+	return s.Threshold > 0 && n > s.Threshold
+}
+
+// engagesBetweenness reports whether betweenness over a graph of n nodes is sampled.
+func (s Sampling) engagesBetweenness(n int) bool {
+	return s.BetweennessThreshold > 0 && n > s.BetweennessThreshold && max(s.Pivots, 1) < n
+}
+
+// CentralityRun describes how a project's PageRank and betweenness were computed. It is
+// stored under the "centrality" project analytics scope so sampled scores are never
+// mistaken for exact ones.
+type CentralityRun struct {
+	Mode                 string `json:"mode"` // CentralityExact or CentralitySampled
+	Nodes                int    `json:"nodes"`
+	Edges                int    `json:"edges"`
+	Threshold            int    `json:"threshold,omitempty"`
+	Walks                int    `json:"walks,omitempty"`
+	Seed                 int64  `json:"seed,omitempty"`
+	BetweennessMode      string `json:"betweenness_mode,omitempty"` // CentralityExact or CentralitySampled
+	BetweennessThreshold int    `json:"betweenness_threshold,omitempty"`
+	Pivots               int    `json:"pivots,omitempty"`
+}
+
+// Approximate reports whether the ranks were sampled.
+func (r CentralityRun) Approximate() bool {
+	return r.Mode == CentralitySampled
+}
+
+// BetweennessApproximate reports whether the betweenness scores were sampled.
+func (r CentralityRun) BetweennessApproximate() bool {
+	return r.BetweennessMode == CentralitySampled
+}
+
+// Summary describes the run in a sentence or two for the project analytics.
+func (r CentralityRun) Summary() string {
+	summary := fmt.Sprintf("PageRank is exact over %d nodes and %d edges.", r.Nodes, r.Edges)
+	if r.Approximate() {
+		summary = fmt.Sprintf("PageRank is approximate: the graph has %d nodes (over the sampling threshold of %d), "+
+			"so ranks were estimated from %d random walks (seed %d). The ordering of highly ranked symbols is reliable; "+
+			"small differences between low ranks are noise.", r.Nodes, r.Threshold, r.Walks, r.Seed)
+	}
+	if r.BetweennessApproximate() {
+		summary += fmt.Sprintf(" Betweenness is approximate: the graph is over the betweenness threshold of %d nodes, "+
+			"so it was estimated from the shortest paths out of %d sampled symbols (seed %d).", r.BetweennessThreshold, r.Pivots, r.Seed)
+	}
+	return summary
+}
+
+// rankGraph computes PageRank over the edge list, exactly or, for graphs over the
+// sampling threshold, by sampling.
+func rankGraph(edges []postgres.GetEdgeListRow, s Sampling) (map[uuid.UUID]float64, CentralityRun) {
+	// Build adjacency lists over dense node indexes, in edge order so sampling is repeatable
+	index := make(map[uuid.UUID]int)
+	var nodes []uuid.UUID
+	indexOf := func(id uuid.UUID) int {
+		i, ok := index[id]
+		if !ok {
+			i = len(nodes)
+			index[id] = i
+			nodes = append(nodes, id)
+		}
+		return i
+	}
+	var outLinks [][]int
+	for _, edge := range edges {
+		src, tgt := indexOf(edge.SourceID), indexOf(edge.TargetID)
+		for len(outLinks) < len(nodes) {
+			outLinks = append(outLinks, nil)
+		}
+		outLinks[src] = append(outLinks[src], tgt)
+	}
+
+	run := CentralityRun{Mode: CentralityExact, Nodes: len(nodes), Edges: len(edges)}
+	var rank []float64
+	if s.engages(len(nodes)) {
+		run.Mode, run.Threshold, run.Walks, run.Seed = CentralitySampled, s.Threshold, max(s.Walks, 1), s.Seed
+		rank = sampledPageRank(outLinks, run.Walks, s.Seed)
+	} else {
+		rank = exactPageRank(outLinks)
+	}
+
+	ranks := make(map[uuid.UUID]float64, len(nodes))
+	for i, id := range nodes {
+		ranks[id] = rank[i]
+	}
+	return ranks, run
+}
+
+// exactPageRank runs pageRankIterations rounds of power iteration, spreading the rank of
+// sink nodes evenly over the graph.
+func exactPageRank(outLinks [][]int) []float64 {
+	n := len(outLinks)
+	rank := make([]float64, n)
+	if n == 0 {
+		return rank
+	}
+	for i := range rank {
+		rank[i] = 1.0 / float64(n)
+	}
+
+	for range pageRankIterations {
+		sinkRank := 0.0
+		for i, targets := range outLinks {
+			if len(targets) == 0 {
+				sinkRank += rank[i]
+			}
+		}
+
+		base := (1.0-pageRankDamping)/float64(n) + pageRankDamping*sinkRank/float64(n)
+		newRank := make([]float64, n)
+		for i := range newRank {
+			newRank[i] = base
+		}
+		for src, targets := range outLinks {
+			share := pageRankDamping * rank[src] / float64(len(targets))
+			for _, tgt := range targets {
+				newRank[tgt] += share
+			}
+		}
+		rank = newRank
+	}
+	return rank
+}
+
+// sampledPageRank estimates PageRank from random walks. Each walk starts at a uniformly
+// chosen node and continues along a random out-link with the damping probability; a walk
+// reaching a sink jumps to a uniformly chosen node, as the sink's rank is spread in the
+// exact computation. A node's rank is its share of all visits.
+func sampledPageRank(outLinks [][]int, walks int, seed int64) []float64 {
+	n := len(outLinks)
+	rank := make([]float64, n)
+	if n == 0 {
+		return rank
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	visits := make([]int, n)
+	total := 0
+	for range walks {
+		cur := rng.Intn(n)
+		for {
+			visits[cur]++
+			total++
+			if rng.Float64() >= pageRankDamping {
+				break
+			}
+			if targets := outLinks[cur]; len(targets) > 0 {
+				cur = targets[rng.Intn(len(targets))]
+			} else {
+				cur = rng.Intn(n)
+			}
+		}
+	}
+
+	for i, v := range visits {
+		rank[i] = float64(v) / float64(total)
+	}
+	return rank
+}
@@ -0,0 +1,177 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Reference statuses stored as reference_status in the metadata of candidate symbols.
+const (
+	statusReferenced          = "referenced"
+	statusUnreferenced        = "unreferenced"
+	statusLowConfidenceBridge = "low_confidence_bridge" // only inbound edges are weak cross-language matches
+)
+
+// unreferencedSampleSize bounds the names stored with each kind under the
+// "unreferenced" scope.
+const unreferencedSampleSize = 10
+
+// unreferencedKinds are the symbol kinds whose lack of inbound edges suggests dead code.
+// Entry points reached from outside the graph (endpoints, triggers, migrations, config
+// keys) are not candidates, nor are members, which are reached through their type.
+var unreferencedKinds = []string{"table", "view", "procedure", "function", "class", "interface"}
+
+// entryPointNames are functions the runtime calls rather than the code.
+var entryPointNames = map[string]bool{"main": true, "init": true, "Main": true}
+
+// nonUsageEdges are edge types that do not make their target used: an index on a table
+// does not read it.
+var nonUsageEdges = map[string]bool{"indexes": true}
+
+// ComputeUnreferenced flags candidate dead code: tables, views, procedures, functions,
+// classes and interfaces that no other symbol references. A cross-language edge below
+// the engine's confidence threshold does not count as a reference, but the symbols it
+// alone reaches are set apart as low_confidence_bridge rather than reported unreferenced.
+// Each candidate's status is stored as reference_status in its metadata; counts and a
+// sample per kind go under the "unreferenced" analytics scope with a project overview.
+func (e *Engine) ComputeUnreferenced(ctx context.Context, projectID uuid.UUID) error {
+	symbols, err := e.store.ListSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+	edges, err := e.store.ListEdgesByProject(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("list edges: %w", err)
+	}
+
+	statuses := classifyReferences(symbols, edges, e.lowConfidence)
+	e.logger.Info("computing unreferenced symbols",
+		slog.Int("candidates", len(statuses)),
+		slog.Float64("threshold", e.lowConfidence))
+
+	type kindCounts struct {
+		unreferenced, lowConfidence int
+		sample                      []string
+	}
+	byKind := make(map[string]*kindCounts, len(unreferencedKinds))
+	for _, k := range unreferencedKinds {
+		byKind[k] = &kindCounts{}
+	}
+	unreferenced, lowConfidence := 0, 0
+	changed := make(map[uuid.UUID]map[string]any)
+	for _, sym := range symbols {
+		status, ok := statuses[sym.ID]
+		if !ok {
+			continue
+		}
+		switch status {
+		case statusUnreferenced:
+			unreferenced++
+			byKind[sym.Kind].unreferenced++
+			byKind[sym.Kind].sample = append(byKind[sym.Kind].sample, sym.QualifiedName)
+		case statusLowConfidenceBridge:
+			lowConfidence++
+			byKind[sym.Kind].lowConfidence++
+		}
+
+		// Most statuses hold between runs; only changes are written
+		var meta struct {
+			ReferenceStatus string `json:"reference_status"`
+		}
+		if len(sym.Metadata) > 0 {
+			_ = json.Unmarshal(sym.Metadata, &meta)
+		}
+		if meta.ReferenceStatus == status {
+			continue
+		}
+		changed[sym.ID] = map[string]any{"reference_status": status}
+	}
+	e.updateSymbolsMetadata(ctx, changed, "reference status")
+
+	// Every candidate kind is written, so a kind emptied since the last run reads zero
+	for _, kind := range unreferencedKinds {
+		c := byKind[kind]
+		sort.Strings(c.sample)
+		if len(c.sample) > unreferencedSampleSize {
+			c.sample = c.sample[:unreferencedSampleSize]
+		}
+		kindJSON, _ := json.Marshal(map[string]any{
+			"kind":                 kind,
+			"unreferenced_count":   c.unreferenced,
+			"low_confidence_count": c.lowConfidence,
+			"sample":               c.sample,
+		})
+		summary := fmt.Sprintf("%d unreferenced %s symbols", c.unreferenced, kind)
+		if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+			ProjectID: projectID,
+			Scope:     "unreferenced",
+			ScopeID:   kind,
+			Analytics: kindJSON,
+			Summary:   &summary,
+		}); err != nil {
+			e.logger.Warn("failed to upsert unreferenced symbols", slog.String("kind", kind))
+		}
+	}
+
+	overviewJSON, _ := json.Marshal(map[string]any{
+		"candidate_count":      len(statuses),
+		"unreferenced_count":   unreferenced,
+		"low_confidence_count": lowConfidence,
+		"confidence_threshold": e.lowConfidence,
+	})
+	summary := fmt.Sprintf("%d of %d candidate symbols are unreferenced; %d more are reached only by cross-language links below %.2f confidence.",
+		unreferenced, len(statuses), lowConfidence, e.lowConfidence)
+	if _, err := e.store.UpsertProjectAnalytics(ctx, postgres.UpsertProjectAnalyticsParams{
+		ProjectID: projectID,
+		Scope:     "project",
+		ScopeID:   "unreferenced",
+		Analytics: overviewJSON,
+		Summary:   &summary,
+	}); err != nil {
+		return fmt.Errorf("upsert unreferenced overview: %w", err)
+	}
+	return nil
+}
+
+// classifyReferences returns the reference status of each candidate symbol. An inbound
+// edge from another symbol references its target unless its type is not a use or it is
+// a cross-language bridge below threshold.
+func classifyReferences(symbols []postgres.Symbol, edges []postgres.SymbolEdge, threshold float64) map[uuid.UUID]string {
+	candidate := make(map[string]bool, len(unreferencedKinds))
+	for _, k := range unreferencedKinds {
+		candidate[k] = true
+	}
+	statuses := make(map[uuid.UUID]string)
+	for _, sym := range symbols {
+		if candidate[sym.Kind] && !entryPointNames[sym.Name] {
+			statuses[sym.ID] = statusUnreferenced
+		}
+	}
+
+	for _, edge := range edges {
+		status, ok := statuses[edge.TargetID]
+		if !ok || status == statusReferenced || edge.SourceID == edge.TargetID || nonUsageEdges[edge.EdgeType] {
+			continue
+		}
+		var meta struct {
+			Confidence *float64 `json:"confidence"`
+			Bridge     string   `json:"bridge"`
+		}
+		if len(edge.Metadata) > 0 {
+			_ = json.Unmarshal(edge.Metadata, &meta)
+		}
+		if meta.Bridge != "" && meta.Confidence != nil && *meta.Confidence < threshold {
+			statuses[edge.TargetID] = statusLowConfidenceBridge
+			continue
+		}
+		statuses[edge.TargetID] = statusReferenced
+	}
+	return statuses
+}
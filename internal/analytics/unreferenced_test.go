@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestClassifyReferences(t *testing.T) {
+	sym := func(name, kind string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: name, QualifiedName: "app." + name, Kind: kind}
+	}
+	var (
+		orders    = sym("Orders", "table")
+		archive   = sym("OrdersArchive", "table") // only indexed
+		legacy    = sym("usp_Legacy", "procedure")
+		bridged   = sym("usp_GetOrder", "procedure") // called from C# by a weak name match
+		exact     = sym("usp_PlaceOrder", "procedure")
+		recursive = sym("walk", "function")
+		mainFn    = sym("main", "function")
+		endpoint  = sym("GET /orders", "endpoint")
+		column    = sym("Orders.Id", "column")
+	)
+	symbols := []postgres.Symbol{orders, archive, legacy, bridged, exact, recursive, mainFn, endpoint, column}
+	edge := func(from, to postgres.Symbol, edgeType, meta string) postgres.SymbolEdge {
+		return postgres.SymbolEdge{SourceID: from.ID, TargetID: to.ID, EdgeType: edgeType, Metadata: []byte(meta)}
+	}
+	edges := []postgres.SymbolEdge{
+		edge(exact, orders, "writes_to", ""),
+		edge(sym("ix_archive", "index"), archive, "indexes", ""),
+		edge(endpoint, bridged, "calls", `{"confidence": 0.6, "bridge": "csharp→tsql", "match_strategy": "case_insensitive"}`),
+		edge(endpoint, exact, "calls", `{"confidence": 0.95, "bridge": "csharp→tsql", "match_strategy": "schema_qualified"}`),
+		edge(recursive, recursive, "calls", ""),
+	}
+
+	got := classifyReferences(symbols, edges, DefaultLowConfidenceThreshold)
+	want := map[uuid.UUID]string{
+		orders.ID:    statusReferenced,
+		archive.ID:   statusUnreferenced,
+		legacy.ID:    statusUnreferenced,
+		bridged.ID:   statusLowConfidenceBridge,
+		exact.ID:     statusReferenced,
+		recursive.ID: statusUnreferenced,
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d candidates (no entry points or columns), got %d", len(want), len(got))
+	}
+	for _, s := range symbols {
+		if got[s.ID] != want[s.ID] {
+			t.Errorf("%s: got %q, want %q", s.Name, got[s.ID], want[s.ID])
+		}
+	}
+
+	// A confident edge outweighs a weak one in either order
+	edges = append(edges, edge(exact, bridged, "calls", ""))
+	if got := classifyReferences(symbols, edges, DefaultLowConfidenceThreshold); got[bridged.ID] != statusReferenced {
+		t.Errorf("expected a confident caller to make usp_GetOrder referenced, got %q", got[bridged.ID])
+	}
+	// Lowering the threshold trusts the weak match
+	if got := classifyReferences(symbols, edges[:3], 0.5); got[bridged.ID] != statusReferenced {
+		t.Errorf("expected a 0.6 bridge to count at threshold 0.5, got %q", got[bridged.ID])
+	}
+}
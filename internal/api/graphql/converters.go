@@ -135,22 +135,3 @@ func impactNodeToGQL(n impact.ImpactNode) *ImpactNode {
 		Path:     n.Path,
 	}
 }
-
-func filterEdges(dbEdges []postgres.SymbolEdge, types []models.EdgeType) []*models.SymbolEdge {
-	typeSet := make(map[string]bool, len(types))
-	for _, t := range types {
-		typeSet[strings.ToLower(string(t))] = true
-	}
-
-	var result []*models.SymbolEdge
-	for _, e := range dbEdges {
-		if len(typeSet) > 0 && !typeSet[strings.ToLower(e.EdgeType)] {
-			continue
-		}
-		result = append(result, dbEdgeToGQL(e))
-	}
-	if result == nil {
-		result = []*models.SymbolEdge{}
-	}
-	return result
-}
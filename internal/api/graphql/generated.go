@@ -84,6 +84,7 @@ type ComplexityRoot struct {
 		ChangeType       func(childComplexity int) int
 		DirectImpact     func(childComplexity int) int
 		Root             func(childComplexity int) int
+		Severity         func(childComplexity int) int
 		TotalAffected    func(childComplexity int) int
 		TransitiveImpact func(childComplexity int) int
 	}
@@ -131,6 +132,11 @@ type ComplexityRoot struct {
 		UpdateProject   func(childComplexity int, slug string, input UpdateProjectInput) int
 	}
 
+	PageInfo struct {
+		EndCursor   func(childComplexity int) int
+		HasNextPage func(childComplexity int) int
+	}
+
 	Project struct {
 		CreatedAt   func(childComplexity int) int
 		Description func(childComplexity int) int
@@ -151,6 +157,7 @@ type ComplexityRoot struct {
 
 	Query struct {
 		ColumnLineage  func(childComplexity int, columnID string, depth *int, direction *LineageDirection) int
+		Edges          func(childComplexity int, symbolID string, direction *EdgeDirection, types []models.EdgeType, first int, after *string) int
 		ImpactAnalysis func(childComplexity int, symbolID string, changeType *ChangeType, maxDepth *int) int
 		LineageGraph   func(childComplexity int, symbolID string, depth *int, direction *LineageDirection) int
 		Project        func(childComplexity int, slug string) int
@@ -194,6 +201,11 @@ type ComplexityRoot struct {
 		Source   func(childComplexity int) int
 		Target   func(childComplexity int) int
 	}
+
+	SymbolEdgeConnection struct {
+		Nodes    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
 }
 
 type FileResolver interface {
@@ -222,6 +234,7 @@ type QueryResolver interface {
 	SemanticSearch(ctx context.Context, projectSlug string, query string, kinds []models.SymbolKind, topK *int) ([]*SemanticSearchResult, error)
 	ColumnLineage(ctx context.Context, columnID string, depth *int, direction *LineageDirection) (*ColumnLineageGraph, error)
 	ImpactAnalysis(ctx context.Context, symbolID string, changeType *ChangeType, maxDepth *int) (*ImpactAnalysisResult, error)
+	Edges(ctx context.Context, symbolID string, direction *EdgeDirection, types []models.EdgeType, first int, after *string) (*SymbolEdgeConnection, error)
 }
 type SymbolResolver interface {
 	ID(ctx context.Context, obj *models.Symbol) (string, error)
@@ -374,6 +387,12 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.ImpactAnalysisResult.Root(childComplexity), true
+	case "ImpactAnalysisResult.severity":
+		if e.complexity.ImpactAnalysisResult.Severity == nil {
+			break
+		}
+
+		return e.complexity.ImpactAnalysisResult.Severity(childComplexity), true
 	case "ImpactAnalysisResult.totalAffected":
 		if e.complexity.ImpactAnalysisResult.TotalAffected == nil {
 			break
@@ -590,6 +609,19 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Mutation.UpdateProject(childComplexity, args["slug"].(string), args["input"].(UpdateProjectInput)), true
 
+	case "PageInfo.endCursor":
+		if e.complexity.PageInfo.EndCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.EndCursor(childComplexity), true
+	case "PageInfo.hasNextPage":
+		if e.complexity.PageInfo.HasNextPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+
 	case "Project.createdAt":
 		if e.complexity.Project.CreatedAt == nil {
 			break
@@ -680,6 +712,17 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 		}
 
 		return e.complexity.Query.ColumnLineage(childComplexity, args["columnId"].(string), args["depth"].(*int), args["direction"].(*LineageDirection)), true
+	case "Query.edges":
+		if e.complexity.Query.Edges == nil {
+			break
+		}
+
+		args, err := ec.field_Query_edges_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Edges(childComplexity, args["symbolId"].(string), args["direction"].(*EdgeDirection), args["types"].([]models.EdgeType), args["first"].(int), args["after"].(*string)), true
 	case "Query.impactAnalysis":
 		if e.complexity.Query.ImpactAnalysis == nil {
 			break
@@ -910,6 +953,19 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.SymbolEdge.Target(childComplexity), true
 
+	case "SymbolEdgeConnection.nodes":
+		if e.complexity.SymbolEdgeConnection.Nodes == nil {
+			break
+		}
+
+		return e.complexity.SymbolEdgeConnection.Nodes(childComplexity), true
+	case "SymbolEdgeConnection.pageInfo":
+		if e.complexity.SymbolEdgeConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.SymbolEdgeConnection.PageInfo(childComplexity), true
+
 	}
 	return 0, false
 }
@@ -1040,7 +1096,7 @@ var parsedSchema = gqlparser.MustLoadSchema(sources...)
 func (ec *executionContext) field_Mutation_createProject_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNCreateProjectInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateProjectInput)
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNCreateProjectInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateProjectInput)
 	if err != nil {
 		return nil, err
 	}
@@ -1056,7 +1112,7 @@ func (ec *executionContext) field_Mutation_createSource_args(ctx context.Context
 		return nil, err
 	}
 	args["projectSlug"] = arg0
-	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNCreateSourceInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateSourceInput)
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNCreateSourceInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateSourceInput)
 	if err != nil {
 		return nil, err
 	}
@@ -1110,7 +1166,7 @@ func (ec *executionContext) field_Mutation_updateProject_args(ctx context.Contex
 		return nil, err
 	}
 	args["slug"] = arg0
-	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNUpdateProjectInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐUpdateProjectInput)
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "input", ec.unmarshalNUpdateProjectInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐUpdateProjectInput)
 	if err != nil {
 		return nil, err
 	}
@@ -1153,7 +1209,7 @@ func (ec *executionContext) field_Query_columnLineage_args(ctx context.Context,
 		return nil, err
 	}
 	args["depth"] = arg1
-	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "direction", ec.unmarshalOLineageDirection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection)
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "direction", ec.unmarshalOLineageDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection)
 	if err != nil {
 		return nil, err
 	}
@@ -1161,6 +1217,37 @@ func (ec *executionContext) field_Query_columnLineage_args(ctx context.Context,
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_edges_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "symbolId", ec.unmarshalNID2string)
+	if err != nil {
+		return nil, err
+	}
+	args["symbolId"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "direction", ec.unmarshalOEdgeDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐEdgeDirection)
+	if err != nil {
+		return nil, err
+	}
+	args["direction"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "types", ec.unmarshalOEdgeType2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["types"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "first", ec.unmarshalNInt2int)
+	if err != nil {
+		return nil, err
+	}
+	args["first"] = arg3
+	arg4, err := graphql.ProcessArgField(ctx, rawArgs, "after", ec.unmarshalOString2ᚖstring)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg4
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_impactAnalysis_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -1169,7 +1256,7 @@ func (ec *executionContext) field_Query_impactAnalysis_args(ctx context.Context,
 		return nil, err
 	}
 	args["symbolId"] = arg0
-	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "changeType", ec.unmarshalOChangeType2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType)
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "changeType", ec.unmarshalOChangeType2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType)
 	if err != nil {
 		return nil, err
 	}
@@ -1195,7 +1282,7 @@ func (ec *executionContext) field_Query_lineageGraph_args(ctx context.Context, r
 		return nil, err
 	}
 	args["depth"] = arg1
-	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "direction", ec.unmarshalOLineageDirection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection)
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "direction", ec.unmarshalOLineageDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection)
 	if err != nil {
 		return nil, err
 	}
@@ -1243,7 +1330,7 @@ func (ec *executionContext) field_Query_searchSymbols_args(ctx context.Context,
 		return nil, err
 	}
 	args["query"] = arg1
-	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "kinds", ec.unmarshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ)
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "kinds", ec.unmarshalOSymbolKind2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ)
 	if err != nil {
 		return nil, err
 	}
@@ -1274,7 +1361,7 @@ func (ec *executionContext) field_Query_semanticSearch_args(ctx context.Context,
 		return nil, err
 	}
 	args["query"] = arg1
-	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "kinds", ec.unmarshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ)
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "kinds", ec.unmarshalOSymbolKind2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ)
 	if err != nil {
 		return nil, err
 	}
@@ -1301,7 +1388,7 @@ func (ec *executionContext) field_Query_symbol_args(ctx context.Context, rawArgs
 func (ec *executionContext) field_Symbol_incomingEdges_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "types", ec.unmarshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ)
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "types", ec.unmarshalOEdgeType2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ)
 	if err != nil {
 		return nil, err
 	}
@@ -1312,7 +1399,7 @@ func (ec *executionContext) field_Symbol_incomingEdges_args(ctx context.Context,
 func (ec *executionContext) field_Symbol_outgoingEdges_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "types", ec.unmarshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ)
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "types", ec.unmarshalOEdgeType2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ)
 	if err != nil {
 		return nil, err
 	}
@@ -1498,7 +1585,7 @@ func (ec *executionContext) _ColumnLineageGraph_nodes(ctx context.Context, field
 			return obj.Nodes, nil
 		},
 		nil,
-		ec.marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNodeᚄ,
+		ec.marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNodeᚄ,
 		true,
 		true,
 	)
@@ -1539,7 +1626,7 @@ func (ec *executionContext) _ColumnLineageGraph_edges(ctx context.Context, field
 			return obj.Edges, nil
 		},
 		nil,
-		ec.marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdgeᚄ,
+		ec.marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdgeᚄ,
 		true,
 		true,
 	)
@@ -1868,7 +1955,7 @@ func (ec *executionContext) _ImpactAnalysisResult_root(ctx context.Context, fiel
 			return obj.Root, nil
 		},
 		nil,
-		ec.marshalNImpactSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol,
+		ec.marshalNImpactSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol,
 		true,
 		true,
 	)
@@ -1909,7 +1996,7 @@ func (ec *executionContext) _ImpactAnalysisResult_changeType(ctx context.Context
 			return obj.ChangeType, nil
 		},
 		nil,
-		ec.marshalNChangeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType,
+		ec.marshalNChangeType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType,
 		true,
 		true,
 	)
@@ -1938,7 +2025,7 @@ func (ec *executionContext) _ImpactAnalysisResult_directImpact(ctx context.Conte
 			return obj.DirectImpact, nil
 		},
 		nil,
-		ec.marshalNImpactNode2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ,
+		ec.marshalNImpactNode2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ,
 		true,
 		true,
 	)
@@ -1979,7 +2066,7 @@ func (ec *executionContext) _ImpactAnalysisResult_transitiveImpact(ctx context.C
 			return obj.TransitiveImpact, nil
 		},
 		nil,
-		ec.marshalNImpactNode2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ,
+		ec.marshalNImpactNode2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ,
 		true,
 		true,
 	)
@@ -2039,6 +2126,35 @@ func (ec *executionContext) fieldContext_ImpactAnalysisResult_totalAffected(_ co
 	return fc, nil
 }
 
+func (ec *executionContext) _ImpactAnalysisResult_severity(ctx context.Context, field graphql.CollectedField, obj *ImpactAnalysisResult) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_ImpactAnalysisResult_severity,
+		func(ctx context.Context) (any, error) {
+			return obj.Severity, nil
+		},
+		nil,
+		ec.marshalNSeverity2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_ImpactAnalysisResult_severity(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ImpactAnalysisResult",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Severity does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _ImpactNode_symbol(ctx context.Context, field graphql.CollectedField, obj *ImpactNode) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -2049,7 +2165,7 @@ func (ec *executionContext) _ImpactNode_symbol(ctx context.Context, field graphq
 			return obj.Symbol, nil
 		},
 		nil,
-		ec.marshalNImpactSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol,
+		ec.marshalNImpactSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol,
 		true,
 		true,
 	)
@@ -2119,7 +2235,7 @@ func (ec *executionContext) _ImpactNode_severity(ctx context.Context, field grap
 			return obj.Severity, nil
 		},
 		nil,
-		ec.marshalNSeverity2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity,
+		ec.marshalNSeverity2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity,
 		true,
 		true,
 	)
@@ -2380,7 +2496,7 @@ func (ec *executionContext) _IndexRun_status(ctx context.Context, field graphql.
 			return obj.Status, nil
 		},
 		nil,
-		ec.marshalNIndexRunStatus2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus,
+		ec.marshalNIndexRunStatus2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus,
 		true,
 		true,
 	)
@@ -2612,7 +2728,7 @@ func (ec *executionContext) _LineageGraph_nodes(ctx context.Context, field graph
 			return obj.Nodes, nil
 		},
 		nil,
-		ec.marshalNSymbol2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ,
+		ec.marshalNSymbol2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ,
 		true,
 		true,
 	)
@@ -2667,7 +2783,7 @@ func (ec *executionContext) _LineageGraph_edges(ctx context.Context, field graph
 			return obj.Edges, nil
 		},
 		nil,
-		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
+		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
 		true,
 		true,
 	)
@@ -2736,7 +2852,7 @@ func (ec *executionContext) _Mutation_createProject(ctx context.Context, field g
 			return ec.resolvers.Mutation().CreateProject(ctx, fc.Args["input"].(CreateProjectInput))
 		},
 		nil,
-		ec.marshalNProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
+		ec.marshalNProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
 		true,
 		true,
 	)
@@ -2799,7 +2915,7 @@ func (ec *executionContext) _Mutation_updateProject(ctx context.Context, field g
 			return ec.resolvers.Mutation().UpdateProject(ctx, fc.Args["slug"].(string), fc.Args["input"].(UpdateProjectInput))
 		},
 		nil,
-		ec.marshalNProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
+		ec.marshalNProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
 		true,
 		true,
 	)
@@ -2903,7 +3019,7 @@ func (ec *executionContext) _Mutation_createSource(ctx context.Context, field gr
 			return ec.resolvers.Mutation().CreateSource(ctx, fc.Args["projectSlug"].(string), fc.Args["input"].(CreateSourceInput))
 		},
 		nil,
-		ec.marshalNSource2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource,
+		ec.marshalNSource2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource,
 		true,
 		true,
 	)
@@ -2997,7 +3113,7 @@ func (ec *executionContext) _Mutation_triggerIndexRun(ctx context.Context, field
 			return ec.resolvers.Mutation().TriggerIndexRun(ctx, fc.Args["projectSlug"].(string), fc.Args["sourceId"].(*string))
 		},
 		nil,
-		ec.marshalNIndexRun2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun,
+		ec.marshalNIndexRun2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun,
 		true,
 		true,
 	)
@@ -3047,6 +3163,64 @@ func (ec *executionContext) fieldContext_Mutation_triggerIndexRun(ctx context.Co
 	return fc, nil
 }
 
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PageInfo_hasNextPage,
+		func(ctx context.Context) (any, error) {
+			return obj.HasNextPage, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_hasNextPage(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_PageInfo_endCursor,
+		func(ctx context.Context) (any, error) {
+			return obj.EndCursor, nil
+		},
+		nil,
+		ec.marshalOString2ᚖstring,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_endCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Project_id(ctx context.Context, field graphql.CollectedField, obj *Project) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -3173,7 +3347,7 @@ func (ec *executionContext) _Project_sources(ctx context.Context, field graphql.
 			return ec.resolvers.Project().Sources(ctx, obj)
 		},
 		nil,
-		ec.marshalNSource2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceᚄ,
+		ec.marshalNSource2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceᚄ,
 		true,
 		true,
 	)
@@ -3215,7 +3389,7 @@ func (ec *executionContext) _Project_indexRuns(ctx context.Context, field graphq
 			return ec.resolvers.Project().IndexRuns(ctx, obj, fc.Args["limit"].(*int))
 		},
 		nil,
-		ec.marshalNIndexRun2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunᚄ,
+		ec.marshalNIndexRun2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunᚄ,
 		true,
 		true,
 	)
@@ -3391,7 +3565,7 @@ func (ec *executionContext) _ProjectConnection_nodes(ctx context.Context, field
 			return obj.Nodes, nil
 		},
 		nil,
-		ec.marshalNProject2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectᚄ,
+		ec.marshalNProject2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectᚄ,
 		true,
 		true,
 	)
@@ -3472,7 +3646,7 @@ func (ec *executionContext) _Query_projects(ctx context.Context, field graphql.C
 			return ec.resolvers.Query().Projects(ctx, fc.Args["limit"].(*int), fc.Args["offset"].(*int))
 		},
 		nil,
-		ec.marshalNProjectConnection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection,
+		ec.marshalNProjectConnection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection,
 		true,
 		true,
 	)
@@ -3519,7 +3693,7 @@ func (ec *executionContext) _Query_project(ctx context.Context, field graphql.Co
 			return ec.resolvers.Query().Project(ctx, fc.Args["slug"].(string))
 		},
 		nil,
-		ec.marshalOProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
+		ec.marshalOProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject,
 		true,
 		false,
 	)
@@ -3582,7 +3756,7 @@ func (ec *executionContext) _Query_symbol(ctx context.Context, field graphql.Col
 			return ec.resolvers.Query().Symbol(ctx, fc.Args["id"].(string))
 		},
 		nil,
-		ec.marshalOSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol,
+		ec.marshalOSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol,
 		true,
 		false,
 	)
@@ -3649,7 +3823,7 @@ func (ec *executionContext) _Query_searchSymbols(ctx context.Context, field grap
 			return ec.resolvers.Query().SearchSymbols(ctx, fc.Args["projectSlug"].(string), fc.Args["query"].(string), fc.Args["kinds"].([]models.SymbolKind), fc.Args["languages"].([]string), fc.Args["limit"].(*int))
 		},
 		nil,
-		ec.marshalNSymbol2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ,
+		ec.marshalNSymbol2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ,
 		true,
 		true,
 	)
@@ -3716,7 +3890,7 @@ func (ec *executionContext) _Query_lineageGraph(ctx context.Context, field graph
 			return ec.resolvers.Query().LineageGraph(ctx, fc.Args["symbolId"].(string), fc.Args["depth"].(*int), fc.Args["direction"].(*LineageDirection))
 		},
 		nil,
-		ec.marshalNLineageGraph2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph,
+		ec.marshalNLineageGraph2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph,
 		true,
 		true,
 	)
@@ -3765,7 +3939,7 @@ func (ec *executionContext) _Query_semanticSearch(ctx context.Context, field gra
 			return ec.resolvers.Query().SemanticSearch(ctx, fc.Args["projectSlug"].(string), fc.Args["query"].(string), fc.Args["kinds"].([]models.SymbolKind), fc.Args["topK"].(*int))
 		},
 		nil,
-		ec.marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResultᚄ,
+		ec.marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResultᚄ,
 		true,
 		true,
 	)
@@ -3812,7 +3986,7 @@ func (ec *executionContext) _Query_columnLineage(ctx context.Context, field grap
 			return ec.resolvers.Query().ColumnLineage(ctx, fc.Args["columnId"].(string), fc.Args["depth"].(*int), fc.Args["direction"].(*LineageDirection))
 		},
 		nil,
-		ec.marshalNColumnLineageGraph2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph,
+		ec.marshalNColumnLineageGraph2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph,
 		true,
 		true,
 	)
@@ -3861,7 +4035,7 @@ func (ec *executionContext) _Query_impactAnalysis(ctx context.Context, field gra
 			return ec.resolvers.Query().ImpactAnalysis(ctx, fc.Args["symbolId"].(string), fc.Args["changeType"].(*ChangeType), fc.Args["maxDepth"].(*int))
 		},
 		nil,
-		ec.marshalNImpactAnalysisResult2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult,
+		ec.marshalNImpactAnalysisResult2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult,
 		true,
 		true,
 	)
@@ -3885,6 +4059,8 @@ func (ec *executionContext) fieldContext_Query_impactAnalysis(ctx context.Contex
 				return ec.fieldContext_ImpactAnalysisResult_transitiveImpact(ctx, field)
 			case "totalAffected":
 				return ec.fieldContext_ImpactAnalysisResult_totalAffected(ctx, field)
+			case "severity":
+				return ec.fieldContext_ImpactAnalysisResult_severity(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type ImpactAnalysisResult", field.Name)
 		},
@@ -3903,6 +4079,53 @@ func (ec *executionContext) fieldContext_Query_impactAnalysis(ctx context.Contex
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_edges(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Query_edges,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Query().Edges(ctx, fc.Args["symbolId"].(string), fc.Args["direction"].(*EdgeDirection), fc.Args["types"].([]models.EdgeType), fc.Args["first"].(int), fc.Args["after"].(*string))
+		},
+		nil,
+		ec.marshalNSymbolEdgeConnection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSymbolEdgeConnection,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Query_edges(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "nodes":
+				return ec.fieldContext_SymbolEdgeConnection_nodes(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_SymbolEdgeConnection_pageInfo(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SymbolEdgeConnection", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_edges_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -4021,7 +4244,7 @@ func (ec *executionContext) _SemanticSearchResult_symbol(ctx context.Context, fi
 			return obj.Symbol, nil
 		},
 		nil,
-		ec.marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol,
+		ec.marshalNSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol,
 		true,
 		true,
 	)
@@ -4163,7 +4386,7 @@ func (ec *executionContext) _Source_sourceType(ctx context.Context, field graphq
 			return obj.SourceType, nil
 		},
 		nil,
-		ec.marshalNSourceType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType,
+		ec.marshalNSourceType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType,
 		true,
 		true,
 	)
@@ -4337,7 +4560,7 @@ func (ec *executionContext) _Symbol_kind(ctx context.Context, field graphql.Coll
 			return obj.Kind, nil
 		},
 		nil,
-		ec.marshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKind,
+		ec.marshalNSymbolKind2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKind,
 		true,
 		true,
 	)
@@ -4395,7 +4618,7 @@ func (ec *executionContext) _Symbol_file(ctx context.Context, field graphql.Coll
 			return ec.resolvers.Symbol().File(ctx, obj)
 		},
 		nil,
-		ec.marshalNFile2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐFile,
+		ec.marshalNFile2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐFile,
 		true,
 		true,
 	)
@@ -4551,7 +4774,7 @@ func (ec *executionContext) _Symbol_incomingEdges(ctx context.Context, field gra
 			return ec.resolvers.Symbol().IncomingEdges(ctx, obj, fc.Args["types"].([]models.EdgeType))
 		},
 		nil,
-		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
+		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
 		true,
 		true,
 	)
@@ -4602,7 +4825,7 @@ func (ec *executionContext) _Symbol_outgoingEdges(ctx context.Context, field gra
 			return ec.resolvers.Symbol().OutgoingEdges(ctx, obj, fc.Args["types"].([]models.EdgeType))
 		},
 		nil,
-		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
+		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
 		true,
 		true,
 	)
@@ -4681,7 +4904,7 @@ func (ec *executionContext) _SymbolEdge_source(ctx context.Context, field graphq
 			return ec.resolvers.SymbolEdge().Source(ctx, obj)
 		},
 		nil,
-		ec.marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol,
+		ec.marshalNSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol,
 		true,
 		true,
 	)
@@ -4736,7 +4959,7 @@ func (ec *executionContext) _SymbolEdge_target(ctx context.Context, field graphq
 			return ec.resolvers.SymbolEdge().Target(ctx, obj)
 		},
 		nil,
-		ec.marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol,
+		ec.marshalNSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol,
 		true,
 		true,
 	)
@@ -4791,7 +5014,7 @@ func (ec *executionContext) _SymbolEdge_edgeType(ctx context.Context, field grap
 			return obj.EdgeType, nil
 		},
 		nil,
-		ec.marshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeType,
+		ec.marshalNEdgeType2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeType,
 		true,
 		true,
 	)
@@ -4810,6 +5033,80 @@ func (ec *executionContext) fieldContext_SymbolEdge_edgeType(_ context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _SymbolEdgeConnection_nodes(ctx context.Context, field graphql.CollectedField, obj *SymbolEdgeConnection) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SymbolEdgeConnection_nodes,
+		func(ctx context.Context) (any, error) {
+			return obj.Nodes, nil
+		},
+		nil,
+		ec.marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SymbolEdgeConnection_nodes(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SymbolEdgeConnection",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_SymbolEdge_id(ctx, field)
+			case "source":
+				return ec.fieldContext_SymbolEdge_source(ctx, field)
+			case "target":
+				return ec.fieldContext_SymbolEdge_target(ctx, field)
+			case "edgeType":
+				return ec.fieldContext_SymbolEdge_edgeType(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SymbolEdge", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SymbolEdgeConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *SymbolEdgeConnection) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_SymbolEdgeConnection_pageInfo,
+		func(ctx context.Context) (any, error) {
+			return obj.PageInfo, nil
+		},
+		nil,
+		ec.marshalNPageInfo2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐPageInfo,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_SymbolEdgeConnection_pageInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SymbolEdgeConnection",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "hasNextPage":
+				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+			case "endCursor":
+				return ec.fieldContext_PageInfo_endCursor(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -6320,7 +6617,7 @@ func (ec *executionContext) unmarshalInputCreateSourceInput(ctx context.Context,
 			it.Name = data
 		case "sourceType":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("sourceType"))
-			data, err := ec.unmarshalNSourceType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx, v)
+			data, err := ec.unmarshalNSourceType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx, v)
 			if err != nil {
 				return it, err
 			}
@@ -6667,6 +6964,11 @@ func (ec *executionContext) _ImpactAnalysisResult(ctx context.Context, sel ast.S
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "severity":
+			out.Values[i] = ec._ImpactAnalysisResult_severity(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -7011,6 +7313,47 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 	return out
 }
 
+var pageInfoImplementors = []string{"PageInfo"}
+
+func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, pageInfoImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "hasNextPage":
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "endCursor":
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var projectImplementors = []string{"Project"}
 
 func (ec *executionContext) _Project(ctx context.Context, sel ast.SelectionSet, obj *Project) graphql.Marshaler {
@@ -7448,6 +7791,28 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "edges":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_edges(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -7939,6 +8304,50 @@ func (ec *executionContext) _SymbolEdge(ctx context.Context, sel ast.SelectionSe
 	return out
 }
 
+var symbolEdgeConnectionImplementors = []string{"SymbolEdgeConnection"}
+
+func (ec *executionContext) _SymbolEdgeConnection(ctx context.Context, sel ast.SelectionSet, obj *SymbolEdgeConnection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, symbolEdgeConnectionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SymbolEdgeConnection")
+		case "nodes":
+			out.Values[i] = ec._SymbolEdgeConnection_nodes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "pageInfo":
+			out.Values[i] = ec._SymbolEdgeConnection_pageInfo(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
@@ -8290,17 +8699,17 @@ func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.Se
 	return res
 }
 
-func (ec *executionContext) unmarshalNChangeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, v any) (ChangeType, error) {
+func (ec *executionContext) unmarshalNChangeType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, v any) (ChangeType, error) {
 	var res ChangeType
 	err := res.UnmarshalGQL(v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNChangeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, sel ast.SelectionSet, v ChangeType) graphql.Marshaler {
+func (ec *executionContext) marshalNChangeType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, sel ast.SelectionSet, v ChangeType) graphql.Marshaler {
 	return v
 }
 
-func (ec *executionContext) marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdgeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ColumnLineageEdge) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdgeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ColumnLineageEdge) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8324,7 +8733,7 @@ func (ec *executionContext) marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋlatti
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNColumnLineageEdge2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdge(ctx, sel, v[i])
+			ret[i] = ec.marshalNColumnLineageEdge2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdge(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8344,7 +8753,7 @@ func (ec *executionContext) marshalNColumnLineageEdge2ᚕᚖgithubᚗcomᚋlatti
 	return ret
 }
 
-func (ec *executionContext) marshalNColumnLineageEdge2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdge(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageEdge) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageEdge2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageEdge(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageEdge) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8354,11 +8763,11 @@ func (ec *executionContext) marshalNColumnLineageEdge2ᚖgithubᚗcomᚋlattice
 	return ec._ColumnLineageEdge(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNColumnLineageGraph2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph(ctx context.Context, sel ast.SelectionSet, v ColumnLineageGraph) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageGraph2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph(ctx context.Context, sel ast.SelectionSet, v ColumnLineageGraph) graphql.Marshaler {
 	return ec._ColumnLineageGraph(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNColumnLineageGraph2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageGraph) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageGraph2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageGraph(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageGraph) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8368,7 +8777,7 @@ func (ec *executionContext) marshalNColumnLineageGraph2ᚖgithubᚗcomᚋlattice
 	return ec._ColumnLineageGraph(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNodeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ColumnLineageNode) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNodeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ColumnLineageNode) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8392,7 +8801,7 @@ func (ec *executionContext) marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋlatti
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNColumnLineageNode2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNode(ctx, sel, v[i])
+			ret[i] = ec.marshalNColumnLineageNode2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNode(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8412,7 +8821,7 @@ func (ec *executionContext) marshalNColumnLineageNode2ᚕᚖgithubᚗcomᚋlatti
 	return ret
 }
 
-func (ec *executionContext) marshalNColumnLineageNode2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNode(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageNode) graphql.Marshaler {
+func (ec *executionContext) marshalNColumnLineageNode2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐColumnLineageNode(ctx context.Context, sel ast.SelectionSet, v *ColumnLineageNode) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8422,12 +8831,12 @@ func (ec *executionContext) marshalNColumnLineageNode2ᚖgithubᚗcomᚋlattice
 	return ec._ColumnLineageNode(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNCreateProjectInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateProjectInput(ctx context.Context, v any) (CreateProjectInput, error) {
+func (ec *executionContext) unmarshalNCreateProjectInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateProjectInput(ctx context.Context, v any) (CreateProjectInput, error) {
 	res, err := ec.unmarshalInputCreateProjectInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) unmarshalNCreateSourceInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateSourceInput(ctx context.Context, v any) (CreateSourceInput, error) {
+func (ec *executionContext) unmarshalNCreateSourceInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐCreateSourceInput(ctx context.Context, v any) (CreateSourceInput, error) {
 	res, err := ec.unmarshalInputCreateSourceInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
@@ -8448,13 +8857,13 @@ func (ec *executionContext) marshalNDateTime2timeᚐTime(ctx context.Context, se
 	return res
 }
 
-func (ec *executionContext) unmarshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx context.Context, v any) (models.EdgeType, error) {
+func (ec *executionContext) unmarshalNEdgeType2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx context.Context, v any) (models.EdgeType, error) {
 	tmp, err := graphql.UnmarshalString(v)
 	res := models.EdgeType(tmp)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx context.Context, sel ast.SelectionSet, v models.EdgeType) graphql.Marshaler {
+func (ec *executionContext) marshalNEdgeType2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx context.Context, sel ast.SelectionSet, v models.EdgeType) graphql.Marshaler {
 	_ = sel
 	res := graphql.MarshalString(string(v))
 	if res == graphql.Null {
@@ -8465,11 +8874,11 @@ func (ec *executionContext) marshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlat
 	return res
 }
 
-func (ec *executionContext) marshalNFile2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐFile(ctx context.Context, sel ast.SelectionSet, v models.File) graphql.Marshaler {
+func (ec *executionContext) marshalNFile2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐFile(ctx context.Context, sel ast.SelectionSet, v models.File) graphql.Marshaler {
 	return ec._File(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNFile2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐFile(ctx context.Context, sel ast.SelectionSet, v *models.File) graphql.Marshaler {
+func (ec *executionContext) marshalNFile2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐFile(ctx context.Context, sel ast.SelectionSet, v *models.File) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8541,11 +8950,11 @@ func (ec *executionContext) marshalNID2ᚕstringᚄ(ctx context.Context, sel ast
 	return ret
 }
 
-func (ec *executionContext) marshalNImpactAnalysisResult2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult(ctx context.Context, sel ast.SelectionSet, v ImpactAnalysisResult) graphql.Marshaler {
+func (ec *executionContext) marshalNImpactAnalysisResult2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult(ctx context.Context, sel ast.SelectionSet, v ImpactAnalysisResult) graphql.Marshaler {
 	return ec._ImpactAnalysisResult(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNImpactAnalysisResult2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult(ctx context.Context, sel ast.SelectionSet, v *ImpactAnalysisResult) graphql.Marshaler {
+func (ec *executionContext) marshalNImpactAnalysisResult2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactAnalysisResult(ctx context.Context, sel ast.SelectionSet, v *ImpactAnalysisResult) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8555,7 +8964,7 @@ func (ec *executionContext) marshalNImpactAnalysisResult2ᚖgithubᚗcomᚋlatti
 	return ec._ImpactAnalysisResult(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNImpactNode2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ImpactNode) graphql.Marshaler {
+func (ec *executionContext) marshalNImpactNode2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNodeᚄ(ctx context.Context, sel ast.SelectionSet, v []*ImpactNode) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8579,7 +8988,7 @@ func (ec *executionContext) marshalNImpactNode2ᚕᚖgithubᚗcomᚋlatticeᚑla
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNImpactNode2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNode(ctx, sel, v[i])
+			ret[i] = ec.marshalNImpactNode2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNode(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8599,7 +9008,7 @@ func (ec *executionContext) marshalNImpactNode2ᚕᚖgithubᚗcomᚋlatticeᚑla
 	return ret
 }
 
-func (ec *executionContext) marshalNImpactNode2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNode(ctx context.Context, sel ast.SelectionSet, v *ImpactNode) graphql.Marshaler {
+func (ec *executionContext) marshalNImpactNode2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactNode(ctx context.Context, sel ast.SelectionSet, v *ImpactNode) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8609,7 +9018,7 @@ func (ec *executionContext) marshalNImpactNode2ᚖgithubᚗcomᚋlatticeᚑlabs
 	return ec._ImpactNode(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNImpactSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol(ctx context.Context, sel ast.SelectionSet, v *ImpactSymbol) graphql.Marshaler {
+func (ec *executionContext) marshalNImpactSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐImpactSymbol(ctx context.Context, sel ast.SelectionSet, v *ImpactSymbol) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8619,11 +9028,11 @@ func (ec *executionContext) marshalNImpactSymbol2ᚖgithubᚗcomᚋlatticeᚑlab
 	return ec._ImpactSymbol(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNIndexRun2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx context.Context, sel ast.SelectionSet, v IndexRun) graphql.Marshaler {
+func (ec *executionContext) marshalNIndexRun2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx context.Context, sel ast.SelectionSet, v IndexRun) graphql.Marshaler {
 	return ec._IndexRun(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNIndexRun2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunᚄ(ctx context.Context, sel ast.SelectionSet, v []*IndexRun) graphql.Marshaler {
+func (ec *executionContext) marshalNIndexRun2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunᚄ(ctx context.Context, sel ast.SelectionSet, v []*IndexRun) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8647,7 +9056,7 @@ func (ec *executionContext) marshalNIndexRun2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNIndexRun2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx, sel, v[i])
+			ret[i] = ec.marshalNIndexRun2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8667,7 +9076,7 @@ func (ec *executionContext) marshalNIndexRun2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 	return ret
 }
 
-func (ec *executionContext) marshalNIndexRun2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx context.Context, sel ast.SelectionSet, v *IndexRun) graphql.Marshaler {
+func (ec *executionContext) marshalNIndexRun2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRun(ctx context.Context, sel ast.SelectionSet, v *IndexRun) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8677,13 +9086,13 @@ func (ec *executionContext) marshalNIndexRun2ᚖgithubᚗcomᚋlatticeᚑlabsᚋ
 	return ec._IndexRun(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNIndexRunStatus2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus(ctx context.Context, v any) (IndexRunStatus, error) {
+func (ec *executionContext) unmarshalNIndexRunStatus2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus(ctx context.Context, v any) (IndexRunStatus, error) {
 	var res IndexRunStatus
 	err := res.UnmarshalGQL(v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNIndexRunStatus2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus(ctx context.Context, sel ast.SelectionSet, v IndexRunStatus) graphql.Marshaler {
+func (ec *executionContext) marshalNIndexRunStatus2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐIndexRunStatus(ctx context.Context, sel ast.SelectionSet, v IndexRunStatus) graphql.Marshaler {
 	return v
 }
 
@@ -8719,11 +9128,11 @@ func (ec *executionContext) marshalNInt2int64(ctx context.Context, sel ast.Selec
 	return res
 }
 
-func (ec *executionContext) marshalNLineageGraph2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph(ctx context.Context, sel ast.SelectionSet, v LineageGraph) graphql.Marshaler {
+func (ec *executionContext) marshalNLineageGraph2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph(ctx context.Context, sel ast.SelectionSet, v LineageGraph) graphql.Marshaler {
 	return ec._LineageGraph(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNLineageGraph2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph(ctx context.Context, sel ast.SelectionSet, v *LineageGraph) graphql.Marshaler {
+func (ec *executionContext) marshalNLineageGraph2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageGraph(ctx context.Context, sel ast.SelectionSet, v *LineageGraph) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8733,11 +9142,21 @@ func (ec *executionContext) marshalNLineageGraph2ᚖgithubᚗcomᚋlatticeᚑlab
 	return ec._LineageGraph(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNProject2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v Project) graphql.Marshaler {
+func (ec *executionContext) marshalNPageInfo2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *PageInfo) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._PageInfo(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNProject2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v Project) graphql.Marshaler {
 	return ec._Project(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNProject2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectᚄ(ctx context.Context, sel ast.SelectionSet, v []*Project) graphql.Marshaler {
+func (ec *executionContext) marshalNProject2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectᚄ(ctx context.Context, sel ast.SelectionSet, v []*Project) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8761,7 +9180,7 @@ func (ec *executionContext) marshalNProject2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx, sel, v[i])
+			ret[i] = ec.marshalNProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8781,7 +9200,7 @@ func (ec *executionContext) marshalNProject2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 	return ret
 }
 
-func (ec *executionContext) marshalNProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v *Project) graphql.Marshaler {
+func (ec *executionContext) marshalNProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v *Project) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8791,11 +9210,11 @@ func (ec *executionContext) marshalNProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋl
 	return ec._Project(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNProjectConnection2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection(ctx context.Context, sel ast.SelectionSet, v ProjectConnection) graphql.Marshaler {
+func (ec *executionContext) marshalNProjectConnection2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection(ctx context.Context, sel ast.SelectionSet, v ProjectConnection) graphql.Marshaler {
 	return ec._ProjectConnection(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNProjectConnection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection(ctx context.Context, sel ast.SelectionSet, v *ProjectConnection) graphql.Marshaler {
+func (ec *executionContext) marshalNProjectConnection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProjectConnection(ctx context.Context, sel ast.SelectionSet, v *ProjectConnection) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8805,7 +9224,7 @@ func (ec *executionContext) marshalNProjectConnection2ᚖgithubᚗcomᚋlattice
 	return ec._ProjectConnection(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResultᚄ(ctx context.Context, sel ast.SelectionSet, v []*SemanticSearchResult) graphql.Marshaler {
+func (ec *executionContext) marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResultᚄ(ctx context.Context, sel ast.SelectionSet, v []*SemanticSearchResult) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8829,7 +9248,7 @@ func (ec *executionContext) marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋla
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSemanticSearchResult2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResult(ctx, sel, v[i])
+			ret[i] = ec.marshalNSemanticSearchResult2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResult(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8849,7 +9268,7 @@ func (ec *executionContext) marshalNSemanticSearchResult2ᚕᚖgithubᚗcomᚋla
 	return ret
 }
 
-func (ec *executionContext) marshalNSemanticSearchResult2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResult(ctx context.Context, sel ast.SelectionSet, v *SemanticSearchResult) graphql.Marshaler {
+func (ec *executionContext) marshalNSemanticSearchResult2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSemanticSearchResult(ctx context.Context, sel ast.SelectionSet, v *SemanticSearchResult) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8859,21 +9278,21 @@ func (ec *executionContext) marshalNSemanticSearchResult2ᚖgithubᚗcomᚋlatti
 	return ec._SemanticSearchResult(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNSeverity2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity(ctx context.Context, v any) (Severity, error) {
+func (ec *executionContext) unmarshalNSeverity2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity(ctx context.Context, v any) (Severity, error) {
 	var res Severity
 	err := res.UnmarshalGQL(v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNSeverity2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity(ctx context.Context, sel ast.SelectionSet, v Severity) graphql.Marshaler {
+func (ec *executionContext) marshalNSeverity2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSeverity(ctx context.Context, sel ast.SelectionSet, v Severity) graphql.Marshaler {
 	return v
 }
 
-func (ec *executionContext) marshalNSource2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx context.Context, sel ast.SelectionSet, v Source) graphql.Marshaler {
+func (ec *executionContext) marshalNSource2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx context.Context, sel ast.SelectionSet, v Source) graphql.Marshaler {
 	return ec._Source(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNSource2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceᚄ(ctx context.Context, sel ast.SelectionSet, v []*Source) graphql.Marshaler {
+func (ec *executionContext) marshalNSource2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceᚄ(ctx context.Context, sel ast.SelectionSet, v []*Source) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8897,7 +9316,7 @@ func (ec *executionContext) marshalNSource2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSource2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx, sel, v[i])
+			ret[i] = ec.marshalNSource2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -8917,7 +9336,7 @@ func (ec *executionContext) marshalNSource2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 	return ret
 }
 
-func (ec *executionContext) marshalNSource2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx context.Context, sel ast.SelectionSet, v *Source) graphql.Marshaler {
+func (ec *executionContext) marshalNSource2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSource(ctx context.Context, sel ast.SelectionSet, v *Source) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -8927,13 +9346,13 @@ func (ec *executionContext) marshalNSource2ᚖgithubᚗcomᚋlatticeᚑlabsᚋla
 	return ec._Source(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNSourceType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx context.Context, v any) (SourceType, error) {
+func (ec *executionContext) unmarshalNSourceType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx context.Context, v any) (SourceType, error) {
 	var res SourceType
 	err := res.UnmarshalGQL(v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNSourceType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx context.Context, sel ast.SelectionSet, v SourceType) graphql.Marshaler {
+func (ec *executionContext) marshalNSourceType2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSourceType(ctx context.Context, sel ast.SelectionSet, v SourceType) graphql.Marshaler {
 	return v
 }
 
@@ -8953,11 +9372,11 @@ func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.S
 	return res
 }
 
-func (ec *executionContext) marshalNSymbol2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v models.Symbol) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbol2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v models.Symbol) graphql.Marshaler {
 	return ec._Symbol(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNSymbol2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.Symbol) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbol2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.Symbol) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -8981,7 +9400,7 @@ func (ec *executionContext) marshalNSymbol2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx, sel, v[i])
+			ret[i] = ec.marshalNSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -9001,7 +9420,7 @@ func (ec *executionContext) marshalNSymbol2ᚕᚖgithubᚗcomᚋlatticeᚑlabs
 	return ret
 }
 
-func (ec *executionContext) marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v *models.Symbol) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v *models.Symbol) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -9011,7 +9430,7 @@ func (ec *executionContext) marshalNSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋla
 	return ec._Symbol(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.SymbolEdge) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdgeᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.SymbolEdge) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -9035,7 +9454,7 @@ func (ec *executionContext) marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑla
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSymbolEdge2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdge(ctx, sel, v[i])
+			ret[i] = ec.marshalNSymbolEdge2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdge(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -9055,7 +9474,7 @@ func (ec *executionContext) marshalNSymbolEdge2ᚕᚖgithubᚗcomᚋlatticeᚑla
 	return ret
 }
 
-func (ec *executionContext) marshalNSymbolEdge2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolEdge(ctx context.Context, sel ast.SelectionSet, v *models.SymbolEdge) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbolEdge2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolEdge(ctx context.Context, sel ast.SelectionSet, v *models.SymbolEdge) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
@@ -9065,13 +9484,27 @@ func (ec *executionContext) marshalNSymbolEdge2ᚖgithubᚗcomᚋlatticeᚑlabs
 	return ec._SymbolEdge(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx context.Context, v any) (models.SymbolKind, error) {
+func (ec *executionContext) marshalNSymbolEdgeConnection2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSymbolEdgeConnection(ctx context.Context, sel ast.SelectionSet, v SymbolEdgeConnection) graphql.Marshaler {
+	return ec._SymbolEdgeConnection(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSymbolEdgeConnection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐSymbolEdgeConnection(ctx context.Context, sel ast.SelectionSet, v *SymbolEdgeConnection) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			graphql.AddErrorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._SymbolEdgeConnection(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNSymbolKind2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx context.Context, v any) (models.SymbolKind, error) {
 	tmp, err := graphql.UnmarshalString(v)
 	res := models.SymbolKind(tmp)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx context.Context, sel ast.SelectionSet, v models.SymbolKind) graphql.Marshaler {
+func (ec *executionContext) marshalNSymbolKind2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx context.Context, sel ast.SelectionSet, v models.SymbolKind) graphql.Marshaler {
 	_ = sel
 	res := graphql.MarshalString(string(v))
 	if res == graphql.Null {
@@ -9082,7 +9515,7 @@ func (ec *executionContext) marshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋl
 	return res
 }
 
-func (ec *executionContext) unmarshalNUpdateProjectInput2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐUpdateProjectInput(ctx context.Context, v any) (UpdateProjectInput, error) {
+func (ec *executionContext) unmarshalNUpdateProjectInput2githubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐUpdateProjectInput(ctx context.Context, v any) (UpdateProjectInput, error) {
 	res, err := ec.unmarshalInputUpdateProjectInput(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
@@ -9370,7 +9803,7 @@ func (ec *executionContext) marshalOBoolean2ᚖbool(ctx context.Context, sel ast
 	return res
 }
 
-func (ec *executionContext) unmarshalOChangeType2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, v any) (*ChangeType, error) {
+func (ec *executionContext) unmarshalOChangeType2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, v any) (*ChangeType, error) {
 	if v == nil {
 		return nil, nil
 	}
@@ -9379,7 +9812,7 @@ func (ec *executionContext) unmarshalOChangeType2ᚖgithubᚗcomᚋlatticeᚑlab
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalOChangeType2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, sel ast.SelectionSet, v *ChangeType) graphql.Marshaler {
+func (ec *executionContext) marshalOChangeType2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐChangeType(ctx context.Context, sel ast.SelectionSet, v *ChangeType) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
@@ -9404,7 +9837,23 @@ func (ec *executionContext) marshalODateTime2ᚖtimeᚐTime(ctx context.Context,
 	return res
 }
 
-func (ec *executionContext) unmarshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ(ctx context.Context, v any) ([]models.EdgeType, error) {
+func (ec *executionContext) unmarshalOEdgeDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐEdgeDirection(ctx context.Context, v any) (*EdgeDirection, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(EdgeDirection)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOEdgeDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐEdgeDirection(ctx context.Context, sel ast.SelectionSet, v *EdgeDirection) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOEdgeType2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ(ctx context.Context, v any) ([]models.EdgeType, error) {
 	if v == nil {
 		return nil, nil
 	}
@@ -9414,7 +9863,7 @@ func (ec *executionContext) unmarshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabs
 	res := make([]models.EdgeType, len(vSlice))
 	for i := range vSlice {
 		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
-		res[i], err = ec.unmarshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx, vSlice[i])
+		res[i], err = ec.unmarshalNEdgeType2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx, vSlice[i])
 		if err != nil {
 			return nil, err
 		}
@@ -9422,7 +9871,7 @@ func (ec *executionContext) unmarshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabs
 	return res, nil
 }
 
-func (ec *executionContext) marshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []models.EdgeType) graphql.Marshaler {
+func (ec *executionContext) marshalOEdgeType2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []models.EdgeType) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
@@ -9449,7 +9898,7 @@ func (ec *executionContext) marshalOEdgeType2ᚕgithubᚗcomᚋlatticeᚑlabsᚋ
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNEdgeType2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx, sel, v[i])
+			ret[i] = ec.marshalNEdgeType2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐEdgeType(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -9505,7 +9954,7 @@ func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.Sele
 	return res
 }
 
-func (ec *executionContext) unmarshalOLineageDirection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection(ctx context.Context, v any) (*LineageDirection, error) {
+func (ec *executionContext) unmarshalOLineageDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection(ctx context.Context, v any) (*LineageDirection, error) {
 	if v == nil {
 		return nil, nil
 	}
@@ -9514,14 +9963,14 @@ func (ec *executionContext) unmarshalOLineageDirection2ᚖgithubᚗcomᚋlattice
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalOLineageDirection2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection(ctx context.Context, sel ast.SelectionSet, v *LineageDirection) graphql.Marshaler {
+func (ec *executionContext) marshalOLineageDirection2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐLineageDirection(ctx context.Context, sel ast.SelectionSet, v *LineageDirection) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
 	return v
 }
 
-func (ec *executionContext) marshalOProject2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v *Project) graphql.Marshaler {
+func (ec *executionContext) marshalOProject2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋinternalᚋapiᚋgraphqlᚐProject(ctx context.Context, sel ast.SelectionSet, v *Project) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
@@ -9582,14 +10031,14 @@ func (ec *executionContext) marshalOString2ᚖstring(ctx context.Context, sel as
 	return res
 }
 
-func (ec *executionContext) marshalOSymbol2ᚖgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v *models.Symbol) graphql.Marshaler {
+func (ec *executionContext) marshalOSymbol2ᚖgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbol(ctx context.Context, sel ast.SelectionSet, v *models.Symbol) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
 	return ec._Symbol(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ(ctx context.Context, v any) ([]models.SymbolKind, error) {
+func (ec *executionContext) unmarshalOSymbolKind2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ(ctx context.Context, v any) ([]models.SymbolKind, error) {
 	if v == nil {
 		return nil, nil
 	}
@@ -9599,7 +10048,7 @@ func (ec *executionContext) unmarshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlab
 	res := make([]models.SymbolKind, len(vSlice))
 	for i := range vSlice {
 		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
-		res[i], err = ec.unmarshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx, vSlice[i])
+		res[i], err = ec.unmarshalNSymbolKind2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx, vSlice[i])
 		if err != nil {
 			return nil, err
 		}
@@ -9607,7 +10056,7 @@ func (ec *executionContext) unmarshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlab
 	return res, nil
 }
 
-func (ec *executionContext) marshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ(ctx context.Context, sel ast.SelectionSet, v []models.SymbolKind) graphql.Marshaler {
+func (ec *executionContext) marshalOSymbolKind2ᚕgithubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKindᚄ(ctx context.Context, sel ast.SelectionSet, v []models.SymbolKind) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
@@ -9634,7 +10083,7 @@ func (ec *executionContext) marshalOSymbolKind2ᚕgithubᚗcomᚋlatticeᚑlabs
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNSymbolKind2githubᚗcomᚋlatticeᚑlabsᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx, sel, v[i])
+			ret[i] = ec.marshalNSymbolKind2githubᚗcomᚋmaraichrᚋlatticeᚋpkgᚋmodelsᚐSymbolKind(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
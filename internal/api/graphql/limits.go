@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// Limits bounds the work a single GraphQL request can ask for.
+type Limits struct {
+	MaxPageSize int // largest `first` accepted by paginated fields
+	MaxDepth    int // deepest field nesting in an operation
+	MaxFields   int // fields selected by an operation, fragments expanded
+}
+
+// DefaultLimits returns the limits used when none are configured.
+func DefaultLimits() Limits {
+	return Limits{MaxPageSize: 500, MaxDepth: 10, MaxFields: 500}
+}
+
+func (l Limits) withDefaults() Limits {
+	d := DefaultLimits()
+	if l.MaxPageSize <= 0 {
+		l.MaxPageSize = d.MaxPageSize
+	}
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = d.MaxDepth
+	}
+	if l.MaxFields <= 0 {
+		l.MaxFields = d.MaxFields
+	}
+	return l
+}
+
+// QueryBudget is a handler extension that rejects operations nested deeper than
+// MaxDepth or selecting more than MaxFields fields before any resolver runs.
+// Introspection fields are not counted so the playground keeps working.
+type QueryBudget struct {
+	MaxDepth  int
+	MaxFields int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = QueryBudget{}
+
+// NewQueryBudget creates the extension from the configured limits.
+func NewQueryBudget(l Limits) QueryBudget {
+	l = l.withDefaults()
+	return QueryBudget{MaxDepth: l.MaxDepth, MaxFields: l.MaxFields}
+}
+
+func (QueryBudget) ExtensionName() string { return "QueryBudget" }
+
+func (QueryBudget) Validate(graphql.ExecutableSchema) error { return nil }
+
+// MutateOperationContext measures the operation and fails the request when it is over budget.
+func (b QueryBudget) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	if oc.Operation == nil {
+		return nil
+	}
+	depth, fields := measureSelection(oc.Operation.SelectionSet, 1)
+
+	var msg string
+	switch {
+	case b.MaxDepth > 0 && depth > b.MaxDepth:
+		msg = fmt.Sprintf("query depth %d exceeds the limit of %d", depth, b.MaxDepth)
+	case b.MaxFields > 0 && fields > b.MaxFields:
+		msg = fmt.Sprintf("query selects %d fields, exceeding the limit of %d", fields, b.MaxFields)
+	default:
+		return nil
+	}
+	err := apierr.QueryTooComplex(msg)
+	return &gqlerror.Error{
+		Message:    err.Message(),
+		Extensions: map[string]interface{}{"code": string(err.Code())},
+	}
+}
+
+// measureSelection returns the deepest field level and the number of fields in set,
+// where level is the depth of the fields directly in set.
+func measureSelection(set ast.SelectionSet, level int) (depth, fields int) {
+	for _, sel := range set {
+		var d, n int
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d, n = measureSelection(s.SelectionSet, level+1)
+			d = max(d, level)
+			n++
+		case *ast.InlineFragment:
+			d, n = measureSelection(s.SelectionSet, level)
+		case *ast.FragmentSpread:
+			if s.Definition == nil {
+				continue
+			}
+			d, n = measureSelection(s.Definition.SelectionSet, level)
+		}
+		depth = max(depth, d)
+		fields += n
+	}
+	return depth, fields
+}
@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// budgetServer is a GraphQL handler with no backing store; rejected operations
+// never reach a resolver.
+func budgetServer(l Limits) *handler.Server {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(NewQueryBudget(l))
+	return srv
+}
+
+type gqlResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func runQuery(t *testing.T, srv http.Handler, query string) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	var resp gqlResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestQueryBudget_RejectsDeepQuery(t *testing.T) {
+	srv := budgetServer(Limits{MaxDepth: 4, MaxFields: 100})
+
+	// symbol > edges > source > edges > target > name is six levels deep, half of it
+	// hidden in a fragment.
+	resp := runQuery(t, srv, `
+		query { symbol(id: "00000000-0000-0000-0000-000000000001") { ...in } }
+		fragment in on Symbol { incomingEdges { source { outgoingEdges { target { name } } } } }`)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "QUERY_TOO_COMPLEX" {
+		t.Fatalf("expected QUERY_TOO_COMPLEX, got %+v", resp.Errors)
+	}
+	if !strings.Contains(resp.Errors[0].Message, "depth 6 exceeds the limit of 4") {
+		t.Errorf("unexpected message %q", resp.Errors[0].Message)
+	}
+}
+
+func TestQueryBudget_RejectsWideQuery(t *testing.T) {
+	srv := budgetServer(Limits{MaxDepth: 10, MaxFields: 20})
+
+	var b strings.Builder
+	b.WriteString("query {")
+	for i := range 25 {
+		fmt.Fprintf(&b, " s%d: symbol(id: \"00000000-0000-0000-0000-000000000001\") { id }", i)
+	}
+	b.WriteString(" }")
+	resp := runQuery(t, srv, b.String())
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "QUERY_TOO_COMPLEX" {
+		t.Fatalf("expected QUERY_TOO_COMPLEX, got %+v", resp.Errors)
+	}
+}
+
+func TestQueryBudget_AllowsIntrospection(t *testing.T) {
+	srv := budgetServer(Limits{MaxDepth: 2, MaxFields: 5})
+	resp := runQuery(t, srv, `{ __schema { types { name fields { name type { name ofType { name } } } } } }`)
+	if len(resp.Errors) != 0 {
+		t.Fatalf("introspection should not count against the budget, got %+v", resp.Errors)
+	}
+}
@@ -52,6 +52,7 @@ type ImpactAnalysisResult struct {
 	DirectImpact     []*ImpactNode `json:"directImpact"`
 	TransitiveImpact []*ImpactNode `json:"transitiveImpact"`
 	TotalAffected    int           `json:"totalAffected"`
+	Severity         Severity      `json:"severity"`
 }
 
 type ImpactNode struct {
@@ -91,6 +92,11 @@ type LineageGraph struct {
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
 type Project struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name"`
@@ -125,6 +131,11 @@ type Source struct {
 	CreatedAt    time.Time  `json:"createdAt"`
 }
 
+type SymbolEdgeConnection struct {
+	Nodes    []*models.SymbolEdge `json:"nodes"`
+	PageInfo *PageInfo            `json:"pageInfo"`
+}
+
 type UpdateProjectInput struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
@@ -250,6 +261,61 @@ func (e DerivationType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type EdgeDirection string
+
+const (
+	EdgeDirectionIncoming EdgeDirection = "INCOMING"
+	EdgeDirectionOutgoing EdgeDirection = "OUTGOING"
+)
+
+var AllEdgeDirection = []EdgeDirection{
+	EdgeDirectionIncoming,
+	EdgeDirectionOutgoing,
+}
+
+func (e EdgeDirection) IsValid() bool {
+	switch e {
+	case EdgeDirectionIncoming, EdgeDirectionOutgoing:
+		return true
+	}
+	return false
+}
+
+func (e EdgeDirection) String() string {
+	return string(e)
+}
+
+func (e *EdgeDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EdgeDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EdgeDirection", str)
+	}
+	return nil
+}
+
+func (e EdgeDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *EdgeDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e EdgeDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type IndexRunStatus string
 
 const (
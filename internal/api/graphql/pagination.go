@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// edgePager is the subset of the store needed to page through a symbol's edges.
+type edgePager interface {
+	ListIncomingEdgesPage(ctx context.Context, arg postgres.ListIncomingEdgesPageParams) ([]postgres.SymbolEdge, error)
+	ListOutgoingEdgesPage(ctx context.Context, arg postgres.ListOutgoingEdgesPageParams) ([]postgres.SymbolEdge, error)
+}
+
+// encodeCursor makes an opaque cursor from the last edge ID of a page.
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.FromBytes(raw)
+}
+
+// pageEdges returns up to first edges of a symbol in the given direction, after the
+// edge named by the cursor. first must be between 1 and maxPage.
+func pageEdges(ctx context.Context, db edgePager, symbolID uuid.UUID, direction EdgeDirection, types []models.EdgeType, first int, after *string, maxPage int) (*SymbolEdgeConnection, error) {
+	if first < 1 || first > maxPage {
+		return nil, apierr.InvalidPageSize(maxPage)
+	}
+	afterID := uuid.Nil
+	if after != nil && *after != "" {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return nil, apierr.InvalidCursor()
+		}
+		afterID = id
+	}
+	edgeTypes := make([]string, len(types))
+	for i, t := range types {
+		edgeTypes[i] = strings.ToLower(string(t))
+	}
+
+	// Fetch one extra edge to learn whether another page follows.
+	lim := int32(first + 1)
+	var (
+		edges []postgres.SymbolEdge
+		err   error
+	)
+	if direction == EdgeDirectionIncoming {
+		edges, err = db.ListIncomingEdgesPage(ctx, postgres.ListIncomingEdgesPageParams{
+			TargetID: symbolID, AfterID: afterID, EdgeTypes: edgeTypes, Lim: lim,
+		})
+	} else {
+		edges, err = db.ListOutgoingEdgesPage(ctx, postgres.ListOutgoingEdgesPageParams{
+			SourceID: symbolID, AfterID: afterID, EdgeTypes: edgeTypes, Lim: lim,
+		})
+	}
+	if err != nil {
+		return nil, apierr.InternalError(err)
+	}
+
+	conn := &SymbolEdgeConnection{Nodes: []*models.SymbolEdge{}, PageInfo: &PageInfo{}}
+	if len(edges) > first {
+		edges = edges[:first]
+		conn.PageInfo.HasNextPage = true
+	}
+	for _, e := range edges {
+		conn.Nodes = append(conn.Nodes, dbEdgeToGQL(e))
+	}
+	if len(edges) > 0 {
+		cursor := encodeCursor(edges[len(edges)-1].ID)
+		conn.PageInfo.EndCursor = &cursor
+	}
+	return conn, nil
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// fakeEdgePages serves keyset pages over an in-memory edge list.
+type fakeEdgePages struct {
+	edges []postgres.SymbolEdge
+}
+
+func (f *fakeEdgePages) page(match func(postgres.SymbolEdge) bool, after uuid.UUID, types []string, lim int32) []postgres.SymbolEdge {
+	var out []postgres.SymbolEdge
+	for _, e := range f.edges {
+		if !match(e) || bytes.Compare(e.ID[:], after[:]) <= 0 {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, e.EdgeType) {
+			continue
+		}
+		out = append(out, e)
+	}
+	slices.SortFunc(out, func(a, b postgres.SymbolEdge) int { return bytes.Compare(a.ID[:], b.ID[:]) })
+	if len(out) > int(lim) {
+		out = out[:lim]
+	}
+	return out
+}
+
+func (f *fakeEdgePages) ListIncomingEdgesPage(_ context.Context, arg postgres.ListIncomingEdgesPageParams) ([]postgres.SymbolEdge, error) {
+	return f.page(func(e postgres.SymbolEdge) bool { return e.TargetID == arg.TargetID }, arg.AfterID, arg.EdgeTypes, arg.Lim), nil
+}
+
+func (f *fakeEdgePages) ListOutgoingEdgesPage(_ context.Context, arg postgres.ListOutgoingEdgesPageParams) ([]postgres.SymbolEdge, error) {
+	return f.page(func(e postgres.SymbolEdge) bool { return e.SourceID == arg.SourceID }, arg.AfterID, arg.EdgeTypes, arg.Lim), nil
+}
+
+func TestPageEdges_WalksHubSymbol(t *testing.T) {
+	hub := uuid.New()
+	db := &fakeEdgePages{}
+	const callers = 1050
+	for range callers {
+		db.edges = append(db.edges, postgres.SymbolEdge{ID: uuid.New(), SourceID: uuid.New(), TargetID: hub, EdgeType: "calls"})
+	}
+	for range 20 {
+		db.edges = append(db.edges, postgres.SymbolEdge{ID: uuid.New(), SourceID: uuid.New(), TargetID: hub, EdgeType: "reads_from"})
+	}
+	ctx := context.Background()
+
+	seen := make(map[uuid.UUID]bool)
+	var after *string
+	pages := 0
+	for {
+		conn, err := pageEdges(ctx, db, hub, EdgeDirectionIncoming, []models.EdgeType{"CALLS"}, 100, after, 500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		if len(conn.Nodes) > 100 {
+			t.Fatalf("page %d has %d edges, more than first", pages, len(conn.Nodes))
+		}
+		for _, e := range conn.Nodes {
+			if seen[e.ID] {
+				t.Fatalf("edge %s returned twice", e.ID)
+			}
+			seen[e.ID] = true
+		}
+		if !conn.PageInfo.HasNextPage {
+			break
+		}
+		after = conn.PageInfo.EndCursor
+	}
+	if len(seen) != callers || pages != 11 {
+		t.Errorf("expected %d calls edges over 11 pages, got %d over %d", callers, len(seen), pages)
+	}
+}
+
+func TestPageEdges_EnforcesPageSizeAndCursor(t *testing.T) {
+	db := &fakeEdgePages{}
+	ctx := context.Background()
+	var apiErr *apierr.Error
+
+	for _, first := range []int{0, 501} {
+		_, err := pageEdges(ctx, db, uuid.New(), EdgeDirectionOutgoing, nil, first, nil, 500)
+		if !errors.As(err, &apiErr) || apiErr.Code() != apierr.CodeInvalidPageSize {
+			t.Errorf("first=%d: expected INVALID_PAGE_SIZE, got %v", first, err)
+		}
+	}
+
+	bad := "not-a-cursor"
+	_, err := pageEdges(ctx, db, uuid.New(), EdgeDirectionOutgoing, nil, 10, &bad, 500)
+	if !errors.As(err, &apiErr) || apiErr.Code() != apierr.CodeInvalidCursor {
+		t.Errorf("expected INVALID_CURSOR, got %v", err)
+	}
+
+	conn, err := pageEdges(ctx, db, uuid.New(), EdgeDirectionOutgoing, nil, 10, nil, 500)
+	if err != nil || len(conn.Nodes) != 0 || conn.PageInfo.HasNextPage || conn.PageInfo.EndCursor != nil {
+		t.Errorf("expected an empty final page, got %+v (%v)", conn, err)
+	}
+}
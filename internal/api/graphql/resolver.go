@@ -18,9 +18,17 @@ type Resolver struct {
 	Embed   embedding.Embedder
 	Lineage *lineage.Engine
 	Impact  *impact.Engine
+	Limits  Limits
+
+	// Directions is which way lineage walks each edge type.
+	Directions lineage.EdgeDirections
 }
 
-// NewResolver creates a new root resolver.
-func NewResolver(logger *slog.Logger, s *store.Store, g *graph.Client, embed embedding.Embedder, lin *lineage.Engine, imp *impact.Engine) *Resolver {
-	return &Resolver{Logger: logger, Store: s, Graph: g, Embed: embed, Lineage: lin, Impact: imp}
+// NewResolver creates a new root resolver. Lineage walks edges the way dirs points them,
+// or the default way when dirs is nil.
+func NewResolver(logger *slog.Logger, s *store.Store, g *graph.Client, embed embedding.Embedder, lin *lineage.Engine, imp *impact.Engine, dirs lineage.EdgeDirections, limits Limits) *Resolver {
+	if dirs == nil {
+		dirs = lineage.DefaultEdgeDirections()
+	}
+	return &Resolver{Logger: logger, Store: s, Graph: g, Embed: embed, Lineage: lin, Impact: imp, Limits: limits.withDefaults(), Directions: dirs}
 }
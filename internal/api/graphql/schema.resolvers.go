@@ -7,6 +7,7 @@ package graphql
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 	pgvector "github.com/pgvector/pgvector-go"
 
 	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/graph"
 	"github.com/maraichr/lattice/internal/store/postgres"
 	"github.com/maraichr/lattice/pkg/apierr"
 	"github.com/maraichr/lattice/pkg/models"
@@ -48,6 +50,9 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, slug string, input
 		}
 		return nil, apierr.InternalError(err)
 	}
+	if current.ArchivedAt.Valid {
+		return nil, apierr.ProjectArchived()
+	}
 
 	name := current.Name
 	if input.Name != nil {
@@ -72,9 +77,18 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, slug string, input
 
 // DeleteProject is the resolver for the deleteProject field.
 func (r *mutationResolver) DeleteProject(ctx context.Context, slug string) (bool, error) {
-	if err := r.Store.DeleteProject(ctx, slug); err != nil {
+	project, err := r.Store.SoftDeleteProjectCascade(ctx, slug)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return false, apierr.ProjectNotFound()
+		}
 		return false, apierr.ProjectDeleteFailed(err)
 	}
+	if r.Graph != nil {
+		if err := r.Graph.ClearProject(ctx, project.ID); err != nil {
+			r.Logger.Warn("clear deleted project from graph", slog.String("project", slug), slog.String("error", err.Error()))
+		}
+	}
 	return true, nil
 }
 
@@ -87,6 +101,9 @@ func (r *mutationResolver) CreateSource(ctx context.Context, projectSlug string,
 		}
 		return nil, apierr.InternalError(err)
 	}
+	if project.ArchivedAt.Valid {
+		return nil, apierr.ProjectArchived()
+	}
 
 	configBytes := []byte("{}")
 	if input.Config != nil {
@@ -112,6 +129,20 @@ func (r *mutationResolver) DeleteSource(ctx context.Context, id string) (bool, e
 	if err != nil {
 		return false, apierr.InvalidID("source")
 	}
+	source, err := r.Store.GetSource(ctx, uid)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return false, apierr.SourceNotFound()
+		}
+		return false, apierr.InternalError(err)
+	}
+	project, err := r.Store.GetProjectByID(ctx, source.ProjectID)
+	if err != nil {
+		return false, apierr.InternalError(err)
+	}
+	if project.ArchivedAt.Valid {
+		return false, apierr.ProjectArchived()
+	}
 	if err := r.Store.DeleteSource(ctx, uid); err != nil {
 		return false, apierr.SourceDeleteFailed(err)
 	}
@@ -127,6 +158,9 @@ func (r *mutationResolver) TriggerIndexRun(ctx context.Context, projectSlug stri
 		}
 		return nil, apierr.InternalError(err)
 	}
+	if project.ArchivedAt.Valid {
+		return nil, apierr.ProjectArchived()
+	}
 
 	var sid pgtype.UUID
 	if sourceID != nil {
@@ -317,10 +351,6 @@ func (r *queryResolver) SearchSymbols(ctx context.Context, projectSlug string, q
 
 // LineageGraph is the resolver for the lineageGraph field.
 func (r *queryResolver) LineageGraph(ctx context.Context, symbolID string, depth *int, direction *LineageDirection) (*LineageGraph, error) {
-	if r.Graph == nil {
-		return nil, apierr.NotImplemented("Lineage graph (Neo4j not configured)")
-	}
-
 	uid, err := uuid.Parse(symbolID)
 	if err != nil {
 		return nil, apierr.InvalidID("symbol")
@@ -336,7 +366,7 @@ func (r *queryResolver) LineageGraph(ctx context.Context, symbolID string, depth
 		dir = strings.ToLower(direction.String())
 	}
 
-	result, err := r.Graph.Lineage(ctx, uid, dir, d)
+	result, err := graph.LineageFor(ctx, r.Graph, r.Store, uid, dir, d, r.Directions.UpstreamTypes())
 	if err != nil {
 		return nil, apierr.LineageQueryFailed(err)
 	}
@@ -563,9 +593,23 @@ func (r *queryResolver) ImpactAnalysis(ctx context.Context, symbolID string, cha
 		DirectImpact:     direct,
 		TransitiveImpact: transitive,
 		TotalAffected:    result.TotalAffected,
+		Severity:         Severity(strings.ToUpper(result.Severity)),
 	}, nil
 }
 
+// Edges is the resolver for the edges field.
+func (r *queryResolver) Edges(ctx context.Context, symbolID string, direction *EdgeDirection, types []models.EdgeType, first int, after *string) (*SymbolEdgeConnection, error) {
+	uid, err := uuid.Parse(symbolID)
+	if err != nil {
+		return nil, apierr.InvalidID("symbol")
+	}
+	dir := EdgeDirectionOutgoing
+	if direction != nil {
+		dir = *direction
+	}
+	return pageEdges(ctx, r.Store, uid, dir, types, first, after, r.Limits.MaxPageSize)
+}
+
 // ID is the resolver for the id field.
 func (r *symbolResolver) ID(ctx context.Context, obj *models.Symbol) (string, error) {
 	return obj.ID.String(), nil
@@ -582,20 +626,20 @@ func (r *symbolResolver) File(ctx context.Context, obj *models.Symbol) (*models.
 
 // IncomingEdges is the resolver for the incomingEdges field.
 func (r *symbolResolver) IncomingEdges(ctx context.Context, obj *models.Symbol, types []models.EdgeType) ([]*models.SymbolEdge, error) {
-	edges, err := r.Store.GetIncomingEdges(ctx, obj.ID)
+	conn, err := pageEdges(ctx, r.Store, obj.ID, EdgeDirectionIncoming, types, r.Limits.MaxPageSize, nil, r.Limits.MaxPageSize)
 	if err != nil {
-		return nil, apierr.InternalError(err)
+		return nil, err
 	}
-	return filterEdges(edges, types), nil
+	return conn.Nodes, nil
 }
 
 // OutgoingEdges is the resolver for the outgoingEdges field.
 func (r *symbolResolver) OutgoingEdges(ctx context.Context, obj *models.Symbol, types []models.EdgeType) ([]*models.SymbolEdge, error) {
-	edges, err := r.Store.GetOutgoingEdges(ctx, obj.ID)
+	conn, err := pageEdges(ctx, r.Store, obj.ID, EdgeDirectionOutgoing, types, r.Limits.MaxPageSize, nil, r.Limits.MaxPageSize)
 	if err != nil {
-		return nil, apierr.InternalError(err)
+		return nil, err
 	}
-	return filterEdges(edges, types), nil
+	return conn.Nodes, nil
 }
 
 // ID is the resolver for the id field.
@@ -0,0 +1,109 @@
+//go:build integration
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+func TestArchivedProject_RefusesIngest(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Test Archived Project",
+		Slug: fmt.Sprintf("test-archive-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "external", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			admin := &auth.Principal{Sub: "test", Roles: map[string]bool{"lattice_admin": true}}
+			next.ServeHTTP(w, req.WithContext(auth.WithPrincipal(req.Context(), admin)))
+		})
+	})
+	projects := NewProjectHandler(logger, s, nil, nil, 0)
+	bulk := NewBulkHandler(logger, s)
+	indexRuns := NewIndexRunHandler(logger, s, nil)
+	r.Post("/projects/{slug}/archive", projects.Archive)
+	r.Post("/projects/{slug}/unarchive", projects.Unarchive)
+	r.Post("/projects/{slug}/sources/{sourceID}/bulk", bulk.Ingest)
+	r.Post("/projects/{slug}/index-runs", indexRuns.Trigger)
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+		return w
+	}
+	code := func(w *httptest.ResponseRecorder) apierr.Code {
+		var resp apierr.ErrorResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return resp.Error.Code
+	}
+	bulkPath := fmt.Sprintf("/projects/%s/sources/%s/bulk", proj.Slug, source.ID)
+	payload := map[string]any{
+		"symbols": []map[string]any{
+			{"path": "deploy/api.yaml", "language": "yaml", "name": "OrdersApi", "qualified_name": "svc.OrdersApi", "kind": "service", "start_line": 1},
+		},
+	}
+
+	if w := post("/projects/"+proj.Slug+"/archive", map[string]bool{"evict_graph": true}); w.Code != http.StatusOK {
+		t.Fatalf("archive: got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/projects/"+proj.Slug+"/archive", nil); w.Code != http.StatusConflict {
+		t.Errorf("archiving twice: expected 409, got %d", w.Code)
+	}
+
+	if w := post(bulkPath, payload); w.Code != http.StatusConflict || code(w) != apierr.CodeProjectArchived {
+		t.Errorf("bulk ingest into archived project: expected 409 %s, got %d: %s", apierr.CodeProjectArchived, w.Code, w.Body.String())
+	}
+	if w := post("/projects/"+proj.Slug+"/index-runs", nil); w.Code != http.StatusConflict || code(w) != apierr.CodeProjectArchived {
+		t.Errorf("index run of archived project: expected 409 %s, got %d: %s", apierr.CodeProjectArchived, w.Code, w.Body.String())
+	}
+	if runs, err := s.ListIndexRunsByProjectID(ctx, postgres.ListIndexRunsByProjectIDParams{ProjectID: proj.ID, Limit: 10}); err != nil || len(runs) != 0 {
+		t.Errorf("expected no index runs, got %d (err %v)", len(runs), err)
+	}
+
+	// Unarchiving restores ingestion.
+	if w := post("/projects/"+proj.Slug+"/unarchive", nil); w.Code != http.StatusOK {
+		t.Fatalf("unarchive: got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/projects/"+proj.Slug+"/unarchive", nil); w.Code != http.StatusConflict || code(w) != apierr.CodeProjectNotArchived {
+		t.Errorf("unarchiving a live project: expected 409 %s, got %d", apierr.CodeProjectNotArchived, w.Code)
+	}
+	if w := post(bulkPath, payload); w.Code != http.StatusOK {
+		t.Errorf("bulk ingest after unarchive: got %d: %s", w.Code, w.Body.String())
+	}
+}
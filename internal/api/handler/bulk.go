@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// maxBulkItems caps the symbols and edges accepted in one bulk request.
+const maxBulkItems = 10000
+
+// BulkHandler accepts symbols and edges produced by external parsers.
+type BulkHandler struct {
+	logger *slog.Logger
+	store  *store.Store
+}
+
+func NewBulkHandler(logger *slog.Logger, s *store.Store) *BulkHandler {
+	return &BulkHandler{logger: logger, store: s}
+}
+
+// bulkSymbol is one externally parsed symbol. Symbols are upserted by
+// (qualified_name, kind) like parser output.
+type bulkSymbol struct {
+	Path          string  `json:"path"`
+	Language      string  `json:"language"`
+	Name          string  `json:"name"`
+	QualifiedName string  `json:"qualified_name"`
+	Kind          string  `json:"kind"`
+	StartLine     int32   `json:"start_line"`
+	EndLine       int32   `json:"end_line"`
+	Signature     *string `json:"signature,omitempty"`
+	DocComment    *string `json:"doc_comment,omitempty"`
+}
+
+// bulkEdge links two symbols by qualified name. Endpoints may be symbols in the same
+// request or symbols already in the project.
+type bulkEdge struct {
+	Source   string          `json:"source"`
+	Target   string          `json:"target"`
+	Type     string          `json:"type"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+type bulkRequest struct {
+	Symbols []bulkSymbol `json:"symbols"`
+	Edges   []bulkEdge   `json:"edges"`
+}
+
+type bulkResult struct {
+	Files      int        `json:"files"`
+	Symbols    int        `json:"symbols"`
+	Edges      int        `json:"edges"`
+	Unresolved []bulkEdge `json:"unresolved_edges"`
+}
+
+// Ingest upserts externally parsed symbols and edges into a source. Every edge type
+// must be builtin or registered for the project; otherwise nothing is written.
+// Graph sync and analytics pick the data up on the project's next index run.
+// POST /projects/{slug}/sources/{sourceID}/bulk
+func (h *BulkHandler) Ingest(w http.ResponseWriter, r *http.Request) {
+	sourceID, err := uuid.Parse(chi.URLParam(r, "sourceID"))
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidSourceID())
+		return
+	}
+
+	var req bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+	if msg := validateBulkRequest(req); msg != "" {
+		writeAPIError(w, h.logger, apierr.InvalidBulkPayload(msg))
+		return
+	}
+
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
+	source, ok := getSourceOr404(w, r, h.logger, h.store, sourceID)
+	if !ok {
+		return
+	}
+	if source.ProjectID != project.ID {
+		writeAPIError(w, h.logger, apierr.SourceNotFound())
+		return
+	}
+
+	if unknown := unknownEdgeTypes(models.EdgeTypeRegistryFromSettings(project.Settings), req.Edges); len(unknown) > 0 {
+		writeAPIError(w, h.logger, apierr.UnknownReferenceTypes(unknown))
+		return
+	}
+
+	var result bulkResult
+	err = h.store.WithTx(r.Context(), func(q *postgres.Queries) error {
+		var txErr error
+		result, txErr = ingestBulk(r.Context(), q, project.ID, source.ID, req)
+		return txErr
+	})
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.BulkIngestFailed(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateBulkRequest returns a message describing the first invalid item, or "".
+func validateBulkRequest(req bulkRequest) string {
+	if len(req.Symbols) == 0 && len(req.Edges) == 0 {
+		return "symbols or edges are required"
+	}
+	if len(req.Symbols) > maxBulkItems || len(req.Edges) > maxBulkItems {
+		return fmt.Sprintf("at most %d symbols and %d edges per request", maxBulkItems, maxBulkItems)
+	}
+	for i, s := range req.Symbols {
+		if s.Path == "" || s.Name == "" || s.QualifiedName == "" || s.Kind == "" || s.Language == "" {
+			return fmt.Sprintf("symbols[%d]: path, language, name, qualified_name and kind are required", i)
+		}
+	}
+	for i, e := range req.Edges {
+		if e.Source == "" || e.Target == "" || e.Type == "" {
+			return fmt.Sprintf("edges[%d]: source, target and type are required", i)
+		}
+	}
+	return ""
+}
+
+// unknownEdgeTypes returns the edge types in edges that the registry does not accept.
+func unknownEdgeTypes(reg *models.EdgeTypeRegistry, edges []bulkEdge) []string {
+	types := make([]string, len(edges))
+	for i, e := range edges {
+		types[i] = e.Type
+	}
+	return reg.Unknown(types)
+}
+
+// ingestBulk writes files, symbols and edges. Edges whose endpoints cannot be found
+// are returned as unresolved rather than failing the request.
+func ingestBulk(ctx context.Context, q *postgres.Queries, projectID, sourceID uuid.UUID, req bulkRequest) (bulkResult, error) {
+	var result bulkResult
+	fileIDs := make(map[string]uuid.UUID)
+	symbolIDs := make(map[string]uuid.UUID)
+
+	for _, s := range req.Symbols {
+		fileID, ok := fileIDs[s.Path]
+		if !ok {
+			f, err := q.UpsertFile(ctx, postgres.UpsertFileParams{
+				ProjectID: projectID,
+				SourceID:  sourceID,
+				Path:      s.Path,
+				Language:  s.Language,
+				Hash:      "bulk",
+			})
+			if err != nil {
+				return result, fmt.Errorf("upsert file %s: %w", s.Path, err)
+			}
+			fileID = f.ID
+			fileIDs[s.Path] = fileID
+		}
+
+		sym, err := q.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID:     projectID,
+			FileID:        fileID,
+			Name:          s.Name,
+			QualifiedName: s.QualifiedName,
+			Kind:          s.Kind,
+			Language:      s.Language,
+			StartLine:     s.StartLine,
+			EndLine:       max(s.EndLine, s.StartLine),
+			Signature:     s.Signature,
+			DocComment:    s.DocComment,
+		})
+		if err != nil {
+			return result, fmt.Errorf("upsert symbol %s: %w", s.QualifiedName, err)
+		}
+		symbolIDs[s.QualifiedName] = sym.ID
+	}
+	result.Files = len(fileIDs)
+	result.Symbols = len(req.Symbols)
+
+	resolve := func(qname string) (uuid.UUID, bool) {
+		if id, ok := symbolIDs[qname]; ok {
+			return id, true
+		}
+		sym, err := q.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: projectID, QualifiedName: qname})
+		if err != nil {
+			return uuid.Nil, false
+		}
+		symbolIDs[qname] = sym.ID
+		return sym.ID, true
+	}
+
+	result.Unresolved = []bulkEdge{}
+	for _, e := range req.Edges {
+		src, okSrc := resolve(e.Source)
+		tgt, okTgt := resolve(e.Target)
+		if !okSrc || !okTgt {
+			result.Unresolved = append(result.Unresolved, e)
+			continue
+		}
+		metadata := []byte(e.Metadata)
+		if len(metadata) == 0 {
+			metadata = []byte("{}")
+		}
+		if _, err := q.CreateSymbolEdgeWithMetadata(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+			ProjectID: projectID,
+			SourceID:  src,
+			TargetID:  tgt,
+			EdgeType:  e.Type,
+			Metadata:  metadata,
+		}); err != nil {
+			return result, fmt.Errorf("create edge %s -[%s]-> %s: %w", e.Source, e.Type, e.Target, err)
+		}
+		result.Edges++
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+//go:build integration
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp/tools"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func setupStore(t *testing.T) *store.Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("postgres not available: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("postgres ping failed: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return store.New(pool)
+}
+
+func TestBulkIngest_CustomReferenceTypeTraversable(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Test Bulk Project",
+		Slug: fmt.Sprintf("test-bulk-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "external", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			admin := &auth.Principal{Sub: "test", Roles: map[string]bool{"lattice_admin": true}}
+			next.ServeHTTP(w, req.WithContext(auth.WithPrincipal(req.Context(), admin)))
+		})
+	})
+	refTypes := NewReferenceTypeHandler(logger, s)
+	bulk := NewBulkHandler(logger, s)
+	r.Post("/projects/{slug}/reference-types", refTypes.Register)
+	r.Post("/projects/{slug}/sources/{sourceID}/bulk", bulk.Ingest)
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+		return w
+	}
+	bulkPath := fmt.Sprintf("/projects/%s/sources/%s/bulk", proj.Slug, source.ID)
+	payload := map[string]any{
+		"symbols": []map[string]any{
+			{"path": "deploy/api.yaml", "language": "yaml", "name": "OrdersApi", "qualified_name": "svc.OrdersApi", "kind": "service", "start_line": 1},
+			{"path": "deploy/cluster.yaml", "language": "yaml", "name": "prod-eu", "qualified_name": "k8s.prod-eu", "kind": "cluster", "start_line": 1},
+		},
+		"edges": []map[string]any{
+			{"source": "svc.OrdersApi", "target": "k8s.prod-eu", "type": "deploys_to"},
+		},
+	}
+
+	// Unregistered types are rejected and nothing is written.
+	if w := post(bulkPath, payload); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for unregistered type, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := post("/projects/"+proj.Slug+"/reference-types", map[string]string{
+		"name": "deploys_to", "label": "Deploys to", "category": "infrastructure",
+	}); w.Code != http.StatusCreated {
+		t.Fatalf("register type: got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := post(bulkPath, payload)
+	if w.Code != http.StatusOK {
+		t.Fatalf("bulk ingest: got %d: %s", w.Code, w.Body.String())
+	}
+	var res bulkResult
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Symbols != 2 || res.Edges != 1 || len(res.Unresolved) != 0 {
+		t.Fatalf("unexpected ingest result: %+v", res)
+	}
+
+	api, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: proj.ID, QualifiedName: "svc.OrdersApi"})
+	if err != nil {
+		t.Fatalf("lookup ingested symbol: %v", err)
+	}
+	out, err := tools.NewExtractSubgraphHandler(s, nil, nil, logger).Handle(ctx, tools.ExtractSubgraphParams{
+		Project:     proj.Slug,
+		SeedSymbols: []string{api.ID.String()},
+		MaxDepth:    1,
+	})
+	if err != nil {
+		t.Fatalf("extract_subgraph: %v", err)
+	}
+	if !strings.Contains(out, "prod-eu") {
+		t.Errorf("expected the deploys_to target in the subgraph, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Deploys to (deploys_to, infrastructure): 1 edges") {
+		t.Errorf("expected the custom type rendered with its label, got:\n%s", out)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+func TestUnknownEdgeTypes_RejectsUnregistered(t *testing.T) {
+	edges := []bulkEdge{
+		{Source: "a", Target: "b", Type: "calls"},
+		{Source: "a", Target: "e", Type: "reads_config"},
+		{Source: "a", Target: "c", Type: "deploys_to"},
+		{Source: "b", Target: "c", Type: "secured_by"},
+		{Source: "c", Target: "d", Type: "deploys_to"},
+	}
+
+	reg := models.EdgeTypeRegistryFromSettings(nil)
+	if got := unknownEdgeTypes(reg, edges); !reflect.DeepEqual(got, []string{"deploys_to", "secured_by"}) {
+		t.Errorf("expected deploys_to and secured_by to be unknown, got %v", got)
+	}
+
+	reg = models.EdgeTypeRegistryFromSettings([]byte(`{"reference_types":[{"name":"deploys_to","label":"Deploys to","category":"infrastructure"}]}`))
+	if got := unknownEdgeTypes(reg, edges); !reflect.DeepEqual(got, []string{"secured_by"}) {
+		t.Errorf("expected only secured_by to be unknown, got %v", got)
+	}
+}
+
+func TestEdgeTypeRegistry_Register(t *testing.T) {
+	reg := models.NewEdgeTypeRegistry()
+	if err := reg.Register(models.EdgeTypeInfo{Name: "calls"}); err == nil {
+		t.Error("expected builtin type to be rejected")
+	}
+	if err := reg.Register(models.EdgeTypeInfo{Name: "reads_config"}); err == nil {
+		t.Error("expected reads_config, written by the parsers, to be builtin")
+	}
+	if err := reg.Register(models.EdgeTypeInfo{Name: "Deploys-To"}); err == nil {
+		t.Error("expected non-snake_case name to be rejected")
+	}
+	if err := reg.Register(models.EdgeTypeInfo{Name: "secured_by"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	info, ok := reg.Lookup("secured_by")
+	if !ok || !info.Custom || info.Label != "secured_by" || info.Category != "custom" {
+		t.Errorf("expected defaulted custom type, got %+v", info)
+	}
+}
+
+func TestValidateBulkRequest(t *testing.T) {
+	if msg := validateBulkRequest(bulkRequest{}); msg == "" {
+		t.Error("expected empty request to be rejected")
+	}
+	req := bulkRequest{Symbols: []bulkSymbol{{Path: "a.yaml", Language: "yaml", Name: "A", QualifiedName: "x.A"}}}
+	if msg := validateBulkRequest(req); msg == "" {
+		t.Error("expected symbol without kind to be rejected")
+	}
+	req.Symbols[0].Kind = "service"
+	req.Edges = []bulkEdge{{Source: "x.A", Target: "x.B", Type: "deploys_to"}}
+	if msg := validateBulkRequest(req); msg != "" {
+		t.Errorf("expected valid request, got %q", msg)
+	}
+}
+
+func TestWithSetting_PreservesOtherKeys(t *testing.T) {
+	out, err := withSetting([]byte(`{"embed_kinds":["table"]}`), "reference_types", []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"embed_kinds":["table"],"reference_types":["x"]}` {
+		t.Errorf("unexpected settings: %s", out)
+	}
+}
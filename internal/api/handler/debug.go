@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// maxDebugParseBytes caps the request body accepted by the debug parse endpoint.
+const maxDebugParseBytes = 4 << 20
+
+// DebugHandler exposes admin-only tooling for reproducing parser behaviour. Tree-sitter
+// parsers are not safe for concurrent use, so each request parses with a registry of its
+// own from the pool.
+type DebugHandler struct {
+	logger     *slog.Logger
+	registries *parser.RegistryPool
+}
+
+func NewDebugHandler(logger *slog.Logger, registries *parser.RegistryPool) *DebugHandler {
+	return &DebugHandler{logger: logger, registries: registries}
+}
+
+type debugParseRequest struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// Parse runs the registered parser for filename over content and returns the raw
+// ParseResult. Nothing is persisted.
+// POST /debug/parse
+func (h *DebugHandler) Parse(w http.ResponseWriter, r *http.Request) {
+	if h.registries == nil {
+		writeAPIError(w, h.logger, apierr.NotImplemented("Debug parsing (no parser registry)"))
+		return
+	}
+
+	var req debugParseRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDebugParseBytes)).Decode(&req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+	if req.Filename == "" {
+		writeAPIError(w, h.logger, apierr.FilenameRequired())
+		return
+	}
+
+	registry, err := h.registries.Get(r.Context())
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InternalError(err))
+		return
+	}
+	defer h.registries.Put(registry)
+
+	p := registry.ForFile(req.Filename)
+	if p == nil {
+		p = registry.ForContent(req.Filename, []byte(req.Content))
+	}
+	if p == nil {
+		writeAPIError(w, h.logger, apierr.UnsupportedFileType(req.Filename))
+		return
+	}
+
+	// Same dialect detection as the parse stage, so results match an ingest
+	content := []byte(req.Content)
+	language := "sql"
+	if ext := strings.ToLower(filepath.Ext(req.Filename)); ext == ".sql" || ext == ".sqldataprovider" {
+		language = parser.DetectDialect(content)
+	}
+
+	result, err := p.Parse(parser.FileInput{Path: req.Filename, Content: content, Language: language})
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.ParseFailed(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/builtin"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+func TestDebugHandler_Parse_TSQL(t *testing.T) {
+	h := NewDebugHandler(nil, newDebugRegistries())
+	body, _ := json.Marshal(map[string]string{
+		"filename": "repro.sql",
+		"content": `CREATE TABLE dbo.Orders (Id INT, CustomerId INT);
+GO
+CREATE PROCEDURE dbo.usp_GetOrders AS
+BEGIN
+    SELECT Id FROM dbo.Orders;
+END`,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/debug/parse", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Parse(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result parser.ParseResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, s := range result.Symbols {
+		kinds[s.QualifiedName] = s.Kind
+	}
+	if kinds["dbo.Orders"] != "table" || kinds["dbo.usp_GetOrders"] != "procedure" {
+		t.Errorf("expected table dbo.Orders and procedure dbo.usp_GetOrders, got %v", kinds)
+	}
+	found := false
+	for _, ref := range result.References {
+		if ref.FromSymbol == "dbo.usp_GetOrders" && ref.ToQualified == "dbo.Orders" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a reference from usp_GetOrders to dbo.Orders, got %+v", result.References)
+	}
+}
+
+func TestDebugHandler_Parse_UnsupportedFile(t *testing.T) {
+	h := NewDebugHandler(nil, newDebugRegistries())
+	body, _ := json.Marshal(map[string]string{"filename": "notes.txt", "content": "hello"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/debug/parse", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.Parse(w, req)
+
+	var resp apierr.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || resp.Error.Code != apierr.CodeUnsupportedFileType {
+		t.Errorf("expected 400 %s, got %d %s", apierr.CodeUnsupportedFileType, w.Code, resp.Error.Code)
+	}
+}
+
+// Concurrent requests must each parse with a registry of their own: run with -race.
+func TestDebugHandler_Parse_Concurrent(t *testing.T) {
+	h := NewDebugHandler(nil, parser.NewRegistryPool(2, func() *parser.Registry {
+		return builtin.NewRegistry(builtin.Options{})
+	}))
+	body, _ := json.Marshal(map[string]string{
+		"filename": "Orders.java",
+		"content":  "package shop;\npublic class Orders { void find(int id) {} }",
+	})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.Parse(w, httptest.NewRequest(http.MethodPost, "/api/v1/debug/parse", bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Errorf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func newDebugRegistries() *parser.RegistryPool {
+	return parser.NewRegistryPool(1, func() *parser.Registry {
+		return builtin.NewRegistry(builtin.Options{})
+	})
+}
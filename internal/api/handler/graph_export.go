@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/mcp/tools"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+const (
+	// defaultExportNodes and maxExportNodes bound the symbols a graph export holds.
+	defaultExportNodes = 200
+	maxExportNodes     = 5000
+)
+
+// exportContentTypes maps each export format to its media type and file extension.
+var exportContentTypes = map[string][2]string{
+	tools.ExportGraphML: {"application/graphml+xml", ".graphml"},
+	tools.ExportDOT:     {"text/vnd.graphviz", ".dot"},
+}
+
+// GraphExportHandler exports project subgraphs for graph tools such as Gephi and Graphviz.
+type GraphExportHandler struct {
+	logger   *slog.Logger
+	store    *store.Store
+	subgraph *tools.ExtractSubgraphHandler
+}
+
+func NewGraphExportHandler(logger *slog.Logger, s *store.Store, embedder embedding.Embedder) *GraphExportHandler {
+	return &GraphExportHandler{logger: logger, store: s, subgraph: tools.NewExtractSubgraphHandler(s, nil, embedder, logger)}
+}
+
+// Export streams the subgraph extract_subgraph would extract, with the same seed and
+// expansion semantics, as GraphML or DOT.
+// GET /projects/{slug}/graph/export?format=graphml|dot&topic=&kinds=&seeds=&max_depth=&max_nodes=
+func (h *GraphExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+
+	format, params, apiErr := parseGraphExportQuery(r, project.Slug)
+	if apiErr != nil {
+		writeAPIError(w, h.logger, apiErr)
+		return
+	}
+	// Seeds are looked up by ID alone, so those of other projects are refused here
+	for _, id := range params.SeedSymbols {
+		if !h.seedInProject(w, r, id, project) {
+			return
+		}
+	}
+
+	subgraph, err := h.subgraph.Extract(r.Context(), params)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.GraphExportFailed(err))
+		return
+	}
+
+	contentType := exportContentTypes[format]
+	w.Header().Set("Content-Type", contentType[0])
+	w.Header().Set("Content-Disposition", `attachment; filename="`+project.Slug+contentType[1]+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err := subgraph.Write(w, format); err != nil {
+		h.logger.Warn("graph export interrupted", slog.String("project", project.Slug), slog.String("error", err.Error()))
+	}
+}
+
+func (h *GraphExportHandler) seedInProject(w http.ResponseWriter, r *http.Request, id string, project postgres.Project) bool {
+	symbolID, err := uuid.Parse(id)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidID("seed symbol"))
+		return false
+	}
+	sym, err := h.store.GetSymbol(r.Context(), symbolID)
+	if err != nil || sym.ProjectID != project.ID {
+		writeAPIError(w, h.logger, apierr.SymbolNotFound())
+		return false
+	}
+	return true
+}
+
+// parseGraphExportQuery reads the export format and the extract_subgraph parameters of
+// an export request. Lists are comma-separated; max_nodes defaults to 200 and is capped
+// at 5000.
+func parseGraphExportQuery(r *http.Request, slug string) (string, tools.ExtractSubgraphParams, *apierr.Error) {
+	q := r.URL.Query()
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = tools.ExportGraphML
+	}
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", tools.ExtractSubgraphParams{}, apierr.InvalidExportFormat()
+	}
+
+	params := tools.ExtractSubgraphParams{
+		Project:     slug,
+		Topic:       strings.TrimSpace(q.Get("topic")),
+		Kinds:       splitList(q.Get("kinds")),
+		SeedSymbols: splitList(q.Get("seeds")),
+	}
+	if params.Topic == "" && len(params.Kinds) == 0 && len(params.SeedSymbols) == 0 {
+		return "", tools.ExtractSubgraphParams{}, apierr.ExportSeedRequired()
+	}
+	params.MaxDepth, _ = strconv.Atoi(q.Get("max_depth"))
+	params.MaxNodes, _ = strconv.Atoi(q.Get("max_nodes"))
+	if params.MaxNodes <= 0 {
+		params.MaxNodes = defaultExportNodes
+	}
+	params.MaxNodes = min(params.MaxNodes, maxExportNodes)
+	return format, params, nil
+}
+
+// splitList splits a comma-separated query value, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
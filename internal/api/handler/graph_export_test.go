@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+func TestParseGraphExportQuery(t *testing.T) {
+	r := httptest.NewRequest("GET", "/graph/export?format=DOT&kinds=table,%20view,&max_nodes=100000&max_depth=3", nil)
+	format, params, err := parseGraphExportQuery(r, "shop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format != "dot" || params.Project != "shop" || params.MaxDepth != 3 {
+		t.Errorf("unexpected format %q and params %+v", format, params)
+	}
+	if !slices.Equal(params.Kinds, []string{"table", "view"}) {
+		t.Errorf("expected kinds [table view], got %v", params.Kinds)
+	}
+	if params.MaxNodes != maxExportNodes {
+		t.Errorf("expected max_nodes capped at %d, got %d", maxExportNodes, params.MaxNodes)
+	}
+
+	r = httptest.NewRequest("GET", "/graph/export?topic=orders", nil)
+	if format, params, err = parseGraphExportQuery(r, "shop"); err != nil || format != "graphml" || params.MaxNodes != defaultExportNodes {
+		t.Errorf("expected graphml with %d nodes by default, got %q, %+v, %v", defaultExportNodes, format, params, err)
+	}
+
+	for query, code := range map[string]apierr.Code{
+		"format=svg&topic=orders": apierr.CodeInvalidExportFormat,
+		"format=graphml":          apierr.CodeExportSeedRequired,
+	} {
+		r = httptest.NewRequest("GET", "/graph/export?"+query, nil)
+		if _, _, err := parseGraphExportQuery(r, "shop"); err == nil || err.Code() != code {
+			t.Errorf("%s: expected %s, got %v", query, code, err)
+		}
+	}
+}
@@ -79,6 +79,9 @@ func (h *IndexRunHandler) Trigger(w http.ResponseWriter, r *http.Request) {
 	if !checkTenantAccess(w, r, h.logger, project) {
 		return
 	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
 
 	// Optional source_id from query or body
 	if sid := r.URL.Query().Get("source_id"); sid != "" {
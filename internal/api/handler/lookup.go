@@ -59,3 +59,13 @@ func checkTenantAccess(w http.ResponseWriter, r *http.Request, logger *slog.Logg
 	}
 	return true
 }
+
+// checkWritable refuses changes to an archived project, which stays read-only until it
+// is unarchived. Returns true if the project may be changed.
+func checkWritable(w http.ResponseWriter, logger *slog.Logger, project postgres.Project) bool {
+	if project.ArchivedAt.Valid {
+		writeAPIError(w, logger, apierr.ProjectArchived())
+		return false
+	}
+	return true
+}
@@ -1,26 +1,34 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/graph"
+	"github.com/maraichr/lattice/internal/ingestion"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
 	"github.com/maraichr/lattice/pkg/apierr"
 )
 
 type ProjectHandler struct {
-	logger *slog.Logger
-	store  *store.Store
+	logger    *slog.Logger
+	store     *store.Store
+	graph     *graph.Client       // optional; kept in step with soft-deletes and restores
+	producer  *ingestion.Producer // optional; rebuilds the graph of unarchived and restored projects
+	retention time.Duration
 }
 
-func NewProjectHandler(logger *slog.Logger, s *store.Store) *ProjectHandler {
-	return &ProjectHandler{logger: logger, store: s}
+func NewProjectHandler(logger *slog.Logger, s *store.Store, g *graph.Client, producer *ingestion.Producer, retention time.Duration) *ProjectHandler {
+	return &ProjectHandler{logger: logger, store: s, graph: g, producer: producer, retention: retention}
 }
 
 func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -75,6 +83,7 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Name        string  `json:"name"`
 		Slug        string  `json:"slug"`
 		Description *string `json:"description"`
+		Template    string  `json:"template"` // optional project template to copy settings from
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
@@ -90,11 +99,34 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project, err := h.store.CreateProject(r.Context(), postgres.CreateProjectParams{
-		Name:        req.Name,
-		Slug:        req.Slug,
-		Description: req.Description,
-		TenantID:    p.TenantID,
+	var template *postgres.ProjectTemplate
+	if req.Template != "" {
+		t, err := h.store.GetProjectTemplate(r.Context(), req.Template)
+		if err != nil {
+			if apierr.IsNotFound(err) {
+				writeAPIError(w, h.logger, apierr.TemplateNotFound())
+			} else {
+				writeAPIError(w, h.logger, apierr.InternalError(err))
+			}
+			return
+		}
+		template = &t
+	}
+
+	var project postgres.Project
+	err := h.store.WithTx(r.Context(), func(q *postgres.Queries) error {
+		var err error
+		project, err = q.CreateProject(r.Context(), postgres.CreateProjectParams{
+			Name:        req.Name,
+			Slug:        req.Slug,
+			Description: req.Description,
+			TenantID:    p.TenantID,
+		})
+		if err != nil || template == nil {
+			return err
+		}
+		project, err = applyTemplate(r.Context(), q, project, *template)
+		return err
 	})
 	if err != nil {
 		writeAPIError(w, h.logger, apierr.ProjectCreateFailed(err))
@@ -104,6 +136,158 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, project)
 }
 
+// ApplyTemplate re-applies a project template, overwriting only the settings sections
+// the template defines.
+// POST /projects/{slug}/template
+func (h *ProjectHandler) ApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Template string `json:"template"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Template == "" {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+
+	current, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, current) {
+		return
+	}
+	if !checkWritable(w, h.logger, current) {
+		return
+	}
+	template, err := h.store.GetProjectTemplate(r.Context(), req.Template)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.TemplateNotFound())
+		} else {
+			writeAPIError(w, h.logger, apierr.InternalError(err))
+		}
+		return
+	}
+
+	project, err := applyTemplate(r.Context(), h.store.Queries, current, template)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.TemplateApplyFailed(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+// Archive makes a project read-only: ingestion, reprocessing and changes to it are
+// refused until it is unarchived, while queries keep working. With evict_graph its
+// Neo4j subgraph is dropped to free the graph database; lineage then falls back to
+// traversing the edges in Postgres.
+// POST /projects/{slug}/archive
+func (h *ProjectHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		EvictGraph bool `json:"evict_graph"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+			return
+		}
+	}
+
+	current, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, current) {
+		return
+	}
+
+	project, err := h.store.ArchiveProject(r.Context(), current.ID)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.ProjectArchived())
+		} else {
+			writeAPIError(w, h.logger, apierr.ProjectArchiveFailed(err))
+		}
+		return
+	}
+	if req.EvictGraph && h.graph != nil {
+		if err := h.graph.ClearProject(r.Context(), project.ID); err != nil {
+			h.logger.Warn("evict archived project from graph", slog.String("project", project.Slug), slog.String("error", err.Error()))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+// Unarchive makes an archived project writable again and enqueues an index run that
+// rebuilds its graph, which may have been evicted on archiving.
+// POST /projects/{slug}/unarchive
+func (h *ProjectHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	current, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, current) {
+		return
+	}
+
+	project, err := h.store.UnarchiveProject(r.Context(), current.ID)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.ProjectNotArchived())
+		} else {
+			writeAPIError(w, h.logger, apierr.ProjectArchiveFailed(err))
+		}
+		return
+	}
+	h.enqueueGraphRebuild(r.Context(), project)
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+// enqueueGraphRebuild enqueues an index run that only runs the graph stage for project.
+// It is best-effort: on failure the run is marked failed, and the project's next index
+// run rebuilds the graph anyway.
+func (h *ProjectHandler) enqueueGraphRebuild(ctx context.Context, project postgres.Project) {
+	if h.producer == nil {
+		return
+	}
+	run, err := h.store.CreateIndexRun(ctx, postgres.CreateIndexRunParams{ProjectID: project.ID})
+	if err != nil {
+		h.logger.Warn("create graph rebuild run", slog.String("project", project.Slug), slog.String("error", err.Error()))
+		return
+	}
+	msg := ingestion.IngestMessage{
+		IndexRunID: run.ID,
+		ProjectID:  project.ID,
+		Trigger:    "manual",
+		Stages:     []string{ingestion.GraphStageName},
+	}
+	if _, err := h.producer.Enqueue(ctx, msg); err != nil {
+		h.logger.Error("enqueue graph rebuild", slog.String("project", project.Slug), slog.String("error", err.Error()))
+		errMsg := err.Error()
+		_ = h.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
+			ID:           run.ID,
+			Status:       "failed",
+			ErrorMessage: &errMsg,
+		})
+	}
+}
+
+// applyTemplate merges a template's sections into the project's settings and saves them.
+func applyTemplate(ctx context.Context, q *postgres.Queries, project postgres.Project, template postgres.ProjectTemplate) (postgres.Project, error) {
+	settings, err := applyTemplateSettings(project.Settings, template.Settings)
+	if err != nil {
+		return project, err
+	}
+	return q.UpdateProject(ctx, postgres.UpdateProjectParams{
+		Slug:        project.Slug,
+		Name:        project.Name,
+		Description: project.Description,
+		Settings:    settings,
+	})
+}
+
 func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
@@ -130,6 +314,9 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if !checkTenantAccess(w, r, h.logger, current) {
 		return
 	}
+	if !checkWritable(w, h.logger, current) {
+		return
+	}
 
 	name := current.Name
 	if req.Name != "" {
@@ -165,10 +352,50 @@ func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.DeleteProject(r.Context(), slug); err != nil {
+	// Soft-delete: the project stays restorable until the retention window passes.
+	if _, err := h.store.SoftDeleteProjectCascade(r.Context(), slug); err != nil {
 		writeAPIError(w, h.logger, apierr.ProjectDeleteFailed(err))
 		return
 	}
+	if h.graph != nil {
+		if err := h.graph.ClearProject(r.Context(), project.ID); err != nil {
+			h.logger.Warn("clear deleted project from graph", slog.String("project", slug), slog.String("error", err.Error()))
+		}
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Restore brings back a soft-deleted project with its symbols and edges and enqueues an
+// index run that resyncs its graph. Projects deleted longer ago than the retention window cannot be restored.
+func (h *ProjectHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	deleted, err := h.store.GetDeletedProject(r.Context(), slug)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.ProjectNotFound())
+		} else {
+			writeAPIError(w, h.logger, apierr.InternalError(err))
+		}
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, deleted) {
+		return
+	}
+
+	project, err := h.store.RestoreProjectCascade(r.Context(), slug, h.retention, time.Now())
+	if err != nil {
+		if errors.Is(err, store.ErrRetentionElapsed) {
+			writeAPIError(w, h.logger, apierr.RetentionElapsed())
+		} else if errors.Is(err, store.ErrSlugTaken) {
+			writeAPIError(w, h.logger, apierr.ProjectSlugTaken())
+		} else {
+			writeAPIError(w, h.logger, apierr.ProjectRestoreFailed(err))
+		}
+		return
+	}
+	h.enqueueGraphRebuild(r.Context(), project)
+
+	writeJSON(w, http.StatusOK, project)
+}
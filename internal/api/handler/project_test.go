@@ -6,7 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
 	"github.com/maraichr/lattice/pkg/apierr"
 )
 
@@ -77,3 +81,38 @@ func TestProjectHandler_Create_InvalidName(t *testing.T) {
 		t.Errorf("expected code %s, got %s", apierr.CodeNameRequired, resp.Error.Code)
 	}
 }
+
+func TestProjectHandler_Archive_InvalidBody(t *testing.T) {
+	ph := &ProjectHandler{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/p/archive", bytes.NewReader([]byte("invalid")))
+	w := httptest.NewRecorder()
+
+	ph.Archive(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	w := httptest.NewRecorder()
+	if !checkWritable(w, nil, postgres.Project{}) {
+		t.Fatal("live project refused")
+	}
+
+	archived := postgres.Project{ArchivedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	w = httptest.NewRecorder()
+	if checkWritable(w, nil, archived) {
+		t.Fatal("archived project accepted")
+	}
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409, got %d", w.Code)
+	}
+	var resp apierr.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != apierr.CodeProjectArchived {
+		t.Errorf("expected code %s, got %s", apierr.CodeProjectArchived, resp.Error.Code)
+	}
+}
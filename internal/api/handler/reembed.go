@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// ReembedHandler runs admin-triggered re-embeds in the background, one per project.
+type ReembedHandler struct {
+	logger *slog.Logger
+	store  *store.Store
+	embed  embedding.Embedder
+
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+}
+
+func NewReembedHandler(logger *slog.Logger, s *store.Store, embed embedding.Embedder) *ReembedHandler {
+	return &ReembedHandler{logger: logger, store: s, embed: embed, running: make(map[uuid.UUID]context.CancelFunc)}
+}
+
+// Start re-embeds the project's symbols with the configured model in the background.
+// POST /projects/{slug}/reembed?page_size=N
+func (h *ReembedHandler) Start(w http.ResponseWriter, r *http.Request) {
+	if h.embed == nil {
+		writeAPIError(w, h.logger, apierr.NotImplemented("Re-embedding (embeddings not configured)"))
+		return
+	}
+
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	h.mu.Lock()
+	if _, busy := h.running[project.ID]; busy {
+		h.mu.Unlock()
+		writeAPIError(w, h.logger, apierr.ReembedInProgress())
+		return
+	}
+	// Detached from the request: the run outlives it and stops only on Cancel.
+	ctx, cancel := context.WithCancel(context.Background())
+	h.running[project.ID] = cancel
+	h.mu.Unlock()
+
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			delete(h.running, project.ID)
+			h.mu.Unlock()
+			cancel()
+		}()
+		if _, err := embedding.Reembed(ctx, h.embed, h.store, project.ID, embedding.KindFilterFromSettings(project.Settings), pageSize, h.logger); err != nil && ctx.Err() == nil {
+			h.logger.Error("reembed failed", slog.String("project_id", project.ID.String()), slog.String("error", err.Error()))
+		}
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"project_id": project.ID,
+		"model":      h.embed.ModelID(),
+		"status":     "started",
+	})
+}
+
+// Cancel stops a running re-embed; progress so far is kept and a later Start resumes.
+// DELETE /projects/{slug}/reembed
+func (h *ReembedHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+
+	h.mu.Lock()
+	cancel, running := h.running[project.ID]
+	h.mu.Unlock()
+	if !running {
+		writeAPIError(w, h.logger, apierr.ReembedNotRunning())
+		return
+	}
+	cancel()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"project_id": project.ID,
+		"status":     "cancelled",
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// ReferenceTypeHandler manages a project's registry of custom reference (edge) types.
+type ReferenceTypeHandler struct {
+	logger *slog.Logger
+	store  *store.Store
+}
+
+func NewReferenceTypeHandler(logger *slog.Logger, s *store.Store) *ReferenceTypeHandler {
+	return &ReferenceTypeHandler{logger: logger, store: s}
+}
+
+// List returns the builtin and custom reference types accepted by the project.
+// GET /projects/{slug}/reference-types
+func (h *ReferenceTypeHandler) List(w http.ResponseWriter, r *http.Request) {
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"builtin": models.BuiltinEdgeTypes,
+		"custom":  models.EdgeTypeRegistryFromSettings(project.Settings).Custom(),
+	})
+}
+
+// Register adds or updates a custom reference type in the project settings.
+// POST /projects/{slug}/reference-types
+func (h *ReferenceTypeHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req models.EdgeTypeInfo
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
+
+	reg := models.EdgeTypeRegistryFromSettings(project.Settings)
+	if err := reg.Register(req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidReferenceType(err))
+		return
+	}
+	registered, _ := reg.Lookup(string(req.Name))
+
+	settings, err := withSetting(project.Settings, "reference_types", reg.Custom())
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InternalError(err))
+		return
+	}
+	if _, err := h.store.UpdateProject(r.Context(), postgres.UpdateProjectParams{
+		Slug:        project.Slug,
+		Name:        project.Name,
+		Description: project.Description,
+		Settings:    settings,
+	}); err != nil {
+		writeAPIError(w, h.logger, apierr.ProjectUpdateFailed(err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, registered)
+}
+
+// withSetting returns settings with key set to value, preserving every other key.
+func withSetting(settings []byte, key string, value any) ([]byte, error) {
+	m := make(map[string]json.RawMessage)
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &m); err != nil {
+			return nil, err
+		}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	m[key] = raw
+	return json.Marshal(m)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/maraichr/lattice/pkg/apierr"
 )
@@ -21,3 +22,44 @@ func writeAPIError(w http.ResponseWriter, logger *slog.Logger, e *apierr.Error)
 	}
 	writeJSON(w, e.Status(), e.Response())
 }
+
+// ndjsonContentType is the media type of newline-delimited JSON streams.
+const ndjsonContentType = "application/x-ndjson"
+
+// wantsNDJSON reports whether the client asked for a newline-delimited JSON stream.
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonContentType)
+}
+
+// ndjsonWriter streams one JSON value per line. The 200 header goes out with the first
+// line, so a failure before any output can still be answered with an error status, and
+// each line is flushed so clients can process results as they arrive.
+type ndjsonWriter struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	started bool
+}
+
+func newNDJSONWriter(w http.ResponseWriter) *ndjsonWriter {
+	return &ndjsonWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) start() {
+	if n.started {
+		return
+	}
+	n.started = true
+	n.w.Header().Set("Content-Type", ndjsonContentType)
+	n.w.WriteHeader(http.StatusOK)
+}
+
+func (n *ndjsonWriter) write(v any) error {
+	n.start()
+	if err := n.enc.Encode(v); err != nil {
+		return err
+	}
+	if f, ok := n.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
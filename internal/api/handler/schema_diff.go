@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/schema"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// SchemaDiffHandler compares the schema snapshots recorded by two index runs.
+type SchemaDiffHandler struct {
+	logger *slog.Logger
+	store  *store.Store
+}
+
+func NewSchemaDiffHandler(logger *slog.Logger, s *store.Store) *SchemaDiffHandler {
+	return &SchemaDiffHandler{logger: logger, store: s}
+}
+
+// Get returns the tables, columns and foreign keys added, dropped or retyped between two runs.
+// run_b defaults to the latest run and run_a to the run before it.
+// GET /projects/{slug}/schema-diff?run_a=&run_b=
+func (h *SchemaDiffHandler) Get(w http.ResponseWriter, r *http.Request) {
+	project, ok := getProjectOr404(w, r, h.logger, h.store, chi.URLParam(r, "slug"))
+	if !ok {
+		return
+	}
+	if !checkTenantAccess(w, r, h.logger, project) {
+		return
+	}
+
+	var runs [2]uuid.UUID
+	for i, key := range []string{"run_a", "run_b"} {
+		if v := r.URL.Query().Get(key); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				writeAPIError(w, h.logger, apierr.InvalidRunID())
+				return
+			}
+			runs[i] = id
+		}
+	}
+
+	a, b, err := schema.LoadRuns(r.Context(), h.store, project.ID, runs[0], runs[1])
+	if errors.Is(err, schema.ErrSnapshotNotFound) || errors.Is(err, schema.ErrNotEnoughRuns) {
+		writeAPIError(w, h.logger, apierr.SchemaSnapshotNotFound(err))
+		return
+	}
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.SchemaDiffFailed(err))
+		return
+	}
+	snapA, err := schema.Decode(a.Snapshot)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.SchemaDiffFailed(err))
+		return
+	}
+	snapB, err := schema.Decode(b.Snapshot)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.SchemaDiffFailed(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"run_a": a.IndexRunID,
+		"run_b": b.IndexRunID,
+		"diff":  schema.Compare(snapA, snapB),
+	})
+}
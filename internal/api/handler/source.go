@@ -84,6 +84,10 @@ func (h *SourceHandler) Create(w http.ResponseWriter, r *http.Request) {
 		writeAPIError(w, h.logger, err)
 		return
 	}
+	if err := validateConnectionURI(req.SourceType, req.ConnectionURI); err != nil {
+		writeAPIError(w, h.logger, err)
+		return
+	}
 
 	project, ok := getProjectOr404(w, r, h.logger, h.store, projectSlug)
 	if !ok {
@@ -92,6 +96,9 @@ func (h *SourceHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if !checkTenantAccess(w, r, h.logger, project) {
 		return
 	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
 
 	configBytes := []byte("{}")
 	if len(req.Config) > 0 {
@@ -120,7 +127,16 @@ func (h *SourceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, ok := getSourceOr404(w, r, h.logger, h.store, sourceID); !ok {
+	source, ok := getSourceOr404(w, r, h.logger, h.store, sourceID)
+	if !ok {
+		return
+	}
+	project, err := h.store.GetProjectByID(r.Context(), source.ProjectID)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InternalError(err))
+		return
+	}
+	if !checkWritable(w, h.logger, project) {
 		return
 	}
 
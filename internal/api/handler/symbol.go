@@ -23,13 +23,25 @@ type SymbolHandler struct {
 	graph   *graph.Client
 	lineage *lineage.Engine
 	impact  *impact.Engine
+
+	directions lineage.EdgeDirections
 }
 
-func NewSymbolHandler(logger *slog.Logger, s *store.Store, g *graph.Client, lin *lineage.Engine, imp *impact.Engine) *SymbolHandler {
-	return &SymbolHandler{logger: logger, store: s, graph: g, lineage: lin, impact: imp}
+// NewSymbolHandler creates a symbol handler whose lineage walks edges the way dirs points
+// them, or the default way when dirs is nil.
+func NewSymbolHandler(logger *slog.Logger, s *store.Store, g *graph.Client, lin *lineage.Engine, imp *impact.Engine, dirs lineage.EdgeDirections) *SymbolHandler {
+	if dirs == nil {
+		dirs = lineage.DefaultEdgeDirections()
+	}
+	return &SymbolHandler{logger: logger, store: s, graph: g, lineage: lin, impact: imp, directions: dirs}
 }
 
-// Search finds symbols matching a query within a project.
+// maxStreamLimit caps the limit of a search streamed as NDJSON, which is not buffered
+// and so may ask for far more rows than a JSON response.
+const maxStreamLimit = 10000
+
+// Search finds symbols matching a query within a project. With
+// Accept: application/x-ndjson the matches are streamed one JSON object per line.
 // GET /projects/{slug}/symbols?q=...&kind=...&limit=...
 func (h *SymbolHandler) Search(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -47,15 +59,20 @@ func (h *SymbolHandler) Search(w http.ResponseWriter, r *http.Request) {
 	if languages == nil {
 		languages = []string{}
 	}
-	limit := intQuery(r, "limit", 20, 100)
-
-	rows, err := h.store.SearchSymbols(r.Context(), postgres.SearchSymbolsParams{
+	params := postgres.SearchSymbolsParams{
 		ProjectSlug: slug,
 		Query:       &q,
 		Kinds:       kinds,
 		Languages:   languages,
-		Lim:         int32(limit),
-	})
+		Lim:         int32(intQuery(r, "limit", 20, 100)),
+	}
+	if wantsNDJSON(r) {
+		params.Lim = int32(intQuery(r, "limit", 20, maxStreamLimit))
+		h.streamSearch(w, r, params)
+		return
+	}
+
+	rows, err := h.store.SearchSymbols(r.Context(), params)
 	if err != nil {
 		writeAPIError(w, h.logger, apierr.SearchFailed(err))
 		return
@@ -67,6 +84,29 @@ func (h *SymbolHandler) Search(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// streamSearch writes each match as it is read from the database. An error before the
+// first match is a regular JSON error; after it, the stream ends with an error line.
+func (h *SymbolHandler) streamSearch(w http.ResponseWriter, r *http.Request, params postgres.SearchSymbolsParams) {
+	out := newNDJSONWriter(w)
+	err := h.store.SearchSymbolsEach(r.Context(), params, func(sym postgres.Symbol) error {
+		return out.write(sym)
+	})
+	if err == nil {
+		out.start() // no matches: an empty stream
+		return
+	}
+	e := apierr.SearchFailed(err)
+	if !out.started {
+		writeAPIError(w, h.logger, e)
+		return
+	}
+	if r.Context().Err() != nil {
+		return // the client went away
+	}
+	h.logger.Error("stream search results", slog.String("error", err.Error()))
+	out.write(e.Response())
+}
+
 // Get returns a single symbol by ID.
 // GET /symbols/{id}
 func (h *SymbolHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -126,14 +166,10 @@ func (h *SymbolHandler) References(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Lineage returns the lineage graph for a symbol via Neo4j.
+// Lineage returns the lineage graph for a symbol via Neo4j, or without Neo4j and for a
+// symbol of an archived project by traversing the edges in the database.
 // GET /symbols/{id}/lineage?direction=upstream|downstream|both&max_depth=3
 func (h *SymbolHandler) Lineage(w http.ResponseWriter, r *http.Request) {
-	if h.graph == nil {
-		writeAPIError(w, h.logger, apierr.NotImplemented("Lineage (Neo4j not configured)"))
-		return
-	}
-
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		writeAPIError(w, h.logger, apierr.InvalidID("symbol"))
@@ -146,9 +182,13 @@ func (h *SymbolHandler) Lineage(w http.ResponseWriter, r *http.Request) {
 	}
 	maxDepth := intQuery(r, "max_depth", 3, 10)
 
-	result, err := h.graph.Lineage(r.Context(), id, direction, maxDepth)
+	result, err := graph.LineageFor(r.Context(), h.graph, h.store, id, direction, maxDepth, h.directions.UpstreamTypes())
 	if err != nil {
-		writeAPIError(w, h.logger, apierr.LineageQueryFailed(err))
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.SymbolNotFound())
+		} else {
+			writeAPIError(w, h.logger, apierr.LineageQueryFailed(err))
+		}
 		return
 	}
 
@@ -0,0 +1,99 @@
+//go:build integration
+
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestSearchSymbols_NDJSONStream(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Test Stream Project",
+		Slug: fmt.Sprintf("test-stream-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "repo", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	file, err := s.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: proj.ID, SourceID: source.ID, Path: "db/orders.sql", Language: "tsql", Hash: "h1",
+	})
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	for i := range 5 {
+		name := fmt.Sprintf("usp_Order%d", i)
+		if _, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: file.ID, Name: name, QualifiedName: "dbo." + name,
+			Kind: "procedure", Language: "tsql", StartLine: 1, EndLine: 10,
+		}); err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Get("/projects/{slug}/symbols", NewSymbolHandler(logger, s, nil, nil, nil, nil).Search)
+
+	stream := func(query string) []postgres.Symbol {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/projects/"+proj.Slug+"/symbols?"+query, nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", query, w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("content type = %q", ct)
+		}
+		var syms []postgres.Symbol
+		sc := bufio.NewScanner(w.Body)
+		for sc.Scan() {
+			var sym postgres.Symbol
+			if err := json.Unmarshal(sc.Bytes(), &sym); err != nil {
+				t.Fatalf("line %q: %v", sc.Text(), err)
+			}
+			syms = append(syms, sym)
+		}
+		return syms
+	}
+
+	if got := stream("q=usp_Order"); len(got) != 5 {
+		t.Errorf("expected 5 lines, got %d", len(got))
+	}
+	got := stream("q=usp_Order&limit=3")
+	if len(got) != 3 {
+		t.Fatalf("expected the limit to cap the stream at 3 lines, got %d", len(got))
+	}
+	if got[0].Name != "usp_Order0" || got[2].Name != "usp_Order2" {
+		t.Errorf("expected matches in name order, got %s..%s", got[0].Name, got[2].Name)
+	}
+	if got := stream("q=nothing_matches"); len(got) != 0 {
+		t.Errorf("expected an empty stream, got %d lines", len(got))
+	}
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// TemplateHandler manages project templates: named settings bundles that projects
+// can be created from and re-applied to. Each top-level settings key is a templated
+// section.
+type TemplateHandler struct {
+	logger *slog.Logger
+	store  *store.Store
+}
+
+func NewTemplateHandler(logger *slog.Logger, s *store.Store) *TemplateHandler {
+	return &TemplateHandler{logger: logger, store: s}
+}
+
+// templateResponse renders a template with its settings as JSON rather than bytes.
+type templateResponse struct {
+	ID          uuid.UUID       `json:"id"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	Settings    json.RawMessage `json:"settings"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func toTemplateResponse(t postgres.ProjectTemplate) templateResponse {
+	return templateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Settings:    json.RawMessage(t.Settings),
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// List returns every project template.
+// GET /templates
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.store.ListProjectTemplates(r.Context())
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.TemplateListFailed(err))
+		return
+	}
+	out := make([]templateResponse, len(templates))
+	for i, t := range templates {
+		out[i] = toTemplateResponse(t)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// Get returns one template.
+// GET /templates/{name}
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.getTemplateOr404(w, r, chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toTemplateResponse(t))
+}
+
+// Create registers a new template.
+// POST /templates
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string          `json:"name"`
+		Description *string         `json:"description"`
+		Settings    json.RawMessage `json:"settings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+	if err := validateSlug(req.Name); err != nil {
+		writeAPIError(w, h.logger, err)
+		return
+	}
+	if !isJSONObject(req.Settings) {
+		writeAPIError(w, h.logger, apierr.InvalidTemplateSettings())
+		return
+	}
+
+	t, err := h.store.CreateProjectTemplate(r.Context(), postgres.CreateProjectTemplateParams{
+		Name:        req.Name,
+		Description: req.Description,
+		Settings:    req.Settings,
+	})
+	if err != nil {
+		if apierr.IsUniqueViolation(err) {
+			writeAPIError(w, h.logger, apierr.TemplateExists())
+		} else {
+			writeAPIError(w, h.logger, apierr.TemplateSaveFailed(err))
+		}
+		return
+	}
+	writeJSON(w, http.StatusCreated, toTemplateResponse(t))
+}
+
+// Update replaces a template's description and settings. Projects created from it
+// are unchanged until the template is re-applied to them.
+// PUT /templates/{name}
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Description *string         `json:"description"`
+		Settings    json.RawMessage `json:"settings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, h.logger, apierr.InvalidRequestBody())
+		return
+	}
+
+	current, ok := h.getTemplateOr404(w, r, chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+	settings := current.Settings
+	if req.Settings != nil {
+		if !isJSONObject(req.Settings) {
+			writeAPIError(w, h.logger, apierr.InvalidTemplateSettings())
+			return
+		}
+		settings = req.Settings
+	}
+	desc := current.Description
+	if req.Description != nil {
+		desc = req.Description
+	}
+
+	t, err := h.store.UpdateProjectTemplate(r.Context(), postgres.UpdateProjectTemplateParams{
+		Name:        current.Name,
+		Description: desc,
+		Settings:    settings,
+	})
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.TemplateSaveFailed(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, toTemplateResponse(t))
+}
+
+// Delete removes a template. Projects created from it keep their settings.
+// DELETE /templates/{name}
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	n, err := h.store.DeleteProjectTemplate(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.TemplateDeleteFailed(err))
+		return
+	}
+	if n == 0 {
+		writeAPIError(w, h.logger, apierr.TemplateNotFound())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TemplateHandler) getTemplateOr404(w http.ResponseWriter, r *http.Request, name string) (postgres.ProjectTemplate, bool) {
+	t, err := h.store.GetProjectTemplate(r.Context(), name)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			writeAPIError(w, h.logger, apierr.TemplateNotFound())
+		} else {
+			writeAPIError(w, h.logger, apierr.InternalError(err))
+		}
+		return t, false
+	}
+	return t, true
+}
+
+// applyTemplateSettings overwrites the sections of a project's settings that the
+// template defines (its top-level keys) and leaves every other section as it is.
+func applyTemplateSettings(settings, template []byte) ([]byte, error) {
+	merged := make(map[string]json.RawMessage)
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &merged); err != nil {
+			return nil, err
+		}
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(template, &sections); err != nil {
+		return nil, err
+	}
+	for key, value := range sections {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
+
+// isJSONObject reports whether raw holds a JSON object.
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	var m map[string]json.RawMessage
+	return json.Unmarshal(trimmed, &m) == nil
+}
@@ -0,0 +1,105 @@
+//go:build integration
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestProjectTemplate_CreateAndReapply(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	suffix := uuid.NewString()[:8]
+	name := "dotnet-" + suffix
+	slug := "test-tmpl-" + suffix
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE slug = $1", slug)
+		s.Pool().Exec(ctx, "DELETE FROM project_templates WHERE name = $1", name)
+	})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			admin := &auth.Principal{Sub: "test", Roles: map[string]bool{"lattice_admin": true}}
+			next.ServeHTTP(w, req.WithContext(auth.WithPrincipal(req.Context(), admin)))
+		})
+	})
+	templates := NewTemplateHandler(logger, s)
+	projects := NewProjectHandler(logger, s, nil, nil, 0)
+	r.Post("/templates", templates.Create)
+	r.Put("/templates/{name}", templates.Update)
+	r.Post("/projects", projects.Create)
+	r.Post("/projects/{slug}/template", projects.ApplyTemplate)
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w
+	}
+	settingsOf := func() map[string]any {
+		p, err := s.GetProject(ctx, slug)
+		if err != nil {
+			t.Fatalf("get project: %v", err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(p.Settings, &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+
+	if w := send(http.MethodPost, "/templates", map[string]any{
+		"name":     name,
+		"settings": map[string]any{"lineage_exclude_paths": []string{"vendor/**"}, "module_detection": "manifest"},
+	}); w.Code != http.StatusCreated {
+		t.Fatalf("create template: got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := send(http.MethodPost, "/projects", map[string]any{"name": "Templated", "slug": slug, "template": name}); w.Code != http.StatusCreated {
+		t.Fatalf("create project: got %d: %s", w.Code, w.Body.String())
+	}
+	got := settingsOf()
+	if got["module_detection"] != "manifest" || fmt.Sprint(got["lineage_exclude_paths"]) != "[vendor/**]" {
+		t.Fatalf("expected template settings copied into the project, got %v", got)
+	}
+
+	// A project-specific section the template does not define survives re-application.
+	p, _ := s.GetProject(ctx, slug)
+	settings, _ := withSetting(p.Settings, "detect_conditional_calls", true)
+	if _, err := s.UpdateProject(ctx, postgres.UpdateProjectParams{Slug: slug, Name: p.Name, Settings: settings}); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := send(http.MethodPut, "/templates/"+name, map[string]any{
+		"settings": map[string]any{"lineage_exclude_paths": []string{"vendor/**", "dist/**"}},
+	}); w.Code != http.StatusOK {
+		t.Fatalf("update template: got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send(http.MethodPost, "/projects/"+slug+"/template", map[string]any{"template": name}); w.Code != http.StatusOK {
+		t.Fatalf("apply template: got %d: %s", w.Code, w.Body.String())
+	}
+
+	got = settingsOf()
+	if fmt.Sprint(got["lineage_exclude_paths"]) != "[vendor/** dist/**]" {
+		t.Errorf("expected the templated section updated, got %v", got["lineage_exclude_paths"])
+	}
+	if got["module_detection"] != "manifest" || got["detect_conditional_calls"] != true {
+		t.Errorf("expected untemplated sections left as they were, got %v", got)
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyTemplateSettings_CopiesIntoNewProject(t *testing.T) {
+	template := []byte(`{"lineage_exclude_paths":["vendor/**"],"module_detection":"manifest"}`)
+
+	got, err := applyTemplateSettings([]byte(`{}`), template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, got, template)
+}
+
+func TestApplyTemplateSettings_ReapplyUpdatesOnlyTemplatedSections(t *testing.T) {
+	project := []byte(`{"lineage_exclude_paths":["old/**"],"module_detection":"off","reference_types":[{"name":"deploys_to"}]}`)
+	template := []byte(`{"lineage_exclude_paths":["vendor/**","dist/**"],"detect_conditional_calls":true}`)
+
+	got, err := applyTemplateSettings(project, template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte(`{
+		"lineage_exclude_paths": ["vendor/**", "dist/**"],
+		"detect_conditional_calls": true,
+		"module_detection": "off",
+		"reference_types": [{"name": "deploys_to"}]
+	}`)
+	assertJSONEqual(t, got, want)
+}
+
+func TestIsJSONObject(t *testing.T) {
+	tests := map[string]bool{
+		`{"a":1}`:    true,
+		` {} `:       true,
+		`[1,2]`:      false,
+		`"settings"`: false,
+		``:           false,
+		`{"a":`:      false,
+	}
+	for raw, want := range tests {
+		if got := isJSONObject(json.RawMessage(raw)); got != want {
+			t.Errorf("isJSONObject(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("settings mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/maraichr/lattice/internal/ingestion"
-	minioclient "github.com/maraichr/lattice/internal/store/minio"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
 	"github.com/maraichr/lattice/pkg/apierr"
@@ -21,12 +20,12 @@ import (
 type UploadHandler struct {
 	logger   *slog.Logger
 	store    *store.Store
-	minio    *minioclient.Client
+	blobs    store.BlobStore
 	producer *ingestion.Producer
 }
 
-func NewUploadHandler(logger *slog.Logger, s *store.Store, minio *minioclient.Client, producer *ingestion.Producer) *UploadHandler {
-	return &UploadHandler{logger: logger, store: s, minio: minio, producer: producer}
+func NewUploadHandler(logger *slog.Logger, s *store.Store, blobs store.BlobStore, producer *ingestion.Producer) *UploadHandler {
+	return &UploadHandler{logger: logger, store: s, blobs: blobs, producer: producer}
 }
 
 func (h *UploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +41,9 @@ func (h *UploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	if !checkTenantAccess(w, r, h.logger, project) {
 		return
 	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
@@ -72,8 +74,8 @@ func (h *UploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Upload to MinIO
-	if err := h.minio.UploadFile(r.Context(), objectName, file, header.Size); err != nil {
+	// Upload to the blob store
+	if err := h.blobs.UploadFile(r.Context(), objectName, file, header.Size); err != nil {
 		writeAPIError(w, h.logger, apierr.UploadFailed(err))
 		return
 	}
@@ -3,6 +3,7 @@ package handler
 import (
 	"regexp"
 
+	"github.com/maraichr/lattice/internal/ingestion/connectors"
 	"github.com/maraichr/lattice/pkg/apierr"
 )
 
@@ -41,3 +42,15 @@ func validateSourceType(st string) *apierr.Error {
 	}
 	return nil
 }
+
+// validateConnectionURI checks a git source's connection_uri: its repository URL and
+// ref are passed to git, so neither may look like an option.
+func validateConnectionURI(sourceType string, uri *string) *apierr.Error {
+	if sourceType != "git" || uri == nil {
+		return nil
+	}
+	if err := connectors.ValidateGitSource(*uri); err != nil {
+		return apierr.InvalidConnectionURI(err.Error())
+	}
+	return nil
+}
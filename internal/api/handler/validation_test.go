@@ -89,3 +89,31 @@ func TestValidateSourceType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConnectionURI(t *testing.T) {
+	uri := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		sourceType string
+		uri        *string
+		wantErr    bool
+	}{
+		{"git repo", "git", uri("https://github.com/org/repo@v1.2.0"), false},
+		{"git without uri", "git", nil, false},
+		{"ref as option", "git", uri("https://github.com/org/repo@--upload-pack=x"), true},
+		{"url as option", "git", uri("--config=core.sshCommand=x"), true},
+		{"not git", "database", uri("--anything"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConnectionURI(tt.sourceType, tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateConnectionURI error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err.Code() != apierr.CodeInvalidConnectionURI {
+				t.Errorf("validateConnectionURI code = %v, want %v", err.Code(), apierr.CodeInvalidConnectionURI)
+			}
+		})
+	}
+}
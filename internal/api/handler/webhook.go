@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -57,6 +58,16 @@ func (h *WebhookHandler) GitLabPush(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Pushes to an archived project's repository are not indexed
+	project, err := h.store.GetProjectByID(r.Context(), source.ProjectID)
+	if err != nil {
+		writeAPIError(w, h.logger, apierr.InternalError(err))
+		return
+	}
+	if !checkWritable(w, h.logger, project) {
+		return
+	}
+
 	// Create index run
 	run, err := h.store.CreateIndexRun(r.Context(), postgres.CreateIndexRunParams{
 		ProjectID: source.ProjectID,
@@ -69,7 +80,7 @@ func (h *WebhookHandler) GitLabPush(w http.ResponseWriter, r *http.Request) {
 
 	// Enqueue
 	if h.producer != nil {
-		h.enqueue(r.Context(), run, source)
+		h.enqueue(r.Context(), &run, source)
 	}
 
 	h.logger.Info("webhook received",
@@ -81,7 +92,9 @@ func (h *WebhookHandler) GitLabPush(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (h *WebhookHandler) enqueue(ctx context.Context, run postgres.IndexRun, source postgres.Source) {
+// enqueue queues the run of a push, or cancels it when the push is coalesced into a run
+// already waiting for the source.
+func (h *WebhookHandler) enqueue(ctx context.Context, run *postgres.IndexRun, source postgres.Source) {
 	msg := ingestion.IngestMessage{
 		IndexRunID: run.ID,
 		ProjectID:  source.ProjectID,
@@ -89,7 +102,21 @@ func (h *WebhookHandler) enqueue(ctx context.Context, run postgres.IndexRun, sou
 		SourceType: source.SourceType,
 		Trigger:    "webhook",
 	}
-	if _, err := h.producer.Enqueue(ctx, msg); err != nil {
+	_, err := h.producer.Enqueue(ctx, msg)
+	var coalesced *ingestion.CoalescedError
+	switch {
+	case errors.As(err, &coalesced):
+		reason := coalesced.Error()
+		if err := h.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
+			ID:           run.ID,
+			Status:       "cancelled",
+			ErrorMessage: &reason,
+		}); err != nil {
+			h.logger.Warn("cancel coalesced index run", slog.String("index_run_id", run.ID.String()), slog.String("error", err.Error()))
+			return
+		}
+		run.Status, run.ErrorMessage = "cancelled", &reason
+	case err != nil:
 		h.logger.Error("enqueue ingestion", slog.String("error", err.Error()))
 	}
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -21,21 +22,26 @@ import (
 	"github.com/maraichr/lattice/internal/ingestion"
 	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/oracle"
+	"github.com/maraichr/lattice/internal/parser"
 	minioclient "github.com/maraichr/lattice/internal/store/minio"
 	"github.com/maraichr/lattice/internal/store"
 )
 
 // RouterDeps holds optional dependencies for the router.
 type RouterDeps struct {
-	MinIO       *minioclient.Client
-	Producer    *ingestion.Producer
-	Graph       *graph.Client
-	Embed       embedding.Embedder
-	Lineage     *lineage.Engine
-	Impact      *impact.Engine
-	Oracle      *oracle.Engine
-	Verifier    *auth.Verifier
-	AuthEnabled bool
+	MinIO          *minioclient.Client
+	Producer       *ingestion.Producer
+	Graph          *graph.Client
+	Embed          embedding.Embedder
+	Lineage        *lineage.Engine
+	Impact         *impact.Engine
+	EdgeDirections lineage.EdgeDirections // which way lineage walks each edge type; nil for the defaults
+	Oracle         *oracle.Engine
+	Parsers        *parser.RegistryPool
+	GraphQL        graphql.Limits
+	Retention      time.Duration // restore window for soft-deleted projects
+	Verifier       *auth.Verifier
+	AuthEnabled    bool
 }
 
 func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
@@ -65,7 +71,7 @@ func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
 		r.Use(authHandler)
 
 		r.Route("/projects", func(r chi.Router) {
-			projects := apihandler.NewProjectHandler(logger, s)
+			projects := apihandler.NewProjectHandler(logger, s, deps.Graph, deps.Producer, deps.Retention)
 
 			r.With(auth.RequireScope("lattice:read")).Get("/", projects.List)
 			r.With(auth.RequireScope("lattice:write")).Post("/", projects.Create)
@@ -73,17 +79,27 @@ func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
 				r.With(auth.RequireScope("lattice:read")).Get("/", projects.Get)
 				r.With(auth.RequireScope("lattice:write")).Put("/", projects.Update)
 				r.With(auth.RequireScope("lattice:write")).Delete("/", projects.Delete)
+				r.With(auth.RequireScope("lattice:admin")).Post("/restore", projects.Restore)
+				r.With(auth.RequireScope("lattice:admin")).Post("/archive", projects.Archive)
+				r.With(auth.RequireScope("lattice:admin")).Post("/unarchive", projects.Unarchive)
+				r.With(auth.RequireScope("lattice:write")).Post("/template", projects.ApplyTemplate)
 
 				sources := apihandler.NewSourceHandler(logger, s)
+				bulk := apihandler.NewBulkHandler(logger, s)
 				r.Route("/sources", func(r chi.Router) {
 					r.With(auth.RequireScope("lattice:read")).Get("/", sources.List)
 					r.With(auth.RequireScope("lattice:write")).Post("/", sources.Create)
 					r.Route("/{sourceID}", func(r chi.Router) {
 						r.With(auth.RequireScope("lattice:read")).Get("/", sources.Get)
 						r.With(auth.RequireScope("lattice:write")).Delete("/", sources.Delete)
+						r.With(auth.RequireScope("lattice:ingest")).Post("/bulk", bulk.Ingest)
 					})
 				})
 
+				refTypes := apihandler.NewReferenceTypeHandler(logger, s)
+				r.With(auth.RequireScope("lattice:read")).Get("/reference-types", refTypes.List)
+				r.With(auth.RequireScope("lattice:write")).Post("/reference-types", refTypes.Register)
+
 				indexRuns := apihandler.NewIndexRunHandler(logger, s, deps.Producer)
 				r.Route("/index-runs", func(r chi.Router) {
 					r.With(auth.RequireScope("lattice:read")).Get("/", indexRuns.List)
@@ -91,12 +107,22 @@ func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
 					r.With(auth.RequireScope("lattice:read")).Get("/{runID}", indexRuns.Get)
 				})
 
-				symbolsInProject := apihandler.NewSymbolHandler(logger, s, deps.Graph, deps.Lineage, deps.Impact)
+				symbolsInProject := apihandler.NewSymbolHandler(logger, s, deps.Graph, deps.Lineage, deps.Impact, deps.EdgeDirections)
 				r.With(auth.RequireScope("lattice:read")).Get("/symbols", symbolsInProject.Search)
 
+				schemaDiff := apihandler.NewSchemaDiffHandler(logger, s)
+				r.With(auth.RequireScope("lattice:read")).Get("/schema-diff", schemaDiff.Get)
+
+				graphExport := apihandler.NewGraphExportHandler(logger, s, deps.Embed)
+				r.With(auth.RequireScope("lattice:read")).Get("/graph/export", graphExport.Export)
+
 				search := apihandler.NewSearchHandler(logger, s, deps.Embed)
 				r.With(auth.RequireScope("lattice:read")).Post("/search/semantic", search.Semantic)
 
+				reembed := apihandler.NewReembedHandler(logger, s, deps.Embed)
+				r.With(auth.RequireScope("lattice:admin")).Post("/reembed", reembed.Start)
+				r.With(auth.RequireScope("lattice:admin")).Delete("/reembed", reembed.Cancel)
+
 				analytics := apihandler.NewAnalyticsHandler(logger, s)
 				r.Route("/analytics", func(r chi.Router) {
 					r.Use(auth.RequireScope("lattice:read"))
@@ -125,7 +151,17 @@ func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
 			})
 		})
 
-		symbols := apihandler.NewSymbolHandler(logger, s, deps.Graph, deps.Lineage, deps.Impact)
+		templates := apihandler.NewTemplateHandler(logger, s)
+		r.Route("/templates", func(r chi.Router) {
+			r.Use(auth.RequireScope("lattice:admin"))
+			r.Get("/", templates.List)
+			r.Post("/", templates.Create)
+			r.Get("/{name}", templates.Get)
+			r.Put("/{name}", templates.Update)
+			r.Delete("/{name}", templates.Delete)
+		})
+
+		symbols := apihandler.NewSymbolHandler(logger, s, deps.Graph, deps.Lineage, deps.Impact, deps.EdgeDirections)
 		r.Route("/symbols", func(r chi.Router) {
 			r.Use(auth.RequireScope("lattice:read"))
 			r.Get("/search", symbols.SearchGlobal)
@@ -138,16 +174,20 @@ func NewRouter(logger *slog.Logger, s *store.Store, deps *RouterDeps) *chi.Mux {
 			})
 		})
 
+		debug := apihandler.NewDebugHandler(logger, deps.Parsers)
+		r.With(auth.RequireScope("lattice:admin")).Post("/debug/parse", debug.Parse)
+
 		webhooks := apihandler.NewWebhookHandler(logger, s, deps.Producer)
 		r.With(auth.RequireScope("lattice:ingest")).Post("/webhooks/gitlab/{sourceID}", webhooks.GitLabPush)
 	})
 
 	// GraphQL — auth on handler, playground stays open
-	gqlResolver := graphql.NewResolver(logger, s, deps.Graph, deps.Embed, deps.Lineage, deps.Impact)
+	gqlResolver := graphql.NewResolver(logger, s, deps.Graph, deps.Embed, deps.Lineage, deps.Impact, deps.EdgeDirections, deps.GraphQL)
 	gqlSrv := handler.New(graphql.NewExecutableSchema(graphql.Config{Resolvers: gqlResolver}))
 	gqlSrv.SetErrorPresenter(graphql.ErrorPresenter())
 	gqlSrv.AddTransport(transport.POST{})
 	gqlSrv.Use(extension.Introspection{})
+	gqlSrv.Use(graphql.NewQueryBudget(deps.GraphQL))
 
 	r.With(authHandler).Handle("/graphql", gqlSrv)
 	r.Get("/graphql/playground", playground.Handler("Lattice", "/graphql"))
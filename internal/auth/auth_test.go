@@ -190,3 +190,80 @@ func TestRequireScope_NoPrincipal(t *testing.T) {
 		t.Fatalf("got status %d, want 401", rec.Code)
 	}
 }
+
+func TestPrincipalFromClaims_CustomPaths(t *testing.T) {
+	raw := map[string]any{
+		"sub":   "user-7",
+		"email": "ops@example.com",
+		"azp":   "lattice-cli",
+		"scope": "openid lattice:read",
+		"org": map[string]any{
+			"id": "11111111-2222-3333-4444-555555555555",
+		},
+		"realm_access": map[string]any{
+			"roles": []any{"offline_access", "platform-admins"},
+		},
+	}
+	m := ClaimMapping{TenantClaim: "org.id", RolesClaim: "realm_access.roles", AdminRole: "platform-admins"}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := principalFromClaims(raw, "https://idp.example.com", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TenantID != uuid.MustParse("11111111-2222-3333-4444-555555555555") {
+		t.Errorf("got tenant %v", p.TenantID)
+	}
+	if !p.HasRole("platform-admins") || !p.HasRole("offline_access") {
+		t.Errorf("expected IdP roles kept, got %v", p.Roles)
+	}
+	if !p.IsAdmin() {
+		t.Error("expected the configured admin role to make the principal an admin")
+	}
+	if !p.HasScope("lattice:read") || p.Sub != "user-7" || p.ClientID != "lattice-cli" || p.Issuer != "https://idp.example.com" {
+		t.Errorf("unexpected principal %+v", p)
+	}
+
+	// Without the admin role, the same token is not an admin; roles may be a flat string.
+	raw["realm_access"] = map[string]any{"roles": "viewer editor"}
+	p, err = principalFromClaims(raw, "", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsAdmin() || !p.HasRole("viewer") || !p.HasRole("editor") {
+		t.Errorf("expected non-admin with two roles, got %v", p.Roles)
+	}
+
+	// With a custom admin role, the built-in role name in the token grants nothing.
+	raw["realm_access"] = map[string]any{"roles": []any{"lattice_admin"}}
+	p, err = principalFromClaims(raw, "", m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsAdmin() || p.HasRole("lattice_admin") {
+		t.Errorf("expected lattice_admin to be ignored when platform-admins is the admin role, got %v", p.Roles)
+	}
+
+	// The default mapping looks for tenant_id, which this token lacks.
+	if _, err := principalFromClaims(raw, "", DefaultClaimMapping()); err == nil || err.Error() != "missing tenant_id claim" {
+		t.Errorf("expected missing tenant_id error, got %v", err)
+	}
+}
+
+func TestClaimMappingValidate(t *testing.T) {
+	if err := DefaultClaimMapping().Validate(); err != nil {
+		t.Errorf("default mapping should be valid: %v", err)
+	}
+	for _, m := range []ClaimMapping{
+		{TenantClaim: "", RolesClaim: "roles", AdminRole: "admin"},
+		{TenantClaim: "org..id", RolesClaim: "roles", AdminRole: "admin"},
+		{TenantClaim: "tenant", RolesClaim: "realm_access.", AdminRole: "admin"},
+		{TenantClaim: "tenant", RolesClaim: "roles", AdminRole: " "},
+	} {
+		if err := m.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", m)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RoleAdmin is the role IsAdmin checks. A token is given it when it carries the
+// mapping's admin role, whatever the IdP calls that role, and only then: with another
+// admin role configured, a lattice_admin role in the token grants nothing.
+const RoleAdmin = "lattice_admin"
+
+// ClaimMapping says where an IdP puts the tenant and roles in its tokens. Claim paths are
+// dot-separated, so "realm_access.roles" reads {"realm_access": {"roles": [...]}}.
+type ClaimMapping struct {
+	TenantClaim string // claim holding the tenant UUID
+	RolesClaim  string // claim holding the roles: a string array or a space-separated string
+	AdminRole   string // role that makes a principal an admin
+}
+
+// DefaultClaimMapping matches the Keycloak realm shipped with Lattice.
+func DefaultClaimMapping() ClaimMapping {
+	return ClaimMapping{
+		TenantClaim: "tenant_id",
+		RolesClaim:  "realm_access.roles",
+		AdminRole:   RoleAdmin,
+	}
+}
+
+// Validate reports a mapping that could never match a claim.
+func (m ClaimMapping) Validate() error {
+	for _, p := range []struct{ name, path string }{
+		{"tenant claim", m.TenantClaim},
+		{"roles claim", m.RolesClaim},
+	} {
+		if p.path == "" {
+			return fmt.Errorf("%s is empty", p.name)
+		}
+		for _, seg := range strings.Split(p.path, ".") {
+			if strings.TrimSpace(seg) == "" {
+				return fmt.Errorf("%s %q has an empty path segment", p.name, p.path)
+			}
+		}
+	}
+	if strings.TrimSpace(m.AdminRole) == "" {
+		return fmt.Errorf("admin role is empty")
+	}
+	return nil
+}
+
+// principalFromClaims builds the Principal for a verified token's claims.
+func principalFromClaims(raw map[string]any, issuer string, m ClaimMapping) (*Principal, error) {
+	tenant, _ := lookupClaim(raw, m.TenantClaim).(string)
+	if tenant == "" {
+		return nil, fmt.Errorf("missing %s claim", m.TenantClaim)
+	}
+	tenantID, err := uuid.Parse(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s claim: %w", m.TenantClaim, err)
+	}
+
+	scopes := make(map[string]bool)
+	for _, s := range strings.Fields(stringClaim(raw, "scope")) {
+		scopes[s] = true
+	}
+	// Also parse lattice-specific scopes from custom claim
+	for _, s := range strings.Fields(stringClaim(raw, "lattice_scopes")) {
+		scopes[s] = true
+	}
+
+	roles := make(map[string]bool)
+	switch v := lookupClaim(raw, m.RolesClaim).(type) {
+	case []any:
+		for _, r := range v {
+			if s, ok := r.(string); ok && s != "" {
+				roles[s] = true
+			}
+		}
+	case string:
+		for _, r := range strings.Fields(v) {
+			roles[r] = true
+		}
+	}
+	admin := roles[m.AdminRole]
+	delete(roles, RoleAdmin)
+	if admin {
+		roles[RoleAdmin] = true
+	}
+
+	return &Principal{
+		Sub:      stringClaim(raw, "sub"),
+		TenantID: tenantID,
+		Scopes:   scopes,
+		Roles:    roles,
+		ClientID: stringClaim(raw, "azp"),
+		Issuer:   issuer,
+		Email:    stringClaim(raw, "email"),
+	}, nil
+}
+
+// lookupClaim follows a dot-separated path through nested claim objects, returning nil
+// when any step is missing.
+func lookupClaim(raw map[string]any, path string) any {
+	var cur any = raw
+	for _, seg := range strings.Split(path, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[seg]
+	}
+	return cur
+}
+
+func stringClaim(raw map[string]any, name string) string {
+	s, _ := raw[name].(string)
+	return s
+}
@@ -55,9 +55,9 @@ func (p *Principal) HasAnyScope(scopes ...string) bool {
 	return false
 }
 
-// IsAdmin returns true if the principal has the lattice_admin role.
+// IsAdmin returns true if the principal has the lattice_admin role (RoleAdmin).
 func (p *Principal) IsAdmin() bool {
-	return p.Roles["lattice_admin"]
+	return p.Roles[RoleAdmin]
 }
 
 // HasRole returns true if the principal has the given role.
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
-	"github.com/google/uuid"
 )
 
 // Verifier validates JWTs using OIDC discovery and JWKS.
@@ -16,13 +15,19 @@ type Verifier struct {
 	provider *oidc.Provider
 	verifier *oidc.IDTokenVerifier
 	audience string
+	claims   ClaimMapping
 }
 
 // NewVerifier creates a Verifier using OIDC discovery from the issuer URL.
 // publicIssuer optionally specifies the expected token issuer when it differs
 // from the discovery URL (e.g. in Docker where discovery uses http://keycloak:8081
-// but tokens contain iss: http://localhost:8081).
-func NewVerifier(ctx context.Context, issuerURL, publicIssuer, audience string) (*Verifier, error) {
+// but tokens contain iss: http://localhost:8081). claims says where tokens carry the
+// tenant and roles; it is validated before discovery.
+func NewVerifier(ctx context.Context, issuerURL, publicIssuer, audience string, claims ClaimMapping) (*Verifier, error) {
+	if err := claims.Validate(); err != nil {
+		return nil, fmt.Errorf("claim mapping: %w", err)
+	}
+
 	if publicIssuer != "" && publicIssuer != issuerURL {
 		// Tell go-oidc to accept tokens whose iss claim matches publicIssuer
 		// even though discovery is fetched from issuerURL.
@@ -42,24 +47,10 @@ func NewVerifier(ctx context.Context, issuerURL, publicIssuer, audience string)
 		provider: provider,
 		verifier: verifier,
 		audience: audience,
+		claims:   claims,
 	}, nil
 }
 
-// claims represents the JWT claims we extract.
-type claims struct {
-	Sub             string      `json:"sub"`
-	Email           string      `json:"email"`
-	TenantID        string      `json:"tenant_id"`
-	Scope           string      `json:"scope"`
-	LatticeScopes string      `json:"lattice_scopes"`
-	Azp             string      `json:"azp"`
-	RealmAccess     realmAccess `json:"realm_access"`
-}
-
-type realmAccess struct {
-	Roles []string `json:"roles"`
-}
-
 // VerifyToken verifies a raw Bearer token string and returns the Principal
 // and the token's expiry time.
 func (v *Verifier) VerifyToken(ctx context.Context, rawToken string) (*Principal, time.Time, error) {
@@ -68,43 +59,16 @@ func (v *Verifier) VerifyToken(ctx context.Context, rawToken string) (*Principal
 		return nil, time.Time{}, fmt.Errorf("token verification failed: %w", err)
 	}
 
-	var c claims
-	if err := token.Claims(&c); err != nil {
+	var raw map[string]any
+	if err := token.Claims(&raw); err != nil {
 		return nil, time.Time{}, fmt.Errorf("failed to parse claims: %w", err)
 	}
 
-	if c.TenantID == "" {
-		return nil, time.Time{}, fmt.Errorf("missing tenant_id claim")
-	}
-
-	tenantID, err := uuid.Parse(c.TenantID)
+	p, err := principalFromClaims(raw, token.Issuer, v.claims)
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("invalid tenant_id claim: %w", err)
-	}
-
-	scopes := make(map[string]bool)
-	for _, s := range strings.Fields(c.Scope) {
-		scopes[s] = true
-	}
-	// Also parse lattice-specific scopes from custom claim
-	for _, s := range strings.Fields(c.LatticeScopes) {
-		scopes[s] = true
+		return nil, time.Time{}, err
 	}
-
-	roles := make(map[string]bool)
-	for _, r := range c.RealmAccess.Roles {
-		roles[r] = true
-	}
-
-	return &Principal{
-		Sub:      c.Sub,
-		TenantID: tenantID,
-		Scopes:   scopes,
-		Roles:    roles,
-		ClientID: c.Azp,
-		Issuer:   token.Issuer,
-		Email:    c.Email,
-	}, token.Expiry, nil
+	return p, token.Expiry, nil
 }
 
 // VerifyRequest extracts and verifies the Bearer token from the request.
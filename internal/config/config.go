@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,12 +14,129 @@ type Config struct {
 	Neo4j      Neo4jConfig
 	Bedrock    BedrockConfig
 	OpenRouter OpenRouterConfig
+	Embedding  EmbeddingConfig
 	Valkey     ValkeyConfig
 	MinIO      MinIOConfig
 	S3         S3Config
+	GitHub     GitHubConfig
 	MCP        MCPConfig
 	Auth       AuthConfig
 	Oracle     OracleConfig
+	Parser     ParserConfig
+	Resolver   ResolverConfig
+	GraphQL    GraphQLConfig
+	Retention  RetentionConfig
+	Analytics  AnalyticsConfig
+	Lineage    LineageConfig
+	Impact     ImpactConfig
+	Scheduler  SchedulerConfig
+}
+
+// RetentionConfig controls how long soft-deleted projects stay restorable.
+type RetentionConfig struct {
+	SoftDelete    time.Duration // SOFT_DELETE_RETENTION_HOURS: restore window before purge (default: 720, 0 keeps forever)
+	PurgeInterval time.Duration // SOFT_DELETE_PURGE_INTERVAL_MINS: how often the worker purges (default: 60)
+}
+
+// SchedulerConfig controls the scheduler service: how often project index schedules are
+// checked and where push webhooks are received.
+type SchedulerConfig struct {
+	Addr          string        // SCHEDULER_ADDR: webhook listen address (default: :8091)
+	TickInterval  time.Duration // SCHEDULER_TICK_SECS: how often index schedules are checked (default: 60)
+	WebhookSecret string        // WEBHOOK_SECRET: GitHub signing secret and GitLab token; webhooks are refused without it
+}
+
+// AnalyticsConfig controls when graph centrality is approximated instead of computed exactly,
+// which edges count as low-trust inference, and how large a project may be for community
+// detection.
+type AnalyticsConfig struct {
+	SamplingThreshold      int     // ANALYTICS_SAMPLING_THRESHOLD: nodes above which PageRank is sampled (default: 1000000, 0 disables)
+	SampleWalks            int     // ANALYTICS_SAMPLE_WALKS: random walks used to estimate PageRank (default: 2000000)
+	SampleSeed             int64   // ANALYTICS_SAMPLE_SEED: seeds the walks and pivots so reruns agree (default: 1)
+	BetweennessThreshold   int     // ANALYTICS_BETWEENNESS_THRESHOLD: nodes above which betweenness is sampled (default: 5000, 0 disables)
+	BetweennessPivots      int     // ANALYTICS_BETWEENNESS_PIVOTS: source nodes used to estimate betweenness (default: 1000)
+	LowConfidenceThreshold float64 // ANALYTICS_LOW_CONFIDENCE_THRESHOLD: edge confidence below which a link is low-trust (default: 0.8)
+	MaxCommunityNodes      int     // ANALYTICS_MAX_COMMUNITY_NODES: symbols above which community detection is skipped (default: 50000, 0 never skips)
+}
+
+// LineageConfig controls how lineage queries read edges.
+type LineageConfig struct {
+	// LINEAGE_EDGE_DIRECTIONS overrides whether an edge type's target is upstream or
+	// downstream of its source, e.g. "uses_table=upstream" (unset types keep their defaults)
+	EdgeDirections map[string]string
+}
+
+// ImpactConfig controls how impact analysis rates the blast radius of a change.
+type ImpactConfig struct {
+	// IMPACT_SEVERITY_THRESHOLDS overrides the dependent counts from which a change is
+	// rated medium, high or critical, e.g. "critical_direct=20,high_transitive=30", and
+	// whether crossing languages raises the rating, "cross_language=false"
+	SeverityThresholds map[string]string
+}
+
+// EmbeddingConfig selects the embedding provider and controls how symbol text longer
+// than the embedding model accepts is fitted to its input limit.
+type EmbeddingConfig struct {
+	MaxInputTokens int    // EMBED_MAX_INPUT_TOKENS: longest input sent to the model (default: 8000, 0 disables)
+	InputStrategy  string // EMBED_INPUT_STRATEGY: truncate, or chunk to embed pieces and average them (default: truncate)
+
+	// EMBEDDING_PROVIDER: local embeds on this host and is chosen ahead of OpenRouter and
+	// Bedrock; empty auto-selects from the credentials set
+	Provider string
+
+	LocalModelPath   string // EMBEDDING_LOCAL_MODEL_PATH: ONNX sentence-transformer model, e.g. all-MiniLM-L6-v2/model.onnx
+	LocalVocabPath   string // EMBEDDING_LOCAL_VOCAB_PATH: WordPiece vocab.txt (default: vocab.txt beside the model)
+	LocalRuntimePath string // EMBEDDING_LOCAL_RUNTIME_PATH: onnxruntime shared library (default: the loader's search path)
+	LocalDimensions  int    // EMBEDDING_LOCAL_DIMENSIONS: width of the model's vectors (default: 384)
+	LocalMaxTokens   int    // EMBEDDING_LOCAL_MAX_TOKENS: model sequence length, including [CLS] and [SEP] (default: 256)
+}
+
+// GraphQLConfig holds per-request limits for the GraphQL API.
+type GraphQLConfig struct {
+	MaxPageSize int // GRAPHQL_MAX_PAGE_SIZE: largest `first` on paginated fields (default: 500)
+	MaxDepth    int // GRAPHQL_MAX_DEPTH: deepest field nesting accepted (default: 10)
+	MaxFields   int // GRAPHQL_MAX_FIELDS: fields an operation may select (default: 500)
+}
+
+// ParserConfig holds limits applied by the source parsers during ingestion.
+type ParserConfig struct {
+	TSQLMaxNestingDepth    int // TSQL_MAX_NESTING_DEPTH (default: 128, 0 disables)
+	TSQLMaxStatementTokens int // TSQL_MAX_STATEMENT_TOKENS (default: 100000, 0 disables)
+
+	// JS_PATTERN_CONFIDENCE overrides per-pattern ORM/DB reference confidence in the
+	// JS/TS parser, e.g. "prisma=0.7,knex=0.95" (unset patterns keep their defaults)
+	JSPatternConfidence map[string]string
+
+	// PARSER_DETECT_MIN_CONFIDENCE is the confidence content-based language detection needs
+	// to parse a file without a registered extension (default: 0.8)
+	DetectMinConfidence float64
+
+	// PARSER_MAX_SYMBOLS_PER_FILE caps the symbols kept from one file; larger files keep
+	// their top-level symbols and are tagged oversized (default: 20000)
+	MaxSymbolsPerFile int
+
+	// PARSE_CONCURRENCY is how many ingestion jobs a worker runs at once, each with its
+	// own parser instances (default: 1)
+	Concurrency int
+
+	// PARSE_COALESCE_WINDOW_SECS is how long after a push its queued run takes in later
+	// pushes to the same source, which are then cancelled and indexed by that run, until a
+	// worker starts it (default: 600, 0 queues a run for each push)
+	CoalesceWindow time.Duration
+
+	// PARSER_PROJECT_SYMBOL_WARN flags a project whose run reaches this many symbols
+	// (default: 2000000); PARSER_PROJECT_SYMBOL_LIMIT aborts the run past this many
+	// (default: 10000000). 0 disables either.
+	ProjectSymbolWarn  int
+	ProjectSymbolLimit int
+}
+
+// ResolverConfig holds settings for cross-file symbol resolution.
+type ResolverConfig struct {
+	IgnoreSymbols   []string // RESOLVER_IGNORE_SYMBOLS: comma-separated "pattern" or "language:pattern", added to the built-in ignores
+	TablePrecedence []string // RESOLVER_TABLE_PRECEDENCE: table mapping sources, most authoritative first (default: attribute,orm,sql)
+	TableConflict   string   // RESOLVER_TABLE_CONFLICT: "drop" or "demote" outranked table mappings (default: drop)
+	RouteCase       string   // RESOLVER_ROUTE_CASE: "insensitive" or "sensitive" API route path matching (default: insensitive)
 }
 
 // OracleConfig holds configuration for the LLM-powered Oracle feature.
@@ -32,12 +150,22 @@ type AuthConfig struct {
 	IssuerURL     string // Discovery URL (may be internal, e.g. http://keycloak:8081/realms/lattice)
 	PublicIssuer  string // Token issuer claim (browser-facing, e.g. http://localhost:8081/realms/lattice)
 	Audience      string
+
+	// Where tokens carry the tenant and roles, as dot-separated claim paths, and the role
+	// that makes a principal an admin: AUTH_TENANT_CLAIM (default: tenant_id),
+	// AUTH_ROLES_CLAIM (default: realm_access.roles), AUTH_ADMIN_ROLE (default: lattice_admin)
+	TenantClaim string
+	RolesClaim  string
+	AdminRole   string
 }
 
 // MCPConfig holds the MCP server listen configuration.
 type MCPConfig struct {
 	Addr    string // Listen address (e.g. ":8080"). Env: MCP_ADDR.
 	BaseURL string // Public base URL for RFC 9728 resource metadata. Env: MCP_BASE_URL.
+	// NavigationRulesFile is a JSON file of navigation-hint rules evaluated ahead of the
+	// built-in ones. Env: MCP_NAVIGATION_RULES_FILE.
+	NavigationRulesFile string
 }
 
 type ServerConfig struct {
@@ -56,6 +184,12 @@ type DatabaseConfig struct {
 	SSLMode  string
 	MaxConns int32
 	MinConns int32
+
+	EdgeBatchSize int // DB_EDGE_BATCH_SIZE: edges per multi-row insert during ingestion (default: 1000)
+
+	// Server-side statement_timeout budgets; 0 disables.
+	ReadTimeout  time.Duration // DB_READ_TIMEOUT_SECS: API and MCP read queries (default: 25)
+	WriteTimeout time.Duration // DB_WRITE_TIMEOUT_SECS: writes, transactions and ingestion (default: 600)
 }
 
 func (d DatabaseConfig) DSN() string {
@@ -103,7 +237,26 @@ type S3Config struct {
 	Endpoint string // S3_ENDPOINT (for MinIO/LocalStack compatibility)
 }
 
+// GitHubConfig controls the GitHub connector used for git sources on github.com.
+type GitHubConfig struct {
+	Enabled bool   // GITHUB_CONNECTOR_ENABLED: clone github.com sources with the GitHub connector (default: true)
+	Token   string // GITHUB_TOKEN: token for private repositories; public ones clone without it
+}
+
 func Load() (*Config, error) {
+	edgeDirections, err := getEnvMap("LINEAGE_EDGE_DIRECTIONS")
+	if err != nil {
+		return nil, err
+	}
+	severityThresholds, err := getEnvMap("IMPACT_SEVERITY_THRESHOLDS")
+	if err != nil {
+		return nil, err
+	}
+	jsPatternConfidence, err := getEnvMap("JS_PATTERN_CONFIDENCE")
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
 			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
@@ -120,6 +273,10 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 			MaxConns: int32(getEnvInt("DB_MAX_CONNS", 25)),
 			MinConns: int32(getEnvInt("DB_MIN_CONNS", 5)),
+
+			EdgeBatchSize: getEnvInt("DB_EDGE_BATCH_SIZE", 1000),
+			ReadTimeout:   time.Duration(getEnvInt("DB_READ_TIMEOUT_SECS", 25)) * time.Second,
+			WriteTimeout:  time.Duration(getEnvInt("DB_WRITE_TIMEOUT_SECS", 600)) * time.Second,
 		},
 		Neo4j: Neo4jConfig{
 			URI:      getEnv("NEO4J_URI", "bolt://localhost:7687"),
@@ -137,6 +294,17 @@ func Load() (*Config, error) {
 			BaseURLEmbeddings: getEnv("OPENROUTER_BASE_URL_EMBEDDINGS", ""),
 			Dimensions:       getEnvInt("OPENROUTER_DIMENSIONS", 1024),
 		},
+		Embedding: EmbeddingConfig{
+			MaxInputTokens: getEnvInt("EMBED_MAX_INPUT_TOKENS", 8000),
+			InputStrategy:  getEnv("EMBED_INPUT_STRATEGY", "truncate"),
+
+			Provider:         getEnv("EMBEDDING_PROVIDER", ""),
+			LocalModelPath:   getEnv("EMBEDDING_LOCAL_MODEL_PATH", ""),
+			LocalVocabPath:   getEnv("EMBEDDING_LOCAL_VOCAB_PATH", ""),
+			LocalRuntimePath: getEnv("EMBEDDING_LOCAL_RUNTIME_PATH", ""),
+			LocalDimensions:  getEnvInt("EMBEDDING_LOCAL_DIMENSIONS", 384),
+			LocalMaxTokens:   getEnvInt("EMBEDDING_LOCAL_MAX_TOKENS", 256),
+		},
 		Valkey: ValkeyConfig{
 			Addr:     getEnv("VALKEY_ADDR", "localhost:6379"),
 			Password: getEnv("VALKEY_PASSWORD", ""),
@@ -155,20 +323,74 @@ func Load() (*Config, error) {
 			Prefix:   getEnv("S3_PREFIX", ""),
 			Endpoint: getEnv("S3_ENDPOINT", ""),
 		},
+		GitHub: GitHubConfig{
+			Enabled: getEnvBool("GITHUB_CONNECTOR_ENABLED", true),
+			Token:   getEnv("GITHUB_TOKEN", ""),
+		},
 		MCP: MCPConfig{
-			Addr:    getEnv("MCP_ADDR", ":8080"),
-			BaseURL: getEnv("MCP_BASE_URL", ""),
+			Addr:                getEnv("MCP_ADDR", ":8080"),
+			BaseURL:             getEnv("MCP_BASE_URL", ""),
+			NavigationRulesFile: getEnv("MCP_NAVIGATION_RULES_FILE", ""),
 		},
 		Auth: AuthConfig{
 			Enabled:      getEnvBool("AUTH_ENABLED", false),
 			IssuerURL:    getEnv("AUTH_ISSUER_URL", ""),
 			PublicIssuer: getEnv("AUTH_PUBLIC_ISSUER", ""),
 			Audience:     getEnv("AUTH_AUDIENCE", "lattice"),
+			TenantClaim:  getEnv("AUTH_TENANT_CLAIM", "tenant_id"),
+			RolesClaim:   getEnv("AUTH_ROLES_CLAIM", "realm_access.roles"),
+			AdminRole:    getEnv("AUTH_ADMIN_ROLE", "lattice_admin"),
 		},
 		Oracle: OracleConfig{
 			Model:   getEnv("ORACLE_MODEL", "minimax/minimax-m1"),
 			Enabled: getEnvBool("ORACLE_ENABLED", false),
 		},
+		Parser: ParserConfig{
+			TSQLMaxNestingDepth:    getEnvInt("TSQL_MAX_NESTING_DEPTH", 128),
+			TSQLMaxStatementTokens: getEnvInt("TSQL_MAX_STATEMENT_TOKENS", 100000),
+			JSPatternConfidence:    jsPatternConfidence,
+			DetectMinConfidence:    getEnvFloat("PARSER_DETECT_MIN_CONFIDENCE", 0.8),
+			MaxSymbolsPerFile:      getEnvInt("PARSER_MAX_SYMBOLS_PER_FILE", 20000),
+			Concurrency:            getEnvInt("PARSE_CONCURRENCY", 1),
+			CoalesceWindow:         time.Duration(getEnvInt("PARSE_COALESCE_WINDOW_SECS", 600)) * time.Second,
+			ProjectSymbolWarn:      getEnvInt("PARSER_PROJECT_SYMBOL_WARN", 2000000),
+			ProjectSymbolLimit:     getEnvInt("PARSER_PROJECT_SYMBOL_LIMIT", 10000000),
+		},
+		Resolver: ResolverConfig{
+			IgnoreSymbols:   getEnvList("RESOLVER_IGNORE_SYMBOLS"),
+			TablePrecedence: getEnvList("RESOLVER_TABLE_PRECEDENCE"),
+			TableConflict:   getEnv("RESOLVER_TABLE_CONFLICT", "drop"),
+			RouteCase:       getEnv("RESOLVER_ROUTE_CASE", "insensitive"),
+		},
+		GraphQL: GraphQLConfig{
+			MaxPageSize: getEnvInt("GRAPHQL_MAX_PAGE_SIZE", 500),
+			MaxDepth:    getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+			MaxFields:   getEnvInt("GRAPHQL_MAX_FIELDS", 500),
+		},
+		Analytics: AnalyticsConfig{
+			SamplingThreshold:      getEnvInt("ANALYTICS_SAMPLING_THRESHOLD", 1000000),
+			SampleWalks:            getEnvInt("ANALYTICS_SAMPLE_WALKS", 2000000),
+			SampleSeed:             int64(getEnvInt("ANALYTICS_SAMPLE_SEED", 1)),
+			BetweennessThreshold:   getEnvInt("ANALYTICS_BETWEENNESS_THRESHOLD", 5000),
+			BetweennessPivots:      getEnvInt("ANALYTICS_BETWEENNESS_PIVOTS", 1000),
+			LowConfidenceThreshold: getEnvFloat("ANALYTICS_LOW_CONFIDENCE_THRESHOLD", 0.8),
+			MaxCommunityNodes:      getEnvInt("ANALYTICS_MAX_COMMUNITY_NODES", 50000),
+		},
+		Lineage: LineageConfig{
+			EdgeDirections: edgeDirections,
+		},
+		Impact: ImpactConfig{
+			SeverityThresholds: severityThresholds,
+		},
+		Retention: RetentionConfig{
+			SoftDelete:    time.Duration(getEnvInt("SOFT_DELETE_RETENTION_HOURS", 720)) * time.Hour,
+			PurgeInterval: time.Duration(getEnvInt("SOFT_DELETE_PURGE_INTERVAL_MINS", 60)) * time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			Addr:          getEnv("SCHEDULER_ADDR", ":8091"),
+			TickInterval:  time.Duration(getEnvInt("SCHEDULER_TICK_SECS", 60)) * time.Second,
+			WebhookSecret: getEnv("WEBHOOK_SECRET", ""),
+		},
 	}
 	return cfg, nil
 }
@@ -189,6 +411,41 @@ func getEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+// getEnvList splits a comma-separated variable, dropping empty items.
+func getEnvList(key string) []string {
+	var out []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// getEnvMap parses a comma-separated list of key=value pairs. An item that is not a
+// key=value pair is an error, rather than a setting silently left at its default.
+func getEnvMap(key string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, item := range getEnvList(key) {
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%s: %q is not a key=value pair", key, item)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	if v := os.Getenv(key); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
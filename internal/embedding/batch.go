@@ -12,44 +12,72 @@ import (
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
+// symbolEmbeddingWriter is the subset of the store needed to persist embeddings.
+type symbolEmbeddingWriter interface {
+	UpsertSymbolEmbedding(ctx context.Context, arg postgres.UpsertSymbolEmbeddingParams) error
+}
+
 // EmbedSymbols generates and stores embeddings for all symbols in a project
-// that don't already have them. Returns the number of symbols embedded.
-func EmbedSymbols(ctx context.Context, client Embedder, s *store.Store, projectID uuid.UUID, logger *slog.Logger) (int, error) {
+// that don't already have them and whose kind the filter allows. Returns the
+// number of symbols embedded.
+func EmbedSymbols(ctx context.Context, client Embedder, s *store.Store, projectID uuid.UUID, kinds KindFilter, logger *slog.Logger) (int, error) {
 	// Find symbols without embeddings
 	symbols, err := s.ListSymbolsWithoutEmbeddings(ctx, projectID)
 	if err != nil {
 		return 0, fmt.Errorf("list symbols without embeddings: %w", err)
 	}
 
-	if len(symbols) == 0 {
+	embeddable := kinds.Filter(symbols)
+	if skipped := len(symbols) - len(embeddable); skipped > 0 {
+		logger.Info("skipping excluded symbol kinds", slog.Int("skipped", skipped))
+	}
+	if len(embeddable) == 0 {
 		return 0, nil
 	}
 
-	logger.Info("embedding symbols", slog.Int("count", len(symbols)))
+	logger.Info("embedding symbols", slog.Int("count", len(embeddable)))
+	return embedSymbols(ctx, client, s, embeddable)
+}
 
-	// Build text representations
+// EmbedSymbolList generates and stores embeddings for exactly the given symbols.
+// Returns the number of symbols embedded.
+func EmbedSymbolList(ctx context.Context, client Embedder, s *store.Store, symbols []postgres.Symbol) (int, error) {
+	return embedSymbols(ctx, client, s, symbols)
+}
+
+func embedSymbols(ctx context.Context, client Embedder, w symbolEmbeddingWriter, symbols []postgres.Symbol) (int, error) {
+	// Build text representations, fitted to the model's input limit
 	texts := make([]string, len(symbols))
 	for i, sym := range symbols {
 		texts[i] = BuildEmbeddingText(sym)
 	}
+	fitted := inputLimitOf(client).fit(texts)
 
 	// Generate embeddings
-	embeddings, err := client.EmbedBatch(ctx, texts, "search_document")
+	vectors, err := client.EmbedBatch(ctx, fitted.inputs, "search_document")
 	if err != nil {
 		return 0, fmt.Errorf("embed batch: %w", err)
 	}
-
-	if len(embeddings) != len(symbols) {
-		return 0, fmt.Errorf("embedding count mismatch: got %d, expected %d", len(embeddings), len(symbols))
+	embeddings, err := fitted.combine(vectors)
+	if err != nil {
+		return 0, err
 	}
 
 	// Store embeddings
 	for i, sym := range symbols {
+		var handling *string
+		if fitted.handling[i] != "" {
+			handling = &fitted.handling[i]
+		}
 		vec := pgvector.NewVector(embeddings[i])
-		err := s.UpsertSymbolEmbedding(ctx, postgres.UpsertSymbolEmbeddingParams{
-			SymbolID:  sym.ID,
-			Embedding: vec,
-			Model:     client.ModelID(),
+		hash := ContentHash(sym)
+		err := w.UpsertSymbolEmbedding(ctx, postgres.UpsertSymbolEmbeddingParams{
+			SymbolID:      sym.ID,
+			ProjectID:     sym.ProjectID,
+			Embedding:     vec,
+			Model:         client.ModelID(),
+			InputHandling: handling,
+			ContentHash:   &hash,
 		})
 		if err != nil {
 			return i, fmt.Errorf("upsert embedding for %s: %w", sym.QualifiedName, err)
@@ -15,8 +15,9 @@ const maxBatchSize = 96 // Cohere embed API limit
 
 // Client wraps the AWS Bedrock runtime for embedding generation.
 type Client struct {
-	bedrock *bedrockruntime.Client
-	modelID string
+	bedrock    *bedrockruntime.Client
+	modelID    string
+	inputLimit InputLimit
 }
 
 // NewClient creates a new Bedrock embedding client.
@@ -95,4 +96,7 @@ func (c *Client) embedSingle(ctx context.Context, texts []string, inputType stri
 // ModelID returns the Bedrock model identifier.
 func (c *Client) ModelID() string { return c.modelID }
 
+// InputLimit returns the limit symbol text is fitted to before embedding.
+func (c *Client) InputLimit() InputLimit { return c.inputLimit }
+
 func strPtr(s string) *string { return &s }
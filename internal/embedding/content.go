@@ -1,12 +1,32 @@
 package embedding
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
+// ContentHash identifies the parts of a symbol its embedding text is built from, so an
+// unchanged symbol can keep its vector when a re-index recreates it.
+func ContentHash(sym postgres.Symbol) string {
+	h := sha256.New()
+	for _, part := range []string{strings.ToLower(sym.Kind), sym.Name, sym.QualifiedName, deref(sym.Signature), deref(sym.DocComment)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // BuildEmbeddingText creates the text representation of a symbol for embedding.
 // Different symbol kinds get different text formats to maximize semantic quality.
 func BuildEmbeddingText(sym postgres.Symbol) string {
@@ -48,6 +68,16 @@ func BuildEmbeddingText(sym postgres.Symbol) string {
 		}
 		return text
 
+	case "sequence":
+		return fmt.Sprintf("Sequence %s", sym.QualifiedName)
+
+	case "index":
+		text := fmt.Sprintf("Index %s", sym.QualifiedName)
+		if sym.Signature != nil && *sym.Signature != "" {
+			text += fmt.Sprintf(" %s", *sym.Signature)
+		}
+		return text
+
 	case "column":
 		text := fmt.Sprintf("Column %s", sym.QualifiedName)
 		if sym.Signature != nil && *sym.Signature != "" {
@@ -13,13 +13,46 @@ type Embedder interface {
 	ModelID() string
 }
 
-// NewEmbedder auto-selects provider: OpenRouter (if API key set) > Bedrock (if region set) > nil.
-func NewEmbedder(cfg *config.Config) (Embedder, error) {
+// ColumnSource reads the width of the symbol_embeddings vector column, as store.Store
+// does from the database schema.
+type ColumnSource interface {
+	GetEmbeddingColumnDimensions(ctx context.Context) (int32, error)
+}
+
+// NewEmbedder auto-selects provider: local (if EMBEDDING_PROVIDER=local) > OpenRouter (if API
+// key set) > Bedrock (if region set) > nil. Symbol text is fitted to the input limit in
+// cfg.Embedding before it is embedded. The local provider pads its vectors to the width
+// of the column, read from columns.
+func NewEmbedder(ctx context.Context, cfg *config.Config, columns ColumnSource) (Embedder, error) {
+	limit := InputLimit{MaxTokens: cfg.Embedding.MaxInputTokens, Strategy: cfg.Embedding.InputStrategy}
+
+	switch cfg.Embedding.Provider {
+	case "":
+	case "local":
+		column, err := columns.GetEmbeddingColumnDimensions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read embedding column width: %w", err)
+		}
+		client, err := NewLocalClient(cfg.Embedding, int(column))
+		if err != nil {
+			return nil, fmt.Errorf("local embedder: %w", err)
+		}
+		// The model reads no further than its sequence length
+		client.inputLimit = limit
+		if limit.MaxTokens <= 0 || limit.MaxTokens > client.maxTokens {
+			client.inputLimit.MaxTokens = client.maxTokens
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDING_PROVIDER %q (want local, or unset to auto-select)", cfg.Embedding.Provider)
+	}
+
 	if cfg.OpenRouter.APIKey != "" {
 		client, err := NewOpenRouterClient(cfg.OpenRouter)
 		if err != nil {
 			return nil, fmt.Errorf("openrouter client: %w", err)
 		}
+		client.inputLimit = limit
 		return client, nil
 	}
 
@@ -28,6 +61,7 @@ func NewEmbedder(cfg *config.Config) (Embedder, error) {
 		if err != nil {
 			return nil, fmt.Errorf("bedrock client: %w", err)
 		}
+		client.inputLimit = limit
 		return client, nil
 	}
 
@@ -0,0 +1,141 @@
+package embedding
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Strategies for text longer than the model accepts.
+const (
+	InputTruncate = "truncate" // embed the head of the text
+	InputChunk    = "chunk"    // embed consecutive pieces and average their vectors
+)
+
+// How an input was fitted, recorded as symbol_embeddings.input_handling.
+const (
+	handlingTruncated = "truncated"
+	handlingChunked   = "chunked"
+)
+
+// charsPerToken estimates token counts without the model's tokenizer. Code and SQL
+// tokenize denser than prose, so this errs towards short inputs.
+const charsPerToken = 3
+
+// maxInputChunks caps the pieces one text is split into; text beyond them is dropped
+// and the input counts as truncated.
+const maxInputChunks = 16
+
+// InputLimit fits symbol text to an embedding model's input length so no request is
+// rejected for being too long.
+type InputLimit struct {
+	MaxTokens int    // longest input sent to the model; 0 disables the limit
+	Strategy  string // InputTruncate (default) or InputChunk
+}
+
+// inputLimited is implemented by embedders that know their model's input limit.
+type inputLimited interface {
+	InputLimit() InputLimit
+}
+
+// inputLimitOf returns the client's input limit, or no limit.
+func inputLimitOf(client Embedder) InputLimit {
+	if l, ok := client.(inputLimited); ok {
+		return l.InputLimit()
+	}
+	return InputLimit{}
+}
+
+// maxChars is the longest input in bytes, or 0 when unlimited.
+func (l InputLimit) maxChars() int {
+	if l.MaxTokens <= 0 {
+		return 0
+	}
+	return l.MaxTokens * charsPerToken
+}
+
+// fittedInputs maps texts to the inputs actually sent to the model.
+type fittedInputs struct {
+	inputs   []string
+	spans    [][2]int // inputs[spans[i][0]:spans[i][1]] belong to text i
+	handling []string // how text i was fitted; "" when it was sent whole
+}
+
+// fit splits or truncates every text longer than the limit.
+func (l InputLimit) fit(texts []string) fittedInputs {
+	out := fittedInputs{spans: make([][2]int, len(texts)), handling: make([]string, len(texts))}
+	limit := l.maxChars()
+	for i, text := range texts {
+		start := len(out.inputs)
+		switch {
+		case limit == 0 || len(text) <= limit:
+			out.inputs = append(out.inputs, text)
+		case l.Strategy == InputChunk:
+			rest := text
+			for len(rest) > 0 && len(out.inputs)-start < maxInputChunks {
+				n := cutAt(rest, limit)
+				out.inputs = append(out.inputs, rest[:n])
+				rest = rest[n:]
+			}
+			out.handling[i] = handlingChunked
+			if len(rest) > 0 {
+				out.handling[i] = handlingTruncated
+			}
+		default:
+			out.inputs = append(out.inputs, text[:cutAt(text, limit)])
+			out.handling[i] = handlingTruncated
+		}
+		out.spans[i] = [2]int{start, len(out.inputs)}
+	}
+	return out
+}
+
+// combine turns the model's vectors for the fitted inputs back into one vector per text,
+// averaging the vectors of chunked texts and scaling the mean back to unit length.
+func (f fittedInputs) combine(vectors [][]float32) ([][]float32, error) {
+	if len(vectors) != len(f.inputs) {
+		return nil, fmt.Errorf("embedding count mismatch: got %d, expected %d", len(vectors), len(f.inputs))
+	}
+	out := make([][]float32, len(f.spans))
+	for i, span := range f.spans {
+		if span[1]-span[0] == 1 {
+			out[i] = vectors[span[0]]
+			continue
+		}
+		dim := len(vectors[span[0]])
+		sum := make([]float64, dim)
+		for _, v := range vectors[span[0]:span[1]] {
+			if len(v) != dim {
+				return nil, fmt.Errorf("chunk embeddings differ in dimension: %d and %d", dim, len(v))
+			}
+			for j, x := range v {
+				sum[j] += float64(x)
+			}
+		}
+		var norm float64
+		for _, x := range sum {
+			norm += x * x
+		}
+		norm = math.Sqrt(norm)
+		mean := make([]float32, dim)
+		for j, x := range sum {
+			if norm > 0 {
+				mean[j] = float32(x / norm)
+			}
+		}
+		out[i] = mean
+	}
+	return out, nil
+}
+
+// cutAt returns the largest prefix length of s no longer than n bytes that ends on a
+// rune boundary.
+func cutAt(s string, n int) int {
+	if len(s) <= n {
+		return len(s)
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
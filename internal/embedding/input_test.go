@@ -0,0 +1,111 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const testDimensions = 8
+
+// limitedEmbedder rejects inputs longer than its model accepts, as the providers do.
+type limitedEmbedder struct {
+	limit  InputLimit
+	inputs []string
+}
+
+func (e *limitedEmbedder) EmbedBatch(_ context.Context, texts []string, _ string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		if len(text) > e.limit.maxChars() {
+			return nil, fmt.Errorf("input %d is %d bytes, over the model limit", i, len(text))
+		}
+		e.inputs = append(e.inputs, text)
+		vec := make([]float32, testDimensions)
+		vec[len(e.inputs)%testDimensions] = 1 // a distinct unit vector per input
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func (e *limitedEmbedder) ModelID() string        { return "test-model" }
+func (e *limitedEmbedder) InputLimit() InputLimit { return e.limit }
+
+// paramsWriter records the stored embedding rows.
+type paramsWriter struct {
+	rows []postgres.UpsertSymbolEmbeddingParams
+}
+
+func (w *paramsWriter) UpsertSymbolEmbedding(_ context.Context, arg postgres.UpsertSymbolEmbeddingParams) error {
+	w.rows = append(w.rows, arg)
+	return nil
+}
+
+func longProcedure() postgres.Symbol {
+	sig := "(" + strings.Repeat("@param_ünïcode INT, ", 400) + ")"
+	return postgres.Symbol{ID: uuid.New(), Name: "usp_Huge", QualifiedName: "dbo.usp_Huge", Kind: "procedure", Signature: &sig}
+}
+
+func TestEmbedSymbols_OverLengthInput(t *testing.T) {
+	short := postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table"}
+
+	for _, tc := range []struct {
+		strategy     string
+		wantHandling string
+		wantInputs   int
+	}{
+		{InputTruncate, "truncated", 2},
+		{InputChunk, "chunked", 11},
+	} {
+		t.Run(tc.strategy, func(t *testing.T) {
+			client := &limitedEmbedder{limit: InputLimit{MaxTokens: 300, Strategy: tc.strategy}}
+			w := &paramsWriter{}
+			n, err := embedSymbols(context.Background(), client, w, []postgres.Symbol{longProcedure(), short})
+			if err != nil {
+				t.Fatalf("embedSymbols: %v", err)
+			}
+			if n != 2 || len(w.rows) != 2 {
+				t.Fatalf("expected 2 embeddings, got %d (%d rows)", n, len(w.rows))
+			}
+			if len(client.inputs) != tc.wantInputs {
+				t.Errorf("sent %d inputs, want %d", len(client.inputs), tc.wantInputs)
+			}
+
+			long := w.rows[0]
+			if long.InputHandling == nil || *long.InputHandling != tc.wantHandling {
+				t.Errorf("input handling = %v, want %q", long.InputHandling, tc.wantHandling)
+			}
+			vec := long.Embedding.Slice()
+			if len(vec) != testDimensions {
+				t.Fatalf("vector has %d dimensions, want %d", len(vec), testDimensions)
+			}
+			var norm float64
+			for _, x := range vec {
+				if math.IsNaN(float64(x)) {
+					t.Fatalf("vector contains NaN: %v", vec)
+				}
+				norm += float64(x) * float64(x)
+			}
+			if math.Abs(norm-1) > 1e-5 {
+				t.Errorf("vector norm² = %f, want 1", norm)
+			}
+			if w.rows[1].InputHandling != nil {
+				t.Errorf("short input recorded as %q", *w.rows[1].InputHandling)
+			}
+		})
+	}
+}
+
+func TestInputLimit_ChunkCap(t *testing.T) {
+	text := strings.Repeat("x", 3*(maxInputChunks+2))
+	fitted := InputLimit{MaxTokens: 1, Strategy: InputChunk}.fit([]string{text})
+	if len(fitted.inputs) != maxInputChunks || fitted.handling[0] != "truncated" {
+		t.Errorf("expected %d chunks recorded as truncated, got %d (%q)", maxInputChunks, len(fitted.inputs), fitted.handling[0])
+	}
+}
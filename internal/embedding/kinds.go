@@ -0,0 +1,70 @@
+package embedding
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// DefaultExcludedKinds are symbol kinds that are not embedded unless a project opts in.
+// On large schemas they dominate embedding cost while rarely being what a semantic search
+// is looking for. Config keys are repeated in every file that reads them and carry no code.
+var DefaultExcludedKinds = []string{"column", "field", "property", "config_key"}
+
+// KindFilter decides which symbol kinds get embedded. A non-empty Include embeds only
+// those kinds; Exclude is applied after Include.
+type KindFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// DefaultKindFilter embeds every kind except DefaultExcludedKinds.
+func DefaultKindFilter() KindFilter {
+	return KindFilter{Exclude: DefaultExcludedKinds}
+}
+
+// KindFilterFromSettings reads embed_kinds and embed_exclude_kinds from project settings.
+// A missing embed_exclude_kinds keeps the default exclusions unless embed_kinds names the
+// kinds to embed, so {"embed_kinds": ["column"]} embeds columns; an empty list embeds
+// every kind.
+func KindFilterFromSettings(settings []byte) KindFilter {
+	f := DefaultKindFilter()
+	if len(settings) == 0 {
+		return f
+	}
+	var s struct {
+		Include []string  `json:"embed_kinds"`
+		Exclude *[]string `json:"embed_exclude_kinds"`
+	}
+	if json.Unmarshal(settings, &s) != nil {
+		return f
+	}
+	f.Include = s.Include
+	switch {
+	case s.Exclude != nil:
+		f.Exclude = *s.Exclude
+	case len(s.Include) > 0:
+		f.Exclude = nil
+	}
+	return f
+}
+
+// Allows reports whether symbols of the given kind should be embedded.
+func (f KindFilter) Allows(kind string) bool {
+	if len(f.Include) > 0 && !slices.Contains(f.Include, kind) {
+		return false
+	}
+	return !slices.Contains(f.Exclude, kind)
+}
+
+// Filter returns the symbols whose kind is allowed.
+func (f KindFilter) Filter(symbols []postgres.Symbol) []postgres.Symbol {
+	out := make([]postgres.Symbol, 0, len(symbols))
+	for _, sym := range symbols {
+		if f.Allows(sym.Kind) {
+			out = append(out, sym)
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package embedding
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// recordingEmbedder records the texts it was asked to embed.
+type recordingEmbedder struct {
+	texts []string
+}
+
+func (r *recordingEmbedder) EmbedBatch(_ context.Context, texts []string, _ string) ([][]float32, error) {
+	r.texts = append(r.texts, texts...)
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = []float32{1}
+	}
+	return out, nil
+}
+
+func (r *recordingEmbedder) ModelID() string { return "test-model" }
+
+// recordingWriter records the symbols embeddings were stored for.
+type recordingWriter struct {
+	ids []uuid.UUID
+}
+
+func (w *recordingWriter) UpsertSymbolEmbedding(_ context.Context, arg postgres.UpsertSymbolEmbeddingParams) error {
+	w.ids = append(w.ids, arg.SymbolID)
+	return nil
+}
+
+func TestEmbedSymbols_SkipsExcludedKinds(t *testing.T) {
+	table := postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_GetOrders", QualifiedName: "dbo.usp_GetOrders", Kind: "procedure"}
+	column := postgres.Symbol{ID: uuid.New(), Name: "CustomerId", QualifiedName: "dbo.Orders.CustomerId", Kind: "column"}
+	field := postgres.Symbol{ID: uuid.New(), Name: "_total", QualifiedName: "App.Order._total", Kind: "field"}
+	prop := postgres.Symbol{ID: uuid.New(), Name: "Total", QualifiedName: "App.Order.Total", Kind: "property"}
+	symbols := []postgres.Symbol{table, column, proc, field, prop}
+
+	client := &recordingEmbedder{}
+	w := &recordingWriter{}
+	n, err := embedSymbols(context.Background(), client, w, DefaultKindFilter().Filter(symbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || !slices.Equal(w.ids, []uuid.UUID{table.ID, proc.ID}) {
+		t.Errorf("expected only table and procedure embedded, got %d: %v", n, w.ids)
+	}
+	for _, text := range client.texts {
+		for _, excluded := range []postgres.Symbol{column, field, prop} {
+			if text == BuildEmbeddingText(excluded) {
+				t.Errorf("excluded %s %s was sent to the embedder", excluded.Kind, excluded.Name)
+			}
+		}
+	}
+}
+
+func TestKindFilterFromSettings(t *testing.T) {
+	tests := []struct {
+		settings string
+		kind     string
+		want     bool
+	}{
+		{`{}`, "table", true},
+		{`{}`, "column", false},
+		{`{"embed_exclude_kinds": []}`, "column", true},
+		{`{"embed_exclude_kinds": ["view"]}`, "view", false},
+		{`{"embed_exclude_kinds": ["view"]}`, "column", true},
+		{`{"embed_kinds": ["table", "column"]}`, "procedure", false},
+		{`{"embed_kinds": ["table", "column"]}`, "column", true}, // default exclusions only apply without embed_kinds
+		{`{"embed_kinds": ["column"]}`, "column", true},
+		{`{"embed_kinds": ["table", "column"], "embed_exclude_kinds": ["column"]}`, "column", false},
+		{`{"embed_kinds": [], "embed_exclude_kinds": ["view"]}`, "column", true},
+	}
+	for _, tt := range tests {
+		if got := KindFilterFromSettings([]byte(tt.settings)).Allows(tt.kind); got != tt.want {
+			t.Errorf("KindFilterFromSettings(%s).Allows(%q) = %v, want %v", tt.settings, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	doc := "Returns one order"
+	sym := postgres.Symbol{ID: uuid.New(), Name: "GetOrder", QualifiedName: "dbo.GetOrder", Kind: "function", DocComment: &doc}
+	recreated := sym
+	recreated.ID = uuid.New()
+	recreated.StartLine = 40
+	if ContentHash(sym) != ContentHash(recreated) {
+		t.Error("expected the hash to ignore the symbol's ID and position")
+	}
+	edited := sym
+	edited.DocComment = nil
+	if ContentHash(sym) == ContentHash(edited) {
+		t.Error("expected a doc comment change to change the hash")
+	}
+}
@@ -0,0 +1,203 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/config"
+)
+
+const (
+	defaultLocalDimensions = 384 // all-MiniLM-L6-v2
+	defaultLocalMaxTokens  = 256
+	localBatchSize         = 32
+)
+
+// sentenceModel runs a sentence-transformer over a batch of token sequences of equal
+// length. It returns the output tensor flattened with its shape: [batch, seq, dim]
+// token embeddings to be mean-pooled, or [batch, dim] sentence embeddings.
+type sentenceModel interface {
+	run(ids, mask []int64, batch, seqLen int) ([]float32, []int64, error)
+}
+
+// LocalClient implements Embedder with a sentence-transformer run on this host, so no
+// symbol text leaves it. Vectors are mean-pooled over the tokens, L2-normalized and
+// zero-padded to the vector column's width, which leaves cosine distances unchanged.
+type LocalClient struct {
+	model      sentenceModel
+	tokenizer  *wordPiece
+	modelID    string
+	dimensions int // the model's
+	column     int // the vector column's
+	maxTokens  int
+	inputLimit InputLimit
+}
+
+// NewLocalClient loads the ONNX model and WordPiece vocabulary named in cfg, to store
+// vectors in a column of the given width. A model wider than the column is rejected,
+// since its vectors could not be stored.
+func NewLocalClient(cfg config.EmbeddingConfig, column int) (*LocalClient, error) {
+	if cfg.LocalModelPath == "" {
+		return nil, fmt.Errorf("EMBEDDING_LOCAL_MODEL_PATH is required for EMBEDDING_PROVIDER=local")
+	}
+	vocabPath := cfg.LocalVocabPath
+	if vocabPath == "" {
+		vocabPath = filepath.Join(filepath.Dir(cfg.LocalModelPath), "vocab.txt")
+	}
+	tokenizer, err := loadWordPiece(vocabPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := cfg.LocalDimensions
+	if dimensions <= 0 {
+		dimensions = defaultLocalDimensions
+	}
+	if column <= 0 {
+		return nil, fmt.Errorf("the embedding column has no fixed width")
+	}
+	if dimensions > column {
+		return nil, fmt.Errorf("local model produces %d-dimension vectors but the embedding column holds %d; migrate the column to a wider vector", dimensions, column)
+	}
+	maxTokens := cfg.LocalMaxTokens
+	if maxTokens < 2 {
+		maxTokens = defaultLocalMaxTokens
+	}
+
+	model, err := openONNXModel(cfg.LocalModelPath, cfg.LocalRuntimePath)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalClient{
+		model:      model,
+		tokenizer:  tokenizer,
+		modelID:    localModelID(cfg.LocalModelPath),
+		dimensions: dimensions,
+		column:     column,
+		maxTokens:  maxTokens,
+	}, nil
+}
+
+// localModelID names the model after its directory (all-MiniLM-L6-v2/model.onnx) or,
+// for a file not called model.onnx, the file itself.
+func localModelID(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if name == "model" {
+		name = filepath.Base(filepath.Dir(path))
+	}
+	return "local/" + name
+}
+
+// EmbedBatch embeds texts in sub-batches of localBatchSize. Every sequence is padded
+// to the model's full length, so a text's vector does not depend on what it was
+// batched with. The model makes no distinction between queries and documents, so
+// inputType is ignored.
+func (c *LocalClient) EmbedBatch(ctx context.Context, texts []string, inputType string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for i := 0; i < len(texts); i += localBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(i+localBatchSize, len(texts))
+		vectors, err := c.embedSingle(texts[i:end])
+		if err != nil {
+			return nil, fmt.Errorf("embed batch %d: %w", i/localBatchSize, err)
+		}
+		embeddings = append(embeddings, vectors...)
+	}
+	return embeddings, nil
+}
+
+func (c *LocalClient) embedSingle(texts []string) ([][]float32, error) {
+	n, seqLen := len(texts), c.maxTokens
+	ids := make([]int64, 0, n*seqLen)
+	mask := make([]int64, 0, n*seqLen)
+	for _, text := range texts {
+		textIDs, textMask := c.tokenizer.encode(text, seqLen)
+		ids = append(ids, textIDs...)
+		mask = append(mask, textMask...)
+	}
+
+	out, shape, err := c.model.run(ids, mask, n, seqLen)
+	if err != nil {
+		return nil, fmt.Errorf("run model: %w", err)
+	}
+	// The configured width is what the column guard checked, so the model must match it
+	pooled := len(shape) == 2
+	want := []int64{int64(n), int64(seqLen), int64(c.dimensions)}
+	if pooled {
+		want = []int64{int64(n), int64(c.dimensions)}
+	}
+	size := 1
+	for _, d := range want {
+		size *= int(d)
+	}
+	if !slices.Equal(shape, want) || len(out) != size {
+		return nil, fmt.Errorf("model output shape %v, want %v (check EMBEDDING_LOCAL_DIMENSIONS and EMBEDDING_LOCAL_MAX_TOKENS)", shape, want)
+	}
+
+	vectors := make([][]float32, n)
+	for i := range n {
+		vec := make([]float32, c.column)
+		if pooled {
+			copy(vec, out[i*c.dimensions:(i+1)*c.dimensions])
+		} else {
+			meanPool(vec[:c.dimensions], out[i*seqLen*c.dimensions:(i+1)*seqLen*c.dimensions], mask[i*seqLen:(i+1)*seqLen])
+		}
+		normalize(vec)
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
+
+// meanPool averages the token embeddings the attention mask marks as real into dst.
+func meanPool(dst, tokens []float32, mask []int64) {
+	dim := len(dst)
+	count := 0
+	for t, m := range mask {
+		if m == 0 {
+			continue
+		}
+		count++
+		for d := range dim {
+			dst[d] += tokens[t*dim+d]
+		}
+	}
+	if count == 0 {
+		return
+	}
+	for d := range dim {
+		dst[d] /= float32(count)
+	}
+}
+
+// normalize scales vec to unit length in place.
+func normalize(vec []float32) {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vec {
+		vec[i] /= norm
+	}
+}
+
+// ModelID returns the model identifier.
+func (c *LocalClient) ModelID() string {
+	return c.modelID
+}
+
+// InputLimit returns the limit symbol text is fitted to before embedding.
+func (c *LocalClient) InputLimit() InputLimit { return c.inputLimit }
@@ -0,0 +1,152 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/config"
+)
+
+func testWordPiece(t *testing.T) *wordPiece {
+	t.Helper()
+	vocab := map[string]int64{}
+	for i, token := range []string{"[PAD]", "[UNK]", "[CLS]", "[SEP]", "get", "##order", "##by", "##id", "order", "(", ")", "."} {
+		vocab[token] = int64(i)
+	}
+	tok, err := newWordPiece(vocab)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestWordPiece_Encode(t *testing.T) {
+	tok := testWordPiece(t)
+
+	ids, mask := tok.encode("dbo.GetOrderById(order)", 12)
+	// dbo is not in the vocab; getorderbyid splits into pieces
+	want := []int64{2, 1, 11, 4, 5, 6, 7, 9, 8, 10, 3, 0}
+	if !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if !slices.Equal(mask, []int64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0}) {
+		t.Errorf("mask = %v", mask)
+	}
+
+	// Truncation keeps [SEP] last
+	ids, _ = tok.encode("GetOrderById", 4)
+	if !slices.Equal(ids, []int64{2, 4, 5, 3}) {
+		t.Errorf("truncated ids = %v", ids)
+	}
+}
+
+// fakeSentenceModel returns token embeddings that are a token's ID in each dimension.
+type fakeSentenceModel struct {
+	dim   int
+	calls int
+}
+
+func (f *fakeSentenceModel) run(ids, mask []int64, batch, seqLen int) ([]float32, []int64, error) {
+	f.calls++
+	out := make([]float32, 0, len(ids)*f.dim)
+	for _, id := range ids {
+		for range f.dim {
+			out = append(out, float32(id))
+		}
+	}
+	return out, []int64{int64(batch), int64(seqLen), int64(f.dim)}, nil
+}
+
+func TestLocalClient_EmbedBatch(t *testing.T) {
+	model := &fakeSentenceModel{dim: 4}
+	client := &LocalClient{model: model, tokenizer: testWordPiece(t), modelID: "local/test", dimensions: 4, column: 8, maxTokens: 8}
+
+	texts := make([]string, localBatchSize+1)
+	for i := range texts {
+		texts[i] = "get order"
+	}
+	texts[0] = "GetOrderById"
+	vectors, err := client.EmbedBatch(context.Background(), texts, "search_document")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != len(texts) || model.calls != 2 {
+		t.Fatalf("expected %d vectors from 2 batches, got %d from %d", len(texts), len(vectors), model.calls)
+	}
+	for i, vec := range vectors {
+		if len(vec) != 8 {
+			t.Fatalf("vector %d: expected the column's 8 dimensions, got %d", i, len(vec))
+		}
+		if slices.ContainsFunc(vec[4:], func(v float32) bool { return v != 0 }) {
+			t.Errorf("vector %d: expected zero padding beyond the model's dimensions, got %v", i, vec)
+		}
+		var norm float64
+		for _, v := range vec {
+			norm += float64(v) * float64(v)
+		}
+		if math.Abs(norm-1) > 1e-5 {
+			t.Errorf("vector %d: expected unit length, got %f", i, math.Sqrt(norm))
+		}
+	}
+	// The same text embeds the same wherever it is batched
+	again, err := client.EmbedBatch(context.Background(), []string{"get order"}, "search_query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(again[0], vectors[localBatchSize]) {
+		t.Errorf("expected a deterministic vector, got %v and %v", again[0], vectors[localBatchSize])
+	}
+
+	// A model whose width differs from the configured one is caught before storing
+	client.dimensions = 3
+	if _, err := client.EmbedBatch(context.Background(), []string{"get order"}, "search_document"); err == nil || !strings.Contains(err.Error(), "EMBEDDING_LOCAL_DIMENSIONS") {
+		t.Errorf("expected a dimension mismatch error, got %v", err)
+	}
+}
+
+func TestNewLocalClient_ColumnGuard(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "vocab.txt"), []byte("[PAD]\n[UNK]\n[CLS]\n[SEP]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewLocalClient(config.EmbeddingConfig{
+		LocalModelPath:  filepath.Join(dir, "model.onnx"),
+		LocalDimensions: 1536,
+	}, 1024)
+	if err == nil || !strings.Contains(err.Error(), "1536-dimension vectors but the embedding column holds 1024") {
+		t.Errorf("expected the column guard to reject a wider model, got %v", err)
+	}
+}
+
+// columnWidth is a ColumnSource for a vector column of fixed width.
+type columnWidth int32
+
+func (c columnWidth) GetEmbeddingColumnDimensions(context.Context) (int32, error) {
+	return int32(c), nil
+}
+
+func TestNewEmbedder_Provider(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Embedding: config.EmbeddingConfig{Provider: "local"}, OpenRouter: config.OpenRouterConfig{APIKey: "sk-test"}}
+	if _, err := NewEmbedder(ctx, cfg, columnWidth(1024)); err == nil || !strings.Contains(err.Error(), "EMBEDDING_LOCAL_MODEL_PATH") {
+		t.Errorf("expected local to be chosen ahead of OpenRouter, got %v", err)
+	}
+	cfg.Embedding.Provider = "ollama"
+	if _, err := NewEmbedder(ctx, cfg, columnWidth(1024)); err == nil {
+		t.Error("expected an unknown provider to be rejected")
+	}
+}
+
+func TestLocalModelID(t *testing.T) {
+	if got := localModelID("/models/all-MiniLM-L6-v2/model.onnx"); got != "local/all-MiniLM-L6-v2" {
+		t.Errorf("got %s", got)
+	}
+	if got := localModelID("/models/minilm-int8.onnx"); got != "local/minilm-int8" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,99 @@
+//go:build onnx
+
+package embedding
+
+import (
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// ortInit initializes the onnxruntime environment once per process.
+var ortInit struct {
+	sync.Once
+	err error
+}
+
+// onnxModel runs a sentence-transformer exported to ONNX with onnxruntime. Models take
+// input_ids and attention_mask, and BERT-based ones token_type_ids too; the first
+// output is used.
+type onnxModel struct {
+	session *ort.DynamicAdvancedSession
+	inputs  []string
+	output  string
+}
+
+// openONNXModel loads the model at path with the onnxruntime library at runtimePath,
+// or the loader's default when empty.
+func openONNXModel(path, runtimePath string) (sentenceModel, error) {
+	ortInit.Do(func() {
+		if runtimePath != "" {
+			ort.SetSharedLibraryPath(runtimePath)
+		}
+		ortInit.err = ort.InitializeEnvironment()
+	})
+	if ortInit.err != nil {
+		return nil, fmt.Errorf("initialize onnxruntime: %w", ortInit.err)
+	}
+
+	inputInfo, outputInfo, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("read model %s: %w", path, err)
+	}
+	if len(outputInfo) == 0 {
+		return nil, fmt.Errorf("model %s has no outputs", path)
+	}
+	m := &onnxModel{output: outputInfo[0].Name}
+	for _, in := range inputInfo {
+		switch in.Name {
+		case "input_ids", "attention_mask", "token_type_ids":
+			m.inputs = append(m.inputs, in.Name)
+		default:
+			return nil, fmt.Errorf("model %s has unsupported input %q", path, in.Name)
+		}
+	}
+
+	m.session, err = ort.NewDynamicAdvancedSession(path, m.inputs, []string{m.output}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("load model %s: %w", path, err)
+	}
+	return m, nil
+}
+
+func (m *onnxModel) run(ids, mask []int64, batch, seqLen int) ([]float32, []int64, error) {
+	shape := ort.NewShape(int64(batch), int64(seqLen))
+	var inputs []ort.Value
+	defer func() {
+		for _, v := range inputs {
+			v.Destroy()
+		}
+	}()
+	for _, name := range m.inputs {
+		data := ids
+		switch name {
+		case "attention_mask":
+			data = mask
+		case "token_type_ids":
+			data = make([]int64, len(ids))
+		}
+		tensor, err := ort.NewTensor(shape, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create %s tensor: %w", name, err)
+		}
+		inputs = append(inputs, tensor)
+	}
+
+	// A nil output is allocated by onnxruntime with whatever shape the model produces
+	outputs := []ort.Value{nil}
+	if err := m.session.Run(inputs, outputs); err != nil {
+		return nil, nil, err
+	}
+	defer outputs[0].Destroy()
+	tensor, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, nil, fmt.Errorf("output %s is not a float32 tensor", m.output)
+	}
+	// The tensor's data is freed with it
+	return append([]float32(nil), tensor.GetData()...), []int64(tensor.GetShape()), nil
+}
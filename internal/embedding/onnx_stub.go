@@ -0,0 +1,11 @@
+//go:build !onnx
+
+package embedding
+
+import "fmt"
+
+// openONNXModel reports that this binary was built without onnxruntime. Local
+// embeddings need github.com/yalue/onnxruntime_go and a build with -tags onnx.
+func openONNXModel(path, runtimePath string) (sentenceModel, error) {
+	return nil, fmt.Errorf("EMBEDDING_PROVIDER=local needs a build with -tags onnx (onnxruntime support is not compiled in)")
+}
@@ -29,6 +29,7 @@ type OpenRouterClient struct {
 	baseURL    string
 	dimensions int
 	http       *http.Client
+	inputLimit InputLimit
 }
 
 // NewOpenRouterClient creates a new OpenRouter embedding client.
@@ -218,3 +219,8 @@ func (c *OpenRouterClient) doEmbedRequest(ctx context.Context, reqBody []byte) (
 func (c *OpenRouterClient) ModelID() string {
 	return c.model
 }
+
+// InputLimit returns the limit symbol text is fitted to before embedding.
+func (c *OpenRouterClient) InputLimit() InputLimit {
+	return c.inputLimit
+}
@@ -0,0 +1,65 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// DefaultReembedPageSize is the number of symbols embedded per request when re-embedding.
+const DefaultReembedPageSize = 100
+
+// Reembed regenerates embeddings for every symbol in a project that has no vector from
+// client's model and whose kind the filter allows, walking symbols in ID order one page at a time. Only symbol_embeddings
+// rows are written. Because already-converted symbols are skipped, an interrupted run can
+// simply be started again; cancelling ctx stops it between pages. Returns the number of
+// symbols re-embedded.
+func Reembed(ctx context.Context, client Embedder, s *store.Store, projectID uuid.UUID, kinds KindFilter, pageSize int, logger *slog.Logger) (int, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultReembedPageSize
+	}
+	model := client.ModelID()
+	logger = logger.With(slog.String("project_id", projectID.String()), slog.String("model", model))
+	logger.Info("reembed started", slog.Int("page_size", pageSize))
+
+	done := 0
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			logger.Info("reembed cancelled", slog.Int("embedded", done))
+			return done, err
+		}
+
+		symbols, err := s.ListSymbolsForReembed(ctx, postgres.ListSymbolsForReembedParams{
+			ProjectID: projectID,
+			AfterID:   after,
+			Model:     model,
+			Lim:       int32(pageSize),
+		})
+		if err != nil {
+			return done, fmt.Errorf("list symbols: %w", err)
+		}
+		if len(symbols) == 0 {
+			break
+		}
+
+		if embeddable := kinds.Filter(symbols); len(embeddable) > 0 {
+			n, err := embedSymbols(ctx, client, s, embeddable)
+			done += n
+			if err != nil {
+				return done, err
+			}
+		}
+
+		after = symbols[len(symbols)-1].ID
+		logger.Info("reembed progress", slog.Int("embedded", done), slog.String("last_symbol_id", after.String()))
+	}
+
+	logger.Info("reembed complete", slog.Int("embedded", done))
+	return done, nil
+}
@@ -0,0 +1,167 @@
+//go:build integration
+
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeEmbedder returns constant 1024-dim vectors and counts the texts it was asked to embed.
+type fakeEmbedder struct {
+	model    string
+	embedded int
+}
+
+func (f *fakeEmbedder) EmbedBatch(_ context.Context, texts []string, _ string) ([][]float32, error) {
+	f.embedded += len(texts)
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = make([]float32, 1024)
+		out[i][0] = 1
+	}
+	return out, nil
+}
+
+func (f *fakeEmbedder) ModelID() string { return f.model }
+
+func setupStore(t *testing.T) *store.Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("postgres not available: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("postgres ping failed: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return store.New(pool)
+}
+
+// seedProject creates a project with n symbols in one file.
+func seedProject(t *testing.T, s *store.Store, n int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Test Reembed Project",
+		Slug: fmt.Sprintf("test-reembed-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "test-source", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	file, err := s.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: proj.ID, SourceID: source.ID,
+		Path: "test.sql", Language: "tsql", SizeBytes: 100, Hash: "abc123",
+	})
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	for i := range n {
+		if _, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: file.ID,
+			Name: fmt.Sprintf("T%d", i), QualifiedName: fmt.Sprintf("dbo.T%d", i),
+			Kind: "table", Language: "tsql", StartLine: int32(i + 1), EndLine: int32(i + 1),
+		}); err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM symbols WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM files WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM sources WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+	return proj.ID
+}
+
+func TestReembed_NewModelLeavesSymbolsUntouched(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	projID := seedProject(t, s, 5)
+
+	if _, err := EmbedSymbols(ctx, &fakeEmbedder{model: "old-model"}, s, projID, DefaultKindFilter(), logger); err != nil {
+		t.Fatalf("initial embed: %v", err)
+	}
+	before, err := s.ListSymbolsByProject(ctx, projID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeEmbedder{model: "new-model"}
+	count, err := Reembed(ctx, client, s, projID, DefaultKindFilter(), 2, logger)
+	if err != nil {
+		t.Fatalf("reembed: %v", err)
+	}
+	if count != 5 || client.embedded != 5 {
+		t.Errorf("expected 5 symbols re-embedded over 3 pages, got count=%d embedded=%d", count, client.embedded)
+	}
+
+	var stale int
+	if err := s.Pool().QueryRow(ctx, `SELECT count(*) FROM symbol_embeddings se
+		JOIN symbols s ON s.id = se.symbol_id
+		WHERE s.project_id = $1 AND se.model <> 'new-model'`, projID).Scan(&stale); err != nil {
+		t.Fatal(err)
+	}
+	if stale != 0 {
+		t.Errorf("expected every vector to be tagged new-model, %d are not", stale)
+	}
+
+	after, err := s.ListSymbolsByProject(ctx, projID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := func(a, b postgres.Symbol) int { return strings.Compare(a.ID.String(), b.ID.String()) }
+	slices.SortFunc(before, byID)
+	slices.SortFunc(after, byID)
+	if !reflect.DeepEqual(before, after) {
+		t.Error("reembed modified symbol rows")
+	}
+
+	// A second run finds nothing left to do, which is what makes an interrupted run resumable.
+	again := &fakeEmbedder{model: "new-model"}
+	if count, err := Reembed(ctx, again, s, projID, DefaultKindFilter(), 2, logger); err != nil || count != 0 {
+		t.Errorf("expected resumed run to be a no-op, got count=%d err=%v", count, err)
+	}
+}
+
+func TestReembed_StopsWhenCancelled(t *testing.T) {
+	s := setupStore(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	projID := seedProject(t, s, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &fakeEmbedder{model: "new-model"}
+	if _, err := Reembed(ctx, client, s, projID, DefaultKindFilter(), 1, logger); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if client.embedded != 0 {
+		t.Errorf("expected no embedding requests after cancel, got %d", client.embedded)
+	}
+}
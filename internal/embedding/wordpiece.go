@@ -0,0 +1,139 @@
+package embedding
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// maxWordPieceChars is the longest word WordPiece splits; longer words become [UNK], as
+// in the BERT reference tokenizer.
+const maxWordPieceChars = 100
+
+// wordPiece is the uncased BERT tokenizer sentence-transformers such as all-MiniLM use.
+type wordPiece struct {
+	vocab                  map[string]int64
+	cls, sep, pad, unknown int64
+}
+
+// loadWordPiece reads a vocab.txt with one token per line, the line number being its ID.
+func loadWordPiece(path string) (*wordPiece, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open vocab: %w", err)
+	}
+	defer f.Close()
+
+	vocab := make(map[string]int64)
+	scanner := bufio.NewScanner(f)
+	for id := int64(0); scanner.Scan(); id++ {
+		vocab[strings.TrimRight(scanner.Text(), "\r")] = id
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read vocab: %w", err)
+	}
+	return newWordPiece(vocab)
+}
+
+func newWordPiece(vocab map[string]int64) (*wordPiece, error) {
+	t := &wordPiece{vocab: vocab}
+	for token, id := range map[string]*int64{"[CLS]": &t.cls, "[SEP]": &t.sep, "[PAD]": &t.pad, "[UNK]": &t.unknown} {
+		v, ok := vocab[token]
+		if !ok {
+			return nil, fmt.Errorf("vocab has no %s token", token)
+		}
+		*id = v
+	}
+	return t, nil
+}
+
+// encode returns the token IDs of text between [CLS] and [SEP], truncated to maxLen
+// and padded to it with [PAD], and the attention mask marking the real tokens.
+func (t *wordPiece) encode(text string, maxLen int) (ids, mask []int64) {
+	ids = make([]int64, maxLen)
+	mask = make([]int64, maxLen)
+	ids[0], mask[0] = t.cls, 1
+	n := 1
+	for _, word := range basicTokens(text) {
+		for _, id := range t.pieces(word) {
+			if n == maxLen-1 {
+				break
+			}
+			ids[n], mask[n] = id, 1
+			n++
+		}
+	}
+	ids[n], mask[n] = t.sep, 1
+	for i := n + 1; i < maxLen; i++ {
+		ids[i] = t.pad
+	}
+	return ids, mask
+}
+
+// pieces splits a word into the longest vocabulary pieces from the left, continuations
+// prefixed ##. A word with any unmatched remainder is [UNK].
+func (t *wordPiece) pieces(word string) []int64 {
+	runes := []rune(word)
+	if len(runes) > maxWordPieceChars {
+		return []int64{t.unknown}
+	}
+	var ids []int64
+	for start := 0; start < len(runes); {
+		end := len(runes)
+		found := false
+		for ; end > start; end-- {
+			piece := string(runes[start:end])
+			if start > 0 {
+				piece = "##" + piece
+			}
+			if id, ok := t.vocab[piece]; ok {
+				ids = append(ids, id)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []int64{t.unknown}
+		}
+		start = end
+	}
+	return ids
+}
+
+// basicTokens lowercases text and splits it on whitespace, with each punctuation mark
+// and CJK character a token of its own. Control characters are dropped.
+func basicTokens(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r == 0 || r == unicode.ReplacementChar || unicode.IsControl(r):
+		case isBERTPunct(r) || unicode.Is(unicode.Han, r):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isBERTPunct treats all non-alphanumeric ASCII as punctuation, as BERT does, so
+// symbols such as $ and ^ split words too.
+func isBERTPunct(r rune) bool {
+	if r >= 33 && r <= 47 || r >= 58 && r <= 64 || r >= 91 && r <= 96 || r >= 123 && r <= 126 {
+		return true
+	}
+	return unicode.IsPunct(r)
+}
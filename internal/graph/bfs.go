@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// EdgeSource is the edge and symbol lookup LineageBFS walks; store.Backend satisfies it.
+type EdgeSource interface {
+	GetSymbol(ctx context.Context, id uuid.UUID) (postgres.Symbol, error)
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]postgres.SymbolEdge, error)
+}
+
+// Lineager answers lineage queries from the graph database; *Client satisfies it.
+type Lineager interface {
+	Lineage(ctx context.Context, symbolID uuid.UUID, direction string, maxDepth int, upstreamTypes []string) (*LineageResult, error)
+}
+
+// ProjectEdgeSource is an EdgeSource that also looks up the project a symbol belongs to.
+type ProjectEdgeSource interface {
+	EdgeSource
+	GetProjectByID(ctx context.Context, id uuid.UUID) (postgres.Project, error)
+}
+
+// LineageFor answers a lineage query from the graph database, unless Neo4j is not
+// configured (g is nil) or the symbol's project is archived, whose subgraph may have
+// been evicted from Neo4j: then its lineage is found by LineageBFS over the edges in the
+// database. Edges of the upstreamTypes are walked against their direction, as in
+// Client.Lineage.
+func LineageFor(ctx context.Context, g Lineager, db ProjectEdgeSource, symbolID uuid.UUID, direction string, maxDepth int, upstreamTypes []string) (*LineageResult, error) {
+	if c, ok := g.(*Client); g == nil || (ok && c == nil) {
+		return LineageBFS(ctx, db, symbolID, direction, maxDepth, upstreamTypes)
+	}
+	sym, err := db.GetSymbol(ctx, symbolID)
+	if err != nil {
+		return nil, fmt.Errorf("get symbol: %w", err)
+	}
+	project, err := db.GetProjectByID(ctx, sym.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+	if project.ArchivedAt.Valid {
+		return LineageBFS(ctx, db, symbolID, direction, maxDepth, upstreamTypes)
+	}
+	return g.Lineage(ctx, symbolID, direction, maxDepth, upstreamTypes)
+}
+
+// LineageBFS answers a lineage query by breadth-first search over the edges in the
+// database, for deployments without Neo4j. It takes the same arguments as Lineage and
+// returns the same shape: every symbol within maxDepth hops and the edges walked to reach
+// them. Inferred edges are not followed, as they are not synced to the graph database.
+func LineageBFS(ctx context.Context, g EdgeSource, symbolID uuid.UUID, direction string, maxDepth int, upstreamTypes []string) (*LineageResult, error) {
+	if maxDepth <= 0 || maxDepth > 10 {
+		maxDepth = 3
+	}
+	upstream := make(map[string]bool, len(upstreamTypes))
+	for _, t := range upstreamTypes {
+		upstream[t] = true
+	}
+
+	root, err := g.GetSymbol(ctx, symbolID)
+	if err != nil {
+		return nil, fmt.Errorf("get symbol: %w", err)
+	}
+
+	result := &LineageResult{RootID: symbolID.String()}
+	seen := map[uuid.UUID]bool{symbolID: true}
+	result.Nodes = append(result.Nodes, lineageNode(root))
+	seenEdge := make(map[LineageEdge]bool)
+
+	// Each direction keeps its own visited set, so a symbol both upstream and downstream
+	// of the root (a cycle) is still expanded both ways. Downstream steps go along edges
+	// and against those of the upstream types; upstream steps the reverse.
+	walk := func(downstream bool) error {
+		visited := map[uuid.UUID]bool{symbolID: true}
+		frontier := []uuid.UUID{symbolID}
+		for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+			var next []uuid.UUID
+			for _, id := range frontier {
+				var edges []postgres.SymbolEdge
+				if downstream || len(upstream) > 0 {
+					out, err := g.GetOutgoingEdges(ctx, id)
+					if err != nil {
+						return fmt.Errorf("get edges: %w", err)
+					}
+					edges = append(edges, out...)
+				}
+				if !downstream || len(upstream) > 0 {
+					in, err := g.GetIncomingEdges(ctx, id)
+					if err != nil {
+						return fmt.Errorf("get edges: %w", err)
+					}
+					edges = append(edges, in...)
+				}
+
+				for _, e := range edges {
+					if models.IsInferredEdgeType(e.EdgeType) {
+						continue
+					}
+					// An edge from id is taken to its target, one into id to its source
+					outward := e.SourceID == id
+					if outward != (downstream != upstream[e.EdgeType]) {
+						continue
+					}
+					neighbor := e.TargetID
+					if !outward {
+						neighbor = e.SourceID
+					}
+					if !visited[neighbor] {
+						sym, err := g.GetSymbol(ctx, neighbor)
+						if err != nil {
+							continue // dangling or deleted symbol
+						}
+						visited[neighbor] = true
+						next = append(next, neighbor)
+						if !seen[neighbor] {
+							seen[neighbor] = true
+							result.Nodes = append(result.Nodes, lineageNode(sym))
+						}
+					}
+					edge := LineageEdge{SourceID: e.SourceID.String(), TargetID: e.TargetID.String(), EdgeType: e.EdgeType, Conditional: models.IsConditionalEdge(e.Metadata)}
+					if !seenEdge[edge] {
+						seenEdge[edge] = true
+						result.Edges = append(result.Edges, edge)
+					}
+				}
+			}
+			frontier = next
+		}
+		return nil
+	}
+
+	switch direction {
+	case "upstream":
+		err = walk(false)
+	case "downstream":
+		err = walk(true)
+	default:
+		if err = walk(false); err == nil {
+			err = walk(true)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func lineageNode(s postgres.Symbol) LineageNode {
+	return LineageNode{
+		ID:            s.ID.String(),
+		Name:          s.Name,
+		QualifiedName: s.QualifiedName,
+		Kind:          s.Kind,
+		Language:      s.Language,
+		FileID:        s.FileID.String(),
+	}
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+type fakeEdges struct {
+	symbols map[uuid.UUID]postgres.Symbol
+	edges   []postgres.SymbolEdge
+	project postgres.Project // the project every symbol belongs to
+}
+
+func (f *fakeEdges) add(names ...string) []postgres.Symbol {
+	var out []postgres.Symbol
+	for _, n := range names {
+		s := postgres.Symbol{ID: uuid.New(), Name: n, QualifiedName: "dbo." + n, Kind: "table"}
+		f.symbols[s.ID] = s
+		out = append(out, s)
+	}
+	return out
+}
+
+func (f *fakeEdges) link(from, to postgres.Symbol, edgeType string) {
+	f.edges = append(f.edges, postgres.SymbolEdge{SourceID: from.ID, TargetID: to.ID, EdgeType: edgeType})
+}
+
+func (f *fakeEdges) GetSymbol(_ context.Context, id uuid.UUID) (postgres.Symbol, error) {
+	s, ok := f.symbols[id]
+	if !ok {
+		return postgres.Symbol{}, pgx.ErrNoRows
+	}
+	return s, nil
+}
+
+func (f *fakeEdges) GetOutgoingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	var out []postgres.SymbolEdge
+	for _, e := range f.edges {
+		if e.SourceID == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEdges) GetIncomingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	var out []postgres.SymbolEdge
+	for _, e := range f.edges {
+		if e.TargetID == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEdges) GetProjectByID(_ context.Context, id uuid.UUID) (postgres.Project, error) {
+	if id != f.project.ID {
+		return postgres.Project{}, pgx.ErrNoRows
+	}
+	return f.project, nil
+}
+
+// fakeLineager stands in for Neo4j, counting the lineage queries it answers.
+type fakeLineager struct {
+	calls int
+}
+
+func (l *fakeLineager) Lineage(_ context.Context, symbolID uuid.UUID, _ string, _ int, _ []string) (*LineageResult, error) {
+	l.calls++
+	return &LineageResult{RootID: symbolID.String()}, nil
+}
+
+func names(r *LineageResult) []string {
+	var out []string
+	for _, n := range r.Nodes {
+		out = append(out, n.Name)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestLineageBFS_FollowsDirectionAndDepth(t *testing.T) {
+	f := &fakeEdges{symbols: map[uuid.UUID]postgres.Symbol{}}
+	s := f.add("Raw", "Staging", "Orders", "Report", "Export")
+	raw, staging, orders, report, export := s[0], s[1], s[2], s[3], s[4]
+	f.link(raw, staging, "direct_copy")
+	f.link(staging, orders, "transforms_to")
+	f.link(orders, report, "reads_from")
+	f.link(report, export, "writes_to")
+	f.link(report, orders, "writes_to") // cycle back into the root
+	f.edges = append(f.edges, postgres.SymbolEdge{SourceID: orders.ID, TargetID: uuid.New(), EdgeType: "reads_from"})
+
+	ctx := context.Background()
+
+	down, err := LineageBFS(ctx, f, orders.ID, "downstream", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(down); !slices.Equal(got, []string{"Orders", "Report"}) {
+		t.Errorf("downstream depth 1: got %v", got)
+	}
+	if len(down.Edges) != 1 || down.RootID != orders.ID.String() {
+		t.Errorf("expected one edge from the root, got %+v", down)
+	}
+
+	up, err := LineageBFS(ctx, f, orders.ID, "upstream", 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(up); !slices.Equal(got, []string{"Orders", "Raw", "Report", "Staging"}) {
+		t.Errorf("upstream: got %v", got)
+	}
+
+	both, err := LineageBFS(ctx, f, orders.ID, "both", 0, nil) // defaults to depth 3
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(both); !slices.Equal(got, []string{"Export", "Orders", "Raw", "Report", "Staging"}) {
+		t.Errorf("both: got %v", got)
+	}
+	// report→orders is walked in both directions but reported once.
+	if len(both.Edges) != 5 {
+		t.Errorf("expected 5 distinct edges, got %d: %+v", len(both.Edges), both.Edges)
+	}
+
+	if _, err := LineageBFS(ctx, f, uuid.New(), "both", 3, nil); err == nil {
+		t.Error("expected an error for an unknown root")
+	}
+}
+
+// A report procedure reads Orders and writes Summary: with reads_from upstream, Orders is
+// upstream of the report and Summary downstream, and the report downstream of Orders.
+func TestLineageBFS_UpstreamTypesWalkAgainstEdges(t *testing.T) {
+	f := &fakeEdges{symbols: map[uuid.UUID]postgres.Symbol{}}
+	s := f.add("Orders", "usp_Report", "Summary")
+	orders, report, summary := s[0], s[1], s[2]
+	f.link(report, orders, "reads_from")
+	f.link(report, summary, "writes_to")
+
+	ctx := context.Background()
+	upstreamTypes := []string{"reads_from"}
+
+	up, err := LineageBFS(ctx, f, report.ID, "upstream", 3, upstreamTypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(up); !slices.Equal(got, []string{"Orders", "usp_Report"}) {
+		t.Errorf("upstream of the report: got %v", got)
+	}
+
+	down, err := LineageBFS(ctx, f, orders.ID, "downstream", 3, upstreamTypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(down); !slices.Equal(got, []string{"Orders", "Summary", "usp_Report"}) {
+		t.Errorf("downstream of Orders: got %v", got)
+	}
+	if len(down.Edges) != 2 {
+		t.Errorf("expected the read and the write, got %+v", down.Edges)
+	}
+}
+
+func TestLineageFor_ArchivedProjectUsesDatabase(t *testing.T) {
+	f := &fakeEdges{symbols: map[uuid.UUID]postgres.Symbol{}, project: postgres.Project{ID: uuid.Nil}}
+	s := f.add("Staging", "Orders")
+	f.link(s[0], s[1], "transforms_to")
+	ctx := context.Background()
+
+	g := &fakeLineager{}
+	if _, err := LineageFor(ctx, g, f, s[1].ID, "upstream", 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	if g.calls != 1 {
+		t.Fatalf("live project: graph queried %d times, want 1", g.calls)
+	}
+
+	f.project.ArchivedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	result, err := LineageFor(ctx, g, f, s[1].ID, "upstream", 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.calls != 1 {
+		t.Errorf("archived project: graph queried, want a database traversal")
+	}
+	if got := names(result); !slices.Equal(got, []string{"Orders", "Staging"}) {
+		t.Errorf("archived project upstream: got %v", got)
+	}
+
+	if _, err := LineageFor(ctx, g, f, uuid.New(), "both", 3, nil); err == nil {
+		t.Error("expected an error for an unknown symbol")
+	}
+}
+
+func TestLineageFor_WithoutNeo4jUsesDatabase(t *testing.T) {
+	f := &fakeEdges{symbols: map[uuid.UUID]postgres.Symbol{}, project: postgres.Project{ID: uuid.Nil}}
+	s := f.add("Staging", "Orders")
+	f.link(s[0], s[1], "transforms_to")
+
+	var client *Client // Neo4j not configured
+	result, err := LineageFor(context.Background(), client, f, s[1].ID, "upstream", 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(result); !slices.Equal(got, []string{"Orders", "Staging"}) {
+		t.Errorf("upstream without Neo4j: got %v", got)
+	}
+}
@@ -22,9 +22,10 @@ type LineageNode struct {
 
 // LineageEdge represents a relationship in the lineage graph.
 type LineageEdge struct {
-	SourceID string
-	TargetID string
-	EdgeType string
+	SourceID    string
+	TargetID    string
+	EdgeType    string
+	Conditional bool // only taken behind a feature-flag check
 }
 
 // LineageResult contains the result of a lineage query.
@@ -34,8 +35,10 @@ type LineageResult struct {
 	RootID string
 }
 
-// Lineage queries the Neo4j graph for upstream/downstream dependencies.
-func (c *Client) Lineage(ctx context.Context, symbolID uuid.UUID, direction string, maxDepth int) (*LineageResult, error) {
+// Lineage queries the Neo4j graph for upstream/downstream dependencies. Edges point
+// from a symbol to what it depends on, except those of the upstreamTypes, such as
+// reads_from, whose target feeds the source: walks take those the other way.
+func (c *Client) Lineage(ctx context.Context, symbolID uuid.UUID, direction string, maxDepth int, upstreamTypes []string) (*LineageResult, error) {
 	if maxDepth <= 0 || maxDepth > 10 {
 		maxDepth = 3
 	}
@@ -54,8 +57,12 @@ func (c *Client) Lineage(ctx context.Context, symbolID uuid.UUID, direction stri
 	}
 
 	result, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
+		if upstreamTypes == nil {
+			upstreamTypes = []string{}
+		}
 		records, err := tx.Run(ctx, query, map[string]any{
-			"symbolId": symbolID.String(),
+			"symbolId":      symbolID.String(),
+			"upstreamTypes": upstreamTypes,
 		})
 		if err != nil {
 			return nil, err
@@ -114,14 +121,17 @@ func (c *Client) Lineage(ctx context.Context, symbolID uuid.UUID, direction stri
 					edgeType = rel.Type
 				}
 
+				conditional, _ := rel.Props["conditional"].(bool)
+
 				startID := elemToSymbol[rel.StartElementId]
 				endID := elemToSymbol[rel.EndElementId]
 
 				if startID != "" && endID != "" {
 					edges = append(edges, LineageEdge{
-						SourceID: startID,
-						TargetID: endID,
-						EdgeType: edgeType,
+						SourceID:    startID,
+						TargetID:    endID,
+						EdgeType:    edgeType,
+						Conditional: conditional,
 					})
 				}
 			}
@@ -24,6 +24,7 @@ func NewClient(cfg config.Neo4jConfig) (*Client, error) {
 
 // EnsureIndexes creates uniqueness constraints on Symbol(id) and File(id) if they do not exist.
 // These constraints create indexes that make MERGE/MATCH by id fast; without them, sync can take many minutes.
+// It also indexes both labels by projectId, which pruning and clearing a project match on.
 func (c *Client) EnsureIndexes(ctx context.Context) error {
 	session := c.Session(ctx)
 	defer session.Close(ctx)
@@ -34,6 +35,12 @@ func (c *Client) EnsureIndexes(ctx context.Context) error {
 		if _, err := tx.Run(ctx, CreateConstraintFileID, nil); err != nil {
 			return struct{}{}, fmt.Errorf("create file id constraint: %w", err)
 		}
+		if _, err := tx.Run(ctx, CreateIndexSymbolProject, nil); err != nil {
+			return struct{}{}, fmt.Errorf("create symbol project index: %w", err)
+		}
+		if _, err := tx.Run(ctx, CreateIndexFileProject, nil); err != nil {
+			return struct{}{}, fmt.Errorf("create file project index: %w", err)
+		}
 		return struct{}{}, nil
 	})
 	return err
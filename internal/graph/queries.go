@@ -6,6 +6,10 @@ const (
 	CreateConstraintSymbolID = `CREATE CONSTRAINT symbol_id IF NOT EXISTS FOR (s:Symbol) REQUIRE s.id IS UNIQUE`
 	// CreateConstraintFileID ensures File(id) is unique and indexed (required for fast MERGE/MATCH).
 	CreateConstraintFileID = `CREATE CONSTRAINT file_id IF NOT EXISTS FOR (f:File) REQUIRE f.id IS UNIQUE`
+	// CreateIndexSymbolProject indexes Symbol(projectId), which pruning and clearing a project match on.
+	CreateIndexSymbolProject = `CREATE INDEX symbol_project IF NOT EXISTS FOR (s:Symbol) ON (s.projectId)`
+	// CreateIndexFileProject indexes File(projectId), which pruning and clearing a project match on.
+	CreateIndexFileProject = `CREATE INDEX file_project IF NOT EXISTS FOR (f:File) ON (f.projectId)`
 
 	// UpsertSymbolNode merges a symbol node by its ID and sets all properties.
 	UpsertSymbolNode = `
@@ -18,16 +22,21 @@ SET s.name = sym.name,
     s.projectId = sym.projectId,
     s.fileId = sym.fileId,
     s.startLine = sym.startLine,
-    s.endLine = sym.endLine
+    s.endLine = sym.endLine,
+    s.syncRunId = sym.runId
 `
 
-	// UpsertEdge merges a relationship between source and target symbols.
+	// UpsertEdge merges a relationship keyed on the PostgreSQL edge ID, so replaying a
+	// batch (e.g. after a failed sync) updates the existing relationship instead of adding one.
 	UpsertEdge = `
 UNWIND $edges AS edge
 MATCH (src:Symbol {id: edge.sourceId})
 MATCH (tgt:Symbol {id: edge.targetId})
-MERGE (src)-[r:DEPENDS_ON {edgeType: edge.edgeType}]->(tgt)
-SET r.projectId = edge.projectId
+MERGE (src)-[r:DEPENDS_ON {id: edge.id}]->(tgt)
+SET r.edgeType = edge.edgeType,
+    r.conditional = edge.conditional,
+    r.projectId = edge.projectId,
+    r.syncRunId = edge.runId
 `
 
 	// UpsertFileNode merges a file node by its ID.
@@ -37,7 +46,8 @@ MERGE (file:File {id: f.id})
 SET file.path = f.path,
     file.language = f.language,
     file.projectId = f.projectId,
-    file.sourceId = f.sourceId
+    file.sourceId = f.sourceId,
+    file.syncRunId = f.runId
 `
 
 	// LinkSymbolToFile creates DEFINED_IN relationships between symbols and files.
@@ -48,41 +58,86 @@ MATCH (f:File {id: sym.fileId})
 MERGE (s)-[:DEFINED_IN]->(f)
 `
 
-	// DeleteProjectNodes removes all nodes and relationships for a project.
+	// DeleteStaleProjectRelationships removes synced relationships not written by the given
+	// run: edges deleted since the last sync and legacy relationships without a stable ID.
+	// Relationships are found from the project's symbols and deleted in batches, so it must
+	// run in an auto-commit transaction.
+	DeleteStaleProjectRelationships = `
+MATCH (:Symbol {projectId: $projectId})-[r:DEPENDS_ON|COLUMN_FLOW]->()
+WHERE coalesce(r.syncRunId, '') <> $runId
+CALL (r) {
+  DELETE r
+} IN TRANSACTIONS OF 10000 ROWS
+`
+
+	// DeleteStaleProjectNodes removes the nodes with the label %s (Symbol or File) not
+	// written by the given run, in batches, so it must run in an auto-commit transaction.
+	DeleteStaleProjectNodes = `
+MATCH (n:%s {projectId: $projectId})
+WHERE coalesce(n.syncRunId, '') <> $runId
+CALL (n) {
+  DETACH DELETE n
+} IN TRANSACTIONS OF 10000 ROWS
+`
+
+	// DeleteProjectNodes removes all nodes with the label %s (Symbol or File) and their
+	// relationships for a project, in batches, so it must run in an auto-commit transaction.
 	DeleteProjectNodes = `
-MATCH (n {projectId: $projectId})
-DETACH DELETE n
+MATCH (n:%s {projectId: $projectId})
+CALL (n) {
+  DETACH DELETE n
+} IN TRANSACTIONS OF 10000 ROWS
 `
 
-	// LineageUpstream finds all upstream dependencies of a symbol.
+	// LineageUpstream finds all upstream dependencies of a symbol: each step goes against
+	// an edge, or along one whose edgeType is in $upstreamTypes, such as reads_from.
 	LineageUpstream = `
-MATCH path = (upstream)-[:DEPENDS_ON*1..%d]->(target:Symbol {id: $symbolId})
+MATCH path = (target:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]-(upstream)
+WHERE all(i IN range(0, length(path) - 1) WHERE
+  (startNode(relationships(path)[i]) = nodes(path)[i]) = (relationships(path)[i].edgeType IN $upstreamTypes))
 RETURN path
 `
 
-	// LineageDownstream finds all downstream dependents of a symbol.
+	// LineageDownstream finds all downstream dependents of a symbol: each step goes along
+	// an edge, or against one whose edgeType is in $upstreamTypes.
 	LineageDownstream = `
-MATCH path = (source:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]->(downstream)
+MATCH path = (source:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]-(downstream)
+WHERE all(i IN range(0, length(path) - 1) WHERE
+  (startNode(relationships(path)[i]) = nodes(path)[i]) <> (relationships(path)[i].edgeType IN $upstreamTypes))
 RETURN path
 `
 
 	// LineageBoth finds both upstream and downstream connections.
 	LineageBoth = `
-MATCH path = (upstream)-[:DEPENDS_ON*1..%d]->(target:Symbol {id: $symbolId})
+MATCH path = (target:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]-(upstream)
+WHERE all(i IN range(0, length(path) - 1) WHERE
+  (startNode(relationships(path)[i]) = nodes(path)[i]) = (relationships(path)[i].edgeType IN $upstreamTypes))
 RETURN path
 UNION
-MATCH path = (source:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]->(downstream)
+MATCH path = (source:Symbol {id: $symbolId})-[:DEPENDS_ON*1..%d]-(downstream)
+WHERE all(i IN range(0, length(path) - 1) WHERE
+  (startNode(relationships(path)[i]) = nodes(path)[i]) <> (relationships(path)[i].edgeType IN $upstreamTypes))
 RETURN path
 `
 
-	// UpsertColumnEdge creates COLUMN_FLOW relationships with metadata.
+	// UpsertColumnEdge merges COLUMN_FLOW relationships keyed on the PostgreSQL edge ID.
 	UpsertColumnEdge = `
 UNWIND $edges AS edge
 MATCH (src:Symbol {id: edge.sourceId})
 MATCH (tgt:Symbol {id: edge.targetId})
-MERGE (src)-[r:COLUMN_FLOW {derivationType: edge.derivationType}]->(tgt)
-SET r.projectId = edge.projectId,
-    r.expression = edge.expression
+MERGE (src)-[r:COLUMN_FLOW {id: edge.id}]->(tgt)
+SET r.derivationType = edge.derivationType,
+    r.projectId = edge.projectId,
+    r.expression = edge.expression,
+    r.syncRunId = edge.runId
+`
+
+	// DeleteColumnEdges removes COLUMN_FLOW relationships by their PostgreSQL edge IDs.
+	DeleteColumnEdges = `
+UNWIND $ids AS id
+MATCH ()-[r:COLUMN_FLOW {id: id}]->()
+WHERE r.projectId = $projectId
+DELETE r
 `
 
 	// ColumnLineageUpstream finds upstream column flows.
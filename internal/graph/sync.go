@@ -4,17 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 
 	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
 )
 
 const batchSize = 500
 
+// Sync is idempotent: nodes and relationships are merged on their PostgreSQL IDs and
+// stamped with the index run that wrote them, and every batch runs in a managed write
+// transaction that the driver retries on transient errors. Replaying a partially
+// applied sync with the same run ID converges to the same graph; PruneStale then drops
+// whatever an earlier run left behind.
+
 // SyncSymbols upserts symbol nodes into Neo4j from PostgreSQL data.
-func (c *Client) SyncSymbols(ctx context.Context, projectID uuid.UUID, symbols []postgres.Symbol) error {
+func (c *Client) SyncSymbols(ctx context.Context, projectID, runID uuid.UUID, symbols []postgres.Symbol) error {
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
@@ -34,6 +42,7 @@ func (c *Client) SyncSymbols(ctx context.Context, projectID uuid.UUID, symbols [
 				"fileId":        sym.FileID.String(),
 				"startLine":     sym.StartLine,
 				"endLine":       sym.EndLine,
+				"runId":         runID.String(),
 			}
 		}
 
@@ -53,11 +62,16 @@ func (c *Client) SyncSymbols(ctx context.Context, projectID uuid.UUID, symbols [
 	return nil
 }
 
-// SyncEdges upserts edges into Neo4j from PostgreSQL data.
-func (c *Client) SyncEdges(ctx context.Context, projectID uuid.UUID, edges []postgres.SymbolEdge) error {
+// SyncEdges upserts edges into Neo4j from PostgreSQL data. Inferred edges are left out,
+// so that lineage over DEPENDS_ON never follows a guessed relationship.
+func (c *Client) SyncEdges(ctx context.Context, projectID, runID uuid.UUID, edges []postgres.SymbolEdge) error {
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
+	edges = slices.DeleteFunc(slices.Clone(edges), func(e postgres.SymbolEdge) bool {
+		return models.IsInferredEdgeType(e.EdgeType)
+	})
+
 	for i := 0; i < len(edges); i += batchSize {
 		end := min(i+batchSize, len(edges))
 		batch := edges[i:end]
@@ -65,10 +79,13 @@ func (c *Client) SyncEdges(ctx context.Context, projectID uuid.UUID, edges []pos
 		params := make([]map[string]any, len(batch))
 		for j, edge := range batch {
 			params[j] = map[string]any{
-				"sourceId":  edge.SourceID.String(),
-				"targetId":  edge.TargetID.String(),
-				"edgeType":  edge.EdgeType,
-				"projectId": projectID.String(),
+				"id":          edge.ID.String(),
+				"sourceId":    edge.SourceID.String(),
+				"targetId":    edge.TargetID.String(),
+				"edgeType":    edge.EdgeType,
+				"conditional": models.IsConditionalEdge(edge.Metadata),
+				"projectId":   projectID.String(),
+				"runId":       runID.String(),
 			}
 		}
 
@@ -84,7 +101,7 @@ func (c *Client) SyncEdges(ctx context.Context, projectID uuid.UUID, edges []pos
 }
 
 // SyncFiles upserts file nodes into Neo4j from PostgreSQL data.
-func (c *Client) SyncFiles(ctx context.Context, projectID uuid.UUID, files []postgres.File) error {
+func (c *Client) SyncFiles(ctx context.Context, projectID, runID uuid.UUID, files []postgres.File) error {
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
@@ -100,6 +117,7 @@ func (c *Client) SyncFiles(ctx context.Context, projectID uuid.UUID, files []pos
 				"language":  f.Language,
 				"projectId": projectID.String(),
 				"sourceId":  f.SourceID.String(),
+				"runId":     runID.String(),
 			}
 		}
 
@@ -115,7 +133,7 @@ func (c *Client) SyncFiles(ctx context.Context, projectID uuid.UUID, files []pos
 }
 
 // SyncColumnEdges upserts column-level edges into Neo4j.
-func (c *Client) SyncColumnEdges(ctx context.Context, projectID uuid.UUID, edges []postgres.SymbolEdge) error {
+func (c *Client) SyncColumnEdges(ctx context.Context, projectID, runID uuid.UUID, edges []postgres.SymbolEdge) error {
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
@@ -145,11 +163,13 @@ func (c *Client) SyncColumnEdges(ctx context.Context, projectID uuid.UUID, edges
 				}
 			}
 			params[j] = map[string]any{
+				"id":             edge.ID.String(),
 				"sourceId":       edge.SourceID.String(),
 				"targetId":       edge.TargetID.String(),
 				"derivationType": derivation,
 				"expression":     expression,
 				"projectId":      projectID.String(),
+				"runId":          runID.String(),
 			}
 		}
 
@@ -164,16 +184,76 @@ func (c *Client) SyncColumnEdges(ctx context.Context, projectID uuid.UUID, edges
 	return nil
 }
 
-// ClearProject removes all graph data for a project.
-func (c *Client) ClearProject(ctx context.Context, projectID uuid.UUID) error {
+// DeleteColumnEdges removes the COLUMN_FLOW relationships of deleted column-lineage
+// edges, so an incremental run can replace one file's lineage without a full resync.
+func (c *Client) DeleteColumnEdges(ctx context.Context, projectID uuid.UUID, edgeIDs []uuid.UUID) error {
+	if len(edgeIDs) == 0 {
+		return nil
+	}
 	session := c.Session(ctx)
 	defer session.Close(ctx)
 
+	ids := make([]string, len(edgeIDs))
+	for i, id := range edgeIDs {
+		ids[i] = id.String()
+	}
 	_, err := neo4j.ExecuteWrite(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
-		_, err := tx.Run(ctx, DeleteProjectNodes, map[string]any{
+		_, err := tx.Run(ctx, DeleteColumnEdges, map[string]any{
+			"ids":       ids,
 			"projectId": projectID.String(),
 		})
 		return struct{}{}, err
 	})
+	if err != nil {
+		return fmt.Errorf("delete column edges: %w", err)
+	}
+	return nil
+}
+
+// PruneStale removes a project's nodes and relationships that were not written by runID.
+// Call it only after a complete sync for that run.
+func (c *Client) PruneStale(ctx context.Context, projectID, runID uuid.UUID) error {
+	session := c.Session(ctx)
+	defer session.Close(ctx)
+
+	params := map[string]any{
+		"projectId": projectID.String(),
+		"runId":     runID.String(),
+	}
+	queries := []string{
+		DeleteStaleProjectRelationships,
+		fmt.Sprintf(DeleteStaleProjectNodes, "Symbol"),
+		fmt.Sprintf(DeleteStaleProjectNodes, "File"),
+	}
+	for _, query := range queries {
+		if err := runAutoCommit(ctx, session, query, params); err != nil {
+			return fmt.Errorf("prune stale graph data: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClearProject removes all graph data for a project.
+func (c *Client) ClearProject(ctx context.Context, projectID uuid.UUID) error {
+	session := c.Session(ctx)
+	defer session.Close(ctx)
+
+	params := map[string]any{"projectId": projectID.String()}
+	for _, label := range []string{"Symbol", "File"} {
+		if err := runAutoCommit(ctx, session, fmt.Sprintf(DeleteProjectNodes, label), params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAutoCommit runs query in an auto-commit transaction, which queries that commit in
+// batches with CALL { ... } IN TRANSACTIONS require, and waits for it to finish.
+func runAutoCommit(ctx context.Context, session neo4j.SessionWithContext, query string, params map[string]any) error {
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume(ctx)
 	return err
 }
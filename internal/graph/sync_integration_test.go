@@ -0,0 +1,124 @@
+//go:build integration
+
+package graph
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/maraichr/lattice/internal/config"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func setupClient(t *testing.T) *Client {
+	t.Helper()
+	uri := os.Getenv("TEST_NEO4J_URI")
+	if uri == "" {
+		t.Fatal("TEST_NEO4J_URI not set")
+	}
+	ctx := context.Background()
+	c, err := NewClient(config.Neo4jConfig{
+		URI:      uri,
+		User:     os.Getenv("TEST_NEO4J_USER"),
+		Password: os.Getenv("TEST_NEO4J_PASSWORD"),
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	if err := c.Verify(ctx); err != nil {
+		t.Skipf("neo4j not available: %v", err)
+	}
+	if err := c.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("ensure indexes: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close(ctx) })
+	return c
+}
+
+func countDependsOn(t *testing.T, c *Client, projectID uuid.UUID) int64 {
+	t.Helper()
+	ctx := context.Background()
+	session := c.Session(ctx)
+	defer session.Close(ctx)
+
+	result, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, `MATCH ()-[r:DEPENDS_ON {projectId: $projectId}]->() RETURN count(r) AS n`,
+			map[string]any{"projectId": projectID.String()})
+		if err != nil {
+			return nil, err
+		}
+		rec, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		n, _ := rec.Get("n")
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("count relationships: %v", err)
+	}
+	return result.(int64)
+}
+
+func TestSync_RetryAfterMidSyncFailureHasNoDuplicates(t *testing.T) {
+	c := setupClient(t)
+	ctx := context.Background()
+
+	projectID := uuid.New()
+	fileID := uuid.New()
+	t.Cleanup(func() { _ = c.ClearProject(ctx, projectID) })
+
+	files := []postgres.File{{ID: fileID, ProjectID: projectID, SourceID: uuid.New(), Path: "orders.sql", Language: "tsql"}}
+	symbols := make([]postgres.Symbol, 4)
+	for i := range symbols {
+		symbols[i] = postgres.Symbol{ID: uuid.New(), ProjectID: projectID, FileID: fileID, Name: "s", QualifiedName: "dbo.s", Kind: "procedure", Language: "tsql"}
+	}
+	// Two edges share source, target and type: keying on edge type alone would collapse them,
+	// keying on nothing would duplicate them on replay.
+	edges := []postgres.SymbolEdge{
+		{ID: uuid.New(), ProjectID: projectID, SourceID: symbols[0].ID, TargetID: symbols[1].ID, EdgeType: "calls"},
+		{ID: uuid.New(), ProjectID: projectID, SourceID: symbols[1].ID, TargetID: symbols[2].ID, EdgeType: "calls"},
+		{ID: uuid.New(), ProjectID: projectID, SourceID: symbols[2].ID, TargetID: symbols[3].ID, EdgeType: "reads_from"},
+		{ID: uuid.New(), ProjectID: projectID, SourceID: symbols[0].ID, TargetID: symbols[3].ID, EdgeType: "writes_to"},
+	}
+
+	runID := uuid.New()
+	sync := func(edges []postgres.SymbolEdge) {
+		t.Helper()
+		if err := c.SyncFiles(ctx, projectID, runID, files); err != nil {
+			t.Fatalf("sync files: %v", err)
+		}
+		if err := c.SyncSymbols(ctx, projectID, runID, symbols); err != nil {
+			t.Fatalf("sync symbols: %v", err)
+		}
+		if err := c.SyncEdges(ctx, projectID, runID, edges); err != nil {
+			t.Fatalf("sync edges: %v", err)
+		}
+	}
+
+	// First attempt dies after writing part of the edges.
+	sync(edges[:3])
+
+	// The retried stage replays everything with the same run ID, then prunes.
+	sync(edges)
+	if err := c.PruneStale(ctx, projectID, runID); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if got := countDependsOn(t, c, projectID); got != int64(len(edges)) {
+		t.Errorf("after retry: expected %d relationships, got %d", len(edges), got)
+	}
+
+	// A later run that no longer has the last edge converges as well.
+	runID = uuid.New()
+	sync(edges[:3])
+	if err := c.PruneStale(ctx, projectID, runID); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if got := countDependsOn(t, c, projectID); got != 3 {
+		t.Errorf("after re-index: expected 3 relationships, got %d", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
 
 	"github.com/google/uuid"
 
@@ -28,6 +29,9 @@ type ImpactNode struct {
 	Severity string        `json:"severity"` // critical, high, medium, low
 	EdgeType string        `json:"edge_type"`
 	Path     []string      `json:"path"`
+	// Conditional marks a dependent reached only through a call behind a feature-flag
+	// check; its Severity is rated a level lower.
+	Conditional bool `json:"conditional,omitempty"`
 }
 
 // ImpactResult contains the full impact analysis for a symbol change.
@@ -37,26 +41,33 @@ type ImpactResult struct {
 	DirectImpact     []ImpactNode  `json:"direct_impact"`
 	TransitiveImpact []ImpactNode  `json:"transitive_impact"`
 	TotalAffected    int           `json:"total_affected"`
+	// Severity rates the whole blast radius by the engine's SeverityConfig; each node's
+	// Severity rates that dependent alone, by its depth and edge.
+	Severity string `json:"severity"`
 }
 
-// Engine performs impact analysis using Neo4j lineage data.
+// Engine performs impact analysis using Neo4j lineage data, or the edges in the database
+// when Neo4j is not configured (g is nil).
 type Engine struct {
-	graph  *graph.Client
-	store  *store.Store
-	logger *slog.Logger
+	graph    *graph.Client
+	store    *store.Store
+	severity SeverityConfig
+	logger   *slog.Logger
 }
 
-// NewEngine creates a new impact analysis engine.
+// NewEngine creates a new impact analysis engine rating blast radii by the default
+// severity thresholds.
 func NewEngine(g *graph.Client, s *store.Store, logger *slog.Logger) *Engine {
-	return &Engine{graph: g, store: s, logger: logger}
+	return &Engine{graph: g, store: s, severity: DefaultSeverityConfig(), logger: logger}
+}
+
+// SetSeverityConfig sets the thresholds blast radii are rated by.
+func (e *Engine) SetSeverityConfig(c SeverityConfig) {
+	e.severity = c
 }
 
 // Analyze computes the downstream impact of changing a symbol.
 func (e *Engine) Analyze(ctx context.Context, symbolID uuid.UUID, changeType string, maxDepth int) (*ImpactResult, error) {
-	if e.graph == nil {
-		return nil, fmt.Errorf("neo4j not configured")
-	}
-
 	if maxDepth <= 0 || maxDepth > 10 {
 		maxDepth = 5
 	}
@@ -77,45 +88,77 @@ func (e *Engine) Analyze(ctx context.Context, symbolID uuid.UUID, changeType str
 
 	// Query upstream lineage from Neo4j — find everything that depends on this symbol.
 	// Edge direction: (A)-[:DEPENDS_ON]->(B) means A depends on B.
-	// Upstream from B returns all paths like (A)-[:DEPENDS_ON*]->(B).
-	lineageResult, err := e.graph.Lineage(ctx, symbolID, "upstream", maxDepth)
+	// Upstream from B returns all paths like (A)-[:DEPENDS_ON*]->(B); dependents are found
+	// against every edge, whichever way data flows along it.
+	lineageResult, err := graph.LineageFor(ctx, e.graph, e.store, symbolID, "upstream", maxDepth, nil)
 	if err != nil {
 		return nil, fmt.Errorf("lineage query: %w", err)
 	}
 
+	direct, transitive := dependents(lineageResult, symbolID.String(), changeType, maxDepth)
+	if direct == nil {
+		direct = []ImpactNode{}
+	}
+	if transitive == nil {
+		transitive = []ImpactNode{}
+	}
+
+	result := &ImpactResult{
+		Root:             root,
+		ChangeType:       changeType,
+		DirectImpact:     direct,
+		TransitiveImpact: transitive,
+		TotalAffected:    len(direct) + len(transitive),
+	}
+	result.Severity = e.severity.Classify(len(direct), len(transitive), crossesLanguages(root, direct, transitive))
+
+	e.logger.Info("impact analysis complete",
+		slog.String("symbol", sym.QualifiedName),
+		slog.String("change_type", changeType),
+		slog.Int("total_affected", result.TotalAffected))
+
+	return result, nil
+}
+
+// dependents walks a lineage result from the root outward to the symbols depending on
+// it, splitting them into direct and transitive dependents. A dependent reached through a
+// conditional edge, or through one reached so, is marked Conditional.
+func dependents(lineage *graph.LineageResult, rootID, changeType string, maxDepth int) (direct, transitive []ImpactNode) {
 	// Build reverse adjacency: for upstream edges (A→B), we need to traverse
 	// from B outward to A (from the changed symbol to its dependents).
 	// Key on TargetID, traverse to SourceID.
 	reverseAdj := make(map[string][]graph.LineageEdge)
-	for _, edge := range lineageResult.Edges {
+	for _, edge := range lineage.Edges {
 		reverseAdj[edge.TargetID] = append(reverseAdj[edge.TargetID], edge)
 	}
 
 	// Build node map for lookup
 	nodeMap := make(map[string]graph.LineageNode)
-	for _, n := range lineageResult.Nodes {
+	for _, n := range lineage.Nodes {
 		nodeMap[n.ID] = n
 	}
 
 	// BFS from root symbol outward through reverse edges to find impacted nodes
 	type bfsEntry struct {
-		id    string
-		depth int
-		path  []string
-		edge  string
+		id          string
+		depth       int
+		path        []string
+		edge        string
+		conditional bool
 	}
 
 	visited := make(map[string]bool)
-	visited[symbolID.String()] = true
-	queue := []bfsEntry{{id: symbolID.String(), depth: 0, path: []string{symbolID.String()}}}
-
-	var direct, transitive []ImpactNode
+	visited[rootID] = true
+	queue := []bfsEntry{{id: rootID, depth: 0, path: []string{rootID}}}
 
 	for len(queue) > 0 {
 		current := queue[0]
 		queue = queue[1:]
 
-		for _, edge := range reverseAdj[current.id] {
+		// Unconditional edges first, so a dependent reachable both ways is not marked
+		edges := reverseAdj[current.id]
+		sort.SliceStable(edges, func(i, j int) bool { return !edges[i].Conditional && edges[j].Conditional })
+		for _, edge := range edges {
 			dependentID := edge.SourceID // the node that depends on current
 			if visited[dependentID] {
 				continue
@@ -124,13 +167,17 @@ func (e *Engine) Analyze(ctx context.Context, symbolID uuid.UUID, changeType str
 
 			depth := current.depth + 1
 			path := append(append([]string{}, current.path...), dependentID)
+			conditional := current.conditional || edge.Conditional
 
 			node, exists := nodeMap[dependentID]
 			if !exists {
 				continue
 			}
 
-			severity := classifySeverity(depth, edge.EdgeType, changeType)
+			severity := dependentSeverity(depth, edge.EdgeType, changeType)
+			if conditional {
+				severity = lowerSeverity(severity)
+			}
 			impactNode := ImpactNode{
 				Symbol: SymbolSummary{
 					ID:            node.ID,
@@ -139,10 +186,11 @@ func (e *Engine) Analyze(ctx context.Context, symbolID uuid.UUID, changeType str
 					Kind:          node.Kind,
 					Language:      node.Language,
 				},
-				Depth:    depth,
-				Severity: severity,
-				EdgeType: edge.EdgeType,
-				Path:     path,
+				Depth:       depth,
+				Severity:    severity,
+				EdgeType:    edge.EdgeType,
+				Path:        path,
+				Conditional: conditional,
 			}
 
 			if depth == 1 {
@@ -152,36 +200,29 @@ func (e *Engine) Analyze(ctx context.Context, symbolID uuid.UUID, changeType str
 			}
 
 			if depth < maxDepth {
-				queue = append(queue, bfsEntry{id: dependentID, depth: depth, path: path, edge: edge.EdgeType})
+				queue = append(queue, bfsEntry{id: dependentID, depth: depth, path: path, edge: edge.EdgeType, conditional: conditional})
 			}
 		}
 	}
+	return direct, transitive
+}
 
-	if direct == nil {
-		direct = []ImpactNode{}
-	}
-	if transitive == nil {
-		transitive = []ImpactNode{}
-	}
-
-	result := &ImpactResult{
-		Root:             root,
-		ChangeType:       changeType,
-		DirectImpact:     direct,
-		TransitiveImpact: transitive,
-		TotalAffected:    len(direct) + len(transitive),
+// crossesLanguages reports whether any affected symbol is in a language other than the
+// root's, so that the impact crosses a language bridge.
+func crossesLanguages(root SymbolSummary, groups ...[]ImpactNode) bool {
+	for _, group := range groups {
+		for _, n := range group {
+			if n.Symbol.Language != "" && root.Language != "" && n.Symbol.Language != root.Language {
+				return true
+			}
+		}
 	}
-
-	e.logger.Info("impact analysis complete",
-		slog.String("symbol", sym.QualifiedName),
-		slog.String("change_type", changeType),
-		slog.Int("total_affected", result.TotalAffected))
-
-	return result, nil
+	return false
 }
 
-// classifySeverity determines the impact severity based on depth, edge type, and change type.
-func classifySeverity(depth int, edgeType, changeType string) string {
+// dependentSeverity rates one dependent by its depth, the edge it was reached by and the
+// change type. The blast radius as a whole is rated by SeverityConfig.
+func dependentSeverity(depth int, edgeType, changeType string) string {
 	if depth == 1 {
 		if changeType == "delete" {
 			switch edgeType {
@@ -202,3 +243,14 @@ func classifySeverity(depth int, edgeType, changeType string) string {
 	}
 	return "low"
 }
+
+// lowerSeverity returns the dependent severity one level below s, low staying low.
+func lowerSeverity(s string) string {
+	switch s {
+	case SeverityCritical:
+		return SeverityHigh
+	case SeverityHigh:
+		return SeverityMedium
+	}
+	return SeverityLow
+}
@@ -0,0 +1,34 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/graph"
+)
+
+func TestDependents_ConditionalPathsRatedLower(t *testing.T) {
+	// checkout calls charge unconditionally; beta only behind a flag, and report calls beta
+	lineage := &graph.LineageResult{
+		Nodes: []graph.LineageNode{{ID: "charge"}, {ID: "checkout"}, {ID: "beta"}, {ID: "report"}},
+		Edges: []graph.LineageEdge{
+			{SourceID: "checkout", TargetID: "charge", EdgeType: "calls"},
+			{SourceID: "beta", TargetID: "charge", EdgeType: "calls", Conditional: true},
+			{SourceID: "report", TargetID: "beta", EdgeType: "calls"},
+		},
+	}
+	direct, transitive := dependents(lineage, "charge", "delete", 5)
+
+	got := make(map[string]ImpactNode)
+	for _, n := range append(direct, transitive...) {
+		got[n.Symbol.ID] = n
+	}
+	if n := got["checkout"]; n.Conditional || n.Severity != SeverityCritical {
+		t.Errorf("checkout: got conditional=%v severity=%s, want an unconditional critical dependent", n.Conditional, n.Severity)
+	}
+	if n := got["beta"]; !n.Conditional || n.Severity != SeverityHigh {
+		t.Errorf("beta: got conditional=%v severity=%s, want a conditional high dependent", n.Conditional, n.Severity)
+	}
+	if n := got["report"]; !n.Conditional || n.Severity != SeverityLow {
+		t.Errorf("report: got conditional=%v severity=%s, want the flag carried to a low dependent", n.Conditional, n.Severity)
+	}
+}
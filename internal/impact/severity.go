@@ -0,0 +1,165 @@
+package impact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Blast-radius severities, from least to most severe.
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// SeverityThreshold is the blast radius from which a severity applies: at least Direct
+// direct or at least Transitive transitive dependents. A zero count is not checked.
+type SeverityThreshold struct {
+	Direct     int `json:"direct,omitempty"`
+	Transitive int `json:"transitive,omitempty"`
+}
+
+func (t SeverityThreshold) meets(direct, transitive int) bool {
+	return (t.Direct > 0 && direct >= t.Direct) || (t.Transitive > 0 && transitive >= t.Transitive)
+}
+
+// SeverityConfig rates the blast radius of a change. A change is low unless it meets the
+// medium, high or critical threshold, and then takes the highest it meets. With
+// CrossLanguage, an impact crossing a language bridge is rated one level higher: the
+// break surfaces in code built and deployed apart from the change.
+type SeverityConfig struct {
+	Medium        SeverityThreshold `json:"medium"`
+	High          SeverityThreshold `json:"high"`
+	Critical      SeverityThreshold `json:"critical"`
+	CrossLanguage bool              `json:"cross_language"`
+}
+
+// DefaultSeverityConfig returns the built-in thresholds.
+func DefaultSeverityConfig() SeverityConfig {
+	return SeverityConfig{
+		Medium:        SeverityThreshold{Direct: 3, Transitive: 10},
+		High:          SeverityThreshold{Direct: 10, Transitive: 50},
+		Critical:      SeverityThreshold{Direct: 25, Transitive: 200},
+		CrossLanguage: true,
+	}
+}
+
+// NewSeverityConfig returns the defaults with overrides applied, keyed by severity and
+// count, e.g. {"critical_direct": "20", "high_transitive": "30"}, or "cross_language".
+func NewSeverityConfig(overrides map[string]string) (SeverityConfig, error) {
+	c := DefaultSeverityConfig()
+	for key, value := range overrides {
+		if key == "cross_language" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return SeverityConfig{}, fmt.Errorf("cross_language must be true or false, got %q", value)
+			}
+			c.CrossLanguage = b
+			continue
+		}
+		level, count, _ := strings.Cut(key, "_")
+		t := c.threshold(level)
+		if t == nil || (count != "direct" && count != "transitive") {
+			return SeverityConfig{}, fmt.Errorf("unknown severity threshold %s: want medium, high or critical with _direct or _transitive", key)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return SeverityConfig{}, fmt.Errorf("severity threshold %s: %q is not a count", key, value)
+		}
+		if count == "direct" {
+			t.Direct = n
+		} else {
+			t.Transitive = n
+		}
+	}
+	return c, c.Validate()
+}
+
+// threshold returns the threshold of a severity level, nil for low or an unknown one.
+func (c *SeverityConfig) threshold(level string) *SeverityThreshold {
+	switch level {
+	case SeverityMedium:
+		return &c.Medium
+	case SeverityHigh:
+		return &c.High
+	case SeverityCritical:
+		return &c.Critical
+	}
+	return nil
+}
+
+// SeverityOverride replaces parts of a SeverityConfig for one analysis. A threshold
+// given replaces that level's whole threshold.
+type SeverityOverride struct {
+	Medium        *SeverityThreshold `json:"medium,omitempty"`
+	High          *SeverityThreshold `json:"high,omitempty"`
+	Critical      *SeverityThreshold `json:"critical,omitempty"`
+	CrossLanguage *bool              `json:"cross_language,omitempty"`
+}
+
+// With returns c with o applied, failing when the result is not a valid config.
+func (c SeverityConfig) With(o *SeverityOverride) (SeverityConfig, error) {
+	if o == nil {
+		return c, nil
+	}
+	if o.Medium != nil {
+		c.Medium = *o.Medium
+	}
+	if o.High != nil {
+		c.High = *o.High
+	}
+	if o.Critical != nil {
+		c.Critical = *o.Critical
+	}
+	if o.CrossLanguage != nil {
+		c.CrossLanguage = *o.CrossLanguage
+	}
+	return c, c.Validate()
+}
+
+// Validate checks that no count is negative and that each count set does not fall as
+// severity rises, so that a larger blast radius is never rated lower.
+func (c SeverityConfig) Validate() error {
+	levels := []struct {
+		name string
+		t    SeverityThreshold
+	}{{SeverityMedium, c.Medium}, {SeverityHigh, c.High}, {SeverityCritical, c.Critical}}
+	var lastDirect, lastTransitive int
+	var lastDirectName, lastTransitiveName string
+	for _, l := range levels {
+		if l.t.Direct < 0 || l.t.Transitive < 0 {
+			return fmt.Errorf("%s severity threshold: counts must not be negative", l.name)
+		}
+		if l.t.Direct > 0 {
+			if l.t.Direct < lastDirect {
+				return fmt.Errorf("%s severity threshold: direct %d is below %s's %d", l.name, l.t.Direct, lastDirectName, lastDirect)
+			}
+			lastDirect, lastDirectName = l.t.Direct, l.name
+		}
+		if l.t.Transitive > 0 {
+			if l.t.Transitive < lastTransitive {
+				return fmt.Errorf("%s severity threshold: transitive %d is below %s's %d", l.name, l.t.Transitive, lastTransitiveName, lastTransitive)
+			}
+			lastTransitive, lastTransitiveName = l.t.Transitive, l.name
+		}
+	}
+	return nil
+}
+
+// Classify rates a blast radius of direct and transitive dependents, raised a level
+// when crossLanguage and the config counts language bridges.
+func (c SeverityConfig) Classify(direct, transitive int, crossLanguage bool) string {
+	levels := []string{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+	level := 0
+	for i, t := range []SeverityThreshold{c.Medium, c.High, c.Critical} {
+		if t.meets(direct, transitive) {
+			level = i + 1
+		}
+	}
+	if crossLanguage && c.CrossLanguage && level < len(levels)-1 {
+		level++
+	}
+	return levels[level]
+}
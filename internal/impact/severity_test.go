@@ -0,0 +1,44 @@
+package impact
+
+import "testing"
+
+func TestNewSeverityConfig_Overrides(t *testing.T) {
+	c, err := NewSeverityConfig(map[string]string{"critical_direct": "20", "high_transitive": "30", "cross_language": "false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Critical.Direct != 20 || c.High.Transitive != 30 || c.CrossLanguage {
+		t.Errorf("overrides not applied: %+v", c)
+	}
+	if c.Medium != DefaultSeverityConfig().Medium {
+		t.Errorf("expected unset thresholds to keep their defaults, got %+v", c.Medium)
+	}
+	if got := c.Classify(20, 0, true); got != SeverityCritical {
+		t.Errorf("20 direct: got %s, want critical", got)
+	}
+
+	for _, bad := range []map[string]string{
+		{"severe_direct": "5"},
+		{"high_indirect": "5"},
+		{"high_direct": "many"},
+		{"medium_direct": "-1"},
+		{"medium_transitive": "500"}, // above high and critical
+		{"cross_language": "sometimes"},
+	} {
+		if _, err := NewSeverityConfig(bad); err == nil {
+			t.Errorf("expected %v to be rejected", bad)
+		}
+	}
+}
+
+func TestCrossesLanguages(t *testing.T) {
+	root := SymbolSummary{Language: "tsql"}
+	sameLanguage := []ImpactNode{{Symbol: SymbolSummary{Language: "tsql"}}}
+	otherLanguage := []ImpactNode{{Symbol: SymbolSummary{Language: "csharp"}}}
+	if crossesLanguages(root, sameLanguage, nil) {
+		t.Error("expected dependents in the root's language not to cross languages")
+	}
+	if !crossesLanguages(root, sameLanguage, otherLanguage) {
+		t.Error("expected a transitive C# dependent of T-SQL to cross languages")
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 // AnalyticsStage computes graph analytics after embedding.
-// Runs: degree counts, PageRank, layer classification, project summaries, cross-language bridges.
+// Runs: degree counts, PageRank, layer classification, project summaries, cross-language bridges,
+// then PII auto-tagging of columns.
 type AnalyticsStage struct {
 	engine *analytics.Engine
 	logger *slog.Logger
@@ -28,5 +29,13 @@ func (s *AnalyticsStage) Execute(ctx context.Context, rc *IndexRunContext) error
 		return fmt.Errorf("compute analytics: %w", err)
 	}
 
+	patterns, err := analytics.CompilePIIPatterns(rc.PIIPatterns)
+	if err != nil {
+		s.logger.Warn("ignoring invalid pii_patterns", slog.String("error", err.Error()))
+	}
+	if err := s.engine.TagPIIColumns(ctx, rc.ProjectID, patterns); err != nil {
+		return fmt.Errorf("tag pii columns: %w", err)
+	}
+
 	return nil
 }
@@ -15,14 +15,23 @@ import (
 
 // CloneStage fetches source files (ZIP extract, git clone, or S3 sync) into a local work directory.
 type CloneStage struct {
-	store   *store.Store
-	zipConn *connectors.ZipConnector
-	gitConn *connectors.GitLabConnector
-	s3Conn  *connectors.S3Connector
+	store      *store.Store
+	zipConn    *connectors.ZipConnector
+	gitConn    *connectors.GitLabConnector
+	githubConn *connectors.GitHubConnector
+	s3Conn     *connectors.S3Connector
 }
 
-func NewCloneStage(s *store.Store, zipConn *connectors.ZipConnector, gitConn *connectors.GitLabConnector, s3Conn *connectors.S3Connector) *CloneStage {
-	return &CloneStage{store: s, zipConn: zipConn, gitConn: gitConn, s3Conn: s3Conn}
+// gitCloner is the connector behaviour the clone stage needs for git sources.
+type gitCloner interface {
+	Clone(ctx context.Context, repoURL, destDir string) error
+	CloneFull(ctx context.Context, repoURL, destDir string) error
+}
+
+// NewCloneStage creates the clone stage. githubConn and s3Conn may be nil when those
+// connectors are not configured; github.com sources then go through gitConn.
+func NewCloneStage(s *store.Store, zipConn *connectors.ZipConnector, gitConn *connectors.GitLabConnector, githubConn *connectors.GitHubConnector, s3Conn *connectors.S3Connector) *CloneStage {
+	return &CloneStage{store: s, zipConn: zipConn, gitConn: gitConn, githubConn: githubConn, s3Conn: s3Conn}
 }
 
 func (s *CloneStage) Name() string { return "clone" }
@@ -56,6 +65,7 @@ func (s *CloneStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 		if source.ConnectionUri == nil || *source.ConnectionUri == "" {
 			return fmt.Errorf("git source missing connection_uri")
 		}
+		gitConn := s.gitConnector(*source.ConnectionUri)
 
 		// Check for incremental indexing
 		previousSHA := ""
@@ -65,7 +75,7 @@ func (s *CloneStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 
 		if previousSHA != "" {
 			// Full clone needed for git diff
-			if err := s.gitConn.CloneFull(ctx, *source.ConnectionUri, workDir); err != nil {
+			if err := gitConn.CloneFull(ctx, *source.ConnectionUri, workDir); err != nil {
 				return fmt.Errorf("git clone (full): %w", err)
 			}
 
@@ -80,9 +90,15 @@ func (s *CloneStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 				rc.ChangedFiles = delta.ChangedFiles
 				rc.DeletedFiles = delta.DeletedFiles
 			}
+		} else if rc.TrackOwnership {
+			// First index with ownership tracking — full history for git log
+			if err := gitConn.CloneFull(ctx, *source.ConnectionUri, workDir); err != nil {
+				return fmt.Errorf("git clone (full): %w", err)
+			}
+			rc.CurrentSHA = gitHeadSHA(ctx, workDir)
 		} else {
 			// First index — shallow clone
-			if err := s.gitConn.Clone(ctx, *source.ConnectionUri, workDir); err != nil {
+			if err := gitConn.Clone(ctx, *source.ConnectionUri, workDir); err != nil {
 				return fmt.Errorf("git clone: %w", err)
 			}
 			// Capture HEAD SHA for next incremental run
@@ -110,6 +126,15 @@ func (s *CloneStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 	return nil
 }
 
+// gitConnector picks the connector that clones a repository: the GitHub connector for
+// github.com URLs when it is configured, the GitLab one otherwise.
+func (s *CloneStage) gitConnector(repoURL string) gitCloner {
+	if s.githubConn != nil && connectors.IsGitHubURL(repoURL) {
+		return s.githubConn
+	}
+	return s.gitConn
+}
+
 // gitHeadSHA reads the current HEAD SHA from a git repo.
 func gitHeadSHA(ctx context.Context, workDir string) string {
 	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
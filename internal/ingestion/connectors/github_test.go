@@ -0,0 +1,177 @@
+package connectors
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appconfig "github.com/maraichr/lattice/internal/config"
+)
+
+func TestIsGitHubURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://github.com/acme/app":          true,
+		"https://GitHub.com/acme/app.git@main": true,
+		"git@github.com:acme/app.git":          true,
+		"ssh://git@github.com/acme/app":        true,
+		"https://gitlab.com/acme/app":          false,
+		"https://github.example.com/acme/app":  false,
+		"acme/app":                             false,
+	}
+	for uri, want := range cases {
+		if got := IsGitHubURL(uri); got != want {
+			t.Errorf("IsGitHubURL(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
+
+func TestGitHubParseSourceConfig(t *testing.T) {
+	g := NewGitHubConnector(appconfig.GitHubConfig{})
+	cases := []struct {
+		uri, repo, ref string
+	}{
+		{"https://github.com/acme/app", "https://github.com/acme/app", ""},
+		{"https://github.com/acme/app.git@v1.2.0", "https://github.com/acme/app.git", "v1.2.0"},
+		{"https://github.com/acme/app/tree/feature/login", "https://github.com/acme/app", "feature/login"},
+		{"git@github.com:acme/app.git", "git@github.com:acme/app.git", ""},
+		{"git@github.com:acme/app@release", "git@github.com:acme/app", "release"},
+	}
+	for _, c := range cases {
+		repo, ref := g.ParseSourceConfig(c.uri)
+		if repo != c.repo || ref != c.ref {
+			t.Errorf("ParseSourceConfig(%q) = %q, %q; want %q, %q", c.uri, repo, ref, c.repo, c.ref)
+		}
+	}
+}
+
+func TestGitHubClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+
+	// A repository with two commits on main and one on a feature branch.
+	origin := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = origin
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(origin, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "--quiet", "--initial-branch=main")
+	write("a.go", "package a\n")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "one")
+	write("a.go", "package a // two\n")
+	git("commit", "--quiet", "-am", "two")
+	git("checkout", "--quiet", "-b", "feature")
+	write("b.go", "package a\n")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "three")
+	git("checkout", "--quiet", "main")
+
+	g := NewGitHubConnector(appconfig.GitHubConfig{})
+	repoURL := "file://" + origin
+
+	t.Run("default branch is shallow", func(t *testing.T) {
+		dest := t.TempDir()
+		if err := g.Clone(ctx, repoURL, dest); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dest, "b.go")); !os.IsNotExist(err) {
+			t.Error("clone of the default branch has the feature branch's file")
+		}
+		out, err := exec.Command("git", "-C", dest, "rev-list", "--count", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(out)) != "1" {
+			t.Errorf("clone has %s commits, want 1", strings.TrimSpace(string(out)))
+		}
+	})
+
+	t.Run("ref", func(t *testing.T) {
+		dest := t.TempDir()
+		if err := g.Clone(ctx, repoURL+"@feature", dest); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dest, "b.go")); err != nil {
+			t.Errorf("clone of the feature branch lacks b.go: %v", err)
+		}
+	})
+
+	t.Run("full clone of ref", func(t *testing.T) {
+		dest := t.TempDir()
+		if err := g.CloneFull(ctx, repoURL+"/tree/feature", dest); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command("git", "-C", dest, "rev-list", "--count", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(out)) != "3" {
+			t.Errorf("full clone has %s commits, want 3", strings.TrimSpace(string(out)))
+		}
+	})
+
+	t.Run("refs git would read as options are refused", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "ran")
+		for _, uri := range []string{
+			repoURL + "@--upload-pack=touch " + marker,
+			repoURL + "/tree/main..feature",
+		} {
+			if err := g.Clone(ctx, uri, t.TempDir()); err == nil {
+				t.Errorf("clone of %q succeeded", uri)
+			}
+			if err := g.CloneFull(ctx, uri, filepath.Join(t.TempDir(), "full")); err == nil {
+				t.Errorf("full clone of %q succeeded", uri)
+			}
+		}
+		if _, err := os.Stat(marker); err == nil {
+			t.Error("a ref was run as a git option")
+		}
+	})
+
+	t.Run("failure leaves an empty work dir", func(t *testing.T) {
+		dest := t.TempDir()
+		if err := g.Clone(ctx, repoURL+"@missing", dest); err == nil {
+			t.Fatal("clone of a missing ref succeeded")
+		}
+		entries, err := os.ReadDir(dest)
+		if err != nil {
+			t.Fatalf("work dir removed: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("work dir left with %d entries", len(entries))
+		}
+	})
+}
+
+func TestValidateGitSource(t *testing.T) {
+	for uri, ok := range map[string]bool{
+		"https://github.com/org/repo":                    true,
+		"https://github.com/org/repo@v1.2.0":             true,
+		"https://github.com/org/repo/tree/feature/login": true,
+		"https://github.com/org/repo@--upload-pack=x":    false,
+		"https://github.com/org/repo/tree/-x":            false,
+		"--config=core.sshCommand=x":                     false,
+		"":                                               false,
+	} {
+		if err := ValidateGitSource(uri); (err == nil) != ok {
+			t.Errorf("ValidateGitSource(%q) = %v, want ok=%v", uri, err, ok)
+		}
+	}
+}
@@ -20,7 +20,7 @@ func NewGitLabConnector() *GitLabConnector {
 func (g *GitLabConnector) Clone(ctx context.Context, repoURL, destDir string) error {
 	cloneURL := injectToken(repoURL)
 
-	cmd := exec.CommandContext(ctx, "git", "clone", "--depth=1", cloneURL, destDir)
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth=1", "--", cloneURL, destDir)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -35,7 +35,7 @@ func (g *GitLabConnector) Clone(ctx context.Context, repoURL, destDir string) er
 func (g *GitLabConnector) CloneFull(ctx context.Context, repoURL, destDir string) error {
 	cloneURL := injectToken(repoURL)
 
-	cmd := exec.CommandContext(ctx, "git", "clone", cloneURL, destDir)
+	cmd := exec.CommandContext(ctx, "git", "clone", "--", cloneURL, destDir)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
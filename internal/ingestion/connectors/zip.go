@@ -9,26 +9,27 @@ import (
 	"path/filepath"
 	"strings"
 
-	minioclient "github.com/maraichr/lattice/internal/store/minio"
+	"github.com/maraichr/lattice/internal/store"
 )
 
 // ZipConnector handles ZIP file upload and extraction.
 type ZipConnector struct {
-	minio *minioclient.Client
+	blobs store.BlobStore
 }
 
-func NewZipConnector(minio *minioclient.Client) *ZipConnector {
-	return &ZipConnector{minio: minio}
+// NewZipConnector creates a connector storing archives in blobs, MinIO by default.
+func NewZipConnector(blobs store.BlobStore) *ZipConnector {
+	return &ZipConnector{blobs: blobs}
 }
 
-// Upload streams the ZIP file to MinIO object storage.
+// Upload streams the ZIP file to the blob store.
 func (z *ZipConnector) Upload(ctx context.Context, objectName string, reader io.Reader, size int64) error {
-	return z.minio.UploadFile(ctx, objectName, reader, size)
+	return z.blobs.UploadFile(ctx, objectName, reader, size)
 }
 
-// Extract downloads a ZIP from MinIO and extracts it to a local directory.
+// Extract downloads a ZIP from the blob store and extracts it to a local directory.
 func (z *ZipConnector) Extract(ctx context.Context, objectName, destDir string) error {
-	reader, err := z.minio.DownloadFile(ctx, objectName)
+	reader, err := z.blobs.DownloadFile(ctx, objectName)
 	if err != nil {
 		return fmt.Errorf("download zip: %w", err)
 	}
@@ -0,0 +1,112 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/valkey-io/valkey-go"
+)
+
+// DeadLetterStream holds the ingestion messages that failed MaxRetries times, until
+// they are replayed or dropped.
+const DeadLetterStream = "lattice:ingest:dlq"
+
+// DeadLetter is a message in DeadLetterStream.
+type DeadLetter struct {
+	ID       string // entry ID in DeadLetterStream
+	Message  IngestMessage
+	Error    string // the error of its last attempt
+	FailedAt time.Time
+}
+
+// DeadLetterQueue lists and replays dead-lettered ingestion messages.
+type DeadLetterQueue struct {
+	streams streams
+}
+
+func NewDeadLetterQueue(client valkey.Client) *DeadLetterQueue {
+	return &DeadLetterQueue{streams: valkeyStreams{client}}
+}
+
+// addDeadLetter moves a message that failed with cause to DeadLetterStream.
+func addDeadLetter(ctx context.Context, s streams, msg IngestMessage, cause error) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+	if _, err := s.xadd(ctx, DeadLetterStream, map[string]string{
+		"data":      string(data),
+		"error":     cause.Error(),
+		"failed_at": time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return fmt.Errorf("xadd: %w", err)
+	}
+	return nil
+}
+
+// List returns the dead-lettered messages of a project, oldest first, or of every
+// project when projectID is uuid.Nil.
+func (q *DeadLetterQueue) List(ctx context.Context, projectID uuid.UUID) ([]DeadLetter, error) {
+	return q.rangeEntries(ctx, "-", "+", projectID)
+}
+
+// Get returns the dead-lettered message with entry ID id.
+func (q *DeadLetterQueue) Get(ctx context.Context, id string) (DeadLetter, error) {
+	letters, err := q.rangeEntries(ctx, id, id, uuid.Nil)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if len(letters) == 0 {
+		return DeadLetter{}, fmt.Errorf("no dead-lettered message %s", id)
+	}
+	return letters[0], nil
+}
+
+// Replay enqueues a dead-lettered message back onto StreamName with its attempts reset,
+// after modify (if not nil) has adjusted it, e.g. to skip the stage it failed in, and
+// then removes it from DeadLetterStream. It returns the new entry ID in StreamName. The
+// message is enqueued before it is removed, so a failure in between leaves it in both
+// rather than in neither.
+func (q *DeadLetterQueue) Replay(ctx context.Context, id string, modify func(*IngestMessage)) (string, error) {
+	letter, err := q.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	msg := letter.Message
+	msg.Attempt = 0
+	if modify != nil {
+		modify(&msg)
+	}
+
+	newID, err := enqueue(ctx, q.streams, msg)
+	if err != nil {
+		return "", fmt.Errorf("re-enqueue %s: %w", id, err)
+	}
+	if err := q.streams.xdel(ctx, DeadLetterStream, id); err != nil {
+		return newID, fmt.Errorf("remove %s from dead letters (replayed as %s): %w", id, newID, err)
+	}
+	return newID, nil
+}
+
+func (q *DeadLetterQueue) rangeEntries(ctx context.Context, start, end string, projectID uuid.UUID) ([]DeadLetter, error) {
+	entries, err := q.streams.xrange(ctx, DeadLetterStream, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("xrange: %w", err)
+	}
+	var letters []DeadLetter
+	for _, e := range entries {
+		letter := DeadLetter{ID: e.ID, Error: e.FieldValues["error"]}
+		if err := json.Unmarshal([]byte(e.FieldValues["data"]), &letter.Message); err != nil {
+			return nil, fmt.Errorf("unmarshal dead letter %s: %w", e.ID, err)
+		}
+		if projectID != uuid.Nil && letter.Message.ProjectID != projectID {
+			continue
+		}
+		letter.FailedAt, _ = time.Parse(time.RFC3339, e.FieldValues["failed_at"])
+		letters = append(letters, letter)
+	}
+	return letters, nil
+}
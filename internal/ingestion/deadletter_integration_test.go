@@ -0,0 +1,78 @@
+//go:build integration
+
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/valkey-io/valkey-go"
+)
+
+func setupValkey(t *testing.T) valkey.Client {
+	t.Helper()
+	addr := os.Getenv("TEST_VALKEY_ADDR")
+	if addr == "" {
+		t.Fatal("TEST_VALKEY_ADDR not set")
+	}
+	client, err := valkey.NewClient(valkey.ClientOption{InitAddress: []string{addr}})
+	if err != nil {
+		t.Skipf("valkey not available: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestDeadLetterQueue_ReplayReenqueuesAndRemoves(t *testing.T) {
+	ctx := context.Background()
+	client := setupValkey(t)
+	dlq := NewDeadLetterQueue(client)
+
+	projectID := uuid.New()
+	msg := IngestMessage{
+		IndexRunID: uuid.New(), ProjectID: projectID, SourceID: uuid.New(),
+		SourceType: "git", Trigger: "webhook", Attempt: MaxRetries - 1,
+	}
+	if err := addDeadLetter(ctx, valkeyStreams{client}, msg, errors.New("stage embed failed: rate limited")); err != nil {
+		t.Fatalf("dead-letter: %v", err)
+	}
+
+	letters, err := dlq.List(ctx, projectID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(letters) != 1 || letters[0].Message.IndexRunID != msg.IndexRunID || letters[0].Error == "" || letters[0].FailedAt.IsZero() {
+		t.Fatalf("expected the dead letter with its error and time, got %+v", letters)
+	}
+
+	newID, err := dlq.Replay(ctx, letters[0].ID, func(m *IngestMessage) {
+		m.SkipStages = []string{"embed"}
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Do(context.Background(), client.B().Xdel().Key(StreamName).Id(newID).Build())
+	})
+
+	entries, err := client.Do(ctx, client.B().Xrange().Key(StreamName).Start(newID).End(newID).Build()).AsXRange()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected the replayed message on %s, got %v, %v", StreamName, entries, err)
+	}
+	var replayed IngestMessage
+	if err := json.Unmarshal([]byte(entries[0].FieldValues["data"]), &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if replayed.IndexRunID != msg.IndexRunID || replayed.Attempt != 0 || !slices.Equal(replayed.SkipStages, []string{"embed"}) {
+		t.Errorf("expected the message with attempts reset and embed skipped, got %+v", replayed)
+	}
+
+	if letters, err := dlq.List(ctx, projectID); err != nil || len(letters) != 0 {
+		t.Errorf("expected the replayed message removed from the dead letters, got %+v, %v", letters, err)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
+
 	"github.com/maraichr/lattice/internal/embedding"
 	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
-// EmbedStage generates vector embeddings for symbols.
+// EmbedStage generates vector embeddings for symbols. Re-indexing recreates a file's
+// symbols, so vectors are matched to them by content hash and only symbols whose
+// embedding text changed are sent to the model.
 type EmbedStage struct {
 	client embedding.Embedder
 	store  *store.Store
@@ -23,11 +28,86 @@ func NewEmbedStage(client embedding.Embedder, s *store.Store, logger *slog.Logge
 func (s *EmbedStage) Name() string { return "embed" }
 
 func (s *EmbedStage) Execute(ctx context.Context, rc *IndexRunContext) error {
-	count, err := embedding.EmbedSymbols(ctx, s.client, s.store, rc.ProjectID, s.logger)
+	symbols, err := s.store.ListSymbolsByProject(ctx, rc.ProjectID)
+	if err != nil {
+		return fmt.Errorf("list symbols: %w", err)
+	}
+	hashes, err := s.store.ListEmbeddingHashes(ctx, rc.ProjectID)
+	if err != nil {
+		return fmt.Errorf("list embedding hashes: %w", err)
+	}
+
+	plan := planEmbeddings(rc.EmbedKinds.Filter(symbols), hashes, s.client.ModelID())
+
+	if len(plan.copies.SymbolIds) > 0 {
+		if err := s.store.CopySymbolEmbeddings(ctx, plan.copies); err != nil {
+			return fmt.Errorf("reuse embeddings: %w", err)
+		}
+	}
+	embedded := 0
+	if len(plan.embed) > 0 {
+		embedded, err = embedding.EmbedSymbolList(ctx, s.client, s.store, plan.embed)
+		if err != nil {
+			return fmt.Errorf("embed symbols: %w", err)
+		}
+	}
+	// Vectors of deleted symbols are only kept until this run has had a chance to reuse them
+	pruned, err := s.store.DeleteOrphanedEmbeddings(ctx, rc.ProjectID)
 	if err != nil {
-		return fmt.Errorf("embed symbols: %w", err)
+		return fmt.Errorf("delete orphaned embeddings: %w", err)
 	}
 
-	s.logger.Info("embedded symbols", slog.Int("count", count))
+	s.logger.Info("embedded symbols",
+		slog.Int("embedded", embedded),
+		slog.Int("reused", len(plan.copies.SymbolIds)),
+		slog.Int("unchanged", plan.unchanged),
+		slog.Int64("pruned", pruned))
 	return nil
 }
+
+// embeddingPlan is what the embed stage does with each symbol.
+type embeddingPlan struct {
+	// unchanged symbols already have a vector and are left alone
+	unchanged int
+	// copies gives recreated symbols the vector of an identical one
+	copies postgres.CopySymbolEmbeddingsParams
+	// embed is the symbols that need a new vector
+	embed []postgres.Symbol
+}
+
+// planEmbeddings diffs symbols against the project's stored vectors for model. A symbol
+// keeps its vector unless its content hash changed. One without a vector takes a copy
+// of any stored vector with the same hash, typically the one its previous incarnation
+// left behind. Vectors without a hash or from another model are left for Reembed.
+func planEmbeddings(symbols []postgres.Symbol, hashes []postgres.ListEmbeddingHashesRow, model string) embeddingPlan {
+	attached := make(map[uuid.UUID]postgres.ListEmbeddingHashesRow, len(hashes))
+	byHash := make(map[string]uuid.UUID, len(hashes))
+	for _, h := range hashes {
+		if h.SymbolID.Valid {
+			attached[uuid.UUID(h.SymbolID.Bytes)] = h
+		}
+		if h.Model == model && h.ContentHash != nil {
+			byHash[*h.ContentHash] = h.ID
+		}
+	}
+
+	var plan embeddingPlan
+	for _, sym := range symbols {
+		hash := embedding.ContentHash(sym)
+		if h, ok := attached[sym.ID]; ok {
+			if h.Model != model || h.ContentHash == nil || *h.ContentHash == hash {
+				plan.unchanged++
+			} else {
+				plan.embed = append(plan.embed, sym)
+			}
+			continue
+		}
+		if source, ok := byHash[hash]; ok {
+			plan.copies.SourceIds = append(plan.copies.SourceIds, source)
+			plan.copies.SymbolIds = append(plan.copies.SymbolIds, sym.ID)
+			continue
+		}
+		plan.embed = append(plan.embed, sym)
+	}
+	return plan
+}
@@ -0,0 +1,57 @@
+package ingestion
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestPlanEmbeddings(t *testing.T) {
+	sig := "(@OrderID INT)"
+	proc := func(name string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: name, QualifiedName: "dbo." + name, Kind: "procedure", Signature: &sig}
+	}
+	stored := func(sym postgres.Symbol, attached bool, model string) postgres.ListEmbeddingHashesRow {
+		hash := embedding.ContentHash(sym)
+		row := postgres.ListEmbeddingHashesRow{ID: uuid.New(), Model: model, ContentHash: &hash}
+		if attached {
+			row.SymbolID = pgtype.UUID{Bytes: sym.ID, Valid: true}
+		}
+		return row
+	}
+
+	kept := proc("usp_GetOrder")
+	edited := proc("usp_SaveOrder")
+	editedRow := stored(edited, true, "m")
+	newDoc := "Saves an order"
+	edited.DocComment = &newDoc
+	// Re-indexing recreated the symbol under a new ID, orphaning its vector
+	previous := proc("usp_CancelOrder")
+	orphan := stored(previous, false, "m")
+	recreated := previous
+	recreated.ID = uuid.New()
+	added := proc("usp_ShipOrder")
+	otherModel := proc("usp_ListOrders")
+
+	hashes := []postgres.ListEmbeddingHashesRow{stored(kept, true, "m"), editedRow, orphan, stored(otherModel, true, "old")}
+	plan := planEmbeddings([]postgres.Symbol{kept, edited, recreated, added, otherModel}, hashes, "m")
+
+	if plan.unchanged != 2 {
+		t.Errorf("expected the unchanged and other-model symbols to be skipped, got %d", plan.unchanged)
+	}
+	if !slices.Equal(plan.copies.SymbolIds, []uuid.UUID{recreated.ID}) || !slices.Equal(plan.copies.SourceIds, []uuid.UUID{orphan.ID}) {
+		t.Errorf("expected the recreated symbol to reuse the orphaned vector, got %+v", plan.copies)
+	}
+	var embed []string
+	for _, sym := range plan.embed {
+		embed = append(embed, sym.Name)
+	}
+	if !slices.Equal(embed, []string{"usp_SaveOrder", "usp_ShipOrder"}) {
+		t.Errorf("expected the edited and added symbols to be embedded, got %v", embed)
+	}
+}
@@ -0,0 +1,66 @@
+package ingestion
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// goImportPath returns the import path of the Go package in dir, a slash-separated
+// directory of the work dir: the module path of the nearest go.mod at or above dir, joined
+// with the rest of dir. It returns "" when dir is in no module. Module paths are cached
+// in rc.GoModules.
+func goImportPath(rc *IndexRunContext, dir string) string {
+	rest := ""
+	for {
+		if module := goModulePath(rc, dir); module != "" {
+			return path.Join(module, rest)
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return ""
+		}
+		rest = path.Join(path.Base(dir), rest)
+		dir = path.Dir(dir)
+	}
+}
+
+// goModulePath returns the module path declared by the go.mod in dir, or "".
+func goModulePath(rc *IndexRunContext, dir string) string {
+	if module, ok := rc.GoModules[dir]; ok {
+		return module
+	}
+	module := ""
+	if data, err := os.ReadFile(filepath.Join(rc.WorkDir, filepath.FromSlash(dir), "go.mod")); err == nil {
+		module = parseModulePath(data)
+	}
+	if rc.GoModules == nil {
+		rc.GoModules = make(map[string]string)
+	}
+	rc.GoModules[dir] = module
+	return module
+}
+
+// parseModulePath returns the path of the module directive of a go.mod, or "".
+func parseModulePath(gomod []byte) string {
+	sc := bufio.NewScanner(bytes.NewReader(gomod))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t' && rest[0] != '"') {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(rest); err == nil {
+			return unquoted
+		}
+		return rest
+	}
+	return ""
+}
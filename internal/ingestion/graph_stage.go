@@ -9,7 +9,11 @@ import (
 	"github.com/maraichr/lattice/internal/store"
 )
 
-// GraphStage syncs symbols and edges from PostgreSQL to Neo4j.
+// GraphStageName names the GraphStage in IngestMessage.Stages and SkipStages.
+const GraphStageName = "graph_build"
+
+// GraphStage syncs symbols and edges from PostgreSQL to Neo4j. The sync is keyed on
+// stable IDs and the index run ID, so a retried stage converges instead of duplicating.
 type GraphStage struct {
 	store  *store.Store
 	graph  *graph.Client
@@ -20,7 +24,7 @@ func NewGraphStage(s *store.Store, g *graph.Client, logger *slog.Logger) *GraphS
 	return &GraphStage{store: s, graph: g, logger: logger}
 }
 
-func (s *GraphStage) Name() string { return "graph_build" }
+func (s *GraphStage) Name() string { return GraphStageName }
 
 func (s *GraphStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 	// Load all files for project
@@ -48,29 +52,34 @@ func (s *GraphStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 
 	// Sync files
 	s.logger.Info("neo4j: syncing files", slog.Int("count", len(files)))
-	if err := s.graph.SyncFiles(ctx, rc.ProjectID, files); err != nil {
+	if err := s.graph.SyncFiles(ctx, rc.ProjectID, rc.IndexRunID, files); err != nil {
 		return fmt.Errorf("sync files to neo4j: %w", err)
 	}
 	s.logger.Info("neo4j: files synced")
 
 	// Sync symbols
 	s.logger.Info("neo4j: syncing symbols", slog.Int("count", len(symbols)))
-	if err := s.graph.SyncSymbols(ctx, rc.ProjectID, symbols); err != nil {
+	if err := s.graph.SyncSymbols(ctx, rc.ProjectID, rc.IndexRunID, symbols); err != nil {
 		return fmt.Errorf("sync symbols to neo4j: %w", err)
 	}
 	s.logger.Info("neo4j: symbols synced")
 
 	// Sync edges (DEPENDS_ON relationships)
 	s.logger.Info("neo4j: syncing edges", slog.Int("count", len(edges)))
-	if err := s.graph.SyncEdges(ctx, rc.ProjectID, edges); err != nil {
+	if err := s.graph.SyncEdges(ctx, rc.ProjectID, rc.IndexRunID, edges); err != nil {
 		return fmt.Errorf("sync edges to neo4j: %w", err)
 	}
 	s.logger.Info("neo4j: edges synced")
 
 	// Sync column-level edges (COLUMN_FLOW relationships)
-	if err := s.graph.SyncColumnEdges(ctx, rc.ProjectID, edges); err != nil {
+	if err := s.graph.SyncColumnEdges(ctx, rc.ProjectID, rc.IndexRunID, edges); err != nil {
 		return fmt.Errorf("sync column edges to neo4j: %w", err)
 	}
 
+	// Everything for this run is in place; drop what earlier runs left behind.
+	if err := s.graph.PruneStale(ctx, rc.ProjectID, rc.IndexRunID); err != nil {
+		return fmt.Errorf("prune neo4j: %w", err)
+	}
+
 	return nil
 }
@@ -6,10 +6,11 @@ import (
 	"log/slog"
 
 	"github.com/maraichr/lattice/internal/lineage"
-	"github.com/maraichr/lattice/internal/parser"
 )
 
 // LineageStage builds column-level lineage edges from parsed column references.
+// Incremental runs replace only the lineage derived from changed and deleted files; full
+// runs replace all the lineage derived from the source.
 type LineageStage struct {
 	engine *lineage.Engine
 	logger *slog.Logger
@@ -22,18 +23,25 @@ func NewLineageStage(e *lineage.Engine, logger *slog.Logger) *LineageStage {
 func (s *LineageStage) Name() string { return "lineage" }
 
 func (s *LineageStage) Execute(ctx context.Context, rc *IndexRunContext) error {
-	// Gather all column references from parse results
-	var allColRefs []parser.ColumnReference
-	for _, fr := range rc.ParseResults {
-		allColRefs = append(allColRefs, fr.ColumnReferences...)
-	}
+	files, changed := columnLineageScope(rc)
 
-	if len(allColRefs) == 0 {
+	refs := 0
+	for _, f := range files {
+		refs += len(f.Refs)
+	}
+	if rc.Incremental && refs == 0 && len(changed) == 0 {
 		s.logger.Info("no column references to process")
 		return nil
 	}
 
-	created, err := s.engine.BuildColumnLineage(ctx, rc.ProjectID, allColRefs)
+	// A full run also replaces lineage from files the source no longer has
+	var created int
+	var err error
+	if rc.Incremental {
+		created, err = s.engine.ReplaceColumnLineage(ctx, rc.ProjectID, rc.SourceID, changed, files)
+	} else {
+		created, err = s.engine.RebuildColumnLineage(ctx, rc.ProjectID, rc.SourceID, files)
+	}
 	if err != nil {
 		return fmt.Errorf("build column lineage: %w", err)
 	}
@@ -41,3 +49,22 @@ func (s *LineageStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 	rc.EdgesFound += created
 	return nil
 }
+
+// columnLineageScope returns the column references of each parsed file and, for an
+// incremental run, the files whose existing lineage is replaced: the changed and deleted
+// ones. Full runs need no file list: they replace all of the source's lineage.
+func columnLineageScope(rc *IndexRunContext) ([]lineage.FileColumnRefs, []string) {
+	var files []lineage.FileColumnRefs
+	for _, fr := range rc.ParseResults {
+		if len(fr.ColumnReferences) > 0 {
+			files = append(files, lineage.FileColumnRefs{Path: fr.Path, Refs: fr.ColumnReferences})
+		}
+	}
+	if !rc.Incremental {
+		return files, nil
+	}
+	changed := make([]string, 0, len(rc.ChangedFiles)+len(rc.DeletedFiles))
+	changed = append(changed, rc.ChangedFiles...)
+	changed = append(changed, rc.DeletedFiles...)
+	return files, changed
+}
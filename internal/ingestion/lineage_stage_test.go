@@ -0,0 +1,98 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/builtin"
+)
+
+func TestColumnLineageScope_IncrementalReplacesOnlyChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"orders.sql": `
+CREATE PROCEDURE dbo.usp_ArchiveOrders
+AS
+BEGIN
+    INSERT INTO dbo.OrdersArchive (OrderID, Total)
+    SELECT OrderID, Total FROM dbo.Orders;
+END
+GO
+`,
+		"customers.sql": `
+CREATE PROCEDURE dbo.usp_CopyCustomers
+AS
+BEGIN
+    INSERT INTO dbo.CustomersCopy (CustomerID, Name)
+    SELECT CustomerID, UPPER(Name) FROM dbo.Customers;
+END
+GO
+`,
+	}
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	parse := func(rc *IndexRunContext, rel string) parser.FileResult {
+		t.Helper()
+		abs := filepath.Join(dir, rel)
+		info, err := os.Stat(abs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fr := stage.parseFile(registry, rc, abs, rel, info)
+		if fr == nil || len(fr.ColumnReferences) == 0 {
+			t.Fatalf("expected column references from %s", rel)
+		}
+		return *fr
+	}
+	for rel, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A full run rebuilds every file's lineage and needs no file list.
+	full := &IndexRunContext{WorkDir: dir}
+	full.ParseResults = []parser.FileResult{parse(full, "orders.sql"), parse(full, "customers.sql")}
+	scoped, changed := columnLineageScope(full)
+	if len(scoped) != 2 || changed != nil {
+		t.Fatalf("expected both files rebuilt without a file list, got %d files, changed %v", len(scoped), changed)
+	}
+
+	// Changing one file replaces only its lineage.
+	if err := os.WriteFile(filepath.Join(dir, "orders.sql"), []byte(`
+CREATE PROCEDURE dbo.usp_ArchiveOrders
+AS
+BEGIN
+    INSERT INTO dbo.OrdersArchive (OrderID, Total, Status)
+    SELECT OrderID, Total, Status FROM dbo.Orders;
+END
+GO
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	incr := &IndexRunContext{WorkDir: dir, Incremental: true, ChangedFiles: []string{"orders.sql"}, DeletedFiles: []string{"legacy.sql"}}
+	incr.ParseResults = []parser.FileResult{parse(incr, "orders.sql")}
+	scoped, changed = columnLineageScope(incr)
+
+	if !slices.Equal(changed, []string{"orders.sql", "legacy.sql"}) {
+		t.Errorf("expected lineage of the changed and deleted files replaced, got %v", changed)
+	}
+	if len(scoped) != 1 || scoped[0].Path != "orders.sql" {
+		t.Fatalf("expected only orders.sql rebuilt, got %+v", scoped)
+	}
+	hasStatus := false
+	for _, ref := range scoped[0].Refs {
+		if ref.Context != "dbo.usp_ArchiveOrders" {
+			t.Errorf("expected only references from the changed procedure, got %+v", ref)
+		}
+		if ref.TargetColumn == "dbo.OrdersArchive.Status" {
+			hasStatus = true
+		}
+	}
+	if !hasStatus {
+		t.Errorf("expected the rebuilt lineage to include the new Status column, got %+v", scoped[0].Refs)
+	}
+}
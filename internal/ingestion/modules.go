@@ -0,0 +1,93 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module detection modes (project.settings module_detection).
+const (
+	ModuleDetectionOff      = ""
+	ModuleDetectionManifest = "manifest"      // nearest package.json / *.csproj directory
+	ModuleDetectionTopLevel = "top_level_dir" // first path segment
+)
+
+// moduleSkipDirs are never treated as module roots or descended into during detection.
+var moduleSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"bin":          true,
+	"obj":          true,
+	"dist":         true,
+	"vendor":       true,
+}
+
+// isModuleManifest reports whether a file name marks a project boundary.
+func isModuleManifest(name string) bool {
+	return name == "package.json" || strings.HasSuffix(strings.ToLower(name), ".csproj")
+}
+
+// detectModuleRoots walks workDir and returns the relative directories (slash-separated)
+// that contain a project manifest, longest first. A manifest at the repository root is a
+// workspace definition rather than an app, so it is not returned.
+func detectModuleRoots(workDir string) ([]string, error) {
+	seen := make(map[string]bool)
+	err := filepath.Walk(workDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if moduleSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isModuleManifest(info.Name()) {
+			return nil
+		}
+		rel, _ := filepath.Rel(workDir, filepath.Dir(path))
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			seen[rel] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]string, 0, len(seen))
+	for r := range seen {
+		roots = append(roots, r)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if len(roots[i]) != len(roots[j]) {
+			return len(roots[i]) > len(roots[j])
+		}
+		return roots[i] < roots[j]
+	})
+	return roots, nil
+}
+
+// moduleForPath returns the module tag for a relative file path: the innermost manifest
+// root containing it (manifest mode) or its top-level directory (top_level_dir mode).
+// Files outside every module get "".
+func moduleForPath(relPath, mode string, roots []string) string {
+	norm := filepath.ToSlash(relPath)
+	switch mode {
+	case ModuleDetectionManifest:
+		// roots are sorted longest first, so the first match is the innermost.
+		for _, root := range roots {
+			if strings.HasPrefix(norm, root+"/") {
+				return root
+			}
+		}
+	case ModuleDetectionTopLevel:
+		if i := strings.Index(norm, "/"); i > 0 {
+			return norm[:i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package ingestion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/javascript"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModuleDetection_DistinctPackageJSONRoots(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "package.json", `{"workspaces": ["apps/*"]}`)
+	writeFile(t, root, "apps/web/package.json", `{"name": "web"}`)
+	writeFile(t, root, "apps/web/src/cart.js", "function addToCart(item) { return item; }\n")
+	writeFile(t, root, "apps/admin/package.json", `{"name": "admin"}`)
+	writeFile(t, root, "apps/admin/src/users.js", "function listUsers() { return []; }\n")
+	writeFile(t, root, "apps/web/node_modules/dep/package.json", `{"name": "dep"}`)
+
+	roots, err := detectModuleRoots(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 module roots (root workspace and node_modules excluded), got %v", roots)
+	}
+
+	registry := parser.NewRegistry()
+	registry.Register(".js", javascript.NewJS())
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: root, ModuleDetection: ModuleDetectionManifest, ModuleRoots: roots}
+
+	modules := make(map[string]string)
+	for _, rel := range []string{"apps/web/src/cart.js", "apps/admin/src/users.js"} {
+		abs := filepath.Join(root, rel)
+		info, err := os.Stat(abs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fr := stage.parseFile(registry, rc, abs, rel, info)
+		if fr == nil || len(fr.Symbols) == 0 {
+			t.Fatalf("%s: expected parsed symbols", rel)
+		}
+		modules[rel] = fr.Module
+	}
+
+	if modules["apps/web/src/cart.js"] != "apps/web" {
+		t.Errorf("cart.js: expected module apps/web, got %q", modules["apps/web/src/cart.js"])
+	}
+	if modules["apps/admin/src/users.js"] != "apps/admin" {
+		t.Errorf("users.js: expected module apps/admin, got %q", modules["apps/admin/src/users.js"])
+	}
+}
+
+func TestModuleForPath(t *testing.T) {
+	roots := []string{"apps/web/legacy", "apps/web", "services/Billing"}
+	tests := []struct {
+		path, mode, want string
+	}{
+		{"apps/web/src/a.js", ModuleDetectionManifest, "apps/web"},
+		{"apps/web/legacy/b.js", ModuleDetectionManifest, "apps/web/legacy"},
+		{"apps/webhooks/c.js", ModuleDetectionManifest, ""},
+		{"services/Billing/Invoice.cs", ModuleDetectionManifest, "services/Billing"},
+		{"README.md", ModuleDetectionManifest, ""},
+		{"apps/web/src/a.js", ModuleDetectionTopLevel, "apps"},
+		{"README.md", ModuleDetectionTopLevel, ""},
+		{"apps/web/src/a.js", ModuleDetectionOff, ""},
+	}
+	for _, tt := range tests {
+		if got := moduleForPath(tt.path, tt.mode, roots); got != tt.want {
+			t.Errorf("moduleForPath(%q, %q) = %q, want %q", tt.path, tt.mode, got, tt.want)
+		}
+	}
+}
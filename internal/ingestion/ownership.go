@@ -0,0 +1,123 @@
+package ingestion
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// FileOwner is the author and date of the last commit that touched a file.
+type FileOwner struct {
+	Author      string
+	CommittedAt time.Time
+}
+
+// gitLogFormat prefixes each commit header with a NUL so it cannot be mistaken for a
+// file name in --name-only output.
+const gitLogFormat = "--format=%x00%an%x09%aI"
+
+// gitFileOwners walks the history of the repository in workDir once, newest commit
+// first, and returns the last commit touching each of paths. The walk stops as soon as
+// every path has an owner. The clone must not be shallow, or every file is attributed
+// to the tip commit.
+func gitFileOwners(ctx context.Context, workDir string, paths []string) (map[string]FileOwner, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "-c", "core.quotepath=off", "log", gitLogFormat, "--name-only", "--no-renames")
+	cmd.Dir = workDir
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		wanted[p] = true
+	}
+	owners, err := parseGitLogOwners(out, wanted)
+	if err != nil {
+		cancel()
+		_ = cmd.Wait()
+		return nil, err
+	}
+	// Stopping early kills git, so its exit status only matters after a full read.
+	if len(owners) < len(wanted) {
+		if err := cmd.Wait(); err != nil {
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+	} else {
+		cancel()
+		_ = cmd.Wait()
+	}
+	return owners, nil
+}
+
+// parseGitLogOwners reads `git log --name-only` output in gitLogFormat and records the
+// first (newest) commit listing each wanted path.
+func parseGitLogOwners(r io.Reader, wanted map[string]bool) (map[string]FileOwner, error) {
+	owners := make(map[string]FileOwner, len(wanted))
+	var current FileOwner
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() && len(owners) < len(wanted) {
+		line := scanner.Text()
+		if header, ok := strings.CutPrefix(line, "\x00"); ok {
+			author, date, _ := strings.Cut(header, "\t")
+			committedAt, err := time.Parse(time.RFC3339, date)
+			if err != nil {
+				return nil, fmt.Errorf("parse commit date %q: %w", date, err)
+			}
+			current = FileOwner{Author: author, CommittedAt: committedAt}
+			continue
+		}
+		if line == "" || !wanted[line] {
+			continue
+		}
+		if _, seen := owners[line]; !seen {
+			owners[line] = current
+		}
+	}
+	return owners, scanner.Err()
+}
+
+// recordOwnership stores the last commit author and date of each parsed file in one
+// batched update.
+func recordOwnership(ctx context.Context, s *store.Store, rc *IndexRunContext, results []parser.FileResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	paths := make([]string, len(results))
+	for i, fr := range results {
+		paths[i] = fr.Path
+	}
+	owners, err := gitFileOwners(ctx, rc.WorkDir, paths)
+	if err != nil {
+		return err
+	}
+
+	arg := postgres.UpdateFileOwnershipParams{ProjectID: rc.ProjectID, SourceID: rc.SourceID}
+	for path, owner := range owners {
+		arg.Paths = append(arg.Paths, path)
+		arg.Authors = append(arg.Authors, owner.Author)
+		arg.CommittedAt = append(arg.CommittedAt, pgtype.Timestamptz{Time: owner.CommittedAt, Valid: true})
+	}
+	if len(arg.Paths) == 0 {
+		return nil
+	}
+	_, err = s.UpdateFileOwnership(ctx, arg)
+	return err
+}
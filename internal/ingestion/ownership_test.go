@@ -0,0 +1,34 @@
+package ingestion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGitLogOwners(t *testing.T) {
+	log := "\x00Dana Reyes\t2026-09-30T14:02:11+02:00\n\n" +
+		"src/orders/service.ts\n" +
+		"README.md\n" +
+		"\x00Sam Okafor\t2026-08-12T09:30:00Z\n\n" +
+		"src/orders/service.ts\n" +
+		"src/billing/invoice.ts\n"
+	wanted := map[string]bool{"src/orders/service.ts": true, "src/billing/invoice.ts": true, "src/unknown.ts": true}
+
+	owners, err := parseGitLogOwners(strings.NewReader(log), wanted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 2 {
+		t.Fatalf("expected owners for the 2 files in the log, got %v", owners)
+	}
+	if o := owners["src/orders/service.ts"]; o.Author != "Dana Reyes" || !o.CommittedAt.Equal(time.Date(2026, 9, 30, 12, 2, 11, 0, time.UTC)) {
+		t.Errorf("expected the newest commit to own service.ts, got %+v", o)
+	}
+	if o := owners["src/billing/invoice.ts"]; o.Author != "Sam Okafor" {
+		t.Errorf("expected Sam Okafor to own invoice.ts, got %+v", o)
+	}
+	if _, ok := owners["README.md"]; ok {
+		t.Error("files outside the wanted set should be skipped")
+	}
+}
@@ -3,7 +3,10 @@ package ingestion
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,12 +18,36 @@ import (
 
 // ParseStage walks the work directory, parses SQL files, and persists results.
 type ParseStage struct {
-	registry *parser.Registry
-	store    *store.Store
+	registries        *parser.RegistryPool
+	store             *store.Store
+	edgeBatchSize     int
+	maxSymbolsPerFile int
+	symbolLimits      SymbolLimits
+	logger            *slog.Logger
 }
 
-func NewParseStage(registry *parser.Registry, store *store.Store) *ParseStage {
-	return &ParseStage{registry: registry, store: store}
+// NewParseStage creates the parse stage. Each run borrows a parser registry from
+// registries for its duration, so concurrent runs never share a parser.
+// maxSymbolsPerFile caps the symbols kept from a single file (DefaultMaxSymbolsPerFile if <= 0).
+func NewParseStage(registries *parser.RegistryPool, store *store.Store, edgeBatchSize, maxSymbolsPerFile int) *ParseStage {
+	if maxSymbolsPerFile <= 0 {
+		maxSymbolsPerFile = DefaultMaxSymbolsPerFile
+	}
+	return &ParseStage{
+		registries:        registries,
+		store:             store,
+		edgeBatchSize:     edgeBatchSize,
+		maxSymbolsPerFile: maxSymbolsPerFile,
+		symbolLimits:      SymbolLimits{Warn: DefaultProjectSymbolWarn, Max: DefaultProjectSymbolLimit},
+		logger:            slog.Default(),
+	}
+}
+
+// SetSymbolLimits replaces the default per-project symbol limits; warnings are logged
+// to logger.
+func (s *ParseStage) SetSymbolLimits(limits SymbolLimits, logger *slog.Logger) {
+	s.symbolLimits = limits
+	s.logger = logger
 }
 
 func (s *ParseStage) Name() string { return "parse" }
@@ -45,7 +72,89 @@ func (s *ParseStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 		}
 	}
 
+	// Manifest roots come from the full work dir so incremental runs tag consistently.
+	if rc.ModuleDetection == ModuleDetectionManifest {
+		roots, err := detectModuleRoots(rc.WorkDir)
+		if err != nil {
+			return fmt.Errorf("detect module roots: %w", err)
+		}
+		rc.ModuleRoots = roots
+	}
+
+	registry, err := s.registries.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire parsers: %w", err)
+	}
+	defer s.registries.Put(registry)
+
+	// Incremental runs add to the symbols already stored (changed files count twice, which
+	// errs towards warning early); full scans replace them.
+	baseline := 0
+	if rc.Incremental {
+		n, err := s.store.CountSymbolsByProject(ctx, rc.ProjectID)
+		if err != nil {
+			return fmt.Errorf("count project symbols: %w", err)
+		}
+		baseline = int(n)
+	}
+	budget := newSymbolBudget(s.symbolLimits, baseline)
+
+	results, err := s.parseAll(registry, rc, budget)
+	if err != nil {
+		return err
+	}
+	if budget.warned {
+		s.logger.Warn("project is over its symbol warning limit",
+			slog.String("project_id", rc.ProjectID.String()),
+			slog.Int("symbols", budget.total),
+			slog.Int("warn_at", s.symbolLimits.Warn))
+		if err := recordSymbolWarning(ctx, s.store, rc, budget); err != nil {
+			return fmt.Errorf("record symbol warning: %w", err)
+		}
+	}
+
+	files, symbols, edges, err := PersistResults(ctx, s.store, results, s.edgeBatchSize, s.logger)
+	if err != nil {
+		return fmt.Errorf("persist results: %w", err)
+	}
+
+	if rc.TrackOwnership && rc.SourceType == "git" {
+		if err := recordOwnership(ctx, s.store, rc, results); err != nil {
+			return fmt.Errorf("record ownership: %w", err)
+		}
+	}
+
+	if len(rc.SkippedFiles) > 0 || len(rc.OversizedFiles) > 0 {
+		if err := recordParseIssues(ctx, s.store, rc); err != nil {
+			return fmt.Errorf("record parse issues: %w", err)
+		}
+	}
+
+	// Path aliases come from the full work dir so incremental runs resolve them too.
+	if err := recordTSPaths(ctx, s.store, rc, s.logger); err != nil {
+		return fmt.Errorf("record tsconfig paths: %w", err)
+	}
+
+	rc.FilesProcessed = files
+	rc.SymbolsFound = symbols
+	rc.EdgesFound = edges
+	rc.ParseResults = results
+
+	return nil
+}
+
+// parseAll parses the changed files of an incremental run, or every file under the work
+// directory, counting symbols against budget. It stops at the first file that takes the
+// project past its hard limit.
+func (s *ParseStage) parseAll(registry *parser.Registry, rc *IndexRunContext, budget *symbolBudget) ([]parser.FileResult, error) {
 	var results []parser.FileResult
+	add := func(fr *parser.FileResult) error {
+		if fr == nil {
+			return nil
+		}
+		results = append(results, *fr)
+		return budget.add(countSymbols(fr.Symbols))
+	}
 
 	if rc.Incremental && len(rc.ChangedFiles) > 0 {
 		// Incremental: only parse changed files
@@ -55,49 +164,39 @@ func (s *ParseStage) Execute(ctx context.Context, rc *IndexRunContext) error {
 			if err != nil {
 				continue // file might not exist
 			}
-			fr := s.parseFile(rc, absPath, relPath, info)
-			if fr != nil {
-				results = append(results, *fr)
+			if err := add(s.parseFile(registry, rc, absPath, relPath, info)); err != nil {
+				return nil, err
 			}
 		}
-	} else {
-		// Full scan
-		err := filepath.Walk(rc.WorkDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if info.IsDir() {
-				return nil
-			}
+		return results, nil
+	}
 
-			relPath, _ := filepath.Rel(rc.WorkDir, path)
-			fr := s.parseFile(rc, path, relPath, info)
-			if fr != nil {
-				results = append(results, *fr)
-			}
-			return nil
-		})
+	// Full scan
+	err := filepath.Walk(rc.WorkDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return fmt.Errorf("walk work dir: %w", err)
+			return err
+		}
+		if info.IsDir() {
+			return nil
 		}
-	}
 
-	files, symbols, edges, err := PersistResults(ctx, s.store, results)
+		relPath, _ := filepath.Rel(rc.WorkDir, path)
+		return add(s.parseFile(registry, rc, path, relPath, info))
+	})
+	if errors.Is(err, ErrProjectSymbolLimit) {
+		return nil, err
+	}
 	if err != nil {
-		return fmt.Errorf("persist results: %w", err)
+		return nil, fmt.Errorf("walk work dir: %w", err)
 	}
-
-	rc.FilesProcessed = files
-	rc.SymbolsFound = symbols
-	rc.EdgesFound = edges
-	rc.ParseResults = results
-
-	return nil
+	return results, nil
 }
 
-func (s *ParseStage) parseFile(rc *IndexRunContext, absPath, relPath string, info os.FileInfo) *parser.FileResult {
-	p := s.registry.ForFile(absPath)
-	if p == nil {
+func (s *ParseStage) parseFile(registry *parser.Registry, rc *IndexRunContext, absPath, relPath string, info os.FileInfo) *parser.FileResult {
+	ext := strings.ToLower(filepath.Ext(absPath))
+	p := registry.ForFile(absPath)
+	byContent := p == nil && registry.RoutesByContent(absPath)
+	if p == nil && !byContent && !parser.Detectable(relPath) && !parser.MaybeMigration(relPath) {
 		return nil
 	}
 
@@ -105,27 +204,75 @@ func (s *ParseStage) parseFile(rc *IndexRunContext, absPath, relPath string, inf
 	if err != nil {
 		return nil
 	}
+	migration, isMigration := parser.DetectMigration(relPath, content)
+
+	// YAML or JSON file: parsed only when it is a document a parser reads, such as an
+	// OpenAPI spec, and otherwise ignored like any file without a parser
+	if byContent && !isMigration {
+		if p = registry.ForContent(relPath, content); p == nil {
+			return nil
+		}
+	}
+
+	// Extensionless or generic text file: route it by content, or record why it was skipped.
+	// Liquibase XML/YAML changelogs have no parser; their schema operations are read below.
+	if p == nil && !isMigration {
+		var det parser.Detection
+		p, det = registry.Detect(relPath, content)
+		if p == nil {
+			rc.SkippedFiles = append(rc.SkippedFiles, SkippedFile{Path: relPath, Reason: det.Reason})
+			return nil
+		}
+		ext = det.Extension
+	}
 
 	// Detect SQL dialect for SQL files
-	ext := strings.ToLower(filepath.Ext(absPath))
 	language := "sql"
 	if ext == ".sql" || ext == ".sqldataprovider" {
 		language = parser.DetectDialect(content)
+	} else if p == nil {
+		language = migration.Framework
 	}
 
 	// Classify migration/schema files: skip column-level lineage to avoid direct_copy explosion
-	skipColumnLineage := isMigrationOrSchemaFile(relPath, rc.LineageExcludePaths)
+	skipColumnLineage := isMigration || isMigrationOrSchemaFile(relPath, rc.LineageExcludePaths)
 
 	input := parser.FileInput{
-		Path:              relPath,
-		Content:           content,
-		Language:          language,
-		SkipColumnLineage: skipColumnLineage,
+		Path:                   relPath,
+		Content:                content,
+		Language:               language,
+		SkipColumnLineage:      skipColumnLineage,
+		DetectConditionalCalls: rc.DetectConditionalCalls,
+	}
+	if ext == ".go" {
+		input.ImportPath = goImportPath(rc, filepath.ToSlash(filepath.Dir(relPath)))
 	}
 
-	result, err := p.Parse(input)
-	if err != nil {
-		return nil
+	result := &parser.ParseResult{}
+	if p != nil {
+		if result, err = p.Parse(input); err != nil {
+			return nil
+		}
+	}
+	if isMigration {
+		// The migration's place in the schema history, and what it creates and alters
+		lines := strings.Count(string(content), "\n") + 1
+		result.Symbols = append(result.Symbols, migration.Symbol(relPath, language, lines))
+		result.References = append(result.References, migration.SchemaOps(relPath, content)...)
+	}
+
+	symbols, refs := result.Symbols, result.References
+	warnings := result.Warnings
+	var tags []string
+	var dropped int
+	if symbols, refs, dropped = capSymbols(symbols, refs, s.maxSymbolsPerFile); dropped > 0 {
+		kept := countSymbols(symbols)
+		warnings = append(warnings, oversizedWarning(kept+dropped, kept, s.maxSymbolsPerFile))
+		tags = append(tags, FileTagOversized)
+		rc.OversizedFiles = append(rc.OversizedFiles, OversizedFile{Path: relPath, Symbols: kept + dropped, Kept: kept})
+	}
+	if rc.DedupeReferences {
+		refs = dedupeReferences(refs)
 	}
 
 	hash := fmt.Sprintf("%x", sha256.Sum256(content))
@@ -137,10 +284,60 @@ func (s *ParseStage) parseFile(rc *IndexRunContext, absPath, relPath string, inf
 		Language:         language,
 		SizeBytes:        info.Size(),
 		Hash:             hash,
-		Symbols:          result.Symbols,
-		References:       result.References,
+		Symbols:          symbols,
+		References:       refs,
 		ColumnReferences: result.ColumnReferences,
+		Module:           moduleForPath(relPath, rc.ModuleDetection, rc.ModuleRoots),
+		Tags:             tags,
+		Warnings:         warnings,
+	}
+}
+
+// maxRecordedFiles bounds how many skipped or oversized files are listed in the index run metadata.
+const maxRecordedFiles = 200
+
+// recordParseIssues stores the files content detection could not route (skipped_files) and
+// the files whose symbols were capped (oversized_files) in the index run metadata. Lists are
+// capped; the *_total keys hold the full counts.
+func recordParseIssues(ctx context.Context, s *store.Store, rc *IndexRunContext) error {
+	skipped, oversized := rc.SkippedFiles, rc.OversizedFiles
+	if len(skipped) > maxRecordedFiles {
+		skipped = skipped[:maxRecordedFiles]
+	}
+	if len(oversized) > maxRecordedFiles {
+		oversized = oversized[:maxRecordedFiles]
+	}
+	meta, err := json.Marshal(map[string]any{
+		"skipped_files":         skipped,
+		"skipped_files_total":   len(rc.SkippedFiles),
+		"oversized_files":       oversized,
+		"oversized_files_total": len(rc.OversizedFiles),
+	})
+	if err != nil {
+		return err
+	}
+	return s.MergeIndexRunMetadata(ctx, postgres.MergeIndexRunMetadataParams{
+		ID:       rc.IndexRunID,
+		Metadata: meta,
+	})
+}
+
+// recordSymbolWarning flags the index run of a project that reached its symbol warning
+// limit (symbol_warning in the index run metadata).
+func recordSymbolWarning(ctx context.Context, s *store.Store, rc *IndexRunContext, budget *symbolBudget) error {
+	meta, err := json.Marshal(map[string]any{
+		"symbol_warning": map[string]int{
+			"symbols": budget.total,
+			"warn_at": budget.limits.Warn,
+		},
+	})
+	if err != nil {
+		return err
 	}
+	return s.MergeIndexRunMetadata(ctx, postgres.MergeIndexRunMetadataParams{
+		ID:       rc.IndexRunID,
+		Metadata: meta,
+	})
 }
 
 // isMigrationOrSchemaFile returns true for paths that look like migration or schema DDL
@@ -174,3 +371,49 @@ func isMigrationOrSchemaFile(relPath string, lineageExcludePaths []string) bool
 	}
 	return false
 }
+
+// dedupeReferences collapses identical references emitted for the same file (e.g. a
+// proc queried inside a loop) into one per (FromSymbol, ToName, ToQualified, ReferenceType,
+// Receiver, Mapping, Entity, Args), so calls to different overloads stay apart. The first occurrence's position is kept and the highest confidence wins, where an
+// unset confidence (0) counts as 1.0.
+func dedupeReferences(refs []parser.RawReference) []parser.RawReference {
+	if len(refs) < 2 {
+		return refs
+	}
+
+	type refKey struct {
+		from, toName, toQualified, refType string
+		receiver, mapping, entity, args    string
+		hasArgs                            bool
+	}
+
+	index := make(map[refKey]int, len(refs))
+	out := make([]parser.RawReference, 0, len(refs))
+	for _, ref := range refs {
+		key := refKey{
+			ref.FromSymbol, ref.ToName, ref.ToQualified, ref.ReferenceType,
+			ref.Receiver, ref.Mapping, ref.Entity, strings.Join(ref.Args, "\x00"),
+			ref.Args != nil,
+		}
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(out)
+			out = append(out, ref)
+			continue
+		}
+		if effectiveConfidence(ref.Confidence) > effectiveConfidence(out[i].Confidence) {
+			out[i].Confidence = ref.Confidence
+		}
+		// Conditional only if every occurrence sits behind a feature flag
+		out[i].Conditional = out[i].Conditional && ref.Conditional
+	}
+	return out
+}
+
+// effectiveConfidence maps the unset confidence value (0) to 1.0.
+func effectiveConfidence(c float64) float64 {
+	if c == 0 {
+		return 1.0
+	}
+	return c
+}
@@ -0,0 +1,493 @@
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/builtin"
+)
+
+func TestDedupeReferences_CollapsesIdenticalRefs(t *testing.T) {
+	refs := []parser.RawReference{
+		{FromSymbol: "OrderService.Load", ToName: "usp_GetOrder", ToQualified: "dbo.usp_GetOrder", ReferenceType: "calls", Confidence: 0.6, Line: 10},
+		{FromSymbol: "OrderService.Load", ToName: "Orders", ToQualified: "dbo.Orders", ReferenceType: "uses_table", Confidence: 0.8, Line: 11},
+		{FromSymbol: "OrderService.Load", ToName: "usp_GetOrder", ToQualified: "dbo.usp_GetOrder", ReferenceType: "calls", Confidence: 0.9, Line: 20},
+		{FromSymbol: "OrderService.Load", ToName: "usp_GetOrder", ToQualified: "dbo.usp_GetOrder", ReferenceType: "calls", Confidence: 0.7, Line: 30},
+	}
+
+	got := dedupeReferences(refs)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 distinct references, got %d", len(got))
+	}
+
+	call := got[0]
+	if call.ReferenceType != "calls" {
+		t.Fatalf("expected first reference to stay first, got %s", call.ReferenceType)
+	}
+	if call.Confidence != 0.9 {
+		t.Errorf("expected highest confidence 0.9, got %.2f", call.Confidence)
+	}
+	if call.Line != 10 {
+		t.Errorf("expected first occurrence line 10, got %d", call.Line)
+	}
+}
+
+func TestDedupeReferences_UnsetConfidenceWins(t *testing.T) {
+	refs := []parser.RawReference{
+		{FromSymbol: "a", ToName: "b", ReferenceType: "calls", Confidence: 0.5, Line: 1},
+		{FromSymbol: "a", ToName: "b", ReferenceType: "calls", Line: 2},
+	}
+
+	got := dedupeReferences(refs)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 reference, got %d", len(got))
+	}
+	if got[0].Confidence != 0 {
+		t.Errorf("unset confidence (1.0) should win over 0.5, got %.2f", got[0].Confidence)
+	}
+}
+
+func TestDedupeReferences_DistinctTypesKept(t *testing.T) {
+	refs := []parser.RawReference{
+		{FromSymbol: "p", ToName: "Orders", ToQualified: "dbo.Orders", ReferenceType: "reads_from"},
+		{FromSymbol: "p", ToName: "Orders", ToQualified: "dbo.Orders", ReferenceType: "writes_to"},
+	}
+	if got := dedupeReferences(refs); len(got) != 2 {
+		t.Errorf("references with different types must not collapse, got %d", len(got))
+	}
+}
+
+func TestDedupeReferences_ConditionalOnlyIfAllOccurrencesAre(t *testing.T) {
+	refs := []parser.RawReference{
+		{FromSymbol: "a", ToName: "b", ReferenceType: "calls", Conditional: true, Line: 1},
+		{FromSymbol: "a", ToName: "b", ReferenceType: "calls", Line: 2},
+		{FromSymbol: "a", ToName: "c", ReferenceType: "calls", Conditional: true, Line: 3},
+		{FromSymbol: "a", ToName: "c", ReferenceType: "calls", Conditional: true, Line: 4},
+	}
+
+	got := dedupeReferences(refs)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 references, got %d", len(got))
+	}
+	if got[0].Conditional {
+		t.Error("a->b is also called unguarded and should not be conditional")
+	}
+	if !got[1].Conditional {
+		t.Error("a->c is only called behind a flag and should stay conditional")
+	}
+}
+
+func TestParseFile_DedupeKeepsOverloadCalls(t *testing.T) {
+	dir := t.TempDir()
+	abs := filepath.Join(dir, "OrderService.java")
+	src := `package shop;
+
+class OrderService {
+    private OrderRepository repo;
+
+    void place(Order o) {
+        repo.save(o);
+        repo.save(o, "note");
+        repo.save(o);
+    }
+}
+`
+	if err := os.WriteFile(abs, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := builtin.NewRegistry(builtin.Options{})
+	rc := &IndexRunContext{WorkDir: dir, DedupeReferences: true}
+	fr := NewParseStage(nil, nil, 0, 0).parseFile(registry, rc, abs, "OrderService.java", info)
+	if fr == nil {
+		t.Fatalf("expected OrderService.java to be parsed, skipped: %+v", rc.SkippedFiles)
+	}
+
+	var saves [][]string
+	for _, ref := range fr.References {
+		if ref.ReferenceType == "calls" && ref.ToName == "save" {
+			saves = append(saves, ref.Args)
+		}
+	}
+	if len(saves) != 2 {
+		t.Fatalf("expected one call per save overload, got %d: %v", len(saves), saves)
+	}
+	if len(saves[0]) != 1 || len(saves[1]) != 2 {
+		t.Errorf("expected the one- and two-argument calls in order, got %v", saves)
+	}
+}
+
+func TestParseFile_DetectsExtensionlessTSQL(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) (string, os.FileInfo) {
+		abs := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return abs, info
+	}
+
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: dir}
+
+	abs, info := write("db/create_orders", `
+CREATE TABLE dbo.Orders (
+    OrderID INT IDENTITY(1,1) PRIMARY KEY,
+    Notes NVARCHAR(MAX)
+);
+GO
+CREATE PROCEDURE dbo.usp_GetOrders
+AS
+BEGIN
+    SET NOCOUNT ON;
+    SELECT OrderID, Notes FROM dbo.Orders;
+END
+GO
+`)
+	fr := stage.parseFile(registry, rc, abs, "db/create_orders", info)
+	if fr == nil {
+		t.Fatalf("expected extensionless T-SQL to be parsed, skipped: %+v", rc.SkippedFiles)
+	}
+	if fr.Language != "tsql" {
+		t.Errorf("expected tsql, got %s", fr.Language)
+	}
+	names := make(map[string]bool)
+	for _, sym := range fr.Symbols {
+		names[sym.QualifiedName] = true
+	}
+	if !names["dbo.Orders"] || !names["dbo.usp_GetOrders"] {
+		t.Errorf("expected table and procedure symbols, got %v", names)
+	}
+
+	// A lone SELECT in a note is below the confidence threshold and must not be parsed.
+	abs, info = write("notes.txt", "remember to select name from the guest list\n")
+	if fr := stage.parseFile(registry, rc, abs, "notes.txt", info); fr != nil {
+		t.Errorf("expected low-confidence file to be skipped, got %d symbols", len(fr.Symbols))
+	}
+	abs, info = write("Dockerfile", "FROM golang:1.24\nRUN go build ./...\n")
+	if fr := stage.parseFile(registry, rc, abs, "Dockerfile", info); fr != nil {
+		t.Error("expected Dockerfile to be skipped")
+	}
+	if len(rc.SkippedFiles) != 2 || rc.SkippedFiles[1].Reason != "detected dockerfile (Dockerfile), no parser registered" {
+		t.Errorf("expected two skipped files with reasons, got %+v", rc.SkippedFiles)
+	}
+
+	// Shebang scripts are parsed by their interpreter's parser
+	abs, info = write("bin/manage", "#!/usr/bin/env python3\n\ndef main():\n    pass\n")
+	if fr := stage.parseFile(registry, rc, abs, "bin/manage", info); fr == nil || len(fr.Symbols) != 1 || fr.Symbols[0].Language != "python" {
+		t.Errorf("expected a python shebang script to be parsed by the python parser, got %+v", fr)
+	}
+
+	// Files under hidden directories are never sniffed.
+	abs, info = write(".git/HEAD", "ref: refs/heads/main\n")
+	if fr := stage.parseFile(registry, rc, abs, ".git/HEAD", info); fr != nil || len(rc.SkippedFiles) != 2 {
+		t.Error("expected .git contents to be ignored without a skip record")
+	}
+}
+
+func TestParseFile_RoutesOpenAPISpecsByContent(t *testing.T) {
+	dir := t.TempDir()
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: dir}
+	parse := func(rel, content string) *parser.FileResult {
+		t.Helper()
+		abs := filepath.Join(dir, rel)
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stage.parseFile(registry, rc, abs, rel, info)
+	}
+
+	fr := parse("openapi.yml", "openapi: 3.1.0\npaths:\n  /health:\n    get: {}\n")
+	if fr == nil || len(fr.Symbols) != 1 || fr.Symbols[0].QualifiedName != "openapi:GET /health" {
+		t.Fatalf("expected the spec's operation, got %+v", fr)
+	}
+	// Other YAML and JSON files are ignored without a skip record
+	if fr := parse("package.json", `{"name": "web", "version": "1.0.0"}`); fr != nil {
+		t.Errorf("expected package.json to be ignored, got %+v", fr)
+	}
+	if len(rc.SkippedFiles) != 0 {
+		t.Errorf("expected no skipped files, got %+v", rc.SkippedFiles)
+	}
+}
+
+func TestParseFile_QualifiesGoWithImportPath(t *testing.T) {
+	dir := t.TempDir()
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: dir}
+	write := func(rel, content string) string {
+		t.Helper()
+		abs := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return abs
+	}
+	write("services/billing/go.mod", "// billing service\nmodule github.com/acme/billing // v2 soon\n\ngo 1.25\n")
+	abs := write("services/billing/internal/store/store.go", "package store\n\nfunc New() {}\n")
+	info, err := os.Stat(abs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fr := stage.parseFile(registry, rc, abs, filepath.FromSlash("services/billing/internal/store/store.go"), info)
+	if fr == nil {
+		t.Fatal("expected the Go file to be parsed")
+	}
+	want := map[string]bool{"github.com/acme/billing/internal/store": true, "github.com/acme/billing/internal/store.New": true}
+	for _, sym := range fr.Symbols {
+		delete(want, sym.QualifiedName)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing symbols %v in %+v", want, fr.Symbols)
+	}
+}
+
+func TestParseFile_CapsOversizedFile(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("namespace Generated.Models\n{\n")
+	for c := 0; c < 3; c++ {
+		fmt.Fprintf(&b, "    public class Model%d\n    {\n", c)
+		for m := 0; m < 40; m++ {
+			fmt.Fprintf(&b, "        public int Field%d { get; set; }\n", m)
+		}
+		fmt.Fprintf(&b, "        public void Save() { Repository.Save(this); }\n    }\n")
+	}
+	b.WriteString("}\n")
+
+	dir := t.TempDir()
+	abs := filepath.Join(dir, "Models.g.cs")
+	if err := os.WriteFile(abs, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(abs)
+
+	const limit = 50
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, limit)
+	rc := &IndexRunContext{WorkDir: dir}
+	fr := stage.parseFile(registry, rc, abs, "Models.g.cs", info)
+	if fr == nil {
+		t.Fatal("expected file to be parsed")
+	}
+
+	if n := countSymbols(fr.Symbols); n != limit {
+		t.Errorf("expected symbols capped at %d, got %d", limit, n)
+	}
+	classes := 0
+	for _, sym := range fr.Symbols {
+		if sym.Kind == "class" {
+			classes++
+		}
+	}
+	if classes != 3 {
+		t.Errorf("expected all 3 top-level classes kept, got %d", classes)
+	}
+	if len(fr.Tags) != 1 || fr.Tags[0] != FileTagOversized {
+		t.Errorf("expected file tagged oversized, got %v", fr.Tags)
+	}
+	if len(fr.Warnings) == 0 || !strings.Contains(fr.Warnings[len(fr.Warnings)-1].Message, "over the cap of 50") {
+		t.Errorf("expected an oversized warning, got %+v", fr.Warnings)
+	}
+	if len(rc.OversizedFiles) != 1 || rc.OversizedFiles[0].Kept != limit || rc.OversizedFiles[0].Symbols <= limit {
+		t.Errorf("expected the file recorded as oversized, got %+v", rc.OversizedFiles)
+	}
+}
+
+func TestParseFile_MigrationsContributeSchemaHistory(t *testing.T) {
+	dir := t.TempDir()
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: dir}
+
+	parse := func(rel, content string) *parser.FileResult {
+		t.Helper()
+		abs := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := os.Stat(abs)
+		fr := stage.parseFile(registry, rc, abs, rel, info)
+		if fr == nil {
+			t.Fatalf("%s: expected migration to be parsed", rel)
+		}
+		return fr
+	}
+	history := func(fr *parser.FileResult) (string, map[string]string) {
+		t.Helper()
+		var migration string
+		for _, sym := range fr.Symbols {
+			if sym.Kind == "migration" {
+				migration = sym.QualifiedName
+			}
+		}
+		ops := make(map[string]string)
+		for _, ref := range fr.References {
+			if ref.ReferenceType == "creates" || ref.ReferenceType == "alters" {
+				if ref.FromSymbol != migration {
+					t.Errorf("%s edge to %s from %q, want the migration %q", ref.ReferenceType, ref.ToQualified, ref.FromSymbol, migration)
+				}
+				if _, seen := ops[ref.ToQualified]; !seen {
+					ops[ref.ToQualified] = ref.ReferenceType
+				}
+			}
+		}
+		return migration, ops
+	}
+
+	flyway := parse("db/migration/V2_1__add_orders.sql", `
+CREATE TABLE orders (
+    id BIGINT PRIMARY KEY,
+    customer_id BIGINT NOT NULL
+);
+ALTER TABLE customers ADD COLUMN last_order_id BIGINT;
+CREATE INDEX ix_orders_customer ON orders (customer_id);
+`)
+	migration, ops := history(flyway)
+	if migration != "migration:flyway:000002.000001" {
+		t.Errorf("expected the Flyway version in the migration name, got %q", migration)
+	}
+	if ops["orders"] != "creates" || ops["customers"] != "alters" {
+		t.Errorf("expected orders created and customers altered, got %v", ops)
+	}
+
+	ef := parse("src/Data/Migrations/20240105120000_AddOrders.cs", `
+using Microsoft.EntityFrameworkCore.Migrations;
+
+public partial class AddOrders : Migration
+{
+    protected override void Up(MigrationBuilder migrationBuilder)
+    {
+        migrationBuilder.CreateTable(
+            name: "Orders",
+            schema: "sales",
+            columns: table => new
+            {
+                Id = table.Column<int>(nullable: false),
+                CustomerId = table.Column<int>(nullable: false)
+            });
+
+        migrationBuilder.AddColumn<string>(
+            name: "Email",
+            table: "Customers",
+            nullable: true);
+    }
+
+    protected override void Down(MigrationBuilder migrationBuilder)
+    {
+        migrationBuilder.DropTable(name: "Invoices");
+    }
+}
+`)
+	migration, ops = history(ef)
+	if migration != "migration:ef:20240105120000" {
+		t.Errorf("expected the EF timestamp in the migration name, got %q", migration)
+	}
+	if ops["sales.Orders"] != "creates" || ops["Customers"] != "alters" {
+		t.Errorf("expected Up() to create sales.Orders and alter Customers, got %v", ops)
+	}
+	if _, ok := ops["Invoices"]; ok {
+		t.Error("expected Down() operations to be ignored")
+	}
+	if len(ef.Symbols) < 2 {
+		t.Errorf("expected the C# class alongside the migration symbol, got %+v", ef.Symbols)
+	}
+}
+
+func TestCapSymbols_CollapsesReferencesOntoKeptAncestor(t *testing.T) {
+	symbols := []parser.Symbol{
+		{QualifiedName: "App.Orders", Kind: "class"},
+		{QualifiedName: "App.Orders.Load", Kind: "method"},
+		{QualifiedName: "App.Orders.Save", Kind: "method"},
+		{QualifiedName: "dbo.Orders", Kind: "table", Children: []parser.Symbol{
+			{QualifiedName: "dbo.Orders.Id", Kind: "column"},
+		}},
+	}
+	refs := []parser.RawReference{
+		{FromSymbol: "App.Orders.Save", ToName: "usp_SaveOrder", ReferenceType: "calls"},
+		{FromSymbol: "App.Orders.Load", ToName: "usp_GetOrder", ReferenceType: "calls"},
+	}
+
+	got, gotRefs, dropped := capSymbols(symbols, refs, 3)
+	if dropped != 2 || countSymbols(got) != 3 {
+		t.Fatalf("expected 3 kept and 2 dropped, got %d kept, %d dropped", countSymbols(got), dropped)
+	}
+	if len(got) != 3 || got[1].QualifiedName != "App.Orders.Load" || len(got[2].Children) != 0 {
+		t.Errorf("expected both top-level symbols and the first member kept, got %+v", got)
+	}
+	if gotRefs[0].FromSymbol != "App.Orders" || gotRefs[1].FromSymbol != "App.Orders.Load" {
+		t.Errorf("expected the dropped member's call collapsed onto its class, got %+v", gotRefs)
+	}
+
+	if same, _, n := capSymbols(symbols, refs, 10); n != 0 || len(same) != len(symbols) {
+		t.Error("expected files under the cap to be untouched")
+	}
+}
+
+func TestParseAll_AbortsAtProjectSymbolLimit(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		src := fmt.Sprintf("CREATE TABLE dbo.T%d (ID INT);\nGO\nCREATE VIEW dbo.V%d AS SELECT ID FROM dbo.T%d;\nGO\n", i, i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("t%d.sql", i)), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := builtin.NewRegistry(builtin.Options{})
+	stage := NewParseStage(nil, nil, 0, 0)
+	rc := &IndexRunContext{WorkDir: dir}
+
+	budget := newSymbolBudget(SymbolLimits{Warn: 4, Max: 6}, 0)
+	results, err := stage.parseAll(registry, rc, budget)
+	if !errors.Is(err, ErrProjectSymbolLimit) {
+		t.Fatalf("expected ErrProjectSymbolLimit, got %v (%d results)", err, len(results))
+	}
+	if !strings.Contains(err.Error(), "exclude globs") || !strings.Contains(err.Error(), "more than 6 symbols") {
+		t.Errorf("expected guidance towards exclude globs, got %q", err)
+	}
+	if !budget.warned {
+		t.Error("expected the warning limit to be reached before the hard limit")
+	}
+	if results != nil {
+		t.Errorf("expected no results from an aborted run, got %d", len(results))
+	}
+
+	// Under both limits the run completes without a warning.
+	budget = newSymbolBudget(SymbolLimits{Warn: 100, Max: 200}, 0)
+	results, err = stage.parseAll(registry, rc, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 || budget.warned || budget.total < 10 {
+		t.Errorf("expected 5 files and no warning, got %d files, %d symbols, warned=%v", len(results), budget.total, budget.warned)
+	}
+}
@@ -3,18 +3,29 @@ package ingestion
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/maraichr/lattice/internal/parser"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
-// PersistResults writes parsed file results to PostgreSQL.
+// conditionalEdgeMetadata marks edges for calls that only happen behind a feature flag,
+// so impact analysis can weight or filter them.
+var conditionalEdgeMetadata = []byte(`{"conditional": true}`)
+
+// PersistResults writes parsed file results to PostgreSQL. Edges are buffered and
+// written edgeBatchSize at a time (store.DefaultEdgeBatchSize if <= 0); edges the
+// database rejects are logged to logger and skipped.
 // Returns counts of files, symbols, and edges persisted.
-func PersistResults(ctx context.Context, s *store.Store, results []parser.FileResult) (files, symbols, edges int, err error) {
+func PersistResults(ctx context.Context, s *store.Store, results []parser.FileResult, edgeBatchSize int, logger *slog.Logger) (files, symbols, edges int, err error) {
+	ew := store.NewEdgeWriter(s, edgeBatchSize, logger)
 	for _, fr := range results {
 		// Upsert file
 		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(fr.Path)))
@@ -29,6 +40,7 @@ func PersistResults(ctx context.Context, s *store.Store, results []parser.FileRe
 			Language:  fr.Language,
 			SizeBytes: fr.SizeBytes,
 			Hash:      hash,
+			Tags:      fr.Tags,
 		})
 		if err != nil {
 			return files, symbols, edges, fmt.Errorf("upsert file %s: %w", fr.Path, err)
@@ -38,10 +50,31 @@ func PersistResults(ctx context.Context, s *store.Store, results []parser.FileRe
 		// Delete existing symbols for this file (re-index)
 		_ = s.DeleteSymbolsByFile(ctx, dbFile.ID)
 
+		// Project-wide symbols survive the delete; their edges are re-derived below
+		// and by the resolver
+		if _, err := s.ClearDerivedEdgeMetadataByFile(ctx, dbFile.ID); err != nil {
+			return files, symbols, edges, fmt.Errorf("clear edge metadata for %s: %w", fr.Path, err)
+		}
+
 		// Insert symbols, tracking qualified_name -> ID for edge resolution
 		symbolIDs := make(map[string]uuid.UUID)
 
 		for _, sym := range fr.Symbols {
+			// A project-wide symbol another file, or an earlier run, stored is shared
+			if sym.ProjectWide {
+				existing, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{
+					ProjectID:     fr.ProjectID,
+					QualifiedName: sym.QualifiedName,
+				})
+				if err == nil {
+					symbolIDs[sym.QualifiedName] = existing.ID
+					continue
+				}
+				if !errors.Is(err, pgx.ErrNoRows) {
+					return files, symbols, edges, fmt.Errorf("look up symbol %s: %w", sym.QualifiedName, err)
+				}
+			}
+
 			created, err := createSymbol(ctx, s, fr.ProjectID, dbFile.ID, sym)
 			if err != nil {
 				return files, symbols, edges, fmt.Errorf("create symbol %s: %w", sym.QualifiedName, err)
@@ -60,6 +93,21 @@ func PersistResults(ctx context.Context, s *store.Store, results []parser.FileRe
 			}
 		}
 
+		// Tag the file's symbols with their monorepo module
+		if fr.Module != "" && len(symbolIDs) > 0 {
+			ids := make([]uuid.UUID, 0, len(symbolIDs))
+			for _, id := range symbolIDs {
+				ids = append(ids, id)
+			}
+			metaJSON, _ := json.Marshal(map[string]any{"module": fr.Module})
+			if err := s.BatchUpdateSymbolMetadata(ctx, postgres.BatchUpdateSymbolMetadataParams{
+				AnalyticsJson: metaJSON,
+				SymbolIds:     ids,
+			}); err != nil {
+				return files, symbols, edges, fmt.Errorf("tag module for %s: %w", fr.Path, err)
+			}
+		}
+
 		// Insert edges (best-effort: skip if source or target symbol not found)
 		for _, ref := range fr.References {
 			sourceID, ok := symbolIDs[ref.FromSymbol]
@@ -75,21 +123,29 @@ func PersistResults(ctx context.Context, s *store.Store, results []parser.FileRe
 				}
 			}
 
-			_, err := s.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{
+			var metadata []byte
+			if ref.Conditional {
+				metadata = conditionalEdgeMetadata
+			}
+			if err := ew.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
 				ProjectID: fr.ProjectID,
 				SourceID:  sourceID,
 				TargetID:  targetID,
 				EdgeType:  ref.ReferenceType,
-			})
-			if err != nil {
-				// ON CONFLICT DO NOTHING means this is ok
-				continue
+				Metadata:  metadata,
+			}); err != nil {
+				return files, symbols, ew.Written(), err
 			}
-			edges++
 		}
 	}
 
-	return files, symbols, edges, nil
+	if err := ew.Flush(ctx); err != nil {
+		return files, symbols, ew.Written(), err
+	}
+	if ew.Skipped() > 0 {
+		logger.Warn("skipped edges the database rejected", slog.Int("edges_skipped", ew.Skipped()))
+	}
+	return files, symbols, ew.Written(), nil
 }
 
 func createSymbol(ctx context.Context, s *store.Store, projectID, fileID uuid.UUID, sym parser.Symbol) (postgres.Symbol, error) {
@@ -110,6 +166,24 @@ func createSymbol(ctx context.Context, s *store.Store, projectID, fileID uuid.UU
 		doc = &sym.DocComment
 	}
 
+	// Kept in the symbol metadata for the metrics analytics (see analytics.ComputeSymbolMetrics)
+	// and for the resolver to tell overloads apart; project_wide keeps the symbol when its
+	// file is indexed again
+	var meta []byte
+	if sym.Complexity > 0 || sym.NormalizedSignature != "" || sym.ProjectWide {
+		fields := make(map[string]any, 3)
+		if sym.Complexity > 0 {
+			fields["complexity"] = sym.Complexity
+		}
+		if sym.NormalizedSignature != "" {
+			fields["normalized_signature"] = sym.NormalizedSignature
+		}
+		if sym.ProjectWide {
+			fields["project_wide"] = true
+		}
+		meta, _ = json.Marshal(fields)
+	}
+
 	return s.CreateSymbol(ctx, postgres.CreateSymbolParams{
 		ProjectID:     projectID,
 		FileID:        fileID,
@@ -123,5 +197,6 @@ func createSymbol(ctx context.Context, s *store.Store, projectID, fileID uuid.UU
 		EndCol:        endCol,
 		Signature:     sig,
 		DocComment:    doc,
+		Metadata:      meta,
 	})
 }
@@ -0,0 +1,154 @@
+//go:build integration
+
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/golang"
+	"github.com/maraichr/lattice/internal/parser/java"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func setupPersistStore(t *testing.T) *store.Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("postgres not available: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("postgres ping failed: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return store.New(pool)
+}
+
+// seedPersistProject creates a project with an upload source to persist files into.
+func seedPersistProject(t *testing.T, s *store.Store) (projectID, sourceID uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Persist",
+		Slug: fmt.Sprintf("test-persist-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "src", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	return proj.ID, source.ID
+}
+
+// parseFile parses path with p into a result for the project's source.
+func parseFile(t *testing.T, p parser.Parser, projectID, sourceID uuid.UUID, path, src string) parser.FileResult {
+	t.Helper()
+	result, err := p.Parse(parser.FileInput{Path: path, Content: []byte(src)})
+	if err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+	return parser.FileResult{
+		ProjectID: projectID, SourceID: sourceID, Path: path, Language: p.Languages()[0],
+		Symbols: result.Symbols, References: result.References,
+	}
+}
+
+func TestPersistResults_ReindexKeepsSharedGoPackage(t *testing.T) {
+	ctx := context.Background()
+	s := setupPersistStore(t)
+	projectID, sourceID := seedPersistProject(t, s)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	p := golang.New()
+	a := parseFile(t, p, projectID, sourceID, "shop/a.go", "package shop\n\nfunc Open() {}\n")
+	b := parseFile(t, p, projectID, sourceID, "shop/b.go", "package shop\n\nfunc Run() {}\n")
+	if _, _, _, err := PersistResults(ctx, s, []parser.FileResult{a, b}, 0, logger); err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+
+	pkg, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: projectID, QualifiedName: "shop"})
+	if err != nil {
+		t.Fatalf("package symbol: %v", err)
+	}
+	run, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: projectID, QualifiedName: "shop.Run"})
+	if err != nil {
+		t.Fatalf("shop.Run: %v", err)
+	}
+	// Stands in for an edge of b.go's that leaves the package symbol, e.g. an import
+	if _, err := s.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{
+		ProjectID: projectID, SourceID: pkg.ID, TargetID: run.ID, EdgeType: "imports",
+	}); err != nil {
+		t.Fatalf("create edge: %v", err)
+	}
+
+	// Re-indexing a.go alone must not take the package, and b.go's edge, with it
+	if _, _, _, err := PersistResults(ctx, s, []parser.FileResult{a}, 0, logger); err != nil {
+		t.Fatalf("re-persist: %v", err)
+	}
+	again, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: projectID, QualifiedName: "shop"})
+	if err != nil || again.ID != pkg.ID {
+		t.Fatalf("expected the package symbol to be kept, got %v, %v", again.ID, err)
+	}
+	edges, err := s.GetOutgoingEdges(ctx, pkg.ID)
+	if err != nil {
+		t.Fatalf("outgoing edges: %v", err)
+	}
+	if len(edges) != 1 {
+		t.Errorf("expected b.go's edge from the package to survive, got %d edges", len(edges))
+	}
+}
+
+func TestPersistResults_ReindexKeepsSharedConfigKey(t *testing.T) {
+	ctx := context.Background()
+	s := setupPersistStore(t)
+	projectID, sourceID := seedPersistProject(t, s)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	reader := func(class string) string {
+		return "package com.example;\n\npublic class " + class + " {\n" +
+			"    public String host() { return System.getenv(\"MAIL_HOST\"); }\n}\n"
+	}
+	p := java.New()
+	a := parseFile(t, p, projectID, sourceID, "A.java", reader("A"))
+	b := parseFile(t, p, projectID, sourceID, "B.java", reader("B"))
+	if _, _, _, err := PersistResults(ctx, s, []parser.FileResult{a, b}, 0, logger); err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+
+	// Re-indexing A.java alone must keep B.java's read of the shared key
+	if _, _, _, err := PersistResults(ctx, s, []parser.FileResult{a}, 0, logger); err != nil {
+		t.Fatalf("re-persist: %v", err)
+	}
+	key, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: projectID, QualifiedName: parser.ConfigKeyPrefix + "MAIL_HOST"})
+	if err != nil {
+		t.Fatalf("config key: %v", err)
+	}
+	edges, err := s.GetIncomingEdges(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("incoming edges: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Errorf("expected reads_config edges from both files, got %d", len(edges))
+	}
+}
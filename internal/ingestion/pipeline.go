@@ -3,9 +3,15 @@ package ingestion
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/analytics"
+	"github.com/maraichr/lattice/internal/embedding"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
@@ -21,12 +27,32 @@ func NewPipeline(s *store.Store, stages []Stage, logger *slog.Logger) *Pipeline
 	return &Pipeline{store: s, stages: stages, logger: logger}
 }
 
+// ErrProjectArchived is returned by Run for an index run of an archived project.
+var ErrProjectArchived = errors.New("project is archived")
+
 // Run processes a single ingestion message through all pipeline stages.
 func (p *Pipeline) Run(ctx context.Context, msg IngestMessage) error {
 	p.logger.Info("pipeline started",
 		slog.String("index_run_id", msg.IndexRunID.String()),
 		slog.String("source_type", msg.SourceType))
 
+	// An archived project is read-only: runs enqueued before it was archived are refused.
+	// A project that cannot be loaded fails the attempt, so the job is retried rather
+	// than run without the archive check and the project's settings
+	proj, err := p.store.GetProjectByID(ctx, msg.ProjectID)
+	if err != nil {
+		return fmt.Errorf("load project: %w", err)
+	}
+	if proj.ArchivedAt.Valid {
+		errMsg := ErrProjectArchived.Error()
+		_ = p.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
+			ID:           msg.IndexRunID,
+			Status:       "failed",
+			ErrorMessage: &errMsg,
+		})
+		return ErrProjectArchived
+	}
+
 	// Mark as running
 	if err := p.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
 		ID:     msg.IndexRunID,
@@ -41,22 +67,64 @@ func (p *Pipeline) Run(ctx context.Context, msg IngestMessage) error {
 		SourceID:   msg.SourceID,
 		SourceType: msg.SourceType,
 		Trigger:    msg.Trigger,
+
+		DedupeReferences: true,
+		PIIPatterns:      analytics.DefaultPIIPatterns,
+		EmbedKinds:       embedding.DefaultKindFilter(),
 	}
 
-	// Load project settings for optional lineage_exclude_paths
-	if proj, err := p.store.GetProjectByID(ctx, msg.ProjectID); err == nil && len(proj.Settings) > 0 {
+	// Load project settings for optional lineage_exclude_paths, dedupe_references, module_detection, pii_patterns, detect_conditional_calls, track_ownership and embed kinds
+	if len(proj.Settings) > 0 {
+		rc.EmbedKinds = embedding.KindFilterFromSettings(proj.Settings)
 		var settings struct {
-			LineageExcludePaths []string `json:"lineage_exclude_paths"`
+			LineageExcludePaths []string  `json:"lineage_exclude_paths"`
+			DedupeReferences    *bool     `json:"dedupe_references"`
+			ModuleDetection     string    `json:"module_detection"`
+			PIIPatterns         *[]string `json:"pii_patterns"`
+			DetectConditional   bool      `json:"detect_conditional_calls"`
+			TrackOwnership      bool      `json:"track_ownership"`
 		}
-		if json.Unmarshal(proj.Settings, &settings) == nil && len(settings.LineageExcludePaths) > 0 {
-			rc.LineageExcludePaths = settings.LineageExcludePaths
+		if json.Unmarshal(proj.Settings, &settings) == nil {
+			if len(settings.LineageExcludePaths) > 0 {
+				rc.LineageExcludePaths = settings.LineageExcludePaths
+			}
+			if settings.DedupeReferences != nil {
+				rc.DedupeReferences = *settings.DedupeReferences
+			}
+			switch settings.ModuleDetection {
+			case ModuleDetectionManifest, ModuleDetectionTopLevel:
+				rc.ModuleDetection = settings.ModuleDetection
+			case ModuleDetectionOff:
+			default:
+				p.logger.Warn("ignoring unknown module_detection setting",
+					slog.String("module_detection", settings.ModuleDetection))
+			}
+			if settings.PIIPatterns != nil {
+				rc.PIIPatterns = *settings.PIIPatterns
+			}
+			rc.DetectConditionalCalls = settings.DetectConditional
+			rc.TrackOwnership = settings.TrackOwnership
 		}
 	}
 
+	readiness := ""
 	for _, stage := range p.stages {
+		if len(msg.Stages) > 0 && !slices.Contains(msg.Stages, stage.Name()) {
+			continue
+		}
+		if slices.Contains(msg.SkipStages, stage.Name()) {
+			p.logger.Warn("stage skipped", slog.String("stage", stage.Name()),
+				slog.String("index_run_id", msg.IndexRunID.String()))
+			continue
+		}
 		p.logger.Info("stage started", slog.String("stage", stage.Name()),
 			slog.String("index_run_id", msg.IndexRunID.String()))
 
+		if r := readinessForStage(stage.Name()); r != readiness {
+			readiness = r
+			p.setReadiness(ctx, msg.ProjectID, r)
+		}
+
 		if err := stage.Execute(ctx, rc); err != nil {
 			errMsg := err.Error()
 			_ = p.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
@@ -94,6 +162,9 @@ func (p *Pipeline) Run(ctx context.Context, msg IngestMessage) error {
 		return fmt.Errorf("update status to completed: %w", err)
 	}
 
+	// A failed run leaves the project not ready, so tools keep warning until a run succeeds
+	p.setReadiness(ctx, msg.ProjectID, store.ReadinessReady)
+
 	p.logger.Info("pipeline completed",
 		slog.String("index_run_id", msg.IndexRunID.String()),
 		slog.Int("files", rc.FilesProcessed),
@@ -103,6 +174,27 @@ func (p *Pipeline) Run(ctx context.Context, msg IngestMessage) error {
 	return nil
 }
 
+// readinessForStage returns the project readiness state while the named stage runs.
+func readinessForStage(name string) string {
+	switch name {
+	case "clone", "parse":
+		return store.ReadinessIndexing
+	case "resolve":
+		return store.ReadinessResolving
+	default:
+		return store.ReadinessAnalyticsPending
+	}
+}
+
+func (p *Pipeline) setReadiness(ctx context.Context, projectID uuid.UUID, readiness string) {
+	if err := p.store.SetProjectReadiness(ctx, postgres.SetProjectReadinessParams{
+		ID:        projectID,
+		Readiness: readiness,
+	}); err != nil {
+		p.logger.Warn("update project readiness", slog.String("readiness", readiness), slog.String("error", err.Error()))
+	}
+}
+
 // NoOpStage is a placeholder stage that just logs.
 type NoOpStage struct {
 	name string
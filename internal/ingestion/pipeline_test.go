@@ -0,0 +1,87 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeDB answers every statement with err, or with success and zero-valued rows when err
+// is nil, which loads a project as unarchived with no settings.
+type fakeDB struct {
+	err error
+}
+
+func (db fakeDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, db.err
+}
+
+func (db fakeDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("fakeDB: Query not supported")
+}
+
+func (db fakeDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return fakeRow(db)
+}
+
+type fakeRow fakeDB
+
+func (r fakeRow) Scan(...any) error { return r.err }
+
+// recordingStage records that it ran.
+type recordingStage struct {
+	name string
+	ran  *[]string
+}
+
+func (s recordingStage) Name() string { return s.name }
+
+func (s recordingStage) Execute(context.Context, *IndexRunContext) error {
+	*s.ran = append(*s.ran, s.name)
+	return nil
+}
+
+func newTestPipeline(db fakeDB, ran *[]string, names ...string) *Pipeline {
+	var stages []Stage
+	for _, name := range names {
+		stages = append(stages, recordingStage{name: name, ran: ran})
+	}
+	return NewPipeline(&store.Store{Queries: postgres.New(db)}, stages, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestPipeline_FailsWhenProjectCannotBeLoaded(t *testing.T) {
+	down := errors.New("connection refused")
+	var ran []string
+	p := newTestPipeline(fakeDB{err: down}, &ran, "clone", "parse")
+
+	err := p.Run(context.Background(), IngestMessage{IndexRunID: uuid.New(), ProjectID: uuid.New()})
+	if !errors.Is(err, down) || errors.Is(err, ErrProjectArchived) {
+		t.Fatalf("Run = %v, want the load error", err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("stages %v ran without the project", ran)
+	}
+}
+
+func TestPipeline_RunsOnlyRequestedStages(t *testing.T) {
+	var ran []string
+	p := newTestPipeline(fakeDB{}, &ran, "clone", "parse", "resolve", GraphStageName, "analytics")
+
+	msg := IngestMessage{IndexRunID: uuid.New(), ProjectID: uuid.New(), Stages: []string{GraphStageName}}
+	if err := p.Run(context.Background(), msg); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := []string{GraphStageName}; !slices.Equal(ran, want) {
+		t.Fatalf("ran %v, want %v", ran, want)
+	}
+}
@@ -3,8 +3,10 @@ package ingestion
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +20,46 @@ const (
 	ClaimTimeout  = 5 * time.Minute
 )
 
+// RetrySet holds failed jobs waiting to be retried, scored by the Unix time in
+// milliseconds from which they are due. Consumers move due jobs back onto StreamName.
+const RetrySet = "lattice:ingest:retry"
+
+// A failed job is retried RetryBaseDelay after its first failure, with the delay
+// doubling on each further failure up to RetryMaxDelay.
+const (
+	RetryBaseDelay = 30 * time.Second
+	RetryMaxDelay  = 10 * time.Minute
+)
+
+// retryDelay is how long a job waits before its attempt (counting from 1 for the first
+// retry).
+func retryDelay(attempt int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempt && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, RetryMaxDelay)
+}
+
+// pendingPushPrefix keys, per project and source, the index run of the push-triggered
+// job of the source waiting in StreamName. A run indexes one source of its project, so
+// the key names both.
+const pendingPushPrefix = "lattice:ingest:pending:"
+
+func pendingPushKey(msg IngestMessage) string {
+	return pendingPushPrefix + msg.ProjectID.String() + ":" + msg.SourceID.String()
+}
+
+// CoalescedError is returned by Enqueue for a push that the index run of a job already
+// waiting in the stream will cover.
+type CoalescedError struct {
+	IndexRunID uuid.UUID // the waiting job's index run
+}
+
+func (e *CoalescedError) Error() string {
+	return "coalesced into index run " + e.IndexRunID.String()
+}
+
 // IngestMessage is the payload enqueued for worker processing.
 type IngestMessage struct {
 	IndexRunID uuid.UUID `json:"index_run_id"`
@@ -25,126 +67,214 @@ type IngestMessage struct {
 	SourceID   uuid.UUID `json:"source_id"`
 	SourceType string    `json:"source_type"`
 	Trigger    string    `json:"trigger"` // "manual", "webhook", "schedule"
+
+	// Attempt counts the earlier failed attempts of the job; after MaxRetries it is
+	// moved to DeadLetterStream.
+	Attempt int `json:"attempt,omitempty"`
+	// SkipStages names pipeline stages not to run, set when replaying a job that a
+	// stage keeps failing.
+	SkipStages []string `json:"skip_stages,omitempty"`
+	// Stages, when set, names the only pipeline stages to run, such as GraphStageName
+	// alone to rebuild a project's graph without re-indexing it.
+	Stages []string `json:"stages,omitempty"`
 }
 
 // Producer enqueues ingestion jobs to the Valkey stream.
 type Producer struct {
-	client valkey.Client
+	streams        streams
+	coalesceWindow time.Duration
 }
 
 func NewProducer(client valkey.Client) *Producer {
-	return &Producer{client: client}
+	return &Producer{streams: valkeyStreams{client}}
 }
 
+// SetCoalesceWindow makes Enqueue coalesce pushes: while the job of a push waits in the
+// stream, for up to window after it was enqueued, later pushes to its source are not
+// enqueued and get a CoalescedError instead. The clone stage diffs the source's last
+// indexed commit against HEAD, so the waiting job's run covers them. A job stops taking
+// in pushes once a consumer starts it, whatever the number of consumers or their
+// concurrency. 0, the default, enqueues every push.
+func (p *Producer) SetCoalesceWindow(window time.Duration) {
+	p.coalesceWindow = window
+}
+
+// Enqueue adds msg to the stream and returns its entry ID, or returns a CoalescedError
+// for a push coalesced into a waiting job; see SetCoalesceWindow.
 func (p *Producer) Enqueue(ctx context.Context, msg IngestMessage) (string, error) {
-	data, err := json.Marshal(msg)
+	if msg.Trigger != "webhook" || p.coalesceWindow <= 0 {
+		return enqueue(ctx, p.streams, msg)
+	}
+
+	key := pendingPushKey(msg)
+	set, err := p.streams.setNX(ctx, key, msg.IndexRunID.String(), p.coalesceWindow)
 	if err != nil {
-		return "", fmt.Errorf("marshal message: %w", err)
+		return "", fmt.Errorf("set %s: %w", key, err)
+	}
+	if !set {
+		waiting, err := p.streams.get(ctx, key)
+		if err != nil {
+			return "", fmt.Errorf("get %s: %w", key, err)
+		}
+		// An empty value means the waiting job started, or the key expired, since setNX
+		if runID, err := uuid.Parse(waiting); err == nil {
+			return "", &CoalescedError{IndexRunID: runID}
+		}
+		return enqueue(ctx, p.streams, msg)
 	}
 
-	resp := p.client.Do(ctx, p.client.B().Xadd().
-		Key(StreamName).Id("*").
-		FieldValue().FieldValue("data", string(data)).
-		Build())
-	if err := resp.Error(); err != nil {
-		return "", fmt.Errorf("xadd: %w", err)
+	id, err := enqueue(ctx, p.streams, msg)
+	if err != nil {
+		// Later pushes must not be coalesced into a job that is not in the stream
+		_ = p.streams.del(ctx, key)
+		return "", err
 	}
+	return id, nil
+}
 
-	id, err := resp.ToString()
+// enqueue adds msg to StreamName as is.
+func enqueue(ctx context.Context, s streams, msg IngestMessage) (string, error) {
+	data, err := json.Marshal(msg)
 	if err != nil {
-		return "", fmt.Errorf("parse xadd response: %w", err)
+		return "", fmt.Errorf("marshal message: %w", err)
+	}
+	id, err := s.xadd(ctx, StreamName, map[string]string{"data": string(data)})
+	if err != nil {
+		return "", fmt.Errorf("xadd: %w", err)
 	}
 	return id, nil
 }
 
 // Consumer reads ingestion jobs from the Valkey stream.
 type Consumer struct {
-	client     valkey.Client
+	streams    streams
 	consumerID string
 	logger     *slog.Logger
+	slots      chan struct{} // one token per job that may run at once
+	inFlight   sync.WaitGroup
+	now        func() time.Time
 }
 
+// NewConsumer creates a consumer that handles one job at a time; see SetConcurrency.
 func NewConsumer(client valkey.Client, consumerID string, logger *slog.Logger) *Consumer {
-	return &Consumer{client: client, consumerID: consumerID, logger: logger}
+	c := &Consumer{streams: valkeyStreams{client}, consumerID: consumerID, logger: logger, now: time.Now}
+	c.SetConcurrency(1)
+	return c
+}
+
+// SetConcurrency sets how many jobs are handled at once (n < 1 is treated as 1). The
+// consumer only reads as many messages as it has free slots, so jobs it cannot start
+// stay in the stream for other workers. Call it before Consume.
+func (c *Consumer) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.slots = make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		c.slots <- struct{}{}
+	}
 }
 
 // EnsureGroup creates the consumer group if it doesn't exist.
 func (c *Consumer) EnsureGroup(ctx context.Context) error {
-	resp := c.client.Do(ctx, c.client.B().XgroupCreate().
-		Key(StreamName).Group(GroupName).Id("0").Mkstream().Build())
-	if err := resp.Error(); err != nil {
-		// BUSYGROUP means group already exists — that's fine
-		if err.Error() != "BUSYGROUP Consumer Group name already exists" {
-			return fmt.Errorf("xgroup create: %w", err)
-		}
+	if err := c.streams.xgroupCreate(ctx, StreamName, GroupName); err != nil {
+		return fmt.Errorf("xgroup create: %w", err)
 	}
 	return nil
 }
 
 // Consume blocks until a message is available, processes it via handler, and ACKs.
-// On startup, it first drains any pending messages from a previous crash.
+// On startup, it first drains any pending messages from a previous crash. Before each
+// read it moves the retries that are due from RetrySet onto the stream. Up to the
+// configured concurrency jobs run at once; on shutdown Consume waits for them to return.
 func (c *Consumer) Consume(ctx context.Context, handler func(context.Context, IngestMessage) error) error {
+	defer c.inFlight.Wait()
+
 	// First, drain pending messages from previous runs (Id "0" returns pending)
 	c.drainPending(ctx, handler)
 
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+		free, err := c.acquireSlots(ctx)
+		if err != nil {
+			return err
 		}
 
-		resp := c.client.Do(ctx, c.client.B().Xreadgroup().
-			Group(GroupName, c.consumerID).
-			Count(1).Block(5000).
-			Streams().Key(StreamName).Id(">").
-			Build())
-
-		if err := resp.Error(); err != nil {
+		c.promoteRetries(ctx)
+		messages, err := c.streams.xreadgroup(ctx, StreamName, GroupName, c.consumerID, ">", int64(free), 5*time.Second)
+		if err != nil {
+			c.releaseSlots(free)
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
-			// Timeout is normal for BLOCK reads
 			continue
 		}
 
-		results, err := resp.AsXRead()
-		if err != nil {
-			continue
+		for _, msg := range messages {
+			if free == 0 {
+				// XREADGROUP honours COUNT; never run more than we hold.
+				c.logger.Warn("read more messages than free slots", slog.String("id", msg.ID))
+				break
+			}
+			free--
+			c.dispatch(ctx, msg, handler)
 		}
+		c.releaseSlots(free)
+	}
+}
 
-		for _, messages := range results {
-			for _, msg := range messages {
-				c.processMessage(ctx, msg, handler)
-			}
+// acquireSlots waits for at least one free job slot and then takes every other slot
+// that is free, returning how many it holds.
+func (c *Consumer) acquireSlots(ctx context.Context) (int, error) {
+	select {
+	case <-c.slots:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	n := 1
+	for {
+		select {
+		case <-c.slots:
+			n++
+		default:
+			return n, nil
 		}
 	}
 }
 
+func (c *Consumer) releaseSlots(n int) {
+	for i := 0; i < n; i++ {
+		c.slots <- struct{}{}
+	}
+}
+
+// dispatch handles msg in its own goroutine using a slot already held by the caller,
+// releasing the slot when the handler returns.
+func (c *Consumer) dispatch(ctx context.Context, msg valkey.XRangeEntry, handler func(context.Context, IngestMessage) error) {
+	c.inFlight.Add(1)
+	go func() {
+		defer c.inFlight.Done()
+		defer c.releaseSlots(1)
+		c.processMessage(ctx, msg, handler)
+	}()
+}
+
 // drainPending reads messages previously delivered to this consumer but not ACKed.
 func (c *Consumer) drainPending(ctx context.Context, handler func(context.Context, IngestMessage) error) {
 	// XREADGROUP with Id "0" returns pending messages for this consumer
-	resp := c.client.Do(ctx, c.client.B().Xreadgroup().
-		Group(GroupName, c.consumerID).
-		Count(10).
-		Streams().Key(StreamName).Id("0").
-		Build())
-
-	if err := resp.Error(); err != nil {
-		c.logger.Warn("drain pending failed", slog.String("error", err.Error()))
-		return
-	}
-
-	results, err := resp.AsXRead()
+	messages, err := c.streams.xreadgroup(ctx, StreamName, GroupName, c.consumerID, "0", 10, 0)
 	if err != nil {
+		c.logger.Warn("drain pending failed", slog.String("error", err.Error()))
 		return
 	}
 
-	for _, messages := range results {
-		for _, msg := range messages {
-			c.logger.Info("recovering pending message", slog.String("id", msg.ID))
-			c.processMessage(ctx, msg, handler)
+	for _, msg := range messages {
+		c.logger.Info("recovering pending message", slog.String("id", msg.ID))
+		select {
+		case <-c.slots:
+		case <-ctx.Done():
+			return
 		}
+		c.dispatch(ctx, msg, handler)
 	}
 }
 
@@ -163,19 +293,88 @@ func (c *Consumer) processMessage(ctx context.Context, msg valkey.XRangeEntry, h
 		return
 	}
 
+	if ingestMsg.Trigger == "webhook" {
+		c.startPush(ctx, ingestMsg)
+	}
+
 	if err := handler(ctx, ingestMsg); err != nil {
 		c.logger.Error("handle message", slog.String("error", err.Error()),
 			slog.String("id", msg.ID),
-			slog.String("index_run_id", ingestMsg.IndexRunID.String()))
+			slog.String("index_run_id", ingestMsg.IndexRunID.String()),
+			slog.Int("attempt", ingestMsg.Attempt+1))
+		c.retry(ctx, msg.ID, ingestMsg, err)
 	} else {
 		c.ack(ctx, msg.ID)
 	}
 }
 
+// startPush clears the waiting push of msg's source if msg is its job, so that pushes
+// from now on, which the run may not see, are enqueued instead of coalesced into it. A
+// push enqueued between get and del may not be coalesced, which only costs a run.
+func (c *Consumer) startPush(ctx context.Context, msg IngestMessage) {
+	key := pendingPushKey(msg)
+	waiting, err := c.streams.get(ctx, key)
+	if err == nil && waiting == msg.IndexRunID.String() {
+		err = c.streams.del(ctx, key)
+	}
+	if err != nil {
+		c.logger.Warn("clear waiting push", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
+
+// retry schedules a failed job for another attempt after retryDelay, or once it has
+// failed MaxRetries times moves it to DeadLetterStream, and ACKs the original. A job
+// refused because its project is archived is dropped. If neither can be written the
+// message stays pending.
+func (c *Consumer) retry(ctx context.Context, msgID string, msg IngestMessage, cause error) {
+	switch {
+	case errors.Is(cause, ErrProjectArchived):
+	case msg.Attempt+1 < MaxRetries:
+		msg.Attempt++
+		data, err := json.Marshal(msg)
+		if err == nil {
+			due := c.now().Add(retryDelay(msg.Attempt))
+			err = c.streams.zadd(ctx, RetrySet, float64(due.UnixMilli()), string(data))
+		}
+		if err != nil {
+			c.logger.Error("schedule retry failed", slog.String("error", err.Error()), slog.String("id", msgID))
+			return
+		}
+	default:
+		if err := addDeadLetter(ctx, c.streams, msg, cause); err != nil {
+			c.logger.Error("dead-letter failed", slog.String("error", err.Error()), slog.String("id", msgID))
+			return
+		}
+		c.logger.Warn("message dead-lettered", slog.String("id", msgID),
+			slog.String("index_run_id", msg.IndexRunID.String()))
+	}
+	c.ack(ctx, msgID)
+}
+
+// promoteRetries moves the jobs of RetrySet that are due onto the stream. A job is only
+// moved by the consumer whose ZREM removes it, so each is enqueued once.
+func (c *Consumer) promoteRetries(ctx context.Context) {
+	now := float64(c.now().UnixMilli())
+	due, err := c.streams.zrangeByScore(ctx, RetrySet, now, 100)
+	if err != nil {
+		c.logger.Warn("read due retries", slog.String("error", err.Error()))
+		return
+	}
+	for _, data := range due {
+		removed, err := c.streams.zrem(ctx, RetrySet, data)
+		if err != nil || !removed {
+			continue
+		}
+		if _, err := c.streams.xadd(ctx, StreamName, map[string]string{"data": data}); err != nil {
+			c.logger.Error("enqueue retry failed", slog.String("error", err.Error()))
+			// Put it back so a later pass retries it
+			_ = c.streams.zadd(ctx, RetrySet, now, data)
+		}
+	}
+}
+
 func (c *Consumer) ack(ctx context.Context, msgID string) {
-	resp := c.client.Do(ctx, c.client.B().Xack().
-		Key(StreamName).Group(GroupName).Id(msgID).Build())
-	if err := resp.Error(); err != nil {
+	if err := c.streams.xack(ctx, StreamName, GroupName, msgID); err != nil {
 		c.logger.Error("xack failed", slog.String("error", err.Error()), slog.String("id", msgID))
 	}
 }
@@ -0,0 +1,380 @@
+package ingestion
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/valkey-io/valkey-go"
+)
+
+// fakeStreams is an in-memory streams with one consumer group per stream.
+type fakeStreams struct {
+	mu      sync.Mutex
+	seq     int
+	streams map[string][]*fakeEntry
+	keys    map[string]string
+	zsets   map[string]map[string]float64
+	added   chan struct{} // signalled by xadd, for blocked reads
+}
+
+type fakeEntry struct {
+	entry     valkey.XRangeEntry
+	delivered bool
+	acked     bool
+}
+
+func newFakeStreams() *fakeStreams {
+	return &fakeStreams{
+		streams: make(map[string][]*fakeEntry),
+		keys:    make(map[string]string),
+		zsets:   make(map[string]map[string]float64),
+		added:   make(chan struct{}, 1),
+	}
+}
+
+func (f *fakeStreams) xadd(_ context.Context, key string, fields map[string]string) (string, error) {
+	f.mu.Lock()
+	f.seq++
+	id := fmt.Sprintf("%d-0", f.seq)
+	f.streams[key] = append(f.streams[key], &fakeEntry{entry: valkey.XRangeEntry{ID: id, FieldValues: fields}})
+	f.mu.Unlock()
+	select {
+	case f.added <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+func (f *fakeStreams) xreadgroup(ctx context.Context, key, _, _, id string, count int64, block time.Duration) ([]valkey.XRangeEntry, error) {
+	read := func() []valkey.XRangeEntry {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var out []valkey.XRangeEntry
+		for _, e := range f.streams[key] {
+			if int64(len(out)) == count {
+				break
+			}
+			if id == ">" && !e.delivered || id == "0" && e.delivered && !e.acked {
+				e.delivered = true
+				out = append(out, e.entry)
+			}
+		}
+		return out
+	}
+	if out := read(); len(out) > 0 || block <= 0 {
+		return out, nil
+	}
+	select {
+	case <-f.added:
+	case <-time.After(block):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return read(), nil
+}
+
+func (f *fakeStreams) xack(_ context.Context, key, _, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range f.streams[key] {
+		if e.entry.ID == id {
+			e.acked = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeStreams) xgroupCreate(context.Context, string, string) error { return nil }
+
+func (f *fakeStreams) xrange(_ context.Context, key, start, end string) ([]valkey.XRangeEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []valkey.XRangeEntry
+	for _, e := range f.streams[key] {
+		if (start == "-" || entrySeq(e.entry.ID) >= entrySeq(start)) && (end == "+" || entrySeq(e.entry.ID) <= entrySeq(end)) {
+			out = append(out, e.entry)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStreams) xdel(_ context.Context, key, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries := f.streams[key]
+	for i, e := range entries {
+		if e.entry.ID == id {
+			f.streams[key] = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *fakeStreams) zadd(_ context.Context, key string, score float64, member string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.zsets[key] == nil {
+		f.zsets[key] = make(map[string]float64)
+	}
+	f.zsets[key][member] = score
+	return nil
+}
+
+func (f *fakeStreams) zrangeByScore(_ context.Context, key string, max float64, count int64) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for member, score := range f.zsets[key] {
+		if score <= max && int64(len(out)) < count {
+			out = append(out, member)
+		}
+	}
+	slices.SortFunc(out, func(a, b string) int { return cmp.Compare(f.zsets[key][a], f.zsets[key][b]) })
+	return out, nil
+}
+
+func (f *fakeStreams) zrem(_ context.Context, key, member string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.zsets[key][member]
+	delete(f.zsets[key], member)
+	return ok, nil
+}
+
+func (f *fakeStreams) setNX(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	f.keys[key] = value
+	return true, nil
+}
+
+func (f *fakeStreams) get(_ context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keys[key], nil
+}
+
+func (f *fakeStreams) del(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.keys, key)
+	return nil
+}
+
+func entrySeq(id string) int {
+	n, _ := strconv.Atoi(strings.TrimSuffix(id, "-0"))
+	return n
+}
+
+func newTestConsumer(s streams) *Consumer {
+	c := &Consumer{streams: s, consumerID: "worker-1", logger: slog.New(slog.NewTextHandler(io.Discard, nil)), now: time.Now}
+	c.SetConcurrency(1)
+	return c
+}
+
+func TestConsumer_CoalescesPushesWhileBusy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := newFakeStreams()
+	producer := &Producer{streams: fake}
+	producer.SetCoalesceWindow(time.Minute)
+	consumer := newTestConsumer(fake)
+
+	project, source := uuid.New(), uuid.New()
+	push := func(source uuid.UUID) IngestMessage {
+		return IngestMessage{IndexRunID: uuid.New(), ProjectID: project, SourceID: source, Trigger: "webhook"}
+	}
+	first, second, third, other := push(source), push(source), push(source), push(uuid.New())
+
+	started := make(chan uuid.UUID, 4)
+	release := make(chan struct{})
+	handler := func(_ context.Context, msg IngestMessage) error {
+		started <- msg.IndexRunID
+		<-release
+		return nil
+	}
+
+	if _, err := producer.Enqueue(ctx, first); err != nil {
+		t.Fatalf("enqueue first: %v", err)
+	}
+	done := make(chan error)
+	go func() { done <- consumer.Consume(ctx, handler) }()
+	if got := <-started; got != first.IndexRunID {
+		t.Fatalf("expected the first push to run, got %s", got)
+	}
+
+	// The only slot is busy: the second push waits in the stream and takes in the third
+	if _, err := producer.Enqueue(ctx, second); err != nil {
+		t.Fatalf("enqueue second: %v", err)
+	}
+	var coalesced *CoalescedError
+	if _, err := producer.Enqueue(ctx, third); !errors.As(err, &coalesced) || coalesced.IndexRunID != second.IndexRunID {
+		t.Fatalf("expected the third push coalesced into the second, got %v", err)
+	}
+	if _, err := producer.Enqueue(ctx, other); err != nil {
+		t.Fatalf("a push to another source must be enqueued, got %v", err)
+	}
+
+	close(release)
+	for _, want := range []uuid.UUID{second.IndexRunID, other.IndexRunID} {
+		select {
+		case got := <-started:
+			if got != want {
+				t.Fatalf("expected run %s, got %s", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %s did not start", want)
+		}
+	}
+
+	// Once the second push's run started, a new push is enqueued again
+	if _, err := producer.Enqueue(ctx, push(source)); err != nil {
+		t.Fatalf("push after the run started: %v", err)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("consume: %v", err)
+	}
+}
+
+func TestProducer_EnqueuesEveryPushWithoutWindow(t *testing.T) {
+	fake := newFakeStreams()
+	producer := &Producer{streams: fake}
+	msg := IngestMessage{IndexRunID: uuid.New(), ProjectID: uuid.New(), SourceID: uuid.New(), Trigger: "webhook"}
+	for range 2 {
+		if _, err := producer.Enqueue(context.Background(), msg); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	if n := len(fake.streams[StreamName]); n != 2 {
+		t.Fatalf("expected 2 jobs, got %d", n)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{
+		1:  RetryBaseDelay,
+		2:  2 * RetryBaseDelay,
+		3:  4 * RetryBaseDelay,
+		10: RetryMaxDelay,
+	} {
+		if got := retryDelay(attempt); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, got)
+		}
+	}
+}
+
+// readJob delivers the next job of the stream to the consumer, failing the test if none.
+func readJob(t *testing.T, s streams) valkey.XRangeEntry {
+	t.Helper()
+	entries, err := s.xreadgroup(context.Background(), StreamName, GroupName, "worker-1", ">", 1, 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected a job in the stream, got %v (%v)", entries, err)
+	}
+	return entries[0]
+}
+
+func TestConsumer_RetryBacksOffThenDeadLettersThenReplays(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStreams()
+	consumer := newTestConsumer(fake)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	consumer.now = func() time.Time { return clock }
+
+	msg := IngestMessage{IndexRunID: uuid.New(), ProjectID: uuid.New(), SourceID: uuid.New(), SourceType: "git", Trigger: "manual"}
+	if _, err := enqueue(ctx, fake, msg); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	var attempts []int
+	failing := func(_ context.Context, m IngestMessage) error {
+		attempts = append(attempts, m.Attempt)
+		return errors.New("stage embed failed: rate limited")
+	}
+
+	for attempt := 1; attempt < MaxRetries; attempt++ {
+		consumer.processMessage(ctx, readJob(t, fake), failing)
+
+		// The retry waits out its delay rather than going straight back onto the stream
+		consumer.promoteRetries(ctx)
+		if entries, _ := fake.xreadgroup(ctx, StreamName, GroupName, "worker-1", ">", 1, 0); len(entries) != 0 {
+			t.Fatalf("attempt %d: retry enqueued before its delay", attempt)
+		}
+		clock = clock.Add(retryDelay(attempt) - time.Second)
+		consumer.promoteRetries(ctx)
+		if entries, _ := fake.xreadgroup(ctx, StreamName, GroupName, "worker-1", ">", 1, 0); len(entries) != 0 {
+			t.Fatalf("attempt %d: retry enqueued a second before its delay", attempt)
+		}
+		clock = clock.Add(time.Second)
+		consumer.promoteRetries(ctx)
+	}
+
+	// The last attempt fails too: the job is dead-lettered, not retried
+	consumer.processMessage(ctx, readJob(t, fake), failing)
+	if !slices.Equal(attempts, []int{0, 1, 2}) {
+		t.Fatalf("expected attempts 0, 1, 2, got %v", attempts)
+	}
+	if n := len(fake.zsets[RetrySet]); n != 0 {
+		t.Fatalf("expected no retry waiting, got %d", n)
+	}
+	for _, e := range fake.streams[StreamName] {
+		if !e.acked {
+			t.Fatalf("job %s left unacknowledged", e.entry.ID)
+		}
+	}
+
+	dlq := &DeadLetterQueue{streams: fake}
+	letters, err := dlq.List(ctx, msg.ProjectID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(letters) != 1 || letters[0].Message.IndexRunID != msg.IndexRunID || letters[0].Message.Attempt != MaxRetries-1 ||
+		letters[0].Error != "stage embed failed: rate limited" {
+		t.Fatalf("expected the job dead-lettered with its error, got %+v", letters)
+	}
+
+	if _, err := dlq.Replay(ctx, letters[0].ID, func(m *IngestMessage) { m.SkipStages = []string{"embed"} }); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	var replayed IngestMessage
+	if err := json.Unmarshal([]byte(readJob(t, fake).FieldValues["data"]), &replayed); err != nil {
+		t.Fatalf("unmarshal replayed job: %v", err)
+	}
+	if replayed.IndexRunID != msg.IndexRunID || replayed.Attempt != 0 || !slices.Equal(replayed.SkipStages, []string{"embed"}) {
+		t.Fatalf("expected the job replayed with attempts reset, got %+v", replayed)
+	}
+	if letters, _ := dlq.List(ctx, uuid.Nil); len(letters) != 0 {
+		t.Fatalf("expected the dead letter removed, got %+v", letters)
+	}
+}
+
+func TestConsumer_DropsArchivedProjectJobs(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeStreams()
+	consumer := newTestConsumer(fake)
+	if _, err := enqueue(ctx, fake, IngestMessage{IndexRunID: uuid.New(), Trigger: "manual"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	consumer.processMessage(ctx, readJob(t, fake), func(context.Context, IngestMessage) error {
+		return fmt.Errorf("run: %w", ErrProjectArchived)
+	})
+	if len(fake.zsets[RetrySet]) != 0 || len(fake.streams[DeadLetterStream]) != 0 {
+		t.Fatal("expected a job of an archived project to be dropped")
+	}
+}
@@ -0,0 +1,49 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/maraichr/lattice/internal/schema"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// SchemaSnapshotStage records the project's tables, columns and foreign keys as of this
+// run. Symbols are overwritten in place by later runs, so the snapshot is what lets
+// schema_diff compare two runs. Runs after resolve so foreign key edges exist.
+type SchemaSnapshotStage struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+func NewSchemaSnapshotStage(s *store.Store, logger *slog.Logger) *SchemaSnapshotStage {
+	return &SchemaSnapshotStage{store: s, logger: logger}
+}
+
+func (s *SchemaSnapshotStage) Name() string { return "schema_snapshot" }
+
+func (s *SchemaSnapshotStage) Execute(ctx context.Context, rc *IndexRunContext) error {
+	snap, err := schema.Capture(ctx, s.store, rc.ProjectID)
+	if err != nil {
+		return fmt.Errorf("capture schema: %w", err)
+	}
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encode schema snapshot: %w", err)
+	}
+	if err := s.store.UpsertSchemaSnapshot(ctx, postgres.UpsertSchemaSnapshotParams{
+		IndexRunID: rc.IndexRunID,
+		ProjectID:  rc.ProjectID,
+		Snapshot:   raw,
+	}); err != nil {
+		return fmt.Errorf("store schema snapshot: %w", err)
+	}
+
+	s.logger.Info("schema snapshot recorded",
+		slog.String("project_id", rc.ProjectID.String()),
+		slog.Int("tables", len(snap.Tables)))
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/maraichr/lattice/internal/embedding"
 	"github.com/maraichr/lattice/internal/parser"
 )
 
@@ -40,6 +41,48 @@ type IndexRunContext struct {
 	// Carried from parse to resolve stage (in-memory)
 	ParseResults []parser.FileResult
 
+	// Files without a registered extension that content detection could not route to a
+	// parser (set by parse stage, recorded in the index run metadata)
+	SkippedFiles []SkippedFile
+
+	// Files whose symbols exceeded the per-file cap (set by parse stage, recorded in the
+	// index run metadata)
+	OversizedFiles []OversizedFile
+
 	// Optional: path patterns to exclude from column lineage (from project.settings lineage_exclude_paths)
 	LineageExcludePaths []string
+
+	// Collapse identical references within a file before persistence (project.settings dedupe_references, default true)
+	DedupeReferences bool
+
+	// Tag symbols with the monorepo module/app they belong to (project.settings module_detection:
+	// "manifest" or "top_level_dir"; empty disables). ModuleRoots is set by the parse stage.
+	ModuleDetection string
+	ModuleRoots     []string
+
+	// Module paths of the go.mod files by work dir directory, "" for a directory without
+	// one; filled by the parse stage as it qualifies Go packages with their import paths
+	GoModules map[string]string
+
+	// Column-name regexes auto-tagged as PII by the analytics stage (project.settings pii_patterns;
+	// defaults to analytics.DefaultPIIPatterns, an empty list disables auto-tagging)
+	PIIPatterns []string
+
+	// Tag calls guarded by feature-flag checks as conditional edges (project.settings
+	// detect_conditional_calls, default false; JS/TS, C# and Java only)
+	DetectConditionalCalls bool
+
+	// Record the author and date of the last commit touching each file (project.settings
+	// track_ownership, default false; git sources only, which are then cloned with full history)
+	TrackOwnership bool
+
+	// Symbol kinds the embed stage embeds (project.settings embed_kinds / embed_exclude_kinds;
+	// defaults to every kind except embedding.DefaultExcludedKinds)
+	EmbedKinds embedding.KindFilter
+}
+
+// SkippedFile is a file the parse stage left out, and why.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
 }
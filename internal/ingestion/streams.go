@@ -0,0 +1,124 @@
+package ingestion
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// streams is the Valkey commands the ingestion queue runs. valkeyStreams runs them on
+// a server; the unit tests run the queue on an in-memory fake.
+type streams interface {
+	// xadd appends an entry to the stream key and returns its ID.
+	xadd(ctx context.Context, key string, fields map[string]string) (string, error)
+	// xreadgroup reads up to count entries of key for consumer in group, after id (">"
+	// for new entries, "0" for those delivered but not acknowledged), waiting up to block
+	// for new entries when block is above zero.
+	xreadgroup(ctx context.Context, key, group, consumer, id string, count int64, block time.Duration) ([]valkey.XRangeEntry, error)
+	xack(ctx context.Context, key, group, id string) error
+	// xgroupCreate creates group on key, and key if missing; an existing group is not an error.
+	xgroupCreate(ctx context.Context, key, group string) error
+	xrange(ctx context.Context, key, start, end string) ([]valkey.XRangeEntry, error)
+	xdel(ctx context.Context, key, id string) error
+
+	zadd(ctx context.Context, key string, score float64, member string) error
+	// zrangeByScore returns up to count members of key scored at most max, lowest first.
+	zrangeByScore(ctx context.Context, key string, max float64, count int64) ([]string, error)
+	// zrem removes member from key, reporting whether it was there.
+	zrem(ctx context.Context, key, member string) (bool, error)
+
+	// setNX sets key to value for ttl unless it is set, reporting whether it set it.
+	setNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// get returns the value of key, or "" if it is not set.
+	get(ctx context.Context, key string) (string, error)
+	del(ctx context.Context, key string) error
+}
+
+// valkeyStreams runs the queue's commands on a Valkey client.
+type valkeyStreams struct {
+	client valkey.Client
+}
+
+func (s valkeyStreams) xadd(ctx context.Context, key string, fields map[string]string) (string, error) {
+	fv := s.client.B().Xadd().Key(key).Id("*").FieldValue()
+	for f, v := range fields {
+		fv = fv.FieldValue(f, v)
+	}
+	return s.client.Do(ctx, fv.Build()).ToString()
+}
+
+func (s valkeyStreams) xreadgroup(ctx context.Context, key, group, consumer, id string, count int64, block time.Duration) ([]valkey.XRangeEntry, error) {
+	read := s.client.B().Xreadgroup().Group(group, consumer).Count(count)
+	var cmd valkey.Completed
+	if block > 0 {
+		cmd = read.Block(block.Milliseconds()).Streams().Key(key).Id(id).Build()
+	} else {
+		cmd = read.Streams().Key(key).Id(id).Build()
+	}
+	results, err := s.client.Do(ctx, cmd).AsXRead()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return results[key], nil
+}
+
+func (s valkeyStreams) xack(ctx context.Context, key, group, id string) error {
+	return s.client.Do(ctx, s.client.B().Xack().Key(key).Group(group).Id(id).Build()).Error()
+}
+
+func (s valkeyStreams) xgroupCreate(ctx context.Context, key, group string) error {
+	err := s.client.Do(ctx, s.client.B().XgroupCreate().Key(key).Group(group).Id("0").Mkstream().Build()).Error()
+	// BUSYGROUP means group already exists — that's fine
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func (s valkeyStreams) xrange(ctx context.Context, key, start, end string) ([]valkey.XRangeEntry, error) {
+	return s.client.Do(ctx, s.client.B().Xrange().Key(key).Start(start).End(end).Build()).AsXRange()
+}
+
+func (s valkeyStreams) xdel(ctx context.Context, key, id string) error {
+	return s.client.Do(ctx, s.client.B().Xdel().Key(key).Id(id).Build()).Error()
+}
+
+func (s valkeyStreams) zadd(ctx context.Context, key string, score float64, member string) error {
+	return s.client.Do(ctx, s.client.B().Zadd().Key(key).ScoreMember().ScoreMember(score, member).Build()).Error()
+}
+
+func (s valkeyStreams) zrangeByScore(ctx context.Context, key string, max float64, count int64) ([]string, error) {
+	return s.client.Do(ctx, s.client.B().Zrangebyscore().Key(key).
+		Min("-inf").Max(strconv.FormatFloat(max, 'f', -1, 64)).Limit(0, count).Build()).AsStrSlice()
+}
+
+func (s valkeyStreams) zrem(ctx context.Context, key, member string) (bool, error) {
+	n, err := s.client.Do(ctx, s.client.B().Zrem().Key(key).Member(member).Build()).AsInt64()
+	return n == 1, err
+}
+
+func (s valkeyStreams) setNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	err := s.client.Do(ctx, s.client.B().Set().Key(key).Value(value).Nx().Px(ttl).Build()).Error()
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s valkeyStreams) get(ctx context.Context, key string) (string, error) {
+	value, err := s.client.Do(ctx, s.client.B().Get().Key(key).Build()).ToString()
+	if valkey.IsValkeyNil(err) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (s valkeyStreams) del(ctx context.Context, key string) error {
+	return s.client.Do(ctx, s.client.B().Del().Key(key).Build()).Error()
+}
@@ -0,0 +1,51 @@
+package ingestion
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Default per-project symbol limits. They sit well above large monorepos and only trip
+// when a run picks up vendored or generated code wholesale.
+const (
+	DefaultProjectSymbolWarn  = 2_000_000
+	DefaultProjectSymbolLimit = 10_000_000
+)
+
+// ErrProjectSymbolLimit is returned by the parse stage when a project grows past its hard
+// symbol limit. The run is aborted before anything is persisted.
+var ErrProjectSymbolLimit = errors.New("project symbol limit exceeded")
+
+// SymbolLimits bound how many symbols one project may hold. At Warn the run is logged and
+// flagged in the index run metadata; past Max it is aborted. Zero disables either limit.
+type SymbolLimits struct {
+	Warn int
+	Max  int
+}
+
+// symbolBudget counts the symbols a run adds to its project against the limits.
+type symbolBudget struct {
+	limits SymbolLimits
+	total  int  // symbols in the project so far, including those kept from earlier runs
+	warned bool // total has reached limits.Warn
+}
+
+func newSymbolBudget(limits SymbolLimits, baseline int) *symbolBudget {
+	return &symbolBudget{limits: limits, total: baseline}
+}
+
+// add counts n more symbols. It returns an error wrapping ErrProjectSymbolLimit, with
+// guidance on narrowing the source, once the total exceeds the hard limit.
+func (b *symbolBudget) add(n int) error {
+	b.total += n
+	if b.limits.Warn > 0 && b.total >= b.limits.Warn {
+		b.warned = true
+	}
+	if b.limits.Max > 0 && b.total > b.limits.Max {
+		return fmt.Errorf("%w: more than %d symbols (PARSER_PROJECT_SYMBOL_LIMIT); "+
+			"exclude generated, vendored and third-party code with exclude globs such as "+
+			"**/node_modules/**, **/vendor/** or **/*.g.cs, or split the repository into several projects",
+			ErrProjectSymbolLimit, b.limits.Max)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package ingestion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// DefaultMaxSymbolsPerFile is the per-file symbol cap when none is configured. It is well
+// above hand-written code and only trips on generated blobs.
+const DefaultMaxSymbolsPerFile = 20000
+
+// FileTagOversized marks a file whose symbols were capped.
+const FileTagOversized = "oversized"
+
+// OversizedFile is a file whose symbols were capped by the parse stage.
+type OversizedFile struct {
+	Path    string `json:"path"`
+	Symbols int    `json:"symbols"`
+	Kept    int    `json:"kept"`
+}
+
+// capSymbols limits a file to max symbols, counting children. Top-level symbols (those
+// whose qualified-name parent is not declared in the file) are always kept; the remaining
+// budget goes to nested members shallowest first, in file order. References from dropped
+// members are collapsed onto their nearest kept ancestor so their edges survive.
+// It returns the kept symbols and references and how many symbols were dropped.
+func capSymbols(symbols []parser.Symbol, refs []parser.RawReference, max int) ([]parser.Symbol, []parser.RawReference, int) {
+	type entry struct {
+		qn    string
+		depth int
+	}
+	declared := make(map[string]bool)
+	var entries []entry
+	for _, sym := range symbols {
+		declared[sym.QualifiedName] = true
+		entries = append(entries, entry{qn: sym.QualifiedName})
+		for _, child := range sym.Children {
+			declared[child.QualifiedName] = true
+			entries = append(entries, entry{qn: child.QualifiedName})
+		}
+	}
+	if len(entries) <= max {
+		return symbols, refs, 0
+	}
+
+	for i := range entries {
+		for parent := parentName(entries[i].qn); parent != ""; parent = parentName(parent) {
+			if declared[parent] {
+				entries[i].depth++
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].depth < entries[j].depth })
+
+	kept := make(map[string]bool, max)
+	for i, e := range entries {
+		if e.depth > 0 && i >= max {
+			break
+		}
+		kept[e.qn] = true
+	}
+
+	var out []parser.Symbol
+	for _, sym := range symbols {
+		if !kept[sym.QualifiedName] {
+			continue
+		}
+		var children []parser.Symbol
+		for _, child := range sym.Children {
+			if kept[child.QualifiedName] {
+				children = append(children, child)
+			}
+		}
+		sym.Children = children
+		out = append(out, sym)
+	}
+
+	outRefs := make([]parser.RawReference, 0, len(refs))
+	for _, ref := range refs {
+		if declared[ref.FromSymbol] {
+			from := ref.FromSymbol
+			for from != "" && !kept[from] {
+				from = parentName(from)
+			}
+			if from == "" {
+				continue
+			}
+			ref.FromSymbol = from
+		}
+		outRefs = append(outRefs, ref)
+	}
+
+	return out, outRefs, len(entries) - len(kept)
+}
+
+// countSymbols counts symbols including their children.
+func countSymbols(symbols []parser.Symbol) int {
+	n := len(symbols)
+	for _, sym := range symbols {
+		n += len(sym.Children)
+	}
+	return n
+}
+
+// parentName strips the last dotted segment of a qualified name ("" at the top).
+func parentName(qn string) string {
+	i := strings.LastIndex(qn, ".")
+	if i < 0 {
+		return ""
+	}
+	return qn[:i]
+}
+
+// oversizedWarning is the parse warning recorded for a capped file.
+func oversizedWarning(total, kept, max int) parser.ParseWarning {
+	return parser.ParseWarning{
+		Message: fmt.Sprintf("file has %d symbols, over the cap of %d; kept %d top-level and shallowest members", total, max, kept),
+	}
+}
@@ -0,0 +1,230 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/resolver"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// maxTSConfigExtends bounds how many extends a tsconfig.json chain is followed through.
+const maxTSConfigExtends = 8
+
+// tsconfigJSON is the part of a tsconfig.json that module resolution depends on.
+type tsconfigJSON struct {
+	Extends         json.RawMessage `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// tsconfigOptions are a tsconfig's effective baseUrl and paths, after extends, as
+// absolute directories.
+type tsconfigOptions struct {
+	baseURL  string // "" when unset
+	paths    map[string][]string
+	pathsDir string // directory of the tsconfig that set paths
+}
+
+// recordTSPaths stores the path aliases of the tsconfig.json files in the work dir as
+// project metadata of the run's source, for the resolver to rewrite aliased imports.
+// A tsconfig that cannot be read is logged and skipped.
+func recordTSPaths(ctx context.Context, s *store.Store, rc *IndexRunContext, logger *slog.Logger) error {
+	configs := loadTSPathConfigs(rc.WorkDir, logger)
+	if len(configs) == 0 {
+		return s.DeleteProjectMetadata(ctx, postgres.DeleteProjectMetadataParams{
+			ProjectID: rc.ProjectID,
+			SourceID:  rc.SourceID,
+			Key:       resolver.TSPathsMetadataKey,
+		})
+	}
+	value, err := json.Marshal(configs)
+	if err != nil {
+		return err
+	}
+	return s.UpsertProjectMetadata(ctx, postgres.UpsertProjectMetadataParams{
+		ProjectID: rc.ProjectID,
+		SourceID:  rc.SourceID,
+		Key:       resolver.TSPathsMetadataKey,
+		Value:     value,
+	})
+}
+
+// loadTSPathConfigs finds the tsconfig.json files under workDir and returns the module
+// resolution settings of those that set a baseUrl or paths, ordered by directory.
+func loadTSPathConfigs(workDir string, logger *slog.Logger) []resolver.TSPathConfig {
+	var files []string
+	_ = filepath.Walk(workDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if moduleSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == "tsconfig.json" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+
+	var configs []resolver.TSPathConfig
+	for _, file := range files {
+		opts, err := readTSConfig(file, 0)
+		if err != nil {
+			logger.Warn("skipping tsconfig", slog.String("file", relSlash(workDir, file)), slog.String("error", err.Error()))
+			continue
+		}
+		if opts.baseURL == "" && len(opts.paths) == 0 {
+			continue
+		}
+
+		cfg := resolver.TSPathConfig{Dir: relSlash(workDir, filepath.Dir(file))}
+		if cfg.Dir == "." {
+			cfg.Dir = ""
+		}
+		// paths targets are relative to baseUrl, or without one to their own tsconfig.
+		pathsBase := opts.pathsDir
+		if opts.baseURL != "" {
+			cfg.BaseURL = relSlash(workDir, opts.baseURL)
+			pathsBase = opts.baseURL
+		}
+		if len(opts.paths) > 0 {
+			cfg.Paths = make(map[string][]string, len(opts.paths))
+			for pattern, targets := range opts.paths {
+				for _, t := range targets {
+					cfg.Paths[pattern] = append(cfg.Paths[pattern], relSlash(workDir, filepath.Join(pathsBase, t)))
+				}
+			}
+		}
+		configs = append(configs, cfg)
+	}
+	return configs
+}
+
+// readTSConfig reads a tsconfig.json and the relative tsconfigs it extends, the
+// extending file's options overriding those it extends. Extends of a package, such as
+// "@tsconfig/node20/tsconfig.json", are not followed.
+func readTSConfig(file string, depth int) (tsconfigOptions, error) {
+	var opts tsconfigOptions
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return opts, err
+	}
+	var tc tsconfigJSON
+	if err := json.Unmarshal(stripJSONC(data), &tc); err != nil {
+		return opts, fmt.Errorf("parse %s: %w", filepath.Base(file), err)
+	}
+	dir := filepath.Dir(file)
+
+	// extends is a path, or since TypeScript 5.0 a list of them applied in order
+	var bases []string
+	if len(tc.Extends) > 0 {
+		var one string
+		if err := json.Unmarshal(tc.Extends, &one); err == nil {
+			bases = []string{one}
+		} else {
+			_ = json.Unmarshal(tc.Extends, &bases)
+		}
+	}
+	for _, base := range bases {
+		if !strings.HasPrefix(base, "./") && !strings.HasPrefix(base, "../") {
+			continue
+		}
+		if depth >= maxTSConfigExtends {
+			return opts, fmt.Errorf("%s: extends nested too deeply", filepath.Base(file))
+		}
+		path := filepath.Join(dir, base)
+		if !strings.HasSuffix(path, ".json") {
+			path += ".json"
+		}
+		parent, err := readTSConfig(path, depth+1)
+		if err != nil {
+			return opts, err
+		}
+		if parent.baseURL != "" {
+			opts.baseURL = parent.baseURL
+		}
+		if parent.paths != nil {
+			opts.paths, opts.pathsDir = parent.paths, parent.pathsDir
+		}
+	}
+
+	if tc.CompilerOptions.BaseURL != nil {
+		opts.baseURL = filepath.Join(dir, *tc.CompilerOptions.BaseURL)
+	}
+	if tc.CompilerOptions.Paths != nil {
+		opts.paths, opts.pathsDir = tc.CompilerOptions.Paths, dir
+	}
+	return opts, nil
+}
+
+// stripJSONC turns the JSON-with-comments of tsconfig files into JSON: // and /* */
+// comments and trailing commas are removed outside of strings.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && (data[i] != '*' || data[i+1] != '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// relSlash returns path relative to root, slash-separated.
+func relSlash(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
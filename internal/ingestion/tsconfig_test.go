@@ -0,0 +1,44 @@
+package ingestion
+
+import (
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/resolver"
+)
+
+func TestLoadTSPathConfigs_ExtendsAndComments(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "tsconfig.base.json", `{
+  // shared by every app
+  "compilerOptions": {
+    "baseUrl": ".",
+    "paths": {
+      "@shared/*": ["libs/shared/src/*"], /* trailing comma next */
+    },
+  },
+}`)
+	writeFile(t, root, "apps/web/tsconfig.json", `{
+  "extends": "../../tsconfig.base",
+  "compilerOptions": {
+    "baseUrl": "src",
+    "paths": { "@app/*": ["app/*"], "@env": ["environments/env.ts"] }
+  }
+}`)
+	writeFile(t, root, "apps/admin/tsconfig.json", `{ "extends": "../../tsconfig.base.json" }`)
+	writeFile(t, root, "apps/plain/tsconfig.json", `{ "compilerOptions": { "strict": true } }`)
+	writeFile(t, root, "node_modules/dep/tsconfig.json", `{ "compilerOptions": { "baseUrl": "." } }`)
+
+	got := loadTSPathConfigs(root, slog.Default())
+	want := []resolver.TSPathConfig{
+		{Dir: "apps/admin", BaseURL: ".", Paths: map[string][]string{"@shared/*": {"libs/shared/src/*"}}},
+		{Dir: "apps/web", BaseURL: "apps/web/src", Paths: map[string][]string{
+			"@app/*": {"apps/web/src/app/*"},
+			"@env":   {"apps/web/src/environments/env.ts"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadTSPathConfigs =\n%+v\nwant\n%+v", got, want)
+	}
+}
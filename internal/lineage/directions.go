@@ -0,0 +1,150 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+// Direction is where an edge's target lies relative to its source in lineage.
+type Direction string
+
+const (
+	// Downstream targets receive data or control from the source: a procedure's callees
+	// and the tables it writes_to.
+	Downstream Direction = "downstream"
+	// Upstream targets feed the source: the tables a procedure reads_from.
+	Upstream Direction = "upstream"
+)
+
+// EdgeDirections maps an edge type to the direction of its target. Types not listed are
+// Downstream, following the edge, except inferred types such as related_to, which are
+// not followed unless listed.
+type EdgeDirections map[string]Direction
+
+// DefaultEdgeDirections returns the built-in map. Reads point from the reader at the
+// data it takes in, so their targets are upstream; writes, calls and column derivations
+// point the way data and control flow.
+func DefaultEdgeDirections() EdgeDirections {
+	return EdgeDirections{
+		"reads_from":    Upstream,
+		"joins":         Upstream,
+		"reads_config":  Upstream,
+		"writes_to":     Downstream,
+		"calls":         Downstream,
+		"calls_api":     Downstream,
+		"calls_graphql": Downstream,
+		"direct_copy":   Downstream,
+		"transforms_to": Downstream,
+		"uses_column":   Downstream,
+	}
+}
+
+// NewEdgeDirections returns the defaults with overrides applied, e.g.
+// {"uses_table": "upstream"}.
+func NewEdgeDirections(overrides map[string]string) (EdgeDirections, error) {
+	d := DefaultEdgeDirections()
+	for edgeType, dir := range overrides {
+		switch Direction(dir) {
+		case Upstream, Downstream:
+			d[edgeType] = Direction(dir)
+		default:
+			return nil, fmt.Errorf("edge type %s: direction must be upstream or downstream, got %q", edgeType, dir)
+		}
+	}
+	return d, nil
+}
+
+// WithInferred returns a copy that also follows inferred edges. A related_to edge points
+// from a column to the key it seems to reference, whose values it takes, so its target
+// is upstream.
+func (d EdgeDirections) WithInferred() EdgeDirections {
+	w := make(EdgeDirections, len(d)+1)
+	for edgeType, dir := range d {
+		w[edgeType] = dir
+	}
+	if _, ok := w[string(models.EdgeTypeRelatedTo)]; !ok {
+		w[string(models.EdgeTypeRelatedTo)] = Upstream
+	}
+	return w
+}
+
+// UpstreamTypes returns the edge types whose targets are upstream, sorted.
+func (d EdgeDirections) UpstreamTypes() []string {
+	var types []string
+	for edgeType, dir := range d {
+		if dir == Upstream {
+			types = append(types, edgeType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// follows reports whether walks take edges of a type: inferred types only when listed.
+func (d EdgeDirections) follows(edgeType string) bool {
+	if !models.IsInferredEdgeType(edgeType) {
+		return true
+	}
+	_, ok := d[edgeType]
+	return ok
+}
+
+// Of returns the direction of an edge type's target.
+func (d EdgeDirections) Of(edgeType string) Direction {
+	if dir, ok := d[edgeType]; ok {
+		return dir
+	}
+	return Downstream
+}
+
+// EdgeGraph is the edge lookup Neighbors needs.
+type EdgeGraph interface {
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]postgres.SymbolEdge, error)
+}
+
+// Hop is one step of a lineage walk: the edge taken and the symbol it reaches.
+type Hop struct {
+	Edge    postgres.SymbolEdge
+	ID      uuid.UUID
+	Outward bool // the edge points from the symbol stepped from to ID
+}
+
+// Neighbors returns the symbols one step upstream or downstream of id. Upstream are the
+// sources of incoming Downstream edges and the targets of outgoing Upstream ones;
+// downstream is the reverse.
+func (d EdgeDirections) Neighbors(ctx context.Context, g EdgeGraph, id uuid.UUID, upstream bool) ([]Hop, error) {
+	out, err := g.GetOutgoingEdges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	in, err := g.GetIncomingEdges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Along outgoing edges the target is reached in the edge's direction; along incoming
+	// ones the source is reached against it.
+	want := Downstream
+	if upstream {
+		want = Upstream
+	}
+	var hops []Hop
+	for _, e := range out {
+		if d.follows(e.EdgeType) && d.Of(e.EdgeType) == want {
+			hops = append(hops, Hop{Edge: e, ID: e.TargetID, Outward: true})
+		}
+	}
+	for _, e := range in {
+		if d.follows(e.EdgeType) && d.Of(e.EdgeType) != want {
+			hops = append(hops, Hop{Edge: e, ID: e.SourceID})
+		}
+	}
+	return hops, nil
+}
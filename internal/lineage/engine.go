@@ -27,9 +27,96 @@ func NewEngine(s *store.Store, g *graph.Client, logger *slog.Logger) *Engine {
 	return &Engine{store: s, graph: g, logger: logger}
 }
 
-// BuildColumnLineage resolves column references to symbol IDs and creates edges.
-// Returns the number of edges created.
-func (e *Engine) BuildColumnLineage(ctx context.Context, projectID uuid.UUID, colRefs []parser.ColumnReference) (int, error) {
+// FileColumnRefs are the column references parsed from one file of a source.
+type FileColumnRefs struct {
+	Path string
+	Refs []parser.ColumnReference
+}
+
+// ReplaceColumnLineage rebuilds the column lineage derived from the given changed or
+// deleted files of a source from files, in PostgreSQL and Neo4j. An edge lists every file
+// it was derived from and is only deleted once none of them derives it, so lineage that
+// other files still derive is left intact.
+func (e *Engine) ReplaceColumnLineage(ctx context.Context, projectID, sourceID uuid.UUID, paths []string, files []FileColumnRefs) (int, error) {
+	if len(paths) > 0 {
+		origins := make([]string, len(paths))
+		for i, path := range paths {
+			origins[i] = columnEdgeOrigin(sourceID, path)
+		}
+		if err := e.releaseColumnLineage(ctx, projectID, sourceID, origins, false); err != nil {
+			return 0, err
+		}
+	}
+	return e.BuildColumnLineage(ctx, projectID, sourceID, files)
+}
+
+// RebuildColumnLineage replaces all the column lineage derived from a source with the
+// lineage of files, for a full run. Edges written before edges listed their origins cannot
+// be told apart by source, so they are dropped as well; each source's next full run
+// derives its own again.
+func (e *Engine) RebuildColumnLineage(ctx context.Context, projectID, sourceID uuid.UUID, files []FileColumnRefs) (int, error) {
+	if err := e.releaseColumnLineage(ctx, projectID, sourceID, nil, true); err != nil {
+		return 0, err
+	}
+	return e.BuildColumnLineage(ctx, projectID, sourceID, files)
+}
+
+// releaseColumnLineage removes origins, or with allFiles every origin of the source, from
+// the column-lineage edges listing them and deletes the edges left without one.
+func (e *Engine) releaseColumnLineage(ctx context.Context, projectID, sourceID uuid.UUID, origins []string, allFiles bool) error {
+	if origins == nil {
+		origins = []string{} // pgx sends nil as NULL, against which every edge reads as released
+	}
+	prefix := columnEdgeOrigin(sourceID, "")
+
+	var deleted []uuid.UUID
+	var kept int64
+	err := e.store.WithTx(ctx, func(q *postgres.Queries) error {
+		var err error
+		deleted, err = q.DeleteColumnEdgesByOrigins(ctx, postgres.DeleteColumnEdgesByOriginsParams{
+			ProjectID:    projectID,
+			Origins:      origins,
+			AllFiles:     allFiles,
+			SourcePrefix: prefix,
+		})
+		if err != nil {
+			return fmt.Errorf("delete column edges: %w", err)
+		}
+		kept, err = q.ReleaseColumnEdgeOrigins(ctx, postgres.ReleaseColumnEdgeOriginsParams{
+			Origins:      origins,
+			AllFiles:     allFiles,
+			SourcePrefix: prefix,
+			ProjectID:    projectID,
+		})
+		if err != nil {
+			return fmt.Errorf("release column edge origins: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if e.graph != nil {
+		if err := e.graph.DeleteColumnEdges(ctx, projectID, deleted); err != nil {
+			return err
+		}
+	}
+	e.logger.Info("column lineage released",
+		slog.Int("files", len(origins)),
+		slog.Bool("all_files", allFiles),
+		slog.Int("edges_deleted", len(deleted)),
+		slog.Int64("edges_kept", kept))
+	return nil
+}
+
+// BuildColumnLineage resolves column references to symbol IDs and creates edges, each
+// adding the file it was derived from to the edge's origins. Returns the number of edges
+// created.
+func (e *Engine) BuildColumnLineage(ctx context.Context, projectID, sourceID uuid.UUID, files []FileColumnRefs) (int, error) {
+	if len(files) == 0 {
+		return 0, nil
+	}
+
 	// Load all column symbols for the project
 	columns, err := e.store.ListColumnSymbolsByProject(ctx, projectID)
 	if err != nil {
@@ -54,59 +141,52 @@ func (e *Engine) BuildColumnLineage(ctx context.Context, projectID uuid.UUID, co
 
 	created := 0
 	skipped := 0
-	for _, ref := range colRefs {
-		sourceID := resolveColumnID(ref.SourceColumn, fqnMap, symbolFQN)
-		targetID := resolveColumnID(ref.TargetColumn, fqnMap, symbolFQN)
-
-		// Fallback: if target is unresolvable (e.g. "proc.ColName"), try the
-		// parent symbol (e.g. "proc"). This creates column→procedure edges
-		// that let users trace which procedures read/write each column.
-		if targetID == uuid.Nil && ref.TargetColumn != "" {
-			if parts := strings.Split(ref.TargetColumn, "."); len(parts) > 1 {
-				parent := strings.Join(parts[:len(parts)-1], ".")
-				targetID = resolveColumnID(parent, fqnMap, symbolFQN)
+	processed := 0
+	for _, file := range files {
+		processed += len(file.Refs)
+		for _, ref := range file.Refs {
+			fromID := resolveColumnID(ref.SourceColumn, fqnMap, symbolFQN)
+			toID := resolveColumnID(ref.TargetColumn, fqnMap, symbolFQN)
+
+			// Fallback: if target is unresolvable (e.g. "proc.ColName"), try the
+			// parent symbol (e.g. "proc"). This creates column→procedure edges
+			// that let users trace which procedures read/write each column.
+			if toID == uuid.Nil && ref.TargetColumn != "" {
+				if parts := strings.Split(ref.TargetColumn, "."); len(parts) > 1 {
+					parent := strings.Join(parts[:len(parts)-1], ".")
+					toID = resolveColumnID(parent, fqnMap, symbolFQN)
+				}
 			}
-		}
 
-		// Same fallback for source: "@ParamName" won't resolve, but the
-		// procedure context might (e.g. UPDATE SET col = @param).
-		if sourceID == uuid.Nil && ref.SourceColumn != "" && ref.Context != "" {
-			sourceID = resolveColumnID(ref.Context, fqnMap, symbolFQN)
-		}
+			// Same fallback for source: "@ParamName" won't resolve, but the
+			// procedure context might (e.g. UPDATE SET col = @param).
+			if fromID == uuid.Nil && ref.SourceColumn != "" && ref.Context != "" {
+				fromID = resolveColumnID(ref.Context, fqnMap, symbolFQN)
+			}
 
-		if sourceID == uuid.Nil || targetID == uuid.Nil || sourceID == targetID {
-			skipped++
-			continue
-		}
+			if fromID == uuid.Nil || toID == uuid.Nil || fromID == toID {
+				skipped++
+				continue
+			}
 
-		edgeType := mapDerivationToEdgeType(ref.DerivationType)
-		confidence := derivationConfidence(ref.DerivationType)
-		metadata := map[string]interface{}{
-			"derivation_type": ref.DerivationType,
-			"confidence":      confidence,
-		}
-		if ref.Expression != "" {
-			metadata["expression"] = ref.Expression
-		}
-		metaJSON, _ := json.Marshal(metadata)
-
-		_, err := e.store.CreateSymbolEdgeWithMetadata(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
-			ProjectID: projectID,
-			SourceID:  sourceID,
-			TargetID:  targetID,
-			EdgeType:  edgeType,
-			Metadata:  metaJSON,
-		})
-		if err != nil {
-			continue
+			err := e.store.UpsertColumnEdge(ctx, postgres.UpsertColumnEdgeParams{
+				ProjectID: projectID,
+				SourceID:  fromID,
+				TargetID:  toID,
+				EdgeType:  mapDerivationToEdgeType(ref.DerivationType),
+				Metadata:  columnEdgeMetadata(ref, sourceID, file.Path),
+			})
+			if err != nil {
+				continue
+			}
+			created++
 		}
-		created++
 	}
 
 	e.logger.Info("column lineage built",
 		slog.Int("edges_created", created),
 		slog.Int("refs_skipped", skipped),
-		slog.Int("column_refs_processed", len(colRefs)))
+		slog.Int("column_refs_processed", processed))
 
 	return created, nil
 }
@@ -120,6 +200,26 @@ func (e *Engine) QueryColumnLineage(ctx context.Context, symbolID uuid.UUID, dir
 	return e.graph.ColumnLineage(ctx, symbolID, direction, maxDepth)
 }
 
+// columnEdgeMetadata describes a column-lineage edge: its derivation and confidence, and
+// the file it was derived from as its origin, by which incremental runs replace it.
+func columnEdgeMetadata(ref parser.ColumnReference, sourceID uuid.UUID, path string) []byte {
+	metadata := map[string]interface{}{
+		"derivation_type": ref.DerivationType,
+		"confidence":      derivationConfidence(ref.DerivationType),
+		"origins":         []string{columnEdgeOrigin(sourceID, path)},
+	}
+	if ref.Expression != "" {
+		metadata["expression"] = ref.Expression
+	}
+	metaJSON, _ := json.Marshal(metadata)
+	return metaJSON
+}
+
+// columnEdgeOrigin names a file a column-lineage edge was derived from, "<source_id>:<path>".
+func columnEdgeOrigin(sourceID uuid.UUID, path string) string {
+	return sourceID.String() + ":" + path
+}
+
 func resolveColumnID(name string, colMap, allMap map[string]uuid.UUID) uuid.UUID {
 	lower := strings.ToLower(name)
 
@@ -0,0 +1,164 @@
+//go:build integration
+
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func setupStore(t *testing.T) *store.Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("postgres not available: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("postgres ping failed: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return store.New(pool)
+}
+
+// An edge derived by two files survives an incremental run in which one of them stops
+// deriving it, and goes once neither does.
+func TestReplaceColumnLineage_SharedEdge(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Test Column Lineage",
+		Slug: fmt.Sprintf("test-lineage-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM symbol_edges WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM symbols WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM files WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM sources WHERE project_id = $1", proj.ID)
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "test-source", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	file, err := s.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: proj.ID, SourceID: source.ID,
+		Path: "db/schema.sql", Language: "tsql", SizeBytes: 100, Hash: "schema",
+	})
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	column := func(qualified string) postgres.Symbol {
+		sym, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: file.ID, Name: "Name", QualifiedName: qualified,
+			Kind: "column", Language: "tsql", StartLine: 1, EndLine: 1,
+		})
+		if err != nil {
+			t.Fatalf("create column: %v", err)
+		}
+		return sym
+	}
+	from, to := column("dbo.Customers.Name"), column("dbo.CustomersCopy.Name")
+
+	copyRef := []parser.ColumnReference{{
+		SourceColumn: "dbo.Customers.Name", TargetColumn: "dbo.CustomersCopy.Name", DerivationType: "direct_copy",
+	}}
+	e := NewEngine(s, nil, slog.Default())
+
+	origins := func() []string {
+		t.Helper()
+		edges, err := s.ListColumnEdgesByProject(ctx, proj.ID)
+		if err != nil {
+			t.Fatalf("list column edges: %v", err)
+		}
+		if len(edges) == 0 {
+			return nil
+		}
+		if len(edges) != 1 || edges[0].SourceID != from.ID || edges[0].TargetID != to.ID {
+			t.Fatalf("expected the one copy edge, got %+v", edges)
+		}
+		var meta struct {
+			Origins []string `json:"origins"`
+		}
+		if err := json.Unmarshal(edges[0].Metadata, &meta); err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(meta.Origins)
+		return meta.Origins
+	}
+	originOf := func(path string) string { return source.ID.String() + ":" + path }
+
+	// Full run: both files derive the edge
+	if _, err := e.RebuildColumnLineage(ctx, proj.ID, source.ID, []FileColumnRefs{
+		{Path: "a.sql", Refs: copyRef},
+		{Path: "b.sql", Refs: copyRef},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := origins(), []string{originOf("a.sql"), originOf("b.sql")}; !slices.Equal(got, want) {
+		t.Fatalf("after full run: origins %v, want %v", got, want)
+	}
+
+	// b.sql changes and no longer derives the edge; a.sql, unchanged, still does
+	if _, err := e.ReplaceColumnLineage(ctx, proj.ID, source.ID, []string{"b.sql"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := origins(), []string{originOf("a.sql")}; !slices.Equal(got, want) {
+		t.Fatalf("after b.sql changed: origins %v, want %v", got, want)
+	}
+
+	// a.sql is deleted: nothing derives the edge any more
+	if _, err := e.ReplaceColumnLineage(ctx, proj.ID, source.ID, []string{"a.sql"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := origins(); got != nil {
+		t.Fatalf("after a.sql deleted: expected no edge, got origins %v", got)
+	}
+
+	// An edge from before edges listed their origins is left by incremental runs and
+	// replaced by a full one
+	if _, err := s.CreateSymbolEdgeWithMetadata(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+		ProjectID: proj.ID, SourceID: from.ID, TargetID: to.ID, EdgeType: "direct_copy",
+		Metadata: []byte(`{"derivation_type": "direct_copy"}`),
+	}); err != nil {
+		t.Fatalf("create legacy edge: %v", err)
+	}
+	if _, err := e.ReplaceColumnLineage(ctx, proj.ID, source.ID, []string{"a.sql"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := origins(); len(got) != 0 {
+		t.Fatalf("legacy edge: expected no origins, got %v", got)
+	}
+	if _, err := e.RebuildColumnLineage(ctx, proj.ID, source.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	edges, err := s.ListColumnEdgesByProject(ctx, proj.ID)
+	if err != nil {
+		t.Fatalf("list column edges: %v", err)
+	}
+	if len(edges) != 0 {
+		t.Errorf("expected the legacy edge dropped by the full run, got %+v", edges)
+	}
+}
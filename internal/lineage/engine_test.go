@@ -0,0 +1,113 @@
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestColumnEdgeMetadata_RecordsOrigin(t *testing.T) {
+	sourceID := uuid.New()
+	ref := parser.ColumnReference{
+		SourceColumn:   "dbo.Customers.Name",
+		TargetColumn:   "dbo.CustomersCopy.Name",
+		DerivationType: "transform",
+		Expression:     "UPPER(Name)",
+	}
+
+	var meta map[string]any
+	if err := json.Unmarshal(columnEdgeMetadata(ref, sourceID, "db/customers.sql"), &meta); err != nil {
+		t.Fatal(err)
+	}
+	// DeleteColumnEdgesByOrigins and UpsertColumnEdge match on the origins list.
+	origins, _ := meta["origins"].([]any)
+	if len(origins) != 1 || origins[0] != sourceID.String()+":db/customers.sql" {
+		t.Errorf("expected the source and file recorded as the origin, got %v", meta)
+	}
+	if meta["derivation_type"] != "transform" || meta["expression"] != "UPPER(Name)" || meta["confidence"] != 1.0 {
+		t.Errorf("unexpected derivation metadata: %v", meta)
+	}
+}
+
+type fakeEdgeGraph []postgres.SymbolEdge
+
+func (g fakeEdgeGraph) GetOutgoingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	var out []postgres.SymbolEdge
+	for _, e := range g {
+		if e.SourceID == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (g fakeEdgeGraph) GetIncomingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	var out []postgres.SymbolEdge
+	for _, e := range g {
+		if e.TargetID == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestEdgeDirections_ReadsAreUpstreamWritesDownstream(t *testing.T) {
+	proc, caller, callee := uuid.New(), uuid.New(), uuid.New()
+	customers, orders := uuid.New(), uuid.New()
+	g := fakeEdgeGraph{
+		{SourceID: proc, TargetID: customers, EdgeType: "reads_from"},
+		{SourceID: proc, TargetID: orders, EdgeType: "writes_to"},
+		{SourceID: caller, TargetID: proc, EdgeType: "calls"},
+		{SourceID: proc, TargetID: callee, EdgeType: "calls"},
+	}
+	ids := func(hops []Hop) map[uuid.UUID]bool {
+		out := make(map[uuid.UUID]bool)
+		for _, h := range hops {
+			out[h.ID] = true
+		}
+		return out
+	}
+	d := DefaultEdgeDirections()
+
+	up, err := d.Neighbors(context.Background(), g, proc, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(up); !got[customers] || got[orders] || !got[caller] || got[callee] || len(got) != 2 {
+		t.Errorf("upstream of the proc should be the table it reads and its caller, got %v", up)
+	}
+
+	down, err := d.Neighbors(context.Background(), g, proc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(down); !got[orders] || got[customers] || !got[callee] || got[caller] || len(got) != 2 {
+		t.Errorf("downstream of the proc should be the table it writes and its callee, got %v", down)
+	}
+
+	// From the tables' side the same edges point back at the proc.
+	if hops, _ := d.Neighbors(context.Background(), g, customers, false); len(hops) != 1 || hops[0].ID != proc || hops[0].Outward {
+		t.Errorf("the proc should be downstream of the table it reads, got %v", hops)
+	}
+	if hops, _ := d.Neighbors(context.Background(), g, orders, true); len(hops) != 1 || hops[0].ID != proc {
+		t.Errorf("the proc should be upstream of the table it writes, got %v", hops)
+	}
+}
+
+func TestNewEdgeDirections_AppliesOverrides(t *testing.T) {
+	d, err := NewEdgeDirections(map[string]string{"uses_table": "upstream", "reads_from": "downstream"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Of("uses_table") != Upstream || d.Of("reads_from") != Downstream || d.Of("writes_to") != Downstream || d.Of("inherits") != Downstream {
+		t.Errorf("unexpected directions: %v", d)
+	}
+	if _, err := NewEdgeDirections(map[string]string{"calls": "sideways"}); err == nil {
+		t.Error("expected an error for an unknown direction")
+	}
+}
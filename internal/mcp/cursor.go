@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrCursorMismatch is returned for a cursor issued for another query or filters.
+var ErrCursorMismatch = errors.New("cursor was issued for a different query; start again without it")
+
+// PageCursor marks the last result of a page in a stable order: score descending, ties
+// broken by symbol ID ascending. The next page holds the results after it.
+type PageCursor struct {
+	Score float64
+	ID    uuid.UUID
+}
+
+// cursorJSON is the encoded form of a cursor, bound to the query it pages through.
+type cursorJSON struct {
+	Key   string    `json:"k"`
+	Score float64   `json:"s"`
+	ID    uuid.UUID `json:"i"`
+}
+
+// CursorKey identifies the query a cursor pages through, from the tool name and every
+// parameter that changes its results, so that a cursor cannot be replayed against
+// another query.
+func CursorKey(tool string, parts ...string) string {
+	sum := sha256.Sum256([]byte(tool + "\x00" + strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CursorBefore reports whether a result scored a with ID aID comes before one scored b
+// with ID bID in cursor order.
+func CursorBefore(a float64, aID uuid.UUID, b float64, bID uuid.UUID) bool {
+	if a != b {
+		return a > b
+	}
+	return strings.Compare(aID.String(), bID.String()) < 0
+}
+
+// After reports whether a result scored score with ID id comes after the cursor.
+func (c PageCursor) After(score float64, id uuid.UUID) bool {
+	return CursorBefore(c.Score, c.ID, score, id)
+}
+
+// Encode returns the cursor as an opaque string bound to key (see CursorKey).
+func (c PageCursor) Encode(key string) string {
+	b, _ := json.Marshal(cursorJSON{Key: key, Score: c.Score, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor from Encode, failing with ErrCursorMismatch when it was
+// issued for a query other than key.
+func DecodeCursor(s, key string) (PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return PageCursor{}, errors.New("malformed cursor")
+	}
+	var c cursorJSON
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+		return PageCursor{}, errors.New("malformed cursor")
+	}
+	if c.Key != key {
+		return PageCursor{}, ErrCursorMismatch
+	}
+	return PageCursor{Score: c.Score, ID: c.ID}, nil
+}
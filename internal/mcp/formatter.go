@@ -1,8 +1,14 @@
 package mcp
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/maraichr/lattice/internal/mcp/session"
 	"github.com/maraichr/lattice/internal/store/postgres"
@@ -38,6 +44,20 @@ type ResponseBuilder struct {
 	maxTokens     int
 	truncated     bool
 	itemCount     int
+	owners        map[uuid.UUID]Owner
+	nextPage      *NavigationStep
+}
+
+// Owner is the author and date of the last commit touching the file that defines a
+// symbol, as recorded for git sources with track_ownership.
+type Owner struct {
+	Author      string
+	CommittedAt time.Time
+}
+
+// String renders the owner as "alice, 2026-09-30".
+func (o Owner) String() string {
+	return fmt.Sprintf("%s, %s", o.Author, o.CommittedAt.Format(time.DateOnly))
 }
 
 // NewResponseBuilder creates a builder with the given token budget.
@@ -69,10 +89,33 @@ func (rb *ResponseBuilder) AddLine(text string) bool {
 	return true
 }
 
+// SetOwners attaches last-commit ownership to the symbol cards added afterwards;
+// symbols without an entry render as before.
+func (rb *ResponseBuilder) SetOwners(owners map[uuid.UUID]Owner) {
+	rb.owners = owners
+}
+
+// SetNextCursor makes the navigation hints lead with the call fetching the next page:
+// next with cursor added to its parameters. The cursor is shown as next_cursor.
+func (rb *ResponseBuilder) SetNextCursor(cursor string, next NavigationStep) {
+	params := make(map[string]string, len(next.Params)+1)
+	for k, v := range next.Params {
+		params[k] = v
+	}
+	params["cursor"] = cursor
+	next.Params = params
+	next.Description = fmt.Sprintf("Next page (next_cursor: `%s`)", cursor)
+	rb.nextPage = &next
+}
+
 // AddSymbolCard renders a symbol at the requested verbosity.
 // Returns false if the card would exceed the token budget.
 func (rb *ResponseBuilder) AddSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Session) bool {
-	card := formatSymbolCard(sym, verbosity, sess)
+	var owner *Owner
+	if o, ok := rb.owners[sym.ID]; ok {
+		owner = &o
+	}
+	card := formatSymbolCard(sym, verbosity, sess, owner)
 	cost := len(card) / 4
 	if rb.tokenEstimate+cost > rb.maxTokens {
 		rb.truncated = true
@@ -142,10 +185,17 @@ func (rb *ResponseBuilder) FinalizeWithHints(totalCount, returnedCount int, hint
 			returnedCount, totalCount, rb.tokenEstimate))
 	}
 
-	if hints != nil && len(hints.Steps) > 0 {
+	var steps []NavigationStep
+	if rb.nextPage != nil {
+		steps = append(steps, *rb.nextPage)
+	}
+	if hints != nil {
+		steps = append(steps, hints.Steps...)
+	}
+	if len(steps) > 0 {
 		rb.buf.WriteString("\n---\n**Next steps:**\n")
-		for _, step := range hints.Steps {
-			rb.buf.WriteString(fmt.Sprintf("- %s → `%s`", step.Description, step.Tool))
+		for _, step := range steps {
+			rb.buf.WriteString(fmt.Sprintf("- %s → `%s`", step.Description, formatStepCall(step)))
 			if step.EstimatedTokens > 0 {
 				rb.buf.WriteString(fmt.Sprintf(" (~%d tokens)", step.EstimatedTokens))
 			}
@@ -156,6 +206,35 @@ func (rb *ResponseBuilder) FinalizeWithHints(totalCount, returnedCount int, hint
 	return rb.buf.String()
 }
 
+// formatStepCall renders a hint as a ready-to-call tool invocation, e.g.
+// get_lineage(direction="upstream", symbol_name="dbo.Orders").
+func formatStepCall(step NavigationStep) string {
+	if len(step.Params) == 0 && len(step.ListParams) == 0 {
+		return step.Tool
+	}
+	keys := make([]string, 0, len(step.Params)+len(step.ListParams))
+	for k := range step.Params {
+		keys = append(keys, k)
+	}
+	for k := range step.ListParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]string, len(keys))
+	for i, k := range keys {
+		if list, ok := step.ListParams[k]; ok {
+			quoted := make([]string, len(list))
+			for j, v := range list {
+				quoted[j] = fmt.Sprintf("%q", v)
+			}
+			args[i] = fmt.Sprintf("%s=[%s]", k, strings.Join(quoted, ", "))
+			continue
+		}
+		args[i] = fmt.Sprintf("%s=%q", k, step.Params[k])
+	}
+	return step.Tool + "(" + strings.Join(args, ", ") + ")"
+}
+
 // TokenEstimate returns the current estimated token count.
 func (rb *ResponseBuilder) TokenEstimate() int {
 	return rb.tokenEstimate
@@ -192,8 +271,9 @@ func FormatDryRun(result DryRunResult) string {
 	return b.String()
 }
 
-// formatSymbolCard renders a symbol as a Markdown card at the given verbosity.
-func formatSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Session) string {
+// formatSymbolCard renders a symbol as a Markdown card at the given verbosity, with an
+// Owner line when owner is non-nil.
+func formatSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Session, owner *Owner) string {
 	var b strings.Builder
 
 	// Check if already seen
@@ -202,10 +282,16 @@ func formatSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Se
 		seen = " *(seen)*"
 	}
 
+	ownerLine := ""
+	if owner != nil {
+		ownerLine = fmt.Sprintf("  Owner: %s (last commit)\n", owner)
+	}
+
 	switch verbosity {
 	case VerbositySummary:
 		b.WriteString(fmt.Sprintf("**%s** (%s)%s\n", sym.Name, sym.Kind, seen))
 		b.WriteString(fmt.Sprintf("  FQN: `%s`\n", sym.QualifiedName))
+		b.WriteString(ownerLine)
 		b.WriteString(fmt.Sprintf("  ID: `%s`\n\n", sym.ID))
 
 	case VerbosityFull:
@@ -219,6 +305,9 @@ func formatSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Se
 		if sym.DocComment != nil {
 			b.WriteString(fmt.Sprintf("  Doc: %s\n", *sym.DocComment))
 		}
+		b.WriteString(metricsLine(sym))
+		b.WriteString(tagsLine(sym))
+		b.WriteString(ownerLine)
 		b.WriteString(fmt.Sprintf("  ID: `%s`\n\n", sym.ID))
 
 	default: // standard
@@ -228,8 +317,61 @@ func formatSymbolCard(sym postgres.Symbol, verbosity Verbosity, sess *session.Se
 		if sym.Signature != nil {
 			b.WriteString(fmt.Sprintf("  Signature: `%s`\n", *sym.Signature))
 		}
+		b.WriteString(tagsLine(sym))
+		b.WriteString(ownerLine)
 		b.WriteString(fmt.Sprintf("  ID: `%s`\n\n", sym.ID))
 	}
 
 	return b.String()
 }
+
+// metricsLine renders the size and coupling measures analytics stored on a function or
+// procedure, or "" for symbols that were not measured.
+func metricsLine(sym postgres.Symbol) string {
+	if len(sym.Metadata) == 0 {
+		return ""
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(sym.Metadata, &meta); err != nil {
+		return ""
+	}
+	lines, ok := meta["line_count"].(float64)
+	if !ok {
+		return ""
+	}
+	fanIn, _ := meta["fan_in"].(float64)
+	fanOut, _ := meta["fan_out"].(float64)
+	line := fmt.Sprintf("  Metrics: %d lines | fan-in %d | fan-out %d", int(lines), int(fanIn), int(fanOut))
+	if c, ok := meta["complexity"].(float64); ok && c > 0 {
+		line += fmt.Sprintf(" | complexity %d", int(c))
+	}
+	if score, ok := meta["complexity_score"].(float64); ok {
+		line += fmt.Sprintf(" | score %.2f", score)
+	}
+	return line + "\n"
+}
+
+// tagsLine renders the symbol's curated metadata tags followed by the ones analytics
+// applied automatically (auto_tags, e.g. pii), or "" for untagged symbols.
+func tagsLine(sym postgres.Symbol) string {
+	if len(sym.Metadata) == 0 {
+		return ""
+	}
+	var meta struct {
+		Tags     []string `json:"tags"`
+		AutoTags []string `json:"auto_tags"`
+	}
+	if json.Unmarshal(sym.Metadata, &meta) != nil {
+		return ""
+	}
+	var tags []string
+	for _, tag := range append(meta.Tags, meta.AutoTags...) {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Tags: %s\n", strings.Join(tags, ", "))
+}
@@ -161,6 +161,61 @@ func TestResponseBuilder_AddSymbolCard_Full(t *testing.T) {
 	}
 }
 
+func TestResponseBuilder_AddSymbolCard_FullMetrics(t *testing.T) {
+	rb := NewResponseBuilder(2000)
+	measured := testSymbol("Reconcile", "function", "billing.Reconcile", "go")
+	measured.Metadata = []byte(`{"line_count": 240, "fan_in": 18, "fan_out": 25, "complexity": 31, "complexity_score": 9.5}`)
+	unmeasured := testSymbol("Orders", "table", "dbo.Orders", "tsql")
+	unmeasured.Metadata = []byte(`{"in_degree": 4}`)
+
+	rb.AddSymbolCard(measured, VerbosityFull, nil)
+	rb.AddSymbolCard(unmeasured, VerbosityFull, nil)
+	result := rb.Finalize(2, 2)
+	if !strings.Contains(result, "Metrics: 240 lines | fan-in 18 | fan-out 25 | complexity 31 | score 9.50") {
+		t.Errorf("full card should carry the symbol's metrics, got:\n%s", result)
+	}
+	if strings.Count(result, "Metrics:") != 1 {
+		t.Errorf("symbols without metrics should have no metrics line, got:\n%s", result)
+	}
+}
+
+func TestResponseBuilder_AddSymbolCard_Tags(t *testing.T) {
+	rb := NewResponseBuilder(2000)
+	tagged := testSymbol("email", "column", "dbo.Customers.email", "tsql")
+	tagged.Metadata = []byte(`{"tags": ["contact"], "auto_tags": ["pii"]}`)
+	cleared := testSymbol("status", "column", "dbo.Customers.status", "tsql")
+	cleared.Metadata = []byte(`{"auto_tags": []}`)
+
+	rb.AddSymbolCard(tagged, VerbosityStandard, nil)
+	rb.AddSymbolCard(cleared, VerbosityStandard, nil)
+	result := rb.Finalize(2, 2)
+	if !strings.Contains(result, "Tags: contact, pii") {
+		t.Errorf("tagged symbol should carry its curated and automatic tags, got:\n%s", result)
+	}
+	if strings.Count(result, "Tags:") != 1 {
+		t.Errorf("symbols without tags should have no tags line, got:\n%s", result)
+	}
+}
+
+func TestResponseBuilder_AddSymbolCard_Owner(t *testing.T) {
+	rb := NewResponseBuilder(2000)
+	owned := testSymbol("Orders", "table", "dbo.Orders", "tsql")
+	unowned := testSymbol("Customers", "table", "dbo.Customers", "tsql")
+	rb.SetOwners(map[uuid.UUID]Owner{
+		owned.ID: {Author: "Dana Reyes", CommittedAt: time.Date(2026, 9, 30, 14, 0, 0, 0, time.UTC)},
+	})
+
+	rb.AddSymbolCard(owned, VerbosityStandard, nil)
+	rb.AddSymbolCard(unowned, VerbosityStandard, nil)
+	result := rb.Finalize(2, 2)
+	if !strings.Contains(result, "Owner: Dana Reyes, 2026-09-30 (last commit)") {
+		t.Errorf("owned symbol should carry its owner, got:\n%s", result)
+	}
+	if strings.Count(result, "Owner:") != 1 {
+		t.Errorf("symbols without ownership data should have no owner line, got:\n%s", result)
+	}
+}
+
 func TestResponseBuilder_AddSymbolCard_SeenMarker(t *testing.T) {
 	rb := NewResponseBuilder(2000)
 	sym := testSymbol("Foo", "class", "app.Foo", "go")
@@ -297,3 +352,45 @@ func TestResponseBuilder_ManyCards_RespectsBudget(t *testing.T) {
 		t.Errorf("token estimate %d should not exceed budget 500", rb.TokenEstimate())
 	}
 }
+
+func TestResponseBuilder_FinalizeWithHints_RendersParams(t *testing.T) {
+	rb := NewResponseBuilder(2000)
+	rb.AddLine("result")
+	hints := &NavigationHints{
+		Steps: []NavigationStep{{
+			Tool:        "get_lineage",
+			Description: "Trace upstream lineage of dbo.Orders",
+			Params:      map[string]string{"symbol_name": "dbo.Orders", "direction": "upstream"},
+		}},
+	}
+	result := rb.FinalizeWithHints(1, 1, hints)
+	if !strings.Contains(result, `get_lineage(direction="upstream", symbol_name="dbo.Orders")`) {
+		t.Errorf("hint should render a ready-to-call invocation, got:\n%s", result)
+	}
+}
+
+func TestResponseBuilder_SetNextCursor_LeadsHints(t *testing.T) {
+	rb := NewResponseBuilder(2000)
+	rb.AddLine("result")
+	rb.SetNextCursor("abc", NavigationStep{Tool: "search_symbols", Params: map[string]string{"query": "order"}})
+	hints := &NavigationHints{Steps: []NavigationStep{{Tool: "get_dependencies", Description: "Explore deps"}}}
+	result := rb.FinalizeWithHints(40, 20, hints)
+	if !strings.Contains(result, "next_cursor: `abc`") {
+		t.Errorf("expected next_cursor in the hints, got:\n%s", result)
+	}
+	next := strings.Index(result, `search_symbols(cursor="abc", query="order")`)
+	if next < 0 || next > strings.Index(result, "get_dependencies") {
+		t.Errorf("expected the next-page call first among the hints, got:\n%s", result)
+	}
+}
+
+func TestFormatStepCall_ListParams(t *testing.T) {
+	step := NavigationStep{
+		Tool:       "search_symbols",
+		Params:     map[string]string{"query": "order"},
+		ListParams: map[string][]string{"kinds": {"table", "view"}},
+	}
+	if got, want := formatStepCall(step), `search_symbols(kinds=["table", "view"], query="order")`; got != want {
+		t.Errorf("formatStepCall = %s, want %s", got, want)
+	}
+}
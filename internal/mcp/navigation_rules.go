@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// NavigationRule maps the results of a tool call to suggested next steps. Rules are
+// evaluated in order; the steps of every matching rule are collected until one with
+// Stop set matches. Deployments load their own rules from JSON (see LoadNavigationRules)
+// to promote the follow-up tools their agents should reach for.
+type NavigationRule struct {
+	Tool  string         `json:"tool"` // tool whose results the rule applies to; "*" matches any
+	When  RuleCondition  `json:"when"`
+	Steps []StepTemplate `json:"steps"`
+	Stop  bool           `json:"stop,omitempty"` // later rules are not evaluated when this one matches
+
+	// generate computes the steps of a built-in rule in place of Steps.
+	generate func(symbols []postgres.Symbol) []NavigationStep
+}
+
+// RuleCondition describes the result sets a rule applies to. A kind is a symbol kind
+// (table, method, ...) or a category: data, code or container. Empty fields match anything.
+type RuleCondition struct {
+	TopKind    []string `json:"top_kind,omitempty"`    // the first result is one of these kinds
+	AnyKind    []string `json:"any_kind,omitempty"`    // some result is one of these kinds
+	MinResults int      `json:"min_results,omitempty"` // at least this many results
+	MaxResults int      `json:"max_results,omitempty"` // at most this many results (0 = no limit)
+}
+
+// StepTemplate is a suggested tool call whose description and params may use the
+// placeholders {id}, {name}, {qualified_name} and {kind} of the target symbol, and
+// {count}, the number of results.
+type StepTemplate struct {
+	Tool            string            `json:"tool"`
+	Description     string            `json:"description"`
+	Params          map[string]string `json:"params,omitempty"`
+	EstimatedTokens int               `json:"estimated_tokens,omitempty"`
+	Target          string            `json:"target,omitempty"` // top (default) or most_connected
+}
+
+// DefaultNavigationRules returns the built-in rule set: one rule per exploration tool,
+// each ending evaluation, and a fallback for every other tool suggesting a closer look at
+// the top result.
+func DefaultNavigationRules() []NavigationRule {
+	builtin := func(tool string, generate func([]postgres.Symbol) []NavigationStep) NavigationRule {
+		return NavigationRule{Tool: tool, Stop: true, generate: generate}
+	}
+	return []NavigationRule{
+		builtin("search_symbols", hintsAfterSearch),
+		builtin("get_symbol_details", hintsAfterDetails),
+		builtin("get_dependencies", hintsAfterDependencies),
+		builtin("trace_lineage", hintsAfterLineage),
+		builtin("list_project_overview", hintsAfterOverview),
+		builtin("find_usages", hintsAfterUsages),
+		builtin("analyze_impact", hintsAfterImpact),
+		builtin("extract_subgraph", hintsAfterSubgraph),
+		builtin("*", defaultHints),
+	}
+}
+
+// LoadNavigationRules reads a JSON array of rules from path and returns them ahead of
+// the default rules, so their steps come first and a rule with stop set replaces the
+// defaults for its tool. An empty path yields the defaults alone.
+func LoadNavigationRules(path string) ([]NavigationRule, error) {
+	if path == "" {
+		return DefaultNavigationRules(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read navigation rules: %w", err)
+	}
+	rules, err := ParseNavigationRules(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, DefaultNavigationRules()...), nil
+}
+
+// ParseNavigationRules decodes and validates a JSON array of rules.
+func ParseNavigationRules(data []byte) ([]NavigationRule, error) {
+	var rules []NavigationRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse navigation rules: %w", err)
+	}
+	for i, r := range rules {
+		if r.Tool == "" {
+			return nil, fmt.Errorf("navigation rule %d: tool is required", i)
+		}
+		if len(r.Steps) == 0 && !r.Stop {
+			return nil, fmt.Errorf("navigation rule %d: steps are required unless the rule stops evaluation", i)
+		}
+		for _, st := range r.Steps {
+			if st.Tool == "" {
+				return nil, fmt.Errorf("navigation rule %d: every step needs a tool", i)
+			}
+			switch st.Target {
+			case "", "top", "most_connected":
+			default:
+				return nil, fmt.Errorf("navigation rule %d: target must be top or most_connected, got %q", i, st.Target)
+			}
+		}
+	}
+	return rules, nil
+}
+
+func (r NavigationRule) matches(toolName string, symbols []postgres.Symbol) bool {
+	if r.Tool != "*" && r.Tool != toolName {
+		return false
+	}
+	if len(symbols) < r.When.MinResults || (r.When.MaxResults > 0 && len(symbols) > r.When.MaxResults) {
+		return false
+	}
+	if len(r.When.TopKind) > 0 && !kindMatches(symbols[0].Kind, r.When.TopKind) {
+		return false
+	}
+	if len(r.When.AnyKind) > 0 && !slices.ContainsFunc(symbols, func(sym postgres.Symbol) bool {
+		return kindMatches(sym.Kind, r.When.AnyKind)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (r NavigationRule) suggest(symbols []postgres.Symbol) []NavigationStep {
+	if r.generate != nil {
+		return r.generate(symbols)
+	}
+	steps := make([]NavigationStep, 0, len(r.Steps))
+	for _, st := range r.Steps {
+		target := symbols[0]
+		if st.Target == "most_connected" {
+			target, _ = mostConnected(symbols)
+		}
+		fill := strings.NewReplacer(
+			"{id}", target.ID.String(),
+			"{name}", target.Name,
+			"{qualified_name}", target.QualifiedName,
+			"{kind}", target.Kind,
+			"{count}", strconv.Itoa(len(symbols)),
+		).Replace
+		step := NavigationStep{
+			Tool:            st.Tool,
+			Description:     fill(st.Description),
+			EstimatedTokens: st.EstimatedTokens,
+		}
+		if len(st.Params) > 0 {
+			step.Params = make(map[string]string, len(st.Params))
+			for k, v := range st.Params {
+				step.Params[k] = fill(v)
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// kindMatches reports whether a symbol kind is among kinds, by name or category.
+func kindMatches(kind string, kinds []string) bool {
+	category := map[symbolKindCategory]string{
+		categoryData:      "data",
+		categoryCode:      "code",
+		categoryContainer: "container",
+	}[classifyKind(kind)]
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) || (category != "" && k == category) {
+			return true
+		}
+	}
+	return false
+}
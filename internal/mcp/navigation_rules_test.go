@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestCustomNavigationRule(t *testing.T) {
+	rules, err := ParseNavigationRules([]byte(`[
+		{
+			"tool": "search_symbols",
+			"when": {"top_kind": ["data"], "min_results": 2},
+			"steps": [{
+				"tool": "schema_diff",
+				"description": "Check recent schema changes to {qualified_name} ({count} matches)",
+				"params": {"symbol_id": "{id}", "kind": "{kind}"},
+				"estimated_tokens": 500
+			}],
+			"stop": true
+		},
+		{
+			"tool": "*",
+			"when": {"any_kind": ["procedure"]},
+			"steps": [{"tool": "call_tree", "description": "Walk the call tree of {name}", "target": "most_connected"}]
+		}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nav := NewNavigator(nil)
+	nav.SetRules(append(rules, DefaultNavigationRules()...))
+
+	orders := makeSymbol("Orders", "table", "dbo.Orders")
+	syms := []postgres.Symbol{orders, makeSymbol("OrderLines", "table", "dbo.OrderLines")}
+	hints := nav.SuggestNextSteps("search_symbols", syms, nil)
+	if hints == nil || len(hints.Steps) != 1 {
+		t.Fatalf("expected the custom rule to replace the search hints, got %+v", hints)
+	}
+	step := hints.Steps[0]
+	if step.Tool != "schema_diff" || step.Description != "Check recent schema changes to dbo.Orders (2 matches)" {
+		t.Errorf("unexpected step %+v", step)
+	}
+	if step.Params["symbol_id"] != orders.ID.String() || step.Params["kind"] != "table" || step.EstimatedTokens != 500 {
+		t.Errorf("unexpected params %+v", step)
+	}
+
+	// A single table does not match the first rule: the defaults apply.
+	hints = nav.SuggestNextSteps("search_symbols", syms[:1], nil)
+	if hints == nil || hints.Steps[0].Tool != "get_symbol_details" {
+		t.Errorf("expected the default search hints, got %+v", hints)
+	}
+
+	// A rule without stop adds its steps ahead of the defaults.
+	proc := makeSymbol("usp_Load", "procedure", "dbo.usp_Load")
+	hints = nav.SuggestNextSteps("find_usages", []postgres.Symbol{orders, proc}, nil)
+	if hints == nil || len(hints.Steps) != 3 || hints.Steps[0].Tool != "call_tree" || hints.Steps[1].Tool != "get_symbol_details" {
+		t.Errorf("expected call_tree followed by the usage hints, got %+v", hints)
+	}
+}
+
+func TestParseNavigationRulesRejectsInvalid(t *testing.T) {
+	for _, data := range []string{
+		`[{"steps": [{"tool": "find_usages"}]}]`,
+		`[{"tool": "search_symbols"}]`,
+		`[{"tool": "search_symbols", "steps": [{"description": "no tool"}]}]`,
+		`[{"tool": "search_symbols", "steps": [{"tool": "find_usages", "target": "last"}]}]`,
+	} {
+		if _, err := ParseNavigationRules([]byte(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/maraichr/lattice/internal/mcp/session"
@@ -12,22 +13,31 @@ type NavigationHints struct {
 	Steps []NavigationStep `json:"steps"`
 }
 
-// NavigationStep is a suggested next MCP tool call.
+// NavigationStep is a suggested next MCP tool call. ListParams holds the parameters
+// taking a list of strings.
 type NavigationStep struct {
-	Tool            string            `json:"tool"`
-	Description     string            `json:"description"`
-	Params          map[string]string `json:"params,omitempty"`
-	EstimatedTokens int               `json:"estimated_tokens,omitempty"`
+	Tool            string              `json:"tool"`
+	Description     string              `json:"description"`
+	Params          map[string]string   `json:"params,omitempty"`
+	ListParams      map[string][]string `json:"list_params,omitempty"`
+	EstimatedTokens int                 `json:"estimated_tokens,omitempty"`
 }
 
-// Navigator generates context-aware navigation hints for MCP tool responses.
+// Navigator generates context-aware navigation hints for MCP tool responses by
+// evaluating a rule set, DefaultNavigationRules unless replaced with SetRules.
 type Navigator struct {
 	store *postgres.Queries
+	rules []NavigationRule
 }
 
 // NewNavigator creates a navigator with access to the store for edge counting.
 func NewNavigator(store *postgres.Queries) *Navigator {
-	return &Navigator{store: store}
+	return &Navigator{store: store, rules: DefaultNavigationRules()}
+}
+
+// SetRules replaces the navigator's rule set, such as one from LoadNavigationRules.
+func (n *Navigator) SetRules(rules []NavigationRule) {
+	n.rules = rules
 }
 
 // symbolKindCategory classifies symbol kinds for navigation routing.
@@ -54,31 +64,22 @@ func classifyKind(kind string) symbolKindCategory {
 }
 
 // SuggestNextSteps returns navigation hints based on the tool that was just called
-// and the symbols it returned.
+// and the symbols it returned: the steps of each matching rule in order, until one
+// that stops evaluation.
 func (n *Navigator) SuggestNextSteps(toolName string, symbols []postgres.Symbol, sess *session.Session) *NavigationHints {
 	if len(symbols) == 0 {
 		return nil
 	}
 
 	hints := &NavigationHints{}
-
-	switch toolName {
-	case "search_symbols":
-		hints.Steps = n.hintsAfterSearch(symbols)
-	case "get_symbol_details":
-		hints.Steps = n.hintsAfterDetails(symbols)
-	case "get_dependencies":
-		hints.Steps = n.hintsAfterDependencies(symbols)
-	case "trace_lineage":
-		hints.Steps = n.hintsAfterLineage(symbols)
-	case "list_project_overview":
-		hints.Steps = n.hintsAfterOverview(symbols)
-	case "find_usages":
-		hints.Steps = n.hintsAfterUsages(symbols)
-	case "analyze_impact":
-		hints.Steps = n.hintsAfterImpact(symbols)
-	default:
-		hints.Steps = n.defaultHints(symbols)
+	for _, rule := range n.rules {
+		if !rule.matches(toolName, symbols) {
+			continue
+		}
+		hints.Steps = append(hints.Steps, rule.suggest(symbols)...)
+		if rule.Stop {
+			break
+		}
 	}
 
 	// Limit to top 3 hints
@@ -89,7 +90,7 @@ func (n *Navigator) SuggestNextSteps(toolName string, symbols []postgres.Symbol,
 	return hints
 }
 
-func (n *Navigator) hintsAfterSearch(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterSearch(symbols []postgres.Symbol) []NavigationStep {
 	steps := make([]NavigationStep, 0, 3)
 
 	if len(symbols) > 0 {
@@ -119,6 +120,11 @@ func (n *Navigator) hintsAfterSearch(symbols []postgres.Symbol) []NavigationStep
 		}
 	}
 
+	// A better-connected symbol elsewhere in the results deserves its own relationship query.
+	if rel, ok := relationshipHint(symbols); ok && rel.Params["symbol_id"] != symbols[0].ID.String() {
+		steps = append(steps, rel)
+	}
+
 	if len(symbols) > 3 {
 		steps = append(steps, NavigationStep{
 			Tool:            "extract_subgraph",
@@ -130,7 +136,7 @@ func (n *Navigator) hintsAfterSearch(symbols []postgres.Symbol) []NavigationStep
 	return steps
 }
 
-func (n *Navigator) hintsAfterDetails(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterDetails(symbols []postgres.Symbol) []NavigationStep {
 	if len(symbols) == 0 {
 		return nil
 	}
@@ -170,7 +176,7 @@ func (n *Navigator) hintsAfterDetails(symbols []postgres.Symbol) []NavigationSte
 	return steps
 }
 
-func (n *Navigator) hintsAfterDependencies(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterDependencies(symbols []postgres.Symbol) []NavigationStep {
 	steps := make([]NavigationStep, 0, 3)
 
 	// Find unexplored high-value symbols
@@ -197,7 +203,7 @@ func (n *Navigator) hintsAfterDependencies(symbols []postgres.Symbol) []Navigati
 	return steps
 }
 
-func (n *Navigator) hintsAfterLineage(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterLineage(symbols []postgres.Symbol) []NavigationStep {
 	steps := make([]NavigationStep, 0, 3)
 
 	for _, sym := range symbols {
@@ -212,16 +218,25 @@ func (n *Navigator) hintsAfterLineage(symbols []postgres.Symbol) []NavigationSte
 		}
 	}
 
-	steps = append(steps, NavigationStep{
+	impact := NavigationStep{
 		Tool:            "analyze_impact",
 		Description:     "Assess blast radius of changes to this data flow",
 		EstimatedTokens: 1000,
-	})
+	}
+	if hub, ok := mostConnected(symbols); ok {
+		impact.Description = fmt.Sprintf("Assess blast radius of changing %s", hub.QualifiedName)
+		impact.Params = map[string]string{
+			"symbol_id":   hub.ID.String(),
+			"symbol_name": hub.QualifiedName,
+			"change_type": "modify",
+		}
+	}
+	steps = append(steps, impact)
 
 	return steps
 }
 
-func (n *Navigator) hintsAfterOverview(_ []postgres.Symbol) []NavigationStep {
+func hintsAfterOverview(_ []postgres.Symbol) []NavigationStep {
 	return []NavigationStep{
 		{
 			Tool:            "search_symbols",
@@ -236,7 +251,7 @@ func (n *Navigator) hintsAfterOverview(_ []postgres.Symbol) []NavigationStep {
 	}
 }
 
-func (n *Navigator) hintsAfterUsages(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterUsages(symbols []postgres.Symbol) []NavigationStep {
 	steps := make([]NavigationStep, 0, 2)
 
 	for _, sym := range symbols {
@@ -254,7 +269,7 @@ func (n *Navigator) hintsAfterUsages(symbols []postgres.Symbol) []NavigationStep
 	return steps
 }
 
-func (n *Navigator) hintsAfterImpact(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterImpact(symbols []postgres.Symbol) []NavigationStep {
 	steps := make([]NavigationStep, 0, 2)
 
 	for _, sym := range symbols {
@@ -274,7 +289,15 @@ func (n *Navigator) hintsAfterImpact(symbols []postgres.Symbol) []NavigationStep
 	return steps
 }
 
-func (n *Navigator) defaultHints(symbols []postgres.Symbol) []NavigationStep {
+func hintsAfterSubgraph(symbols []postgres.Symbol) []NavigationStep {
+	steps := make([]NavigationStep, 0, 2)
+	if rel, ok := relationshipHint(symbols); ok {
+		steps = append(steps, rel)
+	}
+	return append(steps, defaultHints(symbols)...)
+}
+
+func defaultHints(symbols []postgres.Symbol) []NavigationStep {
 	if len(symbols) == 0 {
 		return nil
 	}
@@ -289,6 +312,99 @@ func (n *Navigator) defaultHints(symbols []postgres.Symbol) []NavigationStep {
 	}
 }
 
+// relationshipHint builds a ready-to-call relationship query for the most connected
+// symbol of the result set's dominant category: get_lineage for data-heavy results,
+// with the direction taken from the symbol's edge balance, or analyze_impact for
+// code-heavy results.
+func relationshipHint(symbols []postgres.Symbol) (NavigationStep, bool) {
+	var data, code []postgres.Symbol
+	for _, sym := range symbols {
+		switch classifyKind(sym.Kind) {
+		case categoryData:
+			data = append(data, sym)
+		case categoryCode:
+			code = append(code, sym)
+		}
+	}
+
+	if len(data) > 0 && len(data) >= len(code) {
+		target, _ := mostConnected(data)
+		in, out := symbolDegrees(target)
+		direction := "upstream"
+		switch {
+		case in > 0 && out > 0:
+			direction = "both"
+		case out > in:
+			direction = "downstream"
+		}
+		desc := fmt.Sprintf("Trace %s lineage of %s", direction, target.QualifiedName)
+		if direction == "both" {
+			desc = fmt.Sprintf("Trace lineage of %s in both directions", target.QualifiedName)
+		}
+		return NavigationStep{
+			Tool:        "get_lineage",
+			Description: desc,
+			Params: map[string]string{
+				"symbol_id":   target.ID.String(),
+				"symbol_name": target.QualifiedName,
+				"direction":   direction,
+			},
+			EstimatedTokens: 800,
+		}, true
+	}
+
+	if len(code) > 0 {
+		target, _ := mostConnected(code)
+		desc := fmt.Sprintf("Analyze impact of changing %s", target.QualifiedName)
+		if in, _ := symbolDegrees(target); in > 0 {
+			desc = fmt.Sprintf("Analyze impact of changing %s (%d dependents)", target.QualifiedName, in)
+		}
+		return NavigationStep{
+			Tool:        "analyze_impact",
+			Description: desc,
+			Params: map[string]string{
+				"symbol_id":   target.ID.String(),
+				"symbol_name": target.QualifiedName,
+				"change_type": "modify",
+			},
+			EstimatedTokens: 1000,
+		}, true
+	}
+
+	return NavigationStep{}, false
+}
+
+// mostConnected returns the symbol with the highest total degree, keeping result
+// order on ties.
+func mostConnected(symbols []postgres.Symbol) (postgres.Symbol, bool) {
+	if len(symbols) == 0 {
+		return postgres.Symbol{}, false
+	}
+	best, bestDeg := symbols[0], -1
+	for _, sym := range symbols {
+		in, out := symbolDegrees(sym)
+		if in+out > bestDeg {
+			best, bestDeg = sym, in+out
+		}
+	}
+	return best, true
+}
+
+// symbolDegrees returns the in/out degree recorded by analytics (0 when not computed).
+func symbolDegrees(sym postgres.Symbol) (in, out int) {
+	if len(sym.Metadata) == 0 {
+		return 0, 0
+	}
+	var meta struct {
+		InDegree  int `json:"in_degree"`
+		OutDegree int `json:"out_degree"`
+	}
+	if json.Unmarshal(sym.Metadata, &meta) != nil {
+		return 0, 0
+	}
+	return meta.InDegree, meta.OutDegree
+}
+
 func estimateDetailTokens(sym postgres.Symbol) int {
 	base := 200
 	if sym.DocComment != nil {
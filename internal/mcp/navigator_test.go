@@ -265,3 +265,55 @@ func TestSuggestNextSteps_HintsHaveTokenEstimates(t *testing.T) {
 		}
 	}
 }
+
+func TestSuggestNextSteps_TableHeavyResultsGetLineageHint(t *testing.T) {
+	nav := NewNavigator(nil)
+	syms := []postgres.Symbol{
+		makeSymbolWithMeta("Customers", "table", "dbo.Customers", map[string]any{"in_degree": 2}),
+		makeSymbolWithMeta("Orders", "table", "dbo.Orders", map[string]any{"in_degree": 14}),
+		makeSymbolWithMeta("OrderLines", "table", "dbo.OrderLines", map[string]any{"in_degree": 5}),
+		makeSymbolWithMeta("usp_PlaceOrder", "procedure", "dbo.usp_PlaceOrder", map[string]any{"out_degree": 3}),
+	}
+
+	for _, tool := range []string{"extract_subgraph", "search_symbols"} {
+		hints := nav.SuggestNextSteps(tool, syms, nil)
+		if hints == nil {
+			t.Fatalf("%s: expected hints", tool)
+		}
+		var lineage *NavigationStep
+		for i, s := range hints.Steps {
+			if s.Tool == "get_lineage" {
+				lineage = &hints.Steps[i]
+				break
+			}
+		}
+		if lineage == nil {
+			t.Fatalf("%s: table-heavy results should suggest get_lineage, got %+v", tool, hints.Steps)
+		}
+		if lineage.Params["symbol_name"] != "dbo.Orders" {
+			t.Errorf("%s: lineage hint should target the most connected table dbo.Orders, got %q", tool, lineage.Params["symbol_name"])
+		}
+		if lineage.Params["symbol_id"] != syms[1].ID.String() {
+			t.Errorf("%s: lineage hint should carry the symbol ID", tool)
+		}
+		if lineage.Params["direction"] != "upstream" {
+			t.Errorf("%s: table with only incoming edges should trace upstream, got %q", tool, lineage.Params["direction"])
+		}
+	}
+}
+
+func TestSuggestNextSteps_CodeHeavyResultsGetImpactHint(t *testing.T) {
+	nav := NewNavigator(nil)
+	syms := []postgres.Symbol{
+		makeSymbolWithMeta("Save", "method", "app.Repo.Save", map[string]any{"in_degree": 1}),
+		makeSymbolWithMeta("usp_Bill", "procedure", "dbo.usp_Bill", map[string]any{"in_degree": 9, "out_degree": 2}),
+	}
+	hints := nav.SuggestNextSteps("extract_subgraph", syms, nil)
+	if hints == nil || len(hints.Steps) == 0 {
+		t.Fatal("expected hints")
+	}
+	first := hints.Steps[0]
+	if first.Tool != "analyze_impact" || first.Params["symbol_name"] != "dbo.usp_Bill" {
+		t.Errorf("expected analyze_impact on dbo.usp_Bill first, got %s %v", first.Tool, first.Params)
+	}
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Progress reports how far a long tool call has got while it runs, such as the depth a
+// graph walk has reached, so clients can show it is working. It carries status only:
+// the response is in the call's result.
+type Progress struct {
+	notify func(step int, message string)
+	steps  int
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context whose tool call reports progress through notify,
+// called with each message and its 1-based step number.
+func WithProgress(ctx context.Context, notify func(step int, message string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, &Progress{notify: notify})
+}
+
+// ProgressFrom returns the progress reporter of a call started with WithProgress, or nil.
+func ProgressFrom(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+// Report sends the next progress message. It is a no-op on a nil Progress.
+func (p *Progress) Report(format string, args ...any) {
+	if p == nil {
+		return
+	}
+	p.steps++
+	p.notify(p.steps, fmt.Sprintf(format, args...))
+}
+
+// Steps returns the number of messages sent.
+func (p *Progress) Steps() int {
+	if p == nil {
+		return 0
+	}
+	return p.steps
+}
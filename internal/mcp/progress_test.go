@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProgress_Report(t *testing.T) {
+	var messages []string
+	ctx := WithProgress(context.Background(), func(step int, message string) {
+		if step != len(messages)+1 {
+			t.Errorf("expected step %d, got %d", len(messages)+1, step)
+		}
+		messages = append(messages, message)
+	})
+
+	p := ProgressFrom(ctx)
+	p.Report("walked depth %d of %d", 1, 3)
+	p.Report("found %d callers", 4)
+	if len(messages) != 2 || messages[0] != "walked depth 1 of 3" || messages[1] != "found 4 callers" {
+		t.Fatalf("unexpected messages %q", messages)
+	}
+	if p.Steps() != 2 {
+		t.Errorf("expected 2 steps, got %d", p.Steps())
+	}
+
+	// Without progress in the context reporting does nothing.
+	none := ProgressFrom(context.Background())
+	none.Report("ignored")
+	if none.Steps() != 0 {
+		t.Errorf("expected no steps without progress, got %d", none.Steps())
+	}
+}
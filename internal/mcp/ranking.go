@@ -78,7 +78,8 @@ func RankSymbols(symbols []postgres.Symbol, query string, config RankConfig, ses
 		ranked[i] = RankedSymbol{Symbol: sym, Score: score}
 	}
 
-	sort.Slice(ranked, func(i, j int) bool {
+	// Stable, so equal scores keep the order the symbols came in
+	sort.SliceStable(ranked, func(i, j int) bool {
 		return ranked[i].Score > ranked[j].Score
 	})
 
@@ -183,6 +184,7 @@ func kindPriorityScore(kind string) float64 {
 		"type":      0.65,
 		"enum":      0.6,
 		"trigger":   0.6,
+		"sequence":  0.5,
 		"property":  0.5,
 		"field":     0.45,
 		"variable":  0.4,
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CallStats describes the outcome of a single tool call. Handlers fill it in through
+// RecordResults and RecordIntent; it never holds response content.
+type CallStats struct {
+	Intent    string      // ask_codebase intent, empty for other tools
+	Total     int         // results found
+	Returned  int         // results included in the response
+	Truncated bool        // fewer results returned than found
+	Recorded  bool        // the handler reported result counts
+	Symbols   []uuid.UUID // symbols shown in the response (see ResponseSymbols)
+}
+
+type callStatsKey struct{}
+
+// WithCallStats returns a context that collects CallStats for one tool call.
+func WithCallStats(ctx context.Context) (context.Context, *CallStats) {
+	stats := &CallStats{}
+	return context.WithValue(ctx, callStatsKey{}, stats), stats
+}
+
+// RecordResults notes how many results a tool call found and returned. It is a no-op
+// outside a call started with WithCallStats; a later call overwrites an earlier one, so
+// a delegating tool reports what its delegate returned.
+func RecordResults(ctx context.Context, total, returned int) {
+	if stats, ok := ctx.Value(callStatsKey{}).(*CallStats); ok {
+		stats.Total = total
+		stats.Returned = returned
+		stats.Truncated = returned < total
+		stats.Recorded = true
+	}
+}
+
+// RecordIntent notes the classified intent of an ask_codebase call.
+func RecordIntent(ctx context.Context, intent string) {
+	if stats, ok := ctx.Value(callStatsKey{}).(*CallStats); ok {
+		stats.Intent = intent
+	}
+}
+
+// Telemetry emits per-tool-call structured logs and Prometheus metrics about result
+// usefulness: empty and truncated results by tool and intent, and call duration. With
+// TrackUsage it also counts the symbols shown in successful responses.
+type Telemetry struct {
+	logger    *slog.Logger
+	calls     *prometheus.CounterVec
+	empty     *prometheus.CounterVec
+	truncated *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	usage     *Usage
+}
+
+// NewTelemetry creates the tool metrics and registers them with reg.
+func NewTelemetry(reg prometheus.Registerer, logger *slog.Logger) *Telemetry {
+	labels := []string{"tool", "intent"}
+	t := &Telemetry{
+		logger: logger,
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "lattice_mcp_tool_calls_total",
+			Help: "MCP tool calls by tool, intent and outcome (ok or error).",
+		}, append(labels, "outcome")),
+		empty: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "lattice_mcp_tool_empty_results_total",
+			Help: "MCP tool calls that returned zero results.",
+		}, labels),
+		truncated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "lattice_mcp_tool_truncated_results_total",
+			Help: "MCP tool calls whose results were truncated.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "lattice_mcp_tool_duration_seconds",
+			Help:    "MCP tool call duration.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+	}
+	reg.MustRegister(t.calls, t.empty, t.truncated, t.duration)
+	return t
+}
+
+// TrackUsage counts the symbols each successful call shows in u.
+func (t *Telemetry) TrackUsage(u *Usage) {
+	t.usage = u
+}
+
+// Observe records one finished tool call. A nil Telemetry records nothing.
+func (t *Telemetry) Observe(tool string, stats *CallStats, elapsed time.Duration, err error) {
+	if t == nil {
+		return
+	}
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	t.calls.WithLabelValues(tool, stats.Intent, outcome).Inc()
+	t.duration.WithLabelValues(tool, stats.Intent).Observe(elapsed.Seconds())
+	if err == nil && stats.Recorded {
+		if stats.Total == 0 {
+			t.empty.WithLabelValues(tool, stats.Intent).Inc()
+		}
+		if stats.Truncated {
+			t.truncated.WithLabelValues(tool, stats.Intent).Inc()
+		}
+	}
+	if err == nil && t.usage != nil {
+		t.usage.Record(stats.Symbols)
+	}
+
+	attrs := []any{
+		slog.String("tool", tool),
+		slog.String("outcome", outcome),
+		slog.Duration("duration", elapsed),
+	}
+	if stats.Intent != "" {
+		attrs = append(attrs, slog.String("intent", stats.Intent))
+	}
+	if stats.Recorded {
+		attrs = append(attrs,
+			slog.Int("results", stats.Total),
+			slog.Int("returned", stats.Returned),
+			slog.Bool("truncated", stats.Truncated),
+			slog.Bool("empty", stats.Total == 0))
+	}
+	t.logger.Info("tool call", attrs...)
+}
@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// maxFileImpactSymbolNames caps how many affected symbol names are listed per file.
+const maxFileImpactSymbolNames = 5
+
+// AnalyzeFileImpactParams are the parameters for the analyze_file_impact tool.
+type AnalyzeFileImpactParams struct {
+	Project    string `json:"project"`
+	Path       string `json:"path"`                  // file path as indexed, e.g. src/orders/service.ts
+	ChangeType string `json:"change_type,omitempty"` // modify, delete, rename
+	MaxDepth   int    `json:"max_depth,omitempty"`
+}
+
+// AnalyzeFileImpactHandler implements the analyze_file_impact MCP tool.
+type AnalyzeFileImpactHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewAnalyzeFileImpactHandler creates a new handler.
+func NewAnalyzeFileImpactHandler(s *store.Store, logger *slog.Logger) *AnalyzeFileImpactHandler {
+	return &AnalyzeFileImpactHandler{store: s, logger: logger}
+}
+
+// fileImpactGraph is the store subset needed to expand a file into its symbols and
+// map affected symbols back to the files defining them.
+type fileImpactGraph interface {
+	symbolGraph
+	GetFile(ctx context.Context, id uuid.UUID) (postgres.File, error)
+	ListFilesByPath(ctx context.Context, arg postgres.ListFilesByPathParams) ([]postgres.File, error)
+	ListSymbolsByFileIDs(ctx context.Context, fileIDs []uuid.UUID) ([]postgres.Symbol, error)
+}
+
+// Handle performs impact analysis for every symbol defined in a file and reports the
+// affected files.
+func (h *AnalyzeFileImpactHandler) Handle(ctx context.Context, params AnalyzeFileImpactParams) (string, error) {
+	if params.Path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = 3
+	}
+	if params.ChangeType == "" {
+		params.ChangeType = "modify"
+	}
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	res, err := collectFileImpact(ctx, h.store, project.ID, params.Path, params.MaxDepth)
+	if err != nil {
+		return "", err
+	}
+	mcp.RecordResults(ctx, len(res.Files), len(res.Files))
+	return formatFileImpact(res, params), nil
+}
+
+// affectedFile is a file containing symbols affected by a change to another file.
+type affectedFile struct {
+	Path     string
+	Symbols  []impactNode
+	MinDepth int
+}
+
+// fileImpactResult aggregates the symbol-level impact of every symbol in a file.
+type fileImpactResult struct {
+	Path          string
+	SymbolCount   int            // symbols defined in the changed file
+	Files         []affectedFile // affected files, most affected symbols first
+	TotalAffected int            // distinct affected symbols outside the changed file
+}
+
+// collectFileImpact runs collectImpact from each symbol defined at path and groups
+// the affected symbols by the file defining them. Each symbol is counted once, at the
+// shallowest depth any seed reached it; symbols of the changed file itself are left out.
+func collectFileImpact(ctx context.Context, g fileImpactGraph, projectID uuid.UUID, path string, maxDepth int) (fileImpactResult, error) {
+	res := fileImpactResult{Path: path}
+
+	files, err := g.ListFilesByPath(ctx, postgres.ListFilesByPathParams{ProjectID: projectID, Path: path})
+	if err != nil {
+		return res, err
+	}
+	if len(files) == 0 {
+		return res, fmt.Errorf("file %q not found in project", path)
+	}
+	fileIDs := make([]uuid.UUID, len(files))
+	own := make(map[uuid.UUID]bool, len(files))
+	for i, f := range files {
+		fileIDs[i] = f.ID
+		own[f.ID] = true
+	}
+	seeds, err := g.ListSymbolsByFileIDs(ctx, fileIDs)
+	if err != nil {
+		return res, err
+	}
+	res.SymbolCount = len(seeds)
+
+	affected := make(map[uuid.UUID]impactNode)
+	for _, seed := range seeds {
+		r := collectImpact(ctx, g, nil, seed, maxDepth, false)
+		for _, group := range [][]impactNode{r.Direct, r.Transitive, r.Callers} {
+			for _, n := range group {
+				if own[n.Symbol.FileID] {
+					continue
+				}
+				if prev, ok := affected[n.Symbol.ID]; ok && prev.Depth <= n.Depth {
+					continue
+				}
+				affected[n.Symbol.ID] = n
+			}
+		}
+	}
+	res.TotalAffected = len(affected)
+
+	byFile := make(map[uuid.UUID]*affectedFile)
+	for _, n := range affected {
+		af, ok := byFile[n.Symbol.FileID]
+		if !ok {
+			af = &affectedFile{Path: "(unknown file)", MinDepth: n.Depth}
+			if f, err := g.GetFile(ctx, n.Symbol.FileID); err == nil {
+				af.Path = f.Path
+			}
+			byFile[n.Symbol.FileID] = af
+		}
+		af.Symbols = append(af.Symbols, n)
+		af.MinDepth = min(af.MinDepth, n.Depth)
+	}
+
+	for _, af := range byFile {
+		sort.Slice(af.Symbols, func(i, j int) bool {
+			if af.Symbols[i].Depth != af.Symbols[j].Depth {
+				return af.Symbols[i].Depth < af.Symbols[j].Depth
+			}
+			return af.Symbols[i].Symbol.QualifiedName < af.Symbols[j].Symbol.QualifiedName
+		})
+		res.Files = append(res.Files, *af)
+	}
+	sort.Slice(res.Files, func(i, j int) bool {
+		a, b := res.Files[i], res.Files[j]
+		if len(a.Symbols) != len(b.Symbols) {
+			return len(a.Symbols) > len(b.Symbols)
+		}
+		return a.Path < b.Path
+	})
+	return res, nil
+}
+
+// formatFileImpact renders the affected files with their affected-symbol counts.
+func formatFileImpact(res fileImpactResult, params AnalyzeFileImpactParams) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**File Impact Analysis: %s `%s`**", params.ChangeType, res.Path))
+	rb.AddLine(fmt.Sprintf("%d symbols defined in the file; %d affected symbols across %d files.",
+		res.SymbolCount, res.TotalAffected, len(res.Files)))
+	rb.AddLine("")
+
+	if len(res.Files) == 0 {
+		rb.AddLine("No impact outside this file found.")
+		return rb.Finalize(0, 0)
+	}
+
+	rb.AddLine("### Affected Files")
+	shown := 0
+	for _, af := range res.Files {
+		names := ""
+		for i, n := range af.Symbols {
+			if i == maxFileImpactSymbolNames {
+				names += fmt.Sprintf(", +%d more", len(af.Symbols)-i)
+				break
+			}
+			if i > 0 {
+				names += ", "
+			}
+			names += "`" + n.Symbol.Name + "`"
+		}
+		if !rb.AddLine(fmt.Sprintf("- `%s` — %d symbols (nearest depth %d): %s",
+			af.Path, len(af.Symbols), af.MinDepth, names)) {
+			break
+		}
+		shown++
+	}
+	rb.AddLine("")
+	rb.AddLine("**Drill down:** use `analyze_impact` on an individual symbol for edge types and severity.")
+
+	return rb.Finalize(len(res.Files), shown)
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeFileImpactGraph adds files to the impact graph fake.
+type fakeFileImpactGraph struct {
+	*fakeImpactGraph
+	files map[uuid.UUID]postgres.File
+}
+
+func (g *fakeFileImpactGraph) GetFile(_ context.Context, id uuid.UUID) (postgres.File, error) {
+	f, ok := g.files[id]
+	if !ok {
+		return postgres.File{}, errors.New("not found")
+	}
+	return f, nil
+}
+
+func (g *fakeFileImpactGraph) ListFilesByPath(_ context.Context, arg postgres.ListFilesByPathParams) ([]postgres.File, error) {
+	var out []postgres.File
+	for _, f := range g.files {
+		if f.Path == arg.Path {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func (g *fakeFileImpactGraph) ListSymbolsByFileIDs(_ context.Context, ids []uuid.UUID) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, s := range g.symbols {
+		for _, id := range ids {
+			if s.FileID == id {
+				out = append(out, s)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestAnalyzeFileImpact_CombinesAndDeduplicatesFiles(t *testing.T) {
+	file := func(path string) postgres.File { return postgres.File{ID: uuid.New(), Path: path} }
+	changed, orders, billing, audit := file("src/service.ts"), file("src/orders.ts"), file("src/billing.ts"), file("src/audit.ts")
+	sym := func(f postgres.File, name string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), FileID: f.ID, Name: name, QualifiedName: name, Kind: "function", Language: "typescript"}
+	}
+	create, charge := sym(changed, "createOrder"), sym(changed, "chargeCard")
+	saveOrder, sendInvoice, logEvent := sym(orders, "saveOrder"), sym(billing, "sendInvoice"), sym(audit, "logEvent")
+
+	g := &fakeFileImpactGraph{
+		fakeImpactGraph: &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(create, charge, saveOrder, sendInvoice, logEvent)},
+		files:           map[uuid.UUID]postgres.File{changed.ID: changed, orders.ID: orders, billing.ID: billing, audit.ID: audit},
+	}
+	g.link(create, saveOrder, "calls")
+	g.link(charge, sendInvoice, "calls")
+	g.link(create, logEvent, "calls")
+	g.link(charge, logEvent, "calls")
+	g.link(create, charge, "calls") // within the changed file: not reported
+
+	res, err := collectFileImpact(context.Background(), g, uuid.New(), "src/service.ts", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SymbolCount != 2 {
+		t.Errorf("expected 2 symbols in the changed file, got %d", res.SymbolCount)
+	}
+
+	counts := make(map[string]int)
+	for _, f := range res.Files {
+		if _, dup := counts[f.Path]; dup {
+			t.Errorf("file %s listed twice", f.Path)
+		}
+		counts[f.Path] = len(f.Symbols)
+	}
+	want := map[string]int{"src/orders.ts": 1, "src/billing.ts": 1, "src/audit.ts": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("expected files %v, got %v", want, counts)
+	}
+	for path, n := range want {
+		if counts[path] != n {
+			t.Errorf("%s: expected %d affected symbols, got %d", path, n, counts[path])
+		}
+	}
+	if res.TotalAffected != 3 {
+		t.Errorf("expected 3 distinct affected symbols, got %d", res.TotalAffected)
+	}
+
+	out := formatFileImpact(res, AnalyzeFileImpactParams{ChangeType: "modify"})
+	if !strings.Contains(out, "- `src/audit.ts` — 1 symbols (nearest depth 1): `logEvent`") {
+		t.Errorf("expected audit.ts line, got:\n%s", out)
+	}
+	if strings.Contains(out, "src/service.ts` —") {
+		t.Errorf("changed file must not be listed as affected, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeFileImpact_UnknownPath(t *testing.T) {
+	g := &fakeFileImpactGraph{
+		fakeImpactGraph: &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph()},
+		files:           map[uuid.UUID]postgres.File{},
+	}
+	if _, err := collectFileImpact(context.Background(), g, uuid.New(), "missing.ts", 3); err == nil {
+		t.Fatal("expected an error for an unknown path")
+	}
+}
@@ -4,13 +4,23 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 
 	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/impact"
 	"github.com/maraichr/lattice/internal/mcp"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
+)
+
+const (
+	defaultImpactMaxAffected = 200
+	maxImpactAffected        = 2000
 )
 
 // AnalyzeImpactParams are the parameters for the analyze_impact tool.
@@ -20,17 +30,38 @@ type AnalyzeImpactParams struct {
 	SymbolName string `json:"symbol_name,omitempty"`
 	ChangeType string `json:"change_type,omitempty"` // modify, delete, rename
 	MaxDepth   int    `json:"max_depth,omitempty"`
+	// MaxAffected caps how many affected symbols are listed individually; larger
+	// blast radii are summarized by kind and layer. Default: 200, max: 2000.
+	MaxAffected int `json:"max_affected,omitempty"`
+	// IncludeImplementations follows calls to an interface method on to the matching
+	// methods of its implementations, at reduced confidence.
+	IncludeImplementations bool `json:"include_implementations,omitempty"`
+	// ExcludeConditional skips calls only taken behind a feature-flag check, and what
+	// is reached only through them. By default they are followed at reduced confidence.
+	ExcludeConditional bool `json:"exclude_conditional,omitempty"`
+	// IncludeOwnership appends the last commit author and date of each affected
+	// symbol's file (git sources with track_ownership only).
+	IncludeOwnership bool `json:"include_ownership,omitempty"`
+	// SeverityThresholds replaces, for this analysis, the configured thresholds the
+	// blast radius is rated by.
+	SeverityThresholds *impact.SeverityOverride `json:"severity_thresholds,omitempty"`
 }
 
 // AnalyzeImpactHandler implements the analyze_impact MCP tool.
 type AnalyzeImpactHandler struct {
-	store  *store.Store
-	logger *slog.Logger
+	store    *store.Store
+	severity impact.SeverityConfig
+	logger   *slog.Logger
 }
 
 // NewAnalyzeImpactHandler creates a new handler.
 func NewAnalyzeImpactHandler(s *store.Store, logger *slog.Logger) *AnalyzeImpactHandler {
-	return &AnalyzeImpactHandler{store: s, logger: logger}
+	return &AnalyzeImpactHandler{store: s, severity: impact.DefaultSeverityConfig(), logger: logger}
+}
+
+// SetSeverityConfig sets the thresholds the blast radius of a change is rated by.
+func (h *AnalyzeImpactHandler) SetSeverityConfig(c impact.SeverityConfig) {
+	h.severity = c
 }
 
 // Handle performs downstream impact analysis from a symbol.
@@ -41,9 +72,19 @@ func (h *AnalyzeImpactHandler) Handle(ctx context.Context, params AnalyzeImpactP
 	if params.MaxDepth <= 0 {
 		params.MaxDepth = 3
 	}
+	if params.MaxAffected <= 0 {
+		params.MaxAffected = defaultImpactMaxAffected
+	}
+	if params.MaxAffected > maxImpactAffected {
+		params.MaxAffected = maxImpactAffected
+	}
 	if params.ChangeType == "" {
 		params.ChangeType = "modify"
 	}
+	severity, err := h.severity.With(params.SeverityThresholds)
+	if err != nil {
+		return "", fmt.Errorf("severity_thresholds: %w", err)
+	}
 
 	project, err := h.store.GetProject(ctx, params.Project)
 	if err != nil {
@@ -59,71 +100,287 @@ func (h *AnalyzeImpactHandler) Handle(ctx context.Context, params AnalyzeImpactP
 		return "", err
 	}
 
-	// BFS downstream to find all affected symbols
-	type impactNode struct {
-		Symbol     postgres.Symbol
-		Depth      int
-		EdgeType   string
-		Confidence float64
+	var impls implementationGraph
+	if params.IncludeImplementations {
+		impls = h.store
+	}
+	res := collectImpact(ctx, h.store, impls, seed, params.MaxDepth, params.ExcludeConditional)
+	res.Severity = res.classify(severity)
+	total := res.total()
+
+	var owners map[uuid.UUID]mcp.Owner
+	if params.IncludeOwnership && total <= params.MaxAffected {
+		mcp.ProgressFrom(ctx).Report("loading ownership of %d affected symbols", total)
+		if owners, err = loadOwners(ctx, h.store, res.symbolIDs()); err != nil {
+			return "", err
+		}
+	}
+	mcp.RecordResults(ctx, total, total)
+	return formatImpact(res, params, owners), nil
+}
+
+// impactNode is a symbol reached during impact analysis.
+type impactNode struct {
+	Symbol     postgres.Symbol
+	Depth      int
+	EdgeType   string
+	Confidence float64
+	// Via names the interface method this node implements when it was reached by
+	// polymorphic dispatch rather than a direct edge.
+	Via string
+	// Conditional marks a node reached only through a call behind a feature-flag check.
+	Conditional bool
+}
+
+// implementationConfidence scales the confidence of an edge that reaches an
+// implementation through its interface method: the call may dispatch to any of them.
+const implementationConfidence = 0.5
+
+// conditionalConfidence scales the confidence of an edge only taken behind a
+// feature-flag check: the dependent breaks only while the flag is on.
+const conditionalConfidence = 0.5
+
+// implementationGraph is the store lookup needed to follow an interface method to
+// the corresponding methods of the types implementing it.
+type implementationGraph interface {
+	GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetSymbol(ctx context.Context, id uuid.UUID) (postgres.Symbol, error)
+	GetSymbolByQualifiedName(ctx context.Context, arg postgres.GetSymbolByQualifiedNameParams) (postgres.Symbol, error)
+}
+
+// impactResult holds every symbol affected by a change to Seed.
+type impactResult struct {
+	Seed       postgres.Symbol
+	Direct     []impactNode
+	Transitive []impactNode
+	Callers    []impactNode
+	Severity   string // rating of the blast radius, "" when not rated
+}
+
+// classify rates the blast radius under c. Callers count as direct dependents: they
+// break as soon as the seed changes.
+func (r impactResult) classify(c impact.SeverityConfig) string {
+	return c.Classify(len(r.Direct)+len(r.Callers), len(r.Transitive), r.crossesLanguages())
+}
+
+// crossesLanguages reports whether any affected symbol is in a language other than
+// the seed's, so that the impact crosses a language bridge.
+func (r impactResult) crossesLanguages() bool {
+	for _, group := range [][]impactNode{r.Direct, r.Transitive, r.Callers} {
+		for _, n := range group {
+			if n.Symbol.Language != "" && r.Seed.Language != "" && n.Symbol.Language != r.Seed.Language {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r impactResult) total() int {
+	return len(r.Direct) + len(r.Transitive) + len(r.Callers)
+}
+
+// symbolIDs lists every affected symbol, for batched lookups.
+func (r impactResult) symbolIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, r.total())
+	for _, group := range [][]impactNode{r.Direct, r.Transitive, r.Callers} {
+		for _, n := range group {
+			ids = append(ids, n.Symbol.ID)
+		}
 	}
+	return ids
+}
 
+// collectImpact walks outgoing edges breadth-first from seed up to maxDepth hops and
+// gathers the seed's direct incoming references as callers. When impls is non-nil, a
+// method reached by a calls edge (or the seed itself) also reaches the same-named
+// methods of every type that implements or inherits its declaring type. Conditional
+// edges are skipped when skipConditional, and otherwise mark what they reach Conditional
+// at reduced confidence. Each depth walked is reported to the call's progress.
+func collectImpact(ctx context.Context, g symbolGraph, impls implementationGraph, seed postgres.Symbol, maxDepth int, skipConditional bool) impactResult {
+	progress := mcp.ProgressFrom(ctx)
+	res := impactResult{Seed: seed}
 	visited := map[uuid.UUID]bool{seed.ID: true}
-	var direct, transitive []impactNode
 
-	queue := []impactNode{{Symbol: seed, Depth: 0}}
+	add := func(node impactNode) {
+		if node.Depth == 1 {
+			res.Direct = append(res.Direct, node)
+		} else {
+			res.Transitive = append(res.Transitive, node)
+		}
+	}
+
+	var queue []impactNode
+	// dispatch adds the implementations of an interface method reached at node.
+	dispatch := func(node impactNode) {
+		depth := max(node.Depth, 1)
+		conf := node.Confidence
+		if conf == 0 {
+			conf = 1
+		}
+		for _, impl := range findImplementations(ctx, impls, node.Symbol) {
+			if visited[impl.ID] {
+				continue
+			}
+			visited[impl.ID] = true
+			n := impactNode{Symbol: impl, Depth: depth, EdgeType: "calls", Confidence: conf * implementationConfidence, Via: node.Symbol.QualifiedName, Conditional: node.Conditional}
+			add(n)
+			queue = append(queue, n)
+		}
+	}
+
+	queue = append(queue, impactNode{Symbol: seed, Depth: 0})
+	if impls != nil {
+		dispatch(queue[0])
+	}
+	depth := 0
 	for len(queue) > 0 {
 		cur := queue[0]
 		queue = queue[1:]
-		if cur.Depth >= params.MaxDepth {
+		if cur.Depth > depth {
+			// The queue is breadth-first: every node of the previous depth is walked
+			progress.Report("walked depth %d of %d: %d affected", depth+1, maxDepth, len(res.Direct)+len(res.Transitive))
+			depth = cur.Depth
+		}
+		if cur.Depth >= maxDepth {
 			continue
 		}
 
-		edges, err := h.store.GetOutgoingEdges(ctx, cur.Symbol.ID)
+		edges, err := g.GetOutgoingEdges(ctx, cur.Symbol.ID)
 		if err != nil {
 			continue
 		}
+		// Unconditional edges first, so a symbol reachable both ways is not marked
+		sort.SliceStable(edges, func(i, j int) bool {
+			return !models.IsConditionalEdge(edges[i].Metadata) && models.IsConditionalEdge(edges[j].Metadata)
+		})
 		for _, e := range edges {
-			if visited[e.TargetID] {
+			conditional := models.IsConditionalEdge(e.Metadata)
+			if visited[e.TargetID] || (skipConditional && conditional) {
 				continue
 			}
 			visited[e.TargetID] = true
-			sym, err := h.store.GetSymbol(ctx, e.TargetID)
+			sym, err := g.GetSymbol(ctx, e.TargetID)
 			if err != nil {
 				continue
 			}
-			node := impactNode{Symbol: sym, Depth: cur.Depth + 1, EdgeType: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata)}
-			if cur.Depth == 0 {
-				direct = append(direct, node)
-			} else {
-				transitive = append(transitive, node)
+			node := impactNode{Symbol: sym, Depth: cur.Depth + 1, EdgeType: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata), Conditional: cur.Conditional}
+			if conditional {
+				node = weightConditional(node)
 			}
+			add(node)
 			queue = append(queue, node)
+			if impls != nil && e.EdgeType == "calls" {
+				dispatch(node)
+			}
 		}
 	}
 
+	if depth < maxDepth {
+		progress.Report("walked depth %d of %d: %d affected", depth+1, maxDepth, len(res.Direct)+len(res.Transitive))
+	}
+
 	// Also check incoming edges for "who references this" (reverse impact)
-	inEdges, _ := h.store.GetIncomingEdges(ctx, seed.ID)
-	var callers []impactNode
+	inEdges, _ := g.GetIncomingEdges(ctx, seed.ID)
 	for _, e := range inEdges {
-		if visited[e.SourceID] {
+		conditional := models.IsConditionalEdge(e.Metadata)
+		if visited[e.SourceID] || (skipConditional && conditional) {
 			continue
 		}
-		sym, err := h.store.GetSymbol(ctx, e.SourceID)
+		sym, err := g.GetSymbol(ctx, e.SourceID)
 		if err != nil {
 			continue
 		}
-		callers = append(callers, impactNode{Symbol: sym, Depth: 1, EdgeType: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata)})
+		node := impactNode{Symbol: sym, Depth: 1, EdgeType: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata)}
+		if conditional {
+			node = weightConditional(node)
+		}
+		res.Callers = append(res.Callers, node)
 	}
+	progress.Report("found %d callers/references", len(res.Callers))
+	return res
+}
+
+// weightConditional marks a node reached over a conditional edge and scales its
+// confidence by conditionalConfidence.
+func weightConditional(n impactNode) impactNode {
+	if n.Confidence == 0 {
+		n.Confidence = 1
+	}
+	n.Confidence *= conditionalConfidence
+	n.Conditional = true
+	return n
+}
+
+// findImplementations returns the methods overriding method in the types that
+// implement or inherit its declaring type, matched by qualified name.
+func findImplementations(ctx context.Context, g implementationGraph, method postgres.Symbol) []postgres.Symbol {
+	if g == nil || method.Kind != "method" {
+		return nil
+	}
+	i := strings.LastIndex(method.QualifiedName, ".")
+	if i <= 0 {
+		return nil
+	}
+	owner, err := g.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{
+		ProjectID:     method.ProjectID,
+		QualifiedName: method.QualifiedName[:i],
+	})
+	if err != nil {
+		return nil
+	}
+	inEdges, err := g.GetIncomingEdges(ctx, owner.ID)
+	if err != nil {
+		return nil
+	}
+	var out []postgres.Symbol
+	for _, e := range inEdges {
+		if e.EdgeType != "implements" && e.EdgeType != "inherits" {
+			continue
+		}
+		impl, err := g.GetSymbol(ctx, e.SourceID)
+		if err != nil {
+			continue
+		}
+		m, err := g.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{
+			ProjectID:     method.ProjectID,
+			QualifiedName: impl.QualifiedName + "." + method.Name,
+		})
+		if err != nil {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// formatImpact renders an impact result, listing every affected symbol for small
+// blast radii and switching to a by-kind/by-layer summary above params.MaxAffected.
+// Listed symbols found in owners get their last commit appended.
+func formatImpact(res impactResult, params AnalyzeImpactParams, owners map[uuid.UUID]mcp.Owner) string {
+	seed := res.Seed
+	direct, transitive, callers := res.Direct, res.Transitive, res.Callers
+	total := res.total()
 
-	// Format response
 	rb := mcp.NewResponseBuilder(4000)
 	rb.AddHeader(fmt.Sprintf("**Impact Analysis: %s %s**", params.ChangeType, seed.Name))
 	rb.AddLine(fmt.Sprintf("Symbol: `%s` (%s, %s)", seed.QualifiedName, seed.Kind, seed.Language))
-	total := len(direct) + len(transitive) + len(callers)
 	rb.AddLine(fmt.Sprintf("Total affected: %d direct, %d transitive, %d callers/references",
 		len(direct), len(transitive), len(callers)))
+	if res.Severity != "" {
+		bridge := ""
+		if res.crossesLanguages() {
+			bridge = " (crosses languages)"
+		}
+		rb.AddLine(fmt.Sprintf("Blast radius severity: **%s**%s", strings.ToUpper(res.Severity), bridge))
+	}
 	rb.AddLine("")
 
+	if total > params.MaxAffected {
+		formatImpactSummary(rb, res, params)
+		return rb.Finalize(total, 0)
+	}
+
 	if len(direct) > 0 {
 		rb.AddLine("### Direct Impact")
 		for _, n := range direct {
@@ -132,8 +389,9 @@ func (h *AnalyzeImpactHandler) Handle(ctx context.Context, params AnalyzeImpactP
 			if n.Confidence > 0 {
 				confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
 			}
-			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] via %s%s — **%s**",
-				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.EdgeType, confStr, severity))
+			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] via %s%s%s — **%s**%s",
+				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.EdgeType, viaSuffix(n), confStr, severity,
+				ownerSuffix(owners, n.Symbol.ID)))
 		}
 		rb.AddLine("")
 	}
@@ -145,8 +403,9 @@ func (h *AnalyzeImpactHandler) Handle(ctx context.Context, params AnalyzeImpactP
 			if n.Confidence > 0 {
 				confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
 			}
-			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] (depth %d, via %s%s)",
-				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.Depth, n.EdgeType, confStr))
+			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] (depth %d, via %s%s%s)%s",
+				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.Depth, n.EdgeType, viaSuffix(n), confStr,
+				ownerSuffix(owners, n.Symbol.ID)))
 		}
 		rb.AddLine("")
 	}
@@ -158,16 +417,104 @@ func (h *AnalyzeImpactHandler) Handle(ctx context.Context, params AnalyzeImpactP
 			if n.Confidence > 0 {
 				confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
 			}
-			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] via %s%s",
-				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.EdgeType, confStr))
+			rb.AddLine(fmt.Sprintf("- %s `%s` [%s] via %s%s%s%s",
+				n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.EdgeType, viaSuffix(n), confStr,
+				ownerSuffix(owners, n.Symbol.ID)))
 		}
 	}
 
-	if len(direct) == 0 && len(transitive) == 0 && len(callers) == 0 {
+	if total == 0 {
 		rb.AddLine("No downstream impact found. This symbol appears to be a leaf node.")
 	}
 
-	return rb.Finalize(total, total), nil
+	return rb.Finalize(total, total)
+}
+
+// viaSuffix notes the interface method a polymorphically reached node implements, and
+// whether it is reached only behind a feature flag.
+func viaSuffix(n impactNode) string {
+	suffix := ""
+	if n.Via != "" {
+		suffix = fmt.Sprintf(" (implementation of `%s`)", n.Via)
+	}
+	if n.Conditional {
+		suffix += " (behind a feature flag)"
+	}
+	return suffix
+}
+
+// formatImpactSummary writes the aggregate form used when the blast radius exceeds
+// max_affected: counts by kind and layer, severity of direct hits, and a drill-down hint.
+func formatImpactSummary(rb *mcp.ResponseBuilder, res impactResult, params AnalyzeImpactParams) {
+	byKind := make(map[string]int)
+	byLayer := make(map[string]int)
+	for _, group := range [][]impactNode{res.Direct, res.Transitive, res.Callers} {
+		for _, n := range group {
+			byKind[pluralKind(n.Symbol.Kind)]++
+			byLayer[inferLayer(n.Symbol)]++
+		}
+	}
+	severities := make(map[string]int)
+	for _, n := range res.Direct {
+		severities[classifyImpactSeverity(params.ChangeType, n.EdgeType)]++
+	}
+
+	rb.AddLine(fmt.Sprintf("**%s affected: %s**", formatThousands(res.total()), inlineCounts(byKind)))
+	rb.AddLine(fmt.Sprintf("_Blast radius exceeds max_affected=%d; showing a summary instead of every symbol._", params.MaxAffected))
+	rb.AddLine("")
+	rb.AddSection("By Kind", formatCountMap(byKind))
+	rb.AddSection("By Layer", formatCountMap(byLayer))
+	if len(severities) > 0 {
+		rb.AddSection("Direct Impact Severity", formatCountMap(severities))
+	}
+	rb.AddLine(fmt.Sprintf("**Drill down:** re-run with `max_depth: 1` for direct dependents only, "+
+		"raise `max_affected` (up to %d) for the full list, or use `get_lineage` on `%s` to walk one direction at a time.",
+		maxImpactAffected, res.Seed.QualifiedName))
+}
+
+// inlineCounts renders a name→count map as "12 procedures, 3,900 columns", largest first.
+func inlineCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = formatThousands(counts[name]) + " " + name
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pluralKind returns the plural form of a symbol kind for summary lines.
+func pluralKind(kind string) string {
+	switch {
+	case kind == "":
+		return "unknown"
+	case strings.HasSuffix(kind, "s"), strings.HasSuffix(kind, "x"):
+		return kind + "es"
+	case strings.HasSuffix(kind, "y") && !strings.HasSuffix(kind, "ey"):
+		return kind[:len(kind)-1] + "ies"
+	default:
+		return kind + "s"
+	}
+}
+
+// formatThousands renders n with comma thousands separators.
+func formatThousands(n int) string {
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
 }
 
 func classifyImpactSeverity(changeType, edgeType string) string {
@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/impact"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeImpactGraph adds incoming-edge lookup to the endpoint graph fake.
+type fakeImpactGraph struct {
+	*fakeEndpointGraph
+}
+
+func (g *fakeImpactGraph) GetIncomingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	var out []postgres.SymbolEdge
+	for _, edges := range g.edges {
+		for _, e := range edges {
+			if e.TargetID == id {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (g *fakeImpactGraph) GetSymbolByQualifiedName(_ context.Context, arg postgres.GetSymbolByQualifiedNameParams) (postgres.Symbol, error) {
+	for _, s := range g.symbols {
+		if s.QualifiedName == arg.QualifiedName {
+			return s, nil
+		}
+	}
+	return postgres.Symbol{}, errors.New("not found")
+}
+
+// hubFixture builds a core table with the given number of columns and procedures
+// depending on it.
+func hubFixture(columns, procs int) (postgres.Symbol, *fakeImpactGraph) {
+	table := postgres.Symbol{ID: uuid.New(), Name: "Customers", QualifiedName: "dbo.Customers", Kind: "table", Language: "tsql"}
+	g := &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(table)}
+	for i := 0; i < columns; i++ {
+		col := postgres.Symbol{ID: uuid.New(), Name: fmt.Sprintf("Col%d", i), Kind: "column", Language: "tsql"}
+		g.symbols[col.ID] = col
+		g.link(table, col, "contains")
+	}
+	for i := 0; i < procs; i++ {
+		proc := postgres.Symbol{ID: uuid.New(), Name: fmt.Sprintf("usp_Proc%d", i), Kind: "procedure", Language: "tsql"}
+		g.symbols[proc.ID] = proc
+		g.link(proc, table, "reads_from")
+	}
+	return table, g
+}
+
+func TestAnalyzeImpact_SummarizesHighFanIn(t *testing.T) {
+	table, g := hubFixture(1200, 12)
+	res := collectImpact(context.Background(), g, nil, table, 3, false)
+	if res.total() != 1212 {
+		t.Fatalf("expected 1212 affected symbols, got %d", res.total())
+	}
+
+	out := formatImpact(res, AnalyzeImpactParams{ChangeType: "modify", MaxAffected: 200}, nil)
+	if !strings.Contains(out, "**1,212 affected: 1,200 columns, 12 procedures**") {
+		t.Errorf("expected aggregate summary line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "exceeds max_affected=200") {
+		t.Errorf("expected cap note, got:\n%s", out)
+	}
+	if !strings.Contains(out, "### By Layer") || !strings.Contains(out, "- **database:** 1212") {
+		t.Errorf("expected by-layer breakdown, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Drill down") {
+		t.Errorf("expected drill-down hint, got:\n%s", out)
+	}
+	if strings.Contains(out, "### Direct Impact\n") || strings.Contains(out, "`Col0`") {
+		t.Errorf("summary form should not list individual symbols, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeImpact_ListsSmallBlastRadius(t *testing.T) {
+	table, g := hubFixture(3, 1)
+	res := collectImpact(context.Background(), g, nil, table, 3, false)
+
+	out := formatImpact(res, AnalyzeImpactParams{ChangeType: "delete", MaxAffected: 200}, nil)
+	if strings.Contains(out, "max_affected") {
+		t.Errorf("small blast radius should not be capped, got:\n%s", out)
+	}
+	if !strings.Contains(out, "### Direct Impact") || !strings.Contains(out, "`Col2`") {
+		t.Errorf("expected full direct listing, got:\n%s", out)
+	}
+	if !strings.Contains(out, "### Callers / References") || !strings.Contains(out, "`usp_Proc0`") {
+		t.Errorf("expected caller listing, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeImpact_SeverityFollowsConfig(t *testing.T) {
+	// 4 columns and 8 procedures reading the table: 12 direct dependents
+	table, g := hubFixture(4, 8)
+	res := collectImpact(context.Background(), g, nil, table, 3, false)
+
+	if got := res.classify(impact.DefaultSeverityConfig()); got != impact.SeverityHigh {
+		t.Errorf("default thresholds: got %s, want high", got)
+	}
+	strict, err := impact.DefaultSeverityConfig().With(&impact.SeverityOverride{
+		Critical: &impact.SeverityThreshold{Direct: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.classify(strict); got != impact.SeverityCritical {
+		t.Errorf("critical from 10 direct: got %s, want critical", got)
+	}
+
+	res.Severity = res.classify(strict)
+	out := formatImpact(res, AnalyzeImpactParams{ChangeType: "modify", MaxAffected: 200}, nil)
+	if !strings.Contains(out, "Blast radius severity: **CRITICAL**") {
+		t.Errorf("expected the rating in the header, got:\n%s", out)
+	}
+
+	// A caller in another language raises the rating, unless the config ignores bridges
+	page := postgres.Symbol{ID: uuid.New(), Name: "CustomersPage", Kind: "function", Language: "typescript"}
+	g.symbols[page.ID] = page
+	g.link(page, table, "uses_table")
+	res = collectImpact(context.Background(), g, nil, table, 3, false)
+	if got := res.classify(impact.DefaultSeverityConfig()); got != impact.SeverityCritical {
+		t.Errorf("cross-language impact: got %s, want critical", got)
+	}
+	noBridges := false
+	flat, _ := impact.DefaultSeverityConfig().With(&impact.SeverityOverride{CrossLanguage: &noBridges})
+	if got := res.classify(flat); got != impact.SeverityHigh {
+		t.Errorf("cross-language impact without the bump: got %s, want high", got)
+	}
+
+	if _, err := impact.DefaultSeverityConfig().With(&impact.SeverityOverride{
+		High: &impact.SeverityThreshold{Direct: 100},
+	}); err == nil {
+		t.Error("expected a high threshold above the critical one to be rejected")
+	}
+}
+
+// fakeOwnership serves ListSymbolOwnership from a fixed author map.
+type fakeOwnership map[uuid.UUID]string
+
+func (f fakeOwnership) ListSymbolOwnership(_ context.Context, ids []uuid.UUID) ([]postgres.ListSymbolOwnershipRow, error) {
+	var out []postgres.ListSymbolOwnershipRow
+	for _, id := range ids {
+		if author, ok := f[id]; ok {
+			out = append(out, postgres.ListSymbolOwnershipRow{
+				SymbolID:     id,
+				LastAuthor:   &author,
+				LastCommitAt: pgtype.Timestamptz{Time: time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC), Valid: true},
+			})
+		}
+	}
+	return out, nil
+}
+
+func TestAnalyzeImpact_IncludeOwnership(t *testing.T) {
+	table, g := hubFixture(1, 1)
+	res := collectImpact(context.Background(), g, nil, table, 3, false)
+	proc := res.Callers[0].Symbol
+
+	owners, err := loadOwners(context.Background(), fakeOwnership{proc.ID: "Dana Reyes"}, res.symbolIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := formatImpact(res, AnalyzeImpactParams{ChangeType: "modify", MaxAffected: 200, IncludeOwnership: true}, owners)
+	if !strings.Contains(out, "`usp_Proc0` [tsql] via reads_from — last commit: Dana Reyes, 2026-09-30") {
+		t.Errorf("expected the caller to carry its owner, got:\n%s", out)
+	}
+	if strings.Count(out, "last commit:") != 1 {
+		t.Errorf("symbols without ownership data should be listed without an owner, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeImpact_IncludeImplementations(t *testing.T) {
+	method := func(qn string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: qn[strings.LastIndex(qn, ".")+1:], QualifiedName: qn, Kind: "method", Language: "csharp"}
+	}
+	iface := postgres.Symbol{ID: uuid.New(), Name: "IRepository", QualifiedName: "App.IRepository", Kind: "interface", Language: "csharp"}
+	sqlRepo := postgres.Symbol{ID: uuid.New(), Name: "SqlRepository", QualifiedName: "App.SqlRepository", Kind: "class", Language: "csharp"}
+	memRepo := postgres.Symbol{ID: uuid.New(), Name: "MemoryRepository", QualifiedName: "App.MemoryRepository", Kind: "class", Language: "csharp"}
+	save, sqlSave, memSave := method("App.IRepository.Save"), method("App.SqlRepository.Save"), method("App.MemoryRepository.Save")
+	caller := method("App.OrderService.Place")
+
+	g := &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(iface, sqlRepo, memRepo, save, sqlSave, memSave, caller)}
+	g.link(caller, save, "calls")
+	g.link(sqlRepo, iface, "implements")
+	g.link(memRepo, iface, "implements")
+
+	names := func(res impactResult) map[string]impactNode {
+		out := make(map[string]impactNode)
+		for _, n := range append(res.Direct, res.Transitive...) {
+			out[n.Symbol.QualifiedName] = n
+		}
+		return out
+	}
+
+	off := names(collectImpact(context.Background(), g, nil, caller, 3, false))
+	if _, ok := off["App.SqlRepository.Save"]; ok {
+		t.Errorf("implementations must not be reached without include_implementations, got %v", off)
+	}
+
+	on := names(collectImpact(context.Background(), g, g, caller, 3, false))
+	if _, ok := on["App.IRepository.Save"]; !ok {
+		t.Errorf("expected the interface method in impact, got %v", on)
+	}
+	for _, qn := range []string{"App.SqlRepository.Save", "App.MemoryRepository.Save"} {
+		n, ok := on[qn]
+		if !ok {
+			t.Errorf("expected %s in impact, got %v", qn, on)
+			continue
+		}
+		if n.Confidence != implementationConfidence || n.Via != "App.IRepository.Save" {
+			t.Errorf("%s: expected reduced confidence via the interface method, got %+v", qn, n)
+		}
+	}
+}
+
+func TestFormatThousands(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 4213: "4,213", 1234567: "1,234,567", -4213: "-4,213"}
+	for n, want := range tests {
+		if got := formatThousands(n); got != want {
+			t.Errorf("formatThousands(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestAnalyzeImpact_ReportsEachDepth(t *testing.T) {
+	table, g := hubFixture(4, 2)
+	var messages []string
+	ctx := mcp.WithProgress(context.Background(), func(_ int, message string) {
+		messages = append(messages, message)
+	})
+
+	collectImpact(ctx, g, nil, table, 3, false)
+	want := []string{"walked depth 1 of 3: 4 affected", "walked depth 2 of 3: 4 affected", "found 2 callers/references"}
+	if strings.Join(messages, "|") != strings.Join(want, "|") {
+		t.Errorf("expected progress %q, got %q", want, messages)
+	}
+}
+
+func TestCollectImpact_ConditionalEdges(t *testing.T) {
+	fn := func(name string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: name, QualifiedName: "app." + name, Kind: "function", Language: "go"}
+	}
+	checkout, charge, beta, audit := fn("Checkout"), fn("Charge"), fn("BetaPricing"), fn("Audit")
+	g := &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(checkout, charge, beta, audit)}
+	g.link(checkout, charge, "calls")
+	// Checkout calls BetaPricing only behind a flag, and BetaPricing calls Audit
+	g.edges[checkout.ID] = append(g.edges[checkout.ID], postgres.SymbolEdge{
+		SourceID: checkout.ID, TargetID: beta.ID, EdgeType: "calls", Metadata: []byte(`{"conditional": true}`),
+	})
+	g.link(beta, audit, "calls")
+
+	names := func(res impactResult) map[string]impactNode {
+		out := make(map[string]impactNode)
+		for _, n := range append(res.Direct, res.Transitive...) {
+			out[n.Symbol.Name] = n
+		}
+		return out
+	}
+
+	all := names(collectImpact(context.Background(), g, nil, checkout, 3, false))
+	if n := all["Charge"]; n.Conditional || n.Confidence != 0 {
+		t.Errorf("Charge: expected an unconditional dependent, got %+v", n)
+	}
+	if n := all["BetaPricing"]; !n.Conditional || n.Confidence != conditionalConfidence {
+		t.Errorf("BetaPricing: expected a conditional dependent at reduced confidence, got %+v", n)
+	}
+	if n := all["Audit"]; !n.Conditional {
+		t.Errorf("Audit: expected the flag carried past BetaPricing, got %+v", n)
+	}
+	out := formatImpact(collectImpact(context.Background(), g, nil, checkout, 3, false), AnalyzeImpactParams{ChangeType: "modify", MaxAffected: 200}, nil)
+	if !strings.Contains(out, "`BetaPricing` [go] via calls (behind a feature flag)") {
+		t.Errorf("expected the conditional dependent to be noted, got:\n%s", out)
+	}
+
+	skipped := names(collectImpact(context.Background(), g, nil, checkout, 3, true))
+	if _, ok := skipped["Charge"]; !ok {
+		t.Errorf("expected Charge still reached with exclude_conditional, got %v", skipped)
+	}
+	for _, name := range []string{"BetaPricing", "Audit"} {
+		if _, ok := skipped[name]; ok {
+			t.Errorf("expected %s skipped with exclude_conditional, got %v", name, skipped)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/maraichr/lattice/internal/auth"
 	"github.com/maraichr/lattice/internal/embedding"
+	"github.com/maraichr/lattice/internal/impact"
+	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/mcp"
 	"github.com/maraichr/lattice/internal/mcp/session"
 	"github.com/maraichr/lattice/internal/store"
@@ -20,6 +22,7 @@ type AskCodebaseParams struct {
 	Question          string   `json:"question"`
 	Kinds             []string `json:"kinds,omitempty"`
 	Languages         []string `json:"languages,omitempty"`
+	RankBy            string   `json:"rank_by,omitempty"` // ranking questions: in_degree (default), pagerank, betweenness
 	MaxResponseTokens int      `json:"max_response_tokens,omitempty"`
 	SessionID         string   `json:"session_id,omitempty"`
 	Verbosity         string   `json:"verbosity,omitempty"`
@@ -32,7 +35,9 @@ type AskCodebaseHandler struct {
 	subgraph *ExtractSubgraphHandler
 	impact   *AnalyzeImpactHandler
 	lineage  *GetLineageHandler
+	columns  *TraceColumnLineageHandler
 	trace    *TraceCrossLanguageHandler
+	nav      *mcp.Navigator
 	logger   *slog.Logger
 }
 
@@ -44,11 +49,30 @@ func NewAskCodebaseHandler(s *store.Store, sm *session.Manager, embedder embeddi
 		subgraph: NewExtractSubgraphHandler(s, sm, embedder, logger),
 		impact:   NewAnalyzeImpactHandler(s, logger),
 		lineage:  NewGetLineageHandler(s, logger),
+		columns:  NewTraceColumnLineageHandler(s, logger),
 		trace:    NewTraceCrossLanguageHandler(s, logger),
+		nav:      mcp.NewNavigator(s.Queries),
 		logger:   logger,
 	}
 }
 
+// SetNavigationRules sets the rules that pick the navigation hints in responses,
+// including those of the subgraph answers it delegates.
+func (h *AskCodebaseHandler) SetNavigationRules(rules []mcp.NavigationRule) {
+	h.nav.SetRules(rules)
+	h.subgraph.SetNavigationRules(rules)
+}
+
+// SetSeverityConfig sets the thresholds impact answers rate a blast radius by.
+func (h *AskCodebaseHandler) SetSeverityConfig(c impact.SeverityConfig) {
+	h.impact.SetSeverityConfig(c)
+}
+
+// SetEdgeDirections sets which way each edge type points in lineage answers.
+func (h *AskCodebaseHandler) SetEdgeDirections(d lineage.EdgeDirections) {
+	h.lineage.SetEdgeDirections(d)
+}
+
 // Intent represents a classified question intent.
 type Intent string
 
@@ -56,6 +80,7 @@ const (
 	IntentSearch        Intent = "search"
 	IntentImpact        Intent = "impact"
 	IntentLineage       Intent = "lineage"
+	IntentColumnLineage Intent = "column_lineage"
 	IntentOverview      Intent = "overview"
 	IntentSubgraph      Intent = "subgraph"
 	IntentDeps          Intent = "dependencies"
@@ -64,6 +89,7 @@ const (
 	IntentBridges       Intent = "bridges"
 	IntentAnalytics     Intent = "analytics"
 	IntentCrossLanguage Intent = "cross_language"
+	IntentCycles        Intent = "cycles"
 )
 
 // Handle classifies the question intent and routes to the appropriate tool chain.
@@ -76,6 +102,7 @@ func (h *AskCodebaseHandler) Handle(ctx context.Context, params AskCodebaseParam
 	h.logger.Info("classified intent",
 		slog.String("question", params.Question),
 		slog.String("intent", string(intent)))
+	mcp.RecordIntent(ctx, string(intent))
 
 	switch intent {
 	case IntentOverview:
@@ -86,6 +113,8 @@ func (h *AskCodebaseHandler) Handle(ctx context.Context, params AskCodebaseParam
 		return h.handleImpact(ctx, params)
 	case IntentLineage:
 		return h.handleLineage(ctx, params)
+	case IntentColumnLineage:
+		return h.handleColumnLineage(ctx, params)
 	case IntentSubgraph:
 		return h.handleSubgraph(ctx, params)
 	case IntentDeps:
@@ -98,6 +127,8 @@ func (h *AskCodebaseHandler) Handle(ctx context.Context, params AskCodebaseParam
 		return h.handleAnalytics(ctx, params)
 	case IntentCrossLanguage:
 		return h.handleCrossLanguage(ctx, params)
+	case IntentCycles:
+		return h.handleCycles(ctx, params)
 	default:
 		return h.handleSearch(ctx, params)
 	}
@@ -106,6 +137,18 @@ func (h *AskCodebaseHandler) Handle(ctx context.Context, params AskCodebaseParam
 func classifyIntent(question string) Intent {
 	q := strings.ToLower(question)
 
+	// Cycle patterns (check first — "circular dependency" also reads as a dependency
+	// question and "largest cycles" as a ranking one)
+	cyclePatterns := []string{
+		"circular dependenc", "dependency cycle", "circular reference",
+		"import cycle", "cyclic", "cycles between",
+	}
+	for _, p := range cyclePatterns {
+		if strings.Contains(q, p) {
+			return IntentCycles
+		}
+	}
+
 	// Ranking patterns (check early — "most used", "top", "busiest", "most important")
 	rankingPatterns := []string{
 		"most used", "most important", "most referenced", "most connected",
@@ -129,6 +172,17 @@ func classifyIntent(question string) Intent {
 		}
 	}
 
+	// Column lineage patterns (check before symbol lineage)
+	columnLineagePatterns := []string{
+		"column lineage", "get its value", "gets its value", "get their value",
+		"column's value", "column value", "column get populated", "column come from",
+	}
+	for _, p := range columnLineagePatterns {
+		if strings.Contains(q, p) {
+			return IntentColumnLineage
+		}
+	}
+
 	// Lineage patterns
 	lineagePatterns := []string{
 		"data flow", "lineage", "where does", "data come from",
@@ -276,12 +330,20 @@ func (h *AskCodebaseHandler) handleOverview(ctx context.Context, params AskCodeb
 		}
 	}
 
-	nav := mcp.NewNavigator(h.store.Queries)
-	hints := nav.SuggestNextSteps("list_project_overview", nil, nil)
+	hints := h.nav.SuggestNextSteps("list_project_overview", nil, nil)
+	mcp.RecordResults(ctx, 1, 1)
 	return rb.FinalizeWithHints(1, 1, hints), nil
 }
 
 func (h *AskCodebaseHandler) handleRanking(ctx context.Context, params AskCodebaseParams) (string, error) {
+	if err := validateRankBy(params.RankBy); err != nil {
+		return "", err
+	}
+	rankBy := params.RankBy
+	if rankBy == "" {
+		rankBy = "in_degree"
+	}
+
 	project, err := h.store.GetProject(ctx, params.Project)
 	if err != nil {
 		return "", WrapProjectError(err)
@@ -300,6 +362,7 @@ func (h *AskCodebaseHandler) handleRanking(ctx context.Context, params AskCodeba
 		ProjectSlug: project.Slug,
 		Kinds:       kinds,
 		Languages:   params.Languages,
+		RankBy:      rankBy,
 		Lim:         10,
 	})
 	if err != nil {
@@ -307,6 +370,7 @@ func (h *AskCodebaseHandler) handleRanking(ctx context.Context, params AskCodeba
 	}
 
 	if len(results) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
 		return fmt.Sprintf("No symbols found matching the criteria (kinds=%v).", kinds), nil
 	}
 
@@ -317,7 +381,11 @@ func (h *AskCodebaseHandler) handleRanking(ctx context.Context, params AskCodeba
 	if len(kinds) > 0 {
 		kindLabel = strings.Join(kinds, "/") + "s"
 	}
-	rb.AddHeader(fmt.Sprintf("**Top %s by usage (in-degree)**", kindLabel))
+	if rankBy == "in_degree" {
+		rb.AddHeader(fmt.Sprintf("**Top %s by usage (in-degree)**", kindLabel))
+	} else {
+		rb.AddHeader(fmt.Sprintf("**Top %s by %s**", kindLabel, centralityMetrics[rankBy]))
+	}
 
 	var sess *session.Session
 	if h.session != nil && params.SessionID != "" {
@@ -332,8 +400,8 @@ func (h *AskCodebaseHandler) handleRanking(ctx context.Context, params AskCodeba
 		returned++
 	}
 
-	nav := mcp.NewNavigator(h.store.Queries)
-	hints := nav.SuggestNextSteps("search_symbols", results, sess)
+	hints := h.nav.SuggestNextSteps("search_symbols", results, sess)
+	mcp.RecordResults(ctx, len(results), returned)
 	return rb.FinalizeWithHints(len(results), returned, hints), nil
 }
 
@@ -346,37 +414,41 @@ func (h *AskCodebaseHandler) handleSearch(ctx context.Context, params AskCodebas
 		return "", fmt.Errorf("access denied to project %s", params.Project)
 	}
 
+	// Load session for ranking
+	var sess *session.Session
+	if h.session != nil && params.SessionID != "" {
+		sess, _ = h.session.Load(ctx, params.SessionID)
+	}
+
 	searchTerms := extractSearchTerms(params.Question)
 	kinds := params.Kinds
 	if kinds == nil {
 		kinds = []string{}
 	}
-	results, err := h.store.SearchSymbols(ctx, postgres.SearchSymbolsParams{
+	ranked, expansions, err := searchWithSynonyms(ctx, h.store, postgres.SearchSymbolsParams{
 		ProjectSlug: project.Slug,
 		Query:       &searchTerms,
 		Kinds:       kinds,
 		Languages:   params.Languages,
 		Lim:         20,
-	})
+	}, synonymsFromSettings(project.Settings), mcp.DefaultRankConfig(), sess)
 	if err != nil {
 		return "", fmt.Errorf("search symbols: %w", err)
 	}
 
-	if len(results) == 0 {
+	if len(ranked) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
 		return fmt.Sprintf("No symbols found matching '%s'.", params.Question), nil
 	}
-
-	// Load session for ranking
-	var sess *session.Session
-	if h.session != nil && params.SessionID != "" {
-		sess, _ = h.session.Load(ctx, params.SessionID)
+	if len(ranked) > 20 {
+		ranked = ranked[:20]
 	}
 
 	verbosity := mcp.ParseVerbosity(params.Verbosity)
-	ranked := mcp.RankSymbols(results, extractSearchTerms(params.Question), mcp.DefaultRankConfig(), sess)
 
 	rb := mcp.NewResponseBuilder(params.MaxResponseTokens)
 	rb.AddHeader(fmt.Sprintf("**Search results for: %s**", params.Question))
+	addSynonymNote(rb, expansions)
 
 	returned := 0
 	for _, r := range ranked {
@@ -386,14 +458,14 @@ func (h *AskCodebaseHandler) handleSearch(ctx context.Context, params AskCodebas
 		returned++
 	}
 
-	nav := mcp.NewNavigator(h.store.Queries)
 	symbols := make([]postgres.Symbol, 0, len(ranked))
 	for _, r := range ranked {
 		symbols = append(symbols, r.Symbol)
 	}
-	hints := nav.SuggestNextSteps("search_symbols", symbols, sess)
+	hints := h.nav.SuggestNextSteps("search_symbols", symbols, sess)
 
-	return rb.FinalizeWithHints(len(results), returned, hints), nil
+	mcp.RecordResults(ctx, len(ranked), returned)
+	return rb.FinalizeWithHints(len(ranked), returned, hints), nil
 }
 
 func (h *AskCodebaseHandler) handleImpact(ctx context.Context, params AskCodebaseParams) (string, error) {
@@ -430,6 +502,50 @@ func (h *AskCodebaseHandler) handleLineage(ctx context.Context, params AskCodeba
 	})
 }
 
+// handleColumnLineage traces a column named in the question, preferably as a dotted
+// name such as dbo.Orders.Amount, upstream unless the question asks where it goes.
+func (h *AskCodebaseHandler) handleColumnLineage(ctx context.Context, params AskCodebaseParams) (string, error) {
+	column := extractColumnName(params.Question)
+	direction := "upstream"
+	q := strings.ToLower(params.Question)
+	if strings.Contains(q, "downstream") || strings.Contains(q, "flow to") || strings.Contains(q, "flows to") || strings.Contains(q, "used by") {
+		direction = "downstream"
+	}
+	return h.columns.Handle(ctx, TraceColumnLineageParams{
+		Project:   params.Project,
+		Column:    column,
+		Direction: direction,
+	})
+}
+
+// extractColumnName returns the dotted name in a question, the one with the most parts
+// if there are several, or else its search terms without the words asking for lineage.
+func extractColumnName(question string) string {
+	best := ""
+	for _, w := range strings.Fields(question) {
+		w = strings.Trim(w, "?.,!\"'`()")
+		if strings.Count(w, ".") > strings.Count(best, ".") {
+			best = w
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	noise := map[string]bool{
+		"column": true, "columns": true, "lineage": true, "value": true, "values": true,
+		"its": true, "their": true, "come": true, "comes": true, "from": true,
+		"populated": true, "gets": true,
+	}
+	var terms []string
+	for _, w := range strings.Fields(extractSearchTerms(question)) {
+		if !noise[w] {
+			terms = append(terms, w)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
 func (h *AskCodebaseHandler) handleCrossLanguage(ctx context.Context, params AskCodebaseParams) (string, error) {
 	symbolName := extractSearchTerms(params.Question)
 	return h.trace.Handle(ctx, TraceCrossLanguageParams{
@@ -496,6 +612,7 @@ func (h *AskCodebaseHandler) handleBridges(ctx context.Context, params AskCodeba
 
 	if len(rows) == 0 {
 		rb.AddLine("No cross-language bridges found.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -504,6 +621,7 @@ func (h *AskCodebaseHandler) handleBridges(ctx context.Context, params AskCodeba
 			r.SourceLanguage, r.TargetLanguage, r.EdgeType, r.EdgeCount))
 	}
 
+	mcp.RecordResults(ctx, len(rows), len(rows))
 	return rb.Finalize(len(rows), len(rows)), nil
 }
 
@@ -527,6 +645,14 @@ func (h *AskCodebaseHandler) handleAnalytics(ctx context.Context, params AskCode
 	})
 }
 
+func (h *AskCodebaseHandler) handleCycles(ctx context.Context, params AskCodebaseParams) (string, error) {
+	handler := NewGetProjectAnalyticsHandler(h.store, h.logger)
+	return handler.Handle(ctx, GetProjectAnalyticsParams{
+		Project: params.Project,
+		Scope:   "cycles",
+	})
+}
+
 // extractKindsFromQuestion infers symbol kinds from natural language question text.
 func extractKindsFromQuestion(question string) []string {
 	q := strings.ToLower(question)
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	defaultCallTreeDepth = 5
+	maxCallTreeDepth     = 10
+	defaultCallTreeNodes = 200
+	maxCallTreeNodes     = 1000
+)
+
+// callTreeEdges are the edge types followed downstream from the root: calls through the
+// code and across APIs, then data access at the leaves.
+var callTreeEdges = map[string]bool{
+	"calls":           true,
+	"calls_api":       true,
+	"uses_table":      true,
+	"uses_collection": true,
+	"reads_from":      true,
+	"writes_to":       true,
+	"joins":           true,
+}
+
+// CallTreeParams are the parameters for the call_tree tool.
+type CallTreeParams struct {
+	Project    string `json:"project"`
+	SymbolID   string `json:"symbol_id,omitempty"`
+	SymbolName string `json:"symbol_name,omitempty"` // endpoint or entry point, e.g. OrdersController.Get
+	MaxDepth   int    `json:"max_depth,omitempty"`   // default: 5, max: 10
+	MaxNodes   int    `json:"max_nodes,omitempty"`   // nodes rendered, default: 200, max: 1000
+}
+
+// CallTreeHandler implements the call_tree MCP tool.
+type CallTreeHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewCallTreeHandler creates a new handler.
+func NewCallTreeHandler(s *store.Store, logger *slog.Logger) *CallTreeHandler {
+	return &CallTreeHandler{store: s, logger: logger}
+}
+
+// callTreeNode is one symbol in the outline. A symbol reached again is a reference to the
+// node where it was first expanded rather than a copy of its subtree.
+type callTreeNode struct {
+	Symbol   postgres.Symbol
+	EdgeType string // edge from the parent; empty for the root
+	Depth    int
+	Number   int // preorder number of an expanded node, referenced by later repeats
+	RefTo    int // number of the node this one repeats, 0 if expanded here
+	DepthCut bool
+	Children []*callTreeNode
+}
+
+// callTree is the outline below a root with what limited it.
+type callTree struct {
+	Root      *callTreeNode
+	Nodes     int
+	Truncated bool // stopped at the node cap
+	DepthCut  bool // some branches continue past max depth
+}
+
+// Handle renders the downstream call and data tree of a symbol as an indented outline.
+func (h *CallTreeHandler) Handle(ctx context.Context, params CallTreeParams) (string, error) {
+	if params.SymbolID == "" && params.SymbolName == "" {
+		return "", fmt.Errorf("symbol_id or symbol_name is required")
+	}
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = defaultCallTreeDepth
+	}
+	params.MaxDepth = min(params.MaxDepth, maxCallTreeDepth)
+	if params.MaxNodes <= 0 {
+		params.MaxNodes = defaultCallTreeNodes
+	}
+	params.MaxNodes = min(params.MaxNodes, maxCallTreeNodes)
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	root, err := resolveTraceSeed(ctx, h.store, project, params.SymbolID, params.SymbolName)
+	if err != nil {
+		return "", err
+	}
+
+	tree := buildCallTree(ctx, h.store, root, params.MaxDepth, params.MaxNodes)
+	mcp.RecordResults(ctx, tree.Nodes, tree.Nodes)
+	return formatCallTree(tree, params), nil
+}
+
+// buildCallTree expands the root depth-first along callTreeEdges. Each symbol's subtree
+// is expanded once; later occurrences refer back to it, which also stops cycles.
+func buildCallTree(ctx context.Context, g endpointGraph, root postgres.Symbol, maxDepth, maxNodes int) callTree {
+	tree := callTree{}
+	expanded := make(map[uuid.UUID]int) // symbol -> number of the node expanding it
+
+	var expand func(sym postgres.Symbol, edgeType string, depth int) *callTreeNode
+	expand = func(sym postgres.Symbol, edgeType string, depth int) *callTreeNode {
+		tree.Nodes++
+		node := &callTreeNode{Symbol: sym, EdgeType: edgeType, Depth: depth}
+		if n, ok := expanded[sym.ID]; ok {
+			node.RefTo = n
+			return node
+		}
+		node.Number = tree.Nodes
+		expanded[sym.ID] = node.Number
+
+		edges, err := g.GetOutgoingEdges(ctx, sym.ID)
+		if err != nil {
+			return node
+		}
+		for _, e := range edges {
+			if !callTreeEdges[e.EdgeType] || e.TargetID == sym.ID {
+				continue
+			}
+			if depth >= maxDepth {
+				node.DepthCut = true
+				tree.DepthCut = true
+				break
+			}
+			if tree.Nodes >= maxNodes {
+				tree.Truncated = true
+				break
+			}
+			child, err := g.GetSymbol(ctx, e.TargetID)
+			if err != nil {
+				continue
+			}
+			node.Children = append(node.Children, expand(child, e.EdgeType, depth+1))
+		}
+		return node
+	}
+
+	tree.Root = expand(root, "", 0)
+	return tree
+}
+
+// formatCallTree renders the tree as a nested list, two spaces per level, so clients that
+// fold Markdown lists can collapse any subtree.
+func formatCallTree(tree callTree, params CallTreeParams) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Call tree: %s**", tree.Root.Symbol.Name))
+	rb.AddLine(fmt.Sprintf("%d nodes, depth %d. `#n` numbers a node; `↩ see #n` marks a symbol whose subtree is shown at #n.",
+		tree.Nodes, params.MaxDepth))
+	rb.AddLine("")
+
+	shown := 0
+	var render func(n *callTreeNode) bool
+	render = func(n *callTreeNode) bool {
+		indent := strings.Repeat("  ", n.Depth)
+		label := fmt.Sprintf("%s `%s` [%s]", n.Symbol.Kind, n.Symbol.QualifiedName, n.Symbol.Language)
+		if n.EdgeType != "" {
+			label = n.EdgeType + " → " + label
+		}
+		var line string
+		if n.RefTo > 0 {
+			line = fmt.Sprintf("%s- %s ↩ see #%d", indent, label, n.RefTo)
+		} else {
+			line = fmt.Sprintf("%s- #%d %s", indent, n.Number, label)
+		}
+		if !rb.AddLine(line) {
+			return false
+		}
+		shown++
+		for _, c := range n.Children {
+			if !render(c) {
+				return false
+			}
+		}
+		if n.DepthCut && !rb.AddLine(indent+"  - …") {
+			return false
+		}
+		return true
+	}
+	render(tree.Root)
+
+	if tree.Truncated || tree.DepthCut {
+		rb.AddLine("")
+	}
+	if tree.Truncated {
+		rb.AddLine(fmt.Sprintf("_Tree truncated at %d nodes; raise max_nodes (up to %d) to see the rest._", params.MaxNodes, maxCallTreeNodes))
+	}
+	if tree.DepthCut {
+		rb.AddLine(fmt.Sprintf("_Branches marked … continue past depth %d; raise max_depth (up to %d) to follow them._", params.MaxDepth, maxCallTreeDepth))
+	}
+
+	return rb.Finalize(tree.Nodes, shown)
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// callTreeFixture is an endpoint whose controller calls two services that share a
+// repository procedure writing one table.
+func callTreeFixture() (postgres.Symbol, *fakeEndpointGraph) {
+	ep := postgres.Symbol{ID: uuid.New(), Name: "GetOrders", QualifiedName: "GET /api/orders", Kind: "endpoint", Language: "csharp"}
+	ctrl := postgres.Symbol{ID: uuid.New(), Name: "Get", QualifiedName: "Shop.OrdersController.Get", Kind: "method", Language: "csharp"}
+	orders := postgres.Symbol{ID: uuid.New(), Name: "List", QualifiedName: "Shop.OrderService.List", Kind: "method", Language: "csharp"}
+	audit := postgres.Symbol{ID: uuid.New(), Name: "Record", QualifiedName: "Shop.AuditService.Record", Kind: "method", Language: "csharp"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_Orders", QualifiedName: "dbo.usp_Orders", Kind: "procedure", Language: "tsql"}
+	table := postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table", Language: "tsql"}
+
+	g := newFakeEndpointGraph(ep, ctrl, orders, audit, proc, table)
+	g.link(ep, ctrl, "calls")
+	g.link(ctrl, orders, "calls")
+	g.link(ctrl, audit, "calls")
+	g.link(ctrl, ctrl, "calls") // recursion is not a child
+	g.link(orders, proc, "calls")
+	g.link(audit, proc, "calls")
+	g.link(proc, table, "writes_to")
+	g.link(orders, table, "contains") // not a call or data edge
+	return ep, g
+}
+
+func TestCallTree_IndentsLevelsAndMarksSharedSubtrees(t *testing.T) {
+	ep, g := callTreeFixture()
+	params := CallTreeParams{MaxDepth: 5, MaxNodes: 100}
+
+	tree := buildCallTree(context.Background(), g, ep, params.MaxDepth, params.MaxNodes)
+	if tree.Nodes != 7 || tree.Truncated || tree.DepthCut {
+		t.Fatalf("expected 7 nodes without truncation, got %+v", tree)
+	}
+
+	out := formatCallTree(tree, params)
+	want := []string{
+		"- #1 endpoint `GET /api/orders` [csharp]",
+		"  - #2 calls → method `Shop.OrdersController.Get` [csharp]",
+		"    - #3 calls → method `Shop.OrderService.List` [csharp]",
+		"      - #4 calls → procedure `dbo.usp_Orders` [tsql]",
+		"        - #5 writes_to → table `dbo.Orders` [tsql]",
+		"    - #6 calls → method `Shop.AuditService.Record` [csharp]",
+		"      - calls → procedure `dbo.usp_Orders` [tsql] ↩ see #4",
+	}
+	var lines []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(strings.TrimLeft(l, " "), "- ") {
+			lines = append(lines, l)
+		}
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected outline:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if strings.Count(out, "dbo.Orders") != 1 {
+		t.Errorf("expected the shared procedure's subtree to be rendered once:\n%s", out)
+	}
+}
+
+func TestCallTree_ReportsDepthAndNodeCaps(t *testing.T) {
+	ep, g := callTreeFixture()
+
+	deep := CallTreeParams{MaxDepth: 2, MaxNodes: 100}
+	tree := buildCallTree(context.Background(), g, ep, deep.MaxDepth, deep.MaxNodes)
+	if !tree.DepthCut || tree.Truncated {
+		t.Fatalf("expected a depth cut only, got %+v", tree)
+	}
+	out := formatCallTree(tree, deep)
+	if !strings.Contains(out, "      - …") || !strings.Contains(out, "raise max_depth") {
+		t.Errorf("expected depth-cut markers under level 2:\n%s", out)
+	}
+
+	small := CallTreeParams{MaxDepth: 5, MaxNodes: 3}
+	tree = buildCallTree(context.Background(), g, ep, small.MaxDepth, small.MaxNodes)
+	if tree.Nodes != 3 || !tree.Truncated {
+		t.Fatalf("expected truncation at 3 nodes, got %+v", tree)
+	}
+	if out := formatCallTree(tree, small); !strings.Contains(out, "Tree truncated at 3 nodes") {
+		t.Errorf("expected a truncation note:\n%s", out)
+	}
+}
@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	defaultConnectionDepth = 6
+	maxConnectionDepth     = 10
+	maxConnectionNodes     = 2000 // symbols each path search visits before giving up
+	maxUnresolvedLookups   = 50
+)
+
+// ExplainConnectionParams are the parameters for the explain_connection tool.
+type ExplainConnectionParams struct {
+	Project        string `json:"project"`
+	FromSymbolID   string `json:"from_symbol_id,omitempty"`
+	FromSymbolName string `json:"from_symbol_name,omitempty"`
+	ToSymbolID     string `json:"to_symbol_id,omitempty"`
+	ToSymbolName   string `json:"to_symbol_name,omitempty"`
+	MaxDepth       int    `json:"max_depth,omitempty"` // path search depth, default: 6, max: 10
+}
+
+// ExplainConnectionHandler implements the explain_connection MCP tool.
+type ExplainConnectionHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewExplainConnectionHandler creates a new handler.
+func NewExplainConnectionHandler(s *store.Store, logger *slog.Logger) *ExplainConnectionHandler {
+	return &ExplainConnectionHandler{store: s, logger: logger}
+}
+
+// connectionGraph is the subset of the store needed to explain a connection.
+type connectionGraph interface {
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	ListUnresolvedReferencesToName(ctx context.Context, arg postgres.ListUnresolvedReferencesToNameParams) ([]postgres.UnresolvedReference, error)
+}
+
+// connectionResult describes how (or whether) two symbols are connected.
+type connectionResult struct {
+	From, To    postgres.Symbol
+	DirectEdge  *postgres.SymbolEdge
+	PathLength  int  // hops along the connection, 0 when there is none
+	Reversed    bool // connection runs To → From
+	NearMiss    *postgres.UnresolvedReference
+	NearMissHop int // hops from the connected end to the near miss's source
+	NodeLimit   int // set when a path search stopped at this many symbols
+}
+
+// Handle explains the relationship between two symbols.
+func (h *ExplainConnectionHandler) Handle(ctx context.Context, params ExplainConnectionParams) (string, error) {
+	if params.FromSymbolID == "" && params.FromSymbolName == "" {
+		return "", fmt.Errorf("from_symbol_id or from_symbol_name is required")
+	}
+	if params.ToSymbolID == "" && params.ToSymbolName == "" {
+		return "", fmt.Errorf("to_symbol_id or to_symbol_name is required")
+	}
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = defaultConnectionDepth
+	}
+	if params.MaxDepth > maxConnectionDepth {
+		params.MaxDepth = maxConnectionDepth
+	}
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	from, err := h.resolveSymbol(ctx, project, params.FromSymbolID, params.FromSymbolName)
+	if err != nil {
+		return "", err
+	}
+	to, err := h.resolveSymbol(ctx, project, params.ToSymbolID, params.ToSymbolName)
+	if err != nil {
+		return "", err
+	}
+
+	res := explainConnection(ctx, h.store, project.ID, from, to, params.MaxDepth, maxConnectionNodes)
+	if res.DirectEdge != nil || res.PathLength > 0 || res.NearMiss != nil {
+		mcp.RecordResults(ctx, 1, 1)
+	} else {
+		mcp.RecordResults(ctx, 0, 0)
+	}
+	return formatConnection(res), nil
+}
+
+func (h *ExplainConnectionHandler) resolveSymbol(ctx context.Context, project postgres.Project, id, name string) (postgres.Symbol, error) {
+	if id != "" {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return postgres.Symbol{}, fmt.Errorf("invalid symbol id: %w", err)
+		}
+		sym, err := h.store.GetSymbol(ctx, uid)
+		if err != nil {
+			return postgres.Symbol{}, WrapSymbolError(err)
+		}
+		return sym, nil
+	}
+	return ResolveSymbolByName(ctx, h.store, project.Slug, name)
+}
+
+// explainConnection checks, in order: a direct edge in either direction, a directed path
+// within maxDepth hops in either direction, and finally the unresolved reference closest
+// to one symbol whose target names the other. Each path search visits at most maxNodes
+// symbols.
+func explainConnection(ctx context.Context, g connectionGraph, projectID uuid.UUID, from, to postgres.Symbol, maxDepth, maxNodes int) connectionResult {
+	res := connectionResult{From: from, To: to}
+
+	for _, dir := range []struct {
+		src, dst postgres.Symbol
+		reversed bool
+	}{{from, to, false}, {to, from, true}} {
+		edges, err := g.GetOutgoingEdges(ctx, dir.src.ID)
+		if err != nil {
+			continue
+		}
+		for _, e := range edges {
+			if e.TargetID == dir.dst.ID {
+				edge := e
+				res.DirectEdge = &edge
+				res.PathLength = 1
+				res.Reversed = dir.reversed
+				return res
+			}
+		}
+	}
+
+	distFrom, capped := reachable(ctx, g, from.ID, maxDepth, maxNodes)
+	if d, ok := distFrom[to.ID]; ok {
+		res.PathLength = d
+		return res
+	}
+	distTo, cappedTo := reachable(ctx, g, to.ID, maxDepth, maxNodes)
+	if capped || cappedTo {
+		res.NodeLimit = maxNodes
+	}
+	if d, ok := distTo[from.ID]; ok {
+		res.PathLength = d
+		res.Reversed = true
+		return res
+	}
+
+	best := -1
+	for _, dir := range []struct {
+		dist     map[uuid.UUID]int
+		target   postgres.Symbol
+		reversed bool
+	}{{distFrom, to, false}, {distTo, from, true}} {
+		sources := make([]uuid.UUID, 0, len(dir.dist))
+		for id := range dir.dist {
+			sources = append(sources, id)
+		}
+		refs, err := g.ListUnresolvedReferencesToName(ctx, postgres.ListUnresolvedReferencesToNameParams{
+			ProjectID:     projectID,
+			SourceIds:     sources,
+			Name:          dir.target.Name,
+			QualifiedName: dir.target.QualifiedName,
+			Lim:           maxUnresolvedLookups,
+		})
+		if err != nil {
+			continue
+		}
+		for _, ref := range refs {
+			hop, ok := dir.dist[ref.SourceID]
+			if !ok || (best >= 0 && hop >= best) {
+				continue
+			}
+			r := ref
+			best = hop
+			res.NearMiss = &r
+			res.NearMissHop = hop
+			res.Reversed = dir.reversed
+		}
+	}
+	return res
+}
+
+// reachable returns the hop count to every symbol reachable from start over outgoing
+// edges within maxDepth hops. start itself is at distance 0. The search stops once it has
+// reached maxNodes symbols, and then reports that it was capped.
+func reachable(ctx context.Context, g connectionGraph, start uuid.UUID, maxDepth, maxNodes int) (map[uuid.UUID]int, bool) {
+	dist := map[uuid.UUID]int{start: 0}
+	frontier := []uuid.UUID{start}
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		var next []uuid.UUID
+		for _, id := range frontier {
+			edges, err := g.GetOutgoingEdges(ctx, id)
+			if err != nil {
+				continue
+			}
+			for _, e := range edges {
+				if _, seen := dist[e.TargetID]; seen {
+					continue
+				}
+				if len(dist) >= maxNodes {
+					return dist, true
+				}
+				dist[e.TargetID] = depth
+				next = append(next, e.TargetID)
+			}
+		}
+		frontier = next
+	}
+	return dist, false
+}
+
+func formatConnection(res connectionResult) string {
+	src, dst := res.From, res.To
+	if res.Reversed {
+		src, dst = res.To, res.From
+	}
+
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Connection: %s → %s**", res.From.QualifiedName, res.To.QualifiedName))
+
+	switch {
+	case res.DirectEdge != nil:
+		rb.AddLine(fmt.Sprintf("Direct edge: `%s` —%s→ `%s`", src.QualifiedName, res.DirectEdge.EdgeType, dst.QualifiedName))
+		if len(res.DirectEdge.Metadata) > 0 && string(res.DirectEdge.Metadata) != "{}" {
+			rb.AddLine(fmt.Sprintf("Metadata: `%s`", res.DirectEdge.Metadata))
+		}
+	case res.PathLength > 0:
+		rb.AddLine(fmt.Sprintf("No direct edge, but a path exists: `%s` reaches `%s` in %d hops.", src.QualifiedName, dst.QualifiedName, res.PathLength))
+		rb.AddLine("")
+		rb.AddLine(fmt.Sprintf("Use `get_lineage` or `extract_subgraph` on `%s` to see the intermediate symbols.", src.ID))
+	case res.NearMiss != nil:
+		target := res.NearMiss.ToName
+		if res.NearMiss.ToQualified != "" {
+			target = res.NearMiss.ToQualified
+		}
+		rb.AddLine("No edge or path found.")
+		rb.AddLine(fmt.Sprintf("Nearest unresolved reference: a `%s` reference to `%s` (line %d) from symbol `%s`, %d hops from `%s`.",
+			res.NearMiss.ReferenceType, target, res.NearMiss.Line, res.NearMiss.SourceID, res.NearMissHop, src.QualifiedName))
+		rb.AddLine(fmt.Sprintf("Had it resolved to `%s`, the two symbols would be connected.", dst.QualifiedName))
+	default:
+		rb.AddLine("No edge, path, or unresolved reference connects these symbols.")
+	}
+	if res.DirectEdge == nil && res.PathLength == 0 && res.NodeLimit > 0 {
+		rb.AddLine("")
+		rb.AddLine(fmt.Sprintf("The path search stopped after visiting %d symbols, so a longer connection may have been missed. "+
+			"Lower max_depth to search a narrower neighbourhood exhaustively.", res.NodeLimit))
+	}
+
+	return rb.Finalize(1, 1)
+}
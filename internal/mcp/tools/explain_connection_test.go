@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeConnectionGraph adds an unresolved-reference store to the endpoint graph fake.
+type fakeConnectionGraph struct {
+	*fakeEndpointGraph
+	unresolved []postgres.UnresolvedReference
+}
+
+func (g *fakeConnectionGraph) ListUnresolvedReferencesToName(_ context.Context, arg postgres.ListUnresolvedReferencesToNameParams) ([]postgres.UnresolvedReference, error) {
+	var out []postgres.UnresolvedReference
+	for _, r := range g.unresolved {
+		if !slices.Contains(arg.SourceIds, r.SourceID) {
+			continue
+		}
+		if strings.EqualFold(r.ToName, arg.Name) || strings.EqualFold(r.ToQualified, arg.QualifiedName) {
+			out = append(out, r)
+		}
+	}
+	return out[:min(len(out), int(arg.Lim))], nil
+}
+
+func connectionFixture() (svc, repo, proc, orders postgres.Symbol, g *fakeConnectionGraph) {
+	svc = postgres.Symbol{ID: uuid.New(), Name: "OrderService", QualifiedName: "App.OrderService", Kind: "class"}
+	repo = postgres.Symbol{ID: uuid.New(), Name: "OrderRepository", QualifiedName: "App.OrderRepository", Kind: "class"}
+	proc = postgres.Symbol{ID: uuid.New(), Name: "usp_GetOrders", QualifiedName: "dbo.usp_GetOrders", Kind: "procedure"}
+	orders = postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table"}
+	g = &fakeConnectionGraph{fakeEndpointGraph: newFakeEndpointGraph(svc, repo, proc, orders)}
+	return
+}
+
+func TestExplainConnection_DirectEdge(t *testing.T) {
+	svc, repo, _, _, g := connectionFixture()
+	g.edges[repo.ID] = append(g.edges[repo.ID], postgres.SymbolEdge{
+		SourceID: repo.ID, TargetID: svc.ID, EdgeType: "calls", Metadata: []byte(`{"conditional":true}`),
+	})
+
+	res := explainConnection(context.Background(), g, uuid.New(), svc, repo, 6, maxConnectionNodes)
+	if res.DirectEdge == nil || !res.Reversed {
+		t.Fatalf("expected reversed direct edge, got %+v", res)
+	}
+	out := formatConnection(res)
+	if !strings.Contains(out, "Direct edge") || !strings.Contains(out, `"conditional":true`) {
+		t.Errorf("expected direct edge with metadata in output, got:\n%s", out)
+	}
+}
+
+func TestExplainConnection_Path(t *testing.T) {
+	svc, repo, proc, orders, g := connectionFixture()
+	g.link(svc, repo, "calls")
+	g.link(repo, proc, "calls")
+	g.link(proc, orders, "reads_from")
+
+	res := explainConnection(context.Background(), g, uuid.New(), svc, orders, 6, maxConnectionNodes)
+	if res.DirectEdge != nil || res.PathLength != 3 || res.Reversed {
+		t.Fatalf("expected forward path of 3 hops, got %+v", res)
+	}
+	if out := formatConnection(res); !strings.Contains(out, "in 3 hops") {
+		t.Errorf("expected path length in output, got:\n%s", out)
+	}
+
+	if res := explainConnection(context.Background(), g, uuid.New(), svc, orders, 2, maxConnectionNodes); res.PathLength != 0 {
+		t.Errorf("expected no path within 2 hops, got %d", res.PathLength)
+	}
+}
+
+func TestExplainConnection_UnresolvedNearMiss(t *testing.T) {
+	svc, repo, proc, _, g := connectionFixture()
+	g.link(svc, repo, "calls")
+	projectID := uuid.New()
+	g.unresolved = []postgres.UnresolvedReference{
+		{SourceID: uuid.New(), ToName: "usp_GetOrders", ReferenceType: "calls", Line: 3}, // unrelated caller
+		{SourceID: repo.ID, ToName: "usp_GetOrder", ToQualified: "dbo.usp_GetOrders", ReferenceType: "calls", Line: 42},
+		{SourceID: svc.ID, ToName: "Customers", ReferenceType: "uses_table", Line: 7},
+	}
+
+	res := explainConnection(context.Background(), g, projectID, svc, proc, 6, maxConnectionNodes)
+	if res.DirectEdge != nil || res.PathLength != 0 {
+		t.Fatalf("expected no edge or path, got %+v", res)
+	}
+	if res.NearMiss == nil || res.NearMiss.SourceID != repo.ID || res.NearMissHop != 1 {
+		t.Fatalf("expected near miss from OrderRepository at 1 hop, got %+v", res.NearMiss)
+	}
+	out := formatConnection(res)
+	if !strings.Contains(out, "Nearest unresolved reference") || !strings.Contains(out, "line 42") {
+		t.Errorf("expected near miss in output, got:\n%s", out)
+	}
+}
+
+func TestExplainConnection_NodeLimit(t *testing.T) {
+	svc, repo, _, orders, g := connectionFixture()
+	// svc fans out to many helpers before reaching repo, which leads on to orders
+	for i := range 20 {
+		helper := postgres.Symbol{ID: uuid.New(), Name: fmt.Sprintf("Helper%d", i), Kind: "class"}
+		g.symbols[helper.ID] = helper
+		g.link(svc, helper, "calls")
+	}
+	g.link(svc, repo, "calls")
+	g.link(repo, orders, "reads_from")
+
+	res := explainConnection(context.Background(), g, uuid.New(), svc, orders, 6, 10)
+	if res.PathLength != 0 || res.NodeLimit != 10 {
+		t.Fatalf("expected the capped search to find no path and report the limit, got %+v", res)
+	}
+	if out := formatConnection(res); !strings.Contains(out, "stopped after visiting 10 symbols") {
+		t.Errorf("expected the node limit in output, got:\n%s", out)
+	}
+
+	if res := explainConnection(context.Background(), g, uuid.New(), svc, orders, 6, maxConnectionNodes); res.PathLength != 2 || res.NodeLimit != 0 {
+		t.Errorf("expected a 2-hop path under the default limit, got %+v", res)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	pgvector_go "github.com/pgvector/pgvector-go"
@@ -16,6 +19,7 @@ import (
 	"github.com/maraichr/lattice/internal/mcp/session"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
 )
 
 // ExtractSubgraphParams are the parameters for the extract_subgraph tool.
@@ -31,6 +35,12 @@ type ExtractSubgraphParams struct {
 	MaxResponseTokens int      `json:"max_response_tokens,omitempty"`
 	SessionID         string   `json:"session_id,omitempty"`
 	DryRun            bool     `json:"dry_run,omitempty"`
+	GroupBy           string   `json:"group_by,omitempty"`   // "community" groups symbol cards by detected community
+	Output            string   `json:"output,omitempty"`     // "cards" (default), "edges" for a JSON node/edge list, or "dot" for Graphviz
+	MaxFanout         int      `json:"max_fanout,omitempty"` // output=dot: edges drawn from each node, default: 10
+	// Cursor continues the symbol cards from the next_cursor of a previous page of the
+	// same extraction; cards are paged by PageRank.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // ExtractSubgraphHandler implements the extract_subgraph MCP tool.
@@ -38,29 +48,40 @@ type ExtractSubgraphHandler struct {
 	store    *store.Store
 	session  *session.Manager
 	embedder embedding.Embedder
+	nav      *mcp.Navigator
 	logger   *slog.Logger
 }
 
 // NewExtractSubgraphHandler creates a new handler.
 func NewExtractSubgraphHandler(s *store.Store, sm *session.Manager, embedder embedding.Embedder, logger *slog.Logger) *ExtractSubgraphHandler {
-	return &ExtractSubgraphHandler{store: s, session: sm, embedder: embedder, logger: logger}
+	return &ExtractSubgraphHandler{store: s, session: sm, embedder: embedder, nav: mcp.NewNavigator(s.Queries), logger: logger}
+}
+
+// SetNavigationRules sets the rules that pick the navigation hints in responses.
+func (h *ExtractSubgraphHandler) SetNavigationRules(rules []mcp.NavigationRule) {
+	h.nav.SetRules(rules)
 }
 
 // Handle executes the subgraph extraction: seed discovery → BFS → boundary → trim → format.
 func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgraphParams) (string, error) {
 	// Apply defaults
-	if params.MaxDepth <= 0 {
-		params.MaxDepth = 2
-	}
-	if params.MaxNodes <= 0 {
-		params.MaxNodes = 50
-	}
+	params = withSubgraphDefaults(params)
 	if params.MaxResponseTokens <= 0 {
 		params.MaxResponseTokens = 4000
 	}
 
 	verbosity := mcp.ParseVerbosity(params.Verbosity)
 
+	cursorKey := subgraphCursorKey(params)
+	var cursor *mcp.PageCursor
+	if params.Cursor != "" {
+		c, err := mcp.DecodeCursor(params.Cursor, cursorKey)
+		if err != nil {
+			return "", fmt.Errorf("cursor: %w", err)
+		}
+		cursor = &c
+	}
+
 	// Load session
 	var sess *session.Session
 	if h.session != nil && params.SessionID != "" {
@@ -71,22 +92,17 @@ func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgr
 		}
 	}
 
-	// 1. Seed discovery
-	seeds, err := h.discoverSeeds(ctx, params)
+	// 1-3. Seed discovery, BFS expansion and the edges within the subgraph
+	seeds, subgraph, edges, err := h.extract(ctx, params)
 	if err != nil {
-		return "", fmt.Errorf("seed discovery: %w", err)
+		return "", err
 	}
 
 	if len(seeds) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
 		return "No symbols found matching the topic. Try a different search term or provide seed_symbols.", nil
 	}
 
-	// 2. BFS expansion
-	subgraph := h.expandBFS(ctx, seeds, params.MaxDepth, params.MaxNodes)
-
-	// 3. Collect edges within the subgraph
-	edges := h.collectEdges(ctx, subgraph)
-
 	// Dry run: return counts only
 	if params.DryRun {
 		return mcp.FormatDryRun(mcp.DryRunResult{
@@ -97,8 +113,31 @@ func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgr
 		}), nil
 	}
 
-	// 4. Token-aware trimming
-	subgraph = h.trimToTokenBudget(subgraph, params.MaxResponseTokens, verbosity)
+	// Edge-list mode: the raw graph as JSON for client-side rendering, bounded by max_nodes
+	if params.Output == "edges" {
+		mcp.RecordResults(ctx, len(subgraph), len(subgraph))
+		return formatSubgraphEdges(subgraph, edges)
+	}
+
+	// Graphviz mode: a DOT digraph ready for rendering, bounded by max_nodes and max_fanout
+	if params.Output == "dot" {
+		mcp.RecordResults(ctx, len(subgraph), len(subgraph))
+		fanout := params.MaxFanout
+		if fanout <= 0 {
+			fanout = defaultSubgraphFanout
+		}
+		return formatSubgraphDOT(params.Topic, subgraph, edges, dotOptions{fanout: fanout}), nil
+	}
+
+	// 4. Token-aware trimming of the page after the cursor, highest PageRank first
+	sortByPageRank(subgraph)
+	rest := subgraph[subgraphPageStart(subgraph, cursor):]
+	subgraph = h.trimToTokenBudget(rest, params.MaxResponseTokens, verbosity)
+	ordered := slices.Clone(subgraph)
+	groupByCommunity := params.GroupBy == "community"
+	if groupByCommunity {
+		sortByCommunity(subgraph)
+	}
 
 	// 5. Format response
 	rb := mcp.NewResponseBuilder(params.MaxResponseTokens)
@@ -109,7 +148,16 @@ func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgr
 
 	// Add symbol cards
 	returned := 0
+	lastCommunity := -1
 	for _, sym := range subgraph {
+		if groupByCommunity {
+			if c := getCommunityID(sym); c != lastCommunity {
+				lastCommunity = c
+				if !rb.AddLine("\n" + communityHeading(c, subgraph)) {
+					break
+				}
+			}
+		}
 		isCore := coreIDs[sym.ID]
 		if sess != nil && sess.IsSeen(sym.ID) && !isCore {
 			if !rb.AddSymbolStub(sym) {
@@ -125,7 +173,7 @@ func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgr
 
 	// Add edge summary
 	if len(edges) > 0 {
-		edgeSummary := formatEdgeSummary(edges, subgraph)
+		edgeSummary := formatEdgeSummary(edges, subgraph, h.edgeTypes(ctx, seeds[0].ProjectID))
 		rb.AddSection("Relationships", edgeSummary)
 	}
 
@@ -144,12 +192,93 @@ func (h *ExtractSubgraphHandler) Handle(ctx context.Context, params ExtractSubgr
 	}
 
 	// Navigation hints
-	nav := mcp.NewNavigator(h.store.Queries)
-	hints := nav.SuggestNextSteps("extract_subgraph", symbolsFromSubgraph(subgraph), sess)
+	hints := h.nav.SuggestNextSteps("extract_subgraph", symbolsFromSubgraph(subgraph), sess)
+	if next, ok := nextSubgraphCursor(ordered, subgraph[:returned], len(rest)); ok {
+		step := mcp.NavigationStep{Tool: "extract_subgraph", Params: map[string]string{"project": params.Project}}
+		if params.Topic != "" {
+			step.Params["topic"] = params.Topic
+		}
+		rb.SetNextCursor(next.Encode(cursorKey), step)
+	}
 
+	mcp.RecordResults(ctx, len(subgraph), returned)
 	return rb.FinalizeWithHints(len(subgraph), returned, hints), nil
 }
 
+// subgraphCursorKey binds extract_subgraph cursors to the parameters that shape the
+// subgraph.
+func subgraphCursorKey(params ExtractSubgraphParams) string {
+	kinds, seeds := slices.Clone(params.Kinds), slices.Clone(params.SeedSymbols)
+	slices.Sort(kinds)
+	slices.Sort(seeds)
+	return mcp.CursorKey("extract_subgraph", params.Project, params.Topic, strings.Join(kinds, ","),
+		strings.Join(seeds, ","), strconv.Itoa(params.MaxDepth), strconv.Itoa(params.MaxNodes),
+		strconv.FormatBool(params.CrossBoundary))
+}
+
+// sortByPageRank orders symbols by PageRank descending, ties broken by symbol ID, the
+// order extract_subgraph pages its cards in.
+func sortByPageRank(symbols []postgres.Symbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return mcp.CursorBefore(getPageRank(symbols[i]), symbols[i].ID, getPageRank(symbols[j]), symbols[j].ID)
+	})
+}
+
+// subgraphPageStart returns the index of the first symbol after cursor in PageRank
+// order, 0 without one.
+func subgraphPageStart(symbols []postgres.Symbol, cursor *mcp.PageCursor) int {
+	if cursor == nil {
+		return 0
+	}
+	return sort.Search(len(symbols), func(i int) bool {
+		return cursor.After(getPageRank(symbols[i]), symbols[i].ID)
+	})
+}
+
+// nextSubgraphCursor returns the cursor of the page after one whose cards, page in
+// PageRank order, were cut short or left out of remaining symbols. The next page
+// starts at the first card not shown; cards after it that were shown, when grouping
+// by community reordered them, are shown again.
+func nextSubgraphCursor(page, shown []postgres.Symbol, remaining int) (mcp.PageCursor, bool) {
+	seen := make(map[uuid.UUID]bool, len(shown))
+	for _, sym := range shown {
+		seen[sym.ID] = true
+	}
+	n := 0
+	for n < len(page) && seen[page[n].ID] {
+		n++
+	}
+	if n == 0 || n >= remaining {
+		return mcp.PageCursor{}, false
+	}
+	return mcp.PageCursor{Score: getPageRank(page[n-1]), ID: page[n-1].ID}, true
+}
+
+// withSubgraphDefaults fills in the default depth and node limits.
+func withSubgraphDefaults(params ExtractSubgraphParams) ExtractSubgraphParams {
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = 2
+	}
+	if params.MaxNodes <= 0 {
+		params.MaxNodes = 50
+	}
+	return params
+}
+
+// extract discovers the seeds for params, expands them breadth-first and collects the
+// edges between the symbols reached. The subgraph is empty when no seed is found.
+func (h *ExtractSubgraphHandler) extract(ctx context.Context, params ExtractSubgraphParams) ([]postgres.Symbol, []postgres.Symbol, []subgraphEdge, error) {
+	seeds, err := h.discoverSeeds(ctx, params)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("seed discovery: %w", err)
+	}
+	if len(seeds) == 0 {
+		return nil, nil, nil, nil
+	}
+	subgraph := expandSubgraph(ctx, h.store, seeds, params.MaxDepth, params.MaxNodes)
+	return seeds, subgraph, collectSubgraphEdges(ctx, h.store, subgraph), nil
+}
+
 func (h *ExtractSubgraphHandler) discoverSeeds(ctx context.Context, params ExtractSubgraphParams) ([]postgres.Symbol, error) {
 	var seeds []postgres.Symbol
 
@@ -257,7 +386,18 @@ func (h *ExtractSubgraphHandler) discoverSeeds(ctx context.Context, params Extra
 	return seeds, nil
 }
 
-func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres.Symbol, maxDepth, maxNodes int) []postgres.Symbol {
+// edgeTypes loads the project's reference type registry for labelling custom edge types.
+func (h *ExtractSubgraphHandler) edgeTypes(ctx context.Context, projectID uuid.UUID) *models.EdgeTypeRegistry {
+	project, err := h.store.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return models.NewEdgeTypeRegistry()
+	}
+	return models.EdgeTypeRegistryFromSettings(project.Settings)
+}
+
+// expandSubgraph walks edges in both directions from the seeds, breadth-first, until
+// maxDepth or maxNodes is reached. Edges of any type, builtin or custom, are followed.
+func expandSubgraph(ctx context.Context, g symbolGraph, seeds []postgres.Symbol, maxDepth, maxNodes int) []postgres.Symbol {
 	visited := make(map[uuid.UUID]bool)
 	var result []postgres.Symbol
 
@@ -281,7 +421,7 @@ func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres
 		}
 
 		// Get outgoing edges
-		outEdges, err := h.store.GetOutgoingEdges(ctx, entry.id)
+		outEdges, err := g.GetOutgoingEdges(ctx, entry.id)
 		if err != nil {
 			continue
 		}
@@ -289,7 +429,7 @@ func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres
 			if visited[edge.TargetID] || len(result) >= maxNodes {
 				continue
 			}
-			sym, err := h.store.GetSymbol(ctx, edge.TargetID)
+			sym, err := g.GetSymbol(ctx, edge.TargetID)
 			if err != nil {
 				continue
 			}
@@ -305,7 +445,7 @@ func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres
 		}
 
 		// Get incoming edges
-		inEdges, err := h.store.GetIncomingEdges(ctx, entry.id)
+		inEdges, err := g.GetIncomingEdges(ctx, entry.id)
 		if err != nil {
 			continue
 		}
@@ -313,7 +453,7 @@ func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres
 			if visited[edge.SourceID] || len(result) >= maxNodes {
 				continue
 			}
-			sym, err := h.store.GetSymbol(ctx, edge.SourceID)
+			sym, err := g.GetSymbol(ctx, edge.SourceID)
 			if err != nil {
 				continue
 			}
@@ -331,7 +471,7 @@ func (h *ExtractSubgraphHandler) expandBFS(ctx context.Context, seeds []postgres
 	return result
 }
 
-func (h *ExtractSubgraphHandler) collectEdges(ctx context.Context, symbols []postgres.Symbol) []subgraphEdge {
+func collectSubgraphEdges(ctx context.Context, g symbolGraph, symbols []postgres.Symbol) []subgraphEdge {
 	symbolSet := make(map[uuid.UUID]bool)
 	for _, s := range symbols {
 		symbolSet[s.ID] = true
@@ -341,7 +481,7 @@ func (h *ExtractSubgraphHandler) collectEdges(ctx context.Context, symbols []pos
 	seen := make(map[string]bool)
 
 	for _, sym := range symbols {
-		outEdges, err := h.store.GetOutgoingEdges(ctx, sym.ID)
+		outEdges, err := g.GetOutgoingEdges(ctx, sym.ID)
 		if err != nil {
 			continue
 		}
@@ -355,9 +495,10 @@ func (h *ExtractSubgraphHandler) collectEdges(ctx context.Context, symbols []pos
 			}
 			seen[key] = true
 			edges = append(edges, subgraphEdge{
-				SourceID: e.SourceID,
-				TargetID: e.TargetID,
-				EdgeType: e.EdgeType,
+				SourceID:   e.SourceID,
+				TargetID:   e.TargetID,
+				EdgeType:   e.EdgeType,
+				Confidence: extractEdgeConfidence(e.Metadata),
 			})
 		}
 	}
@@ -365,12 +506,8 @@ func (h *ExtractSubgraphHandler) collectEdges(ctx context.Context, symbols []pos
 	return edges
 }
 
+// trimToTokenBudget keeps the leading symbols whose cards fit within maxTokens.
 func (h *ExtractSubgraphHandler) trimToTokenBudget(symbols []postgres.Symbol, maxTokens int, verbosity mcp.Verbosity) []postgres.Symbol {
-	// Sort by PageRank descending to keep highest-value symbols
-	sort.Slice(symbols, func(i, j int) bool {
-		return getPageRank(symbols[i]) > getPageRank(symbols[j])
-	})
-
 	estimated := 0
 	tokensPerSymbol := symbolTokenEstimate(verbosity)
 	var result []postgres.Symbol
@@ -396,9 +533,70 @@ type bfsEntry struct {
 }
 
 type subgraphEdge struct {
-	SourceID uuid.UUID
-	TargetID uuid.UUID
-	EdgeType string
+	SourceID   uuid.UUID
+	TargetID   uuid.UUID
+	EdgeType   string
+	Confidence float64 // 0 = not set (treated as 1.0)
+}
+
+// subgraphJSON is the output=edges shape of extract_subgraph.
+type subgraphJSON struct {
+	Nodes []subgraphJSONNode `json:"nodes"`
+	Edges []subgraphJSONEdge `json:"edges"`
+}
+
+type subgraphJSONNode struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Language string `json:"language"`
+}
+
+type subgraphJSONEdge struct {
+	Source     string  `json:"source"`
+	Target     string  `json:"target"`
+	Type       string  `json:"type"`
+	Confidence float64 `json:"confidence"`
+}
+
+// formatSubgraphEdges renders the subgraph as a compact JSON node list plus the edges
+// between those nodes.
+func formatSubgraphEdges(symbols []postgres.Symbol, edges []subgraphEdge) (string, error) {
+	out := subgraphJSON{
+		Nodes: make([]subgraphJSONNode, 0, len(symbols)),
+		Edges: make([]subgraphJSONEdge, 0, len(edges)),
+	}
+	inGraph := make(map[uuid.UUID]bool, len(symbols))
+	for _, s := range symbols {
+		inGraph[s.ID] = true
+		out.Nodes = append(out.Nodes, subgraphJSONNode{
+			ID:       s.ID.String(),
+			Name:     s.Name,
+			Kind:     s.Kind,
+			Language: s.Language,
+		})
+	}
+	for _, e := range edges {
+		if !inGraph[e.SourceID] || !inGraph[e.TargetID] {
+			continue
+		}
+		confidence := e.Confidence
+		if confidence == 0 {
+			confidence = 1.0
+		}
+		out.Edges = append(out.Edges, subgraphJSONEdge{
+			Source:     e.SourceID.String(),
+			Target:     e.TargetID.String(),
+			Type:       e.EdgeType,
+			Confidence: confidence,
+		})
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("marshal subgraph: %w", err)
+	}
+	return string(data), nil
 }
 
 func isLowValue(sym postgres.Symbol) bool {
@@ -420,6 +618,47 @@ func getPageRank(sym postgres.Symbol) float64 {
 	return 0
 }
 
+// getCommunityID returns the Louvain community assigned by analytics (0 = unassigned).
+func getCommunityID(sym postgres.Symbol) int {
+	if len(sym.Metadata) == 0 {
+		return 0
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(sym.Metadata, &meta); err != nil {
+		return 0
+	}
+	if c, ok := meta["community_id"].(float64); ok {
+		return int(c)
+	}
+	return 0
+}
+
+// sortByCommunity orders symbols by community (unassigned last), keeping the
+// PageRank order within each community.
+func sortByCommunity(symbols []postgres.Symbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		ci, cj := getCommunityID(symbols[i]), getCommunityID(symbols[j])
+		if (ci == 0) != (cj == 0) {
+			return cj == 0
+		}
+		return ci < cj
+	})
+}
+
+// communityHeading renders the group heading for a community within the subgraph.
+func communityHeading(community int, symbols []postgres.Symbol) string {
+	count := 0
+	for _, s := range symbols {
+		if getCommunityID(s) == community {
+			count++
+		}
+	}
+	if community == 0 {
+		return fmt.Sprintf("### Unassigned (%d)", count)
+	}
+	return fmt.Sprintf("### Community %d (%d)", community, count)
+}
+
 func symbolTokenEstimate(verbosity mcp.Verbosity) int {
 	switch verbosity {
 	case mcp.VerbositySummary:
@@ -444,7 +683,7 @@ func identifyCore(seeds []postgres.Symbol, subgraph []postgres.Symbol) map[uuid.
 	return core
 }
 
-func formatEdgeSummary(edges []subgraphEdge, symbols []postgres.Symbol) string {
+func formatEdgeSummary(edges []subgraphEdge, symbols []postgres.Symbol, reg *models.EdgeTypeRegistry) string {
 	nameMap := make(map[uuid.UUID]string)
 	for _, s := range symbols {
 		nameMap[s.ID] = s.Name
@@ -458,6 +697,10 @@ func formatEdgeSummary(edges []subgraphEdge, symbols []postgres.Symbol) string {
 
 	var summary string
 	for edgeType, count := range byType {
+		if t, ok := reg.Lookup(edgeType); ok && t.Custom {
+			summary += fmt.Sprintf("- %s (%s, %s): %d edges\n", t.Label, edgeType, t.Category, count)
+			continue
+		}
 		summary += fmt.Sprintf("- %s: %d edges\n", edgeType, count)
 	}
 
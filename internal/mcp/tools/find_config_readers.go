@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// FindConfigReadersParams are the parameters for the find_config_readers tool.
+type FindConfigReadersParams struct {
+	Project string `json:"project"`
+	Key     string `json:"key"` // e.g. DATABASE_URL, Smtp:Host, mail.host
+}
+
+// FindConfigReadersHandler implements the find_config_readers MCP tool.
+type FindConfigReadersHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewFindConfigReadersHandler creates a new handler.
+func NewFindConfigReadersHandler(s *store.Store, logger *slog.Logger) *FindConfigReadersHandler {
+	return &FindConfigReadersHandler{store: s, logger: logger}
+}
+
+// configReaderGraph is the subset of the store needed to find a config key's readers.
+type configReaderGraph interface {
+	symbolGraph
+	ListSymbolsByNames(ctx context.Context, arg postgres.ListSymbolsByNamesParams) ([]postgres.Symbol, error)
+}
+
+// configReaders are the symbols reading one configuration key.
+type configReaders struct {
+	Keys    int // config_key symbols found, one per file reading the key
+	Readers []postgres.Symbol
+}
+
+// Handle lists the symbols that read a configuration key.
+func (h *FindConfigReadersHandler) Handle(ctx context.Context, params FindConfigReadersParams) (string, error) {
+	if params.Key == "" {
+		return "", fmt.Errorf("key is required")
+	}
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	result, err := collectConfigReaders(ctx, h.store, project.ID, params.Key)
+	if err != nil {
+		return "", err
+	}
+
+	mcp.RecordResults(ctx, len(result.Readers), len(result.Readers))
+	return formatConfigReaders(params.Key, result), nil
+}
+
+// collectConfigReaders finds the config_key symbols for key, which parsers emit in every
+// file reading it, and the sources of their incoming reads_config edges.
+func collectConfigReaders(ctx context.Context, g configReaderGraph, projectID uuid.UUID, key string) (configReaders, error) {
+	var result configReaders
+
+	symbols, err := g.ListSymbolsByNames(ctx, postgres.ListSymbolsByNamesParams{
+		ProjectID: projectID,
+		Column2:   []string{key},
+	})
+	if err != nil {
+		return result, fmt.Errorf("find config key: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, sym := range symbols {
+		if sym.Kind != "config_key" {
+			continue
+		}
+		result.Keys++
+
+		edges, err := g.GetIncomingEdges(ctx, sym.ID)
+		if err != nil {
+			continue
+		}
+		for _, e := range edges {
+			if e.EdgeType != "reads_config" || seen[e.SourceID] {
+				continue
+			}
+			seen[e.SourceID] = true
+			reader, err := g.GetSymbol(ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			result.Readers = append(result.Readers, reader)
+		}
+	}
+
+	sort.Slice(result.Readers, func(i, j int) bool {
+		return result.Readers[i].QualifiedName < result.Readers[j].QualifiedName
+	})
+	return result, nil
+}
+
+func formatConfigReaders(key string, result configReaders) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Readers of config key `%s`** (%d)", key, len(result.Readers)))
+
+	if result.Keys == 0 {
+		rb.AddLine("No code reads this key. Keys are matched exactly, as written in the code (e.g. `Smtp:Host`, `mail.host`).")
+		return rb.Finalize(0, 0)
+	}
+	if len(result.Readers) == 0 {
+		rb.AddLine("The key is read only outside any function, method or class (e.g. module-level code).")
+		return rb.Finalize(0, 0)
+	}
+
+	shown := 0
+	for _, r := range result.Readers {
+		if !rb.AddLine(fmt.Sprintf("- %s `%s` [%s] | ID: `%s`", r.Kind, r.QualifiedName, r.Language, r.ID)) {
+			break
+		}
+		shown++
+	}
+
+	return rb.Finalize(len(result.Readers), shown)
+}
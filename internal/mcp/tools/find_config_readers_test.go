@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeConfigGraph adds name lookup to the impact graph fake.
+type fakeConfigGraph struct {
+	*fakeImpactGraph
+}
+
+func (g *fakeConfigGraph) ListSymbolsByNames(_ context.Context, arg postgres.ListSymbolsByNamesParams) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, s := range g.symbols {
+		for _, name := range arg.Column2 {
+			if s.Name == name {
+				out = append(out, s)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestCollectConfigReaders_ListsReadersAcrossFiles(t *testing.T) {
+	// Each file reading the key carries its own config_key symbol.
+	tsKey := postgres.Symbol{ID: uuid.New(), Name: "DATABASE_URL", QualifiedName: "config:DATABASE_URL", Kind: "config_key", Language: "typescript"}
+	csKey := postgres.Symbol{ID: uuid.New(), Name: "DATABASE_URL", QualifiedName: "config:DATABASE_URL", Kind: "config_key", Language: "csharp"}
+	otherKey := postgres.Symbol{ID: uuid.New(), Name: "REDIS_URL", QualifiedName: "config:REDIS_URL", Kind: "config_key", Language: "typescript"}
+	// A same-named symbol that is not a config key is ignored.
+	constant := postgres.Symbol{ID: uuid.New(), Name: "DATABASE_URL", QualifiedName: "settings.DATABASE_URL", Kind: "constant", Language: "typescript"}
+
+	connect := postgres.Symbol{ID: uuid.New(), Name: "connect", QualifiedName: "connect", Kind: "function", Language: "typescript"}
+	reconnect := postgres.Symbol{ID: uuid.New(), Name: "reconnect", QualifiedName: "reconnect", Kind: "function", Language: "typescript"}
+	startup := postgres.Symbol{ID: uuid.New(), Name: "Startup", QualifiedName: "Shop.Startup", Kind: "class", Language: "csharp"}
+	cache := postgres.Symbol{ID: uuid.New(), Name: "cache", QualifiedName: "cache", Kind: "function", Language: "typescript"}
+	caller := postgres.Symbol{ID: uuid.New(), Name: "main", QualifiedName: "main", Kind: "function", Language: "typescript"}
+
+	g := &fakeConfigGraph{&fakeImpactGraph{newFakeEndpointGraph(tsKey, csKey, otherKey, constant, connect, reconnect, startup, cache, caller)}}
+	g.link(connect, tsKey, "reads_config")
+	g.link(reconnect, tsKey, "reads_config")
+	g.link(startup, csKey, "reads_config")
+	g.link(cache, otherKey, "reads_config")
+	g.link(caller, tsKey, "calls")
+	g.link(caller, constant, "reads_config")
+
+	result, err := collectConfigReaders(context.Background(), g, uuid.New(), "DATABASE_URL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Keys != 2 {
+		t.Errorf("expected 2 config_key symbols, got %d", result.Keys)
+	}
+	var got []string
+	for _, r := range result.Readers {
+		got = append(got, r.QualifiedName)
+	}
+	if want := "Shop.Startup,connect,reconnect"; strings.Join(got, ",") != want {
+		t.Errorf("expected readers %s, got %v", want, got)
+	}
+
+	out := formatConfigReaders("DATABASE_URL", result)
+	for _, want := range []string{"`DATABASE_URL`** (3)", "class `Shop.Startup` [csharp]", "function `connect` [typescript]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatConfigReaders_UnknownKey(t *testing.T) {
+	out := formatConfigReaders("NOPE", configReaders{})
+	if !strings.Contains(out, "No code reads this key") {
+		t.Errorf("expected a no-readers message, got:\n%s", out)
+	}
+}
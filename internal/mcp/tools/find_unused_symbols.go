@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	defaultUnusedLimit = 50
+	maxUnusedLimit     = 200
+)
+
+// FindUnusedSymbolsParams are the parameters for the find_unused_symbols tool.
+type FindUnusedSymbolsParams struct {
+	Project   string   `json:"project"`
+	Kinds     []string `json:"kinds,omitempty"`
+	Languages []string `json:"languages,omitempty"`
+	// IncludeLowConfidence also lists symbols reached only by cross-language links below
+	// the analytics confidence threshold.
+	IncludeLowConfidence bool   `json:"include_low_confidence,omitempty"`
+	Limit                int32  `json:"limit,omitempty"` // default: 50, max: 200
+	Verbosity            string `json:"verbosity,omitempty"`
+	MaxResponseTokens    int    `json:"max_response_tokens,omitempty"`
+}
+
+// unusedStore is the subset of the store find_unused_symbols reads.
+type unusedStore interface {
+	GetProject(ctx context.Context, slug string) (postgres.Project, error)
+	ListUnreferencedSymbols(ctx context.Context, arg postgres.ListUnreferencedSymbolsParams) ([]postgres.Symbol, error)
+}
+
+// FindUnusedSymbolsHandler implements the find_unused_symbols MCP tool.
+type FindUnusedSymbolsHandler struct {
+	store  unusedStore
+	logger *slog.Logger
+}
+
+// NewFindUnusedSymbolsHandler creates a new handler.
+func NewFindUnusedSymbolsHandler(s *store.Store, logger *slog.Logger) *FindUnusedSymbolsHandler {
+	return &FindUnusedSymbolsHandler{store: s, logger: logger}
+}
+
+// Handle lists the symbols analytics flagged as referenced by nothing (see
+// analytics.ComputeUnreferenced), by kind and then name.
+func (h *FindUnusedSymbolsHandler) Handle(ctx context.Context, params FindUnusedSymbolsParams) (string, error) {
+	if params.Limit <= 0 {
+		params.Limit = defaultUnusedLimit
+	}
+	params.Limit = min(params.Limit, maxUnusedLimit)
+	if params.MaxResponseTokens <= 0 {
+		params.MaxResponseTokens = 4000
+	}
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	statuses := []string{"unreferenced"}
+	if params.IncludeLowConfidence {
+		statuses = append(statuses, "low_confidence_bridge")
+	}
+	kinds := params.Kinds
+	if kinds == nil {
+		kinds = []string{}
+	}
+	languages := params.Languages
+	if languages == nil {
+		languages = []string{}
+	}
+	symbols, err := h.store.ListUnreferencedSymbols(ctx, postgres.ListUnreferencedSymbolsParams{
+		ProjectID: project.ID,
+		Statuses:  statuses,
+		Kinds:     kinds,
+		Languages: languages,
+		Lim:       params.Limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("list unreferenced symbols: %w", err)
+	}
+
+	if len(symbols) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return "No unreferenced symbols found. Analytics flags them after each ingest; check get_project_analytics scope=unreferenced for the last run.", nil
+	}
+
+	rb := mcp.NewResponseBuilder(params.MaxResponseTokens)
+	rb.AddHeader(fmt.Sprintf("**Unreferenced symbols: %s** (%d)", project.Name, len(symbols)))
+	rb.AddLine("No symbol in the graph references these. Dynamic calls, reflection and callers outside the indexed sources are not seen, so confirm before deleting.")
+	if params.IncludeLowConfidence {
+		rb.AddLine("Symbols reached only by low-confidence cross-language links are included and marked.")
+	}
+	rb.AddLine("")
+
+	verbosity := mcp.ParseVerbosity(params.Verbosity)
+	returned := 0
+	for _, sym := range symbols {
+		if params.IncludeLowConfidence && referenceStatus(sym) == "low_confidence_bridge" {
+			if !rb.AddLine(fmt.Sprintf("_%s: only low-confidence cross-language references_", sym.QualifiedName)) {
+				break
+			}
+		}
+		if !rb.AddSymbolCard(sym, verbosity, nil) {
+			break
+		}
+		returned++
+	}
+
+	mcp.RecordResults(ctx, len(symbols), returned)
+	return rb.Finalize(len(symbols), returned), nil
+}
+
+// referenceStatus reads the reference_status analytics stored in a symbol's metadata.
+func referenceStatus(sym postgres.Symbol) string {
+	var meta struct {
+		ReferenceStatus string `json:"reference_status"`
+	}
+	if len(sym.Metadata) > 0 {
+		_ = json.Unmarshal(sym.Metadata, &meta)
+	}
+	return meta.ReferenceStatus
+}
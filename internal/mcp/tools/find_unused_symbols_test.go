@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+type fakeUnusedStore struct {
+	symbols []postgres.Symbol
+}
+
+func (f *fakeUnusedStore) GetProject(_ context.Context, slug string) (postgres.Project, error) {
+	return postgres.Project{ID: uuid.New(), Slug: slug, Name: slug}, nil
+}
+
+func (f *fakeUnusedStore) ListUnreferencedSymbols(_ context.Context, arg postgres.ListUnreferencedSymbolsParams) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, s := range f.symbols {
+		if !slices.Contains(arg.Statuses, referenceStatus(s)) {
+			continue
+		}
+		if len(arg.Kinds) > 0 && !slices.Contains(arg.Kinds, s.Kind) {
+			continue
+		}
+		if len(arg.Languages) > 0 && !slices.Contains(arg.Languages, s.Language) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func TestFindUnusedSymbols(t *testing.T) {
+	sym := func(name, kind, lang, status string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: name, QualifiedName: name, Kind: kind, Language: lang,
+			Metadata: []byte(`{"reference_status": "` + status + `"}`)}
+	}
+	s := &fakeUnusedStore{symbols: []postgres.Symbol{
+		sym("dbo.OrdersArchive", "table", "tsql", "unreferenced"),
+		sym("dbo.usp_Legacy", "procedure", "tsql", "unreferenced"),
+		sym("dbo.usp_GetOrder", "procedure", "tsql", "low_confidence_bridge"),
+		sym("Shop.OldMailer", "class", "csharp", "unreferenced"),
+		sym("dbo.Orders", "table", "tsql", "referenced"),
+	}}
+	h := &FindUnusedSymbolsHandler{store: s}
+
+	out, err := h.Handle(context.Background(), FindUnusedSymbolsParams{Project: "shop", Languages: []string{"tsql"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"dbo.OrdersArchive", "dbo.usp_Legacy"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in the results, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"dbo.usp_GetOrder", "Shop.OldMailer", "dbo.Orders`"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected %s to be left out, got:\n%s", unwanted, out)
+		}
+	}
+
+	out, err = h.Handle(context.Background(), FindUnusedSymbolsParams{Project: "shop", Kinds: []string{"procedure"}, IncludeLowConfidence: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "_dbo.usp_GetOrder: only low-confidence cross-language references_") || strings.Contains(out, "dbo.OrdersArchive") {
+		t.Errorf("expected the weakly bridged procedure marked and no tables, got:\n%s", out)
+	}
+}
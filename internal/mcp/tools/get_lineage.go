@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/mcp"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
@@ -21,17 +22,35 @@ type GetLineageParams struct {
 	SymbolName string `json:"symbol_name,omitempty"`
 	Direction  string `json:"direction,omitempty"` // upstream, downstream, both
 	MaxDepth   int    `json:"max_depth,omitempty"`
+	// IncludePaths adds the edge paths from the seed to each result, with edge types and
+	// confidences; MaxPaths caps the paths listed per result (default: 3, max: 10).
+	IncludePaths bool `json:"include_paths,omitempty"`
+	MaxPaths     int  `json:"max_paths,omitempty"`
+	// IncludeInferred also follows related_to edges from columns to the keys they seem to
+	// reference, inferred from their names and types where no foreign key is declared.
+	IncludeInferred bool `json:"include_inferred,omitempty"`
 }
 
+const (
+	defaultLineagePaths = 3
+	maxLineagePaths     = 10
+)
+
 // GetLineageHandler implements the get_lineage MCP tool.
 type GetLineageHandler struct {
-	store  *store.Store
-	logger *slog.Logger
+	store      *store.Store
+	directions lineage.EdgeDirections
+	logger     *slog.Logger
 }
 
-// NewGetLineageHandler creates a new handler.
+// NewGetLineageHandler creates a new handler using the default edge directions.
 func NewGetLineageHandler(s *store.Store, logger *slog.Logger) *GetLineageHandler {
-	return &GetLineageHandler{store: s, logger: logger}
+	return &GetLineageHandler{store: s, directions: lineage.DefaultEdgeDirections(), logger: logger}
+}
+
+// SetEdgeDirections sets which way each edge type points in lineage.
+func (h *GetLineageHandler) SetEdgeDirections(d lineage.EdgeDirections) {
+	h.directions = d
 }
 
 // Handle traces upstream or downstream lineage from a symbol.
@@ -45,6 +64,14 @@ func (h *GetLineageHandler) Handle(ctx context.Context, params GetLineageParams)
 	if params.Direction == "" {
 		params.Direction = "both"
 	}
+	switch {
+	case !params.IncludePaths:
+		params.MaxPaths = 0
+	case params.MaxPaths <= 0:
+		params.MaxPaths = defaultLineagePaths
+	default:
+		params.MaxPaths = min(params.MaxPaths, maxLineagePaths)
+	}
 
 	project, err := h.store.GetProject(ctx, params.Project)
 	if err != nil {
@@ -60,77 +87,16 @@ func (h *GetLineageHandler) Handle(ctx context.Context, params GetLineageParams)
 		return "", err
 	}
 
-	// BFS lineage traversal
-	type lineageNode struct {
-		Symbol     postgres.Symbol
-		Depth      int
-		Via        string  // edge type that led here
-		Confidence float64 // from edge metadata, 0 = unknown
+	directions := h.directions
+	if params.IncludeInferred {
+		directions = directions.WithInferred()
 	}
-
-	visited := map[uuid.UUID]bool{seed.ID: true}
 	var upstream, downstream []lineageNode
-
-	// Upstream: follow incoming edges
 	if params.Direction == "upstream" || params.Direction == "both" {
-		queue := []lineageNode{{Symbol: seed, Depth: 0}}
-		for len(queue) > 0 {
-			cur := queue[0]
-			queue = queue[1:]
-			if cur.Depth >= params.MaxDepth {
-				continue
-			}
-			edges, err := h.store.GetIncomingEdges(ctx, cur.Symbol.ID)
-			if err != nil {
-				continue
-			}
-			for _, e := range edges {
-				if visited[e.SourceID] {
-					continue
-				}
-				visited[e.SourceID] = true
-				sym, err := h.store.GetSymbol(ctx, e.SourceID)
-				if err != nil {
-					continue
-				}
-				node := lineageNode{Symbol: sym, Depth: cur.Depth + 1, Via: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata)}
-				upstream = append(upstream, node)
-				queue = append(queue, node)
-			}
-		}
+		upstream = collectLineage(ctx, h.store, directions, seed, true, params.MaxDepth, params.MaxPaths)
 	}
-
-	// Downstream: follow outgoing edges
 	if params.Direction == "downstream" || params.Direction == "both" {
-		// Reset visited for downstream except seed
-		if params.Direction == "both" {
-			visited = map[uuid.UUID]bool{seed.ID: true}
-		}
-		queue := []lineageNode{{Symbol: seed, Depth: 0}}
-		for len(queue) > 0 {
-			cur := queue[0]
-			queue = queue[1:]
-			if cur.Depth >= params.MaxDepth {
-				continue
-			}
-			edges, err := h.store.GetOutgoingEdges(ctx, cur.Symbol.ID)
-			if err != nil {
-				continue
-			}
-			for _, e := range edges {
-				if visited[e.TargetID] {
-					continue
-				}
-				visited[e.TargetID] = true
-				sym, err := h.store.GetSymbol(ctx, e.TargetID)
-				if err != nil {
-					continue
-				}
-				node := lineageNode{Symbol: sym, Depth: cur.Depth + 1, Via: e.EdgeType, Confidence: extractEdgeConfidence(e.Metadata)}
-				downstream = append(downstream, node)
-				queue = append(queue, node)
-			}
-		}
+		downstream = collectLineage(ctx, h.store, directions, seed, false, params.MaxDepth, params.MaxPaths)
 	}
 
 	// Format response
@@ -139,27 +105,13 @@ func (h *GetLineageHandler) Handle(ctx context.Context, params GetLineageParams)
 
 	if len(upstream) > 0 {
 		rb.AddLine("### Upstream (data sources / callers)")
-		for _, n := range upstream {
-			indent := strings.Repeat("  ", n.Depth)
-			confStr := ""
-			if n.Confidence > 0 {
-				confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
-			}
-			rb.AddLine(fmt.Sprintf("%s- %s `%s` [%s] (via %s%s)", indent, n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.Via, confStr))
-		}
+		formatLineageNodes(rb, seed, upstream, params.IncludePaths)
 		rb.AddLine("")
 	}
 
 	if len(downstream) > 0 {
 		rb.AddLine("### Downstream (consumers / dependents)")
-		for _, n := range downstream {
-			indent := strings.Repeat("  ", n.Depth)
-			confStr := ""
-			if n.Confidence > 0 {
-				confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
-			}
-			rb.AddLine(fmt.Sprintf("%s- %s `%s` [%s] (via %s%s)", indent, n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.Via, confStr))
-		}
+		formatLineageNodes(rb, seed, downstream, params.IncludePaths)
 		rb.AddLine("")
 	}
 
@@ -167,9 +119,147 @@ func (h *GetLineageHandler) Handle(ctx context.Context, params GetLineageParams)
 		rb.AddLine("No lineage connections found for this symbol.")
 	}
 
+	mcp.RecordResults(ctx, len(upstream)+len(downstream), len(upstream)+len(downstream))
 	return rb.Finalize(len(upstream)+len(downstream), len(upstream)+len(downstream)), nil
 }
 
+// lineageNode is a symbol reached from the seed.
+type lineageNode struct {
+	Symbol     postgres.Symbol
+	Depth      int
+	Via        string         // edge type that led here
+	Confidence float64        // from edge metadata, 0 = unknown
+	Paths      [][]lineageHop // shortest edge paths from the seed, when requested
+}
+
+// lineageHop is one edge of a provenance path, ending at Symbol.
+type lineageHop struct {
+	Symbol     postgres.Symbol
+	EdgeType   string
+	Confidence float64
+	Outward    bool // the edge points towards Symbol
+}
+
+// lineagePred is an edge reaching a symbol from one a level closer to the seed.
+type lineagePred struct {
+	from uuid.UUID
+	hop  lineageHop
+}
+
+// collectLineage walks breadth-first from seed upstream or downstream up to maxDepth,
+// following each edge type the way directions points it. With maxPaths > 0 every result
+// also carries up to maxPaths of the shortest edge paths leading to it from the seed.
+func collectLineage(ctx context.Context, g symbolGraph, directions lineage.EdgeDirections, seed postgres.Symbol, upstream bool, maxDepth, maxPaths int) []lineageNode {
+	depth := map[uuid.UUID]int{seed.ID: 0}
+	preds := make(map[uuid.UUID][]lineagePred)
+	index := make(map[uuid.UUID]int) // position in nodes
+	var nodes []lineageNode
+
+	queue := []lineageNode{{Symbol: seed, Depth: 0}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.Depth >= maxDepth {
+			continue
+		}
+		hops, err := directions.Neighbors(ctx, g, cur.Symbol.ID, upstream)
+		if err != nil {
+			continue
+		}
+		for _, h := range hops {
+			next, e := h.ID, h.Edge
+			conf := extractEdgeConfidence(e.Metadata)
+			if d, seen := depth[next]; seen {
+				// Another shortest path to a symbol already queued at this level.
+				if maxPaths > 0 && d == cur.Depth+1 {
+					if i, ok := index[next]; ok {
+						preds[next] = append(preds[next], lineagePred{from: cur.Symbol.ID, hop: lineageHop{Symbol: nodes[i].Symbol, EdgeType: e.EdgeType, Confidence: conf, Outward: h.Outward}})
+					}
+				}
+				continue
+			}
+			depth[next] = cur.Depth + 1
+			sym, err := g.GetSymbol(ctx, next)
+			if err != nil {
+				continue
+			}
+			node := lineageNode{Symbol: sym, Depth: cur.Depth + 1, Via: e.EdgeType, Confidence: conf}
+			preds[next] = append(preds[next], lineagePred{from: cur.Symbol.ID, hop: lineageHop{Symbol: sym, EdgeType: e.EdgeType, Confidence: conf, Outward: h.Outward}})
+			index[next] = len(nodes)
+			nodes = append(nodes, node)
+			queue = append(queue, node)
+		}
+	}
+
+	if maxPaths > 0 {
+		memo := map[uuid.UUID][][]lineageHop{seed.ID: {nil}}
+		for i := range nodes {
+			nodes[i].Paths = lineagePaths(nodes[i].Symbol.ID, preds, memo, maxPaths)
+		}
+	}
+	return nodes
+}
+
+// lineagePaths returns up to maxPaths shortest paths from the seed to id, each listing
+// its hops from the seed outwards.
+func lineagePaths(id uuid.UUID, preds map[uuid.UUID][]lineagePred, memo map[uuid.UUID][][]lineageHop, maxPaths int) [][]lineageHop {
+	if paths, ok := memo[id]; ok {
+		return paths
+	}
+	var paths [][]lineageHop
+	for _, p := range preds[id] {
+		for _, prefix := range lineagePaths(p.from, preds, memo, maxPaths) {
+			if len(paths) == maxPaths {
+				break
+			}
+			path := make([]lineageHop, len(prefix), len(prefix)+1)
+			copy(path, prefix)
+			paths = append(paths, append(path, p.hop))
+		}
+	}
+	memo[id] = paths
+	return paths
+}
+
+// formatLineageNodes lists lineage results indented by depth, each followed by its
+// provenance paths when includePaths is set.
+func formatLineageNodes(rb *mcp.ResponseBuilder, seed postgres.Symbol, nodes []lineageNode, includePaths bool) {
+	for _, n := range nodes {
+		indent := strings.Repeat("  ", n.Depth)
+		confStr := ""
+		if n.Confidence > 0 {
+			confStr = fmt.Sprintf(", confidence: %.2f", n.Confidence)
+		}
+		rb.AddLine(fmt.Sprintf("%s- %s `%s` [%s] (via %s%s)", indent, n.Symbol.Kind, n.Symbol.Name, n.Symbol.Language, n.Via, confStr))
+		if !includePaths {
+			continue
+		}
+		for _, path := range n.Paths {
+			rb.AddLine(fmt.Sprintf("%s  path: %s", indent, formatLineagePath(seed, path)))
+		}
+	}
+}
+
+// formatLineagePath renders a path as "Seed -[calls 0.90]→ A -[writes_to]→ B", with
+// arrows pointing along the edges, so an edge taken against its direction reads
+// "Seed ←[calls]- A".
+func formatLineagePath(seed postgres.Symbol, path []lineageHop) string {
+	var b strings.Builder
+	b.WriteString(seed.Name)
+	for _, hop := range path {
+		label := hop.EdgeType
+		if hop.Confidence > 0 {
+			label += fmt.Sprintf(" %.2f", hop.Confidence)
+		}
+		if hop.Outward {
+			fmt.Fprintf(&b, " -[%s]→ %s", label, hop.Symbol.Name)
+		} else {
+			fmt.Fprintf(&b, " ←[%s]- %s", label, hop.Symbol.Name)
+		}
+	}
+	return b.String()
+}
+
 func (h *GetLineageHandler) resolveSeed(ctx context.Context, project postgres.Project, params GetLineageParams) (postgres.Symbol, error) {
 	if params.SymbolID != "" {
 		id, err := uuid.Parse(params.SymbolID)
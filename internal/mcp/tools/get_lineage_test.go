@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/lineage"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// lineageFixture links Orders page -calls_api→ OrdersController -calls→ usp_SaveOrders
+// -writes_to→ Orders, plus a second route to the procedure through OrderService. The
+// procedure also reads_from Customers, which is upstream of it.
+func lineageFixture() (page, orders postgres.Symbol, g *fakeImpactGraph) {
+	page = postgres.Symbol{ID: uuid.New(), Name: "OrdersPage", Kind: "function", Language: "typescript"}
+	ctrl := postgres.Symbol{ID: uuid.New(), Name: "OrdersController", Kind: "class", Language: "csharp"}
+	svc := postgres.Symbol{ID: uuid.New(), Name: "OrderService", Kind: "class", Language: "csharp"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_SaveOrders", Kind: "procedure", Language: "tsql"}
+	orders = postgres.Symbol{ID: uuid.New(), Name: "Orders", Kind: "table", Language: "tsql"}
+	customers := postgres.Symbol{ID: uuid.New(), Name: "Customers", Kind: "table", Language: "tsql"}
+
+	g = &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(page, ctrl, svc, proc, orders, customers)}
+	g.edges[page.ID] = append(g.edges[page.ID],
+		postgres.SymbolEdge{SourceID: page.ID, TargetID: ctrl.ID, EdgeType: "calls_api", Metadata: []byte(`{"confidence":0.85}`)},
+		postgres.SymbolEdge{SourceID: page.ID, TargetID: svc.ID, EdgeType: "calls"},
+	)
+	g.link(ctrl, proc, "calls")
+	g.link(svc, proc, "calls")
+	g.link(proc, orders, "writes_to")
+	g.link(proc, customers, "reads_from")
+	return page, orders, g
+}
+
+func TestCollectLineage_IncludesFullPath(t *testing.T) {
+	page, orders, g := lineageFixture()
+
+	dirs := lineage.DefaultEdgeDirections()
+	nodes := collectLineage(context.Background(), g, dirs, page, false, 3, defaultLineagePaths)
+	var table *lineageNode
+	for i := range nodes {
+		if nodes[i].Symbol.ID == orders.ID {
+			table = &nodes[i]
+		}
+		if nodes[i].Symbol.Name == "Customers" {
+			t.Errorf("Customers is read by the procedure, so it is not downstream of the page")
+		}
+	}
+	if table == nil || table.Depth != 3 {
+		t.Fatalf("expected Orders at depth 3, got %+v", table)
+	}
+	if len(table.Paths) != 2 {
+		t.Fatalf("expected both routes to Orders, got %d paths", len(table.Paths))
+	}
+	for _, path := range table.Paths {
+		if len(path) != 3 {
+			t.Fatalf("expected a 3-edge path, got %+v", path)
+		}
+		if path[2].EdgeType != "writes_to" || path[2].Symbol.ID != orders.ID || path[1].Symbol.Name != "usp_SaveOrders" {
+			t.Errorf("path does not end usp_SaveOrders -writes_to→ Orders: %+v", path)
+		}
+	}
+
+	rb := mcp.NewResponseBuilder(4000)
+	formatLineageNodes(rb, page, nodes, true)
+	out := rb.Finalize(len(nodes), len(nodes))
+	want := "OrdersPage -[calls_api 0.85]→ OrdersController -[calls]→ usp_SaveOrders -[writes_to]→ Orders"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected path %q in output, got:\n%s", want, out)
+	}
+
+	capped := collectLineage(context.Background(), g, dirs, page, false, 3, 1)
+	for _, n := range capped {
+		if len(n.Paths) != 1 {
+			t.Errorf("%s: expected paths capped at 1, got %d", n.Symbol.Name, len(n.Paths))
+		}
+	}
+	for _, n := range collectLineage(context.Background(), g, dirs, page, false, 3, 0) {
+		if n.Paths != nil {
+			t.Errorf("%s: paths collected without include_paths", n.Symbol.Name)
+		}
+	}
+}
+
+func TestCollectLineage_UpstreamPath(t *testing.T) {
+	page, orders, g := lineageFixture()
+
+	nodes := collectLineage(context.Background(), g, lineage.DefaultEdgeDirections(), orders, true, 3, 1)
+	var found bool
+	for _, n := range nodes {
+		switch n.Symbol.Name {
+		case page.Name:
+			found = true
+			if got := formatLineagePath(orders, n.Paths[0]); !strings.HasPrefix(got, "Orders ←[writes_to]- usp_SaveOrders ←[calls]- ") {
+				t.Errorf("unexpected upstream path %q", got)
+			}
+		case "Customers":
+			// The procedure writing Orders reads Customers: an input to Orders, reached
+			// along the reads_from edge.
+			if got := formatLineagePath(orders, n.Paths[0]); got != "Orders ←[writes_to]- usp_SaveOrders -[reads_from]→ Customers" {
+				t.Errorf("unexpected upstream path %q", got)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected OrdersPage upstream of Orders")
+	}
+}
@@ -2,8 +2,12 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/maraichr/lattice/internal/auth"
 	"github.com/maraichr/lattice/internal/mcp"
@@ -14,7 +18,8 @@ import (
 // GetProjectAnalyticsParams are the parameters for the get_project_analytics tool.
 type GetProjectAnalyticsParams struct {
 	Project string `json:"project"`
-	Scope   string `json:"scope,omitempty"` // summary, languages, kinds, layers, bridges
+	Scope   string `json:"scope,omitempty"`  // summary, languages, kinds, layers, bridges, bridge_coverage, communities, modules, low_confidence_regions, index_suggestions, metrics, cycles, unreferenced, hot_symbols
+	Module  string `json:"module,omitempty"` // with scope=modules, show a single module's breakdown
 }
 
 // GetProjectAnalyticsHandler implements the get_project_analytics MCP tool.
@@ -57,8 +62,24 @@ func (h *GetProjectAnalyticsHandler) Handle(ctx context.Context, params GetProje
 		return h.handleBridges(ctx, project, rb)
 	case "bridge_coverage":
 		return h.handleBridgeCoverage(ctx, project, rb)
+	case "communities":
+		return h.handleCommunities(ctx, project, rb)
+	case "modules":
+		return h.handleModules(ctx, project, params.Module, rb)
+	case "low_confidence_regions":
+		return h.handleLowConfidenceRegions(ctx, project, rb)
+	case "index_suggestions":
+		return h.handleIndexSuggestions(ctx, project, rb)
+	case "metrics":
+		return h.handleMetrics(ctx, project, rb)
+	case "cycles":
+		return h.handleCycles(ctx, project, rb)
+	case "unreferenced":
+		return h.handleUnreferenced(ctx, project, rb)
+	case "hot_symbols":
+		return hotSymbols(ctx, h.store, project, rb)
 	default:
-		return "", fmt.Errorf("unknown scope: %s (valid: summary, languages, kinds, layers, bridges, bridge_coverage)", params.Scope)
+		return "", fmt.Errorf("unknown scope: %s (valid: summary, languages, kinds, layers, bridges, bridge_coverage, communities, modules, low_confidence_regions, index_suggestions, metrics, cycles, unreferenced, hot_symbols)", params.Scope)
 	}
 }
 
@@ -68,6 +89,7 @@ func (h *GetProjectAnalyticsHandler) handleSummary(ctx context.Context, project
 	stats, err := h.store.GetProjectSymbolStats(ctx, project.ID)
 	if err != nil {
 		rb.AddLine("No analytics data available. Run an indexing job first.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -87,6 +109,24 @@ func (h *GetProjectAnalyticsHandler) handleSummary(ctx context.Context, project
 		rb.AddLine(*analytics.Summary)
 	}
 
+	// Flag sampled centrality so scores from a large graph are not read as exact
+	centrality, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "centrality",
+	})
+	if err == nil && centrality.Summary != nil {
+		var run struct {
+			Mode            string `json:"mode"`
+			BetweennessMode string `json:"betweenness_mode"`
+		}
+		if json.Unmarshal(centrality.Analytics, &run) == nil && (run.Mode == "sampled" || run.BetweennessMode == "sampled") {
+			rb.AddLine("")
+			rb.AddLine(*centrality.Summary)
+		}
+	}
+
+	mcp.RecordResults(ctx, 1, 1)
 	return rb.Finalize(1, 1), nil
 }
 
@@ -100,6 +140,7 @@ func (h *GetProjectAnalyticsHandler) handleLanguages(ctx context.Context, projec
 
 	if len(rows) == 0 {
 		rb.AddLine("No language data available.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -107,6 +148,7 @@ func (h *GetProjectAnalyticsHandler) handleLanguages(ctx context.Context, projec
 		rb.AddLine(fmt.Sprintf("- **%s:** %d symbols", r.Language, r.Cnt))
 	}
 
+	mcp.RecordResults(ctx, len(rows), len(rows))
 	return rb.Finalize(len(rows), len(rows)), nil
 }
 
@@ -120,6 +162,7 @@ func (h *GetProjectAnalyticsHandler) handleKinds(ctx context.Context, project po
 
 	if len(rows) == 0 {
 		rb.AddLine("No kind data available.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -127,6 +170,7 @@ func (h *GetProjectAnalyticsHandler) handleKinds(ctx context.Context, project po
 		rb.AddLine(fmt.Sprintf("- **%s:** %d", r.Kind, r.Cnt))
 	}
 
+	mcp.RecordResults(ctx, len(rows), len(rows))
 	return rb.Finalize(len(rows), len(rows)), nil
 }
 
@@ -140,6 +184,7 @@ func (h *GetProjectAnalyticsHandler) handleLayers(ctx context.Context, project p
 
 	if len(rows) == 0 {
 		rb.AddLine("No layer data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -147,6 +192,7 @@ func (h *GetProjectAnalyticsHandler) handleLayers(ctx context.Context, project p
 		rb.AddLine(fmt.Sprintf("- **%v:** %d symbols", r.Layer, r.Cnt))
 	}
 
+	mcp.RecordResults(ctx, len(rows), len(rows))
 	return rb.Finalize(len(rows), len(rows)), nil
 }
 
@@ -160,6 +206,7 @@ func (h *GetProjectAnalyticsHandler) handleBridges(ctx context.Context, project
 
 	if len(rows) == 0 {
 		rb.AddLine("No cross-language bridges found.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -168,6 +215,7 @@ func (h *GetProjectAnalyticsHandler) handleBridges(ctx context.Context, project
 			r.SourceLanguage, r.TargetLanguage, r.EdgeType, r.EdgeCount))
 	}
 
+	mcp.RecordResults(ctx, len(rows), len(rows))
 	return rb.Finalize(len(rows), len(rows)), nil
 }
 
@@ -182,6 +230,7 @@ func (h *GetProjectAnalyticsHandler) handleBridgeCoverage(ctx context.Context, p
 	})
 	if err == nil && analytics.Summary != nil {
 		rb.AddLine(*analytics.Summary)
+		mcp.RecordResults(ctx, 1, 1)
 		return rb.Finalize(1, 1), nil
 	}
 
@@ -193,6 +242,7 @@ func (h *GetProjectAnalyticsHandler) handleBridgeCoverage(ctx context.Context, p
 
 	if stats.TotalCrossLangEdges == 0 {
 		rb.AddLine("No cross-language edges found.")
+		mcp.RecordResults(ctx, 0, 0)
 		return rb.Finalize(0, 0), nil
 	}
 
@@ -203,5 +253,578 @@ func (h *GetProjectAnalyticsHandler) handleBridgeCoverage(ctx context.Context, p
 	}
 	rb.AddLine(fmt.Sprintf("- **Low confidence (<0.8):** %d", stats.LowConfidenceEdges))
 
+	mcp.RecordResults(ctx, 1, 1)
 	return rb.Finalize(1, 1), nil
 }
+
+func (h *GetProjectAnalyticsHandler) handleCommunities(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (communities)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "communities",
+	})
+	if err != nil {
+		rb.AddLine("No community data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	var counts struct {
+		CommunityCount int `json:"community_count"`
+	}
+	_ = json.Unmarshal(overview.Analytics, &counts)
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+	}
+	if counts.CommunityCount == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	rb.AddLine("")
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "communities",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list communities: %w", err)
+	}
+
+	// scope_id sorts lexically; present communities by numeric ID (largest first).
+	sort.Slice(rows, func(i, j int) bool {
+		a, _ := strconv.Atoi(rows[i].ScopeID)
+		b, _ := strconv.Atoi(rows[j].ScopeID)
+		return a < b
+	})
+
+	// Communities are stored by rank; rows past the current count are from earlier runs.
+	total, shown, full := 0, 0, false
+	for _, r := range rows {
+		if rank, _ := strconv.Atoi(r.ScopeID); rank > counts.CommunityCount {
+			continue
+		}
+		total++
+		if full {
+			continue
+		}
+		var data struct {
+			Size   int      `json:"size"`
+			Sample []string `json:"sample"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		line := fmt.Sprintf("- **Community %s:** %d symbols", r.ScopeID, data.Size)
+		if len(data.Sample) > 0 {
+			line += " — e.g. " + strings.Join(data.Sample, ", ")
+		}
+		if !rb.AddLine(line) {
+			full = true
+			continue
+		}
+		shown++
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleLowConfidenceRegions(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (low-confidence regions)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "low_confidence_regions",
+	})
+	if err != nil {
+		rb.AddLine("No low-confidence region data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	var counts struct {
+		RegionCount int `json:"region_count"`
+	}
+	_ = json.Unmarshal(overview.Analytics, &counts)
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+	}
+	if counts.RegionCount == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	rb.AddLine("Answers drawing on these symbols rely on inferred links; verify them before acting.")
+	rb.AddLine("")
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "low_confidence_regions",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list low confidence regions: %w", err)
+	}
+
+	// Regions are numbered largest first; rows past the current count are from earlier runs.
+	sort.Slice(rows, func(i, j int) bool {
+		a, _ := strconv.Atoi(rows[i].ScopeID)
+		b, _ := strconv.Atoi(rows[j].ScopeID)
+		return a < b
+	})
+
+	total, shown, full := 0, 0, false
+	for _, r := range rows {
+		if id, _ := strconv.Atoi(r.ScopeID); id > counts.RegionCount {
+			continue
+		}
+		total++
+		if full {
+			continue
+		}
+		var data struct {
+			Size          int      `json:"size"`
+			MinConfidence float64  `json:"min_confidence"`
+			Sample        []string `json:"sample"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		line := fmt.Sprintf("- **Region %s:** %d symbols, weakest link %.2f", r.ScopeID, data.Size, data.MinConfidence)
+		if len(data.Sample) > 0 {
+			line += " — e.g. " + strings.Join(data.Sample, ", ")
+		}
+		if full = !rb.AddLine(line); !full {
+			shown++
+		}
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleIndexSuggestions(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (index suggestions)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "index_suggestions",
+	})
+	if err != nil {
+		rb.AddLine("No index suggestion data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	var counts struct {
+		SuggestionCount int `json:"suggestion_count"`
+	}
+	_ = json.Unmarshal(overview.Analytics, &counts)
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+	}
+	if counts.SuggestionCount == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	rb.AddLine("Ranked by the number of queries filtering or joining on each column; check the table's size and the database's own plans before adding one.")
+	rb.AddLine("")
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "index_suggestions",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list index suggestions: %w", err)
+	}
+
+	// Suggestions are stored by rank; rows past the current count are from earlier runs.
+	sort.Slice(rows, func(i, j int) bool {
+		a, _ := strconv.Atoi(rows[i].ScopeID)
+		b, _ := strconv.Atoi(rows[j].ScopeID)
+		return a < b
+	})
+
+	total, shown, full := 0, 0, false
+	for _, r := range rows {
+		if rank, _ := strconv.Atoi(r.ScopeID); rank > counts.SuggestionCount {
+			continue
+		}
+		total++
+		if full {
+			continue
+		}
+		var data struct {
+			Table   string `json:"table"`
+			Column  string `json:"column"`
+			Filters int    `json:"filters"`
+			Joins   int    `json:"joins"`
+			DDL     string `json:"ddl"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		line := fmt.Sprintf("%s. **%s.%s** — filtered by %d, joined on by %d queries: `%s`",
+			r.ScopeID, data.Table, data.Column, data.Filters, data.Joins, data.DDL)
+		if full = !rb.AddLine(line); !full {
+			shown++
+		}
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleMetrics(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (metrics)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "metrics",
+	})
+	if err != nil {
+		rb.AddLine("No symbol metrics available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	var counts struct {
+		RankedCount int `json:"ranked_count"`
+	}
+	_ = json.Unmarshal(overview.Analytics, &counts)
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+	}
+	if counts.RankedCount == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	rb.AddLine("Ranked by combined score: lines, fan-in, fan-out and branch complexity, each relative to the project's 90th percentile.")
+	rb.AddLine("")
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "metrics",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list symbol metrics: %w", err)
+	}
+
+	// Symbols are stored by rank; rows past the current count are from earlier runs.
+	sort.Slice(rows, func(i, j int) bool {
+		a, _ := strconv.Atoi(rows[i].ScopeID)
+		b, _ := strconv.Atoi(rows[j].ScopeID)
+		return a < b
+	})
+
+	total, shown, full := 0, 0, false
+	for _, r := range rows {
+		if rank, _ := strconv.Atoi(r.ScopeID); rank > counts.RankedCount {
+			continue
+		}
+		total++
+		if full {
+			continue
+		}
+		var data struct {
+			QualifiedName   string  `json:"qualified_name"`
+			Kind            string  `json:"kind"`
+			LineCount       int     `json:"line_count"`
+			FanIn           int     `json:"fan_in"`
+			FanOut          int     `json:"fan_out"`
+			Complexity      int     `json:"complexity"`
+			ComplexityScore float64 `json:"complexity_score"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		complexity := "n/a"
+		if data.Complexity > 0 {
+			complexity = strconv.Itoa(data.Complexity)
+		}
+		line := fmt.Sprintf("%s. **%s** (%s) — score %.2f: %d lines, fan-in %d, fan-out %d, complexity %s",
+			r.ScopeID, data.QualifiedName, data.Kind, data.ComplexityScore, data.LineCount, data.FanIn, data.FanOut, complexity)
+		if full = !rb.AddLine(line); !full {
+			shown++
+		}
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleCycles(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (cycles)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "cycles",
+	})
+	if err != nil {
+		rb.AddLine("No dependency cycle data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	var counts struct {
+		RankedCount int `json:"ranked_count"`
+	}
+	_ = json.Unmarshal(overview.Analytics, &counts)
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+	}
+	if counts.RankedCount == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	rb.AddLine("")
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "cycles",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list dependency cycles: %w", err)
+	}
+
+	// Cycles are stored by rank; rows past the current count are from earlier runs.
+	sort.Slice(rows, func(i, j int) bool {
+		a, _ := strconv.Atoi(rows[i].ScopeID)
+		b, _ := strconv.Atoi(rows[j].ScopeID)
+		return a < b
+	})
+
+	total, shown, full := 0, 0, false
+	for _, r := range rows {
+		if rank, _ := strconv.Atoi(r.ScopeID); rank > counts.RankedCount {
+			continue
+		}
+		total++
+		if full {
+			continue
+		}
+		var data struct {
+			Size    int `json:"size"`
+			Members []struct {
+				QualifiedName string `json:"qualified_name"`
+				Kind          string `json:"kind"`
+			} `json:"members"`
+			EdgeTypes map[string]int `json:"edge_types"`
+			Loop      []struct {
+				From     string `json:"from"`
+				To       string `json:"to"`
+				EdgeType string `json:"edge_type"`
+			} `json:"loop"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+
+		types := make([]string, 0, len(data.EdgeTypes))
+		for t := range data.EdgeTypes {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for i, t := range types {
+			types[i] = fmt.Sprintf("%s (%d)", t, data.EdgeTypes[t])
+		}
+		var loop strings.Builder
+		for i, step := range data.Loop {
+			if i == 0 {
+				loop.WriteString(step.From)
+			}
+			fmt.Fprintf(&loop, " —%s→ %s", step.EdgeType, step.To)
+		}
+		members := make([]string, len(data.Members))
+		for i, m := range data.Members {
+			members[i] = fmt.Sprintf("%s (%s)", m.QualifiedName, m.Kind)
+		}
+		if more := data.Size - len(data.Members); more > 0 {
+			members = append(members, fmt.Sprintf("and %d more", more))
+		}
+
+		entry := fmt.Sprintf("%s. **%d symbols** — edges: %s\n   Loop: %s\n   Members: %s",
+			r.ScopeID, data.Size, strings.Join(types, ", "), loop.String(), strings.Join(members, ", "))
+		if full = !rb.AddLine(entry); !full {
+			shown++
+		}
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleUnreferenced(ctx context.Context, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (unreferenced)", project.Name))
+
+	overview, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+		ProjectID: project.ID,
+		Scope:     "project",
+		ScopeID:   "unreferenced",
+	})
+	if err != nil {
+		rb.AddLine("No unreferenced symbol data available. Run analytics pipeline first.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+	if overview.Summary != nil {
+		rb.AddLine(*overview.Summary)
+		rb.AddLine("")
+	}
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "unreferenced",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list unreferenced symbols: %w", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ScopeID < rows[j].ScopeID })
+
+	total, shown := 0, 0
+	for _, r := range rows {
+		var data struct {
+			UnreferencedCount  int      `json:"unreferenced_count"`
+			LowConfidenceCount int      `json:"low_confidence_count"`
+			Sample             []string `json:"sample"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		if data.UnreferencedCount == 0 && data.LowConfidenceCount == 0 {
+			continue
+		}
+		total++
+		line := fmt.Sprintf("- **%s:** %d unreferenced", r.ScopeID, data.UnreferencedCount)
+		if data.LowConfidenceCount > 0 {
+			line += fmt.Sprintf(", %d only via low-confidence bridges", data.LowConfidenceCount)
+		}
+		if len(data.Sample) > 0 {
+			line += " — e.g. " + strings.Join(data.Sample, ", ")
+		}
+		if !rb.AddLine(line) {
+			break
+		}
+		shown++
+	}
+	if total > 0 {
+		rb.AddLine("")
+		rb.AddLine("Use find_unused_symbols to list them by kind or language.")
+	}
+
+	mcp.RecordResults(ctx, total, shown)
+	return rb.Finalize(total, shown), nil
+}
+
+// hotSymbolsWindowDays and hotSymbolsLimit bound the hot_symbols scope.
+const (
+	hotSymbolsWindowDays = 7
+	hotSymbolsLimit      = 25
+)
+
+// hotSymbolStore is the subset of the store the hot_symbols scope reads.
+type hotSymbolStore interface {
+	ListHotSymbols(ctx context.Context, arg postgres.ListHotSymbolsParams) ([]postgres.ListHotSymbolsRow, error)
+}
+
+// hotSymbols renders the hot_symbols scope from the usage counts tool telemetry keeps
+// (see mcp.Usage) as they stand, rather than rows the analytics pipeline computed at ingest.
+func hotSymbols(ctx context.Context, s hotSymbolStore, project postgres.Project, rb *mcp.ResponseBuilder) (string, error) {
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (hot symbols)", project.Name))
+
+	rows, err := s.ListHotSymbols(ctx, postgres.ListHotSymbolsParams{
+		ProjectID: project.ID,
+		Days:      hotSymbolsWindowDays,
+		Lim:       hotSymbolsLimit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("list hot symbols: %w", err)
+	}
+	if len(rows) == 0 {
+		rb.AddLine(fmt.Sprintf("No symbols from this project were shown in tool responses in the last %d days.", hotSymbolsWindowDays))
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+
+	rb.AddLine(fmt.Sprintf("Symbols shown most often in tool responses over the last %d days. This is what agents look at, not how connected a symbol is (see scope=metrics).", hotSymbolsWindowDays))
+	rb.AddLine("")
+	shown := 0
+	for i, r := range rows {
+		// No ID line: this response must not count toward the symbols' own usage
+		if !rb.AddLine(fmt.Sprintf("%d. `%s` (%s, %s) — %d appearances", i+1, r.QualifiedName, r.Kind, r.Language, r.Hits)) {
+			break
+		}
+		shown++
+	}
+
+	mcp.RecordResults(ctx, len(rows), shown)
+	return rb.Finalize(len(rows), shown), nil
+}
+
+func (h *GetProjectAnalyticsHandler) handleModules(ctx context.Context, project postgres.Project, module string, rb *mcp.ResponseBuilder) (string, error) {
+	if module != "" {
+		rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (module %s)", project.Name, module))
+
+		row, err := h.store.GetProjectAnalytics(ctx, postgres.GetProjectAnalyticsParams{
+			ProjectID: project.ID,
+			Scope:     "module",
+			ScopeID:   module,
+		})
+		if err != nil {
+			rb.AddLine(fmt.Sprintf("No analytics for module %s. Check the name with scope=modules.", module))
+			mcp.RecordResults(ctx, 0, 0)
+			return rb.Finalize(0, 0), nil
+		}
+
+		var data struct {
+			SymbolCount int            `json:"symbol_count"`
+			Languages   map[string]int `json:"languages"`
+			Kinds       map[string]int `json:"kinds"`
+		}
+		_ = json.Unmarshal(row.Analytics, &data)
+		rb.AddLine(fmt.Sprintf("- **Total symbols:** %d", data.SymbolCount))
+		rb.AddSection("Languages", formatCountMap(data.Languages))
+		rb.AddSection("Kinds", formatCountMap(data.Kinds))
+		mcp.RecordResults(ctx, 1, 1)
+		return rb.Finalize(1, 1), nil
+	}
+
+	rb.AddHeader(fmt.Sprintf("**Project Analytics: %s** (modules)", project.Name))
+
+	rows, err := h.store.ListProjectAnalyticsByScope(ctx, postgres.ListProjectAnalyticsByScopeParams{
+		ProjectID: project.ID,
+		Scope:     "module",
+	})
+	if err != nil {
+		return "", fmt.Errorf("list modules: %w", err)
+	}
+
+	if len(rows) == 0 {
+		rb.AddLine("No module data available. Enable module_detection in project settings and re-index.")
+		mcp.RecordResults(ctx, 0, 0)
+		return rb.Finalize(0, 0), nil
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ScopeID < rows[j].ScopeID })
+
+	shown := 0
+	for _, r := range rows {
+		var data struct {
+			SymbolCount int `json:"symbol_count"`
+		}
+		_ = json.Unmarshal(r.Analytics, &data)
+		if !rb.AddLine(fmt.Sprintf("- **%s:** %d symbols", r.ScopeID, data.SymbolCount)) {
+			break
+		}
+		shown++
+	}
+
+	mcp.RecordResults(ctx, len(rows), shown)
+	return rb.Finalize(len(rows), shown), nil
+}
+
+// formatCountMap renders a name→count map as a bulleted list, largest first.
+func formatCountMap(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "- **%s:** %d\n", name, counts[name])
+	}
+	return b.String()
+}
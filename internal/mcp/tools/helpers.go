@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -20,16 +22,30 @@ type ToolHandler[P any] interface {
 
 // WrapHandler adapts a ToolHandler into the SDK's AddTool callback.
 // It handles nil params by using a zero value and maps errors to CallToolResult.
+// When the client asks for progress, what handlers report through mcp.ProgressFrom is
+// sent as progress notifications; the response is only in the result.
 func WrapHandler[P any](h ToolHandler[P]) func(context.Context, *sdkmcp.CallToolRequest, *P) (*sdkmcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *sdkmcp.CallToolRequest, params *P) (*sdkmcp.CallToolResult, any, error) {
 		if params == nil {
 			params = new(P)
 		}
+		if req != nil && req.Session != nil && req.Params != nil {
+			if token := req.Params.GetProgressToken(); token != nil {
+				ctx = mcp.WithProgress(ctx, func(step int, message string) {
+					// A lost notification costs the client a status update, not the result
+					_ = req.Session.NotifyProgress(ctx, &sdkmcp.ProgressNotificationParams{
+						ProgressToken: token,
+						Message:       message,
+						Progress:      float64(step),
+					})
+				})
+			}
+		}
 		result, err := h.Handle(ctx, *params)
 		if err != nil {
 			return &sdkmcp.CallToolResult{
 				IsError: true,
-				Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: err.Error()}},
+				Content: []sdkmcp.Content{&sdkmcp.TextContent{Text: toolErrorText(err)}},
 			}, nil, nil
 		}
 		return &sdkmcp.CallToolResult{
@@ -38,6 +54,45 @@ func WrapHandler[P any](h ToolHandler[P]) func(context.Context, *sdkmcp.CallTool
 	}
 }
 
+// toolErrorText is the message shown to the agent for a failed tool call.
+func toolErrorText(err error) string {
+	if errors.Is(err, store.ErrQueryTimeout) {
+		return "The query took too long and was cancelled. Narrow the request and try again: " +
+			"lower max_depth, filter by kind or language, or use a more specific symbol or query."
+	}
+	return err.Error()
+}
+
+// instrumented wraps a tool handler with per-call telemetry.
+type instrumented[P any] struct {
+	tool      string
+	telemetry *mcp.Telemetry
+	next      ToolHandler[P]
+}
+
+// Instrument wraps h so every call is reported to t under the tool name: duration,
+// outcome, the result counts the handler records with mcp.RecordResults, and the
+// symbols the response shows.
+func Instrument[P any](tool string, t *mcp.Telemetry, h ToolHandler[P]) ToolHandler[P] {
+	return &instrumented[P]{tool: tool, telemetry: t, next: h}
+}
+
+func (h *instrumented[P]) Handle(ctx context.Context, params P) (string, error) {
+	ctx, stats := mcp.WithCallStats(ctx)
+	start := time.Now()
+	out, err := h.next.Handle(ctx, params)
+	stats.Symbols = mcp.ResponseSymbols(out)
+	h.telemetry.Observe(h.tool, stats, time.Since(start), err)
+	return out, err
+}
+
+// symbolGraph is the subset of the store needed to walk edges in both directions.
+type symbolGraph interface {
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetSymbol(ctx context.Context, id uuid.UUID) (postgres.Symbol, error)
+}
+
 // WrapProjectError translates database errors from GetProject into user-friendly messages.
 func WrapProjectError(err error) error {
 	if errors.Is(err, pgx.ErrNoRows) {
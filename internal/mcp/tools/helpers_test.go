@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/maraichr/lattice/internal/mcp"
+)
+
+// pagedTool lists symbols a page at a time, reporting each page like a long-running
+// tool reporting its progress.
+type pagedTool struct{}
+
+func (pagedTool) Handle(ctx context.Context, params SearchSymbolsParams) (string, error) {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader("**Results for: " + params.Query + "**")
+	for page := range 3 {
+		for i := range 4 {
+			rb.AddLine(fmt.Sprintf("- symbol %d", page*4+i+1))
+		}
+		mcp.ProgressFrom(ctx).Report("page %d of 3", page+1)
+	}
+	rb.AddLine("done")
+	return rb.Finalize(12, 12), nil
+}
+
+// callWithProgress calls a tool over an in-memory MCP session and returns its result
+// text and the progress messages received, in order. It waits for up to want messages.
+func callWithProgress(t *testing.T, h ToolHandler[SearchSymbolsParams], progressToken any, want int) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	server := sdkmcp.NewServer(&sdkmcp.Implementation{Name: "lattice", Version: "test"}, nil)
+	sdkmcp.AddTool(server, &sdkmcp.Tool{Name: "paged"}, WrapHandler[SearchSymbolsParams](h))
+
+	var mu sync.Mutex
+	received := map[float64]string{}
+	client := sdkmcp.NewClient(&sdkmcp.Implementation{Name: "agent", Version: "test"}, &sdkmcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *sdkmcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[req.Params.Progress] = req.Params.Message
+		},
+	})
+	st, ct := sdkmcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	params := &sdkmcp.CallToolParams{Name: "paged", Arguments: map[string]any{"project": "demo", "query": "orders"}}
+	if progressToken != nil {
+		params.Meta = sdkmcp.Meta{"progressToken": progressToken}
+	}
+	res, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := res.Content[0].(*sdkmcp.TextContent).Text
+
+	// Notifications may be handled after the result arrives
+	ordered := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		seqs := make([]float64, 0, len(received))
+		for seq := range received {
+			seqs = append(seqs, seq)
+		}
+		sort.Float64s(seqs)
+		out := make([]string, len(seqs))
+		for i, seq := range seqs {
+			out[i] = received[seq]
+		}
+		return out
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ordered()) < want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return text, ordered()
+}
+
+func TestWrapHandler_ReportsProgress(t *testing.T) {
+	text, messages := callWithProgress(t, pagedTool{}, "call-1", 3)
+	if want := []string{"page 1 of 3", "page 2 of 3", "page 3 of 3"}; strings.Join(messages, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected progress %q, got %q", want, messages)
+	}
+	if !strings.Contains(text, "symbol 12") || !strings.HasSuffix(text, "done\n") {
+		t.Errorf("expected the whole response in the result, got %q", text)
+	}
+}
+
+func TestWrapHandler_NoProgressWithoutToken(t *testing.T) {
+	text, messages := callWithProgress(t, pagedTool{}, nil, 0)
+	if len(messages) != 0 {
+		t.Errorf("expected no progress notifications without a progress token, got %d", len(messages))
+	}
+	if !strings.Contains(text, "symbol 12") {
+		t.Errorf("expected the whole response in the result, got %q", text)
+	}
+
+	// A tool that reports nothing sends no notifications even when progress is asked for.
+	_, messages = callWithProgress(t, emptySearch{}, "call-2", 0)
+	if len(messages) != 0 {
+		t.Errorf("expected no progress from a tool that does not report it, got %d", len(messages))
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// emptySearch mimics search_symbols finding no matches.
+type emptySearch struct{}
+
+func (emptySearch) Handle(ctx context.Context, params SearchSymbolsParams) (string, error) {
+	mcp.RecordResults(ctx, 0, 0)
+	return fmt.Sprintf("No symbols found matching '%s'.", params.Query), nil
+}
+
+func TestInstrument_EmptySearchCountsEmptyResult(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	telemetry := mcp.NewTelemetry(reg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h := Instrument[SearchSymbolsParams]("search_symbols", telemetry, emptySearch{})
+
+	if _, err := h.Handle(context.Background(), SearchSymbolsParams{Project: "demo", Query: "nothing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP lattice_mcp_tool_empty_results_total MCP tool calls that returned zero results.
+# TYPE lattice_mcp_tool_empty_results_total counter
+lattice_mcp_tool_empty_results_total{intent="",tool="search_symbols"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "lattice_mcp_tool_empty_results_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+// usageTable aggregates symbol usage like the symbol_usage table, for one day.
+type usageTable struct {
+	symbols map[uuid.UUID]postgres.Symbol
+	hits    map[uuid.UUID]int64
+}
+
+func (u *usageTable) IncrementSymbolUsage(_ context.Context, arg postgres.IncrementSymbolUsageParams) error {
+	for i, id := range arg.SymbolIds {
+		if _, ok := u.symbols[id]; ok {
+			u.hits[id] += arg.Hits[i]
+		}
+	}
+	return nil
+}
+
+func (u *usageTable) DeleteSymbolUsageBefore(context.Context, int32) error { return nil }
+
+func (u *usageTable) ListHotSymbols(_ context.Context, arg postgres.ListHotSymbolsParams) ([]postgres.ListHotSymbolsRow, error) {
+	var rows []postgres.ListHotSymbolsRow
+	for id, hits := range u.hits {
+		s := u.symbols[id]
+		if s.ProjectID == arg.ProjectID {
+			rows = append(rows, postgres.ListHotSymbolsRow{ID: s.ID, ProjectID: s.ProjectID, QualifiedName: s.QualifiedName, Kind: s.Kind, Language: s.Language, Hits: hits})
+		}
+	}
+	slices.SortFunc(rows, func(a, b postgres.ListHotSymbolsRow) int { return cmp.Compare(b.Hits, a.Hits) })
+	return rows, nil
+}
+
+// cardSearch mimics search_symbols returning the given symbols as cards.
+type cardSearch struct{ symbols []postgres.Symbol }
+
+func (c cardSearch) Handle(ctx context.Context, params SearchSymbolsParams) (string, error) {
+	rb := mcp.NewResponseBuilder(4000)
+	for _, s := range c.symbols {
+		rb.AddSymbolCard(s, mcp.VerbosityStandard, nil)
+	}
+	mcp.RecordResults(ctx, len(c.symbols), len(c.symbols))
+	return rb.Finalize(len(c.symbols), len(c.symbols)), nil
+}
+
+func TestInstrument_RecordsHotSymbols(t *testing.T) {
+	projectID := uuid.New()
+	sym := func(name string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), ProjectID: projectID, Name: name, QualifiedName: "dbo." + name, Kind: "table", Language: "tsql"}
+	}
+	orders, customers := sym("Orders"), sym("Customers")
+	table := &usageTable{
+		symbols: map[uuid.UUID]postgres.Symbol{orders.ID: orders, customers.ID: customers},
+		hits:    map[uuid.UUID]int64{},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	telemetry := mcp.NewTelemetry(prometheus.NewRegistry(), logger)
+	usage := mcp.NewUsage(table, logger)
+	telemetry.TrackUsage(usage)
+
+	ctx := context.Background()
+	for range 3 {
+		h := Instrument[SearchSymbolsParams]("search_symbols", telemetry, cardSearch{symbols: []postgres.Symbol{orders}})
+		if _, err := h.Handle(ctx, SearchSymbolsParams{Project: "demo", Query: "orders"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := Instrument[SearchSymbolsParams]("search_symbols", telemetry, cardSearch{symbols: []postgres.Symbol{customers, orders}})
+	if _, err := h.Handle(ctx, SearchSymbolsParams{Project: "demo", Query: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := usage.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if table.hits[orders.ID] != 4 || table.hits[customers.ID] != 1 {
+		t.Fatalf("expected Orders seen 4 times and Customers once, got %d and %d", table.hits[orders.ID], table.hits[customers.ID])
+	}
+
+	out, err := hotSymbols(ctx, table, postgres.Project{ID: projectID, Name: "demo"}, mcp.NewResponseBuilder(4000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1. `dbo.Orders` (table, tsql) — 4 appearances") || !strings.Contains(out, "2. `dbo.Customers`") {
+		t.Errorf("expected Orders ranked first in hot_symbols, got:\n%s", out)
+	}
+	// The scope itself shows no symbol cards, so reading it adds no usage
+	if ids := mcp.ResponseSymbols(out); len(ids) != 0 {
+		t.Errorf("expected no symbol IDs in the hot_symbols response, got %v", ids)
+	}
+}
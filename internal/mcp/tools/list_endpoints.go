@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	defaultEndpointDepth = 4
+	maxEndpointDepth     = 8
+	defaultEndpointLimit = 50
+	maxEndpointLimit     = 200
+)
+
+// endpointTraversalEdges are the edge types followed from an endpoint down to the data it touches.
+var endpointTraversalEdges = map[string]bool{
+	"calls":           true,
+	"uses_table":      true,
+	"uses_collection": true,
+	"reads_from":      true,
+	"writes_to":       true,
+	"joins":           true,
+}
+
+// ListEndpointsParams are the parameters for the list_endpoints tool.
+type ListEndpointsParams struct {
+	Project  string `json:"project"`
+	Table    string `json:"table,omitempty"`     // only endpoints that (transitively) touch this table
+	MaxDepth int    `json:"max_depth,omitempty"` // call-chain depth, default: 4, max: 8
+	Limit    int    `json:"limit,omitempty"`     // max endpoints, default: 50
+}
+
+// ListEndpointsHandler implements the list_endpoints MCP tool.
+type ListEndpointsHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewListEndpointsHandler creates a new handler.
+func NewListEndpointsHandler(s *store.Store, logger *slog.Logger) *ListEndpointsHandler {
+	return &ListEndpointsHandler{store: s, logger: logger}
+}
+
+// endpointGraph is the subset of the store needed to walk an endpoint's call chain.
+type endpointGraph interface {
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetSymbol(ctx context.Context, id uuid.UUID) (postgres.Symbol, error)
+}
+
+// endpointTable is a table or view reached from an endpoint.
+type endpointTable struct {
+	Symbol postgres.Symbol
+	Access map[string]bool // edge types that reached the table (reads_from, writes_to, ...)
+	Depth  int
+}
+
+// endpointData is the result of walking a single endpoint's call chain.
+type endpointData struct {
+	Endpoint  postgres.Symbol
+	Method    string
+	Route     string
+	Tables    []endpointTable
+	Truncated bool // call chain continued past max depth
+}
+
+// Handle lists endpoint symbols with the tables each reaches through its call chain.
+func (h *ListEndpointsHandler) Handle(ctx context.Context, params ListEndpointsParams) (string, error) {
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = defaultEndpointDepth
+	}
+	if params.MaxDepth > maxEndpointDepth {
+		params.MaxDepth = maxEndpointDepth
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultEndpointLimit
+	}
+	if params.Limit > maxEndpointLimit {
+		params.Limit = maxEndpointLimit
+	}
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	// With a table, the store finds the endpoints reaching it, so the limit applies to
+	// those rather than to all endpoints
+	var endpoints []postgres.Symbol
+	if params.Table != "" {
+		edgeTypes := make([]string, 0, len(endpointTraversalEdges))
+		for edgeType := range endpointTraversalEdges {
+			edgeTypes = append(edgeTypes, edgeType)
+		}
+		endpoints, err = h.store.ListEndpointsTouchingTable(ctx, postgres.ListEndpointsTouchingTableParams{
+			ProjectID: project.ID,
+			TableName: params.Table,
+			MaxDepth:  int32(params.MaxDepth),
+			EdgeTypes: edgeTypes,
+			Lim:       int32(params.Limit),
+		})
+	} else {
+		endpoints, err = h.store.ListTopSymbolsByKind(ctx, postgres.ListTopSymbolsByKindParams{
+			ProjectSlug: project.Slug,
+			Kinds:       []string{"endpoint"},
+			Languages:   []string{},
+			Lim:         int32(params.Limit),
+		})
+	}
+	if err != nil {
+		return "", fmt.Errorf("list endpoints: %w", err)
+	}
+
+	var results []endpointData
+	for _, ep := range endpoints {
+		results = append(results, collectEndpointData(ctx, h.store, ep, params.MaxDepth))
+	}
+
+	mcp.RecordResults(ctx, len(results), len(results))
+	return formatEndpoints(results, params), nil
+}
+
+// collectEndpointData walks outgoing call/data edges breadth-first from an endpoint,
+// collecting every table or view reached within maxDepth hops.
+func collectEndpointData(ctx context.Context, g endpointGraph, ep postgres.Symbol, maxDepth int) endpointData {
+	method, route := endpointRoute(ep)
+	data := endpointData{Endpoint: ep, Method: method, Route: route}
+
+	type queued struct {
+		id    uuid.UUID
+		depth int
+	}
+
+	visited := map[uuid.UUID]bool{ep.ID: true}
+	tables := make(map[uuid.UUID]*endpointTable)
+	var order []uuid.UUID
+	queue := []queued{{id: ep.ID}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		edges, err := g.GetOutgoingEdges(ctx, cur.id)
+		if err != nil {
+			continue
+		}
+		for _, e := range edges {
+			if !endpointTraversalEdges[e.EdgeType] {
+				continue
+			}
+			if cur.depth >= maxDepth {
+				data.Truncated = true
+				break
+			}
+			if t, ok := tables[e.TargetID]; ok {
+				t.Access[e.EdgeType] = true
+				continue
+			}
+			if visited[e.TargetID] {
+				continue
+			}
+			visited[e.TargetID] = true
+
+			sym, err := g.GetSymbol(ctx, e.TargetID)
+			if err != nil {
+				continue
+			}
+			if sym.Kind == "table" || sym.Kind == "view" {
+				tables[sym.ID] = &endpointTable{
+					Symbol: sym,
+					Access: map[string]bool{e.EdgeType: true},
+					Depth:  cur.depth + 1,
+				}
+				order = append(order, sym.ID)
+				continue
+			}
+			queue = append(queue, queued{id: sym.ID, depth: cur.depth + 1})
+		}
+	}
+
+	for _, id := range order {
+		data.Tables = append(data.Tables, *tables[id])
+	}
+	sort.SliceStable(data.Tables, func(i, j int) bool {
+		return data.Tables[i].Symbol.QualifiedName < data.Tables[j].Symbol.QualifiedName
+	})
+	return data
+}
+
+// endpointRoute returns the HTTP method and route for an endpoint symbol. Parsers record
+// these as http_method/route metadata or as a "METHOD /route" signature; otherwise the
+// qualified name stands in for the route.
+func endpointRoute(sym postgres.Symbol) (string, string) {
+	if len(sym.Metadata) > 0 {
+		var meta map[string]interface{}
+		if json.Unmarshal(sym.Metadata, &meta) == nil {
+			method, _ := meta["http_method"].(string)
+			route, _ := meta["route"].(string)
+			if route != "" {
+				return strings.ToUpper(method), route
+			}
+		}
+	}
+	if sym.Signature != nil {
+		if fields := strings.Fields(*sym.Signature); len(fields) == 2 && strings.HasPrefix(fields[1], "/") {
+			return strings.ToUpper(fields[0]), fields[1]
+		}
+	}
+	return "", sym.QualifiedName
+}
+
+// accessLabel summarizes how an endpoint touches a table.
+func accessLabel(access map[string]bool) string {
+	reads := access["reads_from"] || access["joins"] || access["uses_table"] || access["uses_collection"]
+	writes := access["writes_to"]
+	switch {
+	case reads && writes:
+		return "read/write"
+	case writes:
+		return "write"
+	default:
+		return "read"
+	}
+}
+
+func formatEndpoints(results []endpointData, params ListEndpointsParams) string {
+	rb := mcp.NewResponseBuilder(4000)
+	header := fmt.Sprintf("**Endpoints** (%d)", len(results))
+	if params.Table != "" {
+		header = fmt.Sprintf("**Endpoints touching %s** (%d)", params.Table, len(results))
+	}
+	rb.AddHeader(header)
+
+	if len(results) == 0 {
+		rb.AddLine("No endpoints found.")
+		return rb.Finalize(0, 0)
+	}
+
+	shown := 0
+	anyTruncated := false
+	for _, r := range results {
+		label := r.Route
+		if r.Method != "" {
+			label = r.Method + " " + r.Route
+		}
+		if !rb.AddLine(fmt.Sprintf("- `%s` — %s `%s` [%s] | ID: `%s`",
+			label, r.Endpoint.Kind, r.Endpoint.Name, r.Endpoint.Language, r.Endpoint.ID)) {
+			break
+		}
+		shown++
+		if len(r.Tables) == 0 {
+			rb.AddLine("  - no tables reached")
+		}
+		for _, t := range r.Tables {
+			rb.AddLine(fmt.Sprintf("  - %s `%s` (%s, depth %d)", t.Symbol.Kind, t.Symbol.QualifiedName, accessLabel(t.Access), t.Depth))
+		}
+		if r.Truncated {
+			anyTruncated = true
+			rb.AddLine(fmt.Sprintf("  - _call chain truncated at depth %d_", params.MaxDepth))
+		}
+	}
+
+	if anyTruncated {
+		rb.AddLine("")
+		rb.AddLine("Some call chains were truncated; increase max_depth (up to 8) to follow them further.")
+	}
+
+	return rb.Finalize(len(results), shown)
+}
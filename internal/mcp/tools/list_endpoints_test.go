@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeEndpointGraph is an in-memory endpointGraph for traversal tests.
+type fakeEndpointGraph struct {
+	symbols map[uuid.UUID]postgres.Symbol
+	edges   map[uuid.UUID][]postgres.SymbolEdge
+}
+
+func newFakeEndpointGraph(symbols ...postgres.Symbol) *fakeEndpointGraph {
+	g := &fakeEndpointGraph{
+		symbols: make(map[uuid.UUID]postgres.Symbol),
+		edges:   make(map[uuid.UUID][]postgres.SymbolEdge),
+	}
+	for _, s := range symbols {
+		g.symbols[s.ID] = s
+	}
+	return g
+}
+
+func (g *fakeEndpointGraph) link(from, to postgres.Symbol, edgeType string) {
+	g.edges[from.ID] = append(g.edges[from.ID], postgres.SymbolEdge{SourceID: from.ID, TargetID: to.ID, EdgeType: edgeType})
+}
+
+func (g *fakeEndpointGraph) GetOutgoingEdges(_ context.Context, id uuid.UUID) ([]postgres.SymbolEdge, error) {
+	return g.edges[id], nil
+}
+
+func (g *fakeEndpointGraph) GetSymbol(_ context.Context, id uuid.UUID) (postgres.Symbol, error) {
+	s, ok := g.symbols[id]
+	if !ok {
+		return postgres.Symbol{}, errors.New("not found")
+	}
+	return s, nil
+}
+
+func TestCollectEndpointData_EndpointProcTable(t *testing.T) {
+	sig := "POST /api/payments"
+	ep := postgres.Symbol{ID: uuid.New(), Name: "CreatePayment", Kind: "endpoint", Signature: &sig}
+	method := postgres.Symbol{ID: uuid.New(), Name: "PaymentService.Create", Kind: "method"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_InsertPayment", QualifiedName: "dbo.usp_InsertPayment", Kind: "procedure"}
+	payments := postgres.Symbol{ID: uuid.New(), Name: "Payments", QualifiedName: "dbo.Payments", Kind: "table"}
+	audit := postgres.Symbol{ID: uuid.New(), Name: "Audit", QualifiedName: "dbo.Audit", Kind: "table"}
+
+	g := newFakeEndpointGraph(ep, method, proc, payments, audit)
+	g.link(ep, method, "calls")
+	g.link(method, proc, "calls")
+	g.link(proc, payments, "writes_to")
+	g.link(proc, payments, "reads_from")
+	g.link(proc, audit, "writes_to")
+
+	data := collectEndpointData(context.Background(), g, ep, 4)
+
+	if data.Method != "POST" || data.Route != "/api/payments" {
+		t.Errorf("expected POST /api/payments, got %q %q", data.Method, data.Route)
+	}
+	if len(data.Tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(data.Tables))
+	}
+	if data.Tables[1].Symbol.QualifiedName != "dbo.Payments" {
+		t.Errorf("expected dbo.Payments second (sorted), got %s", data.Tables[1].Symbol.QualifiedName)
+	}
+	if got := accessLabel(data.Tables[1].Access); got != "read/write" {
+		t.Errorf("expected read/write access to Payments, got %s", got)
+	}
+	if data.Tables[1].Depth != 3 {
+		t.Errorf("expected Payments at depth 3, got %d", data.Tables[1].Depth)
+	}
+	if data.Truncated {
+		t.Error("chain fits within depth; should not be truncated")
+	}
+}
+
+func TestCollectEndpointData_TruncatesAtMaxDepth(t *testing.T) {
+	ep := postgres.Symbol{ID: uuid.New(), Name: "GetOrders", QualifiedName: "OrdersController.GetOrders", Kind: "endpoint"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_GetOrders", Kind: "procedure"}
+	orders := postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table"}
+
+	g := newFakeEndpointGraph(ep, proc, orders)
+	g.link(ep, proc, "calls")
+	g.link(proc, orders, "reads_from")
+
+	data := collectEndpointData(context.Background(), g, ep, 1)
+	if len(data.Tables) != 0 {
+		t.Errorf("expected no tables within depth 1, got %d", len(data.Tables))
+	}
+	if !data.Truncated {
+		t.Error("expected truncation when the chain continues past max depth")
+	}
+	if data.Route != "OrdersController.GetOrders" {
+		t.Errorf("expected qualified name as route fallback, got %s", data.Route)
+	}
+}
+
+func TestEndpointRoute_Metadata(t *testing.T) {
+	sym := postgres.Symbol{Metadata: []byte(`{"http_method":"get","route":"/api/users/{id}"}`)}
+	method, route := endpointRoute(sym)
+	if method != "GET" || route != "/api/users/{id}" {
+		t.Errorf("expected GET /api/users/{id}, got %q %q", method, route)
+	}
+}
@@ -55,6 +55,7 @@ func (h *ListProjectsHandler) Handle(ctx context.Context, params ListProjectsPar
 	}
 
 	if len(projects) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
 		return "No projects found.", nil
 	}
 
@@ -71,5 +72,6 @@ func (h *ListProjectsHandler) Handle(ctx context.Context, params ListProjectsPar
 		}
 	}
 
+	mcp.RecordResults(ctx, len(projects), len(projects))
 	return rb.Finalize(len(projects), len(projects)), nil
 }
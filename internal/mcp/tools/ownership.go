@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// ownershipLookup is the store query behind include_ownership.
+type ownershipLookup interface {
+	ListSymbolOwnership(ctx context.Context, symbolIds []uuid.UUID) ([]postgres.ListSymbolOwnershipRow, error)
+}
+
+// loadOwners fetches the last-commit owner of each symbol's file in one query. Symbols
+// without ownership data (non-git sources, or track_ownership off) are left out.
+func loadOwners(ctx context.Context, g ownershipLookup, ids []uuid.UUID) (map[uuid.UUID]mcp.Owner, error) {
+	owners := make(map[uuid.UUID]mcp.Owner)
+	if len(ids) == 0 {
+		return owners, nil
+	}
+	rows, err := g.ListSymbolOwnership(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load ownership: %w", err)
+	}
+	for _, r := range rows {
+		if r.LastAuthor == nil {
+			continue
+		}
+		owners[r.SymbolID] = mcp.Owner{Author: *r.LastAuthor, CommittedAt: r.LastCommitAt.Time}
+	}
+	return owners, nil
+}
+
+// ownerSuffix renders a symbol's owner for one-line listings, or "" when unknown.
+func ownerSuffix(owners map[uuid.UUID]mcp.Owner, id uuid.UUID) string {
+	o, ok := owners[id]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" — last commit: %s", o)
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Readiness gate modes, set per project as settings.readiness_gate.
+const (
+	readinessGateWarn   = "warn" // default: prepend a warning to results
+	readinessGateRefuse = "refuse"
+	readinessGateOff    = "off"
+)
+
+// projectLookup is the subset of the store needed to check project readiness.
+type projectLookup interface {
+	GetProject(ctx context.Context, slug string) (postgres.Project, error)
+}
+
+// projectScoped is implemented by tool params that target a single project.
+type projectScoped interface {
+	projectSlug() string
+}
+
+// machineReadable is implemented by tool params that can request JSON output, which a
+// prepended warning would corrupt.
+type machineReadable interface {
+	machineReadable() bool
+}
+
+func (p AnalyzeFileImpactParams) projectSlug() string   { return p.Project }
+func (p AnalyzeImpactParams) projectSlug() string       { return p.Project }
+func (p AskCodebaseParams) projectSlug() string         { return p.Project }
+func (p CallTreeParams) projectSlug() string            { return p.Project }
+func (p ExplainConnectionParams) projectSlug() string   { return p.Project }
+func (p ExtractSubgraphParams) projectSlug() string     { return p.Project }
+func (p FindConfigReadersParams) projectSlug() string   { return p.Project }
+func (p FindUnusedSymbolsParams) projectSlug() string   { return p.Project }
+func (p GetLineageParams) projectSlug() string          { return p.Project }
+func (p GetProjectAnalyticsParams) projectSlug() string { return p.Project }
+func (p ListEndpointsParams) projectSlug() string       { return p.Project }
+func (p SchemaDiffParams) projectSlug() string          { return p.Project }
+func (p SearchSymbolsParams) projectSlug() string       { return p.Project }
+func (p SemanticSearchParams) projectSlug() string      { return p.Project }
+func (p TraceColumnLineageParams) projectSlug() string  { return p.Project }
+func (p TraceCrossLanguageParams) projectSlug() string  { return p.Project }
+func (p TraceToUIParams) projectSlug() string           { return p.Project }
+
+func (p ExtractSubgraphParams) machineReadable() bool {
+	return p.Output == "edges" || p.Output == "dot"
+}
+
+// readinessGate wraps a project-scoped tool handler with the readiness check.
+type readinessGate[P any] struct {
+	store projectLookup
+	next  ToolHandler[P]
+}
+
+// GateReadiness wraps h so that results for a project whose latest ingest has not finished
+// carry an "incomplete results" warning, or are refused when the project sets
+// readiness_gate to "refuse". Params that are not project-scoped pass straight through.
+func GateReadiness[P any](s projectLookup, h ToolHandler[P]) ToolHandler[P] {
+	return &readinessGate[P]{store: s, next: h}
+}
+
+func (g *readinessGate[P]) Handle(ctx context.Context, params P) (string, error) {
+	scoped, ok := any(params).(projectScoped)
+	if !ok {
+		return g.next.Handle(ctx, params)
+	}
+	// Lookup and access errors are left to the wrapped handler to report.
+	project, err := g.store.GetProject(ctx, scoped.projectSlug())
+	if err != nil {
+		return g.next.Handle(ctx, params)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return g.next.Handle(ctx, params)
+	}
+
+	note := ""
+	if project.Readiness != "" && project.Readiness != store.ReadinessReady {
+		switch readinessGateMode(project.Settings) {
+		case readinessGateOff:
+		case readinessGateRefuse:
+			return "", fmt.Errorf("project %s is not ready (%s); try again when indexing completes", project.Slug, readinessLabel(project.Readiness))
+		default:
+			note = fmt.Sprintf("> **Warning:** results may be incomplete: %s.", readinessLabel(project.Readiness))
+		}
+	}
+
+	out, err := g.next.Handle(ctx, params)
+	if err != nil || note == "" {
+		return out, err
+	}
+	if mr, ok := any(params).(machineReadable); ok && mr.machineReadable() {
+		return out, nil
+	}
+	return note + "\n\n" + out, nil
+}
+
+// readinessGateMode reads settings.readiness_gate, defaulting to warn.
+func readinessGateMode(settings []byte) string {
+	if len(settings) == 0 {
+		return readinessGateWarn
+	}
+	var s struct {
+		ReadinessGate string `json:"readiness_gate"`
+	}
+	if json.Unmarshal(settings, &s) != nil {
+		return readinessGateWarn
+	}
+	switch s.ReadinessGate {
+	case readinessGateRefuse, readinessGateOff:
+		return s.ReadinessGate
+	default:
+		return readinessGateWarn
+	}
+}
+
+func readinessLabel(readiness string) string {
+	switch readiness {
+	case store.ReadinessIndexing:
+		return "indexing in progress"
+	case store.ReadinessResolving:
+		return "cross-file resolution pending"
+	case store.ReadinessAnalyticsPending:
+		return "analytics pending"
+	default:
+		return readiness
+	}
+}
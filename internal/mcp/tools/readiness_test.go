@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+type fakeProjectLookup map[string]postgres.Project
+
+func (f fakeProjectLookup) GetProject(_ context.Context, slug string) (postgres.Project, error) {
+	p, ok := f[slug]
+	if !ok {
+		return postgres.Project{}, errors.New("not found")
+	}
+	return p, nil
+}
+
+type stubHandler[P any] struct{ out string }
+
+func (h stubHandler[P]) Handle(context.Context, P) (string, error) { return h.out, nil }
+
+func TestGateReadiness(t *testing.T) {
+	projects := fakeProjectLookup{
+		"fresh":   {Slug: "fresh", Readiness: store.ReadinessAnalyticsPending},
+		"strict":  {Slug: "strict", Readiness: store.ReadinessResolving, Settings: []byte(`{"readiness_gate":"refuse"}`)},
+		"done":    {Slug: "done", Readiness: store.ReadinessReady},
+		"unaware": {Slug: "unaware", Readiness: store.ReadinessIndexing, Settings: []byte(`{"readiness_gate":"off"}`)},
+	}
+	search := GateReadiness[SearchSymbolsParams](projects, stubHandler[SearchSymbolsParams]{out: "**Results**"})
+
+	out, err := search.Handle(context.Background(), SearchSymbolsParams{Project: "fresh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "> **Warning:** results may be incomplete: analytics pending.") || !strings.HasSuffix(out, "**Results**") {
+		t.Errorf("expected warning before results, got %q", out)
+	}
+
+	if _, err := search.Handle(context.Background(), SearchSymbolsParams{Project: "strict"}); err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("expected refusal for readiness_gate=refuse, got %v", err)
+	}
+
+	for _, slug := range []string{"done", "unaware", "missing"} {
+		if out, _ := search.Handle(context.Background(), SearchSymbolsParams{Project: slug}); out != "**Results**" {
+			t.Errorf("%s: expected unmodified results, got %q", slug, out)
+		}
+	}
+
+	edges := GateReadiness[ExtractSubgraphParams](projects, stubHandler[ExtractSubgraphParams]{out: `{"nodes":[]}`})
+	if out, _ := edges.Handle(context.Background(), ExtractSubgraphParams{Project: "fresh", Output: "edges"}); out != `{"nodes":[]}` {
+		t.Errorf("expected JSON output left intact, got %q", out)
+	}
+}
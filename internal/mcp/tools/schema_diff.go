@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/schema"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// SchemaDiffParams are the parameters for the schema_diff tool.
+type SchemaDiffParams struct {
+	Project string `json:"project"`
+	RunA    string `json:"run_a,omitempty"` // older index run ID, default: the run before run_b
+	RunB    string `json:"run_b,omitempty"` // newer index run ID, default: the latest run
+}
+
+// SchemaDiffHandler implements the schema_diff MCP tool.
+type SchemaDiffHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewSchemaDiffHandler creates a new handler.
+func NewSchemaDiffHandler(s *store.Store, logger *slog.Logger) *SchemaDiffHandler {
+	return &SchemaDiffHandler{store: s, logger: logger}
+}
+
+// Handle compares the schema snapshots of two index runs.
+func (h *SchemaDiffHandler) Handle(ctx context.Context, params SchemaDiffParams) (string, error) {
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	runA, err := parseRunID(params.RunA)
+	if err != nil {
+		return "", fmt.Errorf("invalid run_a: %w", err)
+	}
+	runB, err := parseRunID(params.RunB)
+	if err != nil {
+		return "", fmt.Errorf("invalid run_b: %w", err)
+	}
+
+	a, b, err := schema.LoadRuns(ctx, h.store, project.ID, runA, runB)
+	if err != nil {
+		return "", err
+	}
+	snapA, err := schema.Decode(a.Snapshot)
+	if err != nil {
+		return "", err
+	}
+	snapB, err := schema.Decode(b.Snapshot)
+	if err != nil {
+		return "", err
+	}
+
+	d := schema.Compare(snapA, snapB)
+	n := schemaChangeCount(d)
+	mcp.RecordResults(ctx, n, n)
+	return formatSchemaDiff(project.Slug, a, b, d), nil
+}
+
+// parseRunID parses an optional index run ID; empty means "use the default run".
+func parseRunID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
+
+func schemaChangeCount(d *schema.Diff) int {
+	return len(d.AddedTables) + len(d.DroppedTables) +
+		len(d.AddedColumns) + len(d.DroppedColumns) + len(d.RetypedColumns) +
+		len(d.AddedForeignKeys) + len(d.DroppedForeignKeys)
+}
+
+// formatSchemaDiff renders a diff for migration review, destructive changes first.
+func formatSchemaDiff(project string, a, b postgres.SchemaSnapshot, d *schema.Diff) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Schema diff for %s**: run `%s` (%s) → run `%s` (%s)",
+		project, a.IndexRunID, a.CreatedAt.Format("2006-01-02 15:04"), b.IndexRunID, b.CreatedAt.Format("2006-01-02 15:04")))
+
+	total := schemaChangeCount(d)
+	if total == 0 {
+		rb.AddLine("No schema changes between these runs.")
+		return rb.Finalize(0, 0)
+	}
+	rb.AddLine(fmt.Sprintf("%d tables added, %d dropped; %d columns added, %d dropped, %d retyped; %d foreign keys added, %d dropped.",
+		len(d.AddedTables), len(d.DroppedTables), len(d.AddedColumns), len(d.DroppedColumns), len(d.RetypedColumns),
+		len(d.AddedForeignKeys), len(d.DroppedForeignKeys)))
+	rb.AddLine("")
+
+	shown := 0
+	section := func(heading string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		rb.AddLine("### " + heading)
+		for _, l := range lines {
+			if !rb.AddLine(l) {
+				return
+			}
+			shown++
+		}
+		rb.AddLine("")
+	}
+
+	var lines []string
+	for _, t := range d.DroppedTables {
+		lines = append(lines, fmt.Sprintf("- `%s`", t))
+	}
+	section("Dropped tables (destructive)", lines)
+
+	lines = nil
+	for _, c := range d.DroppedColumns {
+		lines = append(lines, fmt.Sprintf("- `%s.%s`%s", c.Table, c.Column, typeSuffix(c.Type)))
+	}
+	section("Dropped columns (destructive)", lines)
+
+	lines = nil
+	for _, c := range d.RetypedColumns {
+		lines = append(lines, fmt.Sprintf("- `%s.%s`: %s → %s", c.Table, c.Column, c.OldType, c.Type))
+	}
+	section("Retyped columns", lines)
+
+	lines = nil
+	for _, fk := range d.DroppedForeignKeys {
+		lines = append(lines, fmt.Sprintf("- `%s.%s` → `%s`", fk.Table, fk.Column, fk.References))
+	}
+	section("Dropped foreign keys", lines)
+
+	lines = nil
+	for _, t := range d.AddedTables {
+		lines = append(lines, fmt.Sprintf("- `%s`", t))
+	}
+	section("Added tables", lines)
+
+	lines = nil
+	for _, c := range d.AddedColumns {
+		lines = append(lines, fmt.Sprintf("- `%s.%s`%s", c.Table, c.Column, typeSuffix(c.Type)))
+	}
+	section("Added columns", lines)
+
+	lines = nil
+	for _, fk := range d.AddedForeignKeys {
+		lines = append(lines, fmt.Sprintf("- `%s.%s` → `%s`", fk.Table, fk.Column, fk.References))
+	}
+	section("Added foreign keys", lines)
+
+	return rb.Finalize(total, shown)
+}
+
+func typeSuffix(typ string) string {
+	if typ == "" {
+		return ""
+	}
+	return " " + typ
+}
@@ -1,9 +1,16 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/maraichr/lattice/internal/auth"
 	"github.com/maraichr/lattice/internal/mcp"
@@ -18,32 +25,59 @@ type SearchSymbolsParams struct {
 	Query             string   `json:"query"`
 	Kinds             []string `json:"kinds,omitempty"`
 	Languages         []string `json:"languages,omitempty"`
+	Module            string   `json:"module,omitempty"` // monorepo module/app tag (see project module_detection)
+	Tag               string   `json:"tag,omitempty"`    // curated (metadata tags) or automatic (auto_tags, e.g. pii) symbol tag
 	Limit             int32    `json:"limit,omitempty"`
+	RankBy            string   `json:"rank_by,omitempty"` // in_degree, pagerank, betweenness; default: relevance to the query
 	Verbosity         string   `json:"verbosity,omitempty"`
 	MaxResponseTokens int      `json:"max_response_tokens,omitempty"`
 	SessionID         string   `json:"session_id,omitempty"`
+	// IncludeOwnership adds the last commit author and date of each symbol's file to
+	// its card (git sources with track_ownership only).
+	IncludeOwnership bool `json:"include_ownership,omitempty"`
+	// Cursor continues from the next_cursor of a previous page of the same search.
+	Cursor string `json:"cursor,omitempty"`
+	// NamePattern keeps the matches whose qualified name it matches: a glob, where *
+	// matches any run of characters, ? any one and \ escapes the next, matching the whole
+	// name regardless of case; or with PatternType "regex" a regular expression matching
+	// any part of it, in the syntax common to RE2 and PostgreSQL.
+	NamePattern string `json:"name_pattern,omitempty"`
+	PatternType string `json:"pattern_type,omitempty"` // glob (default) or regex
 }
 
 // SearchSymbolsHandler implements the search_symbols MCP tool.
 type SearchSymbolsHandler struct {
 	store   *store.Store
 	session *session.Manager
+	nav     *mcp.Navigator
 	logger  *slog.Logger
 }
 
 // NewSearchSymbolsHandler creates a new handler.
 func NewSearchSymbolsHandler(s *store.Store, sm *session.Manager, logger *slog.Logger) *SearchSymbolsHandler {
-	return &SearchSymbolsHandler{store: s, session: sm, logger: logger}
+	return &SearchSymbolsHandler{store: s, session: sm, nav: mcp.NewNavigator(s.Queries), logger: logger}
+}
+
+// SetNavigationRules sets the rules that pick the navigation hints in responses.
+func (h *SearchSymbolsHandler) SetNavigationRules(rules []mcp.NavigationRule) {
+	h.nav.SetRules(rules)
 }
 
 // Handle searches for symbols by name/query within a project.
 func (h *SearchSymbolsHandler) Handle(ctx context.Context, params SearchSymbolsParams) (string, error) {
-	if params.Query == "" {
-		return "", fmt.Errorf("query is required")
+	if params.Query == "" && params.NamePattern == "" {
+		return "", fmt.Errorf("query or name_pattern is required")
+	}
+	pattern, err := compileNamePattern(params.NamePattern, params.PatternType)
+	if err != nil {
+		return "", err
 	}
 	if params.Limit <= 0 {
 		params.Limit = 20
 	}
+	if err := validateRankBy(params.RankBy); err != nil {
+		return "", err
+	}
 	if params.MaxResponseTokens <= 0 {
 		params.MaxResponseTokens = 4000
 	}
@@ -65,47 +99,311 @@ func (h *SearchSymbolsHandler) Handle(ctx context.Context, params SearchSymbolsP
 		languages = []string{}
 	}
 
-	query := params.Query
-	results, err := h.store.SearchSymbols(ctx, postgres.SearchSymbolsParams{
+	cursorKey := searchCursorKey(project.Slug, params)
+	var cursor *mcp.PageCursor
+	if params.Cursor != "" {
+		c, err := mcp.DecodeCursor(params.Cursor, cursorKey)
+		if err != nil {
+			return "", fmt.Errorf("cursor: %w", err)
+		}
+		cursor = &c
+	}
+
+	var sess *session.Session
+	if h.session != nil && params.SessionID != "" {
+		sess, _ = h.session.Load(ctx, params.SessionID)
+	}
+
+	// Pages follow the database's session-independent order; the session only reorders
+	// the cards within a page
+	expansions := expandQuery(params.Query, synonymsFromSettings(project.Settings))
+	queries := make([]string, len(expansions))
+	for i, e := range expansions {
+		queries[i] = e.Query
+	}
+	arg := postgres.SearchSymbolsPageParams{
+		RankBy:      params.RankBy,
+		Queries:     queries,
 		ProjectSlug: project.Slug,
-		Query:       &query,
 		Kinds:       kinds,
 		Languages:   languages,
-		Lim:         params.Limit,
-	})
+		Module:      params.Module,
+		Tag:         params.Tag,
+		NameLike:    pattern.like,
+		NameRegex:   pattern.regex,
+		Lim:         params.Limit + 1,
+	}
+	if cursor != nil {
+		arg.AfterID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		arg.AfterScore = &cursor.Score
+	}
+	page, err := h.store.SearchSymbolsPage(ctx, arg)
 	if err != nil {
 		return "", fmt.Errorf("search symbols: %w", err)
 	}
+	more := len(page) > int(params.Limit)
+	page = page[:min(len(page), int(params.Limit))]
 
-	if len(results) == 0 {
-		return fmt.Sprintf("No symbols found matching '%s'.", params.Query), nil
+	label := searchLabel(params)
+	if len(page) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		if cursor != nil {
+			return fmt.Sprintf("No more symbols matching %s.", label), nil
+		}
+		return fmt.Sprintf("No symbols found matching %s.", label), nil
 	}
+	matches := int(page[0].Total)
 
-	var sess *session.Session
-	if h.session != nil && params.SessionID != "" {
-		sess, _ = h.session.Load(ctx, params.SessionID)
+	symbols := make([]postgres.Symbol, len(page))
+	for i, row := range page {
+		symbols[i] = pageSymbol(row)
+	}
+	if params.RankBy == "" {
+		ranked := mcp.RankSymbols(symbols, params.Query, mcp.DefaultRankConfig(), sess)
+		for i, r := range ranked {
+			symbols[i] = r.Symbol
+		}
 	}
 
 	verbosity := mcp.ParseVerbosity(params.Verbosity)
-	ranked := mcp.RankSymbols(results, params.Query, mcp.DefaultRankConfig(), sess)
-
 	rb := mcp.NewResponseBuilder(params.MaxResponseTokens)
-	rb.AddHeader(fmt.Sprintf("**Search results for: %s** (%d matches)", params.Query, len(results)))
+	if params.IncludeOwnership {
+		ids := make([]uuid.UUID, len(symbols))
+		for i, sym := range symbols {
+			ids[i] = sym.ID
+		}
+		owners, err := loadOwners(ctx, h.store, ids)
+		if err != nil {
+			return "", err
+		}
+		rb.SetOwners(owners)
+	}
+	if params.RankBy != "" {
+		rb.AddHeader(fmt.Sprintf("**Search results for: %s** (%d matches, by %s)", label, matches, centralityMetrics[params.RankBy]))
+	} else {
+		rb.AddHeader(fmt.Sprintf("**Search results for: %s** (%d matches)", label, matches))
+	}
+	addSynonymNote(rb, matchedExpansions(expansions, symbols))
 
+	// done marks the rows of the page shown as cards
+	done := make(map[uuid.UUID]bool, len(page))
 	returned := 0
-	for _, r := range ranked {
-		if !rb.AddSymbolCard(r.Symbol, verbosity, sess) {
+	for _, sym := range symbols {
+		if !rb.AddSymbolCard(sym, verbosity, sess) {
 			break
 		}
+		done[sym.ID] = true
 		returned++
 	}
 
-	nav := mcp.NewNavigator(h.store.Queries)
-	symbols := make([]postgres.Symbol, 0, len(ranked))
-	for _, r := range ranked {
-		symbols = append(symbols, r.Symbol)
+	hints := h.nav.SuggestNextSteps("search_symbols", symbols, sess)
+
+	// The next page starts after the rows this one accounted for, whether it ended at
+	// the limit or the token budget
+	if end, ok := pageEnd(page, done); ok && (more || end < len(page)-1) {
+		next := mcp.PageCursor{Score: page[end].SearchScore, ID: page[end].ID}
+		rb.SetNextCursor(next.Encode(cursorKey), searchNextStep(project.Slug, params))
+	}
+
+	mcp.RecordResults(ctx, matches, returned)
+	return rb.FinalizeWithHints(matches, returned, hints), nil
+}
+
+// centralityMetrics names the centrality measures symbols can be ranked by, keyed by
+// the rank_by value and the symbol metadata analytics stores them under.
+var centralityMetrics = map[string]string{
+	"in_degree":   "in-degree",
+	"pagerank":    "PageRank",
+	"betweenness": "betweenness",
+}
+
+// validateRankBy checks a rank_by parameter; "" leaves the default order.
+func validateRankBy(rankBy string) error {
+	if _, ok := centralityMetrics[rankBy]; rankBy != "" && !ok {
+		return fmt.Errorf("rank_by must be in_degree, pagerank or betweenness")
+	}
+	return nil
+}
+
+// searchCursorKey binds search_symbols cursors to the project, query and filters.
+func searchCursorKey(project string, params SearchSymbolsParams) string {
+	kinds, languages := slices.Clone(params.Kinds), slices.Clone(params.Languages)
+	slices.Sort(kinds)
+	slices.Sort(languages)
+	return mcp.CursorKey("search_symbols", project, params.Query, strings.Join(kinds, ","),
+		strings.Join(languages, ","), params.Module, params.RankBy, params.NamePattern,
+		cmp.Or(params.PatternType, "glob"), params.Tag)
+}
+
+// searchNextStep is the call fetching the next page of a search: every parameter
+// searchCursorKey binds the cursor to, so the cursor is accepted, plus the ones shaping
+// the page.
+func searchNextStep(project string, params SearchSymbolsParams) mcp.NavigationStep {
+	step := mcp.NavigationStep{
+		Tool:   "search_symbols",
+		Params: map[string]string{"project": project},
+	}
+	if params.Query != "" {
+		step.Params["query"] = params.Query
+	}
+	if params.NamePattern != "" {
+		step.Params["name_pattern"] = params.NamePattern
+		step.Params["pattern_type"] = cmp.Or(params.PatternType, "glob")
+	}
+	if params.Module != "" {
+		step.Params["module"] = params.Module
+	}
+	if params.Tag != "" {
+		step.Params["tag"] = params.Tag
+	}
+	if params.RankBy != "" {
+		step.Params["rank_by"] = params.RankBy
+	}
+	if params.Verbosity != "" {
+		step.Params["verbosity"] = params.Verbosity
+	}
+	if len(params.Kinds) > 0 || len(params.Languages) > 0 {
+		step.ListParams = make(map[string][]string)
+		if len(params.Kinds) > 0 {
+			step.ListParams["kinds"] = params.Kinds
+		}
+		if len(params.Languages) > 0 {
+			step.ListParams["languages"] = params.Languages
+		}
+	}
+	return step
+}
+
+// searchLabel describes a search in responses: its query and name pattern.
+func searchLabel(params SearchSymbolsParams) string {
+	var parts []string
+	if params.Query != "" {
+		parts = append(parts, fmt.Sprintf("'%s'", params.Query))
+	}
+	if params.NamePattern != "" {
+		parts = append(parts, fmt.Sprintf("name %s `%s`", cmp.Or(params.PatternType, "glob"), params.NamePattern))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// namePattern is a compiled name_pattern: the LIKE pattern and, for a regex, the
+// expression the query filters qualified names by.
+type namePattern struct {
+	like  string
+	regex string
+}
+
+// compileNamePattern compiles a name_pattern of the given pattern_type. A glob becomes a
+// LIKE pattern. A regex is checked with RE2, so that a malformed one is reported as such
+// rather than as a query error, and is matched by the query, with its literal prefix,
+// which every match contains, as a LIKE ahead of it.
+func compileNamePattern(pattern, patternType string) (namePattern, error) {
+	if pattern == "" {
+		return namePattern{}, nil
+	}
+	switch patternType {
+	case "", "glob":
+		like, err := globToLike(pattern)
+		if err != nil {
+			return namePattern{}, err
+		}
+		return namePattern{like: like}, nil
+	case "regex":
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return namePattern{}, fmt.Errorf("invalid name_pattern: %w", err)
+		}
+		p := namePattern{regex: pattern}
+		if prefix, _ := re.LiteralPrefix(); prefix != "" {
+			p.like = "%" + likeEscaper.Replace(prefix) + "%"
+		}
+		return p, nil
+	}
+	return namePattern{}, fmt.Errorf("pattern_type must be glob or regex")
+}
+
+// likeEscaper escapes the characters special to LIKE.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// globToLike translates a glob to the LIKE pattern matching the same names: * to %, ?
+// to _, and everything else, including \-escaped characters, literally.
+func globToLike(glob string) (string, error) {
+	var b strings.Builder
+	escaped := false
+	for _, r := range glob {
+		switch {
+		case escaped:
+			b.WriteString(likeEscaper.Replace(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteByte('%')
+		case r == '?':
+			b.WriteByte('_')
+		default:
+			b.WriteString(likeEscaper.Replace(string(r)))
+		}
+	}
+	if escaped {
+		return "", fmt.Errorf("invalid name_pattern: trailing \\ escapes nothing")
+	}
+	return b.String(), nil
+}
+
+// pageSymbol is the symbol of a search result row.
+func pageSymbol(r postgres.SearchSymbolsPageRow) postgres.Symbol {
+	return postgres.Symbol{
+		ID:            r.ID,
+		ProjectID:     r.ProjectID,
+		FileID:        r.FileID,
+		Name:          r.Name,
+		QualifiedName: r.QualifiedName,
+		Kind:          r.Kind,
+		Language:      r.Language,
+		StartLine:     r.StartLine,
+		EndLine:       r.EndLine,
+		StartCol:      r.StartCol,
+		EndCol:        r.EndCol,
+		Signature:     r.Signature,
+		DocComment:    r.DocComment,
+		Metadata:      r.Metadata,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+		DeletedAt:     r.DeletedAt,
+	}
+}
+
+// pageEnd returns the index of the last row of page, in page order, up to which every
+// row is done; the next page starts after it. Cards shown out of page order after a row
+// the token budget left out are shown again on the next page. ok is false when the
+// first row is not done.
+func pageEnd(page []postgres.SearchSymbolsPageRow, done map[uuid.UUID]bool) (int, bool) {
+	end := -1
+	for i, row := range page {
+		if !done[row.ID] {
+			break
+		}
+		end = i
 	}
-	hints := nav.SuggestNextSteps("search_symbols", symbols, sess)
+	return end, end >= 0
+}
 
-	return rb.FinalizeWithHints(len(results), returned, hints), nil
+// matchedExpansions returns the notes of the synonym rewrites that match a symbol of
+// the page.
+func matchedExpansions(expansions []expandedQuery, symbols []postgres.Symbol) []string {
+	var notes []string
+	for _, e := range expansions {
+		if e.Note == "" {
+			continue
+		}
+		q := strings.ToLower(e.Query)
+		for _, sym := range symbols {
+			if strings.Contains(strings.ToLower(sym.Name), q) || strings.Contains(strings.ToLower(sym.QualifiedName), q) {
+				notes = append(notes, e.Note)
+				break
+			}
+		}
+	}
+	return notes
 }
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestValidateRankBy(t *testing.T) {
+	if err := validateRankBy("closeness"); err == nil {
+		t.Error("expected an unknown rank_by to be rejected")
+	}
+	if err := validateRankBy(""); err != nil {
+		t.Errorf("expected an empty rank_by to be accepted, got %v", err)
+	}
+}
+
+func TestPageEnd(t *testing.T) {
+	page := make([]postgres.SearchSymbolsPageRow, 4)
+	for i := range page {
+		page[i].ID = uuid.New()
+	}
+	done := func(rows ...int) map[uuid.UUID]bool {
+		m := make(map[uuid.UUID]bool)
+		for _, i := range rows {
+			m[page[i].ID] = true
+		}
+		return m
+	}
+
+	if end, ok := pageEnd(page, done(0, 1, 2, 3)); !ok || end != 3 {
+		t.Errorf("expected a fully shown page to end at its last row, got %d, %v", end, ok)
+	}
+	// The session moved row 3 ahead of row 2 and the token budget cut row 2: the next
+	// page starts at row 2 rather than skipping it
+	if end, ok := pageEnd(page, done(0, 1, 3)); !ok || end != 1 {
+		t.Errorf("expected the page to end before the first row left out, got %d, %v", end, ok)
+	}
+	if _, ok := pageEnd(page, done(1, 2)); ok {
+		t.Error("expected no end when the first row was left out")
+	}
+}
+
+func TestSearchCursorKey(t *testing.T) {
+	key := searchCursorKey("shop", SearchSymbolsParams{Query: "order", Kinds: []string{"table", "view"}})
+
+	// The kinds filter is part of the key, in any order; another query is not
+	encoded := mcp.PageCursor{ID: uuid.New()}.Encode(key)
+	if _, err := mcp.DecodeCursor(encoded, searchCursorKey("shop", SearchSymbolsParams{Query: "order", Kinds: []string{"view", "table"}})); err != nil {
+		t.Errorf("expected the cursor to fit the same filters, got %v", err)
+	}
+	if _, err := mcp.DecodeCursor(encoded, searchCursorKey("shop", SearchSymbolsParams{Query: "invoice", Kinds: []string{"table", "view"}})); !errors.Is(err, mcp.ErrCursorMismatch) {
+		t.Errorf("expected a cursor replayed against another query to be refused, got %v", err)
+	}
+}
+
+func TestSearchNextStep_CarriesCursorKey(t *testing.T) {
+	params := SearchSymbolsParams{
+		Project:     "shop",
+		Query:       "order",
+		Kinds:       []string{"view", "table"},
+		Languages:   []string{"tsql"},
+		Module:      "billing",
+		RankBy:      "pagerank",
+		NamePattern: "dbo.*",
+	}
+	cursor := mcp.PageCursor{Score: 0.5, ID: uuid.New()}.Encode(searchCursorKey("shop", params))
+
+	// Call the step as suggested: its parameters and the cursor, nothing else
+	step := searchNextStep("shop", params)
+	args := map[string]any{"cursor": cursor}
+	for k, v := range step.Params {
+		args[k] = v
+	}
+	for k, v := range step.ListParams {
+		args[k] = v
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var next SearchSymbolsParams
+	if err := json.Unmarshal(raw, &next); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mcp.DecodeCursor(next.Cursor, searchCursorKey(next.Project, next)); err != nil {
+		t.Errorf("expected the suggested step to accept its cursor, got %v (params %v)", err, args)
+	}
+}
+
+func TestCompileNamePattern(t *testing.T) {
+	p, err := compileNamePattern(`dbo.usp_Get*Order?`, "")
+	if err != nil || p.regex != "" || p.like != `dbo.usp\_Get%Order_` {
+		t.Errorf("glob: got %+v, %v", p, err)
+	}
+	if p, _ := compileNamePattern(`a\*b%`, "glob"); p.like != `a*b\%` {
+		t.Errorf("expected escaped glob characters to match literally, got %q", p.like)
+	}
+	if _, err := compileNamePattern(`a\`, "glob"); err == nil {
+		t.Error("expected a trailing escape to be rejected")
+	}
+
+	p, err = compileNamePattern(`^billing\.(Invoice|Payment)Service$`, "regex")
+	if err != nil || p.regex != `^billing\.(Invoice|Payment)Service$` {
+		t.Fatalf("expected the regex passed to the query, got %+v, %v", p, err)
+	}
+	if p.like != `%billing.%` {
+		t.Errorf("expected the literal prefix to narrow the query, got %q", p.like)
+	}
+	if p, _ := compileNamePattern(`Service$`, "regex"); p.like != "%Service%" {
+		t.Errorf("unexpected prefilter %q", p.like)
+	}
+	if p, _ := compileNamePattern(`.*Repo`, "regex"); p.like != "" {
+		t.Errorf("expected no prefilter without a literal prefix, got %q", p.like)
+	}
+
+	if _, err := compileNamePattern(`(Order`, "regex"); err == nil {
+		t.Error("expected an invalid regex to be rejected")
+	}
+	if _, err := compileNamePattern(`Order*`, "sql"); err == nil {
+		t.Error("expected an unknown pattern_type to be rejected")
+	}
+}
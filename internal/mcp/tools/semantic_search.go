@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 
+	"github.com/google/uuid"
 	pgvector_go "github.com/pgvector/pgvector-go"
 
 	"github.com/maraichr/lattice/internal/auth"
@@ -14,17 +17,46 @@ import (
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
 
+// Semantic search bounds: matches less similar to the query than defaultMinSimilarity
+// (cosine similarity, 1 - distance) are dropped, so an off-topic query finds nothing
+// rather than its least distant symbols.
+const (
+	defaultMinSimilarity = 0.3
+	defaultSemanticLimit = 10
+	maxSemanticLimit     = 50
+)
+
+// Hybrid search fuses the vector and keyword rankings by reciprocal rank fusion: a
+// symbol scores weight/(rrfK+rank) in each ranking it appears in. rrfK = 60 is the
+// constant of the original RRF paper; it keeps the top few ranks from dominating.
+const (
+	rrfK                 = 60
+	defaultKeywordWeight = 0.5
+	keywordSearchPool    = 200 // name matches ranked for fusion, SearchSymbols returns them by name
+)
+
 // SemanticSearchParams are the parameters for the semantic_search tool.
 type SemanticSearchParams struct {
-	Project string   `json:"project"`
-	Query   string   `json:"query"`
-	Kinds   []string `json:"kinds,omitempty"`
-	TopK    int32    `json:"top_k,omitempty"`
+	Project       string   `json:"project"`
+	Query         string   `json:"query"`
+	Kinds         []string `json:"kinds,omitempty"`
+	Mode          string   `json:"mode,omitempty"`           // vector, keyword, hybrid (default)
+	Limit         int32    `json:"limit,omitempty"`          // max results, default: 10, max: 50
+	TopK          int32    `json:"top_k,omitempty"`          // former name of limit
+	MinSimilarity float64  `json:"min_similarity,omitempty"` // 0-1, default: 0.3
+	KeywordWeight *float64 `json:"keyword_weight,omitempty"` // 0-1 share of the keyword ranking in hybrid mode, default: 0.5
+}
+
+// semanticStore is the subset of the store semantic search reads.
+type semanticStore interface {
+	GetProject(ctx context.Context, slug string) (postgres.Project, error)
+	SemanticSearch(ctx context.Context, arg postgres.SemanticSearchParams) ([]postgres.SemanticSearchRow, error)
+	SearchSymbols(ctx context.Context, arg postgres.SearchSymbolsParams) ([]postgres.Symbol, error)
 }
 
 // SemanticSearchHandler implements the semantic_search MCP tool.
 type SemanticSearchHandler struct {
-	store    *store.Store
+	store    semanticStore
 	embedder embedding.Embedder
 	logger   *slog.Logger
 }
@@ -34,16 +66,59 @@ func NewSemanticSearchHandler(s *store.Store, embedder embedding.Embedder, logge
 	return &SemanticSearchHandler{store: s, embedder: embedder, logger: logger}
 }
 
-// Handle performs semantic (vector) search over symbols.
+// searchHit is a symbol found by the vector search, the keyword search or both.
+type searchHit struct {
+	id            uuid.UUID
+	fileID        uuid.UUID
+	name          string
+	qualifiedName string
+	kind          string
+	language      string
+	startLine     int32
+	endLine       int32
+	signature     *string
+	similarity    float64 // cosine similarity; valid when vectorRank > 0
+	relevance     float64 // name match score; valid when keywordRank > 0
+	vectorRank    int     // 1-based, 0 when not a vector match
+	keywordRank   int     // 1-based, 0 when not a keyword match
+	score         float64 // fused score in hybrid mode
+}
+
+// Handle searches symbols by embedding similarity, by name, or by both fused.
 func (h *SemanticSearchHandler) Handle(ctx context.Context, params SemanticSearchParams) (string, error) {
-	if h.embedder == nil {
-		return "", fmt.Errorf("semantic search is not available: no embedding provider configured. Set OPENROUTER_API_KEY or BEDROCK_REGION")
+	if params.Mode == "" {
+		params.Mode = "hybrid"
+	}
+	if params.Mode != "vector" && params.Mode != "keyword" && params.Mode != "hybrid" {
+		return "", fmt.Errorf("mode must be vector, keyword or hybrid")
+	}
+	if h.embedder == nil && params.Mode != "keyword" {
+		return "", fmt.Errorf("semantic search is not available: no embedding provider configured. Set OPENROUTER_API_KEY or BEDROCK_REGION, or use mode=keyword")
 	}
 	if params.Query == "" {
 		return "", fmt.Errorf("query is required")
 	}
-	if params.TopK <= 0 {
-		params.TopK = 10
+	limit := params.Limit
+	if limit <= 0 {
+		limit = params.TopK
+	}
+	if limit <= 0 {
+		limit = defaultSemanticLimit
+	}
+	limit = min(limit, maxSemanticLimit)
+	minSimilarity := params.MinSimilarity
+	if minSimilarity <= 0 {
+		minSimilarity = defaultMinSimilarity
+	}
+	if minSimilarity > 1 {
+		return "", fmt.Errorf("min_similarity must be between 0 and 1")
+	}
+	keywordWeight := defaultKeywordWeight
+	if params.KeywordWeight != nil {
+		keywordWeight = *params.KeywordWeight
+	}
+	if keywordWeight < 0 || keywordWeight > 1 {
+		return "", fmt.Errorf("keyword_weight must be between 0 and 1")
 	}
 
 	project, err := h.store.GetProject(ctx, params.Project)
@@ -54,51 +129,205 @@ func (h *SemanticSearchHandler) Handle(ctx context.Context, params SemanticSearc
 		return "", fmt.Errorf("access denied to project %s", params.Project)
 	}
 
-	// Embed the query
-	vectors, err := h.embedder.EmbedBatch(ctx, []string{params.Query}, "search_query")
-	if err != nil {
-		return "", fmt.Errorf("embed query: %w", err)
-	}
-	if len(vectors) == 0 || len(vectors[0]) == 0 {
-		return "", fmt.Errorf("embedding returned empty vector")
-	}
-
 	kinds := params.Kinds
 	if kinds == nil {
 		kinds = []string{}
 	}
 
+	var vectorHits, keywordHits []searchHit
+	if params.Mode != "keyword" {
+		vectorHits, err = h.vectorSearch(ctx, project, params.Query, kinds, limit, minSimilarity)
+		if err != nil {
+			return "", err
+		}
+	}
+	if params.Mode != "vector" {
+		keywordHits, err = h.keywordSearch(ctx, project, params.Query, kinds)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var matches []searchHit
+	switch params.Mode {
+	case "vector":
+		matches = vectorHits
+	case "keyword":
+		matches = keywordHits
+	default:
+		matches = fuseRankings(vectorHits, keywordHits, 1-keywordWeight, keywordWeight)
+	}
+	if len(matches) > int(limit) {
+		matches = matches[:limit]
+	}
+
+	if len(matches) == 0 {
+		mcp.RecordResults(ctx, 0, 0)
+		if params.Mode == "keyword" {
+			return fmt.Sprintf("No symbols found matching '%s'.", params.Query), nil
+		}
+		return fmt.Sprintf("No sufficiently similar symbols found for '%s' (min_similarity %.2f). Try rephrasing the query or lowering min_similarity.",
+			params.Query, minSimilarity), nil
+	}
+
+	rb := mcp.NewResponseBuilder(4000)
+	switch params.Mode {
+	case "vector":
+		rb.AddHeader(fmt.Sprintf("**Semantic Search: %s** (%d results, similarity ≥ %.2f)", params.Query, len(matches), minSimilarity))
+	case "keyword":
+		rb.AddHeader(fmt.Sprintf("**Semantic Search: %s** (%d results, keyword)", params.Query, len(matches)))
+	default:
+		rb.AddHeader(fmt.Sprintf("**Semantic Search: %s** (%d results, hybrid, similarity ≥ %.2f)", params.Query, len(matches), minSimilarity))
+	}
+
+	for i, m := range matches {
+		sig := ""
+		if m.signature != nil {
+			sig = fmt.Sprintf("\n  Signature: `%s`", *m.signature)
+		}
+		rb.AddLine(fmt.Sprintf("%d. **%s** `%s` (%s)\n   %s [%s] %s:%d-%d%s",
+			i+1, m.kind, m.name, hitScores(m),
+			m.qualifiedName, m.language,
+			m.fileID.String()[:8], m.startLine, m.endLine, sig))
+	}
+
+	mcp.RecordResults(ctx, len(matches), len(matches))
+	return rb.Finalize(len(matches), len(matches)), nil
+}
+
+// vectorSearch returns the symbols nearest the query's embedding at least minSimilarity
+// similar to it, most similar first.
+func (h *SemanticSearchHandler) vectorSearch(ctx context.Context, project postgres.Project, query string, kinds []string, limit int32, minSimilarity float64) ([]searchHit, error) {
+	vectors, err := h.embedder.EmbedBatch(ctx, []string{query}, "search_query")
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return nil, fmt.Errorf("embedding returned empty vector")
+	}
+
 	results, err := h.store.SemanticSearch(ctx, postgres.SemanticSearchParams{
 		QueryEmbedding: pgvector_go.NewVector(vectors[0]),
 		ProjectID:      project.ID,
 		Kinds:          kinds,
-		Lim:            params.TopK,
+		Lim:            limit,
 	})
 	if err != nil {
-		return "", fmt.Errorf("semantic search: %w", err)
+		return nil, fmt.Errorf("semantic search: %w", err)
 	}
 
-	if len(results) == 0 {
-		return fmt.Sprintf("No semantic matches found for '%s'.", params.Query), nil
+	// Results come most similar first, so the weak matches are a tail.
+	var hits []searchHit
+	for _, r := range results {
+		sim, ok := similarity(r.Distance)
+		if !ok || sim < minSimilarity {
+			continue
+		}
+		hits = append(hits, searchHit{
+			id: r.ID, fileID: r.FileID, name: r.Name, qualifiedName: r.QualifiedName,
+			kind: r.Kind, language: r.Language, startLine: r.StartLine, endLine: r.EndLine,
+			signature: r.Signature, similarity: sim, vectorRank: len(hits) + 1,
+		})
 	}
+	return hits, nil
+}
 
-	rb := mcp.NewResponseBuilder(4000)
-	rb.AddHeader(fmt.Sprintf("**Semantic Search: %s** (%d results)", params.Query, len(results)))
+// keywordSearch returns the symbols whose name contains the query, ranked by how
+// closely the name matches it as search_symbols ranks them: exact names first.
+func (h *SemanticSearchHandler) keywordSearch(ctx context.Context, project postgres.Project, query string, kinds []string) ([]searchHit, error) {
+	results, err := h.store.SearchSymbols(ctx, postgres.SearchSymbolsParams{
+		ProjectSlug: project.Slug,
+		Query:       &query,
+		Kinds:       kinds,
+		Languages:   []string{},
+		Lim:         keywordSearchPool,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search symbols: %w", err)
+	}
 
-	for i, r := range results {
-		sig := ""
-		if r.Signature != nil {
-			sig = fmt.Sprintf("\n  Signature: `%s`", *r.Signature)
+	ranked := mcp.RankSymbols(results, query, mcp.RankConfig{QueryRelevance: 1}, nil)
+	hits := make([]searchHit, 0, len(ranked))
+	for _, r := range ranked {
+		s := r.Symbol
+		hits = append(hits, searchHit{
+			id: s.ID, fileID: s.FileID, name: s.Name, qualifiedName: s.QualifiedName,
+			kind: s.Kind, language: s.Language, startLine: s.StartLine, endLine: s.EndLine,
+			signature: s.Signature, relevance: r.Score, keywordRank: len(hits) + 1,
+		})
+	}
+	return hits, nil
+}
+
+// fuseRankings merges the vector and keyword rankings by weighted reciprocal rank
+// fusion. Equal fused scores go to the closer name match, then the more similar
+// symbol, then by qualified name, so the order never depends on map iteration.
+func fuseRankings(vector, keyword []searchHit, vectorWeight, keywordWeight float64) []searchHit {
+	byID := make(map[uuid.UUID]*searchHit, len(vector)+len(keyword))
+	var fused []*searchHit
+	for _, v := range vector {
+		hit := v
+		byID[v.id] = &hit
+		fused = append(fused, &hit)
+	}
+	for _, k := range keyword {
+		if hit, ok := byID[k.id]; ok {
+			hit.relevance, hit.keywordRank = k.relevance, k.keywordRank
+			continue
 		}
-		dist := ""
-		if r.Distance != nil {
-			dist = fmt.Sprintf(" (distance: %v)", r.Distance)
+		hit := k
+		byID[k.id] = &hit
+		fused = append(fused, &hit)
+	}
+
+	out := make([]searchHit, 0, len(fused))
+	for _, hit := range fused {
+		if hit.vectorRank > 0 {
+			hit.score += vectorWeight / float64(rrfK+hit.vectorRank)
 		}
-		rb.AddLine(fmt.Sprintf("%d. **%s** `%s`%s\n   %s [%s] %s:%d-%d%s",
-			i+1, r.Kind, r.Name, dist,
-			r.QualifiedName, r.Language,
-			r.FileID.String()[:8], r.StartLine, r.EndLine, sig))
+		if hit.keywordRank > 0 {
+			hit.score += keywordWeight / float64(rrfK+hit.keywordRank)
+		}
+		out = append(out, *hit)
 	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		if out[i].relevance != out[j].relevance {
+			return out[i].relevance > out[j].relevance
+		}
+		if out[i].similarity != out[j].similarity {
+			return out[i].similarity > out[j].similarity
+		}
+		return out[i].qualifiedName < out[j].qualifiedName
+	})
+	return out
+}
+
+// hitScores describes why a symbol matched: its similarity, its name match, or both.
+func hitScores(m searchHit) string {
+	var parts []string
+	if m.vectorRank > 0 {
+		parts = append(parts, fmt.Sprintf("similarity: %.2f", m.similarity))
+	}
+	if m.keywordRank > 0 {
+		match := "name match"
+		if m.relevance >= 1 {
+			match = "exact name"
+		}
+		parts = append(parts, match)
+	}
+	return strings.Join(parts, ", ")
+}
 
-	return rb.Finalize(len(results), len(results)), nil
+// similarity converts a pgvector cosine distance to a cosine similarity.
+func similarity(distance any) (float64, bool) {
+	switch d := distance.(type) {
+	case float64:
+		return 1 - d, true
+	case float32:
+		return 1 - float64(d), true
+	}
+	return 0, false
 }
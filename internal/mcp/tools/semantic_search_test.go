@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+type fakeSemanticStore struct {
+	results []postgres.SemanticSearchRow
+	named   []postgres.Symbol // SearchSymbols results, by name as the query returns them
+	lim     int32
+}
+
+func (f *fakeSemanticStore) GetProject(_ context.Context, slug string) (postgres.Project, error) {
+	return postgres.Project{ID: uuid.New(), Slug: slug}, nil
+}
+
+func (f *fakeSemanticStore) SemanticSearch(_ context.Context, arg postgres.SemanticSearchParams) ([]postgres.SemanticSearchRow, error) {
+	f.lim = arg.Lim
+	return f.results, nil
+}
+
+func (f *fakeSemanticStore) SearchSymbols(_ context.Context, arg postgres.SearchSymbolsParams) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, s := range f.named {
+		if strings.Contains(strings.ToLower(s.QualifiedName), strings.ToLower(*arg.Query)) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+type constEmbedder struct{}
+
+func (constEmbedder) EmbedBatch(_ context.Context, texts []string, _ string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = []float32{1, 0}
+	}
+	return out, nil
+}
+
+func (constEmbedder) ModelID() string { return "const" }
+
+func TestSemanticSearch_MinSimilarity(t *testing.T) {
+	row := func(name string, distance float64) postgres.SemanticSearchRow {
+		return postgres.SemanticSearchRow{ID: uuid.New(), FileID: uuid.New(), Name: name, QualifiedName: "dbo." + name, Kind: "procedure", Distance: distance}
+	}
+	s := &fakeSemanticStore{results: []postgres.SemanticSearchRow{
+		row("usp_GetInvoices", 0.18),
+		row("usp_ListPayments", 0.55),
+		row("usp_PurgeLogs", 0.82),
+	}}
+	h := &SemanticSearchHandler{store: s, embedder: constEmbedder{}}
+
+	out, err := h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "invoices", Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.lim != 5 {
+		t.Errorf("expected the limit passed to the query, got %d", s.lim)
+	}
+	if !strings.Contains(out, "usp_GetInvoices` (similarity: 0.82)") || !strings.Contains(out, "usp_ListPayments` (similarity: 0.45)") {
+		t.Errorf("expected the matches with their similarity, got:\n%s", out)
+	}
+	if strings.Contains(out, "usp_PurgeLogs") {
+		t.Errorf("expected the match below the default threshold to be dropped, got:\n%s", out)
+	}
+
+	out, err = h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "weather", MinSimilarity: 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No sufficiently similar symbols found") {
+		t.Errorf("expected no matches above 0.9, got:\n%s", out)
+	}
+}
+
+func TestSemanticSearch_HybridExactNameFirst(t *testing.T) {
+	// The vector search ranks a conceptually similar procedure above the one the
+	// user named; only the named one matches by name.
+	similar := postgres.SemanticSearchRow{ID: uuid.New(), FileID: uuid.New(), Name: "usp_ListInvoices", QualifiedName: "dbo.usp_ListInvoices", Kind: "procedure", Distance: 0.12}
+	named := postgres.SemanticSearchRow{ID: uuid.New(), FileID: uuid.New(), Name: "usp_GetInvoice", QualifiedName: "dbo.usp_GetInvoice", Kind: "procedure", Distance: 0.2}
+	s := &fakeSemanticStore{
+		results: []postgres.SemanticSearchRow{similar, named},
+		named: []postgres.Symbol{
+			{ID: named.ID, FileID: named.FileID, Name: named.Name, QualifiedName: named.QualifiedName, Kind: "procedure"},
+			{ID: uuid.New(), FileID: uuid.New(), Name: "usp_GetInvoiceLines", QualifiedName: "dbo.usp_GetInvoiceLines", Kind: "procedure"},
+		},
+	}
+	h := &SemanticSearchHandler{store: s, embedder: constEmbedder{}}
+
+	out, err := h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "usp_GetInvoice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1. **procedure** `usp_GetInvoice` (similarity: 0.80, exact name)") {
+		t.Errorf("expected the exact name match first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "2. **procedure** `usp_ListInvoices` (similarity: 0.88)") {
+		t.Errorf("expected the purely semantic match second, got:\n%s", out)
+	}
+	if !strings.Contains(out, "`usp_GetInvoiceLines` (name match)") {
+		t.Errorf("expected the keyword-only match to be kept, got:\n%s", out)
+	}
+
+	out, err = h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "usp_GetInvoice", Mode: "vector"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1. **procedure** `usp_ListInvoices`") || strings.Contains(out, "usp_GetInvoiceLines") {
+		t.Errorf("expected vector mode to keep the similarity order, got:\n%s", out)
+	}
+
+	noKeyword := 0.0
+	out, err = h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "usp_GetInvoice", KeywordWeight: &noKeyword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1. **procedure** `usp_ListInvoices`") {
+		t.Errorf("expected keyword_weight 0 to rank by similarity, got:\n%s", out)
+	}
+}
+
+func TestSemanticSearch_KeywordModeWithoutEmbedder(t *testing.T) {
+	s := &fakeSemanticStore{named: []postgres.Symbol{
+		{ID: uuid.New(), FileID: uuid.New(), Name: "usp_GetInvoiceLines", QualifiedName: "dbo.usp_GetInvoiceLines", Kind: "procedure"},
+		{ID: uuid.New(), FileID: uuid.New(), Name: "usp_GetInvoice", QualifiedName: "dbo.usp_GetInvoice", Kind: "procedure"},
+	}}
+	h := &SemanticSearchHandler{store: s}
+
+	out, err := h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "usp_GetInvoice", Mode: "keyword"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1. **procedure** `usp_GetInvoice` (exact name)") {
+		t.Errorf("expected the exact name first, got:\n%s", out)
+	}
+	if _, err := h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "invoices"}); err == nil {
+		t.Error("expected hybrid mode without an embedding provider to fail")
+	}
+	if _, err := h.Handle(context.Background(), SemanticSearchParams{Project: "billing", Query: "invoices", Mode: "bm25"}); err == nil {
+		t.Error("expected an unknown mode to fail")
+	}
+}
+
+func TestFuseRankings_Stable(t *testing.T) {
+	// With equal weights the top vector hit and the top keyword hit tie; the name
+	// match wins, and equal hits fall back to the qualified name.
+	semantic := searchHit{id: uuid.New(), qualifiedName: "app.listOrders", similarity: 0.9, vectorRank: 1}
+	exact := searchHit{id: uuid.New(), qualifiedName: "app.getOrder", relevance: 1, keywordRank: 1}
+	b := searchHit{id: uuid.New(), qualifiedName: "app.b", similarity: 0.5, vectorRank: 2}
+	a := searchHit{id: uuid.New(), qualifiedName: "app.a", relevance: 0.6, keywordRank: 2}
+	a2 := searchHit{id: uuid.New(), qualifiedName: "app.a2", relevance: 0.6, keywordRank: 3}
+
+	for range 10 {
+		got := fuseRankings([]searchHit{semantic, b}, []searchHit{exact, a, a2}, 0.5, 0.5)
+		var names []string
+		for _, h := range got {
+			names = append(names, h.qualifiedName)
+		}
+		if want := "app.getOrder app.listOrders app.a app.b app.a2"; strings.Join(names, " ") != want {
+			t.Fatalf("expected %s, got %s", want, strings.Join(names, " "))
+		}
+	}
+}
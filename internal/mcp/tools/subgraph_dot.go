@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// defaultSubgraphFanout is how many edges output=dot draws from each node unless
+// max_fanout says otherwise.
+const defaultSubgraphFanout = 10
+
+// dotStyle is how a symbol kind is drawn in Graphviz.
+type dotStyle struct {
+	shape string
+	fill  string
+}
+
+// dotStyles groups kinds into data, code and API shapes so a slide reads at a glance.
+var dotStyles = map[string]dotStyle{
+	"table":     {"cylinder", "#cfe2f3"},
+	"view":      {"cylinder", "#d9ead3"},
+	"column":    {"note", "#eeeeee"},
+	"procedure": {"component", "#fce5cd"},
+	"function":  {"box", "#fff2cc"},
+	"method":    {"box", "#fff2cc"},
+	"trigger":   {"component", "#f4cccc"},
+	"class":     {"box3d", "#d9d2e9"},
+	"interface": {"box3d", "#ead1dc"},
+	"module":    {"folder", "#e6e6e6"},
+	"service":   {"box3d", "#d0e0e3"},
+	"endpoint":  {"hexagon", "#b6d7a8"},
+}
+
+var defaultDotStyle = dotStyle{"ellipse", "#ffffff"}
+
+// dotOptions selects what formatSubgraphDOT draws beyond the styled nodes and edges.
+type dotOptions struct {
+	fanout     int  // edges drawn from each node, the rest counted in a comment; 0 draws every edge
+	attributes bool // keep kind, language and PageRank of nodes and type and confidence of edges as attributes
+}
+
+// formatSubgraphDOT renders the subgraph as a Graphviz digraph: nodes shaped and filled by
+// kind, edges labelled with their type and dashed below full confidence. output=dot limits
+// the fan-out for a readable slide; exports keep every edge and the attributes graph tools
+// read.
+func formatSubgraphDOT(title string, symbols []postgres.Symbol, edges []subgraphEdge, opts dotOptions) string {
+	if title == "" {
+		title = "subgraph"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotID(title))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10, style=filled];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=8];\n")
+
+	inGraph := make(map[uuid.UUID]bool, len(symbols))
+	for _, s := range symbols {
+		inGraph[s.ID] = true
+		style, ok := dotStyles[s.Kind]
+		if !ok {
+			style = defaultDotStyle
+		}
+		attrs := fmt.Sprintf("label=%s, shape=%s, fillcolor=%s, tooltip=%s", dotID(s.Name), style.shape, dotID(style.fill),
+			dotID(fmt.Sprintf("%s %s (%s)", s.Kind, s.QualifiedName, s.Language)))
+		if opts.attributes {
+			attrs += fmt.Sprintf(", kind=%s, language=%s, pagerank=%s",
+				dotID(s.Kind), dotID(s.Language), dotID(strconv.FormatFloat(getPageRank(s), 'g', -1, 64)))
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotID(s.ID.String()), attrs)
+	}
+
+	drawn := make(map[uuid.UUID]int)
+	omitted := 0
+	for _, e := range edges {
+		if !inGraph[e.SourceID] || !inGraph[e.TargetID] {
+			continue
+		}
+		if opts.fanout > 0 && drawn[e.SourceID] >= opts.fanout {
+			omitted++
+			continue
+		}
+		drawn[e.SourceID]++
+		confidence := edgeConfidence(e)
+		attrs := "label=" + dotID(e.EdgeType)
+		if opts.attributes {
+			attrs += fmt.Sprintf(", type=%s, confidence=%s", dotID(e.EdgeType), dotID(strconv.FormatFloat(confidence, 'g', -1, 64)))
+		}
+		if confidence < 1 {
+			attrs += fmt.Sprintf(", style=dashed, tooltip=%s", dotID(fmt.Sprintf("confidence %.2f", confidence)))
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotID(e.SourceID.String()), dotID(e.TargetID.String()), attrs)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&b, "  // %d edges omitted by max_fanout=%d\n", omitted, opts.fanout)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotID quotes s as a DOT identifier, escaping quotes and backslashes and turning line
+// breaks into DOT's centered line break.
+func dotID(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
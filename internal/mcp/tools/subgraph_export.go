@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Subgraph export formats.
+const (
+	ExportGraphML = "graphml"
+	ExportDOT     = "dot"
+)
+
+// Subgraph is the symbols extract_subgraph reaches from its seeds and the edges between
+// them, for export to graph tools such as Gephi and Graphviz.
+type Subgraph struct {
+	title   string
+	symbols []postgres.Symbol
+	edges   []subgraphEdge
+}
+
+// Extract runs extract_subgraph's seed discovery and expansion for params without
+// formatting a response. The subgraph is empty when no seed is found.
+func (h *ExtractSubgraphHandler) Extract(ctx context.Context, params ExtractSubgraphParams) (*Subgraph, error) {
+	_, symbols, edges, err := h.extract(ctx, withSubgraphDefaults(params))
+	if err != nil {
+		return nil, err
+	}
+	return &Subgraph{title: params.Topic, symbols: symbols, edges: edges}, nil
+}
+
+// Len returns the number of symbols in the subgraph.
+func (g *Subgraph) Len() int { return len(g.symbols) }
+
+// Write renders the subgraph in format, ExportGraphML or ExportDOT.
+func (g *Subgraph) Write(w io.Writer, format string) error {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case ExportGraphML:
+		g.writeGraphML(bw)
+	case ExportDOT:
+		// Every edge is kept, with the attributes graph tools read
+		bw.WriteString(formatSubgraphDOT(g.title, g.symbols, g.edges, dotOptions{attributes: true}))
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	return bw.Flush()
+}
+
+// writeGraphML writes a directed GraphML graph whose nodes carry their name, qualified
+// name, kind, language and PageRank, and whose edges carry their type and confidence.
+func (g *Subgraph) writeGraphML(w *bufio.Writer) {
+	w.WriteString(xml.Header)
+	w.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for _, key := range [][4]string{
+		{"name", "node", "name", "string"},
+		{"qualified_name", "node", "qualified_name", "string"},
+		{"kind", "node", "kind", "string"},
+		{"language", "node", "language", "string"},
+		{"pagerank", "node", "pagerank", "double"},
+		{"type", "edge", "type", "string"},
+		{"confidence", "edge", "confidence", "double"},
+	} {
+		fmt.Fprintf(w, "  <key id=%q for=%q attr.name=%q attr.type=%q/>\n", key[0], key[1], key[2], key[3])
+	}
+	fmt.Fprintf(w, "  <graph id=\"%s\" edgedefault=\"directed\">\n", xmlText(g.graphTitle()))
+
+	inGraph := make(map[uuid.UUID]bool, len(g.symbols))
+	for _, s := range g.symbols {
+		inGraph[s.ID] = true
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", s.ID)
+		for _, data := range [][2]string{
+			{"name", s.Name},
+			{"qualified_name", s.QualifiedName},
+			{"kind", s.Kind},
+			{"language", s.Language},
+			{"pagerank", strconv.FormatFloat(getPageRank(s), 'g', -1, 64)},
+		} {
+			fmt.Fprintf(w, "      <data key=\"%s\">%s</data>\n", data[0], xmlText(data[1]))
+		}
+		w.WriteString("    </node>\n")
+	}
+	for i, e := range g.edges {
+		if !inGraph[e.SourceID] || !inGraph[e.TargetID] {
+			continue
+		}
+		fmt.Fprintf(w, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, e.SourceID, e.TargetID)
+		fmt.Fprintf(w, "      <data key=\"type\">%s</data>\n", xmlText(e.EdgeType))
+		fmt.Fprintf(w, "      <data key=\"confidence\">%s</data>\n", strconv.FormatFloat(edgeConfidence(e), 'g', -1, 64))
+		w.WriteString("    </edge>\n")
+	}
+	w.WriteString("  </graph>\n</graphml>\n")
+}
+
+func (g *Subgraph) graphTitle() string {
+	if g.title == "" {
+		return "subgraph"
+	}
+	return g.title
+}
+
+// edgeConfidence returns an edge's confidence, 1 when it is not set.
+func edgeConfidence(e subgraphEdge) float64 {
+	if e.Confidence == 0 {
+		return 1
+	}
+	return e.Confidence
+}
+
+// xmlText escapes s for use as XML character data or an attribute value.
+func xmlText(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
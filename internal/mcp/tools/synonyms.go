@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/mcp/session"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// maxSynonymQueries bounds the expanded queries run for one search.
+const maxSynonymQueries = 8
+
+// synonymGroup is a canonical term and the project jargon meaning the same, all
+// lowercase and each split into words.
+type synonymGroup [][]string
+
+// synonymsFromSettings reads the search_synonyms project setting, a map from each
+// canonical term to its synonyms: {"customer": ["client"], "procedure": ["sp", "proc"]}.
+// Groups are ordered by canonical term.
+func synonymsFromSettings(settings []byte) []synonymGroup {
+	if len(settings) == 0 {
+		return nil
+	}
+	var s struct {
+		Synonyms map[string][]string `json:"search_synonyms"`
+	}
+	if json.Unmarshal(settings, &s) != nil {
+		return nil
+	}
+	canonical := make([]string, 0, len(s.Synonyms))
+	for c := range s.Synonyms {
+		canonical = append(canonical, c)
+	}
+	sort.Strings(canonical)
+
+	var groups []synonymGroup
+	for _, c := range canonical {
+		var g synonymGroup
+		for _, term := range append([]string{c}, s.Synonyms[c]...) {
+			if words := strings.Fields(strings.ToLower(term)); len(words) > 0 {
+				g = append(g, words)
+			}
+		}
+		if len(g) > 1 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// expandedQuery is a search query and, for one rewritten with a synonym, the
+// substitution made, "client → customer".
+type expandedQuery struct {
+	Query string
+	Note  string
+}
+
+// expandQuery returns the query followed by its rewrites with each synonym of a term it
+// contains. Terms match whole words, so "ClientOrders" and "client_orders" contain
+// "client" but "usp_Get" does not contain "sp".
+func expandQuery(query string, groups []synonymGroup) []expandedQuery {
+	out := []expandedQuery{{Query: query}}
+	spans := wordSpans(query)
+	seen := map[string]bool{strings.ToLower(query): true}
+	for _, g := range groups {
+		for _, term := range g {
+			start, end, ok := findWords(query, spans, term)
+			if !ok {
+				continue
+			}
+			for _, alt := range g {
+				rewritten := query[:start] + strings.Join(alt, " ") + query[end:]
+				if seen[strings.ToLower(rewritten)] || len(out) == maxSynonymQueries {
+					continue
+				}
+				seen[strings.ToLower(rewritten)] = true
+				out = append(out, expandedQuery{
+					Query: rewritten,
+					Note:  query[start:end] + " → " + strings.Join(alt, " "),
+				})
+			}
+		}
+	}
+	return out
+}
+
+// wordSpans splits s into words at non-alphanumerics and camelCase humps, returning
+// the byte offsets of each.
+func wordSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	var prev rune
+	for i, r := range s {
+		alnum := unicode.IsLetter(r) || unicode.IsDigit(r)
+		hump := start >= 0 && unicode.IsUpper(r) && unicode.IsLower(prev)
+		if start >= 0 && (!alnum || hump) {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+		if alnum && start < 0 {
+			start = i
+		}
+		prev = r
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// findWords finds the first run of consecutive words of s equal to term, ignoring
+// case, and returns its byte range.
+func findWords(s string, spans [][2]int, term []string) (int, int, bool) {
+	for i := 0; i+len(term) <= len(spans); i++ {
+		match := true
+		for j, w := range term {
+			span := spans[i+j]
+			if !strings.EqualFold(s[span[0]:span[1]], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return spans[i][0], spans[i+len(term)-1][1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// symbolSearcher is the store search the synonym expansion runs.
+type symbolSearcher interface {
+	SearchSymbols(ctx context.Context, arg postgres.SearchSymbolsParams) ([]postgres.Symbol, error)
+}
+
+// searchWithSynonyms searches for arg's query and each synonym rewrite of it, ranking
+// every match against the query that found it. A symbol found by several queries keeps
+// its best score. It returns the ranked matches and the notes of the rewrites that
+// found any.
+func searchWithSynonyms(ctx context.Context, g symbolSearcher, arg postgres.SearchSymbolsParams, groups []synonymGroup, config mcp.RankConfig, sess *session.Session) ([]mcp.RankedSymbol, []string, error) {
+	query := ""
+	if arg.Query != nil {
+		query = *arg.Query
+	}
+
+	best := make(map[uuid.UUID]int)
+	var merged []mcp.RankedSymbol
+	var notes []string
+	for _, q := range expandQuery(query, groups) {
+		arg.Query = &q.Query
+		results, err := g.SearchSymbols(ctx, arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(results) > 0 && q.Note != "" {
+			notes = append(notes, q.Note)
+		}
+		for _, r := range mcp.RankSymbols(results, q.Query, config, sess) {
+			if i, ok := best[r.Symbol.ID]; ok {
+				merged[i].Score = max(merged[i].Score, r.Score)
+				continue
+			}
+			best[r.Symbol.ID] = len(merged)
+			merged = append(merged, r)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+	return merged, notes, nil
+}
+
+// addSynonymNote tells the reader which synonym rewrites contributed results.
+func addSynonymNote(rb *mcp.ResponseBuilder, expansions []string) {
+	if len(expansions) > 0 {
+		rb.AddLine("_Also searched project synonyms: " + strings.Join(expansions, ", ") + "_")
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// nameSearcher matches symbols whose qualified name contains the query, ignoring case.
+type nameSearcher []postgres.Symbol
+
+func (s nameSearcher) SearchSymbols(_ context.Context, arg postgres.SearchSymbolsParams) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, sym := range s {
+		if strings.Contains(strings.ToLower(sym.QualifiedName), strings.ToLower(*arg.Query)) {
+			out = append(out, sym)
+		}
+	}
+	return out, nil
+}
+
+func TestSearchWithSynonyms_CanonicalTermMatches(t *testing.T) {
+	symbols := nameSearcher{
+		{ID: uuid.New(), Name: "Customers", QualifiedName: "dbo.Customers", Kind: "table"},
+		{ID: uuid.New(), Name: "usp_GetOrders", QualifiedName: "dbo.usp_GetOrders", Kind: "procedure"},
+	}
+	groups := synonymsFromSettings([]byte(`{"search_synonyms": {"customer": ["client"]}}`))
+
+	query := "client"
+	ranked, notes, err := searchWithSynonyms(context.Background(), symbols, postgres.SearchSymbolsParams{Query: &query}, groups, mcp.DefaultRankConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranked) != 1 || ranked[0].Symbol.QualifiedName != "dbo.Customers" {
+		t.Fatalf("expected a search for client to find dbo.Customers, got %+v", ranked)
+	}
+	if len(notes) != 1 || notes[0] != "client → customer" {
+		t.Errorf("expected the expansion to be noted, got %v", notes)
+	}
+
+	// Without the setting the search finds nothing and notes nothing
+	ranked, notes, _ = searchWithSynonyms(context.Background(), symbols, postgres.SearchSymbolsParams{Query: &query}, nil, mcp.DefaultRankConfig(), nil)
+	if len(ranked) != 0 || len(notes) != 0 {
+		t.Errorf("expected no matches without synonyms, got %+v %v", ranked, notes)
+	}
+}
+
+func TestExpandQuery(t *testing.T) {
+	groups := synonymsFromSettings([]byte(`{"search_synonyms": {"procedure": ["sp", "stored proc"], "customer": ["client"]}}`))
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"ClientOrders", []string{"ClientOrders", "customerOrders"}},
+		{"get_client_by_id", []string{"get_client_by_id", "get_customer_by_id"}},
+		{"usp_GetOrders", []string{"usp_GetOrders"}},
+		{"stored proc audit", []string{"stored proc audit", "procedure audit", "sp audit"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, q := range expandQuery(tt.query, groups) {
+			got = append(got, q.Query)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("expandQuery(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if groups := synonymsFromSettings([]byte(`{"search_synonyms": "nope"}`)); groups != nil {
+		t.Errorf("expected malformed settings to be ignored, got %v", groups)
+	}
+}
@@ -1,10 +1,19 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/maraichr/lattice/internal/mcp"
 	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
 )
 
 // --- classifyIntent ---
@@ -39,6 +48,32 @@ func TestClassifyIntent_Lineage(t *testing.T) {
 	}
 }
 
+func TestClassifyIntent_ColumnLineage(t *testing.T) {
+	tests := []string{
+		"Where does dbo.ActiveUsers.Amount get its value?",
+		"Show the column lineage of Orders.Total",
+		"Where does the Email column come from?",
+	}
+	for _, q := range tests {
+		if classifyIntent(q) != IntentColumnLineage {
+			t.Errorf("expected IntentColumnLineage for %q, got %s", q, classifyIntent(q))
+		}
+	}
+}
+
+func TestExtractColumnName(t *testing.T) {
+	tests := map[string]string{
+		"Where does dbo.ActiveUsers.Amount get its value?": "dbo.ActiveUsers.Amount",
+		"Show the column lineage of `Orders.Total`":        "Orders.Total",
+		"Where does the Email column come from?":           "email",
+	}
+	for q, want := range tests {
+		if got := extractColumnName(q); got != want {
+			t.Errorf("extractColumnName(%q) = %q, want %q", q, got, want)
+		}
+	}
+}
+
 func TestClassifyIntent_Overview(t *testing.T) {
 	tests := []string{
 		"Give me an overview of this project",
@@ -70,6 +105,20 @@ func TestClassifyIntent_Dependencies(t *testing.T) {
 	}
 }
 
+func TestClassifyIntent_Cycles(t *testing.T) {
+	tests := []string{
+		"Are there any circular dependencies between these modules?",
+		"Show me the dependency cycles",
+		"What are the largest dependency cycles?",
+		"Any cyclic references in the billing code?",
+	}
+	for _, q := range tests {
+		if classifyIntent(q) != IntentCycles {
+			t.Errorf("expected IntentCycles for %q, got %s", q, classifyIntent(q))
+		}
+	}
+}
+
 func TestClassifyIntent_Subgraph(t *testing.T) {
 	tests := []string{
 		"Show me everything about order processing",
@@ -200,3 +249,238 @@ func TestIdentifyCore(t *testing.T) {
 		t.Error("non-seed symbols should not be core")
 	}
 }
+
+// --- formatSubgraphEdges ---
+
+func TestFormatSubgraphEdges_MatchesSubgraph(t *testing.T) {
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_PlaceOrder", Kind: "procedure", Language: "tsql"}
+	orders := postgres.Symbol{ID: uuid.New(), Name: "Orders", Kind: "table", Language: "tsql"}
+	svc := postgres.Symbol{ID: uuid.New(), Name: "OrderService", Kind: "class", Language: "csharp"}
+	outside := uuid.New()
+
+	edges := []subgraphEdge{
+		{SourceID: proc.ID, TargetID: orders.ID, EdgeType: "writes_to"},
+		{SourceID: svc.ID, TargetID: proc.ID, EdgeType: "calls", Confidence: 0.7},
+		{SourceID: svc.ID, TargetID: outside, EdgeType: "calls"}, // trimmed node
+	}
+
+	out, err := formatSubgraphEdges([]postgres.Symbol{proc, orders, svc}, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Nodes []map[string]any `json:"nodes"`
+		Edges []map[string]any `json:"edges"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+
+	if len(got.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(got.Nodes))
+	}
+	for _, key := range []string{"id", "name", "kind", "language"} {
+		if _, ok := got.Nodes[0][key]; !ok {
+			t.Errorf("node missing %q: %v", key, got.Nodes[0])
+		}
+	}
+	if got.Nodes[1]["id"] != orders.ID.String() || got.Nodes[1]["kind"] != "table" {
+		t.Errorf("unexpected node %v", got.Nodes[1])
+	}
+
+	if len(got.Edges) != 2 {
+		t.Fatalf("expected 2 edges within the subgraph, got %d", len(got.Edges))
+	}
+	first := got.Edges[0]
+	if first["source"] != proc.ID.String() || first["target"] != orders.ID.String() || first["type"] != "writes_to" {
+		t.Errorf("unexpected edge %v", first)
+	}
+	if first["confidence"] != 1.0 {
+		t.Errorf("unset confidence should be reported as 1.0, got %v", first["confidence"])
+	}
+	if got.Edges[1]["confidence"] != 0.7 {
+		t.Errorf("expected confidence 0.7, got %v", got.Edges[1]["confidence"])
+	}
+}
+
+// --- formatSubgraphDOT ---
+
+func TestFormatSubgraphDOT_ValidAndStyled(t *testing.T) {
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_PlaceOrder", QualifiedName: "dbo.usp_PlaceOrder", Kind: "procedure", Language: "tsql"}
+	orders := postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table", Language: "tsql"}
+	odd := postgres.Symbol{ID: uuid.New(), Name: `Say "hi" \ bye` + "\n", QualifiedName: "App.Odd", Kind: "class", Language: "csharp"}
+	orderLines := postgres.Symbol{ID: uuid.New(), Name: "OrderLines", QualifiedName: "dbo.OrderLines", Kind: "table", Language: "tsql"}
+
+	edges := []subgraphEdge{
+		{SourceID: proc.ID, TargetID: orders.ID, EdgeType: "writes_to"},
+		{SourceID: proc.ID, TargetID: orderLines.ID, EdgeType: "writes_to"},
+		{SourceID: odd.ID, TargetID: proc.ID, EdgeType: "calls", Confidence: 0.6},
+		{SourceID: odd.ID, TargetID: uuid.New(), EdgeType: "calls"}, // trimmed node
+	}
+
+	out := formatSubgraphDOT(`orders "flow"`, []postgres.Symbol{proc, orders, odd, orderLines}, edges, dotOptions{fanout: 1})
+	assertValidDOT(t, out)
+
+	if !strings.HasPrefix(out, `digraph "orders \"flow\"" {`) {
+		t.Errorf("expected escaped graph name, got %q", strings.SplitN(out, "\n", 2)[0])
+	}
+	for _, want := range []string{
+		fmt.Sprintf(`"%s" [label="Orders", shape=cylinder`, orders.ID),
+		fmt.Sprintf(`"%s" [label="usp_PlaceOrder", shape=component`, proc.ID),
+		fmt.Sprintf(`"%s" [label="Say \"hi\" \\ bye\n", shape=box3d`, odd.ID),
+		fmt.Sprintf(`"%s" -> "%s" [label="writes_to"];`, proc.ID, orders.ID),
+		fmt.Sprintf(`"%s" -> "%s" [label="calls", style=dashed`, odd.ID, proc.ID),
+		"// 1 edges omitted by max_fanout=1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected DOT to contain %s\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, orderLines.ID.String()+`" [label`) {
+		t.Error("expected every node drawn even when its edges are cut by max_fanout")
+	}
+	if strings.Count(out, " -> ") != 2 {
+		t.Errorf("expected 2 edges after fanout and trimming, got %d\n%s", strings.Count(out, " -> "), out)
+	}
+}
+
+// assertValidDOT checks the digraph's statement syntax: one graph body, balanced quotes,
+// brackets and braces, and every statement a node, edge, attribute default or comment.
+func TestSubgraphWrite_GraphMLAndDOT(t *testing.T) {
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_PlaceOrder", QualifiedName: "dbo.usp_PlaceOrder", Kind: "procedure", Language: "tsql",
+		Metadata: []byte(`{"pagerank": 0.25}`)}
+	orders := postgres.Symbol{ID: uuid.New(), Name: "Orders & <Lines>", QualifiedName: "dbo.Orders", Kind: "table", Language: "tsql"}
+	g := &Subgraph{
+		title:   "orders",
+		symbols: []postgres.Symbol{proc, orders},
+		edges: []subgraphEdge{
+			{SourceID: proc.ID, TargetID: orders.ID, EdgeType: "writes_to", Confidence: 0.8},
+			{SourceID: proc.ID, TargetID: uuid.New(), EdgeType: "calls"}, // trimmed node
+		},
+	}
+
+	var graphml strings.Builder
+	if err := g.Write(&graphml, ExportGraphML); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Data   []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(graphml.String()), &doc); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, graphml.String())
+	}
+	if doc.Graph.EdgeDefault != "directed" || len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Fatalf("expected a directed graph of 2 nodes and 1 edge, got %+v", doc.Graph)
+	}
+	nodeData := map[string]string{}
+	for _, d := range doc.Graph.Nodes[0].Data {
+		nodeData[d.Key] = d.Value
+	}
+	if nodeData["kind"] != "procedure" || nodeData["language"] != "tsql" || nodeData["pagerank"] != "0.25" {
+		t.Errorf("expected kind, language and PageRank node data, got %v", nodeData)
+	}
+	if doc.Graph.Nodes[1].Data[0].Value != orders.Name {
+		t.Errorf("expected the escaped name to round-trip, got %q", doc.Graph.Nodes[1].Data[0].Value)
+	}
+	edgeData := map[string]string{}
+	for _, d := range doc.Graph.Edges[0].Data {
+		edgeData[d.Key] = d.Value
+	}
+	if edgeData["type"] != "writes_to" || edgeData["confidence"] != "0.8" {
+		t.Errorf("expected type and confidence edge data, got %v", edgeData)
+	}
+
+	var dot strings.Builder
+	if err := g.Write(&dot, ExportDOT); err != nil {
+		t.Fatal(err)
+	}
+	assertValidDOT(t, dot.String())
+	for _, want := range []string{`kind="procedure", language="tsql", pagerank="0.25"`, `type="writes_to", confidence="0.8", style=dashed`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("expected DOT to contain %s:\n%s", want, dot.String())
+		}
+	}
+
+	if err := g.Write(&dot, "svg"); err == nil {
+		t.Error("expected an unknown format to be refused")
+	}
+}
+
+func assertValidDOT(t *testing.T, dot string) {
+	t.Helper()
+	id := `"(?:[^"\\]|\\.)*"`
+	attrs := `\[(?:[^\]"]|` + id + `)*\]`
+	stmt := regexp.MustCompile(`^(?:` + id + `(?: -> ` + id + `)? ` + attrs + `;|(?:node|edge) ` + attrs + `;|rankdir=LR;|//.*)$`)
+
+	lines := strings.Split(strings.TrimRight(dot, "\n"), "\n")
+	if len(lines) < 2 || !regexp.MustCompile(`^digraph `+id+` \{$`).MatchString(lines[0]) || lines[len(lines)-1] != "}" {
+		t.Fatalf("expected a single digraph body, got:\n%s", dot)
+	}
+	for _, line := range lines[1 : len(lines)-1] {
+		if !stmt.MatchString(strings.TrimSpace(line)) {
+			t.Errorf("invalid DOT statement: %s", line)
+		}
+	}
+}
+
+// --- custom reference types ---
+
+func TestNextSubgraphCursor_ResumesAtFirstUnshownCard(t *testing.T) {
+	sym := func(pagerank string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Metadata: []byte(`{"pagerank": ` + pagerank + `}`)}
+	}
+	symbols := []postgres.Symbol{sym("0.1"), sym("0.4"), sym("0.2"), sym("0.3")}
+	sortByPageRank(symbols)
+	page := symbols[:3]
+
+	// Grouping by community showed the first and third cards before the budget ran out
+	next, ok := nextSubgraphCursor(page, []postgres.Symbol{page[2], page[0]}, len(symbols))
+	if !ok || next.ID != page[0].ID {
+		t.Fatalf("expected the cursor after the first card, got %v %v", next, ok)
+	}
+	if start := subgraphPageStart(symbols, &next); start != 1 {
+		t.Errorf("expected the next page to start at the second card, got %d", start)
+	}
+	if _, ok := nextSubgraphCursor(symbols, symbols, len(symbols)); ok {
+		t.Error("expected no cursor once every symbol is shown")
+	}
+}
+
+func TestExpandSubgraph_FollowsCustomEdgeTypes(t *testing.T) {
+	api := postgres.Symbol{ID: uuid.New(), Name: "OrdersApi", Kind: "service", Language: "yaml"}
+	cluster := postgres.Symbol{ID: uuid.New(), Name: "prod-eu", Kind: "cluster", Language: "yaml"}
+	g := &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(api, cluster)}
+	g.link(api, cluster, "deploys_to")
+
+	subgraph := expandSubgraph(context.Background(), g, []postgres.Symbol{api}, 2, 10)
+	if len(subgraph) != 2 || subgraph[1].ID != cluster.ID {
+		t.Fatalf("expected deploys_to target in subgraph, got %v", subgraph)
+	}
+
+	edges := collectSubgraphEdges(context.Background(), g, subgraph)
+	reg := models.EdgeTypeRegistryFromSettings([]byte(`{"reference_types":[{"name":"deploys_to","label":"Deploys to","category":"infrastructure"}]}`))
+	out := formatEdgeSummary(edges, subgraph, reg)
+	if !strings.Contains(out, "- Deploys to (deploys_to, infrastructure): 1 edges") {
+		t.Errorf("expected labelled custom edge type, got:\n%s", out)
+	}
+	if !strings.Contains(out, "OrdersApi -[deploys_to]-> prod-eu") {
+		t.Errorf("expected edge example, got:\n%s", out)
+	}
+}
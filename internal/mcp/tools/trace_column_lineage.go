@@ -0,0 +1,280 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// TraceColumnLineageParams are the parameters for the trace_column_lineage tool.
+type TraceColumnLineageParams struct {
+	Project   string `json:"project"`
+	Column    string `json:"column"`              // e.g. dbo.Orders.Amount
+	Direction string `json:"direction,omitempty"` // upstream, downstream, both
+	MaxDepth  int    `json:"max_depth,omitempty"`
+}
+
+const (
+	defaultColumnLineageDepth = 5
+	maxColumnLineageDepth     = 10
+	maxColumnLineageChains    = 50
+)
+
+// TraceColumnLineageHandler implements the trace_column_lineage MCP tool.
+type TraceColumnLineageHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewTraceColumnLineageHandler creates a new handler.
+func NewTraceColumnLineageHandler(s *store.Store, logger *slog.Logger) *TraceColumnLineageHandler {
+	return &TraceColumnLineageHandler{store: s, logger: logger}
+}
+
+// columnLineageGraph is the subset of the store needed to trace column lineage.
+type columnLineageGraph interface {
+	symbolGraph
+	ListColumnSymbolsByProject(ctx context.Context, projectID uuid.UUID) ([]postgres.Symbol, error)
+}
+
+// columnHop is one edge of a column lineage chain, ending at Symbol.
+type columnHop struct {
+	Symbol     postgres.Symbol
+	Derivation string // direct_copy, transform, aggregate, filter, join, conditional
+	Expression string
+}
+
+// columnChains are the column lineage chains walked from a column in one direction.
+type columnChains struct {
+	Chains    [][]columnHop // hops outward from the column
+	Columns   int           // distinct symbols reached
+	Truncated bool          // more chains than maxColumnLineageChains
+}
+
+// Handle traces the column references recorded for a column, hop by hop.
+func (h *TraceColumnLineageHandler) Handle(ctx context.Context, params TraceColumnLineageParams) (string, error) {
+	if params.Column == "" {
+		return "", fmt.Errorf("column is required")
+	}
+	if params.Direction == "" {
+		params.Direction = "both"
+	}
+	if params.Direction != "upstream" && params.Direction != "downstream" && params.Direction != "both" {
+		return "", fmt.Errorf("direction must be upstream, downstream or both")
+	}
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = defaultColumnLineageDepth
+	}
+	params.MaxDepth = min(params.MaxDepth, maxColumnLineageDepth)
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	seed, err := resolveColumn(ctx, h.store, project.ID, params.Column)
+	if err != nil {
+		return "", err
+	}
+
+	var upstream, downstream columnChains
+	if params.Direction == "upstream" || params.Direction == "both" {
+		upstream = collectColumnChains(ctx, h.store, seed, true, params.MaxDepth)
+	}
+	if params.Direction == "downstream" || params.Direction == "both" {
+		downstream = collectColumnChains(ctx, h.store, seed, false, params.MaxDepth)
+	}
+
+	total := upstream.Columns + downstream.Columns
+	mcp.RecordResults(ctx, total, total)
+	return formatColumnLineage(seed, params.Direction, upstream, downstream), nil
+}
+
+// resolveColumn finds a column symbol by its qualified name, ignoring case. A name that
+// leaves out the schema, Orders.Amount for dbo.Orders.Amount, matches by suffix; of
+// several matches the lexically first is taken.
+func resolveColumn(ctx context.Context, g columnLineageGraph, projectID uuid.UUID, name string) (postgres.Symbol, error) {
+	columns, err := g.ListColumnSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return postgres.Symbol{}, fmt.Errorf("list columns: %w", err)
+	}
+	lower := strings.ToLower(name)
+	var matches []postgres.Symbol
+	for _, c := range columns {
+		qn := strings.ToLower(c.QualifiedName)
+		if qn == lower {
+			return c, nil
+		}
+		if strings.HasSuffix(qn, "."+lower) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return postgres.Symbol{}, fmt.Errorf("no column found matching '%s'", name)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].QualifiedName < matches[j].QualifiedName
+	})
+	return matches[0], nil
+}
+
+// collectColumnChains walks the column-lineage edges from seed depth first, against
+// the flow of data upstream or along it downstream, and returns every chain up to
+// maxDepth hops. Only edges built from column references, which record a
+// derivation_type, are followed. A chain stops where no edge goes on or where it
+// would revisit a symbol already on it.
+func collectColumnChains(ctx context.Context, g symbolGraph, seed postgres.Symbol, upstream bool, maxDepth int) columnChains {
+	var result columnChains
+	reached := make(map[uuid.UUID]bool)
+	onChain := map[uuid.UUID]bool{seed.ID: true}
+
+	var walk func(id uuid.UUID, chain []columnHop)
+	walk = func(id uuid.UUID, chain []columnHop) {
+		if result.Truncated {
+			return
+		}
+		var next []columnHop
+		if len(chain) < maxDepth {
+			next = columnNeighbors(ctx, g, id, upstream)
+		}
+		extended := false
+		for _, hop := range next {
+			if onChain[hop.Symbol.ID] {
+				continue
+			}
+			extended = true
+			reached[hop.Symbol.ID] = true
+			onChain[hop.Symbol.ID] = true
+			walk(hop.Symbol.ID, append(chain, hop))
+			onChain[hop.Symbol.ID] = false
+		}
+		if extended || len(chain) == 0 {
+			return
+		}
+		if len(result.Chains) == maxColumnLineageChains {
+			result.Truncated = true
+			return
+		}
+		result.Chains = append(result.Chains, append([]columnHop(nil), chain...))
+	}
+	walk(seed.ID, nil)
+
+	result.Columns = len(reached)
+	return result
+}
+
+// columnNeighbors returns the symbols one column-lineage edge away from id: the
+// sources of its incoming edges upstream, the targets of its outgoing edges downstream.
+func columnNeighbors(ctx context.Context, g symbolGraph, id uuid.UUID, upstream bool) []columnHop {
+	var edges []postgres.SymbolEdge
+	var err error
+	if upstream {
+		edges, err = g.GetIncomingEdges(ctx, id)
+	} else {
+		edges, err = g.GetOutgoingEdges(ctx, id)
+	}
+	if err != nil {
+		return nil
+	}
+
+	var hops []columnHop
+	for _, e := range edges {
+		var meta struct {
+			DerivationType string `json:"derivation_type"`
+			Expression     string `json:"expression"`
+		}
+		if len(e.Metadata) == 0 || json.Unmarshal(e.Metadata, &meta) != nil || meta.DerivationType == "" {
+			continue
+		}
+		other := e.TargetID
+		if upstream {
+			other = e.SourceID
+		}
+		sym, err := g.GetSymbol(ctx, other)
+		if err != nil {
+			continue
+		}
+		hops = append(hops, columnHop{Symbol: sym, Derivation: meta.DerivationType, Expression: meta.Expression})
+	}
+	sort.SliceStable(hops, func(i, j int) bool {
+		return hops[i].Symbol.QualifiedName < hops[j].Symbol.QualifiedName
+	})
+	return hops
+}
+
+func formatColumnLineage(seed postgres.Symbol, direction string, upstream, downstream columnChains) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Column lineage for `%s`** (%s)", seed.QualifiedName, direction))
+
+	total := len(upstream.Chains) + len(downstream.Chains)
+	shown := 0
+	if len(upstream.Chains) > 0 {
+		rb.AddLine(fmt.Sprintf("### Upstream (where its value comes from, %d columns)", upstream.Columns))
+		shown += addColumnChains(rb, seed, upstream, true)
+		rb.AddLine("")
+	}
+	if len(downstream.Chains) > 0 {
+		rb.AddLine(fmt.Sprintf("### Downstream (where its value goes, %d columns)", downstream.Columns))
+		shown += addColumnChains(rb, seed, downstream, false)
+		rb.AddLine("")
+	}
+	if total == 0 {
+		rb.AddLine("No column lineage recorded for this column. Column references are extracted from SQL sources (T-SQL and PostgreSQL).")
+	}
+
+	return rb.Finalize(total, shown)
+}
+
+// addColumnChains lists chains in the direction data flows, returning how many fit.
+func addColumnChains(rb *mcp.ResponseBuilder, seed postgres.Symbol, chains columnChains, upstream bool) int {
+	shown := 0
+	for _, chain := range chains.Chains {
+		if !rb.AddLine("- " + formatColumnChain(seed, chain, upstream)) {
+			return shown
+		}
+		shown++
+	}
+	if chains.Truncated {
+		rb.AddLine(fmt.Sprintf("- … more than %d chains; lower max_depth to see the nearest hops", maxColumnLineageChains))
+	}
+	return shown
+}
+
+// formatColumnChain renders a chain in data-flow order, each arrow labelled with the
+// derivation of the hop: "`dbo.Orders.Amount` -[direct_copy]→ `dbo.OrderHistory.Amount`".
+// Upstream chains, walked from the seed back to the origin, end at the seed.
+func formatColumnChain(seed postgres.Symbol, chain []columnHop, upstream bool) string {
+	label := func(h columnHop) string {
+		if h.Expression != "" {
+			return h.Derivation + ": " + h.Expression
+		}
+		return h.Derivation
+	}
+
+	var b strings.Builder
+	if upstream {
+		for i := len(chain) - 1; i >= 0; i-- {
+			fmt.Fprintf(&b, "`%s` -[%s]→ ", chain[i].Symbol.QualifiedName, label(chain[i]))
+		}
+		fmt.Fprintf(&b, "`%s`", seed.QualifiedName)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "`%s`", seed.QualifiedName)
+	for _, h := range chain {
+		fmt.Fprintf(&b, " -[%s]→ `%s`", label(h), h.Symbol.QualifiedName)
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// columnFixture links dbo.Orders.Amount -direct_copy→ dbo.OrderHistory.Amount
+// -transform→ dbo.ActiveUsers.Amount, and has a procedure read the last column through
+// an edge that is not column lineage.
+func columnFixture() (orders, history, active postgres.Symbol, g *fakeImpactGraph) {
+	column := func(qn string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Name: "Amount", QualifiedName: qn, Kind: "column", Language: "tsql"}
+	}
+	orders, history, active = column("dbo.Orders.Amount"), column("dbo.OrderHistory.Amount"), column("dbo.ActiveUsers.Amount")
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_Report", QualifiedName: "dbo.usp_Report", Kind: "procedure", Language: "tsql"}
+
+	g = &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(orders, history, active, proc)}
+	g.edges[orders.ID] = append(g.edges[orders.ID], postgres.SymbolEdge{
+		SourceID: orders.ID, TargetID: history.ID, EdgeType: "direct_copy",
+		Metadata: []byte(`{"derivation_type":"direct_copy","confidence":0.9}`),
+	})
+	g.edges[history.ID] = append(g.edges[history.ID], postgres.SymbolEdge{
+		SourceID: history.ID, TargetID: active.ID, EdgeType: "transforms_to",
+		Metadata: []byte(`{"derivation_type":"transform","expression":"ROUND(Amount, 2)"}`),
+	})
+	g.link(proc, active, "reads_from")
+	return orders, history, active, g
+}
+
+func TestCollectColumnChains_Upstream(t *testing.T) {
+	orders, _, active, g := columnFixture()
+
+	up := collectColumnChains(context.Background(), g, active, true, 5)
+	if len(up.Chains) != 1 || up.Columns != 2 || up.Truncated {
+		t.Fatalf("expected one chain over 2 columns, got %+v", up)
+	}
+	if end := up.Chains[0][len(up.Chains[0])-1].Symbol; end.ID != orders.ID {
+		t.Errorf("expected the chain to reach dbo.Orders.Amount, got %s", end.QualifiedName)
+	}
+
+	want := "`dbo.Orders.Amount` -[direct_copy]→ `dbo.OrderHistory.Amount` -[transform: ROUND(Amount, 2)]→ `dbo.ActiveUsers.Amount`"
+	if got := formatColumnChain(active, up.Chains[0], true); got != want {
+		t.Errorf("got chain %s\nwant %s", got, want)
+	}
+
+	short := collectColumnChains(context.Background(), g, active, true, 1)
+	if len(short.Chains) != 1 || len(short.Chains[0]) != 1 {
+		t.Errorf("expected max_depth 1 to stop after one hop, got %+v", short.Chains)
+	}
+}
+
+func TestCollectColumnChains_DownstreamSkipsOtherEdges(t *testing.T) {
+	orders, _, active, g := columnFixture()
+
+	down := collectColumnChains(context.Background(), g, orders, false, 5)
+	if len(down.Chains) != 1 || len(down.Chains[0]) != 2 {
+		t.Fatalf("expected one 2-hop chain, got %+v", down.Chains)
+	}
+	want := "`dbo.Orders.Amount` -[direct_copy]→ `dbo.OrderHistory.Amount` -[transform: ROUND(Amount, 2)]→ `dbo.ActiveUsers.Amount`"
+	if got := formatColumnChain(orders, down.Chains[0], false); got != want {
+		t.Errorf("got chain %s\nwant %s", got, want)
+	}
+
+	// The procedure reads the column, but reads_from is not column lineage.
+	if up := collectColumnChains(context.Background(), g, active, true, 5); strings.Contains(formatColumnLineage(active, "upstream", up, columnChains{}), "usp_Report") {
+		t.Error("followed a reads_from edge as column lineage")
+	}
+}
+
+func TestCollectColumnChains_Cycle(t *testing.T) {
+	orders, history, _, g := columnFixture()
+	g.edges[history.ID] = append(g.edges[history.ID], postgres.SymbolEdge{
+		SourceID: history.ID, TargetID: orders.ID, EdgeType: "direct_copy",
+		Metadata: []byte(`{"derivation_type":"direct_copy"}`),
+	})
+
+	down := collectColumnChains(context.Background(), g, orders, false, 10)
+	for _, chain := range down.Chains {
+		for _, hop := range chain {
+			if hop.Symbol.ID == orders.ID {
+				t.Fatalf("chain revisits the seed: %s", formatColumnChain(orders, chain, false))
+			}
+		}
+	}
+}
+
+type fakeColumnGraph struct {
+	*fakeImpactGraph
+}
+
+func (g *fakeColumnGraph) ListColumnSymbolsByProject(_ context.Context, _ uuid.UUID) ([]postgres.Symbol, error) {
+	var out []postgres.Symbol
+	for _, s := range g.symbols {
+		if s.Kind == "column" {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestResolveColumn(t *testing.T) {
+	orders, history, _, g := columnFixture()
+	cg := &fakeColumnGraph{g}
+	ctx := context.Background()
+
+	if got, err := resolveColumn(ctx, cg, uuid.Nil, "DBO.ORDERS.AMOUNT"); err != nil || got.ID != orders.ID {
+		t.Errorf("case-insensitive match: got %s, %v", got.QualifiedName, err)
+	}
+	if got, err := resolveColumn(ctx, cg, uuid.Nil, "OrderHistory.Amount"); err != nil || got.ID != history.ID {
+		t.Errorf("match without schema: got %s, %v", got.QualifiedName, err)
+	}
+	if _, err := resolveColumn(ctx, cg, uuid.Nil, "dbo.Orders.Missing"); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
@@ -39,9 +39,78 @@ func NewTraceCrossLanguageHandler(s *store.Store, logger *slog.Logger) *TraceCro
 type traceNode struct {
 	Symbol     postgres.Symbol
 	Depth      int
-	Via        string  // edge type
-	Confidence float64 // from edge metadata, 0 = unknown
-	FromLang   string  // source symbol language
+	Via        string    // edge type
+	Confidence float64   // from edge metadata, 0 = unknown
+	FromLang   string    // source symbol language
+	From       uuid.UUID // symbol this node was reached from
+}
+
+// traceOptions bounds a walkTrace traversal.
+type traceOptions struct {
+	maxDepth  int
+	maxBranch int             // edges followed per symbol; 0 follows all
+	edgeTypes map[string]bool // edge types followed; nil follows all
+}
+
+// walkTrace walks breadth-first from seed along incoming edges (upstream) or outgoing
+// edges, visiting each symbol once at its shallowest depth. It also returns how many
+// symbols had more edges than maxBranch allowed it to follow.
+func walkTrace(ctx context.Context, g symbolGraph, seed postgres.Symbol, upstream bool, opts traceOptions) ([]traceNode, int) {
+	visited := map[uuid.UUID]bool{seed.ID: true}
+	var nodes []traceNode
+	capped := 0
+	queue := []traceNode{{Symbol: seed, Depth: 0}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.Depth >= opts.maxDepth {
+			continue
+		}
+		var edges []postgres.SymbolEdge
+		var err error
+		if upstream {
+			edges, err = g.GetIncomingEdges(ctx, cur.Symbol.ID)
+		} else {
+			edges, err = g.GetOutgoingEdges(ctx, cur.Symbol.ID)
+		}
+		if err != nil {
+			continue
+		}
+		followed := 0
+		for _, e := range edges {
+			if opts.edgeTypes != nil && !opts.edgeTypes[e.EdgeType] {
+				continue
+			}
+			next := e.TargetID
+			if upstream {
+				next = e.SourceID
+			}
+			if visited[next] {
+				continue
+			}
+			if opts.maxBranch > 0 && followed == opts.maxBranch {
+				capped++
+				break
+			}
+			visited[next] = true
+			sym, err := g.GetSymbol(ctx, next)
+			if err != nil {
+				continue
+			}
+			followed++
+			node := traceNode{
+				Symbol:     sym,
+				Depth:      cur.Depth + 1,
+				Via:        e.EdgeType,
+				Confidence: extractEdgeConfidence(e.Metadata),
+				FromLang:   cur.Symbol.Language,
+				From:       cur.Symbol.ID,
+			}
+			nodes = append(nodes, node)
+			queue = append(queue, node)
+		}
+	}
+	return nodes, capped
 }
 
 // Handle traces cross-language paths from a symbol, grouping by stack layer.
@@ -64,103 +133,35 @@ func (h *TraceCrossLanguageHandler) Handle(ctx context.Context, params TraceCros
 		return "", fmt.Errorf("access denied to project %s", params.Project)
 	}
 
-	seed, err := h.resolveSeed(ctx, project, params)
+	seed, err := resolveTraceSeed(ctx, h.store, project, params.SymbolID, params.SymbolName)
 	if err != nil {
 		return "", err
 	}
 
-	// BFS traversal tracking language transitions
-	visited := map[uuid.UUID]bool{seed.ID: true}
+	progress := mcp.ProgressFrom(ctx)
 	var upstream, downstream []traceNode
-	langTransitions := 0
-	var totalConfidence float64
-	confCount := 0
-
-	// Upstream: follow incoming edges
 	if params.Direction == "upstream" || params.Direction == "full" {
-		queue := []traceNode{{Symbol: seed, Depth: 0}}
-		for len(queue) > 0 {
-			cur := queue[0]
-			queue = queue[1:]
-			if cur.Depth >= params.MaxDepth {
-				continue
-			}
-			edges, err := h.store.GetIncomingEdges(ctx, cur.Symbol.ID)
-			if err != nil {
-				continue
-			}
-			for _, e := range edges {
-				if visited[e.SourceID] {
-					continue
-				}
-				visited[e.SourceID] = true
-				sym, err := h.store.GetSymbol(ctx, e.SourceID)
-				if err != nil {
-					continue
-				}
-				conf := extractEdgeConfidence(e.Metadata)
-				node := traceNode{
-					Symbol:     sym,
-					Depth:      cur.Depth + 1,
-					Via:        e.EdgeType,
-					Confidence: conf,
-					FromLang:   cur.Symbol.Language,
-				}
-				if sym.Language != cur.Symbol.Language {
-					langTransitions++
-					if conf > 0 {
-						totalConfidence += conf
-						confCount++
-					}
-				}
-				upstream = append(upstream, node)
-				queue = append(queue, node)
-			}
-		}
+		upstream, _ = walkTrace(ctx, h.store, seed, true, traceOptions{maxDepth: params.MaxDepth})
+		progress.Report("traced upstream: %d symbols", len(upstream))
 	}
-
-	// Downstream: follow outgoing edges
 	if params.Direction == "downstream" || params.Direction == "full" {
-		if params.Direction == "full" {
-			visited = map[uuid.UUID]bool{seed.ID: true}
-		}
-		queue := []traceNode{{Symbol: seed, Depth: 0}}
-		for len(queue) > 0 {
-			cur := queue[0]
-			queue = queue[1:]
-			if cur.Depth >= params.MaxDepth {
-				continue
-			}
-			edges, err := h.store.GetOutgoingEdges(ctx, cur.Symbol.ID)
-			if err != nil {
+		downstream, _ = walkTrace(ctx, h.store, seed, false, traceOptions{maxDepth: params.MaxDepth})
+		progress.Report("traced downstream: %d symbols", len(downstream))
+	}
+
+	// Language transitions and the confidence of the edges bridging them
+	langTransitions := 0
+	var totalConfidence float64
+	confCount := 0
+	for _, group := range [][]traceNode{upstream, downstream} {
+		for _, n := range group {
+			if n.Symbol.Language == n.FromLang {
 				continue
 			}
-			for _, e := range edges {
-				if visited[e.TargetID] {
-					continue
-				}
-				visited[e.TargetID] = true
-				sym, err := h.store.GetSymbol(ctx, e.TargetID)
-				if err != nil {
-					continue
-				}
-				conf := extractEdgeConfidence(e.Metadata)
-				node := traceNode{
-					Symbol:     sym,
-					Depth:      cur.Depth + 1,
-					Via:        e.EdgeType,
-					Confidence: conf,
-					FromLang:   cur.Symbol.Language,
-				}
-				if sym.Language != cur.Symbol.Language {
-					langTransitions++
-					if conf > 0 {
-						totalConfidence += conf
-						confCount++
-					}
-				}
-				downstream = append(downstream, node)
-				queue = append(queue, node)
+			langTransitions++
+			if n.Confidence > 0 {
+				totalConfidence += n.Confidence
+				confCount++
 			}
 		}
 	}
@@ -201,6 +202,7 @@ func (h *TraceCrossLanguageHandler) Handle(ctx context.Context, params TraceCros
 		rb.AddLine(fmt.Sprintf("Average confidence: %.2f", avgConf))
 	}
 
+	mcp.RecordResults(ctx, len(upstream)+len(downstream), len(upstream)+len(downstream))
 	return rb.Finalize(len(upstream)+len(downstream), len(upstream)+len(downstream)), nil
 }
 
@@ -239,7 +241,7 @@ func formatLayerGrouped(rb *mcp.ResponseBuilder, nodes []traceNode) {
 				crossStr = fmt.Sprintf(" (%s → %s)", n.FromLang, n.Symbol.Language)
 			}
 			rb.AddLine(fmt.Sprintf("- %s `%s` via %s%s%s",
-				n.Symbol.Kind, n.Symbol.Name, n.Via, crossStr, confStr))
+				n.Symbol.Kind, traceLabel(n.Symbol), n.Via, crossStr, confStr))
 		}
 		if i < len(groups)-1 {
 			rb.AddLine("")
@@ -247,6 +249,17 @@ func formatLayerGrouped(rb *mcp.ResponseBuilder, nodes []traceNode) {
 	}
 }
 
+// traceLabel names a symbol in trace output. An endpoint is shown by its route as
+// declared (GET /api/users/{id:int}) rather than its normalized name, which routes
+// differing only in parameter constraints or catch-alls share; matching still uses the
+// normalized form.
+func traceLabel(sym postgres.Symbol) string {
+	if sym.Kind == "endpoint" && sym.Signature != nil && *sym.Signature != "" {
+		return *sym.Signature
+	}
+	return sym.Name
+}
+
 // inferLayer determines the architectural layer from symbol metadata or language.
 func inferLayer(sym postgres.Symbol) string {
 	// Check metadata for pre-computed layer
@@ -297,18 +310,19 @@ func extractEdgeConfidence(metadata []byte) float64 {
 	return 0
 }
 
-func (h *TraceCrossLanguageHandler) resolveSeed(ctx context.Context, project postgres.Project, params TraceCrossLanguageParams) (postgres.Symbol, error) {
-	if params.SymbolID != "" {
-		id, err := uuid.Parse(params.SymbolID)
+// resolveTraceSeed looks up the symbol a trace starts from, by ID or by name.
+func resolveTraceSeed(ctx context.Context, s *store.Store, project postgres.Project, symbolID, symbolName string) (postgres.Symbol, error) {
+	if symbolID != "" {
+		id, err := uuid.Parse(symbolID)
 		if err != nil {
 			return postgres.Symbol{}, fmt.Errorf("invalid symbol_id: %w", err)
 		}
-		sym, err := h.store.GetSymbol(ctx, id)
+		sym, err := s.GetSymbol(ctx, id)
 		if err != nil {
 			return postgres.Symbol{}, WrapSymbolError(err)
 		}
 		return sym, nil
 	}
 
-	return ResolveSymbolByName(ctx, h.store, project.Slug, params.SymbolName)
+	return ResolveSymbolByName(ctx, s, project.Slug, symbolName)
 }
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/auth"
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	defaultTraceToUIDepth  = 6
+	maxTraceToUIDepth      = 10
+	defaultTraceToUIBranch = 25
+	maxTraceToUIBranch     = 100
+)
+
+// traceToUIEdges are the edge types walked backwards from a table: data access back to
+// procedures and code, then calls and API calls back to endpoints and the UI.
+var traceToUIEdges = map[string]bool{
+	"reads_from":      true,
+	"writes_to":       true,
+	"uses_table":      true,
+	"uses_collection": true,
+	"joins":           true,
+	"calls":           true,
+	"calls_api":       true,
+}
+
+// TraceToUIParams are the parameters for the trace_to_ui tool.
+type TraceToUIParams struct {
+	Project    string `json:"project"`
+	SymbolID   string `json:"symbol_id,omitempty"`
+	SymbolName string `json:"symbol_name,omitempty"` // table or view, e.g. dbo.Orders
+	MaxDepth   int    `json:"max_depth,omitempty"`   // default: 6, max: 10
+	MaxBranch  int    `json:"max_branch,omitempty"`  // callers followed per symbol, default: 25, max: 100
+}
+
+// TraceToUIHandler implements the trace_to_ui MCP tool: the reverse of
+// trace_cross_language's downstream trace, from data back to the screens showing it.
+type TraceToUIHandler struct {
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// NewTraceToUIHandler creates a new handler.
+func NewTraceToUIHandler(s *store.Store, logger *slog.Logger) *TraceToUIHandler {
+	return &TraceToUIHandler{store: s, logger: logger}
+}
+
+// uiTraceResult is what a table surfaces through: the endpoints and UI-layer symbols
+// reachable backwards from it, each with the path leading to the table.
+type uiTraceResult struct {
+	Seed      postgres.Symbol
+	UI        []uiTracePath
+	Endpoints []uiTracePath
+	Visited   int // symbols reached in total
+	Capped    int // symbols whose callers were cut off by the branch cap
+}
+
+// uiTracePath is a reached symbol and the chain from it down to the seed, the symbol
+// itself first.
+type uiTracePath struct {
+	Node  traceNode
+	Chain []traceNode
+}
+
+// Handle traces a table back to the endpoints and UI components that surface it.
+func (h *TraceToUIHandler) Handle(ctx context.Context, params TraceToUIParams) (string, error) {
+	if params.SymbolID == "" && params.SymbolName == "" {
+		return "", fmt.Errorf("symbol_id or symbol_name is required")
+	}
+	if params.MaxDepth <= 0 {
+		params.MaxDepth = defaultTraceToUIDepth
+	}
+	params.MaxDepth = min(params.MaxDepth, maxTraceToUIDepth)
+	if params.MaxBranch <= 0 {
+		params.MaxBranch = defaultTraceToUIBranch
+	}
+	params.MaxBranch = min(params.MaxBranch, maxTraceToUIBranch)
+
+	project, err := h.store.GetProject(ctx, params.Project)
+	if err != nil {
+		return "", WrapProjectError(err)
+	}
+	if p, ok := auth.PrincipalFrom(ctx); ok && !p.IsAdmin() && project.TenantID != p.TenantID {
+		return "", fmt.Errorf("access denied to project %s", params.Project)
+	}
+
+	seed, err := resolveTraceSeed(ctx, h.store, project, params.SymbolID, params.SymbolName)
+	if err != nil {
+		return "", err
+	}
+
+	res := collectUITrace(ctx, h.store, seed, traceOptions{
+		maxDepth:  params.MaxDepth,
+		maxBranch: params.MaxBranch,
+		edgeTypes: traceToUIEdges,
+	})
+	mcp.RecordResults(ctx, len(res.UI)+len(res.Endpoints), len(res.UI)+len(res.Endpoints))
+	return formatUITrace(res, params), nil
+}
+
+// collectUITrace walks upstream from seed and keeps the UI-layer symbols and endpoints
+// it reaches, with the path from each back to the seed.
+func collectUITrace(ctx context.Context, g symbolGraph, seed postgres.Symbol, opts traceOptions) uiTraceResult {
+	nodes, capped := walkTrace(ctx, g, seed, true, opts)
+	res := uiTraceResult{Seed: seed, Visited: len(nodes), Capped: capped}
+
+	byID := make(map[uuid.UUID]traceNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.Symbol.ID] = n
+	}
+	chain := func(n traceNode) []traceNode {
+		path := []traceNode{n}
+		for cur, ok := byID[n.From]; ok; cur, ok = byID[cur.From] {
+			path = append(path, cur)
+		}
+		return path
+	}
+
+	for _, n := range nodes {
+		switch {
+		case inferLayer(n.Symbol) == "ui":
+			res.UI = append(res.UI, uiTracePath{Node: n, Chain: chain(n)})
+		case n.Symbol.Kind == "endpoint":
+			res.Endpoints = append(res.Endpoints, uiTracePath{Node: n, Chain: chain(n)})
+		}
+	}
+	return res
+}
+
+// formatUITrace lists the UI symbols and endpoints with the chain reaching the table.
+func formatUITrace(res uiTraceResult, params TraceToUIParams) string {
+	rb := mcp.NewResponseBuilder(4000)
+	rb.AddHeader(fmt.Sprintf("**Trace to UI: %s**", res.Seed.Name))
+	rb.AddLine(fmt.Sprintf("Seed: `%s` (%s, %s)", res.Seed.QualifiedName, res.Seed.Kind, res.Seed.Language))
+	rb.AddLine(fmt.Sprintf("%d UI symbols and %d endpoints among %d upstream symbols (depth %d).",
+		len(res.UI), len(res.Endpoints), res.Visited, params.MaxDepth))
+	rb.AddLine("")
+
+	if res.Capped > 0 {
+		rb.AddLine(fmt.Sprintf("_%d symbols had more than %d callers; raise max_branch to follow them all._", res.Capped, params.MaxBranch))
+		rb.AddLine("")
+	}
+
+	total := len(res.UI) + len(res.Endpoints)
+	if total == 0 {
+		rb.AddLine("No endpoint or UI symbol reaches this symbol. It may only be used by jobs, reports or other databases.")
+		return rb.Finalize(0, 0)
+	}
+
+	shown := 0
+	for _, section := range []struct {
+		title string
+		paths []uiTracePath
+	}{
+		{"### UI", res.UI},
+		{"### Endpoints", res.Endpoints},
+	} {
+		if len(section.paths) == 0 {
+			continue
+		}
+		rb.AddLine(section.title)
+		for _, p := range section.paths {
+			if !rb.AddLine(fmt.Sprintf("- %s `%s` [%s] (depth %d): %s",
+				p.Node.Symbol.Kind, traceLabel(p.Node.Symbol), p.Node.Symbol.Language, p.Node.Depth, formatUIChain(p.Chain, res.Seed))) {
+				return rb.Finalize(total, shown)
+			}
+			shown++
+		}
+		rb.AddLine("")
+	}
+	return rb.Finalize(total, shown)
+}
+
+// formatUIChain renders a path as "OrdersPage -calls_api→ GetOrders -calls→ ... Orders",
+// naming the edge type of each hop.
+func formatUIChain(chain []traceNode, seed postgres.Symbol) string {
+	parts := make([]string, 0, len(chain)+1)
+	for _, n := range chain {
+		parts = append(parts, fmt.Sprintf("%s -%s→", traceLabel(n.Symbol), n.Via))
+	}
+	parts = append(parts, seed.Name)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/mcp"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// uiTraceFixture links OrdersPage -calls_api→ GetOrders endpoint -calls→
+// OrdersController.Get -calls→ usp_GetOrders -reads_from→ Orders.
+func uiTraceFixture() (table, page postgres.Symbol, g *fakeImpactGraph) {
+	table = postgres.Symbol{ID: uuid.New(), Name: "Orders", QualifiedName: "dbo.Orders", Kind: "table", Language: "tsql"}
+	proc := postgres.Symbol{ID: uuid.New(), Name: "usp_GetOrders", QualifiedName: "dbo.usp_GetOrders", Kind: "procedure", Language: "tsql"}
+	method := postgres.Symbol{ID: uuid.New(), Name: "Get", QualifiedName: "Shop.OrdersController.Get", Kind: "method", Language: "csharp"}
+	endpoint := postgres.Symbol{ID: uuid.New(), Name: "GetOrders", QualifiedName: "GET /api/orders", Kind: "endpoint", Language: "csharp"}
+	page = postgres.Symbol{ID: uuid.New(), Name: "OrdersPage", QualifiedName: "src/pages/OrdersPage", Kind: "function", Language: "typescript"}
+
+	g = &fakeImpactGraph{fakeEndpointGraph: newFakeEndpointGraph(table, proc, method, endpoint, page)}
+	g.link(page, endpoint, "calls_api")
+	g.link(endpoint, method, "calls")
+	g.link(method, proc, "calls")
+	g.link(proc, table, "reads_from")
+	return table, page, g
+}
+
+func TestTraceToUI_TableReachesComponent(t *testing.T) {
+	table, page, g := uiTraceFixture()
+	// A column the table contains must not be walked into.
+	col := postgres.Symbol{ID: uuid.New(), Name: "Total", Kind: "column", Language: "tsql"}
+	g.symbols[col.ID] = col
+	g.link(table, col, "contains")
+
+	res := collectUITrace(context.Background(), g, table, traceOptions{maxDepth: 6, edgeTypes: traceToUIEdges})
+	if len(res.UI) != 1 || res.UI[0].Node.Symbol.ID != page.ID {
+		t.Fatalf("expected OrdersPage as the only UI symbol, got %+v", res.UI)
+	}
+	if got := res.UI[0].Node.Depth; got != 4 {
+		t.Errorf("OrdersPage depth = %d, want 4", got)
+	}
+	if len(res.Endpoints) != 1 || res.Endpoints[0].Node.Symbol.Name != "GetOrders" {
+		t.Fatalf("expected the GetOrders endpoint, got %+v", res.Endpoints)
+	}
+
+	out := formatUITrace(res, TraceToUIParams{MaxDepth: 6, MaxBranch: 25})
+	want := "OrdersPage -calls_api→ GetOrders -calls→ Get -calls→ usp_GetOrders -reads_from→ Orders"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected chain %q in output, got:\n%s", want, out)
+	}
+
+	if res := collectUITrace(context.Background(), g, table, traceOptions{maxDepth: 3, edgeTypes: traceToUIEdges}); len(res.UI) != 0 {
+		t.Errorf("expected no UI symbol within depth 3, got %+v", res.UI)
+	}
+}
+
+func TestTraceToUI_BranchCap(t *testing.T) {
+	table, _, g := uiTraceFixture()
+	for i := 0; i < 5; i++ {
+		proc := postgres.Symbol{ID: uuid.New(), Name: fmt.Sprintf("usp_Report%d", i), Kind: "procedure", Language: "tsql"}
+		g.symbols[proc.ID] = proc
+		g.link(proc, table, "reads_from")
+	}
+
+	res := collectUITrace(context.Background(), g, table, traceOptions{maxDepth: 6, maxBranch: 3, edgeTypes: traceToUIEdges})
+	if res.Capped != 1 {
+		t.Errorf("expected the table's callers to be capped, got %d capped symbols", res.Capped)
+	}
+	if out := formatUITrace(res, TraceToUIParams{MaxDepth: 6, MaxBranch: 3}); !strings.Contains(out, "raise max_branch") {
+		t.Errorf("expected a branch cap note, got:\n%s", out)
+	}
+}
+
+func TestTraceToUI_ShowsDeclaredRouteTemplate(t *testing.T) {
+	table, _, g := uiTraceFixture()
+	// Two routes that normalize alike, told apart only by their declared templates
+	declared := "GET /api/files/{**path}"
+	catchAll := postgres.Symbol{ID: uuid.New(), Name: "GET /api/files/{path}", QualifiedName: "route:GET /api/files/{path}",
+		Kind: "endpoint", Language: "csharp", Signature: &declared}
+	g.symbols[catchAll.ID] = catchAll
+	g.link(catchAll, table, "reads_from")
+
+	res := collectUITrace(context.Background(), g, table, traceOptions{maxDepth: 6, edgeTypes: traceToUIEdges})
+	out := formatUITrace(res, TraceToUIParams{MaxDepth: 6, MaxBranch: 25})
+	if !strings.Contains(out, "endpoint `GET /api/files/{**path}`") {
+		t.Errorf("expected the endpoint shown by its declared template, got:\n%s", out)
+	}
+	if !strings.Contains(out, "GET /api/files/{**path} -reads_from→ Orders") {
+		t.Errorf("expected the declared template in the chain, got:\n%s", out)
+	}
+	if strings.Contains(out, "`GET /api/files/{path}`") {
+		t.Errorf("expected the normalized route not to be shown, got:\n%s", out)
+	}
+	// An endpoint without a declared template keeps its name
+	if !strings.Contains(out, "GetOrders -calls→") {
+		t.Errorf("expected endpoints without a signature shown by name, got:\n%s", out)
+	}
+
+	rb := mcp.NewResponseBuilder(4000)
+	formatLayerGrouped(rb, []traceNode{{Symbol: catchAll, Depth: 1, Via: "reads_from", FromLang: "tsql"}})
+	if out := rb.Finalize(1, 1); !strings.Contains(out, "endpoint `GET /api/files/{**path}` via reads_from") {
+		t.Errorf("expected the cross-language trace to show the declared template, got:\n%s", out)
+	}
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+const (
+	// UsageFlushInterval is how often recorded symbol usage is written to the store.
+	UsageFlushInterval = time.Minute
+	// usageRetentionDays is how long daily usage counts are kept.
+	usageRetentionDays = 90
+)
+
+// responseSymbolID matches the ID line of the symbol cards and stubs ResponseBuilder writes.
+var responseSymbolID = regexp.MustCompile("ID: `([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`")
+
+// ResponseSymbols returns the distinct symbols a tool response shows, by ID.
+func ResponseSymbols(response string) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, m := range responseSymbolID.FindAllStringSubmatch(response, -1) {
+		id, err := uuid.Parse(m[1])
+		if err == nil && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// UsageStore is the subset of the store Usage writes to.
+type UsageStore interface {
+	IncrementSymbolUsage(ctx context.Context, arg postgres.IncrementSymbolUsageParams) error
+	DeleteSymbolUsageBefore(ctx context.Context, days int32) error
+}
+
+// Usage counts how often each symbol is shown in tool responses and periodically adds
+// the counts to the store's daily totals. Only symbol IDs and counts are kept, never
+// who made the call or what else the response held.
+type Usage struct {
+	store  UsageStore
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	counts map[uuid.UUID]int64
+}
+
+// NewUsage creates a usage recorder writing to s.
+func NewUsage(s UsageStore, logger *slog.Logger) *Usage {
+	return &Usage{store: s, logger: logger, counts: make(map[uuid.UUID]int64)}
+}
+
+// Record counts one appearance of each symbol.
+func (u *Usage) Record(ids []uuid.UUID) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, id := range ids {
+		u.counts[id]++
+	}
+}
+
+// Flush adds the counts recorded since the last flush to the store and drops daily
+// totals past retention. Counts that fail to write are kept for the next flush.
+func (u *Usage) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	counts := u.counts
+	u.counts = make(map[uuid.UUID]int64)
+	u.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	arg := postgres.IncrementSymbolUsageParams{
+		SymbolIds: make([]uuid.UUID, 0, len(counts)),
+		Hits:      make([]int64, 0, len(counts)),
+	}
+	for id, hits := range counts {
+		arg.SymbolIds = append(arg.SymbolIds, id)
+		arg.Hits = append(arg.Hits, hits)
+	}
+	if err := u.store.IncrementSymbolUsage(ctx, arg); err != nil {
+		u.mu.Lock()
+		for id, hits := range counts {
+			u.counts[id] += hits
+		}
+		u.mu.Unlock()
+		return err
+	}
+	return u.store.DeleteSymbolUsageBefore(ctx, usageRetentionDays)
+}
+
+// Run flushes every interval until ctx is done, then flushes once more.
+func (u *Usage) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is done, so the last flush gets its own deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := u.Flush(flushCtx); err != nil {
+				u.logger.Warn("failed to flush symbol usage", slog.String("error", err.Error()))
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := u.Flush(ctx); err != nil {
+				u.logger.Warn("failed to flush symbol usage", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/maraichr/lattice/internal/graph"
 	"github.com/maraichr/lattice/internal/impact"
+	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/llm"
 	"github.com/maraichr/lattice/internal/mcp/session"
 	"github.com/maraichr/lattice/internal/store"
@@ -30,6 +31,8 @@ type Engine struct {
 	graph   *graph.Client
 	impact  *impact.Engine
 	logger  *slog.Logger
+
+	directions lineage.EdgeDirections
 }
 
 // NewEngine creates a new Oracle engine.
@@ -41,9 +44,16 @@ func NewEngine(s *store.Store, sm *session.Manager, llmClient *llm.Client, graph
 		graph:   graphClient,
 		impact:  impactEngine,
 		logger:  logger,
+
+		directions: lineage.DefaultEdgeDirections(),
 	}
 }
 
+// SetEdgeDirections sets which way each edge type points in lineage answers.
+func (e *Engine) SetEdgeDirections(d lineage.EdgeDirections) {
+	e.directions = d
+}
+
 // Store returns the underlying store for project lookups in the handler.
 func (e *Engine) Store() *store.Store {
 	return e.store
@@ -88,7 +98,7 @@ func (e *Engine) Ask(ctx context.Context, project postgres.Project, req Request)
 	case "relationships":
 		blocks, items, execErr = executeRelationships(ctx, e.store, project.Slug, sel.Params)
 	case "lineage":
-		blocks, items, execErr = executeLineage(ctx, e.store, e.graph, project.Slug, sel.Params)
+		blocks, items, execErr = executeLineage(ctx, e.store, e.graph, e.directions, project.Slug, sel.Params)
 	case "impact":
 		blocks, items, execErr = executeImpact(ctx, e.store, e.impact, project.Slug, sel.Params)
 	default:
@@ -9,6 +9,7 @@ import (
 
 	"github.com/maraichr/lattice/internal/graph"
 	"github.com/maraichr/lattice/internal/impact"
+	"github.com/maraichr/lattice/internal/lineage"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
 )
@@ -265,20 +266,13 @@ func executeRelationships(ctx context.Context, s *store.Store, projectSlug strin
 }
 
 // executeLineage traces data flow via Neo4j.
-func executeLineage(ctx context.Context, s *store.Store, graphClient *graph.Client, projectSlug string, params map[string]any) ([]Block, []SymbolItem, error) {
+func executeLineage(ctx context.Context, s *store.Store, graphClient *graph.Client, directions lineage.EdgeDirections, projectSlug string, params map[string]any) ([]Block, []SymbolItem, error) {
 	symbolName := stringParam(params, "symbol_name")
 	direction := stringParam(params, "direction")
 	if direction == "" {
 		direction = "both"
 	}
 
-	if graphClient == nil {
-		return []Block{
-			headerBlock("Lineage"),
-			textBlock("Lineage queries require Neo4j, which is not configured."),
-		}, nil, nil
-	}
-
 	// Find the symbol first
 	results, err := s.SearchSymbols(ctx, postgres.SearchSymbolsParams{
 		ProjectSlug: projectSlug,
@@ -295,7 +289,7 @@ func executeLineage(ctx context.Context, s *store.Store, graphClient *graph.Clie
 	}
 
 	sym := results[0]
-	lineageResult, err := graphClient.Lineage(ctx, sym.ID, direction, 3)
+	lineageResult, err := graph.LineageFor(ctx, graphClient, s, sym.ID, direction, 3, directions.UpstreamTypes())
 	if err != nil {
 		return nil, nil, fmt.Errorf("lineage query: %w", err)
 	}
@@ -338,7 +332,7 @@ func executeImpact(ctx context.Context, s *store.Store, impactEngine *impact.Eng
 	if impactEngine == nil {
 		return []Block{
 			headerBlock("Impact Analysis"),
-			textBlock("Impact analysis requires Neo4j, which is not configured."),
+			textBlock("Impact analysis is not configured."),
 		}, nil, nil
 	}
 
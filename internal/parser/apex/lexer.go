@@ -0,0 +1,133 @@
+package apex
+
+import "strings"
+
+type tokenType int
+
+const (
+	tokIdent tokenType = iota
+	tokString
+	tokNumber
+	tokPunct
+	tokQuery // an inline [SELECT ...] or [FIND ...] block, brackets stripped
+)
+
+type token struct {
+	typ  tokenType
+	text string
+	line int
+}
+
+// is reports whether the token is the punctuation or, case-insensitively, the keyword s.
+func (t token) is(s string) bool {
+	if t.typ == tokPunct {
+		return t.text == s
+	}
+	return t.typ == tokIdent && strings.EqualFold(t.text, s)
+}
+
+// tokenize splits Apex source into tokens, dropping whitespace and comments. Inline
+// SOQL and SOSL queries become single tokQuery tokens.
+func tokenize(src string) []token {
+	var toks []token
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'':
+			start, startLine := i, line
+			i++
+			for i < len(src) && src[i] != '\'' {
+				if src[i] == '\\' {
+					i++
+				} else if src[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i++
+			toks = append(toks, token{typ: tokString, text: src[start+1 : min(i-1, len(src))], line: startLine})
+		case c == '[' && isQueryStart(src[i+1:]):
+			end := queryEnd(src, i)
+			toks = append(toks, token{typ: tokQuery, text: src[i+1 : end], line: line})
+			line += strings.Count(src[i:end], "\n")
+			i = end + 1
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			toks = append(toks, token{typ: tokIdent, text: src[start:i], line: line})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{typ: tokNumber, text: src[start:i], line: line})
+		default:
+			toks = append(toks, token{typ: tokPunct, text: string(c), line: line})
+			i++
+		}
+	}
+	return toks
+}
+
+// isQueryStart reports whether the text after a '[' opens a SOQL or SOSL query.
+func isQueryStart(rest string) bool {
+	rest = strings.TrimLeft(rest, " \t\r\n")
+	for _, kw := range []string{"SELECT", "FIND"} {
+		if len(rest) > len(kw) && strings.EqualFold(rest[:len(kw)], kw) && !isIdentPart(rest[len(kw)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryEnd returns the index of the ']' closing the query opened at open, skipping
+// quoted strings and nested brackets.
+func queryEnd(src string, open int) int {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '\'':
+			for i++; i < len(src) && src[i] != '\''; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
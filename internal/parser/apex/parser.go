@@ -0,0 +1,503 @@
+// Package apex is a best-effort, token-based parser for Salesforce Apex classes (.cls)
+// and triggers (.trigger). It recognizes class, interface, enum, trigger and method
+// declarations, inline and dynamic SOQL/SOSL queries, DML statements and static calls.
+// SObjects read by queries and written by DML become table symbols, so Apex joins the
+// lineage graph the same way stored procedures do.
+package apex
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// Parser implements a parser for Apex source.
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"apex"}
+}
+
+// keywords cannot name a method, variable or type.
+var keywords = map[string]bool{
+	"if": true, "else": true, "for": true, "while": true, "do": true, "switch": true, "when": true,
+	"return": true, "new": true, "try": true, "catch": true, "finally": true, "throw": true,
+	"break": true, "continue": true, "class": true, "interface": true, "enum": true, "trigger": true,
+	"this": true, "super": true, "null": true, "true": true, "false": true, "instanceof": true,
+	"public": true, "private": true, "protected": true, "global": true, "static": true, "final": true,
+	"abstract": true, "virtual": true, "override": true, "transient": true, "with": true, "without": true,
+	"sharing": true, "inherited": true, "testmethod": true, "webservice": true, "extends": true,
+	"implements": true, "on": true, "get": true, "set": true,
+	"insert": true, "update": true, "upsert": true, "delete": true, "undelete": true, "merge": true,
+}
+
+// dmlOps are the DML statements and the Database methods of the same names.
+var dmlOps = map[string]bool{
+	"insert": true, "update": true, "upsert": true, "delete": true, "undelete": true, "merge": true,
+}
+
+// notSObjects are the built-in types a variable holding records is never declared as.
+var notSObjects = map[string]bool{
+	"string": true, "integer": true, "long": true, "decimal": true, "double": true, "boolean": true,
+	"id": true, "date": true, "datetime": true, "time": true, "blob": true, "object": true,
+	"sobject": true, "void": true, "list": true, "set": true, "map": true,
+}
+
+// systemClasses are platform classes whose static methods are not project code.
+var systemClasses = map[string]bool{
+	"system": true, "database": true, "schema": true, "trigger": true, "json": true, "math": true,
+	"string": true, "integer": true, "decimal": true, "date": true, "datetime": true, "limits": true,
+	"userinfo": true, "test": true, "apexpages": true, "http": true, "crypto": true, "encodingutil": true,
+	"label": true, "url": true, "messaging": true, "type": true, "id": true, "list": true, "set": true,
+	"map": true, "blob": true, "pattern": true, "search": true, "eventbus": true, "auth": true,
+}
+
+// dynamicQueryRe recognizes a query string passed to Database.query and friends.
+var dynamicQueryRe = regexp.MustCompile(`(?is)^\s*(SELECT|FIND)\b`)
+
+// scope is an open brace: a type or method declaration, or a plain block.
+type scope struct {
+	symbol int    // index into symbols, -1 for a plain block
+	kind   string // class, interface, enum, trigger, method or block
+	name   string // qualified name of the declaration
+}
+
+type apexParser struct {
+	toks    []token
+	symbols []parser.Symbol
+	refs    []parser.RawReference
+	stack   []scope
+	vars    map[string]string // variable name (lower case) → SObject type of its records
+	sobject string            // SObject of the trigger being parsed
+}
+
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	ap := &apexParser{toks: tokenize(string(input.Content)), vars: make(map[string]string)}
+	ap.run()
+	symbols := append(ap.symbols, sobjectSymbols(ap.refs)...)
+	return &parser.ParseResult{Symbols: symbols, References: ap.refs}, nil
+}
+
+func (p *apexParser) tok(i int) token {
+	if i < 0 || i >= len(p.toks) {
+		return token{typ: tokPunct}
+	}
+	return p.toks[i]
+}
+
+func (p *apexParser) run() {
+	pendingDecl := -1 // a declaration whose body opens at the next '{'
+	for i := 0; i < len(p.toks); i++ {
+		t := p.toks[i]
+		switch {
+		case t.is("{"):
+			if pendingDecl >= 0 {
+				p.stack = append(p.stack, scope{symbol: pendingDecl, kind: p.symbols[pendingDecl].Kind, name: p.symbols[pendingDecl].QualifiedName})
+				pendingDecl = -1
+			} else {
+				p.stack = append(p.stack, scope{symbol: -1, kind: "block"})
+			}
+		case t.is("}"):
+			if n := len(p.stack); n > 0 {
+				if s := p.stack[n-1]; s.symbol >= 0 {
+					p.symbols[s.symbol].EndLine = t.line
+				}
+				p.stack = p.stack[:n-1]
+			}
+		case t.is(";"):
+			pendingDecl = -1
+		case t.typ == tokQuery:
+			p.query(t.text, t.line)
+		case t.is("trigger") && len(p.stack) == 0:
+			pendingDecl = p.trigger(i)
+		case (t.is("class") || t.is("interface") || t.is("enum")) && p.typeDeclAllowed(i):
+			pendingDecl = p.typeDecl(i)
+		case t.typ == tokIdent:
+			if d := p.ident(i); d >= 0 {
+				pendingDecl = d
+			}
+		}
+	}
+	// Unclosed declarations end at the last token.
+	last := p.tok(len(p.toks) - 1).line
+	for _, s := range p.stack {
+		if s.symbol >= 0 {
+			p.symbols[s.symbol].EndLine = last
+		}
+	}
+}
+
+// typeDeclAllowed reports whether a class/interface/enum keyword starts a declaration:
+// at the top level or directly inside another type.
+func (p *apexParser) typeDeclAllowed(i int) bool {
+	if p.tok(i+1).typ != tokIdent || p.tok(i-1).is(".") {
+		return false
+	}
+	top := p.top()
+	return top == nil || top.kind == "class" || top.kind == "interface"
+}
+
+func (p *apexParser) top() *scope {
+	if len(p.stack) == 0 {
+		return nil
+	}
+	return &p.stack[len(p.stack)-1]
+}
+
+// enclosing returns the qualified name of the innermost declaration.
+func (p *apexParser) enclosing() string {
+	for j := len(p.stack) - 1; j >= 0; j-- {
+		if p.stack[j].symbol >= 0 {
+			return p.stack[j].name
+		}
+	}
+	return ""
+}
+
+// enclosingType returns the qualified name of the innermost class, interface or enum.
+func (p *apexParser) enclosingType() string {
+	for j := len(p.stack) - 1; j >= 0; j-- {
+		switch p.stack[j].kind {
+		case "class", "interface", "enum":
+			return p.stack[j].name
+		}
+	}
+	return ""
+}
+
+func (p *apexParser) addSymbol(sym parser.Symbol) int {
+	sym.Language = "apex"
+	if sym.EndLine == 0 {
+		sym.EndLine = sym.StartLine
+	}
+	p.symbols = append(p.symbols, sym)
+	return len(p.symbols) - 1
+}
+
+func (p *apexParser) addRef(ref parser.RawReference) {
+	if ref.FromSymbol == "" {
+		ref.FromSymbol = p.enclosing()
+	}
+	p.refs = append(p.refs, ref)
+}
+
+// trigger parses "trigger Name on SObject (events) {".
+func (p *apexParser) trigger(i int) int {
+	name, on, obj := p.tok(i+1), p.tok(i+2), p.tok(i+3)
+	if name.typ != tokIdent || !on.is("on") || obj.typ != tokIdent {
+		return -1
+	}
+	var events []string
+	j := i + 4
+	if p.tok(j).is("(") {
+		for j++; j < len(p.toks) && !p.tok(j).is(")"); j++ {
+			if p.tok(j).typ == tokIdent {
+				events = append(events, strings.ToLower(p.tok(j).text))
+			}
+		}
+	}
+	pairs := make([]string, 0, len(events)/2)
+	for k := 0; k+1 < len(events); k += 2 {
+		pairs = append(pairs, events[k]+" "+events[k+1])
+	}
+
+	p.sobject = obj.text
+	idx := p.addSymbol(parser.Symbol{
+		Name:          name.text,
+		QualifiedName: name.text,
+		Kind:          "trigger",
+		StartLine:     p.toks[i].line,
+		Signature:     "trigger " + name.text + " on " + obj.text + " (" + strings.Join(pairs, ", ") + ")",
+	})
+	p.addRef(parser.RawReference{
+		FromSymbol:    name.text,
+		ToName:        obj.text,
+		ToQualified:   sobjectName(obj.text),
+		ReferenceType: "uses_table",
+		Line:          obj.line,
+	})
+	return idx
+}
+
+// typeDecl parses a class, interface or enum header up to its body.
+func (p *apexParser) typeDecl(i int) int {
+	kind := strings.ToLower(p.toks[i].text)
+	name := p.tok(i + 1)
+	qualified := name.text
+	if outer := p.enclosingType(); outer != "" {
+		qualified = outer + "." + name.text
+	}
+	idx := p.addSymbol(parser.Symbol{
+		Name:          name.text,
+		QualifiedName: qualified,
+		Kind:          kind,
+		StartLine:     p.toks[i].line,
+	})
+
+	refType := ""
+	for j := i + 2; j < len(p.toks) && !p.tok(j).is("{"); j++ {
+		t := p.tok(j)
+		switch {
+		case t.is("extends"):
+			refType = "inherits"
+			if kind == "interface" {
+				refType = "implements"
+			}
+		case t.is("implements"):
+			refType = "implements"
+		case t.is("<"):
+			j = p.skipGeneric(j) - 1
+		case t.typ == tokIdent && refType != "" && !p.tok(j+1).is("."):
+			p.addRef(parser.RawReference{FromSymbol: qualified, ToName: t.text, ReferenceType: refType, Line: t.line})
+		}
+	}
+	return idx
+}
+
+// ident handles an identifier that may start a method declaration, a variable
+// declaration, a DML statement or a call. It returns the index of a method symbol whose
+// body opens at the next '{', or -1.
+func (p *apexParser) ident(i int) int {
+	t := p.toks[i]
+	lower := strings.ToLower(t.text)
+	prev, next := p.tok(i-1), p.tok(i+1)
+
+	// DML statement: insert records; / Database.insert(records, false)
+	if dmlOps[lower] {
+		if prev.is(".") && p.tok(i-2).is("Database") && next.is("(") {
+			p.dml(i+2, t.line)
+		} else if !prev.is(".") && !next.is("(") && !next.is("=") && !next.is(".") {
+			p.dml(i+1, t.line)
+		}
+		return -1
+	}
+
+	// Dynamic query: Database.query('SELECT ... FROM Account')
+	if (lower == "query" || lower == "getquerylocator" || lower == "countquery") && prev.is(".") && p.tok(i-2).is("Database") &&
+		next.is("(") && p.tok(i+2).typ == tokString && dynamicQueryRe.MatchString(p.tok(i+2).text) {
+		p.query(p.tok(i+2).text, p.tok(i+2).line)
+		return -1
+	}
+
+	if keywords[lower] {
+		return -1
+	}
+
+	// Variable or parameter declaration: Type name followed by = ; , ) or :
+	if elem, end, ok := p.typeAt(i); ok && !prev.is(".") && !prev.is("new") {
+		if v := p.tok(end); v.typ == tokIdent && !keywords[strings.ToLower(v.text)] {
+			switch after := p.tok(end + 1); {
+			case after.is("=") || after.is(";") || after.is(",") || after.is(")") || after.is(":"):
+				if !notSObjects[strings.ToLower(elem)] {
+					p.vars[strings.ToLower(v.text)] = elem
+				}
+			}
+		}
+	}
+
+	if !next.is("(") {
+		return -1
+	}
+
+	// Method or constructor declaration: a type or modifier, the name, parameters, then a
+	// body or, in interfaces and abstract classes, a semicolon.
+	if top := p.top(); top != nil && (top.kind == "class" || top.kind == "interface") &&
+		(prev.typ == tokIdent || prev.is(">") || prev.is("]")) && !p.tok(i-2).is(".") && !prev.is("new") {
+		close := p.matching(i + 1)
+		if body := p.tok(close + 1); body.is("{") || body.is(";") {
+			idx := p.addSymbol(parser.Symbol{
+				Name:          t.text,
+				QualifiedName: top.name + "." + t.text,
+				Kind:          "method",
+				StartLine:     t.line,
+				Signature:     p.signature(i, close),
+			})
+			p.paramDecls(i+2, close)
+			if body.is("{") {
+				return idx
+			}
+			return -1
+		}
+		return -1
+	}
+
+	// Static call on another class: AccountService.recalculate(ids)
+	if prev.is(".") {
+		owner := p.tok(i - 2)
+		if owner.typ == tokIdent && !p.tok(i-3).is(".") && isTypeName(owner.text) &&
+			!systemClasses[strings.ToLower(owner.text)] && p.vars[strings.ToLower(owner.text)] == "" {
+			p.addRef(parser.RawReference{
+				ToName:        t.text,
+				ToQualified:   owner.text + "." + t.text,
+				ReferenceType: "calls",
+				Line:          t.line,
+			})
+		}
+		return -1
+	}
+
+	// Unqualified call to a method of the enclosing class
+	if prev.typ == tokPunct && strings.Contains("=;{}(,!&|+-*/?:", prev.text) || prev.is("return") {
+		if owner := p.enclosingType(); owner != "" {
+			p.addRef(parser.RawReference{
+				ToName:        t.text,
+				ToQualified:   owner + "." + t.text,
+				ReferenceType: "calls",
+				Line:          t.line,
+			})
+		}
+	}
+	return -1
+}
+
+// typeAt reads a type starting at i: a name, optional generic arguments and array
+// brackets. It returns the record type it holds (Account for List<Account> or
+// Account[]), the index after the type, and whether a type was read.
+func (p *apexParser) typeAt(i int) (string, int, bool) {
+	t := p.tok(i)
+	if t.typ != tokIdent {
+		return "", i, false
+	}
+	elem := t.text
+	j := i + 1
+	if p.tok(j).is("<") {
+		end := p.skipGeneric(j)
+		// The record type is the last type argument: List<Account>, Map<Id, Account>.
+		for k := end - 1; k > j; k-- {
+			if p.tok(k).typ == tokIdent {
+				elem = p.tok(k).text
+				break
+			}
+		}
+		j = end
+	}
+	for p.tok(j).is("[") && p.tok(j+1).is("]") {
+		j += 2
+	}
+	return elem, j, true
+}
+
+// skipGeneric returns the index after the '>' closing the '<' at i.
+func (p *apexParser) skipGeneric(i int) int {
+	depth := 0
+	for j := i; j < len(p.toks); j++ {
+		switch {
+		case p.tok(j).is("<"):
+			depth++
+		case p.tok(j).is(">"):
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		case p.tok(j).is(";") || p.tok(j).is("{") || p.tok(j).is("("):
+			return j
+		}
+	}
+	return len(p.toks)
+}
+
+// matching returns the index of the ')' closing the '(' at i.
+func (p *apexParser) matching(i int) int {
+	depth := 0
+	for j := i; j < len(p.toks); j++ {
+		switch {
+		case p.tok(j).is("("):
+			depth++
+		case p.tok(j).is(")"):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(p.toks) - 1
+}
+
+// paramDecls records the record types of method parameters between from and close.
+func (p *apexParser) paramDecls(from, close int) {
+	for j := from; j < close; j++ {
+		if elem, end, ok := p.typeAt(j); ok {
+			if v := p.tok(end); v.typ == tokIdent && (p.tok(end+1).is(",") || end+1 == close) {
+				if !notSObjects[strings.ToLower(elem)] {
+					p.vars[strings.ToLower(v.text)] = elem
+				}
+				j = end
+			}
+		}
+	}
+}
+
+// signature renders a method declaration from its first modifier to the closing
+// parenthesis, leaving out annotations.
+func (p *apexParser) signature(name, close int) string {
+	start := name
+	for start > 0 {
+		t := p.tok(start - 1)
+		if t.typ != tokIdent && !t.is("<") && !t.is(">") && !t.is(",") && !t.is("[") && !t.is("]") {
+			break
+		}
+		if t.typ == tokIdent && p.tok(start-2).is("@") {
+			break
+		}
+		start--
+	}
+	var b strings.Builder
+	for j := start; j <= close; j++ {
+		t := p.tok(j)
+		if j > start && t.typ == tokIdent && (p.tok(j-1).typ == tokIdent || p.tok(j-1).is(",") || p.tok(j-1).is(">")) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+// dml records a writes_to reference for the records a DML statement or Database method
+// acts on, when their SObject type is known: new Account(...), a declared variable,
+// Trigger.new/old, or an inline query.
+func (p *apexParser) dml(arg, line int) {
+	t := p.tok(arg)
+	obj := ""
+	switch {
+	case t.is("new") && p.tok(arg+1).typ == tokIdent:
+		obj, _, _ = p.typeAt(arg + 1)
+	case t.is("Trigger") && p.tok(arg+1).is(".") && p.sobject != "":
+		obj = p.sobject
+	case t.typ == tokQuery:
+		obj = soqlObject(t.text)
+	case t.typ == tokIdent:
+		obj = p.vars[strings.ToLower(t.text)]
+	}
+	if obj == "" || notSObjects[strings.ToLower(obj)] {
+		return
+	}
+	p.addRef(parser.RawReference{
+		ToName:        obj,
+		ToQualified:   sobjectName(obj),
+		ReferenceType: "writes_to",
+		Line:          line,
+	})
+}
+
+// query records reads_from references for the SObjects a SOQL or SOSL query reads.
+func (p *apexParser) query(text string, line int) {
+	for _, obj := range queryObjects(text) {
+		p.addRef(parser.RawReference{
+			ToName:        obj,
+			ToQualified:   sobjectName(obj),
+			ReferenceType: "reads_from",
+			Line:          line,
+		})
+	}
+}
+
+// isTypeName reports whether an identifier is capitalized, as Apex class names are by
+// convention, as opposed to a variable.
+func isTypeName(s string) bool {
+	return s != "" && s[0] >= 'A' && s[0] <= 'Z'
+}
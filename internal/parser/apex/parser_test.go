@@ -0,0 +1,186 @@
+package apex
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+const accountService = `public with sharing class AccountService {
+    // Accounts with open opportunities, for the renewal batch.
+    public static List<Account> openAccounts(Set<Id> ids) {
+        List<Account> accounts = [
+            SELECT Id, Name, (SELECT Id FROM Contacts)
+            FROM Account
+            WHERE Id IN :ids
+              AND Id IN (SELECT AccountId FROM Opportunity WHERE IsClosed = false)
+        ];
+        log(accounts.size());
+        return accounts;
+    }
+
+    private static void log(Integer n) {
+        System.debug('loaded ' + n + ' accounts FROM Lead');
+    }
+}
+`
+
+func TestClassWithSOQL(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "classes/AccountService.cls", Content: []byte(accountService)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "AccountService", "class")
+	assertHasSymbol(t, result.Symbols, "AccountService.openAccounts", "method")
+	assertHasSymbol(t, result.Symbols, "AccountService.log", "method")
+	assertHasSymbol(t, result.Symbols, "account", "table")
+	assertHasSymbol(t, result.Symbols, "opportunity", "table")
+	for _, s := range result.Symbols {
+		if s.QualifiedName == "AccountService.openAccounts" {
+			if s.StartLine != 3 || s.EndLine != 12 {
+				t.Errorf("openAccounts spans %d-%d, want 3-12", s.StartLine, s.EndLine)
+			}
+			if s.Signature != "public static List<Account> openAccounts(Set<Id> ids)" {
+				t.Errorf("openAccounts signature = %q", s.Signature)
+			}
+		}
+		if s.Language != "apex" {
+			t.Errorf("%s has language %q", s.QualifiedName, s.Language)
+		}
+	}
+
+	read := findRef(result.References, "Account")
+	if read == nil || read.ReferenceType != "reads_from" || read.FromSymbol != "AccountService.openAccounts" || read.Line != 4 {
+		t.Errorf("expected openAccounts reads_from Account at line 4, got %+v", read)
+	}
+	assertHasRef(t, result.References, "Opportunity", "reads_from")
+	if findRef(result.References, "Contacts") != nil {
+		t.Error("child relationship subquery should not be read as an object")
+	}
+	if findRef(result.References, "Lead") != nil {
+		t.Error("string literal should not be read as a query")
+	}
+
+	call := findRef(result.References, "log")
+	if call == nil || call.ReferenceType != "calls" || call.ToQualified != "AccountService.log" {
+		t.Errorf("expected a call to AccountService.log, got %+v", call)
+	}
+}
+
+const contactTrigger = `trigger AccountContacts on Account (after insert, after update) {
+    List<Contact> contacts = new List<Contact>();
+    for (Account a : Trigger.new) {
+        contacts.add(new Contact(LastName = a.Name, AccountId = a.Id));
+    }
+    insert contacts;
+    insert new Task(Subject = 'Welcome');
+    update Trigger.new;
+    Database.delete(contacts, false);
+    ContactRouter.assign(contacts);
+}
+`
+
+func TestTriggerWithDML(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "triggers/AccountContacts.trigger", Content: []byte(contactTrigger)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "AccountContacts", "trigger")
+	assertHasSymbol(t, result.Symbols, "contact", "table")
+	assertHasSymbol(t, result.Symbols, "task", "table")
+	for _, s := range result.Symbols {
+		if s.QualifiedName == "AccountContacts" && s.Signature != "trigger AccountContacts on Account (after insert, after update)" {
+			t.Errorf("trigger signature = %q", s.Signature)
+		}
+	}
+
+	var onTable bool
+	writes := make(map[string]int)
+	for _, r := range result.References {
+		if r.FromSymbol != "AccountContacts" {
+			t.Errorf("ref %s (%s) from %q, want the trigger", r.ToName, r.ReferenceType, r.FromSymbol)
+		}
+		switch r.ReferenceType {
+		case "uses_table":
+			onTable = r.ToName == "Account"
+		case "writes_to":
+			writes[r.ToName]++
+		}
+	}
+	if !onTable {
+		t.Error("expected the trigger to use its Account table")
+	}
+	// insert contacts and Database.delete(contacts) write Contact, Trigger.new writes Account.
+	if writes["Contact"] != 2 || writes["Task"] != 1 || writes["Account"] != 1 {
+		t.Errorf("writes = %v", writes)
+	}
+	assertHasRef(t, result.References, "assign", "calls")
+}
+
+func TestSObjectsIgnoreCase(t *testing.T) {
+	src := `public class Renewals {
+    public static void run() {
+        List<Account> open = [SELECT Id FROM account WHERE Renewal__c = true];
+        update open;
+        insert new ACCOUNT(Name = 'Renewals');
+    }
+}`
+	result, err := New().Parse(parser.FileInput{Path: "classes/Renewals.cls", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tables []parser.Symbol
+	for _, s := range result.Symbols {
+		if s.Kind == "table" {
+			tables = append(tables, s)
+		}
+	}
+	if len(tables) != 1 || tables[0].QualifiedName != "account" || !tables[0].ProjectWide {
+		t.Fatalf("expected one project-wide account table, got %+v", tables)
+	}
+	for _, r := range result.References {
+		if r.ReferenceType != "calls" && r.ToQualified != "account" {
+			t.Errorf("ref %s (%s) to %q, want account", r.ToName, r.ReferenceType, r.ToQualified)
+		}
+	}
+}
+
+func findRef(refs []parser.RawReference, toName string) *parser.RawReference {
+	for i := range refs {
+		if refs[i].ToName == toName {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
+	t.Helper()
+	for _, s := range symbols {
+		if s.QualifiedName == qname && s.Kind == kind {
+			return
+		}
+	}
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.QualifiedName + " (" + s.Kind + ")"
+	}
+	t.Errorf("missing symbol %s (%s); have: %v", qname, kind, names)
+}
+
+func assertHasRef(t *testing.T, refs []parser.RawReference, toName, refType string) {
+	t.Helper()
+	for _, r := range refs {
+		if r.ToName == toName && r.ReferenceType == refType {
+			return
+		}
+	}
+	names := make([]string, len(refs))
+	for i, r := range refs {
+		names[i] = r.ToName + " (" + r.ReferenceType + ")"
+	}
+	t.Errorf("missing ref %s (%s); have: %v", toName, refType, names)
+}
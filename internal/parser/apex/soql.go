@@ -0,0 +1,149 @@
+package apex
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+var (
+	fromRe      = regexp.MustCompile(`(?i)\bFROM\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	returningRe = regexp.MustCompile(`(?i)\bRETURNING\s+(.*)$`)
+	soslObjRe   = regexp.MustCompile(`(?:^|,)\s*([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// queryObjects returns the SObjects a SOQL or SOSL query reads. In SOQL the top-level
+// FROM object is read, along with those of semi-join subqueries in the WHERE clause;
+// subqueries in the field list walk child relationships rather than naming objects, so
+// they are skipped. SOSL reads every object in its RETURNING clause.
+func queryObjects(text string) []string {
+	text = stripQuoted(text)
+	if isSOSL(text) {
+		m := returningRe.FindStringSubmatch(text)
+		if m == nil {
+			return nil
+		}
+		var objs []string
+		for _, o := range soslObjRe.FindAllStringSubmatch(stripParens(m[1]), -1) {
+			objs = appendObject(objs, o[1])
+		}
+		return objs
+	}
+
+	// The top-level FROM is the first outside parentheses; stripping keeps offsets, so
+	// FROMs after it in the original text are semi-joins.
+	flat := stripParens(text)
+	top := fromRe.FindStringSubmatch(flat)
+	if top == nil {
+		return nil
+	}
+	objs := appendObject(nil, top[1])
+	fromAt := fromRe.FindStringIndex(flat)[0]
+	for _, loc := range fromRe.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > fromAt {
+			objs = appendObject(objs, text[loc[2]:loc[3]])
+		}
+	}
+	return objs
+}
+
+// sobjectName returns the qualified name of an SObject. Apex names are case-insensitive,
+// so Account and ACCOUNT are the same object.
+func sobjectName(obj string) string {
+	return strings.ToLower(obj)
+}
+
+// soqlObject returns the top-level object of a SOQL query, or "".
+func soqlObject(text string) string {
+	if objs := queryObjects(text); len(objs) > 0 && !isSOSL(text) {
+		return objs[0]
+	}
+	return ""
+}
+
+func appendObject(objs []string, obj string) []string {
+	for _, o := range objs {
+		if strings.EqualFold(o, obj) {
+			return objs
+		}
+	}
+	return append(objs, obj)
+}
+
+// stripQuoted blanks string literals so their contents are not read as clauses.
+func stripQuoted(s string) string {
+	b := []byte(s)
+	in := false
+	for i := 0; i < len(b); i++ {
+		switch {
+		case in && b[i] == '\\':
+			b[i] = ' '
+			if i+1 < len(b) {
+				i++
+				b[i] = ' '
+			}
+		case b[i] == '\'':
+			in = !in
+		case in:
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+// stripParens blanks parenthesized text, keeping offsets, so only the top level remains.
+func stripParens(s string) string {
+	b := []byte(s)
+	depth := 0
+	for i, c := range b {
+		switch {
+		case c == '(':
+			depth++
+			b[i] = ' '
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+			b[i] = ' '
+		case depth > 0:
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+func isSOSL(text string) bool {
+	f := strings.Fields(text)
+	return len(f) > 0 && strings.EqualFold(f[0], "FIND")
+}
+
+// sobjectSymbols returns a table symbol for each SObject the file reads or writes, at
+// its first reference, so lineage edges have a target to resolve to. SObjects are
+// declared in the org rather than the code, so the symbols are project-wide: one per
+// SObject however many files name it.
+func sobjectSymbols(refs []parser.RawReference) []parser.Symbol {
+	var symbols []parser.Symbol
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		switch ref.ReferenceType {
+		case "reads_from", "writes_to", "uses_table":
+		default:
+			continue
+		}
+		if seen[ref.ToQualified] {
+			continue
+		}
+		seen[ref.ToQualified] = true
+		symbols = append(symbols, parser.Symbol{
+			Name:          ref.ToName,
+			QualifiedName: ref.ToQualified,
+			Kind:          "table",
+			Language:      "apex",
+			StartLine:     ref.Line,
+			EndLine:       ref.Line,
+			ProjectWide:   true,
+		})
+	}
+	return symbols
+}
@@ -0,0 +1,77 @@
+// Package builtin wires every parser shipped with Lattice into a parser.Registry.
+package builtin
+
+import (
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/apex"
+	"github.com/maraichr/lattice/internal/parser/asp"
+	"github.com/maraichr/lattice/internal/parser/cobol"
+	csharpp "github.com/maraichr/lattice/internal/parser/csharp"
+	"github.com/maraichr/lattice/internal/parser/delphi"
+	"github.com/maraichr/lattice/internal/parser/golang"
+	javap "github.com/maraichr/lattice/internal/parser/java"
+	jsts "github.com/maraichr/lattice/internal/parser/javascript"
+	"github.com/maraichr/lattice/internal/parser/openapi"
+	"github.com/maraichr/lattice/internal/parser/pgsql"
+	"github.com/maraichr/lattice/internal/parser/python"
+	"github.com/maraichr/lattice/internal/parser/terraform"
+	"github.com/maraichr/lattice/internal/parser/tsql"
+	"github.com/maraichr/lattice/internal/parser/wsdl"
+)
+
+// Options tunes the built-in parsers.
+type Options struct {
+	TSQLLimits   tsql.Limits
+	JSConfidence map[string]float64 // JS/TS ORM pattern confidence overrides
+
+	// DetectMinConfidence is the content-detection threshold for files without a
+	// registered extension (0 keeps parser.DefaultDetectionConfidence)
+	DetectMinConfidence float64
+}
+
+// NewRegistry returns a registry with all built-in parsers registered by file extension.
+func NewRegistry(opts Options) *parser.Registry {
+	registry := parser.NewRegistry()
+	registry.SetDetectionThreshold(opts.DetectMinConfidence)
+	sqlRouter := parser.NewSQLRouter(tsql.NewWithLimits(opts.TSQLLimits), pgsql.New())
+	registry.Register(".sql", sqlRouter)
+	registry.Register(".sqldataprovider", sqlRouter)
+	aspParser := asp.New()
+	registry.Register(".asp", aspParser)
+	registry.Register(".aspx", aspParser)
+	registry.Register(".ascx", aspParser)
+	registry.Register(".ashx", aspParser)
+	registry.Register(".master", aspParser)
+	delphiParser := delphi.New()
+	registry.Register(".pas", delphiParser)
+	registry.Register(".dfm", delphiParser)
+	registry.Register(".dpr", delphiParser)
+	registry.Register(".java", javap.New())
+	registry.Register(".cs", csharpp.New())
+	jsParser := jsts.NewJSWithConfidence(opts.JSConfidence)
+	registry.Register(".js", jsParser)
+	registry.Register(".jsx", jsParser)
+	registry.Register(".mjs", jsParser)
+	tsParser := jsts.NewTSWithConfidence(opts.JSConfidence)
+	registry.Register(".ts", tsParser)
+	registry.Register(".tsx", tsParser)
+	registry.Register(".tf", terraform.New())
+	registry.Register(".py", python.New())
+	registry.Register(".go", golang.New())
+	wsdlParser := wsdl.New()
+	registry.Register(".wsdl", wsdlParser)
+	registry.Register(".xsd", wsdlParser)
+	cobolParser := cobol.New()
+	registry.Register(".cbl", cobolParser)
+	registry.Register(".cob", cobolParser)
+	registry.Register(".cpy", cobolParser)
+	apexParser := apex.New()
+	registry.Register(".cls", apexParser)
+	registry.Register(".trigger", apexParser)
+	// YAML and JSON files are parsed only when they are OpenAPI or Swagger specs
+	openapiParser := openapi.New()
+	registry.RegisterContent(".yaml", openapi.IsSpec, openapiParser)
+	registry.RegisterContent(".yml", openapi.IsSpec, openapiParser)
+	registry.RegisterContent(".json", openapi.IsSpec, openapiParser)
+	return registry
+}
@@ -0,0 +1,307 @@
+// Package cobol is a best-effort, line-based parser for COBOL programs and copybooks.
+// It recognizes program, section and paragraph declarations, COPY statements and
+// embedded EXEC SQL blocks, which is enough to bridge mainframe programs into the graph
+// of the database they share with other applications. Anything else is skipped.
+package cobol
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/parser/sqlutil"
+)
+
+// Parser implements a parser for COBOL source (.cbl, .cob) and copybooks (.cpy).
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"cobol"}
+}
+
+var (
+	programIDRe  = regexp.MustCompile(`(?i)^PROGRAM-ID\s*\.?\s*['"]?([A-Za-z0-9][A-Za-z0-9_-]*)`)
+	endProgramRe = regexp.MustCompile(`(?i)^END\s+PROGRAM\b`)
+	sectionRe    = regexp.MustCompile(`(?i)^([A-Za-z0-9][A-Za-z0-9_-]*)\s+SECTION\s*\.`)
+	paragraphRe  = regexp.MustCompile(`(?i)^([A-Za-z0-9][A-Za-z0-9_-]*)\s*\.\s*$`)
+	copyRe       = regexp.MustCompile(`(?i)(?:^|\s)COPY\s+['"]?([A-Za-z0-9][A-Za-z0-9_-]*)`)
+	execSQLRe    = regexp.MustCompile(`(?i)\bEXEC\s+SQL\b`)
+	endExecRe    = regexp.MustCompile(`(?i)\bEND-EXEC\b`)
+	sqlIncludeRe = regexp.MustCompile(`(?i)^\s*INCLUDE\s+([A-Za-z0-9][A-Za-z0-9_-]*)`)
+	// hostIntoRe matches the host-variable target list of SELECT ... INTO and FETCH ... INTO,
+	// which would otherwise read as a table written to.
+	hostIntoRe = regexp.MustCompile(`(?i)\bINTO\s+:[A-Za-z0-9_.-]+(\s*,\s*:[A-Za-z0-9_.-]+)*`)
+)
+
+// notParagraphs are statements that can stand alone on a line ending in a period and
+// would otherwise look like paragraph names.
+var notParagraphs = map[string]bool{
+	"EXIT": true, "GOBACK": true, "CONTINUE": true, "END-IF": true, "END-PERFORM": true,
+	"END-EVALUATE": true, "END-READ": true, "END-CALL": true, "END-EXEC": true, "ELSE": true,
+	"STOP": true, "END-SEARCH": true, "END-STRING": true, "END-WRITE": true, "END-COMPUTE": true,
+}
+
+// sqlIncludes are the communication areas every embedded-SQL program includes; they
+// are precompiler members rather than project copybooks.
+var sqlIncludes = map[string]bool{"SQLCA": true, "SQLDA": true}
+
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	lines := sourceLines(string(input.Content))
+
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	base := strings.TrimSuffix(filepath.Base(input.Path), filepath.Ext(input.Path))
+	program := ""
+	// Indexes of the open program, section and paragraph symbols, outermost first; each
+	// ends where the next one at its level or above starts.
+	var open []int
+	inProcedure := false
+
+	closeFrom := func(depth, line int) {
+		for len(open) > depth {
+			symbols[open[len(open)-1]].EndLine = line
+			open = open[:len(open)-1]
+		}
+	}
+	enclosing := func() string {
+		if len(open) > 0 {
+			return symbols[open[len(open)-1]].QualifiedName
+		}
+		return base
+	}
+	declare := func(sym parser.Symbol, depth int) {
+		closeFrom(depth, sym.StartLine-1)
+		open = append(open, len(symbols))
+		symbols = append(symbols, sym)
+	}
+
+	var sql strings.Builder
+	sqlLine := 0
+	for _, l := range lines {
+		text := strings.TrimSpace(l.code)
+		upper := strings.ToUpper(text)
+
+		// Embedded SQL, possibly spanning lines until END-EXEC
+		if sqlLine == 0 {
+			if loc := execSQLRe.FindStringIndex(text); loc != nil {
+				sqlLine = l.num
+				sql.Reset()
+				text = text[loc[1]:]
+			}
+		}
+		if sqlLine != 0 {
+			if loc := endExecRe.FindStringIndex(text); loc != nil {
+				sql.WriteString(text[:loc[0]])
+				refs = append(refs, sqlRefs(sql.String(), sqlLine, enclosing())...)
+				sqlLine = 0
+			} else {
+				sql.WriteString(text + "\n")
+			}
+			continue
+		}
+
+		switch {
+		case programIDRe.MatchString(text):
+			program = programIDRe.FindStringSubmatch(text)[1]
+			inProcedure = false
+			declare(parser.Symbol{
+				Name:          program,
+				QualifiedName: program,
+				Kind:          "module",
+				Language:      "cobol",
+				StartLine:     l.num,
+				EndLine:       l.num,
+			}, 0)
+			continue
+		case endProgramRe.MatchString(text):
+			closeFrom(0, l.num)
+			inProcedure = false
+			continue
+		case strings.HasPrefix(upper, "PROCEDURE DIVISION"):
+			inProcedure = true
+			continue
+		}
+
+		if inProcedure {
+			if m := sectionRe.FindStringSubmatch(text); m != nil && !strings.EqualFold(m[1], "DECLARATIVES") {
+				declare(parser.Symbol{
+					Name:          m[1],
+					QualifiedName: qualify(program, m[1]),
+					Kind:          "section",
+					Language:      "cobol",
+					StartLine:     l.num,
+					EndLine:       l.num,
+				}, 1)
+				continue
+			}
+			if m := paragraphRe.FindStringSubmatch(text); m != nil && l.areaA && !notParagraphs[strings.ToUpper(m[1])] {
+				depth := 1
+				if len(open) > 1 && symbols[open[1]].Kind == "section" {
+					depth = 2
+				}
+				declare(parser.Symbol{
+					Name:          m[1],
+					QualifiedName: qualify(program, m[1]),
+					Kind:          "paragraph",
+					Language:      "cobol",
+					StartLine:     l.num,
+					EndLine:       l.num,
+				}, depth)
+				continue
+			}
+		}
+
+		for _, m := range copyRe.FindAllStringSubmatch(text, -1) {
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    enclosing(),
+				ToName:        m[1],
+				ReferenceType: "imports",
+				Line:          l.num,
+			})
+		}
+	}
+
+	lastLine := 0
+	if len(lines) > 0 {
+		lastLine = lines[len(lines)-1].num
+	}
+	closeFrom(0, lastLine)
+
+	// A copybook has no PROGRAM-ID; it becomes a module named after the file so COPY
+	// statements resolve to it.
+	if program == "" {
+		symbols = append([]parser.Symbol{{
+			Name:          base,
+			QualifiedName: base,
+			Kind:          "module",
+			Language:      "cobol",
+			StartLine:     1,
+			EndLine:       max(lastLine, 1),
+		}}, symbols...)
+	}
+
+	return &parser.ParseResult{
+		Symbols:    symbols,
+		References: refs,
+	}, nil
+}
+
+// sqlRefs extracts table and procedure references from one EXEC SQL block. EXEC SQL
+// INCLUDE of a member other than SQLCA/SQLDA is a copybook import.
+func sqlRefs(sql string, line int, from string) []parser.RawReference {
+	if m := sqlIncludeRe.FindStringSubmatch(sql); m != nil {
+		if sqlIncludes[strings.ToUpper(m[1])] {
+			return nil
+		}
+		return []parser.RawReference{{
+			FromSymbol:    from,
+			ToName:        m[1],
+			ReferenceType: "imports",
+			Line:          line,
+		}}
+	}
+	sql = hostIntoRe.ReplaceAllString(sql, "")
+	refs := sqlutil.ExtractTableRefs(sql, line, from, "dbo")
+	for i := range refs {
+		if strings.Contains(refs[i].ToName, ".") {
+			refs[i].ToQualified = refs[i].ToName
+		}
+	}
+	return refs
+}
+
+func qualify(program, name string) string {
+	if program == "" {
+		return name
+	}
+	return program + "." + name
+}
+
+// srcLine is the code area of one source line.
+type srcLine struct {
+	code  string
+	num   int
+	areaA bool // code starts in area A (columns 8-11), where division, section and paragraph headers go
+}
+
+// sourceLines strips sequence numbers, indicator-area comments and *> comments, and
+// joins continuation lines. Fixed format is assumed unless the file says otherwise or
+// has code in the sequence area.
+func sourceLines(content string) []srcLine {
+	raw := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	fixed := isFixedFormat(raw)
+
+	var out []srcLine
+	for i, line := range raw {
+		num := i + 1
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">>") {
+			continue // compiler directive
+		}
+		if !fixed {
+			if strings.HasPrefix(trimmed, "*>") || trimmed == "" {
+				continue
+			}
+			out = append(out, srcLine{code: stripInlineComment(line), num: num, areaA: true})
+			continue
+		}
+
+		if len(line) < 7 {
+			continue
+		}
+		indicator := line[6]
+		code := line[7:]
+		if len(code) > 65 {
+			code = code[:65] // columns 73-80 are the identification area
+		}
+		code = stripInlineComment(code)
+		switch indicator {
+		case '*', '/':
+			continue
+		case '-':
+			if len(out) > 0 {
+				out[len(out)-1].code += strings.TrimLeft(code, " ")
+			}
+			continue
+		}
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		areaA := len(code) > 0 && strings.TrimSpace(code[:min(4, len(code))]) != ""
+		out = append(out, srcLine{code: code, num: num, areaA: areaA})
+	}
+	return out
+}
+
+// isFixedFormat reports whether the source uses fixed-form reference format: no
+// ">>SOURCE FORMAT FREE" directive and nothing but digits and spaces in columns 1-6.
+func isFixedFormat(lines []string) bool {
+	for _, line := range lines {
+		upper := strings.ToUpper(strings.TrimSpace(line))
+		if strings.HasPrefix(upper, ">>SOURCE") && strings.Contains(upper, "FREE") {
+			return false
+		}
+		if strings.HasPrefix(upper, ">>") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		for _, c := range line[:min(6, len(line))] {
+			if c != ' ' && (c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stripInlineComment(s string) string {
+	if i := strings.Index(s, "*>"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package cobol
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+const custInq = `000100 IDENTIFICATION DIVISION.
+000200 PROGRAM-ID. CUSTINQ.
+000300* Looks up a customer by id for the branch terminals.
+000400 DATA DIVISION.
+000500 WORKING-STORAGE SECTION.
+000600     COPY CUSTREC.
+000700     EXEC SQL INCLUDE SQLCA END-EXEC.
+000800 PROCEDURE DIVISION.
+000900 MAIN-LOGIC SECTION.
+001000 MAIN-PARA.
+001100     PERFORM READ-CUSTOMER.
+001200     GOBACK.
+001300 READ-CUSTOMER.
+001400     EXEC SQL
+001500         SELECT CUST_NAME, CUST_BALANCE
+001600           INTO :WS-CUST-NAME, :WS-CUST-BALANCE
+001700           FROM CUSTOMERS
+001800          WHERE CUST_ID = :WS-CUST-ID
+001900     END-EXEC.
+002000     EXEC SQL
+002100         UPDATE AUDIT_LOG SET LAST_READ = CURRENT TIMESTAMP
+002200     END-EXEC.
+002300     EXIT.
+`
+
+func TestProgramWithEmbeddedSQLAndCopy(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "src/CUSTINQ.cbl", Content: []byte(custInq)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "CUSTINQ", "module")
+	assertHasSymbol(t, result.Symbols, "CUSTINQ.MAIN-LOGIC", "section")
+	assertHasSymbol(t, result.Symbols, "CUSTINQ.MAIN-PARA", "paragraph")
+	assertHasSymbol(t, result.Symbols, "CUSTINQ.READ-CUSTOMER", "paragraph")
+	for _, s := range result.Symbols {
+		if s.Name == "GOBACK" || s.Name == "EXIT" || s.Name == "WORKING-STORAGE" {
+			t.Errorf("statement %s parsed as a symbol", s.Name)
+		}
+		if s.QualifiedName == "CUSTINQ.MAIN-PARA" && (s.StartLine != 10 || s.EndLine != 12) {
+			t.Errorf("MAIN-PARA spans %d-%d, want 10-12", s.StartLine, s.EndLine)
+		}
+	}
+
+	copyRef := findRef(result.References, "CUSTREC")
+	if copyRef == nil || copyRef.ReferenceType != "imports" || copyRef.FromSymbol != "CUSTINQ" || copyRef.Line != 6 {
+		t.Errorf("expected CUSTINQ imports CUSTREC at line 6, got %+v", copyRef)
+	}
+	if findRef(result.References, "SQLCA") != nil {
+		t.Error("SQLCA include should not be an import")
+	}
+
+	sel := findRef(result.References, "CUSTOMERS")
+	if sel == nil || sel.ReferenceType != "uses_table" || sel.FromSymbol != "CUSTINQ.READ-CUSTOMER" || sel.Line != 14 {
+		t.Errorf("expected READ-CUSTOMER uses_table CUSTOMERS at line 14, got %+v", sel)
+	}
+	if sel != nil && sel.ToQualified != "dbo.CUSTOMERS" {
+		t.Errorf("ToQualified = %q, want dbo.CUSTOMERS", sel.ToQualified)
+	}
+	assertHasRef(t, result.References, "AUDIT_LOG", "writes_to")
+	for _, r := range result.References {
+		if r.ReferenceType == "writes_to" && r.ToName != "AUDIT_LOG" {
+			t.Errorf("host variable or column read as a written table: %+v", r)
+		}
+	}
+}
+
+func TestCopybookIsModule(t *testing.T) {
+	src := `      * Customer record layout
+       01  CUSTOMER-RECORD.
+           05  CUST-ID        PIC 9(8).
+           05  CUST-NAME      PIC X(40).
+           COPY ADDRREC.
+`
+	result, err := New().Parse(parser.FileInput{Path: "copy/CUSTREC.cpy", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHasSymbol(t, result.Symbols, "CUSTREC", "module")
+	if r := findRef(result.References, "ADDRREC"); r == nil || r.FromSymbol != "CUSTREC" || r.ReferenceType != "imports" {
+		t.Errorf("expected CUSTREC imports ADDRREC, got %+v", r)
+	}
+}
+
+func TestFreeFormat(t *testing.T) {
+	src := `>>SOURCE FORMAT FREE
+IDENTIFICATION DIVISION.
+PROGRAM-ID. billing.
+PROCEDURE DIVISION.
+run-billing.
+    EXEC SQL INSERT INTO pending_invoices (id) VALUES (:ws-id) END-EXEC *> queue it
+    STOP RUN.
+`
+	result, err := New().Parse(parser.FileInput{Path: "billing.cob", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHasSymbol(t, result.Symbols, "billing.run-billing", "paragraph")
+	if r := findRef(result.References, "pending_invoices"); r == nil || r.ReferenceType != "writes_to" || r.FromSymbol != "billing.run-billing" {
+		t.Errorf("expected run-billing writes_to pending_invoices, got %+v", r)
+	}
+}
+
+func findRef(refs []parser.RawReference, toName string) *parser.RawReference {
+	for i := range refs {
+		if refs[i].ToName == toName {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
+	t.Helper()
+	for _, s := range symbols {
+		if s.QualifiedName == qname && s.Kind == kind {
+			return
+		}
+	}
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.QualifiedName + " (" + s.Kind + ")"
+	}
+	t.Errorf("missing symbol %s (%s); have: %v", qname, kind, names)
+}
+
+func assertHasRef(t *testing.T, refs []parser.RawReference, toName, refType string) {
+	t.Helper()
+	for _, r := range refs {
+		if r.ToName == toName && r.ReferenceType == refType {
+			return
+		}
+	}
+	names := make([]string, len(refs))
+	for i, r := range refs {
+		names[i] = r.ToName + " (" + r.ReferenceType + ")"
+	}
+	t.Errorf("missing ref %s (%s); have: %v", toName, refType, names)
+}
@@ -0,0 +1,48 @@
+package parser
+
+import "strings"
+
+// CollectionPrefix marks document database collections: code defining or using the
+// MongoDB collection "users" references "collection:users", the qualified name of a
+// collection symbol emitted in the same file.
+const CollectionPrefix = "collection:"
+
+// CollectionSymbols returns a collection symbol for each distinct collection named by
+// the uses_collection references, placed at its first use. Collections are created by
+// the database rather than declared, so each file carries the ones it uses and the
+// references resolve within the file.
+func CollectionSymbols(refs []RawReference, language string) []Symbol {
+	var symbols []Symbol
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.ReferenceType != "uses_collection" || seen[ref.ToQualified] {
+			continue
+		}
+		seen[ref.ToQualified] = true
+		symbols = append(symbols, Symbol{
+			Name:          ref.ToName,
+			QualifiedName: ref.ToQualified,
+			Kind:          "collection",
+			Language:      language,
+			StartLine:     ref.Line,
+			EndLine:       ref.Line,
+		})
+	}
+	return symbols
+}
+
+// MongooseCollection returns the collection Mongoose stores a model in when none is
+// given: the model name lower-cased and pluralized, as Mongoose's pluralize does for
+// regular English nouns ("User" → "users", "Category" → "categories").
+func MongooseCollection(model string) string {
+	name := strings.ToLower(model)
+	switch {
+	case strings.HasSuffix(name, "s"):
+		return name
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
@@ -0,0 +1,18 @@
+package parser
+
+import "testing"
+
+func TestMongooseCollection(t *testing.T) {
+	for model, want := range map[string]string{
+		"User":     "users",
+		"Category": "categories",
+		"Day":      "days",
+		"Box":      "boxes",
+		"Batch":    "batches",
+		"news":     "news",
+	} {
+		if got := MongooseCollection(model); got != want {
+			t.Errorf("MongooseCollection(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package parser
+
+import "regexp"
+
+// featureFlagCondition recognises the obvious feature-flag checks: flags.X, featureFlags["x"],
+// toggles.X, isEnabled("x"), FeatureManager.IsEnabledAsync("x") and LaunchDarkly-style
+// *Variation("x", ...) calls. Anything else is treated as ordinary control flow.
+var featureFlagCondition = regexp.MustCompile(
+	`(?i)\b(feature_?flags?|flags|feature_?toggles?|toggles|features)\s*(\.|\[|\?\.)` +
+		`|\bis_?(feature_?)?enabled(async)?\s*\(` +
+		`|variation\s*\(`)
+
+// IsFeatureFlagCondition reports whether a branch condition looks like a feature-flag check.
+func IsFeatureFlagCondition(cond string) bool {
+	return featureFlagCondition.MatchString(cond)
+}
+
+// MarkConditionalCalls sets Conditional on "calls" references whose line falls within one
+// of the guarded [start, end] line ranges (1-based, inclusive).
+func MarkConditionalCalls(refs []RawReference, guarded [][2]int) {
+	if len(guarded) == 0 {
+		return
+	}
+	for i := range refs {
+		if refs[i].ReferenceType != "calls" {
+			continue
+		}
+		for _, g := range guarded {
+			if refs[i].Line >= g[0] && refs[i].Line <= g[1] {
+				refs[i].Conditional = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+// ConfigKeyPrefix marks configuration keys: code reading process.env.API_URL references
+// "config:API_URL", the qualified name of a config_key symbol emitted in the same file.
+const ConfigKeyPrefix = "config:"
+
+// configReaderKinds are the symbols a configuration read is attributed to.
+var configReaderKinds = map[string]bool{
+	"function": true, "method": true, "property": true, "field": true, "class": true,
+}
+
+// ConfigRead returns the reads_config reference to key from the innermost function,
+// method, property, field or class around line. FromSymbol is empty for reads outside
+// any of them, such as module-level code.
+func ConfigRead(symbols []Symbol, key string, line int) RawReference {
+	from := ""
+	bestSpan := 1<<31 - 1
+	var visit func([]Symbol)
+	visit = func(syms []Symbol) {
+		for _, s := range syms {
+			if configReaderKinds[s.Kind] && line >= s.StartLine && line <= s.EndLine {
+				if span := s.EndLine - s.StartLine; span < bestSpan {
+					bestSpan = span
+					from = s.QualifiedName
+				}
+			}
+			visit(s.Children)
+		}
+	}
+	visit(symbols)
+
+	return RawReference{
+		FromSymbol:    from,
+		ToName:        key,
+		ToQualified:   ConfigKeyPrefix + key,
+		ReferenceType: "reads_config",
+		Line:          line,
+	}
+}
+
+// ConfigKeySymbols returns a config_key symbol for each distinct key read by the
+// reads_config references, placed at its first read. Nothing declares configuration
+// keys, so each file reading one names it; the symbols are project wide, stored once
+// and kept when any one of the reading files is indexed again.
+func ConfigKeySymbols(refs []RawReference, language string) []Symbol {
+	var symbols []Symbol
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.ReferenceType != "reads_config" || seen[ref.ToQualified] {
+			continue
+		}
+		seen[ref.ToQualified] = true
+		symbols = append(symbols, Symbol{
+			Name:          ref.ToName,
+			QualifiedName: ref.ToQualified,
+			Kind:          "config_key",
+			Language:      language,
+			StartLine:     ref.Line,
+			EndLine:       ref.Line,
+			ProjectWide:   true,
+		})
+	}
+	return symbols
+}
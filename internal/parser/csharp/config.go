@@ -0,0 +1,117 @@
+package csharp
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// configMethods are the IConfiguration methods whose first argument is a key or section.
+var configMethods = map[string]bool{
+	"GetValue":            true,
+	"GetSection":          true,
+	"GetRequiredSection":  true,
+	"GetConnectionString": true,
+}
+
+// extractConfigReads emits reads_config references for configuration read through
+// IConfiguration (Configuration["Jwt:Key"], GetValue<int>("Cache:Ttl"), GetSection("Smtp"),
+// GetConnectionString("Default")) and for Environment.GetEnvironmentVariable("X").
+// Chained sections are joined the way IConfiguration joins them, so
+// GetSection("Smtp")["Host"] reads "Smtp:Host" and GetConnectionString("Default") reads
+// "ConnectionStrings:Default".
+func extractConfigReads(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	consumed := make(map[[2]uint32]bool) // sections already read as part of a longer key
+
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "invocation_expression" && node.Type() != "element_access_expression" {
+			return
+		}
+		if consumed[[2]uint32{node.StartByte(), node.EndByte()}] {
+			return
+		}
+		key, ok := configKey(node, src, consumed)
+		if !ok {
+			return
+		}
+		refs = append(refs, parser.ConfigRead(symbols, key, int(node.StartPoint().Row)+1))
+	})
+
+	return refs
+}
+
+// configKey returns the key node reads, marking the sections it is built on as consumed.
+func configKey(node *sitter.Node, src []byte, consumed map[[2]uint32]bool) (string, bool) {
+	var receiver *sitter.Node
+	var arg, method string
+
+	switch node.Type() {
+	case "element_access_expression":
+		receiver = node.ChildByFieldName("expression")
+		args := node.ChildByFieldName("subscript")
+		if args == nil {
+			args = findChild(node, "bracketed_argument_list")
+		}
+		if receiver == nil || args == nil {
+			return "", false
+		}
+		arg = extractStringLiteral(args, src)
+
+	case "invocation_expression":
+		fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+		if fn == nil || args == nil || fn.Type() != "member_access_expression" {
+			return "", false
+		}
+		name := fn.ChildByFieldName("name")
+		if name != nil && name.Type() == "generic_name" {
+			name = findChild(name, "identifier")
+		}
+		if name == nil {
+			return "", false
+		}
+		receiver = fn.ChildByFieldName("expression")
+		method = name.Content(src)
+		arg, _ = firstRouteArg(args, src)
+		if method == "GetEnvironmentVariable" && receiver != nil && receiver.Content(src) == "Environment" && arg != "" {
+			return arg, true
+		}
+		if !configMethods[method] {
+			return "", false
+		}
+
+	default:
+		return "", false
+	}
+
+	if receiver == nil || arg == "" {
+		return "", false
+	}
+	if method == "GetConnectionString" {
+		arg = "ConnectionStrings:" + arg
+	}
+	if section, ok := configKey(receiver, src, consumed); ok {
+		consumed[[2]uint32{receiver.StartByte(), receiver.EndByte()}] = true
+		return section + ":" + arg, true
+	}
+	if !isConfigReceiver(receiver, src) {
+		return "", false
+	}
+	return arg, true
+}
+
+// isConfigReceiver reports whether expr names an IConfiguration by convention:
+// Configuration, _configuration, config, builder.Configuration and the like.
+func isConfigReceiver(expr *sitter.Node, src []byte) bool {
+	if expr.Type() != "identifier" && expr.Type() != "member_access_expression" {
+		return false
+	}
+	name := expr.Content(src)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ToLower(strings.TrimLeft(name, "_"))
+	return name == "config" || name == "configuration" || name == "cfg"
+}
@@ -0,0 +1,206 @@
+package csharp
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// httpClientMethods are HttpClient and RestSharp client methods whose first argument
+// is the request URI.
+var httpClientMethods = map[string]bool{
+	"GetAsync":            true,
+	"PostAsync":           true,
+	"PutAsync":            true,
+	"PatchAsync":          true,
+	"DeleteAsync":         true,
+	"GetStringAsync":      true,
+	"GetStreamAsync":      true,
+	"GetByteArrayAsync":   true,
+	"GetFromJsonAsync":    true,
+	"PostAsJsonAsync":     true,
+	"PutAsJsonAsync":      true,
+	"PatchAsJsonAsync":    true,
+	"DeleteFromJsonAsync": true,
+	"GetJsonAsync":        true, // RestSharp
+	"PostJsonAsync":       true,
+	"PutJsonAsync":        true,
+}
+
+// refitVerbs are the Refit attributes declaring an interface method's HTTP route.
+var refitVerbs = map[string]bool{
+	"Get": true, "Post": true, "Put": true, "Patch": true, "Delete": true, "Head": true, "Options": true,
+}
+
+// httpClientConfidence is the confidence of a calls_api reference inferred from a
+// client call, where the receiver is only known to be an HTTP client by its method.
+const httpClientConfidence = 0.85
+
+// extractAPICalls emits calls_api references for outgoing HTTP requests:
+// HttpClient.GetAsync("/api/...") and friends, new RestRequest("...") for RestSharp,
+// Refit interface methods annotated with [Get("/api/users/{id}")], and SOAP service
+// contract methods annotated with [OperationContract]. Routes are
+// normalized with parser.NormalizeRoute; interpolated holes become {name} templates.
+func extractAPICalls(root *sitter.Node, src []byte, namespace string, classRanges []classRange) []parser.RawReference {
+	var refs []parser.RawReference
+
+	walkTree(root, func(node *sitter.Node) {
+		line := int(node.StartPoint().Row) + 1
+
+		switch node.Type() {
+		case "invocation_expression":
+			fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+			if fn == nil || args == nil || fn.Type() != "member_access_expression" {
+				return
+			}
+			name := fn.ChildByFieldName("name")
+			if name == nil {
+				return
+			}
+			if name.Type() == "generic_name" {
+				name = findChild(name, "identifier")
+			}
+			if name == nil || !httpClientMethods[name.Content(src)] {
+				return
+			}
+			// GetAsync and friends also exist on caches and stores, so the argument
+			// itself has to look like a URL.
+			route, ok := firstRouteArg(args, src)
+			if !ok || !looksLikeURL(route) {
+				return
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    findEnclosingClass(node, classRanges),
+				ToName:        parser.NormalizeRoute(route),
+				ReferenceType: "calls_api",
+				Confidence:    httpClientConfidence,
+				Line:          line,
+			})
+
+		case "object_creation_expression":
+			typ, args := node.ChildByFieldName("type"), node.ChildByFieldName("arguments")
+			if typ == nil || args == nil || typ.Content(src) != "RestRequest" {
+				return
+			}
+			route, ok := firstRouteArg(args, src)
+			if !ok {
+				return
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    findEnclosingClass(node, classRanges),
+				ToName:        parser.NormalizeRoute(route),
+				ReferenceType: "calls_api",
+				Confidence:    httpClientConfidence,
+				Line:          line,
+			})
+
+		case "attribute":
+			if ref, ok := soapOperationRef(node, src, namespace); ok {
+				refs = append(refs, ref)
+				return
+			}
+			name := node.ChildByFieldName("name")
+			if name == nil || !refitVerbs[name.Content(src)] {
+				return
+			}
+			iface := enclosingInterface(node, src, namespace)
+			if iface == "" {
+				return
+			}
+			template := extractAttributeStringParam(node.Content(src))
+			if template == "" {
+				return
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    iface,
+				ToName:        parser.NormalizeRoute(template),
+				ReferenceType: "calls_api",
+				Line:          line,
+			})
+		}
+	})
+
+	return refs
+}
+
+// firstRouteArg returns the first argument when it is a string literal or an
+// interpolated string, with interpolations rendered as route parameters.
+func firstRouteArg(args *sitter.Node, src []byte) (string, bool) {
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() != "argument" || arg.NamedChildCount() == 0 {
+			continue
+		}
+		value := arg.NamedChild(0)
+		var raw string
+		switch value.Type() {
+		case "string_literal", "verbatim_string_literal":
+			raw = extractStringLiteral(arg, src)
+		case "interpolated_string_expression":
+			raw = interpolatedTemplate(value, src)
+		default:
+			return "", false
+		}
+		if raw == "" || strings.ContainsAny(raw, " \t\n") {
+			return "", false
+		}
+		return raw, true
+	}
+	return "", false
+}
+
+// looksLikeURL reports whether s is an absolute URL or a path, as opposed to a cache
+// key like "user:{id}".
+func looksLikeURL(s string) bool {
+	if strings.Contains(s, "://") {
+		return true
+	}
+	return strings.Contains(s, "/") && !strings.Contains(s, ":")
+}
+
+var identifierPath = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// interpolatedTemplate renders $"/api/users/{user.Id}" as "/api/users/{Id}": each
+// interpolation becomes a route parameter named after its last identifier, or {param}
+// for anything more complex.
+func interpolatedTemplate(node *sitter.Node, src []byte) string {
+	var b strings.Builder
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "string_content":
+			b.WriteString(child.Content(src))
+		case "interpolation":
+			expr := strings.TrimSpace(strings.Trim(child.Content(src), "{}"))
+			if i := strings.IndexAny(expr, ":,"); i >= 0 {
+				expr = strings.TrimSpace(expr[:i]) // format or alignment clause
+			}
+			name := "param"
+			if identifierPath.MatchString(expr) {
+				name = expr[strings.LastIndex(expr, ".")+1:]
+			}
+			b.WriteString("{" + name + "}")
+		}
+	}
+	return b.String()
+}
+
+// enclosingInterface returns the qualified name of the interface declaring the
+// method the node belongs to, or "" outside an interface.
+func enclosingInterface(node *sitter.Node, src []byte, namespace string) string {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		switch n.Type() {
+		case "interface_declaration":
+			if name := n.ChildByFieldName("name"); name != nil {
+				return qualifyCSharp(namespace, name.Content(src))
+			}
+			return ""
+		case "class_declaration", "struct_declaration":
+			return ""
+		}
+	}
+	return ""
+}
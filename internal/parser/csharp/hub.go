@@ -0,0 +1,92 @@
+package csharp
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// hubLifecycleMethods are Hub overrides the SignalR runtime calls itself; clients
+// cannot invoke them.
+var hubLifecycleMethods = map[string]bool{
+	"OnConnectedAsync": true, "OnDisconnectedAsync": true, "Dispose": true,
+}
+
+// isHubClass reports whether a class derives from SignalR's Hub or Hub<T>.
+func isHubClass(node *sitter.Node, src []byte) bool {
+	baseList := findChild(node, "base_list")
+	if baseList == nil {
+		return false
+	}
+	for _, ref := range extractBaseList(baseList, src, "") {
+		name := ref.ToName
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "Hub" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractHubEndpoints emits an endpoint symbol for each public method of a hub class,
+// qualified as "hub:Namespace.ChatHub.Send" so it sits beside the method symbol, and a
+// calls reference from the endpoint to the method implementing it. JS clients invoking
+// the method by name are matched to the endpoint by the resolver's hub_method strategy.
+func extractHubEndpoints(body *sitter.Node, src []byte, ns, typeName string) ([]parser.Symbol, []parser.RawReference) {
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		if child.Type() != "method_declaration" || !isClientInvocable(child, src) {
+			continue
+		}
+		name, _ := extractMethodDecl(child, src)
+		if name == "" || hubLifecycleMethods[name] {
+			continue
+		}
+		methodQName := qualifyCSharp(ns, typeName+"."+name)
+		endpointQName := parser.HubMethodPrefix + methodQName
+		symbols = append(symbols, parser.Symbol{
+			Name:          name,
+			QualifiedName: endpointQName,
+			Kind:          "endpoint",
+			Language:      "csharp",
+			StartLine:     int(child.StartPoint().Row) + 1,
+			EndLine:       int(child.EndPoint().Row) + 1,
+			Signature:     "HUB " + typeName + "." + name,
+		})
+		refs = append(refs, parser.RawReference{
+			FromSymbol:    endpointQName,
+			ToName:        name,
+			ToQualified:   methodQName,
+			ReferenceType: "calls",
+			Line:          int(child.StartPoint().Row) + 1,
+		})
+	}
+
+	return symbols, refs
+}
+
+// isClientInvocable reports whether a hub method is public and an instance method;
+// static and non-public methods are not exposed to clients.
+func isClientInvocable(method *sitter.Node, src []byte) bool {
+	public := false
+	for i := 0; i < int(method.ChildCount()); i++ {
+		child := method.Child(i)
+		if child.Type() != "modifier" {
+			continue
+		}
+		switch child.Content(src) {
+		case "public":
+			public = true
+		case "static":
+			return false
+		}
+	}
+	return public
+}
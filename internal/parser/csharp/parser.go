@@ -2,6 +2,7 @@ package csharp
 
 import (
 	"context"
+	"regexp"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -89,6 +90,24 @@ func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
 	procRefs := extractStoredProcRefs(root, input.Content, classRanges)
 	refs = append(refs, procRefs...)
 
+	refs = append(refs, extractAPICalls(root, input.Content, namespace, classRanges)...)
+
+	// Minimal-API routes (app.MapGet and friends), wherever they are registered
+	routeSyms, routeRefs := extractMinimalAPIEndpoints(root, input.Content)
+	symbols = append(symbols, routeSyms...)
+	refs = append(refs, routeRefs...)
+
+	// Configuration and environment reads, each resolved to a config_key symbol in this file
+	configRefs := extractConfigReads(root, input.Content, symbols)
+	refs = append(refs, configRefs...)
+	symbols = append(symbols, parser.ConfigKeySymbols(configRefs, "csharp")...)
+
+	if input.DetectConditionalCalls {
+		parser.MarkConditionalCalls(refs, featureFlagRanges(root, input.Content))
+	}
+
+	parser.QualifyOverloads(symbols, refs)
+
 	return &parser.ParseResult{
 		Symbols:    symbols,
 		References: refs,
@@ -188,6 +207,18 @@ func extractClass(node *sitter.Node, src []byte, ns string) ([]parser.Symbol, []
 		memberSyms, memberRefs := extractMembers(body, src, ns, name)
 		symbols = append(symbols, memberSyms...)
 		refs = append(refs, memberRefs...)
+
+		// SignalR hubs expose their public methods to clients
+		if isHubClass(node, src) {
+			hubSyms, hubRefs := extractHubEndpoints(body, src, ns, name)
+			symbols = append(symbols, hubSyms...)
+			refs = append(refs, hubRefs...)
+		}
+
+		// ASP.NET controllers expose their attribute-routed actions over HTTP
+		routeSyms, routeRefs := extractControllerEndpoints(node, body, src, ns, name)
+		symbols = append(symbols, routeSyms...)
+		refs = append(refs, routeRefs...)
 	}
 
 	return symbols, refs
@@ -294,13 +325,14 @@ func extractMembers(body *sitter.Node, src []byte, ns, typeName string) ([]parse
 			if name != "" {
 				qname := qualifyCSharp(ns, typeName+"."+name)
 				symbols = append(symbols, parser.Symbol{
-					Name:          name,
-					QualifiedName: qname,
-					Kind:          "method",
-					Language:      "csharp",
-					StartLine:     int(child.StartPoint().Row) + 1,
-					EndLine:       int(child.EndPoint().Row) + 1,
-					Signature:     sig,
+					Name:                name,
+					QualifiedName:       qname,
+					Kind:                "method",
+					Language:            "csharp",
+					StartLine:           int(child.StartPoint().Row) + 1,
+					EndLine:             int(child.EndPoint().Row) + 1,
+					Signature:           sig,
+					NormalizedSignature: normalizeSignature(child, src),
 				})
 			}
 
@@ -335,6 +367,8 @@ func extractMembers(body *sitter.Node, src []byte, ns, typeName string) ([]parse
 						FromSymbol:    qualifyCSharp(ns, typeName),
 						ToName:        dbSetType,
 						ReferenceType: "uses_table",
+						Mapping:       parser.MappingORM,
+						Entity:        dbSetType,
 						Line:          int(child.StartPoint().Row) + 1,
 					})
 				}
@@ -384,6 +418,10 @@ func extractMembers(body *sitter.Node, src []byte, ns, typeName string) ([]parse
 func extractMethodDecl(node *sitter.Node, src []byte) (string, string) {
 	name := ""
 	sig := ""
+	// The name field, not the first identifier: a return type such as Task is one too.
+	if n := node.ChildByFieldName("name"); n != nil {
+		name = n.Content(src)
+	}
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child.Type() == "identifier" && name == "" {
@@ -645,6 +683,8 @@ func extractAttributeRefs(root *sitter.Node, src []byte, _ string, classRanges [
 					ToName:        tableName,
 					ToQualified:   "dbo." + tableName,
 					ReferenceType: "uses_table",
+					Mapping:       parser.MappingAttribute,
+					Entity:        fromSymbol,
 					Line:          line,
 				})
 			}
@@ -654,6 +694,9 @@ func extractAttributeRefs(root *sitter.Node, src []byte, _ string, classRanges [
 	return refs
 }
 
+// setEntityPattern matches the entity of an EF Core Set<T>() query.
+var setEntityPattern = regexp.MustCompile(`\bSet<([A-Za-z_][A-Za-z0-9_.]*)>\(\)`)
+
 func extractInlineSQLRefs(root *sitter.Node, src []byte, _ string, classRanges []classRange) []parser.RawReference {
 	var refs []parser.RawReference
 
@@ -723,6 +766,12 @@ func extractInlineSQLRefs(root *sitter.Node, src []byte, _ string, classRanges [
 				sqlStr := extractStringLiteral(arg, src)
 				if sqlStr != "" && looksLikeSQL(sqlStr) {
 					tableRefs := extractSQLTableRefs(sqlStr, line, fromSymbol)
+					// Set<User>().FromSqlRaw(...) reads the table of entity User
+					if m := setEntityPattern.FindStringSubmatch(memberAccess.Content(src)); m != nil && strings.HasPrefix(methodName, "FromSql") {
+						for i := range tableRefs {
+							tableRefs[i].Entity = m[1]
+						}
+					}
 					refs = append(refs, tableRefs...)
 				}
 			}
@@ -917,6 +966,23 @@ func findChild(node *sitter.Node, nodeType string) *sitter.Node {
 	return nil
 }
 
+// featureFlagRanges returns the line ranges of if statements and conditional expressions whose condition is a
+// feature-flag check, for parser.MarkConditionalCalls.
+func featureFlagRanges(root *sitter.Node, src []byte) [][2]int {
+	var ranges [][2]int
+	walkTree(root, func(n *sitter.Node) {
+		if n.Type() != "if_statement" && n.Type() != "conditional_expression" {
+			return
+		}
+		cond := n.ChildByFieldName("condition")
+		if cond == nil || !parser.IsFeatureFlagCondition(cond.Content(src)) {
+			return
+		}
+		ranges = append(ranges, [2]int{int(n.StartPoint().Row) + 1, int(n.EndPoint().Row) + 1})
+	})
+	return ranges
+}
+
 func walkTree(node *sitter.Node, fn func(*sitter.Node)) {
 	fn(node)
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -993,6 +1059,7 @@ func extractSQLTableRefs(sql string, line int, fromSymbol string) []parser.RawRe
 					ToName:        tableName,
 					ToQualified:   "dbo." + tableName,
 					ReferenceType: "uses_table",
+					Mapping:       parser.MappingSQL,
 					Line:          line,
 				})
 			}
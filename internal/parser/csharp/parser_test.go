@@ -1,6 +1,7 @@
 package csharp
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/maraichr/lattice/internal/parser"
@@ -35,6 +36,34 @@ namespace MyApp.Models {
 	assertSymbol(t, symbolMap, "MyApp.Models.User.User", "method") // constructor
 }
 
+func TestNormalizedSignature(t *testing.T) {
+	src := `
+namespace MyApp.Services {
+    public static class Mailer {
+        public static void Send(this string to, [FromBody] ref Message message, bool urgent = false, params string[] cc) {}
+        public static void Send(string to) {}
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "Mailer.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overloads are qualified by their signature so that both are stored
+	var qnames []string
+	for _, s := range result.Symbols {
+		if s.Name == "Send" {
+			qnames = append(qnames, s.QualifiedName)
+		}
+	}
+	want := []string{"MyApp.Services.Mailer.Send(string,Message,bool=,string...)", "MyApp.Services.Mailer.Send(string)"}
+	if !slices.Equal(qnames, want) {
+		t.Errorf("overload qualified names = %q, want %q", qnames, want)
+	}
+}
+
 func TestInterface(t *testing.T) {
 	src := `
 namespace MyApp {
@@ -245,6 +274,48 @@ namespace MyApp {
 	assertRefTarget(t, tableRefs, "Users")
 }
 
+func TestTableMappingEntities(t *testing.T) {
+	src := `
+namespace MyApp {
+    [Table("Users")]
+    public class User {
+    }
+    public class AppDbContext {
+        public DbSet<User> Users { get; set; }
+        public void Legacy() {
+            var users = Set<User>().FromSqlRaw("SELECT * FROM tbl_users");
+            var audit = Database.ExecuteSqlRaw("DELETE FROM tbl_audit");
+        }
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "AppDbContext.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"Users":     "MyApp.User", // [Table]
+		"User":      "User",       // DbSet<User>
+		"tbl_users": "User",       // Set<User>().FromSqlRaw
+		"tbl_audit": "",           // SQL not tied to an entity
+	}
+	for _, ref := range filterRefs(result.References, "uses_table") {
+		entity, ok := want[ref.ToName]
+		if !ok {
+			continue
+		}
+		if ref.Entity != entity {
+			t.Errorf("%s (%s): entity %q, want %q", ref.ToName, ref.Mapping, ref.Entity, entity)
+		}
+		delete(want, ref.ToName)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing uses_table refs: %v", want)
+	}
+}
+
 func TestDapperQuery(t *testing.T) {
 	src := `
 namespace MyApp {
@@ -670,3 +741,363 @@ func refsToNames(refs []parser.RawReference) []string {
 	}
 	return names
 }
+
+func TestFeatureFlagGuardedCallsAreConditional(t *testing.T) {
+	src := `
+namespace MyApp {
+    public class OrderRepo {
+        public void Place(Order o) {
+            if (flags.NewCheckout) {
+                var cmd = new SqlCommand("PlaceOrderV2", conn);
+            }
+            if (o.Total > 0) {
+                var audit = new SqlCommand("AuditOrder", conn);
+            }
+            var cmd2 = new SqlCommand("NotifyWarehouse", conn);
+        }
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "OrderRepo.cs", Content: []byte(src), DetectConditionalCalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conditional := make(map[string]bool)
+	for _, r := range filterRefs(result.References, "calls") {
+		conditional[r.ToName] = r.Conditional
+	}
+	if len(conditional) != 3 {
+		t.Fatalf("expected 3 calls refs, got %v", conditional)
+	}
+	if !conditional["PlaceOrderV2"] {
+		t.Error("expected PlaceOrderV2 (behind flags.NewCheckout) to be conditional")
+	}
+	if conditional["AuditOrder"] || conditional["NotifyWarehouse"] {
+		t.Errorf("expected ordinary branches and unguarded calls to stay unconditional, got %v", conditional)
+	}
+
+	// Off by default.
+	result, err = p.Parse(parser.FileInput{Path: "OrderRepo.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range result.References {
+		if r.Conditional {
+			t.Errorf("expected no conditional refs when detection is off, got %s", r.ToName)
+		}
+	}
+}
+
+func TestHttpClientCallsAPI(t *testing.T) {
+	src := `namespace Shop.Clients;
+
+public class UserClient
+{
+    private readonly HttpClient _http;
+    private readonly IDistributedCache _cache;
+
+    public async Task<User> GetUser(int id)
+    {
+        var cached = await _cache.GetAsync($"user:{id}");
+        var response = await _http.GetAsync($"/api/users/{id}?include=roles");
+        return await _http.GetFromJsonAsync<User>("https://users.internal/api/users/me/");
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "UserClient.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_api")
+	routes := make(map[string]bool)
+	for _, r := range calls {
+		routes[r.ToName] = true
+		if r.FromSymbol != "Shop.Clients.UserClient" {
+			t.Errorf("%s: expected call from Shop.Clients.UserClient, got %q", r.ToName, r.FromSymbol)
+		}
+	}
+	for _, want := range []string{"/api/users/{id}", "/api/users/me"} {
+		if !routes[want] {
+			t.Errorf("expected calls_api to %s, got %v", want, routes)
+		}
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected 2 calls_api refs (cache keys are not routes), got %v", routes)
+	}
+}
+
+func TestRefitInterfaceCallsAPI(t *testing.T) {
+	src := `namespace Shop.Clients
+{
+    public interface IUsersApi
+    {
+        [Get("/api/users/{id}")]
+        Task<User> GetUser(int id);
+
+        [Post("/api/users")]
+        Task CreateUser([Body] User user);
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "IUsersApi.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_api")
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls_api refs, got %+v", calls)
+	}
+	if calls[0].ToName != "/api/users/{id}" || calls[1].ToName != "/api/users" {
+		t.Errorf("expected the attribute route templates, got %q and %q", calls[0].ToName, calls[1].ToName)
+	}
+	for _, r := range calls {
+		if r.FromSymbol != "Shop.Clients.IUsersApi" {
+			t.Errorf("%s: expected call from the Refit interface, got %q", r.ToName, r.FromSymbol)
+		}
+	}
+}
+
+func TestServiceContractCallsSOAPOperation(t *testing.T) {
+	src := `namespace Shop.Legacy
+{
+    [System.ServiceModel.ServiceContractAttribute(Namespace="http://example.com/orders")]
+    public interface IOrderService
+    {
+        [System.ServiceModel.OperationContractAttribute(Action="http://example.com/orders/IOrderService/GetOrder", ReplyAction="*")]
+        System.Threading.Tasks.Task<GetOrderResponse> GetOrderAsync(GetOrderRequest request);
+
+        [OperationContract]
+        void CancelOrder(int id);
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "Reference.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_api")
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls_api refs, got %+v", calls)
+	}
+	if calls[0].ToName != "soap:GetOrder" || calls[1].ToName != "soap:CancelOrder" {
+		t.Errorf("expected soap:GetOrder and soap:CancelOrder, got %q and %q", calls[0].ToName, calls[1].ToName)
+	}
+	for _, r := range calls {
+		if r.FromSymbol != "Shop.Legacy.IOrderService" {
+			t.Errorf("%s: expected call from the service contract, got %q", r.ToName, r.FromSymbol)
+		}
+	}
+}
+
+func TestHubMethodsAreEndpoints(t *testing.T) {
+	src := `namespace Chat.Hubs
+{
+    public class ChatHub : Hub<IChatClient>
+    {
+        public async Task SendMessage(string user, string message)
+        {
+            await Clients.All.ReceiveMessage(user, message);
+        }
+
+        public override Task OnConnectedAsync() => base.OnConnectedAsync();
+
+        private string Normalize(string text) => text.Trim();
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "ChatHub.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "Chat.Hubs.ChatHub.SendMessage", "method")
+	assertHasSymbol(t, result.Symbols, "hub:Chat.Hubs.ChatHub.SendMessage", "endpoint")
+
+	var endpoints []string
+	for _, s := range result.Symbols {
+		if s.Kind == "endpoint" {
+			endpoints = append(endpoints, s.QualifiedName)
+		}
+	}
+	if len(endpoints) != 1 {
+		t.Errorf("expected only SendMessage as a hub endpoint, got %v", endpoints)
+	}
+
+	found := false
+	for _, r := range filterRefs(result.References, "calls") {
+		if r.FromSymbol == "hub:Chat.Hubs.ChatHub.SendMessage" && r.ToQualified == "Chat.Hubs.ChatHub.SendMessage" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a calls ref from the hub endpoint to its method")
+	}
+}
+
+func TestControllerActionsAreEndpoints(t *testing.T) {
+	src := `namespace Shop.Api
+{
+    [ApiController]
+    [Route("api/[controller]")]
+    public class UsersController : ControllerBase
+    {
+        [HttpGet]
+        public IActionResult List() => Ok();
+
+        [HttpGet("{id:int}", Name = "GetUser")]
+        public IActionResult Get(int id) => Ok();
+
+        [HttpPost]
+        [Route("import/{**path}")]
+        public IActionResult Import(string path) => Ok();
+
+        [HttpDelete("~/admin/users/{id?}")]
+        public IActionResult Remove(int id) => Ok();
+
+        [Route("[action]")]
+        public IActionResult Export() => Ok();
+
+        public IActionResult Helper() => Ok();
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "UsersController.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var endpoints, declared []string
+	for _, s := range result.Symbols {
+		if s.Kind == "endpoint" {
+			endpoints = append(endpoints, s.Name)
+			declared = append(declared, s.Signature)
+		}
+	}
+	want := []string{
+		"GET /api/Users",
+		"GET /api/Users/{id}",
+		"POST /api/Users/import/{path}",
+		"DELETE /admin/users/{id}",
+		"ALL /api/Users/Export",
+	}
+	if !slices.Equal(endpoints, want) {
+		t.Errorf("expected endpoints %v, got %v", want, endpoints)
+	}
+	// The signature keeps each template as declared
+	wantDeclared := []string{
+		"GET /api/Users",
+		"GET /api/Users/{id:int}",
+		"POST /api/Users/import/{**path}",
+		"DELETE /admin/users/{id?}",
+		"ALL /api/Users/Export",
+	}
+	if !slices.Equal(declared, wantDeclared) {
+		t.Errorf("expected declared routes %v, got %v", wantDeclared, declared)
+	}
+
+	found := false
+	for _, r := range filterRefs(result.References, "calls") {
+		if r.FromSymbol == "route:GET /api/Users/{id}" && r.ToQualified == "Shop.Api.UsersController.Get" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a calls ref from the endpoint to its action")
+	}
+}
+
+func TestMinimalAPIRoutesAreEndpoints(t *testing.T) {
+	src := `var app = builder.Build();
+var api = app.MapGroup("/api");
+var users = api.MapGroup("users").RequireAuthorization();
+
+users.MapGet("/{id:guid}", UserHandlers.GetUser);
+users.MapPost("/", async (User u, IUserStore store) => Results.Created());
+app.MapGet("/health", () => "ok").WithName("Health");
+app.MapDelete("/orders/{id}", DeleteOrder);
+
+app.Run();
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "Program.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "route:GET /api/users/{id}", "endpoint")
+	assertHasSymbol(t, result.Symbols, "route:POST /api/users", "endpoint")
+	assertHasSymbol(t, result.Symbols, "route:GET /health", "endpoint")
+	assertHasSymbol(t, result.Symbols, "route:DELETE /orders/{id}", "endpoint")
+
+	handlers := map[string]string{}
+	for _, r := range filterRefs(result.References, "calls") {
+		handlers[r.FromSymbol] = r.ToQualified
+	}
+	if handlers["route:GET /api/users/{id}"] != "UserHandlers.GetUser" || handlers["route:DELETE /orders/{id}"] != "DeleteOrder" {
+		t.Errorf("expected calls refs to method group handlers, got %v", handlers)
+	}
+	if _, ok := handlers["route:GET /health"]; ok {
+		t.Error("expected no calls ref for a lambda handler")
+	}
+}
+
+func TestConfigurationReads(t *testing.T) {
+	src := `namespace Shop.Mail;
+
+public class MailSender
+{
+    private readonly IConfiguration _configuration;
+
+    public string Host => _configuration.GetSection("Smtp")["Host"];
+
+    public void Send()
+    {
+        var port = _configuration.GetValue<int>("Smtp:Port");
+        var key = Configuration["Jwt:Key"];
+        var db = _configuration.GetConnectionString("Default");
+        var region = Environment.GetEnvironmentVariable("AWS_REGION");
+        var cached = _cache["Smtp:Host"];
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "MailSender.cs", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"Smtp:Host":                 "Shop.Mail.MailSender.Host",
+		"Smtp:Port":                 "Shop.Mail.MailSender.Send",
+		"Jwt:Key":                   "Shop.Mail.MailSender.Send",
+		"ConnectionStrings:Default": "Shop.Mail.MailSender.Send",
+		"AWS_REGION":                "Shop.Mail.MailSender.Send",
+	}
+	reads := filterRefs(result.References, "reads_config")
+	if len(reads) != len(want) {
+		t.Errorf("expected %d reads_config refs (sections count once, caches not at all), got %v", len(want), refsToNames(reads))
+	}
+	for _, r := range reads {
+		from, ok := want[r.ToName]
+		if !ok {
+			t.Errorf("unexpected reads_config key %q", r.ToName)
+			continue
+		}
+		if r.FromSymbol != from {
+			t.Errorf("%s: expected read from %s, got %q", r.ToName, from, r.FromSymbol)
+		}
+	}
+	for key := range want {
+		assertHasSymbol(t, result.Symbols, parser.ConfigKeyPrefix+key, "config_key")
+	}
+}
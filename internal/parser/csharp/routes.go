@@ -0,0 +1,342 @@
+package csharp
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// aspNetVerbAttributes maps ASP.NET Core attribute-routing attributes to the HTTP
+// method they route.
+var aspNetVerbAttributes = map[string]string{
+	"HttpGet": "GET", "HttpPost": "POST", "HttpPut": "PUT", "HttpPatch": "PATCH",
+	"HttpDelete": "DELETE", "HttpHead": "HEAD", "HttpOptions": "OPTIONS",
+}
+
+// minimalAPIMethods maps minimal-API route builders to the HTTP method they route.
+var minimalAPIMethods = map[string]string{
+	"MapGet": "GET", "MapPost": "POST", "MapPut": "PUT", "MapPatch": "PATCH", "MapDelete": "DELETE",
+}
+
+// routeToken matches the [controller] and [action] tokens of an attribute route.
+var routeToken = regexp.MustCompile(`(?i)\[(controller|action)\]`)
+
+// routeParameter matches an ASP.NET route parameter with its catch-all stars,
+// constraints, default or optional marker: {id:int}, {id?}, {**path}, {page=1}.
+var routeParameter = regexp.MustCompile(`\{\*{0,2}([A-Za-z_][A-Za-z0-9_]*)[^}]*\}`)
+
+// aspNetRoute combines a controller's route prefix with an action's template the way
+// ASP.NET does, a template starting with / or ~/ replacing the prefix, and normalizes
+// the result: [controller] and [action] are filled in and parameters are reduced to
+// their name, so "api/[controller]" and "{id:int}" on UsersController become
+// /api/Users/{id}.
+func aspNetRoute(prefix, template, controller, action string) string {
+	return parser.NormalizeRoute(routeParameter.ReplaceAllString(aspNetTemplate(prefix, template, controller, action), "{$1}"))
+}
+
+// aspNetTemplate combines a route prefix and template like aspNetRoute but leaves the
+// parameters as declared: api/Users/{id:int}.
+func aspNetTemplate(prefix, template, controller, action string) string {
+	route := template
+	switch {
+	case strings.HasPrefix(template, "~/"):
+		route = template[1:]
+	case !strings.HasPrefix(template, "/"):
+		route = prefix + "/" + template
+	}
+	route = routeToken.ReplaceAllStringFunc(route, func(token string) string {
+		if strings.EqualFold(token, "[action]") {
+			return action
+		}
+		return strings.TrimSuffix(controller, "Controller")
+	})
+	return route
+}
+
+// routeAttribute is an attribute routing an action or controller: its HTTP method, ""
+// for [Route], and its template, "" when it has none.
+type routeAttribute struct {
+	verb, template string
+	hasTemplate    bool
+}
+
+// routeAttributes returns the [Route] and [HttpGet]-style attributes of a declaration.
+func routeAttributes(node *sitter.Node, src []byte) []routeAttribute {
+	var attrs []routeAttribute
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		list := node.NamedChild(i)
+		if list.Type() != "attribute_list" {
+			continue
+		}
+		for j := 0; j < int(list.NamedChildCount()); j++ {
+			attr := list.NamedChild(j)
+			name := attr.ChildByFieldName("name")
+			if attr.Type() != "attribute" || name == nil {
+				continue
+			}
+			short := name.Content(src)
+			if k := strings.LastIndex(short, "."); k >= 0 {
+				short = short[k+1:]
+			}
+			short = strings.TrimSuffix(short, "Attribute")
+			verb, isVerb := aspNetVerbAttributes[short]
+			if !isVerb && short != "Route" {
+				continue
+			}
+			template, ok := attributeTemplate(attr, src)
+			attrs = append(attrs, routeAttribute{verb: verb, template: template, hasTemplate: ok})
+		}
+	}
+	return attrs
+}
+
+// attributeTemplate returns an attribute's first positional string argument, skipping
+// named ones such as Name = "GetUser".
+func attributeTemplate(attr *sitter.Node, src []byte) (string, bool) {
+	args := findChild(attr, "attribute_argument_list")
+	if args == nil {
+		return "", false
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() != "attribute_argument" || arg.NamedChildCount() == 0 {
+			continue
+		}
+		switch arg.NamedChild(0).Type() {
+		case "string_literal", "verbatim_string_literal":
+			return extractStringLiteral(arg, src), true
+		}
+	}
+	return "", false
+}
+
+// extractControllerEndpoints emits an endpoint symbol for each attribute-routed action
+// of a controller, qualified with its method and route, "route:GET /api/Users/{id}" for
+// [Route("api/[controller]")] and [HttpGet("{id:int}")], with a calls reference to the
+// action. An action routed by [Route] alone takes any method (ALL). Frontend calls_api
+// references are matched to it by the resolver's api_route strategy.
+func extractControllerEndpoints(node, body *sitter.Node, src []byte, ns, typeName string) ([]parser.Symbol, []parser.RawReference) {
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	prefixes := []string{""}
+	if class := routeAttributes(node, src); len(class) > 0 {
+		prefixes = nil
+		for _, attr := range class {
+			if attr.verb == "" {
+				prefixes = append(prefixes, attr.template)
+			}
+		}
+		if len(prefixes) == 0 {
+			prefixes = []string{""}
+		}
+	}
+
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		if child.Type() != "method_declaration" {
+			continue
+		}
+		attrs := routeAttributes(child, src)
+		if len(attrs) == 0 {
+			continue
+		}
+		name, _ := extractMethodDecl(child, src)
+		if name == "" {
+			continue
+		}
+
+		// [Route] templates are shared by the verb attributes that have none of their own
+		var templates []string
+		for _, attr := range attrs {
+			if attr.verb == "" {
+				templates = append(templates, attr.template)
+			}
+		}
+		var routes []routeAttribute
+		for _, attr := range attrs {
+			switch {
+			case attr.verb == "":
+			case attr.hasTemplate || len(templates) == 0:
+				routes = append(routes, attr)
+			default:
+				for _, t := range templates {
+					routes = append(routes, routeAttribute{verb: attr.verb, template: t})
+				}
+			}
+		}
+		if len(routes) == 0 {
+			for _, t := range templates {
+				routes = append(routes, routeAttribute{verb: "ALL", template: t})
+			}
+		}
+
+		methodQName := qualifyCSharp(ns, typeName+"."+name)
+		for _, r := range routes {
+			for _, prefix := range prefixes {
+				route := r.verb + " " + aspNetRoute(prefix, r.template, typeName, name)
+				endpointQName := parser.APIRoutePrefix + route
+				symbols = append(symbols, parser.Symbol{
+					Name:          route,
+					QualifiedName: endpointQName,
+					Kind:          "endpoint",
+					Language:      "csharp",
+					StartLine:     int(child.StartPoint().Row) + 1,
+					EndLine:       int(child.EndPoint().Row) + 1,
+					Signature:     r.verb + " " + parser.DeclaredRoute(aspNetTemplate(prefix, r.template, typeName, name)),
+				})
+				refs = append(refs, parser.RawReference{
+					FromSymbol:    endpointQName,
+					ToName:        name,
+					ToQualified:   methodQName,
+					ReferenceType: "calls",
+					Line:          int(child.StartPoint().Row) + 1,
+				})
+			}
+		}
+	}
+
+	return symbols, refs
+}
+
+// extractMinimalAPIEndpoints emits an endpoint symbol for each minimal-API route,
+// app.MapGet("/users/{id}", GetUser), prefixed by the MapGroup calls it is chained on
+// or a variable holding one was assigned from. A handler passed as a method group gets
+// a calls reference from the endpoint; lambdas have no symbol to reference.
+func extractMinimalAPIEndpoints(root *sitter.Node, src []byte) ([]parser.Symbol, []parser.RawReference) {
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+	groups := make(map[string]string) // variable → route prefix
+
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "variable_declarator":
+			if value := declaratorValue(node); value != nil {
+				if prefix, ok := groupPrefix(value, src, groups); ok {
+					if name := findChild(node, "identifier"); name != nil {
+						groups[name.Content(src)] = prefix
+					}
+				}
+			}
+
+		case "invocation_expression":
+			fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+			if fn == nil || args == nil || fn.Type() != "member_access_expression" {
+				return
+			}
+			name := fn.ChildByFieldName("name")
+			if name == nil {
+				return
+			}
+			verb, ok := minimalAPIMethods[name.Content(src)]
+			if !ok {
+				return
+			}
+			template, ok := firstRouteArg(args, src)
+			if !ok {
+				return
+			}
+			prefix, _ := groupPrefix(fn.ChildByFieldName("expression"), src, groups)
+			// Unlike attribute routes, a leading / does not escape the group
+			route := verb + " " + aspNetRoute("", joinRoute(prefix, template), "", "")
+			endpointQName := parser.APIRoutePrefix + route
+			line := int(node.StartPoint().Row) + 1
+			symbols = append(symbols, parser.Symbol{
+				Name:          route,
+				QualifiedName: endpointQName,
+				Kind:          "endpoint",
+				Language:      "csharp",
+				StartLine:     line,
+				EndLine:       int(node.EndPoint().Row) + 1,
+				Signature:     verb + " " + parser.DeclaredRoute(joinRoute(prefix, template)),
+			})
+			if handler := minimalAPIHandler(args); handler != nil {
+				qualified := handler.Content(src)
+				short := qualified
+				if i := strings.LastIndex(short, "."); i >= 0 {
+					short = short[i+1:]
+				}
+				refs = append(refs, parser.RawReference{
+					FromSymbol:    endpointQName,
+					ToName:        short,
+					ToQualified:   qualified,
+					ReferenceType: "calls",
+					Line:          line,
+				})
+			}
+		}
+	})
+
+	return symbols, refs
+}
+
+// declaratorValue returns the initializer of a variable declarator, if any.
+func declaratorValue(node *sitter.Node) *sitter.Node {
+	n := int(node.NamedChildCount())
+	if n < 2 {
+		return nil
+	}
+	value := node.NamedChild(n - 1)
+	if value.Type() == "equals_value_clause" && value.NamedChildCount() > 0 {
+		return value.NamedChild(0)
+	}
+	return value
+}
+
+// groupPrefix returns the route prefix of a route builder expression and whether it is
+// a route group: the MapGroup templates along a call chain such as
+// app.MapGroup("/api").MapGroup("/users").RequireAuthorization(), starting from a
+// variable's recorded group. Other builders, like app, have no prefix.
+func groupPrefix(expr *sitter.Node, src []byte, groups map[string]string) (string, bool) {
+	if expr == nil {
+		return "", false
+	}
+	switch expr.Type() {
+	case "identifier":
+		prefix, ok := groups[expr.Content(src)]
+		return prefix, ok
+	case "invocation_expression":
+		fn := expr.ChildByFieldName("function")
+		if fn == nil || fn.Type() != "member_access_expression" {
+			return "", false
+		}
+		prefix, isGroup := groupPrefix(fn.ChildByFieldName("expression"), src, groups)
+		name := fn.ChildByFieldName("name")
+		if name == nil || name.Content(src) != "MapGroup" {
+			return prefix, isGroup
+		}
+		args := expr.ChildByFieldName("arguments")
+		if args == nil {
+			return prefix, true
+		}
+		if template, ok := firstRouteArg(args, src); ok {
+			prefix = joinRoute(prefix, template)
+		}
+		return prefix, true
+	}
+	return "", false
+}
+
+func joinRoute(prefix, template string) string {
+	return strings.TrimRight(prefix, "/") + "/" + strings.TrimLeft(template, "/")
+}
+
+// minimalAPIHandler returns the handler of a Map call when it is a method group,
+// GetUser or UserHandlers.Get, rather than a lambda.
+func minimalAPIHandler(args *sitter.Node) *sitter.Node {
+	if args.NamedChildCount() < 2 {
+		return nil
+	}
+	arg := args.NamedChild(1)
+	if arg.Type() != "argument" || arg.NamedChildCount() == 0 {
+		return nil
+	}
+	switch value := arg.NamedChild(0); value.Type() {
+	case "identifier", "member_access_expression":
+		return value
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package csharp
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// normalizeSignature returns the parameter types of a method declaration in
+// parser.Symbol's NormalizedSignature form: "(string,int,bool=,string...)" for
+// (this string s, ref int x, bool strict = false, params string[] rest). Attributes and
+// the this, ref, out and in modifiers are dropped.
+func normalizeSignature(node *sitter.Node, src []byte) string {
+	params := node.ChildByFieldName("parameters")
+	if params == nil {
+		return ""
+	}
+	var types []string
+	for i := 0; i < int(params.ChildCount()); i++ {
+		child := params.Child(i)
+		switch {
+		case child.Type() == "parameter":
+			typ := child.ChildByFieldName("type")
+			if typ == nil {
+				continue
+			}
+			t := compactType(typ.Content(src))
+			if hasDefault(child) {
+				t += "="
+			}
+			types = append(types, t)
+		case params.FieldNameForChild(i) == "type":
+			// The grammar leaves a params array's type and name directly in the list
+			t := strings.TrimSuffix(compactType(child.Content(src)), "[]")
+			types = append(types, t+"...")
+		}
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
+
+// hasDefault reports whether a parameter declares a default value, written after its
+// name.
+func hasDefault(param *sitter.Node) bool {
+	name := param.ChildByFieldName("name")
+	if name == nil {
+		return false
+	}
+	last := param.NamedChild(int(param.NamedChildCount()) - 1)
+	return last != nil && last.StartByte() >= name.EndByte()
+}
+
+// compactType removes the whitespace from a type as written.
+func compactType(t string) string {
+	return strings.Join(strings.Fields(t), "")
+}
@@ -0,0 +1,69 @@
+package csharp
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+var (
+	operationActionRe = regexp.MustCompile(`\bAction\s*=\s*"([^"]*)"`)
+	operationNameRe   = regexp.MustCompile(`\bName\s*=\s*"([^"]*)"`)
+)
+
+// soapOperationRef turns an [OperationContract] attribute on a service contract
+// interface method, as written by hand or generated by svcutil/dotnet-svcutil, into a
+// calls_api reference to "soap:Operation". The operation is named by the attribute's
+// Action (its last path segment) or Name, else by the method with any Async suffix
+// dropped; the resolver matches it to the WSDL operation of that name.
+func soapOperationRef(attr *sitter.Node, src []byte, namespace string) (parser.RawReference, bool) {
+	name := attr.ChildByFieldName("name")
+	if name == nil || attributeBaseName(name.Content(src)) != "OperationContract" {
+		return parser.RawReference{}, false
+	}
+	iface := enclosingInterface(attr, src, namespace)
+	if iface == "" {
+		return parser.RawReference{}, false
+	}
+
+	text := attr.Content(src)
+	op := ""
+	if m := operationActionRe.FindStringSubmatch(text); m != nil && m[1] != "*" {
+		op = m[1][strings.LastIndexAny(m[1], "/:")+1:]
+	}
+	if op == "" {
+		if m := operationNameRe.FindStringSubmatch(text); m != nil {
+			op = m[1]
+		}
+	}
+	if op == "" {
+		for n := attr.Parent(); n != nil; n = n.Parent() {
+			if n.Type() == "method_declaration" {
+				method, _ := extractMethodDecl(n, src)
+				op = strings.TrimSuffix(method, "Async")
+				break
+			}
+		}
+	}
+	if op == "" {
+		return parser.RawReference{}, false
+	}
+	return parser.RawReference{
+		FromSymbol:    iface,
+		ToName:        parser.SOAPOperationPrefix + op,
+		ReferenceType: "calls_api",
+		Line:          int(attr.StartPoint().Row) + 1,
+	}, true
+}
+
+// attributeBaseName reduces System.ServiceModel.OperationContractAttribute to
+// OperationContract.
+func attributeBaseName(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, "Attribute")
+}
@@ -1,6 +1,11 @@
 package parser
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -46,3 +51,143 @@ func DetectDialect(content []byte) string {
 	// Default to pgsql for ambiguous cases
 	return "pgsql"
 }
+
+// DefaultDetectionConfidence is the minimum confidence for content-based detection to route
+// a file to a parser. Below it the file is skipped, so a misdetection cannot add bogus symbols.
+const DefaultDetectionConfidence = 0.8
+
+// maxDetectBytes bounds how much of a file is sniffed.
+const maxDetectBytes = 64 * 1024
+
+// Detection is the result of sniffing a file whose extension is not registered.
+type Detection struct {
+	Language   string  // detected language, e.g. tsql, java, shell
+	Extension  string  // registry extension that parses Language, "" when none does
+	Confidence float64 // 0.0-1.0
+	Reason     string  // why the file was or was not routed to a parser
+}
+
+// detectCandidateExts are extensions that say nothing about the language, so the content decides.
+var detectCandidateExts = map[string]bool{
+	"":      true,
+	".txt":  true,
+	".ddl":  true,
+	".dump": true,
+}
+
+// Detectable reports whether a file should be sniffed for its language: it has no
+// extension or a generic text one, and is not under a hidden directory such as .git.
+func Detectable(path string) bool {
+	norm := strings.ReplaceAll(path, "\\", "/")
+	for _, seg := range strings.Split(norm, "/") {
+		if strings.HasPrefix(seg, ".") && seg != "." && seg != ".." {
+			return false
+		}
+	}
+	return detectCandidateExts[strings.ToLower(filepath.Ext(norm))]
+}
+
+// languageSignal is a pattern characteristic of a language, with the confidence it contributes.
+type languageSignal struct {
+	re     *regexp.Regexp
+	weight float64
+}
+
+// languageSignals are matched against a file's content; a language's confidence is the sum
+// of its matching signals, capped at 1.
+var languageSignals = []struct {
+	language  string
+	extension string
+	signals   []languageSignal
+}{
+	{"sql", ".sql", []languageSignal{
+		{regexp.MustCompile(`(?im)^\s*(CREATE|ALTER)\s+(OR\s+(REPLACE|ALTER)\s+)?(TABLE|VIEW|PROC|PROCEDURE|FUNCTION|TRIGGER|INDEX|SEQUENCE|SCHEMA)\b`), 0.6},
+		{regexp.MustCompile(`(?im)^\s*GO\s*$`), 0.3},
+		{regexp.MustCompile(`(?i)\bINSERT\s+INTO\s+[\w.\[\]"]+|\bSELECT\b[^;]{1,500}?\bFROM\s+[\w.\[\]"]+`), 0.2},
+		{regexp.MustCompile(`(?m)^\s*--`), 0.1},
+	}},
+	{"java", ".java", []languageSignal{
+		{regexp.MustCompile(`(?m)^package\s+[\w.]+;`), 0.5},
+		{regexp.MustCompile(`(?m)^import\s+[\w.*]+;`), 0.2},
+		{regexp.MustCompile(`\b(class|interface|enum)\s+\w+[^{;]*\{`), 0.3},
+	}},
+	{"csharp", ".cs", []languageSignal{
+		{regexp.MustCompile(`(?m)^using\s+System[\w.]*;`), 0.4},
+		{regexp.MustCompile(`(?m)^\s*namespace\s+[\w.]+`), 0.4},
+		{regexp.MustCompile(`\b(class|interface|struct)\s+\w+[^{;]*\{`), 0.2},
+	}},
+	{"terraform", ".tf", []languageSignal{
+		{regexp.MustCompile(`(?m)^resource\s+"\w+"\s+"[\w-]+"\s*\{`), 0.6},
+		{regexp.MustCompile(`(?m)^(provider|module|variable|output|data)\s+"[\w-]+"`), 0.3},
+		{regexp.MustCompile(`(?m)^terraform\s*\{`), 0.3},
+	}},
+}
+
+// shebangLanguages maps shebang interpreters to languages; javascript and python have
+// parsers.
+var shebangLanguages = map[string]string{
+	"node": "javascript", "deno": "javascript",
+	"sh": "shell", "bash": "shell", "zsh": "shell", "ksh": "shell",
+	"python": "python", "python3": "python", "ruby": "ruby", "perl": "perl",
+}
+
+// shebangExtensions gives the parser extension of the shebang languages that have one.
+var shebangExtensions = map[string]string{
+	"javascript": ".js",
+	"python":     ".py",
+}
+
+// DetectLanguage guesses a file's language from its name and content: a shebang, a known
+// file name such as Dockerfile, or characteristic tokens. SQL is refined to tsql or pgsql
+// with DetectDialect. The caller decides whether the confidence is high enough to act on.
+func DetectLanguage(path string, content []byte) Detection {
+	if len(content) > maxDetectBytes {
+		content = content[:maxDetectBytes]
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return Detection{Reason: "binary content"}
+	}
+
+	base := strings.ToLower(filepath.Base(path))
+	if base == "dockerfile" || strings.HasPrefix(base, "dockerfile.") {
+		return Detection{Language: "dockerfile", Confidence: 1, Reason: "Dockerfile"}
+	}
+
+	if bytes.HasPrefix(content, []byte("#!")) {
+		line, _, _ := strings.Cut(string(content[2:]), "\n")
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			interp := filepath.Base(fields[0])
+			if interp == "env" && len(fields) > 1 {
+				interp = fields[1]
+			}
+			if lang, ok := shebangLanguages[interp]; ok {
+				return Detection{Language: lang, Extension: shebangExtensions[lang], Confidence: 1, Reason: "shebang " + interp}
+			}
+			return Detection{Reason: "shebang for unknown interpreter " + interp}
+		}
+	}
+
+	best := Detection{Reason: "no language detected"}
+	for _, l := range languageSignals {
+		score := 0.0
+		for _, s := range l.signals {
+			if s.re.Match(content) {
+				score += s.weight
+			}
+		}
+		score = math.Min(score, 1)
+		if score > best.Confidence {
+			best = Detection{
+				Language:   l.language,
+				Extension:  l.extension,
+				Confidence: score,
+				Reason:     fmt.Sprintf("%s tokens", l.language),
+			}
+		}
+	}
+	if best.Language == "sql" {
+		best.Language = DetectDialect(content)
+	}
+	return best
+}
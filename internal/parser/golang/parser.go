@@ -0,0 +1,325 @@
+package golang
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// Parser implements a Go parser on the standard library's go/parser.
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"go"}
+}
+
+// Parse extracts package-qualified functions, methods (qualified by receiver type),
+// structs, interfaces, other named types and constants, with imports references and
+// inherits references for embedded structs and interfaces. Names are qualified with the
+// package's import path, github.com/acme/billing/store.New, or with the bare package
+// name when input.ImportPath is unknown. Each file also declares a package symbol named
+// by the import path, which its imports references come from and those of other
+// packages resolve to. Every file of the package declares the same one, so it is project
+// wide: stored once and kept when any one of the files is indexed again. A
+// file with syntax errors yields what could be parsed, with a warning per error.
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, input.Path, input.Content, goparser.ParseComments|goparser.SkipObjectResolution)
+	if file == nil {
+		return nil, err
+	}
+
+	var warnings []parser.ParseWarning
+	if list, ok := err.(scanner.ErrorList); ok {
+		for _, e := range list {
+			warnings = append(warnings, parser.ParseWarning{Line: e.Pos.Line, Message: e.Msg})
+		}
+	}
+
+	x := &extractor{fset: fset, src: input.Content, pkg: packagePath(file.Name.Name, input.ImportPath), imports: make(map[string]string)}
+	pkg := x.symbol(file.Name.Name, x.pkg, "package", file.Package, file.Name.End(), file.Doc)
+	pkg.Signature = "package " + file.Name.Name
+	pkg.ProjectWide = true
+	x.symbols = append(x.symbols, pkg)
+
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		x.imports[name] = path
+		x.refs = append(x.refs, parser.RawReference{
+			FromSymbol:    x.pkg,
+			ToName:        path,
+			ToQualified:   path,
+			ReferenceType: "imports",
+			Line:          x.line(imp.Pos()),
+		})
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			x.funcDecl(d)
+		case *ast.GenDecl:
+			x.genDecl(d)
+		}
+	}
+
+	return &parser.ParseResult{
+		Symbols:    x.symbols,
+		References: x.refs,
+		Warnings:   warnings,
+	}, nil
+}
+
+type extractor struct {
+	fset    *token.FileSet
+	src     []byte
+	pkg     string            // import path or name that qualifies the file's declarations
+	imports map[string]string // name a package is imported under → import path
+	symbols []parser.Symbol
+	refs    []parser.RawReference
+}
+
+// packagePath returns what qualifies the declarations of package name at importPath: the
+// import path, with _test for an external test package, or name when the path is unknown.
+func packagePath(name, importPath string) string {
+	switch {
+	case importPath == "":
+		return name
+	case strings.HasSuffix(name, "_test"):
+		return importPath + "_test"
+	}
+	return importPath
+}
+
+// importName returns the name a package is conventionally imported under: the last
+// element of its path, before a major version element, without a go- prefix or a .vN
+// suffix. github.com/jackc/pgx/v5 → pgx, gopkg.in/yaml.v3 → yaml.
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func (x *extractor) funcDecl(d *ast.FuncDecl) {
+	name := d.Name.Name
+	qname := x.pkg + "." + name
+	kind := "function"
+	if d.Recv != nil && len(d.Recv.List) > 0 {
+		if recv := receiverType(d.Recv.List[0].Type); recv != "" {
+			qname = x.pkg + "." + recv + "." + name
+			kind = "method"
+		}
+	}
+
+	// The signature runs from func to the body.
+	end := d.End()
+	if d.Body != nil {
+		end = d.Body.Lbrace
+	}
+	sym := x.symbol(name, qname, kind, d.Pos(), d.End(), d.Doc)
+	sym.Signature = strings.TrimSpace(x.text(d.Pos(), end))
+	sym.Complexity = complexity(d.Body)
+	x.symbols = append(x.symbols, sym)
+}
+
+// complexity is a cyclomatic-style count of a function body: one plus each if, loop,
+// non-default case and && or ||. Function literals count towards the enclosing function.
+func complexity(body *ast.BlockStmt) int {
+	n := 1
+	if body == nil {
+		return n
+	}
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch s := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if s.List != nil {
+				n++
+			}
+		case *ast.CommClause:
+			if s.Comm != nil {
+				n++
+			}
+		case *ast.BinaryExpr:
+			if s.Op == token.LAND || s.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+func (x *extractor) genDecl(d *ast.GenDecl) {
+	for _, spec := range d.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			x.typeSpec(d, s)
+		case *ast.ValueSpec:
+			if d.Tok != token.CONST {
+				continue
+			}
+			doc := s.Doc
+			if doc == nil && len(d.Specs) == 1 {
+				doc = d.Doc
+			}
+			for _, n := range s.Names {
+				if n.Name == "_" {
+					continue
+				}
+				sym := x.symbol(n.Name, x.pkg+"."+n.Name, "constant", s.Pos(), s.End(), doc)
+				sym.Signature = strings.TrimSpace(x.text(s.Pos(), s.End()))
+				x.symbols = append(x.symbols, sym)
+			}
+		}
+	}
+}
+
+func (x *extractor) typeSpec(d *ast.GenDecl, s *ast.TypeSpec) {
+	// A lone type declaration spans from the type keyword and carries its doc comment.
+	start, end, doc := s.Pos(), s.End(), s.Doc
+	if !d.Lparen.IsValid() {
+		start, end = d.Pos(), d.End()
+		if doc == nil {
+			doc = d.Doc
+		}
+	}
+
+	name := s.Name.Name
+	qname := x.pkg + "." + name
+	kind := "type"
+	var embedded []ast.Expr
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		kind = "struct"
+		for _, f := range t.Fields.List {
+			if len(f.Names) == 0 {
+				embedded = append(embedded, f.Type)
+			}
+		}
+	case *ast.InterfaceType:
+		kind = "interface"
+		for _, m := range t.Methods.List {
+			if len(m.Names) == 0 {
+				embedded = append(embedded, m.Type)
+			}
+		}
+	}
+
+	sym := x.symbol(name, qname, kind, start, end, doc)
+	sym.Signature = "type " + name + " " + kind
+	if kind == "type" {
+		sym.Signature = strings.TrimSpace("type " + x.text(s.Pos(), s.End()))
+	}
+	x.symbols = append(x.symbols, sym)
+
+	for _, e := range embedded {
+		to, qualified := x.typeName(e)
+		if to == "" {
+			continue // type constraints such as ~int | ~string
+		}
+		x.refs = append(x.refs, parser.RawReference{
+			FromSymbol:    qname,
+			ToName:        to,
+			ToQualified:   qualified,
+			ReferenceType: "inherits",
+			Line:          x.line(e.Pos()),
+		})
+	}
+}
+
+// typeName returns the name of an embedded type and its package-qualified name:
+// Base → (Base, pkg.Base), *Base → (Base, pkg.Base), io.Reader → (Reader, io.Reader),
+// pg.Pool imported from github.com/jackc/pgx/v5/pgxpool → (Pool, github.com/jackc/pgx/v5/pgxpool.Pool).
+// Type arguments are dropped.
+func (x *extractor) typeName(e ast.Expr) (string, string) {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name, x.pkg + "." + t.Name
+	case *ast.StarExpr:
+		return x.typeName(t.X)
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			if path, ok := x.imports[pkg.Name]; ok {
+				return t.Sel.Name, path + "." + t.Sel.Name
+			}
+			return t.Sel.Name, pkg.Name + "." + t.Sel.Name
+		}
+	case *ast.IndexExpr:
+		return x.typeName(t.X)
+	case *ast.IndexListExpr:
+		return x.typeName(t.X)
+	}
+	return "", ""
+}
+
+// receiverType returns the type name of a method receiver: T, *T, T[K] and *T[K] are all T.
+func receiverType(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.ParenExpr:
+		return receiverType(t.X)
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	case *ast.IndexListExpr:
+		return receiverType(t.X)
+	}
+	return ""
+}
+
+func (x *extractor) symbol(name, qname, kind string, start, end token.Pos, doc *ast.CommentGroup) parser.Symbol {
+	s, e := x.fset.Position(start), x.fset.Position(end)
+	sym := parser.Symbol{
+		Name:          name,
+		QualifiedName: qname,
+		Kind:          kind,
+		Language:      "go",
+		StartLine:     s.Line,
+		EndLine:       e.Line,
+		StartCol:      s.Column,
+		EndCol:        e.Column,
+	}
+	if doc != nil {
+		sym.DocComment = strings.TrimSpace(doc.Text())
+	}
+	return sym
+}
+
+func (x *extractor) line(pos token.Pos) int {
+	return x.fset.Position(pos).Line
+}
+
+// text returns the source between two positions.
+func (x *extractor) text(start, end token.Pos) string {
+	s, e := x.fset.Position(start).Offset, x.fset.Position(end).Offset
+	if s < 0 || e > len(x.src) || s > e {
+		return ""
+	}
+	return string(x.src[s:e])
+}
@@ -0,0 +1,284 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+func TestFunctionsMethodsAndTypes(t *testing.T) {
+	src := `
+package store
+
+import (
+	"context"
+	pg "github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MaxRows caps list queries.
+const MaxRows = 500
+
+const (
+	statusOpen   = "open"
+	statusClosed = "closed"
+)
+
+// Store wraps the connection pool.
+type Store struct {
+	*Queries
+	pool *pg.Pool
+}
+
+type Reader interface {
+	context.Context
+	Read(id int) (string, error)
+}
+
+type ID int64
+
+// New returns a store on pool.
+func New(pool *pg.Pool) *Store {
+	return &Store{pool: pool}
+}
+
+func (s *Store) Close() { s.pool.Close() }
+
+func (l List[T]) Len() int { return len(l) }
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "store.go", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "store.New", "function")
+	assertHasSymbol(t, result.Symbols, "store.Store.Close", "method")
+	assertHasSymbol(t, result.Symbols, "store.List.Len", "method")
+	assertHasSymbol(t, result.Symbols, "store.Store", "struct")
+	assertHasSymbol(t, result.Symbols, "store.Reader", "interface")
+	assertHasSymbol(t, result.Symbols, "store.ID", "type")
+	assertHasSymbol(t, result.Symbols, "store.MaxRows", "constant")
+	assertHasSymbol(t, result.Symbols, "store.statusClosed", "constant")
+
+	assertHasRef(t, result.References, "context", "imports")
+	assertHasRef(t, result.References, "github.com/jackc/pgx/v5/pgxpool", "imports")
+
+	for _, sym := range result.Symbols {
+		switch sym.QualifiedName {
+		case "store.New":
+			if sym.Signature != "func New(pool *pg.Pool) *Store" || sym.DocComment != "New returns a store on pool." {
+				t.Errorf("unexpected signature or doc for New: %q / %q", sym.Signature, sym.DocComment)
+			}
+			if sym.StartLine != 31 || sym.EndLine != 33 {
+				t.Errorf("expected New on lines 31-33, got %d-%d", sym.StartLine, sym.EndLine)
+			}
+		case "store.Store":
+			if sym.DocComment != "Store wraps the connection pool." {
+				t.Errorf("expected the type's doc comment, got %q", sym.DocComment)
+			}
+		}
+	}
+}
+
+func TestEmbeddingInherits(t *testing.T) {
+	src := `
+package store
+
+import "context"
+
+type Store struct {
+	*Queries
+	Base[int]
+	name string
+}
+
+type Reader interface {
+	context.Context
+	Closer
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "store.go", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inherits := filterRefs(result.References, "inherits")
+	want := map[string]string{
+		"store.Queries":   "store.Store",
+		"store.Base":      "store.Store",
+		"context.Context": "store.Reader",
+		"store.Closer":    "store.Reader",
+	}
+	if len(inherits) != len(want) {
+		t.Errorf("expected %d inherits refs, got %+v", len(want), inherits)
+	}
+	for _, r := range inherits {
+		if want[r.ToQualified] != r.FromSymbol {
+			t.Errorf("unexpected inherits ref %s -> %s", r.FromSymbol, r.ToQualified)
+		}
+	}
+}
+
+func TestImportPathQualifiesNames(t *testing.T) {
+	src := `
+package store
+
+import (
+	"context"
+	"github.com/acme/billing/internal/model"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Store struct {
+	model.Base
+	context.Context
+}
+
+func New() *Store { return nil }
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "internal/store/store.go", Content: []byte(src), ImportPath: "github.com/acme/billing/internal/store"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "github.com/acme/billing/internal/store", "package")
+	for _, s := range result.Symbols {
+		if s.Kind == "package" && !s.ProjectWide {
+			t.Error("expected the package symbol, shared by the package's files, to be project wide")
+		}
+	}
+	assertHasSymbol(t, result.Symbols, "github.com/acme/billing/internal/store.New", "function")
+	assertHasSymbol(t, result.Symbols, "github.com/acme/billing/internal/store.Store", "struct")
+	for _, r := range filterRefs(result.References, "imports") {
+		if r.FromSymbol != "github.com/acme/billing/internal/store" {
+			t.Errorf("expected %s to be imported by the package, got %q", r.ToName, r.FromSymbol)
+		}
+	}
+	assertHasRef(t, result.References, "github.com/acme/billing/internal/model", "imports")
+
+	inherits := filterRefs(result.References, "inherits")
+	want := map[string]bool{"github.com/acme/billing/internal/model.Base": true, "context.Context": true}
+	for _, r := range inherits {
+		if !want[r.ToQualified] || r.FromSymbol != "github.com/acme/billing/internal/store.Store" {
+			t.Errorf("unexpected inherits ref %s -> %s", r.FromSymbol, r.ToQualified)
+		}
+	}
+
+	for path, name := range map[string]string{
+		"github.com/jackc/pgx/v5":      "pgx",
+		"gopkg.in/yaml.v3":             "yaml",
+		"github.com/go-chi/chi/v5":     "chi",
+		"github.com/redis/go-redis/v9": "redis",
+		"net/http":                     "http",
+	} {
+		if got := importName(path); got != name {
+			t.Errorf("importName(%q) = %q, want %q", path, got, name)
+		}
+	}
+}
+
+func TestSyntaxErrorKeepsParsedDecls(t *testing.T) {
+	src := `
+package broken
+
+func Good() {}
+
+func Bad( {
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "broken.go", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertHasSymbol(t, result.Symbols, "broken.Good", "function")
+	if len(result.Warnings) == 0 {
+		t.Error("expected a warning for the syntax error")
+	}
+}
+
+func TestComplexity(t *testing.T) {
+	src := `
+package flow
+
+func Straight() int { return 1 }
+
+func Branchy(xs []int, ch chan int) int {
+	total := 0
+	for _, x := range xs {
+		if x > 0 && x < 10 || x == 42 {
+			total += x
+		}
+	}
+	switch {
+	case total > 100:
+		total = 100
+	case total < 0:
+		total = 0
+	default:
+	}
+	select {
+	case v := <-ch:
+		total += v
+	default:
+	}
+	return total
+}
+
+type T struct{}
+
+func (T) Lit() func() {
+	return func() {
+		if true {
+		}
+	}
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "flow.go", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Branchy: range, if, && and ||, two cases and one select case, plus one.
+	want := map[string]int{"flow.Straight": 1, "flow.Branchy": 8, "flow.T.Lit": 2, "flow.T": 0}
+	for _, sym := range result.Symbols {
+		if w, ok := want[sym.QualifiedName]; ok && sym.Complexity != w {
+			t.Errorf("%s: expected complexity %d, got %d", sym.QualifiedName, w, sym.Complexity)
+		}
+	}
+}
+
+func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
+	t.Helper()
+	for _, s := range symbols {
+		if s.QualifiedName == qname && s.Kind == kind {
+			return
+		}
+	}
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.QualifiedName + " (" + s.Kind + ")"
+	}
+	t.Errorf("missing symbol %s (%s); have: %v", qname, kind, names)
+}
+
+func filterRefs(refs []parser.RawReference, refType string) []parser.RawReference {
+	var out []parser.RawReference
+	for _, r := range refs {
+		if r.ReferenceType == refType {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func assertHasRef(t *testing.T, refs []parser.RawReference, toName, refType string) {
+	t.Helper()
+	for _, r := range refs {
+		if (r.ToName == toName || r.ToQualified == toName) && r.ReferenceType == refType {
+			return
+		}
+	}
+	t.Errorf("missing ref %s (%s)", toName, refType)
+}
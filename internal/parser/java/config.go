@@ -0,0 +1,71 @@
+package java
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// placeholderRe matches Spring property placeholders, capturing the key without its
+// default: "${mail.host:localhost}" → "mail.host".
+var placeholderRe = regexp.MustCompile(`\$\{([^}:]+)`)
+
+// propertyMethods are the Environment and System methods whose first argument is a
+// property key.
+var propertyMethods = map[string]bool{
+	"getProperty":         true,
+	"getRequiredProperty": true,
+}
+
+// extractConfigReads emits reads_config references for Spring @Value("${key}")
+// placeholders, Environment.getProperty("key") and System.getenv("KEY") /
+// System.getProperty("key").
+func extractConfigReads(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+
+	walkTree(root, func(node *sitter.Node) {
+		line := int(node.StartPoint().Row) + 1
+
+		switch node.Type() {
+		case "annotation":
+			name := node.ChildByFieldName("name")
+			if name == nil || unqualifyJava(name.Content(src)) != "Value" {
+				return
+			}
+			for _, m := range placeholderRe.FindAllStringSubmatch(extractAnnotationStringParam(node.Content(src)), -1) {
+				if key := strings.TrimSpace(m[1]); key != "" {
+					refs = append(refs, parser.ConfigRead(symbols, key, line))
+				}
+			}
+
+		case "method_invocation":
+			obj, name, args := node.ChildByFieldName("object"), node.ChildByFieldName("name"), node.ChildByFieldName("arguments")
+			if obj == nil || name == nil || args == nil {
+				return
+			}
+			method, receiver := name.Content(src), obj.Content(src)
+			isSystem := receiver == "System"
+			switch {
+			case method == "getenv" && isSystem:
+			case propertyMethods[method] && (isSystem || isEnvironmentReceiver(receiver)):
+			default:
+				return
+			}
+			if key := extractFirstStringLiteral(args, src); key != "" {
+				refs = append(refs, parser.ConfigRead(symbols, key, line))
+			}
+		}
+	})
+
+	return refs
+}
+
+// isEnvironmentReceiver reports whether expr names a Spring Environment by convention:
+// env, environment, this.env and the like.
+func isEnvironmentReceiver(expr string) bool {
+	expr = strings.ToLower(unqualifyJava(expr))
+	return expr == "env" || expr == "environment"
+}
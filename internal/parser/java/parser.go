@@ -2,6 +2,7 @@ package java
 
 import (
 	"context"
+	"regexp"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -86,6 +87,30 @@ func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
 	namedQueryRefs := extractNamedQueryRefs(root, input.Content, packageName)
 	refs = append(refs, namedQueryRefs...)
 
+	// MyBatis @Select/@Insert/@Update/@Delete mapper annotations
+	myBatisRefs := extractMyBatisRefs(root, input.Content, symbols)
+	refs = append(refs, myBatisRefs...)
+
+	// jOOQ DSL.table("x") and .from(TABLE) style references
+	jooqRefs := extractJOOQRefs(root, input.Content, symbols)
+	refs = append(refs, jooqRefs...)
+
+	// Method invocations inside method bodies
+	callRefs := extractMethodCalls(root, input.Content, symbols, packageName)
+	refs = append(refs, callRefs...)
+
+	// @Value placeholders and Environment/System property reads, each resolved to a
+	// config_key symbol in this file
+	configRefs := extractConfigReads(root, input.Content, symbols)
+	refs = append(refs, configRefs...)
+	symbols = append(symbols, parser.ConfigKeySymbols(configRefs, "java")...)
+
+	if input.DetectConditionalCalls {
+		parser.MarkConditionalCalls(refs, featureFlagRanges(root, input.Content))
+	}
+
+	parser.QualifyOverloads(symbols, refs)
+
 	return &parser.ParseResult{
 		Symbols:    symbols,
 		References: refs,
@@ -174,6 +199,10 @@ func extractClass(node *sitter.Node, src []byte, pkg string) ([]parser.Symbol, [
 		memberSyms, memberRefs := extractMembers(body, src, pkg, name)
 		symbols = append(symbols, memberSyms...)
 		refs = append(refs, memberRefs...)
+
+		endpointSyms, endpointRefs := extractControllerEndpoints(node, body, src, pkg, name)
+		symbols = append(symbols, endpointSyms...)
+		refs = append(refs, endpointRefs...)
 	}
 
 	return symbols, refs
@@ -263,13 +292,14 @@ func extractMembers(body *sitter.Node, src []byte, pkg, className string) ([]par
 			if name != "" {
 				qname := qualifyJava(pkg, className+"."+name)
 				symbols = append(symbols, parser.Symbol{
-					Name:          name,
-					QualifiedName: qname,
-					Kind:          "method",
-					Language:      "java",
-					StartLine:     int(child.StartPoint().Row) + 1,
-					EndLine:       int(child.EndPoint().Row) + 1,
-					Signature:     sig,
+					Name:                name,
+					QualifiedName:       qname,
+					Kind:                "method",
+					Language:            "java",
+					StartLine:           int(child.StartPoint().Row) + 1,
+					EndLine:             int(child.EndPoint().Row) + 1,
+					Signature:           sig,
+					NormalizedSignature: normalizeSignature(child, src),
 				})
 			}
 
@@ -381,6 +411,26 @@ func qualifyAnnotated(pkg, className, refName string) string {
 	return refName
 }
 
+// enclosing returns the nearest ancestor of node of the given type, or nil.
+func enclosing(node *sitter.Node, nodeType string) *sitter.Node {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		if n.Type() == nodeType {
+			return n
+		}
+	}
+	return nil
+}
+
+// enclosingName returns the name of the nearest ancestor of node of the given type, or "".
+func enclosingName(node *sitter.Node, src []byte, nodeType string) string {
+	if n := enclosing(node, nodeType); n != nil {
+		if name := n.ChildByFieldName("name"); name != nil {
+			return name.Content(src)
+		}
+	}
+	return ""
+}
+
 // extractAnnotationRefs walks the tree looking for Spring/JPA annotations.
 func extractAnnotationRefs(root *sitter.Node, src []byte, pkg string) []parser.RawReference {
 	var refs []parser.RawReference
@@ -416,6 +466,8 @@ func extractAnnotationRefs(root *sitter.Node, src []byte, pkg string) []parser.R
 					FromSymbol:    qualifyAnnotated(pkg, className, ""),
 					ToName:        tableName,
 					ReferenceType: "uses_table",
+					Mapping:       parser.MappingAttribute,
+					Entity:        enclosingName(node, src, "class_declaration"),
 					Line:          line,
 				})
 			}
@@ -426,27 +478,41 @@ func extractAnnotationRefs(root *sitter.Node, src []byte, pkg string) []parser.R
 			query := extractAnnotationStringParam(annoText)
 			if query != "" && looksLikeSQL(query) {
 				tableRefs := extractSQLTableRefs(query, line)
+				// A query on a Spring Data repository reads its entity's table
+				if repo := enclosing(node, "interface_declaration"); repo != nil {
+					entity := extractSpringDataEntity(repo, src)
+					for i := range tableRefs {
+						tableRefs[i].Entity = entity
+					}
+				}
 				refs = append(refs, tableRefs...)
 			}
 		}
 
-		// @RequestMapping, @GetMapping, etc.
-		if strings.Contains(annoText, "Mapping") {
-			path := extractAnnotationStringParam(annoText)
-			if path != "" {
-				refs = append(refs, parser.RawReference{
-					FromSymbol:    qualifyAnnotated(pkg, className, ""),
-					ToName:        path,
-					ReferenceType: "references",
-					Line:          line,
-				})
-			}
-		}
+		// @RequestMapping, @GetMapping and JAX-RS @Path become endpoint symbols in
+		// extractControllerEndpoints
 	})
 
 	return refs
 }
 
+// featureFlagRanges returns the line ranges of if statements and ternaries whose condition is a
+// feature-flag check, for parser.MarkConditionalCalls.
+func featureFlagRanges(root *sitter.Node, src []byte) [][2]int {
+	var ranges [][2]int
+	walkTree(root, func(n *sitter.Node) {
+		if n.Type() != "if_statement" && n.Type() != "ternary_expression" {
+			return
+		}
+		cond := n.ChildByFieldName("condition")
+		if cond == nil || !parser.IsFeatureFlagCondition(cond.Content(src)) {
+			return
+		}
+		ranges = append(ranges, [2]int{int(n.StartPoint().Row) + 1, int(n.EndPoint().Row) + 1})
+	})
+	return ranges
+}
+
 func walkTree(node *sitter.Node, fn func(*sitter.Node)) {
 	fn(node)
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -521,6 +587,7 @@ func extractSQLTableRefs(sql string, line int) []parser.RawReference {
 				refs = append(refs, parser.RawReference{
 					ToName:        tableName,
 					ReferenceType: "uses_table",
+					Mapping:       parser.MappingSQL,
 					Line:          line,
 				})
 			}
@@ -544,23 +611,6 @@ func isSQLKeyword(s string) bool {
 func extractJDBCRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
 	var refs []parser.RawReference
 
-	// Build symbol ranges for FromSymbol resolution
-	findEnclosing := func(line int) string {
-		best := ""
-		bestSpan := 1<<31 - 1
-		for _, s := range symbols {
-			if (s.Kind == "method" || s.Kind == "function" || s.Kind == "class") &&
-				line >= s.StartLine && line <= s.EndLine {
-				span := s.EndLine - s.StartLine
-				if span < bestSpan {
-					bestSpan = span
-					best = s.QualifiedName
-				}
-			}
-		}
-		return best
-	}
-
 	walkTree(root, func(node *sitter.Node) {
 		if node.Type() != "method_invocation" {
 			return
@@ -587,20 +637,373 @@ func extractJDBCRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []p
 			if sqlStr == "" {
 				return
 			}
-			from := findEnclosing(line)
-			if sqlutil.LooksLikeSQL(sqlStr) {
-				tableRefs := sqlutil.ExtractTableRefs(sqlStr, line, from, "")
-				for i := range tableRefs {
-					tableRefs[i].Confidence = 0.9
+			refs = append(refs, sqlStringRefs(sqlStr, line, enclosingSymbol(symbols, line))...)
+		}
+	})
+
+	return refs
+}
+
+// sqlStringRefs extracts table and procedure references from a SQL string literal.
+// A JDBC call escape ({call proc(?)}) has no other SQL keyword, so a string that does
+// not look like SQL is still checked for procedure calls.
+func sqlStringRefs(sqlStr string, line int, from string) []parser.RawReference {
+	var refs []parser.RawReference
+	if sqlutil.LooksLikeSQL(sqlStr) {
+		refs = sqlutil.ExtractTableRefs(sqlStr, line, from, "")
+	} else {
+		refs = sqlutil.ExtractProcCalls(sqlStr, line, from)
+	}
+	for i := range refs {
+		refs[i].Confidence = 0.9
+	}
+	return refs
+}
+
+// enclosingSymbol returns the innermost method or class containing line, for FromSymbol resolution.
+func enclosingSymbol(symbols []parser.Symbol, line int) string {
+	best := ""
+	bestSpan := 1<<31 - 1
+	for _, s := range symbols {
+		if (s.Kind == "method" || s.Kind == "function" || s.Kind == "class") &&
+			line >= s.StartLine && line <= s.EndLine {
+			span := s.EndLine - s.StartLine
+			if span < bestSpan {
+				bestSpan = span
+				best = s.QualifiedName
+			}
+		}
+	}
+	return best
+}
+
+// myBatisAnnotations are the MyBatis mapper annotations that carry inline SQL.
+var myBatisAnnotations = map[string]bool{
+	"Select": true, "Insert": true, "Update": true, "Delete": true,
+}
+
+// extractMyBatisRefs detects MyBatis @Select/@Insert/@Update/@Delete annotations on mapper
+// methods. The SQL may be split across an array or concatenation of string literals.
+func extractMyBatisRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "annotation" {
+			return
+		}
+		name := node.ChildByFieldName("name")
+		args := node.ChildByFieldName("arguments")
+		if name == nil || args == nil || !myBatisAnnotations[unqualifyJava(name.Content(src))] {
+			return
+		}
+
+		var parts []string
+		walkTree(args, func(n *sitter.Node) {
+			if n.Type() == "string_literal" {
+				if text := n.Content(src); len(text) >= 2 {
+					parts = append(parts, text[1:len(text)-1])
 				}
-				refs = append(refs, tableRefs...)
 			}
+		})
+		sqlStr := strings.Join(parts, " ")
+
+		// The annotation sits in the method's modifiers; attribute refs to the method itself.
+		line := int(node.StartPoint().Row) + 1
+		refs = append(refs, sqlStringRefs(sqlStr, line, enclosingSymbol(symbols, line))...)
+	})
+
+	return refs
+}
+
+// jooqTableMethods maps jOOQ DSL methods that take a table to the edge type they imply.
+var jooqTableMethods = map[string]string{
+	"from": "uses_table", "selectFrom": "uses_table", "join": "uses_table",
+	"innerJoin": "uses_table", "leftJoin": "uses_table", "rightJoin": "uses_table",
+	"fullJoin": "uses_table", "crossJoin": "uses_table",
+	"insertInto": "writes_to", "update": "writes_to", "deleteFrom": "writes_to",
+	"delete": "writes_to", "mergeInto": "writes_to",
+}
+
+// jooqQueryStarts are the DSLContext methods that begin a query.
+var jooqQueryStarts = map[string]bool{
+	"select": true, "selectDistinct": true, "selectFrom": true, "selectCount": true,
+	"selectOne": true, "insertInto": true, "update": true, "deleteFrom": true,
+	"delete": true, "mergeInto": true, "with": true,
+}
+
+// jooqConstant matches jOOQ's generated table constants (USERS, ORDER_ITEMS).
+var jooqConstant = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// extractJOOQRefs detects jOOQ table references: DSL.table("name") and generated table
+// constants passed to from/join/insertInto/update/deleteFrom (e.g. .from(USERS) or
+// .from(Tables.USERS)). Constants are upper-cased table names, so they are lowered.
+// Casing alone does not make a table: the constant must be a known generated table
+// (qualified by Tables or statically imported from it) or the call must be part of a
+// query started on a DSLContext, so cache.update(MAX_SIZE) is not a table write.
+func extractJOOQRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	contexts := dslContextNames(root, src)
+	tables := jooqTableImports(root, src)
+
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "method_invocation" {
+			return
 		}
+		name := node.ChildByFieldName("name")
+		args := node.ChildByFieldName("arguments")
+		if name == nil || args == nil {
+			return
+		}
+		line := int(node.StartPoint().Row) + 1
+		method := name.Content(src)
+
+		if method == "table" {
+			obj := node.ChildByFieldName("object")
+			if obj != nil && obj.Content(src) != "DSL" {
+				return
+			}
+			if tableName := extractFirstStringLiteral(args, src); tableName != "" {
+				refs = append(refs, parser.RawReference{
+					FromSymbol:    enclosingSymbol(symbols, line),
+					ToName:        tableName,
+					ReferenceType: "uses_table",
+					Confidence:    0.9,
+					Line:          line,
+				})
+			}
+			return
+		}
+
+		edgeType, ok := jooqTableMethods[method]
+		if !ok {
+			return
+		}
+		for i := 0; i < int(args.NamedChildCount()); i++ {
+			arg := args.NamedChild(i)
+			constant, known := "", false
+			switch arg.Type() {
+			case "identifier":
+				constant = arg.Content(src)
+				known = tables[constant]
+			case "field_access":
+				if field := arg.ChildByFieldName("field"); field != nil {
+					constant = field.Content(src)
+				}
+				if obj := arg.ChildByFieldName("object"); obj != nil {
+					known = unqualifyJava(obj.Content(src)) == "Tables"
+				}
+			}
+			if !jooqConstant.MatchString(constant) || (!known && !inJOOQQuery(node, src, contexts)) {
+				continue
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    enclosingSymbol(symbols, line),
+				ToName:        strings.ToLower(constant),
+				ReferenceType: edgeType,
+				Confidence:    0.8,
+				Line:          line,
+			})
+		}
+	})
+
+	return refs
+}
+
+// javaLibraryReceivers are receivers of calls into the JDK and common logging
+// libraries: static utility classes and conventional logger fields. Their methods are
+// not symbols of the project, so calls on them are not recorded.
+var javaLibraryReceivers = map[string]bool{
+	"System": true, "String": true, "Math": true, "Objects": true, "Arrays": true,
+	"Collections": true, "List": true, "Set": true, "Map": true, "Optional": true,
+	"Stream": true, "Collectors": true, "Integer": true, "Long": true, "Double": true,
+	"Boolean": true, "Character": true, "Thread": true, "UUID": true, "Files": true,
+	"Paths": true, "Path": true, "LocalDate": true, "LocalDateTime": true, "Instant": true,
+	"Duration": true, "BigDecimal": true, "CompletableFuture": true, "Executors": true,
+	"log": true, "logger": true, "LOG": true, "LOGGER": true,
+}
+
+// javaObjectMethods are the methods every object inherits from java.lang.Object.
+var javaObjectMethods = map[string]bool{
+	"equals": true, "hashCode": true, "toString": true, "getClass": true,
+	"notify": true, "notifyAll": true, "wait": true, "clone": true,
+}
+
+// extractMethodCalls emits a calls reference from each method to every method it invokes,
+// by simple name, with the argument types evident at the call site for the resolver to
+// pick among overloads. Calls on this or without a receiver are qualified with the
+// enclosing class, and calls on a variable, field or class whose type the file names are
+// qualified with that type and carry it as the receiver, for the resolver to drop calls
+// into library types. Other receivers' types are unknown, so the references carry a
+// lower confidence for the resolver to weigh. Calls on JDK utilities, loggers and string
+// literals and calls to Object's methods are skipped.
+func extractMethodCalls(root *sitter.Node, src []byte, symbols []parser.Symbol, pkg string) []parser.RawReference {
+	var methods []parser.Symbol
+	for _, s := range symbols {
+		if s.Kind == "method" {
+			methods = append(methods, s)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	types := newTypeScope(root, src, pkg, symbols)
+
+	var refs []parser.RawReference
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "method_invocation" {
+			return
+		}
+		name := node.ChildByFieldName("name")
+		if name == nil {
+			return
+		}
+		line := int(node.StartPoint().Row) + 1
+		from := enclosingSymbol(methods, line)
+		method := name.Content(src)
+		if from == "" || javaObjectMethods[method] {
+			return
+		}
+
+		ref := parser.RawReference{
+			FromSymbol:    from,
+			ToName:        method,
+			ReferenceType: "calls",
+			Confidence:    0.6,
+			Line:          line,
+		}
+		if args := node.ChildByFieldName("arguments"); args != nil {
+			ref.Args = argumentTypes(args, src)
+		}
+		obj := node.ChildByFieldName("object")
+		switch {
+		case obj == nil || obj.Type() == "this":
+			if dot := strings.LastIndexByte(from, '.'); dot >= 0 {
+				ref.ToQualified = from[:dot] + "." + method
+			}
+		case obj.Type() == "string_literal", javaLibraryReceivers[receiverRoot(obj, src)]:
+			return
+		default:
+			if receiver := types.qualify(receiverType(obj, src)); receiver != "" {
+				ref.Receiver = receiver
+				ref.ToQualified = receiver + "." + method
+			}
+		}
+		refs = append(refs, ref)
 	})
 
 	return refs
 }
 
+// receiverRoot returns the leftmost name of a call receiver: System for System.out, repo
+// for repo.findAll().stream().
+func receiverRoot(node *sitter.Node, src []byte) string {
+	for {
+		switch node.Type() {
+		case "identifier":
+			return node.Content(src)
+		case "field_access", "method_invocation":
+			obj := node.ChildByFieldName("object")
+			if obj == nil {
+				return ""
+			}
+			node = obj
+		default:
+			return ""
+		}
+	}
+}
+
+// inJOOQQuery reports whether call is a query start on a DSLContext or chained onto one
+// (ctx.select().from(...)).
+func inJOOQQuery(call *sitter.Node, src []byte, contexts map[string]bool) bool {
+	for n := call; n != nil && n.Type() == "method_invocation"; n = n.ChildByFieldName("object") {
+		name := n.ChildByFieldName("name")
+		if name != nil && jooqQueryStarts[name.Content(src)] && isDSLContext(n.ChildByFieldName("object"), src, contexts) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDSLContext reports whether recv is a DSLContext: a variable, parameter or field
+// declared as one, DSL itself, or DSL.using(...).
+func isDSLContext(recv *sitter.Node, src []byte, contexts map[string]bool) bool {
+	if recv == nil {
+		return false
+	}
+	switch recv.Type() {
+	case "identifier":
+		name := recv.Content(src)
+		return name == "DSL" || contexts[name]
+	case "field_access":
+		field := recv.ChildByFieldName("field")
+		return field != nil && contexts[field.Content(src)]
+	case "method_invocation":
+		name := recv.ChildByFieldName("name")
+		obj := recv.ChildByFieldName("object")
+		return name != nil && obj != nil && name.Content(src) == "using" && obj.Content(src) == "DSL"
+	}
+	return false
+}
+
+// dslContextNames returns the names of the file's variables, parameters and fields
+// declared as a DSLContext.
+func dslContextNames(root *sitter.Node, src []byte) map[string]bool {
+	names := make(map[string]bool)
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "formal_parameter", "local_variable_declaration", "field_declaration":
+		default:
+			return
+		}
+		typ := node.ChildByFieldName("type")
+		if typ == nil || unqualifyJava(typ.Content(src)) != "DSLContext" {
+			return
+		}
+		if node.Type() == "formal_parameter" {
+			if name := node.ChildByFieldName("name"); name != nil {
+				names[name.Content(src)] = true
+			}
+			return
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if decl := node.NamedChild(i); decl.Type() == "variable_declarator" {
+				if name := decl.ChildByFieldName("name"); name != nil {
+					names[name.Content(src)] = true
+				}
+			}
+		}
+	})
+	return names
+}
+
+// jooqTableImports returns the table constants statically imported by name from a
+// jOOQ-generated Tables class (import static com.example.db.Tables.USERS).
+func jooqTableImports(root *sitter.Node, src []byte) map[string]bool {
+	tables := make(map[string]bool)
+	for i := 0; i < int(root.ChildCount()); i++ {
+		child := root.Child(i)
+		if child.Type() != "import_declaration" || !strings.Contains(child.Content(src), "static") {
+			continue
+		}
+		path := extractImportPath(child, src)
+		dot := strings.LastIndexByte(path, '.')
+		if dot < 0 || unqualifyJava(path[:dot]) != "Tables" {
+			continue
+		}
+		tables[path[dot+1:]] = true
+	}
+	return tables
+}
+
+// unqualifyJava returns the simple name of a possibly qualified Java name.
+func unqualifyJava(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
 // extractNamedQueryRefs detects @NamedQuery and @NamedNativeQuery annotations.
 func extractNamedQueryRefs(root *sitter.Node, src []byte, pkg string) []parser.RawReference {
 	var refs []parser.RawReference
@@ -619,8 +1022,11 @@ func extractNamedQueryRefs(root *sitter.Node, src []byte, pkg string) []parser.R
 		query := extractAnnotationParam(annoText, "query")
 		if query != "" && looksLikeSQL(query) {
 			tableRefs := extractSQLTableRefs(query, line)
+			// Named queries are declared on the entity they query
+			entity := enclosingName(node, src, "class_declaration")
 			for i := range tableRefs {
 				tableRefs[i].Confidence = 0.9
+				tableRefs[i].Entity = entity
 			}
 			refs = append(refs, tableRefs...)
 		}
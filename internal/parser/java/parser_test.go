@@ -1,6 +1,7 @@
 package java
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/maraichr/lattice/internal/parser"
@@ -48,13 +49,18 @@ public class User {
 
 	tableRefs := filterRefs(result.References, "uses_table")
 	assertRefTarget(t, tableRefs, "users")
+	for _, ref := range tableRefs {
+		if ref.Entity != "User" {
+			t.Errorf("%s mapped for entity %q, want User", ref.ToName, ref.Entity)
+		}
+	}
 }
 
 func TestQueryAnnotation(t *testing.T) {
 	src := `
 package com.example;
 
-public interface UserRepository {
+public interface UserRepository extends JpaRepository<User, Long> {
     @Query("SELECT u FROM Users u WHERE u.active = true")
     List<User> findActiveUsers();
 }
@@ -67,6 +73,11 @@ public interface UserRepository {
 
 	tableRefs := filterRefs(result.References, "uses_table")
 	assertRefTarget(t, tableRefs, "Users")
+	for _, ref := range tableRefs {
+		if ref.ToName == "Users" && ref.Entity != "User" {
+			t.Errorf("query on the repository mapped for entity %q, want User", ref.Entity)
+		}
+	}
 }
 
 func TestJDBCPrepareStatement(t *testing.T) {
@@ -110,6 +121,32 @@ public class UserDao {
 	assertRefTarget(t, callRefs, "dbo.GetUser")
 }
 
+func TestJDBCCallEscape(t *testing.T) {
+	src := `
+package com.example;
+
+public class OrderDao {
+    public void archive(int days) {
+        CallableStatement cs = conn.prepareCall("{call usp_ArchiveOrders(?)}");
+        cs.execute();
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "OrderDao.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callRefs := filterRefs(result.References, "calls")
+	assertRefTarget(t, callRefs, "usp_ArchiveOrders")
+	for _, r := range callRefs {
+		if r.ToName == "usp_ArchiveOrders" && r.FromSymbol != "com.example.OrderDao.archive" {
+			t.Errorf("expected the call attributed to OrderDao.archive, got %q", r.FromSymbol)
+		}
+	}
+}
+
 func TestSpringDataRepository(t *testing.T) {
 	src := `
 package com.example;
@@ -161,6 +198,247 @@ public class User {
 	assertRefTarget(t, tableRefs, "Users")
 }
 
+func TestMyBatisSelectAnnotation(t *testing.T) {
+	src := `
+package com.example;
+
+public class OrderMapper {
+    @Select({"SELECT o.id, c.name FROM orders o",
+             "JOIN customers c ON c.id = o.customer_id WHERE o.id = #{id}"})
+    public Order findById(long id) { return null; }
+
+    @Insert("INSERT INTO order_audit (order_id) VALUES (#{id})")
+    public void audit(long id) {}
+
+    @SelectProvider(type = OrderSql.class, method = "build")
+    public Order dynamic(long id) { return null; }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "OrderMapper.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasRef(t, result.References, "orders", "uses_table")
+	assertHasRef(t, result.References, "customers", "uses_table")
+	assertHasRef(t, result.References, "order_audit", "writes_to")
+	for _, r := range result.References {
+		if r.ToName == "orders" && r.FromSymbol != "com.example.OrderMapper.findById" {
+			t.Errorf("expected orders ref from findById, got %q", r.FromSymbol)
+		}
+	}
+}
+
+func TestJOOQQuery(t *testing.T) {
+	src := `
+package com.example;
+
+import static com.example.db.Tables.*;
+
+public class OrderRepository {
+    public List<Order> recent(DSLContext ctx) {
+        ctx.insertInto(ORDER_EVENTS).values(1).execute();
+        return ctx.select().from(ORDERS)
+            .join(Tables.CUSTOMERS).on(ORDERS.CUSTOMER_ID.eq(CUSTOMERS.ID))
+            .fetchInto(Order.class);
+    }
+
+    public int legacy(DSLContext ctx) {
+        return ctx.fetchCount(DSL.table("legacy_orders"));
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "OrderRepository.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasRef(t, result.References, "orders", "uses_table")
+	assertHasRef(t, result.References, "customers", "uses_table")
+	assertHasRef(t, result.References, "order_events", "writes_to")
+	assertHasRef(t, result.References, "legacy_orders", "uses_table")
+	for _, r := range result.References {
+		if r.ToName == "orders" && r.FromSymbol != "com.example.OrderRepository.recent" {
+			t.Errorf("expected orders ref from recent, got %q", r.FromSymbol)
+		}
+	}
+}
+
+func TestMethodCalls(t *testing.T) {
+	src := `
+package com.example;
+
+import java.util.List;
+
+public class OrderService {
+    private final OrderRepository repo;
+    private static final Logger log = LoggerFactory.getLogger(OrderService.class);
+
+    public Order place(Order order, List<Order> history) {
+        validate(order);
+        this.audit(order);
+        log.info("placing {}", order);
+        System.out.println(order.toString());
+        history.add(order);
+        Pricing.quote(order);
+        return repo.save(order);
+    }
+
+    private void validate(Order order) {}
+    private void audit(Order order) {}
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "OrderService.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls")
+	want := map[string]string{
+		"validate": "com.example.OrderService.validate",
+		"audit":    "com.example.OrderService.audit",
+		"add":      "java.util.List.add",
+		"quote":    "com.example.Pricing.quote",
+		"save":     "com.example.OrderRepository.save",
+	}
+	receivers := map[string]string{"add": "java.util.List", "quote": "com.example.Pricing", "save": "com.example.OrderRepository"}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls refs, got %+v", len(want), calls)
+	}
+	for _, r := range calls {
+		qualified, ok := want[r.ToName]
+		if !ok || r.ToQualified != qualified || r.Receiver != receivers[r.ToName] {
+			t.Errorf("unexpected calls ref %s -> %s (%s on %q)", r.FromSymbol, r.ToName, r.ToQualified, r.Receiver)
+		}
+		if r.FromSymbol != "com.example.OrderService.place" || r.Confidence != 0.6 {
+			t.Errorf("expected a 0.6 call from place, got %+v", r)
+		}
+	}
+}
+
+func TestNormalizedSignatureAndCallArgs(t *testing.T) {
+	src := `
+package com.example;
+
+public class Mailer {
+    public void send(final String to, @NotNull List<Map<String, Integer>> batches, int codes[], Object... extra) {}
+
+    public void notify(Order order) {
+        send("a@example.com", new ArrayList<>(), null, 42L);
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "Mailer.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range result.Symbols {
+		if s.QualifiedName == "com.example.Mailer.send" && s.NormalizedSignature != "(String,List<Map<String,Integer>>,int[],Object...)" {
+			t.Errorf("unexpected normalized signature %q", s.NormalizedSignature)
+		}
+	}
+	calls := filterRefs(result.References, "calls")
+	if len(calls) != 1 || !slices.Equal(calls[0].Args, []string{"String", "ArrayList<>", "", "long"}) {
+		t.Errorf("expected the argument types of the send call, got %+v", calls)
+	}
+}
+
+func TestConfigReads(t *testing.T) {
+	src := `
+package com.example.mail;
+
+public class MailSender {
+    @Value("${mail.host:localhost}")
+    private String host;
+
+    @Value("${mail.from}")
+    private String from;
+
+    public MailSender(@Value("${mail.port}") int port) {
+    }
+
+    public void send(Environment env) {
+        String user = env.getProperty("mail.user");
+        String key = System.getenv("MAIL_API_KEY");
+        String other = props.getProperty("not.config");
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "MailSender.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"mail.host":    "com.example.mail.MailSender.host",
+		"mail.from":    "com.example.mail.MailSender.from",
+		"mail.port":    "com.example.mail.MailSender.MailSender",
+		"mail.user":    "com.example.mail.MailSender.send",
+		"MAIL_API_KEY": "com.example.mail.MailSender.send",
+	}
+	reads := filterRefs(result.References, "reads_config")
+	if len(reads) != len(want) {
+		t.Errorf("expected %d reads_config refs, got %+v", len(want), reads)
+	}
+	for _, r := range reads {
+		from, ok := want[r.ToName]
+		if !ok {
+			t.Errorf("unexpected reads_config key %q", r.ToName)
+			continue
+		}
+		if r.FromSymbol != from {
+			t.Errorf("%s: expected read from %s, got %q", r.ToName, from, r.FromSymbol)
+		}
+	}
+	for key := range want {
+		assertHasSymbol(t, result.Symbols, parser.ConfigKeyPrefix+key, "config_key")
+	}
+	for _, s := range result.Symbols {
+		if s.Kind == "config_key" && !s.ProjectWide {
+			t.Errorf("expected %s, named by every file reading it, to be project wide", s.QualifiedName)
+		}
+	}
+}
+
+func TestJOOQConstantsNeedQueryContext(t *testing.T) {
+	src := `
+package com.example;
+
+import static com.example.db.Tables.INVOICES;
+
+public class Settings {
+    private final DSLContext dsl;
+
+    public void apply(Cache cache, Flags flags) {
+        cache.update(MAX_SIZE);
+        flags.from(DEFAULT_FLAGS).join(FALLBACK);
+        registry.delete(INVOICES);
+        this.dsl.deleteFrom(AUDIT_LOG).execute();
+    }
+}
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "Settings.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range result.References {
+		switch r.ToName {
+		case "max_size", "default_flags", "fallback":
+			t.Errorf("unexpected table ref %s (%s) outside a jOOQ query", r.ToName, r.ReferenceType)
+		}
+	}
+	assertHasRef(t, result.References, "invoices", "writes_to")
+	assertHasRef(t, result.References, "audit_log", "writes_to")
+}
+
 // --- helpers ---
 
 func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
@@ -177,6 +455,16 @@ func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string)
 	t.Errorf("missing symbol %s (%s); have: %v", qname, kind, names)
 }
 
+func countKind(symbols []parser.Symbol, kind string) int {
+	n := 0
+	for _, s := range symbols {
+		if s.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
 func filterRefs(refs []parser.RawReference, refType string) []parser.RawReference {
 	var out []parser.RawReference
 	for _, r := range refs {
@@ -210,3 +498,73 @@ func assertRefTarget(t *testing.T, refs []parser.RawReference, target string) {
 	}
 	t.Errorf("missing ref target %s; have: %v", target, names)
 }
+
+func TestSpringControllerEndpoints(t *testing.T) {
+	src := `
+package com.example.web;
+
+@RestController
+@RequestMapping("/api/users")
+public class UserController {
+    @GetMapping("/{id:\\d+}")
+    public User get(@PathVariable Long id) { return null; }
+
+    @PostMapping
+    public User create(@RequestBody User user) { return user; }
+
+    @RequestMapping(value = {"/search", "/find"}, method = RequestMethod.GET)
+    public List<User> search() { return null; }
+
+    @RequestMapping("/ping")
+    public String ping() { return "pong"; }
+
+    private void helper() {}
+}
+`
+	result, err := New().Parse(parser.FileInput{Path: "UserController.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, route := range []string{"GET /api/users/{id}", "POST /api/users", "GET /api/users/search", "GET /api/users/find", "ALL /api/users/ping"} {
+		assertHasSymbol(t, result.Symbols, parser.APIRoutePrefix+route, "endpoint")
+	}
+	if n := countKind(result.Symbols, "endpoint"); n != 5 {
+		t.Errorf("expected 5 endpoints, got %d", n)
+	}
+	assertHasRef(t, result.References, "com.example.web.UserController.get", "calls")
+	for _, ref := range result.References {
+		if ref.ReferenceType == "references" {
+			t.Errorf("expected no generic mapping references, got %+v", ref)
+		}
+	}
+}
+
+func TestJAXRSResourceEndpoints(t *testing.T) {
+	src := `
+package com.example.rest;
+
+@Path("/orders")
+public class OrderResource {
+    @GET
+    @Path("{id: [0-9]+}")
+    public Order get(@PathParam("id") long id) { return null; }
+
+    @POST
+    public Response create(Order order) { return null; }
+
+    @Path("{id}/items")
+    public ItemResource items() { return null; }
+}
+`
+	result, err := New().Parse(parser.FileInput{Path: "OrderResource.java", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "route:GET /orders/{id}", "endpoint")
+	assertHasSymbol(t, result.Symbols, "route:POST /orders", "endpoint")
+	if n := countKind(result.Symbols, "endpoint"); n != 2 {
+		t.Errorf("expected the subresource locator to route nothing, got %d endpoints", n)
+	}
+}
@@ -0,0 +1,178 @@
+package java
+
+import (
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// javaLangTypes are the java.lang types a file uses without importing them.
+var javaLangTypes = map[string]bool{
+	"Object": true, "String": true, "StringBuilder": true, "Integer": true, "Long": true,
+	"Short": true, "Byte": true, "Double": true, "Float": true, "Boolean": true,
+	"Character": true, "Number": true, "Math": true, "System": true, "Thread": true,
+	"Class": true, "Enum": true, "Iterable": true, "Runnable": true, "Exception": true,
+	"RuntimeException": true, "Throwable": true, "Error": true,
+}
+
+// typeScope qualifies the simple type names a file uses: types it declares, types it
+// imports by name, java.lang types, and otherwise types of its own package. Without a
+// wildcard import a name matching none of these must be in the file's package; with one
+// it could come from either, so it is left unqualified.
+type typeScope struct {
+	pkg      string
+	declared map[string]string // simple name → qualified name, for the file's own types
+	imports  map[string]string // simple name → qualified name
+	wildcard bool
+}
+
+func newTypeScope(root *sitter.Node, src []byte, pkg string, symbols []parser.Symbol) typeScope {
+	s := typeScope{pkg: pkg, declared: make(map[string]string), imports: make(map[string]string)}
+	for _, sym := range symbols {
+		switch sym.Kind {
+		case "class", "interface", "enum":
+			s.declared[sym.Name] = sym.QualifiedName
+		}
+	}
+	for i := 0; i < int(root.ChildCount()); i++ {
+		child := root.Child(i)
+		if child.Type() != "import_declaration" {
+			continue
+		}
+		path := extractImportPath(child, src)
+		switch {
+		case path == "" || findChild(child, "static") != nil:
+		case findChild(child, "asterisk") != nil:
+			s.wildcard = true
+		default:
+			s.imports[unqualifyJava(path)] = path
+		}
+	}
+	return s
+}
+
+// qualify returns the qualified name of a type as written in the file, or "" when it
+// cannot be told.
+func (s typeScope) qualify(name string) string {
+	if i := strings.IndexByte(name, '<'); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "" || name == "var" || strings.HasSuffix(name, "]") || !unicode.IsUpper(rune(unqualifyJava(name)[0])):
+		// Inferred, array and primitive types have no project methods to call
+		return ""
+	case strings.Contains(name, "."):
+		return name
+	case s.declared[name] != "":
+		return s.declared[name]
+	case s.imports[name] != "":
+		return s.imports[name]
+	case javaLangTypes[name]:
+		return "java.lang." + name
+	case s.wildcard:
+		return ""
+	}
+	return qualifyJava(s.pkg, name)
+}
+
+// receiverType returns the type a call's receiver is declared with, as written: the type
+// of a local variable, parameter or field, or the class itself for a static call. It
+// returns "" for receivers whose type is not evident, such as the result of another call.
+func receiverType(obj *sitter.Node, src []byte) string {
+	switch obj.Type() {
+	case "identifier":
+		name := obj.Content(src)
+		if t := declaredType(obj, name, src); t != "" {
+			return t
+		}
+		// An undeclared capitalized name is a class: Validator.check(order)
+		if unicode.IsUpper(rune(name[0])) {
+			return name
+		}
+	case "field_access":
+		if o := obj.ChildByFieldName("object"); o != nil && o.Type() == "this" {
+			if field := obj.ChildByFieldName("field"); field != nil {
+				return fieldType(obj, field.Content(src), src)
+			}
+		}
+	}
+	return ""
+}
+
+// declaredType returns the type of the variable name visible at node: a parameter or
+// local variable of the enclosing method, or else a field of an enclosing class.
+func declaredType(node *sitter.Node, name string, src []byte) string {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		switch n.Type() {
+		case "method_declaration", "constructor_declaration", "lambda_expression":
+			if t := localType(n, name, src); t != "" {
+				return t
+			}
+		case "class_body":
+			return fieldType(node, name, src)
+		}
+	}
+	return ""
+}
+
+// localType returns the type a parameter or local variable named name is declared with
+// anywhere in method, or "".
+func localType(method *sitter.Node, name string, src []byte) string {
+	found := ""
+	walkTree(method, func(n *sitter.Node) {
+		if found != "" {
+			return
+		}
+		switch n.Type() {
+		case "formal_parameter", "catch_formal_parameter", "enhanced_for_statement":
+			if id := n.ChildByFieldName("name"); id != nil && id.Content(src) == name {
+				if t := n.ChildByFieldName("type"); t != nil {
+					found = t.Content(src)
+				}
+			}
+		case "local_variable_declaration":
+			if declares(n, name, src) {
+				found = n.ChildByFieldName("type").Content(src)
+			}
+		}
+	})
+	return found
+}
+
+// fieldType returns the type of the field name of the innermost class enclosing node that
+// declares it, or "".
+func fieldType(node *sitter.Node, name string, src []byte) string {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		if n.Type() != "class_body" {
+			continue
+		}
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			member := n.NamedChild(i)
+			if member.Type() == "field_declaration" && declares(member, name, src) {
+				return member.ChildByFieldName("type").Content(src)
+			}
+		}
+	}
+	return ""
+}
+
+// declares reports whether a field or local variable declaration declares name.
+func declares(decl *sitter.Node, name string, src []byte) bool {
+	if decl.ChildByFieldName("type") == nil {
+		return false
+	}
+	for i := 0; i < int(decl.NamedChildCount()); i++ {
+		d := decl.NamedChild(i)
+		if d.Type() != "variable_declarator" {
+			continue
+		}
+		if id := d.ChildByFieldName("name"); id != nil && id.Content(src) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package java
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// springMappings maps Spring MVC request-mapping annotations to the HTTP method they
+// route; @RequestMapping takes its methods from its method attribute.
+var springMappings = map[string]string{
+	"GetMapping": "GET", "PostMapping": "POST", "PutMapping": "PUT", "PatchMapping": "PATCH",
+	"DeleteMapping": "DELETE", "RequestMapping": "",
+}
+
+// jaxRSMethods are the JAX-RS annotations designating a resource method's HTTP method.
+var jaxRSMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// pathVariable matches a Spring or JAX-RS path variable with its regex constraint or
+// catch-all star: {id:\d+}, {id: [0-9]+}, {*path}.
+var pathVariable = regexp.MustCompile(`\{\s*\*?([A-Za-z_][A-Za-z0-9_]*)[^}]*\}`)
+
+// annotation is a Java annotation's short name and the values of its elements, a single
+// unnamed element being "value". String, enum constant and array values are kept.
+type annotation struct {
+	name   string
+	values map[string][]string
+}
+
+// annotations returns the annotations on a class or method declaration.
+func annotations(node *sitter.Node, src []byte) []annotation {
+	mods := findChild(node, "modifiers")
+	if mods == nil {
+		return nil
+	}
+	var annos []annotation
+	for i := 0; i < int(mods.NamedChildCount()); i++ {
+		child := mods.NamedChild(i)
+		if child.Type() != "annotation" && child.Type() != "marker_annotation" {
+			continue
+		}
+		name := child.ChildByFieldName("name")
+		if name == nil {
+			continue
+		}
+		anno := annotation{name: unqualifyJava(name.Content(src)), values: make(map[string][]string)}
+		if args := child.ChildByFieldName("arguments"); args != nil {
+			for j := 0; j < int(args.NamedChildCount()); j++ {
+				arg := args.NamedChild(j)
+				if arg.Type() != "element_value_pair" {
+					anno.values["value"] = append(anno.values["value"], elementValues(arg, src)...)
+					continue
+				}
+				key, value := arg.ChildByFieldName("key"), arg.ChildByFieldName("value")
+				if key != nil && value != nil {
+					anno.values[key.Content(src)] = append(anno.values[key.Content(src)], elementValues(value, src)...)
+				}
+			}
+		}
+		annos = append(annos, anno)
+	}
+	return annos
+}
+
+// elementValues returns the strings and enum constants of an annotation element value,
+// RequestMethod.GET as GET. Constants concatenated into a path are not resolved.
+func elementValues(node *sitter.Node, src []byte) []string {
+	switch node.Type() {
+	case "string_literal":
+		return []string{strings.Trim(node.Content(src), `"`)}
+	case "field_access", "identifier":
+		return []string{unqualifyJava(node.Content(src))}
+	case "element_value_array_initializer":
+		var values []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			values = append(values, elementValues(node.NamedChild(i), src)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// paths returns the paths of a mapping annotation, from its value or path element, or
+// a single "" when it has none.
+func (a annotation) paths() []string {
+	paths := slices.Concat(a.values["value"], a.values["path"])
+	if len(paths) == 0 {
+		return []string{""}
+	}
+	return paths
+}
+
+// mappingRoute joins a controller's base path and a method's path the way Spring and
+// JAX-RS both do, and normalizes the result: path variables are reduced to their name,
+// so "/api/users" and "{id:\\d+}" become /api/users/{id}.
+func mappingRoute(base, path string) string {
+	return parser.NormalizeRoute(pathVariable.ReplaceAllString(joinMapping(base, path), "{$1}"))
+}
+
+// joinMapping joins a base path and a method's path with their path variables as
+// declared: /api/users/{id:\\d+}.
+func joinMapping(base, path string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// controllerBases returns a class's base paths: those of its @RequestMapping, or of
+// its JAX-RS @Path, or a single "" when it has neither.
+func controllerBases(annos []annotation) []string {
+	for _, a := range annos {
+		if a.name == "RequestMapping" || a.name == "Path" {
+			return a.paths()
+		}
+	}
+	return []string{""}
+}
+
+// methodRoutes returns the HTTP methods and paths a handler method is mapped to by
+// Spring mapping annotations or JAX-RS method annotations and @Path. A @RequestMapping
+// without a method element takes any method (ALL). A JAX-RS @Path without an HTTP
+// method annotation is a subresource locator and routes nothing itself.
+func methodRoutes(annos []annotation) (verbs, paths []string) {
+	jaxRSPaths := []string{""}
+	for _, a := range annos {
+		verb, isSpring := springMappings[a.name]
+		switch {
+		case isSpring:
+			if verb != "" {
+				verbs = append(verbs, verb)
+			} else if methods := a.values["method"]; len(methods) > 0 {
+				verbs = append(verbs, methods...)
+			} else {
+				verbs = append(verbs, "ALL")
+			}
+			paths = append(paths, a.paths()...)
+		case jaxRSMethods[a.name]:
+			verbs = append(verbs, a.name)
+		case a.name == "Path":
+			jaxRSPaths = a.paths()
+		}
+	}
+	if len(verbs) > 0 && len(paths) == 0 {
+		paths = jaxRSPaths
+	}
+	return verbs, paths
+}
+
+// extractControllerEndpoints emits an endpoint symbol for each handler method of a
+// Spring MVC controller or JAX-RS resource, qualified with its method and route,
+// "route:GET /api/users/{id}" for @RequestMapping("/api/users") and
+// @GetMapping("/{id}"), with a calls reference to the handler. Frontend calls_api
+// references are matched to it by the resolver's api_route strategy. Interfaces are
+// not considered, as mapped interfaces are as often declarative HTTP clients.
+func extractControllerEndpoints(node, body *sitter.Node, src []byte, pkg, className string) ([]parser.Symbol, []parser.RawReference) {
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	bases := controllerBases(annotations(node, src))
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		if child.Type() != "method_declaration" {
+			continue
+		}
+		verbs, paths := methodRoutes(annotations(child, src))
+		if len(verbs) == 0 {
+			continue
+		}
+		name, _ := extractMethodDecl(child, src)
+		if name == "" {
+			continue
+		}
+
+		methodQName := qualifyJava(pkg, className+"."+name)
+		for _, verb := range verbs {
+			for _, base := range bases {
+				for _, path := range paths {
+					route := strings.ToUpper(verb) + " " + mappingRoute(base, path)
+					endpointQName := parser.APIRoutePrefix + route
+					symbols = append(symbols, parser.Symbol{
+						Name:          route,
+						QualifiedName: endpointQName,
+						Kind:          "endpoint",
+						Language:      "java",
+						StartLine:     int(child.StartPoint().Row) + 1,
+						EndLine:       int(child.EndPoint().Row) + 1,
+						Signature:     strings.ToUpper(verb) + " " + parser.DeclaredRoute(joinMapping(base, path)),
+					})
+					refs = append(refs, parser.RawReference{
+						FromSymbol:    endpointQName,
+						ToName:        name,
+						ToQualified:   methodQName,
+						ReferenceType: "calls",
+						Line:          int(child.StartPoint().Row) + 1,
+					})
+				}
+			}
+		}
+	}
+
+	return symbols, refs
+}
@@ -0,0 +1,81 @@
+package java
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// normalizeSignature returns the parameter types of a method or constructor declaration
+// in parser.Symbol's NormalizedSignature form: "(int,List<String>,String...)" for
+// (final int x, @NotNull List<String> names, String... rest). Array dimensions written
+// after a parameter's name move to its type.
+func normalizeSignature(node *sitter.Node, src []byte) string {
+	params := node.ChildByFieldName("parameters")
+	if params == nil {
+		return ""
+	}
+	var types []string
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		switch param.Type() {
+		case "formal_parameter":
+			typ := param.ChildByFieldName("type")
+			if typ == nil {
+				continue
+			}
+			t := compactType(typ.Content(src))
+			if dims := param.ChildByFieldName("dimensions"); dims != nil {
+				t += compactType(dims.Content(src))
+			}
+			types = append(types, t)
+		case "spread_parameter":
+			for j := 0; j < int(param.NamedChildCount()); j++ {
+				if child := param.NamedChild(j); child.Type() != "modifiers" && child.Type() != "variable_declarator" {
+					types = append(types, compactType(child.Content(src))+"...")
+					break
+				}
+			}
+		}
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
+
+// compactType removes the whitespace from a type as written.
+func compactType(t string) string {
+	return strings.Join(strings.Fields(t), "")
+}
+
+// literalTypes are the types of Java literal arguments.
+var literalTypes = map[string]string{
+	"decimal_integer_literal": "int", "hex_integer_literal": "int", "octal_integer_literal": "int",
+	"binary_integer_literal": "int", "decimal_floating_point_literal": "double",
+	"hex_floating_point_literal": "double", "string_literal": "String", "text_block": "String",
+	"character_literal": "char", "true": "boolean", "false": "boolean",
+}
+
+// argumentTypes returns the static type of each argument of a call where the call
+// site shows it: literals, object creations and casts. Others are "".
+func argumentTypes(args *sitter.Node, src []byte) []string {
+	types := make([]string, 0, args.NamedChildCount())
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		t := literalTypes[arg.Type()]
+		switch arg.Type() {
+		case "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal":
+			if lit := arg.Content(src); strings.HasSuffix(lit, "L") || strings.HasSuffix(lit, "l") {
+				t = "long"
+			}
+		case "decimal_floating_point_literal":
+			if lit := arg.Content(src); strings.HasSuffix(lit, "f") || strings.HasSuffix(lit, "F") {
+				t = "float"
+			}
+		case "object_creation_expression", "cast_expression":
+			if typ := arg.ChildByFieldName("type"); typ != nil {
+				t = compactType(typ.Content(src))
+			}
+		}
+		types = append(types, t)
+	}
+	return types
+}
@@ -0,0 +1,63 @@
+package javascript
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// envObjects are the expressions environment variables are read from.
+var envObjects = map[string]bool{
+	"process.env":     true,
+	"import.meta.env": true,
+}
+
+// extractConfigReads emits reads_config references for environment variables read as
+// process.env.API_URL, process.env["API_URL"], import.meta.env.VITE_API_URL or by
+// destructuring const { API_URL } = process.env.
+func (p *Parser) extractConfigReads(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	read := func(key string, node *sitter.Node) {
+		if key != "" {
+			refs = append(refs, parser.ConfigRead(symbols, key, int(node.StartPoint().Row)+1))
+		}
+	}
+
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "member_expression":
+			obj, prop := node.ChildByFieldName("object"), node.ChildByFieldName("property")
+			if obj != nil && prop != nil && envObjects[obj.Content(src)] {
+				read(prop.Content(src), node)
+			}
+
+		case "subscript_expression":
+			obj, index := node.ChildByFieldName("object"), node.ChildByFieldName("index")
+			if obj != nil && index != nil && envObjects[obj.Content(src)] && index.Type() == "string" {
+				read(extractStringContent(index, src), node)
+			}
+
+		case "variable_declarator":
+			name, value := node.ChildByFieldName("name"), node.ChildByFieldName("value")
+			if name == nil || value == nil || name.Type() != "object_pattern" || !envObjects[value.Content(src)] {
+				return
+			}
+			walkChildren(name, func(child *sitter.Node) {
+				switch child.Type() {
+				case "shorthand_property_identifier_pattern":
+					read(child.Content(src), child)
+				case "pair_pattern", "object_assignment_pattern":
+					key := child.ChildByFieldName("key")
+					if key == nil {
+						key = child.ChildByFieldName("left")
+					}
+					if key != nil {
+						read(key.Content(src), child)
+					}
+				}
+			})
+		}
+	})
+
+	return refs
+}
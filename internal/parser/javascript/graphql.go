@@ -0,0 +1,254 @@
+package javascript
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// graphqlTags are the template tags that mark a GraphQL document.
+var graphqlTags = map[string]bool{
+	"gql": true, "graphql": true,
+}
+
+// graphqlRootTypes maps an operation keyword to the schema type its root fields are on.
+var graphqlRootTypes = map[string]string{
+	"query": "Query", "mutation": "Mutation", "subscription": "Subscription",
+}
+
+// graphqlOperation is one operation in a GraphQL document.
+type graphqlOperation struct {
+	kind      string // query, mutation or subscription
+	name      string // operation name, "" if anonymous
+	rootField string // first field of the selection set
+}
+
+// extractGraphQLCalls emits calls_graphql references for operations written in gql`...`
+// templates, whether passed inline (useQuery(gql`...`), client.query({ query: gql`...` }))
+// or declared as a constant and used later. ToName is the operation name, or the root
+// field of an anonymous operation; ToQualified is "graphql:Query.orders", the root field
+// on its schema type, for matching backend resolvers. Documents declared outside any
+// function are attributed to each function that uses the constant.
+func (p *Parser) extractGraphQLCalls(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	findEnclosing := enclosingSymbolFinder(symbols)
+	documents := make(map[string][]graphqlOperation) // module-level constant -> operations
+
+	emit := func(ops []graphqlOperation, from string, line int) {
+		for _, op := range ops {
+			name := op.name
+			if name == "" {
+				name = op.rootField
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    from,
+				ToName:        name,
+				ToQualified:   parser.GraphQLFieldPrefix + graphqlRootTypes[op.kind] + "." + op.rootField,
+				ReferenceType: "calls_graphql",
+				Confidence:    p.confidence[PatternGraphQL],
+				Line:          line,
+			})
+		}
+	}
+
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "call_expression" {
+			return
+		}
+		fn, tmpl := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+		if fn == nil || tmpl == nil || tmpl.Type() != "template_string" || !graphqlTags[fn.Content(src)] {
+			return
+		}
+		ops := parseGraphQLOperations(templateText(tmpl, src))
+		if len(ops) == 0 {
+			return
+		}
+		line := int(node.StartPoint().Row) + 1
+		if from := findEnclosing(line); from != "" {
+			emit(ops, from, line)
+			return
+		}
+		if decl := node.Parent(); decl != nil && decl.Type() == "variable_declarator" {
+			if name := decl.ChildByFieldName("name"); name != nil {
+				documents[name.Content(src)] = ops
+			}
+		}
+	})
+	if len(documents) == 0 {
+		return refs
+	}
+
+	// Uses of module-level documents, once per function
+	seen := make(map[string]bool)
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "identifier" {
+			return
+		}
+		ops, ok := documents[node.Content(src)]
+		if !ok {
+			return
+		}
+		line := int(node.StartPoint().Row) + 1
+		from := findEnclosing(line)
+		if from == "" || seen[from+"\x00"+node.Content(src)] {
+			return
+		}
+		seen[from+"\x00"+node.Content(src)] = true
+		emit(ops, from, line)
+	})
+
+	return refs
+}
+
+// templateText returns the body of a template string with ${...} substitutions blanked
+// out, so interpolated fragments do not break the document.
+func templateText(tmpl *sitter.Node, src []byte) string {
+	start, end := tmpl.StartByte()+1, tmpl.EndByte()-1
+	if end < start {
+		return ""
+	}
+	text := []byte(string(src[start:end]))
+	walkChildren(tmpl, func(sub *sitter.Node) {
+		if sub.Type() != "template_substitution" {
+			return
+		}
+		for i := sub.StartByte(); i < sub.EndByte(); i++ {
+			text[i-start] = ' '
+		}
+	})
+	return string(text)
+}
+
+// parseGraphQLOperations returns the operations of a GraphQL document, skipping
+// fragment definitions. A bare selection set is an anonymous query.
+func parseGraphQLOperations(doc string) []graphqlOperation {
+	toks := graphqlTokens(doc)
+	var ops []graphqlOperation
+	for i := 0; i < len(toks); {
+		op := graphqlOperation{kind: "query"}
+		switch t := toks[i]; {
+		case t == "{":
+		case graphqlRootTypes[t] != "":
+			op.kind = t
+			i++
+			if i < len(toks) && isGraphQLName(toks[i]) {
+				op.name = toks[i]
+				i++
+			}
+			// Variable definitions and directives come before the selection set.
+			for i < len(toks) && toks[i] != "{" {
+				if toks[i] == "(" {
+					i = skipGraphQLGroup(toks, i, "(", ")")
+					continue
+				}
+				i++
+			}
+		default:
+			// fragment definitions and anything unrecognized: skip to the next definition
+			for i < len(toks) && toks[i] != "{" {
+				i++
+			}
+			i = skipGraphQLGroup(toks, i, "{", "}")
+			continue
+		}
+		if i >= len(toks) {
+			break
+		}
+		op.rootField = graphqlRootField(toks, i)
+		i = skipGraphQLGroup(toks, i, "{", "}")
+		if op.rootField != "" {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// graphqlRootField returns the first field selected by the selection set opening at
+// toks[open], looking past aliases (alias: field) and fragment spreads.
+func graphqlRootField(toks []string, open int) string {
+	for i := open + 1; i < len(toks) && toks[i] != "}"; i++ {
+		switch {
+		case toks[i] == "...":
+			i++ // the fragment name, or "on" of an inline fragment
+			if i+1 < len(toks) && toks[i] == "on" {
+				i++
+			}
+		case isGraphQLName(toks[i]):
+			if i+2 < len(toks) && toks[i+1] == ":" && isGraphQLName(toks[i+2]) {
+				return toks[i+2]
+			}
+			return toks[i]
+		}
+	}
+	return ""
+}
+
+// skipGraphQLGroup returns the index after the close token matching the open token at
+// toks[i].
+func skipGraphQLGroup(toks []string, i int, open, close string) int {
+	depth := 0
+	for ; i < len(toks); i++ {
+		switch toks[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// graphqlTokens splits a GraphQL document into names and punctuators, dropping
+// comments, strings and values other than names.
+func graphqlTokens(doc string) []string {
+	var toks []string
+	for i := 0; i < len(doc); {
+		c := doc[i]
+		switch {
+		case c == '#':
+			for i < len(doc) && doc[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			if strings.HasPrefix(doc[i:], `"""`) {
+				end := strings.Index(doc[i+3:], `"""`)
+				if end < 0 {
+					return toks
+				}
+				i += end + 6
+				continue
+			}
+			for i++; i < len(doc) && doc[i] != '"' && doc[i] != '\n'; i++ {
+				if doc[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case strings.HasPrefix(doc[i:], "..."):
+			toks = append(toks, "...")
+			i += 3
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(doc) && (doc[i] == '_' || doc[i] >= 'a' && doc[i] <= 'z' || doc[i] >= 'A' && doc[i] <= 'Z' || doc[i] >= '0' && doc[i] <= '9') {
+				i++
+			}
+			toks = append(toks, doc[start:i])
+		case strings.ContainsRune("{}():", rune(c)):
+			toks = append(toks, string(c))
+			i++
+		default:
+			i++
+		}
+	}
+	return toks
+}
+
+func isGraphQLName(t string) bool {
+	c := t[0]
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
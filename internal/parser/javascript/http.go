@@ -0,0 +1,129 @@
+package javascript
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// httpMethods are the client methods whose first argument is a request URL.
+var httpMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "patch": true, "delete": true, "head": true, "options": true,
+}
+
+// extractAPICalls emits calls_api references for HTTP requests made with fetch, axios,
+// or a client instance created in the same file with axios.create({ baseURL }) or
+// new HttpClient(base). Calls through an instance get its base URL prefixed, so
+// api.get('/users') on a '/api/v2' instance records '/api/v2/users'. Instances are
+// tracked by the expression they are assigned to ("api", "this.client") and only
+// apply to calls that follow them in the file. The request method is kept in the
+// qualified target (route:POST /api/v2/users) for matching server endpoints; fetch
+// defaults to GET unless its options name a method.
+func (p *Parser) extractAPICalls(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	findEnclosing := enclosingSymbolFinder(symbols)
+	bases := make(map[string]string) // instance expression -> base URL
+
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "variable_declarator", "public_field_definition", "field_definition":
+			name := node.ChildByFieldName("name")
+			if name == nil {
+				name = node.ChildByFieldName("property")
+			}
+			value := node.ChildByFieldName("value")
+			if name == nil || value == nil {
+				return
+			}
+			key := name.Content(src)
+			if node.Type() != "variable_declarator" {
+				key = "this." + key
+			}
+			if base, ok := clientBaseURL(value, src); ok {
+				bases[key] = base
+			}
+
+		case "assignment_expression":
+			left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+			if left == nil || right == nil {
+				return
+			}
+			if base, ok := clientBaseURL(right, src); ok {
+				bases[left.Content(src)] = base
+			}
+
+		case "call_expression":
+			fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+			if fn == nil || args == nil {
+				return
+			}
+			path := extractFirstString(args, src)
+			if path == "" {
+				return
+			}
+
+			method := "GET"
+			switch fn.Type() {
+			case "identifier":
+				if fn.Content(src) != "fetch" {
+					return
+				}
+				if m := extractObjectStringProp(args, src, "method"); m != "" {
+					method = strings.ToUpper(m)
+				}
+			case "member_expression":
+				obj, prop := fn.ChildByFieldName("object"), fn.ChildByFieldName("property")
+				if obj == nil || prop == nil || !httpMethods[prop.Content(src)] {
+					return
+				}
+				client := obj.Content(src)
+				base, ok := bases[client]
+				if !ok && client != "axios" {
+					return
+				}
+				path = parser.JoinBaseURL(base, path)
+				method = strings.ToUpper(prop.Content(src))
+			default:
+				return
+			}
+
+			line := int(node.StartPoint().Row) + 1
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    findEnclosing(line),
+				ToName:        path,
+				ToQualified:   parser.APIRoutePrefix + method + " " + path,
+				ReferenceType: "calls_api",
+				Confidence:    p.confidence[PatternHTTPClient],
+				Line:          line,
+			})
+		}
+	})
+
+	return refs
+}
+
+// clientBaseURL reports whether node creates an HTTP client instance and returns its
+// base URL: axios.create({ baseURL: "..." }) or new HttpClient("...").
+func clientBaseURL(node *sitter.Node, src []byte) (string, bool) {
+	switch node.Type() {
+	case "call_expression":
+		fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+		if fn == nil || args == nil || fn.Content(src) != "axios.create" {
+			return "", false
+		}
+		return extractObjectStringProp(args, src, "baseURL"), true
+	case "new_expression":
+		ctor, args := node.ChildByFieldName("constructor"), node.ChildByFieldName("arguments")
+		if ctor == nil || ctor.Content(src) != "HttpClient" {
+			return "", false
+		}
+		base := ""
+		if args != nil {
+			base = extractFirstString(args, src)
+		}
+		return base, true
+	}
+	return "", false
+}
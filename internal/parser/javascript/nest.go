@@ -0,0 +1,200 @@
+package javascript
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// nestRouteDecorators maps NestJS method decorators to the HTTP method they route.
+var nestRouteDecorators = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE",
+	"Head": "HEAD", "Options": "OPTIONS", "All": "ALL",
+}
+
+// nestInjectables are the class decorators that make NestJS resolve constructor
+// parameters from its DI container.
+var nestInjectables = map[string]bool{
+	"Controller": true, "Injectable": true, "Resolver": true, "WebSocketGateway": true,
+}
+
+// extractNestControllers handles NestJS classes. Each @Get/@Post/... handler of a
+// @Controller class becomes an endpoint symbol qualified with its method and combined
+// route, "route:GET /users/{id}" for @Controller('users') and @Get(':id'), with a calls
+// reference to the handler; frontend calls_api references are matched to it by the
+// resolver's api_route strategy. Constructor parameters of injectable classes become
+// references to the provider they inject: the @Inject token, or else the parameter type.
+func (p *Parser) extractNestControllers(root *sitter.Node, src []byte) ([]parser.Symbol, []parser.RawReference) {
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	walkTree(root, func(node *sitter.Node) {
+		if node.Type() != "class_declaration" {
+			return
+		}
+		name := node.ChildByFieldName("name")
+		body := node.ChildByFieldName("body")
+		if name == nil || body == nil {
+			return
+		}
+		className := name.Content(src)
+
+		// Class decorators sit on the export statement when the class is exported.
+		decorators := decoratorsOf(node)
+		if parent := node.Parent(); parent != nil && parent.Type() == "export_statement" {
+			decorators = append(decorators, decoratorsOf(parent)...)
+		}
+		controller, prefix, injectable := false, "", false
+		for _, d := range decorators {
+			n := extractDecoratorName(d, src)
+			if n == "Controller" {
+				controller, prefix = true, decoratorPath(d, src)
+			}
+			injectable = injectable || nestInjectables[n]
+		}
+
+		// Method decorators precede their method_definition in the class body.
+		var pending []*sitter.Node
+		for i := 0; i < int(body.ChildCount()); i++ {
+			child := body.Child(i)
+			if child.Type() == "decorator" {
+				pending = append(pending, child)
+				continue
+			}
+			if child.Type() != "method_definition" {
+				pending = nil
+				continue
+			}
+			methodName := child.ChildByFieldName("name")
+			if methodName == nil {
+				pending = nil
+				continue
+			}
+			method := methodName.Content(src)
+
+			if method == "constructor" && injectable {
+				refs = append(refs, injectedProviders(child, src, className)...)
+			}
+			if controller {
+				for _, d := range pending {
+					verb, ok := nestRouteDecorators[extractDecoratorName(d, src)]
+					if !ok {
+						continue
+					}
+					declared := prefix + "/" + decoratorPath(d, src)
+					route := verb + " " + parser.NormalizeRoute(parser.RouteTemplate(declared))
+					endpointQName := parser.APIRoutePrefix + route
+					symbols = append(symbols, parser.Symbol{
+						Name:          route,
+						QualifiedName: endpointQName,
+						Kind:          "endpoint",
+						Language:      p.lang,
+						StartLine:     int(d.StartPoint().Row) + 1,
+						EndLine:       int(child.EndPoint().Row) + 1,
+						Signature:     verb + " " + parser.DeclaredRoute(declared),
+					})
+					refs = append(refs, parser.RawReference{
+						FromSymbol:    endpointQName,
+						ToName:        method,
+						ToQualified:   className + "." + method,
+						ReferenceType: "calls",
+						Line:          int(child.StartPoint().Row) + 1,
+					})
+				}
+			}
+			pending = nil
+		}
+	})
+
+	return symbols, refs
+}
+
+// injectedProviders returns a references edge from a class to each provider its
+// constructor injects. Parameters with a primitive type and no @Inject token are skipped.
+func injectedProviders(ctor *sitter.Node, src []byte, className string) []parser.RawReference {
+	params := ctor.ChildByFieldName("parameters")
+	if params == nil {
+		return nil
+	}
+	var refs []parser.RawReference
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "required_parameter" && param.Type() != "optional_parameter" {
+			continue
+		}
+		provider := ""
+		for _, d := range decoratorsOf(param) {
+			if extractDecoratorName(d, src) != "Inject" {
+				continue
+			}
+			if call := findChild(d, "call_expression"); call != nil {
+				if args := call.ChildByFieldName("arguments"); args != nil && args.NamedChildCount() > 0 {
+					if token := args.NamedChild(0); token.Type() == "identifier" {
+						provider = token.Content(src)
+					}
+				}
+			}
+		}
+		if provider == "" {
+			provider = annotatedTypeName(param.ChildByFieldName("type"), src)
+		}
+		if provider == "" {
+			continue
+		}
+		refs = append(refs, parser.RawReference{
+			FromSymbol:    className,
+			ToName:        provider,
+			ReferenceType: "references",
+			Line:          int(param.StartPoint().Row) + 1,
+		})
+	}
+	return refs
+}
+
+// annotatedTypeName returns the class named by a type annotation: UsersService for
+// ": UsersService" and Repository for ": Repository<User>". Primitive types yield "".
+func annotatedTypeName(annotation *sitter.Node, src []byte) string {
+	if annotation == nil {
+		return ""
+	}
+	for i := 0; i < int(annotation.NamedChildCount()); i++ {
+		t := annotation.NamedChild(i)
+		switch t.Type() {
+		case "type_identifier":
+			return t.Content(src)
+		case "generic_type":
+			if n := t.ChildByFieldName("name"); n != nil {
+				return n.Content(src)
+			}
+		}
+	}
+	return ""
+}
+
+// decoratorsOf returns the decorator children of a node.
+func decoratorsOf(node *sitter.Node) []*sitter.Node {
+	var out []*sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == "decorator" {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// decoratorPath returns the route path a NestJS decorator is called with:
+// 'users' for @Controller('users') or @Controller({ path: 'users' }), "" for @Get().
+func decoratorPath(decorator *sitter.Node, src []byte) string {
+	call := findChild(decorator, "call_expression")
+	if call == nil {
+		return ""
+	}
+	args := call.ChildByFieldName("arguments")
+	if args == nil {
+		return ""
+	}
+	if path := extractFirstString(args, src); path != "" {
+		return path
+	}
+	return extractObjectStringProp(args, src, "path")
+}
@@ -2,6 +2,10 @@ package javascript
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -12,22 +16,93 @@ import (
 	"github.com/maraichr/lattice/internal/parser/sqlutil"
 )
 
+// Database/ORM pattern keys, used to look up the confidence of references they emit.
+const (
+	PatternEntity            = "entity"             // @Entity("t") / @Table("t") decorators
+	PatternSequelizeDefine   = "sequelize_define"   // sequelize.define("t", ...)
+	PatternQuery             = "query"              // pool.query / conn.execute raw SQL
+	PatternRaw               = "raw"                // knex.raw raw SQL
+	PatternPreparedStatement = "prepared_statement" // conn.prepareStatement / prepareCall
+	PatternPrisma            = "prisma"             // prisma.model.findMany()
+	PatternKnex              = "knex"               // knex("t") query builder
+	PatternHTTPClient        = "http_client"        // fetch / axios / HttpClient request URLs
+	PatternSignalR           = "signalr"            // connection.invoke("HubMethod") on a SignalR connection
+	PatternGraphQL           = "graphql"            // gql`query Orders { orders { id } }` documents
+	PatternMongoose          = "mongoose"           // mongoose.model("User", schema), new Schema({...}, {collection: "users"})
+	PatternMongoCollection   = "mongo_collection"   // db.collection("orders")
+)
+
+// DefaultConfidence returns the confidence assigned to references from each pattern.
+func DefaultConfidence() map[string]float64 {
+	return map[string]float64{
+		PatternEntity:            0.95,
+		PatternSequelizeDefine:   0.95,
+		PatternQuery:             0.9,
+		PatternRaw:               0.85,
+		PatternPreparedStatement: 0.9,
+		PatternPrisma:            0.8,
+		PatternKnex:              0.9,
+		PatternHTTPClient:        0.85,
+		PatternSignalR:           0.85,
+		PatternGraphQL:           0.9,
+		PatternMongoose:          0.85,
+		PatternMongoCollection:   0.85,
+	}
+}
+
 // Parser implements a tree-sitter based JavaScript/TypeScript parser.
 type Parser struct {
-	tsParser *sitter.Parser
-	lang     string // "javascript" or "typescript"
+	tsParser   *sitter.Parser
+	lang       string             // "javascript" or "typescript"
+	confidence map[string]float64 // pattern key -> reference confidence
 }
 
 func NewJS() *Parser {
+	return NewJSWithConfidence(nil)
+}
+
+func NewTS() *Parser {
+	return NewTSWithConfidence(nil)
+}
+
+// NewConfidence returns the default pattern confidences with overrides applied, e.g.
+// {"prisma": "0.7"}. An unknown pattern or a value outside (0, 1] is an error.
+func NewConfidence(overrides map[string]string) (map[string]float64, error) {
+	conf := DefaultConfidence()
+	for pattern, value := range overrides {
+		if _, ok := conf[pattern]; !ok {
+			return nil, fmt.Errorf("unknown pattern %s", pattern)
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f <= 0 || f > 1 {
+			return nil, fmt.Errorf("pattern %s: confidence must be a number in (0, 1], got %q", pattern, value)
+		}
+		conf[pattern] = f
+	}
+	return conf, nil
+}
+
+// NewJSWithConfidence creates a JavaScript parser whose pattern confidences are the
+// defaults overridden by overrides, as validated by NewConfidence.
+func NewJSWithConfidence(overrides map[string]float64) *Parser {
 	p := sitter.NewParser()
 	p.SetLanguage(javascript.GetLanguage())
-	return &Parser{tsParser: p, lang: "javascript"}
+	return &Parser{tsParser: p, lang: "javascript", confidence: mergeConfidence(overrides)}
 }
 
-func NewTS() *Parser {
+// NewTSWithConfidence is NewJSWithConfidence for TypeScript.
+func NewTSWithConfidence(overrides map[string]float64) *Parser {
 	p := sitter.NewParser()
 	p.SetLanguage(typescript.GetLanguage())
-	return &Parser{tsParser: p, lang: "typescript"}
+	return &Parser{tsParser: p, lang: "typescript", confidence: mergeConfidence(overrides)}
+}
+
+func mergeConfidence(overrides map[string]float64) map[string]float64 {
+	conf := DefaultConfidence()
+	for k, v := range overrides {
+		conf[k] = v
+	}
+	return conf
 }
 
 func (p *Parser) Languages() []string {
@@ -56,6 +131,41 @@ func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
 	// Post-extraction pass: detect ORM/SQL database references
 	dbRefs := p.extractDatabaseRefs(root, input.Content, symbols)
 	refs = append(refs, dbRefs...)
+	// Mongoose and MongoDB collections, each resolved to a collection symbol in this file
+	symbols = append(symbols, parser.CollectionSymbols(dbRefs, p.lang)...)
+
+	// HTTP client calls, with base URLs of client instances created in this file
+	refs = append(refs, p.extractAPICalls(root, input.Content, symbols)...)
+
+	// NestJS controller routes and constructor-injected providers
+	nestSyms, nestRefs := p.extractNestControllers(root, input.Content)
+	symbols = append(symbols, nestSyms...)
+	refs = append(refs, nestRefs...)
+
+	// SignalR hub method invocations
+	refs = append(refs, p.extractHubCalls(root, input.Content, symbols)...)
+
+	// GraphQL operations in gql`...` templates
+	refs = append(refs, p.extractGraphQLCalls(root, input.Content, symbols)...)
+
+	// Environment variable reads, each resolved to a config_key symbol in this file
+	configRefs := p.extractConfigReads(root, input.Content, symbols)
+	refs = append(refs, configRefs...)
+	symbols = append(symbols, parser.ConfigKeySymbols(configRefs, p.lang)...)
+
+	if input.DetectConditionalCalls {
+		parser.MarkConditionalCalls(refs, featureFlagRanges(root, input.Content))
+	}
+
+	// The file itself is a module symbol, what its imports come from and what importing
+	// it from another file resolves to
+	module := moduleSymbol(root, input.Path, p.lang)
+	for i := range refs {
+		if refs[i].ReferenceType == "imports" && refs[i].FromSymbol == "" {
+			refs[i].FromSymbol = module.QualifiedName
+		}
+	}
+	symbols = append(symbols, module)
 
 	return &parser.ParseResult{
 		Symbols:    symbols,
@@ -63,6 +173,20 @@ func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
 	}, nil
 }
 
+// moduleSymbol returns the module symbol of a file, qualified by its path so that
+// modules of the same name in different directories stay apart.
+func moduleSymbol(root *sitter.Node, file, lang string) parser.Symbol {
+	file = filepath.ToSlash(file)
+	return parser.Symbol{
+		Name:          path.Base(file),
+		QualifiedName: file,
+		Kind:          "module",
+		Language:      lang,
+		StartLine:     1,
+		EndLine:       int(root.EndPoint().Row) + 1,
+	}
+}
+
 func (p *Parser) extractTopLevel(node *sitter.Node, src []byte, scope string) ([]parser.Symbol, []parser.RawReference) {
 	switch node.Type() {
 	case "function_declaration":
@@ -651,36 +775,11 @@ func (p *Parser) extractEnumDecl(node *sitter.Node, src []byte, scope string) pa
 
 // extractDatabaseRefs walks the AST for ORM and SQL patterns:
 // TypeORM/Sequelize decorators, sequelize.define, raw SQL via pool.query,
-// Knex query builder, Prisma model access.
+// Knex query builder, Prisma model access, and Mongoose/MongoDB collections.
 func (p *Parser) extractDatabaseRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
 	var refs []parser.RawReference
 
-	// Build symbol line ranges for FromSymbol resolution
-	type symRange struct {
-		qname     string
-		startLine int
-		endLine   int
-	}
-	var ranges []symRange
-	for _, s := range symbols {
-		if s.Kind == "class" || s.Kind == "function" || s.Kind == "method" {
-			ranges = append(ranges, symRange{s.QualifiedName, s.StartLine, s.EndLine})
-		}
-	}
-	findEnclosing := func(line int) string {
-		best := ""
-		bestSpan := 1<<31 - 1
-		for _, r := range ranges {
-			if line >= r.startLine && line <= r.endLine {
-				span := r.endLine - r.startLine
-				if span < bestSpan {
-					bestSpan = span
-					best = r.qname
-				}
-			}
-		}
-		return best
-	}
+	findEnclosing := enclosingSymbolFinder(symbols)
 
 	walkTree(root, func(node *sitter.Node) {
 		switch node.Type() {
@@ -691,6 +790,13 @@ func (p *Parser) extractDatabaseRefs(root *sitter.Node, src []byte, symbols []pa
 				refs = append(refs, *ref)
 			}
 
+		case "new_expression":
+			// new Schema({...}, { collection: "users" })
+			line := int(node.StartPoint().Row) + 1
+			if ref := p.extractSchemaCollection(node, src, line, findEnclosing(line)); ref != nil {
+				refs = append(refs, *ref)
+			}
+
 		case "call_expression":
 			line := int(node.StartPoint().Row) + 1
 			from := findEnclosing(line)
@@ -703,6 +809,14 @@ func (p *Parser) extractDatabaseRefs(root *sitter.Node, src []byte, symbols []pa
 			} else {
 				// knex('tablename') — direct call
 				fnIdent := findChild(node, "identifier")
+				// model('User', userSchema) — model imported from mongoose
+				if fnIdent != nil && fnIdent.Content(src) == "model" {
+					if args := findChild(node, "arguments"); args != nil && args.NamedChildCount() >= 2 {
+						if ref := p.mongooseModelRef(args, src, line, from); ref != nil {
+							refs = append(refs, *ref)
+						}
+					}
+				}
 				if fnIdent != nil && fnIdent.Content(src) == "knex" {
 					args := findChild(node, "arguments")
 					if args != nil {
@@ -712,7 +826,7 @@ func (p *Parser) extractDatabaseRefs(root *sitter.Node, src []byte, symbols []pa
 								FromSymbol:    from,
 								ToName:        tableName,
 								ReferenceType: "uses_table",
-								Confidence:    0.9,
+								Confidence:    p.confidence[PatternKnex],
 								Line:          line,
 							})
 						}
@@ -725,6 +839,36 @@ func (p *Parser) extractDatabaseRefs(root *sitter.Node, src []byte, symbols []pa
 	return refs
 }
 
+// enclosingSymbolFinder returns a lookup from a line to the innermost class, function
+// or method containing it, for FromSymbol resolution.
+func enclosingSymbolFinder(symbols []parser.Symbol) func(line int) string {
+	type symRange struct {
+		qname     string
+		startLine int
+		endLine   int
+	}
+	var ranges []symRange
+	for _, s := range symbols {
+		if s.Kind == "class" || s.Kind == "function" || s.Kind == "method" {
+			ranges = append(ranges, symRange{s.QualifiedName, s.StartLine, s.EndLine})
+		}
+	}
+	return func(line int) string {
+		best := ""
+		bestSpan := 1<<31 - 1
+		for _, r := range ranges {
+			if line >= r.startLine && line <= r.endLine {
+				span := r.endLine - r.startLine
+				if span < bestSpan {
+					bestSpan = span
+					best = r.qname
+				}
+			}
+		}
+		return best
+	}
+}
+
 // extractEntityDecorator handles @Entity("tableName") and @Table("tableName") decorators.
 func (p *Parser) extractEntityDecorator(node *sitter.Node, src []byte) *parser.RawReference {
 	// Decorator child is either identifier or call_expression
@@ -767,7 +911,9 @@ func (p *Parser) extractEntityDecorator(node *sitter.Node, src []byte) *parser.R
 				FromSymbol:    className,
 				ToName:        tableName,
 				ReferenceType: "uses_table",
-				Confidence:    0.95,
+				Mapping:       parser.MappingAttribute,
+				Entity:        className,
+				Confidence:    p.confidence[PatternEntity],
 				Line:          int(node.StartPoint().Row) + 1,
 			}
 		}
@@ -808,44 +954,39 @@ func (p *Parser) extractMemberCallDBRef(memberExpr, callNode *sitter.Node, src [
 				FromSymbol:    from,
 				ToName:        tableName,
 				ReferenceType: "uses_table",
-				Confidence:    0.95,
+				Mapping:       parser.MappingORM,
+				Confidence:    p.confidence[PatternSequelizeDefine],
 				Line:          line,
 			})
 		}
 
+	// mongoose.model('User', userSchema), conn.model('User', userSchema, 'users')
+	case methodName == "model" && args != nil && (objectName == "mongoose" || args.NamedChildCount() >= 2):
+		if ref := p.mongooseModelRef(args, src, line, from); ref != nil {
+			refs = append(refs, *ref)
+		}
+
+	// db.collection('orders'), client.db('shop').collection('orders')
+	case methodName == "collection" && args != nil:
+		if name := extractFirstString(args, src); name != "" {
+			refs = append(refs, collectionRef(from, name, p.confidence[PatternMongoCollection], line))
+		}
+
 	// pool.query("SQL"), connection.query("SQL"), client.query("SQL"),
 	// conn.execute("SQL"), connection.execute("SQL")
 	case (methodName == "query" || methodName == "execute") && args != nil:
 		sqlStr := extractFirstString(args, src)
-		if sqlStr != "" && sqlutil.LooksLikeSQL(sqlStr) {
-			tableRefs := sqlutil.ExtractTableRefs(sqlStr, line, from, "")
-			for i := range tableRefs {
-				tableRefs[i].Confidence = 0.9
-			}
-			refs = append(refs, tableRefs...)
-		}
+		refs = append(refs, sqlStringRefs(sqlStr, line, from, p.confidence[PatternQuery])...)
 
 	// knex.raw("SQL"), knex.schema.raw("SQL")
 	case methodName == "raw" && args != nil:
 		sqlStr := extractFirstString(args, src)
-		if sqlStr != "" && sqlutil.LooksLikeSQL(sqlStr) {
-			tableRefs := sqlutil.ExtractTableRefs(sqlStr, line, from, "")
-			for i := range tableRefs {
-				tableRefs[i].Confidence = 0.85
-			}
-			refs = append(refs, tableRefs...)
-		}
+		refs = append(refs, sqlStringRefs(sqlStr, line, from, p.confidence[PatternRaw])...)
 
 	// conn.prepareStatement("SQL"), conn.prepareCall("{call proc}")
 	case (methodName == "prepareStatement" || methodName == "prepareCall") && args != nil:
 		sqlStr := extractFirstString(args, src)
-		if sqlStr != "" && sqlutil.LooksLikeSQL(sqlStr) {
-			tableRefs := sqlutil.ExtractTableRefs(sqlStr, line, from, "")
-			for i := range tableRefs {
-				tableRefs[i].Confidence = 0.9
-			}
-			refs = append(refs, tableRefs...)
-		}
+		refs = append(refs, sqlStringRefs(sqlStr, line, from, p.confidence[PatternPreparedStatement])...)
 
 	// Prisma: prisma.user.findMany(), prisma.order.create(), etc.
 	case isPrismaMethod(methodName) && strings.Contains(memberText, "."):
@@ -856,7 +997,7 @@ func (p *Parser) extractMemberCallDBRef(memberExpr, callNode *sitter.Node, src [
 				FromSymbol:    from,
 				ToName:        modelName,
 				ReferenceType: "uses_table",
-				Confidence:    0.8,
+				Confidence:    p.confidence[PatternPrisma],
 				Line:          line,
 			})
 		}
@@ -877,7 +1018,7 @@ func (p *Parser) extractMemberCallDBRef(memberExpr, callNode *sitter.Node, src [
 							FromSymbol:    from,
 							ToName:        tableName,
 							ReferenceType: "uses_table",
-							Confidence:    0.9,
+							Confidence:    p.confidence[PatternKnex],
 							Line:          line,
 						})
 					}
@@ -889,6 +1030,97 @@ func (p *Parser) extractMemberCallDBRef(memberExpr, callNode *sitter.Node, src [
 	return refs
 }
 
+// mongooseModelRef returns the uses_collection reference of a Mongoose model
+// definition, model("User", schema[, "users"]): to the collection the third argument
+// names, else to the one Mongoose derives from the model name.
+func (p *Parser) mongooseModelRef(args *sitter.Node, src []byte, line int, from string) *parser.RawReference {
+	model := ""
+	if first := args.NamedChild(0); first != nil && first.Type() == "string" {
+		model = extractStringContent(first, src)
+	}
+	if model == "" {
+		return nil
+	}
+	name := parser.MongooseCollection(model)
+	if third := args.NamedChild(2); third != nil && third.Type() == "string" {
+		if collection := extractStringContent(third, src); collection != "" {
+			name = collection
+		}
+	}
+	ref := collectionRef(from, name, p.confidence[PatternMongoose], line)
+	return &ref
+}
+
+// collectionRef returns a uses_collection reference to the named collection's symbol.
+func collectionRef(from, name string, confidence float64, line int) parser.RawReference {
+	return parser.RawReference{
+		FromSymbol:    from,
+		ToName:        name,
+		ToQualified:   parser.CollectionPrefix + name,
+		ReferenceType: "uses_collection",
+		Confidence:    confidence,
+		Line:          line,
+	}
+}
+
+// extractSchemaCollection handles a Mongoose schema naming its collection in its
+// options, new Schema({...}, { collection: "users" }) or new mongoose.Schema(...).
+func (p *Parser) extractSchemaCollection(node *sitter.Node, src []byte, line int, from string) *parser.RawReference {
+	ctor := node.ChildByFieldName("constructor")
+	if ctor == nil {
+		return nil
+	}
+	switch ctor.Type() {
+	case "identifier":
+	case "member_expression":
+		ctor = ctor.ChildByFieldName("property")
+	default:
+		return nil
+	}
+	args := node.ChildByFieldName("arguments")
+	if ctor == nil || ctor.Content(src) != "Schema" || args == nil {
+		return nil
+	}
+	options := args.NamedChild(1)
+	if options == nil || options.Type() != "object" {
+		return nil
+	}
+	for i := 0; i < int(options.NamedChildCount()); i++ {
+		pair := options.NamedChild(i)
+		key, value := pair.ChildByFieldName("key"), pair.ChildByFieldName("value")
+		if pair.Type() != "pair" || key == nil || value == nil || value.Type() != "string" {
+			continue
+		}
+		if strings.Trim(key.Content(src), `"'`) != "collection" {
+			continue
+		}
+		if name := extractStringContent(value, src); name != "" {
+			ref := collectionRef(from, name, p.confidence[PatternMongoose], line)
+			return &ref
+		}
+	}
+	return nil
+}
+
+// sqlStringRefs extracts table and procedure references from a SQL string passed to a
+// driver. A call escape ({call proc(?)}) or bare CALL has no other SQL keyword, so a
+// string that does not look like SQL is still checked for procedure calls.
+func sqlStringRefs(sqlStr string, line int, from string, confidence float64) []parser.RawReference {
+	var refs []parser.RawReference
+	switch {
+	case sqlStr == "":
+		return nil
+	case sqlutil.LooksLikeSQL(sqlStr):
+		refs = sqlutil.ExtractTableRefs(sqlStr, line, from, "")
+	default:
+		refs = sqlutil.ExtractProcCalls(sqlStr, line, from)
+	}
+	for i := range refs {
+		refs[i].Confidence = confidence
+	}
+	return refs
+}
+
 func isPrismaMethod(method string) bool {
 	prisma := map[string]bool{
 		"findMany": true, "findFirst": true, "findUnique": true,
@@ -1013,6 +1245,23 @@ func findChild(node *sitter.Node, nodeType string) *sitter.Node {
 	return nil
 }
 
+// featureFlagRanges returns the line ranges of if statements and ternaries whose condition is a
+// feature-flag check, for parser.MarkConditionalCalls.
+func featureFlagRanges(root *sitter.Node, src []byte) [][2]int {
+	var ranges [][2]int
+	walkTree(root, func(n *sitter.Node) {
+		if n.Type() != "if_statement" && n.Type() != "ternary_expression" {
+			return
+		}
+		cond := n.ChildByFieldName("condition")
+		if cond == nil || !parser.IsFeatureFlagCondition(cond.Content(src)) {
+			return
+		}
+		ranges = append(ranges, [2]int{int(n.StartPoint().Row) + 1, int(n.EndPoint().Row) + 1})
+	})
+	return ranges
+}
+
 func walkTree(node *sitter.Node, fn func(*sitter.Node)) {
 	fn(node)
 	for i := 0; i < int(node.ChildCount()); i++ {
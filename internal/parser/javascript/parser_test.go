@@ -1,6 +1,7 @@
 package javascript
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/maraichr/lattice/internal/parser"
@@ -294,6 +295,25 @@ func TestTSImportStatement(t *testing.T) {
 	assertRefTarget(t, imports, "@angular/core")
 }
 
+func TestTSModuleSymbol(t *testing.T) {
+	src := `import { UserService } from './services/user';
+
+export function render() { return new UserService(); }
+`
+	p := NewTS()
+	result, err := p.Parse(parser.FileInput{Path: "src/app/home.ts", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "src/app/home.ts", "module")
+	for _, ref := range filterRefs(result.References, "imports") {
+		if ref.FromSymbol != "src/app/home.ts" {
+			t.Errorf("expected import of %s from the module, got %q", ref.ToName, ref.FromSymbol)
+		}
+	}
+}
+
 func TestTSFullSample(t *testing.T) {
 	src := `
 import { Injectable } from '@angular/core';
@@ -425,6 +445,76 @@ const User = sequelize.define('users', {
 	assertRefTarget(t, tableRefs, "users")
 }
 
+func TestJSMongooseModel(t *testing.T) {
+	src := `
+const mongoose = require('mongoose');
+const { Schema, model } = mongoose;
+
+const userSchema = new Schema({ name: String });
+const User = mongoose.model('User', userSchema);
+const Order = model('Order', new Schema({ total: Number }), 'orders');
+const auditSchema = new mongoose.Schema({ action: String }, { collection: 'audit_log' });
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "models.js", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collectionRefs := filterRefs(result.References, "uses_collection")
+	// The User model is stored in the collection Mongoose derives from its name
+	assertRefTarget(t, collectionRefs, "users")
+	assertRefTarget(t, collectionRefs, "orders")
+	assertRefTarget(t, collectionRefs, "audit_log")
+	if len(collectionRefs) != 3 {
+		t.Errorf("expected 3 uses_collection refs, got %+v", collectionRefs)
+	}
+	collections := make(map[string]bool)
+	for _, s := range result.Symbols {
+		if s.Kind == "collection" {
+			collections[s.QualifiedName] = true
+		}
+	}
+	for _, r := range collectionRefs {
+		if r.Confidence != 0.85 {
+			t.Errorf("expected confidence 0.85 for %s, got %v", r.ToName, r.Confidence)
+		}
+		if r.ToQualified != parser.CollectionPrefix+r.ToName || !collections[r.ToQualified] {
+			t.Errorf("expected %s to target a collection symbol, got %q among %v", r.ToName, r.ToQualified, collections)
+		}
+	}
+}
+
+func TestJSMongoCollection(t *testing.T) {
+	src := `
+async function listOrders(db) {
+  return db.collection('orders').find({ status: 'open' }).toArray();
+}
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "orders.js", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collectionRefs := filterRefs(result.References, "uses_collection")
+	assertRefTarget(t, collectionRefs, "orders")
+	for _, r := range collectionRefs {
+		if r.FromSymbol != "listOrders" {
+			t.Errorf("expected the ref from the enclosing function, got %q", r.FromSymbol)
+		}
+	}
+	var collections []string
+	for _, s := range result.Symbols {
+		if s.Kind == "collection" {
+			collections = append(collections, s.QualifiedName)
+		}
+	}
+	if len(collections) != 1 || collections[0] != "collection:orders" {
+		t.Errorf("expected one collection:orders symbol, got %v", collections)
+	}
+}
+
 func TestJSPoolQuery(t *testing.T) {
 	src := `
 async function getUsers() {
@@ -470,6 +560,22 @@ const result = await knex.raw("SELECT * FROM payments WHERE amount > 100");
 	assertRefTarget(t, tableRefs, "payments")
 }
 
+func TestJSQueryProcCall(t *testing.T) {
+	src := `
+async function refresh(year) {
+  await conn.query("CALL refresh_totals(?)", [year]);
+}
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "totals.js", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callRefs := filterRefs(result.References, "calls")
+	assertRefTarget(t, callRefs, "refresh_totals")
+}
+
 func TestTSPrismaModelAccess(t *testing.T) {
 	src := `
 async function getUser(id: string) {
@@ -486,6 +592,28 @@ async function getUser(id: string) {
 	assertRefTarget(t, tableRefs, "user")
 }
 
+func TestTSPrismaConfidenceOverride(t *testing.T) {
+	src := `
+async function getUser(id: string) {
+  const user = await prisma.user.findUnique({ where: { id } });
+  return knex('accounts').where({ id });
+}
+`
+	p := NewTSWithConfidence(map[string]float64{PatternPrisma: 0.6})
+	result, err := p.Parse(parser.FileInput{Path: "service.ts", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ref := range filterRefs(result.References, "uses_table") {
+		want := map[string]float64{"user": 0.6, "accounts": 0.9}[ref.ToName]
+		if ref.Confidence != want {
+			t.Errorf("ref to %s: confidence %v, want %v", ref.ToName, ref.Confidence, want)
+		}
+	}
+	assertRefTarget(t, filterRefs(result.References, "uses_table"), "user")
+}
+
 func TestJSConnectionExecute(t *testing.T) {
 	src := `
 async function insertOrder(order) {
@@ -518,6 +646,252 @@ const result = await fetch("/api/users");
 	}
 }
 
+func TestJSAPICallsUseInstanceBaseURL(t *testing.T) {
+	src := `
+import axios from 'axios';
+
+const api = axios.create({ baseURL: '/api/v2' });
+const billing = axios.create({ baseURL: 'https://billing.example.com/v1/' });
+
+export async function loadUsers() {
+  return api.get('/users');
+}
+
+export async function loadInvoices() {
+  await billing.post('invoices');
+  return axios.get('/health');
+}
+
+class OrdersClient {
+  constructor() {
+    this.http = new HttpClient('/api/orders');
+  }
+  list() { return this.http.get('/recent'); }
+}
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "api.js", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_api")
+	want := map[string]string{
+		"/api/v2/users":      "loadUsers",
+		"/v1/invoices":       "loadInvoices",
+		"/health":            "loadInvoices",
+		"/api/orders/recent": "OrdersClient.list",
+	}
+	if len(calls) != len(want) {
+		t.Errorf("expected %d calls_api refs, got %+v", len(want), calls)
+	}
+	for _, r := range calls {
+		from, ok := want[r.ToName]
+		if !ok {
+			t.Errorf("unexpected calls_api target %q", r.ToName)
+			continue
+		}
+		if r.FromSymbol != from {
+			t.Errorf("%s: expected call from %s, got %s", r.ToName, from, r.FromSymbol)
+		}
+	}
+}
+
+func TestTSNestControllerRoutes(t *testing.T) {
+	src := `
+@Controller('users')
+export class UsersController {
+  constructor(private readonly usersService: UsersService) {}
+
+  @Get(':id')
+  findOne(@Param('id') id: string) { return this.usersService.find(id); }
+
+  @Post()
+  @HttpCode(201)
+  create(@Body() dto: CreateUserDto) { return this.usersService.create(dto); }
+}
+`
+	p := NewTS()
+	result, err := p.Parse(parser.FileInput{Path: "users.controller.ts", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "route:GET /users/{id}", "endpoint")
+	assertHasSymbol(t, result.Symbols, "route:POST /users", "endpoint")
+
+	handlers := map[string]string{}
+	for _, r := range filterRefs(result.References, "calls") {
+		handlers[r.FromSymbol] = r.ToQualified
+	}
+	if handlers["route:GET /users/{id}"] != "UsersController.findOne" || handlers["route:POST /users"] != "UsersController.create" {
+		t.Errorf("expected each endpoint to call its handler, got %v", handlers)
+	}
+}
+
+func TestTSNestProviderInjection(t *testing.T) {
+	src := `
+@Injectable()
+export class OrdersService {
+  constructor(
+    private readonly repo: Repository<Order>,
+    @Inject(PAYMENTS_CLIENT) private readonly payments: ClientProxy,
+    private readonly retries: number,
+  ) {}
+}
+`
+	p := NewTS()
+	result, err := p.Parse(parser.FileInput{Path: "orders.service.ts", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var providers []string
+	for _, r := range filterRefs(result.References, "references") {
+		if r.FromSymbol == "OrdersService" {
+			providers = append(providers, r.ToName)
+		}
+	}
+	if len(providers) != 2 || providers[0] != "Repository" || providers[1] != "PAYMENTS_CLIENT" {
+		t.Errorf("expected Repository and the PAYMENTS_CLIENT token injected, got %v", providers)
+	}
+}
+
+func TestJSSignalRHubInvoke(t *testing.T) {
+	src := `
+import * as signalR from '@microsoft/signalr';
+
+const connection = new signalR.HubConnectionBuilder()
+  .withUrl('/hubs/chat')
+  .withAutomaticReconnect()
+  .build();
+
+export async function sendMessage(user, text) {
+  await connection.invoke('SendMessage', user, text);
+}
+
+export function notTheHub(worker) {
+  worker.invoke('SendMessage');
+}
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "chat.js", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_api")
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 calls_api ref from the hub connection, got %+v", calls)
+	}
+	if calls[0].ToName != "hub:SendMessage" || calls[0].FromSymbol != "sendMessage" {
+		t.Errorf("expected sendMessage → hub:SendMessage, got %s → %s", calls[0].FromSymbol, calls[0].ToName)
+	}
+}
+
+func TestJSGraphQLOperations(t *testing.T) {
+	// ~ stands in for a backtick, which a Go raw string cannot hold
+	src := strings.ReplaceAll(`
+import { gql, useQuery, useMutation } from '@apollo/client';
+
+const ORDER_FIELDS = gql~
+  fragment OrderFields on Order { id total }
+~;
+
+const GET_ORDERS = gql~
+  # open orders for the dashboard
+  query GetOrders($status: Status = OPEN) {
+    list: orders(status: $status) { ...OrderFields }
+  }
+  ${ORDER_FIELDS}
+~;
+
+export function OrderList() {
+  const { data } = useQuery(GET_ORDERS);
+  const [cancel] = useMutation(gql~
+    mutation { cancelOrder(id: 1) { id } }
+  ~);
+  return data;
+}
+
+export async function loadCustomer(client, id) {
+  return client.query({ query: gql~{ customer(id: $id) { name } }~ });
+}
+`, "~", "`")
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "orders.jsx", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := filterRefs(result.References, "calls_graphql")
+	want := map[string][2]string{ // ToName -> FromSymbol, ToQualified
+		"GetOrders":   {"OrderList", "graphql:Query.orders"},
+		"cancelOrder": {"OrderList", "graphql:Mutation.cancelOrder"},
+		"customer":    {"loadCustomer", "graphql:Query.customer"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls_graphql refs, got %+v", len(want), calls)
+	}
+	for _, c := range calls {
+		w, ok := want[c.ToName]
+		if !ok || c.FromSymbol != w[0] || c.ToQualified != w[1] {
+			t.Errorf("unexpected ref %s → %s (%s)", c.FromSymbol, c.ToName, c.ToQualified)
+		}
+	}
+}
+
+func TestJSConfigReads(t *testing.T) {
+	src := `
+function connect() {
+  const url = process.env.DATABASE_URL;
+  const pool = process.env["POOL_SIZE"];
+  return open(url, pool);
+}
+
+class Client {
+  load() {
+    const { API_KEY, REGION: region } = process.env;
+    return fetchWith(API_KEY, region, import.meta.env.VITE_API_URL);
+  }
+}
+
+function reconnect() {
+  return open(process.env.DATABASE_URL);
+}
+`
+	p := NewTS()
+	result, err := p.Parse(parser.FileInput{Path: "client.ts", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reads := filterRefs(result.References, "reads_config")
+	want := map[string][]string{
+		"DATABASE_URL": {"connect", "reconnect"},
+		"POOL_SIZE":    {"connect"},
+		"API_KEY":      {"Client.load"},
+		"REGION":       {"Client.load"},
+		"VITE_API_URL": {"Client.load"},
+	}
+	got := make(map[string][]string)
+	for _, r := range reads {
+		if r.ToQualified != parser.ConfigKeyPrefix+r.ToName {
+			t.Errorf("%s: expected qualified target %s, got %s", r.ToName, parser.ConfigKeyPrefix+r.ToName, r.ToQualified)
+		}
+		got[r.ToName] = append(got[r.ToName], r.FromSymbol)
+	}
+	for key, from := range want {
+		if strings.Join(got[key], ",") != strings.Join(from, ",") {
+			t.Errorf("%s: expected reads from %v, got %v", key, from, got[key])
+		}
+		assertHasSymbol(t, result.Symbols, parser.ConfigKeyPrefix+key, "config_key")
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected reads of %d keys, got %v", len(want), got)
+	}
+}
+
 // --- helpers ---
 
 func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
@@ -557,3 +931,50 @@ func assertRefTarget(t *testing.T, refs []parser.RawReference, target string) {
 	}
 	t.Errorf("missing ref target %s; have: %v", target, names)
 }
+
+func TestJSFeatureFlagGuardedCall(t *testing.T) {
+	src := `
+async function placeOrder(order) {
+  if (featureFlags.isEnabled("new-checkout")) {
+    await pool.query("EXEC dbo.PlaceOrderV2 @id = $1");
+  }
+  await pool.query("EXEC dbo.NotifyWarehouse @id = $1");
+}
+`
+	p := NewJS()
+	result, err := p.Parse(parser.FileInput{Path: "orders.js", Content: []byte(src), DetectConditionalCalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range filterRefs(result.References, "calls") {
+		want := r.ToQualified == "dbo.PlaceOrderV2"
+		if r.Conditional != want {
+			t.Errorf("%s: expected conditional=%v, got %v", r.ToQualified, want, r.Conditional)
+		}
+	}
+	if len(filterRefs(result.References, "calls")) != 2 {
+		t.Errorf("expected 2 calls refs, got %+v", result.References)
+	}
+}
+
+func TestNewConfidence(t *testing.T) {
+	conf, err := NewConfidence(map[string]string{PatternPrisma: "0.7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf[PatternPrisma] != 0.7 || conf[PatternKnex] != 0.9 {
+		t.Errorf("expected prisma overridden and knex at its default, got %v", conf)
+	}
+
+	for name, overrides := range map[string]map[string]string{
+		"unknown pattern": {"prsma": "0.7"},
+		"not a number":    {PatternPrisma: "high"},
+		"zero":            {PatternPrisma: "0"},
+		"above one":       {PatternPrisma: "1.5"},
+	} {
+		if _, err := NewConfidence(overrides); err == nil {
+			t.Errorf("%s: expected an error for %v", name, overrides)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package javascript
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// hubMethods are the HubConnection methods whose first argument names the hub method
+// to call on the server.
+var hubMethods = map[string]bool{
+	"invoke": true, "send": true, "stream": true,
+}
+
+// extractHubCalls emits calls_api references for SignalR hub invocations made through
+// a connection built in the same file with new signalR.HubConnectionBuilder()...build().
+// connection.invoke("Send", ...) records "hub:Send", which the resolver matches to the
+// C# hub method of that name. Connections are tracked like HTTP client instances, by
+// the expression they are assigned to.
+func (p *Parser) extractHubCalls(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+	findEnclosing := enclosingSymbolFinder(symbols)
+	connections := make(map[string]bool)
+
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "variable_declarator", "public_field_definition", "field_definition":
+			name := node.ChildByFieldName("name")
+			if name == nil {
+				name = node.ChildByFieldName("property")
+			}
+			value := node.ChildByFieldName("value")
+			if name == nil || value == nil || !isHubConnection(value, src) {
+				return
+			}
+			key := name.Content(src)
+			if node.Type() != "variable_declarator" {
+				key = "this." + key
+			}
+			connections[key] = true
+
+		case "assignment_expression":
+			left, right := node.ChildByFieldName("left"), node.ChildByFieldName("right")
+			if left == nil || right == nil || !isHubConnection(right, src) {
+				return
+			}
+			connections[left.Content(src)] = true
+
+		case "call_expression":
+			fn, args := node.ChildByFieldName("function"), node.ChildByFieldName("arguments")
+			if fn == nil || args == nil || fn.Type() != "member_expression" {
+				return
+			}
+			obj, prop := fn.ChildByFieldName("object"), fn.ChildByFieldName("property")
+			if obj == nil || prop == nil || !hubMethods[prop.Content(src)] || !connections[obj.Content(src)] {
+				return
+			}
+			method := extractFirstString(args, src)
+			if method == "" {
+				return
+			}
+			line := int(node.StartPoint().Row) + 1
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    findEnclosing(line),
+				ToName:        parser.HubMethodPrefix + method,
+				ReferenceType: "calls_api",
+				Confidence:    p.confidence[PatternSignalR],
+				Line:          line,
+			})
+		}
+	})
+
+	return refs
+}
+
+// isHubConnection reports whether node builds a SignalR connection: a builder chain
+// starting at new HubConnectionBuilder() or new signalR.HubConnectionBuilder().
+func isHubConnection(node *sitter.Node, src []byte) bool {
+	for node != nil {
+		switch node.Type() {
+		case "await_expression", "parenthesized_expression":
+			node = node.NamedChild(0)
+		case "call_expression":
+			fn := node.ChildByFieldName("function")
+			if fn == nil || fn.Type() != "member_expression" {
+				return false
+			}
+			node = fn.ChildByFieldName("object")
+		case "new_expression":
+			ctor := node.ChildByFieldName("constructor")
+			if ctor == nil {
+				return false
+			}
+			name := ctor.Content(src)
+			return name == "HubConnectionBuilder" || strings.HasSuffix(name, ".HubConnectionBuilder")
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,418 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Migration frameworks whose files DetectMigration recognizes.
+const (
+	MigrationFlyway    = "flyway"    // V1.2__add_orders.sql, R__refresh_views.sql
+	MigrationEF        = "ef"        // Migrations/20240105120000_AddOrders.cs
+	MigrationLiquibase = "liquibase" // db.changelog-1.2.xml/.yaml, --liquibase formatted sql
+	MigrationDNN       = "dnn"       // 08.00.00.SqlDataProvider
+)
+
+// MigrationSymbolPrefix starts the qualified name of a migration symbol. The rest is
+// "<framework>:<order>", so sorting a table's creates/alters sources by qualified name
+// replays its schema history in the order the framework applies it.
+const MigrationSymbolPrefix = "migration:"
+
+// Migration identifies a file as one step of a framework's ordered schema history.
+type Migration struct {
+	Framework   string
+	Version     string // as written: "1.2", "20240105120000", "08.00.00"; "" for repeatable migrations
+	Description string // "add_orders", "AddOrders"
+	Order       string // sortable key derived from Version
+}
+
+var (
+	flywayFileRe    = regexp.MustCompile(`^([VUR])([0-9][0-9._]*)?__(.+)\.sql$`)
+	efFileRe        = regexp.MustCompile(`^([0-9]{14})_(\w+)\.cs$`)
+	efAttributeRe   = regexp.MustCompile(`\[Migration\("([0-9]{14})_(\w+)"\)\]`)
+	dnnFileRe       = regexp.MustCompile(`(?i)^([0-9]+(?:\.[0-9]+)+)\.sqldataprovider$`)
+	changelogRe     = regexp.MustCompile(`(?i)changelog`)
+	changelogVerRe  = regexp.MustCompile(`[0-9]+(?:[._][0-9]+)*`)
+	liquibaseSQLRe  = regexp.MustCompile(`(?i)^\s*--\s*liquibase formatted sql`)
+	liquibaseRootRe = regexp.MustCompile(`<databaseChangeLog\b|(?m)^databaseChangeLog:`)
+)
+
+// changelogExts are the Liquibase changelog formats recognized by name alone.
+var changelogExts = map[string]bool{".xml": true, ".yaml": true, ".yml": true, ".sql": true}
+
+// MaybeMigration reports whether a file without a registered parser could still be a
+// migration, so its content is worth reading: a Liquibase XML or YAML changelog.
+func MaybeMigration(path string) bool {
+	base := filepath.Base(strings.ReplaceAll(path, "\\", "/"))
+	ext := strings.ToLower(filepath.Ext(base))
+	return ext != ".sql" && changelogExts[ext] && changelogRe.MatchString(base)
+}
+
+// DetectMigration recognizes the migration files of Flyway, EF Migrations, Liquibase and
+// DNN by their naming conventions, falling back to content for EF classes and Liquibase
+// changelogs not named for it. EF designer files hold model snapshots, not schema
+// operations, so they are not migrations here.
+func DetectMigration(path string, content []byte) (Migration, bool) {
+	base := filepath.Base(strings.ReplaceAll(path, "\\", "/"))
+	lower := strings.ToLower(base)
+
+	if m := flywayFileRe.FindStringSubmatch(base); m != nil && (m[1] == "R") == (m[2] == "") {
+		if m[1] == "U" {
+			return Migration{}, false // undo migrations roll history back, not forward
+		}
+		return newMigration(MigrationFlyway, m[2], m[3]), true
+	}
+	if m := dnnFileRe.FindStringSubmatch(base); m != nil {
+		return newMigration(MigrationDNN, m[1], ""), true
+	}
+	if strings.HasSuffix(lower, ".cs") && !strings.HasSuffix(lower, ".designer.cs") {
+		if m := efFileRe.FindStringSubmatch(base); m != nil {
+			return newMigration(MigrationEF, m[1], m[2]), true
+		}
+		if m := efAttributeRe.FindSubmatch(content); m != nil {
+			return newMigration(MigrationEF, string(m[1]), string(m[2])), true
+		}
+		return Migration{}, false
+	}
+
+	ext := strings.ToLower(filepath.Ext(base))
+	if !changelogExts[ext] {
+		return Migration{}, false
+	}
+	isChangelog := changelogRe.MatchString(base) && ext != ".sql"
+	if ext == ".sql" {
+		isChangelog = liquibaseSQLRe.Match(content)
+	} else if !isChangelog {
+		isChangelog = liquibaseRootRe.Match(content)
+	}
+	if !isChangelog {
+		return Migration{}, false
+	}
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	version := changelogVerRe.FindString(stem)
+	return newMigration(MigrationLiquibase, version, stem), true
+}
+
+func newMigration(framework, version, description string) Migration {
+	version = strings.Trim(version, "._")
+	return Migration{
+		Framework:   framework,
+		Version:     version,
+		Description: description,
+		Order:       migrationOrder(version, description),
+	}
+}
+
+// migrationOrder builds a key whose lexical order is the version order: each numeric
+// part is zero-padded, so 1.10 sorts after 1.9. Versionless (repeatable) migrations
+// sort after every versioned one, by description.
+func migrationOrder(version, description string) string {
+	if version == "" {
+		return "~" + description
+	}
+	parts := strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' })
+	for i, p := range parts {
+		parts[i] = fmt.Sprintf("%06s", p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Symbol returns the symbol standing for the migration in a file of the given language
+// and length. Its creates/alters references come from SchemaOps.
+func (m Migration) Symbol(path, language string, lines int) Symbol {
+	base := filepath.Base(strings.ReplaceAll(path, "\\", "/"))
+	sig := m.Framework
+	if m.Version != "" {
+		sig += " " + m.Version
+	}
+	if m.Description != "" {
+		sig += " " + m.Description
+	}
+	return Symbol{
+		Name:          strings.TrimSuffix(base, filepath.Ext(base)),
+		QualifiedName: MigrationSymbolPrefix + m.Framework + ":" + m.Order,
+		Kind:          "migration",
+		Language:      language,
+		StartLine:     1,
+		EndLine:       max(lines, 1),
+		Signature:     sig,
+	}
+}
+
+// SchemaOps returns the creates and alters references a migration file makes, from
+// the migration symbol to each object it creates or changes.
+func (m Migration) SchemaOps(path string, content []byte) []RawReference {
+	from := MigrationSymbolPrefix + m.Framework + ":" + m.Order
+	var ops []schemaOp
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case m.Framework == MigrationEF:
+		ops = efSchemaOps(string(content))
+	case ext == ".xml":
+		ops = liquibaseXMLOps(string(content))
+	case ext == ".yaml" || ext == ".yml":
+		ops = liquibaseYAMLOps(string(content))
+	default:
+		ops = sqlSchemaOps(string(content), 1)
+	}
+
+	refs := make([]RawReference, 0, len(ops))
+	for _, op := range ops {
+		name := strings.NewReplacer("{databaseOwner}", "dbo.", "{objectQualifier}", "").Replace(op.object)
+		name = strings.NewReplacer("[", "", "]", "", `"`, "", "`", "").Replace(name)
+		refs = append(refs, RawReference{
+			FromSymbol:    from,
+			ToName:        name[strings.LastIndex(name, ".")+1:],
+			ToQualified:   name,
+			ReferenceType: op.kind,
+			Line:          op.line,
+		})
+	}
+	return refs
+}
+
+// schemaOp is one schema change: creates or alters of a table, view or routine.
+type schemaOp struct {
+	kind   string // creates or alters
+	object string
+	line   int
+}
+
+var sqlDDLRe = regexp.MustCompile(`(?im)^[ \t]*(CREATE|ALTER|DROP)\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:UNIQUE\s+)?(?:(?:NON)?CLUSTERED\s+)?(TABLE|VIEW|INDEX|SEQUENCE|PROCEDURE|PROC|FUNCTION|TRIGGER)\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?([\w\[\]".{}]+)(?:\s+ON\s+([\w\[\]".{}]+))?`)
+
+// sqlSchemaOps finds DDL statements in SQL text starting at firstLine. Creating a table,
+// view or routine creates it; altering or dropping one, or adding an index to a table,
+// alters it. CREATE OR REPLACE/ALTER alters.
+func sqlSchemaOps(sql string, firstLine int) []schemaOp {
+	var ops []schemaOp
+	for _, m := range sqlDDLRe.FindAllStringSubmatchIndex(sql, -1) {
+		verb, kind := strings.ToUpper(sql[m[2]:m[3]]), strings.ToUpper(sql[m[4]:m[5]])
+		object := sql[m[6]:m[7]]
+		line := firstLine + strings.Count(sql[:m[0]], "\n")
+		opKind := "alters"
+		switch {
+		case kind == "INDEX":
+			if m[8] < 0 {
+				continue // DROP INDEX name: the table is not named
+			}
+			object = sql[m[8]:m[9]]
+		case verb == "CREATE" && !strings.Contains(strings.ToUpper(sql[m[2]:m[5]]), " OR "):
+			opKind = "creates"
+		}
+		ops = append(ops, schemaOp{kind: opKind, object: object, line: line})
+	}
+	return ops
+}
+
+var (
+	efUpRe        = regexp.MustCompile(`\bvoid\s+Up\s*\(`)
+	efCallRe      = regexp.MustCompile(`\.\s*(\w+)\s*(?:<[^>(]*>)?\s*\(`)
+	efNamedArgRe  = regexp.MustCompile(`\b(name|table|schema|newName)\s*:\s*"([^"]*)"`)
+	efSQLArgRe    = regexp.MustCompile(`^\s*@?"((?:[^"]|"")*)"`)
+	efCreateCalls = map[string]bool{"CreateTable": true}
+	efAlterCalls  = map[string]bool{
+		"AddColumn": true, "AlterColumn": true, "DropColumn": true, "RenameColumn": true,
+		"AddForeignKey": true, "DropForeignKey": true, "AddPrimaryKey": true, "DropPrimaryKey": true,
+		"AddUniqueConstraint": true, "DropUniqueConstraint": true, "CreateIndex": true, "DropIndex": true,
+		"RenameIndex": true, "AddCheckConstraint": true, "DropCheckConstraint": true, "AlterTable": true,
+		"DropTable": true, "RenameTable": true, "InsertData": true, "UpdateData": true, "DeleteData": true,
+	}
+)
+
+// efSchemaOps reads migrationBuilder calls in the Up method of an EF migration:
+// CreateTable(name: "Orders") creates Orders, AddColumn<int>(table: "Orders", ...) and
+// DropTable(name: "Orders") alter it, and Sql("...") is read as SQL.
+func efSchemaOps(src string) []schemaOp {
+	loc := efUpRe.FindStringIndex(src)
+	if loc == nil {
+		return nil
+	}
+	open := strings.IndexByte(src[loc[1]:], '{')
+	if open < 0 {
+		return nil
+	}
+	start := loc[1] + open
+	end := matchingBrace(src, start)
+	body := src[start:end]
+	baseLine := 1 + strings.Count(src[:start], "\n")
+
+	var ops []schemaOp
+	for _, m := range efCallRe.FindAllStringSubmatchIndex(body, -1) {
+		method := body[m[2]:m[3]]
+		argsEnd := matchingParen(body, m[1]-1)
+		args := body[m[1]:argsEnd]
+		line := baseLine + strings.Count(body[:m[0]], "\n")
+
+		if method == "Sql" {
+			if s := efSQLArgRe.FindStringSubmatch(args); s != nil {
+				ops = append(ops, sqlSchemaOps(strings.ReplaceAll(s[1], `""`, `"`), line)...)
+			}
+			continue
+		}
+		if !efCreateCalls[method] && !efAlterCalls[method] {
+			continue
+		}
+		named := make(map[string]string)
+		for _, a := range efNamedArgRe.FindAllStringSubmatch(topLevelArgs(args), -1) {
+			named[a[1]] = a[2]
+		}
+		table := named["table"]
+		if table == "" && (strings.HasSuffix(method, "Table") || method == "AlterTable") {
+			table = named["name"]
+		}
+		if table == "" {
+			continue
+		}
+		if named["schema"] != "" {
+			table = named["schema"] + "." + table
+		}
+		kind := "alters"
+		if efCreateCalls[method] {
+			kind = "creates"
+		}
+		ops = append(ops, schemaOp{kind: kind, object: table, line: line})
+	}
+	return ops
+}
+
+// topLevelArgs blanks nested calls and lambdas in an argument list, so the named
+// arguments of a CreateTable's columns are not read as the table's own.
+func topLevelArgs(args string) string {
+	b := []byte(args)
+	depth := 0
+	for i, c := range b {
+		switch c {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		default:
+			if depth > 0 {
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
+
+func matchingBrace(s string, open int) int {
+	return matchingPair(s, open, '{', '}')
+}
+
+func matchingParen(s string, open int) int {
+	return matchingPair(s, open, '(', ')')
+}
+
+// matchingPair returns the index of the closer matching the opener at s[open], skipping
+// string literals, or len(s) if it is never closed.
+func matchingPair(s string, open int, opener, closer byte) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case opener:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+var (
+	liquibaseTagRe  = regexp.MustCompile(`<(\w+)\b([^>]*)>`)
+	liquibaseAttrRe = regexp.MustCompile(`\b(tableName|viewName|schemaName|baseTableName)\s*=\s*"([^"]*)"`)
+	liquibaseYAMLRe = regexp.MustCompile(`^\s*-?\s*(\w+)\s*:\s*(.*?)\s*$`)
+)
+
+// liquibaseOp maps a Liquibase change type and its attributes to a schema operation.
+func liquibaseOp(change string, attrs map[string]string, line int) (schemaOp, bool) {
+	object := attrs["tableName"]
+	if object == "" {
+		object = attrs["viewName"]
+	}
+	if object == "" {
+		object = attrs["baseTableName"]
+	}
+	if object == "" {
+		return schemaOp{}, false
+	}
+	if attrs["schemaName"] != "" {
+		object = attrs["schemaName"] + "." + object
+	}
+	kind := "alters"
+	if change == "createTable" || change == "createView" {
+		kind = "creates"
+	}
+	return schemaOp{kind: kind, object: object, line: line}, true
+}
+
+// liquibaseXMLOps reads change elements of an XML changelog: <createTable tableName="x">
+// creates x, <addColumn tableName="x"> and other table changes alter it, and <sql>
+// bodies are read as SQL.
+func liquibaseXMLOps(doc string) []schemaOp {
+	var ops []schemaOp
+	for _, m := range liquibaseTagRe.FindAllStringSubmatchIndex(doc, -1) {
+		change := doc[m[2]:m[3]]
+		line := 1 + strings.Count(doc[:m[0]], "\n")
+		if change == "sql" {
+			if end := strings.Index(doc[m[1]:], "</sql>"); end >= 0 {
+				body := strings.NewReplacer("<![CDATA[", "", "]]>", "").Replace(doc[m[1] : m[1]+end])
+				ops = append(ops, sqlSchemaOps(body, line)...)
+			}
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, a := range liquibaseAttrRe.FindAllStringSubmatch(doc[m[4]:m[5]], -1) {
+			attrs[a[1]] = a[2]
+		}
+		if op, ok := liquibaseOp(change, attrs, line); ok {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// liquibaseYAMLOps reads changes of a YAML changelog: a change type key such as
+// createTable followed by its tableName, schemaName or viewName.
+func liquibaseYAMLOps(doc string) []schemaOp {
+	var ops []schemaOp
+	change, changeLine := "", 0
+	attrs := make(map[string]string)
+	flush := func() {
+		if change != "" {
+			if op, ok := liquibaseOp(change, attrs, changeLine); ok {
+				ops = append(ops, op)
+			}
+		}
+		change = ""
+		attrs = make(map[string]string)
+	}
+	for i, line := range strings.Split(doc, "\n") {
+		m := liquibaseYAMLRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key, value := m[1], strings.Trim(m[2], `"'`)
+		switch {
+		case value == "" && key != "columns" && key != "column" && key != "changes" && key != "changeSet" && key != "constraints":
+			flush()
+			change, changeLine = key, i+1
+		case key == "tableName" || key == "viewName" || key == "schemaName" || key == "baseTableName":
+			if _, seen := attrs[key]; !seen {
+				attrs[key] = value
+			}
+		}
+	}
+	flush()
+	return ops
+}
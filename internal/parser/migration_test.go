@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestDetectMigration_OrdersByVersion(t *testing.T) {
+	paths := []string{
+		"db/migration/V1_10__add_index.sql",
+		"db/migration/R__refresh_views.sql",
+		"db/migration/V1_9__add_orders.sql",
+		"db/migration/V2__drop_legacy.sql",
+	}
+	var orders []string
+	byOrder := make(map[string]string)
+	for _, p := range paths {
+		m, ok := DetectMigration(p, nil)
+		if !ok || m.Framework != MigrationFlyway {
+			t.Fatalf("%s: expected a Flyway migration, got %+v", p, m)
+		}
+		orders = append(orders, m.Order)
+		byOrder[m.Order] = p
+	}
+	sort.Strings(orders)
+	want := []string{paths[2], paths[0], paths[3], paths[1]}
+	for i, o := range orders {
+		if byOrder[o] != want[i] {
+			t.Errorf("position %d: got %s, want %s", i, byOrder[o], want[i])
+		}
+	}
+
+	for _, p := range []string{"db/migration/U2__undo.sql", "Migrations/20240105120000_AddOrders.Designer.cs", "db/orders.sql"} {
+		if m, ok := DetectMigration(p, []byte("CREATE TABLE t (id int);")); ok {
+			t.Errorf("%s: expected no migration, got %+v", p, m)
+		}
+	}
+}
+
+func TestMigrationSchemaOps_Liquibase(t *testing.T) {
+	doc := []byte(`<?xml version="1.0"?>
+<databaseChangeLog>
+  <changeSet id="1" author="dev">
+    <createTable tableName="orders" schemaName="sales">
+      <column name="id" type="bigint"/>
+    </createTable>
+    <addColumn tableName="customers">
+      <column name="email" type="varchar(255)"/>
+    </addColumn>
+  </changeSet>
+</databaseChangeLog>
+`)
+	m, ok := DetectMigration("db/changelog/db.changelog-1.2.xml", doc)
+	if !ok || m.Framework != MigrationLiquibase || m.Version != "1.2" {
+		t.Fatalf("expected a Liquibase changelog at 1.2, got %+v", m)
+	}
+	ops := make(map[string]string)
+	for _, ref := range m.SchemaOps("db/changelog/db.changelog-1.2.xml", doc) {
+		ops[ref.ToQualified] = ref.ReferenceType
+	}
+	if ops["sales.orders"] != "creates" || ops["customers"] != "alters" || len(ops) != 2 {
+		t.Errorf("expected sales.orders created and customers altered, got %v", ops)
+	}
+}
@@ -0,0 +1,157 @@
+// Package openapi parses OpenAPI 3 and Swagger 2 specs written in YAML or JSON. Each
+// operation becomes an endpoint symbol qualified "openapi:GET /users/{id}" with a
+// calls_api reference to the server route implementing it, so the spec bridges the
+// clients calling an operation and the controller serving it.
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// operationMethods are the keys of a path item that hold operations; the others are
+// shared parameters, servers and descriptions.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// specVersion matches the openapi: "3.x" or swagger: "2.0" key of a spec in YAML or JSON.
+var specVersion = regexp.MustCompile(`(?m)(^|[{,])\s*["']?(openapi|swagger)["']?\s*:\s*["']?[23]\.`)
+
+// maxSniffBytes bounds how much of a file IsSpec looks at; the version key is near the top.
+const maxSniffBytes = 64 * 1024
+
+// IsSpec reports whether a YAML or JSON document declares an OpenAPI or Swagger version,
+// as a spec does at its top level. Parse confirms it.
+func IsSpec(content []byte) bool {
+	if len(content) > maxSniffBytes {
+		content = content[:maxSniffBytes]
+	}
+	return specVersion.Match(content)
+}
+
+// Parser handles OpenAPI and Swagger specs.
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"openapi"}
+}
+
+// Parse emits an endpoint symbol per path and method. Routes are normalized with
+// parser.NormalizeRoute and qualified without the server's base path, so clients
+// calling through any server match; the reference to the implementing route carries
+// it, matching servers routed with or without it. A document without an openapi or
+// swagger key has no symbols.
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(input.Content, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", input.Path, err)
+	}
+
+	result := &parser.ParseResult{}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return result, nil
+	}
+	root := doc.Content[0]
+	if lookup(root, "openapi") == nil && lookup(root, "swagger") == nil {
+		return result, nil
+	}
+	paths := lookup(root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return result, nil
+	}
+	base := basePath(root)
+
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i], paths.Content[i+1]
+		if !strings.HasPrefix(path.Value, "/") || item.Kind != yaml.MappingNode {
+			continue
+		}
+		route := parser.NormalizeRoute(path.Value)
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			method, op := item.Content[j], item.Content[j+1]
+			if !slices.Contains(operationMethods, strings.ToLower(method.Value)) || op.Kind != yaml.MappingNode {
+				continue
+			}
+			verb := strings.ToUpper(method.Value)
+			operation := verb + " " + route
+			qname := parser.OpenAPIOperationPrefix + operation
+			summary := scalar(op, "summary")
+			if summary == "" {
+				summary = scalar(op, "description")
+			}
+			result.Symbols = append(result.Symbols, parser.Symbol{
+				Name:          operation,
+				QualifiedName: qname,
+				Kind:          "endpoint",
+				Language:      "openapi",
+				StartLine:     method.Line,
+				EndLine:       lastLine(op),
+				Signature:     operation,
+				DocComment:    summary,
+			})
+			// The spec is the contract; the server route with the same method and path implements it
+			served := parser.NormalizeRoute(base + route)
+			result.References = append(result.References, parser.RawReference{
+				FromSymbol:    qname,
+				ToName:        served,
+				ToQualified:   parser.APIRoutePrefix + verb + " " + served,
+				ReferenceType: "calls_api",
+				Line:          method.Line,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// basePath returns the path operations are served under: Swagger's basePath, or the
+// path of the first OpenAPI server URL. Server variables are not expanded.
+func basePath(root *yaml.Node) string {
+	base := scalar(root, "basePath")
+	if servers := lookup(root, "servers"); servers != nil && servers.Kind == yaml.SequenceNode && len(servers.Content) > 0 {
+		base = scalar(servers.Content[0], "url")
+	}
+	if base == "" || strings.Contains(base, "{") {
+		return ""
+	}
+	return strings.TrimRight(parser.NormalizeRoute(base), "/")
+}
+
+// lookup returns the value of key in a mapping node, or nil.
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalar returns the string value of key in a mapping node, or "".
+func scalar(node *yaml.Node, key string) string {
+	if v := lookup(node, key); v != nil && v.Kind == yaml.ScalarNode {
+		return strings.TrimSpace(v.Value)
+	}
+	return ""
+}
+
+// lastLine returns the last line a node's content starts on.
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	for _, c := range node.Content {
+		line = max(line, lastLine(c))
+	}
+	return line
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+const sampleSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      summary: List users
+      responses:
+        "200":
+          description: OK
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+  /users/{id}/:
+    parameters:
+      - name: id
+        in: path
+        required: true
+    get:
+      description: Fetch one user
+      responses:
+        "200":
+          description: OK
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "api/users.yaml", Content: []byte(sampleSpec)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"openapi:GET /users":      "List users",
+		"openapi:POST /users":     "",
+		"openapi:GET /users/{id}": "Fetch one user",
+	}
+	if len(result.Symbols) != len(want) {
+		t.Fatalf("expected %d operations, got %+v", len(want), result.Symbols)
+	}
+	for _, sym := range result.Symbols {
+		doc, ok := want[sym.QualifiedName]
+		if !ok || sym.Kind != "endpoint" || sym.Language != "openapi" || sym.DocComment != doc {
+			t.Errorf("unexpected symbol %+v", sym)
+		}
+	}
+	if sym := result.Symbols[0]; sym.StartLine != 9 || sym.EndLine != 14 || sym.Signature != "GET /users" {
+		t.Errorf("expected GET /users on lines 9-14, got %+v", sym)
+	}
+
+	// Each operation calls the server route implementing it, under the server's base path
+	ref := result.References[2]
+	if ref.FromSymbol != "openapi:GET /users/{id}" || ref.ToName != "/v1/users/{id}" || ref.ToQualified != "route:GET /v1/users/{id}" || ref.ReferenceType != "calls_api" {
+		t.Errorf("unexpected reference %+v", ref)
+	}
+}
+
+func TestParseSwaggerJSON(t *testing.T) {
+	spec := `{
+	"swagger": "2.0",
+	"basePath": "/api",
+	"paths": {
+		"/orders/{orderId}": {"delete": {"summary": "Cancel an order"}}
+	}
+}`
+	result, err := New().Parse(parser.FileInput{Path: "swagger.json", Content: []byte(spec)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 1 || result.Symbols[0].QualifiedName != "openapi:DELETE /orders/{orderId}" || result.Symbols[0].StartLine != 5 {
+		t.Errorf("expected the DELETE operation, got %+v", result.Symbols)
+	}
+	if ref := result.References[0]; ref.ToQualified != "route:DELETE /api/orders/{orderId}" {
+		t.Errorf("expected the reference under basePath /api, got %+v", ref)
+	}
+}
+
+func TestIsSpec(t *testing.T) {
+	for content, want := range map[string]bool{
+		sampleSpec:                                          true,
+		`{"swagger":"2.0","paths":{}}`:                      true,
+		"asyncapi: 2.6.0\nchannels: {}\n":                   false,
+		`{"name": "web", "dependencies": {"openapi": "x"}}`: false,
+		"# notes\nopenapi is our contract format\n":         false,
+	} {
+		if got := IsSpec([]byte(content)); got != want {
+			t.Errorf("IsSpec(%.30q) = %v, want %v", content, got, want)
+		}
+	}
+
+	// A nested version key is not a spec
+	result, err := New().Parse(parser.FileInput{Path: "values.yaml", Content: []byte("docs:\n  openapi: 3.0.0\n")})
+	if err != nil || len(result.Symbols) != 0 {
+		t.Errorf("expected no symbols for a non-spec document, got %+v (%v)", result, err)
+	}
+}
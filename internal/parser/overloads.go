@@ -0,0 +1,42 @@
+package parser
+
+// QualifyOverloads keeps overloaded methods apart. Symbols are stored by qualified name
+// and kind, so methods sharing both would collapse into one: when a file declares more
+// than one method with a qualified name, each gets its NormalizedSignature appended,
+// "Shop.Orders.Find(int)" and "Shop.Orders.Find(string)". References made from such a
+// method are renamed along with it, by the overload whose lines contain them (the first
+// when the reference has no line). Methods without a normalized signature are left as is.
+func QualifyOverloads(symbols []Symbol, refs []RawReference) {
+	overloads := make(map[string][]int)
+	for i, s := range symbols {
+		if s.Kind == "method" && s.NormalizedSignature != "" {
+			overloads[s.QualifiedName] = append(overloads[s.QualifiedName], i)
+		}
+	}
+	for qname, idx := range overloads {
+		if len(idx) < 2 {
+			delete(overloads, qname)
+			continue
+		}
+		for _, i := range idx {
+			symbols[i].QualifiedName = qname + symbols[i].NormalizedSignature
+		}
+	}
+	if len(overloads) == 0 {
+		return
+	}
+	for i := range refs {
+		idx, ok := overloads[refs[i].FromSymbol]
+		if !ok {
+			continue
+		}
+		from := symbols[idx[0]]
+		for _, j := range idx {
+			if refs[i].Line >= symbols[j].StartLine && refs[i].Line <= symbols[j].EndLine {
+				from = symbols[j]
+				break
+			}
+		}
+		refs[i].FromSymbol = from.QualifiedName
+	}
+}
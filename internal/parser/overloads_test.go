@@ -0,0 +1,32 @@
+package parser
+
+import "testing"
+
+func TestQualifyOverloads(t *testing.T) {
+	symbols := []Symbol{
+		{Name: "Orders", QualifiedName: "Shop.Orders", Kind: "class", StartLine: 1, EndLine: 20},
+		{Name: "Find", QualifiedName: "Shop.Orders.Find", Kind: "method", StartLine: 2, EndLine: 5, NormalizedSignature: "(int)"},
+		{Name: "Find", QualifiedName: "Shop.Orders.Find", Kind: "method", StartLine: 7, EndLine: 10, NormalizedSignature: "(string)"},
+		{Name: "Save", QualifiedName: "Shop.Orders.Save", Kind: "method", StartLine: 12, EndLine: 15, NormalizedSignature: "(Order)"},
+	}
+	refs := []RawReference{
+		{FromSymbol: "Shop.Orders.Find", ToName: "Query", ReferenceType: "calls", Line: 8},
+		{FromSymbol: "Shop.Orders.Find", ToName: "Load", ReferenceType: "calls", Line: 3},
+		{FromSymbol: "Shop.Orders.Save", ToName: "Find", ReferenceType: "calls", Line: 13},
+	}
+
+	QualifyOverloads(symbols, refs)
+
+	want := []string{"Shop.Orders", "Shop.Orders.Find(int)", "Shop.Orders.Find(string)", "Shop.Orders.Save"}
+	for i, s := range symbols {
+		if s.QualifiedName != want[i] {
+			t.Errorf("symbol %d: qualified name %q, want %q", i, s.QualifiedName, want[i])
+		}
+	}
+	wantFrom := []string{"Shop.Orders.Find(string)", "Shop.Orders.Find(int)", "Shop.Orders.Save"}
+	for i, r := range refs {
+		if r.FromSymbol != wantFrom[i] {
+			t.Errorf("reference to %s: from %q, want %q", r.ToName, r.FromSymbol, wantFrom[i])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package parsebench
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// Result is the throughput of one parser over one fixture.
+type Result struct {
+	Language string
+	Bytes    int
+	Symbols  int // per parse, children included
+	Runs     int
+	Elapsed  time.Duration // all runs together
+}
+
+// PerParse is the mean time of one parse.
+func (r Result) PerParse() time.Duration {
+	if r.Runs == 0 {
+		return 0
+	}
+	return r.Elapsed / time.Duration(r.Runs)
+}
+
+// SymbolsPerSec is the symbols extracted per second of parsing.
+func (r Result) SymbolsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Symbols*r.Runs) / r.Elapsed.Seconds()
+}
+
+// MBPerSec is the source parsed per second, in MB.
+func (r Result) MBPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Bytes*r.Runs) / (1 << 20) / r.Elapsed.Seconds()
+}
+
+// Input is the parser input for a fixture, with the SQL dialect detected as the parse
+// stage does.
+func Input(f Fixture) parser.FileInput {
+	language := ""
+	if strings.EqualFold(filepath.Ext(f.Path), ".sql") {
+		language = parser.DetectDialect(f.Content)
+	}
+	return parser.FileInput{Path: f.Path, Content: f.Content, Language: language}
+}
+
+// Measure parses f runs times (at least once) with the registry's parser for it.
+func Measure(registry *parser.Registry, f Fixture, runs int) (Result, error) {
+	p := registry.ForFile(f.Path)
+	if p == nil {
+		return Result{}, fmt.Errorf("no parser registered for %s", f.Path)
+	}
+	runs = max(runs, 1)
+	input := Input(f)
+
+	res := Result{Language: f.Language, Bytes: len(f.Content), Runs: runs}
+	start := time.Now()
+	for range runs {
+		out, err := p.Parse(input)
+		if err != nil {
+			return Result{}, fmt.Errorf("parse %s: %w", f.Path, err)
+		}
+		res.Symbols = countSymbols(out.Symbols)
+	}
+	res.Elapsed = time.Since(start)
+	return res, nil
+}
+
+func countSymbols(symbols []parser.Symbol) int {
+	n := len(symbols)
+	for _, sym := range symbols {
+		n += len(sym.Children)
+	}
+	return n
+}
@@ -0,0 +1,28 @@
+package parsebench
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser/builtin"
+)
+
+func BenchmarkParsers(b *testing.B) {
+	registry := builtin.NewRegistry(builtin.Options{})
+	for _, f := range Corpus(1) {
+		p := registry.ForFile(f.Path)
+		input := Input(f)
+		b.Run(f.Language, func(b *testing.B) {
+			b.SetBytes(int64(len(f.Content)))
+			b.ReportAllocs()
+			symbols := 0
+			for b.Loop() {
+				out, err := p.Parse(input)
+				if err != nil {
+					b.Fatal(err)
+				}
+				symbols = countSymbols(out.Symbols)
+			}
+			b.ReportMetric(float64(symbols*b.N)/b.Elapsed().Seconds(), "symbols/s")
+		})
+	}
+}
@@ -0,0 +1,52 @@
+//go:build perf
+
+package parsebench
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/maraichr/lattice/internal/parser/builtin"
+)
+
+// defaultBudget is the longest one parse of a scale-1 fixture may take. Every parser
+// runs well under a quarter of it; override with PARSEBENCH_BUDGET (e.g. "5s") on slow
+// or instrumented (-race) runs.
+const defaultBudget = 2 * time.Second
+
+// TestCorpusWithinBudget fails when a parser slows down enough that one parse of its
+// fixture exceeds the budget, or stops finding symbols in it. It measures wall-clock time,
+// so it only runs with the perf build tag: go test -tags perf ./internal/parser/parsebench/
+func TestCorpusWithinBudget(t *testing.T) {
+	budget := defaultBudget
+	if v := os.Getenv("PARSEBENCH_BUDGET"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			t.Fatalf("PARSEBENCH_BUDGET: %v", err)
+		}
+		budget = d
+	}
+
+	registry := builtin.NewRegistry(builtin.Options{})
+	for _, f := range Corpus(1) {
+		// Best of three, so one slow run on a busy machine does not fail the suite.
+		var best Result
+		for i := 0; i < 3; i++ {
+			res, err := Measure(registry, f, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 || res.Elapsed < best.Elapsed {
+				best = res
+			}
+		}
+		if best.Symbols == 0 {
+			t.Errorf("%s: no symbols extracted from %s", f.Language, f.Path)
+		}
+		if best.Elapsed > budget {
+			t.Errorf("%s: parsing %d KB took %v, over the %v budget (%.0f symbols/s)",
+				f.Language, best.Bytes/1024, best.Elapsed, budget, best.SymbolsPerSec())
+		}
+	}
+}
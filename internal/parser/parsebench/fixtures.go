@@ -0,0 +1,362 @@
+// Package parsebench measures parser throughput over a generated corpus of large,
+// representative source files, one per language. The corpus is deterministic, so
+// timings from different commits are comparable; it backs the parser benchmarks, the
+// time-budget regression test and cmd/parsebench.
+package parsebench
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fixture is one generated source file.
+type Fixture struct {
+	Language string // name the results are reported under
+	Path     string // file name the parser is chosen by
+	Content  []byte
+}
+
+// unitsPerScale is how many repeated blocks (tables, classes, paragraphs...) a fixture
+// holds at scale 1; that is around 100 KB per language, the size of a large
+// hand-written file.
+const unitsPerScale = 200
+
+// Corpus returns one fixture per language with scale times the base size (scale < 1 is 1).
+func Corpus(scale int) []Fixture {
+	n := max(scale, 1) * unitsPerScale
+	return []Fixture{
+		{"tsql", "Orders.sql", tsqlSource(n)},
+		{"pgsql", "orders_pg.sql", pgsqlSource(n)},
+		{"csharp", "OrderServices.cs", csharpSource(n)},
+		{"java", "OrderServices.java", javaSource(n)},
+		{"javascript", "orders.js", javascriptSource(n)},
+		{"typescript", "orders.ts", typescriptSource(n)},
+		{"delphi", "OrderUnit.pas", delphiSource(n)},
+		{"asp", "orders.asp", aspSource(n)},
+		{"terraform", "orders.tf", terraformSource(n)},
+		{"wsdl", "Orders.wsdl", wsdlSource(n)},
+		{"cobol", "ORDERS.cbl", cobolSource(n)},
+	}
+}
+
+func tsqlSource(n int) []byte {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, `CREATE TABLE dbo.Orders%[1]d (
+    OrderID INT IDENTITY(1,1) PRIMARY KEY,
+    CustomerID INT NOT NULL,
+    Total DECIMAL(18,2) NOT NULL,
+    Status NVARCHAR(20) NULL,
+    CreatedAt DATETIME NOT NULL DEFAULT GETDATE()
+);
+GO
+CREATE PROCEDURE dbo.usp_ArchiveOrders%[1]d
+    @Since DATETIME
+AS
+BEGIN
+    SET NOCOUNT ON;
+    DECLARE @Count INT;
+    INSERT INTO dbo.OrdersArchive (OrderID, CustomerID, Total)
+    SELECT o.OrderID, o.CustomerID, o.Total * 1.2
+    FROM dbo.Orders%[1]d o
+    INNER JOIN dbo.Customers c ON c.CustomerID = o.CustomerID
+    WHERE o.CreatedAt < @Since AND ISNULL(o.Status, '') <> 'open';
+    SET @Count = @@ROWCOUNT;
+    UPDATE dbo.Orders%[1]d SET Status = 'archived' WHERE CreatedAt < @Since;
+    EXEC dbo.usp_LogArchive @Count;
+END
+GO
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func pgsqlSource(n int) []byte {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, `CREATE TABLE public.orders_%[1]d (
+    order_id BIGSERIAL PRIMARY KEY,
+    customer_id UUID NOT NULL,
+    total NUMERIC(18,2) NOT NULL,
+    payload JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION public.archive_orders_%[1]d(since TIMESTAMPTZ)
+RETURNS INTEGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    moved INTEGER;
+BEGIN
+    INSERT INTO public.orders_archive (order_id, customer_id, total)
+    SELECT o.order_id, o.customer_id, o.total
+    FROM public.orders_%[1]d o
+    JOIN public.customers c ON c.customer_id = o.customer_id
+    WHERE o.created_at < since;
+    GET DIAGNOSTICS moved = ROW_COUNT;
+    DELETE FROM public.orders_%[1]d WHERE created_at < since;
+    PERFORM public.log_archive(moved);
+    RETURN moved;
+END;
+$$;
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func csharpSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("using System;\nusing System.Net.Http;\nusing Dapper;\n\nnamespace Shop.Orders\n{\n")
+	for i := range n {
+		fmt.Fprintf(&b, `    public class OrderService%[1]d : IOrderService
+    {
+        private readonly HttpClient _http;
+        public int Retries { get; set; }
+
+        public OrderService%[1]d(HttpClient http) { _http = http; }
+
+        public async Task<Order> Load(int id)
+        {
+            var order = await _connection.QueryFirstAsync<Order>("SELECT * FROM dbo.Orders WHERE OrderID = @id", new { id });
+            await _http.GetAsync($"/api/orders/{id}/lines");
+            return order;
+        }
+
+        public void Archive(int id)
+        {
+            _connection.Execute("dbo.usp_ArchiveOrders", new { id }, commandType: CommandType.StoredProcedure);
+            Audit.Write("archived", id);
+        }
+    }
+
+`, i)
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+func javaSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("package com.shop.orders;\n\nimport java.sql.Connection;\nimport java.sql.PreparedStatement;\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, `class OrderRepository%[1]d extends BaseRepository implements Repository {
+    private final Connection conn;
+
+    OrderRepository%[1]d(Connection conn) { this.conn = conn; }
+
+    public Order find(long id) throws Exception {
+        PreparedStatement ps = conn.prepareStatement("SELECT order_id, total FROM orders WHERE order_id = ?");
+        ps.setLong(1, id);
+        return mapper.map(ps.executeQuery());
+    }
+
+    public void archive(long id) throws Exception {
+        conn.prepareCall("{call usp_ArchiveOrders(?)}").execute();
+        audit.record("archive", id);
+    }
+}
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func javascriptSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("import { db } from './db';\nimport axios from 'axios';\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, `export class OrderStore%[1]d {
+  constructor(client) {
+    this.client = client;
+  }
+
+  async load(id) {
+    const res = await fetch(`+"`/api/orders/${id}`"+`);
+    const rows = await db.query('SELECT * FROM orders WHERE order_id = $1', [id]);
+    return { ...(await res.json()), rows };
+  }
+
+  archive(id) {
+    return axios.post('/api/orders/archive', { id });
+  }
+}
+
+export function formatOrder%[1]d(order) {
+  return order.total.toFixed(2);
+}
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func typescriptSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("import { Injectable } from '@angular/core';\nimport { HttpClient } from '@angular/common/http';\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, `export interface Order%[1]d {
+  id: number;
+  total: number;
+  status?: string;
+}
+
+@Injectable({ providedIn: 'root' })
+export class OrderApi%[1]d {
+  constructor(private http: HttpClient) {}
+
+  get(id: number): Observable<Order%[1]d> {
+    return this.http.get<Order%[1]d>(`+"`/api/orders/${id}`"+`);
+  }
+
+  archive(id: number): Promise<void> {
+    return fetch('/api/orders/archive', { method: 'POST', body: JSON.stringify({ id }) }).then(() => undefined);
+  }
+}
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func delphiSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("unit OrderUnit;\n\ninterface\n\nuses SysUtils, Classes, Data.DB;\n\ntype\n")
+	for i := range n {
+		fmt.Fprintf(&b, "  TOrderService%[1]d = class(TBaseService)\n  private\n    FTotal: Currency;\n  public\n    procedure Archive;\n    function Load(Id: Integer): Boolean;\n  end;\n\n", i)
+	}
+	b.WriteString("implementation\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, `procedure TOrderService%[1]d.Archive;
+begin
+  Query.SQL.Text := 'UPDATE Orders SET Status = ''archived'' WHERE OrderID = :Id';
+  Query.ExecSQL;
+end;
+
+function TOrderService%[1]d.Load(Id: Integer): Boolean;
+begin
+  Query.SQL.Text := 'SELECT OrderID, Total FROM Orders WHERE OrderID = :Id';
+  Query.Open;
+  Result := not Query.IsEmpty;
+end;
+
+`, i)
+	}
+	b.WriteString("end.\n")
+	return []byte(b.String())
+}
+
+func aspSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("<%@ Language=\"VBScript\" %>\n<!--#include file=\"inc/db.asp\"-->\n<html>\n<body>\n")
+	for i := range n {
+		fmt.Fprintf(&b, `<%%
+Const PAGE_SIZE_%[1]d = 50
+
+Function LoadOrders%[1]d(customerId)
+    Dim rs
+    Set rs = Server.CreateObject("ADODB.Recordset")
+    rs.Open "SELECT OrderID, Total FROM Orders WHERE CustomerID = " & customerId, conn
+    Set LoadOrders%[1]d = rs
+End Function
+
+Sub ArchiveOrder%[1]d(orderId)
+    conn.Execute "UPDATE Orders SET Status = 'archived' WHERE OrderID = " & orderId
+    conn.Execute "EXEC usp_LogArchive " & orderId
+End Sub
+%%>
+<p>Orders <%%= PAGE_SIZE_%[1]d %%></p>
+`, i)
+	}
+	b.WriteString("</body>\n</html>\n")
+	return []byte(b.String())
+}
+
+func terraformSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString("variable \"db_password\" {}\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, `resource "aws_db_instance" "orders_%[1]d" {
+  identifier     = "orders-%[1]d"
+  engine         = "postgres"
+  instance_class = "db.m5.large"
+  db_name        = "orders%[1]d"
+  password       = var.db_password
+}
+
+resource "aws_lambda_function" "archive_%[1]d" {
+  function_name = "archive-orders-%[1]d"
+  handler       = "index.handler"
+  environment {
+    variables = {
+      DB_HOST = aws_db_instance.orders_%[1]d.address
+    }
+  }
+}
+
+`, i)
+	}
+	return []byte(b.String())
+}
+
+func wsdlSource(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions name="Orders"
+    targetNamespace="http://example.com/orders"
+    xmlns:tns="http://example.com/orders"
+    xmlns:xs="http://www.w3.org/2001/XMLSchema"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/">
+  <wsdl:types>
+    <xs:schema targetNamespace="http://example.com/orders">
+`)
+	for i := range n {
+		fmt.Fprintf(&b, `      <xs:complexType name="Order%[1]d">
+        <xs:sequence>
+          <xs:element name="Id" type="xs:int"/>
+          <xs:element name="Total" type="xs:decimal"/>
+        </xs:sequence>
+      </xs:complexType>
+`, i)
+	}
+	b.WriteString("    </xs:schema>\n  </wsdl:types>\n  <wsdl:portType name=\"OrderPort\">\n")
+	for i := range n {
+		fmt.Fprintf(&b, `    <wsdl:operation name="GetOrder%[1]d">
+      <wsdl:input message="tns:GetOrderRequest"/>
+      <wsdl:output message="tns:GetOrderResponse"/>
+    </wsdl:operation>
+`, i)
+	}
+	b.WriteString("  </wsdl:portType>\n</wsdl:definitions>\n")
+	return []byte(b.String())
+}
+
+func cobolSource(n int) []byte {
+	var b strings.Builder
+	line := func(area string) {
+		fmt.Fprintf(&b, "%06d %s\n", b.Len()%1000000, area)
+	}
+	line("IDENTIFICATION DIVISION.")
+	line("PROGRAM-ID. ORDERS.")
+	line("DATA DIVISION.")
+	line("WORKING-STORAGE SECTION.")
+	line("    COPY ORDREC.")
+	line("    EXEC SQL INCLUDE SQLCA END-EXEC.")
+	line("PROCEDURE DIVISION.")
+	for i := range n {
+		line(fmt.Sprintf("ARCHIVE-%d.", i))
+		line("    EXEC SQL")
+		line("        SELECT TOTAL INTO :WS-TOTAL")
+		line("        FROM ORDERS WHERE ORDER_ID = :WS-ID")
+		line("    END-EXEC.")
+		line("    EXEC SQL")
+		line("        INSERT INTO ORDERS_ARCHIVE (ORDER_ID, TOTAL)")
+		line("        VALUES (:WS-ID, :WS-TOTAL)")
+		line("    END-EXEC.")
+		line("    PERFORM LOG-ARCHIVE.")
+	}
+	line("    GOBACK.")
+	return []byte(b.String())
+}
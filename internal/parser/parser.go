@@ -13,10 +13,12 @@ type Parser interface {
 
 // FileInput represents a file to be parsed.
 type FileInput struct {
-	Path               string
-	Content            []byte
-	Language           string
-	SkipColumnLineage  bool // if true, parsers should not extract column-level lineage (e.g. migration/schema files)
+	Path                   string
+	Content                []byte
+	Language               string
+	SkipColumnLineage      bool // if true, parsers should not extract column-level lineage (e.g. migration/schema files)
+	DetectConditionalCalls bool // if true, parsers tag calls guarded by feature-flag checks as Conditional (best-effort)
+	ImportPath             string // Go: import path of the file's package, from the nearest go.mod; "" if unknown
 }
 
 // ColumnReference represents a column-level data flow relationship.
@@ -34,6 +36,14 @@ type ParseResult struct {
 	Symbols          []Symbol
 	References       []RawReference
 	ColumnReferences []ColumnReference
+	Warnings         []ParseWarning
+}
+
+// ParseWarning records a non-fatal problem encountered while parsing, such as a
+// statement that was abandoned because it exceeded the parser's complexity budget.
+type ParseWarning struct {
+	Line    int
+	Message string
 }
 
 // Symbol represents a code symbol (table, view, procedure, function, etc.)
@@ -48,7 +58,19 @@ type Symbol struct {
 	EndCol        int
 	Signature     string
 	DocComment    string
+	Complexity    int      // 1 + branch points of a function body; 0 where the parser does not measure it
 	Children      []Symbol // e.g., columns within a table
+
+	// NormalizedSignature is a method's parameter types alone, "(int,List<String>)", for
+	// telling overloads apart: no whitespace, names, modifiers or default values. An
+	// optional parameter's type ends in "=" and a variadic one's element type in "...".
+	// "" where the parser does not normalize signatures.
+	NormalizedSignature string
+
+	// ProjectWide marks a symbol many files name or share, such as a Salesforce SObject
+	// or a Go package: it is stored once per project, by the first file that names it,
+	// and kept when that file is indexed again.
+	ProjectWide bool
 }
 
 // RawReference represents an unresolved reference from one symbol to another.
@@ -58,10 +80,27 @@ type RawReference struct {
 	ToQualified   string  // qualified name if available
 	ReferenceType string  // calls, reads_from, writes_to, uses_table, etc.
 	Confidence    float64 // 0 = not set (treated as 1.0), otherwise 0.0-1.0
+	Conditional   bool    // call only taken behind a feature-flag check (see FileInput.DetectConditionalCalls)
+	Mapping       string  // for uses_table: how the table was derived (MappingAttribute, MappingORM, MappingSQL), "" if unknown
+	Entity        string  // for uses_table: the class whose table this is (User for [Table("Users")] or DbSet<User>), "" if none
+	Receiver      string  // for calls: qualified type of the receiver when the parser can tell, "" otherwise
 	Line          int
 	Col           int
+
+	// Args are the static types of a call's arguments, "" where not evident from the
+	// call site, for matching it to an overload's NormalizedSignature. nil when the
+	// parser does not record arguments; empty for a call without any.
+	Args []string
 }
 
+// Table mapping sources, from most to least explicit. The resolver keeps the most
+// explicit of conflicting mappings for an entity.
+const (
+	MappingAttribute = "attribute" // [Table("Users")], @Table(name = "users"), @Entity("users")
+	MappingORM       = "orm"       // DbSet<User>, __tablename__, Meta.db_table, sequelize.define
+	MappingSQL       = "sql"       // table inferred from inline SQL
+)
+
 // FileResult pairs parse results with file metadata for persistence.
 type FileResult struct {
 	ProjectID        uuid.UUID
@@ -73,4 +112,7 @@ type FileResult struct {
 	Symbols          []Symbol
 	References       []RawReference
 	ColumnReferences []ColumnReference
+	Module           string // monorepo module/app the file belongs to ("" when detection is off)
+	Tags             []string       // file labels stored with the file, e.g. "oversized"
+	Warnings         []ParseWarning // non-fatal problems raised while parsing or capping the file
 }
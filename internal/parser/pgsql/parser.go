@@ -1,7 +1,10 @@
 package pgsql
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	pg_query "github.com/pganalyze/pg_query_go/v6"
@@ -27,6 +30,7 @@ func (p *PgSQLParser) Parse(input parser.FileInput) (*parser.ParseResult, error)
 	}
 
 	w := &walker{
+		src:     input.Content,
 		symbols: make([]parser.Symbol, 0),
 		refs:    make([]parser.RawReference, 0),
 		colRefs: make([]parser.ColumnReference, 0),
@@ -44,6 +48,7 @@ func (p *PgSQLParser) Parse(input parser.FileInput) (*parser.ParseResult, error)
 }
 
 type walker struct {
+	src     []byte
 	symbols []parser.Symbol
 	refs    []parser.RawReference
 	colRefs []parser.ColumnReference
@@ -61,6 +66,12 @@ func (w *walker) walkStatement(rawStmt *pg_query.RawStmt) {
 	switch {
 	case node.GetCreateStmt() != nil:
 		w.walkCreateTable(node.GetCreateStmt(), startLine)
+	case node.GetIndexStmt() != nil:
+		w.walkCreateIndex(node.GetIndexStmt(), startLine)
+	case node.GetCreateSeqStmt() != nil:
+		w.walkCreateSequence(node.GetCreateSeqStmt(), w.stmtLine(rawStmt))
+	case node.GetAlterTableStmt() != nil:
+		w.walkAlterTable(node.GetAlterTableStmt())
 	case node.GetViewStmt() != nil:
 		w.walkCreateView(node.GetViewStmt(), startLine)
 	case node.GetCreateFunctionStmt() != nil:
@@ -88,7 +99,8 @@ func (w *walker) walkCreateTable(stmt *pg_query.CreateStmt, startLine int) {
 		StartLine:     startLine + 1,
 	}
 
-	// Extract columns
+	// Extract columns; the table precedes the indexes its constraints declare
+	n := len(w.symbols)
 	for _, elt := range stmt.TableElts {
 		if colDef := elt.GetColumnDef(); colDef != nil {
 			col := parser.Symbol{
@@ -99,12 +111,284 @@ func (w *walker) walkCreateTable(stmt *pg_query.CreateStmt, startLine int) {
 				StartLine:     int(colDef.Location) + 1,
 				EndLine:       int(colDef.Location) + 1,
 			}
+			if colDef.TypeName != nil {
+				col.Signature = columnTypeString(colDef.TypeName)
+			}
 			sym.Children = append(sym.Children, col)
+			w.columnSequenceRefs(stmt.Relation, colDef)
+			for _, c := range colDef.Constraints {
+				con := c.GetConstraint()
+				if con == nil {
+					continue
+				}
+				switch con.Contype {
+				case pg_query.ConstrType_CONSTR_FOREIGN:
+					w.addForeignKeyRefs(name, []string{colDef.Colname}, con)
+				case pg_query.ConstrType_CONSTR_PRIMARY, pg_query.ConstrType_CONSTR_UNIQUE:
+					w.addKeyConstraint(stmt.Relation, []string{colDef.Colname}, con, startLine)
+				}
+			}
+		} else if con := elt.GetConstraint(); con != nil {
+			switch con.Contype {
+			case pg_query.ConstrType_CONSTR_FOREIGN:
+				w.addForeignKeyRefs(name, nodeStrings(con.FkAttrs), con)
+			case pg_query.ConstrType_CONSTR_PRIMARY, pg_query.ConstrType_CONSTR_UNIQUE:
+				w.addKeyConstraint(stmt.Relation, nodeStrings(con.Keys), con, startLine)
+			}
 		}
 	}
 
 	sym.EndLine = sym.StartLine // approximate
-	w.symbols = append(w.symbols, sym)
+	w.symbols = slices.Insert(w.symbols, n, sym)
+}
+
+func (w *walker) walkCreateSequence(stmt *pg_query.CreateSeqStmt, line int) {
+	w.symbols = append(w.symbols, parser.Symbol{
+		Name:          stmt.Sequence.Relname,
+		QualifiedName: rangeVarToQualified(stmt.Sequence),
+		Kind:          "sequence",
+		Language:      "pgsql",
+		StartLine:     line,
+		EndLine:       line,
+	})
+}
+
+// walkAlterTable picks up ALTER TABLE ... ALTER COLUMN ... SET DEFAULT nextval('seq')
+// and ALTER TABLE ... ADD [CONSTRAINT ...] FOREIGN KEY, PRIMARY KEY or UNIQUE, the forms
+// pg_dump emits.
+func (w *walker) walkAlterTable(stmt *pg_query.AlterTableStmt) {
+	if stmt.Relation == nil {
+		return
+	}
+	table := rangeVarToQualified(stmt.Relation)
+	for _, c := range stmt.Cmds {
+		cmd := c.GetAlterTableCmd()
+		if cmd == nil || cmd.Def == nil {
+			continue
+		}
+		switch cmd.Subtype {
+		case pg_query.AlterTableType_AT_ColumnDefault:
+			if seq := nextvalSequence(cmd.Def); seq != "" {
+				w.addSequenceRef(table+"."+cmd.Name, seq, stmt.Relation.Schemaname)
+			}
+		case pg_query.AlterTableType_AT_AddConstraint:
+			con := cmd.Def.GetConstraint()
+			if con == nil {
+				continue
+			}
+			switch con.Contype {
+			case pg_query.ConstrType_CONSTR_FOREIGN:
+				w.addForeignKeyRefs(table, nodeStrings(con.FkAttrs), con)
+			case pg_query.ConstrType_CONSTR_PRIMARY, pg_query.ConstrType_CONSTR_UNIQUE:
+				w.addKeyConstraint(stmt.Relation, nodeStrings(con.Keys), con, int(con.Location))
+			}
+		}
+	}
+}
+
+// addForeignKeyRefs records a foreign_key edge from each referencing column to the
+// column it references. A constraint without a column list references the table's
+// primary key, so the edge targets the table itself.
+func (w *walker) addForeignKeyRefs(table string, columns []string, con *pg_query.Constraint) {
+	if con.Pktable == nil {
+		return
+	}
+	refTable := rangeVarToQualified(con.Pktable)
+	refColumns := nodeStrings(con.PkAttrs)
+	for i, col := range columns {
+		ref := parser.RawReference{
+			FromSymbol:    table + "." + col,
+			ToName:        con.Pktable.Relname,
+			ToQualified:   refTable,
+			ReferenceType: "foreign_key",
+		}
+		if i < len(refColumns) {
+			ref.ToName = refColumns[i]
+			ref.ToQualified = refTable + "." + refColumns[i]
+		}
+		w.refs = append(w.refs, ref)
+	}
+}
+
+// walkCreateIndex records CREATE INDEX as an index symbol on its table. An unnamed index
+// takes the name PostgreSQL would give it, <table>_<column>_idx.
+func (w *walker) walkCreateIndex(stmt *pg_query.IndexStmt, startLine int) {
+	if stmt.Relation == nil {
+		return
+	}
+	var columns []string
+	for _, param := range stmt.IndexParams {
+		elem := param.GetIndexElem()
+		if elem == nil {
+			continue
+		}
+		if elem.Name == "" {
+			columns = append(columns, "(expression)")
+			continue
+		}
+		columns = append(columns, elem.Name)
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	name := stmt.Idxname
+	if name == "" {
+		name = stmt.Relation.Relname + "_" + strings.Trim(columns[0], "()") + "_idx"
+	}
+	var modifiers []string
+	if stmt.Unique {
+		modifiers = append(modifiers, "unique")
+	}
+	if stmt.AccessMethod != "" && stmt.AccessMethod != "btree" {
+		modifiers = append(modifiers, stmt.AccessMethod)
+	}
+	w.addIndex(rangeVarToQualified(stmt.Relation), name, strings.Join(modifiers, " "), columns, startLine+1)
+}
+
+// addKeyConstraint records the index enforcing a PRIMARY KEY or UNIQUE constraint, named
+// as PostgreSQL names it when the constraint is unnamed: <table>_pkey or <table>_<columns>_key.
+func (w *walker) addKeyConstraint(table *pg_query.RangeVar, columns []string, con *pg_query.Constraint, startLine int) {
+	if len(columns) == 0 {
+		return
+	}
+	modifier := "unique"
+	name := con.Conname
+	if con.Contype == pg_query.ConstrType_CONSTR_PRIMARY {
+		modifier = "primary key"
+		if name == "" {
+			name = table.Relname + "_pkey"
+		}
+	}
+	if name == "" {
+		name = table.Relname + "_" + strings.Join(columns, "_") + "_key"
+	}
+	w.addIndex(rangeVarToQualified(table), name, modifier, columns, startLine+1)
+}
+
+// addIndex records an index symbol, qualified by its table, with an indexes reference to
+// its leading key column: the column a lookup can seek on without the others. An index
+// led by an expression serves no plain column.
+func (w *walker) addIndex(table, name, modifiers string, columns []string, line int) {
+	qname := table + "." + name
+	w.symbols = append(w.symbols, parser.Symbol{
+		Name:          name,
+		QualifiedName: qname,
+		Kind:          "index",
+		Language:      "pgsql",
+		StartLine:     line,
+		EndLine:       line,
+		Signature:     strings.TrimSpace(modifiers + " (" + strings.Join(columns, ", ") + ")"),
+	})
+	if strings.HasPrefix(columns[0], "(") {
+		return
+	}
+	w.refs = append(w.refs, parser.RawReference{
+		FromSymbol:    qname,
+		ToName:        columns[0],
+		ToQualified:   table + "." + columns[0],
+		ReferenceType: "indexes",
+	})
+}
+
+// columnSequenceRefs links a column to the sequence backing it: an explicit
+// DEFAULT nextval('seq'), or the implicit <table>_<column>_seq of a serial or identity column.
+func (w *walker) columnSequenceRefs(table *pg_query.RangeVar, colDef *pg_query.ColumnDef) {
+	colName := rangeVarToQualified(table) + "." + colDef.Colname
+	schema := table.Schemaname
+
+	implicit := colDef.TypeName != nil && isSerialType(typeNameToString(colDef.TypeName))
+	if colDef.RawDefault != nil {
+		if seq := nextvalSequence(colDef.RawDefault); seq != "" {
+			w.addSequenceRef(colName, seq, schema)
+		}
+	}
+	for _, c := range colDef.Constraints {
+		con := c.GetConstraint()
+		if con == nil {
+			continue
+		}
+		switch con.Contype {
+		case pg_query.ConstrType_CONSTR_DEFAULT:
+			if seq := nextvalSequence(con.RawExpr); seq != "" {
+				w.addSequenceRef(colName, seq, schema)
+			}
+		case pg_query.ConstrType_CONSTR_IDENTITY:
+			implicit = true
+		}
+	}
+	if !implicit {
+		return
+	}
+
+	// PostgreSQL creates the backing sequence itself, so there is no CREATE SEQUENCE to parse.
+	seqName := table.Relname + "_" + colDef.Colname + "_seq"
+	qualified := seqName
+	if schema != "" {
+		qualified = schema + "." + seqName
+	}
+	w.symbols = append(w.symbols, parser.Symbol{
+		Name:          seqName,
+		QualifiedName: qualified,
+		Kind:          "sequence",
+		Language:      "pgsql",
+		StartLine:     w.lineAt(colDef.Location),
+		EndLine:       w.lineAt(colDef.Location),
+	})
+	w.addSequenceRef(colName, qualified, schema)
+}
+
+// lineAt converts a pg_query byte offset into the parsed file to a 1-based line number.
+func (w *walker) lineAt(offset int32) int {
+	if offset < 0 {
+		return 1
+	}
+	return bytes.Count(w.src[:min(int(offset), len(w.src))], []byte("\n")) + 1
+}
+
+// stmtLine returns the 1-based line a statement starts on. StmtLocation points just past
+// the previous statement's semicolon, so blank lines and comments before it are skipped.
+func (w *walker) stmtLine(rawStmt *pg_query.RawStmt) int {
+	i := max(int(rawStmt.StmtLocation), 0)
+	for i < len(w.src) {
+		rest := w.src[i:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			i++
+		case bytes.HasPrefix(rest, []byte("--")):
+			end := bytes.IndexByte(rest, '\n')
+			if end < 0 {
+				return w.lineAt(int32(i))
+			}
+			i += end + 1
+		case bytes.HasPrefix(rest, []byte("/*")):
+			end := bytes.Index(rest[2:], []byte("*/"))
+			if end < 0 {
+				return w.lineAt(int32(i))
+			}
+			i += end + 4
+		default:
+			return w.lineAt(int32(i))
+		}
+	}
+	return w.lineAt(rawStmt.StmtLocation)
+}
+
+// addSequenceRef records a references edge from a column to a sequence, qualifying an
+// unqualified sequence name with the table's schema.
+func (w *walker) addSequenceRef(column, seq, schema string) {
+	name := seq
+	if i := strings.LastIndex(seq, "."); i >= 0 {
+		name = seq[i+1:]
+	} else if schema != "" {
+		seq = schema + "." + seq
+	}
+	w.refs = append(w.refs, parser.RawReference{
+		FromSymbol:    column,
+		ToName:        name,
+		ToQualified:   seq,
+		ReferenceType: "references",
+	})
 }
 
 func (w *walker) walkCreateView(stmt *pg_query.ViewStmt, startLine int) {
@@ -242,6 +526,17 @@ func (w *walker) walkSelect(stmt *pg_query.SelectStmt, context string) {
 	for _, from := range stmt.FromClause {
 		w.extractTableRefs(from, context, "reads_from")
 	}
+	if context == "" {
+		return
+	}
+	scope := make(map[string]string)
+	for _, from := range stmt.FromClause {
+		addTableScope(from, scope)
+	}
+	for _, from := range stmt.FromClause {
+		w.joinPredicates(from, scope, context)
+	}
+	w.predicateColumns(stmt.WhereClause, scope, context, "filter")
 }
 
 func (w *walker) walkInsert(stmt *pg_query.InsertStmt, context string) {
@@ -304,6 +599,13 @@ func (w *walker) walkUpdate(stmt *pg_query.UpdateStmt, context string) {
 				})
 			}
 		}
+
+		scope := make(map[string]string)
+		addTableScope(&pg_query.Node{Node: &pg_query.Node_RangeVar{RangeVar: stmt.Relation}}, scope)
+		for _, from := range stmt.FromClause {
+			addTableScope(from, scope)
+		}
+		w.predicateColumns(stmt.WhereClause, scope, context, "filter")
 	}
 }
 
@@ -316,9 +618,103 @@ func (w *walker) walkDelete(stmt *pg_query.DeleteStmt, context string) {
 			ToQualified:   name,
 			ReferenceType: "writes_to",
 		})
+
+		scope := make(map[string]string)
+		addTableScope(&pg_query.Node{Node: &pg_query.Node_RangeVar{RangeVar: stmt.Relation}}, scope)
+		for _, from := range stmt.UsingClause {
+			addTableScope(from, scope)
+		}
+		w.predicateColumns(stmt.WhereClause, scope, context, "filter")
+	}
+}
+
+// addTableScope adds the tables of a FROM item to scope, by alias or by name when
+// unaliased, for qualifying the columns a condition compares.
+func addTableScope(node *pg_query.Node, scope map[string]string) {
+	if node == nil {
+		return
+	}
+	if rv := node.GetRangeVar(); rv != nil {
+		key := rv.Relname
+		if rv.Alias != nil && rv.Alias.Aliasname != "" {
+			key = rv.Alias.Aliasname
+		}
+		scope[key] = rangeVarToQualified(rv)
+	}
+	if jt := node.GetJoinExpr(); jt != nil {
+		addTableScope(jt.Larg, scope)
+		addTableScope(jt.Rarg, scope)
+	}
+}
+
+// joinPredicates records the columns compared by the ON conditions of a FROM item's joins.
+func (w *walker) joinPredicates(node *pg_query.Node, scope map[string]string, context string) {
+	jt := node.GetJoinExpr()
+	if jt == nil {
+		return
+	}
+	w.joinPredicates(jt.Larg, scope, context)
+	w.joinPredicates(jt.Rarg, scope, context)
+	w.predicateColumns(jt.Quals, scope, context, "join")
+}
+
+// predicateColumns records each column a condition compares as a filter or join column
+// reference from context. Only plain column operands count: a column wrapped in a
+// function call or cast, or compared inside a subquery, is not one an index on it would
+// serve.
+func (w *walker) predicateColumns(node *pg_query.Node, scope map[string]string, context, derivation string) {
+	if node == nil {
+		return
+	}
+	switch {
+	case node.GetBoolExpr() != nil:
+		for _, arg := range node.GetBoolExpr().Args {
+			w.predicateColumns(arg, scope, context, derivation)
+		}
+	case node.GetAExpr() != nil:
+		w.predicateColumns(node.GetAExpr().Lexpr, scope, context, derivation)
+		w.predicateColumns(node.GetAExpr().Rexpr, scope, context, derivation)
+	case node.GetNullTest() != nil:
+		w.predicateColumns(node.GetNullTest().Arg, scope, context, derivation)
+	case node.GetColumnRef() != nil:
+		if col := qualifyScopedColumn(node.GetColumnRef(), scope); col != "" {
+			w.colRefs = append(w.colRefs, parser.ColumnReference{
+				SourceColumn:   col,
+				TargetColumn:   context,
+				DerivationType: derivation,
+				Context:        context,
+			})
+		}
 	}
 }
 
+// qualifyScopedColumn qualifies a column reference with the table it belongs to: by its
+// alias or table name, or the only table in scope for a bare column. It returns "" when
+// the table cannot be told.
+func qualifyScopedColumn(cr *pg_query.ColumnRef, scope map[string]string) string {
+	parts := make([]string, 0, len(cr.Fields))
+	for _, f := range cr.Fields {
+		s := f.GetString_()
+		if s == nil {
+			return "" // t.*
+		}
+		parts = append(parts, s.Sval)
+	}
+	switch len(parts) {
+	case 1:
+		if len(scope) == 1 {
+			for _, table := range scope {
+				return table + "." + parts[0]
+			}
+		}
+	case 2:
+		if table, ok := scope[parts[0]]; ok {
+			return table + "." + parts[1]
+		}
+	}
+	return ""
+}
+
 func (w *walker) extractTableRefs(node *pg_query.Node, context, refType string) {
 	if node == nil || context == "" {
 		return
@@ -562,6 +958,41 @@ func (w *walker) parsePLpgSQLBody(body, context string) {
 
 // Helpers
 
+// nextvalSequence returns the sequence named by a nextval('seq') or nextval('seq'::regclass)
+// default expression, or "" if the expression is anything else.
+func nextvalSequence(node *pg_query.Node) string {
+	if node == nil {
+		return ""
+	}
+	fc := node.GetFuncCall()
+	if fc == nil || len(fc.Funcname) == 0 || len(fc.Args) != 1 {
+		return ""
+	}
+	last := fc.Funcname[len(fc.Funcname)-1].GetString_()
+	if last == nil || !strings.EqualFold(last.Sval, "nextval") {
+		return ""
+	}
+	arg := fc.Args[0]
+	if tc := arg.GetTypeCast(); tc != nil {
+		arg = tc.Arg
+	}
+	if c := arg.GetAConst(); c != nil {
+		if sv := c.GetSval(); sv != nil {
+			return strings.Trim(sv.Sval, `"`)
+		}
+	}
+	return ""
+}
+
+// isSerialType reports whether a column type implies an owned sequence.
+func isSerialType(typeName string) bool {
+	switch strings.ToLower(typeName) {
+	case "serial", "serial2", "serial4", "serial8", "smallserial", "bigserial":
+		return true
+	}
+	return false
+}
+
 func rangeVarToQualified(rv *pg_query.RangeVar) string {
 	if rv.Schemaname != "" {
 		return rv.Schemaname + "." + rv.Relname
@@ -569,6 +1000,35 @@ func rangeVarToQualified(rv *pg_query.RangeVar) string {
 	return rv.Relname
 }
 
+// columnTypeString renders a column's declared type with its modifiers, e.g. varchar(100) or int4[].
+func columnTypeString(tn *pg_query.TypeName) string {
+	typ := typeNameToString(tn)
+	var mods []string
+	for _, m := range tn.Typmods {
+		if c := m.GetAConst(); c != nil && c.GetIval() != nil {
+			mods = append(mods, strconv.Itoa(int(c.GetIval().Ival)))
+		}
+	}
+	if len(mods) > 0 {
+		typ += "(" + strings.Join(mods, ",") + ")"
+	}
+	if len(tn.ArrayBounds) > 0 {
+		typ += "[]"
+	}
+	return typ
+}
+
+// nodeStrings returns the values of a list of String nodes, such as a constraint's column list.
+func nodeStrings(nodes []*pg_query.Node) []string {
+	out := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if s := n.GetString_(); s != nil {
+			out = append(out, s.Sval)
+		}
+	}
+	return out
+}
+
 func typeNameToString(tn *pg_query.TypeName) string {
 	parts := make([]string, 0, len(tn.Names))
 	for _, n := range tn.Names {
@@ -148,3 +148,192 @@ EXECUTE FUNCTION update_timestamp();
 		t.Error("expected calls reference to update_timestamp")
 	}
 }
+
+func TestParseSequenceBackedColumns(t *testing.T) {
+	input := `
+CREATE SEQUENCE public.invoice_no_seq START 1000;
+
+CREATE TABLE public.invoices (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_no BIGINT NOT NULL DEFAULT nextval('invoice_no_seq'::regclass),
+    total NUMERIC(12,2) DEFAULT 0
+);
+
+ALTER TABLE public.invoices ALTER COLUMN total SET DEFAULT nextval('public.total_seq');
+
+-- audit numbering
+CREATE SEQUENCE public.audit_seq;
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sequences := make(map[string]bool)
+	for _, s := range result.Symbols {
+		if s.Kind == "sequence" {
+			sequences[s.QualifiedName] = true
+		}
+		// Explicit sequences sit on their CREATE SEQUENCE line, the implicit one on the
+		// line of the column that declares it
+		wantLines := map[string]int{"public.invoice_no_seq": 2, "public.audit_seq": 13, "public.invoices_id_seq": 5}
+		if want, ok := wantLines[s.QualifiedName]; ok && (s.StartLine != want || s.EndLine != want) {
+			t.Errorf("%s: expected line %d, got %d-%d", s.QualifiedName, want, s.StartLine, s.EndLine)
+		}
+	}
+	for _, want := range []string{"public.invoice_no_seq", "public.audit_seq", "public.invoices_id_seq"} {
+		if !sequences[want] {
+			t.Errorf("expected sequence symbol %s, got %v", want, sequences)
+		}
+	}
+
+	refs := make(map[string]string)
+	for _, r := range result.References {
+		if r.ReferenceType == "references" {
+			refs[r.FromSymbol] = r.ToQualified
+		}
+	}
+	tests := map[string]string{
+		"public.invoices.invoice_no": "public.invoice_no_seq",  // explicit nextval, qualified with the table schema
+		"public.invoices.id":         "public.invoices_id_seq", // implicit serial sequence
+		"public.invoices.total":      "public.total_seq",       // ALTER COLUMN SET DEFAULT
+	}
+	for col, want := range tests {
+		if got := refs[col]; got != want {
+			t.Errorf("%s: expected references edge to %s, got %q", col, want, got)
+		}
+	}
+}
+
+func TestParseColumnTypesAndForeignKeys(t *testing.T) {
+	input := `
+CREATE TABLE public.orders (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES public.customers(id),
+    total NUMERIC(10,2),
+    tags TEXT[],
+    warehouse_id INT,
+    region_code VARCHAR(8),
+    CONSTRAINT fk_region FOREIGN KEY (region_code) REFERENCES regions (code)
+);
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT fk_warehouse FOREIGN KEY (warehouse_id) REFERENCES public.warehouses;
+`
+	result, err := New().Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]string)
+	for _, sym := range result.Symbols {
+		if sym.Kind != "table" {
+			continue
+		}
+		for _, col := range sym.Children {
+			types[col.Name] = col.Signature
+		}
+	}
+	for col, want := range map[string]string{
+		"customer_id": "int4",
+		"total":       "numeric(10,2)",
+		"tags":        "text[]",
+		"region_code": "varchar(8)",
+	} {
+		if types[col] != want {
+			t.Errorf("column %s: expected type %q, got %q", col, want, types[col])
+		}
+	}
+
+	fks := make(map[string]string)
+	for _, ref := range result.References {
+		if ref.ReferenceType == "foreign_key" {
+			fks[ref.FromSymbol] = ref.ToQualified
+		}
+	}
+	for from, to := range map[string]string{
+		"public.orders.customer_id":  "public.customers.id",
+		"public.orders.region_code":  "regions.code",
+		"public.orders.warehouse_id": "public.warehouses",
+	} {
+		if fks[from] != to {
+			t.Errorf("expected foreign key %s -> %s, got %q", from, to, fks[from])
+		}
+	}
+}
+
+func TestIndexesAndPredicateColumns(t *testing.T) {
+	input := `
+CREATE TABLE orders (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+ALTER TABLE ONLY customers ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+CREATE INDEX ON orders (customer_id, created_at);
+CREATE UNIQUE INDEX orders_lower_status ON orders (lower(status));
+
+CREATE VIEW open_orders AS
+SELECT o.id, c.name
+FROM orders o
+JOIN customers c ON c.id = o.customer_id
+WHERE o.status = 'open' AND lower(o.created_at::text) LIKE '2024%';
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signatures := make(map[string]string)
+	for _, sym := range result.Symbols {
+		if sym.Kind == "index" {
+			signatures[sym.QualifiedName] = sym.Signature
+		}
+	}
+	want := map[string]string{
+		"orders.orders_pkey":            "primary key (id)",
+		"customers.customers_pkey":      "primary key (id)",
+		"orders.orders_customer_id_idx": "(customer_id, created_at)",
+		"orders.orders_lower_status":    "unique ((expression))",
+	}
+	for name, sig := range want {
+		if signatures[name] != sig {
+			t.Errorf("index %s: expected signature %q, got %q", name, sig, signatures[name])
+		}
+	}
+
+	indexed := make(map[string]bool)
+	for _, ref := range result.References {
+		if ref.ReferenceType == "indexes" {
+			indexed[ref.ToQualified] = true
+		}
+	}
+	if !indexed["orders.customer_id"] || !indexed["customers.id"] || len(indexed) != 3 {
+		t.Errorf("expected indexes refs to orders.id, orders.customer_id and customers.id, got %v", indexed)
+	}
+
+	predicates := make(map[string]string)
+	for _, ref := range result.ColumnReferences {
+		if ref.DerivationType == "filter" || ref.DerivationType == "join" {
+			if ref.TargetColumn != "open_orders" {
+				t.Errorf("expected predicate columns used by the view, got %+v", ref)
+			}
+			predicates[ref.SourceColumn] = ref.DerivationType
+		}
+	}
+	wantPredicates := map[string]string{
+		"customers.id":       "join",
+		"orders.customer_id": "join",
+		"orders.status":      "filter",
+	}
+	if len(predicates) != len(wantPredicates) {
+		t.Errorf("expected predicate columns %v, got %v", wantPredicates, predicates)
+	}
+	for col, derivation := range wantPredicates {
+		if predicates[col] != derivation {
+			t.Errorf("expected %s as a %s column, got %q", col, derivation, predicates[col])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// RegistryPool hands parser registries to concurrent parse tasks. Tree-sitter parsers
+// are not safe for concurrent use, so each task holds a registry of its own for as long
+// as it parses. Registries are created on demand, at most size of them, and reused once
+// returned; a task asking while all are in use waits for one.
+type RegistryPool struct {
+	newRegistry func() *Registry
+	idle        chan *Registry
+	tokens      chan struct{} // one per registry that may still be created
+	created     atomic.Int64
+}
+
+// NewRegistryPool returns a pool of at most size registries built by newRegistry
+// (size < 1 is treated as 1).
+func NewRegistryPool(size int, newRegistry func() *Registry) *RegistryPool {
+	if size < 1 {
+		size = 1
+	}
+	p := &RegistryPool{
+		newRegistry: newRegistry,
+		idle:        make(chan *Registry, size),
+		tokens:      make(chan struct{}, size),
+	}
+	for i := 0; i < size; i++ {
+		p.tokens <- struct{}{}
+	}
+	return p
+}
+
+// Get returns an idle registry, creates one while under the limit, or waits for one to
+// be returned. It fails only when ctx is done first.
+func (p *RegistryPool) Get(ctx context.Context) (*Registry, error) {
+	select {
+	case r := <-p.idle:
+		return r, nil
+	default:
+	}
+	select {
+	case r := <-p.idle:
+		return r, nil
+	case <-p.tokens:
+		p.created.Add(1)
+		return p.newRegistry(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Put returns a registry obtained from Get for reuse.
+func (p *RegistryPool) Put(r *Registry) {
+	p.idle <- r
+}
+
+// Created reports how many registries the pool has built.
+func (p *RegistryPool) Created() int {
+	return int(p.created.Load())
+}
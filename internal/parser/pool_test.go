@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistryPoolBoundsParserInstances(t *testing.T) {
+	const workers, tasks = 3, 24
+	pool := NewRegistryPool(workers, NewRegistry)
+
+	var processed, holding, maxHolding atomic.Int64
+	var inUse sync.Map // *Registry -> struct{}; a registry must never be shared
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := pool.Get(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, shared := inUse.LoadOrStore(r, struct{}{}); shared {
+				t.Error("registry handed to two tasks at once")
+			}
+			n := holding.Add(1)
+			for {
+				m := maxHolding.Load()
+				if n <= m || maxHolding.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			holding.Add(-1)
+			inUse.Delete(r)
+			processed.Add(1)
+			pool.Put(r)
+		}()
+	}
+	wg.Wait()
+
+	if got := processed.Load(); got != tasks {
+		t.Errorf("processed %d tasks, want %d", got, tasks)
+	}
+	if got := pool.Created(); got < 1 || got > workers {
+		t.Errorf("created %d registries, want 1..%d", got, workers)
+	}
+	if got := maxHolding.Load(); got > workers {
+		t.Errorf("%d tasks held a registry at once, want at most %d", got, workers)
+	}
+}
+
+func TestRegistryPoolGetHonoursContext(t *testing.T) {
+	pool := NewRegistryPool(1, NewRegistry)
+	if _, err := pool.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(ctx); err == nil {
+		t.Fatal("Get succeeded with every registry in use")
+	}
+}
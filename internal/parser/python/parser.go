@@ -0,0 +1,468 @@
+package python
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/python"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// Parser implements a tree-sitter based Python parser.
+type Parser struct {
+	tsParser *sitter.Parser
+}
+
+func New() *Parser {
+	p := sitter.NewParser()
+	p.SetLanguage(python.GetLanguage())
+	return &Parser{tsParser: p}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"python"}
+}
+
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	tree, err := p.tsParser.ParseCtx(context.Background(), nil, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	defer tree.Close()
+
+	root := tree.RootNode()
+
+	var symbols []parser.Symbol
+	var refs []parser.RawReference
+
+	for i := 0; i < int(root.ChildCount()); i++ {
+		child := root.Child(i)
+		switch child.Type() {
+		case "import_statement", "import_from_statement":
+			refs = append(refs, extractImports(child, input.Content)...)
+
+		case "expression_statement":
+			symbols = append(symbols, extractAssignments(child, input.Content)...)
+
+		default:
+			syms, rfs := extractDefinition(child, input.Content, "", false)
+			symbols = append(symbols, syms...)
+			refs = append(refs, rfs...)
+		}
+	}
+
+	// Post-extraction pass: detect SQLAlchemy and Django table declarations
+	refs = append(refs, extractDatabaseRefs(root, input.Content, symbols)...)
+
+	return &parser.ParseResult{
+		Symbols:    symbols,
+		References: refs,
+	}, nil
+}
+
+// extractDefinition extracts a function or class, unwrapping decorators. Functions
+// directly inside a class are methods.
+func extractDefinition(node *sitter.Node, src []byte, scope string, inClass bool) ([]parser.Symbol, []parser.RawReference) {
+	if node.Type() == "decorated_definition" {
+		def := node.ChildByFieldName("definition")
+		if def == nil {
+			return nil, nil
+		}
+		syms, refs := extractDefinition(def, src, scope, inClass)
+		// The definition spans its decorators.
+		if len(syms) > 0 {
+			syms[0].StartLine = int(node.StartPoint().Row) + 1
+		}
+		return syms, refs
+	}
+
+	switch node.Type() {
+	case "function_definition":
+		return []parser.Symbol{extractFunction(node, src, scope, inClass)}, nil
+	case "class_definition":
+		return extractClass(node, src, scope)
+	}
+	return nil, nil
+}
+
+func extractFunction(node *sitter.Node, src []byte, scope string, inClass bool) parser.Symbol {
+	name := ""
+	if n := node.ChildByFieldName("name"); n != nil {
+		name = n.Content(src)
+	}
+	sig := ""
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		sig = params.Content(src)
+	}
+	if ret := node.ChildByFieldName("return_type"); ret != nil {
+		sig += " -> " + ret.Content(src)
+	}
+
+	kind := "function"
+	if inClass {
+		kind = "method"
+	}
+	return parser.Symbol{
+		Name:          name,
+		QualifiedName: qualify(scope, name),
+		Kind:          kind,
+		Language:      "python",
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		Signature:     sig,
+	}
+}
+
+func extractClass(node *sitter.Node, src []byte, scope string) ([]parser.Symbol, []parser.RawReference) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil, nil
+	}
+	name := nameNode.Content(src)
+	qname := qualify(scope, name)
+
+	symbols := []parser.Symbol{{
+		Name:          name,
+		QualifiedName: qname,
+		Kind:          "class",
+		Language:      "python",
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+	}}
+	var refs []parser.RawReference
+
+	// Base classes: class Order(Base) / class Order(models.Model, metaclass=ABCMeta)
+	if bases := node.ChildByFieldName("superclasses"); bases != nil {
+		walkChildren(bases, func(base *sitter.Node) {
+			if base.Type() != "identifier" && base.Type() != "attribute" {
+				return
+			}
+			parent := base.Content(src)
+			if parent == "object" {
+				return
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    qname,
+				ToName:        parent[strings.LastIndex(parent, ".")+1:],
+				ToQualified:   parent,
+				ReferenceType: "inherits",
+				Line:          int(base.StartPoint().Row) + 1,
+			})
+		})
+	}
+
+	if body := node.ChildByFieldName("body"); body != nil {
+		walkChildren(body, func(member *sitter.Node) {
+			syms, rfs := extractDefinition(member, src, qname, true)
+			symbols = append(symbols, syms...)
+			refs = append(refs, rfs...)
+		})
+	}
+
+	return symbols, refs
+}
+
+// extractAssignments returns a variable symbol for each name bound by a module-level
+// assignment: MAX_RETRIES = 3, engine = create_engine(url), a, b = 1, 2.
+func extractAssignments(node *sitter.Node, src []byte) []parser.Symbol {
+	var symbols []parser.Symbol
+	walkChildren(node, func(assign *sitter.Node) {
+		if assign.Type() != "assignment" {
+			return
+		}
+		left := assign.ChildByFieldName("left")
+		if left == nil {
+			return
+		}
+		targets := []*sitter.Node{left}
+		if left.Type() == "pattern_list" || left.Type() == "tuple_pattern" {
+			targets = nil
+			walkChildren(left, func(n *sitter.Node) { targets = append(targets, n) })
+		}
+		for _, target := range targets {
+			if target.Type() != "identifier" {
+				continue
+			}
+			name := target.Content(src)
+			sym := parser.Symbol{
+				Name:          name,
+				QualifiedName: name,
+				Kind:          "variable",
+				Language:      "python",
+				StartLine:     int(assign.StartPoint().Row) + 1,
+				EndLine:       int(assign.EndPoint().Row) + 1,
+			}
+			if typ := assign.ChildByFieldName("type"); typ != nil {
+				sym.Signature = typ.Content(src)
+			}
+			symbols = append(symbols, sym)
+		}
+	})
+	return symbols
+}
+
+// extractImports returns an imports reference per imported module or name:
+// import os.path → os.path; from app.models import Order → app.models.Order.
+func extractImports(node *sitter.Node, src []byte) []parser.RawReference {
+	var refs []parser.RawReference
+	line := int(node.StartPoint().Row) + 1
+	add := func(path string) {
+		if path == "" {
+			return
+		}
+		refs = append(refs, parser.RawReference{
+			ToName:        path,
+			ToQualified:   path,
+			ReferenceType: "imports",
+			Line:          line,
+		})
+	}
+
+	module := ""
+	if m := node.ChildByFieldName("module_name"); m != nil {
+		module = m.Content(src)
+	}
+	var names []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) != "name" {
+			continue
+		}
+		child := node.Child(i)
+		if child.Type() == "aliased_import" {
+			child = child.ChildByFieldName("name")
+			if child == nil {
+				continue
+			}
+		}
+		names = append(names, child.Content(src))
+	}
+
+	if node.Type() == "import_statement" {
+		for _, name := range names {
+			add(name)
+		}
+		return refs
+	}
+
+	// from x import * names no symbol, so the module itself is the import.
+	if len(names) == 0 {
+		add(module)
+		return refs
+	}
+	for _, name := range names {
+		if strings.HasSuffix(module, ".") {
+			add(module + name)
+		} else {
+			add(module + "." + name)
+		}
+	}
+	return refs
+}
+
+// extractDatabaseRefs detects ORM table declarations: SQLAlchemy __tablename__ = "t" and
+// Table("t", metadata, ...), Column(ForeignKey("t.id")), and Django's
+// class Meta: db_table = "t".
+func extractDatabaseRefs(root *sitter.Node, src []byte, symbols []parser.Symbol) []parser.RawReference {
+	var refs []parser.RawReference
+
+	findEnclosing := enclosingSymbolFinder(symbols)
+
+	walkTree(root, func(node *sitter.Node) {
+		switch node.Type() {
+		case "class_definition":
+			// Class-level table names belong to the model class itself.
+			ref := extractModelTable(node, src)
+			if ref != nil {
+				ref.FromSymbol = findEnclosing(int(node.StartPoint().Row) + 1)
+				ref.Entity = ref.FromSymbol
+				refs = append(refs, *ref)
+			}
+
+		case "call":
+			fn := node.ChildByFieldName("function")
+			args := node.ChildByFieldName("arguments")
+			if fn == nil || args == nil {
+				return
+			}
+			line := int(node.StartPoint().Row) + 1
+			table := ""
+			switch calleeName(fn, src) {
+			case "Table":
+				table = firstStringArg(args, src)
+			case "ForeignKey":
+				// ForeignKey("customers.id") names table.column
+				if target := firstStringArg(args, src); strings.Contains(target, ".") {
+					table = target[:strings.LastIndex(target, ".")]
+				}
+			}
+			if table == "" {
+				return
+			}
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    findEnclosing(line),
+				ToName:        table,
+				ToQualified:   table,
+				ReferenceType: "uses_table",
+				Mapping:       parser.MappingORM,
+				Line:          line,
+			})
+		}
+	})
+
+	return refs
+}
+
+// extractModelTable returns the table a model class maps to, from a SQLAlchemy
+// __tablename__ attribute or a Django Meta.db_table, or nil.
+func extractModelTable(class *sitter.Node, src []byte) *parser.RawReference {
+	body := class.ChildByFieldName("body")
+	if body == nil {
+		return nil
+	}
+	var ref *parser.RawReference
+	walkChildren(body, func(member *sitter.Node) {
+		if ref != nil {
+			return
+		}
+		switch member.Type() {
+		case "expression_statement":
+			ref = stringAssignment(member, src, "__tablename__")
+		case "class_definition":
+			if name := member.ChildByFieldName("name"); name == nil || name.Content(src) != "Meta" {
+				return
+			}
+			if meta := member.ChildByFieldName("body"); meta != nil {
+				walkChildren(meta, func(stmt *sitter.Node) {
+					if ref == nil && stmt.Type() == "expression_statement" {
+						ref = stringAssignment(stmt, src, "db_table")
+					}
+				})
+			}
+		}
+	})
+	return ref
+}
+
+// stringAssignment returns a uses_table reference for name = "table" in stmt, or nil.
+func stringAssignment(stmt *sitter.Node, src []byte, name string) *parser.RawReference {
+	assign := findChild(stmt, "assignment")
+	if assign == nil {
+		return nil
+	}
+	left, right := assign.ChildByFieldName("left"), assign.ChildByFieldName("right")
+	if left == nil || right == nil || left.Content(src) != name {
+		return nil
+	}
+	table := stringValue(right, src)
+	if table == "" {
+		return nil
+	}
+	return &parser.RawReference{
+		ToName:        table,
+		ToQualified:   table,
+		ReferenceType: "uses_table",
+		Mapping:       parser.MappingORM,
+		Line:          int(assign.StartPoint().Row) + 1,
+	}
+}
+
+// enclosingSymbolFinder returns a lookup from a line to the innermost class, function
+// or method containing it, for FromSymbol resolution.
+func enclosingSymbolFinder(symbols []parser.Symbol) func(line int) string {
+	type symRange struct {
+		qname     string
+		startLine int
+		endLine   int
+	}
+	var ranges []symRange
+	for _, s := range symbols {
+		if s.Kind == "class" || s.Kind == "function" || s.Kind == "method" {
+			ranges = append(ranges, symRange{s.QualifiedName, s.StartLine, s.EndLine})
+		}
+	}
+	return func(line int) string {
+		best := ""
+		bestSpan := 1<<31 - 1
+		for _, r := range ranges {
+			if line >= r.startLine && line <= r.endLine {
+				span := r.endLine - r.startLine
+				if span < bestSpan {
+					bestSpan = span
+					best = r.qname
+				}
+			}
+		}
+		return best
+	}
+}
+
+// --- Helpers ---
+
+// calleeName returns the called name without its receiver: Table, sa.Table → Table.
+func calleeName(fn *sitter.Node, src []byte) string {
+	if fn.Type() == "attribute" {
+		if attr := fn.ChildByFieldName("attribute"); attr != nil {
+			return attr.Content(src)
+		}
+		return ""
+	}
+	return fn.Content(src)
+}
+
+func firstStringArg(args *sitter.Node, src []byte) string {
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() == "keyword_argument" {
+			continue
+		}
+		return stringValue(arg, src)
+	}
+	return ""
+}
+
+// stringValue returns the contents of a plain string literal, or "" for anything else,
+// including f-strings.
+func stringValue(node *sitter.Node, src []byte) string {
+	if node.Type() != "string" || findChild(node, "interpolation") != nil {
+		return ""
+	}
+	text := strings.TrimLeft(node.Content(src), "rRbBuU")
+	for _, q := range []string{`"""`, `'''`, `"`, `'`} {
+		if len(text) >= 2*len(q) && strings.HasPrefix(text, q) && strings.HasSuffix(text, q) {
+			return text[len(q) : len(text)-len(q)]
+		}
+	}
+	return ""
+}
+
+func qualify(scope, name string) string {
+	if scope != "" {
+		return scope + "." + name
+	}
+	return name
+}
+
+func findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+func walkTree(node *sitter.Node, fn func(*sitter.Node)) {
+	fn(node)
+	for i := 0; i < int(node.ChildCount()); i++ {
+		walkTree(node.Child(i), fn)
+	}
+}
+
+func walkChildren(node *sitter.Node, fn func(*sitter.Node)) {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		fn(node.Child(i))
+	}
+}
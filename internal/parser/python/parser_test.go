@@ -0,0 +1,160 @@
+package python
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+func TestModuleSymbolsAndImports(t *testing.T) {
+	src := `
+import os.path
+import json as j
+from app.models import Order, Customer as C
+from . import views
+
+MAX_RETRIES = 3
+timeout: float = 2.5
+
+def retry(fn, attempts=MAX_RETRIES):
+    return fn()
+
+class OrderService(BaseService):
+    @staticmethod
+    def total(order) -> float:
+        return sum(line.amount for line in order.lines)
+
+    def cancel(self, order):
+        pass
+`
+	result, err := New().Parse(parser.FileInput{Path: "app/services.py", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "retry", "function")
+	assertHasSymbol(t, result.Symbols, "OrderService", "class")
+	assertHasSymbol(t, result.Symbols, "OrderService.total", "method")
+	assertHasSymbol(t, result.Symbols, "OrderService.cancel", "method")
+	assertHasSymbol(t, result.Symbols, "MAX_RETRIES", "variable")
+	assertHasSymbol(t, result.Symbols, "timeout", "variable")
+	for _, s := range result.Symbols {
+		if s.QualifiedName == "OrderService.total" {
+			if s.StartLine != 14 || s.Signature != "(order) -> float" {
+				t.Errorf("total starts at %d with signature %q, want 14 and (order) -> float", s.StartLine, s.Signature)
+			}
+		}
+	}
+
+	for _, imp := range []string{"os.path", "json", "app.models.Order", "app.models.Customer", ".views"} {
+		assertHasRef(t, result.References, imp, "imports")
+	}
+	assertHasRef(t, result.References, "BaseService", "inherits")
+}
+
+func TestSQLAlchemyModel(t *testing.T) {
+	src := `
+from sqlalchemy import Column, ForeignKey, Integer, Table
+from app.db import Base, metadata
+
+order_tags = Table("order_tags", metadata, Column("order_id", Integer))
+
+class Order(Base):
+    __tablename__ = "orders"
+
+    id = Column(Integer, primary_key=True)
+    customer_id = Column(Integer, ForeignKey("customers.id"))
+`
+	result, err := New().Parse(parser.FileInput{Path: "app/models.py", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables := filterRefs(result.References, "uses_table")
+	assertRefFrom(t, tables, "orders", "Order")
+	assertRefFrom(t, tables, "customers", "Order")
+	assertRefFrom(t, tables, "order_tags", "")
+}
+
+func TestDjangoMetaDBTable(t *testing.T) {
+	src := `
+from django.db import models
+
+class Invoice(models.Model):
+    number = models.CharField(max_length=20)
+
+    class Meta:
+        db_table = "billing_invoice"
+        ordering = ["number"]
+`
+	result, err := New().Parse(parser.FileInput{Path: "billing/models.py", Content: []byte(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertHasSymbol(t, result.Symbols, "Invoice.Meta", "class")
+	assertRefFrom(t, filterRefs(result.References, "uses_table"), "billing_invoice", "Invoice")
+
+	var inherits *parser.RawReference
+	for i, r := range result.References {
+		if r.ReferenceType == "inherits" {
+			inherits = &result.References[i]
+		}
+	}
+	if inherits == nil || inherits.ToName != "Model" || inherits.ToQualified != "models.Model" {
+		t.Errorf("expected Invoice inherits models.Model, got %+v", inherits)
+	}
+}
+
+// --- helpers ---
+
+func assertHasSymbol(t *testing.T, symbols []parser.Symbol, qname, kind string) {
+	t.Helper()
+	for _, s := range symbols {
+		if s.QualifiedName == qname && s.Kind == kind {
+			return
+		}
+	}
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.QualifiedName + " (" + s.Kind + ")"
+	}
+	t.Errorf("missing symbol %s (%s); have: %v", qname, kind, names)
+}
+
+func filterRefs(refs []parser.RawReference, refType string) []parser.RawReference {
+	var out []parser.RawReference
+	for _, r := range refs {
+		if r.ReferenceType == refType {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func assertHasRef(t *testing.T, refs []parser.RawReference, toName, refType string) {
+	t.Helper()
+	for _, r := range refs {
+		if (r.ToName == toName || r.ToQualified == toName) && r.ReferenceType == refType {
+			return
+		}
+	}
+	t.Errorf("missing ref %s (%s)", toName, refType)
+}
+
+func assertRefFrom(t *testing.T, refs []parser.RawReference, target, from string) {
+	t.Helper()
+	for _, r := range refs {
+		if r.ToName == target {
+			if r.FromSymbol != from {
+				t.Errorf("ref %s from %q, want %q", target, r.FromSymbol, from)
+			}
+			return
+		}
+	}
+	names := make([]string, len(refs))
+	for i, r := range refs {
+		names[i] = r.ToName
+	}
+	t.Errorf("missing ref target %s; have: %v", target, names)
+}
@@ -8,23 +8,86 @@ import (
 
 // Registry maps file extensions to parsers.
 type Registry struct {
-	parsers map[string]Parser // extension -> parser
+	parsers       map[string]Parser         // extension -> parser
+	byContent     map[string][]contentRoute // extension -> parsers of some of its files
+	minConfidence float64                   // content detection threshold for unregistered files
+}
+
+// contentRoute is a parser for the files of a generic format, such as YAML or JSON,
+// that are written in the one document type it parses.
+type contentRoute struct {
+	accepts func(content []byte) bool
+	parser  Parser
 }
 
 func NewRegistry() *Registry {
-	return &Registry{parsers: make(map[string]Parser)}
+	return &Registry{
+		parsers:       make(map[string]Parser),
+		byContent:     make(map[string][]contentRoute),
+		minConfidence: DefaultDetectionConfidence,
+	}
+}
+
+// SetDetectionThreshold sets the minimum confidence content detection needs to route a file
+// to a parser. Values outside (0, 1] keep the default.
+func (r *Registry) SetDetectionThreshold(min float64) {
+	if min > 0 && min <= 1 {
+		r.minConfidence = min
+	}
 }
 
 func (r *Registry) Register(ext string, p Parser) {
 	r.parsers[strings.ToLower(ext)] = p
 }
 
+// RegisterContent registers p for the files with extension ext whose content it
+// accepts, such as OpenAPI specs among .yaml files. Other files of the extension are
+// not parsed. A parser registered for ext with Register takes precedence.
+func (r *Registry) RegisterContent(ext string, accepts func(content []byte) bool, p Parser) {
+	ext = strings.ToLower(ext)
+	r.byContent[ext] = append(r.byContent[ext], contentRoute{accepts: accepts, parser: p})
+}
+
+// RoutesByContent reports whether a parser may accept the file at path by its content.
+func (r *Registry) RoutesByContent(path string) bool {
+	return len(r.byContent[strings.ToLower(filepath.Ext(path))]) > 0
+}
+
+// ForContent returns the first parser registered with RegisterContent for the file's
+// extension that accepts its content, or nil if none does.
+func (r *Registry) ForContent(path string, content []byte) Parser {
+	for _, route := range r.byContent[strings.ToLower(filepath.Ext(path))] {
+		if route.accepts(content) {
+			return route.parser
+		}
+	}
+	return nil
+}
+
 // ForFile returns the parser for a given file path, or nil if none matches.
 func (r *Registry) ForFile(path string) Parser {
 	ext := strings.ToLower(filepath.Ext(path))
 	return r.parsers[ext]
 }
 
+// Detect picks a parser for a file whose extension is not registered by sniffing its
+// content. It returns nil when the language is unknown, has no parser, or was detected
+// below the confidence threshold; the Detection's Reason says which.
+func (r *Registry) Detect(path string, content []byte) (Parser, Detection) {
+	d := DetectLanguage(path, content)
+	switch {
+	case d.Language == "":
+		return nil, d
+	case d.Extension == "" || r.parsers[d.Extension] == nil:
+		d.Reason = fmt.Sprintf("detected %s (%s), no parser registered", d.Language, d.Reason)
+		return nil, d
+	case d.Confidence < r.minConfidence:
+		d.Reason = fmt.Sprintf("detected %s with confidence %.2f, below threshold %.2f", d.Language, d.Confidence, r.minConfidence)
+		return nil, d
+	}
+	return r.parsers[d.Extension], d
+}
+
 // ParseFile detects the parser and parses the file.
 func (r *Registry) ParseFile(input FileInput) (*ParseResult, error) {
 	p := r.ForFile(input.Path)
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HubMethodPrefix marks SignalR hub methods: hub endpoint symbols are qualified as
+// "hub:Namespace.ChatHub.Send" and client invocations reference "hub:Send".
+const HubMethodPrefix = "hub:"
+
+// SOAPOperationPrefix marks SOAP operations: WSDL endpoint symbols are qualified as
+// "soap:OrderService.GetOrder" and C# service contract methods reference "soap:GetOrder".
+const SOAPOperationPrefix = "soap:"
+
+// GraphQLFieldPrefix marks GraphQL root fields: client operations reference
+// "graphql:Query.orders" or "graphql:Mutation.createOrder".
+const GraphQLFieldPrefix = "graphql:"
+
+// APIRoutePrefix marks HTTP routes: server endpoint symbols are qualified as
+// "route:GET /users/{id}" and client requests carry "route:GET /users/42" as their
+// qualified target, beside the bare path.
+const APIRoutePrefix = "route:"
+
+// OpenAPIOperationPrefix marks the operations of an OpenAPI spec, qualified like server
+// routes ("openapi:GET /users/{id}") but kept apart from the endpoints implementing them.
+const OpenAPIOperationPrefix = "openapi:"
+
+// JoinBaseURL prefixes a relative request path with the path of a client's base URL,
+// the way axios and HttpClient combine them. Absolute request URLs are returned unchanged.
+func JoinBaseURL(base, path string) string {
+	if base == "" || strings.Contains(path, "://") {
+		return path
+	}
+	if strings.Contains(base, "://") {
+		u, err := url.Parse(base)
+		if err != nil {
+			return path
+		}
+		base = u.Path
+	}
+	base = strings.TrimRight(base, "/")
+	if base == "" {
+		return path
+	}
+	if !strings.HasPrefix(base, "/") {
+		base = "/" + base
+	}
+	return base + "/" + strings.TrimLeft(path, "/")
+}
+
+// NormalizeRoute reduces a request URL to the route it calls: the scheme and host of
+// an absolute URL, the query string and any trailing slash are dropped, and the path
+// gets a leading slash. Route templates such as {id} are kept as written.
+func NormalizeRoute(raw string) string {
+	route := strings.TrimSpace(raw)
+	if i := strings.Index(route, "://"); i >= 0 {
+		rest := route[i+3:]
+		if j := strings.IndexByte(rest, '/'); j >= 0 {
+			route = rest[j:]
+		} else {
+			route = "/"
+		}
+	}
+	if i := strings.IndexAny(route, "?#"); i >= 0 {
+		route = route[:i]
+	}
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+	if len(route) > 1 {
+		route = strings.TrimRight(route, "/")
+	}
+	return route
+}
+
+// DeclaredRoute tidies a route template as declared, for endpoint signatures shown to
+// readers: it gets a leading slash and loses any trailing one, but unlike
+// NormalizeRoute its parameters keep their constraints, defaults and catch-all or
+// optional markers ({id:int}, {**path}, :id?), so routes that normalize alike can be
+// told apart.
+func DeclaredRoute(route string) string {
+	route = "/" + strings.TrimLeft(strings.TrimSpace(route), "/")
+	if len(route) > 1 {
+		route = strings.TrimRight(route, "/")
+	}
+	return route
+}
+
+// RouteTemplate writes the :name parameters of an Express or NestJS route as {name}
+// templates, the form NormalizeRoute keeps: /users/:id/orders → /users/{id}/orders.
+func RouteTemplate(route string) string {
+	segments := strings.Split(route, "/")
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok && name != "" {
+			segments[i] = "{" + strings.TrimSuffix(name, "?") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
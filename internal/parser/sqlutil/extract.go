@@ -7,7 +7,8 @@ import (
 )
 
 // ExtractTableRefs parses SQL text and returns RawReferences for table names
-// found after FROM, JOIN, INTO, UPDATE, DELETE, MERGE keywords.
+// found after FROM, JOIN, INTO, UPDATE, DELETE, MERGE keywords, and for the
+// stored procedures it calls (see ExtractProcCalls).
 // fromSymbol is the qualified name of the enclosing symbol.
 // defaultSchema is prepended to ToQualified (e.g. "dbo").
 func ExtractTableRefs(sql string, line int, fromSymbol, defaultSchema string) []parser.RawReference {
@@ -46,52 +47,103 @@ func ExtractTableRefs(sql string, line int, fromSymbol, defaultSchema string) []
 		}
 	}
 
-	// Handle EXEC/EXECUTE patterns for stored procedure calls
-	for _, execKw := range []string{"EXEC ", "EXECUTE "} {
+	// Stored procedure calls, qualified with the default schema when unqualified
+	for _, ref := range ExtractProcCalls(sql, line, fromSymbol) {
+		if defaultSchema != "" && !strings.Contains(ref.ToName, ".") {
+			ref.ToQualified = defaultSchema + "." + ref.ToName
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// ExtractProcCalls returns a "calls" reference for each stored procedure a SQL
+// string invokes: EXEC/EXECUTE proc (including EXEC @rc = proc), CALL proc(...)
+// at the start of a statement, and the ODBC/JDBC escapes {call proc(?)} and
+// {? = call proc(?)}. ToQualified is the name as written.
+func ExtractProcCalls(sql string, line int, fromSymbol string) []parser.RawReference {
+	var refs []parser.RawReference
+	upper := strings.ToUpper(sql)
+	for _, kw := range []string{"EXEC", "EXECUTE", "CALL"} {
 		idx := 0
 		for {
-			pos := strings.Index(upper[idx:], execKw)
+			pos := strings.Index(upper[idx:], kw)
 			if pos < 0 {
 				break
 			}
 			absPos := idx + pos
-			// Check word boundary on left
-			if absPos > 0 {
-				ch := upper[absPos-1]
-				if ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '_' {
-					idx = absPos + len(execKw)
+			idx = absPos + len(kw)
+			if !wordAt(upper, absPos, len(kw)) {
+				continue
+			}
+			// CALL is only a statement at the start, after a separator or inside an escape;
+			// elsewhere it is more likely prose than SQL
+			if kw == "CALL" && !statementStart(upper[:absPos]) {
+				continue
+			}
+
+			rest := strings.TrimSpace(sql[idx:])
+			if kw != "CALL" && strings.HasPrefix(rest, "@") {
+				// EXEC @rc = proc: the proc follows the return variable
+				eq := strings.Index(rest, "=")
+				if eq < 0 {
 					continue
 				}
+				rest = strings.TrimSpace(rest[eq+1:])
 			}
-			pos = absPos + len(execKw)
-			rest := strings.TrimSpace(sql[pos:])
-			end := strings.IndexAny(rest, " \t\n\r,;)(")
 			procName := rest
-			if end > 0 {
+			if end := strings.IndexAny(rest, " \t\n\r,;)(}"); end >= 0 {
 				procName = rest[:end]
 			}
-			procName = strings.TrimSpace(procName)
-			if procName != "" && !IsSQLKeyword(procName) {
-				ref := parser.RawReference{
-					FromSymbol:    fromSymbol,
-					ToName:        procName,
-					ReferenceType: "calls",
-					Line:          line,
-				}
-				if defaultSchema != "" && !strings.Contains(procName, ".") {
-					ref.ToQualified = defaultSchema + "." + procName
-				} else {
-					ref.ToQualified = procName
-				}
-				refs = append(refs, ref)
+			if procName == "" || !isIdentStart(procName[0]) || IsSQLKeyword(procName) {
+				continue
 			}
-			idx = pos
+			refs = append(refs, parser.RawReference{
+				FromSymbol:    fromSymbol,
+				ToName:        procName,
+				ToQualified:   procName,
+				ReferenceType: "calls",
+				Line:          line,
+			})
 		}
 	}
-
 	return refs
 }
 
+// wordAt reports whether the n bytes at pos in upper are a whole word.
+func wordAt(upper string, pos, n int) bool {
+	if pos > 0 && isWordByte(upper[pos-1]) {
+		return false
+	}
+	return pos+n >= len(upper) || !isWordByte(upper[pos+n])
+}
+
+// statementStart reports whether before ends where a statement can begin: at the
+// start of the string, after a semicolon, or after the { or ? = of a call escape.
+func statementStart(before string) bool {
+	before = strings.TrimRight(before, " \t\n\r")
+	if before == "" || strings.HasSuffix(before, ";") || strings.HasSuffix(before, "{") {
+		return true
+	}
+	if strings.HasSuffix(before, "=") {
+		before = strings.TrimRight(strings.TrimSuffix(before, "="), " \t")
+		before = strings.TrimRight(strings.TrimSuffix(before, "?"), " \t")
+		return strings.HasSuffix(before, "{")
+	}
+	return false
+}
+
+func isWordByte(ch byte) bool {
+	return ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_'
+}
+
+// isIdentStart reports whether ch can begin a procedure name, including the
+// [bracketed], "quoted" and `backquoted` forms.
+func isIdentStart(ch byte) bool {
+	return isWordByte(ch) || ch == '[' || ch == '"' || ch == '`' || ch == '#'
+}
+
 // inferEdgeType returns the appropriate edge type based on the SQL keyword context.
 func inferEdgeType(keyword string) string {
 	switch keyword {
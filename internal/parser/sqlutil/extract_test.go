@@ -45,6 +45,47 @@ func TestExtractTableRefs_Exec(t *testing.T) {
 	assertHasRef(t, refs, "sp_GetOrders", "calls", "dbo.sp_GetOrders")
 }
 
+func TestExtractProcCalls_Exec(t *testing.T) {
+	refs := ExtractProcCalls("EXEC dbo.usp_GetOrder @id = 1; EXECUTE usp_Audit", 3, "caller")
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 refs, got %d: %v", len(refs), refNames(refs))
+	}
+	assertHasRef(t, refs, "dbo.usp_GetOrder", "calls", "dbo.usp_GetOrder")
+	assertHasRef(t, refs, "usp_Audit", "calls", "usp_Audit")
+	if refs[0].FromSymbol != "caller" || refs[0].Line != 3 {
+		t.Errorf("expected FromSymbol caller on line 3, got %s on %d", refs[0].FromSymbol, refs[0].Line)
+	}
+
+	refs = ExtractProcCalls("EXEC @rc = dbo.usp_Archive", 1, "")
+	assertHasRef(t, refs, "dbo.usp_Archive", "calls", "")
+
+	// Dynamic SQL names no procedure
+	if refs := ExtractProcCalls("EXEC ('SELECT 1'); EXEC sp_executesql @sql", 1, ""); len(refs) != 1 {
+		t.Errorf("expected only sp_executesql, got %v", refNames(refs))
+	}
+}
+
+func TestExtractProcCalls_ODBCEscape(t *testing.T) {
+	refs := ExtractProcCalls("{call usp_ArchiveOrders(?, ?)}", 1, "")
+	assertHasRef(t, refs, "usp_ArchiveOrders", "calls", "usp_ArchiveOrders")
+
+	refs = ExtractProcCalls("{ ? = CALL sales.fn_Total(?) }", 1, "")
+	assertHasRef(t, refs, "sales.fn_Total", "calls", "sales.fn_Total")
+}
+
+func TestExtractProcCalls_Call(t *testing.T) {
+	refs := ExtractProcCalls("CALL refresh_totals(2024)", 1, "")
+	assertHasRef(t, refs, "refresh_totals", "calls", "refresh_totals")
+
+	refs = ExtractProcCalls("SET @x = 1; call audit.log_event('x')", 1, "")
+	assertHasRef(t, refs, "audit.log_event", "calls", "audit.log_event")
+
+	// CALL mid-sentence is not a statement
+	if refs := ExtractProcCalls("please call support", 1, ""); len(refs) != 0 {
+		t.Errorf("expected no refs, got %v", refNames(refs))
+	}
+}
+
 func TestExtractTableRefs_NoSchema(t *testing.T) {
 	refs := ExtractTableRefs("SELECT * FROM users", 1, "", "")
 	if len(refs) != 1 {
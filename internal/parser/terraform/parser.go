@@ -0,0 +1,172 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// Parser is a lightweight Terraform scanner. It does not evaluate HCL; it only picks
+// database and schema resources out of .tf files so the resolver can recognise
+// database qualifiers (e.g. OrdersDb.dbo.Orders) in SQL and application code.
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"terraform"}
+}
+
+// Database is a database or schema declared by a Terraform resource.
+type Database struct {
+	ResourceType string // e.g. aws_db_instance
+	ResourceName string // e.g. orders
+	Kind         string // database or schema
+	Name         string // logical database (or schema) name
+	Identifier   string // instance/cluster identifier, if any
+	Database     string // owning database, for schemas
+	Engine       string
+	Line         int
+}
+
+// databaseResources maps resource types to the attributes holding the logical name and
+// the instance identifier. The first non-empty attribute wins.
+var databaseResources = map[string]struct {
+	kind       string
+	nameAttrs  []string
+	identAttrs []string
+}{
+	"aws_db_instance":        {"database", []string{"db_name", "name"}, []string{"identifier"}},
+	"aws_rds_cluster":        {"database", []string{"database_name"}, []string{"cluster_identifier"}},
+	"aws_redshift_cluster":   {"database", []string{"database_name"}, []string{"cluster_identifier"}},
+	"azurerm_mssql_database": {"database", []string{"name"}, nil},
+	"azurerm_sql_database":   {"database", []string{"name"}, []string{"server_name"}},
+	"google_sql_database":    {"database", []string{"name"}, []string{"instance"}},
+	"postgresql_database":    {"database", []string{"name"}, nil},
+	"mysql_database":         {"database", []string{"name"}, nil},
+	"mssql_database":         {"database", []string{"name"}, nil},
+	"postgresql_schema":      {"schema", []string{"name"}, nil},
+	"snowflake_database":     {"database", []string{"name"}, nil},
+	"snowflake_schema":       {"schema", []string{"name"}, nil},
+}
+
+var (
+	resourceRe  = regexp.MustCompile(`^\s*resource\s+"([A-Za-z0-9_]+)"\s+"([A-Za-z0-9_-]+)"\s*\{`)
+	attributeRe = regexp.MustCompile(`^\s*([A-Za-z0-9_]+)\s*=\s*"([^"$]*)"\s*$`)
+)
+
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	var symbols []parser.Symbol
+	for _, db := range Scan(input.Content) {
+		name := db.Name
+		if name == "" {
+			name = db.Identifier
+		}
+		if name == "" {
+			continue
+		}
+		qualified := name
+		if db.Kind == "schema" && db.Database != "" {
+			qualified = db.Database + "." + name
+		}
+		sig := db.ResourceType + "." + db.ResourceName
+		if db.Identifier != "" && db.Identifier != name {
+			sig += " identifier=" + db.Identifier
+		}
+		if db.Engine != "" {
+			sig += " engine=" + db.Engine
+		}
+		symbols = append(symbols, parser.Symbol{
+			Name:          name,
+			QualifiedName: qualified,
+			Kind:          db.Kind,
+			Language:      "terraform",
+			StartLine:     db.Line,
+			EndLine:       db.Line,
+			Signature:     sig,
+		})
+	}
+	return &parser.ParseResult{Symbols: symbols}, nil
+}
+
+// Scan returns the databases and schemas declared in Terraform source. Only literal
+// string attributes are read; interpolated values (var.x, "${...}") are ignored.
+func Scan(content []byte) []Database {
+	var out []Database
+	var cur *Database
+	var attrs map[string]string
+	depth := 0
+	line := 0
+
+	flush := func() {
+		spec := databaseResources[cur.ResourceType]
+		cur.Kind = spec.kind
+		cur.Name = firstAttr(attrs, spec.nameAttrs)
+		cur.Identifier = firstAttr(attrs, spec.identAttrs)
+		cur.Database = attrs["database"]
+		cur.Engine = attrs["engine"]
+		out = append(out, *cur)
+		cur = nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line++
+		text := stripComment(sc.Text())
+
+		if cur == nil && depth == 0 {
+			if m := resourceRe.FindStringSubmatch(text); m != nil {
+				if _, ok := databaseResources[m[1]]; ok {
+					cur = &Database{ResourceType: m[1], ResourceName: m[2], Line: line}
+					attrs = make(map[string]string)
+				}
+			}
+		} else if cur != nil && depth == 1 {
+			// Only top-level attributes of the resource body; nested blocks are skipped.
+			if m := attributeRe.FindStringSubmatch(text); m != nil {
+				attrs[m[1]] = m[2]
+			}
+		}
+
+		depth += strings.Count(text, "{") - strings.Count(text, "}")
+		if depth <= 0 {
+			depth = 0
+			if cur != nil {
+				flush()
+			}
+		}
+	}
+	return out
+}
+
+func firstAttr(attrs map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := attrs[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// stripComment drops # and // line comments outside string literals.
+func stripComment(s string) string {
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"' && (i == 0 || s[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case s[i] == '#':
+			return s[:i]
+		case s[i] == '/' && i+1 < len(s) && s[i+1] == '/':
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+const sampleTF = `
+variable "db_password" {}
+
+resource "aws_db_instance" "orders" {
+  identifier     = "orders-prod"   # instance name in RDS
+  engine         = "sqlserver-se"
+  instance_class = "db.m5.large"
+  db_name        = "OrdersDb"
+  password       = var.db_password
+
+  tags = {
+    name = "not-a-database"
+  }
+}
+
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+}
+
+resource "postgresql_schema" "billing" {
+  name     = "billing"
+  database = "FinanceDb"
+}
+`
+
+func TestScan_ExtractsDatabaseName(t *testing.T) {
+	dbs := Scan([]byte(sampleTF))
+	if len(dbs) != 2 {
+		t.Fatalf("expected 2 database resources, got %d: %+v", len(dbs), dbs)
+	}
+
+	db := dbs[0]
+	if db.ResourceType != "aws_db_instance" || db.ResourceName != "orders" {
+		t.Errorf("unexpected resource %s.%s", db.ResourceType, db.ResourceName)
+	}
+	if db.Name != "OrdersDb" {
+		t.Errorf("expected database name OrdersDb, got %q", db.Name)
+	}
+	if db.Identifier != "orders-prod" {
+		t.Errorf("expected identifier orders-prod, got %q", db.Identifier)
+	}
+	if db.Engine != "sqlserver-se" {
+		t.Errorf("expected engine sqlserver-se, got %q", db.Engine)
+	}
+
+	schema := dbs[1]
+	if schema.Kind != "schema" || schema.Name != "billing" || schema.Database != "FinanceDb" {
+		t.Errorf("unexpected schema %+v", schema)
+	}
+}
+
+func TestParse_EmitsDatabaseSymbols(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "infra/main.tf", Content: []byte(sampleTF)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 2 {
+		t.Fatalf("expected 2 symbols, got %d", len(result.Symbols))
+	}
+	db := result.Symbols[0]
+	if db.Kind != "database" || db.QualifiedName != "OrdersDb" {
+		t.Errorf("expected database symbol OrdersDb, got %s %s", db.Kind, db.QualifiedName)
+	}
+	if db.Signature != "aws_db_instance.orders identifier=orders-prod engine=sqlserver-se" {
+		t.Errorf("unexpected signature %q", db.Signature)
+	}
+	if got := result.Symbols[1].QualifiedName; got != "FinanceDb.billing" {
+		t.Errorf("expected schema FinanceDb.billing, got %s", got)
+	}
+}
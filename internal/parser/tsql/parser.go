@@ -1,6 +1,8 @@
 package tsql
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/maraichr/lattice/internal/parser"
@@ -13,15 +15,48 @@ type Parser struct {
 	symbols          []parser.Symbol
 	refs             []parser.RawReference
 	colRefs          []parser.ColumnReference
+	warnings         []parser.ParseWarning
 	schema           string // current default schema
 	skipColumnLineage bool  // when true, do not extract column-level lineage (migration/schema files)
+	transient        map[string]*transientTable // CTEs, temp tables and table variables in scope, by lowercased name
+
+	// Complexity budget for the statement currently being parsed.
+	limits    Limits
+	stmtSteps int  // tokens consumed since the current statement started
+	stmtStart int  // token index at which the current statement started
+	stmtLine  int  // line on which the current statement started
+	bailed    bool // budget exceeded; tokens are exhausted until resumeAt
+	resumeAt  int  // token index at which the statement after the abandoned one starts
+}
+
+// Limits bounds the work the parser will spend on a single statement. A deeply
+// nested or malformed statement that exceeds either limit is abandoned with a
+// parse warning and parsing resumes at the next statement. Zero disables a limit.
+type Limits struct {
+	MaxNestingDepth    int // maximum paren / BEGIN...END nesting within a statement
+	MaxStatementTokens int // maximum tokens consumed by a single statement
+}
+
+// DefaultLimits returns limits generous enough for hand-written and generated SQL.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxNestingDepth:    128,
+		MaxStatementTokens: 100000,
+	}
 }
 
 // TSQLParser implements the parser.Parser interface.
-type TSQLParser struct{}
+type TSQLParser struct {
+	limits Limits
+}
 
 func New() *TSQLParser {
-	return &TSQLParser{}
+	return &TSQLParser{limits: DefaultLimits()}
+}
+
+// NewWithLimits creates a T-SQL parser with a custom complexity budget.
+func NewWithLimits(limits Limits) *TSQLParser {
+	return &TSQLParser{limits: limits}
 }
 
 func (t *TSQLParser) Languages() []string {
@@ -40,23 +75,27 @@ func (t *TSQLParser) Parse(input parser.FileInput) (*parser.ParseResult, error)
 	var allSymbols []parser.Symbol
 	var allRefs []parser.RawReference
 	var allColRefs []parser.ColumnReference
+	var allWarnings []parser.ParseWarning
 
 	for _, batch := range batches {
 		p := &Parser{
 			tokens:            batch,
 			schema:            "dbo",
 			skipColumnLineage: input.SkipColumnLineage,
+			limits:            t.limits,
 		}
 		p.parseBatch()
 		allSymbols = append(allSymbols, p.symbols...)
 		allRefs = append(allRefs, p.refs...)
 		allColRefs = append(allColRefs, p.colRefs...)
+		allWarnings = append(allWarnings, p.warnings...)
 	}
 
 	return &parser.ParseResult{
 		Symbols:          allSymbols,
 		References:       allRefs,
 		ColumnReferences: allColRefs,
+		Warnings:         allWarnings,
 	}, nil
 }
 
@@ -94,16 +133,20 @@ func splitBatches(tokens []Token) [][]Token {
 }
 
 func (p *Parser) parseBatch() {
-	for p.pos < len(p.tokens) {
+	for p.pos < len(p.tokens) || p.bailed {
+		p.resume()
 		tok := p.current()
 		if tok.Type == TokenEOF {
 			break
 		}
 
 		if tok.Type == TokenKeyword {
+			p.beginStatement()
 			switch tok.Value {
 			case "CREATE":
 				p.parseCreate()
+			case "WITH":
+				p.parseWith("")
 			case "SELECT":
 				p.parseSelect("")
 			case "INSERT":
@@ -138,7 +181,7 @@ func (p *Parser) parseCreate() {
 	}
 
 	tok := p.current()
-	if tok.Type != TokenKeyword {
+	if tok.Type != TokenKeyword && tok.Type != TokenIdent {
 		return
 	}
 
@@ -155,7 +198,12 @@ func (p *Parser) parseCreate() {
 		p.parseCreateTrigger(startLine)
 	case "TYPE":
 		p.parseCreateType(startLine)
+	case "UNIQUE", "INDEX":
+		p.parseCreateIndex(startLine)
 	default:
+		if tok.Type == TokenIdent && isIndexOption(tok.Value) {
+			p.parseCreateIndex(startLine)
+		}
 		// skip unknown CREATE
 	}
 }
@@ -167,6 +215,13 @@ func (p *Parser) parseCreateTable(startLine int) {
 	if name == "" {
 		return
 	}
+	if isTransientName(name) {
+		// Temp tables live for the session; their columns come from what is inserted
+		if p.matchPunct("(") {
+			p.skipParens()
+		}
+		return
+	}
 
 	sym := parser.Symbol{
 		Name:          unqualify(name),
@@ -176,18 +231,20 @@ func (p *Parser) parseCreateTable(startLine int) {
 		StartLine:     startLine,
 	}
 
-	// Parse columns
+	// Parse columns; the table precedes the indexes its constraints declare
+	n := len(p.symbols)
 	if p.matchPunct("(") {
 		p.advance() // skip (
 		sym.Children = p.parseColumnDefs(name)
 	}
 
 	sym.EndLine = p.currentLine()
-	p.symbols = append(p.symbols, sym)
+	p.symbols = slices.Insert(p.symbols, n, sym)
 }
 
 func (p *Parser) parseColumnDefs(tableName string) []parser.Symbol {
 	var cols []parser.Symbol
+	var constraintName string
 	depth := 1
 
 	for p.pos < len(p.tokens) && depth > 0 {
@@ -198,6 +255,9 @@ func (p *Parser) parseColumnDefs(tableName string) []parser.Symbol {
 
 		if p.matchPunct("(") {
 			depth++
+			if !p.withinDepth(depth) {
+				break
+			}
 			p.advance()
 			continue
 		}
@@ -207,10 +267,39 @@ func (p *Parser) parseColumnDefs(tableName string) []parser.Symbol {
 			continue
 		}
 
-		// Skip constraints
-		if tok.Type == TokenKeyword && (tok.Value == "CONSTRAINT" || tok.Value == "PRIMARY" ||
-			tok.Value == "FOREIGN" || tok.Value == "UNIQUE" || tok.Value == "CHECK" || tok.Value == "INDEX") {
+		// CONSTRAINT <name> introduces a table constraint handled below.
+		if p.matchKeyword("CONSTRAINT") {
+			p.advance() // skip CONSTRAINT
+			constraintName = p.current().Value
+			p.advance() // skip constraint name
+			continue
+		}
+		if p.matchKeyword("FOREIGN") {
+			p.parseTableForeignKey(tableName)
+			p.skipToCommaOrParen(depth)
+			constraintName = ""
+			continue
+		}
+		if p.matchKeyword("PRIMARY") || p.matchKeyword("UNIQUE") {
+			p.parseKeyConstraint(tableName, constraintName, nil)
+			p.skipToCommaOrParen(depth)
+			constraintName = ""
+			continue
+		}
+		if p.matchKeyword("INDEX") {
+			line := p.current().Line
+			p.advance() // skip INDEX
+			name := p.current().Value
+			p.advance()
+			p.parseIndexKey(tableName, name, line)
+			p.skipToCommaOrParen(depth)
+			continue
+		}
+
+		// Skip other constraints
+		if p.matchKeyword("CHECK") {
 			p.skipToCommaOrParen(depth)
+			constraintName = ""
 			continue
 		}
 
@@ -229,7 +318,10 @@ func (p *Parser) parseColumnDefs(tableName string) []parser.Symbol {
 					Language:      "tsql",
 					StartLine:     colLine,
 					EndLine:       colLine,
+					Signature:     p.readColumnType(),
 				})
+				p.skipColumnConstraints(tableName, colName, depth)
+				continue
 			}
 			p.skipToCommaOrParen(depth)
 			continue
@@ -240,6 +332,245 @@ func (p *Parser) parseColumnDefs(tableName string) []parser.Symbol {
 	return cols
 }
 
+// readColumnType reads a column's declared data type with its size, e.g. decimal(10,2)
+// or nvarchar(max). Computed columns (AS expr) have no declared type.
+func (p *Parser) readColumnType() string {
+	if p.matchKeyword("AS") {
+		return ""
+	}
+	typ := p.readQualifiedName()
+	if typ == "" || !p.matchPunct("(") {
+		return strings.ToLower(typ)
+	}
+	return strings.ToLower(typ + "(" + strings.Join(p.readParenList(), ",") + ")")
+}
+
+// skipColumnConstraints skips to the end of a column definition, recording an inline
+// REFERENCES constraint as a foreign key and PRIMARY KEY or UNIQUE as an index.
+func (p *Parser) skipColumnConstraints(tableName, colName string, depth int) {
+	var constraintName string
+	for p.pos < len(p.tokens) && p.current().Type != TokenEOF {
+		if p.matchKeyword("REFERENCES") {
+			p.parseReferences(tableName, []string{colName})
+			continue
+		}
+		if p.matchKeyword("CONSTRAINT") {
+			p.advance()
+			constraintName = p.current().Value
+			p.advance()
+			continue
+		}
+		if p.matchKeyword("PRIMARY") || p.matchKeyword("UNIQUE") {
+			p.parseKeyConstraint(tableName, constraintName, []string{colName})
+			continue
+		}
+		if p.matchPunct(",") && depth <= 1 {
+			p.advance()
+			return
+		}
+		if p.matchPunct(")") {
+			return // don't consume - let caller handle
+		}
+		if p.matchPunct("(") {
+			p.skipParens()
+			continue
+		}
+		p.advance()
+	}
+}
+
+// parseTableForeignKey parses FOREIGN KEY (col, ...) REFERENCES table (col, ...).
+func (p *Parser) parseTableForeignKey(tableName string) {
+	p.advance() // skip FOREIGN
+	if p.matchKeyword("KEY") {
+		p.advance()
+	}
+	if !p.matchPunct("(") {
+		return
+	}
+	cols := p.readParenList()
+	if p.matchKeyword("REFERENCES") {
+		p.parseReferences(tableName, cols)
+	}
+}
+
+// parseReferences parses REFERENCES table [(col, ...)] and records a foreign_key reference
+// from each referencing column to the column it references, or to the table when no
+// column list is given.
+func (p *Parser) parseReferences(tableName string, columns []string) {
+	line := p.currentLine()
+	p.advance() // skip REFERENCES
+	refTable := p.readQualifiedName()
+	if refTable == "" {
+		return
+	}
+	var refColumns []string
+	if p.matchPunct("(") {
+		refColumns = p.readParenList()
+	}
+	for i, col := range columns {
+		ref := parser.RawReference{
+			FromSymbol:    tableName + "." + col,
+			ToName:        unqualify(refTable),
+			ToQualified:   refTable,
+			ReferenceType: "foreign_key",
+			Line:          line,
+		}
+		if i < len(refColumns) {
+			ref.ToName = refColumns[i]
+			ref.ToQualified = refTable + "." + refColumns[i]
+		}
+		p.refs = append(p.refs, ref)
+	}
+}
+
+// parseKeyConstraint parses PRIMARY KEY or UNIQUE, [NON]CLUSTERED, and the key column
+// list of a table constraint, recording the index that enforces it. An inline column
+// constraint has no column list and keys on columns. Unnamed constraints are named
+// PK_<table> and UQ_<table>_<columns>.
+func (p *Parser) parseKeyConstraint(tableName, name string, columns []string) {
+	line := p.current().Line
+	primary := p.matchKeyword("PRIMARY")
+	p.advance() // skip PRIMARY or UNIQUE
+	if p.matchKeyword("KEY") {
+		p.advance()
+	}
+	for p.current().Type == TokenIdent && isIndexOption(p.current().Value) {
+		p.advance()
+	}
+	if p.matchPunct("(") {
+		columns = p.readKeyColumns()
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	modifier := "unique"
+	if primary {
+		modifier = "primary key"
+	}
+	if name == "" {
+		if primary {
+			name = "PK_" + unqualify(tableName)
+		} else {
+			name = "UQ_" + unqualify(tableName) + "_" + strings.Join(columns, "_")
+		}
+	}
+	p.addIndex(tableName, name, modifier, columns, line)
+}
+
+// parseCreateIndex parses CREATE [UNIQUE] [[NON]CLUSTERED] INDEX name ON table (cols).
+// Included columns, filters and storage options are skipped.
+func (p *Parser) parseCreateIndex(startLine int) {
+	var modifiers []string
+	for !p.matchKeyword("INDEX") {
+		tok := p.current()
+		if tok.Type == TokenEOF || (!p.matchKeyword("UNIQUE") && !(tok.Type == TokenIdent && isIndexOption(tok.Value))) {
+			return
+		}
+		modifiers = append(modifiers, strings.ToLower(tok.Value))
+		p.advance()
+	}
+	p.advance() // skip INDEX
+
+	name := p.current().Value
+	p.advance()
+	if !p.matchKeyword("ON") {
+		return
+	}
+	p.advance()
+	table := p.readQualifiedName()
+	if table == "" || isTransientName(table) || !p.matchPunct("(") {
+		return
+	}
+	p.addIndex(table, name, strings.Join(modifiers, " "), p.readKeyColumns(), startLine)
+}
+
+// parseIndexKey parses the [NON]CLUSTERED (cols) of an inline INDEX in CREATE TABLE.
+func (p *Parser) parseIndexKey(tableName, name string, line int) {
+	var modifiers []string
+	for p.current().Type == TokenIdent && isIndexOption(p.current().Value) {
+		modifiers = append(modifiers, strings.ToLower(p.current().Value))
+		p.advance()
+	}
+	if p.matchPunct("(") {
+		p.addIndex(tableName, name, strings.Join(modifiers, " "), p.readKeyColumns(), line)
+	}
+}
+
+// isIndexOption reports whether an identifier is an index kind written before INDEX or
+// after a key constraint.
+func isIndexOption(s string) bool {
+	switch strings.ToUpper(s) {
+	case "CLUSTERED", "NONCLUSTERED":
+		return true
+	}
+	return false
+}
+
+// readKeyColumns reads a parenthesized index key list such as (CustomerID, OrderDate DESC),
+// returning the column names without their sort order.
+func (p *Parser) readKeyColumns() []string {
+	var cols []string
+	expectColumn := true
+	p.advance() // skip (
+	for p.pos < len(p.tokens) && p.current().Type != TokenEOF && !p.matchPunct(")") {
+		if p.matchPunct(",") {
+			expectColumn = true
+		} else if expectColumn {
+			cols = append(cols, p.current().Value)
+			expectColumn = false
+		}
+		p.advance()
+	}
+	if p.matchPunct(")") {
+		p.advance()
+	}
+	return cols
+}
+
+// addIndex records an index symbol, qualified by its table, with an indexes reference to
+// its leading key column: the column a lookup can seek on without the others.
+func (p *Parser) addIndex(table, name, modifiers string, columns []string, line int) {
+	if name == "" || len(columns) == 0 {
+		return
+	}
+	qname := table + "." + name
+	p.symbols = append(p.symbols, parser.Symbol{
+		Name:          name,
+		QualifiedName: qname,
+		Kind:          "index",
+		Language:      "tsql",
+		StartLine:     line,
+		EndLine:       p.currentLine(),
+		Signature:     strings.TrimSpace(modifiers + " (" + strings.Join(columns, ", ") + ")"),
+	})
+	p.refs = append(p.refs, parser.RawReference{
+		FromSymbol:    qname,
+		ToName:        columns[0],
+		ToQualified:   table + "." + columns[0],
+		ReferenceType: "indexes",
+		Line:          line,
+	})
+}
+
+// readParenList reads a parenthesized, comma-separated list of single tokens such as
+// (a, b) or (10, 2), consuming the closing parenthesis.
+func (p *Parser) readParenList() []string {
+	var items []string
+	p.advance() // skip (
+	for p.pos < len(p.tokens) && p.current().Type != TokenEOF && !p.matchPunct(")") {
+		if !p.matchPunct(",") {
+			items = append(items, p.current().Value)
+		}
+		p.advance()
+	}
+	if p.matchPunct(")") {
+		p.advance()
+	}
+	return items
+}
+
 func (p *Parser) parseCreateView(startLine int) {
 	p.advance() // skip VIEW
 	name := p.readQualifiedName()
@@ -261,12 +592,18 @@ func (p *Parser) parseCreateView(startLine int) {
 	}
 	if p.matchKeyword("AS") {
 		p.advance()
+		if p.matchKeyword("WITH") {
+			p.parseWith(name)
+		}
 		colRefsBefore := len(p.colRefs)
 		p.parseSelect(name)
 
 		// Create column children for the view from the SELECT output columns.
 		// This ensures view columns exist as symbols so lineage edges can resolve.
 		for _, ref := range p.colRefs[colRefsBefore:] {
+			if ref.TargetColumn == name {
+				continue // a column the view filters or joins on
+			}
 			parts := strings.Split(ref.TargetColumn, ".")
 			colName := parts[len(parts)-1]
 			sym.Children = append(sym.Children, parser.Symbol{
@@ -431,7 +768,8 @@ func (p *Parser) parseCreateType(startLine int) {
 // parseBody parses the body of a procedure/function/trigger, extracting DML references.
 func (p *Parser) parseBody(context string) {
 	depth := 0
-	for p.pos < len(p.tokens) {
+	for p.pos < len(p.tokens) || p.bailed {
+		p.resume()
 		tok := p.current()
 		if tok.Type == TokenEOF {
 			break
@@ -450,18 +788,25 @@ func (p *Parser) parseBody(context string) {
 				if depth == 0 {
 					return
 				}
-			case "SELECT":
-				p.parseSelect(context)
-			case "INSERT":
-				p.parseInsert(context)
-			case "UPDATE":
-				p.parseUpdate(context)
-			case "DELETE":
-				p.parseDelete(context)
-			case "EXEC", "EXECUTE":
-				p.parseExec(context)
-			case "MERGE":
-				p.parseMerge(context)
+			case "WITH":
+				p.beginStatement()
+				p.parseWith(context)
+			case "SELECT", "INSERT", "UPDATE", "DELETE", "EXEC", "EXECUTE", "MERGE":
+				p.beginStatement()
+				switch tok.Value {
+				case "SELECT":
+					p.parseSelect(context)
+				case "INSERT":
+					p.parseInsert(context)
+				case "UPDATE":
+					p.parseUpdate(context)
+				case "DELETE":
+					p.parseDelete(context)
+				case "EXEC", "EXECUTE":
+					p.parseExec(context)
+				case "MERGE":
+					p.parseMerge(context)
+				}
 			default:
 				p.advance()
 			}
@@ -473,11 +818,58 @@ func (p *Parser) parseBody(context string) {
 
 func (p *Parser) parseSelect(context string) {
 	selectLine := p.current().Line
+	selectItems, fromTables, into := p.readSelect(context)
+
+	// SELECT ... INTO writes its columns to the target table rather than returning them
+	target := context
+	if into != "" {
+		if isTransientName(into) {
+			p.defineTransient(into, nil, selectItems, fromTables)
+			return
+		}
+		p.tableRef(context, into, "writes_to", selectLine)
+		target = into
+		if context == "" {
+			context = into
+		}
+	}
+
+	// Generate column references from parsed select items with qualified source columns
+	if context != "" && !p.skipColumnLineage {
+		for _, item := range selectItems {
+			if item.sourceColumn == "" {
+				continue
+			}
+			for _, src := range p.lineageSources(qualifyColumn(item.sourceColumn, fromTables), item.derivationType) {
+				p.colRefs = append(p.colRefs, parser.ColumnReference{
+					SourceColumn:   src.source,
+					TargetColumn:   target + "." + item.alias,
+					DerivationType: src.derivation,
+					Expression:     item.expression,
+					Context:        context,
+					Line:           selectLine,
+				})
+			}
+		}
+	}
+}
+
+// readSelect reads a SELECT through its FROM, JOIN and WHERE clauses, recording the
+// tables it reads and the columns its conditions compare, and returns its output columns with the alias→table map that qualifies them and
+// the target of a SELECT ... INTO, if any. The statement ends at a semicolon, UNION, or
+// the keyword starting the next statement.
+func (p *Parser) readSelect(context string) ([]selectItem, map[string]string, string) {
 	p.advance() // skip SELECT
 
 	// Parse select columns before FROM
 	selectItems := p.parseSelectColumns()
 
+	var into string
+	if p.matchKeyword("INTO") {
+		p.advance()
+		into = p.readQualifiedName()
+	}
+
 	// Collect FROM tables with aliases for column qualification
 	fromTables := make(map[string]string)
 	if p.matchKeyword("FROM") {
@@ -486,7 +878,7 @@ func (p *Parser) parseSelect(context string) {
 	}
 
 	// Process JOINs — also collect table aliases
-	for p.pos < len(p.tokens) && !p.matchPunct(";") && !p.matchKeyword("UNION") {
+	for p.pos < len(p.tokens) && !p.matchPunct(";") && !p.matchKeyword("UNION") && !p.atStatementStart() {
 		if p.matchKeyword("JOIN") || p.matchKeyword("INNER") || p.matchKeyword("LEFT") || p.matchKeyword("RIGHT") || p.matchKeyword("CROSS") || p.matchKeyword("OUTER") || p.matchKeyword("FULL") {
 			// Advance past join type keywords until we get past JOIN
 			for p.matchKeyword("INNER") || p.matchKeyword("LEFT") || p.matchKeyword("RIGHT") || p.matchKeyword("CROSS") || p.matchKeyword("OUTER") || p.matchKeyword("FULL") || p.matchKeyword("JOIN") {
@@ -499,41 +891,290 @@ func (p *Parser) parseSelect(context string) {
 			name, alias := p.readTableWithAlias()
 			if name != "" {
 				fromTables[strings.ToLower(alias)] = name
-				if context != "" {
-					p.refs = append(p.refs, parser.RawReference{
-						FromSymbol:    context,
-						ToName:        unqualify(name),
-						ToQualified:   name,
-						ReferenceType: "joins",
-						Line:          p.currentLine(),
-					})
-				}
+				p.tableRef(context, name, "joins", p.currentLine())
+			}
+		} else if p.matchKeyword("ON") || p.matchKeyword("WHERE") {
+			derivation := "join"
+			if p.matchKeyword("WHERE") {
+				derivation = "filter"
 			}
+			line := p.current().Line
+			p.advance()
+			p.readPredicate(context, derivation, fromTables, line)
 		} else if p.matchPunct("(") {
 			p.skipParens()
 		} else {
 			p.advance()
 		}
 	}
+	return selectItems, fromTables, into
+}
 
-	// Generate column references from parsed select items with qualified source columns
-	if context != "" && !p.skipColumnLineage {
-		for _, item := range selectItems {
-			if item.sourceColumn == "" {
-				continue
+// readPredicate reads a join condition or WHERE clause, recording each column it
+// compares as a join or filter column reference from context. Only plain column operands
+// count: a column wrapped in a function call, listed in a subquery, or belonging to a
+// transient table is not one an index on a permanent table would serve. The condition
+// ends at the next clause or statement.
+func (p *Parser) readPredicate(context, derivation string, fromTables map[string]string, line int) {
+	seen := make(map[string]bool)
+	depth := 0
+	var prev Token
+	for p.pos < len(p.tokens) && !p.matchPunct(";") && !p.atStatementStart() {
+		tok := p.current()
+		if tok.Type == TokenEOF || (depth == 0 && p.atClauseEnd()) {
+			return
+		}
+		switch {
+		case p.matchPunct("("):
+			// grouping parentheses hold more conditions; anything else is a call or a list
+			grouping := prev.Type == TokenEOF || (prev.Type == TokenKeyword && (prev.Value == "AND" ||
+				prev.Value == "OR" || prev.Value == "NOT" || prev.Value == "WHERE" || prev.Value == "ON"))
+			if !grouping || p.peek(1).Type == TokenKeyword && p.peek(1).Value == "SELECT" {
+				p.skipParens()
+			} else {
+				depth++
+				if !p.withinDepth(depth) {
+					return
+				}
+				p.advance()
 			}
+		case p.matchPunct(")"):
+			if depth == 0 {
+				return
+			}
+			depth--
+			p.advance()
+		case tok.Type == TokenIdent && !strings.HasPrefix(tok.Value, "@"):
+			name := p.readQualifiedName()
+			if p.matchPunct("(") {
+				p.skipParens()
+				break
+			}
+			col := qualifyColumn(name, fromTables)
+			dot := strings.LastIndex(col, ".")
+			key := strings.ToLower(col)
+			if col == name || seen[key] || p.isTransient(col[:dot]) || context == "" || p.skipColumnLineage {
+				break
+			}
+			seen[key] = true
 			p.colRefs = append(p.colRefs, parser.ColumnReference{
-				SourceColumn:   qualifyColumn(item.sourceColumn, fromTables),
-				TargetColumn:   context + "." + item.alias,
-				DerivationType: item.derivationType,
-				Expression:     item.expression,
+				SourceColumn:   col,
+				TargetColumn:   context,
+				DerivationType: derivation,
 				Context:        context,
-				Line:           selectLine,
+				Line:           line,
 			})
+		default:
+			p.advance()
+		}
+		prev = tok
+	}
+}
+
+// atClauseEnd reports whether the current keyword ends a join condition or WHERE clause:
+// the next join, clause or control-of-flow statement.
+func (p *Parser) atClauseEnd() bool {
+	if p.current().Type != TokenKeyword {
+		return false
+	}
+	switch p.current().Value {
+	case "LEFT", "RIGHT":
+		// LEFT(...) and RIGHT(...) are string functions
+		return p.peek(1).Type != TokenPunctuation || p.peek(1).Value != "("
+	case "JOIN", "INNER", "CROSS", "FULL", "OUTER", "ON", "WHERE", "GROUP", "ORDER", "HAVING",
+		"UNION", "EXCEPT", "INTERSECT", "OPTION", "OUTPUT", "FOR", "SET", "IF", "ELSE", "WHILE",
+		"BEGIN", "END", "RETURN", "WITH":
+		return true
+	}
+	return false
+}
+
+// atStatementStart reports whether the current keyword can only begin a new statement,
+// so a statement not terminated by a semicolon ends before it.
+func (p *Parser) atStatementStart() bool {
+	if p.current().Type != TokenKeyword {
+		return false
+	}
+	switch p.current().Value {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "EXEC", "EXECUTE", "DECLARE":
+		return true
+	}
+	return false
+}
+
+// transientTable is a table that exists only while a batch runs: a common table
+// expression, a #temp table or a @table variable. Lineage passes through it to the
+// columns its rows were drawn from, kept here by lowercased column name.
+type transientTable struct {
+	columns map[string][]transientColumn
+}
+
+type transientColumn struct {
+	source     string // qualified source column
+	derivation string
+}
+
+// isTransientName reports whether a table name is a temp table (#t, ##t) or a table
+// variable (@t).
+func isTransientName(name string) bool {
+	return strings.HasPrefix(name, "#") || strings.HasPrefix(name, "@")
+}
+
+// parseWith parses the WITH clause ahead of a statement. Each common table expression
+// is registered in the batch's scope before its query is parsed, so references to it,
+// recursive ones included, are not taken for tables; the query's own reads are recorded
+// against context, and its columns are kept so lineage through the CTE reaches the
+// tables behind it. Anything else introduced by WITH (table hints, options) is skipped.
+func (p *Parser) parseWith(context string) {
+	p.advance() // skip WITH
+	for {
+		tok := p.current()
+		if tok.Type != TokenIdent {
+			return
+		}
+		name := tok.Value
+		p.advance()
+		var columns []string
+		if p.matchPunct("(") {
+			columns = p.readParenList()
+		}
+		if !p.matchKeyword("AS") || p.peek(1).Type != TokenPunctuation || p.peek(1).Value != "(" {
+			return
+		}
+		p.advance() // skip AS
+		start := p.pos + 1
+		p.skipParens()
+		if p.bailed {
+			return
+		}
+		p.defineCTE(context, name, columns, p.tokens[start:p.pos-1])
+
+		if !p.matchPunct(",") {
+			return
 		}
+		p.advance()
 	}
 }
 
+// defineCTE registers a common table expression and parses its query, given as the
+// tokens between its parentheses. The first SELECT names the columns (overridden by an
+// explicit column list); every SELECT, such as the recursive member after UNION ALL,
+// contributes the tables it reads and the columns it draws from.
+func (p *Parser) defineCTE(context, name string, columns []string, body []Token) {
+	p.defineTransient(name, nil, nil, nil)
+	nested := &Parser{
+		tokens:            body,
+		schema:            p.schema,
+		skipColumnLineage: p.skipColumnLineage,
+		limits:            p.limits,
+		transient:         p.transient,
+	}
+	for nested.pos < len(nested.tokens) || nested.bailed {
+		nested.resume()
+		if !nested.matchKeyword("SELECT") {
+			nested.advance()
+			continue
+		}
+		nested.beginStatement()
+		items, fromTables, _ := nested.readSelect(context)
+		if len(columns) == 0 {
+			for _, item := range items {
+				columns = append(columns, item.alias)
+			}
+		}
+		nested.defineTransient(name, columns, items, fromTables)
+	}
+	p.refs = append(p.refs, nested.refs...)
+	p.colRefs = append(p.colRefs, nested.colRefs...)
+	p.warnings = append(p.warnings, nested.warnings...)
+}
+
+// defineTransient registers a transient table, adding the select items written to it as
+// the sources of its columns. Columns are matched to items by position, or by the
+// items' own names when columns is nil. Writing to a transient table again (another
+// INSERT into a #temp table, the recursive member of a CTE) adds further sources.
+func (p *Parser) defineTransient(name string, columns []string, items []selectItem, fromTables map[string]string) {
+	if p.transient == nil {
+		p.transient = make(map[string]*transientTable)
+	}
+	key := strings.ToLower(name)
+	t := p.transient[key]
+	if t == nil {
+		t = &transientTable{columns: make(map[string][]transientColumn)}
+		p.transient[key] = t
+	}
+	for i, item := range items {
+		col := item.alias
+		if columns != nil {
+			if i >= len(columns) {
+				break
+			}
+			col = columns[i]
+		}
+		source := item.sourceColumn
+		if source == "" {
+			source = item.expression
+		}
+		if col == "" || source == "" {
+			continue
+		}
+		key := strings.ToLower(col)
+		for _, src := range p.lineageSources(qualifyColumn(source, fromTables), item.derivationType) {
+			if !slices.Contains(t.columns[key], src) {
+				t.columns[key] = append(t.columns[key], src)
+			}
+		}
+	}
+}
+
+// isTransient reports whether a table name refers to a transient table: a temp table or
+// table variable by its name, or a common table expression in scope.
+func (p *Parser) isTransient(name string) bool {
+	if isTransientName(name) {
+		return true
+	}
+	_, ok := p.transient[strings.ToLower(name)]
+	return ok
+}
+
+// lineageSources maps a column of a transient table to the columns it was drawn from,
+// so lineage skips the staging: #Totals.Total → dbo.Orders.Amount. A direct copy of a
+// derived column takes on that column's derivation. Columns of permanent tables are
+// their own source; columns of a transient table with no known source have none.
+func (p *Parser) lineageSources(col, derivation string) []transientColumn {
+	dot := strings.LastIndex(col, ".")
+	if dot < 0 || !p.isTransient(col[:dot]) {
+		return []transientColumn{{source: col, derivation: derivation}}
+	}
+	t := p.transient[strings.ToLower(col[:dot])]
+	if t == nil {
+		return nil
+	}
+	var out []transientColumn
+	for _, src := range t.columns[strings.ToLower(col[dot+1:])] {
+		if derivation != "direct_copy" {
+			src.derivation = derivation
+		}
+		out = append(out, src)
+	}
+	return out
+}
+
+// tableRef records a reference from context to a table. Transient tables are skipped:
+// the permanent tables behind them were referenced by the statements that filled them.
+func (p *Parser) tableRef(context, name, refType string, line int) {
+	if context == "" || p.isTransient(name) {
+		return
+	}
+	p.refs = append(p.refs, parser.RawReference{
+		FromSymbol:    context,
+		ToName:        unqualify(name),
+		ToQualified:   name,
+		ReferenceType: refType,
+		Line:          line,
+	})
+}
+
 // selectItem represents a parsed SELECT column expression.
 type selectItem struct {
 	sourceColumn   string // source column reference (may be qualified)
@@ -554,8 +1195,8 @@ func (p *Parser) parseSelectColumns() []selectItem {
 			break
 		}
 
-		// Stop at FROM (not inside parens)
-		if parenDepth == 0 && p.matchKeyword("FROM") {
+		// Stop at FROM or SELECT ... INTO (not inside parens)
+		if parenDepth == 0 && (p.matchKeyword("FROM") || p.matchKeyword("INTO")) {
 			break
 		}
 
@@ -584,6 +1225,9 @@ func (p *Parser) parseSelectColumns() []selectItem {
 
 		if p.matchPunct("(") {
 			parenDepth++
+			if !p.withinDepth(parenDepth) {
+				break
+			}
 			currentTokens = append(currentTokens, tok.Value)
 			p.advance()
 			continue
@@ -751,14 +1395,8 @@ func (p *Parser) parseInsert(context string) {
 	}
 
 	targetTable := p.readQualifiedName()
-	if targetTable != "" && context != "" {
-		p.refs = append(p.refs, parser.RawReference{
-			FromSymbol:    context,
-			ToName:        unqualify(targetTable),
-			ToQualified:   targetTable,
-			ReferenceType: "writes_to",
-			Line:          p.current().Line,
-		})
+	if targetTable != "" {
+		p.tableRef(context, targetTable, "writes_to", p.current().Line)
 	}
 
 	// Check for column list: (col1, col2, ...)
@@ -780,18 +1418,17 @@ func (p *Parser) parseInsert(context string) {
 		}
 	}
 
+	// Rows staged in a temp table or table variable: remember where its columns come from
+	if p.matchKeyword("SELECT") && targetTable != "" && p.isTransient(targetTable) {
+		selectItems, fromTables, _ := p.readSelect(context)
+		p.defineTransient(targetTable, targetCols, selectItems, fromTables)
+		return
+	}
+
 	// If followed by SELECT, correlate columns positionally.
 	// Allow both top-level (context="") and in-body (context=procName) INSERT...SELECT.
 	if p.matchKeyword("SELECT") && targetTable != "" && len(targetCols) > 0 {
-		p.advance() // skip SELECT
-		selectItems := p.parseSelectColumns()
-
-		// Read FROM tables for source column qualification
-		fromTables := make(map[string]string)
-		if p.matchKeyword("FROM") {
-			p.advance()
-			fromTables = p.collectFromTables(context, "reads_from")
-		}
+		selectItems, fromTables, _ := p.readSelect(context)
 
 		// Use target table as context for top-level statements
 		effectiveContext := context
@@ -806,14 +1443,16 @@ func (p *Parser) parseInsert(context string) {
 					if srcCol == "" {
 						srcCol = selectItems[i].expression
 					}
-					p.colRefs = append(p.colRefs, parser.ColumnReference{
-						SourceColumn:   qualifyColumn(srcCol, fromTables),
-						TargetColumn:   targetTable + "." + col,
-						DerivationType: selectItems[i].derivationType,
-						Expression:     selectItems[i].expression,
-						Context:        effectiveContext,
-						Line:           insertLine,
-					})
+					for _, src := range p.lineageSources(qualifyColumn(srcCol, fromTables), selectItems[i].derivationType) {
+						p.colRefs = append(p.colRefs, parser.ColumnReference{
+							SourceColumn:   src.source,
+							TargetColumn:   targetTable + "." + col,
+							DerivationType: src.derivation,
+							Expression:     selectItems[i].expression,
+							Context:        effectiveContext,
+							Line:           insertLine,
+						})
+					}
 				}
 			}
 		}
@@ -824,20 +1463,18 @@ func (p *Parser) parseUpdate(context string) {
 	updateLine := p.current().Line
 	p.advance() // skip UPDATE
 	targetTable := p.readQualifiedName()
-	if targetTable != "" && context != "" {
-		p.refs = append(p.refs, parser.RawReference{
-			FromSymbol:    context,
-			ToName:        unqualify(targetTable),
-			ToQualified:   targetTable,
-			ReferenceType: "writes_to",
-			Line:          p.current().Line,
-		})
+	if targetTable != "" {
+		p.tableRef(context, targetTable, "writes_to", p.current().Line)
 	}
 
 	// Parse SET clause for column references
-	if p.matchKeyword("SET") && context != "" && targetTable != "" {
+	if p.matchKeyword("SET") && context != "" && targetTable != "" && !p.isTransient(targetTable) {
 		p.advance()
 		p.parseSetClause(context, targetTable, updateLine)
+		if p.matchKeyword("WHERE") {
+			p.advance()
+			p.readPredicate(context, "filter", map[string]string{strings.ToLower(unqualify(targetTable)): targetTable}, updateLine)
+		}
 	}
 }
 
@@ -886,6 +1523,9 @@ func (p *Parser) parseSetClause(context, targetTable string, line int) {
 			}
 			if p.matchPunct("(") {
 				parenDepth++
+				if !p.withinDepth(parenDepth) {
+					break
+				}
 			}
 			if p.matchPunct(")") {
 				if parenDepth > 0 {
@@ -927,20 +1567,23 @@ func (p *Parser) parseDelete(context string) {
 	}
 
 	name := p.readQualifiedName()
-	if name != "" && context != "" {
-		p.refs = append(p.refs, parser.RawReference{
-			FromSymbol:    context,
-			ToName:        unqualify(name),
-			ToQualified:   name,
-			ReferenceType: "writes_to",
-			Line:          p.current().Line,
-		})
+	if name != "" {
+		p.tableRef(context, name, "writes_to", p.current().Line)
+	}
+	if name != "" && p.matchKeyword("WHERE") {
+		line := p.current().Line
+		p.advance()
+		p.readPredicate(context, "filter", map[string]string{strings.ToLower(unqualify(name)): name}, line)
 	}
 }
 
 func (p *Parser) parseExec(context string) {
 	p.advance() // skip EXEC/EXECUTE
 	name := p.readQualifiedName()
+	if strings.EqualFold(unqualify(name), "sp_executesql") {
+		p.parseExecuteSQL(context)
+		return
+	}
 	if name != "" && context != "" {
 		p.refs = append(p.refs, parser.RawReference{
 			FromSymbol:    context,
@@ -952,6 +1595,45 @@ func (p *Parser) parseExec(context string) {
 	}
 }
 
+// parseExecuteSQL parses the statement argument of sp_executesql as a nested batch whose
+// reads, writes and column lineage are attributed to the enclosing context. Only a literal
+// first argument is followed; @variables and concatenations are dynamic and skipped.
+func (p *Parser) parseExecuteSQL(context string) {
+	// Optional named parameter: @stmt = N'...'
+	if strings.EqualFold(p.current().Value, "@stmt") && p.peek(1).Value == "=" {
+		p.advance()
+		p.advance()
+	}
+	// N'...' lexes as the identifier N followed by the string.
+	if tok := p.current(); tok.Type == TokenIdent && strings.EqualFold(tok.Value, "N") && p.peek(1).Type == TokenString {
+		p.advance()
+	}
+	tok := p.current()
+	if tok.Type != TokenString || len(tok.Value) < 2 {
+		return
+	}
+	p.advance()
+	sql := strings.ReplaceAll(tok.Value[1:len(tok.Value)-1], "''", "'")
+
+	for _, batch := range splitBatches(NewLexer(sql).Tokenize()) {
+		for i := range batch {
+			batch[i].Line += tok.Line - 1
+		}
+		nested := &Parser{
+			tokens:            batch,
+			schema:            p.schema,
+			skipColumnLineage: p.skipColumnLineage,
+			limits:            p.limits,
+		}
+		for nested.pos < len(nested.tokens) && nested.current().Type != TokenEOF {
+			nested.parseBody(context)
+		}
+		p.refs = append(p.refs, nested.refs...)
+		p.colRefs = append(p.colRefs, nested.colRefs...)
+		p.warnings = append(p.warnings, nested.warnings...)
+	}
+}
+
 func (p *Parser) parseMerge(context string) {
 	p.advance() // skip MERGE
 
@@ -960,14 +1642,8 @@ func (p *Parser) parseMerge(context string) {
 	}
 
 	name := p.readQualifiedName()
-	if name != "" && context != "" {
-		p.refs = append(p.refs, parser.RawReference{
-			FromSymbol:    context,
-			ToName:        unqualify(name),
-			ToQualified:   name,
-			ReferenceType: "writes_to",
-			Line:          p.current().Line,
-		})
+	if name != "" {
+		p.tableRef(context, name, "writes_to", p.current().Line)
 	}
 }
 
@@ -980,9 +1656,102 @@ func (p *Parser) current() Token {
 	return p.tokens[p.pos]
 }
 
+// peek returns the token n positions ahead of the current one.
+func (p *Parser) peek(n int) Token {
+	if p.pos+n >= len(p.tokens) {
+		return Token{Type: TokenEOF}
+	}
+	return p.tokens[p.pos+n]
+}
+
 func (p *Parser) advance() {
 	if p.pos < len(p.tokens) {
 		p.pos++
+		p.stmtSteps++
+		if p.limits.MaxStatementTokens > 0 && p.stmtSteps > p.limits.MaxStatementTokens {
+			p.bail(fmt.Sprintf("statement exceeds %d tokens", p.limits.MaxStatementTokens))
+		}
+	}
+}
+
+// beginStatement resets the complexity budget at the start of a statement.
+func (p *Parser) beginStatement() {
+	p.stmtSteps = 0
+	p.stmtStart = p.pos
+	p.stmtLine = p.current().Line
+}
+
+// withinDepth reports whether a nesting depth is inside the budget, abandoning
+// the current statement when it is not.
+func (p *Parser) withinDepth(depth int) bool {
+	if p.limits.MaxNestingDepth > 0 && depth > p.limits.MaxNestingDepth {
+		p.bail(fmt.Sprintf("nesting depth exceeds %d", p.limits.MaxNestingDepth))
+		return false
+	}
+	return true
+}
+
+// bail abandons the current statement: it records a warning, remembers where the
+// next statement starts (see statementEnd), and exhausts the token stream so every
+// loop in the call stack unwinds. The statement loop then calls resume.
+func (p *Parser) bail(reason string) {
+	if p.bailed {
+		return
+	}
+	p.warnings = append(p.warnings, parser.ParseWarning{
+		Line:    p.stmtLine,
+		Message: "statement skipped: " + reason,
+	})
+	p.resumeAt = p.statementEnd()
+	p.pos = len(p.tokens)
+	p.bailed = true
+}
+
+// statementStarts are the keywords that open a new statement, or a BEGIN...END block
+// the body loop counts, when they appear outside parentheses and CASE expressions.
+var statementStarts = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "WITH": true, "SELECT": true,
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "EXEC": true,
+	"EXECUTE": true, "DECLARE": true, "SET": true, "IF": true, "ELSE": true,
+	"WHILE": true, "RETURN": true, "BEGIN": true, "END": true,
+}
+
+// statementEnd returns the token index just past the abandoned statement: past the
+// next ';' or at the next statement-starting keyword, whichever comes first after the
+// current position at the statement's top level. Most T-SQL omits semicolons, so the
+// keyword keeps one abandoned statement from taking the rest of its batch with it.
+func (p *Parser) statementEnd() int {
+	parens, cases := 0, 0
+	for i := p.stmtStart; i < len(p.tokens); i++ {
+		tok := p.tokens[i]
+		top := parens == 0 && cases == 0
+		if i > p.pos || (i == p.pos && i > p.stmtStart) {
+			if top && tok.Type == TokenPunctuation && tok.Value == ";" {
+				return i + 1
+			}
+			if top && tok.Type == TokenKeyword && statementStarts[tok.Value] {
+				return i
+			}
+		}
+		switch {
+		case tok.Type == TokenPunctuation && tok.Value == "(":
+			parens++
+		case tok.Type == TokenPunctuation && tok.Value == ")" && parens > 0:
+			parens--
+		case tok.Type == TokenKeyword && tok.Value == "CASE":
+			cases++
+		case tok.Type == TokenKeyword && tok.Value == "END" && cases > 0:
+			cases--
+		}
+	}
+	return len(p.tokens)
+}
+
+// resume continues parsing after a statement abandoned by bail.
+func (p *Parser) resume() {
+	if p.bailed {
+		p.pos = p.resumeAt
+		p.bailed = false
 	}
 }
 
@@ -1065,6 +1834,9 @@ func (p *Parser) skipParens() {
 	for p.pos < len(p.tokens) && depth > 0 {
 		if p.matchPunct("(") {
 			depth++
+			if !p.withinDepth(depth) {
+				return
+			}
 		} else if p.matchPunct(")") {
 			depth--
 		}
@@ -1125,15 +1897,7 @@ func (p *Parser) collectFromTables(context, refType string) map[string]string {
 		return fromTables
 	}
 	fromTables[strings.ToLower(alias)] = name
-	if context != "" {
-		p.refs = append(p.refs, parser.RawReference{
-			FromSymbol:    context,
-			ToName:        unqualify(name),
-			ToQualified:   name,
-			ReferenceType: refType,
-			Line:          p.currentLine(),
-		})
-	}
+	p.tableRef(context, name, refType, p.currentLine())
 
 	// Handle comma-separated tables: FROM dbo.Users u, dbo.Roles r
 	for p.matchPunct(",") {
@@ -1143,15 +1907,7 @@ func (p *Parser) collectFromTables(context, refType string) map[string]string {
 			break
 		}
 		fromTables[strings.ToLower(alias)] = name
-		if context != "" {
-			p.refs = append(p.refs, parser.RawReference{
-				FromSymbol:    context,
-				ToName:        unqualify(name),
-				ToQualified:   name,
-				ReferenceType: refType,
-				Line:          p.currentLine(),
-			})
-		}
+		p.tableRef(context, name, refType, p.currentLine())
 	}
 
 	return fromTables
@@ -39,6 +39,51 @@ GO
 	}
 }
 
+func TestParseColumnTypesAndForeignKeys(t *testing.T) {
+	input := `
+CREATE TABLE dbo.Orders (
+    OrderID INT IDENTITY(1,1) PRIMARY KEY,
+    CustomerID INT NOT NULL REFERENCES dbo.Customers(CustomerID),
+    Total DECIMAL(10, 2) NULL,
+    Notes NVARCHAR(MAX),
+    RegionCode CHAR(2),
+    CONSTRAINT FK_Orders_Regions FOREIGN KEY (RegionCode) REFERENCES dbo.Regions (Code)
+);
+GO
+`
+	result, err := New().Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]string)
+	for _, col := range result.Symbols[0].Children {
+		types[col.Name] = col.Signature
+	}
+	for col, want := range map[string]string{
+		"OrderID":    "int",
+		"CustomerID": "int",
+		"Total":      "decimal(10,2)",
+		"Notes":      "nvarchar(max)",
+		"RegionCode": "char(2)",
+	} {
+		if types[col] != want {
+			t.Errorf("column %s: expected type %q, got %q", col, want, types[col])
+		}
+	}
+
+	fks := make(map[string]string)
+	for _, ref := range result.References {
+		if ref.ReferenceType == "foreign_key" {
+			fks[ref.FromSymbol] = ref.ToQualified
+		}
+	}
+	if len(fks) != 2 || fks["dbo.Orders.CustomerID"] != "dbo.Customers.CustomerID" ||
+		fks["dbo.Orders.RegionCode"] != "dbo.Regions.Code" {
+		t.Errorf("unexpected foreign keys: %v", fks)
+	}
+}
+
 func TestParseCreateProcedure(t *testing.T) {
 	input := `
 CREATE PROCEDURE dbo.GetUserOrders
@@ -214,6 +259,9 @@ GO
 	}
 
 	for _, ref := range result.ColumnReferences {
+		if ref.DerivationType == "filter" {
+			continue
+		}
 		expected, ok := expectedRefs[ref.SourceColumn]
 		if !ok {
 			t.Errorf("unexpected source column: %s", ref.SourceColumn)
@@ -520,3 +568,430 @@ GO
 		t.Errorf("expected tsql, got %s", d)
 	}
 }
+
+func TestNestingLimitSkipsStatementAndContinues(t *testing.T) {
+	nested := strings.Repeat("(", 500) + "1" + strings.Repeat(")", 500)
+	input := `
+CREATE PROCEDURE dbo.DeepQuery
+AS
+BEGIN
+    SELECT * FROM dbo.Orders WHERE OrderID IN ` + nested + `;
+    UPDATE dbo.AuditLog SET Touched = 1;
+END
+GO
+CREATE TABLE dbo.Trailing (
+    ID INT NOT NULL
+);
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d: %+v", len(result.Warnings), result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0].Message, "nesting depth") {
+		t.Errorf("unexpected warning message: %s", result.Warnings[0].Message)
+	}
+	if result.Warnings[0].Line != 5 {
+		t.Errorf("expected warning on line 5, got %d", result.Warnings[0].Line)
+	}
+
+	// The statement after the abandoned one, in the same body, is still parsed
+	foundWrite := false
+	for _, ref := range result.References {
+		if ref.FromSymbol == "dbo.DeepQuery" && ref.ToQualified == "dbo.AuditLog" && ref.ReferenceType == "writes_to" {
+			foundWrite = true
+		}
+	}
+	if !foundWrite {
+		t.Error("expected writes_to dbo.AuditLog after the abandoned statement")
+	}
+
+	kinds := make(map[string]string)
+	for _, s := range result.Symbols {
+		kinds[s.QualifiedName] = s.Kind
+	}
+	if kinds["dbo.DeepQuery"] != "procedure" {
+		t.Error("expected procedure dbo.DeepQuery")
+	}
+	if kinds["dbo.Trailing"] != "table" {
+		t.Error("expected table dbo.Trailing in the following batch")
+	}
+}
+
+func TestNestingLimitResumesWithoutSemicolons(t *testing.T) {
+	input := `
+CREATE TABLE dbo.A (ID INT NOT NULL)
+SELECT ((((((1)))))) AS Deep, CASE WHEN ID > 0 THEN 1 END AS Flag FROM dbo.A
+CREATE TABLE dbo.B (ID INT NOT NULL)
+CREATE TABLE dbo.C (ID INT NOT NULL)
+`
+	p := NewWithLimits(Limits{MaxNestingDepth: 4})
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Warnings) != 1 || result.Warnings[0].Line != 3 {
+		t.Fatalf("expected 1 warning on line 3, got %+v", result.Warnings)
+	}
+	tables := make(map[string]bool)
+	for _, s := range result.Symbols {
+		if s.Kind == "table" {
+			tables[s.QualifiedName] = true
+		}
+	}
+	for _, want := range []string{"dbo.A", "dbo.B", "dbo.C"} {
+		if !tables[want] {
+			t.Errorf("expected table %s, got %v", want, tables)
+		}
+	}
+}
+
+func TestStatementTokenLimit(t *testing.T) {
+	input := `
+SELECT a, b, c, d, e, f, g, h, i, j, k, l FROM dbo.Wide;
+CREATE TABLE dbo.Customers (
+    ID INT NOT NULL
+);
+`
+	p := NewWithLimits(Limits{MaxStatementTokens: 20})
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d: %+v", len(result.Warnings), result.Warnings)
+	}
+	if len(result.Symbols) != 1 || result.Symbols[0].QualifiedName != "dbo.Customers" {
+		t.Errorf("expected dbo.Customers to be parsed after the skipped SELECT, got %+v", result.Symbols)
+	}
+}
+
+func TestParseExecuteSQL(t *testing.T) {
+	input := `
+CREATE PROCEDURE dbo.GetOrder
+    @OrderId INT
+AS
+BEGIN
+    EXEC sp_executesql N'SELECT o.Id, o.Total FROM dbo.Orders o WHERE o.Status = ''open'' AND o.Id = @id',
+        N'@id INT', @id = @OrderId
+    DECLARE @sql NVARCHAR(MAX) = N'SELECT * FROM dbo.Ignored'
+    EXEC sp_executesql @sql
+END
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, ref := range result.References {
+		if ref.ToQualified == "sp_executesql" {
+			t.Errorf("unexpected calls reference to sp_executesql: %+v", ref)
+		}
+		if ref.FromSymbol == "dbo.GetOrder" && ref.ToQualified == "dbo.Orders" && ref.ReferenceType == "reads_from" {
+			found = true
+			if ref.Line != 6 {
+				t.Errorf("expected reads_from on line 6, got %d", ref.Line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected reads_from dbo.Orders from the sp_executesql statement, got %+v", result.References)
+	}
+}
+
+func TestCommonTableExpressions(t *testing.T) {
+	input := `
+CREATE PROCEDURE dbo.CustomerTotals
+AS
+BEGIN
+    ;WITH OrderTotals (CustomerID, Total) AS (
+        SELECT o.CustomerID, SUM(o.Amount)
+        FROM dbo.Orders o
+        GROUP BY o.CustomerID
+    ),
+    BigSpenders AS (
+        SELECT t.CustomerID, t.Total FROM OrderTotals t WHERE t.Total > 1000
+    )
+    INSERT INTO dbo.CustomerSummary (CustomerID, Name, Total)
+    SELECT b.CustomerID, c.Name, b.Total
+    FROM BigSpenders b
+    JOIN dbo.Customers c ON c.CustomerID = b.CustomerID
+END
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := make(map[string]bool)
+	for _, ref := range result.References {
+		refs[ref.ReferenceType+":"+ref.ToQualified] = true
+		if strings.EqualFold(ref.ToName, "OrderTotals") || strings.EqualFold(ref.ToName, "BigSpenders") {
+			t.Errorf("unexpected reference to a CTE: %+v", ref)
+		}
+	}
+	for _, want := range []string{"reads_from:dbo.Orders", "joins:dbo.Customers", "writes_to:dbo.CustomerSummary"} {
+		if !refs[want] {
+			t.Errorf("missing %s, got %v", want, refs)
+		}
+	}
+
+	lineage := make(map[string]parser.ColumnReference)
+	for _, ref := range result.ColumnReferences {
+		lineage[ref.TargetColumn] = ref
+	}
+	if ref := lineage["dbo.CustomerSummary.CustomerID"]; ref.SourceColumn != "dbo.Orders.CustomerID" || ref.DerivationType != "direct_copy" {
+		t.Errorf("expected CustomerID copied through both CTEs from dbo.Orders, got %+v", ref)
+	}
+	if ref := lineage["dbo.CustomerSummary.Total"]; ref.SourceColumn != "dbo.Orders.Amount" || ref.DerivationType != "aggregate" {
+		t.Errorf("expected Total aggregated from dbo.Orders.Amount, got %+v", ref)
+	}
+	if ref := lineage["dbo.CustomerSummary.Name"]; ref.SourceColumn != "dbo.Customers.Name" {
+		t.Errorf("expected Name from dbo.Customers, got %+v", ref)
+	}
+}
+
+func TestRecursiveCTEInView(t *testing.T) {
+	input := `
+CREATE VIEW dbo.EmployeeTree AS
+WITH Tree AS (
+    SELECT e.EmployeeID, e.ManagerID FROM dbo.Employees e WHERE e.ManagerID IS NULL
+    UNION ALL
+    SELECT e.EmployeeID, e.ManagerID FROM dbo.Employees e JOIN Tree t ON e.ManagerID = t.EmployeeID
+)
+SELECT t.EmployeeID, t.ManagerID FROM Tree t
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range result.References {
+		if ref.ToQualified == "Tree" {
+			t.Errorf("unexpected reference to the recursive CTE: %+v", ref)
+		}
+	}
+	var reads bool
+	for _, ref := range result.References {
+		reads = reads || ref.FromSymbol == "dbo.EmployeeTree" && ref.ToQualified == "dbo.Employees" && ref.ReferenceType == "reads_from"
+	}
+	if !reads {
+		t.Errorf("expected the view to read dbo.Employees, got %+v", result.References)
+	}
+	lineage := 0
+	for _, ref := range result.ColumnReferences {
+		if !strings.HasPrefix(ref.SourceColumn, "dbo.Employees.") {
+			t.Errorf("expected view lineage from dbo.Employees, got %+v", ref)
+		}
+		if ref.TargetColumn != "dbo.EmployeeTree" {
+			lineage++
+		}
+	}
+	if lineage != 2 {
+		t.Errorf("expected 2 column lineage references, got %+v", result.ColumnReferences)
+	}
+}
+
+func TestTempTablesThreadLineage(t *testing.T) {
+	input := `
+CREATE PROCEDURE dbo.LoadCustomerTotals
+AS
+BEGIN
+    CREATE TABLE #Staging (CustomerID INT, Total MONEY)
+    DECLARE @Regions TABLE (RegionID INT, Name NVARCHAR(50))
+
+    INSERT INTO #Staging (CustomerID, Total)
+    SELECT o.CustomerID, SUM(o.Amount) FROM dbo.Orders o GROUP BY o.CustomerID
+
+    INSERT INTO @Regions (RegionID, Name)
+    SELECT r.RegionID, r.Name FROM dbo.Regions r
+
+    SELECT c.CustomerID, c.RegionID INTO #Customers FROM dbo.Customers c
+
+    UPDATE #Staging SET Total = Total * 1.1
+
+    INSERT INTO dbo.CustomerTotals (CustomerID, Total, RegionName)
+    SELECT s.CustomerID, s.Total, rg.Name
+    FROM #Staging s
+    JOIN #Customers c ON c.CustomerID = s.CustomerID
+    JOIN @Regions rg ON rg.RegionID = c.RegionID
+
+    DROP TABLE #Staging
+END
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sym := range result.Symbols {
+		if strings.HasPrefix(sym.Name, "#") {
+			t.Errorf("unexpected symbol for a temp table: %+v", sym)
+		}
+	}
+	refs := make(map[string]bool)
+	for _, ref := range result.References {
+		if strings.HasPrefix(ref.ToName, "#") || strings.HasPrefix(ref.ToName, "@") {
+			t.Errorf("unexpected reference to a transient table: %+v", ref)
+		}
+		refs[ref.ReferenceType+":"+ref.ToQualified] = true
+	}
+	for _, want := range []string{"reads_from:dbo.Orders", "reads_from:dbo.Regions", "reads_from:dbo.Customers", "writes_to:dbo.CustomerTotals"} {
+		if !refs[want] {
+			t.Errorf("missing %s, got %v", want, refs)
+		}
+	}
+
+	lineage := make(map[string]parser.ColumnReference)
+	for _, ref := range result.ColumnReferences {
+		if strings.Contains(ref.SourceColumn, "#") || strings.Contains(ref.TargetColumn, "#") ||
+			strings.Contains(ref.SourceColumn, "@") || strings.Contains(ref.TargetColumn, "@") {
+			t.Errorf("unexpected transient column in lineage: %+v", ref)
+		}
+		lineage[ref.TargetColumn] = ref
+	}
+	if ref := lineage["dbo.CustomerTotals.CustomerID"]; ref.SourceColumn != "dbo.Orders.CustomerID" || ref.DerivationType != "direct_copy" {
+		t.Errorf("expected CustomerID copied from dbo.Orders through #Staging, got %+v", ref)
+	}
+	if ref := lineage["dbo.CustomerTotals.Total"]; ref.SourceColumn != "dbo.Orders.Amount" || ref.DerivationType != "aggregate" {
+		t.Errorf("expected Total aggregated from dbo.Orders.Amount, got %+v", ref)
+	}
+	if ref := lineage["dbo.CustomerTotals.RegionName"]; ref.SourceColumn != "dbo.Regions.Name" {
+		t.Errorf("expected RegionName from dbo.Regions through @Regions, got %+v", ref)
+	}
+}
+
+func TestPredicateColumns(t *testing.T) {
+	input := `
+CREATE PROCEDURE dbo.GetOpenOrders
+    @CustomerID INT
+AS
+BEGIN
+    SELECT o.OrderID, c.Name
+    FROM dbo.Orders o
+    INNER JOIN dbo.Customers c ON c.CustomerID = o.CustomerID
+    WHERE o.Status = 'open'
+      AND (o.CustomerID = @CustomerID OR o.RegionID IN (SELECT r.RegionID FROM dbo.Regions r))
+      AND YEAR(o.OrderDate) = 2024
+    ORDER BY o.OrderID
+
+    UPDATE dbo.Orders SET Status = 'seen' WHERE ShippedAt IS NULL
+    DELETE FROM dbo.OrderLines WHERE OrderID = @CustomerID
+END
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, ref := range result.ColumnReferences {
+		if ref.DerivationType != "filter" && ref.DerivationType != "join" {
+			continue
+		}
+		if ref.TargetColumn != "dbo.GetOpenOrders" || ref.Context != "dbo.GetOpenOrders" {
+			t.Errorf("expected predicate columns used by the procedure, got %+v", ref)
+		}
+		if _, ok := got[ref.SourceColumn]; !ok {
+			got[ref.SourceColumn] = ref.DerivationType
+		}
+	}
+	want := map[string]string{
+		"dbo.Customers.CustomerID": "join",
+		"dbo.Orders.CustomerID":    "join",
+		"dbo.Orders.Status":        "filter",
+		"dbo.Orders.RegionID":      "filter",
+		"dbo.Orders.ShippedAt":     "filter",
+		"dbo.OrderLines.OrderID":   "filter",
+	}
+	for col, derivation := range want {
+		if got[col] != derivation {
+			t.Errorf("expected %s as a %s column, got %q", col, derivation, got[col])
+		}
+	}
+	for _, skipped := range []string{"dbo.Orders.OrderDate", "dbo.Regions.RegionID", "dbo.Orders.OrderID"} {
+		if _, ok := got[skipped]; ok {
+			t.Errorf("unexpected predicate column %s", skipped)
+		}
+	}
+}
+
+func TestIndexes(t *testing.T) {
+	input := `
+CREATE TABLE dbo.Orders (
+    OrderID INT NOT NULL PRIMARY KEY,
+    CustomerID INT NOT NULL,
+    Email NVARCHAR(200) CONSTRAINT UQ_Orders_Email UNIQUE,
+    OrderDate DATETIME,
+    INDEX IX_Orders_OrderDate NONCLUSTERED (OrderDate DESC)
+);
+GO
+CREATE TABLE dbo.OrderLines (
+    OrderID INT NOT NULL,
+    LineNo INT NOT NULL,
+    CONSTRAINT PK_OrderLines PRIMARY KEY CLUSTERED (OrderID ASC, LineNo ASC)
+);
+GO
+CREATE UNIQUE NONCLUSTERED INDEX IX_Orders_Customer ON dbo.Orders (CustomerID, OrderDate) INCLUDE (Email);
+GO
+`
+	p := New()
+	result, err := p.Parse(parser.FileInput{Path: "test.sql", Content: []byte(input)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Symbols[0].QualifiedName != "dbo.Orders" || len(result.Symbols[0].Children) != 4 {
+		t.Errorf("expected dbo.Orders with 4 columns first, got %+v", result.Symbols[0])
+	}
+	signatures := make(map[string]string)
+	for _, sym := range result.Symbols {
+		if sym.Kind == "index" {
+			signatures[sym.QualifiedName] = sym.Signature
+		}
+	}
+	wantSignatures := map[string]string{
+		"dbo.Orders.PK_Orders":           "primary key (OrderID)",
+		"dbo.Orders.UQ_Orders_Email":     "unique (Email)",
+		"dbo.Orders.IX_Orders_OrderDate": "nonclustered (OrderDate)",
+		"dbo.OrderLines.PK_OrderLines":   "primary key (OrderID, LineNo)",
+		"dbo.Orders.IX_Orders_Customer":  "unique nonclustered (CustomerID, OrderDate)",
+	}
+	for name, sig := range wantSignatures {
+		if signatures[name] != sig {
+			t.Errorf("index %s: expected signature %q, got %q", name, sig, signatures[name])
+		}
+	}
+	if len(signatures) != len(wantSignatures) {
+		t.Errorf("expected %d indexes, got %v", len(wantSignatures), signatures)
+	}
+
+	// Each index links its leading key column only
+	indexed := make(map[string]string)
+	for _, ref := range result.References {
+		if ref.ReferenceType == "indexes" {
+			indexed[ref.FromSymbol] = ref.ToQualified
+		}
+	}
+	if indexed["dbo.OrderLines.PK_OrderLines"] != "dbo.OrderLines.OrderID" ||
+		indexed["dbo.Orders.IX_Orders_Customer"] != "dbo.Orders.CustomerID" {
+		t.Errorf("expected indexes refs to the leading key columns, got %v", indexed)
+	}
+}
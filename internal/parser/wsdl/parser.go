@@ -0,0 +1,155 @@
+// Package wsdl parses WSDL 1.1 service descriptions and XSD schemas. SOAP operations
+// become endpoint symbols referencing their input and output types, and XSD complex,
+// simple and top-level element types become type symbols with their fields.
+package wsdl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// xsdNamespace is the XML Schema namespace; types in it are built-ins such as xs:string.
+const xsdNamespace = "http://www.w3.org/2001/XMLSchema"
+
+// Parser handles .wsdl and .xsd files.
+type Parser struct{}
+
+func New() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) Languages() []string {
+	return []string{"wsdl", "xsd"}
+}
+
+// node is an element of the parsed document. Prefixes holds the namespace prefixes in
+// scope, needed to resolve QName attribute values such as type="tns:Order".
+type node struct {
+	Local     string
+	Attrs     map[string]string
+	Children  []*node
+	Prefixes  map[string]string
+	StartLine int
+	EndLine   int
+}
+
+func (n *node) attr(name string) string { return n.Attrs[name] }
+
+func (n *node) children(local string) []*node {
+	var out []*node
+	for _, c := range n.Children {
+		if c.Local == local {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// qname resolves a prefixed attribute value to its local name, reporting whether it
+// names an XML Schema built-in.
+func (n *node) qname(value string) (local string, builtin bool) {
+	prefix, local, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, n.Prefixes[""] == xsdNamespace
+	}
+	ns, declared := n.Prefixes[prefix]
+	if !declared {
+		return local, prefix == "xs" || prefix == "xsd"
+	}
+	return local, ns == xsdNamespace
+}
+
+func (p *Parser) Parse(input parser.FileInput) (*parser.ParseResult, error) {
+	root, err := parseTree(input.Content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", input.Path, err)
+	}
+
+	b := &builder{types: make(map[string]bool)}
+	switch root.Local {
+	case "definitions":
+		b.language = "wsdl"
+		for _, types := range root.children("types") {
+			for _, schema := range types.children("schema") {
+				b.schema(schema)
+			}
+		}
+		b.definitions(root)
+	case "schema":
+		b.language = "xsd"
+		b.schema(root)
+	default:
+		return &parser.ParseResult{}, nil
+	}
+	return &parser.ParseResult{Symbols: b.symbols, References: b.refs}, nil
+}
+
+// parseTree reads the document into a node tree, tracking namespace prefixes and lines.
+func parseTree(content []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	dec.Strict = false
+	var root *node
+	var stack []*node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := dec.InputPos()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{Local: t.Name.Local, Attrs: make(map[string]string), StartLine: line}
+			if len(stack) > 0 {
+				n.Prefixes = stack[len(stack)-1].Prefixes
+			}
+			copied := false
+			for _, a := range t.Attr {
+				prefix, isDecl := "", false
+				switch {
+				case a.Name.Space == "xmlns":
+					prefix, isDecl = a.Name.Local, true
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					isDecl = true
+				}
+				if !isDecl {
+					n.Attrs[a.Name.Local] = a.Value
+					continue
+				}
+				if !copied {
+					n.Prefixes = maps.Clone(n.Prefixes)
+					if n.Prefixes == nil {
+						n.Prefixes = make(map[string]string)
+					}
+					copied = true
+				}
+				n.Prefixes[prefix] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			stack[len(stack)-1].EndLine = line
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
@@ -0,0 +1,166 @@
+package wsdl
+
+import (
+	"testing"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+const sampleWSDL = `<?xml version="1.0" encoding="utf-8"?>
+<wsdl:definitions name="Orders"
+    targetNamespace="http://example.com/orders"
+    xmlns:tns="http://example.com/orders"
+    xmlns:xs="http://www.w3.org/2001/XMLSchema"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/">
+  <wsdl:types>
+    <xs:schema targetNamespace="http://example.com/orders">
+      <xs:element name="GetOrderRequest">
+        <xs:complexType>
+          <xs:sequence>
+            <xs:element name="OrderId" type="xs:int"/>
+          </xs:sequence>
+        </xs:complexType>
+      </xs:element>
+      <xs:element name="GetOrderResponse" type="tns:Order"/>
+      <xs:complexType name="Order">
+        <xs:sequence>
+          <xs:element name="Id" type="xs:int"/>
+          <xs:element name="Total" type="xs:decimal"/>
+        </xs:sequence>
+      </xs:complexType>
+    </xs:schema>
+  </wsdl:types>
+  <wsdl:message name="GetOrderIn">
+    <wsdl:part name="parameters" element="tns:GetOrderRequest"/>
+  </wsdl:message>
+  <wsdl:message name="GetOrderOut">
+    <wsdl:part name="parameters" element="tns:GetOrderResponse"/>
+  </wsdl:message>
+  <wsdl:portType name="IOrderService">
+    <wsdl:operation name="GetOrder">
+      <wsdl:input message="tns:GetOrderIn"/>
+      <wsdl:output message="tns:GetOrderOut"/>
+    </wsdl:operation>
+  </wsdl:portType>
+  <wsdl:binding name="OrderServiceSoap" type="tns:IOrderService">
+    <soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+    <wsdl:operation name="GetOrder">
+      <soap:operation soapAction="http://example.com/orders/GetOrder"/>
+    </wsdl:operation>
+  </wsdl:binding>
+  <wsdl:service name="OrderService">
+    <wsdl:port name="OrderServiceSoap" binding="tns:OrderServiceSoap">
+      <soap:address location="https://legacy.example.com/OrderService.svc"/>
+    </wsdl:port>
+  </wsdl:service>
+</wsdl:definitions>
+`
+
+func TestParseWSDLOperation(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "orders.wsdl", Content: []byte(sampleWSDL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byQName := make(map[string]parser.Symbol)
+	for _, s := range result.Symbols {
+		byQName[s.QualifiedName] = s
+	}
+	svc, ok := byQName["OrderService"]
+	if !ok || svc.Kind != "service" || svc.Language != "wsdl" {
+		t.Fatalf("expected OrderService service symbol, got %+v", result.Symbols)
+	}
+	op, ok := byQName["soap:OrderService.GetOrder"]
+	if !ok || op.Kind != "endpoint" || op.Name != "GetOrder" {
+		t.Fatalf("expected GetOrder endpoint, got %+v", result.Symbols)
+	}
+	if op.Signature != "SOAP GetOrder(GetOrderRequest) GetOrderResponse" {
+		t.Errorf("unexpected operation signature %q", op.Signature)
+	}
+	for _, name := range []string{"GetOrderRequest", "GetOrderResponse", "Order"} {
+		if byQName[name].Kind != "type" {
+			t.Errorf("expected inline schema type %s", name)
+		}
+	}
+
+	refs := make(map[string]bool)
+	for _, r := range result.References {
+		refs[r.FromSymbol+" -"+r.ReferenceType+"-> "+r.ToQualified] = true
+	}
+	for _, want := range []string{
+		"soap:OrderService.GetOrder -references-> GetOrderRequest",
+		"soap:OrderService.GetOrder -references-> GetOrderResponse",
+		"OrderService -contains-> soap:OrderService.GetOrder",
+		"GetOrderResponse -references-> Order",
+	} {
+		if !refs[want] {
+			t.Errorf("missing reference %s, got %v", want, refs)
+		}
+	}
+}
+
+const sampleXSD = `<?xml version="1.0"?>
+<xsd:schema xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+    xmlns:c="http://example.com/customers"
+    targetNamespace="http://example.com/customers">
+  <xsd:complexType name="Customer">
+    <xsd:complexContent>
+      <xsd:extension base="c:Party">
+        <xsd:sequence>
+          <xsd:element name="Email" type="xsd:string"/>
+          <xsd:element name="Address" type="c:Address" minOccurs="0"/>
+        </xsd:sequence>
+        <xsd:attribute name="status" type="c:CustomerStatus"/>
+      </xsd:extension>
+    </xsd:complexContent>
+  </xsd:complexType>
+  <xsd:simpleType name="CustomerStatus">
+    <xsd:restriction base="xsd:string">
+      <xsd:enumeration value="Active"/>
+      <xsd:enumeration value="Closed"/>
+    </xsd:restriction>
+  </xsd:simpleType>
+</xsd:schema>
+`
+
+func TestParseXSDComplexType(t *testing.T) {
+	result, err := New().Parse(parser.FileInput{Path: "customers.xsd", Content: []byte(sampleXSD)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 2 {
+		t.Fatalf("expected Customer and CustomerStatus, got %+v", result.Symbols)
+	}
+
+	customer := result.Symbols[0]
+	if customer.QualifiedName != "Customer" || customer.Kind != "type" || customer.Language != "xsd" {
+		t.Fatalf("unexpected complex type symbol %+v", customer)
+	}
+	if customer.StartLine != 5 || customer.EndLine != 15 {
+		t.Errorf("expected Customer at L5-L15, got L%d-L%d", customer.StartLine, customer.EndLine)
+	}
+	fields := make(map[string]string)
+	for _, f := range customer.Children {
+		fields[f.QualifiedName] = f.Signature
+	}
+	if len(fields) != 3 || fields["Customer.Email"] != "string" || fields["Customer.Address"] != "Address" || fields["Customer.status"] != "CustomerStatus" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+
+	status := result.Symbols[1]
+	if status.Signature != "simpleType CustomerStatus restricts string (Active, Closed)" {
+		t.Errorf("unexpected simple type signature %q", status.Signature)
+	}
+
+	refs := make(map[string]string)
+	for _, r := range result.References {
+		refs[r.ToName] = r.ReferenceType
+	}
+	if refs["Party"] != "inherits" || refs["Address"] != "references" || refs["CustomerStatus"] != "references" {
+		t.Errorf("unexpected references %v", refs)
+	}
+	if _, ok := refs["string"]; ok {
+		t.Error("built-in XSD types should not be referenced")
+	}
+}
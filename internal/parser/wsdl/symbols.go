@@ -0,0 +1,268 @@
+package wsdl
+
+import (
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// builder accumulates the symbols and references of one document.
+type builder struct {
+	language string
+	symbols  []parser.Symbol
+	refs     []parser.RawReference
+	types    map[string]bool // declared type names; an element named like its type is not repeated
+}
+
+// schema emits the named complex and simple types of an XSD schema, then its top-level
+// elements. Elements come second so an element sharing its type's name folds into it.
+func (b *builder) schema(s *node) {
+	for _, c := range s.Children {
+		name := c.attr("name")
+		if name == "" {
+			continue
+		}
+		switch c.Local {
+		case "complexType":
+			b.complexType(c, name, "complexType "+name)
+		case "simpleType":
+			b.simpleType(c, name)
+		}
+	}
+	for _, c := range s.children("element") {
+		name := c.attr("name")
+		if name == "" || b.types[name] {
+			continue
+		}
+		if typ := c.attr("type"); typ != "" {
+			local, builtin := c.qname(typ)
+			b.addType(c, name, "element "+name+": "+local, nil)
+			if !builtin && local != name {
+				b.ref(name, local, "references", c.StartLine)
+			}
+			continue
+		}
+		if ct := c.children("complexType"); len(ct) > 0 {
+			b.complexType(ct[0], name, "element "+name)
+		} else {
+			b.addType(c, name, "element "+name, nil)
+		}
+	}
+}
+
+// complexType emits a type with its elements and attributes as fields.
+func (b *builder) complexType(n *node, name, sig string) {
+	var fields []parser.Symbol
+	b.collectFields(n, name, &fields)
+	b.addType(n, name, sig, fields)
+}
+
+// collectFields walks a complex type's content model. Nested anonymous types are not
+// descended into: their fields belong to the nested element, not to this type.
+func (b *builder) collectFields(n *node, typeName string, fields *[]parser.Symbol) {
+	for _, c := range n.Children {
+		switch c.Local {
+		case "sequence", "all", "choice", "complexContent", "simpleContent":
+			b.collectFields(c, typeName, fields)
+		case "extension", "restriction":
+			if base := c.attr("base"); base != "" {
+				if local, builtin := c.qname(base); !builtin {
+					b.ref(typeName, local, "inherits", c.StartLine)
+				}
+			}
+			b.collectFields(c, typeName, fields)
+		case "element", "attribute":
+			name, typ := c.attr("name"), c.attr("type")
+			if name == "" && c.attr("ref") != "" {
+				name, _ = c.qname(c.attr("ref"))
+				typ = c.attr("ref")
+			}
+			if name == "" {
+				continue
+			}
+			sig := ""
+			if typ != "" {
+				local, builtin := c.qname(typ)
+				sig = local
+				if !builtin {
+					b.ref(typeName, local, "references", c.StartLine)
+				}
+			}
+			*fields = append(*fields, parser.Symbol{
+				Name:          name,
+				QualifiedName: typeName + "." + name,
+				Kind:          "field",
+				Language:      b.language,
+				StartLine:     c.StartLine,
+				EndLine:       max(c.StartLine, c.EndLine),
+				Signature:     sig,
+			})
+		}
+	}
+}
+
+// simpleType emits a restricted scalar type, e.g. an enumeration of status codes.
+func (b *builder) simpleType(n *node, name string) {
+	sig := "simpleType " + name
+	for _, r := range n.children("restriction") {
+		if base := r.attr("base"); base != "" {
+			local, builtin := r.qname(base)
+			sig += " restricts " + local
+			if !builtin {
+				b.ref(name, local, "inherits", r.StartLine)
+			}
+		}
+		var values []string
+		for _, e := range r.children("enumeration") {
+			values = append(values, e.attr("value"))
+		}
+		if len(values) > 0 {
+			sig += " (" + strings.Join(values, ", ") + ")"
+		}
+	}
+	b.addType(n, name, sig, nil)
+}
+
+func (b *builder) addType(n *node, name, sig string, fields []parser.Symbol) {
+	b.types[name] = true
+	b.symbols = append(b.symbols, parser.Symbol{
+		Name:          name,
+		QualifiedName: name,
+		Kind:          "type",
+		Language:      b.language,
+		StartLine:     n.StartLine,
+		EndLine:       max(n.StartLine, n.EndLine),
+		Signature:     sig,
+		Children:      fields,
+	})
+}
+
+func (b *builder) ref(from, to, refType string, line int) {
+	b.refs = append(b.refs, parser.RawReference{
+		FromSymbol:    from,
+		ToName:        to,
+		ToQualified:   to,
+		ReferenceType: refType,
+		Line:          line,
+	})
+}
+
+// definitions emits each service and the operations of the port types its ports bind,
+// as endpoints qualified "soap:Service.Operation". Operations of port types no service
+// exposes are qualified by the port type instead.
+func (b *builder) definitions(root *node) {
+	messages := make(map[string][]string) // message -> part types
+	for _, m := range root.children("message") {
+		var parts []string
+		for _, part := range m.children("part") {
+			typ := part.attr("element")
+			if typ == "" {
+				typ = part.attr("type")
+			}
+			if typ == "" {
+				continue
+			}
+			if local, builtin := part.qname(typ); !builtin {
+				parts = append(parts, local)
+			}
+		}
+		messages[m.attr("name")] = parts
+	}
+
+	portTypes := make(map[string]*node)
+	for _, pt := range root.children("portType") {
+		portTypes[pt.attr("name")] = pt
+	}
+	bindings := make(map[string]string) // binding -> port type
+	for _, bnd := range root.children("binding") {
+		local, _ := bnd.qname(bnd.attr("type"))
+		bindings[bnd.attr("name")] = local
+	}
+
+	exposed := make(map[string]bool)
+	for _, svc := range root.children("service") {
+		name := svc.attr("name")
+		if name == "" {
+			continue
+		}
+		sig := "SOAP service " + name
+		var bound []string
+		for _, port := range svc.children("port") {
+			binding, _ := port.qname(port.attr("binding"))
+			if pt, ok := bindings[binding]; ok && portTypes[pt] != nil {
+				bound = append(bound, pt)
+			}
+			for _, addr := range port.children("address") {
+				if loc := addr.attr("location"); loc != "" && !strings.Contains(sig, " at ") {
+					sig += " at " + loc
+				}
+			}
+		}
+		b.symbols = append(b.symbols, parser.Symbol{
+			Name:          name,
+			QualifiedName: name,
+			Kind:          "service",
+			Language:      b.language,
+			StartLine:     svc.StartLine,
+			EndLine:       max(svc.StartLine, svc.EndLine),
+			Signature:     sig,
+		})
+		seen := make(map[string]bool)
+		for _, pt := range bound {
+			if seen[pt] {
+				continue
+			}
+			seen[pt] = true
+			exposed[pt] = true
+			b.operations(portTypes[pt], name, messages)
+		}
+	}
+	for _, pt := range root.children("portType") {
+		if name := pt.attr("name"); name != "" && !exposed[name] {
+			b.operations(pt, "", messages)
+		}
+	}
+}
+
+// operations emits a port type's operations under service, or under the port type's
+// own name when service is "". Each operation references its input and output types.
+func (b *builder) operations(pt *node, service string, messages map[string][]string) {
+	owner := service
+	if owner == "" {
+		owner = pt.attr("name")
+	}
+	for _, op := range pt.children("operation") {
+		name := op.attr("name")
+		if name == "" {
+			continue
+		}
+		qname := parser.SOAPOperationPrefix + owner + "." + name
+		in := messageTypes(op, "input", messages)
+		out := messageTypes(op, "output", messages)
+		b.symbols = append(b.symbols, parser.Symbol{
+			Name:          name,
+			QualifiedName: qname,
+			Kind:          "endpoint",
+			Language:      b.language,
+			StartLine:     op.StartLine,
+			EndLine:       max(op.StartLine, op.EndLine),
+			Signature:     "SOAP " + name + "(" + strings.Join(in, ", ") + ") " + strings.Join(out, ", "),
+		})
+		if service != "" {
+			b.ref(service, qname, "contains", op.StartLine)
+		}
+		for _, t := range append(in, out...) {
+			b.ref(qname, t, "references", op.StartLine)
+		}
+	}
+}
+
+// messageTypes returns the part types of an operation's input or output message.
+func messageTypes(op *node, direction string, messages map[string][]string) []string {
+	var types []string
+	for _, m := range op.children(direction) {
+		local, _ := m.qname(m.attr("message"))
+		types = append(types, messages[local]...)
+	}
+	return types
+}
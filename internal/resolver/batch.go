@@ -0,0 +1,185 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// batchRef is a reference queued for resolveBatch with the scope it was made in.
+type batchRef struct {
+	ref        parser.RawReference
+	localScope map[string]uuid.UUID
+	sourceLang string
+}
+
+// acrossKey is what the case-insensitive and cross-language steps of a resolution
+// depend on; references sharing it resolve alike.
+type acrossKey struct {
+	toName, toQualified, referenceType, sourceLang string
+}
+
+// resolveBatch resolves references the way resolveTarget does one at a time, returning
+// the result of each in order. References the exact lookups miss go through the
+// case-insensitive and cross-language steps once per distinct target, against name and
+// route indexes built on first need instead of a scan of the table per reference.
+func resolveBatch(refs []batchRef, table *SymbolTable, crossLang *CrossLangResolver) []resolveResult {
+	results := make([]resolveResult, len(refs))
+	var index *crossLangIndex
+	seen := make(map[acrossKey]resolveResult)
+	for i, r := range refs {
+		if result, ok := resolveLocal(r.ref, r.localScope, table); ok {
+			results[i] = result
+			continue
+		}
+		key := acrossKey{r.ref.ToName, r.ref.ToQualified, r.ref.ReferenceType, r.sourceLang}
+		result, ok := seen[key]
+		if !ok {
+			if index == nil {
+				index = newCrossLangIndex(table)
+			}
+			result = resolveAcross(r.ref, r.sourceLang, index, crossLang)
+			seen[key] = result
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// crossLangIndex answers the lookups of bridge rules from indexes over a symbol table:
+// qualified names by lowercased short and full name, and API routes and OpenAPI
+// operations by segment count.
+// Where several symbols match, it takes the first by qualified name.
+type crossLangIndex struct {
+	table  *SymbolTable
+	short  map[string][]string // lowercased short name → qualified names
+	folded map[string][]string // lowercased qualified name → qualified names
+	routes map[routeKey][]indexedRoute
+}
+
+// routeKey groups the endpoints qualified with a prefix by their segment count.
+type routeKey struct {
+	prefix   string
+	segments int
+}
+
+// routePrefixes are the prefixes of the endpoints route lookups match.
+var routePrefixes = []string{parser.APIRoutePrefix, parser.OpenAPIOperationPrefix}
+
+// indexedRoute is an endpoint symbol ("route:GET /users/{id}") split for matching.
+type indexedRoute struct {
+	fqn      string
+	id       uuid.UUID
+	verb     string
+	template []string
+}
+
+func newCrossLangIndex(table *SymbolTable) *crossLangIndex {
+	ix := &crossLangIndex{
+		table:  table,
+		short:  make(map[string][]string),
+		folded: make(map[string][]string),
+		routes: make(map[routeKey][]indexedRoute),
+	}
+	fqns := make([]string, 0, len(table.ByFQN))
+	for fqn := range table.ByFQN {
+		fqns = append(fqns, fqn)
+	}
+	sort.Strings(fqns)
+	for _, fqn := range fqns {
+		short := strings.ToLower(shortNameOf(fqn))
+		ix.short[short] = append(ix.short[short], fqn)
+		folded := strings.ToLower(fqn)
+		ix.folded[folded] = append(ix.folded[folded], fqn)
+		for _, prefix := range routePrefixes {
+			if rest, ok := strings.CutPrefix(fqn, prefix); ok {
+				verb, route, _ := strings.Cut(rest, " ")
+				template := strings.Split(strings.TrimPrefix(route, "/"), "/")
+				key := routeKey{prefix, len(template)}
+				ix.routes[key] = append(ix.routes[key], indexedRoute{
+					fqn: fqn, id: table.ByFQN[fqn], verb: verb, template: template,
+				})
+			}
+		}
+	}
+	return ix
+}
+
+func (ix *crossLangIndex) exact(qualified string) (uuid.UUID, bool) {
+	id, ok := ix.table.ByFQN[qualified]
+	return id, ok
+}
+
+func (ix *crossLangIndex) shortName(name, targetLang string) (uuid.UUID, bool) {
+	for _, fqn := range ix.short[strings.ToLower(name)] {
+		if ix.table.inLanguage(fqn, targetLang) {
+			return ix.table.ByFQN[fqn], true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (ix *crossLangIndex) qualified(name string) (uuid.UUID, bool) {
+	if fqns := ix.folded[strings.ToLower(name)]; len(fqns) > 0 {
+		return ix.table.ByFQN[fqns[0]], true
+	}
+	return uuid.Nil, false
+}
+
+// operation is matchOperation over the symbols sharing the method's short name.
+func (ix *crossLangIndex) operation(prefix, targetName, targetLang string) (uuid.UUID, bool) {
+	method, ok := strings.CutPrefix(targetName, prefix)
+	if !ok || method == "" {
+		return uuid.Nil, false
+	}
+	var match uuid.UUID
+	found := 0
+	for _, fqn := range ix.short[strings.ToLower(method)] {
+		if !strings.HasPrefix(fqn, prefix) || !ix.table.inLanguage(fqn, targetLang) {
+			continue
+		}
+		match = ix.table.ByFQN[fqn]
+		found++
+	}
+	return match, found == 1
+}
+
+// route is matchRoute over the routes as long as the path, for whole matches, and the
+// shorter ones, for suffix matches.
+func (ix *crossLangIndex) route(prefix, path, qualified, targetLang string, caseSensitive bool) (uuid.UUID, bool) {
+	method := ""
+	if rest, ok := strings.CutPrefix(qualified, parser.APIRoutePrefix); ok {
+		method, _, _ = strings.Cut(rest, " ")
+	}
+	requested := strings.Split(strings.TrimPrefix(parser.NormalizeRoute(path), "/"), "/")
+
+	candidate := func(r indexedRoute) bool {
+		if !ix.table.inLanguage(r.fqn, targetLang) {
+			return false
+		}
+		return method == "" || r.verb == "ALL" || strings.EqualFold(r.verb, method)
+	}
+	var whole, suffix []uuid.UUID
+	for _, r := range ix.routes[routeKey{prefix, len(requested)}] {
+		if candidate(r) && routeFills(r.template, requested, caseSensitive) {
+			whole = append(whole, r.id)
+		}
+	}
+	if len(whole) > 0 {
+		return whole[0], len(whole) == 1
+	}
+	for n := 1; n < len(requested); n++ {
+		for _, r := range ix.routes[routeKey{prefix, n}] {
+			if candidate(r) && r.template[0] != "" && routeFills(r.template, requested[len(requested)-n:], caseSensitive) {
+				suffix = append(suffix, r.id)
+			}
+		}
+	}
+	if len(suffix) > 0 {
+		return suffix[0], len(suffix) == 1
+	}
+	return uuid.Nil, false
+}
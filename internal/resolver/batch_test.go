@@ -0,0 +1,143 @@
+package resolver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// polyglotTable is a fixture of SQL objects, C# and Delphi classes, SignalR hubs, a
+// WSDL service and NestJS routes.
+func polyglotTable() *SymbolTable {
+	table := newSymbolTable()
+	for qname, lang := range map[string]string{
+		"dbo.Customers":                       "tsql",
+		"dbo.OrderLines":                      "tsql",
+		"dbo.usp_GetOrder":                    "tsql",
+		"sales.Invoices":                      "tsql",
+		"public.audit_log":                    "pgsql",
+		"Shop.Models.Customer":                "csharp",
+		"Shop.Services.OrderService":          "csharp",
+		"Legacy.TPayment":                     "delphi",
+		"hub:Chat.Hubs.ChatHub.SendMessage":   "csharp",
+		"hub:Chat.Hubs.ChatHub.Join":          "csharp",
+		"hub:Chat.Hubs.LobbyHub.Join":         "csharp",
+		"soap:OrderService.GetOrderStatus":    "wsdl",
+		"route:GET /users/{id}":               "typescript",
+		"route:POST /users":                   "typescript",
+		"route:ALL /health":                   "typescript",
+		"route:GET /orders/{orderId}/lines":   "typescript",
+		"route:GET /reports/{year}/{quarter}": "typescript",
+		"route:GET /reports/latest/summary":   "typescript",
+	} {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+	}
+	return table
+}
+
+func TestResolveBatch_MatchesPerRef(t *testing.T) {
+	table := polyglotTable()
+	crossLang := NewCrossLangResolver(nil)
+	ref := func(toName, toQualified, refType string) parser.RawReference {
+		return parser.RawReference{FromSymbol: "caller", ToName: toName, ToQualified: toQualified, ReferenceType: refType}
+	}
+	refs := []batchRef{
+		// exact lookups
+		{ref: ref("Customers", "dbo.Customers", "uses_table"), sourceLang: "csharp"},
+		{ref: ref("OrderService", "", "calls"), sourceLang: "csharp"},
+		{ref: ref("Local", "", "calls"), sourceLang: "go", localScope: map[string]uuid.UUID{"Local": table.ByFQN["dbo.Customers"]}},
+		// case-insensitive short name
+		{ref: ref("customers", "", "uses_table"), sourceLang: "java"},
+		{ref: ref("ORDERLINES", "", "uses_table"), sourceLang: "asp"},
+		// schema-qualified, ORM and prefix conventions
+		{ref: ref("Invoices", "SALES.INVOICES", "uses_table"), sourceLang: "csharp"},
+		{ref: ref("OrderLine", "", "uses_table"), sourceLang: "csharp"},
+		{ref: ref("Invoice", "", "uses_table"), sourceLang: "java"},
+		{ref: ref("TAudit_log", "", "uses_table"), sourceLang: "delphi"},
+		// SignalR and SOAP operations, one ambiguous
+		{ref: ref("hub:sendMessage", "", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("hub:Join", "", "calls_api"), sourceLang: "javascript"},
+		{ref: ref("soap:GetOrderStatus", "", "calls_api"), sourceLang: "csharp"},
+		// routes: whole, with method, ALL, prefixed, variable and ambiguous requests
+		{ref: ref("/users/42", "route:GET /users/42", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("/users", "route:POST /users", "calls_api"), sourceLang: "javascript"},
+		{ref: ref("/users", "route:GET /users", "calls_api"), sourceLang: "javascript"},
+		{ref: ref("/health", "route:DELETE /health", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("/api/v1/orders/7/lines", "", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("/reports/${year}/q1", "", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("/reports/latest/summary", "route:GET /reports/latest/summary", "calls_api"), sourceLang: "typescript"},
+		{ref: ref("/users/42", "route:GET /users/42", "calls"), sourceLang: "typescript"},
+		// unresolvable, repeated to share the batched lookup
+		{ref: ref("Nowhere", "dbo.Nowhere", "uses_table"), sourceLang: "csharp"},
+		{ref: ref("Nowhere", "dbo.Nowhere", "uses_table"), sourceLang: "csharp"},
+		{ref: ref("customers", "", "uses_table"), sourceLang: "java"},
+	}
+
+	batched := resolveBatch(refs, table, crossLang)
+	if len(batched) != len(refs) {
+		t.Fatalf("expected %d results, got %d", len(refs), len(batched))
+	}
+	resolved := 0
+	for i, r := range refs {
+		want := resolveTarget(r.ref, r.localScope, table, crossLang, r.sourceLang)
+		if batched[i] != want {
+			t.Errorf("%s (%s): batched %+v, per-ref %+v", r.ref.ToName, r.sourceLang, batched[i], want)
+		}
+		if want.Resolved {
+			resolved++
+		}
+	}
+	if resolved < len(refs)-6 {
+		t.Errorf("expected the fixture to resolve most references, resolved %d of %d", resolved, len(refs))
+	}
+}
+
+// benchmarkRefs builds a project of n tables, entity classes and routes, and
+// references to each that need the case-insensitive, ORM and route steps.
+func benchmarkRefs(n int) (*SymbolTable, []batchRef) {
+	table := newSymbolTable()
+	add := func(qname, lang string) {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+	}
+	var refs []batchRef
+	for i := range n {
+		add(fmt.Sprintf("dbo.Table%d", i), "tsql")
+		add(fmt.Sprintf("App.Models.Entity%d", i), "csharp")
+		add(fmt.Sprintf("route:GET /items%d/{id}", i), "typescript")
+		refs = append(refs,
+			batchRef{ref: parser.RawReference{ToName: fmt.Sprintf("table%d", i), ReferenceType: "uses_table"}, sourceLang: "java"},
+			batchRef{ref: parser.RawReference{ToName: fmt.Sprintf("Table%ds", i), ReferenceType: "uses_table"}, sourceLang: "csharp"},
+			batchRef{ref: parser.RawReference{ToName: fmt.Sprintf("/api/items%d/42", i), ReferenceType: "calls_api"}, sourceLang: "typescript"},
+		)
+	}
+	return table, refs
+}
+
+func BenchmarkResolvePerRef(b *testing.B) {
+	table, refs := benchmarkRefs(500)
+	crossLang := NewCrossLangResolver(nil)
+	b.ResetTimer()
+	for range b.N {
+		for _, r := range refs {
+			resolveTarget(r.ref, r.localScope, table, crossLang, r.sourceLang)
+		}
+	}
+}
+
+func BenchmarkResolveBatch(b *testing.B) {
+	table, refs := benchmarkRefs(500)
+	crossLang := NewCrossLangResolver(nil)
+	b.ResetTimer()
+	for range b.N {
+		resolveBatch(refs, table, crossLang)
+	}
+}
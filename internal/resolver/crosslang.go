@@ -13,13 +13,13 @@ import (
 type BridgeRule struct {
 	SourceLanguage string // e.g., "delphi", "asp", "java"
 	TargetLanguage string // e.g., "tsql", "pgsql"
-	MatchStrategy  string // exact, case_insensitive, schema_qualified, strip_prefix
+	MatchStrategy  string // exact, case_insensitive, schema_qualified, strip_prefix, orm_convention, hub_method, soap_operation, openapi_operation, api_route
 }
 
 // BridgeMatch represents a successful cross-language resolution with confidence.
 type BridgeMatch struct {
 	TargetID   uuid.UUID
-	Confidence float64 // exact=1.0, schema_qualified=0.95, case_insensitive=0.85, strip_prefix=0.75, orm_convention=0.7
+	Confidence float64 // exact=1.0, schema_qualified=0.95, case_insensitive=0.85, strip_prefix=0.75, orm_convention=0.7, hub_method=0.9, soap_operation=0.9, openapi_operation=0.95, api_route=0.85
 	Strategy   string
 	Bridge     string // e.g., "csharp→tsql"
 }
@@ -28,6 +28,10 @@ type BridgeMatch struct {
 type CrossLangResolver struct {
 	rules  []BridgeRule
 	logger *slog.Logger
+
+	// caseSensitiveRoutes makes api_route compare path segments exactly, for servers
+	// such as Express in strict routing mode; ASP.NET and NestJS by default ignore case
+	caseSensitiveRoutes bool
 }
 
 // NewCrossLangResolver creates a new cross-language resolver.
@@ -37,6 +41,12 @@ func NewCrossLangResolver(logger *slog.Logger) *CrossLangResolver {
 	return c
 }
 
+// SetCaseSensitiveRoutes sets whether request paths must match the case of route
+// templates. Trailing slashes are ignored either way.
+func (c *CrossLangResolver) SetCaseSensitiveRoutes(sensitive bool) {
+	c.caseSensitiveRoutes = sensitive
+}
+
 // RegisterDefaultRules sets up the default cross-language bridge rules.
 func (c *CrossLangResolver) RegisterDefaultRules() {
 	c.rules = []BridgeRule{
@@ -64,12 +74,41 @@ func (c *CrossLangResolver) RegisterDefaultRules() {
 
 		// Delphi T-prefix: strip T from class names when matching SQL objects
 		{SourceLanguage: "delphi", TargetLanguage: "tsql", MatchStrategy: "strip_prefix"},
+
+		// SignalR: JS/TS clients invoking C# hub methods by name
+		{SourceLanguage: "javascript", TargetLanguage: "csharp", MatchStrategy: "hub_method"},
+		{SourceLanguage: "typescript", TargetLanguage: "csharp", MatchStrategy: "hub_method"},
+
+		// SOAP: C# service contract methods calling WSDL operations by name
+		{SourceLanguage: "csharp", TargetLanguage: "wsdl", MatchStrategy: "soap_operation"},
+
+		// HTTP: JS/TS clients calling operations of an OpenAPI spec, which take precedence
+		// over the routes of NestJS, ASP.NET, Spring and JAX-RS controllers; spec operations in turn call
+		// the routes implementing them
+		{SourceLanguage: "javascript", TargetLanguage: "openapi", MatchStrategy: "openapi_operation"},
+		{SourceLanguage: "typescript", TargetLanguage: "openapi", MatchStrategy: "openapi_operation"},
+		{SourceLanguage: "openapi", TargetLanguage: "typescript", MatchStrategy: "api_route"},
+		{SourceLanguage: "openapi", TargetLanguage: "javascript", MatchStrategy: "api_route"},
+		{SourceLanguage: "openapi", TargetLanguage: "csharp", MatchStrategy: "api_route"},
+		{SourceLanguage: "openapi", TargetLanguage: "java", MatchStrategy: "api_route"},
+		{SourceLanguage: "javascript", TargetLanguage: "typescript", MatchStrategy: "api_route"},
+		{SourceLanguage: "typescript", TargetLanguage: "typescript", MatchStrategy: "api_route"},
+		{SourceLanguage: "javascript", TargetLanguage: "csharp", MatchStrategy: "api_route"},
+		{SourceLanguage: "typescript", TargetLanguage: "csharp", MatchStrategy: "api_route"},
+		{SourceLanguage: "javascript", TargetLanguage: "java", MatchStrategy: "api_route"},
+		{SourceLanguage: "typescript", TargetLanguage: "java", MatchStrategy: "api_route"},
 	}
 }
 
 // Resolve attempts to resolve a reference using cross-language bridge rules.
 // Returns a BridgeMatch with confidence and strategy information.
 func (c *CrossLangResolver) Resolve(ref parser.RawReference, sourceLang string, table *SymbolTable) (BridgeMatch, bool) {
+	return c.resolveWith(ref, sourceLang, tableScan{table})
+}
+
+// resolveWith applies the bridge rules for sourceLang in order, answering their name
+// lookups from lookup.
+func (c *CrossLangResolver) resolveWith(ref parser.RawReference, sourceLang string, lookup symbolLookup) (BridgeMatch, bool) {
 	targetName := ref.ToName
 	targetQualified := ref.ToQualified
 	if targetQualified == "" {
@@ -85,21 +124,13 @@ func (c *CrossLangResolver) Resolve(ref parser.RawReference, sourceLang string,
 
 		switch rule.MatchStrategy {
 		case "exact":
-			if id, ok := table.ByFQN[targetQualified]; ok {
+			if id, ok := lookup.exact(targetQualified); ok {
 				return BridgeMatch{TargetID: id, Confidence: 1.0, Strategy: "exact", Bridge: bridge}, true
 			}
 
 		case "case_insensitive":
-			lower := strings.ToLower(targetName)
-			for fqn, id := range table.ByFQN {
-				if strings.ToLower(shortNameOf(fqn)) == lower {
-					// Verify target language matches if available
-					if lang, hasLang := table.ByLang[fqn]; hasLang && matchesLanguage(lang, rule.TargetLanguage) {
-						return BridgeMatch{TargetID: id, Confidence: 0.85, Strategy: "case_insensitive", Bridge: bridge}, true
-					} else if !hasLang {
-						return BridgeMatch{TargetID: id, Confidence: 0.85, Strategy: "case_insensitive", Bridge: bridge}, true
-					}
-				}
+			if id, ok := lookup.shortName(targetName, rule.TargetLanguage); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.85, Strategy: "case_insensitive", Bridge: bridge}, true
 			}
 
 		case "schema_qualified":
@@ -110,11 +141,8 @@ func (c *CrossLangResolver) Resolve(ref parser.RawReference, sourceLang string,
 				targetName,
 			}
 			for _, candidate := range candidates {
-				lower := strings.ToLower(candidate)
-				for fqn, id := range table.ByFQN {
-					if strings.ToLower(fqn) == lower {
-						return BridgeMatch{TargetID: id, Confidence: 0.95, Strategy: "schema_qualified", Bridge: bridge}, true
-					}
+				if id, ok := lookup.qualified(candidate); ok {
+					return BridgeMatch{TargetID: id, Confidence: 0.95, Strategy: "schema_qualified", Bridge: bridge}, true
 				}
 			}
 
@@ -124,34 +152,205 @@ func (c *CrossLangResolver) Resolve(ref parser.RawReference, sourceLang string,
 			if strings.HasPrefix(stripped, "T") && len(stripped) > 1 {
 				stripped = stripped[1:]
 			}
-			lower := strings.ToLower(stripped)
-			for fqn, id := range table.ByFQN {
-				if strings.ToLower(shortNameOf(fqn)) == lower {
-					return BridgeMatch{TargetID: id, Confidence: 0.75, Strategy: "strip_prefix", Bridge: bridge}, true
-				}
+			if id, ok := lookup.shortName(stripped, ""); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.75, Strategy: "strip_prefix", Bridge: bridge}, true
 			}
 
 		case "orm_convention":
 			// ORM naming: try pluralize/singularize
-			variants := ormNameVariants(targetName)
-			for _, variant := range variants {
-				lower := strings.ToLower(variant)
-				for fqn, id := range table.ByFQN {
-					if strings.ToLower(shortNameOf(fqn)) == lower {
-						if lang, hasLang := table.ByLang[fqn]; hasLang && matchesLanguage(lang, rule.TargetLanguage) {
-							return BridgeMatch{TargetID: id, Confidence: 0.7, Strategy: "orm_convention", Bridge: bridge}, true
-						} else if !hasLang {
-							return BridgeMatch{TargetID: id, Confidence: 0.7, Strategy: "orm_convention", Bridge: bridge}, true
-						}
-					}
+			for _, variant := range ormNameVariants(targetName) {
+				if id, ok := lookup.shortName(variant, rule.TargetLanguage); ok {
+					return BridgeMatch{TargetID: id, Confidence: 0.7, Strategy: "orm_convention", Bridge: bridge}, true
 				}
 			}
+
+		case "hub_method":
+			// hub:Send → the one hub endpoint named Send (SignalR matches names case-insensitively)
+			if id, ok := lookup.operation(parser.HubMethodPrefix, targetName, rule.TargetLanguage); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.9, Strategy: "hub_method", Bridge: bridge}, true
+			}
+
+		case "soap_operation":
+			// soap:GetOrder → the one WSDL operation named GetOrder
+			if id, ok := lookup.operation(parser.SOAPOperationPrefix, targetName, rule.TargetLanguage); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.9, Strategy: "soap_operation", Bridge: bridge}, true
+			}
+
+		case "openapi_operation":
+			// GET /api/users/42 → the spec operation GET /users/{id}
+			if ref.ReferenceType != "calls_api" {
+				continue
+			}
+			if id, ok := lookup.route(parser.OpenAPIOperationPrefix, targetName, targetQualified, rule.TargetLanguage, c.caseSensitiveRoutes); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.95, Strategy: "openapi_operation", Bridge: bridge}, true
+			}
+
+		case "api_route":
+			// GET /api/users/42 → the endpoint routed GET /users/{id}
+			if ref.ReferenceType != "calls_api" {
+				continue
+			}
+			if id, ok := lookup.route(parser.APIRoutePrefix, targetName, targetQualified, rule.TargetLanguage, c.caseSensitiveRoutes); ok {
+				return BridgeMatch{TargetID: id, Confidence: 0.85, Strategy: "api_route", Bridge: bridge}, true
+			}
 		}
 	}
 
 	return BridgeMatch{}, false
 }
 
+// symbolLookup answers the name queries of bridge rules. A target language of ""
+// accepts symbols of any language; otherwise symbols of another recorded language are
+// skipped.
+type symbolLookup interface {
+	exact(qualified string) (uuid.UUID, bool)
+	// shortName matches the last part of qualified names case-insensitively.
+	shortName(name, targetLang string) (uuid.UUID, bool)
+	// qualified matches whole qualified names case-insensitively.
+	qualified(name string) (uuid.UUID, bool)
+	operation(prefix, targetName, targetLang string) (uuid.UUID, bool)
+	// route matches the endpoints qualified with prefix, route: or openapi:.
+	route(prefix, path, qualified, targetLang string, caseSensitive bool) (uuid.UUID, bool)
+}
+
+// tableScan answers lookups by scanning every symbol of the table. Where several
+// symbols match, it takes whichever the map iteration reaches first.
+type tableScan struct {
+	table *SymbolTable
+}
+
+func (s tableScan) exact(qualified string) (uuid.UUID, bool) {
+	id, ok := s.table.ByFQN[qualified]
+	return id, ok
+}
+
+func (s tableScan) shortName(name, targetLang string) (uuid.UUID, bool) {
+	lower := strings.ToLower(name)
+	for fqn, id := range s.table.ByFQN {
+		if strings.ToLower(shortNameOf(fqn)) == lower && s.table.inLanguage(fqn, targetLang) {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (s tableScan) qualified(name string) (uuid.UUID, bool) {
+	lower := strings.ToLower(name)
+	for fqn, id := range s.table.ByFQN {
+		if strings.ToLower(fqn) == lower {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (s tableScan) operation(prefix, targetName, targetLang string) (uuid.UUID, bool) {
+	return matchOperation(prefix, targetName, targetLang, s.table)
+}
+
+func (s tableScan) route(prefix, path, qualified, targetLang string, caseSensitive bool) (uuid.UUID, bool) {
+	return matchRoute(prefix, path, qualified, targetLang, caseSensitive, s.table)
+}
+
+// inLanguage reports whether the symbol named fqn may be a target in targetLang: any
+// language when targetLang is "", else its recorded language must match if it has one.
+func (t *SymbolTable) inLanguage(fqn, targetLang string) bool {
+	if targetLang == "" {
+		return true
+	}
+	lang, hasLang := t.ByLang[fqn]
+	return !hasLang || matchesLanguage(lang, targetLang)
+}
+
+// matchOperation resolves a reference named prefix+"Method" (hub:Send, soap:GetOrder)
+// to the endpoint symbol qualified with the same prefix whose short name matches.
+// Callers name only the method, so a name defined by several hubs or services is left
+// unresolved rather than guessed.
+func matchOperation(prefix, targetName, targetLang string, table *SymbolTable) (uuid.UUID, bool) {
+	method, ok := strings.CutPrefix(targetName, prefix)
+	if !ok || method == "" {
+		return uuid.Nil, false
+	}
+	var match uuid.UUID
+	found := 0
+	for fqn, id := range table.ByFQN {
+		if !strings.HasPrefix(fqn, prefix) || !strings.EqualFold(shortNameOf(fqn), method) {
+			continue
+		}
+		if lang, hasLang := table.ByLang[fqn]; hasLang && !matchesLanguage(lang, targetLang) {
+			continue
+		}
+		match = id
+		found++
+	}
+	return match, found == 1
+}
+
+// matchRoute resolves a request path to the endpoint symbol qualified with prefix
+// ("route:GET /users/{id}", "openapi:GET /users/{id}") whose route template it fills, with the request method taken from the qualified target
+// ("route:GET /users/42") when present. An endpoint routed for ALL methods takes any
+// request. Requests may carry a prefix the server adds globally or a proxy strips
+// (/api/users/42), so endpoints matching a suffix of the path are tried when none
+// match it whole. More than one match leaves the request unresolved.
+func matchRoute(prefix, path, qualified, targetLang string, caseSensitive bool, table *SymbolTable) (uuid.UUID, bool) {
+	method := ""
+	if rest, ok := strings.CutPrefix(qualified, parser.APIRoutePrefix); ok {
+		method, _, _ = strings.Cut(rest, " ")
+	}
+	requested := strings.Split(strings.TrimPrefix(parser.NormalizeRoute(path), "/"), "/")
+
+	var whole, suffix []uuid.UUID
+	for fqn, id := range table.ByFQN {
+		rest, ok := strings.CutPrefix(fqn, prefix)
+		if !ok {
+			continue
+		}
+		if lang, hasLang := table.ByLang[fqn]; hasLang && !matchesLanguage(lang, targetLang) {
+			continue
+		}
+		verb, route, _ := strings.Cut(rest, " ")
+		if method != "" && verb != "ALL" && !strings.EqualFold(verb, method) {
+			continue
+		}
+		template := strings.Split(strings.TrimPrefix(route, "/"), "/")
+		switch {
+		case routeFills(template, requested, caseSensitive):
+			whole = append(whole, id)
+		case len(template) < len(requested) && template[0] != "" && routeFills(template, requested[len(requested)-len(template):], caseSensitive):
+			suffix = append(suffix, id)
+		}
+	}
+	if len(whole) > 0 {
+		return whole[0], len(whole) == 1
+	}
+	if len(suffix) > 0 {
+		return suffix[0], len(suffix) == 1
+	}
+	return uuid.Nil, false
+}
+
+// routeFills reports whether the segments of a request path fill a route template:
+// {name} segments take any value, other segments must match (case-insensitively unless
+// caseSensitive), and a request segment built from a variable (${id}, {id}) only fills
+// a template parameter.
+func routeFills(template, requested []string, caseSensitive bool) bool {
+	if len(template) != len(requested) {
+		return false
+	}
+	for i, seg := range template {
+		param := strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+		variable := strings.HasPrefix(requested[i], "${") || strings.HasPrefix(requested[i], "{")
+		switch {
+		case param && requested[i] != "":
+		case variable:
+			return false
+		case caseSensitive && seg != requested[i], !strings.EqualFold(seg, requested[i]):
+			return false
+		}
+	}
+	return true
+}
+
 // ormNameVariants returns naming convention variants for ORM resolution.
 func ormNameVariants(name string) []string {
 	variants := []string{name}
@@ -0,0 +1,115 @@
+package resolver
+
+import (
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// defaultIgnores are well-known framework and standard-library symbols, keyed by the
+// language of the referencing symbol ("" applies to every language). Entries ending in
+// "." or "*" are prefixes; anything else must match the target name exactly. A bare
+// name, without a namespace (Map, Error, express), only applies when the project has no
+// symbol of that name.
+var defaultIgnores = map[string][]string{
+	"csharp": {
+		"System", "System.", "Microsoft.", "Newtonsoft.",
+		"Exception", "Attribute", "IDisposable", "object", "string",
+	},
+	"java": {
+		"java.", "javax.", "jakarta.", "org.springframework.", "lombok.",
+		"Object", "String", "Exception", "RuntimeException", "Serializable",
+	},
+	"javascript": {
+		"React", "React.", "react", "react-dom", "node:", "lodash", "express",
+		"Error", "Promise", "Array", "Object", "Map", "Set",
+	},
+	"typescript": {
+		"React", "React.", "react", "react-dom", "node:", "@angular/", "lodash", "express",
+		"Error", "Promise", "Array", "Object", "Map", "Set",
+	},
+}
+
+// IgnoreList holds external symbols the resolver never tries to resolve, so references to
+// framework types neither cost a project-wide lookup nor pick up a same-named project
+// symbol. Bare names are the exception: they defer to a project symbol of the same name.
+type IgnoreList struct {
+	exact  map[string]map[string]bool // language → qualified names
+	bare   map[string]map[string]bool // language → names without a namespace
+	prefix map[string][]string        // language → prefixes
+}
+
+// NewIgnoreList returns the built-in defaults plus extra entries. Each extra entry is
+// "pattern" (any language) or "language:pattern", e.g. "csharp:Acme.Vendor." or "Moment".
+func NewIgnoreList(extra []string) *IgnoreList {
+	l := &IgnoreList{exact: make(map[string]map[string]bool), bare: make(map[string]map[string]bool), prefix: make(map[string][]string)}
+	for lang, patterns := range defaultIgnores {
+		for _, p := range patterns {
+			l.add(lang, p)
+		}
+	}
+	for _, entry := range extra {
+		entry = strings.TrimSpace(entry)
+		lang, pattern, ok := strings.Cut(entry, ":")
+		// "node:" style prefixes contain a colon too; only known languages are split off.
+		if !ok || !ignoreLanguages[lang] {
+			lang, pattern = "", entry
+		}
+		l.add(lang, pattern)
+	}
+	return l
+}
+
+var ignoreLanguages = map[string]bool{
+	"csharp": true, "java": true, "javascript": true, "typescript": true,
+	"tsql": true, "pgsql": true, "delphi": true, "asp": true,
+}
+
+func (l *IgnoreList) add(lang, pattern string) {
+	switch {
+	case pattern == "":
+	case strings.HasSuffix(pattern, "*"):
+		l.prefix[lang] = append(l.prefix[lang], strings.TrimSuffix(pattern, "*"))
+	case strings.HasSuffix(pattern, ".") || strings.HasSuffix(pattern, "/") || strings.HasSuffix(pattern, ":"):
+		l.prefix[lang] = append(l.prefix[lang], pattern)
+	case !strings.ContainsAny(pattern, "./:@"):
+		if l.bare[lang] == nil {
+			l.bare[lang] = make(map[string]bool)
+		}
+		l.bare[lang][pattern] = true
+	default:
+		if l.exact[lang] == nil {
+			l.exact[lang] = make(map[string]bool)
+		}
+		l.exact[lang][pattern] = true
+	}
+}
+
+// Ignored reports whether ref targets a well-known external symbol for the given
+// source language. A bare name is only ignored when defined, if not nil, reports the
+// project has no symbol of that name. A nil list ignores nothing.
+func (l *IgnoreList) Ignored(lang string, ref parser.RawReference, defined func(name string) bool) bool {
+	if l == nil {
+		return false
+	}
+	target := ref.ToQualified
+	if target == "" {
+		target = ref.ToName
+	}
+	for _, key := range []string{lang, ""} {
+		if l.exact[key][target] || l.exact[key][ref.ToName] {
+			return true
+		}
+		for _, name := range []string{target, ref.ToName} {
+			if l.bare[key][name] && (defined == nil || !defined(name)) {
+				return true
+			}
+		}
+		for _, p := range l.prefix[key] {
+			if strings.HasPrefix(target, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
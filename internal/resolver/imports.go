@@ -0,0 +1,162 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// TSPathsMetadataKey is the project metadata key the parse stage records tsconfig.json
+// module resolution settings under, one []TSPathConfig per source.
+const TSPathsMetadataKey = "tsconfig_paths"
+
+// TSPathConfig holds the module resolution settings of one tsconfig.json. Every path is
+// relative to the root of the source: Dir is the directory the tsconfig applies to, ""
+// for the root, BaseURL its compilerOptions.baseUrl and Paths its compilerOptions.paths
+// with the targets already joined to the directory they are relative to,
+// {"@app/*": ["src/app/*"]}. SourceID is set on loading, as a tsconfig applies only to
+// the files of the source it was found in.
+type TSPathConfig struct {
+	SourceID uuid.UUID           `json:"-"`
+	Dir      string              `json:"dir"`
+	BaseURL  string              `json:"base_url,omitempty"`
+	Paths    map[string][]string `json:"paths,omitempty"`
+}
+
+// importSuffixes are tried in order after an import specifier's path, as TypeScript and
+// bundlers do for extensionless imports and directory index files.
+var importSuffixes = []string{
+	"", ".ts", ".tsx", ".d.ts", ".js", ".jsx", ".mjs", ".cjs",
+	"/index.ts", "/index.tsx", "/index.js", "/index.jsx",
+}
+
+// isModuleImport reports whether a reference is a JavaScript or TypeScript import of a
+// module specifier, './user' or '@app/services/user', rather than of a symbol.
+func isModuleImport(referenceType, lang string) bool {
+	return referenceType == "imports" && (lang == "javascript" || lang == "typescript")
+}
+
+// loadTSPaths returns the tsconfig settings recorded for every source of a project,
+// innermost directory first so the nearest tsconfig of a file is found first.
+func (e *Engine) loadTSPaths(ctx context.Context, projectID uuid.UUID) ([]TSPathConfig, error) {
+	values, err := e.store.ListProjectMetadataValues(ctx, postgres.ListProjectMetadataValuesParams{
+		ProjectID: projectID,
+		Key:       TSPathsMetadataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var configs []TSPathConfig
+	for _, v := range values {
+		var cs []TSPathConfig
+		if err := json.Unmarshal(v.Value, &cs); err != nil {
+			return nil, err
+		}
+		for i := range cs {
+			cs[i].SourceID = v.SourceID
+		}
+		configs = append(configs, cs...)
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return len(configs[i].Dir) > len(configs[j].Dir)
+	})
+	return configs, nil
+}
+
+// resolveImport resolves a module specifier imported by the file at fromPath to the
+// module symbol of the file it names. Aliases from the nearest tsconfig.json are
+// rewritten to their targets first, then relative specifiers are joined to the importing
+// file's directory. Other specifiers, such as 'react', are packages outside the project
+// unless they name a file under the baseUrl, and come back ignored.
+func resolveImport(specifier, fromPath string, table *SymbolTable) resolveResult {
+	source := table.FileSource[table.FileByPath[fromPath]]
+	candidates, inProject := importCandidates(specifier, fromPath, source, table.TSPaths)
+	for _, base := range candidates {
+		for _, suffix := range importSuffixes {
+			fileID, ok := table.FileByPath[base+suffix]
+			if !ok {
+				continue
+			}
+			if id := inferSourceFromFileSymbols(fileID, table); id != uuid.Nil {
+				return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+			}
+		}
+	}
+	if !inProject {
+		return resolveResult{Ignored: true}
+	}
+	return resolveResult{}
+}
+
+// importCandidates returns the project-relative paths, without extension, that a module
+// specifier imported by the file at fromPath of source may name, and whether it is sure
+// to name a project file: a relative specifier or one matching a paths alias.
+func importCandidates(specifier, fromPath string, source uuid.UUID, configs []TSPathConfig) ([]string, bool) {
+	if specifier == "." || specifier == ".." || strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") {
+		return []string{path.Join(path.Dir(fromPath), specifier)}, true
+	}
+	if strings.HasPrefix(specifier, "/") {
+		return nil, false
+	}
+	cfg := tsConfigFor(fromPath, source, configs)
+	if cfg == nil {
+		return nil, false
+	}
+	candidates := matchTSPaths(specifier, cfg.Paths)
+	inProject := len(candidates) > 0
+	if cfg.BaseURL != "" {
+		candidates = append(candidates, path.Join(cfg.BaseURL, specifier))
+	}
+	return candidates, inProject
+}
+
+// tsConfigFor returns the first config of source whose directory contains file, or nil;
+// with configs sorted longest directory first, that is the innermost.
+func tsConfigFor(file string, source uuid.UUID, configs []TSPathConfig) *TSPathConfig {
+	for i, c := range configs {
+		if c.SourceID != source {
+			continue
+		}
+		if c.Dir == "" || c.Dir == "." || strings.HasPrefix(file, c.Dir+"/") {
+			return &configs[i]
+		}
+	}
+	return nil
+}
+
+// matchTSPaths rewrites a specifier with the paths pattern TypeScript would pick: an
+// exact pattern, else the wildcard pattern with the longest prefix, its * replaced in
+// each target by what it matched.
+func matchTSPaths(specifier string, paths map[string][]string) []string {
+	if targets, ok := paths[specifier]; ok && !strings.Contains(specifier, "*") {
+		return targets
+	}
+	best, bestPrefix, capture := "", -1, ""
+	for pattern := range paths {
+		prefix, suffix, ok := strings.Cut(pattern, "*")
+		if !ok || len(specifier) < len(prefix)+len(suffix) {
+			continue
+		}
+		if !strings.HasPrefix(specifier, prefix) || !strings.HasSuffix(specifier, suffix) {
+			continue
+		}
+		// Ties go to the lexically smaller pattern so map order cannot change the result.
+		if len(prefix) > bestPrefix || (len(prefix) == bestPrefix && pattern < best) {
+			best, bestPrefix, capture = pattern, len(prefix), specifier[len(prefix):len(specifier)-len(suffix)]
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	var out []string
+	for _, target := range paths[best] {
+		out = append(out, strings.Replace(target, "*", capture, 1))
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package resolver
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Confidence of inferred relationships: a column matching a declared primary key, and
+// one matching an undeclared "id" column taken to be its table's key.
+const (
+	inferredFKConfidence   = 0.8
+	inferredIDFKConfidence = 0.6
+)
+
+// columnTypeAliases folds the spellings of a column type that hold the same values, so
+// that an int column can reference a serial key.
+var columnTypeAliases = map[string]string{
+	"integer": "int", "int4": "int", "serial": "int", "serial4": "int",
+	"int8": "bigint", "bigserial": "bigint", "serial8": "bigint",
+	"int2": "smallint", "smallserial": "smallint",
+	"uniqueidentifier": "uuid",
+}
+
+// inferredFK is a column whose name and type match another table's primary key,
+// although no foreign key declares the relationship.
+type inferredFK struct {
+	SourceID   uuid.UUID // the referencing column
+	TargetID   uuid.UUID // the key column it matches
+	Confidence float64
+}
+
+// keyColumn is a table's single-column primary key.
+type keyColumn struct {
+	table      string
+	column     postgres.Symbol
+	confidence float64
+}
+
+// inferForeignKeys finds columns shaped like foreign keys that no foreign key declares:
+// Orders.CustomerId or orders.customer_id of the same type as the primary key Id of
+// Customers. A key column matches its table's name, singular or plural, followed by the
+// key's name; a key not named id also matches by its name alone. Tables without a
+// declared primary key take an id column as their key, at lower confidence. Columns
+// with a declared foreign key, given as "table.column" in declared, and columns
+// matching more than one key are skipped.
+func inferForeignKeys(symbols []postgres.Symbol, declared map[string]bool) []inferredFK {
+	columns := make(map[string]postgres.Symbol)
+	byTable := make(map[string][]postgres.Symbol)
+	for _, s := range symbols {
+		if s.Kind != "column" {
+			continue
+		}
+		table, _, ok := cutLast(s.QualifiedName)
+		if !ok {
+			continue
+		}
+		columns[strings.ToLower(s.QualifiedName)] = s
+		byTable[table] = append(byTable[table], s)
+	}
+
+	// Key columns by the names a referencing column would have
+	keys := make(map[string][]keyColumn)
+	addKey := func(table string, col postgres.Symbol, confidence float64) {
+		k := keyColumn{table: table, column: col, confidence: confidence}
+		tableName := fkName(shortNameOf(table))
+		colName := fkName(col.Name)
+		names := []string{tableName + colName, singular(tableName) + colName}
+		if colName != "id" {
+			names = append(names, colName)
+		}
+		seen := make(map[string]bool)
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				keys[n] = append(keys[n], k)
+			}
+		}
+	}
+	keyed := make(map[string]bool)
+	for _, s := range symbols {
+		if s.Kind != "index" || s.Signature == nil {
+			continue
+		}
+		cols, ok := strings.CutPrefix(*s.Signature, "primary key (")
+		if !ok || strings.Contains(cols, ",") {
+			continue
+		}
+		table, _, ok := cutLast(s.QualifiedName)
+		if !ok {
+			continue
+		}
+		keyed[table] = true
+		if col, ok := columns[strings.ToLower(table+"."+strings.TrimSuffix(cols, ")"))]; ok {
+			addKey(table, col, inferredFKConfidence)
+		}
+	}
+	for table, cols := range byTable {
+		if keyed[table] {
+			continue
+		}
+		for _, col := range cols {
+			if fkName(col.Name) == "id" {
+				addKey(table, col, inferredIDFKConfidence)
+			}
+		}
+	}
+
+	var inferred []inferredFK
+	for _, col := range columns {
+		if declared[strings.ToLower(col.QualifiedName)] {
+			continue
+		}
+		table, _, _ := cutLast(col.QualifiedName)
+		if columnType(col) == "" {
+			continue
+		}
+		var match *keyColumn
+		for _, k := range keys[fkName(col.Name)] {
+			if k.table == table || k.column.ID == col.ID || columnType(k.column) != columnType(col) {
+				continue
+			}
+			if match != nil {
+				match = nil // ambiguous
+				break
+			}
+			match = &k
+		}
+		if match != nil {
+			inferred = append(inferred, inferredFK{SourceID: col.ID, TargetID: match.column.ID, Confidence: match.confidence})
+		}
+	}
+	return inferred
+}
+
+// cutLast splits a qualified name at its last dot.
+func cutLast(qname string) (prefix, name string, ok bool) {
+	i := strings.LastIndex(qname, ".")
+	if i < 0 {
+		return "", "", false
+	}
+	return qname[:i], qname[i+1:], true
+}
+
+// fkName folds a table or column name for matching, so CustomerId, customer_id and
+// [CustomerID] compare equal.
+func fkName(name string) string {
+	name = strings.Trim(name, `[]"`+"`")
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// singular strips the plural ending of a folded table name: categories, addresses,
+// orders.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// columnType returns a column's base type, without its length or precision, or "" when
+// its type is unknown.
+func columnType(col postgres.Symbol) string {
+	if col.Signature == nil {
+		return ""
+	}
+	t, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(*col.Signature)), "(")
+	t = strings.TrimPrefix(strings.TrimSpace(t), "pg_catalog.")
+	if alias, ok := columnTypeAliases[t]; ok {
+		return alias
+	}
+	return t
+}
@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// boxedTypes maps the boxed and aliased spellings of primitive types to one name, so
+// an int argument fits an Integer parameter and a C# Int32 an int one.
+var boxedTypes = map[string]string{
+	"integer": "int", "int32": "int", "int64": "long", "int16": "short", "character": "char",
+	"bool": "boolean", "single": "float",
+}
+
+// wideningTypes lists the numeric types each argument type converts to implicitly.
+var wideningTypes = map[string][]string{
+	"byte":  {"short", "int", "long", "float", "double", "decimal"},
+	"short": {"int", "long", "float", "double", "decimal"},
+	"char":  {"int", "long", "float", "double"},
+	"int":   {"long", "float", "double", "decimal"},
+	"long":  {"float", "double", "decimal"},
+	"float": {"double"},
+}
+
+// sigParam is a parameter of a normalized signature.
+type sigParam struct {
+	typ      string
+	optional bool // has a default value
+	variadic bool // takes any number of arguments of typ
+}
+
+// parseSignature splits a normalized signature, "(int,List<String>,bool=,string...)",
+// into its parameters. ok is false when sig is not one.
+func parseSignature(sig string) (params []sigParam, ok bool) {
+	if !strings.HasPrefix(sig, "(") || !strings.HasSuffix(sig, ")") {
+		return nil, false
+	}
+	inner := sig[1 : len(sig)-1]
+	if inner == "" {
+		return nil, true
+	}
+	depth, start := 0, 0
+	split := func(end int) {
+		p := sigParam{typ: inner[start:end]}
+		if t, ok := strings.CutSuffix(p.typ, "..."); ok {
+			p.typ, p.variadic = t, true
+		} else if t, ok := strings.CutSuffix(p.typ, "="); ok {
+			p.typ, p.optional = t, true
+		}
+		params = append(params, p)
+	}
+	for i, r := range inner {
+		switch r {
+		case '<', '(', '[':
+			depth++
+		case '>', ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				split(i)
+				start = i + 1
+			}
+		}
+	}
+	split(len(inner))
+	return params, true
+}
+
+// fit scores how well a call's arguments fit a normalized signature: ok when their
+// number does and each argument of known type converts to its parameter's, exact the
+// number of arguments whose type is the parameter's own.
+func fit(sig string, args []string) (exact int, ok bool) {
+	params, ok := parseSignature(sig)
+	if !ok {
+		return 0, false
+	}
+	required, variadic := 0, false
+	for _, p := range params {
+		switch {
+		case p.variadic:
+			variadic = true
+		case !p.optional:
+			required++
+		}
+	}
+	if len(args) < required || (!variadic && len(args) > len(params)) {
+		return 0, false
+	}
+	for i, arg := range args {
+		if arg == "" {
+			continue
+		}
+		// Arguments past the last parameter are the variadic one's
+		p := params[min(i, len(params)-1)]
+		argType, paramType := simpleType(arg), simpleType(p.typ)
+		switch {
+		case argType == paramType:
+			exact++
+		case paramType == "object" || converts(argType, paramType):
+		default:
+			return 0, false
+		}
+	}
+	return exact, true
+}
+
+// simpleType reduces a type to the name compared when matching overloads: lowercased,
+// without namespace, type arguments or nullability, and with boxed and aliased
+// primitives folded (Integer as int, String as string).
+func simpleType(t string) string {
+	t = strings.ToLower(t)
+	if i := strings.IndexByte(t, '<'); i >= 0 {
+		t = t[:i] + t[strings.LastIndexByte(t, '>')+1:]
+	}
+	t = strings.TrimSuffix(t, "?")
+	if folded, ok := boxedTypes[t]; ok {
+		return folded
+	}
+	if i := strings.LastIndexByte(t, '.'); i >= 0 {
+		t = t[i+1:]
+	}
+	if folded, ok := boxedTypes[t]; ok {
+		return folded
+	}
+	return t
+}
+
+// converts reports whether an argument of type from widens implicitly to type to.
+func converts(from, to string) bool {
+	for _, t := range wideningTypes[from] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// overloadFor picks among same-named candidates the one whose normalized signature
+// best fits a call's arguments: the only one that fits, or the only one of those with
+// the most arguments of exactly their parameter's type. Candidates without a signature
+// are not considered.
+func (t *SymbolTable) overloadFor(candidates []uuid.UUID, args []string) (uuid.UUID, bool) {
+	best, bestExact, tied := uuid.Nil, -1, false
+	for _, id := range candidates {
+		sig, ok := t.Signatures[id]
+		if !ok {
+			continue
+		}
+		exact, ok := fit(sig, args)
+		switch {
+		case !ok:
+		case exact > bestExact:
+			best, bestExact, tied = id, exact, false
+		case exact == bestExact:
+			tied = true
+		}
+	}
+	return best, best != uuid.Nil && !tied
+}
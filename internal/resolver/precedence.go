@@ -0,0 +1,95 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/parser"
+)
+
+// demotedConfidence scales the confidence of a table mapping outranked by a more
+// explicit one for the same symbol.
+const demotedConfidence = 0.5
+
+// DefaultTablePrecedence ranks table mapping sources from most to least authoritative:
+// an explicit attribute beats an ORM convention, which beats a table inferred from SQL.
+func DefaultTablePrecedence() []string {
+	return []string{parser.MappingAttribute, parser.MappingORM, parser.MappingSQL}
+}
+
+// TablePrecedence settles conflicting uses_table mappings: when one entity is mapped to
+// tables by sources of different rank ([Table("Users")] on a class that also appears as
+// DbSet<User> and as Set<User>().FromSqlRaw against tbl_users), only the highest-ranked
+// mapping is kept as is, and the rest are dropped or demoted.
+type TablePrecedence struct {
+	rank map[string]int // mapping source → rank, 0 = most authoritative
+	drop bool           // drop outranked mappings rather than demote them
+}
+
+// NewTablePrecedence ranks the given mapping sources in order (DefaultTablePrecedence
+// when empty). Outranked mappings are dropped when drop is set and written with reduced
+// confidence otherwise.
+func NewTablePrecedence(order []string, drop bool) (*TablePrecedence, error) {
+	if len(order) == 0 {
+		order = DefaultTablePrecedence()
+	}
+	known := make(map[string]bool)
+	for _, m := range DefaultTablePrecedence() {
+		known[m] = true
+	}
+	p := &TablePrecedence{rank: make(map[string]int), drop: drop}
+	for i, m := range order {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if !known[m] {
+			return nil, fmt.Errorf("unknown table mapping source %q (want attribute, orm or sql)", m)
+		}
+		if _, dup := p.rank[m]; dup {
+			return nil, fmt.Errorf("table mapping source %q listed twice", m)
+		}
+		p.rank[m] = i
+	}
+	return p, nil
+}
+
+// outranked returns, for each reference in refs superseded by a more authoritative
+// mapping of the same entity, the winning mapping source keyed by index. Entities are
+// matched by type name, as an ORM names the type a table holds without its namespace.
+// References without a ranked mapping source or an entity never conflict, so the other
+// tables a class reads or writes are kept. A nil precedence supersedes nothing.
+func (p *TablePrecedence) outranked(refs []parser.RawReference) map[int]string {
+	if p == nil {
+		return nil
+	}
+	best := make(map[string]string) // entity type name → best mapping source
+	for _, ref := range refs {
+		entity, r, ok := p.ranked(ref)
+		if !ok {
+			continue
+		}
+		if cur, seen := best[entity]; !seen || r < p.rank[cur] {
+			best[entity] = ref.Mapping
+		}
+	}
+
+	var out map[int]string
+	for i, ref := range refs {
+		entity, r, ok := p.ranked(ref)
+		if !ok || r == p.rank[best[entity]] {
+			continue
+		}
+		if out == nil {
+			out = make(map[int]string)
+		}
+		out[i] = best[entity]
+	}
+	return out
+}
+
+// ranked returns the entity type name a table mapping is for and the rank of its source.
+func (p *TablePrecedence) ranked(ref parser.RawReference) (string, int, bool) {
+	if ref.ReferenceType != "uses_table" || ref.Mapping == "" || ref.Entity == "" {
+		return "", 0, false
+	}
+	r, ok := p.rank[ref.Mapping]
+	return shortNameOf(ref.Entity), r, ok
+}
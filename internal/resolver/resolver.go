@@ -12,30 +12,58 @@ import (
 	"github.com/maraichr/lattice/internal/parser"
 	"github.com/maraichr/lattice/internal/store"
 	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/models"
 )
 
 // Engine performs cross-file symbol resolution within a project.
 type Engine struct {
-	store     *store.Store
-	crossLang *CrossLangResolver
-	logger    *slog.Logger
+	store         *store.Store
+	crossLang     *CrossLangResolver
+	ignore        *IgnoreList
+	precedence    *TablePrecedence
+	edgeBatchSize int
+	logger        *slog.Logger
 }
 
-func NewEngine(s *store.Store, logger *slog.Logger) *Engine {
+// NewEngine creates a resolver writing edges edgeBatchSize at a time
+// (store.DefaultEdgeBatchSize if <= 0). Conflicting table mappings are settled by
+// DefaultTablePrecedence, dropping outranked ones.
+func NewEngine(s *store.Store, ignore *IgnoreList, edgeBatchSize int, logger *slog.Logger) *Engine {
+	precedence, _ := NewTablePrecedence(nil, true)
 	return &Engine{
-		store:     s,
-		crossLang: NewCrossLangResolver(logger),
-		logger:    logger,
+		store:         s,
+		crossLang:     NewCrossLangResolver(logger),
+		ignore:        ignore,
+		precedence:    precedence,
+		edgeBatchSize: edgeBatchSize,
+		logger:        logger,
 	}
 }
 
+// SetCaseSensitiveRoutes sets whether API requests must match the case of the routes
+// they call.
+func (e *Engine) SetCaseSensitiveRoutes(sensitive bool) {
+	e.crossLang.SetCaseSensitiveRoutes(sensitive)
+}
+
+// SetTablePrecedence replaces how conflicting table mappings are settled; nil keeps
+// every mapping.
+func (e *Engine) SetTablePrecedence(p *TablePrecedence) {
+	e.precedence = p
+}
+
 // SymbolTable indexes all symbols in a project for fast lookup.
 type SymbolTable struct {
 	ByFQN       map[string]uuid.UUID   // qualified_name → symbol ID
 	ByShortName map[string][]uuid.UUID // short name → candidate IDs
-	ByFile      map[uuid.UUID][]uuid.UUID // file ID → symbol IDs
+	ByFile      map[uuid.UUID][]uuid.UUID // file ID → symbol IDs, in declaration order
+	Modules     map[uuid.UUID]uuid.UUID   // file ID → the module symbol of the file, where the parser declares one
 	FileByPath  map[string]uuid.UUID   // file path → file ID
+	FileSource  map[uuid.UUID]uuid.UUID   // file ID → source ID
 	ByLang      map[string]string      // qualified_name → language
+	Databases   map[string]bool        // lowercased declared database names/identifiers (e.g. from Terraform)
+	TSPaths     []TSPathConfig         // tsconfig.json module resolution settings, innermost directory first
+	Signatures  map[uuid.UUID]string   // method ID → normalized signature, for picking overloads
 }
 
 func newSymbolTable() *SymbolTable {
@@ -43,8 +71,12 @@ func newSymbolTable() *SymbolTable {
 		ByFQN:       make(map[string]uuid.UUID),
 		ByShortName: make(map[string][]uuid.UUID),
 		ByFile:      make(map[uuid.UUID][]uuid.UUID),
+		Modules:     make(map[uuid.UUID]uuid.UUID),
 		FileByPath:  make(map[string]uuid.UUID),
+		FileSource:  make(map[uuid.UUID]uuid.UUID),
 		ByLang:      make(map[string]string),
+		Databases:   make(map[string]bool),
+		Signatures:  make(map[uuid.UUID]string),
 	}
 }
 
@@ -68,6 +100,7 @@ func (e *Engine) Resolve(ctx context.Context, projectID uuid.UUID, parseResults
 
 	for _, f := range files {
 		table.FileByPath[f.Path] = f.ID
+		table.FileSource[f.ID] = f.SourceID
 	}
 
 	for _, sym := range symbols {
@@ -76,6 +109,20 @@ func (e *Engine) Resolve(ctx context.Context, projectID uuid.UUID, parseResults
 		table.ByShortName[shortName] = append(table.ByShortName[shortName], sym.ID)
 		table.ByFile[sym.FileID] = append(table.ByFile[sym.FileID], sym.ID)
 		table.ByLang[sym.QualifiedName] = sym.Language
+		if sym.Kind == "module" {
+			table.Modules[sym.FileID] = sym.ID
+		}
+		if sym.Kind == "database" {
+			table.addDatabase(sym)
+		}
+		if sym.Kind == "method" {
+			table.addSignature(sym)
+		}
+	}
+
+	// Path aliases are best-effort: without them only relative imports resolve
+	if table.TSPaths, err = e.loadTSPaths(ctx, projectID); err != nil {
+		e.logger.Warn("load tsconfig paths", slog.String("error", err.Error()))
 	}
 
 	// Build file-local symbol sets for scope resolution
@@ -88,10 +135,25 @@ func (e *Engine) Resolve(ctx context.Context, projectID uuid.UUID, parseResults
 		fileSymbols[sym.FileID][sym.Name] = sym.ID
 	}
 
-	created := 0
+	ignored, unresolved, superseded := 0, 0, 0
+
+	// Table mappings outranked by a more explicit one for the same entity. An entity is
+	// often mapped in one file ([Table] on the class) and again in another (a DbSet on the
+	// context), so the mappings of every file are settled together
+	var allRefs []parser.RawReference
+	for _, fr := range parseResults {
+		allRefs = append(allRefs, fr.References...)
+	}
+	outranked := e.precedence.outranked(allRefs)
+	offset := 0
 
-	// For each file's unresolved references, attempt cross-file resolution
+	// For each file's unresolved references, find the source and queue the target for
+	// resolution; module imports name a file rather than a symbol and resolve here
+	var queue []queuedRef
+	var batch []batchRef
 	for _, fr := range parseResults {
+		base := offset
+		offset += len(fr.References)
 		fileID, ok := table.FileByPath[fr.Path]
 		if !ok {
 			continue
@@ -99,80 +161,229 @@ func (e *Engine) Resolve(ctx context.Context, projectID uuid.UUID, parseResults
 
 		localScope := fileSymbols[fileID]
 
-		for _, ref := range fr.References {
+		// Unresolved references are rebuilt per file on every run
+		if err := e.store.DeleteUnresolvedReferencesByFile(ctx, fileID); err != nil {
+			e.logger.Warn("clear unresolved references", slog.String("file", fr.Path), slog.String("error", err.Error()))
+		}
+
+		for i, ref := range fr.References {
+			winner, demoted := outranked[base+i]
+			if demoted && e.precedence.drop {
+				superseded++
+				continue
+			}
+
 			sourceID, ok := localScope[ref.FromSymbol]
 			if !ok {
 				// Source symbol not in this file's scope — try project-wide
 				sourceID, ok = table.ByFQN[ref.FromSymbol]
 			}
-			// When FromSymbol is empty but ToName is set (e.g. C# [Table("X")] fallback), infer source from this file's symbols
-			if !ok && ref.FromSymbol == "" && ref.ToName != "" && ref.ReferenceType == "uses_table" {
+			// When FromSymbol is empty but ToName is set (e.g. C# [Table("X")] fallback, a
+			// top-level Mongoose model or a JS/TS module import), infer source from this
+			// file's symbols
+			if !ok && ref.FromSymbol == "" && ref.ToName != "" && (ref.ReferenceType == "uses_table" || ref.ReferenceType == "uses_collection" || isModuleImport(ref.ReferenceType, fr.Language)) {
 				sourceID = inferSourceFromFileSymbols(fileID, table)
 			}
 			if sourceID == uuid.Nil {
 				continue
 			}
 
-			// Try to resolve the target
-			result := resolveTarget(ref, localScope, table, e.crossLang, fr.Language)
-			if !result.Resolved {
-				continue
+			q := queuedRef{ref: ref, fileID: fileID, sourceID: sourceID, demoted: demoted, winner: winner, batched: -1}
+			switch {
+			case isModuleImport(ref.ReferenceType, fr.Language):
+				q.result = resolveImport(ref.ToName, fr.Path, table)
+			case e.ignored(ref, table, fr.Language):
+				q.result = resolveResult{Ignored: true}
+			default:
+				q.batched = len(batch)
+				batch = append(batch, batchRef{ref: ref, localScope: localScope, sourceLang: fr.Language})
 			}
+			queue = append(queue, q)
+		}
+	}
 
-			// Skip self-references
-			if sourceID == result.TargetID {
-				continue
+	// Resolve the queued targets together, so the cross-language indexes are built once
+	results := resolveBatch(batch, table, e.crossLang)
+
+	// Best-effort: unresolved references are kept so tools can explain why an expected
+	// edge is missing, written in batches of the edge batch size
+	missBatchSize := e.edgeBatchSize
+	if missBatchSize <= 0 {
+		missBatchSize = store.DefaultEdgeBatchSize
+	}
+	misses := postgres.BatchCreateUnresolvedReferencesParams{ProjectID: projectID}
+	flushMisses := func() {
+		if len(misses.SourceIds) == 0 {
+			return
+		}
+		n, err := e.store.BatchCreateUnresolvedReferences(ctx, misses)
+		if err != nil {
+			e.logger.Warn("record unresolved references", slog.Int("count", len(misses.SourceIds)), slog.String("error", err.Error()))
+		}
+		unresolved += int(n)
+		misses = postgres.BatchCreateUnresolvedReferencesParams{ProjectID: projectID}
+	}
+
+	ew := store.NewEdgeWriter(e.store, e.edgeBatchSize, e.logger)
+	for _, q := range queue {
+		ref, sourceID, demoted := q.ref, q.sourceID, q.demoted
+		result := q.result
+		if q.batched >= 0 {
+			result = results[q.batched]
+		}
+		if result.Ignored {
+			ignored++
+			continue
+		}
+		if !result.Resolved {
+			misses.FileIds = append(misses.FileIds, q.fileID)
+			misses.SourceIds = append(misses.SourceIds, sourceID)
+			misses.ToNames = append(misses.ToNames, ref.ToName)
+			misses.ToQualified = append(misses.ToQualified, ref.ToQualified)
+			misses.ReferenceTypes = append(misses.ReferenceTypes, ref.ReferenceType)
+			misses.Lines = append(misses.Lines, int32(ref.Line))
+			if len(misses.SourceIds) >= missBatchSize {
+				flushMisses()
 			}
+			continue
+		}
 
-			// Determine confidence: use ref's confidence if set, otherwise from resolution
-			confidence := result.Confidence
-			if ref.Confidence > 0 && confidence > 0 {
-				// Multiply parser confidence with resolution confidence
-				confidence = ref.Confidence * confidence
-			} else if ref.Confidence > 0 {
-				confidence = ref.Confidence
+		// Skip self-references
+		if sourceID == result.TargetID {
+			continue
+		}
+
+		// Determine confidence: use ref's confidence if set, otherwise from resolution
+		confidence := result.Confidence
+		if ref.Confidence > 0 && confidence > 0 {
+			// Multiply parser confidence with resolution confidence
+			confidence = ref.Confidence * confidence
+		} else if ref.Confidence > 0 {
+			confidence = ref.Confidence
+		}
+		if demoted {
+			if confidence == 0 {
+				confidence = 1
 			}
+			confidence *= demotedConfidence
+			superseded++
+		}
 
-			// Use CreateSymbolEdgeWithMetadata for cross-language edges with confidence
+		// Cross-language edges, demoted table mappings and references the parser was
+		// unsure of carry their confidence, cross-language edges their match strategy,
+		// calls guarded by a feature flag are marked conditional, and demoted table
+		// mappings name the source that outranked them
+		uncertain := ref.Confidence > 0 && ref.Confidence < 1
+		var metaJSON []byte
+		if result.CrossLang || ref.Conditional || demoted || uncertain {
+			meta := map[string]interface{}{}
+			if result.CrossLang || demoted || uncertain {
+				meta["confidence"] = confidence
+			}
 			if result.CrossLang {
-				meta := map[string]interface{}{
-					"confidence":     confidence,
-					"match_strategy": result.Strategy,
-					"bridge":         result.Bridge,
-				}
-				metaJSON, _ := json.Marshal(meta)
-				_, err := e.store.CreateSymbolEdgeWithMetadata(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
-					ProjectID: projectID,
-					SourceID:  sourceID,
-					TargetID:  result.TargetID,
-					EdgeType:  ref.ReferenceType,
-					Metadata:  metaJSON,
-				})
-				if err != nil {
-					continue
-				}
-			} else {
-				_, err := e.store.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{
-					ProjectID: projectID,
-					SourceID:  sourceID,
-					TargetID:  result.TargetID,
-					EdgeType:  ref.ReferenceType,
-				})
-				if err != nil {
-					continue
-				}
+				meta["match_strategy"] = result.Strategy
+				meta["bridge"] = result.Bridge
+			}
+			if ref.Conditional {
+				meta["conditional"] = true
 			}
-			created++
+			if demoted {
+				meta["mapping"] = ref.Mapping
+				meta["superseded_by"] = q.winner
+			}
+			metaJSON, _ = json.Marshal(meta)
+		}
+		if err := ew.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+			ProjectID: projectID,
+			SourceID:  sourceID,
+			TargetID:  result.TargetID,
+			EdgeType:  ref.ReferenceType,
+			Metadata:  metaJSON,
+		}); err != nil {
+			return ew.Written(), fmt.Errorf("write edges: %w", err)
 		}
 	}
 
+	flushMisses()
+	if err := ew.Flush(ctx); err != nil {
+		return ew.Written(), fmt.Errorf("write edges: %w", err)
+	}
+	created := ew.Written()
+
+	related, err := e.relateInferredForeignKeys(ctx, projectID, symbols)
+	if err != nil {
+		return created, err
+	}
+
 	e.logger.Info("cross-file resolution complete",
 		slog.Int("edges_created", created),
+		slog.Int("edges_skipped", ew.Skipped()),
+		slog.Int("inferred_relationships", related),
+		slog.Int("refs_ignored", ignored),
+		slog.Int("refs_unresolved", unresolved),
+		slog.Int("table_mappings_superseded", superseded),
 		slog.Int("symbols_indexed", len(symbols)))
 
 	return created, nil
 }
 
+// relateInferredForeignKeys replaces the project's related_to edges with one from each
+// column shaped like a foreign key that no foreign key declares to the key it matches
+// (see inferForeignKeys). They carry their confidence and are marked inferred, apart from
+// the declared foreign_key edges, and lineage follows them only when asked to.
+func (e *Engine) relateInferredForeignKeys(ctx context.Context, projectID uuid.UUID, symbols []postgres.Symbol) (int, error) {
+	if _, err := e.store.DeleteEdgesByType(ctx, postgres.DeleteEdgesByTypeParams{
+		ProjectID: projectID,
+		EdgeType:  string(models.EdgeTypeRelatedTo),
+	}); err != nil {
+		return 0, fmt.Errorf("clear inferred relationships: %w", err)
+	}
+	fks, err := e.store.ListEdgeEndpointsByType(ctx, postgres.ListEdgeEndpointsByTypeParams{
+		ProjectID: projectID,
+		EdgeType:  string(models.EdgeTypeForeignKey),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("load foreign keys: %w", err)
+	}
+	declared := make(map[string]bool, len(fks))
+	for _, fk := range fks {
+		declared[strings.ToLower(fk.SourceName)] = true
+	}
+
+	ew := store.NewEdgeWriter(e.store, e.edgeBatchSize, e.logger)
+	for _, fk := range inferForeignKeys(symbols, declared) {
+		meta, _ := json.Marshal(map[string]interface{}{
+			"confidence":     fk.Confidence,
+			"inferred":       true,
+			"match_strategy": "naming",
+		})
+		if err := ew.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+			ProjectID: projectID,
+			SourceID:  fk.SourceID,
+			TargetID:  fk.TargetID,
+			EdgeType:  string(models.EdgeTypeRelatedTo),
+			Metadata:  meta,
+		}); err != nil {
+			return ew.Written(), fmt.Errorf("write inferred relationships: %w", err)
+		}
+	}
+	if err := ew.Flush(ctx); err != nil {
+		return ew.Written(), fmt.Errorf("write inferred relationships: %w", err)
+	}
+	return ew.Written(), nil
+}
+
+// queuedRef is a reference whose source is known, waiting for its target.
+type queuedRef struct {
+	ref      parser.RawReference
+	fileID   uuid.UUID
+	sourceID uuid.UUID
+	demoted  bool   // outranked table mapping kept at lower confidence
+	winner   string // the mapping that outranked it
+	result   resolveResult
+	batched  int // index of the result from resolveBatch, -1 when resolved in place
+}
+
 // resolveResult holds the outcome of target resolution.
 type resolveResult struct {
 	TargetID   uuid.UUID
@@ -181,45 +392,113 @@ type resolveResult struct {
 	Bridge     string
 	CrossLang  bool
 	Resolved   bool
+	Ignored    bool // target is a well-known external symbol on the ignore list
+}
+
+// resolveRef resolves one reference, short-circuiting targets on the ignore list. The
+// referencing symbol's language selects the per-language ignores.
+func (e *Engine) resolveRef(ref parser.RawReference, localScope map[string]uuid.UUID, table *SymbolTable, fileLang string) resolveResult {
+	if e.ignored(ref, table, fileLang) {
+		return resolveResult{Ignored: true}
+	}
+	return resolveTarget(ref, localScope, table, e.crossLang, fileLang)
+}
+
+// ignored reports whether a reference's target is on the ignore list for the language
+// of the referencing symbol, or of its file when the symbol is not in the table, is a
+// call on a receiver whose type is not a project symbol, such as a java.util.List, or is
+// a Go import of a package outside the project, such as net/http.
+func (e *Engine) ignored(ref parser.RawReference, table *SymbolTable, fileLang string) bool {
+	if ref.Receiver != "" {
+		if _, ok := table.ByFQN[ref.Receiver]; !ok {
+			return true
+		}
+	}
+	lang := table.ByLang[ref.FromSymbol]
+	if lang == "" {
+		lang = fileLang
+	}
+	if ref.ReferenceType == "imports" && lang == "go" {
+		if _, ok := table.ByFQN[ref.ToQualified]; !ok {
+			return true
+		}
+	}
+	return e.ignore.Ignored(lang, ref, table.defines)
+}
+
+// defines reports whether the project has a symbol of the given qualified or short name.
+func (t *SymbolTable) defines(name string) bool {
+	if _, ok := t.ByFQN[name]; ok {
+		return true
+	}
+	return len(t.ByShortName[name]) > 0
 }
 
 // resolveTarget attempts to find the target symbol for a reference.
-// Resolution order: qualified name → file-local scope → project-wide short name → case-insensitive → cross-language.
+// Resolution order: qualified name → qualified name without a declared database → file-local scope →
+// project-wide short name → overload fitting the arguments → case-insensitive → cross-language.
 func resolveTarget(ref parser.RawReference, localScope map[string]uuid.UUID, table *SymbolTable, crossLang *CrossLangResolver, sourceLang string) resolveResult {
+	if result, ok := resolveLocal(ref, localScope, table); ok {
+		return result
+	}
+	return resolveAcross(ref, sourceLang, tableScan{table}, crossLang)
+}
+
+// resolveLocal tries the exact lookups of resolveTarget: qualified name, with and without
+// a declared database, file-local scope and unambiguous short name.
+func resolveLocal(ref parser.RawReference, localScope map[string]uuid.UUID, table *SymbolTable) (resolveResult, bool) {
 	// 1. Try fully qualified name
 	if ref.ToQualified != "" {
 		if id, ok := table.ByFQN[ref.ToQualified]; ok {
-			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}, true
+		}
+	}
+
+	// 1b. Strip a declared database qualifier (OrdersDb.dbo.Orders → dbo.Orders), so
+	// cross-database references resolve to the table in this project.
+	if rest, ok := table.stripDatabase(ref.ToQualified); ok {
+		if id, ok := table.ByFQN[rest]; ok {
+			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}, true
 		}
 	}
 
 	// 2. Try the target name in local scope (already resolved in parse stage, but try anyway)
 	if id, ok := localScope[ref.ToName]; ok {
-		return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+		return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}, true
 	}
 	if ref.ToQualified != "" {
 		if id, ok := localScope[ref.ToQualified]; ok {
-			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}, true
 		}
 	}
 
 	// 3. Try project-wide by short name (if unambiguous)
 	candidates := table.ByShortName[ref.ToName]
 	if len(candidates) == 1 {
-		return resolveResult{TargetID: candidates[0], Confidence: 1.0, Resolved: true}
+		return resolveResult{TargetID: candidates[0], Confidence: 1.0, Resolved: true}, true
 	}
 
-	// 4. Try case-insensitive FQN match (SQL is often case-insensitive)
-	lowerTarget := strings.ToLower(ref.ToName)
-	for fqn, id := range table.ByFQN {
-		if strings.ToLower(shortNameOf(fqn)) == lowerTarget {
-			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+	// 3b. Among same-named methods, the overload the call's arguments fit
+	if len(candidates) > 1 && ref.Args != nil {
+		if id, ok := table.overloadFor(candidates, ref.Args); ok {
+			return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}, true
 		}
 	}
+	return resolveResult{}, false
+}
+
+// resolveAcross tries the fuzzy lookups of resolveTarget, case-insensitive and then
+// cross-language, answering them from lookup. They depend only on the reference's
+// target, type and source language, not on where it was made.
+func resolveAcross(ref parser.RawReference, sourceLang string, lookup symbolLookup, crossLang *CrossLangResolver) resolveResult {
+	// 4. Try case-insensitive FQN match (SQL is often case-insensitive)
+	if id, ok := lookup.shortName(ref.ToName, ""); ok {
+		return resolveResult{TargetID: id, Confidence: 1.0, Resolved: true}
+	}
 
 	// 5. Try cross-language resolution
 	if crossLang != nil && sourceLang != "" {
-		if match, ok := crossLang.Resolve(ref, sourceLang, table); ok {
+		if match, ok := crossLang.resolveWith(ref, sourceLang, lookup); ok {
 			return resolveResult{
 				TargetID:   match.TargetID,
 				Confidence: match.Confidence,
@@ -234,16 +513,69 @@ func resolveTarget(ref parser.RawReference, localScope map[string]uuid.UUID, tab
 	return resolveResult{}
 }
 
-// shortNameOf extracts the short name from a qualified name.
-// e.g., "dbo.Customers" → "Customers", "schema.proc" → "proc"
+// addSignature records the normalized signature a method's parser stored in its
+// metadata, if any.
+func (t *SymbolTable) addSignature(sym postgres.Symbol) {
+	if len(sym.Metadata) == 0 {
+		return
+	}
+	var meta struct {
+		NormalizedSignature string `json:"normalized_signature"`
+	}
+	if json.Unmarshal(sym.Metadata, &meta) == nil && meta.NormalizedSignature != "" {
+		t.Signatures[sym.ID] = meta.NormalizedSignature
+	}
+}
+
+// addDatabase records a declared database symbol under its name and, for Terraform
+// resources, the instance identifier carried in the signature ("... identifier=x").
+func (t *SymbolTable) addDatabase(sym postgres.Symbol) {
+	t.Databases[strings.ToLower(sym.Name)] = true
+	if sym.Signature == nil {
+		return
+	}
+	for _, f := range strings.Fields(*sym.Signature) {
+		if id, ok := strings.CutPrefix(f, "identifier="); ok {
+			t.Databases[strings.ToLower(id)] = true
+		}
+	}
+}
+
+// stripDatabase removes a leading declared-database part from a three-part (or longer)
+// qualified name. It reports false when the first part is not a known database.
+func (t *SymbolTable) stripDatabase(qualified string) (string, bool) {
+	if len(t.Databases) == 0 {
+		return "", false
+	}
+	parts := strings.SplitN(qualified, ".", 2)
+	if len(parts) != 2 || strings.Count(parts[1], ".") == 0 {
+		return "", false
+	}
+	db := strings.Trim(parts[0], "[]\"`")
+	if !t.Databases[strings.ToLower(db)] {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// shortNameOf extracts the short name from a qualified name, without the signature of
+// an overload (see parser.QualifyOverloads).
+// e.g., "dbo.Customers" → "Customers", "schema.proc" → "proc", "Orders.Find(int)" → "Find"
 func shortNameOf(qualifiedName string) string {
+	if i := strings.IndexByte(qualifiedName, '('); i > 0 && strings.HasSuffix(qualifiedName, ")") {
+		qualifiedName = qualifiedName[:i]
+	}
 	parts := strings.Split(qualifiedName, ".")
 	return parts[len(parts)-1]
 }
 
-// inferSourceFromFileSymbols returns one symbol ID from the file when refs have no FromSymbol (e.g. C# uses_table).
+// inferSourceFromFileSymbols returns the symbol standing for a file when refs have no FromSymbol (e.g. C# uses_table):
+// its module symbol, or else the first symbol it declares, which encloses the rest.
 // Used so that [Table("X")] or inline SQL refs can still create an edge from the enclosing type.
 func inferSourceFromFileSymbols(fileID uuid.UUID, table *SymbolTable) uuid.UUID {
+	if id, ok := table.Modules[fileID]; ok {
+		return id
+	}
 	ids := table.ByFile[fileID]
 	if len(ids) == 0 {
 		return uuid.Nil
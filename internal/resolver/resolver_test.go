@@ -0,0 +1,461 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/parser"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestResolveRef_IgnoresFrameworkSymbols(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+		return id
+	}
+	add("MyApp.OrderService", "csharp")
+	add("MyApp.Text.String", "csharp") // would otherwise swallow System.String by short name
+	repo := add("MyApp.OrderRepository", "csharp")
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+
+	framework := parser.RawReference{FromSymbol: "MyApp.OrderService", ToName: "String", ToQualified: "System.String", ReferenceType: "references"}
+	if got := e.resolveRef(framework, nil, table, "csharp"); got.Resolved || !got.Ignored {
+		t.Errorf("expected System.String to be ignored without an edge, got %+v", got)
+	}
+
+	project := parser.RawReference{FromSymbol: "MyApp.OrderService", ToName: "OrderRepository", ToQualified: "MyApp.OrderRepository", ReferenceType: "references"}
+	if got := e.resolveRef(project, nil, table, "csharp"); !got.Resolved || got.TargetID != repo {
+		t.Errorf("expected project reference to resolve to OrderRepository, got %+v", got)
+	}
+}
+
+func TestResolveRef_ProjectSymbolShadowsBareIgnore(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = "javascript"
+		return id
+	}
+	add("src/app.start")
+	tiles := add("src/tiles.Map")
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+
+	// The project's own Map class wins over the default ignore of the built-in Map
+	own := parser.RawReference{FromSymbol: "src/app.start", ToName: "Map", ReferenceType: "calls"}
+	if got := e.resolveRef(own, nil, table, "javascript"); !got.Resolved || got.TargetID != tiles {
+		t.Errorf("expected Map to resolve to the project's class, got %+v", got)
+	}
+	builtin := parser.RawReference{FromSymbol: "src/app.start", ToName: "Set", ReferenceType: "calls"}
+	if got := e.resolveRef(builtin, nil, table, "javascript"); got.Resolved || !got.Ignored {
+		t.Errorf("expected Set with no project symbol to be ignored, got %+v", got)
+	}
+}
+
+func TestResolveRef_OverloadByArity(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, sig string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = "java"
+		table.Signatures[id] = sig
+		return id
+	}
+	// Overloads of one class, qualified by signature as the parsers store them
+	add("com.acme.OrderService.place", "(Order)")
+	single := add("com.acme.OrderRepository.save(Order)", "(Order)")
+	batch := add("com.acme.OrderRepository.save(Order,String,int=)", "(Order,String,int=)")
+	add("com.acme.OrderRepository.save(Order,int)", "(Order,int)")
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+	call := func(args ...string) parser.RawReference {
+		return parser.RawReference{FromSymbol: "com.acme.OrderService.place", ToName: "save", ReferenceType: "calls", Args: args}
+	}
+
+	if got := e.resolveRef(call(""), nil, table, "java"); !got.Resolved || got.TargetID != single {
+		t.Errorf("expected a one-argument call to resolve to save(Order), got %+v", got)
+	}
+	// The three-parameter overload's last parameter is optional; save(Order,int) takes an int second
+	if got := e.resolveRef(call("", "String"), nil, table, "java"); !got.Resolved || got.TargetID != batch {
+		t.Errorf("expected a (_, String) call to resolve to save(Order,String,int=), got %+v", got)
+	}
+	// Both two-parameter overloads fit arguments of unknown type
+	if got, ok := resolveLocal(call("", ""), nil, table); ok {
+		t.Errorf("expected an ambiguous call to stay unresolved here, got %+v", got)
+	}
+}
+
+func TestResolveRef_CollectionSymbol(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+		return id
+	}
+	add("listOrders", "javascript")
+	add("orders", "sql") // a table of the same name must not take the collection's references
+	collection := add(parser.CollectionPrefix+"orders", "javascript")
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+	ref := parser.RawReference{FromSymbol: "listOrders", ToName: "orders", ToQualified: parser.CollectionPrefix + "orders", ReferenceType: "uses_collection"}
+	if got := e.resolveRef(ref, nil, table, "javascript"); !got.Resolved || got.TargetID != collection {
+		t.Errorf("expected the reference to bind to the orders collection, got %+v", got)
+	}
+}
+
+func TestResolveRef_CallReceiver(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = "java"
+		return id
+	}
+	add("com.example.OrderService.place")
+	add("com.example.OrderRepository")
+	save := add("com.example.OrderRepository.save")
+	add("com.example.Cart.add") // the only add in the project must not take List.add
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+	call := func(receiver, method string) parser.RawReference {
+		return parser.RawReference{
+			FromSymbol: "com.example.OrderService.place", ToName: method, ToQualified: receiver + "." + method,
+			Receiver: receiver, ReferenceType: "calls", Confidence: 0.6,
+		}
+	}
+	if got := e.resolveRef(call("com.example.OrderRepository", "save"), nil, table, "java"); !got.Resolved || got.TargetID != save {
+		t.Errorf("expected the call on the repository to bind to its save, got %+v", got)
+	}
+	if got := e.resolveRef(call("java.util.List", "add"), nil, table, "java"); !got.Ignored {
+		t.Errorf("expected a call on a library type to be ignored, got %+v", got)
+	}
+}
+
+func TestResolveRef_GoImportBindsToPackage(t *testing.T) {
+	table := newSymbolTable()
+	pkg := uuid.New()
+	table.ByFQN["github.com/acme/billing/internal/model"] = pkg
+	table.ByLang["github.com/acme/billing/internal/store.New"] = "go"
+
+	e := &Engine{ignore: NewIgnoreList(nil)}
+	ref := parser.RawReference{
+		FromSymbol: "github.com/acme/billing/internal/store.New", ToName: "github.com/acme/billing/internal/model",
+		ToQualified: "github.com/acme/billing/internal/model", ReferenceType: "imports",
+	}
+	if got := e.resolveRef(ref, nil, table, "go"); !got.Resolved || got.TargetID != pkg {
+		t.Errorf("expected the import to bind to the package symbol, got %+v", got)
+	}
+	ref.ToName, ref.ToQualified = "net/http", "net/http"
+	if got := e.resolveRef(ref, nil, table, "go"); !got.Ignored {
+		t.Errorf("expected an import from outside the project to be ignored, got %+v", got)
+	}
+}
+
+func TestCrossLang_HubMethod(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+		return id
+	}
+	add("Chat.Hubs.ChatHub.SendMessage", "csharp")
+	endpoint := add("hub:Chat.Hubs.ChatHub.SendMessage", "csharp")
+	add("hub:Chat.Hubs.ChatHub.Join", "csharp")
+	add("hub:Chat.Hubs.LobbyHub.Join", "csharp")
+
+	c := NewCrossLangResolver(nil)
+
+	ref := parser.RawReference{FromSymbol: "sendMessage", ToName: "hub:sendMessage", ReferenceType: "calls_api"}
+	match, ok := c.Resolve(ref, "typescript", table)
+	if !ok || match.TargetID != endpoint || match.Strategy != "hub_method" {
+		t.Errorf("expected hub:sendMessage to match the ChatHub endpoint, got %+v (ok=%v)", match, ok)
+	}
+
+	ambiguous := parser.RawReference{FromSymbol: "join", ToName: "hub:Join", ReferenceType: "calls_api"}
+	if match, ok := c.Resolve(ambiguous, "javascript", table); ok {
+		t.Errorf("expected a method defined by two hubs to stay unresolved, got %+v", match)
+	}
+}
+
+func TestCrossLang_SOAPOperation(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+		return id
+	}
+	add("GetOrder", "wsdl") // request element type sharing the operation's name
+	op := add("soap:OrderService.GetOrder", "wsdl")
+
+	ref := parser.RawReference{FromSymbol: "Shop.Legacy.IOrderService", ToName: "soap:GetOrder", ReferenceType: "calls_api"}
+	match, ok := NewCrossLangResolver(nil).Resolve(ref, "csharp", table)
+	if !ok || match.TargetID != op || match.Strategy != "soap_operation" {
+		t.Errorf("expected soap:GetOrder to match the WSDL operation, got %+v (ok=%v)", match, ok)
+	}
+}
+
+func TestCrossLang_APIRoute(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByShortName[shortNameOf(qname)] = append(table.ByShortName[shortNameOf(qname)], id)
+		table.ByLang[qname] = lang
+		return id
+	}
+	getUser := add("route:GET /users/{id}", "typescript")
+	add("route:DELETE /users/{id}", "typescript")
+	listUsers := add("route:GET /users", "typescript")
+	createUser := add("route:POST /users", "typescript")
+	getOrder := add("route:GET /api/Orders/{id}", "csharp")
+	getInvoice := add("route:GET /api/invoices/{id}", "java")
+
+	c := NewCrossLangResolver(nil)
+	cases := []struct {
+		path, qualified string
+		want            uuid.UUID
+	}{
+		{"/users/${id}", "route:GET /users/${id}", getUser},
+		{"/api/users", "route:POST /api/users", createUser}, // global prefix
+		{"https://app.example.com/users?page=2", "route:GET https://app.example.com/users?page=2", listUsers},
+		{"/api/orders/${id}", "route:GET /api/orders/${id}", getOrder},     // ASP.NET controller
+		{"/api/invoices/{id}", "route:GET /api/invoices/{id}", getInvoice}, // Spring controller
+	}
+	for _, tc := range cases {
+		ref := parser.RawReference{FromSymbol: "loadUsers", ToName: tc.path, ToQualified: tc.qualified, ReferenceType: "calls_api"}
+		match, ok := c.Resolve(ref, "typescript", table)
+		if !ok || match.TargetID != tc.want || match.Strategy != "api_route" {
+			t.Errorf("%s: expected the matching endpoint, got %+v (ok=%v)", tc.qualified, match, ok)
+		}
+	}
+
+	// Without a method, GET and DELETE /users/{id} both match.
+	ambiguous := parser.RawReference{FromSymbol: "loadUser", ToName: "/users/7", ReferenceType: "calls_api"}
+	if match, ok := c.Resolve(ambiguous, "javascript", table); ok {
+		t.Errorf("expected a path routed for two methods to stay unresolved, got %+v", match)
+	}
+}
+
+func TestCrossLang_OpenAPIOperation(t *testing.T) {
+	table := newSymbolTable()
+	add := func(qname, lang string) uuid.UUID {
+		id := uuid.New()
+		table.ByFQN[qname] = id
+		table.ByLang[qname] = lang
+		return id
+	}
+	operation := add("openapi:GET /users/{id}", "openapi")
+	controller := add("route:GET /api/Users/{id}", "csharp")
+
+	c := NewCrossLangResolver(nil)
+	// The frontend call matches the spec ahead of the controller
+	call := parser.RawReference{FromSymbol: "loadUser", ToName: "/api/users/${id}", ToQualified: "route:GET /api/users/${id}", ReferenceType: "calls_api"}
+	match, ok := c.Resolve(call, "typescript", table)
+	if !ok || match.TargetID != operation || match.Strategy != "openapi_operation" || match.Confidence != 0.95 {
+		t.Errorf("expected the call to match the spec operation, got %+v (ok=%v)", match, ok)
+	}
+	// and the spec operation, served under /api, matches the controller serving it
+	implemented := parser.RawReference{FromSymbol: "openapi:GET /users/{id}", ToName: "/api/users/{id}", ToQualified: "route:GET /api/users/{id}", ReferenceType: "calls_api"}
+	match, ok = c.Resolve(implemented, "openapi", table)
+	if !ok || match.TargetID != controller || match.Strategy != "api_route" {
+		t.Errorf("expected the operation to match the controller route, got %+v (ok=%v)", match, ok)
+	}
+	if got := resolveBatch([]batchRef{{ref: call, sourceLang: "typescript"}, {ref: implemented, sourceLang: "openapi"}}, table, c); got[0].TargetID != operation || got[1].TargetID != controller {
+		t.Errorf("expected the batched lookup to match alike, got %+v", got)
+	}
+}
+
+func TestCrossLang_APIRouteCase(t *testing.T) {
+	table := newSymbolTable()
+	users := uuid.New()
+	table.ByFQN["route:GET /api/users"] = users
+	table.ByLang["route:GET /api/users"] = "typescript"
+	ref := parser.RawReference{FromSymbol: "loadUsers", ToName: "/API/Users/", ToQualified: "route:GET /API/Users/", ReferenceType: "calls_api"}
+
+	c := NewCrossLangResolver(nil)
+	if match, ok := c.Resolve(ref, "typescript", table); !ok || match.TargetID != users {
+		t.Errorf("expected /API/Users/ to match /api/users by default, got %+v (ok=%v)", match, ok)
+	}
+	if got := resolveBatch([]batchRef{{ref: ref, sourceLang: "typescript"}}, table, c)[0]; !got.Resolved {
+		t.Errorf("expected the batched lookup to match too, got %+v", got)
+	}
+
+	c.SetCaseSensitiveRoutes(true)
+	if match, ok := c.Resolve(ref, "typescript", table); ok {
+		t.Errorf("expected /API/Users not to match /api/users when case-sensitive, got %+v", match)
+	}
+	if got := resolveBatch([]batchRef{{ref: ref, sourceLang: "typescript"}}, table, c)[0]; got.Resolved {
+		t.Errorf("expected the batched lookup to honour case-sensitivity, got %+v", got)
+	}
+	// Trailing slashes still don't matter
+	ref.ToName, ref.ToQualified = "/api/users/", "route:GET /api/users/"
+	if match, ok := c.Resolve(ref, "typescript", table); !ok || match.TargetID != users {
+		t.Errorf("expected /api/users/ to match /api/users, got %+v (ok=%v)", match, ok)
+	}
+}
+
+func TestIgnoreList_ExtraEntries(t *testing.T) {
+	l := NewIgnoreList([]string{"java:com.vendor.*", "Moment", "node:crypto"})
+
+	tests := []struct {
+		lang string
+		ref  parser.RawReference
+		want bool
+	}{
+		{"java", parser.RawReference{ToName: "Client", ToQualified: "com.vendor.sdk.Client"}, true},
+		{"csharp", parser.RawReference{ToName: "Client", ToQualified: "com.vendor.sdk.Client"}, false},
+		{"javascript", parser.RawReference{ToName: "Moment"}, true},
+		{"tsql", parser.RawReference{ToName: "Moment"}, true},
+		{"javascript", parser.RawReference{ToName: "node:crypto"}, true},
+		{"java", parser.RawReference{ToName: "List", ToQualified: "java.util.List"}, true},
+		{"java", parser.RawReference{ToName: "Order", ToQualified: "com.acme.Order"}, false},
+	}
+	for _, tt := range tests {
+		if got := l.Ignored(tt.lang, tt.ref, nil); got != tt.want {
+			t.Errorf("Ignored(%q, %s) = %v, want %v", tt.lang, tt.ref.ToQualified+tt.ref.ToName, got, tt.want)
+		}
+	}
+}
+
+func TestTablePrecedence_AttributeMappingWins(t *testing.T) {
+	// User is mapped three ways across the entity, the context and a repository
+	refs := []parser.RawReference{
+		{FromSymbol: "MyApp.UserRepository", ToName: "tbl_users", ReferenceType: "uses_table", Mapping: parser.MappingSQL, Entity: "User"},
+		{FromSymbol: "MyApp.AppDbContext", ToName: "User", ReferenceType: "uses_table", Mapping: parser.MappingORM, Entity: "User"},
+		{FromSymbol: "MyApp.User", ToName: "Users", ReferenceType: "uses_table", Mapping: parser.MappingAttribute, Entity: "MyApp.User"},
+		{FromSymbol: "MyApp.User", ToName: "Audit", ReferenceType: "uses_table"},                                                      // unknown source: never conflicts
+		{FromSymbol: "MyApp.AppDbContext", ToName: "Order", ReferenceType: "uses_table", Mapping: parser.MappingORM, Entity: "Order"}, // another entity of the same context
+		{FromSymbol: "MyApp.UserRepository", ToName: "tbl_audit", ReferenceType: "uses_table", Mapping: parser.MappingSQL},            // SQL not tied to an entity
+		{FromSymbol: "MyApp.User", ToName: "Save", ReferenceType: "calls"},
+	}
+
+	p, err := NewTablePrecedence(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := p.outranked(refs)
+	want := map[int]string{0: parser.MappingAttribute, 1: parser.MappingAttribute}
+	if len(got) != len(want) {
+		t.Fatalf("outranked = %v, want %v", got, want)
+	}
+	for i, winner := range want {
+		if got[i] != winner {
+			t.Errorf("ref %s superseded by %q, want %q", refs[i].ToName, got[i], winner)
+		}
+	}
+
+	// With SQL ranked first, the inferred table wins instead.
+	p, err = NewTablePrecedence([]string{"sql", "attribute", "orm"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.outranked(refs); len(got) != 2 || got[1] != parser.MappingSQL || got[2] != parser.MappingSQL {
+		t.Errorf("sql-first outranked = %v", got)
+	}
+
+	if _, err := NewTablePrecedence([]string{"attribute", "guess"}, true); err == nil {
+		t.Error("expected an unknown mapping source to be rejected")
+	}
+	var none *TablePrecedence
+	if got := none.outranked(refs); got != nil {
+		t.Errorf("nil precedence outranked %v", got)
+	}
+}
+
+func TestResolveImport_TSPathAlias(t *testing.T) {
+	table := newSymbolTable()
+	// Each file declares a class before its module symbol, which imports resolve to
+	addFile := func(path string) uuid.UUID {
+		fileID, classID, moduleID := uuid.New(), uuid.New(), uuid.New()
+		table.FileByPath[path] = fileID
+		table.ByFile[fileID] = []uuid.UUID{classID, moduleID}
+		table.Modules[fileID] = moduleID
+		return moduleID
+	}
+	userService := addFile("apps/web/src/app/services/user.ts")
+	sharedIndex := addFile("libs/shared/src/index.ts")
+	button := addFile("apps/web/src/components/Button.tsx")
+	addFile("apps/admin/src/app/services/user.ts")
+
+	// As loaded from apps/web/tsconfig.json extending the root tsconfig.base.json, after
+	// that of another source which must not apply to this one's files
+	table.TSPaths = []TSPathConfig{
+		{SourceID: uuid.New(), Dir: "apps/web", Paths: map[string][]string{"@app/*": {"apps/admin/src/app/*"}}},
+		{Dir: "apps/web", BaseURL: "apps/web/src", Paths: map[string][]string{
+			"@app/*":  {"apps/web/src/app/*"},
+			"@shared": {"libs/shared/src"},
+		}},
+		{Dir: "", BaseURL: ".", Paths: map[string][]string{"@shared/*": {"libs/shared/src/*"}}},
+	}
+
+	from := "apps/web/src/pages/home.tsx"
+	cases := []struct {
+		specifier string
+		want      uuid.UUID
+	}{
+		{"@app/services/user", userService},
+		{"@shared", sharedIndex},
+		{"components/Button", button}, // baseUrl-relative
+		{"../components/Button", button},
+	}
+	for _, tc := range cases {
+		if got := resolveImport(tc.specifier, from, table); !got.Resolved || got.TargetID != tc.want {
+			t.Errorf("import %q: expected %v, got %+v", tc.specifier, tc.want, got)
+		}
+	}
+
+	if got := resolveImport("react", from, table); got.Resolved || !got.Ignored {
+		t.Errorf("expected a package import to be ignored, got %+v", got)
+	}
+	if got := resolveImport("@app/services/missing", from, table); got.Resolved || got.Ignored {
+		t.Errorf("expected an alias to a missing file to stay unresolved, got %+v", got)
+	}
+}
+
+func TestInferForeignKeys_PKShapedColumns(t *testing.T) {
+	sym := func(kind, qname, sig string) postgres.Symbol {
+		return postgres.Symbol{ID: uuid.New(), Kind: kind, Name: shortNameOf(qname), QualifiedName: qname, Signature: &sig}
+	}
+	customerID := sym("column", "dbo.Customers.Id", "INT")
+	orderCustomer := sym("column", "dbo.Orders.CustomerId", "INT")
+	orderProduct := sym("column", "dbo.Orders.ProductId", "INT")
+	orderNote := sym("column", "dbo.Orders.CustomerNote", "NVARCHAR(200)")
+	productID := sym("column", "dbo.Products.Id", "INT")
+	invoiceCustomer := sym("column", "dbo.Invoices.CustomerId", "BIGINT")
+	symbols := []postgres.Symbol{
+		customerID, orderCustomer, orderProduct, orderNote, productID, invoiceCustomer,
+		sym("column", "dbo.Orders.Id", "INT"),
+		sym("index", "dbo.Customers.PK_Customers", "primary key (Id)"),
+		sym("index", "dbo.Orders.PK_Orders", "primary key (Id)"),
+		sym("index", "dbo.Products.PK_Products", "primary key (Id)"),
+	}
+	// Orders.ProductId is declared, Invoices.CustomerId differs in type from the key
+	declared := map[string]bool{"dbo.orders.productid": true}
+
+	got := inferForeignKeys(symbols, declared)
+	if len(got) != 1 {
+		t.Fatalf("inferred %d relationships, want 1: %+v", len(got), got)
+	}
+	if got[0].SourceID != orderCustomer.ID || got[0].TargetID != customerID.ID {
+		t.Errorf("inferred %v -> %v, want Orders.CustomerId -> Customers.Id", got[0].SourceID, got[0].TargetID)
+	}
+	if got[0].Confidence != inferredFKConfidence {
+		t.Errorf("confidence = %v, want %v", got[0].Confidence, inferredFKConfidence)
+	}
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// Locker claims keys shared by the scheduler replicas, so that one of them acts on each.
+type Locker interface {
+	// Claim sets key for ttl unless it is set, reporting whether it set it.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release removes key, so it can be claimed again.
+	Release(ctx context.Context, key string) error
+}
+
+// ValkeyLocker claims keys on a Valkey server.
+type ValkeyLocker struct {
+	client valkey.Client
+}
+
+func NewValkeyLocker(client valkey.Client) *ValkeyLocker {
+	return &ValkeyLocker{client: client}
+}
+
+func (l *ValkeyLocker) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	err := l.client.Do(ctx, l.client.B().Set().Key(key).Value("1").Nx().Px(ttl).Build()).Error()
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *ValkeyLocker) Release(ctx context.Context, key string) error {
+	return l.client.Do(ctx, l.client.B().Del().Key(key).Build()).Error()
+}
@@ -0,0 +1,221 @@
+// Package scheduler enqueues index runs without a user asking: on the cron schedules
+// projects set in their settings, and when a Git host reports a push by webhook.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/ingestion"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// scheduleStore is the storage the scheduler reads schedules and sources from and
+// records index runs in.
+type scheduleStore interface {
+	ListScheduledProjects(ctx context.Context) ([]postgres.ListScheduledProjectsRow, error)
+	UpsertProjectScheduleRun(ctx context.Context, arg postgres.UpsertProjectScheduleRunParams) error
+	ListSourcesByProjectID(ctx context.Context, projectID uuid.UUID) ([]postgres.Source, error)
+	ListRepositorySources(ctx context.Context) ([]postgres.Source, error)
+	CreateIndexRun(ctx context.Context, arg postgres.CreateIndexRunParams) (postgres.IndexRun, error)
+	UpdateIndexRunStatus(ctx context.Context, arg postgres.UpdateIndexRunStatusParams) error
+}
+
+// enqueuer hands index runs to the workers; *ingestion.Producer in production.
+type enqueuer interface {
+	Enqueue(ctx context.Context, msg ingestion.IngestMessage) (string, error)
+}
+
+// Scheduler enqueues scheduled and webhook-triggered index runs.
+type Scheduler struct {
+	store    scheduleStore
+	producer enqueuer
+	locker   Locker
+	logger   *slog.Logger
+}
+
+// New creates a scheduler. Replicas sharing a locker take turns on each scheduled run; a
+// nil locker is for a single replica.
+func New(s scheduleStore, producer enqueuer, locker Locker, logger *slog.Logger) *Scheduler {
+	return &Scheduler{store: s, producer: producer, locker: locker, logger: logger}
+}
+
+// Run checks the project schedules now and then every interval until ctx is done. The
+// first check catches up on runs that fell due while the scheduler was down. A
+// non-positive interval checks every minute.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Tick(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			s.logger.Warn("schedule tick failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick enqueues an index run of every source of each project whose schedule is due at
+// now and records now as its last run. A project seen for the first time is only
+// recorded, so its schedule starts from now rather than from some past occurrence.
+// Projects with an invalid schedule are logged and skipped. Schedules are in UTC.
+//
+// A run that fails to enqueue for every source is retried on the next tick; one that
+// enqueues some sources is recorded, so those are not enqueued twice. With a locker,
+// the replica that claims an occurrence of a project's schedule runs it.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	projects, err := s.store.ListScheduledProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("list scheduled projects: %w", err)
+	}
+	for _, p := range projects {
+		schedule, err := ParseCron(p.Schedule)
+		if err != nil {
+			s.logger.Warn("invalid index schedule",
+				slog.String("project_id", p.ID.String()),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if p.LastRunAt.Valid {
+			occurrence, ok := due(schedule, p.LastRunAt.Time, now)
+			if !ok {
+				continue
+			}
+			claimed, release, err := s.claim(ctx, p.ID, occurrence)
+			if err != nil {
+				s.logger.Warn("claim scheduled index run",
+					slog.String("project_id", p.ID.String()),
+					slog.String("error", err.Error()))
+				continue
+			}
+			if !claimed {
+				continue // another replica runs it
+			}
+			runs, err := s.enqueueProject(ctx, p.ID, "schedule")
+			if err != nil {
+				s.logger.Warn("scheduled index run failed",
+					slog.String("project_id", p.ID.String()),
+					slog.Int("sources", runs),
+					slog.String("error", err.Error()))
+				if runs == 0 {
+					release()
+					continue
+				}
+			} else {
+				s.logger.Info("enqueued scheduled index run",
+					slog.String("project_id", p.ID.String()),
+					slog.Int("sources", runs))
+			}
+		}
+		if err := s.store.UpsertProjectScheduleRun(ctx, postgres.UpsertProjectScheduleRunParams{
+			ProjectID: p.ID,
+			LastRunAt: now,
+		}); err != nil {
+			return fmt.Errorf("record schedule run: %w", err)
+		}
+	}
+	return nil
+}
+
+// due returns the first occurrence of a schedule after lastRun, and whether it is no
+// later than now. However many occurrences were missed, one run catches up on all of
+// them.
+func due(schedule Schedule, lastRun, now time.Time) (time.Time, bool) {
+	next := schedule.Next(lastRun)
+	return next, !next.IsZero() && !next.After(now)
+}
+
+// claimTTL is how long a claimed occurrence keeps other replicas off it: long enough
+// for the claiming replica to record the run, after which it is no longer due.
+const claimTTL = time.Hour
+
+// claim claims an occurrence of a project's schedule for this replica, returning a
+// func that gives it up again for a run that enqueued nothing. Without a locker every
+// occurrence is claimed.
+func (s *Scheduler) claim(ctx context.Context, projectID uuid.UUID, occurrence time.Time) (bool, func(), error) {
+	if s.locker == nil {
+		return true, func() {}, nil
+	}
+	key := fmt.Sprintf("lattice:schedule:%s:%d", projectID, occurrence.Unix())
+	ok, err := s.locker.Claim(ctx, key, claimTTL)
+	release := func() {
+		if err := s.locker.Release(ctx, key); err != nil {
+			s.logger.Warn("release scheduled index run", slog.String("key", key), slog.String("error", err.Error()))
+		}
+	}
+	return ok, release, err
+}
+
+// enqueueProject creates and enqueues an index run for each source of a project,
+// returning how many it enqueued. A source that fails does not stop the others; the
+// error reports every failure.
+func (s *Scheduler) enqueueProject(ctx context.Context, projectID uuid.UUID, trigger string) (int, error) {
+	sources, err := s.store.ListSourcesByProjectID(ctx, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("list sources: %w", err)
+	}
+	runs := 0
+	var errs []error
+	for _, source := range sources {
+		if _, err := s.enqueueSource(ctx, source, trigger); err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", source.ID, err))
+			continue
+		}
+		runs++
+	}
+	return runs, errors.Join(errs...)
+}
+
+// enqueueSource creates an index run of a source and enqueues it for the workers. A run
+// that cannot be enqueued is marked failed, and a push coalesced into a run already
+// waiting for the source is marked cancelled; neither is an error.
+func (s *Scheduler) enqueueSource(ctx context.Context, source postgres.Source, trigger string) (postgres.IndexRun, error) {
+	run, err := s.store.CreateIndexRun(ctx, postgres.CreateIndexRunParams{
+		ProjectID: source.ProjectID,
+		SourceID:  pgtype.UUID{Bytes: source.ID, Valid: true},
+	})
+	if err != nil {
+		return run, fmt.Errorf("create index run: %w", err)
+	}
+	_, err = s.producer.Enqueue(ctx, ingestion.IngestMessage{
+		IndexRunID: run.ID,
+		ProjectID:  source.ProjectID,
+		SourceID:   source.ID,
+		SourceType: source.SourceType,
+		Trigger:    trigger,
+	})
+	if err == nil {
+		return run, nil
+	}
+
+	status := "failed"
+	var coalesced *ingestion.CoalescedError
+	if errors.As(err, &coalesced) {
+		status = "cancelled"
+	}
+	reason := err.Error()
+	if uerr := s.store.UpdateIndexRunStatus(ctx, postgres.UpdateIndexRunStatusParams{
+		ID:           run.ID,
+		Status:       status,
+		ErrorMessage: &reason,
+	}); uerr != nil {
+		s.logger.Warn("update index run status", slog.String("index_run_id", run.ID.String()), slog.String("error", uerr.Error()))
+	}
+	if status == "cancelled" {
+		run.Status, run.ErrorMessage = status, &reason
+		return run, nil
+	}
+	return run, fmt.Errorf("enqueue index run: %w", err)
+}
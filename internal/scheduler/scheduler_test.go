@@ -0,0 +1,296 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/ingestion"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+type fakeStore struct {
+	projects []postgres.ListScheduledProjectsRow
+	sources  []postgres.Source
+	lastRuns map[uuid.UUID]time.Time
+	statuses map[uuid.UUID]string
+}
+
+func (f *fakeStore) ListScheduledProjects(context.Context) ([]postgres.ListScheduledProjectsRow, error) {
+	return f.projects, nil
+}
+
+func (f *fakeStore) UpsertProjectScheduleRun(_ context.Context, arg postgres.UpsertProjectScheduleRunParams) error {
+	f.lastRuns[arg.ProjectID] = arg.LastRunAt
+	return nil
+}
+
+func (f *fakeStore) ListSourcesByProjectID(_ context.Context, projectID uuid.UUID) ([]postgres.Source, error) {
+	var out []postgres.Source
+	for _, s := range f.sources {
+		if s.ProjectID == projectID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) ListRepositorySources(context.Context) ([]postgres.Source, error) {
+	return f.sources, nil
+}
+
+func (f *fakeStore) CreateIndexRun(_ context.Context, arg postgres.CreateIndexRunParams) (postgres.IndexRun, error) {
+	return postgres.IndexRun{ID: uuid.New(), ProjectID: arg.ProjectID, SourceID: arg.SourceID}, nil
+}
+
+func (f *fakeStore) UpdateIndexRunStatus(_ context.Context, arg postgres.UpdateIndexRunStatusParams) error {
+	if f.statuses == nil {
+		f.statuses = make(map[uuid.UUID]string)
+	}
+	f.statuses[arg.ID] = arg.Status
+	return nil
+}
+
+// fakeQueue refuses messages of the sources in fail.
+type fakeQueue struct {
+	msgs []ingestion.IngestMessage
+	fail map[uuid.UUID]bool
+}
+
+func (q *fakeQueue) Enqueue(_ context.Context, msg ingestion.IngestMessage) (string, error) {
+	if q.fail[msg.SourceID] {
+		return "", errors.New("valkey unavailable")
+	}
+	q.msgs = append(q.msgs, msg)
+	return "1-0", nil
+}
+
+// fakeLocker is a set of claimed keys, shared by the schedulers standing in for replicas.
+type fakeLocker struct{ keys map[string]bool }
+
+func (l *fakeLocker) Claim(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if l.keys[key] {
+		return false, nil
+	}
+	l.keys[key] = true
+	return true, nil
+}
+
+func (l *fakeLocker) Release(_ context.Context, key string) error {
+	delete(l.keys, key)
+	return nil
+}
+
+func discardLogger() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
+
+func signedPush(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader([]byte(body)))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func gitSource(projectID uuid.UUID, uri string) postgres.Source {
+	return postgres.Source{ID: uuid.New(), ProjectID: projectID, SourceType: "git", ConnectionUri: &uri}
+}
+
+func TestTickCatchesUpMissedRuns(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	nightly, hourly, fresh := uuid.New(), uuid.New(), uuid.New()
+	store := &fakeStore{
+		projects: []postgres.ListScheduledProjectsRow{
+			// Last ran three days ago: the missed nightly runs are caught up once.
+			{ID: nightly, Schedule: "0 2 * * *", LastRunAt: pgtype.Timestamptz{Time: now.AddDate(0, 0, -3), Valid: true}},
+			// Ran at the top of this hour: not due until the next.
+			{ID: hourly, Schedule: "@hourly", LastRunAt: pgtype.Timestamptz{Time: now, Valid: true}},
+			// Never ran: recorded without a run.
+			{ID: fresh, Schedule: "*/5 * * * *"},
+		},
+		sources: []postgres.Source{
+			gitSource(nightly, "https://github.com/acme/app.git"),
+			gitSource(hourly, "https://github.com/acme/lib.git"),
+			gitSource(fresh, "https://github.com/acme/web.git"),
+		},
+		lastRuns: map[uuid.UUID]time.Time{},
+	}
+	queue := &fakeQueue{}
+	s := New(store, queue, nil, discardLogger())
+
+	if err := s.Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.msgs) != 1 || queue.msgs[0].ProjectID != nightly || queue.msgs[0].Trigger != "schedule" {
+		t.Fatalf("expected one scheduled run of the nightly project, got %+v", queue.msgs)
+	}
+	if !store.lastRuns[nightly].Equal(now) || !store.lastRuns[fresh].Equal(now) {
+		t.Errorf("expected the nightly and new projects recorded at now, got %v", store.lastRuns)
+	}
+	if _, ok := store.lastRuns[hourly]; ok {
+		t.Error("expected the project that is not due to be left alone")
+	}
+}
+
+func TestWebhookReindexesMatchingSources(t *testing.T) {
+	project := uuid.New()
+	store := &fakeStore{sources: []postgres.Source{
+		gitSource(project, "git@github.com:Acme/App.git"),
+		gitSource(project, "https://github.com/acme/other.git"),
+	}}
+	queue := &fakeQueue{}
+	h := New(store, queue, nil, discardLogger()).WebhookHandler("s3cret")
+
+	rec := signedPush(t, h, `{"repository": {"clone_url": "https://github.com/acme/app.git", "ssh_url": "git@github.com:acme/app.git"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if len(queue.msgs) != 1 || queue.msgs[0].SourceID != store.sources[0].ID || queue.msgs[0].Trigger != "webhook" {
+		t.Errorf("expected one webhook run of the matching source, got %+v", queue.msgs)
+	}
+
+	// A GitLab request with the wrong token is refused.
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("X-Gitlab-Token", "wrong")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad GitLab token, got %d", rec.Code)
+	}
+}
+
+func TestTickRecordsPartiallyEnqueuedRuns(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	project := uuid.New()
+	store := &fakeStore{
+		projects: []postgres.ListScheduledProjectsRow{
+			{ID: project, Schedule: "0 2 * * *", LastRunAt: pgtype.Timestamptz{Time: now.AddDate(0, 0, -1), Valid: true}},
+		},
+		sources: []postgres.Source{
+			gitSource(project, "https://github.com/acme/app.git"),
+			gitSource(project, "https://github.com/acme/lib.git"),
+		},
+		lastRuns: map[uuid.UUID]time.Time{},
+	}
+	queue := &fakeQueue{fail: map[uuid.UUID]bool{store.sources[0].ID: true}}
+	s := New(store, queue, nil, discardLogger())
+
+	if err := s.Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.msgs) != 1 || queue.msgs[0].SourceID != store.sources[1].ID {
+		t.Fatalf("expected the healthy source enqueued despite the failure, got %+v", queue.msgs)
+	}
+	if !store.lastRuns[project].Equal(now) {
+		t.Fatal("expected the partly enqueued run recorded, so the next tick does not enqueue it again")
+	}
+	if len(store.statuses) != 1 {
+		t.Fatalf("expected the run that failed to enqueue marked, got %v", store.statuses)
+	}
+	for _, status := range store.statuses {
+		if status != "failed" {
+			t.Errorf("expected the run marked failed, got %q", status)
+		}
+	}
+
+	// When every source fails the run is not recorded, so the next tick retries it.
+	queue.fail[store.sources[1].ID] = true
+	store.lastRuns = map[uuid.UUID]time.Time{}
+	store.projects[0].LastRunAt.Time = now.AddDate(0, 0, -1)
+	if err := s.Tick(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.lastRuns[project]; ok {
+		t.Error("expected a run that enqueued nothing left due")
+	}
+}
+
+func TestTickRunsEachOccurrenceOnOneReplica(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	project := uuid.New()
+	store := &fakeStore{
+		projects: []postgres.ListScheduledProjectsRow{
+			{ID: project, Schedule: "@hourly", LastRunAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}},
+		},
+		sources:  []postgres.Source{gitSource(project, "https://github.com/acme/app.git")},
+		lastRuns: map[uuid.UUID]time.Time{},
+	}
+	queue := &fakeQueue{}
+	locker := &fakeLocker{keys: map[string]bool{}}
+
+	// Both replicas read the schedule before either records the run.
+	for _, s := range []*Scheduler{New(store, queue, locker, discardLogger()), New(store, queue, locker, discardLogger())} {
+		if err := s.Tick(context.Background(), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(queue.msgs) != 1 {
+		t.Fatalf("expected the occurrence enqueued once across replicas, got %d runs", len(queue.msgs))
+	}
+}
+
+func TestWebhookReindexesTrackedRef(t *testing.T) {
+	project := uuid.New()
+	store := &fakeStore{sources: []postgres.Source{
+		gitSource(project, "https://github.com/acme/app.git"),            // default branch
+		gitSource(project, "https://github.com/acme/app/tree/release/2"), // release/2
+		gitSource(project, "https://github.com/acme/app.git@v1.2.0"),     // tag
+	}}
+	queue := &fakeQueue{}
+	h := New(store, queue, nil, discardLogger()).WebhookHandler("s3cret")
+
+	push := func(ref string) []uuid.UUID {
+		t.Helper()
+		queue.msgs = nil
+		rec := signedPush(t, h, `{"ref": "`+ref+`", "repository": {"clone_url": "https://github.com/acme/app.git", "default_branch": "main"}}`)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("push %s: expected 200 or 201, got %d: %s", ref, rec.Code, rec.Body)
+		}
+		var ids []uuid.UUID
+		for _, msg := range queue.msgs {
+			ids = append(ids, msg.SourceID)
+		}
+		return ids
+	}
+
+	if got := push("refs/heads/main"); len(got) != 1 || got[0] != store.sources[0].ID {
+		t.Errorf("push to main: expected the default-branch source, got %v", got)
+	}
+	if got := push("refs/heads/release/2"); len(got) != 1 || got[0] != store.sources[1].ID {
+		t.Errorf("push to release/2: expected the release source, got %v", got)
+	}
+	if got := push("refs/tags/v1.2.0"); len(got) != 1 || got[0] != store.sources[2].ID {
+		t.Errorf("push of v1.2.0: expected the tag source, got %v", got)
+	}
+	if got := push("refs/heads/feature/x"); len(got) != 0 {
+		t.Errorf("push to an untracked branch: expected no runs, got %v", got)
+	}
+}
+
+func TestRepoKeyDropsRef(t *testing.T) {
+	for _, raw := range []string{
+		"https://github.com/acme/app",
+		"https://github.com/Acme/App.git@v1.2.0",
+		"https://github.com/acme/app/tree/feature/login",
+		"git@github.com:acme/app.git@main",
+	} {
+		if got := repoKey(raw); got != "github.com/acme/app" {
+			t.Errorf("repoKey(%q) = %q, want github.com/acme/app", raw, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/maraichr/lattice/internal/ingestion/connectors"
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// maxWebhookBody bounds the push payloads read from Git hosts.
+const maxWebhookBody = 5 << 20
+
+// pushPayload holds the pushed ref and the repository URLs of GitHub and GitLab push
+// events.
+type pushPayload struct {
+	Ref        string `json:"ref"` // refs/heads/main, refs/tags/v1.2.0
+	Repository struct {
+		DefaultBranch string `json:"default_branch"` // GitHub
+
+		CloneURL   string `json:"clone_url"`    // GitHub
+		HTMLURL    string `json:"html_url"`     // GitHub
+		SSHURL     string `json:"ssh_url"`      // GitHub
+		URL        string `json:"url"`          // GitHub API URL, GitLab SSH URL
+		GitHTTPURL string `json:"git_http_url"` // GitLab
+		GitSSHURL  string `json:"git_ssh_url"`  // GitLab
+		Homepage   string `json:"homepage"`     // GitLab
+	} `json:"repository"`
+	Project struct {
+		DefaultBranch string `json:"default_branch"`
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		WebURL        string `json:"web_url"`
+	} `json:"project"` // GitLab
+}
+
+func (p pushPayload) urls() []string {
+	r := p.Repository
+	return []string{r.CloneURL, r.HTMLURL, r.SSHURL, r.URL, r.GitHTTPURL, r.GitSSHURL, r.Homepage,
+		p.Project.GitHTTPURL, p.Project.GitSSHURL, p.Project.WebURL}
+}
+
+// tracks reports whether a source checking out ref, "" for the default branch, is
+// affected by the push. Pushes without a ref, and pushes when the default branch is not
+// known, affect every source of the repository.
+func (p pushPayload) tracks(ref string) bool {
+	if p.Ref == "" {
+		return true
+	}
+	if ref == "" {
+		ref = p.Repository.DefaultBranch
+		if ref == "" {
+			ref = p.Project.DefaultBranch
+		}
+		if ref == "" {
+			return true
+		}
+	}
+	pushed := strings.TrimPrefix(strings.TrimPrefix(p.Ref, "refs/heads/"), "refs/tags/")
+	return ref == pushed || ref == p.Ref
+}
+
+// WebhookHandler returns the HTTP handler for push webhooks: POST /webhooks/github and
+// POST /webhooks/gitlab. GitHub requests are authenticated by their X-Hub-Signature-256
+// HMAC and GitLab requests by their X-Gitlab-Token, both against secret; with no
+// secret configured every request is refused. Each Git source whose repository URL
+// matches the payload's and whose ref is the one pushed gets an index run.
+func (s *Scheduler) WebhookHandler(secret string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhooks/github", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := s.readBody(w, r, secret)
+		if !ok {
+			return
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		signature := r.Header.Get("X-Hub-Signature-256")
+		if signature == "" {
+			s.writeError(w, apierr.MissingAuthToken())
+			return
+		}
+		if !hmac.Equal([]byte(signature), []byte(expected)) {
+			s.writeError(w, apierr.InvalidAuthToken())
+			return
+		}
+		if r.Header.Get("X-GitHub-Event") == "ping" {
+			writeJSON(w, http.StatusOK, map[string]any{"index_runs": []postgres.IndexRun{}})
+			return
+		}
+		s.reindex(w, r, body)
+	})
+	mux.HandleFunc("POST /webhooks/gitlab", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := s.readBody(w, r, secret)
+		if !ok {
+			return
+		}
+		token := r.Header.Get("X-Gitlab-Token")
+		if token == "" {
+			s.writeError(w, apierr.MissingAuthToken())
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			s.writeError(w, apierr.InvalidAuthToken())
+			return
+		}
+		s.reindex(w, r, body)
+	})
+	return mux
+}
+
+func (s *Scheduler) readBody(w http.ResponseWriter, r *http.Request, secret string) ([]byte, bool) {
+	if secret == "" {
+		s.writeError(w, apierr.MissingAuthToken())
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		s.writeError(w, apierr.InvalidRequestBody())
+		return nil, false
+	}
+	return body, true
+}
+
+// reindex enqueues an index run of every Git source cloned from the pushed repository
+// at the pushed ref. It answers 404 when no source clones the repository, and 500 only
+// when every run failed to enqueue, as the others are already queued.
+func (s *Scheduler) reindex(w http.ResponseWriter, r *http.Request, body []byte) {
+	var payload pushPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		s.writeError(w, apierr.InvalidRequestBody())
+		return
+	}
+	repos := make(map[string]bool)
+	for _, u := range payload.urls() {
+		if key := repoKey(u); key != "" {
+			repos[key] = true
+		}
+	}
+	if len(repos) == 0 {
+		s.writeError(w, apierr.InvalidRequestBody())
+		return
+	}
+
+	sources, err := s.store.ListRepositorySources(r.Context())
+	if err != nil {
+		s.writeError(w, apierr.SourceListFailed(err))
+		return
+	}
+	matched := false
+	runs := []postgres.IndexRun{}
+	var errs []error
+	for _, source := range sources {
+		if source.ConnectionUri == nil || !repos[repoKey(*source.ConnectionUri)] {
+			continue
+		}
+		matched = true
+		if _, ref := connectors.SplitRef(*source.ConnectionUri); !payload.tracks(ref) {
+			continue
+		}
+		run, err := s.enqueueSource(r.Context(), source, "webhook")
+		if err != nil {
+			s.logger.Warn("webhook index run failed",
+				slog.String("source_id", source.ID.String()),
+				slog.String("error", err.Error()))
+			errs = append(errs, err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if !matched {
+		s.writeError(w, apierr.SourceNotFound())
+		return
+	}
+	if len(runs) == 0 && len(errs) > 0 {
+		s.writeError(w, apierr.IndexRunCreateFailed(errors.Join(errs...)))
+		return
+	}
+	if len(runs) == 0 {
+		// The push was to a branch no source tracks
+		writeJSON(w, http.StatusOK, map[string]any{"index_runs": runs})
+		return
+	}
+
+	s.logger.Info("webhook enqueued index runs", slog.Int("runs", len(runs)), slog.Int("failed", len(errs)))
+	writeJSON(w, http.StatusCreated, map[string]any{"index_runs": runs})
+}
+
+// repoKey reduces a repository URL to host/path, lowercased, so the HTTPS, SSH and web
+// URLs of one repository agree: https://github.com/Acme/App.git, git@github.com:acme/app
+// and ssh://git@github.com/acme/app/ all become github.com/acme/app. A ref the URL
+// names, as in https://github.com/acme/app@v1.2.0 or .../acme/app/tree/main, is dropped.
+func repoKey(raw string) string {
+	raw, _ = connectors.SplitRef(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		// scp-like syntax: [user@]host:path
+		host, path, ok := strings.Cut(raw, ":")
+		if !ok {
+			return ""
+		}
+		raw = "ssh://" + host + "/" + path
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if path == "" {
+		return ""
+	}
+	return strings.ToLower(u.Hostname() + "/" + path)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *Scheduler) writeError(w http.ResponseWriter, e *apierr.Error) {
+	if e.Status() >= 500 {
+		s.logger.Error(e.Message(), slog.String("code", string(e.Code())), slog.String("error", e.Error()))
+	}
+	writeJSON(w, e.Status(), e.Response())
+}
@@ -0,0 +1,121 @@
+package schema
+
+import "strings"
+
+// ColumnChange is a column added, dropped or retyped between two snapshots.
+type ColumnChange struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Type    string `json:"type,omitempty"`     // type in the newer run (the older run for drops)
+	OldType string `json:"old_type,omitempty"` // set for retyped columns
+}
+
+// ForeignKeyChange is a foreign key added or dropped between two snapshots.
+type ForeignKeyChange struct {
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+	References string `json:"references"`
+}
+
+// Diff is the schema change from an older snapshot to a newer one. Columns and foreign
+// keys of added or dropped tables are reported through the table alone.
+type Diff struct {
+	AddedTables        []string           `json:"added_tables"`
+	DroppedTables      []string           `json:"dropped_tables"`
+	AddedColumns       []ColumnChange     `json:"added_columns"`
+	DroppedColumns     []ColumnChange     `json:"dropped_columns"`
+	RetypedColumns     []ColumnChange     `json:"retyped_columns"`
+	AddedForeignKeys   []ForeignKeyChange `json:"added_foreign_keys"`
+	DroppedForeignKeys []ForeignKeyChange `json:"dropped_foreign_keys"`
+}
+
+// Empty reports whether the two snapshots have the same schema.
+func (d *Diff) Empty() bool {
+	return len(d.AddedTables) == 0 && len(d.DroppedTables) == 0 &&
+		len(d.AddedColumns) == 0 && len(d.DroppedColumns) == 0 && len(d.RetypedColumns) == 0 &&
+		len(d.AddedForeignKeys) == 0 && len(d.DroppedForeignKeys) == 0
+}
+
+// Compare diffs snapshot a (older) against b (newer). Entries follow the snapshots'
+// sorted table and column order. A column is only reported as retyped when both runs
+// know its type, so a parser that starts recording types does not flag every column.
+func Compare(a, b *Snapshot) *Diff {
+	d := &Diff{
+		AddedTables:        []string{},
+		DroppedTables:      []string{},
+		AddedColumns:       []ColumnChange{},
+		DroppedColumns:     []ColumnChange{},
+		RetypedColumns:     []ColumnChange{},
+		AddedForeignKeys:   []ForeignKeyChange{},
+		DroppedForeignKeys: []ForeignKeyChange{},
+	}
+	oldTables := indexTables(a)
+	newTables := indexTables(b)
+
+	for _, t := range a.Tables {
+		if _, ok := newTables[t.Name]; !ok {
+			d.DroppedTables = append(d.DroppedTables, t.Name)
+		}
+	}
+	for i := range b.Tables {
+		nt := &b.Tables[i]
+		ot, ok := oldTables[nt.Name]
+		if !ok {
+			d.AddedTables = append(d.AddedTables, nt.Name)
+			continue
+		}
+		d.compareColumns(ot, nt)
+		d.compareForeignKeys(ot, nt)
+	}
+	return d
+}
+
+func (d *Diff) compareColumns(ot, nt *Table) {
+	oldCols := make(map[string]Column, len(ot.Columns))
+	for _, c := range ot.Columns {
+		oldCols[c.Name] = c
+	}
+	newCols := make(map[string]bool, len(nt.Columns))
+	for _, c := range nt.Columns {
+		newCols[c.Name] = true
+		oc, ok := oldCols[c.Name]
+		switch {
+		case !ok:
+			d.AddedColumns = append(d.AddedColumns, ColumnChange{Table: nt.Name, Column: c.Name, Type: c.Type})
+		case oc.Type != "" && c.Type != "" && !strings.EqualFold(oc.Type, c.Type):
+			d.RetypedColumns = append(d.RetypedColumns, ColumnChange{Table: nt.Name, Column: c.Name, Type: c.Type, OldType: oc.Type})
+		}
+	}
+	for _, c := range ot.Columns {
+		if !newCols[c.Name] {
+			d.DroppedColumns = append(d.DroppedColumns, ColumnChange{Table: ot.Name, Column: c.Name, Type: c.Type})
+		}
+	}
+}
+
+func (d *Diff) compareForeignKeys(ot, nt *Table) {
+	oldFKs := make(map[ForeignKey]bool, len(ot.ForeignKeys))
+	for _, fk := range ot.ForeignKeys {
+		oldFKs[fk] = true
+	}
+	newFKs := make(map[ForeignKey]bool, len(nt.ForeignKeys))
+	for _, fk := range nt.ForeignKeys {
+		newFKs[fk] = true
+		if !oldFKs[fk] {
+			d.AddedForeignKeys = append(d.AddedForeignKeys, ForeignKeyChange{Table: nt.Name, Column: fk.Column, References: fk.References})
+		}
+	}
+	for _, fk := range ot.ForeignKeys {
+		if !newFKs[fk] {
+			d.DroppedForeignKeys = append(d.DroppedForeignKeys, ForeignKeyChange{Table: ot.Name, Column: fk.Column, References: fk.References})
+		}
+	}
+}
+
+func indexTables(s *Snapshot) map[string]*Table {
+	m := make(map[string]*Table, len(s.Tables))
+	for i := range s.Tables {
+		m[s.Tables[i].Name] = &s.Tables[i]
+	}
+	return m
+}
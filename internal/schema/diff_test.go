@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func str(s string) *string { return &s }
+
+// runA is the schema as of the first run.
+var runA = []postgres.ListSchemaSymbolsByProjectRow{
+	{QualifiedName: "public.customers", Kind: "table"},
+	{QualifiedName: "public.customers.id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.customers.fax", Kind: "column", Signature: str("varchar(20)")},
+	{QualifiedName: "public.orders", Kind: "table"},
+	{QualifiedName: "public.orders.id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.orders.customer_id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.orders.total", Kind: "column", Signature: str("numeric(10,2)")},
+	{QualifiedName: "public.legacy_audit", Kind: "table"},
+	{QualifiedName: "public.active_orders", Kind: "view"},
+	{QualifiedName: "public.active_orders.id", Kind: "column"},
+}
+
+// runB adds customers.email, drops customers.fax, widens orders.total, adds a
+// shipments table and drops legacy_audit.
+var runB = []postgres.ListSchemaSymbolsByProjectRow{
+	{QualifiedName: "public.customers", Kind: "table"},
+	{QualifiedName: "public.customers.id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.customers.email", Kind: "column", Signature: str("text")},
+	{QualifiedName: "public.orders", Kind: "table"},
+	{QualifiedName: "public.orders.id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.orders.customer_id", Kind: "column", Signature: str("int4")},
+	{QualifiedName: "public.orders.total", Kind: "column", Signature: str("numeric(12,2)")},
+	{QualifiedName: "public.shipments", Kind: "table"},
+	{QualifiedName: "public.shipments.order_id", Kind: "column", Signature: str("int4")},
+}
+
+func TestCompare_ColumnAddedAndDropped(t *testing.T) {
+	a := Build(runA, []postgres.ListEdgeEndpointsByTypeRow{
+		{SourceName: "public.orders.customer_id", TargetName: "public.customers.id"},
+	})
+	b := Build(runB, []postgres.ListEdgeEndpointsByTypeRow{
+		{SourceName: "public.shipments.order_id", TargetName: "public.orders.id"},
+	})
+
+	d := Compare(a, b)
+
+	if len(d.AddedColumns) != 1 || d.AddedColumns[0] != (ColumnChange{Table: "public.customers", Column: "email", Type: "text"}) {
+		t.Errorf("expected customers.email added, got %+v", d.AddedColumns)
+	}
+	if len(d.DroppedColumns) != 1 || d.DroppedColumns[0] != (ColumnChange{Table: "public.customers", Column: "fax", Type: "varchar(20)"}) {
+		t.Errorf("expected customers.fax dropped, got %+v", d.DroppedColumns)
+	}
+	if len(d.RetypedColumns) != 1 || d.RetypedColumns[0].OldType != "numeric(10,2)" || d.RetypedColumns[0].Type != "numeric(12,2)" {
+		t.Errorf("expected orders.total retyped, got %+v", d.RetypedColumns)
+	}
+	if len(d.AddedTables) != 1 || d.AddedTables[0] != "public.shipments" {
+		t.Errorf("expected shipments added, got %v", d.AddedTables)
+	}
+	if len(d.DroppedTables) != 1 || d.DroppedTables[0] != "public.legacy_audit" {
+		t.Errorf("expected legacy_audit dropped, got %v", d.DroppedTables)
+	}
+	// The new table's foreign key is reported through the table; the orders FK was dropped.
+	if len(d.AddedForeignKeys) != 0 {
+		t.Errorf("expected no added foreign keys on existing tables, got %+v", d.AddedForeignKeys)
+	}
+	if len(d.DroppedForeignKeys) != 1 || d.DroppedForeignKeys[0].References != "public.customers.id" {
+		t.Errorf("expected orders.customer_id foreign key dropped, got %+v", d.DroppedForeignKeys)
+	}
+	if !Compare(b, b).Empty() {
+		t.Error("expected a snapshot to have no diff against itself")
+	}
+}
+
+func TestBuild_SkipsViewColumns(t *testing.T) {
+	snap := Build(runA, nil)
+	for _, tbl := range snap.Tables {
+		if tbl.Name == "public.active_orders" {
+			t.Fatal("views must not appear as tables")
+		}
+	}
+	if len(snap.Tables) != 3 {
+		t.Errorf("expected 3 tables, got %d", len(snap.Tables))
+	}
+}
+
+func TestCompare_UnknownTypeIsNotRetyped(t *testing.T) {
+	a := &Snapshot{Tables: []Table{{Name: "t", Columns: []Column{{Name: "c"}}}}}
+	b := &Snapshot{Tables: []Table{{Name: "t", Columns: []Column{{Name: "c", Type: "int"}}}}}
+	if d := Compare(a, b); !d.Empty() {
+		t.Errorf("expected no diff when the older run has no type, got %+v", d)
+	}
+}
+
+// fakeSnapshots is an in-memory schema_snapshots table.
+type fakeSnapshots struct {
+	rows []postgres.SchemaSnapshot // newest first
+}
+
+func (f *fakeSnapshots) GetSchemaSnapshot(_ context.Context, runID uuid.UUID) (postgres.SchemaSnapshot, error) {
+	for _, s := range f.rows {
+		if s.IndexRunID == runID {
+			return s, nil
+		}
+	}
+	return postgres.SchemaSnapshot{}, pgx.ErrNoRows
+}
+
+func (f *fakeSnapshots) ListSchemaSnapshotsByProject(_ context.Context, arg postgres.ListSchemaSnapshotsByProjectParams) ([]postgres.SchemaSnapshot, error) {
+	var out []postgres.SchemaSnapshot
+	for _, s := range f.rows {
+		if s.ProjectID == arg.ProjectID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestLoadRuns_DefaultsToLatestTwo(t *testing.T) {
+	project := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	raw, _ := json.Marshal(Build(runA, nil))
+	now := time.Now()
+	db := &fakeSnapshots{rows: []postgres.SchemaSnapshot{
+		{IndexRunID: third, ProjectID: project, Snapshot: raw, CreatedAt: now},
+		{IndexRunID: second, ProjectID: project, Snapshot: raw, CreatedAt: now.Add(-time.Hour)},
+		{IndexRunID: first, ProjectID: project, Snapshot: raw, CreatedAt: now.Add(-2 * time.Hour)},
+	}}
+	ctx := context.Background()
+
+	a, b, err := LoadRuns(ctx, db, project, uuid.Nil, uuid.Nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.IndexRunID != second || b.IndexRunID != third {
+		t.Errorf("expected the latest two runs, got %s -> %s", a.IndexRunID, b.IndexRunID)
+	}
+
+	a, _, err = LoadRuns(ctx, db, project, uuid.Nil, second)
+	if err != nil || a.IndexRunID != first {
+		t.Errorf("expected run a to default to the run before b, got %s (%v)", a.IndexRunID, err)
+	}
+
+	if _, _, err := LoadRuns(ctx, db, project, uuid.Nil, first); !errors.Is(err, ErrNotEnoughRuns) {
+		t.Errorf("expected ErrNotEnoughRuns for the first run, got %v", err)
+	}
+	if _, _, err := LoadRuns(ctx, db, uuid.New(), first, third); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("expected another project's run to be not found, got %v", err)
+	}
+}
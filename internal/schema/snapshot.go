@@ -0,0 +1,198 @@
+// Package schema captures a project's relational schema (tables, columns and foreign keys)
+// at the end of each index run and compares two runs for migration review.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Snapshot is the schema of a project as of one index run.
+type Snapshot struct {
+	Tables []Table `json:"tables"`
+}
+
+// Table is a table with its columns and outgoing foreign keys.
+type Table struct {
+	Name        string       `json:"name"`
+	Columns     []Column     `json:"columns"`
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// Column is a table column and its declared type ("" when the parser could not tell).
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// ForeignKey links a column to the column (or table, for an implicit primary key) it references.
+type ForeignKey struct {
+	Column     string `json:"column"`
+	References string `json:"references"`
+}
+
+// Source is the subset of the store needed to capture a snapshot.
+type Source interface {
+	ListSchemaSymbolsByProject(ctx context.Context, projectID uuid.UUID) ([]postgres.ListSchemaSymbolsByProjectRow, error)
+	ListEdgeEndpointsByType(ctx context.Context, arg postgres.ListEdgeEndpointsByTypeParams) ([]postgres.ListEdgeEndpointsByTypeRow, error)
+}
+
+// Capture reads the project's current tables, columns and foreign keys.
+func Capture(ctx context.Context, db Source, projectID uuid.UUID) (*Snapshot, error) {
+	symbols, err := db.ListSchemaSymbolsByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list schema symbols: %w", err)
+	}
+	fks, err := db.ListEdgeEndpointsByType(ctx, postgres.ListEdgeEndpointsByTypeParams{
+		ProjectID: projectID,
+		EdgeType:  "foreign_key",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list foreign keys: %w", err)
+	}
+	return Build(symbols, fks), nil
+}
+
+// Build assembles a snapshot from table and column symbols and foreign_key edges.
+// Columns are attached to the table named by their qualified-name prefix; columns of
+// views and other non-table symbols are left out.
+func Build(symbols []postgres.ListSchemaSymbolsByProjectRow, fks []postgres.ListEdgeEndpointsByTypeRow) *Snapshot {
+	tables := make(map[string]*Table)
+	for _, s := range symbols {
+		if s.Kind == "table" && tables[s.QualifiedName] == nil {
+			tables[s.QualifiedName] = &Table{Name: s.QualifiedName}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range symbols {
+		if s.Kind != "column" || seen[s.QualifiedName] {
+			continue
+		}
+		table, name, ok := splitColumn(s.QualifiedName)
+		if !ok || tables[table] == nil {
+			continue
+		}
+		seen[s.QualifiedName] = true
+		col := Column{Name: name}
+		if s.Signature != nil {
+			col.Type = *s.Signature
+		}
+		tables[table].Columns = append(tables[table].Columns, col)
+	}
+
+	for _, fk := range fks {
+		table, name, ok := splitColumn(fk.SourceName)
+		if !ok || tables[table] == nil {
+			continue
+		}
+		tables[table].ForeignKeys = append(tables[table].ForeignKeys, ForeignKey{Column: name, References: fk.TargetName})
+	}
+
+	snap := &Snapshot{Tables: make([]Table, 0, len(tables))}
+	for _, t := range tables {
+		sort.Slice(t.Columns, func(i, j int) bool { return t.Columns[i].Name < t.Columns[j].Name })
+		sort.Slice(t.ForeignKeys, func(i, j int) bool {
+			if t.ForeignKeys[i].Column != t.ForeignKeys[j].Column {
+				return t.ForeignKeys[i].Column < t.ForeignKeys[j].Column
+			}
+			return t.ForeignKeys[i].References < t.ForeignKeys[j].References
+		})
+		snap.Tables = append(snap.Tables, *t)
+	}
+	sort.Slice(snap.Tables, func(i, j int) bool { return snap.Tables[i].Name < snap.Tables[j].Name })
+	return snap
+}
+
+// splitColumn splits a column's qualified name into its table and column name.
+func splitColumn(qualified string) (table, column string, ok bool) {
+	i := strings.LastIndex(qualified, ".")
+	if i <= 0 || i == len(qualified)-1 {
+		return "", "", false
+	}
+	return qualified[:i], qualified[i+1:], true
+}
+
+// Errors returned by LoadRuns.
+var (
+	ErrSnapshotNotFound = errors.New("no schema snapshot for index run")
+	ErrNotEnoughRuns    = errors.New("schema diff needs at least two indexed runs")
+)
+
+// maxSnapshotLookback bounds how many recent snapshots are scanned to find a run's predecessor.
+const maxSnapshotLookback = 100
+
+// SnapshotStore is the subset of the store needed to load stored snapshots.
+type SnapshotStore interface {
+	GetSchemaSnapshot(ctx context.Context, indexRunID uuid.UUID) (postgres.SchemaSnapshot, error)
+	ListSchemaSnapshotsByProject(ctx context.Context, arg postgres.ListSchemaSnapshotsByProjectParams) ([]postgres.SchemaSnapshot, error)
+}
+
+// LoadRuns returns the stored snapshots for runs a and b of a project. A zero run b
+// defaults to the project's latest snapshot and a zero run a to the snapshot preceding b.
+func LoadRuns(ctx context.Context, db SnapshotStore, projectID, runA, runB uuid.UUID) (a, b postgres.SchemaSnapshot, err error) {
+	if runB == uuid.Nil || runA == uuid.Nil {
+		recent, err := db.ListSchemaSnapshotsByProject(ctx, postgres.ListSchemaSnapshotsByProjectParams{
+			ProjectID: projectID,
+			Limit:     maxSnapshotLookback,
+		})
+		if err != nil {
+			return a, b, fmt.Errorf("list schema snapshots: %w", err)
+		}
+		if runB == uuid.Nil {
+			if len(recent) == 0 {
+				return a, b, ErrNotEnoughRuns
+			}
+			runB = recent[0].IndexRunID
+		}
+		if runA == uuid.Nil {
+			for i, s := range recent {
+				if s.IndexRunID == runB && i+1 < len(recent) {
+					runA = recent[i+1].IndexRunID
+					break
+				}
+			}
+			if runA == uuid.Nil {
+				return a, b, ErrNotEnoughRuns
+			}
+		}
+	}
+
+	if a, err = loadRun(ctx, db, projectID, runA); err != nil {
+		return a, b, err
+	}
+	b, err = loadRun(ctx, db, projectID, runB)
+	return a, b, err
+}
+
+func loadRun(ctx context.Context, db SnapshotStore, projectID, runID uuid.UUID) (postgres.SchemaSnapshot, error) {
+	s, err := db.GetSchemaSnapshot(ctx, runID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && s.ProjectID != projectID) {
+		return s, fmt.Errorf("%w %s", ErrSnapshotNotFound, runID)
+	}
+	if err != nil {
+		return s, fmt.Errorf("get schema snapshot %s: %w", runID, err)
+	}
+	return s, nil
+}
+
+// Decode parses a stored snapshot.
+func Decode(raw []byte) (*Snapshot, error) {
+	var snap Snapshot
+	if len(raw) == 0 {
+		return &snap, nil
+	}
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return nil, fmt.Errorf("decode schema snapshot: %w", err)
+	}
+	return &snap, nil
+}
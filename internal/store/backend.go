@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Backend is the core storage Lattice needs to index and query a project: projects,
+// sources, files, symbols, edges, symbol search and the basic analytics. Store, on
+// Postgres, is the only implementation; storetest is the conformance suite another
+// backend would have to pass. The API, MCP server and worker still take *Store.
+//
+// The methods keep the generated Postgres signatures, so code written against
+// *postgres.Queries moves to Backend without changes. A missing row is pgx.ErrNoRows on
+// every implementation.
+type Backend interface {
+	CreateProject(ctx context.Context, arg postgres.CreateProjectParams) (postgres.Project, error)
+	GetProject(ctx context.Context, slug string) (postgres.Project, error)
+	CreateSource(ctx context.Context, arg postgres.CreateSourceParams) (postgres.Source, error)
+	ListSourcesByProjectID(ctx context.Context, projectID uuid.UUID) ([]postgres.Source, error)
+
+	UpsertFile(ctx context.Context, arg postgres.UpsertFileParams) (postgres.File, error)
+	GetFileByPath(ctx context.Context, arg postgres.GetFileByPathParams) (postgres.File, error)
+
+	CreateSymbol(ctx context.Context, arg postgres.CreateSymbolParams) (postgres.Symbol, error)
+	GetSymbol(ctx context.Context, id uuid.UUID) (postgres.Symbol, error)
+	GetSymbolByQualifiedName(ctx context.Context, arg postgres.GetSymbolByQualifiedNameParams) (postgres.Symbol, error)
+	ListSymbolsByNames(ctx context.Context, arg postgres.ListSymbolsByNamesParams) ([]postgres.Symbol, error)
+	SearchSymbolsRanked(ctx context.Context, arg postgres.SearchSymbolsRankedParams) ([]postgres.Symbol, error)
+	CountSymbolsByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+	DeleteSymbolsByFileID(ctx context.Context, fileID uuid.UUID) error
+	UpdateSymbolMetadata(ctx context.Context, arg postgres.UpdateSymbolMetadataParams) error
+
+	CreateSymbolEdge(ctx context.Context, arg postgres.CreateSymbolEdgeParams) (postgres.SymbolEdge, error)
+	CreateSymbolEdgeWithMetadata(ctx context.Context, arg postgres.CreateSymbolEdgeWithMetadataParams) (postgres.SymbolEdge, error)
+	GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]postgres.SymbolEdge, error)
+	GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]postgres.SymbolEdge, error)
+	CountEdgesByProject(ctx context.Context, projectID uuid.UUID) (int64, error)
+
+	GetEdgeList(ctx context.Context, projectID uuid.UUID) ([]postgres.GetEdgeListRow, error)
+	GetSymbolDegrees(ctx context.Context, projectID uuid.UUID) ([]postgres.GetSymbolDegreesRow, error)
+	GetSymbolCountsByKind(ctx context.Context, projectID uuid.UUID) ([]postgres.GetSymbolCountsByKindRow, error)
+	GetSymbolCountsByLanguage(ctx context.Context, projectID uuid.UUID) ([]postgres.GetSymbolCountsByLanguageRow, error)
+}
+
+var _ Backend = (*Store)(nil)
+
+// BlobStore holds uploaded source archives; the MinIO client implements it.
+type BlobStore interface {
+	UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64) error
+	DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+}
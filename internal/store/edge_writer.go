@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// DefaultEdgeBatchSize is the number of edges buffered per multi-row insert.
+const DefaultEdgeBatchSize = 1000
+
+// edgeInserter is the query used to flush a batch of edges.
+type edgeInserter interface {
+	BatchCreateSymbolEdges(ctx context.Context, arg postgres.BatchCreateSymbolEdgesParams) (int64, error)
+}
+
+// edgeKey is the stable identity of an edge, matching the symbol_edges unique constraint.
+type edgeKey struct {
+	projectID, sourceID, targetID uuid.UUID
+	edgeType                      string
+}
+
+// EdgeWriter buffers symbol edges and writes them with one multi-row insert per batch.
+// Edges repeated on the same key within a batch are merged; a later edge with metadata
+// replaces an earlier one. A batch the database rejects is retried one edge at a time,
+// and edges that still fail are logged and skipped. Call Flush once all edges have been
+// added.
+type EdgeWriter struct {
+	db        edgeInserter
+	batchSize int
+	logger    *slog.Logger
+
+	pending []postgres.CreateSymbolEdgeWithMetadataParams
+	index   map[edgeKey]int
+	written int
+	skipped int
+	flushes int
+}
+
+// NewEdgeWriter returns a writer flushing every batchSize edges (DefaultEdgeBatchSize if <= 0).
+func NewEdgeWriter(db edgeInserter, batchSize int, logger *slog.Logger) *EdgeWriter {
+	if batchSize <= 0 {
+		batchSize = DefaultEdgeBatchSize
+	}
+	return &EdgeWriter{
+		db:        db,
+		batchSize: batchSize,
+		logger:    logger,
+		index:     make(map[edgeKey]int),
+	}
+}
+
+// Add buffers an edge, flushing when the batch is full. Empty metadata is stored as {}.
+func (w *EdgeWriter) Add(ctx context.Context, e postgres.CreateSymbolEdgeWithMetadataParams) error {
+	if len(e.Metadata) == 0 {
+		e.Metadata = []byte("{}")
+	}
+	key := edgeKey{e.ProjectID, e.SourceID, e.TargetID, e.EdgeType}
+	if i, ok := w.index[key]; ok {
+		if string(e.Metadata) != "{}" {
+			w.pending[i].Metadata = e.Metadata
+		}
+		return nil
+	}
+	w.index[key] = len(w.pending)
+	w.pending = append(w.pending, e)
+	if len(w.pending) >= w.batchSize {
+		return w.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush writes any buffered edges. It fails only when the context is done; edges the
+// database rejects are skipped (see Skipped).
+func (w *EdgeWriter) Flush(ctx context.Context) error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	n, err := w.db.BatchCreateSymbolEdges(ctx, batchParams(w.pending))
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("flush %d edges: %w", len(w.pending), err)
+		}
+		// One bad edge fails the whole insert: retry alone so the rest are kept
+		n = 0
+		for _, e := range w.pending {
+			one, err := w.db.BatchCreateSymbolEdges(ctx, batchParams([]postgres.CreateSymbolEdgeWithMetadataParams{e}))
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("flush %d edges: %w", len(w.pending), err)
+				}
+				w.skipped++
+				w.logger.Warn("skip edge",
+					slog.String("source_id", e.SourceID.String()),
+					slog.String("target_id", e.TargetID.String()),
+					slog.String("edge_type", e.EdgeType),
+					slog.String("error", err.Error()))
+				continue
+			}
+			n += one
+		}
+	}
+	w.written += int(n)
+	w.flushes++
+	w.pending = w.pending[:0]
+	clear(w.index)
+	return nil
+}
+
+// batchParams lays edges out as the batch query's parallel arrays.
+func batchParams(edges []postgres.CreateSymbolEdgeWithMetadataParams) postgres.BatchCreateSymbolEdgesParams {
+	arg := postgres.BatchCreateSymbolEdgesParams{
+		ProjectIds: make([]uuid.UUID, len(edges)),
+		SourceIds:  make([]uuid.UUID, len(edges)),
+		TargetIds:  make([]uuid.UUID, len(edges)),
+		EdgeTypes:  make([]string, len(edges)),
+		Metadata:   make([]string, len(edges)),
+	}
+	for i, e := range edges {
+		arg.ProjectIds[i] = e.ProjectID
+		arg.SourceIds[i] = e.SourceID
+		arg.TargetIds[i] = e.TargetID
+		arg.EdgeTypes[i] = e.EdgeType
+		arg.Metadata[i] = string(e.Metadata)
+	}
+	return arg
+}
+
+// Written returns the number of edges inserted or updated by completed flushes.
+func (w *EdgeWriter) Written() int { return w.written }
+
+// Skipped returns the number of edges the database rejected.
+func (w *EdgeWriter) Skipped() int { return w.skipped }
+
+// Flushes returns the number of batches written.
+func (w *EdgeWriter) Flushes() int { return w.flushes }
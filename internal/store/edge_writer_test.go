@@ -0,0 +1,179 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// fakeEdgeTable emulates symbol_edges: rows keyed on the edge key, with the batch
+// query's conflict handling. A batch holding a rejected edge fails as a whole.
+type fakeEdgeTable struct {
+	rows     map[edgeKey]string
+	rejected map[edgeKey]bool
+	batches  []int
+}
+
+func (f *fakeEdgeTable) BatchCreateSymbolEdges(_ context.Context, arg postgres.BatchCreateSymbolEdgesParams) (int64, error) {
+	f.batches = append(f.batches, len(arg.SourceIds))
+	for i := range arg.SourceIds {
+		if f.rejected[edgeKey{arg.ProjectIds[i], arg.SourceIds[i], arg.TargetIds[i], arg.EdgeTypes[i]}] {
+			return 0, errors.New("violates foreign key constraint")
+		}
+	}
+	seen := make(map[edgeKey]bool)
+	var affected int64
+	for i := range arg.SourceIds {
+		key := edgeKey{arg.ProjectIds[i], arg.SourceIds[i], arg.TargetIds[i], arg.EdgeTypes[i]}
+		if seen[key] {
+			panic("ON CONFLICT DO UPDATE command cannot affect row a second time")
+		}
+		seen[key] = true
+		existing, ok := f.rows[key]
+		switch {
+		case !ok:
+			f.rows[key] = arg.Metadata[i]
+			affected++
+		case arg.Metadata[i] != "{}" && existing != arg.Metadata[i]:
+			f.rows[key] = arg.Metadata[i]
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+func TestEdgeWriter_FlushesInBatches(t *testing.T) {
+	db := &fakeEdgeTable{rows: make(map[edgeKey]string)}
+	w := NewEdgeWriter(db, 100, discardLogger())
+	ctx := context.Background()
+	project := uuid.New()
+
+	const n = 1050
+	for range n {
+		if err := w.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+			ProjectID: project, SourceID: uuid.New(), TargetID: uuid.New(), EdgeType: "calls",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (n + 99) / 100; w.Flushes() != want || len(db.batches) != want {
+		t.Errorf("expected %d flushes, got %d (%v)", want, w.Flushes(), db.batches)
+	}
+	if db.batches[len(db.batches)-1] != 50 {
+		t.Errorf("expected a final partial batch of 50, got %d", db.batches[len(db.batches)-1])
+	}
+	if w.Written() != n || len(db.rows) != n {
+		t.Errorf("expected %d edges written, got %d (%d rows)", n, w.Written(), len(db.rows))
+	}
+}
+
+func TestEdgeWriter_DedupesOnEdgeKey(t *testing.T) {
+	db := &fakeEdgeTable{rows: make(map[edgeKey]string)}
+	w := NewEdgeWriter(db, 3, discardLogger())
+	ctx := context.Background()
+	project, a, b, c := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	edge := func(src, tgt uuid.UUID, typ, meta string) postgres.CreateSymbolEdgeWithMetadataParams {
+		e := postgres.CreateSymbolEdgeWithMetadataParams{ProjectID: project, SourceID: src, TargetID: tgt, EdgeType: typ}
+		if meta != "" {
+			e.Metadata = []byte(meta)
+		}
+		return e
+	}
+
+	for _, e := range []postgres.CreateSymbolEdgeWithMetadataParams{
+		edge(a, b, "calls", ""),
+		edge(a, b, "calls", `{"conditional": true}`), // same key in batch: metadata wins
+		edge(a, b, "reads_from", ""),
+		edge(a, b, "calls", ""),       // same key again: keeps metadata
+		edge(b, c, "calls", ""),       // fills the batch
+		edge(a, b, "calls", ""),       // next batch: conflicts with stored row, no-op
+		edge(a, c, "writes_to", `{}`), // empty metadata object
+	} {
+		if err := w.Add(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.batches) != 2 || db.batches[0] != 3 || db.batches[1] != 2 {
+		t.Errorf("expected batches of 3 and 2 unique edges, got %v", db.batches)
+	}
+	if len(db.rows) != 4 || w.Written() != 4 {
+		t.Errorf("expected 4 distinct edges written, got %d rows, %d written", len(db.rows), w.Written())
+	}
+	if got := db.rows[edgeKey{project, a, b, "calls"}]; got != `{"conditional": true}` {
+		t.Errorf("expected merged metadata on a->b calls, got %s", got)
+	}
+}
+
+func TestEdgeWriter_SkipsRejectedEdges(t *testing.T) {
+	project, a, b, c := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	db := &fakeEdgeTable{
+		rows:     make(map[edgeKey]string),
+		rejected: map[edgeKey]bool{{project, a, c, "calls"}: true},
+	}
+	w := NewEdgeWriter(db, 10, discardLogger())
+	ctx := context.Background()
+
+	for _, tgt := range []uuid.UUID{b, c} {
+		if err := w.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+			ProjectID: project, SourceID: a, TargetID: tgt, EdgeType: "calls",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+		ProjectID: project, SourceID: b, TargetID: c, EdgeType: "calls",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(ctx); err != nil {
+		t.Fatalf("expected the rejected edge to be skipped, got %v", err)
+	}
+
+	if w.Written() != 2 || w.Skipped() != 1 {
+		t.Errorf("expected 2 written and 1 skipped, got %d and %d", w.Written(), w.Skipped())
+	}
+	if _, ok := db.rows[edgeKey{project, a, b, "calls"}]; !ok {
+		t.Error("expected a->b to be kept")
+	}
+	if _, ok := db.rows[edgeKey{project, b, c, "calls"}]; !ok {
+		t.Error("expected b->c to be kept")
+	}
+}
+
+func TestEdgeWriter_FailsWhenContextDone(t *testing.T) {
+	project, a, b := uuid.New(), uuid.New(), uuid.New()
+	db := &fakeEdgeTable{
+		rows:     make(map[edgeKey]string),
+		rejected: map[edgeKey]bool{{project, a, b, "calls"}: true},
+	}
+	w := NewEdgeWriter(db, 10, discardLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Add(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+		ProjectID: project, SourceID: a, TargetID: b, EdgeType: "calls",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := w.Flush(ctx); err == nil {
+		t.Fatal("expected an error once the context is done")
+	}
+	if len(db.batches) != 1 {
+		t.Errorf("expected no per-edge retries, got batches %v", db.batches)
+	}
+}
+
+func discardLogger() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
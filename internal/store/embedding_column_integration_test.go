@@ -0,0 +1,20 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+// The local embedder pads its vectors to this width, so it must be the migrated one.
+func TestEmbeddingColumnDimensions(t *testing.T) {
+	s := setupStore(t)
+	got, err := s.GetEmbeddingColumnDimensions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1024 {
+		t.Errorf("expected the vector(1024) column of the initial schema, got %d", got)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// The table filter is applied before the limit: an endpoint touching the table is found
+// however many busier endpoints do not.
+func TestListEndpointsTouchingTable(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, a, edge := seedProject(t, s)
+
+	create := func(name, kind, meta string) postgres.Symbol {
+		sym, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: a.FileID, Name: name, QualifiedName: name,
+			Kind: kind, Language: "csharp", StartLine: 1, EndLine: 2, Metadata: []byte(meta),
+		})
+		if err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+		return sym
+	}
+	link := func(from, to postgres.Symbol, edgeType string) {
+		if _, err := s.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{
+			ProjectID: proj.ID, SourceID: from.ID, TargetID: to.ID, EdgeType: edgeType,
+		}); err != nil {
+			t.Fatalf("create edge: %v", err)
+		}
+	}
+
+	for i := range 60 {
+		create(fmt.Sprintf("GET /api/busy/%d", i), "endpoint", `{"in_degree": 9}`)
+	}
+	orders := create("dbo.Orders", "table", `{}`)
+	b, err := s.GetSymbol(ctx, edge.TargetID)
+	if err != nil {
+		t.Fatalf("get symbol: %v", err)
+	}
+	// GET /api/orders → dbo.A → dbo.B → dbo.Orders
+	quiet := create("GET /api/orders", "endpoint", `{}`)
+	link(quiet, a, "calls")
+	link(b, orders, "reads_from")
+
+	arg := postgres.ListEndpointsTouchingTableParams{
+		ProjectID: proj.ID, TableName: "DBO.ORDERS", MaxDepth: 3,
+		EdgeTypes: []string{"calls", "reads_from"}, Lim: 10,
+	}
+	got, err := s.ListEndpointsTouchingTable(ctx, arg)
+	if err != nil {
+		t.Fatalf("list endpoints: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != quiet.ID {
+		t.Fatalf("expected GET /api/orders, got %v", got)
+	}
+
+	arg.MaxDepth = 2
+	if got, err = s.ListEndpointsTouchingTable(ctx, arg); err != nil || len(got) != 0 {
+		t.Errorf("expected no endpoint within 2 hops, got %v (%v)", got, err)
+	}
+}
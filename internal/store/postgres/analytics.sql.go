@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const batchUpdateSymbolMetadata = `-- name: BatchUpdateSymbolMetadata :exec
@@ -30,6 +31,26 @@ func (q *Queries) BatchUpdateSymbolMetadata(ctx context.Context, arg BatchUpdate
 	return err
 }
 
+const batchUpdateSymbolMetadataEach = `-- name: BatchUpdateSymbolMetadataEach :exec
+UPDATE symbols s
+SET metadata = s.metadata || u.analytics_json::jsonb,
+    updated_at = now()
+FROM unnest($1::uuid[], $2::text[]) AS u(symbol_id, analytics_json)
+WHERE s.id = u.symbol_id
+`
+
+type BatchUpdateSymbolMetadataEachParams struct {
+	SymbolIds     []uuid.UUID `json:"symbol_ids"`
+	AnalyticsJson []string    `json:"analytics_json"`
+}
+
+// Batch update symbol metadata, merging the analytics_json at each index into the symbol
+// at the same index of symbol_ids
+func (q *Queries) BatchUpdateSymbolMetadataEach(ctx context.Context, arg BatchUpdateSymbolMetadataEachParams) error {
+	_, err := q.db.Exec(ctx, batchUpdateSymbolMetadataEach, arg.SymbolIds, arg.AnalyticsJson)
+	return err
+}
+
 const countSymbolsByLayer = `-- name: CountSymbolsByLayer :many
 SELECT metadata->>'layer' AS layer, count(*) AS cnt
 FROM symbols
@@ -74,6 +95,24 @@ func (q *Queries) DeleteProjectAnalytics(ctx context.Context, projectID uuid.UUI
 	return err
 }
 
+const deleteStaleProjectAnalytics = `-- name: DeleteStaleProjectAnalytics :exec
+DELETE FROM project_analytics
+WHERE project_id = $1 AND scope = $2
+  AND NOT (scope_id = ANY($3::text[]))
+`
+
+type DeleteStaleProjectAnalyticsParams struct {
+	ProjectID    uuid.UUID `json:"project_id"`
+	Scope        string    `json:"scope"`
+	KeepScopeIds []string  `json:"keep_scope_ids"`
+}
+
+// Rows of one scope whose scope_id is not among the ones just written
+func (q *Queries) DeleteStaleProjectAnalytics(ctx context.Context, arg DeleteStaleProjectAnalyticsParams) error {
+	_, err := q.db.Exec(ctx, deleteStaleProjectAnalytics, arg.ProjectID, arg.Scope, arg.KeepScopeIds)
+	return err
+}
+
 const getBridgeCoverageStats = `-- name: GetBridgeCoverageStats :one
 SELECT
     count(*) FILTER (WHERE e.metadata ? 'confidence') AS edges_with_confidence,
@@ -189,6 +228,106 @@ func (q *Queries) GetEdgeList(ctx context.Context, projectID uuid.UUID) ([]GetEd
 	return items, nil
 }
 
+const getEdgeConfidences = `-- name: GetEdgeConfidences :many
+SELECT source_id, target_id,
+    COALESCE((metadata->>'confidence')::float, 1)::float AS confidence
+FROM symbol_edges WHERE project_id = $1
+`
+
+type GetEdgeConfidencesRow struct {
+	SourceID   uuid.UUID `json:"source_id"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Confidence float64   `json:"confidence"`
+}
+
+// Edge list with resolution confidence; edges without a recorded confidence are exact (1)
+func (q *Queries) GetEdgeConfidences(ctx context.Context, projectID uuid.UUID) ([]GetEdgeConfidencesRow, error) {
+	rows, err := q.db.Query(ctx, getEdgeConfidences, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetEdgeConfidencesRow{}
+	for rows.Next() {
+		var i GetEdgeConfidencesRow
+		if err := rows.Scan(&i.SourceID, &i.TargetID, &i.Confidence); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getColumnPredicateUsage = `-- name: GetColumnPredicateUsage :many
+SELECT c.id, c.qualified_name,
+    count(*) FILTER (WHERE e.metadata->>'derivation_type' = 'filter') AS filters,
+    count(*) FILTER (WHERE e.metadata->>'derivation_type' = 'join') AS joins
+FROM symbol_edges e
+JOIN symbols c ON c.id = e.source_id
+WHERE e.project_id = $1 AND e.deleted_at IS NULL
+  AND e.edge_type = 'uses_column' AND c.kind = 'column'
+  AND e.metadata->>'derivation_type' IN ('filter', 'join')
+GROUP BY c.id, c.qualified_name
+`
+
+type GetColumnPredicateUsageRow struct {
+	ID            uuid.UUID `json:"id"`
+	QualifiedName string    `json:"qualified_name"`
+	Filters       int64     `json:"filters"`
+	Joins         int64     `json:"joins"`
+}
+
+// Columns compared in WHERE clauses and join conditions, with the number of queries
+// (procedures, views, functions) filtering or joining on each
+func (q *Queries) GetColumnPredicateUsage(ctx context.Context, projectID uuid.UUID) ([]GetColumnPredicateUsageRow, error) {
+	rows, err := q.db.Query(ctx, getColumnPredicateUsage, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetColumnPredicateUsageRow{}
+	for rows.Next() {
+		var i GetColumnPredicateUsageRow
+		if err := rows.Scan(&i.ID, &i.QualifiedName, &i.Filters, &i.Joins); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIndexedColumnIDs = `-- name: GetIndexedColumnIDs :many
+SELECT DISTINCT target_id FROM symbol_edges
+WHERE project_id = $1 AND deleted_at IS NULL AND edge_type = 'indexes'
+`
+
+// Columns leading an index or key constraint
+func (q *Queries) GetIndexedColumnIDs(ctx context.Context, projectID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getIndexedColumnIDs, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var target_id uuid.UUID
+		if err := rows.Scan(&target_id); err != nil {
+			return nil, err
+		}
+		items = append(items, target_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNamespaceStats = `-- name: GetNamespaceStats :many
 SELECT
     CASE
@@ -500,7 +639,7 @@ func (q *Queries) GetSymbolDegrees(ctx context.Context, projectID uuid.UUID) ([]
 }
 
 const getSymbolsByLayer = `-- name: GetSymbolsByLayer :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols
 WHERE project_id = $1
   AND metadata->>'layer' = $2
 ORDER BY qualified_name
@@ -546,6 +685,7 @@ func (q *Queries) GetSymbolsByLayer(ctx context.Context, arg GetSymbolsByLayerPa
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -631,7 +771,7 @@ func (q *Queries) ListProjectAnalyticsByScope(ctx context.Context, arg ListProje
 }
 
 const topSymbolsByInDegree = `-- name: TopSymbolsByInDegree :many
-SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, (s.metadata->>'in_degree')::int AS in_degree
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at, (s.metadata->>'in_degree')::int AS in_degree
 FROM symbols s
 WHERE s.project_id = $1
   AND s.metadata ? 'in_degree'
@@ -646,23 +786,24 @@ type TopSymbolsByInDegreeParams struct {
 }
 
 type TopSymbolsByInDegreeRow struct {
-	ID            uuid.UUID `json:"id"`
-	ProjectID     uuid.UUID `json:"project_id"`
-	FileID        uuid.UUID `json:"file_id"`
-	Name          string    `json:"name"`
-	QualifiedName string    `json:"qualified_name"`
-	Kind          string    `json:"kind"`
-	Language      string    `json:"language"`
-	StartLine     int32     `json:"start_line"`
-	EndLine       int32     `json:"end_line"`
-	StartCol      *int32    `json:"start_col"`
-	EndCol        *int32    `json:"end_col"`
-	Signature     *string   `json:"signature"`
-	DocComment    *string   `json:"doc_comment"`
-	Metadata      []byte    `json:"metadata"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	InDegree      int32     `json:"in_degree"`
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	InDegree      int32              `json:"in_degree"`
 }
 
 // Top symbols by in-degree (most depended-upon)
@@ -692,6 +833,7 @@ func (q *Queries) TopSymbolsByInDegree(ctx context.Context, arg TopSymbolsByInDe
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 			&i.InDegree,
 		); err != nil {
 			return nil, err
@@ -705,7 +847,7 @@ func (q *Queries) TopSymbolsByInDegree(ctx context.Context, arg TopSymbolsByInDe
 }
 
 const topSymbolsByPageRank = `-- name: TopSymbolsByPageRank :many
-SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, (s.metadata->>'pagerank')::float AS pagerank
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at, (s.metadata->>'pagerank')::float AS pagerank
 FROM symbols s
 WHERE s.project_id = $1
   AND s.metadata ? 'pagerank'
@@ -719,23 +861,24 @@ type TopSymbolsByPageRankParams struct {
 }
 
 type TopSymbolsByPageRankRow struct {
-	ID            uuid.UUID `json:"id"`
-	ProjectID     uuid.UUID `json:"project_id"`
-	FileID        uuid.UUID `json:"file_id"`
-	Name          string    `json:"name"`
-	QualifiedName string    `json:"qualified_name"`
-	Kind          string    `json:"kind"`
-	Language      string    `json:"language"`
-	StartLine     int32     `json:"start_line"`
-	EndLine       int32     `json:"end_line"`
-	StartCol      *int32    `json:"start_col"`
-	EndCol        *int32    `json:"end_col"`
-	Signature     *string   `json:"signature"`
-	DocComment    *string   `json:"doc_comment"`
-	Metadata      []byte    `json:"metadata"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	Pagerank      float64   `json:"pagerank"`
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	Pagerank      float64            `json:"pagerank"`
 }
 
 // Top symbols by PageRank
@@ -765,6 +908,7 @@ func (q *Queries) TopSymbolsByPageRank(ctx context.Context, arg TopSymbolsByPage
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 			&i.Pagerank,
 		); err != nil {
 			return nil, err
@@ -7,12 +7,67 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const batchCreateSymbolEdges = `-- name: BatchCreateSymbolEdges :execrows
+INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
+SELECT unnest($1::uuid[]), unnest($2::uuid[]), unnest($3::uuid[]),
+       unnest($4::text[]), unnest($5::text[])::jsonb
+ON CONFLICT (project_id, source_id, target_id, edge_type) DO UPDATE
+SET metadata = EXCLUDED.metadata
+WHERE EXCLUDED.metadata <> '{}'::jsonb
+  AND symbol_edges.metadata IS DISTINCT FROM EXCLUDED.metadata
+`
+
+type BatchCreateSymbolEdgesParams struct {
+	ProjectIds []uuid.UUID `json:"project_ids"`
+	SourceIds  []uuid.UUID `json:"source_ids"`
+	TargetIds  []uuid.UUID `json:"target_ids"`
+	EdgeTypes  []string    `json:"edge_types"`
+	Metadata   []string    `json:"metadata"`
+}
+
+// Multi-row edge insert keyed on (project_id, source_id, target_id, edge_type).
+// Existing edges keep their metadata unless the incoming row carries different metadata.
+func (q *Queries) BatchCreateSymbolEdges(ctx context.Context, arg BatchCreateSymbolEdgesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, batchCreateSymbolEdges,
+		arg.ProjectIds,
+		arg.SourceIds,
+		arg.TargetIds,
+		arg.EdgeTypes,
+		arg.Metadata,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const clearDerivedEdgeMetadataByFile = `-- name: ClearDerivedEdgeMetadataByFile :execrows
+UPDATE symbol_edges e
+SET metadata = e.metadata - '{confidence,match_strategy,bridge,conditional,mapping,superseded_by}'::text[]
+FROM symbols s
+WHERE s.id = e.source_id AND s.file_id = $1
+  AND e.edge_type NOT IN ('transforms_to', 'direct_copy', 'uses_column', 'related_to')
+  AND e.metadata ?| '{confidence,match_strategy,bridge,conditional,mapping,superseded_by}'::text[]
+`
+
+// Parse persistence and resolution derive an edge's confidence, match strategy, conditional
+// flag and superseded mapping; the batch insert leaves them on existing edges, so they are
+// stripped from the edges leaving a file's symbols before it is re-indexed.
+func (q *Queries) ClearDerivedEdgeMetadataByFile(ctx context.Context, fileID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, clearDerivedEdgeMetadataByFile, fileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const countEdgesByProject = `-- name: CountEdgesByProject :one
-SELECT count(*) FROM symbol_edges WHERE project_id = $1
+SELECT count(*) FROM symbol_edges WHERE project_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountEdgesByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
@@ -26,7 +81,7 @@ const createSymbolEdge = `-- name: CreateSymbolEdge :one
 INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type)
 VALUES ($1, $2, $3, $4)
 ON CONFLICT (project_id, source_id, target_id, edge_type) DO NOTHING
-RETURNING id, project_id, source_id, target_id, edge_type, metadata, created_at
+RETURNING id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at
 `
 
 type CreateSymbolEdgeParams struct {
@@ -52,6 +107,7 @@ func (q *Queries) CreateSymbolEdge(ctx context.Context, arg CreateSymbolEdgePara
 		&i.EdgeType,
 		&i.Metadata,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -61,7 +117,7 @@ INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
 VALUES ($1, $2, $3, $4, $5)
 ON CONFLICT (project_id, source_id, target_id, edge_type) DO UPDATE
 SET metadata = EXCLUDED.metadata
-RETURNING id, project_id, source_id, target_id, edge_type, metadata, created_at
+RETURNING id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at
 `
 
 type CreateSymbolEdgeWithMetadataParams struct {
@@ -89,12 +145,77 @@ func (q *Queries) CreateSymbolEdgeWithMetadata(ctx context.Context, arg CreateSy
 		&i.EdgeType,
 		&i.Metadata,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const deleteColumnEdgesByOrigins = `-- name: DeleteColumnEdgesByOrigins :many
+DELETE FROM symbol_edges e
+WHERE e.project_id = $1
+  AND e.edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND CASE WHEN e.metadata ? 'origins' THEN NOT EXISTS (
+          SELECT 1 FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+          WHERE NOT (o.origin = ANY($2::text[])
+                     OR ($3::bool AND starts_with(o.origin, $4::text))))
+      ELSE $3::bool AND e.metadata ? 'derivation_type' END
+RETURNING e.id
+`
+
+type DeleteColumnEdgesByOriginsParams struct {
+	ProjectID    uuid.UUID `json:"project_id"`
+	Origins      []string  `json:"origins"`
+	AllFiles     bool      `json:"all_files"`
+	SourcePrefix string    `json:"source_prefix"`
+}
+
+// Column-lineage edges derived only from released origins: the given ones, or with
+// all_files every origin starting with source_prefix, along with the edges written before
+// edges listed their origins. Edges that other files also derive are kept.
+func (q *Queries) DeleteColumnEdgesByOrigins(ctx context.Context, arg DeleteColumnEdgesByOriginsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, deleteColumnEdgesByOrigins,
+		arg.ProjectID,
+		arg.Origins,
+		arg.AllFiles,
+		arg.SourcePrefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteEdgesByType = `-- name: DeleteEdgesByType :execrows
+DELETE FROM symbol_edges WHERE project_id = $1 AND edge_type = $2
+`
+
+type DeleteEdgesByTypeParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	EdgeType  string    `json:"edge_type"`
+}
+
+func (q *Queries) DeleteEdgesByType(ctx context.Context, arg DeleteEdgesByTypeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteEdgesByType, arg.ProjectID, arg.EdgeType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getIncomingEdges = `-- name: GetIncomingEdges :many
-SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at FROM symbol_edges WHERE target_id = $1
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges WHERE target_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]SymbolEdge, error) {
@@ -114,6 +235,7 @@ func (q *Queries) GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]S
 			&i.EdgeType,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -126,7 +248,7 @@ func (q *Queries) GetIncomingEdges(ctx context.Context, targetID uuid.UUID) ([]S
 }
 
 const getOutgoingEdges = `-- name: GetOutgoingEdges :many
-SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at FROM symbol_edges WHERE source_id = $1
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges WHERE source_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]SymbolEdge, error) {
@@ -146,6 +268,7 @@ func (q *Queries) GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]S
 			&i.EdgeType,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -158,8 +281,8 @@ func (q *Queries) GetOutgoingEdges(ctx context.Context, sourceID uuid.UUID) ([]S
 }
 
 const listColumnEdgesByProject = `-- name: ListColumnEdgesByProject :many
-SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at FROM symbol_edges
-WHERE project_id = $1
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges
+WHERE project_id = $1 AND deleted_at IS NULL
   AND edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
 `
 
@@ -180,6 +303,7 @@ func (q *Queries) ListColumnEdgesByProject(ctx context.Context, projectID uuid.U
 			&i.EdgeType,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -191,8 +315,47 @@ func (q *Queries) ListColumnEdgesByProject(ctx context.Context, projectID uuid.U
 	return items, nil
 }
 
+const listEdgeEndpointsByType = `-- name: ListEdgeEndpointsByType :many
+SELECT s.qualified_name AS source_name, t.qualified_name AS target_name
+FROM symbol_edges e
+JOIN symbols s ON s.id = e.source_id
+JOIN symbols t ON t.id = e.target_id
+WHERE e.project_id = $1 AND e.edge_type = $2 AND e.deleted_at IS NULL
+ORDER BY s.qualified_name, t.qualified_name
+`
+
+type ListEdgeEndpointsByTypeParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	EdgeType  string    `json:"edge_type"`
+}
+
+type ListEdgeEndpointsByTypeRow struct {
+	SourceName string `json:"source_name"`
+	TargetName string `json:"target_name"`
+}
+
+func (q *Queries) ListEdgeEndpointsByType(ctx context.Context, arg ListEdgeEndpointsByTypeParams) ([]ListEdgeEndpointsByTypeRow, error) {
+	rows, err := q.db.Query(ctx, listEdgeEndpointsByType, arg.ProjectID, arg.EdgeType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEdgeEndpointsByTypeRow{}
+	for rows.Next() {
+		var i ListEdgeEndpointsByTypeRow
+		if err := rows.Scan(&i.SourceName, &i.TargetName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEdgesByProject = `-- name: ListEdgesByProject :many
-SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at FROM symbol_edges WHERE project_id = $1
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges WHERE project_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) ListEdgesByProject(ctx context.Context, projectID uuid.UUID) ([]SymbolEdge, error) {
@@ -212,6 +375,109 @@ func (q *Queries) ListEdgesByProject(ctx context.Context, projectID uuid.UUID) (
 			&i.EdgeType,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncomingEdgesPage = `-- name: ListIncomingEdgesPage :many
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges
+WHERE target_id = $1
+  AND id > $2::uuid
+  AND deleted_at IS NULL
+  AND (cardinality($3::text[]) = 0 OR edge_type = ANY($3::text[]))
+ORDER BY id
+LIMIT $4
+`
+
+type ListIncomingEdgesPageParams struct {
+	TargetID  uuid.UUID `json:"target_id"`
+	AfterID   uuid.UUID `json:"after_id"`
+	EdgeTypes []string  `json:"edge_types"`
+	Lim       int32     `json:"lim"`
+}
+
+func (q *Queries) ListIncomingEdgesPage(ctx context.Context, arg ListIncomingEdgesPageParams) ([]SymbolEdge, error) {
+	rows, err := q.db.Query(ctx, listIncomingEdgesPage,
+		arg.TargetID,
+		arg.AfterID,
+		arg.EdgeTypes,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SymbolEdge{}
+	for rows.Next() {
+		var i SymbolEdge
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.SourceID,
+			&i.TargetID,
+			&i.EdgeType,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingEdgesPage = `-- name: ListOutgoingEdgesPage :many
+SELECT id, project_id, source_id, target_id, edge_type, metadata, created_at, deleted_at FROM symbol_edges
+WHERE source_id = $1
+  AND id > $2::uuid
+  AND deleted_at IS NULL
+  AND (cardinality($3::text[]) = 0 OR edge_type = ANY($3::text[]))
+ORDER BY id
+LIMIT $4
+`
+
+type ListOutgoingEdgesPageParams struct {
+	SourceID  uuid.UUID `json:"source_id"`
+	AfterID   uuid.UUID `json:"after_id"`
+	EdgeTypes []string  `json:"edge_types"`
+	Lim       int32     `json:"lim"`
+}
+
+func (q *Queries) ListOutgoingEdgesPage(ctx context.Context, arg ListOutgoingEdgesPageParams) ([]SymbolEdge, error) {
+	rows, err := q.db.Query(ctx, listOutgoingEdgesPage,
+		arg.SourceID,
+		arg.AfterID,
+		arg.EdgeTypes,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SymbolEdge{}
+	for rows.Next() {
+		var i SymbolEdge
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.SourceID,
+			&i.TargetID,
+			&i.EdgeType,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -222,3 +488,119 @@ func (q *Queries) ListEdgesByProject(ctx context.Context, projectID uuid.UUID) (
 	}
 	return items, nil
 }
+
+const purgeDeletedSymbolEdges = `-- name: PurgeDeletedSymbolEdges :execrows
+DELETE FROM symbol_edges WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedSymbolEdges(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedSymbolEdges, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseColumnEdgeOrigins = `-- name: ReleaseColumnEdgeOrigins :execrows
+UPDATE symbol_edges e
+SET metadata = jsonb_set(e.metadata, '{origins}', (
+    SELECT COALESCE(jsonb_agg(o.origin), '[]'::jsonb)
+    FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+    WHERE NOT (o.origin = ANY($1::text[])
+               OR ($2::bool AND starts_with(o.origin, $3::text)))))
+WHERE e.project_id = $4
+  AND e.edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND EXISTS (
+      SELECT 1 FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+      WHERE o.origin = ANY($1::text[])
+         OR ($2::bool AND starts_with(o.origin, $3::text)))
+`
+
+type ReleaseColumnEdgeOriginsParams struct {
+	Origins      []string  `json:"origins"`
+	AllFiles     bool      `json:"all_files"`
+	SourcePrefix string    `json:"source_prefix"`
+	ProjectID    uuid.UUID `json:"project_id"`
+}
+
+// Drops released origins (see DeleteColumnEdgesByOrigins) from the column-lineage edges
+// other files still derive.
+func (q *Queries) ReleaseColumnEdgeOrigins(ctx context.Context, arg ReleaseColumnEdgeOriginsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, releaseColumnEdgeOrigins,
+		arg.Origins,
+		arg.AllFiles,
+		arg.SourcePrefix,
+		arg.ProjectID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreSymbolEdgesByProject = `-- name: RestoreSymbolEdgesByProject :execrows
+UPDATE symbol_edges SET deleted_at = NULL
+WHERE project_id = $1 AND deleted_at = $2::timestamptz
+`
+
+type RestoreSymbolEdgesByProjectParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreSymbolEdgesByProject(ctx context.Context, arg RestoreSymbolEdgesByProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreSymbolEdgesByProject, arg.ProjectID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteSymbolEdgesByProject = `-- name: SoftDeleteSymbolEdgesByProject :execrows
+UPDATE symbol_edges SET deleted_at = $1::timestamptz
+WHERE project_id = $2 AND deleted_at IS NULL
+`
+
+type SoftDeleteSymbolEdgesByProjectParams struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	ProjectID uuid.UUID `json:"project_id"`
+}
+
+func (q *Queries) SoftDeleteSymbolEdgesByProject(ctx context.Context, arg SoftDeleteSymbolEdgesByProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteSymbolEdgesByProject, arg.DeletedAt, arg.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertColumnEdge = `-- name: UpsertColumnEdge :exec
+INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (project_id, source_id, target_id, edge_type) DO UPDATE
+SET metadata = EXCLUDED.metadata || jsonb_build_object('origins', (
+    SELECT jsonb_agg(DISTINCT o.origin ORDER BY o.origin)
+    FROM jsonb_array_elements_text(
+        COALESCE(symbol_edges.metadata->'origins', '[]'::jsonb) || (EXCLUDED.metadata->'origins')) AS o(origin)))
+`
+
+type UpsertColumnEdgeParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	SourceID  uuid.UUID `json:"source_id"`
+	TargetID  uuid.UUID `json:"target_id"`
+	EdgeType  string    `json:"edge_type"`
+	Metadata  []byte    `json:"metadata"`
+}
+
+// Column-lineage edges list the files they were derived from as "<source_id>:<path>"
+// origins in their metadata; deriving an existing edge again adds its file to the list.
+func (q *Queries) UpsertColumnEdge(ctx context.Context, arg UpsertColumnEdgeParams) error {
+	_, err := q.db.Exec(ctx, upsertColumnEdge,
+		arg.ProjectID,
+		arg.SourceID,
+		arg.TargetID,
+		arg.EdgeType,
+		arg.Metadata,
+	)
+	return err
+}
@@ -10,13 +10,161 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	pgvector_go "github.com/pgvector/pgvector-go"
 )
 
+const copySymbolEmbeddings = `-- name: CopySymbolEmbeddings :exec
+INSERT INTO symbol_embeddings (symbol_id, project_id, embedding, model, input_handling, content_hash)
+SELECT u.symbol_id, se.project_id, se.embedding, se.model, se.input_handling, se.content_hash
+FROM unnest($1::uuid[], $2::uuid[]) AS u(source_id, symbol_id)
+JOIN symbol_embeddings se ON se.id = u.source_id
+ON CONFLICT (symbol_id) DO UPDATE SET embedding = EXCLUDED.embedding, model = EXCLUDED.model,
+    input_handling = EXCLUDED.input_handling, content_hash = EXCLUDED.content_hash, created_at = now()
+`
+
+type CopySymbolEmbeddingsParams struct {
+	SourceIds []uuid.UUID `json:"source_ids"`
+	SymbolIds []uuid.UUID `json:"symbol_ids"`
+}
+
+// Gives each symbol a copy of the vector with the matching source ID.
+func (q *Queries) CopySymbolEmbeddings(ctx context.Context, arg CopySymbolEmbeddingsParams) error {
+	_, err := q.db.Exec(ctx, copySymbolEmbeddings, arg.SourceIds, arg.SymbolIds)
+	return err
+}
+
+const deleteOrphanedEmbeddings = `-- name: DeleteOrphanedEmbeddings :execrows
+DELETE FROM symbol_embeddings WHERE project_id = $1 AND symbol_id IS NULL
+`
+
+// Drops the vectors of a project's deleted symbols.
+func (q *Queries) DeleteOrphanedEmbeddings(ctx context.Context, projectID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrphanedEmbeddings, projectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getEmbeddingColumnDimensions = `-- name: GetEmbeddingColumnDimensions :one
+SELECT atttypmod FROM pg_attribute
+WHERE attrelid = 'symbol_embeddings'::regclass AND attname = 'embedding'
+`
+
+// The width of the vector column, as migrated: pgvector keeps it as the type modifier.
+func (q *Queries) GetEmbeddingColumnDimensions(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, getEmbeddingColumnDimensions)
+	var atttypmod int32
+	err := row.Scan(&atttypmod)
+	return atttypmod, err
+}
+
+const listEmbeddingHashes = `-- name: ListEmbeddingHashes :many
+SELECT id, symbol_id, model, content_hash FROM symbol_embeddings
+WHERE project_id = $1
+`
+
+type ListEmbeddingHashesRow struct {
+	ID          uuid.UUID   `json:"id"`
+	SymbolID    pgtype.UUID `json:"symbol_id"`
+	Model       string      `json:"model"`
+	ContentHash *string     `json:"content_hash"`
+}
+
+// The content hash of each of a project's vectors, including those a deleted symbol
+// left behind (symbol_id NULL).
+func (q *Queries) ListEmbeddingHashes(ctx context.Context, projectID uuid.UUID) ([]ListEmbeddingHashesRow, error) {
+	rows, err := q.db.Query(ctx, listEmbeddingHashes, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEmbeddingHashesRow{}
+	for rows.Next() {
+		var i ListEmbeddingHashesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SymbolID,
+			&i.Model,
+			&i.ContentHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSymbolsForReembed = `-- name: ListSymbolsForReembed :many
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at FROM symbols s
+LEFT JOIN symbol_embeddings se ON s.id = se.symbol_id
+WHERE s.project_id = $1
+  AND s.id > $2::uuid
+  AND s.deleted_at IS NULL
+  AND (se.id IS NULL OR se.model <> $3::text)
+ORDER BY s.id
+LIMIT $4
+`
+
+type ListSymbolsForReembedParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	AfterID   uuid.UUID `json:"after_id"`
+	Model     string    `json:"model"`
+	Lim       int32     `json:"lim"`
+}
+
+// Keyset page of a project's symbols that have no embedding from the given model yet.
+func (q *Queries) ListSymbolsForReembed(ctx context.Context, arg ListSymbolsForReembedParams) ([]Symbol, error) {
+	rows, err := q.db.Query(ctx, listSymbolsForReembed,
+		arg.ProjectID,
+		arg.AfterID,
+		arg.Model,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Symbol{}
+	for rows.Next() {
+		var i Symbol
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.FileID,
+			&i.Name,
+			&i.QualifiedName,
+			&i.Kind,
+			&i.Language,
+			&i.StartLine,
+			&i.EndLine,
+			&i.StartCol,
+			&i.EndCol,
+			&i.Signature,
+			&i.DocComment,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSymbolsWithoutEmbeddings = `-- name: ListSymbolsWithoutEmbeddings :many
-SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at FROM symbols s
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at FROM symbols s
 LEFT JOIN symbol_embeddings se ON s.id = se.symbol_id
-WHERE s.project_id = $1 AND se.id IS NULL
+WHERE s.project_id = $1 AND se.id IS NULL AND s.deleted_at IS NULL
 `
 
 func (q *Queries) ListSymbolsWithoutEmbeddings(ctx context.Context, projectID uuid.UUID) ([]Symbol, error) {
@@ -45,6 +193,7 @@ func (q *Queries) ListSymbolsWithoutEmbeddings(ctx context.Context, projectID uu
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -57,10 +206,11 @@ func (q *Queries) ListSymbolsWithoutEmbeddings(ctx context.Context, projectID uu
 }
 
 const semanticSearch = `-- name: SemanticSearch :many
-SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, (se.embedding <=> $1::vector) AS distance
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at, (se.embedding <=> $1::vector) AS distance
 FROM symbols s
 JOIN symbol_embeddings se ON s.id = se.symbol_id
 WHERE s.project_id = $2
+  AND s.deleted_at IS NULL
   AND (cardinality($3::text[]) = 0 OR s.kind = ANY($3::text[]))
 ORDER BY se.embedding <=> $1::vector
 LIMIT $4
@@ -74,23 +224,24 @@ type SemanticSearchParams struct {
 }
 
 type SemanticSearchRow struct {
-	ID            uuid.UUID   `json:"id"`
-	ProjectID     uuid.UUID   `json:"project_id"`
-	FileID        uuid.UUID   `json:"file_id"`
-	Name          string      `json:"name"`
-	QualifiedName string      `json:"qualified_name"`
-	Kind          string      `json:"kind"`
-	Language      string      `json:"language"`
-	StartLine     int32       `json:"start_line"`
-	EndLine       int32       `json:"end_line"`
-	StartCol      *int32      `json:"start_col"`
-	EndCol        *int32      `json:"end_col"`
-	Signature     *string     `json:"signature"`
-	DocComment    *string     `json:"doc_comment"`
-	Metadata      []byte      `json:"metadata"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
-	Distance      interface{} `json:"distance"`
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	Distance      interface{}        `json:"distance"`
 }
 
 func (q *Queries) SemanticSearch(ctx context.Context, arg SemanticSearchParams) ([]SemanticSearchRow, error) {
@@ -124,6 +275,7 @@ func (q *Queries) SemanticSearch(ctx context.Context, arg SemanticSearchParams)
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 			&i.Distance,
 		); err != nil {
 			return nil, err
@@ -137,18 +289,28 @@ func (q *Queries) SemanticSearch(ctx context.Context, arg SemanticSearchParams)
 }
 
 const upsertSymbolEmbedding = `-- name: UpsertSymbolEmbedding :exec
-INSERT INTO symbol_embeddings (symbol_id, embedding, model)
-VALUES ($1, $2, $3)
-ON CONFLICT (symbol_id) DO UPDATE SET embedding = $2, model = $3, created_at = now()
+INSERT INTO symbol_embeddings (symbol_id, project_id, embedding, model, input_handling, content_hash)
+VALUES ($1::uuid, $2, $3, $4, $5, $6)
+ON CONFLICT (symbol_id) DO UPDATE SET embedding = $3, model = $4, input_handling = $5, content_hash = $6, created_at = now()
 `
 
 type UpsertSymbolEmbeddingParams struct {
-	SymbolID  uuid.UUID          `json:"symbol_id"`
-	Embedding pgvector_go.Vector `json:"embedding"`
-	Model     string             `json:"model"`
+	SymbolID      uuid.UUID          `json:"symbol_id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	Embedding     pgvector_go.Vector `json:"embedding"`
+	Model         string             `json:"model"`
+	InputHandling *string            `json:"input_handling"`
+	ContentHash   *string            `json:"content_hash"`
 }
 
 func (q *Queries) UpsertSymbolEmbedding(ctx context.Context, arg UpsertSymbolEmbeddingParams) error {
-	_, err := q.db.Exec(ctx, upsertSymbolEmbedding, arg.SymbolID, arg.Embedding, arg.Model)
+	_, err := q.db.Exec(ctx, upsertSymbolEmbedding,
+		arg.SymbolID,
+		arg.ProjectID,
+		arg.Embedding,
+		arg.Model,
+		arg.InputHandling,
+		arg.ContentHash,
+	)
 	return err
 }
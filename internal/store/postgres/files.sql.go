@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countFilesByProject = `-- name: CountFilesByProject :one
@@ -23,7 +24,7 @@ func (q *Queries) CountFilesByProject(ctx context.Context, projectID uuid.UUID)
 }
 
 const getFile = `-- name: GetFile :one
-SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at FROM files WHERE id = $1
+SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at FROM files WHERE id = $1
 `
 
 func (q *Queries) GetFile(ctx context.Context, id uuid.UUID) (File, error) {
@@ -40,12 +41,15 @@ func (q *Queries) GetFile(ctx context.Context, id uuid.UUID) (File, error) {
 		&i.LastIndexedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.LastAuthor,
+		&i.LastCommitAt,
 	)
 	return i, err
 }
 
 const getFileByPath = `-- name: GetFileByPath :one
-SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at FROM files WHERE project_id = $1 AND source_id = $2 AND path = $3
+SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at FROM files WHERE project_id = $1 AND source_id = $2 AND path = $3
 `
 
 type GetFileByPathParams struct {
@@ -68,12 +72,58 @@ func (q *Queries) GetFileByPath(ctx context.Context, arg GetFileByPathParams) (F
 		&i.LastIndexedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.LastAuthor,
+		&i.LastCommitAt,
 	)
 	return i, err
 }
 
+const listFilesByPath = `-- name: ListFilesByPath :many
+SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at FROM files WHERE project_id = $1 AND path = $2
+`
+
+type ListFilesByPathParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	Path      string    `json:"path"`
+}
+
+func (q *Queries) ListFilesByPath(ctx context.Context, arg ListFilesByPathParams) ([]File, error) {
+	rows, err := q.db.Query(ctx, listFilesByPath, arg.ProjectID, arg.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []File{}
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.SourceID,
+			&i.Path,
+			&i.Language,
+			&i.SizeBytes,
+			&i.Hash,
+			&i.LastIndexedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Tags,
+			&i.LastAuthor,
+			&i.LastCommitAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFilesByProject = `-- name: ListFilesByProject :many
-SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at FROM files WHERE project_id = $1
+SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at FROM files WHERE project_id = $1
 `
 
 func (q *Queries) ListFilesByProject(ctx context.Context, projectID uuid.UUID) ([]File, error) {
@@ -96,6 +146,9 @@ func (q *Queries) ListFilesByProject(ctx context.Context, projectID uuid.UUID) (
 			&i.LastIndexedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Tags,
+			&i.LastAuthor,
+			&i.LastCommitAt,
 		); err != nil {
 			return nil, err
 		}
@@ -108,7 +161,7 @@ func (q *Queries) ListFilesByProject(ctx context.Context, projectID uuid.UUID) (
 }
 
 const listFilesBySourceID = `-- name: ListFilesBySourceID :many
-SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at FROM files WHERE source_id = $1
+SELECT id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at FROM files WHERE source_id = $1
 `
 
 func (q *Queries) ListFilesBySourceID(ctx context.Context, sourceID uuid.UUID) ([]File, error) {
@@ -131,6 +184,9 @@ func (q *Queries) ListFilesBySourceID(ctx context.Context, sourceID uuid.UUID) (
 			&i.LastIndexedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Tags,
+			&i.LastAuthor,
+			&i.LastCommitAt,
 		); err != nil {
 			return nil, err
 		}
@@ -142,16 +198,83 @@ func (q *Queries) ListFilesBySourceID(ctx context.Context, sourceID uuid.UUID) (
 	return items, nil
 }
 
+const listSymbolOwnership = `-- name: ListSymbolOwnership :many
+SELECT s.id AS symbol_id, f.last_author, f.last_commit_at
+FROM symbols s
+JOIN files f ON f.id = s.file_id
+WHERE s.id = ANY($1::uuid[])
+  AND f.last_author IS NOT NULL
+`
+
+type ListSymbolOwnershipRow struct {
+	SymbolID     uuid.UUID          `json:"symbol_id"`
+	LastAuthor   *string            `json:"last_author"`
+	LastCommitAt pgtype.Timestamptz `json:"last_commit_at"`
+}
+
+func (q *Queries) ListSymbolOwnership(ctx context.Context, symbolIds []uuid.UUID) ([]ListSymbolOwnershipRow, error) {
+	rows, err := q.db.Query(ctx, listSymbolOwnership, symbolIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSymbolOwnershipRow{}
+	for rows.Next() {
+		var i ListSymbolOwnershipRow
+		if err := rows.Scan(&i.SymbolID, &i.LastAuthor, &i.LastCommitAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFileOwnership = `-- name: UpdateFileOwnership :execrows
+UPDATE files f
+SET last_author = o.author,
+    last_commit_at = o.committed_at
+FROM unnest($1::text[], $2::text[], $3::timestamptz[]) AS o(path, author, committed_at)
+WHERE f.project_id = $4
+  AND f.source_id = $5
+  AND f.path = o.path
+`
+
+type UpdateFileOwnershipParams struct {
+	Paths       []string             `json:"paths"`
+	Authors     []string             `json:"authors"`
+	CommittedAt []pgtype.Timestamptz `json:"committed_at"`
+	ProjectID   uuid.UUID            `json:"project_id"`
+	SourceID    uuid.UUID            `json:"source_id"`
+}
+
+func (q *Queries) UpdateFileOwnership(ctx context.Context, arg UpdateFileOwnershipParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateFileOwnership,
+		arg.Paths,
+		arg.Authors,
+		arg.CommittedAt,
+		arg.ProjectID,
+		arg.SourceID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertFile = `-- name: UpsertFile :one
-INSERT INTO files (project_id, source_id, path, language, size_bytes, hash, last_indexed_at)
-VALUES ($1, $2, $3, $4, $5, $6, now())
+INSERT INTO files (project_id, source_id, path, language, size_bytes, hash, tags, last_indexed_at)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::text[], '{}'), now())
 ON CONFLICT (project_id, source_id, path) DO UPDATE
 SET language = EXCLUDED.language,
     size_bytes = EXCLUDED.size_bytes,
     hash = EXCLUDED.hash,
+    tags = EXCLUDED.tags,
     last_indexed_at = now(),
     updated_at = now()
-RETURNING id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at
+RETURNING id, project_id, source_id, path, language, size_bytes, hash, last_indexed_at, created_at, updated_at, tags, last_author, last_commit_at
 `
 
 type UpsertFileParams struct {
@@ -161,6 +284,7 @@ type UpsertFileParams struct {
 	Language  string    `json:"language"`
 	SizeBytes int64     `json:"size_bytes"`
 	Hash      string    `json:"hash"`
+	Tags      []string  `json:"tags"`
 }
 
 func (q *Queries) UpsertFile(ctx context.Context, arg UpsertFileParams) (File, error) {
@@ -171,6 +295,7 @@ func (q *Queries) UpsertFile(ctx context.Context, arg UpsertFileParams) (File, e
 		arg.Language,
 		arg.SizeBytes,
 		arg.Hash,
+		arg.Tags,
 	)
 	var i File
 	err := row.Scan(
@@ -184,6 +309,9 @@ func (q *Queries) UpsertFile(ctx context.Context, arg UpsertFileParams) (File, e
 		&i.LastIndexedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.LastAuthor,
+		&i.LastCommitAt,
 	)
 	return i, err
 }
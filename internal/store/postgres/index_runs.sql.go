@@ -70,7 +70,7 @@ func (q *Queries) GetIndexRun(ctx context.Context, id uuid.UUID) (IndexRun, erro
 const listIndexRunsByProject = `-- name: ListIndexRunsByProject :many
 SELECT ir.id, ir.project_id, ir.source_id, ir.status, ir.started_at, ir.completed_at, ir.files_processed, ir.symbols_found, ir.edges_found, ir.error_message, ir.metadata, ir.created_at FROM index_runs ir
 JOIN projects p ON ir.project_id = p.id
-WHERE p.slug = $1
+WHERE p.slug = $1 AND p.deleted_at IS NULL
 ORDER BY ir.created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -156,6 +156,20 @@ func (q *Queries) ListIndexRunsByProjectID(ctx context.Context, arg ListIndexRun
 	return items, nil
 }
 
+const mergeIndexRunMetadata = `-- name: MergeIndexRunMetadata :exec
+UPDATE index_runs SET metadata = metadata || $1::jsonb WHERE id = $2
+`
+
+type MergeIndexRunMetadataParams struct {
+	Metadata []byte    `json:"metadata"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) MergeIndexRunMetadata(ctx context.Context, arg MergeIndexRunMetadataParams) error {
+	_, err := q.db.Exec(ctx, mergeIndexRunMetadata, arg.Metadata, arg.ID)
+	return err
+}
+
 const updateIndexRunStats = `-- name: UpdateIndexRunStats :exec
 UPDATE index_runs
 SET files_processed = $2, symbols_found = $3, edges_found = $4
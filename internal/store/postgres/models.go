@@ -35,6 +35,9 @@ type File struct {
 	LastIndexedAt pgtype.Timestamptz `json:"last_indexed_at"`
 	CreatedAt     time.Time          `json:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at"`
+	Tags          []string           `json:"tags"`
+	LastAuthor    *string            `json:"last_author"`
+	LastCommitAt  pgtype.Timestamptz `json:"last_commit_at"`
 }
 
 type IndexRun struct {
@@ -60,15 +63,18 @@ type Membership struct {
 }
 
 type Project struct {
-	ID          uuid.UUID   `json:"id"`
-	Name        string      `json:"name"`
-	Slug        string      `json:"slug"`
-	Description *string     `json:"description"`
-	Settings    []byte      `json:"settings"`
-	CreatedBy   pgtype.UUID `json:"created_by"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-	TenantID    uuid.UUID   `json:"tenant_id"`
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description *string            `json:"description"`
+	Settings    []byte             `json:"settings"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	TenantID    uuid.UUID          `json:"tenant_id"`
+	Readiness   string             `json:"readiness"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
 }
 
 type ProjectAnalytic struct {
@@ -88,6 +94,35 @@ type ProjectMember struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type ProjectMetadatum struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	SourceID  uuid.UUID `json:"source_id"`
+	Key       string    `json:"key"`
+	Value     []byte    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ProjectScheduleRun struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	LastRunAt time.Time `json:"last_run_at"`
+}
+
+type ProjectTemplate struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Settings    []byte    `json:"settings"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SchemaSnapshot struct {
+	IndexRunID uuid.UUID `json:"index_run_id"`
+	ProjectID  uuid.UUID `json:"project_id"`
+	Snapshot   []byte    `json:"snapshot"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Source struct {
 	ID            uuid.UUID          `json:"id"`
 	ProjectID     uuid.UUID          `json:"project_id"`
@@ -102,40 +137,53 @@ type Source struct {
 }
 
 type Symbol struct {
-	ID            uuid.UUID `json:"id"`
-	ProjectID     uuid.UUID `json:"project_id"`
-	FileID        uuid.UUID `json:"file_id"`
-	Name          string    `json:"name"`
-	QualifiedName string    `json:"qualified_name"`
-	Kind          string    `json:"kind"`
-	Language      string    `json:"language"`
-	StartLine     int32     `json:"start_line"`
-	EndLine       int32     `json:"end_line"`
-	StartCol      *int32    `json:"start_col"`
-	EndCol        *int32    `json:"end_col"`
-	Signature     *string   `json:"signature"`
-	DocComment    *string   `json:"doc_comment"`
-	Metadata      []byte    `json:"metadata"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type SymbolEdge struct {
-	ID        uuid.UUID `json:"id"`
-	ProjectID uuid.UUID `json:"project_id"`
-	SourceID  uuid.UUID `json:"source_id"`
-	TargetID  uuid.UUID `json:"target_id"`
-	EdgeType  string    `json:"edge_type"`
-	Metadata  []byte    `json:"metadata"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID          `json:"id"`
+	ProjectID uuid.UUID          `json:"project_id"`
+	SourceID  uuid.UUID          `json:"source_id"`
+	TargetID  uuid.UUID          `json:"target_id"`
+	EdgeType  string             `json:"edge_type"`
+	Metadata  []byte             `json:"metadata"`
+	CreatedAt time.Time          `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type SymbolEmbedding struct {
-	ID        uuid.UUID          `json:"id"`
-	SymbolID  uuid.UUID          `json:"symbol_id"`
-	Embedding pgvector_go.Vector `json:"embedding"`
-	Model     string             `json:"model"`
-	CreatedAt time.Time          `json:"created_at"`
+	ID            uuid.UUID          `json:"id"`
+	SymbolID      pgtype.UUID        `json:"symbol_id"`
+	Embedding     pgvector_go.Vector `json:"embedding"`
+	Model         string             `json:"model"`
+	CreatedAt     time.Time          `json:"created_at"`
+	InputHandling *string            `json:"input_handling"`
+	ContentHash   *string            `json:"content_hash"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+}
+
+type SymbolUsage struct {
+	ProjectID     uuid.UUID   `json:"project_id"`
+	QualifiedName string      `json:"qualified_name"`
+	Kind          string      `json:"kind"`
+	Day           pgtype.Date `json:"day"`
+	Hits          int64       `json:"hits"`
 }
 
 type Tenant struct {
@@ -147,6 +195,18 @@ type Tenant struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type UnresolvedReference struct {
+	ID            uuid.UUID `json:"id"`
+	ProjectID     uuid.UUID `json:"project_id"`
+	FileID        uuid.UUID `json:"file_id"`
+	SourceID      uuid.UUID `json:"source_id"`
+	ToName        string    `json:"to_name"`
+	ToQualified   string    `json:"to_qualified"`
+	ReferenceType string    `json:"reference_type"`
+	Line          int32     `json:"line"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
 type User struct {
 	ID        uuid.UUID `json:"id"`
 	Email     string    `json:"email"`
@@ -7,13 +7,40 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveProject = `-- name: ArchiveProject :one
+UPDATE projects SET archived_at = now(), updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
+`
+
+func (q *Queries) ArchiveProject(ctx context.Context, id uuid.UUID) (Project, error) {
+	row := q.db.QueryRow(ctx, archiveProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Settings,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const countProjects = `-- name: CountProjects :one
-SELECT count(*) FROM projects
+SELECT count(*) FROM projects WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountProjects(ctx context.Context) (int64, error) {
@@ -24,7 +51,7 @@ func (q *Queries) CountProjects(ctx context.Context) (int64, error) {
 }
 
 const countProjectsByTenant = `-- name: CountProjectsByTenant :one
-SELECT count(*) FROM projects WHERE tenant_id = $1
+SELECT count(*) FROM projects WHERE tenant_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountProjectsByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
@@ -37,7 +64,7 @@ func (q *Queries) CountProjectsByTenant(ctx context.Context, tenantID uuid.UUID)
 const createProject = `-- name: CreateProject :one
 INSERT INTO projects (name, slug, description, created_by, tenant_id)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
 `
 
 type CreateProjectParams struct {
@@ -67,6 +94,9 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
@@ -80,8 +110,33 @@ func (q *Queries) DeleteProject(ctx context.Context, slug string) error {
 	return err
 }
 
+const getDeletedProject = `-- name: GetDeletedProject :one
+SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at FROM projects WHERE slug = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC LIMIT 1
+`
+
+func (q *Queries) GetDeletedProject(ctx context.Context, slug string) (Project, error) {
+	row := q.db.QueryRow(ctx, getDeletedProject, slug)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Settings,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const getProject = `-- name: GetProject :one
-SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id FROM projects WHERE slug = $1 LIMIT 1
+SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at FROM projects WHERE slug = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetProject(ctx context.Context, slug string) (Project, error) {
@@ -97,12 +152,15 @@ func (q *Queries) GetProject(ctx context.Context, slug string) (Project, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
-SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id FROM projects WHERE id = $1 LIMIT 1
+SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at FROM projects WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetProjectByID(ctx context.Context, id uuid.UUID) (Project, error) {
@@ -118,12 +176,15 @@ func (q *Queries) GetProjectByID(ctx context.Context, id uuid.UUID) (Project, er
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
 const listProjects = `-- name: ListProjects :many
-SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id FROM projects ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at FROM projects WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
 
 type ListProjectsParams struct {
@@ -150,6 +211,9 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TenantID,
+			&i.Readiness,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -162,8 +226,8 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 }
 
 const listProjectsByTenant = `-- name: ListProjectsByTenant :many
-SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id FROM projects
-WHERE tenant_id = $1
+SELECT id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at FROM projects
+WHERE tenant_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -193,6 +257,9 @@ func (q *Queries) ListProjectsByTenant(ctx context.Context, arg ListProjectsByTe
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TenantID,
+			&i.Readiness,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -204,11 +271,128 @@ func (q *Queries) ListProjectsByTenant(ctx context.Context, arg ListProjectsByTe
 	return items, nil
 }
 
+const purgeDeletedProjects = `-- name: PurgeDeletedProjects :many
+DELETE FROM projects WHERE deleted_at < $1::timestamptz
+RETURNING id
+`
+
+func (q *Queries) PurgeDeletedProjects(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedProjects, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreProject = `-- name: RestoreProject :one
+UPDATE projects SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
+`
+
+func (q *Queries) RestoreProject(ctx context.Context, id uuid.UUID) (Project, error) {
+	row := q.db.QueryRow(ctx, restoreProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Settings,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const setProjectReadiness = `-- name: SetProjectReadiness :exec
+UPDATE projects SET readiness = $2 WHERE id = $1
+`
+
+type SetProjectReadinessParams struct {
+	ID        uuid.UUID `json:"id"`
+	Readiness string    `json:"readiness"`
+}
+
+func (q *Queries) SetProjectReadiness(ctx context.Context, arg SetProjectReadinessParams) error {
+	_, err := q.db.Exec(ctx, setProjectReadiness, arg.ID, arg.Readiness)
+	return err
+}
+
+const softDeleteProject = `-- name: SoftDeleteProject :one
+UPDATE projects SET deleted_at = now()
+WHERE slug = $1 AND deleted_at IS NULL
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
+`
+
+func (q *Queries) SoftDeleteProject(ctx context.Context, slug string) (Project, error) {
+	row := q.db.QueryRow(ctx, softDeleteProject, slug)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Settings,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const unarchiveProject = `-- name: UnarchiveProject :one
+UPDATE projects SET archived_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
+`
+
+func (q *Queries) UnarchiveProject(ctx context.Context, id uuid.UUID) (Project, error) {
+	row := q.db.QueryRow(ctx, unarchiveProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.Settings,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects
 SET name = $2, description = $3, settings = $4, updated_at = now()
-WHERE slug = $1
-RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id
+WHERE slug = $1 AND deleted_at IS NULL
+RETURNING id, name, slug, description, settings, created_by, created_at, updated_at, tenant_id, readiness, deleted_at, archived_at
 `
 
 type UpdateProjectParams struct {
@@ -236,6 +420,9 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TenantID,
+		&i.Readiness,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
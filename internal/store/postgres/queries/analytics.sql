@@ -24,6 +24,12 @@ ORDER BY scope, scope_id;
 -- name: DeleteProjectAnalytics :exec
 DELETE FROM project_analytics WHERE project_id = $1;
 
+-- Rows of one scope whose scope_id is not among the ones just written
+-- name: DeleteStaleProjectAnalytics :exec
+DELETE FROM project_analytics
+WHERE project_id = @project_id AND scope = @scope
+  AND NOT (scope_id = ANY(@keep_scope_ids::text[]));
+
 -- Degree computation: count in-degree and out-degree per symbol
 -- name: GetSymbolDegrees :many
 SELECT
@@ -57,10 +63,43 @@ SET metadata = metadata || @analytics_json::jsonb,
     updated_at = now()
 WHERE id = ANY(@symbol_ids::uuid[]);
 
+-- Batch update symbol metadata, merging the analytics_json at each index into the symbol
+-- at the same index of symbol_ids
+-- name: BatchUpdateSymbolMetadataEach :exec
+UPDATE symbols s
+SET metadata = s.metadata || u.analytics_json::jsonb,
+    updated_at = now()
+FROM unnest(@symbol_ids::uuid[], @analytics_json::text[]) AS u(symbol_id, analytics_json)
+WHERE s.id = u.symbol_id;
+
 -- Get edge list for PageRank computation
 -- name: GetEdgeList :many
 SELECT source_id, target_id FROM symbol_edges WHERE project_id = $1;
 
+-- Edge list with resolution confidence; edges without a recorded confidence are exact (1)
+-- name: GetEdgeConfidences :many
+SELECT source_id, target_id,
+    COALESCE((metadata->>'confidence')::float, 1)::float AS confidence
+FROM symbol_edges WHERE project_id = $1;
+
+-- Columns compared in WHERE clauses and join conditions, with the number of queries
+-- (procedures, views, functions) filtering or joining on each
+-- name: GetColumnPredicateUsage :many
+SELECT c.id, c.qualified_name,
+    count(*) FILTER (WHERE e.metadata->>'derivation_type' = 'filter') AS filters,
+    count(*) FILTER (WHERE e.metadata->>'derivation_type' = 'join') AS joins
+FROM symbol_edges e
+JOIN symbols c ON c.id = e.source_id
+WHERE e.project_id = $1 AND e.deleted_at IS NULL
+  AND e.edge_type = 'uses_column' AND c.kind = 'column'
+  AND e.metadata->>'derivation_type' IN ('filter', 'join')
+GROUP BY c.id, c.qualified_name;
+
+-- Columns leading an index or key constraint
+-- name: GetIndexedColumnIDs :many
+SELECT DISTINCT target_id FROM symbol_edges
+WHERE project_id = $1 AND deleted_at IS NULL AND edge_type = 'indexes';
+
 -- Cross-language bridge query: edges where source and target have different languages
 -- name: GetCrossLanguageBridges :many
 SELECT
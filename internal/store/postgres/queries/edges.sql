@@ -5,16 +5,16 @@ ON CONFLICT (project_id, source_id, target_id, edge_type) DO NOTHING
 RETURNING *;
 
 -- name: CountEdgesByProject :one
-SELECT count(*) FROM symbol_edges WHERE project_id = $1;
+SELECT count(*) FROM symbol_edges WHERE project_id = $1 AND deleted_at IS NULL;
 
 -- name: GetIncomingEdges :many
-SELECT * FROM symbol_edges WHERE target_id = $1;
+SELECT * FROM symbol_edges WHERE target_id = $1 AND deleted_at IS NULL;
 
 -- name: GetOutgoingEdges :many
-SELECT * FROM symbol_edges WHERE source_id = $1;
+SELECT * FROM symbol_edges WHERE source_id = $1 AND deleted_at IS NULL;
 
 -- name: ListEdgesByProject :many
-SELECT * FROM symbol_edges WHERE project_id = $1;
+SELECT * FROM symbol_edges WHERE project_id = $1 AND deleted_at IS NULL;
 
 -- name: CreateSymbolEdgeWithMetadata :one
 INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
@@ -25,5 +25,109 @@ RETURNING *;
 
 -- name: ListColumnEdgesByProject :many
 SELECT * FROM symbol_edges
-WHERE project_id = $1
+WHERE project_id = $1 AND deleted_at IS NULL
   AND edge_type IN ('transforms_to', 'direct_copy', 'uses_column');
+
+-- Column-lineage edges list the files they were derived from as "<source_id>:<path>"
+-- origins in their metadata; deriving an existing edge again adds its file to the list.
+-- name: UpsertColumnEdge :exec
+INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (project_id, source_id, target_id, edge_type) DO UPDATE
+SET metadata = EXCLUDED.metadata || jsonb_build_object('origins', (
+    SELECT jsonb_agg(DISTINCT o.origin ORDER BY o.origin)
+    FROM jsonb_array_elements_text(
+        COALESCE(symbol_edges.metadata->'origins', '[]'::jsonb) || (EXCLUDED.metadata->'origins')) AS o(origin)));
+
+-- Column-lineage edges derived only from released origins: the given ones, or with
+-- all_files every origin starting with source_prefix, along with the edges written before
+-- edges listed their origins. Edges that other files also derive are kept.
+-- name: DeleteColumnEdgesByOrigins :many
+DELETE FROM symbol_edges e
+WHERE e.project_id = @project_id
+  AND e.edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND CASE WHEN e.metadata ? 'origins' THEN NOT EXISTS (
+          SELECT 1 FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+          WHERE NOT (o.origin = ANY(@origins::text[])
+                     OR (@all_files::bool AND starts_with(o.origin, @source_prefix::text))))
+      ELSE @all_files::bool AND e.metadata ? 'derivation_type' END
+RETURNING e.id;
+
+-- Drops released origins (see DeleteColumnEdgesByOrigins) from the column-lineage edges
+-- other files still derive.
+-- name: ReleaseColumnEdgeOrigins :execrows
+UPDATE symbol_edges e
+SET metadata = jsonb_set(e.metadata, '{origins}', (
+    SELECT COALESCE(jsonb_agg(o.origin), '[]'::jsonb)
+    FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+    WHERE NOT (o.origin = ANY(@origins::text[])
+               OR (@all_files::bool AND starts_with(o.origin, @source_prefix::text)))))
+WHERE e.project_id = @project_id
+  AND e.edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND EXISTS (
+      SELECT 1 FROM jsonb_array_elements_text(e.metadata->'origins') AS o(origin)
+      WHERE o.origin = ANY(@origins::text[])
+         OR (@all_files::bool AND starts_with(o.origin, @source_prefix::text)));
+
+-- Parse persistence and resolution derive an edge's confidence, match strategy, conditional
+-- flag and superseded mapping; the batch insert leaves them on existing edges, so they are
+-- stripped from the edges leaving a file's symbols before it is re-indexed.
+-- name: ClearDerivedEdgeMetadataByFile :execrows
+UPDATE symbol_edges e
+SET metadata = e.metadata - '{confidence,match_strategy,bridge,conditional,mapping,superseded_by}'::text[]
+FROM symbols s
+WHERE s.id = e.source_id AND s.file_id = $1
+  AND e.edge_type NOT IN ('transforms_to', 'direct_copy', 'uses_column', 'related_to')
+  AND e.metadata ?| '{confidence,match_strategy,bridge,conditional,mapping,superseded_by}'::text[];
+
+-- name: DeleteEdgesByType :execrows
+DELETE FROM symbol_edges WHERE project_id = $1 AND edge_type = $2;
+
+-- name: ListEdgeEndpointsByType :many
+SELECT s.qualified_name AS source_name, t.qualified_name AS target_name
+FROM symbol_edges e
+JOIN symbols s ON s.id = e.source_id
+JOIN symbols t ON t.id = e.target_id
+WHERE e.project_id = $1 AND e.edge_type = $2 AND e.deleted_at IS NULL
+ORDER BY s.qualified_name, t.qualified_name;
+
+-- Multi-row edge insert keyed on (project_id, source_id, target_id, edge_type).
+-- Existing edges keep their metadata unless the incoming row carries different metadata.
+-- name: BatchCreateSymbolEdges :execrows
+INSERT INTO symbol_edges (project_id, source_id, target_id, edge_type, metadata)
+SELECT unnest(@project_ids::uuid[]), unnest(@source_ids::uuid[]), unnest(@target_ids::uuid[]),
+       unnest(@edge_types::text[]), unnest(@metadata::text[])::jsonb
+ON CONFLICT (project_id, source_id, target_id, edge_type) DO UPDATE
+SET metadata = EXCLUDED.metadata
+WHERE EXCLUDED.metadata <> '{}'::jsonb
+  AND symbol_edges.metadata IS DISTINCT FROM EXCLUDED.metadata;
+
+-- Keyset pages of a symbol's edges ordered by edge id; pass the zero UUID for the first page.
+-- name: ListIncomingEdgesPage :many
+SELECT * FROM symbol_edges
+WHERE target_id = @target_id
+  AND id > @after_id::uuid
+  AND deleted_at IS NULL
+  AND (cardinality(@edge_types::text[]) = 0 OR edge_type = ANY(@edge_types::text[]))
+ORDER BY id
+LIMIT @lim;
+
+-- name: ListOutgoingEdgesPage :many
+SELECT * FROM symbol_edges
+WHERE source_id = @source_id
+  AND id > @after_id::uuid
+  AND deleted_at IS NULL
+  AND (cardinality(@edge_types::text[]) = 0 OR edge_type = ANY(@edge_types::text[]))
+ORDER BY id
+LIMIT @lim;
+
+-- name: SoftDeleteSymbolEdgesByProject :execrows
+UPDATE symbol_edges SET deleted_at = @deleted_at::timestamptz
+WHERE project_id = @project_id AND deleted_at IS NULL;
+
+-- name: RestoreSymbolEdgesByProject :execrows
+UPDATE symbol_edges SET deleted_at = NULL
+WHERE project_id = @project_id AND deleted_at = @deleted_at::timestamptz;
+
+-- name: PurgeDeletedSymbolEdges :execrows
+DELETE FROM symbol_edges WHERE deleted_at < @before::timestamptz;
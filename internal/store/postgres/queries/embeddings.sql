@@ -1,18 +1,54 @@
 -- name: UpsertSymbolEmbedding :exec
-INSERT INTO symbol_embeddings (symbol_id, embedding, model)
-VALUES ($1, $2, $3)
-ON CONFLICT (symbol_id) DO UPDATE SET embedding = $2, model = $3, created_at = now();
+INSERT INTO symbol_embeddings (symbol_id, project_id, embedding, model, input_handling, content_hash)
+VALUES (@symbol_id::uuid, @project_id, @embedding, @model, @input_handling, @content_hash)
+ON CONFLICT (symbol_id) DO UPDATE SET embedding = @embedding, model = @model, input_handling = @input_handling, content_hash = @content_hash, created_at = now();
+
+-- name: ListEmbeddingHashes :many
+-- The content hash of each of a project's vectors, including those a deleted symbol
+-- left behind (symbol_id NULL).
+SELECT id, symbol_id, model, content_hash FROM symbol_embeddings
+WHERE project_id = $1;
+
+-- name: CopySymbolEmbeddings :exec
+-- Gives each symbol a copy of the vector with the matching source ID.
+INSERT INTO symbol_embeddings (symbol_id, project_id, embedding, model, input_handling, content_hash)
+SELECT u.symbol_id, se.project_id, se.embedding, se.model, se.input_handling, se.content_hash
+FROM unnest(@source_ids::uuid[], @symbol_ids::uuid[]) AS u(source_id, symbol_id)
+JOIN symbol_embeddings se ON se.id = u.source_id
+ON CONFLICT (symbol_id) DO UPDATE SET embedding = EXCLUDED.embedding, model = EXCLUDED.model,
+    input_handling = EXCLUDED.input_handling, content_hash = EXCLUDED.content_hash, created_at = now();
+
+-- name: DeleteOrphanedEmbeddings :execrows
+-- Drops the vectors of a project's deleted symbols.
+DELETE FROM symbol_embeddings WHERE project_id = $1 AND symbol_id IS NULL;
+
+-- name: GetEmbeddingColumnDimensions :one
+-- The width of the vector column, as migrated: pgvector keeps it as the type modifier.
+SELECT atttypmod FROM pg_attribute
+WHERE attrelid = 'symbol_embeddings'::regclass AND attname = 'embedding';
 
 -- name: ListSymbolsWithoutEmbeddings :many
 SELECT s.* FROM symbols s
 LEFT JOIN symbol_embeddings se ON s.id = se.symbol_id
-WHERE s.project_id = $1 AND se.id IS NULL;
+WHERE s.project_id = $1 AND se.id IS NULL AND s.deleted_at IS NULL;
+
+-- name: ListSymbolsForReembed :many
+-- Keyset page of a project's symbols that have no embedding from the given model yet.
+SELECT s.* FROM symbols s
+LEFT JOIN symbol_embeddings se ON s.id = se.symbol_id
+WHERE s.project_id = @project_id
+  AND s.id > @after_id::uuid
+  AND s.deleted_at IS NULL
+  AND (se.id IS NULL OR se.model <> @model::text)
+ORDER BY s.id
+LIMIT @lim;
 
 -- name: SemanticSearch :many
 SELECT s.*, (se.embedding <=> @query_embedding::vector) AS distance
 FROM symbols s
 JOIN symbol_embeddings se ON s.id = se.symbol_id
 WHERE s.project_id = @project_id
+  AND s.deleted_at IS NULL
   AND (cardinality(@kinds::text[]) = 0 OR s.kind = ANY(@kinds::text[]))
 ORDER BY se.embedding <=> @query_embedding::vector
 LIMIT @lim;
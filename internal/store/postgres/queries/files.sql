@@ -1,10 +1,11 @@
 -- name: UpsertFile :one
-INSERT INTO files (project_id, source_id, path, language, size_bytes, hash, last_indexed_at)
-VALUES ($1, $2, $3, $4, $5, $6, now())
+INSERT INTO files (project_id, source_id, path, language, size_bytes, hash, tags, last_indexed_at)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE(@tags::text[], '{}'), now())
 ON CONFLICT (project_id, source_id, path) DO UPDATE
 SET language = EXCLUDED.language,
     size_bytes = EXCLUDED.size_bytes,
     hash = EXCLUDED.hash,
+    tags = EXCLUDED.tags,
     last_indexed_at = now(),
     updated_at = now()
 RETURNING *;
@@ -18,8 +19,27 @@ SELECT * FROM files WHERE id = $1;
 -- name: ListFilesByProject :many
 SELECT * FROM files WHERE project_id = $1;
 
+-- name: ListFilesByPath :many
+SELECT * FROM files WHERE project_id = $1 AND path = $2;
+
 -- name: ListFilesBySourceID :many
 SELECT * FROM files WHERE source_id = $1;
 
 -- name: GetFileByPath :one
 SELECT * FROM files WHERE project_id = $1 AND source_id = $2 AND path = $3;
+
+-- name: UpdateFileOwnership :execrows
+UPDATE files f
+SET last_author = o.author,
+    last_commit_at = o.committed_at
+FROM unnest(@paths::text[], @authors::text[], @committed_at::timestamptz[]) AS o(path, author, committed_at)
+WHERE f.project_id = @project_id
+  AND f.source_id = @source_id
+  AND f.path = o.path;
+
+-- name: ListSymbolOwnership :many
+SELECT s.id AS symbol_id, f.last_author, f.last_commit_at
+FROM symbols s
+JOIN files f ON f.id = s.file_id
+WHERE s.id = ANY(@symbol_ids::uuid[])
+  AND f.last_author IS NOT NULL;
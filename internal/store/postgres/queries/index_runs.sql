@@ -4,7 +4,7 @@ SELECT * FROM index_runs WHERE id = $1 LIMIT 1;
 -- name: ListIndexRunsByProject :many
 SELECT ir.* FROM index_runs ir
 JOIN projects p ON ir.project_id = p.id
-WHERE p.slug = $1
+WHERE p.slug = $1 AND p.deleted_at IS NULL
 ORDER BY ir.created_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -21,6 +21,9 @@ SET status = $2,
     error_message = $3
 WHERE id = $1;
 
+-- name: MergeIndexRunMetadata :exec
+UPDATE index_runs SET metadata = metadata || @metadata::jsonb WHERE id = @id;
+
 -- name: UpdateIndexRunStats :exec
 UPDATE index_runs
 SET files_processed = $2, symbols_found = $3, edges_found = $4
@@ -0,0 +1,13 @@
+-- name: UpsertProjectMetadata :exec
+INSERT INTO project_metadata (project_id, source_id, key, value)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (project_id, source_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = now();
+
+-- name: DeleteProjectMetadata :exec
+DELETE FROM project_metadata WHERE project_id = $1 AND source_id = $2 AND key = $3;
+
+-- name: ListProjectMetadataValues :many
+-- The values each source of a project recorded under key.
+SELECT source_id, value FROM project_metadata
+WHERE project_id = $1 AND key = $2
+ORDER BY source_id;
@@ -0,0 +1,19 @@
+-- name: CreateProjectTemplate :one
+INSERT INTO project_templates (name, description, settings)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetProjectTemplate :one
+SELECT * FROM project_templates WHERE name = $1 LIMIT 1;
+
+-- name: ListProjectTemplates :many
+SELECT * FROM project_templates ORDER BY name;
+
+-- name: UpdateProjectTemplate :one
+UPDATE project_templates
+SET description = $2, settings = $3, updated_at = now()
+WHERE name = $1
+RETURNING *;
+
+-- name: DeleteProjectTemplate :execrows
+DELETE FROM project_templates WHERE name = $1;
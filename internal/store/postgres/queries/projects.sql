@@ -1,11 +1,11 @@
 -- name: GetProject :one
-SELECT * FROM projects WHERE slug = $1 LIMIT 1;
+SELECT * FROM projects WHERE slug = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetProjectByID :one
-SELECT * FROM projects WHERE id = $1 LIMIT 1;
+SELECT * FROM projects WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListProjects :many
-SELECT * FROM projects ORDER BY created_at DESC LIMIT $1 OFFSET $2;
+SELECT * FROM projects WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
 -- name: CreateProject :one
 INSERT INTO projects (name, slug, description, created_by, tenant_id)
@@ -15,20 +15,51 @@ RETURNING *;
 -- name: UpdateProject :one
 UPDATE projects
 SET name = $2, description = $3, settings = $4, updated_at = now()
-WHERE slug = $1
+WHERE slug = $1 AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteProject :exec
 DELETE FROM projects WHERE slug = $1;
 
 -- name: CountProjects :one
-SELECT count(*) FROM projects;
+SELECT count(*) FROM projects WHERE deleted_at IS NULL;
 
 -- name: ListProjectsByTenant :many
 SELECT * FROM projects
-WHERE tenant_id = $1
+WHERE tenant_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: CountProjectsByTenant :one
-SELECT count(*) FROM projects WHERE tenant_id = $1;
+SELECT count(*) FROM projects WHERE tenant_id = $1 AND deleted_at IS NULL;
+
+-- name: SetProjectReadiness :exec
+UPDATE projects SET readiness = $2 WHERE id = $1;
+
+-- name: SoftDeleteProject :one
+UPDATE projects SET deleted_at = now()
+WHERE slug = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedProject :one
+SELECT * FROM projects WHERE slug = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC LIMIT 1;
+
+-- name: RestoreProject :one
+UPDATE projects SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeDeletedProjects :many
+DELETE FROM projects WHERE deleted_at < @before::timestamptz
+RETURNING id;
+
+-- name: ArchiveProject :one
+UPDATE projects SET archived_at = now(), updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+RETURNING *;
+
+-- name: UnarchiveProject :one
+UPDATE projects SET archived_at = NULL, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL
+RETURNING *;
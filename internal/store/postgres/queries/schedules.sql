@@ -0,0 +1,24 @@
+-- name: ListScheduledProjects :many
+-- Live, unarchived projects with an index schedule in their settings, and when it last ran (NULL if never).
+SELECT p.id, (p.settings->>'index_schedule')::text AS schedule, r.last_run_at
+FROM projects p
+LEFT JOIN project_schedule_runs r ON r.project_id = p.id
+WHERE p.deleted_at IS NULL
+  AND p.archived_at IS NULL
+  AND coalesce(p.settings->>'index_schedule', '') <> ''
+ORDER BY p.id;
+
+-- name: UpsertProjectScheduleRun :exec
+INSERT INTO project_schedule_runs (project_id, last_run_at)
+VALUES ($1, $2)
+ON CONFLICT (project_id) DO UPDATE SET last_run_at = EXCLUDED.last_run_at;
+
+-- name: ListRepositorySources :many
+-- Git sources of live, unarchived projects, for matching webhook payloads to sources by repository URL.
+SELECT s.id, s.project_id, s.name, s.source_type, s.connection_uri, s.config, s.last_synced_at, s.created_at, s.updated_at, s.last_commit_sha
+FROM sources s
+JOIN projects p ON p.id = s.project_id
+WHERE p.deleted_at IS NULL
+  AND p.archived_at IS NULL
+  AND s.source_type = 'git'
+  AND s.connection_uri IS NOT NULL;
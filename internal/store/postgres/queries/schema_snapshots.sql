@@ -0,0 +1,11 @@
+-- name: UpsertSchemaSnapshot :exec
+INSERT INTO schema_snapshots (index_run_id, project_id, snapshot)
+VALUES ($1, $2, $3)
+ON CONFLICT (index_run_id) DO UPDATE
+SET snapshot = EXCLUDED.snapshot, created_at = now();
+
+-- name: GetSchemaSnapshot :one
+SELECT * FROM schema_snapshots WHERE index_run_id = $1;
+
+-- name: ListSchemaSnapshotsByProject :many
+SELECT * FROM schema_snapshots WHERE project_id = $1 ORDER BY created_at DESC LIMIT $2;
@@ -4,7 +4,7 @@ SELECT * FROM sources WHERE id = $1 LIMIT 1;
 -- name: ListSourcesByProject :many
 SELECT s.* FROM sources s
 JOIN projects p ON s.project_id = p.id
-WHERE p.slug = $1
+WHERE p.slug = $1 AND p.deleted_at IS NULL
 ORDER BY s.created_at DESC
 LIMIT $2 OFFSET $3;
 
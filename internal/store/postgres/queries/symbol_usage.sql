@@ -0,0 +1,24 @@
+-- name: IncrementSymbolUsage :exec
+-- Adds hits to today's count for each symbol, kept by qualified name and kind so that
+-- the count outlives re-indexing; IDs of symbols deleted since are skipped.
+INSERT INTO symbol_usage (project_id, qualified_name, kind, day, hits)
+SELECT s.project_id, s.qualified_name, s.kind, CURRENT_DATE, u.hits
+FROM unnest(@symbol_ids::uuid[], @hits::bigint[]) AS u(symbol_id, hits)
+JOIN symbols s ON s.id = u.symbol_id
+ON CONFLICT (project_id, qualified_name, kind, day) DO UPDATE SET hits = symbol_usage.hits + EXCLUDED.hits;
+
+-- name: DeleteSymbolUsageBefore :exec
+-- Drops counts older than the given number of days.
+DELETE FROM symbol_usage WHERE day < CURRENT_DATE - @days::int;
+
+-- name: ListHotSymbols :many
+-- The project's symbols shown most often in tool responses over the last @days days.
+SELECT s.*, sum(u.hits)::bigint AS hits
+FROM symbol_usage u
+JOIN symbols s ON s.project_id = u.project_id AND s.qualified_name = u.qualified_name AND s.kind = u.kind
+WHERE u.project_id = @project_id
+  AND s.deleted_at IS NULL
+  AND u.day > CURRENT_DATE - @days::int
+GROUP BY s.id
+ORDER BY hits DESC, s.qualified_name
+LIMIT @lim;
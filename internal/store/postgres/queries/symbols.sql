@@ -1,6 +1,6 @@
 -- name: CreateSymbol :one
-INSERT INTO symbols (project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+INSERT INTO symbols (project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE(sqlc.narg('metadata')::jsonb, '{}'))
 ON CONFLICT (project_id, qualified_name, kind) DO UPDATE SET
     file_id = EXCLUDED.file_id,
     name = EXCLUDED.name,
@@ -11,53 +11,124 @@ ON CONFLICT (project_id, qualified_name, kind) DO UPDATE SET
     end_col = EXCLUDED.end_col,
     signature = EXCLUDED.signature,
     doc_comment = EXCLUDED.doc_comment,
+    metadata = symbols.metadata || EXCLUDED.metadata,
     updated_at = now()
 RETURNING *;
 
 -- name: CountSymbolsByProject :one
-SELECT count(*) FROM symbols WHERE project_id = $1;
+SELECT count(*) FROM symbols WHERE project_id = $1 AND deleted_at IS NULL;
 
 -- name: DeleteSymbolsByFile :exec
-DELETE FROM symbols WHERE file_id = $1;
+-- Project-wide symbols are kept: files indexed earlier have edges to them.
+DELETE FROM symbols WHERE file_id = $1 AND NOT coalesce((metadata->>'project_wide')::boolean, false);
 
 -- name: GetSymbol :one
-SELECT * FROM symbols WHERE id = $1;
+SELECT * FROM symbols WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: SearchSymbols :many
 SELECT * FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = @project_slug)
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = @project_slug AND deleted_at IS NULL)
   AND (name ILIKE '%' || @query || '%' OR qualified_name ILIKE '%' || @query || '%')
   AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
   AND (cardinality(@languages::text[]) = 0 OR language = ANY(@languages::text[]))
-ORDER BY name
+  AND (@module::text = '' OR metadata->>'module' = @module::text)
+  AND (@name_like::text = '' OR qualified_name ILIKE @name_like::text)
+ORDER BY CASE @rank_by::text
+    WHEN 'in_degree' THEN COALESCE((metadata->>'in_degree')::float8, 0)
+    WHEN 'pagerank' THEN COALESCE((metadata->>'pagerank')::float8, 0)
+    WHEN 'betweenness' THEN COALESCE((metadata->>'betweenness')::float8, 0)
+    ELSE 0
+  END DESC, name
+LIMIT @lim;
+
+-- name: SearchSymbolsPage :many
+-- A page of search_symbols results, in an order that does not depend on the caller's
+-- session: by the rank_by centrality measure, or else by the relevance to the best
+-- matching of queries, centrality and kind, weighted as mcp.DefaultRankConfig weighs
+-- them; ties broken by ID. A page starts after the (after_score, after_id) of the last
+-- row of the one before. total counts every match.
+WITH matches AS (
+    SELECT s.*,
+        (CASE @rank_by::text
+            WHEN '' THEN
+                0.3 * (SELECT max(CASE
+                        WHEN q = '' THEN 0.5
+                        WHEN lower(s.name) = lower(q) THEN 1.0
+                        WHEN lower(s.qualified_name) = lower(q) THEN 0.95
+                        WHEN s.name ILIKE '%' || q || '%' THEN 0.8
+                        ELSE 0.6 END)
+                    FROM unnest(@queries::text[]) q
+                    WHERE s.name ILIKE '%' || q || '%' OR s.qualified_name ILIKE '%' || q || '%')
+                + 0.2 * CASE
+                    WHEN s.metadata ? 'pagerank' THEN least(1, ln(1 + (s.metadata->>'pagerank')::float8 * 1000) / ln(11))
+                    WHEN s.metadata ? 'in_degree' THEN least(1, ln(1 + (s.metadata->>'in_degree')::float8) / ln(51))
+                    ELSE 0 END
+                + 0.15 * CASE lower(s.kind)
+                    WHEN 'table' THEN 1.0 WHEN 'class' THEN 0.95 WHEN 'view' THEN 0.9
+                    WHEN 'interface' THEN 0.85 WHEN 'procedure' THEN 0.85
+                    WHEN 'module' THEN 0.8 WHEN 'package' THEN 0.8
+                    WHEN 'method' THEN 0.7 WHEN 'function' THEN 0.7 WHEN 'type' THEN 0.65
+                    WHEN 'enum' THEN 0.6 WHEN 'trigger' THEN 0.6
+                    WHEN 'sequence' THEN 0.5 WHEN 'property' THEN 0.5 WHEN 'field' THEN 0.45
+                    WHEN 'variable' THEN 0.4 WHEN 'constant' THEN 0.4 WHEN 'column' THEN 0.35
+                    ELSE 0.3 END
+            ELSE COALESCE((s.metadata->>@rank_by::text)::float8, 0)
+        END)::float8 AS search_score,
+        count(*) OVER () AS total
+    FROM symbols s
+    WHERE s.deleted_at IS NULL
+      AND s.project_id = (SELECT id FROM projects WHERE slug = @project_slug AND deleted_at IS NULL)
+      AND EXISTS (SELECT 1 FROM unnest(@queries::text[]) q
+                  WHERE s.name ILIKE '%' || q || '%' OR s.qualified_name ILIKE '%' || q || '%')
+      AND (cardinality(@kinds::text[]) = 0 OR s.kind = ANY(@kinds::text[]))
+      AND (cardinality(@languages::text[]) = 0 OR s.language = ANY(@languages::text[]))
+      AND (@module::text = '' OR s.metadata->>'module' = @module::text)
+      AND (@tag::text = '' OR s.metadata->'tags' ? @tag::text OR s.metadata->'auto_tags' ? @tag::text)
+      AND (@name_like::text = '' OR s.qualified_name ILIKE @name_like::text)
+      AND (@name_regex::text = '' OR s.qualified_name ~ @name_regex::text)
+)
+SELECT * FROM matches
+WHERE sqlc.narg('after_id')::uuid IS NULL
+   OR search_score < sqlc.narg('after_score')::float8
+   OR (search_score = sqlc.narg('after_score')::float8 AND id > sqlc.narg('after_id')::uuid)
+ORDER BY search_score DESC, id
 LIMIT @lim;
 
 -- name: GetSymbolsByProject :many
-SELECT * FROM symbols WHERE project_id = $1 ORDER BY qualified_name LIMIT $2 OFFSET $3;
+SELECT * FROM symbols WHERE project_id = $1 AND deleted_at IS NULL ORDER BY qualified_name LIMIT $2 OFFSET $3;
 
 -- name: ListSymbolsByProject :many
-SELECT * FROM symbols WHERE project_id = $1;
+-- In declaration order within each file, so the symbol a file starts with comes first.
+SELECT * FROM symbols WHERE project_id = $1 AND deleted_at IS NULL
+ORDER BY file_id, start_line, start_col, id;
 
 -- name: ListSymbolsByFileIDs :many
-SELECT * FROM symbols WHERE file_id = ANY($1::uuid[]);
+SELECT * FROM symbols WHERE file_id = ANY($1::uuid[]) AND deleted_at IS NULL;
 
 -- name: GetSymbolByQualifiedName :one
-SELECT * FROM symbols WHERE project_id = $1 AND qualified_name = $2;
+SELECT * FROM symbols WHERE project_id = $1 AND qualified_name = $2 AND deleted_at IS NULL;
 
 -- name: ListSymbolsByNames :many
-SELECT * FROM symbols WHERE project_id = $1 AND name = ANY($2::text[]);
+SELECT * FROM symbols WHERE project_id = $1 AND name = ANY($2::text[]) AND deleted_at IS NULL;
 
 -- name: DeleteSymbolsByFileID :exec
 DELETE FROM symbols WHERE file_id = $1;
 
 -- name: ListColumnSymbolsByProject :many
-SELECT * FROM symbols WHERE project_id = $1 AND kind = 'column';
+SELECT * FROM symbols WHERE project_id = $1 AND kind = 'column' AND deleted_at IS NULL;
+
+-- name: ListSchemaSymbolsByProject :many
+SELECT qualified_name, kind, signature FROM symbols
+WHERE project_id = $1 AND kind IN ('table', 'column') AND deleted_at IS NULL
+ORDER BY qualified_name;
 
 -- name: SearchSymbolsGlobal :many
 SELECT s.*, p.slug AS project_slug
 FROM symbols s
 JOIN projects p ON s.project_id = p.id
-WHERE (s.name ILIKE '%' || @query || '%' OR s.qualified_name ILIKE '%' || @query || '%')
+WHERE s.deleted_at IS NULL AND p.deleted_at IS NULL
+  AND (s.name ILIKE '%' || @query || '%' OR s.qualified_name ILIKE '%' || @query || '%')
   AND (cardinality(@kinds::text[]) = 0 OR s.kind = ANY(@kinds::text[]))
   AND (cardinality(@languages::text[]) = 0 OR s.language = ANY(@languages::text[]))
 ORDER BY s.name
@@ -65,7 +136,8 @@ LIMIT @lim;
 
 -- name: SearchSymbolsRanked :many
 SELECT * FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = @project_slug)
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = @project_slug AND deleted_at IS NULL)
   AND (name ILIKE '%' || @query || '%' OR qualified_name ILIKE '%' || @query || '%')
   AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
   AND (cardinality(@languages::text[]) = 0 OR language = ANY(@languages::text[]))
@@ -79,8 +151,61 @@ LIMIT @lim;
 
 -- name: ListTopSymbolsByKind :many
 SELECT * FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = @project_slug)
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = @project_slug AND deleted_at IS NULL)
+  AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
+  AND (cardinality(@languages::text[]) = 0 OR language = ANY(@languages::text[]))
+ORDER BY CASE @rank_by::text
+    WHEN 'pagerank' THEN COALESCE((metadata->>'pagerank')::float8, 0)
+    WHEN 'betweenness' THEN COALESCE((metadata->>'betweenness')::float8, 0)
+    ELSE COALESCE((metadata->>'in_degree')::float8, 0)
+  END DESC, (COALESCE(metadata->>'in_degree', '0'))::int DESC, name
+LIMIT @lim;
+
+-- Endpoints that reach a table or view, by name or qualified name in any case, within
+-- max_depth hops of the given edge types without passing through another table or
+-- view, highest in-degree first.
+-- name: ListEndpointsTouchingTable :many
+WITH RECURSIVE reach(id, depth) AS (
+    SELECT t.id, 0
+    FROM symbols t
+    WHERE t.project_id = @project_id AND t.deleted_at IS NULL
+      AND t.kind IN ('table', 'view')
+      AND (lower(t.name) = lower(@table_name::text) OR lower(t.qualified_name) = lower(@table_name::text))
+  UNION
+    SELECT e.source_id, r.depth + 1
+    FROM reach r
+    JOIN symbol_edges e ON e.target_id = r.id AND e.project_id = @project_id
+    JOIN symbols src ON src.id = e.source_id AND src.deleted_at IS NULL
+    WHERE r.depth < @max_depth::int
+      AND e.edge_type = ANY(@edge_types::text[])
+      AND src.kind NOT IN ('table', 'view')
+)
+SELECT s.* FROM symbols s
+WHERE s.id IN (SELECT id FROM reach) AND s.kind = 'endpoint'
+ORDER BY COALESCE((s.metadata->>'in_degree')::float8, 0) DESC, s.name
+LIMIT @lim;
+
+-- Candidate dead code flagged by analytics (reference_status in the symbol metadata)
+-- name: ListUnreferencedSymbols :many
+SELECT * FROM symbols
+WHERE deleted_at IS NULL
+  AND project_id = @project_id
+  AND metadata->>'reference_status' = ANY(@statuses::text[])
   AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
   AND (cardinality(@languages::text[]) = 0 OR language = ANY(@languages::text[]))
-ORDER BY (COALESCE(metadata->>'in_degree', '0'))::int DESC
+ORDER BY kind, qualified_name
 LIMIT @lim;
+
+-- Symbols are soft-deleted with their project and share its deleted_at, so a restore
+-- only revives the rows that went with it.
+-- name: SoftDeleteSymbolsByProject :execrows
+UPDATE symbols SET deleted_at = @deleted_at::timestamptz
+WHERE project_id = @project_id AND deleted_at IS NULL;
+
+-- name: RestoreSymbolsByProject :execrows
+UPDATE symbols SET deleted_at = NULL
+WHERE project_id = @project_id AND deleted_at = @deleted_at::timestamptz;
+
+-- name: PurgeDeletedSymbols :execrows
+DELETE FROM symbols WHERE deleted_at < @before::timestamptz;
@@ -0,0 +1,19 @@
+-- name: BatchCreateUnresolvedReferences :execrows
+-- Multi-row insert of one project's unresolved references, one array element per reference.
+INSERT INTO unresolved_references (project_id, file_id, source_id, to_name, to_qualified, reference_type, line)
+SELECT @project_id::uuid, u.file_id, u.source_id, u.to_name, u.to_qualified, u.reference_type, u.line
+FROM unnest(@file_ids::uuid[], @source_ids::uuid[], @to_names::text[], @to_qualified::text[],
+            @reference_types::text[], @lines::int[]) AS u(file_id, source_id, to_name, to_qualified, reference_type, line);
+
+-- name: DeleteUnresolvedReferencesByFile :exec
+DELETE FROM unresolved_references WHERE file_id = $1;
+
+-- name: ListUnresolvedReferencesToName :many
+-- Unresolved references from the given sources whose target text names the given symbol
+-- (short or qualified, case-insensitive), qualified matches first.
+SELECT * FROM unresolved_references
+WHERE project_id = @project_id
+  AND source_id = ANY(@source_ids::uuid[])
+  AND (lower(to_name) = lower(@name::text) OR lower(to_qualified) = lower(@qualified_name::text))
+ORDER BY lower(to_qualified) = lower(@qualified_name::text) DESC, created_at
+LIMIT @lim;
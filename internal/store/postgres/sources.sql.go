@@ -83,7 +83,7 @@ func (q *Queries) GetSource(ctx context.Context, id uuid.UUID) (Source, error) {
 const listSourcesByProject = `-- name: ListSourcesByProject :many
 SELECT s.id, s.project_id, s.name, s.source_type, s.connection_uri, s.config, s.last_synced_at, s.created_at, s.updated_at, s.last_commit_sha FROM sources s
 JOIN projects p ON s.project_id = p.id
-WHERE p.slug = $1
+WHERE p.slug = $1 AND p.deleted_at IS NULL
 ORDER BY s.created_at DESC
 LIMIT $2 OFFSET $3
 `
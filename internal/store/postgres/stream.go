@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+)
+
+// Streaming variants of generated :many queries. Rows are handed to fn as they are read
+// off the connection instead of being collected into a slice, so callers can forward
+// large result sets without holding them in memory. Returning an error from fn stops
+// the query and is returned as is.
+
+// SearchSymbolsEach runs SearchSymbols, calling fn for each matching symbol in order.
+func (q *Queries) SearchSymbolsEach(ctx context.Context, arg SearchSymbolsParams, fn func(Symbol) error) error {
+	rows, err := q.db.Query(ctx, searchSymbols, searchSymbolsArgs(arg)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var i Symbol
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.FileID,
+			&i.Name,
+			&i.QualifiedName,
+			&i.Kind,
+			&i.Language,
+			&i.StartLine,
+			&i.EndLine,
+			&i.StartCol,
+			&i.EndCol,
+			&i.Signature,
+			&i.DocComment,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// searchSymbolsArgs lists the parameters of searchSymbols in placeholder order, the same
+// as the generated SearchSymbols passes them. TestSearchSymbolsArgs fails when the query
+// gains a parameter that is not added here.
+func searchSymbolsArgs(arg SearchSymbolsParams) []any {
+	return []any{
+		arg.ProjectSlug,
+		arg.Query,
+		arg.Kinds,
+		arg.Languages,
+		arg.Module,
+		arg.NameLike,
+		arg.RankBy,
+		arg.Lim,
+	}
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// TestSearchSymbolsArgs guards SearchSymbolsEach against drifting from the generated
+// SearchSymbols: it must pass one argument per placeholder of the query, in the order
+// of SearchSymbolsParams.
+func TestSearchSymbolsArgs(t *testing.T) {
+	placeholders := 0
+	for _, m := range regexp.MustCompile(`\$(\d+)`).FindAllStringSubmatch(searchSymbols, -1) {
+		n, _ := strconv.Atoi(m[1])
+		placeholders = max(placeholders, n)
+	}
+
+	params := reflect.ValueOf(SearchSymbolsParams{})
+	if params.NumField() != placeholders {
+		t.Fatalf("SearchSymbolsParams has %d fields, query has %d placeholders", params.NumField(), placeholders)
+	}
+
+	// Give each field a distinct value so the order of the arguments can be checked
+	arg := SearchSymbolsParams{}
+	v := reflect.ValueOf(&arg).Elem()
+	for i := range v.NumField() {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(strconv.Itoa(i))
+		case reflect.Pointer:
+			s := strconv.Itoa(i)
+			f.Set(reflect.ValueOf(&s))
+		case reflect.Slice:
+			f.Set(reflect.ValueOf([]string{strconv.Itoa(i)}))
+		case reflect.Int32:
+			f.SetInt(int64(i))
+		default:
+			t.Fatalf("field %s: unhandled kind %s", v.Type().Field(i).Name, f.Kind())
+		}
+	}
+
+	args := searchSymbolsArgs(arg)
+	if len(args) != placeholders {
+		t.Fatalf("searchSymbolsArgs passes %d arguments, query has %d placeholders", len(args), placeholders)
+	}
+	for i, a := range args {
+		if !reflect.DeepEqual(a, v.Field(i).Interface()) {
+			t.Errorf("argument $%d is %v, want field %s", i+1, a, v.Type().Field(i).Name)
+		}
+	}
+}
@@ -10,10 +10,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countSymbolsByProject = `-- name: CountSymbolsByProject :one
-SELECT count(*) FROM symbols WHERE project_id = $1
+SELECT count(*) FROM symbols WHERE project_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountSymbolsByProject(ctx context.Context, projectID uuid.UUID) (int64, error) {
@@ -24,8 +25,8 @@ func (q *Queries) CountSymbolsByProject(ctx context.Context, projectID uuid.UUID
 }
 
 const createSymbol = `-- name: CreateSymbol :one
-INSERT INTO symbols (project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+INSERT INTO symbols (project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13::jsonb, '{}'))
 ON CONFLICT (project_id, qualified_name, kind) DO UPDATE SET
     file_id = EXCLUDED.file_id,
     name = EXCLUDED.name,
@@ -36,8 +37,9 @@ ON CONFLICT (project_id, qualified_name, kind) DO UPDATE SET
     end_col = EXCLUDED.end_col,
     signature = EXCLUDED.signature,
     doc_comment = EXCLUDED.doc_comment,
+    metadata = symbols.metadata || EXCLUDED.metadata,
     updated_at = now()
-RETURNING id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at
+RETURNING id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at
 `
 
 type CreateSymbolParams struct {
@@ -53,6 +55,7 @@ type CreateSymbolParams struct {
 	EndCol        *int32    `json:"end_col"`
 	Signature     *string   `json:"signature"`
 	DocComment    *string   `json:"doc_comment"`
+	Metadata      []byte    `json:"metadata"`
 }
 
 func (q *Queries) CreateSymbol(ctx context.Context, arg CreateSymbolParams) (Symbol, error) {
@@ -69,6 +72,7 @@ func (q *Queries) CreateSymbol(ctx context.Context, arg CreateSymbolParams) (Sym
 		arg.EndCol,
 		arg.Signature,
 		arg.DocComment,
+		arg.Metadata,
 	)
 	var i Symbol
 	err := row.Scan(
@@ -88,14 +92,16 @@ func (q *Queries) CreateSymbol(ctx context.Context, arg CreateSymbolParams) (Sym
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteSymbolsByFile = `-- name: DeleteSymbolsByFile :exec
-DELETE FROM symbols WHERE file_id = $1
+DELETE FROM symbols WHERE file_id = $1 AND NOT coalesce((metadata->>'project_wide')::boolean, false)
 `
 
+// Project-wide symbols are kept: files indexed earlier have edges to them.
 func (q *Queries) DeleteSymbolsByFile(ctx context.Context, fileID uuid.UUID) error {
 	_, err := q.db.Exec(ctx, deleteSymbolsByFile, fileID)
 	return err
@@ -111,7 +117,7 @@ func (q *Queries) DeleteSymbolsByFileID(ctx context.Context, fileID uuid.UUID) e
 }
 
 const getSymbol = `-- name: GetSymbol :one
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE id = $1
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSymbol(ctx context.Context, id uuid.UUID) (Symbol, error) {
@@ -134,12 +140,13 @@ func (q *Queries) GetSymbol(ctx context.Context, id uuid.UUID) (Symbol, error) {
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getSymbolByQualifiedName = `-- name: GetSymbolByQualifiedName :one
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE project_id = $1 AND qualified_name = $2
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE project_id = $1 AND qualified_name = $2 AND deleted_at IS NULL
 `
 
 type GetSymbolByQualifiedNameParams struct {
@@ -167,12 +174,13 @@ func (q *Queries) GetSymbolByQualifiedName(ctx context.Context, arg GetSymbolByQ
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getSymbolsByProject = `-- name: GetSymbolsByProject :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE project_id = $1 ORDER BY qualified_name LIMIT $2 OFFSET $3
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE project_id = $1 AND deleted_at IS NULL ORDER BY qualified_name LIMIT $2 OFFSET $3
 `
 
 type GetSymbolsByProjectParams struct {
@@ -207,6 +215,7 @@ func (q *Queries) GetSymbolsByProject(ctx context.Context, arg GetSymbolsByProje
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -219,7 +228,7 @@ func (q *Queries) GetSymbolsByProject(ctx context.Context, arg GetSymbolsByProje
 }
 
 const listColumnSymbolsByProject = `-- name: ListColumnSymbolsByProject :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE project_id = $1 AND kind = 'column'
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE project_id = $1 AND kind = 'column' AND deleted_at IS NULL
 `
 
 func (q *Queries) ListColumnSymbolsByProject(ctx context.Context, projectID uuid.UUID) ([]Symbol, error) {
@@ -248,6 +257,84 @@ func (q *Queries) ListColumnSymbolsByProject(ctx context.Context, projectID uuid
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEndpointsTouchingTable = `-- name: ListEndpointsTouchingTable :many
+WITH RECURSIVE reach(id, depth) AS (
+    SELECT t.id, 0
+    FROM symbols t
+    WHERE t.project_id = $1 AND t.deleted_at IS NULL
+      AND t.kind IN ('table', 'view')
+      AND (lower(t.name) = lower($2::text) OR lower(t.qualified_name) = lower($2::text))
+  UNION
+    SELECT e.source_id, r.depth + 1
+    FROM reach r
+    JOIN symbol_edges e ON e.target_id = r.id AND e.project_id = $1
+    JOIN symbols src ON src.id = e.source_id AND src.deleted_at IS NULL
+    WHERE r.depth < $3::int
+      AND e.edge_type = ANY($4::text[])
+      AND src.kind NOT IN ('table', 'view')
+)
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at FROM symbols s
+WHERE s.id IN (SELECT id FROM reach) AND s.kind = 'endpoint'
+ORDER BY COALESCE((s.metadata->>'in_degree')::float8, 0) DESC, s.name
+LIMIT $5
+`
+
+type ListEndpointsTouchingTableParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	TableName string    `json:"table_name"`
+	MaxDepth  int32     `json:"max_depth"`
+	EdgeTypes []string  `json:"edge_types"`
+	Lim       int32     `json:"lim"`
+}
+
+// Endpoints that reach a table or view, by name or qualified name in any case, within
+// max_depth hops of the given edge types without passing through another table or
+// view, highest in-degree first.
+func (q *Queries) ListEndpointsTouchingTable(ctx context.Context, arg ListEndpointsTouchingTableParams) ([]Symbol, error) {
+	rows, err := q.db.Query(ctx, listEndpointsTouchingTable,
+		arg.ProjectID,
+		arg.TableName,
+		arg.MaxDepth,
+		arg.EdgeTypes,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Symbol{}
+	for rows.Next() {
+		var i Symbol
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.FileID,
+			&i.Name,
+			&i.QualifiedName,
+			&i.Kind,
+			&i.Language,
+			&i.StartLine,
+			&i.EndLine,
+			&i.StartCol,
+			&i.EndCol,
+			&i.Signature,
+			&i.DocComment,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -259,8 +346,40 @@ func (q *Queries) ListColumnSymbolsByProject(ctx context.Context, projectID uuid
 	return items, nil
 }
 
+const listSchemaSymbolsByProject = `-- name: ListSchemaSymbolsByProject :many
+SELECT qualified_name, kind, signature FROM symbols
+WHERE project_id = $1 AND kind IN ('table', 'column') AND deleted_at IS NULL
+ORDER BY qualified_name
+`
+
+type ListSchemaSymbolsByProjectRow struct {
+	QualifiedName string  `json:"qualified_name"`
+	Kind          string  `json:"kind"`
+	Signature     *string `json:"signature"`
+}
+
+func (q *Queries) ListSchemaSymbolsByProject(ctx context.Context, projectID uuid.UUID) ([]ListSchemaSymbolsByProjectRow, error) {
+	rows, err := q.db.Query(ctx, listSchemaSymbolsByProject, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSchemaSymbolsByProjectRow{}
+	for rows.Next() {
+		var i ListSchemaSymbolsByProjectRow
+		if err := rows.Scan(&i.QualifiedName, &i.Kind, &i.Signature); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSymbolsByFileIDs = `-- name: ListSymbolsByFileIDs :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE file_id = ANY($1::uuid[])
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE file_id = ANY($1::uuid[]) AND deleted_at IS NULL
 `
 
 func (q *Queries) ListSymbolsByFileIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]Symbol, error) {
@@ -289,6 +408,7 @@ func (q *Queries) ListSymbolsByFileIDs(ctx context.Context, dollar_1 []uuid.UUID
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -301,7 +421,7 @@ func (q *Queries) ListSymbolsByFileIDs(ctx context.Context, dollar_1 []uuid.UUID
 }
 
 const listSymbolsByNames = `-- name: ListSymbolsByNames :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE project_id = $1 AND name = ANY($2::text[])
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE project_id = $1 AND name = ANY($2::text[]) AND deleted_at IS NULL
 `
 
 type ListSymbolsByNamesParams struct {
@@ -335,6 +455,7 @@ func (q *Queries) ListSymbolsByNames(ctx context.Context, arg ListSymbolsByNames
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -347,9 +468,11 @@ func (q *Queries) ListSymbolsByNames(ctx context.Context, arg ListSymbolsByNames
 }
 
 const listSymbolsByProject = `-- name: ListSymbolsByProject :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols WHERE project_id = $1
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols WHERE project_id = $1 AND deleted_at IS NULL
+ORDER BY file_id, start_line, start_col, id
 `
 
+// In declaration order within each file, so the symbol a file starts with comes first.
 func (q *Queries) ListSymbolsByProject(ctx context.Context, projectID uuid.UUID) ([]Symbol, error) {
 	rows, err := q.db.Query(ctx, listSymbolsByProject, projectID)
 	if err != nil {
@@ -376,6 +499,7 @@ func (q *Queries) ListSymbolsByProject(ctx context.Context, projectID uuid.UUID)
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -388,18 +512,24 @@ func (q *Queries) ListSymbolsByProject(ctx context.Context, projectID uuid.UUID)
 }
 
 const listTopSymbolsByKind = `-- name: ListTopSymbolsByKind :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = $1)
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = $1 AND deleted_at IS NULL)
   AND (cardinality($2::text[]) = 0 OR kind = ANY($2::text[]))
   AND (cardinality($3::text[]) = 0 OR language = ANY($3::text[]))
-ORDER BY (COALESCE(metadata->>'in_degree', '0'))::int DESC
-LIMIT $4
+ORDER BY CASE $4::text
+    WHEN 'pagerank' THEN COALESCE((metadata->>'pagerank')::float8, 0)
+    WHEN 'betweenness' THEN COALESCE((metadata->>'betweenness')::float8, 0)
+    ELSE COALESCE((metadata->>'in_degree')::float8, 0)
+  END DESC, (COALESCE(metadata->>'in_degree', '0'))::int DESC, name
+LIMIT $5
 `
 
 type ListTopSymbolsByKindParams struct {
 	ProjectSlug string   `json:"project_slug"`
 	Kinds       []string `json:"kinds"`
 	Languages   []string `json:"languages"`
+	RankBy      string   `json:"rank_by"`
 	Lim         int32    `json:"lim"`
 }
 
@@ -408,6 +538,7 @@ func (q *Queries) ListTopSymbolsByKind(ctx context.Context, arg ListTopSymbolsBy
 		arg.ProjectSlug,
 		arg.Kinds,
 		arg.Languages,
+		arg.RankBy,
 		arg.Lim,
 	)
 	if err != nil {
@@ -434,6 +565,7 @@ func (q *Queries) ListTopSymbolsByKind(ctx context.Context, arg ListTopSymbolsBy
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -445,14 +577,116 @@ func (q *Queries) ListTopSymbolsByKind(ctx context.Context, arg ListTopSymbolsBy
 	return items, nil
 }
 
+const listUnreferencedSymbols = `-- name: ListUnreferencedSymbols :many
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols
+WHERE deleted_at IS NULL
+  AND project_id = $1
+  AND metadata->>'reference_status' = ANY($2::text[])
+  AND (cardinality($3::text[]) = 0 OR kind = ANY($3::text[]))
+  AND (cardinality($4::text[]) = 0 OR language = ANY($4::text[]))
+ORDER BY kind, qualified_name
+LIMIT $5
+`
+
+type ListUnreferencedSymbolsParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	Statuses  []string  `json:"statuses"`
+	Kinds     []string  `json:"kinds"`
+	Languages []string  `json:"languages"`
+	Lim       int32     `json:"lim"`
+}
+
+// Candidate dead code flagged by analytics (reference_status in the symbol metadata)
+func (q *Queries) ListUnreferencedSymbols(ctx context.Context, arg ListUnreferencedSymbolsParams) ([]Symbol, error) {
+	rows, err := q.db.Query(ctx, listUnreferencedSymbols,
+		arg.ProjectID,
+		arg.Statuses,
+		arg.Kinds,
+		arg.Languages,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Symbol{}
+	for rows.Next() {
+		var i Symbol
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.FileID,
+			&i.Name,
+			&i.QualifiedName,
+			&i.Kind,
+			&i.Language,
+			&i.StartLine,
+			&i.EndLine,
+			&i.StartCol,
+			&i.EndCol,
+			&i.Signature,
+			&i.DocComment,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedSymbols = `-- name: PurgeDeletedSymbols :execrows
+DELETE FROM symbols WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeDeletedSymbols(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedSymbols, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreSymbolsByProject = `-- name: RestoreSymbolsByProject :execrows
+UPDATE symbols SET deleted_at = NULL
+WHERE project_id = $1 AND deleted_at = $2::timestamptz
+`
+
+type RestoreSymbolsByProjectParams struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreSymbolsByProject(ctx context.Context, arg RestoreSymbolsByProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreSymbolsByProject, arg.ProjectID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const searchSymbols = `-- name: SearchSymbols :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = $1)
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = $1 AND deleted_at IS NULL)
   AND (name ILIKE '%' || $2 || '%' OR qualified_name ILIKE '%' || $2 || '%')
   AND (cardinality($3::text[]) = 0 OR kind = ANY($3::text[]))
   AND (cardinality($4::text[]) = 0 OR language = ANY($4::text[]))
-ORDER BY name
-LIMIT $5
+  AND ($5::text = '' OR metadata->>'module' = $5::text)
+  AND ($6::text = '' OR qualified_name ILIKE $6::text)
+ORDER BY CASE $7::text
+    WHEN 'in_degree' THEN COALESCE((metadata->>'in_degree')::float8, 0)
+    WHEN 'pagerank' THEN COALESCE((metadata->>'pagerank')::float8, 0)
+    WHEN 'betweenness' THEN COALESCE((metadata->>'betweenness')::float8, 0)
+    ELSE 0
+  END DESC, name
+LIMIT $8
 `
 
 type SearchSymbolsParams struct {
@@ -460,6 +694,9 @@ type SearchSymbolsParams struct {
 	Query       *string  `json:"query"`
 	Kinds       []string `json:"kinds"`
 	Languages   []string `json:"languages"`
+	Module      string   `json:"module"`
+	NameLike    string   `json:"name_like"`
+	RankBy      string   `json:"rank_by"`
 	Lim         int32    `json:"lim"`
 }
 
@@ -469,6 +706,9 @@ func (q *Queries) SearchSymbols(ctx context.Context, arg SearchSymbolsParams) ([
 		arg.Query,
 		arg.Kinds,
 		arg.Languages,
+		arg.Module,
+		arg.NameLike,
+		arg.RankBy,
 		arg.Lim,
 	)
 	if err != nil {
@@ -495,6 +735,7 @@ func (q *Queries) SearchSymbols(ctx context.Context, arg SearchSymbolsParams) ([
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -507,10 +748,11 @@ func (q *Queries) SearchSymbols(ctx context.Context, arg SearchSymbolsParams) ([
 }
 
 const searchSymbolsGlobal = `-- name: SearchSymbolsGlobal :many
-SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, p.slug AS project_slug
+SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at, p.slug AS project_slug
 FROM symbols s
 JOIN projects p ON s.project_id = p.id
-WHERE (s.name ILIKE '%' || $1 || '%' OR s.qualified_name ILIKE '%' || $1 || '%')
+WHERE s.deleted_at IS NULL AND p.deleted_at IS NULL
+  AND (s.name ILIKE '%' || $1 || '%' OR s.qualified_name ILIKE '%' || $1 || '%')
   AND (cardinality($2::text[]) = 0 OR s.kind = ANY($2::text[]))
   AND (cardinality($3::text[]) = 0 OR s.language = ANY($3::text[]))
 ORDER BY s.name
@@ -525,23 +767,24 @@ type SearchSymbolsGlobalParams struct {
 }
 
 type SearchSymbolsGlobalRow struct {
-	ID            uuid.UUID `json:"id"`
-	ProjectID     uuid.UUID `json:"project_id"`
-	FileID        uuid.UUID `json:"file_id"`
-	Name          string    `json:"name"`
-	QualifiedName string    `json:"qualified_name"`
-	Kind          string    `json:"kind"`
-	Language      string    `json:"language"`
-	StartLine     int32     `json:"start_line"`
-	EndLine       int32     `json:"end_line"`
-	StartCol      *int32    `json:"start_col"`
-	EndCol        *int32    `json:"end_col"`
-	Signature     *string   `json:"signature"`
-	DocComment    *string   `json:"doc_comment"`
-	Metadata      []byte    `json:"metadata"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	ProjectSlug   string    `json:"project_slug"`
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	ProjectSlug   string             `json:"project_slug"`
 }
 
 func (q *Queries) SearchSymbolsGlobal(ctx context.Context, arg SearchSymbolsGlobalParams) ([]SearchSymbolsGlobalRow, error) {
@@ -575,6 +818,7 @@ func (q *Queries) SearchSymbolsGlobal(ctx context.Context, arg SearchSymbolsGlob
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 			&i.ProjectSlug,
 		); err != nil {
 			return nil, err
@@ -587,9 +831,154 @@ func (q *Queries) SearchSymbolsGlobal(ctx context.Context, arg SearchSymbolsGlob
 	return items, nil
 }
 
+const searchSymbolsPage = `-- name: SearchSymbolsPage :many
+WITH matches AS (
+    SELECT s.id, s.project_id, s.file_id, s.name, s.qualified_name, s.kind, s.language, s.start_line, s.end_line, s.start_col, s.end_col, s.signature, s.doc_comment, s.metadata, s.created_at, s.updated_at, s.deleted_at,
+        (CASE $1::text
+            WHEN '' THEN
+                0.3 * (SELECT max(CASE
+                        WHEN q = '' THEN 0.5
+                        WHEN lower(s.name) = lower(q) THEN 1.0
+                        WHEN lower(s.qualified_name) = lower(q) THEN 0.95
+                        WHEN s.name ILIKE '%' || q || '%' THEN 0.8
+                        ELSE 0.6 END)
+                    FROM unnest($2::text[]) q
+                    WHERE s.name ILIKE '%' || q || '%' OR s.qualified_name ILIKE '%' || q || '%')
+                + 0.2 * CASE
+                    WHEN s.metadata ? 'pagerank' THEN least(1, ln(1 + (s.metadata->>'pagerank')::float8 * 1000) / ln(11))
+                    WHEN s.metadata ? 'in_degree' THEN least(1, ln(1 + (s.metadata->>'in_degree')::float8) / ln(51))
+                    ELSE 0 END
+                + 0.15 * CASE lower(s.kind)
+                    WHEN 'table' THEN 1.0 WHEN 'class' THEN 0.95 WHEN 'view' THEN 0.9
+                    WHEN 'interface' THEN 0.85 WHEN 'procedure' THEN 0.85
+                    WHEN 'module' THEN 0.8 WHEN 'package' THEN 0.8
+                    WHEN 'method' THEN 0.7 WHEN 'function' THEN 0.7 WHEN 'type' THEN 0.65
+                    WHEN 'enum' THEN 0.6 WHEN 'trigger' THEN 0.6
+                    WHEN 'sequence' THEN 0.5 WHEN 'property' THEN 0.5 WHEN 'field' THEN 0.45
+                    WHEN 'variable' THEN 0.4 WHEN 'constant' THEN 0.4 WHEN 'column' THEN 0.35
+                    ELSE 0.3 END
+            ELSE COALESCE((s.metadata->>$1::text)::float8, 0)
+        END)::float8 AS search_score,
+        count(*) OVER () AS total
+    FROM symbols s
+    WHERE s.deleted_at IS NULL
+      AND s.project_id = (SELECT id FROM projects WHERE slug = $3 AND deleted_at IS NULL)
+      AND EXISTS (SELECT 1 FROM unnest($2::text[]) q
+                  WHERE s.name ILIKE '%' || q || '%' OR s.qualified_name ILIKE '%' || q || '%')
+      AND (cardinality($4::text[]) = 0 OR s.kind = ANY($4::text[]))
+      AND (cardinality($5::text[]) = 0 OR s.language = ANY($5::text[]))
+      AND ($6::text = '' OR s.metadata->>'module' = $6::text)
+      AND ($7::text = '' OR s.metadata->'tags' ? $7::text OR s.metadata->'auto_tags' ? $7::text)
+      AND ($8::text = '' OR s.qualified_name ILIKE $8::text)
+      AND ($9::text = '' OR s.qualified_name ~ $9::text)
+)
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at, search_score, total FROM matches
+WHERE $10::uuid IS NULL
+   OR search_score < $11::float8
+   OR (search_score = $11::float8 AND id > $10::uuid)
+ORDER BY search_score DESC, id
+LIMIT $12;
+`
+
+type SearchSymbolsPageParams struct {
+	RankBy      string      `json:"rank_by"`
+	Queries     []string    `json:"queries"`
+	ProjectSlug string      `json:"project_slug"`
+	Kinds       []string    `json:"kinds"`
+	Languages   []string    `json:"languages"`
+	Module      string      `json:"module"`
+	Tag         string      `json:"tag"`
+	NameLike    string      `json:"name_like"`
+	NameRegex   string      `json:"name_regex"`
+	AfterID     pgtype.UUID `json:"after_id"`
+	AfterScore  *float64    `json:"after_score"`
+	Lim         int32       `json:"lim"`
+}
+
+type SearchSymbolsPageRow struct {
+	ID            uuid.UUID          `json:"id"`
+	ProjectID     uuid.UUID          `json:"project_id"`
+	FileID        uuid.UUID          `json:"file_id"`
+	Name          string             `json:"name"`
+	QualifiedName string             `json:"qualified_name"`
+	Kind          string             `json:"kind"`
+	Language      string             `json:"language"`
+	StartLine     int32              `json:"start_line"`
+	EndLine       int32              `json:"end_line"`
+	StartCol      *int32             `json:"start_col"`
+	EndCol        *int32             `json:"end_col"`
+	Signature     *string            `json:"signature"`
+	DocComment    *string            `json:"doc_comment"`
+	Metadata      []byte             `json:"metadata"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	SearchScore   float64            `json:"search_score"`
+	Total         int64              `json:"total"`
+}
+
+// A page of search_symbols results, in an order that does not depend on the caller's
+// session: by the rank_by centrality measure, or else by the relevance to the best
+// matching of queries, centrality and kind, weighted as mcp.DefaultRankConfig weighs
+// them; ties broken by ID. A page starts after the (after_score, after_id) of the last
+// row of the one before. total counts every match.
+func (q *Queries) SearchSymbolsPage(ctx context.Context, arg SearchSymbolsPageParams) ([]SearchSymbolsPageRow, error) {
+	rows, err := q.db.Query(ctx, searchSymbolsPage,
+		arg.RankBy,
+		arg.Queries,
+		arg.ProjectSlug,
+		arg.Kinds,
+		arg.Languages,
+		arg.Module,
+		arg.Tag,
+		arg.NameLike,
+		arg.NameRegex,
+		arg.AfterID,
+		arg.AfterScore,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchSymbolsPageRow{}
+	for rows.Next() {
+		var i SearchSymbolsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.FileID,
+			&i.Name,
+			&i.QualifiedName,
+			&i.Kind,
+			&i.Language,
+			&i.StartLine,
+			&i.EndLine,
+			&i.StartCol,
+			&i.EndCol,
+			&i.Signature,
+			&i.DocComment,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.SearchScore,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchSymbolsRanked = `-- name: SearchSymbolsRanked :many
-SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at FROM symbols
-WHERE project_id = (SELECT id FROM projects WHERE slug = $1)
+SELECT id, project_id, file_id, name, qualified_name, kind, language, start_line, end_line, start_col, end_col, signature, doc_comment, metadata, created_at, updated_at, deleted_at FROM symbols
+WHERE deleted_at IS NULL
+  AND project_id = (SELECT id FROM projects WHERE slug = $1 AND deleted_at IS NULL)
   AND (name ILIKE '%' || $2 || '%' OR qualified_name ILIKE '%' || $2 || '%')
   AND (cardinality($3::text[]) = 0 OR kind = ANY($3::text[]))
   AND (cardinality($4::text[]) = 0 OR language = ANY($4::text[]))
@@ -642,6 +1031,7 @@ func (q *Queries) SearchSymbolsRanked(ctx context.Context, arg SearchSymbolsRank
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -652,3 +1042,21 @@ func (q *Queries) SearchSymbolsRanked(ctx context.Context, arg SearchSymbolsRank
 	}
 	return items, nil
 }
+
+const softDeleteSymbolsByProject = `-- name: SoftDeleteSymbolsByProject :execrows
+UPDATE symbols SET deleted_at = $1::timestamptz
+WHERE project_id = $2 AND deleted_at IS NULL
+`
+
+type SoftDeleteSymbolsByProjectParams struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	ProjectID uuid.UUID `json:"project_id"`
+}
+
+func (q *Queries) SoftDeleteSymbolsByProject(ctx context.Context, arg SoftDeleteSymbolsByProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteSymbolsByProject, arg.DeletedAt, arg.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,45 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+func TestListTopSymbolsByKindRankBy(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, a, _ := seedProject(t, s)
+	b, err := s.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: proj.ID, QualifiedName: "dbo.B"})
+	if err != nil {
+		t.Fatalf("get symbol: %v", err)
+	}
+
+	// dbo.B is a leaf called from everywhere; dbo.A is called less but ranks higher.
+	if err := s.BatchUpdateSymbolMetadataEach(ctx, postgres.BatchUpdateSymbolMetadataEachParams{
+		SymbolIds: []uuid.UUID{a.ID, b.ID},
+		AnalyticsJson: []string{
+			`{"in_degree": 3, "pagerank": 0.4, "betweenness": 0.2}`,
+			`{"in_degree": 50, "pagerank": 0.01, "betweenness": 0}`,
+		},
+	}); err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+
+	for rankBy, want := range map[string]string{"": "dbo.B", "in_degree": "dbo.B", "pagerank": "dbo.A", "betweenness": "dbo.A"} {
+		top, err := s.ListTopSymbolsByKind(ctx, postgres.ListTopSymbolsByKindParams{
+			ProjectSlug: proj.Slug, Kinds: []string{}, Languages: []string{}, RankBy: rankBy, Lim: 2,
+		})
+		if err != nil {
+			t.Fatalf("list top symbols by %q: %v", rankBy, err)
+		}
+		if len(top) != 2 || top[0].QualifiedName != want {
+			t.Errorf("rank_by %q: expected %s first, got %v", rankBy, want, top)
+		}
+	}
+}
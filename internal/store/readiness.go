@@ -0,0 +1,10 @@
+package store
+
+// Project readiness states, stored in projects.readiness. The ingestion pipeline moves a
+// project through them while it runs; read tools warn while a project is not ready.
+const (
+	ReadinessIndexing         = "indexing"
+	ReadinessResolving        = "resolving"
+	ReadinessAnalyticsPending = "analytics_pending"
+	ReadinessReady            = "ready"
+)
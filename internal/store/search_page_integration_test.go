@@ -0,0 +1,126 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Paging walks every match once, past the 1000 the search used to rank in memory.
+func TestSearchSymbolsPage_WalksEveryMatch(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	const n = 1205
+	for i := range n {
+		name := fmt.Sprintf("dbo.usp_Order%04d", i)
+		kind := "procedure"
+		if i%3 == 0 {
+			kind = "table"
+		}
+		if _, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: sym.FileID, Name: name, QualifiedName: name,
+			Kind: kind, Language: "tsql", StartLine: 1, EndLine: 2,
+			Metadata: []byte(fmt.Sprintf(`{"in_degree": %d}`, i%7)),
+		}); err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	arg := postgres.SearchSymbolsPageParams{
+		Queries: []string{"order"}, ProjectSlug: proj.Slug, Kinds: []string{}, Languages: []string{}, Lim: 500,
+	}
+	last := 2.0
+	for {
+		page, err := s.SearchSymbolsPage(ctx, arg)
+		if err != nil {
+			t.Fatalf("search page: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, row := range page {
+			if row.Total != n {
+				t.Fatalf("expected %d matches, got %d", n, row.Total)
+			}
+			if seen[row.ID] {
+				t.Fatalf("%s returned twice", row.QualifiedName)
+			}
+			if row.SearchScore > last {
+				t.Fatalf("%s scored %v after a row scored %v", row.QualifiedName, row.SearchScore, last)
+			}
+			seen[row.ID], last = true, row.SearchScore
+		}
+		end := page[len(page)-1]
+		arg.AfterID = pgtype.UUID{Bytes: end.ID, Valid: true}
+		arg.AfterScore = &end.SearchScore
+	}
+	if len(seen) != n {
+		t.Fatalf("pages returned %d of %d matches", len(seen), n)
+	}
+}
+
+func TestSearchSymbolsPage_NameRegex(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	for _, name := range []string{"billing.InvoiceService", "billing.PaymentService", "billing.PaymentServiceImpl"} {
+		if _, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: sym.FileID, Name: name, QualifiedName: name,
+			Kind: "class", Language: "csharp", StartLine: 1, EndLine: 2,
+		}); err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+	}
+
+	page, err := s.SearchSymbolsPage(ctx, postgres.SearchSymbolsPageParams{
+		Queries: []string{"service"}, ProjectSlug: proj.Slug, Kinds: []string{}, Languages: []string{},
+		NameRegex: `^billing\.(Invoice|Payment)Service$`, Lim: 10,
+	})
+	if err != nil {
+		t.Fatalf("search page: %v", err)
+	}
+	if len(page) != 2 || page[0].Total != 2 {
+		t.Fatalf("expected the two services matching the regex, got %+v", page)
+	}
+}
+
+func TestSearchSymbolsPage_Tag(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	for name, meta := range map[string]string{
+		"dbo.Customers.email":  `{"auto_tags": ["pii"]}`,
+		"dbo.Customers.phone":  `{"tags": ["pii"]}`,
+		"dbo.Customers.status": `{"auto_tags": []}`,
+	} {
+		if _, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: sym.FileID, Name: name, QualifiedName: name,
+			Kind: "column", Language: "tsql", StartLine: 1, EndLine: 1, Metadata: []byte(meta),
+		}); err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+	}
+
+	page, err := s.SearchSymbolsPage(ctx, postgres.SearchSymbolsPageParams{
+		Queries: []string{"customers"}, ProjectSlug: proj.Slug, Kinds: []string{}, Languages: []string{},
+		Tag: "pii", Lim: 10,
+	})
+	if err != nil {
+		t.Fatalf("search page: %v", err)
+	}
+	if len(page) != 2 || page[0].Total != 2 {
+		t.Fatalf("expected the curated and the automatic pii column, got %+v", page)
+	}
+}
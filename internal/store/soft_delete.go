@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+// ErrRetentionElapsed is returned when restoring a project deleted longer ago than the
+// retention window; the purge job may already have removed it.
+var ErrRetentionElapsed = errors.New("project retention window has elapsed")
+
+// ErrSlugTaken is returned when restoring a project whose slug was given to a new project
+// after it was deleted.
+var ErrSlugTaken = errors.New("project slug is in use by another project")
+
+// SoftDeleteProjectCascade stamps deleted_at on a project and on its symbols and edges in
+// one transaction. All three share the timestamp so a restore revives exactly these rows.
+func (s *Store) SoftDeleteProjectCascade(ctx context.Context, slug string) (postgres.Project, error) {
+	var project postgres.Project
+	err := s.WithTx(ctx, func(q *postgres.Queries) error {
+		var err error
+		if project, err = q.SoftDeleteProject(ctx, slug); err != nil {
+			return err
+		}
+		deletedAt := project.DeletedAt.Time
+		if _, err := q.SoftDeleteSymbolEdgesByProject(ctx, postgres.SoftDeleteSymbolEdgesByProjectParams{
+			DeletedAt: deletedAt, ProjectID: project.ID,
+		}); err != nil {
+			return fmt.Errorf("soft-delete edges: %w", err)
+		}
+		if _, err := q.SoftDeleteSymbolsByProject(ctx, postgres.SoftDeleteSymbolsByProjectParams{
+			DeletedAt: deletedAt, ProjectID: project.ID,
+		}); err != nil {
+			return fmt.Errorf("soft-delete symbols: %w", err)
+		}
+		return nil
+	})
+	return project, err
+}
+
+// RestoreProjectCascade undoes SoftDeleteProjectCascade for a project deleted within the
+// retention window. When the slug has been reused it restores the latest deleted project
+// under it, and fails with ErrSlugTaken while a live project holds the slug.
+func (s *Store) RestoreProjectCascade(ctx context.Context, slug string, retention time.Duration, now time.Time) (postgres.Project, error) {
+	deleted, err := s.GetDeletedProject(ctx, slug)
+	if err != nil {
+		return deleted, err
+	}
+	if !WithinRetention(deleted.DeletedAt.Time, retention, now) {
+		return deleted, ErrRetentionElapsed
+	}
+	if _, err := s.GetProject(ctx, slug); err == nil {
+		return deleted, ErrSlugTaken
+	} else if !apierr.IsNotFound(err) {
+		return deleted, err
+	}
+
+	var project postgres.Project
+	err = s.WithTx(ctx, func(q *postgres.Queries) error {
+		deletedAt := deleted.DeletedAt.Time
+		if _, err := q.RestoreSymbolsByProject(ctx, postgres.RestoreSymbolsByProjectParams{
+			ProjectID: deleted.ID, DeletedAt: deletedAt,
+		}); err != nil {
+			return fmt.Errorf("restore symbols: %w", err)
+		}
+		if _, err := q.RestoreSymbolEdgesByProject(ctx, postgres.RestoreSymbolEdgesByProjectParams{
+			ProjectID: deleted.ID, DeletedAt: deletedAt,
+		}); err != nil {
+			return fmt.Errorf("restore edges: %w", err)
+		}
+		project, err = q.RestoreProject(ctx, deleted.ID)
+		if apierr.IsUniqueViolation(err) {
+			// A project took the slug between the check above and the update.
+			return ErrSlugTaken
+		}
+		return err
+	})
+	return project, err
+}
+
+// WithinRetention reports whether a row soft-deleted at deletedAt can still be restored.
+// A non-positive retention keeps soft-deleted rows forever.
+func WithinRetention(deletedAt time.Time, retention time.Duration, now time.Time) bool {
+	return retention <= 0 || now.Sub(deletedAt) <= retention
+}
+
+// PurgeResult counts the rows removed by one purge pass.
+type PurgeResult struct {
+	Projects []uuid.UUID
+	Symbols  int64
+	Edges    int64
+}
+
+// PurgeSoftDeleted hard-deletes projects, symbols and edges soft-deleted before the cutoff.
+func (s *Store) PurgeSoftDeleted(ctx context.Context, before time.Time) (PurgeResult, error) {
+	var res PurgeResult
+	var err error
+	if res.Edges, err = s.PurgeDeletedSymbolEdges(ctx, before); err != nil {
+		return res, fmt.Errorf("purge edges: %w", err)
+	}
+	if res.Symbols, err = s.PurgeDeletedSymbols(ctx, before); err != nil {
+		return res, fmt.Errorf("purge symbols: %w", err)
+	}
+	if res.Projects, err = s.PurgeDeletedProjects(ctx, before); err != nil {
+		return res, fmt.Errorf("purge projects: %w", err)
+	}
+	return res, nil
+}
+
+// RunPurge purges rows whose retention has elapsed every interval until ctx is done.
+// A non-positive retention disables purging.
+func (s *Store) RunPurge(ctx context.Context, retention, interval time.Duration, logger *slog.Logger) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		res, err := s.PurgeSoftDeleted(ctx, time.Now().Add(-retention))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Warn("soft-delete purge failed", slog.String("error", err.Error()))
+		case len(res.Projects) > 0 || res.Symbols > 0 || res.Edges > 0:
+			logger.Info("purged soft-deleted rows",
+				slog.Int("projects", len(res.Projects)),
+				slog.Int64("symbols", res.Symbols),
+				slog.Int64("edges", res.Edges))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
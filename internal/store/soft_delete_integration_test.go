@@ -0,0 +1,192 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+	"github.com/maraichr/lattice/pkg/apierr"
+)
+
+func setupStore(t *testing.T) *Store {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("postgres not available: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("postgres ping failed: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+	return New(pool)
+}
+
+// seedProject creates a project with two symbols joined by one edge.
+func seedProject(t *testing.T, s *Store) (postgres.Project, postgres.Symbol, postgres.SymbolEdge) {
+	t.Helper()
+	ctx := context.Background()
+	proj, err := s.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Soft Delete",
+		Slug: fmt.Sprintf("test-softdel-%s", uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", proj.ID)
+	})
+	source, err := s.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "src", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	file, err := s.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: proj.ID, SourceID: source.ID, Path: "a.sql", Language: "tsql", Hash: "h",
+	})
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	var syms []postgres.Symbol
+	for _, name := range []string{"dbo.A", "dbo.B"} {
+		sym, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+			ProjectID: proj.ID, FileID: file.ID, Name: name, QualifiedName: name,
+			Kind: "procedure", Language: "tsql", StartLine: 1, EndLine: 2,
+		})
+		if err != nil {
+			t.Fatalf("create symbol: %v", err)
+		}
+		syms = append(syms, sym)
+	}
+	edge, err := s.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{
+		ProjectID: proj.ID, SourceID: syms[0].ID, TargetID: syms[1].ID, EdgeType: "calls",
+	})
+	if err != nil {
+		t.Fatalf("create edge: %v", err)
+	}
+	return proj, syms[0], edge
+}
+
+func TestSoftDeleteHidesAndRestores(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	if _, err := s.SoftDeleteProjectCascade(ctx, proj.Slug); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if _, err := s.GetProject(ctx, proj.Slug); !apierr.IsNotFound(err) {
+		t.Fatalf("GetProject after soft delete: got %v, want not found", err)
+	}
+	if _, err := s.GetSymbol(ctx, sym.ID); !apierr.IsNotFound(err) {
+		t.Fatalf("GetSymbol after soft delete: got %v, want not found", err)
+	}
+	if edges, err := s.GetOutgoingEdges(ctx, sym.ID); err != nil || len(edges) != 0 {
+		t.Fatalf("GetOutgoingEdges after soft delete: %d edges, err %v", len(edges), err)
+	}
+
+	restored, err := s.RestoreProjectCascade(ctx, proj.Slug, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Fatal("restored project still marked deleted")
+	}
+	if _, err := s.GetSymbol(ctx, sym.ID); err != nil {
+		t.Fatalf("GetSymbol after restore: %v", err)
+	}
+	if edges, err := s.GetOutgoingEdges(ctx, sym.ID); err != nil || len(edges) != 1 {
+		t.Fatalf("GetOutgoingEdges after restore: %d edges, err %v", len(edges), err)
+	}
+}
+
+// A deleted project's slug can be reused, and the deleted project then cannot be restored
+// over the new one.
+func TestSoftDeleteSlugReuse(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, _, _ := seedProject(t, s)
+
+	if _, err := s.SoftDeleteProjectCascade(ctx, proj.Slug); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	reused, err := s.CreateProject(ctx, postgres.CreateProjectParams{Name: "Reused", Slug: proj.Slug})
+	if err != nil {
+		t.Fatalf("create project under a deleted slug: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Pool().Exec(ctx, "DELETE FROM projects WHERE id = $1", reused.ID)
+	})
+	if _, err := s.CreateProject(ctx, postgres.CreateProjectParams{Name: "Twin", Slug: proj.Slug}); !apierr.IsUniqueViolation(err) {
+		t.Fatalf("create second live project: got %v, want unique violation", err)
+	}
+
+	if _, err := s.RestoreProjectCascade(ctx, proj.Slug, time.Hour, time.Now()); !errors.Is(err, ErrSlugTaken) {
+		t.Fatalf("restore over a live project: got %v, want ErrSlugTaken", err)
+	}
+	got, err := s.GetProject(ctx, proj.Slug)
+	if err != nil || got.ID != reused.ID {
+		t.Fatalf("GetProject: got %v (%v), want the new project", got.ID, err)
+	}
+
+	if _, err := s.SoftDeleteProjectCascade(ctx, proj.Slug); err != nil {
+		t.Fatalf("soft delete new project: %v", err)
+	}
+	restored, err := s.RestoreProjectCascade(ctx, proj.Slug, time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.ID != reused.ID {
+		t.Fatalf("restored %v, want the latest deleted project %v", restored.ID, reused.ID)
+	}
+}
+
+func TestSoftDeletePurgedAfterRetention(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	if _, err := s.SoftDeleteProjectCascade(ctx, proj.Slug); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+
+	// Past the retention window the project can no longer be restored.
+	later := time.Now().Add(2 * time.Hour)
+	if _, err := s.RestoreProjectCascade(ctx, proj.Slug, time.Hour, later); !errors.Is(err, ErrRetentionElapsed) {
+		t.Fatalf("restore after retention: got %v, want ErrRetentionElapsed", err)
+	}
+
+	res, err := s.PurgeSoftDeleted(ctx, later.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	purged := false
+	for _, id := range res.Projects {
+		purged = purged || id == proj.ID
+	}
+	if !purged || res.Symbols < 2 || res.Edges < 1 {
+		t.Fatalf("unexpected purge result: %+v", res)
+	}
+
+	var n int
+	if err := s.Pool().QueryRow(ctx, "SELECT count(*) FROM symbols WHERE id = $1", sym.ID).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatal("symbol row survived purge")
+	}
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithinRetention(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		deletedAt time.Time
+		retention time.Duration
+		want      bool
+	}{
+		{"recent", now.Add(-time.Hour), 24 * time.Hour, true},
+		{"at boundary", now.Add(-24 * time.Hour), 24 * time.Hour, true},
+		{"elapsed", now.Add(-25 * time.Hour), 24 * time.Hour, false},
+		{"kept forever", now.Add(-1000 * time.Hour), 0, true},
+	}
+	for _, c := range cases {
+		if got := WithinRetention(c.deletedAt, c.retention, now); got != c.want {
+			t.Errorf("%s: WithinRetention = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -12,7 +12,8 @@ import (
 
 type Store struct {
 	*postgres.Queries
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	timeouts Timeouts
 }
 
 func New(pool *pgxpool.Pool) *Store {
@@ -22,19 +23,36 @@ func New(pool *pgxpool.Pool) *Store {
 	}
 }
 
+// NewWithTimeouts creates a store whose queries are cancelled by the server once they
+// exceed their statement_timeout budget, failing with ErrQueryTimeout.
+func NewWithTimeouts(pool *pgxpool.Pool, t Timeouts) *Store {
+	if t.Read <= 0 && t.Write <= 0 {
+		return New(pool)
+	}
+	return &Store{
+		Queries:  postgres.New(&timeoutDB{pool: pool, timeouts: t}),
+		pool:     pool,
+		timeouts: t,
+	}
+}
+
 func (s *Store) Pool() *pgxpool.Pool {
 	return s.pool
 }
 
 func (s *Store) WithTx(ctx context.Context, fn func(*postgres.Queries) error) error {
-	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	opts := pgx.TxOptions{}
+	if s.timeouts.Write > 0 {
+		opts = timeoutTxOptions(s.timeouts.Write)
+	}
+	tx, err := s.pool.BeginTx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
 	if err := fn(s.Queries.WithTx(tx)); err != nil {
-		return err
+		return translateTimeout(ctx, err, s.timeouts.Write)
 	}
 
 	return tx.Commit(ctx)
@@ -0,0 +1,396 @@
+// Package storetest is the conformance suite every store.Backend passes. Each backend's
+// tests call Run with a constructor for a ready backend.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maraichr/lattice/internal/graph"
+	"github.com/maraichr/lattice/internal/store"
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// SlugPrefix starts the slug of every project the suite creates, so a shared database
+// can be cleaned up afterwards.
+const SlugPrefix = "storetest-"
+
+// Run runs the suite. newBackend is called once per subtest; each subtest works in its
+// own project.
+func Run(t *testing.T, newBackend func(t *testing.T) store.Backend) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, b store.Backend)
+	}{
+		{"Projects", testProjects},
+		{"Files", testFiles},
+		{"SymbolUpsert", testSymbolUpsert},
+		{"SymbolLookup", testSymbolLookup},
+		{"Search", testSearch},
+		{"Edges", testEdges},
+		{"Analytics", testAnalytics},
+		{"DeleteByFile", testDeleteByFile},
+		{"LineageBFS", testLineageBFS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newBackend(t))
+		})
+	}
+}
+
+// fixture is a project with one source and one file.
+type fixture struct {
+	project postgres.Project
+	source  postgres.Source
+	file    postgres.File
+}
+
+func newFixture(t *testing.T, b store.Backend) fixture {
+	t.Helper()
+	ctx := context.Background()
+	proj, err := b.CreateProject(ctx, postgres.CreateProjectParams{
+		Name: "Store Test",
+		Slug: fmt.Sprintf("%s%s", SlugPrefix, uuid.NewString()[:8]),
+	})
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	source, err := b.CreateSource(ctx, postgres.CreateSourceParams{
+		ProjectID: proj.ID, Name: "src", SourceType: "upload", Config: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create source: %v", err)
+	}
+	file, err := b.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: proj.ID, SourceID: source.ID, Path: "db/orders.sql", Language: "tsql", Hash: "h1",
+	})
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	return fixture{project: proj, source: source, file: file}
+}
+
+func (f fixture) symbol(t *testing.T, b store.Backend, qualified, kind string) postgres.Symbol {
+	t.Helper()
+	name := qualified
+	if i := strings.LastIndex(qualified, "."); i >= 0 {
+		name = qualified[i+1:]
+	}
+	sym, err := b.CreateSymbol(context.Background(), postgres.CreateSymbolParams{
+		ProjectID: f.project.ID, FileID: f.file.ID, Name: name, QualifiedName: qualified,
+		Kind: kind, Language: "tsql", StartLine: 1, EndLine: 10,
+	})
+	if err != nil {
+		t.Fatalf("create symbol %s: %v", qualified, err)
+	}
+	return sym
+}
+
+func (f fixture) link(t *testing.T, b store.Backend, from, to postgres.Symbol, edgeType string) {
+	t.Helper()
+	if _, err := b.CreateSymbolEdge(context.Background(), postgres.CreateSymbolEdgeParams{
+		ProjectID: f.project.ID, SourceID: from.ID, TargetID: to.ID, EdgeType: edgeType,
+	}); err != nil {
+		t.Fatalf("create edge: %v", err)
+	}
+}
+
+func testProjects(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+
+	got, err := b.GetProject(ctx, f.project.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != f.project.ID || got.Name != "Store Test" || got.CreatedAt.IsZero() || got.DeletedAt.Valid {
+		t.Errorf("unexpected project: %+v", got)
+	}
+	if _, err := b.GetProject(ctx, SlugPrefix+"missing"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing project, got %v", err)
+	}
+	if f.source.ProjectID != f.project.ID || f.source.SourceType != "upload" {
+		t.Errorf("unexpected source: %+v", f.source)
+	}
+	sources, err := b.ListSourcesByProjectID(ctx, f.project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 1 || sources[0].ID != f.source.ID || sources[0].Name != "src" {
+		t.Errorf("expected the project's source, got %+v", sources)
+	}
+}
+
+func testFiles(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+
+	again, err := b.UpsertFile(ctx, postgres.UpsertFileParams{
+		ProjectID: f.project.ID, SourceID: f.source.ID, Path: f.file.Path, Language: "tsql",
+		SizeBytes: 42, Hash: "h2", Tags: []string{"generated"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != f.file.ID {
+		t.Errorf("expected the upsert to keep the file id")
+	}
+
+	got, err := b.GetFileByPath(ctx, postgres.GetFileByPathParams{ProjectID: f.project.ID, SourceID: f.source.ID, Path: f.file.Path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hash != "h2" || got.SizeBytes != 42 || !slices.Equal(got.Tags, []string{"generated"}) || !got.LastIndexedAt.Valid {
+		t.Errorf("unexpected file after upsert: %+v", got)
+	}
+	if _, err := b.GetFileByPath(ctx, postgres.GetFileByPathParams{ProjectID: f.project.ID, SourceID: f.source.ID, Path: "nope.sql"}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing file, got %v", err)
+	}
+}
+
+func testSymbolUpsert(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+
+	first := f.symbol(t, b, "dbo.usp_Orders", "procedure")
+	sig := "CREATE PROCEDURE dbo.usp_Orders @id int"
+	col := int32(4)
+	second, err := b.CreateSymbol(ctx, postgres.CreateSymbolParams{
+		ProjectID: f.project.ID, FileID: f.file.ID, Name: "usp_Orders", QualifiedName: "dbo.usp_Orders",
+		Kind: "procedure", Language: "tsql", StartLine: 5, EndLine: 20, StartCol: &col, Signature: &sig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("expected the same (qualified name, kind) to upsert into one symbol")
+	}
+	if second.StartLine != 5 || second.StartCol == nil || *second.StartCol != 4 || second.Signature == nil || *second.Signature != sig || second.EndCol != nil {
+		t.Errorf("unexpected upserted symbol: %+v", second)
+	}
+
+	// A different kind under the same name is a separate symbol.
+	f.symbol(t, b, "dbo.usp_Orders", "table")
+	if n, err := b.CountSymbolsByProject(ctx, f.project.ID); err != nil || n != 2 {
+		t.Errorf("expected 2 symbols, got %d (%v)", n, err)
+	}
+}
+
+func testSymbolLookup(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	orders := f.symbol(t, b, "dbo.Orders", "table")
+	f.symbol(t, b, "dbo.Customers", "table")
+	f.symbol(t, b, "sales.Orders", "view")
+
+	got, err := b.GetSymbol(ctx, orders.ID)
+	if err != nil || got.QualifiedName != "dbo.Orders" {
+		t.Errorf("GetSymbol: %+v, %v", got, err)
+	}
+	if _, err := b.GetSymbol(ctx, uuid.New()); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing symbol, got %v", err)
+	}
+
+	got, err = b.GetSymbolByQualifiedName(ctx, postgres.GetSymbolByQualifiedNameParams{ProjectID: f.project.ID, QualifiedName: "dbo.Orders"})
+	if err != nil || got.ID != orders.ID {
+		t.Errorf("GetSymbolByQualifiedName: %+v, %v", got, err)
+	}
+
+	byName, err := b.ListSymbolsByNames(ctx, postgres.ListSymbolsByNamesParams{ProjectID: f.project.ID, Column2: []string{"Orders", "Missing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range byName {
+		names = append(names, s.QualifiedName)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"dbo.Orders", "sales.Orders"}) {
+		t.Errorf("ListSymbolsByNames: got %v", names)
+	}
+}
+
+func testSearch(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	f.symbol(t, b, "dbo.OrdersArchive", "table")
+	exact := f.symbol(t, b, "dbo.Orders", "table")
+	f.symbol(t, b, "dbo.usp_GetOrders", "procedure")
+	popular := f.symbol(t, b, "dbo.usp_SaveOrders", "procedure")
+	f.symbol(t, b, "dbo.Customers", "table")
+	if err := b.UpdateSymbolMetadata(ctx, postgres.UpdateSymbolMetadataParams{SymbolID: popular.ID, AnalyticsJson: []byte(`{"in_degree": 7}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string, kinds, languages []string) []string {
+		t.Helper()
+		rows, err := b.SearchSymbolsRanked(ctx, postgres.SearchSymbolsRankedParams{
+			ProjectSlug: f.project.Slug, Query: &query, Kinds: kinds, Languages: languages, Lim: 10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, s := range rows {
+			out = append(out, s.QualifiedName)
+		}
+		return out
+	}
+
+	got := search("orders", nil, nil)
+	if len(got) != 4 || got[0] != exact.QualifiedName || got[1] != "dbo.OrdersArchive" || got[2] != popular.QualifiedName {
+		t.Errorf("expected exact, prefix, then substring matches by in-degree, got %v", got)
+	}
+	if got := search("orders", []string{"procedure"}, nil); len(got) != 2 {
+		t.Errorf("expected the kind filter to keep 2 procedures, got %v", got)
+	}
+	if got := search("orders", nil, []string{"csharp"}); len(got) != 0 {
+		t.Errorf("expected the language filter to drop everything, got %v", got)
+	}
+}
+
+func testEdges(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	proc := f.symbol(t, b, "dbo.usp_Orders", "procedure")
+	table := f.symbol(t, b, "dbo.Orders", "table")
+	f.link(t, b, proc, table, "reads_from")
+
+	_, err := b.CreateSymbolEdge(ctx, postgres.CreateSymbolEdgeParams{ProjectID: f.project.ID, SourceID: proc.ID, TargetID: table.ID, EdgeType: "reads_from"})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected a duplicate edge to return pgx.ErrNoRows, got %v", err)
+	}
+
+	withMeta, err := b.CreateSymbolEdgeWithMetadata(ctx, postgres.CreateSymbolEdgeWithMetadataParams{
+		ProjectID: f.project.ID, SourceID: proc.ID, TargetID: table.ID, EdgeType: "reads_from", Metadata: []byte(`{"columns": ["id"]}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(withMeta.Metadata, &meta); err != nil || meta["columns"] == nil {
+		t.Errorf("expected the edge metadata to be replaced, got %s (%v)", withMeta.Metadata, err)
+	}
+
+	out, err := b.GetOutgoingEdges(ctx, proc.ID)
+	if err != nil || len(out) != 1 || out[0].TargetID != table.ID || out[0].EdgeType != "reads_from" {
+		t.Errorf("GetOutgoingEdges: %+v, %v", out, err)
+	}
+	in, err := b.GetIncomingEdges(ctx, table.ID)
+	if err != nil || len(in) != 1 || in[0].SourceID != proc.ID {
+		t.Errorf("GetIncomingEdges: %+v, %v", in, err)
+	}
+	if n, err := b.CountEdgesByProject(ctx, f.project.ID); err != nil || n != 1 {
+		t.Errorf("expected 1 edge, got %d (%v)", n, err)
+	}
+}
+
+func testAnalytics(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	a := f.symbol(t, b, "dbo.usp_A", "procedure")
+	bb := f.symbol(t, b, "dbo.usp_B", "procedure")
+	table := f.symbol(t, b, "dbo.Orders", "table")
+	f.link(t, b, a, table, "reads_from")
+	f.link(t, b, bb, table, "writes_to")
+	f.link(t, b, a, bb, "calls")
+
+	edges, err := b.GetEdgeList(ctx, f.project.ID)
+	if err != nil || len(edges) != 3 {
+		t.Errorf("GetEdgeList: %d edges, %v", len(edges), err)
+	}
+
+	degrees, err := b.GetSymbolDegrees(ctx, f.project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[uuid.UUID]postgres.GetSymbolDegreesRow)
+	for _, d := range degrees {
+		byID[d.ID] = d
+	}
+	if d := byID[a.ID]; d.InDegree != 0 || d.OutDegree != 2 {
+		t.Errorf("usp_A degrees: %+v", d)
+	}
+	if d := byID[table.ID]; d.InDegree != 2 || d.OutDegree != 0 {
+		t.Errorf("Orders degrees: %+v", d)
+	}
+
+	kinds, err := b.GetSymbolCountsByKind(ctx, f.project.ID)
+	if err != nil || len(kinds) != 2 || kinds[0].Kind != "procedure" || kinds[0].Cnt != 2 {
+		t.Errorf("GetSymbolCountsByKind: %+v, %v", kinds, err)
+	}
+	langs, err := b.GetSymbolCountsByLanguage(ctx, f.project.ID)
+	if err != nil || len(langs) != 1 || langs[0].Language != "tsql" || langs[0].Cnt != 3 {
+		t.Errorf("GetSymbolCountsByLanguage: %+v, %v", langs, err)
+	}
+
+	if err := b.UpdateSymbolMetadata(ctx, postgres.UpdateSymbolMetadataParams{SymbolID: table.ID, AnalyticsJson: []byte(`{"in_degree": 2}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UpdateSymbolMetadata(ctx, postgres.UpdateSymbolMetadataParams{SymbolID: table.ID, AnalyticsJson: []byte(`{"pagerank": 0.5}`)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.GetSymbol(ctx, table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta map[string]float64
+	if err := json.Unmarshal(got.Metadata, &meta); err != nil || meta["in_degree"] != 2 || meta["pagerank"] != 0.5 {
+		t.Errorf("expected metadata updates to merge, got %s (%v)", got.Metadata, err)
+	}
+}
+
+func testDeleteByFile(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	proc := f.symbol(t, b, "dbo.usp_Orders", "procedure")
+	table := f.symbol(t, b, "dbo.Orders", "table")
+	f.link(t, b, proc, table, "reads_from")
+
+	if err := b.DeleteSymbolsByFileID(ctx, f.file.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := b.CountSymbolsByProject(ctx, f.project.ID); err != nil || n != 0 {
+		t.Errorf("expected no symbols, got %d (%v)", n, err)
+	}
+	if n, err := b.CountEdgesByProject(ctx, f.project.ID); err != nil || n != 0 {
+		t.Errorf("expected the edges to go with their symbols, got %d (%v)", n, err)
+	}
+}
+
+func testLineageBFS(t *testing.T, b store.Backend) {
+	ctx := context.Background()
+	f := newFixture(t, b)
+	raw := f.symbol(t, b, "stage.RawOrders", "table")
+	load := f.symbol(t, b, "dbo.usp_LoadOrders", "procedure")
+	orders := f.symbol(t, b, "dbo.Orders", "table")
+	report := f.symbol(t, b, "dbo.usp_Report", "procedure")
+	f.link(t, b, load, raw, "reads_from")
+	f.link(t, b, load, orders, "writes_to")
+	f.link(t, b, report, orders, "reads_from")
+
+	result, err := graph.LineageBFS(ctx, b, orders.ID, "upstream", 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, n := range result.Nodes {
+		names = append(names, n.QualifiedName)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"dbo.Orders", "dbo.usp_LoadOrders", "dbo.usp_Report"}) {
+		t.Errorf("upstream of dbo.Orders: got %v", names)
+	}
+	if len(result.Edges) != 2 {
+		t.Errorf("expected 2 edges, got %+v", result.Edges)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/maraichr/lattice/internal/store/postgres"
+)
+
+// Re-indexing a file recreates its symbols under new IDs; their usage must carry over.
+func TestSymbolUsageSurvivesReindex(t *testing.T) {
+	s := setupStore(t)
+	ctx := context.Background()
+	proj, sym, _ := seedProject(t, s)
+
+	if err := s.IncrementSymbolUsage(ctx, postgres.IncrementSymbolUsageParams{
+		SymbolIds: []uuid.UUID{sym.ID}, Hits: []int64{3},
+	}); err != nil {
+		t.Fatalf("increment usage: %v", err)
+	}
+
+	if err := s.DeleteSymbolsByFile(ctx, sym.FileID); err != nil {
+		t.Fatalf("delete symbols: %v", err)
+	}
+	recreated, err := s.CreateSymbol(ctx, postgres.CreateSymbolParams{
+		ProjectID: proj.ID, FileID: sym.FileID, Name: sym.Name, QualifiedName: sym.QualifiedName,
+		Kind: sym.Kind, Language: sym.Language, StartLine: 1, EndLine: 2,
+	})
+	if err != nil {
+		t.Fatalf("recreate symbol: %v", err)
+	}
+
+	hot, err := s.ListHotSymbols(ctx, postgres.ListHotSymbolsParams{ProjectID: proj.ID, Days: 7, Lim: 10})
+	if err != nil {
+		t.Fatalf("list hot symbols: %v", err)
+	}
+	if len(hot) != 1 || hot[0].ID != recreated.ID || hot[0].Hits != 3 {
+		t.Fatalf("expected the recreated symbol with its 3 hits, got %+v", hot)
+	}
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrQueryTimeout is returned when the server cancels a statement for exceeding the
+// store's statement_timeout budget.
+var ErrQueryTimeout = errors.New("query exceeded the statement timeout")
+
+// sqlstateQueryCanceled is raised both for statement_timeout and for client cancels.
+const sqlstateQueryCanceled = "57014"
+
+// Timeouts are the statement_timeout budgets applied to store queries. A zero budget
+// leaves that class of query unbounded.
+type Timeouts struct {
+	Read  time.Duration // SELECT statements
+	Write time.Duration // inserts, updates, deletes and WithTx transactions
+}
+
+// txBeginner is the part of the connection pool timeoutDB needs.
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// timeoutDB runs each statement in its own transaction opened with
+// SET LOCAL statement_timeout, so a runaway query is cancelled by the server instead
+// of outliving the request that issued it.
+type timeoutDB struct {
+	pool     txBeginner
+	timeouts Timeouts
+}
+
+func (db *timeoutDB) budget(sql string) time.Duration {
+	if isReadQuery(sql) {
+		return db.timeouts.Read
+	}
+	return db.timeouts.Write
+}
+
+func (db *timeoutDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	budget := db.budget(sql)
+	if budget <= 0 {
+		return db.pool.Exec(ctx, sql, args...)
+	}
+	tx, err := db.pool.BeginTx(ctx, timeoutTxOptions(budget))
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := tx.Exec(ctx, sql, args...)
+	return tag, translateTimeout(ctx, finishTx(ctx, tx, err), budget)
+}
+
+func (db *timeoutDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	budget := db.budget(sql)
+	if budget <= 0 {
+		return db.pool.Query(ctx, sql, args...)
+	}
+	tx, err := db.pool.BeginTx(ctx, timeoutTxOptions(budget))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, translateTimeout(ctx, finishTx(ctx, tx, err), budget)
+	}
+	return &timeoutRows{Rows: rows, ctx: ctx, tx: tx, budget: budget}, nil
+}
+
+func (db *timeoutDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	budget := db.budget(sql)
+	if budget <= 0 {
+		return db.pool.QueryRow(ctx, sql, args...)
+	}
+	tx, err := db.pool.BeginTx(ctx, timeoutTxOptions(budget))
+	if err != nil {
+		return errRow{err}
+	}
+	return &timeoutRow{row: tx.QueryRow(ctx, sql, args...), ctx: ctx, tx: tx, budget: budget}
+}
+
+// timeoutRows commits the statement's transaction when the rows are closed.
+type timeoutRows struct {
+	pgx.Rows
+	ctx    context.Context
+	tx     pgx.Tx
+	budget time.Duration
+	done   bool
+	err    error
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	if !r.done {
+		r.done = true
+		r.err = translateTimeout(r.ctx, finishTx(r.ctx, r.tx, r.Rows.Err()), r.budget)
+	}
+}
+
+func (r *timeoutRows) Err() error {
+	if r.done {
+		return r.err
+	}
+	return translateTimeout(r.ctx, r.Rows.Err(), r.budget)
+}
+
+// timeoutRow commits the statement's transaction once the row is scanned.
+type timeoutRow struct {
+	row    pgx.Row
+	ctx    context.Context
+	tx     pgx.Tx
+	budget time.Duration
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	return translateTimeout(r.ctx, finishTx(r.ctx, r.tx, err), r.budget)
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// timeoutTxOptions begins a transaction and sets its statement_timeout in a single
+// round trip.
+func timeoutTxOptions(budget time.Duration) pgx.TxOptions {
+	return pgx.TxOptions{BeginQuery: fmt.Sprintf("BEGIN; SET LOCAL statement_timeout = %d", budget.Milliseconds())}
+}
+
+// finishTx commits tx when the statement succeeded and rolls it back otherwise,
+// returning the statement's error in preference to the commit's.
+func finishTx(ctx context.Context, tx pgx.Tx, err error) error {
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// translateTimeout turns a server-side statement_timeout cancellation into
+// ErrQueryTimeout. Cancellations caused by the caller's context are left alone.
+func translateTimeout(ctx context.Context, err error, budget time.Duration) error {
+	var pgErr *pgconn.PgError
+	if err == nil || ctx.Err() != nil || !errors.As(err, &pgErr) || pgErr.Code != sqlstateQueryCanceled {
+		return err
+	}
+	return fmt.Errorf("%w (%s): %w", ErrQueryTimeout, budget, err)
+}
+
+// isReadQuery reports whether a statement only reads, judged by its first keyword
+// after any leading "-- name:" comments. Data-modifying CTEs count as writes.
+func isReadQuery(sql string) bool {
+	s := strings.TrimSpace(sql)
+	for strings.HasPrefix(s, "--") {
+		nl := strings.IndexByte(s, '\n')
+		if nl < 0 {
+			return false
+		}
+		s = strings.TrimSpace(s[nl+1:])
+	}
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.HasPrefix(upper, "SELECT"):
+		return true
+	case strings.HasPrefix(upper, "WITH"):
+		for _, verb := range []string{"INSERT ", "UPDATE ", "DELETE "} {
+			if strings.Contains(upper, verb) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+//go:build integration
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatementTimeoutCancelsSlowQuery(t *testing.T) {
+	base := setupStore(t)
+	s := NewWithTimeouts(base.Pool(), Timeouts{Read: 100 * time.Millisecond, Write: 10 * time.Second})
+	db := &timeoutDB{pool: s.Pool(), timeouts: s.timeouts}
+	ctx := context.Background()
+
+	start := time.Now()
+	err := db.QueryRow(ctx, "SELECT pg_sleep(5)").Scan(new(any))
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("query was not cancelled server-side: took %s", elapsed)
+	}
+
+	// Queries within budget are unaffected.
+	if _, err := s.CountProjects(ctx); err != nil {
+		t.Fatalf("count projects: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeTimeoutTx fails every statement the way Postgres does when statement_timeout fires.
+type fakeTimeoutTx struct {
+	pgx.Tx
+	committed, rolledBack bool
+}
+
+var errStatementTimeout = &pgconn.PgError{Code: sqlstateQueryCanceled, Message: "canceling statement due to statement timeout"}
+
+func (t *fakeTimeoutTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errStatementTimeout
+}
+
+func (t *fakeTimeoutTx) QueryRow(context.Context, string, ...any) pgx.Row {
+	return errRow{errStatementTimeout}
+}
+
+func (t *fakeTimeoutTx) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errStatementTimeout
+}
+
+func (t *fakeTimeoutTx) Commit(context.Context) error   { t.committed = true; return nil }
+func (t *fakeTimeoutTx) Rollback(context.Context) error { t.rolledBack = true; return nil }
+
+// fakeBeginner hands out fakeTimeoutTx and records how each was begun.
+type fakeBeginner struct {
+	txBeginner
+	begins []string
+	tx     *fakeTimeoutTx
+}
+
+func (b *fakeBeginner) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	b.begins = append(b.begins, opts.BeginQuery)
+	b.tx = &fakeTimeoutTx{}
+	return b.tx, nil
+}
+
+func TestTimeoutDB_ReturnsTypedErrorOnStatementTimeout(t *testing.T) {
+	pool := &fakeBeginner{}
+	db := &timeoutDB{pool: pool, timeouts: Timeouts{Read: 250 * time.Millisecond, Write: 5 * time.Second}}
+	ctx := context.Background()
+
+	var n int
+	err := db.QueryRow(ctx, "-- name: SlowRead :one\nSELECT count(*) FROM symbols").Scan(&n)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("read: expected ErrQueryTimeout, got %v", err)
+	}
+	if got := pool.begins[0]; got != "BEGIN; SET LOCAL statement_timeout = 250" {
+		t.Errorf("read: expected the read budget, began with %q", got)
+	}
+	if !pool.tx.rolledBack || pool.tx.committed {
+		t.Error("read: expected the failed statement's transaction to be rolled back")
+	}
+
+	_, err = db.Exec(ctx, "-- name: SlowWrite :exec\nDELETE FROM symbols WHERE project_id = $1", 1)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("write: expected ErrQueryTimeout, got %v", err)
+	}
+	if got := pool.begins[1]; got != "BEGIN; SET LOCAL statement_timeout = 5000" {
+		t.Errorf("write: expected the write budget, began with %q", got)
+	}
+
+	if _, err := db.Query(ctx, "SELECT 1"); !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("query: expected ErrQueryTimeout, got %v", err)
+	}
+}
+
+func TestTimeoutDB_CallerCancelIsNotATimeout(t *testing.T) {
+	db := &timeoutDB{pool: &fakeBeginner{}, timeouts: Timeouts{Read: time.Second}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := db.QueryRow(ctx, "SELECT 1").Scan(new(int))
+	if errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("a cancelled request must not be reported as a timeout: %v", err)
+	}
+}
+
+func TestIsReadQuery(t *testing.T) {
+	tests := map[string]bool{
+		"-- name: GetSymbol :one\nSELECT * FROM symbols WHERE id = $1":                     true,
+		"WITH recent AS (SELECT id FROM index_runs) SELECT * FROM recent":                  true,
+		"-- name: CreateProject :one\nINSERT INTO projects (name) VALUES ($1) RETURNING *": false,
+		"WITH gone AS (DELETE FROM symbols RETURNING id) SELECT count(*) FROM gone":        false,
+		"UPDATE projects SET name = $1":                                                    false,
+	}
+	for sql, want := range tests {
+		if got := isReadQuery(sql); got != want {
+			t.Errorf("isReadQuery(%q) = %v, want %v", sql, got, want)
+		}
+	}
+}
@@ -11,7 +11,9 @@ CREATE INDEX symbol_name IF NOT EXISTS FOR (s:Symbol) ON (s.name);
 CREATE INDEX symbol_kind IF NOT EXISTS FOR (s:Symbol) ON (s.kind);
 CREATE INDEX symbol_language IF NOT EXISTS FOR (s:Symbol) ON (s.language);
 CREATE INDEX symbol_qualified_name IF NOT EXISTS FOR (s:Symbol) ON (s.qualifiedName);
+CREATE INDEX symbol_project IF NOT EXISTS FOR (s:Symbol) ON (s.projectId);
 CREATE INDEX file_path IF NOT EXISTS FOR (f:File) ON (f.path);
+CREATE INDEX file_project IF NOT EXISTS FOR (f:File) ON (f.projectId);
 
 // Full-text search indexes
 CREATE FULLTEXT INDEX symbol_search IF NOT EXISTS FOR (s:Symbol) ON EACH [s.name, s.qualifiedName];
@@ -0,0 +1,3 @@
+-- 000007_unresolved_references.down.sql
+
+DROP TABLE IF EXISTS unresolved_references;
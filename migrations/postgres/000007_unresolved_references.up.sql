@@ -0,0 +1,18 @@
+-- 000007_unresolved_references.up.sql
+-- References the resolver could not match to a symbol, kept per file so tools can
+-- explain missing edges ("why isn't X linked to Y").
+
+CREATE TABLE unresolved_references (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id     UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    file_id        UUID NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    source_id      UUID NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    to_name        TEXT NOT NULL,
+    to_qualified   TEXT NOT NULL DEFAULT '',
+    reference_type TEXT NOT NULL,
+    line           INT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_unresolved_references_file_id ON unresolved_references(file_id);
+CREATE INDEX idx_unresolved_references_to_name ON unresolved_references(project_id, lower(to_name));
@@ -0,0 +1,3 @@
+-- 000008_project_readiness.down.sql
+
+ALTER TABLE projects DROP COLUMN IF EXISTS readiness;
@@ -0,0 +1,5 @@
+-- 000008_project_readiness.up.sql
+-- Tracks how far the latest ingest has progressed so read tools can flag incomplete data.
+-- Values: indexing, resolving, analytics_pending, ready.
+
+ALTER TABLE projects ADD COLUMN readiness TEXT NOT NULL DEFAULT 'ready';
@@ -0,0 +1,3 @@
+-- 000009_schema_snapshots.down.sql
+
+DROP TABLE IF EXISTS schema_snapshots;
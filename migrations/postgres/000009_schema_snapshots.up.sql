@@ -0,0 +1,12 @@
+-- 000009_schema_snapshots.up.sql
+-- The project's tables, columns and foreign keys as they stood at the end of each index run,
+-- so schema_diff can compare two runs after symbols have been overwritten in place.
+
+CREATE TABLE schema_snapshots (
+    index_run_id UUID PRIMARY KEY REFERENCES index_runs(id) ON DELETE CASCADE,
+    project_id   UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    snapshot     JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_schema_snapshots_project_id ON schema_snapshots(project_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- 000010_file_tags.down.sql
+
+ALTER TABLE files DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,5 @@
+-- 000010_file_tags.up.sql
+-- Free-form labels the parse stage attaches to a file, e.g. "oversized" when its symbols
+-- were capped by PARSER_MAX_SYMBOLS_PER_FILE.
+
+ALTER TABLE files ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
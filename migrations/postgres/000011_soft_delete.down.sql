@@ -0,0 +1,9 @@
+-- 000011_soft_delete.down.sql
+
+DROP INDEX IF EXISTS idx_symbol_edges_deleted_at;
+DROP INDEX IF EXISTS idx_symbols_deleted_at;
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+
+ALTER TABLE symbol_edges DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE symbols DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+-- 000011_soft_delete.up.sql
+-- Deleting a project stamps deleted_at on the project and its symbols and edges instead of
+-- removing them. Reads skip stamped rows; an admin can restore the project within the
+-- retention window (SOFT_DELETE_RETENTION), after which the purge job hard-deletes it.
+
+ALTER TABLE projects ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE symbols ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE symbol_edges ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_projects_deleted_at ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_symbols_deleted_at ON symbols(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_symbol_edges_deleted_at ON symbol_edges(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- 000012_project_templates.down.sql
+
+DROP TABLE IF EXISTS project_templates;
@@ -0,0 +1,12 @@
+-- 000012_project_templates.up.sql
+-- Named settings bundles that new projects can be created from. Each top-level key of
+-- settings is a templated section; applying a template overwrites only those sections.
+
+CREATE TABLE project_templates (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT,
+    settings    JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
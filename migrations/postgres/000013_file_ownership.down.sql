@@ -0,0 +1,4 @@
+-- 000013_file_ownership.down.sql
+
+ALTER TABLE files DROP COLUMN IF EXISTS last_commit_at;
+ALTER TABLE files DROP COLUMN IF EXISTS last_author;
@@ -0,0 +1,6 @@
+-- 000013_file_ownership.up.sql
+-- Author and date of the last commit touching each file, recorded from git history for
+-- projects with track_ownership enabled and shown by MCP tools with include_ownership.
+
+ALTER TABLE files ADD COLUMN last_author TEXT;
+ALTER TABLE files ADD COLUMN last_commit_at TIMESTAMPTZ;
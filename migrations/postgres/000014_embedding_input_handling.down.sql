@@ -0,0 +1,3 @@
+-- 000014_embedding_input_handling.down.sql
+
+ALTER TABLE symbol_embeddings DROP COLUMN IF EXISTS input_handling;
@@ -0,0 +1,5 @@
+-- 000014_embedding_input_handling.up.sql
+-- How a symbol's text was fitted to the embedding model's input limit: 'truncated' or
+-- 'chunked' (embedded in pieces and averaged). NULL when the whole text was embedded.
+
+ALTER TABLE symbol_embeddings ADD COLUMN input_handling TEXT;
@@ -0,0 +1,3 @@
+-- 000015_project_schedule_runs.down.sql
+
+DROP TABLE IF EXISTS project_schedule_runs;
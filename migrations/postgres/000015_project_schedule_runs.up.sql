@@ -0,0 +1,9 @@
+-- 000015_project_schedule_runs.up.sql
+-- When the scheduler last enqueued a project's scheduled index run. Projects opt in with
+-- a cron expression in settings.index_schedule; a run missed while the scheduler was down
+-- is caught up on its next tick.
+
+CREATE TABLE project_schedule_runs (
+    project_id  UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    last_run_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,3 @@
+-- 000016_project_metadata.down.sql
+
+DROP TABLE IF EXISTS project_metadata;
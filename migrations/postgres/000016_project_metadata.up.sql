@@ -0,0 +1,13 @@
+-- 000016_project_metadata.up.sql
+-- Facts about a project derived from its sources at ingest, such as the TypeScript path
+-- aliases of its tsconfig.json files, keyed per source so each index run replaces only
+-- what its own source contributed.
+
+CREATE TABLE project_metadata (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    source_id  UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    key        TEXT NOT NULL,
+    value      JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (project_id, source_id, key)
+);
@@ -0,0 +1,3 @@
+-- 000017_project_archive.down.sql
+
+ALTER TABLE projects DROP COLUMN IF EXISTS archived_at;
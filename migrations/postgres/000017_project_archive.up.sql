@@ -0,0 +1,6 @@
+-- 000017_project_archive.up.sql
+-- Archiving a project stamps archived_at: it stays queryable but refuses index runs and
+-- changes until it is unarchived. Its Neo4j subgraph may be evicted meanwhile, so lineage
+-- over an archived project walks symbol_edges instead.
+
+ALTER TABLE projects ADD COLUMN archived_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- 000018_symbol_usage.down.sql
+
+DROP TABLE IF EXISTS symbol_usage;
@@ -0,0 +1,12 @@
+-- 000018_symbol_usage.up.sql
+-- How often each symbol is shown in MCP tool responses, per day. Only counts are kept:
+-- nothing about who asked or what the response said.
+
+CREATE TABLE symbol_usage (
+    symbol_id UUID NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    day       DATE NOT NULL,
+    hits      BIGINT NOT NULL,
+    PRIMARY KEY (symbol_id, day)
+);
+
+CREATE INDEX idx_symbol_usage_day ON symbol_usage (day);
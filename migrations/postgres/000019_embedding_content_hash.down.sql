@@ -0,0 +1,11 @@
+-- 000019_embedding_content_hash.down.sql
+
+DELETE FROM symbol_embeddings WHERE symbol_id IS NULL;
+
+DROP INDEX IF EXISTS idx_symbol_embeddings_project_hash;
+ALTER TABLE symbol_embeddings DROP CONSTRAINT symbol_embeddings_symbol_id_fkey;
+ALTER TABLE symbol_embeddings ADD CONSTRAINT symbol_embeddings_symbol_id_fkey
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE;
+ALTER TABLE symbol_embeddings ALTER COLUMN symbol_id SET NOT NULL;
+ALTER TABLE symbol_embeddings DROP COLUMN IF EXISTS project_id;
+ALTER TABLE symbol_embeddings DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,20 @@
+-- 000019_embedding_content_hash.up.sql
+-- A hash of the symbol content each vector was computed from, so re-indexing reuses
+-- vectors instead of embedding unchanged symbols again. Re-indexing a file deletes and
+-- recreates its symbols, so vectors now outlive them: a deleted symbol's vector is kept
+-- with a NULL symbol_id until the embed stage copies it to the recreated symbol or
+-- prunes it.
+
+ALTER TABLE symbol_embeddings ADD COLUMN content_hash TEXT;
+ALTER TABLE symbol_embeddings ADD COLUMN project_id UUID REFERENCES projects(id) ON DELETE CASCADE;
+
+UPDATE symbol_embeddings se SET project_id = s.project_id
+FROM symbols s WHERE s.id = se.symbol_id;
+
+ALTER TABLE symbol_embeddings ALTER COLUMN project_id SET NOT NULL;
+ALTER TABLE symbol_embeddings ALTER COLUMN symbol_id DROP NOT NULL;
+ALTER TABLE symbol_embeddings DROP CONSTRAINT symbol_embeddings_symbol_id_fkey;
+ALTER TABLE symbol_embeddings ADD CONSTRAINT symbol_embeddings_symbol_id_fkey
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_symbol_embeddings_project_hash ON symbol_embeddings (project_id, model, content_hash);
@@ -0,0 +1,20 @@
+-- 000020_symbol_usage_by_name.down.sql
+-- Counts of symbols no longer in the index are dropped: they have no ID to key them by.
+
+CREATE TABLE symbol_usage_by_id (
+    symbol_id UUID NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    day       DATE NOT NULL,
+    hits      BIGINT NOT NULL,
+    PRIMARY KEY (symbol_id, day)
+);
+
+INSERT INTO symbol_usage_by_id (symbol_id, day, hits)
+SELECT s.id, u.day, u.hits
+FROM symbol_usage u
+JOIN symbols s ON s.project_id = u.project_id AND s.qualified_name = u.qualified_name AND s.kind = u.kind;
+
+DROP TABLE symbol_usage;
+ALTER TABLE symbol_usage_by_id RENAME TO symbol_usage;
+ALTER INDEX symbol_usage_by_id_pkey RENAME TO symbol_usage_pkey;
+
+CREATE INDEX idx_symbol_usage_day ON symbol_usage (day);
@@ -0,0 +1,25 @@
+-- 000020_symbol_usage_by_name.up.sql
+-- Symbol usage is kept by project, qualified name and kind rather than symbol ID.
+-- Re-indexing a file deletes and recreates its symbols under new IDs, which cascaded to
+-- their usage and wiped the history of every re-indexed symbol.
+
+CREATE TABLE symbol_usage_by_name (
+    project_id     UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    qualified_name TEXT NOT NULL,
+    kind           TEXT NOT NULL,
+    day            DATE NOT NULL,
+    hits           BIGINT NOT NULL,
+    PRIMARY KEY (project_id, qualified_name, kind, day)
+);
+
+INSERT INTO symbol_usage_by_name (project_id, qualified_name, kind, day, hits)
+SELECT s.project_id, s.qualified_name, s.kind, u.day, sum(u.hits)
+FROM symbol_usage u
+JOIN symbols s ON s.id = u.symbol_id
+GROUP BY s.project_id, s.qualified_name, s.kind, u.day;
+
+DROP TABLE symbol_usage;
+ALTER TABLE symbol_usage_by_name RENAME TO symbol_usage;
+ALTER INDEX symbol_usage_by_name_pkey RENAME TO symbol_usage_pkey;
+
+CREATE INDEX idx_symbol_usage_day ON symbol_usage (day);
@@ -0,0 +1,6 @@
+-- 000021_project_slug_live_unique.down.sql
+-- Fails while a soft-deleted project shares its slug with another project; purge it first.
+
+DROP INDEX idx_projects_slug_live;
+
+ALTER TABLE projects ADD CONSTRAINT projects_slug_key UNIQUE (slug);
@@ -0,0 +1,7 @@
+-- 000021_project_slug_live_unique.up.sql
+-- Slugs are unique among live projects only. A soft-deleted project kept its slug for the
+-- whole retention window, so a project could not be recreated under the same name.
+
+ALTER TABLE projects DROP CONSTRAINT projects_slug_key;
+
+CREATE UNIQUE INDEX idx_projects_slug_live ON projects (slug) WHERE deleted_at IS NULL;
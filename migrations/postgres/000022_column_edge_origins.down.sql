@@ -0,0 +1,9 @@
+-- 000022_column_edge_origins.down.sql
+-- An edge derived from several files keeps only its first origin.
+
+UPDATE symbol_edges
+SET metadata = (metadata - 'origins') || jsonb_build_object(
+        'source_id', split_part(metadata->'origins'->>0, ':', 1),
+        'file', substr(metadata->'origins'->>0, 38))
+WHERE edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND jsonb_array_length(metadata->'origins') > 0;
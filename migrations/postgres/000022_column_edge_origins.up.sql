@@ -0,0 +1,11 @@
+-- 000022_column_edge_origins.up.sql
+-- Column-lineage edges list every file they were derived from as "<source_id>:<path>"
+-- origins, instead of naming only the last file that wrote them, so an incremental run
+-- keeps an edge that an unchanged file still derives. Edges written before edges recorded
+-- their file have no origin; the next full run of the project's sources rebuilds them.
+
+UPDATE symbol_edges
+SET metadata = (metadata - '{source_id,file}'::text[])
+    || jsonb_build_object('origins', jsonb_build_array((metadata->>'source_id') || ':' || (metadata->>'file')))
+WHERE edge_type IN ('transforms_to', 'direct_copy', 'uses_column')
+  AND metadata ? 'source_id' AND metadata ? 'file';
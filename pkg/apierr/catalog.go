@@ -1,6 +1,10 @@
 package apierr
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 // --- Common ---
 
@@ -46,6 +50,60 @@ func ProjectCountFailed(cause error) *Error {
 	return Wrap(CodeProjectCountFailed, http.StatusInternalServerError, "Failed to count projects", cause)
 }
 
+func ProjectRestoreFailed(cause error) *Error {
+	return Wrap(CodeProjectRestoreFailed, http.StatusInternalServerError, "Failed to restore project", cause)
+}
+
+func RetentionElapsed() *Error {
+	return New(CodeRetentionElapsed, http.StatusGone, "Project was deleted before the retention window and can no longer be restored")
+}
+
+func ProjectSlugTaken() *Error {
+	return New(CodeProjectSlugTaken, http.StatusConflict, "Another project now uses this slug; rename or delete it before restoring")
+}
+
+func ProjectArchived() *Error {
+	return New(CodeProjectArchived, http.StatusConflict, "Project is archived and read-only; unarchive it first")
+}
+
+func ProjectNotArchived() *Error {
+	return New(CodeProjectNotArchived, http.StatusConflict, "Project is not archived")
+}
+
+func ProjectArchiveFailed(cause error) *Error {
+	return Wrap(CodeProjectArchiveFailed, http.StatusInternalServerError, "Failed to archive or unarchive project", cause)
+}
+
+// --- Project template ---
+
+func TemplateNotFound() *Error {
+	return New(CodeTemplateNotFound, http.StatusNotFound, "Project template not found")
+}
+
+func TemplateExists() *Error {
+	return New(CodeTemplateExists, http.StatusConflict, "A project template with this name already exists")
+}
+
+func InvalidTemplateSettings() *Error {
+	return New(CodeInvalidTemplateSettings, http.StatusBadRequest, "settings must be a JSON object")
+}
+
+func TemplateSaveFailed(cause error) *Error {
+	return Wrap(CodeTemplateSaveFailed, http.StatusInternalServerError, "Failed to save project template", cause)
+}
+
+func TemplateDeleteFailed(cause error) *Error {
+	return Wrap(CodeTemplateDeleteFailed, http.StatusInternalServerError, "Failed to delete project template", cause)
+}
+
+func TemplateListFailed(cause error) *Error {
+	return Wrap(CodeTemplateListFailed, http.StatusInternalServerError, "Failed to list project templates", cause)
+}
+
+func TemplateApplyFailed(cause error) *Error {
+	return Wrap(CodeTemplateApplyFailed, http.StatusInternalServerError, "Failed to apply project template", cause)
+}
+
 // --- Source ---
 
 func SourceNotFound() *Error {
@@ -60,6 +118,10 @@ func InvalidSourceType() *Error {
 	return New(CodeInvalidSourceType, http.StatusBadRequest, "source_type must be one of: git, database, filesystem, upload")
 }
 
+func InvalidConnectionURI(reason string) *Error {
+	return New(CodeInvalidConnectionURI, http.StatusBadRequest, "connection_uri is invalid: "+reason)
+}
+
 func SourceCreateFailed(cause error) *Error {
 	return Wrap(CodeSourceCreateFailed, http.StatusInternalServerError, "Failed to create source", cause)
 }
@@ -114,12 +176,92 @@ func EmbeddingFailed(cause error) *Error {
 	return Wrap(CodeEmbeddingFailed, http.StatusInternalServerError, "Embedding generation failed", cause)
 }
 
+func ReembedInProgress() *Error {
+	return New(CodeReembedInProgress, http.StatusConflict, "A re-embed is already running for this project")
+}
+
+func ReembedNotRunning() *Error {
+	return New(CodeReembedNotRunning, http.StatusNotFound, "No re-embed is running for this project")
+}
+
 // --- Analytics ---
 
 func AnalyticsFailed(cause error) *Error {
 	return Wrap(CodeAnalyticsFailed, http.StatusInternalServerError, "Analytics query failed", cause)
 }
 
+// --- Reference types & bulk ingestion ---
+
+func InvalidReferenceType(cause error) *Error {
+	return New(CodeInvalidReferenceType, http.StatusBadRequest, "Invalid reference type: "+cause.Error())
+}
+
+func UnknownReferenceTypes(types []string) *Error {
+	return New(CodeUnknownReferenceTypes, http.StatusUnprocessableEntity,
+		"Unregistered reference types: "+strings.Join(types, ", ")+" (register them under /reference-types first)")
+}
+
+func InvalidBulkPayload(msg string) *Error {
+	return New(CodeInvalidBulkPayload, http.StatusBadRequest, msg)
+}
+
+func BulkIngestFailed(cause error) *Error {
+	return Wrap(CodeBulkIngestFailed, http.StatusInternalServerError, "Bulk ingestion failed", cause)
+}
+
+// --- Schema diff ---
+
+func SchemaSnapshotNotFound(cause error) *Error {
+	return New(CodeSchemaSnapshotNotFound, http.StatusNotFound, "Schema snapshot not found: "+cause.Error())
+}
+
+func SchemaDiffFailed(cause error) *Error {
+	return Wrap(CodeSchemaDiffFailed, http.StatusInternalServerError, "Schema diff failed", cause)
+}
+
+// --- Graph export ---
+
+func InvalidExportFormat() *Error {
+	return New(CodeInvalidExportFormat, http.StatusBadRequest, "format must be graphml or dot")
+}
+
+func ExportSeedRequired() *Error {
+	return New(CodeExportSeedRequired, http.StatusBadRequest, "One of topic, kinds or seeds is required")
+}
+
+func GraphExportFailed(cause error) *Error {
+	return Wrap(CodeGraphExportFailed, http.StatusInternalServerError, "Graph export failed", cause)
+}
+
+// --- GraphQL ---
+
+func InvalidPageSize(max int) *Error {
+	return New(CodeInvalidPageSize, http.StatusBadRequest, fmt.Sprintf("first must be between 1 and %d", max))
+}
+
+func InvalidCursor() *Error {
+	return New(CodeInvalidCursor, http.StatusBadRequest, "Invalid pagination cursor")
+}
+
+func QueryTooComplex(msg string) *Error {
+	return New(CodeQueryTooComplex, http.StatusBadRequest, msg)
+}
+
+// --- Debug ---
+
+func FilenameRequired() *Error {
+	return New(CodeFilenameRequired, http.StatusBadRequest, "Filename is required")
+}
+
+func UnsupportedFileType(filename string) *Error {
+	return New(CodeUnsupportedFileType, http.StatusBadRequest, "No parser registered for "+filename)
+}
+
+func ParseFailed(cause error) *Error {
+	// Admin-only debug endpoint: the parser error is the useful part, so it is surfaced.
+	return New(CodeParseFailed, http.StatusUnprocessableEntity, "Parser failed: "+cause.Error())
+}
+
 // --- Validation ---
 
 func SlugRequired() *Error {
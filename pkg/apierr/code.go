@@ -19,6 +19,12 @@ const (
 	CodeProjectDeleteFailed Code = "PROJECT_DELETE_FAILED"
 	CodeProjectListFailed   Code = "PROJECT_LIST_FAILED"
 	CodeProjectCountFailed  Code = "PROJECT_COUNT_FAILED"
+	CodeProjectRestoreFailed Code = "PROJECT_RESTORE_FAILED"
+	CodeRetentionElapsed     Code = "RETENTION_ELAPSED"
+	CodeProjectArchived      Code = "PROJECT_ARCHIVED"
+	CodeProjectNotArchived   Code = "PROJECT_NOT_ARCHIVED"
+	CodeProjectArchiveFailed Code = "PROJECT_ARCHIVE_FAILED"
+	CodeProjectSlugTaken     Code = "PROJECT_SLUG_TAKEN"
 )
 
 // Source errors.
@@ -26,11 +32,23 @@ const (
 	CodeSourceNotFound    Code = "SOURCE_NOT_FOUND"
 	CodeInvalidSourceID   Code = "INVALID_SOURCE_ID"
 	CodeInvalidSourceType Code = "INVALID_SOURCE_TYPE"
+	CodeInvalidConnectionURI Code = "INVALID_CONNECTION_URI"
 	CodeSourceCreateFailed Code = "SOURCE_CREATE_FAILED"
 	CodeSourceDeleteFailed Code = "SOURCE_DELETE_FAILED"
 	CodeSourceListFailed   Code = "SOURCE_LIST_FAILED"
 )
 
+// Project template errors.
+const (
+	CodeTemplateNotFound        Code = "TEMPLATE_NOT_FOUND"
+	CodeTemplateExists          Code = "TEMPLATE_EXISTS"
+	CodeInvalidTemplateSettings Code = "INVALID_TEMPLATE_SETTINGS"
+	CodeTemplateSaveFailed      Code = "TEMPLATE_SAVE_FAILED"
+	CodeTemplateDeleteFailed    Code = "TEMPLATE_DELETE_FAILED"
+	CodeTemplateListFailed      Code = "TEMPLATE_LIST_FAILED"
+	CodeTemplateApplyFailed     Code = "TEMPLATE_APPLY_FAILED"
+)
+
 // Index run errors.
 const (
 	CodeIndexRunNotFound    Code = "INDEX_RUN_NOT_FOUND"
@@ -50,6 +68,8 @@ const (
 	CodeSearchFailed       Code = "SEARCH_FAILED"
 	CodeLineageQueryFailed Code = "LINEAGE_QUERY_FAILED"
 	CodeEmbeddingFailed    Code = "EMBEDDING_FAILED"
+	CodeReembedInProgress  Code = "REEMBED_IN_PROGRESS"
+	CodeReembedNotRunning  Code = "REEMBED_NOT_RUNNING"
 )
 
 // Validation errors.
@@ -77,6 +97,41 @@ const (
 	CodeAnalyticsFailed Code = "ANALYTICS_FAILED"
 )
 
+// Reference type & bulk ingestion errors.
+const (
+	CodeInvalidReferenceType  Code = "INVALID_REFERENCE_TYPE"
+	CodeUnknownReferenceTypes Code = "UNKNOWN_REFERENCE_TYPES"
+	CodeInvalidBulkPayload    Code = "INVALID_BULK_PAYLOAD"
+	CodeBulkIngestFailed      Code = "BULK_INGEST_FAILED"
+)
+
+// Schema diff errors.
+const (
+	CodeSchemaSnapshotNotFound Code = "SCHEMA_SNAPSHOT_NOT_FOUND"
+	CodeSchemaDiffFailed       Code = "SCHEMA_DIFF_FAILED"
+)
+
+// Graph export errors.
+const (
+	CodeInvalidExportFormat Code = "INVALID_EXPORT_FORMAT"
+	CodeExportSeedRequired  Code = "EXPORT_SEED_REQUIRED"
+	CodeGraphExportFailed   Code = "GRAPH_EXPORT_FAILED"
+)
+
+// GraphQL errors.
+const (
+	CodeInvalidPageSize Code = "INVALID_PAGE_SIZE"
+	CodeInvalidCursor   Code = "INVALID_CURSOR"
+	CodeQueryTooComplex Code = "QUERY_TOO_COMPLEX"
+)
+
+// Debug errors.
+const (
+	CodeFilenameRequired    Code = "FILENAME_REQUIRED"
+	CodeUnsupportedFileType Code = "UNSUPPORTED_FILE_TYPE"
+	CodeParseFailed         Code = "PARSE_FAILED"
+)
+
 // Auth errors.
 const (
 	CodeUnauthorized Code = "UNAUTHORIZED"
@@ -4,9 +4,16 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // IsNotFound returns true if the error is or wraps pgx.ErrNoRows.
 func IsNotFound(err error) bool {
 	return errors.Is(err, pgx.ErrNoRows)
 }
+
+// IsUniqueViolation returns true if the error is a Postgres unique constraint violation.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
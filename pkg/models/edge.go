@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,21 +10,50 @@ import (
 type EdgeType string
 
 const (
-	EdgeTypeCalls        EdgeType = "calls"
-	EdgeTypeImports      EdgeType = "imports"
-	EdgeTypeInherits     EdgeType = "inherits"
-	EdgeTypeImplements   EdgeType = "implements"
-	EdgeTypeReferences   EdgeType = "references"
-	EdgeTypeContains     EdgeType = "contains"
-	EdgeTypeDependsOn    EdgeType = "depends_on"
-	EdgeTypeReadsFrom    EdgeType = "reads_from"
-	EdgeTypeWritesTo     EdgeType = "writes_to"
-	EdgeTypeUsesTable    EdgeType = "uses_table"
-	EdgeTypeUsesColumn   EdgeType = "uses_column"
-	EdgeTypeJoins        EdgeType = "joins"
-	EdgeTypeTransformsTo EdgeType = "transforms_to"
+	EdgeTypeCalls          EdgeType = "calls"
+	EdgeTypeImports        EdgeType = "imports"
+	EdgeTypeInherits       EdgeType = "inherits"
+	EdgeTypeImplements     EdgeType = "implements"
+	EdgeTypeReferences     EdgeType = "references"
+	EdgeTypeContains       EdgeType = "contains"
+	EdgeTypeDependsOn      EdgeType = "depends_on"
+	EdgeTypeReadsFrom      EdgeType = "reads_from"
+	EdgeTypeWritesTo       EdgeType = "writes_to"
+	EdgeTypeUsesTable      EdgeType = "uses_table"
+	EdgeTypeUsesColumn     EdgeType = "uses_column"
+	EdgeTypeJoins          EdgeType = "joins"
+	EdgeTypeTransformsTo   EdgeType = "transforms_to"
+	EdgeTypeDirectCopy     EdgeType = "direct_copy"
+	EdgeTypeForeignKey     EdgeType = "foreign_key"
+	EdgeTypeCallsAPI       EdgeType = "calls_api"
+	EdgeTypeCallsGraphQL   EdgeType = "calls_graphql"
+	EdgeTypeCreates        EdgeType = "creates"
+	EdgeTypeAlters         EdgeType = "alters"
+	EdgeTypeIndexes        EdgeType = "indexes"
+	EdgeTypeRelatedTo      EdgeType = "related_to"
+	EdgeTypeUsesCollection EdgeType = "uses_collection"
+	EdgeTypeReadsConfig    EdgeType = "reads_config"
 )
 
+// IsInferredEdgeType reports whether edges of a type are guessed from naming rather
+// than declared or observed, like the related_to edges between columns shaped like a
+// foreign key and the key they match. Traversals skip them unless asked to follow them.
+func IsInferredEdgeType(edgeType string) bool {
+	return edgeType == string(EdgeTypeRelatedTo)
+}
+
+// IsConditionalEdge reports whether an edge's metadata marks it as only taken behind a
+// feature-flag check, so impact analysis can weight or skip the paths through it.
+func IsConditionalEdge(metadata []byte) bool {
+	if len(metadata) == 0 {
+		return false
+	}
+	var meta struct {
+		Conditional bool `json:"conditional"`
+	}
+	return json.Unmarshal(metadata, &meta) == nil && meta.Conditional
+}
+
 type SymbolEdge struct {
 	ID        uuid.UUID      `json:"id"`
 	ProjectID uuid.UUID      `json:"project_id"`
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Edge type categories used to group reference types for display.
+const (
+	EdgeCategoryCode      = "code"
+	EdgeCategoryData      = "data"
+	EdgeCategoryStructure = "structure"
+	EdgeCategoryLineage   = "lineage"
+)
+
+// EdgeTypeInfo describes a reference type: its stored name, display label and category.
+type EdgeTypeInfo struct {
+	Name     EdgeType `json:"name"`
+	Label    string   `json:"label"`
+	Category string   `json:"category"`
+	Custom   bool     `json:"custom"`
+}
+
+// BuiltinEdgeTypes are the reference types produced by Lattice's own parsers.
+var BuiltinEdgeTypes = []EdgeTypeInfo{
+	{Name: EdgeTypeCalls, Label: "Calls", Category: EdgeCategoryCode},
+	{Name: EdgeTypeImports, Label: "Imports", Category: EdgeCategoryCode},
+	{Name: EdgeTypeInherits, Label: "Inherits", Category: EdgeCategoryCode},
+	{Name: EdgeTypeImplements, Label: "Implements", Category: EdgeCategoryCode},
+	{Name: EdgeTypeReferences, Label: "References", Category: EdgeCategoryCode},
+	{Name: EdgeTypeCallsAPI, Label: "Calls API", Category: EdgeCategoryCode},
+	{Name: EdgeTypeCallsGraphQL, Label: "Calls GraphQL", Category: EdgeCategoryCode},
+	{Name: EdgeTypeContains, Label: "Contains", Category: EdgeCategoryStructure},
+	{Name: EdgeTypeDependsOn, Label: "Depends on", Category: EdgeCategoryStructure},
+	{Name: EdgeTypeReadsFrom, Label: "Reads from", Category: EdgeCategoryData},
+	{Name: EdgeTypeWritesTo, Label: "Writes to", Category: EdgeCategoryData},
+	{Name: EdgeTypeUsesTable, Label: "Uses table", Category: EdgeCategoryData},
+	{Name: EdgeTypeUsesCollection, Label: "Uses collection", Category: EdgeCategoryData},
+	{Name: EdgeTypeReadsConfig, Label: "Reads config", Category: EdgeCategoryData},
+	{Name: EdgeTypeUsesColumn, Label: "Uses column", Category: EdgeCategoryLineage},
+	{Name: EdgeTypeJoins, Label: "Joins", Category: EdgeCategoryData},
+	{Name: EdgeTypeForeignKey, Label: "Foreign key", Category: EdgeCategoryData},
+	{Name: EdgeTypeRelatedTo, Label: "Related to (inferred)", Category: EdgeCategoryData},
+	{Name: EdgeTypeCreates, Label: "Creates", Category: EdgeCategoryStructure},
+	{Name: EdgeTypeAlters, Label: "Alters", Category: EdgeCategoryStructure},
+	{Name: EdgeTypeIndexes, Label: "Indexes", Category: EdgeCategoryStructure},
+	{Name: EdgeTypeTransformsTo, Label: "Transforms to", Category: EdgeCategoryLineage},
+	{Name: EdgeTypeDirectCopy, Label: "Direct copy", Category: EdgeCategoryLineage},
+}
+
+var edgeTypeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{1,62}$`)
+
+// EdgeTypeRegistry is the set of reference types a project accepts: the builtins plus
+// any custom types registered in project settings under reference_types.
+type EdgeTypeRegistry struct {
+	types map[EdgeType]EdgeTypeInfo
+}
+
+// NewEdgeTypeRegistry returns a registry holding only the builtin types.
+func NewEdgeTypeRegistry() *EdgeTypeRegistry {
+	r := &EdgeTypeRegistry{types: make(map[EdgeType]EdgeTypeInfo, len(BuiltinEdgeTypes))}
+	for _, t := range BuiltinEdgeTypes {
+		r.types[t.Name] = t
+	}
+	return r
+}
+
+// EdgeTypeRegistryFromSettings builds the registry for a project from its settings.
+// Invalid custom entries are skipped so a bad setting never blocks reads.
+func EdgeTypeRegistryFromSettings(settings []byte) *EdgeTypeRegistry {
+	r := NewEdgeTypeRegistry()
+	for _, t := range CustomEdgeTypesFromSettings(settings) {
+		_ = r.Register(t)
+	}
+	return r
+}
+
+// CustomEdgeTypesFromSettings returns the raw reference_types entries from project settings.
+func CustomEdgeTypesFromSettings(settings []byte) []EdgeTypeInfo {
+	if len(settings) == 0 {
+		return nil
+	}
+	var s struct {
+		ReferenceTypes []EdgeTypeInfo `json:"reference_types"`
+	}
+	if json.Unmarshal(settings, &s) != nil {
+		return nil
+	}
+	return s.ReferenceTypes
+}
+
+// Register adds a custom type. Names must be lowercase snake_case and may not shadow
+// a builtin; an empty label defaults to the name and an empty category to "custom".
+func (r *EdgeTypeRegistry) Register(t EdgeTypeInfo) error {
+	if !edgeTypeNameRegex.MatchString(string(t.Name)) {
+		return fmt.Errorf("invalid reference type name %q: must be lowercase snake_case, 2-63 chars", t.Name)
+	}
+	if existing, ok := r.types[t.Name]; ok && !existing.Custom {
+		return fmt.Errorf("reference type %q is builtin", t.Name)
+	}
+	if t.Label == "" {
+		t.Label = string(t.Name)
+	}
+	if t.Category == "" {
+		t.Category = "custom"
+	}
+	t.Custom = true
+	r.types[t.Name] = t
+	return nil
+}
+
+// Lookup returns the info for a reference type and whether it is known.
+func (r *EdgeTypeRegistry) Lookup(name string) (EdgeTypeInfo, bool) {
+	t, ok := r.types[EdgeType(name)]
+	return t, ok
+}
+
+// Label returns the display label for a reference type, falling back to its name.
+func (r *EdgeTypeRegistry) Label(name string) string {
+	if t, ok := r.types[EdgeType(name)]; ok {
+		return t.Label
+	}
+	return name
+}
+
+// Unknown returns the distinct names in types that are not registered, sorted.
+func (r *EdgeTypeRegistry) Unknown(types []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range types {
+		if _, ok := r.types[EdgeType(t)]; ok || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Custom returns the registered custom types sorted by name.
+func (r *EdgeTypeRegistry) Custom() []EdgeTypeInfo {
+	var out []EdgeTypeInfo
+	for _, t := range r.types {
+		if t.Custom {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}